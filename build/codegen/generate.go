@@ -3,3 +3,5 @@
 package codegen
 
 //go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config cfg.yaml ../../api/v1/openapi.yaml
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config cfg-v2.yaml ../../api/v2/openapi.yaml
+//go:generate protoc --proto_path=../../api/v1 --go_out=../.. --go_opt=module=github.com/rhobs/rhobs-synthetics-api --go-grpc_out=../.. --go-grpc_opt=module=github.com/rhobs/rhobs-synthetics-api probes.proto
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/spf13/cobra"
+)
+
+// bundleClient reaches the API a probe bundle is exported from or imported
+// into.
+type bundleClient struct {
+	server   string
+	certFile string
+	keyFile  string
+	caFile   string
+}
+
+// addFlags adds the flags locating the API to cmd.
+func (c *bundleClient) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&c.server, "server", "http://localhost:8080", "Base URL of the API")
+	cmd.Flags().StringVar(&c.certFile, "tls-cert", "", "Path to a PEM client certificate to present to the API (requires --tls-key)")
+	cmd.Flags().StringVar(&c.keyFile, "tls-key", "", "Path to the PEM private key for --tls-cert")
+	cmd.Flags().StringVar(&c.caFile, "tls-ca", "", "Path to a PEM CA bundle to verify the API's certificate with, instead of the system roots")
+}
+
+// do sends a request to the API and returns the response body, with the
+// error message of a response that is not a success.
+func (c *bundleClient) do(method, path string, query url.Values, contentType string, body []byte) ([]byte, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.certFile != "" || c.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if c.caFile != "" {
+		pem, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA bundle: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.caFile)
+		}
+	}
+	client := &http.Client{Timeout: 5 * time.Minute, Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}
+
+	u := strings.TrimSuffix(c.server, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		var errResp v1.ErrorResponse
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error.Message != "" {
+			return data, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, errResp.Error.Message)
+		}
+		return data, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return data, nil
+}
+
+// newExportCmd returns the 'export' subcommand, which writes the probes of a
+// running API to a bundle.
+func newExportCmd() *cobra.Command {
+	var client bundleClient
+	var labelSelector, status, fields, format, output string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export probes to a bundle",
+		Long:  `Exports the probes of a running API, or those matching --label-selector and --status, to a versioned bundle that 'import' restores.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{"format": {format}}
+			if labelSelector != "" {
+				query.Set("label_selector", labelSelector)
+			}
+			if status != "" {
+				query.Set("status", status)
+			}
+			if fields != "" {
+				query.Set("fields", fields)
+			}
+			data, err := client.do(http.MethodGet, "/probes/export", query, "", nil)
+			if err != nil {
+				return err
+			}
+			if output == "" || output == "-" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			return os.WriteFile(output, data, 0o600)
+		},
+	}
+	client.addFlags(cmd)
+	cmd.Flags().StringVar(&labelSelector, "label-selector", "", "Only export the probes matching this label selector")
+	cmd.Flags().StringVar(&status, "status", "", "Only export the probes with one of these comma-separated statuses")
+	cmd.Flags().StringVar(&fields, "fields", "", "Comma-separated fields of the probes to export (every field when empty); keep static_url for the bundle to be importable")
+	cmd.Flags().StringVar(&format, "format", string(v1.Json), "Encoding of the bundle: json, yaml, or ndjson for one probe per line")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write the bundle to (standard output when empty)")
+	return cmd
+}
+
+// newImportCmd returns the 'import' subcommand, which restores a bundle into
+// a running API.
+func newImportCmd() *cobra.Command {
+	var client bundleClient
+	var onConflict string
+	var dryRun, allOrNothing bool
+	cmd := &cobra.Command{
+		Use:   "import [FILE]",
+		Short: "Import probes from a bundle",
+		Long:  `Imports the probes of a bundle made by 'export' into a running API. The bundle is read from FILE, or standard input when it is omitted or "-".`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var data []byte
+			var err error
+			if len(args) == 0 || args[0] == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(args[0])
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read the bundle: %w", err)
+			}
+			// JSON bundles are objects; anything else is sent as YAML.
+			contentType := "application/yaml"
+			if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+				contentType = "application/json"
+			}
+
+			query := url.Values{"on_conflict": {onConflict}}
+			if dryRun {
+				query.Set("dry_run", "true")
+			}
+			if allOrNothing {
+				query.Set("all_or_nothing", "true")
+			}
+			// With all_or_nothing, the probes failing validation come with
+			// a 422, and are listed before its error is returned.
+			body, err := client.do(http.MethodPost, "/probes/import", query, contentType, data)
+			var result v1.ProbeImportResponse
+			if decodeErr := json.Unmarshal(body, &result); decodeErr != nil && err == nil {
+				return fmt.Errorf("failed to decode the response: %w", decodeErr)
+			}
+			for _, failure := range result.Errors {
+				where := fmt.Sprintf("probes[%d]", failure.Index)
+				if failure.Field != nil {
+					where = *failure.Field
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "%s: %d: %s\n", where, failure.Code, failure.Message) //nolint:errcheck
+			}
+			if err != nil {
+				return err
+			}
+			suffix := ""
+			if dryRun {
+				suffix = " (dry run)"
+			}
+			if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%d created, %d overwritten, %d skipped%s\n", len(result.Created), len(result.Overwritten), len(result.Skipped), suffix); err != nil {
+				return err
+			}
+			if len(result.Errors) > 0 {
+				// The flags were fine; the usage would bury the errors.
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d probes of the bundle failed validation and were not imported", len(result.Errors))
+			}
+			return nil
+		},
+	}
+	client.addFlags(cmd)
+	cmd.Flags().StringVar(&onConflict, "on-conflict", string(v1.Fail), "What to do with probes whose static_url is taken: skip, overwrite or fail")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what the import would do without changing anything")
+	cmd.Flags().BoolVar(&allOrNothing, "all-or-nothing", false, "Import nothing if any probe of the bundle fails validation")
+	return cmd
+}
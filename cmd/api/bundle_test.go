@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	newAPI := func() *httptest.Server {
+		store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+		require.NoError(t, err)
+		srv, err := server.New(server.Config{Store: store})
+		require.NoError(t, err)
+		ts := httptest.NewServer(srv.Handler())
+		t.Cleanup(ts.Close)
+		return ts
+	}
+	var stderr bytes.Buffer
+	run := func(newCmd func() *cobra.Command, args ...string) (string, error) {
+		command := newCmd()
+		var out bytes.Buffer
+		command.SetOut(&out)
+		stderr.Reset()
+		command.SetErr(&stderr)
+		command.SetArgs(args)
+		err := command.Execute()
+		return out.String(), err
+	}
+
+	source, target := newAPI(), newAPI()
+	for _, url := range []string{"https://one.example.com", "https://two.example.com"} {
+		res, err := http.Post(source.URL+"/probes", "application/json", strings.NewReader(`{"static_url":"`+url+`","labels":{"team":"sre"}}`))
+		require.NoError(t, err)
+		res.Body.Close() //nolint:errcheck
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			bundle := filepath.Join(t.TempDir(), "probes."+format)
+			_, err := run(newExportCmd, "--server", source.URL, "--format", format, "-o", bundle)
+			require.NoError(t, err)
+
+			out, err := run(newImportCmd, "--server", target.URL, "--on-conflict", "skip", "--dry-run", bundle)
+			require.NoError(t, err)
+			if format == "json" {
+				assert.Equal(t, "2 created, 0 overwritten, 0 skipped (dry run)\n", out)
+			}
+
+			out, err = run(newImportCmd, "--server", target.URL, "--on-conflict", "skip", bundle)
+			require.NoError(t, err)
+			if format == "json" {
+				assert.Equal(t, "2 created, 0 overwritten, 0 skipped\n", out)
+			} else {
+				assert.Equal(t, "0 created, 0 overwritten, 2 skipped\n", out, "the probes were imported from the JSON bundle")
+			}
+
+			_, err = run(newImportCmd, "--server", target.URL, bundle)
+			assert.ErrorContains(t, err, "409 Conflict: 2 probes of the bundle conflict with stored probes")
+		})
+	}
+
+	t.Run("invalid probes", func(t *testing.T) {
+		bundle := filepath.Join(t.TempDir(), "probes.json")
+		require.NoError(t, os.WriteFile(bundle, []byte(`{"version":1,"probes":[`+
+			`{"id":"6f1c1a52-3f4c-4c38-9d1e-0c9f3b1f6a01","static_url":"https://three.example.com"},`+
+			`{"id":"6f1c1a52-3f4c-4c38-9d1e-0c9f3b1f6a02","static_url":"https://four.example.com","interval":"10s","timeout":"30s"}]}`), 0o600))
+
+		_, err := run(newImportCmd, "--server", target.URL, "--all-or-nothing", bundle)
+		assert.ErrorContains(t, err, "422 Unprocessable Entity")
+		assert.Contains(t, stderr.String(), "probes[1].timeout: 400: probe timeout 30s is longer than the interval 10s")
+
+		out, err := run(newImportCmd, "--server", target.URL, bundle)
+		assert.EqualError(t, err, "1 probes of the bundle failed validation and were not imported")
+		assert.Equal(t, "1 created, 0 overwritten, 0 skipped\n", out)
+	})
+}
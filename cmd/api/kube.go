@@ -0,0 +1,124 @@
+//go:build !nokube
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/internal/secrets"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/kubeclient"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultDatabaseEngine is the default of --database-engine.
+const defaultDatabaseEngine = "etcd"
+
+func createKubernetesClient(kubeconfig string) (*kubeclient.Client, error) {
+	cfg := kubeclient.Config{
+		KubeconfigPath: kubeconfig,
+	}
+
+	client, err := kubeclient.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return client, nil
+}
+
+func createKubernetesClientset(kubeconfig string) (*kubernetes.Clientset, error) {
+	client, err := createKubernetesClient(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Clientset().(*kubernetes.Clientset), nil
+}
+
+// createKubernetesProbeStore creates the store of the etcd and crd engines,
+// and returns the clientset /readyz checks along with it.
+func createKubernetesProbeStore(engine string, cfg probestore.KubernetesConfig) (probestore.ProbeStorage, server.KubernetesInterface, error) {
+	switch engine {
+	case "etcd":
+		clientset, err := createKubernetesClientset(cfg.Kubeconfig)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+		}
+		store, err := probestore.NewShardedKubernetesProbeStore(context.Background(), clientset, cfg.Namespace, cfg.ShardNamespaces)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create kubernetes probe store: %w", err)
+		}
+		return store, clientset, nil
+	default:
+		if len(cfg.ShardNamespaces) > 0 {
+			return nil, nil, fmt.Errorf("storage.kubernetes.shard_namespaces is only supported by the etcd engine")
+		}
+		client, err := createKubernetesClient(cfg.Kubeconfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		store, err := probestore.NewCRDProbeStore(context.Background(), client.DynamicClient(), cfg.Namespace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create crd probe store: %w", err)
+		}
+		return store, client.Clientset(), nil
+	}
+}
+
+// createLeaseClient creates the clientset holding the leader election Lease
+// when the store does not use Kubernetes.
+func createLeaseClient(kubeconfig string) (server.KubernetesInterface, error) {
+	return createKubernetesClientset(kubeconfig)
+}
+
+// createDynamicClient creates the client Prometheus Operator Probe resources
+// are written with.
+func createDynamicClient(kubeconfig string) (server.DynamicInterface, error) {
+	client, err := createKubernetesClient(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return client.DynamicClient(), nil
+}
+
+// kubernetesSecretStore returns the store keeping probe secret values in
+// Secrets next to the probes.
+func kubernetesSecretStore(clientset server.KubernetesInterface) (server.SecretStore, error) {
+	return &secrets.KubernetesStore{
+		Client:    clientset,
+		Namespace: viper.GetString("storage.kubernetes.namespace"),
+	}, nil
+}
+
+// eventSink returns the sink recording audit entries as Kubernetes Events on
+// the objects the probes are stored in. With the etcd engine, the ConfigMap
+// is looked up in every namespace it may be in, as probes created before
+// sharding stay in --namespace.
+func eventSink(clientset server.KubernetesInterface) (server.AuditSink, error) {
+	namespace := viper.GetString("storage.kubernetes.namespace")
+	configMaps := &probestore.KubernetesProbeStore{
+		Client:          clientset,
+		Namespace:       namespace,
+		ShardNamespaces: viper.GetStringSlice("storage.kubernetes.shard_namespaces"),
+	}
+	object := func(ctx context.Context, _ string, probeID uuid.UUID) corev1.ObjectReference {
+		return configMaps.ProbeConfigMapReference(ctx, probeID)
+	}
+	if viper.GetString("database_engine") == "crd" {
+		object = func(_ context.Context, namespace string, probeID uuid.UUID) corev1.ObjectReference {
+			return probestore.ProbeResourceReference(namespace, probeID)
+		}
+	}
+	return &audit.EventSink{
+		Client:    clientset,
+		Namespace: namespace,
+		Object:    object,
+	}, nil
+}
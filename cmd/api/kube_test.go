@@ -0,0 +1,64 @@
+//go:build !nokube
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateProbeStore_Kubernetes(t *testing.T) {
+	defer viper.Set("database_engine", viper.GetString("database_engine"))
+	defer viper.Set("storage.kubernetes.namespace", viper.GetString("storage.kubernetes.namespace"))
+	defer viper.Set("storage.kubernetes.kubeconfig", viper.GetString("storage.kubernetes.kubeconfig"))
+
+	t.Run("crd storage without namespace", func(t *testing.T) {
+		viper.Set("database_engine", "crd")
+		viper.Set("storage.kubernetes.namespace", "")
+		viper.Set("storage.kubernetes.kubeconfig", writeKubeconfig(t))
+
+		store, _, err := createProbeStore()
+
+		require.Error(t, err)
+		assert.Nil(t, store)
+		assert.Contains(t, err.Error(), "kubernetes namespace cannot be empty")
+	})
+
+	t.Run("crd storage with shard namespaces", func(t *testing.T) {
+		defer viper.Set("storage.kubernetes.shard_namespaces", nil)
+		viper.Set("database_engine", "crd")
+		viper.Set("storage.kubernetes.namespace", "rhobs")
+		viper.Set("storage.kubernetes.shard_namespaces", []string{"rhobs-probes-0", "rhobs-probes-1"})
+		viper.Set("storage.kubernetes.kubeconfig", writeKubeconfig(t))
+
+		store, _, err := createProbeStore()
+
+		require.Error(t, err)
+		assert.Nil(t, store)
+		assert.Contains(t, err.Error(), "only supported by the etcd engine")
+	})
+}
+
+// writeKubeconfig writes a kubeconfig for an unreachable cluster; creating a
+// client from it does not connect.
+func writeKubeconfig(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`), 0600))
+	return path
+}
@@ -1,201 +1,612 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
 
-	"github.com/getkin/kin-openapi/openapi3"
-	middleware "github.com/oapi-codegen/nethttp-middleware"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
 	"github.com/rhobs/rhobs-synthetics-api/internal/api"
-	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/health"
+	"github.com/rhobs/rhobs-synthetics-api/internal/idempotency"
+	"github.com/rhobs/rhobs-synthetics-api/internal/leader"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/mutation"
 	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/internal/promprobes"
+	"github.com/rhobs/rhobs-synthetics-api/internal/results"
+	"github.com/rhobs/rhobs-synthetics-api/internal/secrets"
+	"github.com/rhobs/rhobs-synthetics-api/internal/shadow"
+	"github.com/rhobs/rhobs-synthetics-api/internal/targetcheck"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tlsreload"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
+	"github.com/rhobs/rhobs-synthetics-api/internal/webhooks"
+	"github.com/rhobs/rhobs-synthetics-api/internal/writelimit"
 	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
-	"github.com/rhobs/rhobs-synthetics-api/pkg/kubeclient"
-	"github.com/rhobs/rhobs-synthetics-api/web"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
-	"k8s.io/client-go/kubernetes"
 )
 
-func createKubernetesClientset() (*kubernetes.Clientset, error) {
-	cfg := kubeclient.Config{
-		KubeconfigPath: viper.GetString("kubeconfig"),
+// legacyStorageKeys maps the flat storage keys that preceded the per-backend
+// stanzas to the keys that replaced them.
+var legacyStorageKeys = map[string]string{
+	"kubeconfig":   "storage.kubernetes.kubeconfig",
+	"namespace":    "storage.kubernetes.namespace",
+	"data_dir":     "storage.local.data_dir",
+	"postgres_dsn": "storage.postgres.dsn",
+}
+
+// checkLegacyStorageKeys fails when the config file still sets one of the flat
+// storage keys, which would otherwise be silently ignored.
+func checkLegacyStorageKeys(v *viper.Viper) error {
+	var found []string
+	for key, replacement := range legacyStorageKeys {
+		if v.InConfig(key) {
+			found = append(found, fmt.Sprintf("%s (use %s)", key, replacement))
+		}
 	}
+	if len(found) == 0 {
+		return nil
+	}
+	sort.Strings(found)
+	return fmt.Errorf("config file sets unsupported storage keys: %s", strings.Join(found, ", "))
+}
 
-	client, err := kubeclient.NewClient(cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+// s3Flags are the flags of the s3 engine.
+var s3Flags = []string{"s3-bucket", "s3-endpoint", "s3-region", "s3-prefix", "s3-path-style", "s3-access-key-id", "s3-secret-access-key"}
+
+// redisFlags are the flags of the redis engine.
+var redisFlags = []string{"redis-url", "redis-prefix"}
+
+// memoryFlags are the flags of the memory engine.
+var memoryFlags = []string{"memory-snapshot-file", "memory-snapshot-interval"}
+
+// storageConfig returns the storage stanzas. The whole configuration is
+// unmarshalled because UnmarshalKey does not see flags and environment
+// variables bound to nested keys.
+func storageConfig() (probestore.Config, error) {
+	var cfg struct {
+		Storage probestore.Config `mapstructure:"storage"`
+	}
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return probestore.Config{}, fmt.Errorf("failed to read storage config: %w", err)
+	}
+	return cfg.Storage, nil
+}
+
+// probeSchedule returns the interval, timeout and module given to probes
+// created without them.
+func probeSchedule() api.Schedule {
+	return api.Schedule{
+		Interval: viper.GetDuration("default_probe_interval"),
+		Timeout:  viper.GetDuration("default_probe_timeout"),
+		Module:   v1.ProbeModuleSchema(viper.GetString("default_probe_module")),
 	}
+}
 
-	return client.Clientset().(*kubernetes.Clientset), nil
+// targetValidation returns what the targets of probes created with
+// validate=connectivity must pass.
+func targetValidation() targetcheck.Config {
+	return targetcheck.Config{
+		Schemes: viper.GetStringSlice("probe_target_schemes"),
+		Timeout: viper.GetDuration("probe_target_timeout"),
+	}
 }
 
-func createRouter(validatedAPI http.Handler, clientset *kubernetes.Clientset, swagger *openapi3.T) http.Handler {
-	// The main router
-	mux := http.NewServeMux()
+// writeLimit returns the bound on probe writes run against the store at once.
+func writeLimit() writelimit.Config {
+	return writelimit.Config{
+		MaxConcurrent: viper.GetInt("max_concurrent_writes"),
+		MaxQueued:     viper.GetInt("write_queue_size"),
+		QueueTimeout:  viper.GetDuration("write_queue_timeout"),
+	}
+}
 
-	// Liveness and Readiness probes
-	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+// statusTransitions returns the status changes allowed on probe updates, as
+// set under status_transitions in the config file, or the defaults.
+func statusTransitions() (api.StatusTransitions, error) {
+	var transitions api.StatusTransitions
+	if err := viper.UnmarshalKey("status_transitions", &transitions); err != nil {
+		return nil, fmt.Errorf("failed to parse status_transitions: %w", err)
+	}
+	if transitions == nil {
+		return api.DefaultStatusTransitions(), nil
+	}
+	if err := transitions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid status_transitions: %w", err)
+	}
+	return transitions, nil
+}
+
+// apiDeprecation returns when version 1 of the API is deprecated and sunset,
+// from --v1-deprecation-date and --v1-sunset-date.
+func apiDeprecation() (server.APIDeprecation, error) {
+	var deprecation server.APIDeprecation
+	var err error
+	if deprecation.At, err = parseDate(viper.GetString("v1_deprecation_date")); err != nil {
+		return deprecation, fmt.Errorf("invalid --v1-deprecation-date: %w", err)
+	}
+	if deprecation.Sunset, err = parseDate(viper.GetString("v1_sunset_date")); err != nil {
+		return deprecation, fmt.Errorf("invalid --v1-sunset-date: %w", err)
+	}
+	if !deprecation.Sunset.IsZero() {
+		if deprecation.At.IsZero() {
+			return deprecation, errors.New("--v1-sunset-date requires --v1-deprecation-date")
+		}
+		if deprecation.Sunset.Before(deprecation.At) {
+			return deprecation, errors.New("--v1-sunset-date must not be before --v1-deprecation-date")
+		}
+	}
+	return deprecation, nil
+}
+
+// parseDate parses an RFC 3339 timestamp or a YYYY-MM-DD date, taken as
+// midnight UTC; an empty value is the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// mutationHooks returns the hooks applied to probes before they are stored,
+// as listed under mutation_hooks in the config file.
+func mutationHooks() (mutation.Chain, error) {
+	var configs []mutation.Config
+	if err := viper.UnmarshalKey("mutation_hooks", &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse mutation_hooks: %w", err)
+	}
+	return mutation.Build(configs)
+}
+
+// defaultLabels returns the labels merged into new probes, as set under
+// default_labels in the config file.
+func defaultLabels() (server.DefaultLabelsConfig, error) {
+	var cfg server.DefaultLabelsConfig
+	if err := viper.UnmarshalKey("default_labels", &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse default_labels: %w", err)
+	}
+	if err := cfg.Validate(api.DefaultLabelPolicy().WithReservedPrefixes(viper.GetStringSlice("reserved_label_prefixes")...)); err != nil {
+		return cfg, fmt.Errorf("invalid default_labels: %w", err)
+	}
+	return cfg, nil
+}
+
+// probeValidators returns the validators listed under probe_validators in
+// the config file.
+func probeValidators() ([]server.ProbeValidatorConfig, error) {
+	var configs []server.ProbeValidatorConfig
+	if err := viper.UnmarshalKey("probe_validators", &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse probe_validators: %w", err)
+	}
+	if _, err := api.BuildValidators(configs, nil); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// checkAuditSink validates the --audit-sink flag against the flags it
+// depends on.
+func checkAuditSink() error {
+	switch sink := viper.GetString("audit_sink"); sink {
+	case "", "stdout":
+	case "file":
+		if viper.GetString("audit_file") == "" {
+			return fmt.Errorf("--audit-sink=file requires --audit-file")
+		}
+	case "events":
+		if engine := viper.GetString("database_engine"); engine != "etcd" && engine != "crd" {
+			return fmt.Errorf("--audit-sink=events requires --database-engine=etcd or crd (current engine: %s)", engine)
+		}
+	default:
+		return fmt.Errorf("unsupported --audit-sink %q, must be one of: stdout, file, events", sink)
+	}
+	return nil
+}
 
-	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		// If not using the etcd backend, we don't need to check k8s connectivity.
-		if clientset == nil {
-			w.WriteHeader(http.StatusOK)
-			_, _ = w.Write([]byte("ok"))
-			return
+// auditSinks returns the sinks set by --audit-sink, and a function closing
+// them once the server has stopped.
+func auditSinks(clientset server.KubernetesInterface) ([]server.AuditSink, func() error, error) {
+	noop := func() error { return nil }
+	switch viper.GetString("audit_sink") {
+	case "stdout":
+		return []server.AuditSink{audit.NewWriterSink("stdout", os.Stdout)}, noop, nil
+	case "file":
+		f, err := os.OpenFile(viper.GetString("audit_file"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open audit file: %w", err)
 		}
-		_, err := clientset.Discovery().ServerVersion()
+		return []server.AuditSink{audit.NewWriterSink("file", f)}, f.Close, nil
+	case "events":
+		sink, err := eventSink(clientset)
 		if err != nil {
-			log.Printf("Readiness check failed: could not connect to Kubernetes API server: %v", err)
-			http.Error(w, "not ready: failed to connect to Kubernetes", http.StatusServiceUnavailable)
-			return
+			return nil, nil, err
 		}
+		return []server.AuditSink{sink}, noop, nil
+	}
+	return nil, noop, nil
+}
 
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+// cacheControl returns the Cache-Control rules set under cache_control in
+// the config file, or nil to use the defaults.
+func cacheControl() ([]server.CacheControlRule, error) {
+	var rules []server.CacheControlRule
+	if err := viper.UnmarshalKey("cache_control", &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse cache_control: %w", err)
+	}
+	return rules, nil
+}
 
-	// Add the Swagger UI handler at /docs
-	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		_, _ = w.Write(web.SwaggerHTML)
-	})
+// notifications returns the receivers and routes set under notifications in
+// the config file.
+func notifications() (server.NotificationConfig, error) {
+	var cfg server.NotificationConfig
+	if err := viper.UnmarshalKey("notifications", &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse notifications: %w", err)
+	}
+	return cfg, nil
+}
+
+// probeEvents returns the sink probe events are published to, set under
+// events in the config file.
+func probeEvents() (server.EventsConfig, error) {
+	var cfg server.EventsConfig
+	if err := viper.UnmarshalKey("events", &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse events: %w", err)
+	}
+	return cfg, nil
+}
+
+// probeTemplates returns the templates listed under probe_templates in the
+// config file.
+func probeTemplates() ([]server.ProbeTemplate, error) {
+	var list []server.ProbeTemplate
+	// Template IDs are decoded from their text form.
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := viper.UnmarshalKey("probe_templates", &list, hook); err != nil {
+		return nil, fmt.Errorf("failed to parse probe_templates: %w", err)
+	}
+	return list, nil
+}
+
+// auditPrivacy returns the anonymization and retention of audit entries.
+func auditPrivacy() server.AuditPrivacy {
+	return server.AuditPrivacy{
+		Retention:   viper.GetDuration("audit_retention"),
+		TruncateIPs: viper.GetBool("audit_truncate_ips"),
+		HashActors:  viper.GetBool("audit_hash_actors"),
+		HashKey:     []byte(viper.GetString("audit_hash_key")),
+	}
+}
+
+// checkAgentCredentials reports agent credential settings New would reject.
+func checkAgentCredentials() error {
+	key := viper.GetString("agent_credential_key")
+	if key == "" {
+		if viper.GetBool("require_agent_credentials") {
+			return fmt.Errorf("--require-agent-credentials requires --agent-credential-key")
+		}
+		return nil
+	}
+	if _, err := agentauth.NewIssuer([]byte(key), viper.GetDuration("agent_bootstrap_token_max_ttl"), viper.GetDuration("agent_credential_ttl")); err != nil {
+		return fmt.Errorf("invalid agent credential settings: %w", err)
+	}
+	return nil
+}
+
+// checkProbeSecretKey reports a --probe-secret-key the local secret store
+// would reject, or one set for another engine.
+func checkProbeSecretKey(databaseEngine string) error {
+	key := viper.GetString("probe_secret_key")
+	if key == "" {
+		return nil
+	}
+	if databaseEngine != "local" {
+		return fmt.Errorf("--probe-secret-key can only be used when --database-engine=local (current engine: %s)", databaseEngine)
+	}
+	if len(key) < secrets.MinKeyLength {
+		return fmt.Errorf("--probe-secret-key must be at least %d bytes, got %d", secrets.MinKeyLength, len(key))
+	}
+	return nil
+}
 
-	// Add the OpenAPI spec handler at /api/v1/openapi.json
-	mux.HandleFunc("/api/v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
-		jsonSpec, err := swagger.MarshalJSON()
+// probeSecrets returns where the passwords and bearer tokens of probe
+// credentials are kept: in Secrets for the etcd and crd engines, and in
+// files encrypted with --probe-secret-key next to the probes for the local
+// engine. Other engines, and the local one without a key, keep none.
+func probeSecrets(clientset server.KubernetesInterface) (server.SecretStore, error) {
+	switch viper.GetString("database_engine") {
+	case "etcd", "crd":
+		return kubernetesSecretStore(clientset)
+	case "local":
+		key := viper.GetString("probe_secret_key")
+		if key == "" {
+			slog.Warn("No probe secret key configured; probes cannot be given passwords or bearer tokens")
+			return nil, nil
+		}
+		dataDir := cmp.Or(viper.GetString("storage.local.data_dir"), "data")
+		store, err := secrets.NewFileStore(filepath.Join(dataDir, "secrets"), []byte(key))
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to marshal swagger spec: %v", err), http.StatusInternalServerError)
-			return
+			return nil, fmt.Errorf("failed to create probe secret store: %w", err)
 		}
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write(jsonSpec)
-	})
-	mux.Handle("/metrics", promhttp.Handler())
+		return store, nil
+	}
+	return nil, nil
+}
 
-	// Mount the validated API router to the main router.
-	// Requests will be matched against the UI handlers first, then fall through to the API.
-	mux.Handle("/", validatedAPI)
-	return mux
+// prometheusProbes returns where Prometheus Operator Probe resources are
+// rendered; it is disabled unless a namespace is set.
+func prometheusProbes() server.PrometheusProbeConfig {
+	return server.PrometheusProbeConfig{
+		Namespace: viper.GetString("prometheus_probes_namespace"),
+		ProberURL: viper.GetString("prometheus_probes_prober_url"),
+		Interval:  viper.GetDuration("prometheus_probes_interval"),
+	}
 }
 
-func createProbeStore() (probestore.ProbeStorage, *kubernetes.Clientset, error) {
-	var store probestore.ProbeStorage
-	var clientset *kubernetes.Clientset
-	var err error
+// leaderElection returns the Lease replicas elect a leader with. A replica is
+// named by its advertised URL, which standby replicas forward writes to, or
+// else by its hostname.
+func leaderElection() (server.LeaderElectionConfig, error) {
+	cfg := server.LeaderElectionConfig{
+		Name:          viper.GetString("leader_election_lease"),
+		Namespace:     cmp.Or(viper.GetString("leader_election_namespace"), viper.GetString("storage.kubernetes.namespace")),
+		Identity:      viper.GetString("advertise_url"),
+		LeaseDuration: viper.GetDuration("leader_lease_duration"),
+	}
+	if cfg.Enabled() && cfg.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return server.LeaderElectionConfig{}, fmt.Errorf("failed to name the replica for leader election: %w", err)
+		}
+		cfg.Identity = hostname
+	}
+	return cfg, nil
+}
+
+func createProbeStore() (probestore.ProbeStorage, server.KubernetesInterface, error) {
+	cfg, err := storageConfig()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	databaseEngine := viper.GetString("database_engine")
-	log.Printf("Using database engine: %s", databaseEngine)
+	slog.Info("Using database engine", "engine", databaseEngine)
+
+	store, clientset, err := openProbeStore(databaseEngine, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return probestore.NewTracedProbeStore(probestore.NewIndexedProbeStore(store), databaseEngine), clientset, nil
+}
+
+// openProbeStore creates the store of an engine from its storage stanza, and
+// returns the clientset of the Kubernetes engines along with it.
+func openProbeStore(databaseEngine string, cfg probestore.Config) (probestore.ProbeStorage, server.KubernetesInterface, error) {
+	var store probestore.ProbeStorage
+	var clientset server.KubernetesInterface
+	var err error
 
 	switch databaseEngine {
-	case "etcd":
-		clientset, err = createKubernetesClientset()
+	case "etcd", "crd":
+		store, clientset, err = createKubernetesProbeStore(databaseEngine, cfg.Kubernetes)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+			return nil, nil, err
 		}
-		namespace := viper.GetString("namespace")
-		store, err = probestore.NewKubernetesProbeStore(context.Background(), clientset, namespace)
+	case "local":
+		slog.Warn("Using local probe store, which is not recommended for production use")
+		store, err = probestore.NewLocalProbeStoreWithDir(cfg.Local.DataDir)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create kubernetes probe store: %w", err)
+			return nil, nil, fmt.Errorf("failed to create local probe store: %w", err)
 		}
-	case "local":
-		log.Printf("Using local probe store: WARNING: This is not recommended for production use.")
-		dataDir := viper.GetString("data_dir")
-		if dataDir != "" {
-			store, err = probestore.NewLocalProbeStoreWithDir(dataDir)
-		} else {
-			store, err = probestore.NewLocalProbeStore()
+	case "memory":
+		slog.Warn("Using memory probe store, which is not recommended for production use")
+		store, err = probestore.OpenMemoryProbeStore(cfg.Memory)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create memory probe store: %w", err)
 		}
+	case "postgres":
+		store, err = probestore.NewPostgresProbeStore(context.Background(), cfg.Postgres.DSN)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to create local probe store: %w", err)
+			return nil, nil, fmt.Errorf("failed to create postgres probe store: %w", err)
+		}
+	case "s3":
+		store, err = probestore.NewS3ProbeStore(context.Background(), cfg.S3)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create s3 probe store: %w", err)
+		}
+	case "redis":
+		store, err = probestore.NewRedisProbeStore(context.Background(), cfg.Redis)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create redis probe store: %w", err)
 		}
 	default:
-		return nil, nil, fmt.Errorf("unsupported database engine: %s. Supported engines are 'etcd', 'local'", databaseEngine)
+		return nil, nil, fmt.Errorf("unsupported database engine: %s. Supported engines are 'etcd', 'crd', 'local', 'memory', 'postgres', 's3', 'redis'", databaseEngine)
 	}
 	return store, clientset, nil
 }
 
-// runWebServer starts the HTTP server.
-func runWebServer(addr string) error {
+// grpcAddr returns the address to serve the gRPC API on, or "" if it is
+// disabled.
+func grpcAddr() string {
+	port := viper.GetInt("grpc_port")
+	if port == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", viper.GetString("host"), port)
+}
 
-	swagger, err := v1.GetSwagger()
+// runWebServer starts the HTTP server and serves until SIGINT or SIGTERM.
+func runWebServer(addr string) error {
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Endpoint:    viper.GetString("otel_endpoint"),
+		SampleRatio: viper.GetFloat64("otel_sample_ratio"),
+	})
 	if err != nil {
-		return fmt.Errorf("error loading swagger spec: %w", err)
+		return fmt.Errorf("failed to set up tracing: %w", err)
 	}
 
-	swagger.Servers = nil
-
 	store, clientset, err := createProbeStore()
 	if err != nil {
 		return fmt.Errorf("failed to create probe store: %w", err)
 	}
 
-	server := api.NewServer(store)
-	serverHandler := v1.NewStrictHandler(server, nil)
-	metrics.RegisterMetrics()
-
-	// The API handlers are registered on a separate router and validated.
-	apiRouter := http.NewServeMux()
-	v1.HandlerFromMux(serverHandler, apiRouter)
-	validatedAPI := middleware.OapiRequestValidator(swagger)(apiRouter)
-	validatedAPI = metrics.Middleware(validatedAPI)
-
-	router := createRouter(validatedAPI, clientset, swagger)
-
-	s := &http.Server{
-		Handler:      router,
-		Addr:         addr,
-		ReadTimeout:  viper.GetDuration("read_timeout"),
-		WriteTimeout: viper.GetDuration("write_timeout"),
+	settings, err := reloadableSettings()
+	if err != nil {
+		return err
 	}
-	monitorCtx, cancelMonitor := context.WithCancel(context.Background())
-	defer cancelMonitor()
-	go server.MonitorProbes(monitorCtx)
-	go server.GarbageCollectProbes(monitorCtx)
 
-	// Start the server in a goroutine so it doesn't block the main thread
-	go func() {
-		log.Printf("API server listening on http://%s", addr)
-		log.Printf("Swagger UI available at http://%s/docs", addr)
-		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
+	cfg := server.Config{
+		Addr:                  addr,
+		GRPCAddr:              grpcAddr(),
+		Store:                 store,
+		ReadTimeout:           viper.GetDuration("read_timeout"),
+		WriteTimeout:          viper.GetDuration("write_timeout"),
+		GracefulTimeout:       viper.GetDuration("graceful_timeout"),
+		DrainDelay:            viper.GetDuration("drain_delay"),
+		AdminToken:            viper.GetString("admin_token"),
+		Profiling:             viper.GetBool("enable_profiling"),
+		APIKeyRateLimit:       viper.GetFloat64("api_key_rate_limit"),
+		APIKeyRefreshInterval: viper.GetDuration("api_key_refresh_interval"),
+		TLS: tlsreload.Config{
+			CertFile:     viper.GetString("tls_cert"),
+			KeyFile:      viper.GetString("tls_key"),
+			ClientCAFile: viper.GetString("tls_client_ca"),
+		},
+		TLSReloadInterval:       viper.GetDuration("tls_reload_interval"),
+		IdempotencyKeyTTL:       viper.GetDuration("idempotency_key_ttl"),
+		ReadinessCheckInterval:  viper.GetDuration("readiness_check_interval"),
+		ReadinessLatencyBudget:  viper.GetDuration("readiness_latency_budget"),
+		ReservedLabelPrefixes:   settings.ReservedLabelPrefixes,
+		AgentFeatures:           viper.GetStringMapString("agent_features"),
+		AgentHeartbeatTTL:       viper.GetDuration("agent_heartbeat_ttl"),
+		AgentAffinityKeys:       viper.GetStringSlice("agent_affinity_keys"),
+		MaxListItems:            viper.GetInt("max_list_items"),
+		WriteLimit:              writeLimit(),
+		TargetValidation:        targetValidation(),
+		ProbeResultRetention:    viper.GetInt("probe_result_retention"),
+		ProbeMonitorInterval:    viper.GetDuration("probe_monitor_interval"),
+		TerminatingGracePeriod:  viper.GetDuration("terminating_grace_period"),
+		ClockSkewTolerance:      viper.GetDuration("clock_skew_tolerance"),
+		Schedule:                probeSchedule(),
+		PageTokenKey:            []byte(viper.GetString("page_token_key")),
+		AgentCredentialKey:      []byte(viper.GetString("agent_credential_key")),
+		AgentBootstrapMaxTTL:    viper.GetDuration("agent_bootstrap_token_max_ttl"),
+		AgentCredentialTTL:      viper.GetDuration("agent_credential_ttl"),
+		RequireAgentCredentials: viper.GetBool("require_agent_credentials"),
+		ReadOnly:                viper.GetBool("read_only"),
+		CompressResponses:       viper.GetBool("compress_responses"),
+		TenantIsolation:         viper.GetBool("tenant_isolation"),
+		AuditHistory:            viper.GetInt("audit_history"),
+		AuditPrivacy:            auditPrivacy(),
+		Shadow: shadow.Config{
+			URL:     viper.GetString("shadow_url"),
+			Percent: viper.GetFloat64("shadow_percent"),
+			Timeout: viper.GetDuration("shadow_timeout"),
+		},
+		Webhooks:     settings.Webhooks,
+		TenantLimits: settings.TenantLimits,
+	}
+	cfg.Clientset = clientset
+	if cfg.Secrets, err = probeSecrets(clientset); err != nil {
+		return err
+	}
+	if cfg.LeaderElection, err = leaderElection(); err != nil {
+		return err
+	}
+	if cfg.APIDeprecation, err = apiDeprecation(); err != nil {
+		return err
+	}
+	cfg.Standby = viper.GetBool("standby")
+	if cfg.LeaderElection.Enabled() && clientset == nil {
+		if cfg.LeaseClient, err = createLeaseClient(viper.GetString("storage.kubernetes.kubeconfig")); err != nil {
+			return err
+		}
+	}
+	if cfg.StatusTransitions, err = statusTransitions(); err != nil {
+		return err
+	}
+	if cfg.MutationHooks, err = mutationHooks(); err != nil {
+		return err
+	}
+	if cfg.DefaultLabels, err = defaultLabels(); err != nil {
+		return err
+	}
+	if cfg.ProbeValidators, err = probeValidators(); err != nil {
+		return err
+	}
+	if cfg.CacheControl, err = cacheControl(); err != nil {
+		return err
+	}
+	if cfg.ProbeTemplates, err = probeTemplates(); err != nil {
+		return err
+	}
+	if cfg.Notifications, err = notifications(); err != nil {
+		return err
+	}
+	if cfg.Events, err = probeEvents(); err != nil {
+		return err
+	}
+	if cfg.PrometheusProbes = prometheusProbes(); cfg.PrometheusProbes.Enabled() {
+		if cfg.DynamicClient, err = createDynamicClient(viper.GetString("storage.kubernetes.kubeconfig")); err != nil {
+			return err
+		}
+	}
+	sinks, closeAuditSinks, err := auditSinks(clientset)
+	if err != nil {
+		return err
+	}
+	cfg.AuditSinks = sinks
+	defer func() {
+		if err := closeAuditSinks(); err != nil {
+			slog.Error("Failed to close audit sink", "error", err)
 		}
-		log.Println("Server stopped serving new connections.")
 	}()
 
-	// Set up a channel to listen for OS signals for graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM) // Listen for Ctrl+C and termination signals
-
-	// Block until a signal is received
-	sig := <-quit
-	log.Printf("Received signal: %v. Initiating graceful shutdown...", sig)
+	srv, err := server.New(cfg)
+	if err != nil {
+		return err
+	}
 
-	// Stop the probe monitor first
-	cancelMonitor()
+	// Listen for Ctrl+C and termination signals to shut down gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go watchConfig(ctx, srv)
+	if err := srv.Run(ctx); err != nil {
+		return err
+	}
 
-	// Create a deadline context for the shutdown process
-	shutdownTimeout := viper.GetDuration("graceful_timeout")
-	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.GracefulTimeout)
 	defer cancel()
-
-	// Attempt graceful shutdown
-	if err := s.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
 
-	log.Println("Server gracefully shut down.")
+	slog.Info("Server gracefully shut down")
 
 	return nil
 }
@@ -227,13 +638,128 @@ func main() {
 		Short: "Start the API web server",
 		Long:  `Starts the HTTP server to expose the synthetics API.`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			// Validate that --data-dir is only used with --database-engine=local
-			databaseEngine := viper.GetString("database_engine")
-			dataDir := viper.GetString("data_dir")
+			if err := logging.Setup(os.Stdout, viper.GetString("log_level"), viper.GetString("log_format")); err != nil {
+				return err
+			}
+
+			if err := checkLegacyStorageKeys(viper.GetViper()); err != nil {
+				return err
+			}
 
-			if dataDir != "" && databaseEngine != "local" {
+			// Backend flags are rejected for other engines; config file stanzas of
+			// other engines are simply unused.
+			databaseEngine := viper.GetString("database_engine")
+			if cmd.Flags().Changed("data-dir") && databaseEngine != "local" {
 				return fmt.Errorf("--data-dir can only be used when --database-engine=local (current engine: %s)", databaseEngine)
 			}
+			if cmd.Flags().Changed("postgres-dsn") && databaseEngine != "postgres" {
+				return fmt.Errorf("--postgres-dsn can only be used when --database-engine=postgres (current engine: %s)", databaseEngine)
+			}
+			if cmd.Flags().Changed("shard-namespaces") && databaseEngine != "etcd" {
+				return fmt.Errorf("--shard-namespaces can only be used when --database-engine=etcd (current engine: %s)", databaseEngine)
+			}
+			for _, flag := range s3Flags {
+				if cmd.Flags().Changed(flag) && databaseEngine != "s3" {
+					return fmt.Errorf("--%s can only be used when --database-engine=s3 (current engine: %s)", flag, databaseEngine)
+				}
+			}
+			for _, flag := range redisFlags {
+				if cmd.Flags().Changed(flag) && databaseEngine != "redis" {
+					return fmt.Errorf("--%s can only be used when --database-engine=redis (current engine: %s)", flag, databaseEngine)
+				}
+			}
+			for _, flag := range memoryFlags {
+				if cmd.Flags().Changed(flag) && databaseEngine != "memory" {
+					return fmt.Errorf("--%s can only be used when --database-engine=memory (current engine: %s)", flag, databaseEngine)
+				}
+			}
+
+			tlsConfig := tlsreload.Config{
+				CertFile:     viper.GetString("tls_cert"),
+				KeyFile:      viper.GetString("tls_key"),
+				ClientCAFile: viper.GetString("tls_client_ca"),
+			}
+			if err := tlsConfig.Validate(); err != nil {
+				return err
+			}
+			if err := probeSchedule().Validate(); err != nil {
+				return fmt.Errorf("invalid default probe schedule: %w", err)
+			}
+			if ttl := viper.GetDuration("idempotency_key_ttl"); ttl <= 0 {
+				return fmt.Errorf("--idempotency-key-ttl must be positive, got %s", ttl)
+			}
+			if interval := viper.GetDuration("readiness_check_interval"); interval <= 0 {
+				return fmt.Errorf("--readiness-check-interval must be positive, got %s", interval)
+			}
+			if delay := viper.GetDuration("drain_delay"); delay < 0 {
+				return fmt.Errorf("--drain-delay must not be negative, got %s", delay)
+			}
+			if budget := viper.GetDuration("readiness_latency_budget"); budget <= 0 {
+				return fmt.Errorf("--readiness-latency-budget must be positive, got %s", budget)
+			}
+			if interval := viper.GetDuration("probe_monitor_interval"); interval <= 0 {
+				return fmt.Errorf("--probe-monitor-interval must be positive, got %s", interval)
+			}
+			if grace := viper.GetDuration("terminating_grace_period"); grace <= 0 {
+				return fmt.Errorf("--terminating-grace-period must be positive, got %s", grace)
+			}
+			if tolerance := viper.GetDuration("clock_skew_tolerance"); tolerance <= 0 {
+				return fmt.Errorf("--clock-skew-tolerance must be positive, got %s", tolerance)
+			}
+			if timeout := viper.GetDuration("webhook_timeout"); timeout <= 0 {
+				return fmt.Errorf("--webhook-timeout must be positive, got %s", timeout)
+			}
+			if err := writeLimit().Validate(); err != nil {
+				return err
+			}
+			if err := targetValidation().Validate(); err != nil {
+				return err
+			}
+			if attempts := viper.GetInt("webhook_max_attempts"); attempts <= 0 {
+				return fmt.Errorf("--webhook-max-attempts must be positive, got %d", attempts)
+			}
+			if _, err := statusTransitions(); err != nil {
+				return err
+			}
+			if _, err := mutationHooks(); err != nil {
+				return err
+			}
+			if _, err := defaultLabels(); err != nil {
+				return err
+			}
+			if _, err := probeValidators(); err != nil {
+				return err
+			}
+			if _, err := apiDeprecation(); err != nil {
+				return err
+			}
+			if _, err := probeTemplates(); err != nil {
+				return err
+			}
+			if err := prometheusProbes().Validate(); err != nil {
+				return err
+			}
+			if err := checkAuditSink(); err != nil {
+				return err
+			}
+			if history := viper.GetInt("audit_history"); history <= 0 {
+				return fmt.Errorf("--audit-history must be positive, got %d", history)
+			}
+			if err := auditPrivacy().Validate(); err != nil {
+				return err
+			}
+			if err := checkAgentCredentials(); err != nil {
+				return err
+			}
+			if err := checkProbeSecretKey(databaseEngine); err != nil {
+				return err
+			}
+			if duration := viper.GetDuration("leader_lease_duration"); duration <= 0 {
+				return fmt.Errorf("--leader-lease-duration must be positive, got %s", duration)
+			}
+			if viper.GetBool("standby") && (viper.GetString("leader_election_lease") == "" || viper.GetString("advertise_url") == "") {
+				return errors.New("--standby requires --leader-election-lease and --advertise-url")
+			}
 
 			return nil
 		},
@@ -243,43 +769,208 @@ func main() {
 			listenAddr := fmt.Sprintf("%s:%d", host, port)
 
 			if err := runWebServer(listenAddr); err != nil {
-				log.Fatalf("Web server failed: %v", err)
+				slog.Error("Web server failed", "error", err)
+				os.Exit(1)
 			}
 		},
 	}
 
 	// General Config flags
 	startCmd.Flags().String("config", "", "Path to Viper config")
-	startCmd.Flags().String("log-level", "info", "Log verbosity: debug, info")
+	startCmd.Flags().String("log-level", "info", "Log verbosity: debug, info, warn, error")
+	startCmd.Flags().String("log-format", "text", "Log output format: text, json")
 
 	// API Server flags
 	startCmd.Flags().IntP("port", "p", 8080, "Port to run the server on (e.g., 8080)")
 	startCmd.Flags().String("host", "0.0.0.0", "Host address to bind")
+	startCmd.Flags().Int("grpc-port", 0, "Port to serve the gRPC API on (disabled when 0)")
 	startCmd.Flags().Duration("read-timeout", 5*time.Second, "Max duration for reading the entire request (e.g. 5s)")
 	startCmd.Flags().Duration("write-timeout", 10*time.Second, "Max duration before timing out writes")
 	startCmd.Flags().Duration("graceful-timeout", 15*time.Second, "Time allowed for graceful shutdown")
-	startCmd.Flags().String("database-engine", "etcd", "Specifies the backend database engine. Supported: 'etcd', 'local'.")
+	startCmd.Flags().Duration("drain-delay", 0, "How long to keep serving with /readyz failing after a termination signal, before shutting down")
+	startCmd.Flags().String("admin-token", "", "Bearer token enabling POST /admin/drain to take the replica out of rotation, and /apikeys to manage API keys (disabled when empty)")
+	startCmd.Flags().Float64("api-key-rate-limit", apikeys.DefaultRateLimit, "Requests per second each API key minted without a rate limit may send (unlimited when 0)")
+	startCmd.Flags().Duration("api-key-refresh-interval", apikeys.DefaultRefreshInterval, "How often to reload the API keys from the store, so keys minted or revoked on other replicas take effect")
+	startCmd.Flags().Bool("enable-profiling", false, "Serve the Go profiler under /debug/pprof/ and a runtime snapshot under /debug/vars (requires --admin-token when set)")
+	startCmd.Flags().String("leader-election-lease", "", "Name of the Kubernetes Lease replicas elect a leader with (disabled when empty)")
+	startCmd.Flags().String("leader-election-namespace", "", "Namespace of the leader election Lease (defaults to --namespace)")
+	startCmd.Flags().Duration("leader-lease-duration", leader.DefaultLeaseDuration, "How long replicas wait for a leader that stopped renewing the Lease before taking over")
+	startCmd.Flags().String("advertise-url", "", "URL the other replicas reach this one at, naming it in the leader election Lease (defaults to the hostname)")
+	startCmd.Flags().Bool("standby", false, "Serve reads on replicas that are not the leader and forward writes to it (requires --leader-election-lease and --advertise-url)")
+	startCmd.Flags().String("tls-cert", "", "Path to a PEM certificate to serve HTTPS with (requires --tls-key)")
+	startCmd.Flags().String("tls-key", "", "Path to the PEM private key for --tls-cert")
+	startCmd.Flags().String("tls-client-ca", "", "Path to a PEM CA bundle; when set, clients must present a certificate signed by it")
+	startCmd.Flags().Duration("tls-reload-interval", tlsreload.DefaultInterval, "How often to re-read the TLS files so rotated certificates are picked up")
+	startCmd.Flags().Duration("readiness-check-interval", health.DefaultInterval, "How long /readyz reuses a backend check while the backend is healthy; failing backends are checked less often")
+	startCmd.Flags().Duration("readiness-latency-budget", health.DefaultLatencyBudget, "How long a backend check may take before /readyz reports the backend as failing")
+	startCmd.Flags().String("database-engine", defaultDatabaseEngine, "Specifies the backend database engine. Supported: 'etcd', 'crd', 'local', 'memory', 'postgres', 's3', 'redis'.")
 	startCmd.Flags().String("data-dir", "", "Directory for local storage (only valid with --database-engine=local, defaults to 'data')")
+	startCmd.Flags().String("memory-snapshot-file", "", "JSON file the memory store is restored from at startup and written to periodically (only valid with --database-engine=memory; probes are lost on restart when unset)")
+	startCmd.Flags().Duration("memory-snapshot-interval", probestore.DefaultSnapshotInterval, "How often to write --memory-snapshot-file")
+	startCmd.Flags().String("postgres-dsn", "", "PostgreSQL connection string (only valid with --database-engine=postgres)")
+	startCmd.Flags().String("s3-bucket", "", "Bucket to store probes in (only valid with --database-engine=s3)")
+	startCmd.Flags().String("s3-endpoint", "", "URL of the S3-compatible service, e.g. https://minio:9000 (defaults to AWS S3 in --s3-region)")
+	startCmd.Flags().String("s3-region", "", "Region to sign S3 requests for (defaults to 'us-east-1')")
+	startCmd.Flags().String("s3-prefix", "", "Prefix of every object key, to share a bucket with other data")
+	startCmd.Flags().Bool("s3-path-style", false, "Address the bucket as a path of --s3-endpoint instead of a subdomain, as most self-hosted services require")
+	startCmd.Flags().String("s3-access-key-id", "", "Access key ID to sign S3 requests with (requests are anonymous when unset)")
+	startCmd.Flags().String("s3-secret-access-key", "", "Secret access key for --s3-access-key-id")
+	startCmd.Flags().String("redis-url", "", "Redis server URL, e.g. redis://:password@redis:6379/0 or rediss:// for TLS (only valid with --database-engine=redis)")
+	startCmd.Flags().String("redis-prefix", "", "Prefix of every Redis key, to share a database with other data (defaults to 'rhobs-synthetics:')")
 	startCmd.Flags().String("kubeconfig", "", "Path to kubeconfig file (optional, for out-of-cluster development)")
-	startCmd.Flags().String("namespace", "rhobs", "The Kubernetes namespace to store probe configmaps in.")
+	startCmd.Flags().StringSlice("reserved-label-prefixes", nil, "Additional label prefixes (e.g. 'example.com/') that clients may not set or modify, on top of 'rhobs-synthetics/'")
+	startCmd.Flags().Duration("agent-heartbeat-ttl", assignment.DefaultHeartbeatTTL, "How long an agent keeps its probe assignments without re-registering")
+	startCmd.Flags().StringSlice("agent-affinity-keys", assignment.DefaultAffinityKeys, "Probe labels that must match the agent's labels when set on the probe")
+	startCmd.Flags().String("shadow-url", "", "Base URL of a shadow deployment to mirror read requests to (disabled when empty)")
+	startCmd.Flags().Float64("shadow-percent", 0, "Percentage of read requests to mirror to --shadow-url (0-100)")
+	startCmd.Flags().Duration("shadow-timeout", 5*time.Second, "Timeout for each mirrored request")
+	startCmd.Flags().Bool("tenant-isolation", false, "Scope requests that name a tenant (tenant header or client certificate organization) to that tenant's probes")
+	startCmd.Flags().Bool("read-only", false, "Reject requests that change probes with 503, e.g. during maintenance; reads keep working")
+	startCmd.Flags().Bool("compress-responses", true, "Gzip responses of at least 1 KiB for clients that send Accept-Encoding: gzip")
+	startCmd.Flags().String("v1-deprecation-date", "", "When version 1 of the API is deprecated (RFC 3339 or YYYY-MM-DD); its responses then carry Deprecation and successor Link headers")
+	startCmd.Flags().String("v1-sunset-date", "", "When version 1 of the API stops being served (RFC 3339 or YYYY-MM-DD), sent in the Sunset header; requires --v1-deprecation-date")
+	startCmd.Flags().Duration("idempotency-key-ttl", idempotency.DefaultTTL, "How long the response of a probe creation made with an Idempotency-Key is kept to replay to retries")
+	startCmd.Flags().Int("max-list-items", 10000, "Most probes a single GET /probes response may contain; larger results get 413 so callers paginate (0 disables the cap)")
+	startCmd.Flags().Int("max-concurrent-writes", 0, "Most probe creates, updates and deletes run against the store at once; further writes queue (0 disables the limit)")
+	startCmd.Flags().Int("write-queue-size", writelimit.DefaultMaxQueued, "Most probe writes waiting for --max-concurrent-writes; further writes get 503")
+	startCmd.Flags().Duration("write-queue-timeout", writelimit.DefaultQueueTimeout, "How long a probe write waits for --max-concurrent-writes before it gets 503")
+	startCmd.Flags().StringSlice("probe-target-schemes", targetcheck.DefaultSchemes, "URL schemes probe targets may use when created with validate=connectivity")
+	startCmd.Flags().Duration("probe-target-timeout", targetcheck.DefaultTimeout, "How long the name lookup and the HEAD request of validate=connectivity may each take")
+	startCmd.Flags().Duration("default-probe-interval", api.DefaultSchedule().Interval, "Interval given to probes created without one")
+	startCmd.Flags().Duration("default-probe-timeout", api.DefaultSchedule().Timeout, "Timeout given to probes created without one; may not exceed --default-probe-interval")
+	startCmd.Flags().String("default-probe-module", string(api.DefaultSchedule().Module), "Module given to probes created without one: http_2xx, tcp, icmp or dns")
+	startCmd.Flags().Duration("probe-monitor-interval", api.DefaultMonitorInterval, "How often every probe is listed to refresh the probe metrics; raise it for large ConfigMap backends")
+	startCmd.Flags().Duration("terminating-grace-period", api.DefaultTerminatingGracePeriod, "How long a probe may stay terminating before it is removed without its agent's confirmation")
+	startCmd.Flags().Duration("clock-skew-tolerance", clock.DefaultSkewTolerance, "How far ahead of the server clock the timestamps clients supply, such as those of probe results and heartbeats, may be")
+	startCmd.Flags().Duration("webhook-timeout", webhooks.DefaultTimeout, "Timeout of each attempt to deliver a probe event to a webhook")
+	startCmd.Flags().Int("webhook-max-attempts", webhooks.DefaultMaxAttempts, "Attempts to deliver a probe event to a webhook before giving up")
+	startCmd.Flags().String("prometheus-probes-namespace", "", "Namespace to render every probe into as a Prometheus Operator Probe resource (disabled when empty)")
+	startCmd.Flags().String("prometheus-probes-prober-url", "", "Blackbox exporter probe endpoint the Probe resources are scraped through, e.g. http://blackbox-exporter:9115/probe")
+	startCmd.Flags().Duration("prometheus-probes-interval", promprobes.DefaultInterval, "How often the Prometheus Probe resources are synced with the stored probes")
+	startCmd.Flags().String("audit-sink", "", "Where to write the audit log besides memory: stdout, file or events (Kubernetes Events; etcd and crd engines only)")
+	startCmd.Flags().String("audit-file", "", "File the audit log is appended to as JSON lines (required with --audit-sink=file)")
+	startCmd.Flags().Int("audit-history", audit.DefaultHistory, "Number of recent audit entries kept in memory for GET /audit")
+	startCmd.Flags().Duration("audit-retention", 0, "How long audit entries are kept in memory for GET /audit (0 keeps them until --audit-history newer ones push them out)")
+	startCmd.Flags().Bool("audit-truncate-ips", false, "Record the caller's /24 (IPv4) or /48 (IPv6) network in audit entries instead of its address")
+	startCmd.Flags().Bool("audit-hash-actors", false, "Record a keyed hash of the actor in audit entries instead of its name")
+	startCmd.Flags().String("audit-hash-key", "", "Key of at least 32 bytes used to hash actors; must match across replicas (random when empty)")
+	startCmd.Flags().Int("probe-result-retention", results.DefaultRetention, "Number of recent results kept in memory per probe for GET /probes/{probe_id}/results")
+	startCmd.Flags().String("page-token-key", "", "Key of at least 32 bytes pagination tokens are encrypted with; must match across replicas (random when empty)")
+	startCmd.Flags().String("agent-credential-key", "", "Key of at least 32 bytes used to sign agent bootstrap tokens and credentials; must match across replicas (disabled when empty)")
+	startCmd.Flags().Duration("agent-bootstrap-token-max-ttl", agentauth.DefaultMaxBootstrapTTL, "Longest lifetime an agent bootstrap token may be minted with")
+	startCmd.Flags().Duration("agent-credential-ttl", agentauth.DefaultCredentialTTL, "Lifetime of agent credentials; agents renew theirs before it ends")
+	startCmd.Flags().Bool("require-agent-credentials", false, "Reject agent registrations and assignment requests that carry no agent credential")
+	startCmd.Flags().String("probe-secret-key", "", "Key of at least 32 bytes the passwords and bearer tokens of probes are encrypted with (only valid with --database-engine=local; credentials are rejected when empty)")
+	startCmd.Flags().String("otel-endpoint", "", "OTLP/HTTP collector URL to export traces to, e.g. http://otel-collector:4318 (tracing is disabled when empty)")
+	startCmd.Flags().Float64("otel-sample-ratio", 0.1, "Fraction of new traces to record (0-1); requests with a sampled parent are always recorded")
+	startCmd.Flags().String("namespace", "rhobs", "The Kubernetes namespace to store probe configmaps or Probe resources in.")
+	startCmd.Flags().StringSlice("shard-namespaces", nil, "Namespaces to spread probe configmaps over by probe ID, instead of --namespace (only valid with --database-engine=etcd)")
 
 	// Bind flags to viper
-	viper.BindPFlag("port", startCmd.Flags().Lookup("port"))                         //nolint:errcheck
-	viper.BindPFlag("host", startCmd.Flags().Lookup("host"))                         //nolint:errcheck
-	viper.BindPFlag("read_timeout", startCmd.Flags().Lookup("read-timeout"))         //nolint:errcheck
-	viper.BindPFlag("write_timeout", startCmd.Flags().Lookup("write-timeout"))       //nolint:errcheck
-	viper.BindPFlag("graceful_timeout", startCmd.Flags().Lookup("graceful-timeout")) //nolint:errcheck
-	viper.BindPFlag("database_engine", startCmd.Flags().Lookup("database-engine"))   //nolint:errcheck
-	viper.BindPFlag("config", startCmd.Flags().Lookup("config"))                     //nolint:errcheck
-	viper.BindPFlag("log_level", startCmd.Flags().Lookup("log-level"))               //nolint:errcheck
-	viper.BindPFlag("kubeconfig", startCmd.Flags().Lookup("kubeconfig"))             //nolint:errcheck
-	viper.BindPFlag("namespace", startCmd.Flags().Lookup("namespace"))               //nolint:errcheck             //nolint:errcheck
+	viper.BindPFlag("port", startCmd.Flags().Lookup("port"))                                                   //nolint:errcheck
+	viper.BindPFlag("host", startCmd.Flags().Lookup("host"))                                                   //nolint:errcheck
+	viper.BindPFlag("grpc_port", startCmd.Flags().Lookup("grpc-port"))                                         //nolint:errcheck
+	viper.BindPFlag("read_timeout", startCmd.Flags().Lookup("read-timeout"))                                   //nolint:errcheck
+	viper.BindPFlag("write_timeout", startCmd.Flags().Lookup("write-timeout"))                                 //nolint:errcheck
+	viper.BindPFlag("graceful_timeout", startCmd.Flags().Lookup("graceful-timeout"))                           //nolint:errcheck
+	viper.BindPFlag("drain_delay", startCmd.Flags().Lookup("drain-delay"))                                     //nolint:errcheck
+	viper.BindPFlag("admin_token", startCmd.Flags().Lookup("admin-token"))                                     //nolint:errcheck
+	viper.BindPFlag("enable_profiling", startCmd.Flags().Lookup("enable-profiling"))                           //nolint:errcheck
+	viper.BindPFlag("api_key_rate_limit", startCmd.Flags().Lookup("api-key-rate-limit"))                       //nolint:errcheck
+	viper.BindPFlag("api_key_refresh_interval", startCmd.Flags().Lookup("api-key-refresh-interval"))           //nolint:errcheck
+	viper.BindPFlag("leader_election_lease", startCmd.Flags().Lookup("leader-election-lease"))                 //nolint:errcheck
+	viper.BindPFlag("leader_election_namespace", startCmd.Flags().Lookup("leader-election-namespace"))         //nolint:errcheck
+	viper.BindPFlag("leader_lease_duration", startCmd.Flags().Lookup("leader-lease-duration"))                 //nolint:errcheck
+	viper.BindPFlag("advertise_url", startCmd.Flags().Lookup("advertise-url"))                                 //nolint:errcheck
+	viper.BindPFlag("standby", startCmd.Flags().Lookup("standby"))                                             //nolint:errcheck
+	viper.BindPFlag("tls_cert", startCmd.Flags().Lookup("tls-cert"))                                           //nolint:errcheck
+	viper.BindPFlag("tls_key", startCmd.Flags().Lookup("tls-key"))                                             //nolint:errcheck
+	viper.BindPFlag("tls_client_ca", startCmd.Flags().Lookup("tls-client-ca"))                                 //nolint:errcheck
+	viper.BindPFlag("tls_reload_interval", startCmd.Flags().Lookup("tls-reload-interval"))                     //nolint:errcheck
+	viper.BindPFlag("readiness_check_interval", startCmd.Flags().Lookup("readiness-check-interval"))           //nolint:errcheck
+	viper.BindPFlag("readiness_latency_budget", startCmd.Flags().Lookup("readiness-latency-budget"))           //nolint:errcheck
+	viper.BindPFlag("database_engine", startCmd.Flags().Lookup("database-engine"))                             //nolint:errcheck
+	viper.BindPFlag("config", startCmd.Flags().Lookup("config"))                                               //nolint:errcheck
+	viper.BindPFlag("log_level", startCmd.Flags().Lookup("log-level"))                                         //nolint:errcheck
+	viper.BindPFlag("log_format", startCmd.Flags().Lookup("log-format"))                                       //nolint:errcheck
+	viper.BindPFlag("storage.kubernetes.kubeconfig", startCmd.Flags().Lookup("kubeconfig"))                    //nolint:errcheck
+	viper.BindPFlag("storage.kubernetes.namespace", startCmd.Flags().Lookup("namespace"))                      //nolint:errcheck
+	viper.BindPFlag("storage.kubernetes.shard_namespaces", startCmd.Flags().Lookup("shard-namespaces"))        //nolint:errcheck
+	viper.BindPFlag("storage.local.data_dir", startCmd.Flags().Lookup("data-dir"))                             //nolint:errcheck
+	viper.BindPFlag("storage.memory.snapshot_file", startCmd.Flags().Lookup("memory-snapshot-file"))           //nolint:errcheck
+	viper.BindPFlag("storage.memory.snapshot_interval", startCmd.Flags().Lookup("memory-snapshot-interval"))   //nolint:errcheck
+	viper.BindPFlag("storage.postgres.dsn", startCmd.Flags().Lookup("postgres-dsn"))                           //nolint:errcheck
+	viper.BindPFlag("storage.s3.bucket", startCmd.Flags().Lookup("s3-bucket"))                                 //nolint:errcheck
+	viper.BindPFlag("storage.s3.endpoint", startCmd.Flags().Lookup("s3-endpoint"))                             //nolint:errcheck
+	viper.BindPFlag("storage.s3.region", startCmd.Flags().Lookup("s3-region"))                                 //nolint:errcheck
+	viper.BindPFlag("storage.s3.prefix", startCmd.Flags().Lookup("s3-prefix"))                                 //nolint:errcheck
+	viper.BindPFlag("storage.s3.path_style", startCmd.Flags().Lookup("s3-path-style"))                         //nolint:errcheck
+	viper.BindPFlag("storage.s3.access_key_id", startCmd.Flags().Lookup("s3-access-key-id"))                   //nolint:errcheck
+	viper.BindPFlag("storage.s3.secret_access_key", startCmd.Flags().Lookup("s3-secret-access-key"))           //nolint:errcheck
+	viper.BindPFlag("storage.redis.url", startCmd.Flags().Lookup("redis-url"))                                 //nolint:errcheck
+	viper.BindPFlag("storage.redis.prefix", startCmd.Flags().Lookup("redis-prefix"))                           //nolint:errcheck
+	viper.BindPFlag("reserved_label_prefixes", startCmd.Flags().Lookup("reserved-label-prefixes"))             //nolint:errcheck
+	viper.BindPFlag("agent_heartbeat_ttl", startCmd.Flags().Lookup("agent-heartbeat-ttl"))                     //nolint:errcheck
+	viper.BindPFlag("agent_affinity_keys", startCmd.Flags().Lookup("agent-affinity-keys"))                     //nolint:errcheck
+	viper.BindPFlag("shadow_url", startCmd.Flags().Lookup("shadow-url"))                                       //nolint:errcheck
+	viper.BindPFlag("shadow_percent", startCmd.Flags().Lookup("shadow-percent"))                               //nolint:errcheck
+	viper.BindPFlag("shadow_timeout", startCmd.Flags().Lookup("shadow-timeout"))                               //nolint:errcheck
+	viper.BindPFlag("read_only", startCmd.Flags().Lookup("read-only"))                                         //nolint:errcheck
+	viper.BindPFlag("compress_responses", startCmd.Flags().Lookup("compress-responses"))                       //nolint:errcheck
+	viper.BindPFlag("v1_deprecation_date", startCmd.Flags().Lookup("v1-deprecation-date"))                     //nolint:errcheck
+	viper.BindPFlag("v1_sunset_date", startCmd.Flags().Lookup("v1-sunset-date"))                               //nolint:errcheck
+	viper.BindPFlag("idempotency_key_ttl", startCmd.Flags().Lookup("idempotency-key-ttl"))                     //nolint:errcheck
+	viper.BindPFlag("tenant_isolation", startCmd.Flags().Lookup("tenant-isolation"))                           //nolint:errcheck
+	viper.BindPFlag("max_list_items", startCmd.Flags().Lookup("max-list-items"))                               //nolint:errcheck
+	viper.BindPFlag("max_concurrent_writes", startCmd.Flags().Lookup("max-concurrent-writes"))                 //nolint:errcheck
+	viper.BindPFlag("write_queue_size", startCmd.Flags().Lookup("write-queue-size"))                           //nolint:errcheck
+	viper.BindPFlag("write_queue_timeout", startCmd.Flags().Lookup("write-queue-timeout"))                     //nolint:errcheck
+	viper.BindPFlag("audit_sink", startCmd.Flags().Lookup("audit-sink"))                                       //nolint:errcheck
+	viper.BindPFlag("audit_file", startCmd.Flags().Lookup("audit-file"))                                       //nolint:errcheck
+	viper.BindPFlag("audit_history", startCmd.Flags().Lookup("audit-history"))                                 //nolint:errcheck
+	viper.BindPFlag("audit_retention", startCmd.Flags().Lookup("audit-retention"))                             //nolint:errcheck
+	viper.BindPFlag("audit_truncate_ips", startCmd.Flags().Lookup("audit-truncate-ips"))                       //nolint:errcheck
+	viper.BindPFlag("audit_hash_actors", startCmd.Flags().Lookup("audit-hash-actors"))                         //nolint:errcheck
+	viper.BindPFlag("audit_hash_key", startCmd.Flags().Lookup("audit-hash-key"))                               //nolint:errcheck
+	viper.BindPFlag("probe_result_retention", startCmd.Flags().Lookup("probe-result-retention"))               //nolint:errcheck
+	viper.BindPFlag("probe_monitor_interval", startCmd.Flags().Lookup("probe-monitor-interval"))               //nolint:errcheck
+	viper.BindPFlag("terminating_grace_period", startCmd.Flags().Lookup("terminating-grace-period"))           //nolint:errcheck
+	viper.BindPFlag("clock_skew_tolerance", startCmd.Flags().Lookup("clock-skew-tolerance"))                   //nolint:errcheck
+	viper.BindPFlag("webhook_timeout", startCmd.Flags().Lookup("webhook-timeout"))                             //nolint:errcheck
+	viper.BindPFlag("webhook_max_attempts", startCmd.Flags().Lookup("webhook-max-attempts"))                   //nolint:errcheck
+	viper.BindPFlag("prometheus_probes_namespace", startCmd.Flags().Lookup("prometheus-probes-namespace"))     //nolint:errcheck
+	viper.BindPFlag("prometheus_probes_prober_url", startCmd.Flags().Lookup("prometheus-probes-prober-url"))   //nolint:errcheck
+	viper.BindPFlag("prometheus_probes_interval", startCmd.Flags().Lookup("prometheus-probes-interval"))       //nolint:errcheck
+	viper.BindPFlag("probe_target_schemes", startCmd.Flags().Lookup("probe-target-schemes"))                   //nolint:errcheck
+	viper.BindPFlag("probe_target_timeout", startCmd.Flags().Lookup("probe-target-timeout"))                   //nolint:errcheck
+	viper.BindPFlag("default_probe_interval", startCmd.Flags().Lookup("default-probe-interval"))               //nolint:errcheck
+	viper.BindPFlag("default_probe_timeout", startCmd.Flags().Lookup("default-probe-timeout"))                 //nolint:errcheck
+	viper.BindPFlag("default_probe_module", startCmd.Flags().Lookup("default-probe-module"))                   //nolint:errcheck
+	viper.BindPFlag("page_token_key", startCmd.Flags().Lookup("page-token-key"))                               //nolint:errcheck
+	viper.BindPFlag("agent_credential_key", startCmd.Flags().Lookup("agent-credential-key"))                   //nolint:errcheck
+	viper.BindPFlag("agent_bootstrap_token_max_ttl", startCmd.Flags().Lookup("agent-bootstrap-token-max-ttl")) //nolint:errcheck
+	viper.BindPFlag("agent_credential_ttl", startCmd.Flags().Lookup("agent-credential-ttl"))                   //nolint:errcheck
+	viper.BindPFlag("require_agent_credentials", startCmd.Flags().Lookup("require-agent-credentials"))         //nolint:errcheck
+	viper.BindPFlag("probe_secret_key", startCmd.Flags().Lookup("probe-secret-key"))                           //nolint:errcheck
+	viper.BindPFlag("otel_endpoint", startCmd.Flags().Lookup("otel-endpoint"))                                 //nolint:errcheck
+	viper.BindPFlag("otel_sample_ratio", startCmd.Flags().Lookup("otel-sample-ratio"))                         //nolint:errcheck
 
 	// Bind environment variables to viper
-	viper.BindEnv("namespace", "NAMESPACE") //nolint:errcheck
+	viper.BindEnv("storage.kubernetes.namespace", "NAMESPACE")             //nolint:errcheck
+	viper.BindEnv("storage.postgres.dsn", "POSTGRES_DSN")                  //nolint:errcheck
+	viper.BindEnv("storage.s3.region", "AWS_REGION")                       //nolint:errcheck
+	viper.BindEnv("storage.s3.access_key_id", "AWS_ACCESS_KEY_ID")         //nolint:errcheck
+	viper.BindEnv("storage.s3.secret_access_key", "AWS_SECRET_ACCESS_KEY") //nolint:errcheck
+	viper.BindEnv("storage.redis.url", "REDIS_URL")                        //nolint:errcheck
+	viper.BindEnv("page_token_key", "PAGE_TOKEN_KEY")                      //nolint:errcheck
+	viper.BindEnv("audit_hash_key", "AUDIT_HASH_KEY")                      //nolint:errcheck
+	viper.BindEnv("agent_credential_key", "AGENT_CREDENTIAL_KEY")          //nolint:errcheck
+	viper.BindEnv("probe_secret_key", "PROBE_SECRET_KEY")                  //nolint:errcheck
+	viper.BindEnv("admin_token", "ADMIN_TOKEN")                            //nolint:errcheck
+	viper.BindEnv("otel_endpoint", "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")   //nolint:errcheck
 
 	// Add commands to the root command
-	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(startCmd, newExportCmd(), newImportCmd(), newMigrateStoreCmd())
 
 	// Execute the root command. This parses the arguments and calls the appropriate command's Run function.
 	if err := rootCmd.Execute(); err != nil {
@@ -1,97 +1,38 @@
 package main
 
 import (
-	"net/http"
-	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/api"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestCreateRouter(t *testing.T) {
-	// Create a simple test handler for the validated API
-	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("test api"))
-	})
-
-	// Create a minimal swagger spec for testing
-	swagger := &openapi3.T{
-		OpenAPI: "3.0.0",
-		Info: &openapi3.Info{
-			Title:   "Test API",
-			Version: "1.0.0",
-		},
-	}
-
-	// Test with nil clientset (local storage mode)
-	router := createRouter(testHandler, nil, swagger)
-	assert.NotNil(t, router)
-
-	// Test health endpoints
-	testCases := []struct {
-		path           string
-		expectedStatus int
-		expectedBody   string
-	}{
-		{"/livez", http.StatusOK, "ok"},
-		{"/readyz", http.StatusOK, "ok"},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.path, func(t *testing.T) {
-			req := httptest.NewRequest("GET", tc.path, nil)
-			w := httptest.NewRecorder()
-
-			router.ServeHTTP(w, req)
-
-			assert.Equal(t, tc.expectedStatus, w.Code)
-			assert.Equal(t, tc.expectedBody, w.Body.String())
-		})
-	}
-
-	// Test docs endpoint
-	t.Run("/docs", func(t *testing.T) {
-		req := httptest.NewRequest("GET", "/docs", nil)
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
-	})
-
-	// Test OpenAPI spec endpoint
-	t.Run("/api/v1/openapi.json", func(t *testing.T) {
-		req := httptest.NewRequest("GET", "/api/v1/openapi.json", nil)
-		w := httptest.NewRecorder()
-
-		router.ServeHTTP(w, req)
-
-		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
-	})
-}
-
 func TestCreateProbeStore(t *testing.T) {
 	// Save original viper values
 	originalEngine := viper.GetString("database_engine")
-	originalNamespace := viper.GetString("namespace")
-	originalDataDir := viper.GetString("data_dir")
+	originalNamespace := viper.GetString("storage.kubernetes.namespace")
+	originalDataDir := viper.GetString("storage.local.data_dir")
+	originalDSN := viper.GetString("storage.postgres.dsn")
 
 	// Reset viper after test
 	defer func() {
 		viper.Set("database_engine", originalEngine)
-		viper.Set("namespace", originalNamespace)
-		viper.Set("data_dir", originalDataDir)
+		viper.Set("storage.kubernetes.namespace", originalNamespace)
+		viper.Set("storage.local.data_dir", originalDataDir)
+		viper.Set("storage.postgres.dsn", originalDSN)
 	}()
 
 	t.Run("local storage", func(t *testing.T) {
 		viper.Set("database_engine", "local")
-		viper.Set("data_dir", "")
+		viper.Set("storage.local.data_dir", "")
 
 		store, clientset, err := createProbeStore()
 
@@ -102,7 +43,7 @@ func TestCreateProbeStore(t *testing.T) {
 
 	t.Run("local storage with custom data dir", func(t *testing.T) {
 		viper.Set("database_engine", "local")
-		viper.Set("data_dir", "/tmp/test-probes")
+		viper.Set("storage.local.data_dir", t.TempDir())
 
 		store, clientset, err := createProbeStore()
 
@@ -111,6 +52,56 @@ func TestCreateProbeStore(t *testing.T) {
 		assert.Nil(t, clientset)
 	})
 
+	t.Run("postgres storage without dsn", func(t *testing.T) {
+		viper.Set("database_engine", "postgres")
+		viper.Set("storage.postgres.dsn", "")
+
+		store, clientset, err := createProbeStore()
+
+		require.Error(t, err)
+		assert.Nil(t, store)
+		assert.Nil(t, clientset)
+		assert.Contains(t, err.Error(), "failed to create postgres probe store")
+	})
+
+	t.Run("s3 storage without bucket", func(t *testing.T) {
+		viper.Set("database_engine", "s3")
+		viper.Set("storage.s3.bucket", "")
+
+		store, clientset, err := createProbeStore()
+
+		require.Error(t, err)
+		assert.Nil(t, store)
+		assert.Nil(t, clientset)
+		assert.Contains(t, err.Error(), "s3 bucket cannot be empty")
+	})
+
+	t.Run("redis storage without url", func(t *testing.T) {
+		viper.Set("database_engine", "redis")
+		viper.Set("storage.redis.url", "")
+
+		store, clientset, err := createProbeStore()
+
+		require.Error(t, err)
+		assert.Nil(t, store)
+		assert.Nil(t, clientset)
+		assert.Contains(t, err.Error(), "redis URL cannot be empty")
+	})
+
+	t.Run("memory storage with snapshot file", func(t *testing.T) {
+		defer viper.Set("storage.memory.snapshot_file", "")
+		file := filepath.Join(t.TempDir(), "probes.json")
+		viper.Set("database_engine", "memory")
+		viper.Set("storage.memory.snapshot_file", file)
+
+		store, clientset, err := createProbeStore()
+
+		require.NoError(t, err)
+		assert.NotNil(t, store)
+		assert.Nil(t, clientset)
+		assert.FileExists(t, file)
+	})
+
 	t.Run("unsupported database engine", func(t *testing.T) {
 		viper.Set("database_engine", "unsupported")
 
@@ -122,3 +113,267 @@ func TestCreateProbeStore(t *testing.T) {
 		assert.Contains(t, err.Error(), "unsupported database engine")
 	})
 }
+
+func TestStorageConfig(t *testing.T) {
+	defer viper.Set("storage.kubernetes.namespace", viper.GetString("storage.kubernetes.namespace"))
+	defer viper.Set("storage.postgres.dsn", viper.GetString("storage.postgres.dsn"))
+	defer viper.Set("storage.s3.path_style", viper.GetBool("storage.s3.path_style"))
+	defer viper.Set("storage.memory.snapshot_interval", viper.GetDuration("storage.memory.snapshot_interval"))
+
+	viper.Set("storage.kubernetes.namespace", "probes")
+	viper.Set("storage.postgres.dsn", "postgres://db/synthetics")
+	viper.Set("storage.s3.path_style", true)
+	viper.Set("storage.memory.snapshot_interval", "5m")
+
+	cfg, err := storageConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, "probes", cfg.Kubernetes.Namespace)
+	assert.Equal(t, "postgres://db/synthetics", cfg.Postgres.DSN)
+	assert.True(t, cfg.S3.PathStyle)
+	assert.Equal(t, 5*time.Minute, cfg.Memory.SnapshotInterval)
+}
+
+func TestCheckLegacyStorageKeys(t *testing.T) {
+	testCases := []struct {
+		name        string
+		config      string
+		expectedErr string
+	}{
+		{
+			name:   "storage stanzas",
+			config: "storage:\n  kubernetes:\n    namespace: rhobs\n  local:\n    data_dir: /data\n",
+		},
+		{
+			name:        "flat keys",
+			config:      "namespace: rhobs\npostgres_dsn: postgres://db\n",
+			expectedErr: "config file sets unsupported storage keys: namespace (use storage.kubernetes.namespace), postgres_dsn (use storage.postgres.dsn)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			require.NoError(t, v.ReadConfig(strings.NewReader(tc.config)))
+
+			err := checkLegacyStorageKeys(v)
+
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestMutationHooks(t *testing.T) {
+	defer viper.Set("mutation_hooks", viper.Get("mutation_hooks"))
+
+	viper.Set("mutation_hooks", []map[string]any{
+		{"name": "normalize_url"},
+		{"name": "default_labels", "labels": map[string]string{"team": "sre"}},
+	})
+	hooks, err := mutationHooks()
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+	assert.Equal(t, "normalize_url", hooks[0].Name())
+	assert.Equal(t, "default_labels", hooks[1].Name())
+
+	viper.Set("mutation_hooks", []map[string]any{{"name": "unknown"}})
+	_, err = mutationHooks()
+	assert.ErrorContains(t, err, `mutation_hooks[0]: unknown hook "unknown"`)
+}
+
+func TestStatusTransitions(t *testing.T) {
+	defer viper.Set("status_transitions", viper.Get("status_transitions"))
+
+	viper.Set("status_transitions", nil)
+	transitions, err := statusTransitions()
+	require.NoError(t, err)
+	assert.Equal(t, api.DefaultStatusTransitions(), transitions)
+
+	viper.Set("status_transitions", map[string][]string{"pending": {"active"}, "active": {"pending"}})
+	transitions, err = statusTransitions()
+	require.NoError(t, err)
+	assert.Equal(t, api.StatusTransitions{v1.Pending: {v1.Active}, v1.Active: {v1.Pending}}, transitions)
+
+	viper.Set("status_transitions", map[string][]string{"active": {"paused"}})
+	_, err = statusTransitions()
+	assert.ErrorContains(t, err, `invalid status_transitions: unknown probe status "paused"`)
+}
+
+func TestAPIDeprecation(t *testing.T) {
+	defer viper.Set("v1_deprecation_date", viper.Get("v1_deprecation_date"))
+	defer viper.Set("v1_sunset_date", viper.Get("v1_sunset_date"))
+
+	viper.Set("v1_deprecation_date", "")
+	viper.Set("v1_sunset_date", "")
+	deprecation, err := apiDeprecation()
+	require.NoError(t, err)
+	assert.Zero(t, deprecation)
+
+	viper.Set("v1_deprecation_date", "2026-11-01")
+	viper.Set("v1_sunset_date", "2027-05-01T12:00:00Z")
+	deprecation, err = apiDeprecation()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), deprecation.At)
+	assert.Equal(t, time.Date(2027, 5, 1, 12, 0, 0, 0, time.UTC), deprecation.Sunset)
+
+	viper.Set("v1_deprecation_date", "next month")
+	_, err = apiDeprecation()
+	assert.ErrorContains(t, err, "invalid --v1-deprecation-date")
+
+	viper.Set("v1_deprecation_date", "2027-06-01")
+	_, err = apiDeprecation()
+	assert.ErrorContains(t, err, "must not be before --v1-deprecation-date")
+
+	viper.Set("v1_deprecation_date", "")
+	_, err = apiDeprecation()
+	assert.ErrorContains(t, err, "--v1-sunset-date requires --v1-deprecation-date")
+}
+
+func TestCheckAuditSink(t *testing.T) {
+	defer viper.Set("audit_sink", viper.Get("audit_sink"))
+	defer viper.Set("audit_file", viper.Get("audit_file"))
+	defer viper.Set("database_engine", viper.Get("database_engine"))
+
+	testCases := []struct {
+		name        string
+		sink        string
+		file        string
+		engine      string
+		expectedErr string
+	}{
+		{name: "memory only", engine: "local"},
+		{name: "stdout", sink: "stdout", engine: "postgres"},
+		{name: "file", sink: "file", file: "audit.jsonl", engine: "local"},
+		{name: "file without path", sink: "file", engine: "local", expectedErr: "--audit-sink=file requires --audit-file"},
+		{name: "events", sink: "events", engine: "crd"},
+		{name: "events without kubernetes", sink: "events", engine: "local", expectedErr: "--audit-sink=events requires --database-engine=etcd or crd (current engine: local)"},
+		{name: "unknown", sink: "syslog", engine: "etcd", expectedErr: `unsupported --audit-sink "syslog", must be one of: stdout, file, events`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			viper.Set("audit_sink", tc.sink)
+			viper.Set("audit_file", tc.file)
+			viper.Set("database_engine", tc.engine)
+			err := checkAuditSink()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	defer viper.Set("cache_control", viper.Get("cache_control"))
+
+	viper.Set("cache_control", nil)
+	rules, err := cacheControl()
+	require.NoError(t, err)
+	assert.Nil(t, rules, "the server defaults apply")
+
+	viper.Set("cache_control", []map[string]any{{"route": "/probes/{probe_id}", "max_age": "30s", "private": true}})
+	rules, err = cacheControl()
+	require.NoError(t, err)
+	assert.Equal(t, []server.CacheControlRule{{Route: "/probes/{probe_id}", MaxAge: 30 * time.Second, Private: true}}, rules)
+}
+
+func TestProbeTemplates(t *testing.T) {
+	defer viper.Set("probe_templates", viper.Get("probe_templates"))
+
+	viper.Set("probe_templates", []map[string]any{{
+		"id":          "5b2c1c0e-3b8f-4d8e-9a55-0d3f0c6c2a11",
+		"name":        "console",
+		"url_pattern": "https://console.{region}.example.com",
+		"labels":      map[string]any{"team": "observability"},
+		"interval":    "1m",
+		"module":      "http_2xx",
+	}})
+	list, err := probeTemplates()
+	require.NoError(t, err)
+	assert.Equal(t, []server.ProbeTemplate{{
+		ID:         uuid.MustParse("5b2c1c0e-3b8f-4d8e-9a55-0d3f0c6c2a11"),
+		Name:       "console",
+		URLPattern: "https://console.{region}.example.com",
+		Labels:     map[string]string{"team": "observability"},
+		Interval:   "1m",
+		Module:     v1.Http2xx,
+	}}, list)
+
+	viper.Set("probe_templates", []map[string]any{{"id": "not-a-uuid", "name": "console"}})
+	_, err = probeTemplates()
+	assert.ErrorContains(t, err, "failed to parse probe_templates")
+}
+
+func TestPrometheusProbes(t *testing.T) {
+	for _, key := range []string{"prometheus_probes_namespace", "prometheus_probes_prober_url", "prometheus_probes_interval"} {
+		defer viper.Set(key, viper.Get(key))
+	}
+
+	viper.Set("prometheus_probes_namespace", "")
+	assert.False(t, prometheusProbes().Enabled())
+
+	viper.Set("prometheus_probes_namespace", "monitoring")
+	viper.Set("prometheus_probes_prober_url", "http://blackbox-exporter:9115/probe")
+	viper.Set("prometheus_probes_interval", "1m")
+	assert.Equal(t, server.PrometheusProbeConfig{
+		Namespace: "monitoring",
+		ProberURL: "http://blackbox-exporter:9115/probe",
+		Interval:  time.Minute,
+	}, prometheusProbes())
+	assert.NoError(t, prometheusProbes().Validate())
+
+	viper.Set("prometheus_probes_prober_url", "")
+	assert.Error(t, prometheusProbes().Validate(), "a prober URL is required once enabled")
+}
+
+func TestAuditPrivacy(t *testing.T) {
+	for _, key := range []string{"audit_retention", "audit_truncate_ips", "audit_hash_actors", "audit_hash_key"} {
+		defer viper.Set(key, viper.Get(key))
+	}
+
+	viper.Set("audit_retention", "720h")
+	viper.Set("audit_truncate_ips", true)
+	viper.Set("audit_hash_actors", true)
+	viper.Set("audit_hash_key", strings.Repeat("k", 32))
+	assert.Equal(t, server.AuditPrivacy{
+		Retention:   720 * time.Hour,
+		TruncateIPs: true,
+		HashActors:  true,
+		HashKey:     []byte(strings.Repeat("k", 32)),
+	}, auditPrivacy())
+	assert.NoError(t, auditPrivacy().Validate())
+
+	viper.Set("audit_hash_key", "too-short")
+	assert.Error(t, auditPrivacy().Validate())
+}
+
+func TestCheckAgentCredentials(t *testing.T) {
+	for _, key := range []string{"agent_credential_key", "agent_bootstrap_token_max_ttl", "require_agent_credentials"} {
+		defer viper.Set(key, viper.Get(key))
+	}
+
+	viper.Set("agent_credential_key", "")
+	viper.Set("agent_bootstrap_token_max_ttl", "24h")
+	viper.Set("require_agent_credentials", false)
+	assert.NoError(t, checkAgentCredentials(), "agent credentials are optional")
+
+	viper.Set("require_agent_credentials", true)
+	assert.EqualError(t, checkAgentCredentials(), "--require-agent-credentials requires --agent-credential-key")
+
+	viper.Set("agent_credential_key", strings.Repeat("k", 32))
+	assert.NoError(t, checkAgentCredentials())
+
+	viper.Set("agent_bootstrap_token_max_ttl", "-1h")
+	assert.Error(t, checkAgentCredentials())
+
+	viper.Set("agent_bootstrap_token_max_ttl", "24h")
+	viper.Set("agent_credential_key", "too-short")
+	assert.Error(t, checkAgentCredentials())
+}
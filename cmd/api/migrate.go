@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// maxReportedProbes caps the probes listed when a migration fails on
+// conflicts or mismatches.
+const maxReportedProbes = 5
+
+// migrationSide is the store probes are migrated from or to: an engine and
+// the config file holding its storage stanza, usually that of the API
+// deployment using the store.
+type migrationSide struct {
+	engine     string
+	configFile string
+}
+
+// open returns the store of the side. The engine flag wins over the
+// database_engine of the config file.
+func (s migrationSide) open(name string) (probestore.ProbeStorage, string, error) {
+	v := viper.New()
+	v.SetDefault("storage.kubernetes.namespace", "rhobs")
+	if s.configFile != "" {
+		v.SetConfigFile(s.configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, "", fmt.Errorf("failed to read the %s config: %w", name, err)
+		}
+		if err := checkLegacyStorageKeys(v); err != nil {
+			return nil, "", fmt.Errorf("%s config: %w", name, err)
+		}
+	}
+	engine := s.engine
+	if engine == "" {
+		engine = v.GetString("database_engine")
+	}
+	if engine == "" {
+		return nil, "", fmt.Errorf("no %s engine: set --%s or database_engine in --%s-config", name, name, name)
+	}
+	var cfg probestore.Config
+	if err := v.UnmarshalKey("storage", &cfg); err != nil {
+		return nil, "", fmt.Errorf("failed to read the %s storage config: %w", name, err)
+	}
+	store, _, err := openProbeStore(engine, cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", name, err)
+	}
+	return store, engine, nil
+}
+
+// newMigrateStoreCmd returns the 'migrate-store' subcommand, which copies the
+// probes of one store to another.
+func newMigrateStoreCmd() *cobra.Command {
+	var from, to migrationSide
+	var dryRun, verify bool
+	cmd := &cobra.Command{
+		Use:   "migrate-store",
+		Short: "Copy every probe from one storage engine to another",
+		Long: `Copies every probe of the source store into the destination store, keeping their IDs, status and labels.
+Probes the destination already holds are skipped, so the migration can be run again to pick up probes created since.
+Each store is configured by the storage stanzas of its config file, usually that of the API deployment using it.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == to {
+				return fmt.Errorf("the source and destination stores are the same; set a different --to or --to-config")
+			}
+			source, sourceEngine, err := from.open("from")
+			if err != nil {
+				return err
+			}
+			dest, destEngine, err := to.open("to")
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Migrating probes from %s to %s\n", sourceEngine, destEngine)
+
+			migrated, err := migrateProbes(cmd.Context(), source, dest, dryRun, out)
+			if memory, ok := dest.(*probestore.MemoryProbeStore); ok && !dryRun {
+				// Nothing else writes its snapshot before the command exits.
+				if err := memory.SaveSnapshot(cmd.Context()); err != nil {
+					return err
+				}
+			}
+			if err != nil {
+				return err
+			}
+			if !verify || dryRun {
+				return nil
+			}
+			return verifyMigration(cmd.Context(), migrated, dest, out)
+		},
+	}
+	cmd.Flags().StringVar(&from.engine, "from", "", "Engine to read probes from: etcd, crd, local, memory, postgres, s3 or redis (defaults to database_engine of --from-config)")
+	cmd.Flags().StringVar(&from.configFile, "from-config", "", "Config file whose storage stanzas configure the source store")
+	cmd.Flags().StringVar(&to.engine, "to", "", "Engine to write probes to: etcd, crd, local, memory, postgres, s3 or redis (defaults to database_engine of --to-config)")
+	cmd.Flags().StringVar(&to.configFile, "to-config", "", "Config file whose storage stanzas configure the destination store")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report what the migration would do without writing to the destination")
+	cmd.Flags().BoolVar(&verify, "verify", true, "Read every migrated probe back from the destination and compare it with the source")
+	return cmd
+}
+
+// migrateProbes copies the probes of source that dest lacks into dest,
+// reporting each on out, and returns the probes dest should now hold.
+func migrateProbes(ctx context.Context, source, dest probestore.ProbeStorage, dryRun bool, out io.Writer) ([]v1.ProbeObject, error) {
+	probes, err := source.ListProbes(ctx, probestore.ProbeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the source probes: %w", err)
+	}
+	existing, err := dest.ListProbes(ctx, probestore.ProbeSelector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the destination probes: %w", err)
+	}
+	migrated := make(map[uuid.UUID]bool, len(existing))
+	for _, probe := range existing {
+		migrated[probe.Id] = true
+	}
+
+	var created, skipped int
+	var conflicts []string
+	for i, probe := range probes {
+		action := "created"
+		switch {
+		case migrated[probe.Id]:
+			action = "skipped (already migrated)"
+			skipped++
+		case dryRun:
+			action = "would create"
+			created++
+		default:
+			urlHash := probestore.URLHash(probe.StaticUrl)
+			if probe.UrlHash == nil {
+				probe.UrlHash = &urlHash
+			}
+			if _, err := dest.CreateProbe(ctx, probe, probestore.URLHashLabel(urlHash)); err != nil {
+				if !k8serrors.IsAlreadyExists(err) {
+					return nil, fmt.Errorf("failed to create probe %s after migrating %d of %d probes: %w", probe.Id, created, len(probes), err)
+				}
+				action = "conflict (another probe has its static_url)"
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s)", probe.Id, probe.StaticUrl))
+				break
+			}
+			created++
+		}
+		_, _ = fmt.Fprintf(out, "[%d/%d] %s %s %s\n", i+1, len(probes), probe.Id, probe.StaticUrl, action)
+	}
+
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+	_, _ = fmt.Fprintf(out, "%d created, %d skipped, %d conflicting%s\n", created, skipped, len(conflicts), suffix)
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%d probes conflict with destination probes for the same static_url: %s", len(conflicts), listProbes(conflicts))
+	}
+	return probes, nil
+}
+
+// verifyMigration checks that dest holds every probe with the settings,
+// status and labels it has in the source.
+func verifyMigration(ctx context.Context, probes []v1.ProbeObject, dest probestore.ProbeStorage, out io.Writer) error {
+	stored, err := dest.ListProbes(ctx, probestore.ProbeSelector)
+	if err != nil {
+		return fmt.Errorf("failed to list the destination probes: %w", err)
+	}
+	byID := make(map[uuid.UUID]v1.ProbeObject, len(stored))
+	for _, probe := range stored {
+		byID[probe.Id] = probe
+	}
+	var mismatches []string
+	for _, probe := range probes {
+		got, ok := byID[probe.Id]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s (missing)", probe.Id))
+		case !sameProbe(probe, got):
+			mismatches = append(mismatches, fmt.Sprintf("%s (differs)", probe.Id))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("verification failed for %d of %d probes: %s", len(mismatches), len(probes), listProbes(mismatches))
+	}
+	_, _ = fmt.Fprintf(out, "Verified %d probes\n", len(probes))
+	return nil
+}
+
+// sameProbe reports whether two probes have the same settings, status and
+// labels. Timestamps and versions are set by each store.
+func sameProbe(a, b v1.ProbeObject) bool {
+	labels := func(p v1.ProbeObject) map[string]string {
+		if p.Labels == nil {
+			return nil
+		}
+		return *p.Labels
+	}
+	paused := func(p v1.ProbeObject) bool { return p.Paused != nil && *p.Paused }
+	return a.StaticUrl == b.StaticUrl &&
+		reflect.DeepEqual(a.AdditionalUrls, b.AdditionalUrls) &&
+		reflect.DeepEqual(a.Regions, b.Regions) &&
+		a.Status == b.Status &&
+		maps.Equal(labels(a), labels(b)) &&
+		reflect.DeepEqual(a.Interval, b.Interval) &&
+		reflect.DeepEqual(a.Timeout, b.Timeout) &&
+		reflect.DeepEqual(a.Module, b.Module) &&
+		reflect.DeepEqual(a.Alerting, b.Alerting) &&
+		reflect.DeepEqual(a.Owner, b.Owner) &&
+		reflect.DeepEqual(a.Assertions, b.Assertions) &&
+		reflect.DeepEqual(a.Auth, b.Auth) &&
+		reflect.DeepEqual(a.Dns, b.Dns) &&
+		reflect.DeepEqual(a.Tcp, b.Tcp) &&
+		reflect.DeepEqual(a.Icmp, b.Icmp) &&
+		paused(a) == paused(b) &&
+		reflect.DeepEqual(a.StatusReason, b.StatusReason) &&
+		reflect.DeepEqual(a.StatusMessage, b.StatusMessage) &&
+		reflect.DeepEqual(a.TargetStatuses, b.TargetStatuses)
+}
+
+// listProbes joins the first probes of a report, noting how many are left.
+func listProbes(probes []string) string {
+	listed := strings.Join(probes[:min(len(probes), maxReportedProbes)], ", ")
+	if more := len(probes) - maxReportedProbes; more > 0 {
+		listed += fmt.Sprintf(" and %d more", more)
+	}
+	return listed
+}
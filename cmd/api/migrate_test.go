@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateStore(t *testing.T) {
+	ctx := context.Background()
+	newStore := func() (probestore.ProbeStorage, string) {
+		dir := t.TempDir()
+		config := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(config, []byte("database_engine: local\nstorage:\n  local:\n    data_dir: "+dir+"\n"), 0o600))
+		store, err := probestore.NewLocalProbeStoreWithDir(dir)
+		require.NoError(t, err)
+		return store, config
+	}
+	create := func(store probestore.ProbeStorage, url string, status v1.StatusSchema) v1.ProbeObject {
+		interval := "1m"
+		probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: url, Status: status, Interval: &interval, Labels: &v1.LabelsSchema{"team": "sre"}}
+		created, err := store.CreateProbe(ctx, probe, probestore.URLHashLabel(probestore.URLHash(url)))
+		require.NoError(t, err)
+		return *created
+	}
+	run := func(args ...string) (string, error) {
+		command := newMigrateStoreCmd()
+		var out bytes.Buffer
+		command.SetOut(&out)
+		command.SetErr(io.Discard)
+		command.SetArgs(args)
+		err := command.Execute()
+		return out.String(), err
+	}
+
+	source, sourceConfig := newStore()
+	dest, destConfig := newStore()
+	active := create(source, "https://one.example.com", v1.Active)
+	create(source, "https://two.example.com", v1.Pending)
+
+	out, err := run("--from-config", sourceConfig, "--to-config", destConfig, "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Migrating probes from local to local\n")
+	assert.Contains(t, out, "2 created, 0 skipped, 0 conflicting (dry run)\n")
+	probes, err := dest.ListProbes(ctx, probestore.ProbeSelector)
+	require.NoError(t, err)
+	assert.Empty(t, probes, "dry runs write nothing")
+
+	out, err = run("--from-config", sourceConfig, "--to-config", destConfig)
+	require.NoError(t, err)
+	assert.Contains(t, out, "[1/2]")
+	assert.Contains(t, out, "2 created, 0 skipped, 0 conflicting\nVerified 2 probes\n")
+	migrated, err := dest.GetProbe(ctx, active.Id)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Active, migrated.Status, "the status is kept")
+	assert.Equal(t, "sre", (*migrated.Labels)["team"])
+	assert.Equal(t, active.Interval, migrated.Interval)
+
+	create(source, "https://three.example.com", v1.Pending)
+	out, err = run("--from-config", sourceConfig, "--to-config", destConfig)
+	require.NoError(t, err)
+	assert.Contains(t, out, "1 created, 2 skipped, 0 conflicting\nVerified 3 probes\n", "runs again pick up new probes")
+
+	create(source, "https://taken.example.com", v1.Pending)
+	create(dest, "https://taken.example.com", v1.Active)
+	_, err = run("--from-config", sourceConfig, "--to-config", destConfig)
+	assert.ErrorContains(t, err, "1 probes conflict with destination probes for the same static_url")
+
+	_, err = run("--from-config", sourceConfig, "--to-config", sourceConfig)
+	assert.ErrorContains(t, err, "the source and destination stores are the same")
+	_, err = run("--to-config", destConfig)
+	assert.ErrorContains(t, err, "no from engine")
+}
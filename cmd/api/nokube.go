@@ -0,0 +1,38 @@
+//go:build nokube
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
+)
+
+// defaultDatabaseEngine is the default of --database-engine; the etcd engine
+// is not built in.
+const defaultDatabaseEngine = "local"
+
+// errNoKubernetes is returned for the features that need a Kubernetes client.
+var errNoKubernetes = errors.New("the binary is built without Kubernetes support (nokube)")
+
+func createKubernetesProbeStore(engine string, _ probestore.KubernetesConfig) (probestore.ProbeStorage, server.KubernetesInterface, error) {
+	return nil, nil, fmt.Errorf("--database-engine=%s is not supported: %w", engine, errNoKubernetes)
+}
+
+func createLeaseClient(string) (server.KubernetesInterface, error) {
+	return nil, fmt.Errorf("leader election is not supported: %w", errNoKubernetes)
+}
+
+func createDynamicClient(string) (server.DynamicInterface, error) {
+	return nil, fmt.Errorf("prometheus probes are not supported: %w", errNoKubernetes)
+}
+
+func kubernetesSecretStore(server.KubernetesInterface) (server.SecretStore, error) {
+	return nil, fmt.Errorf("probe secrets in Kubernetes are not supported: %w", errNoKubernetes)
+}
+
+func eventSink(server.KubernetesInterface) (server.AuditSink, error) {
+	return nil, fmt.Errorf("--audit-sink=events is not supported: %w", errNoKubernetes)
+}
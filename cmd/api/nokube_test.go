@@ -0,0 +1,23 @@
+//go:build nokube
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProbeStore_NoKubernetes(t *testing.T) {
+	defer viper.Set("database_engine", viper.GetString("database_engine"))
+
+	for _, engine := range []string{"etcd", "crd"} {
+		viper.Set("database_engine", engine)
+		store, clientset, err := createProbeStore()
+		assert.ErrorContains(t, err, "--database-engine="+engine+" is not supported: the binary is built without Kubernetes support")
+		assert.Nil(t, store)
+		assert.Nil(t, clientset)
+	}
+	assert.Equal(t, "local", defaultDatabaseEngine)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/webhooks"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
+	"github.com/spf13/viper"
+)
+
+// reloadableSettings reads the settings a running server can change.
+func reloadableSettings() (server.Settings, error) {
+	settings := server.Settings{
+		ReservedLabelPrefixes: viper.GetStringSlice("reserved_label_prefixes"),
+		Webhooks: webhooks.Config{
+			Timeout:     viper.GetDuration("webhook_timeout"),
+			MaxAttempts: viper.GetInt("webhook_max_attempts"),
+		},
+	}
+	if settings.Webhooks.Timeout <= 0 {
+		return server.Settings{}, fmt.Errorf("webhook_timeout must be positive, got %s", settings.Webhooks.Timeout)
+	}
+	if settings.Webhooks.MaxAttempts <= 0 {
+		return server.Settings{}, fmt.Errorf("webhook_max_attempts must be positive, got %d", settings.Webhooks.MaxAttempts)
+	}
+	if err := viper.UnmarshalKey("tenant_limits", &settings.TenantLimits); err != nil {
+		return server.Settings{}, fmt.Errorf("failed to parse tenant_limits: %w", err)
+	}
+	return settings, nil
+}
+
+// configReloader applies the config file to a running server: the log level
+// and the server's Settings. Flags and environment variables still take
+// precedence over the file, as at startup.
+type configReloader struct {
+	srv *server.Server
+
+	mu       sync.Mutex
+	logLevel string
+}
+
+// reload re-reads the config file and applies it. A file that fails to read
+// or validate changes nothing. The file watcher has already read the file,
+// but only logs its errors, so it is read again here.
+func (r *configReloader) reload(trigger string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	var changed []string
+	if viper.ConfigFileUsed() != "" {
+		err = viper.ReadInConfig()
+	}
+	if err == nil {
+		changed, err = r.apply()
+	}
+	metrics.RecordConfigReload(err)
+	if err != nil {
+		slog.Error("Failed to reload the configuration, keeping the current settings", "trigger", trigger, "error", err)
+		return
+	}
+	slog.Info("Reloaded the configuration", "trigger", trigger, "changed", changed)
+}
+
+// apply validates every reloadable setting before applying any, and returns
+// the keys of those that changed.
+func (r *configReloader) apply() ([]string, error) {
+	level := viper.GetString("log_level")
+	if _, err := logging.ParseLevel(level); err != nil {
+		return nil, err
+	}
+	settings, err := reloadableSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	if level != r.logLevel {
+		_ = logging.SetLevel(level)
+		r.logLevel = level
+		changed = append(changed, "log_level")
+	}
+	return append(changed, r.srv.Reload(settings)...), nil
+}
+
+// watchConfig reloads the settings that can change without a restart when
+// the config file is written or the process receives SIGHUP, until ctx is
+// cancelled.
+func watchConfig(ctx context.Context, srv *server.Server) {
+	r := &configReloader{srv: srv, logLevel: viper.GetString("log_level")}
+	if viper.ConfigFileUsed() != "" {
+		viper.OnConfigChange(func(fsnotify.Event) { r.reload("file") })
+		viper.WatchConfig()
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			r.reload("SIGHUP")
+		}
+	}
+}
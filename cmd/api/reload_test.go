@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/server"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReloader_Apply(t *testing.T) {
+	for _, key := range []string{"log_level", "webhook_timeout", "webhook_max_attempts", "reserved_label_prefixes"} {
+		defer viper.Set(key, viper.Get(key))
+	}
+	viper.Set("log_level", "info")
+	viper.Set("webhook_timeout", 10*time.Second)
+	viper.Set("webhook_max_attempts", 5)
+	viper.Set("reserved_label_prefixes", []string{})
+
+	settings, err := reloadableSettings()
+	require.NoError(t, err)
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := server.New(server.Config{Store: store, Webhooks: settings.Webhooks, TenantLimits: settings.TenantLimits})
+	require.NoError(t, err)
+	r := &configReloader{srv: srv, logLevel: "info"}
+
+	changed, err := r.apply()
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	viper.Set("log_level", "debug")
+	viper.Set("reserved_label_prefixes", []string{"example.com/"})
+	changed, err = r.apply()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log_level", "reserved_label_prefixes"}, changed)
+
+	t.Run("invalid settings change nothing", func(t *testing.T) {
+		viper.Set("log_level", "verbose")
+		_, err := r.apply()
+		require.ErrorContains(t, err, "unknown log level")
+		assert.Equal(t, "debug", r.logLevel)
+
+		viper.Set("log_level", "info")
+		viper.Set("webhook_max_attempts", 0)
+		_, err = r.apply()
+		require.ErrorContains(t, err, "webhook_max_attempts must be positive")
+		assert.Equal(t, "debug", r.logLevel)
+	})
+}
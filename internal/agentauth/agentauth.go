@@ -0,0 +1,478 @@
+// Package agentauth issues and verifies the credentials agents authenticate
+// with, so that each agent holds its own instead of the fleet sharing one.
+//
+// Operators mint short-lived bootstrap tokens. An agent exchanges a bootstrap
+// token for a credential bound to its agent ID, presents that credential as a
+// bearer token from then on, and renews it before it expires. Both are
+// HMAC-signed, so any replica sharing the signing key verifies them; rotating
+// the key revokes every token and credential at once.
+//
+// The probe store keeps the little state there is: the bootstrap tokens
+// minted for any agent, which are used up by their first exchange, and the
+// revocations of the credentials of single agents. Each replica checks
+// credentials against its copy of the revocations, refreshed every refresh
+// interval.
+package agentauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// MinKeyLength is the shortest signing key accepted by NewIssuer.
+	MinKeyLength = 32
+	// DefaultBootstrapTTL is the lifetime of bootstrap tokens minted without
+	// one.
+	DefaultBootstrapTTL = time.Hour
+	// DefaultMaxBootstrapTTL is the longest lifetime a bootstrap token may be
+	// minted with when no other maximum is configured.
+	DefaultMaxBootstrapTTL = 24 * time.Hour
+	// DefaultCredentialTTL is the lifetime of credentials when no other is
+	// configured.
+	DefaultCredentialTTL = 30 * 24 * time.Hour
+	// DefaultRefreshInterval is how often Run reloads the revocations.
+	DefaultRefreshInterval = 30 * time.Second
+
+	// bootstrapPrefix and credentialPrefix start the two kinds of token, so
+	// one cannot be used in place of the other and bearer tokens meant for
+	// other services are ignored.
+	bootstrapPrefix  = "rhobs-bootstrap."
+	credentialPrefix = "rhobs-agent."
+
+	// usedTokenKind is the kind of the store records of the bootstrap tokens
+	// without an agent ID that were exchanged, by token ID.
+	usedTokenKind = "agent-bootstrap-tokens"
+	// revocationKind is the kind of the store records of revoked
+	// credentials, by agent ID.
+	revocationKind = "agent-credential-revocations"
+)
+
+var (
+	// ErrInvalid is returned for tokens that are malformed or whose signature
+	// does not match.
+	ErrInvalid = errors.New("invalid agent token")
+	// ErrExpired is returned for bootstrap tokens and credentials past
+	// their expiry.
+	ErrExpired = errors.New("agent token has expired")
+	// ErrOtherAgent is returned, followed by the agent ID, for bootstrap
+	// tokens minted for another agent.
+	ErrOtherAgent = errors.New("bootstrap token was minted for agent")
+	// ErrUsed is returned for bootstrap tokens without an agent ID that
+	// were exchanged before.
+	ErrUsed = errors.New("bootstrap token has already been used")
+	// ErrRevoked is returned for credentials issued before the credentials
+	// of their agent were revoked.
+	ErrRevoked = errors.New("agent credential has been revoked")
+	// ErrRegistered is returned when a bootstrap token without an agent ID
+	// is exchanged for an agent that is registered; the agent renews its
+	// credential instead, or is given a token minted for it.
+	ErrRegistered = errors.New("agent is already registered; renew its credential or exchange a bootstrap token minted for it")
+	// ErrNoRecords is returned for operations that need state the probe
+	// store does not keep.
+	ErrNoRecords = errors.New("the probe store does not keep the records agent credentials need")
+)
+
+// claims is the signed payload of a token.
+type claims struct {
+	// ID tells tokens apart in logs and audit entries.
+	ID string `json:"id"`
+	// AgentID is the agent a credential belongs to, or the only agent a
+	// bootstrap token may be exchanged for. Empty bootstrap tokens are valid
+	// for any agent.
+	AgentID   string    `json:"agent_id,omitempty"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp,omitzero"`
+}
+
+// BootstrapToken is a minted bootstrap token.
+type BootstrapToken struct {
+	ID        string
+	Token     string
+	AgentID   string
+	ExpiresAt time.Time
+}
+
+// Credential is the credential of an agent.
+type Credential struct {
+	ID         string
+	Credential string
+	AgentID    string
+	IssuedAt   time.Time
+	ExpiresAt  time.Time
+}
+
+// revocation is the data of a revocation record.
+type revocation struct {
+	// RevokedAt is when the credentials of the agent were revoked; those
+	// issued at or before it are rejected.
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// usedToken is the data of the record of an exchanged bootstrap token.
+type usedToken struct {
+	AgentID   string    `json:"agent_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Issuer signs and verifies tokens with a shared key.
+type Issuer struct {
+	// Records keeps the exchanged bootstrap tokens and the revocations.
+	// Without it bootstrap tokens without an agent ID are refused, and
+	// credentials cannot be revoked.
+	Records probestore.RecordStore
+	// Registered reports whether an agent is registered, so bootstrap
+	// tokens without an agent ID cannot take over its ID. Nil skips the
+	// check.
+	Registered func(ctx context.Context, agentID string) (bool, error)
+
+	key           []byte
+	maxTTL        time.Duration
+	credentialTTL time.Duration
+	now           func() time.Time
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewIssuer returns an Issuer that signs with key, mints bootstrap tokens of
+// at most maxTTL and issues credentials valid for credentialTTL; zero
+// selects DefaultMaxBootstrapTTL and DefaultCredentialTTL. All replicas must
+// use the same key.
+func NewIssuer(key []byte, maxTTL, credentialTTL time.Duration) (*Issuer, error) {
+	if len(key) < MinKeyLength {
+		return nil, fmt.Errorf("agent credential key must be at least %d bytes, got %d", MinKeyLength, len(key))
+	}
+	if maxTTL < 0 {
+		return nil, fmt.Errorf("maximum bootstrap token lifetime must be positive, got %s", maxTTL)
+	}
+	if credentialTTL < 0 {
+		return nil, fmt.Errorf("agent credential lifetime must be positive, got %s", credentialTTL)
+	}
+	if maxTTL == 0 {
+		maxTTL = DefaultMaxBootstrapTTL
+	}
+	if credentialTTL == 0 {
+		credentialTTL = DefaultCredentialTTL
+	}
+	return &Issuer{
+		key:           key,
+		maxTTL:        maxTTL,
+		credentialTTL: credentialTTL,
+		now:           time.Now,
+		revoked:       map[string]time.Time{},
+	}, nil
+}
+
+// MintBootstrap returns a bootstrap token valid for ttl, for the given agent
+// only unless agentID is empty. A zero ttl selects DefaultBootstrapTTL,
+// capped at the issuer's maximum.
+func (i *Issuer) MintBootstrap(agentID string, ttl time.Duration) (BootstrapToken, error) {
+	if ttl == 0 {
+		ttl = min(DefaultBootstrapTTL, i.maxTTL)
+	}
+	if ttl < 0 || ttl > i.maxTTL {
+		return BootstrapToken{}, fmt.Errorf("bootstrap token lifetime must be positive and at most %s, got %s", i.maxTTL, ttl)
+	}
+	now := i.now().UTC().Truncate(time.Second)
+	c := claims{ID: newID(), AgentID: agentID, IssuedAt: now, ExpiresAt: now.Add(ttl)}
+	token, err := i.encode(bootstrapPrefix, c)
+	if err != nil {
+		return BootstrapToken{}, err
+	}
+	return BootstrapToken{ID: c.ID, Token: token, AgentID: agentID, ExpiresAt: c.ExpiresAt}, nil
+}
+
+// Exchange verifies a bootstrap token and returns a credential for the agent.
+// Tokens minted for an agent can be exchanged by it until they expire. Tokens
+// minted for any agent are used up by their first exchange, and are refused
+// for agents that are registered.
+func (i *Issuer) Exchange(ctx context.Context, bootstrapToken, agentID string) (Credential, error) {
+	c, err := i.decode(bootstrapPrefix, bootstrapToken)
+	if err != nil {
+		return Credential{}, err
+	}
+	if !i.now().Before(c.ExpiresAt) {
+		return Credential{}, ErrExpired
+	}
+	if c.AgentID != "" {
+		if c.AgentID != agentID {
+			return Credential{}, fmt.Errorf("%w %s", ErrOtherAgent, c.AgentID)
+		}
+		return i.issue(agentID)
+	}
+
+	if i.Records == nil {
+		return Credential{}, ErrNoRecords
+	}
+	if i.Registered != nil {
+		registered, err := i.Registered(ctx, agentID)
+		if err != nil {
+			return Credential{}, err
+		}
+		if registered {
+			return Credential{}, ErrRegistered
+		}
+	}
+	data, err := json.Marshal(usedToken{AgentID: agentID, ExpiresAt: c.ExpiresAt})
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to encode bootstrap token record: %w", err)
+	}
+	err = i.Records.CreateRecord(ctx, usedTokenKind, probestore.Record{ID: c.ID, Data: data})
+	if k8serrors.IsAlreadyExists(err) {
+		return Credential{}, ErrUsed
+	}
+	if err != nil {
+		return Credential{}, fmt.Errorf("failed to record bootstrap token use: %w", err)
+	}
+	return i.issue(agentID)
+}
+
+// Renew verifies a credential and returns a new one for the same agent, valid
+// for a full lifetime from now.
+func (i *Issuer) Renew(credential string) (Credential, error) {
+	agentID, err := i.Verify(credential)
+	if err != nil {
+		return Credential{}, err
+	}
+	return i.issue(agentID)
+}
+
+func (i *Issuer) issue(agentID string) (Credential, error) {
+	now := i.now().UTC().Truncate(time.Second)
+	c := claims{ID: newID(), AgentID: agentID, IssuedAt: now, ExpiresAt: now.Add(i.credentialTTL)}
+	token, err := i.encode(credentialPrefix, c)
+	if err != nil {
+		return Credential{}, err
+	}
+	return Credential{ID: c.ID, Credential: token, AgentID: agentID, IssuedAt: c.IssuedAt, ExpiresAt: c.ExpiresAt}, nil
+}
+
+// Verify returns the agent a credential belongs to. Credentials issued before
+// expiries were recorded in them expire one credential lifetime after they
+// were issued.
+func (i *Issuer) Verify(credential string) (string, error) {
+	c, err := i.decode(credentialPrefix, credential)
+	if err != nil {
+		return "", err
+	}
+	expiresAt := c.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = c.IssuedAt.Add(i.credentialTTL)
+	}
+	if !i.now().Before(expiresAt) {
+		return "", ErrExpired
+	}
+	i.mu.RLock()
+	revokedAt, ok := i.revoked[c.AgentID]
+	i.mu.RUnlock()
+	if ok && !c.IssuedAt.After(revokedAt) {
+		return "", ErrRevoked
+	}
+	return c.AgentID, nil
+}
+
+// Revoke rejects every credential issued to the agent so far, on this replica
+// at once and on the others from their next refresh. The agent needs a new
+// bootstrap token to get another credential.
+func (i *Issuer) Revoke(ctx context.Context, agentID string) (time.Time, error) {
+	if i.Records == nil {
+		return time.Time{}, ErrNoRecords
+	}
+	revokedAt := i.now().UTC().Truncate(time.Second)
+	data, err := json.Marshal(revocation{RevokedAt: revokedAt})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to encode revocation: %w", err)
+	}
+	if err := i.Records.PutRecord(ctx, revocationKind, probestore.Record{ID: agentID, Data: data}); err != nil {
+		return time.Time{}, fmt.Errorf("failed to store revocation: %w", err)
+	}
+	i.mu.Lock()
+	i.revoked[agentID] = revokedAt
+	i.mu.Unlock()
+	return revokedAt, nil
+}
+
+// Refresh reloads the revocations, and removes the records that are no
+// longer needed: revocations older than the credential lifetime, whose
+// credentials have all expired, and exchanged bootstrap tokens past their
+// expiry.
+func (i *Issuer) Refresh(ctx context.Context) error {
+	if i.Records == nil {
+		return nil
+	}
+	now := i.now()
+	records, err := i.Records.ListRecords(ctx, revocationKind)
+	if err != nil {
+		return fmt.Errorf("failed to list credential revocations: %w", err)
+	}
+	revoked := make(map[string]time.Time, len(records))
+	for _, record := range records {
+		var r revocation
+		if err := json.Unmarshal(record.Data, &r); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling credential revocation", "agent_id", record.ID, "error", err)
+			continue
+		}
+		if now.Sub(r.RevokedAt) > i.credentialTTL {
+			i.deleteRecord(ctx, revocationKind, record.ID)
+			continue
+		}
+		revoked[record.ID] = r.RevokedAt
+	}
+	i.mu.Lock()
+	i.revoked = revoked
+	i.mu.Unlock()
+
+	records, err = i.Records.ListRecords(ctx, usedTokenKind)
+	if err != nil {
+		return fmt.Errorf("failed to list used bootstrap tokens: %w", err)
+	}
+	for _, record := range records {
+		var used usedToken
+		if err := json.Unmarshal(record.Data, &used); err == nil && now.Before(used.ExpiresAt) {
+			continue
+		}
+		i.deleteRecord(ctx, usedTokenKind, record.ID)
+	}
+	return nil
+}
+
+func (i *Issuer) deleteRecord(ctx context.Context, kind, id string) {
+	if err := i.Records.DeleteRecord(ctx, kind, id); err != nil && !k8serrors.IsNotFound(err) {
+		slog.WarnContext(ctx, "Failed to remove agent credential record", "kind", kind, "id", id, "error", err)
+	}
+}
+
+// Run refreshes the revocations every interval until ctx is cancelled.
+// Failed refreshes keep the previous revocations.
+func (i *Issuer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := i.Refresh(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to refresh agent credential revocations, keeping the previous ones", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (i *Issuer) encode(prefix string, c claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode agent token: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return prefix + enc.EncodeToString(payload) + "." + enc.EncodeToString(i.sign(prefix, payload)), nil
+}
+
+func (i *Issuer) decode(prefix, token string) (claims, error) {
+	rest, ok := strings.CutPrefix(token, prefix)
+	if !ok {
+		return claims{}, ErrInvalid
+	}
+	enc := base64.RawURLEncoding
+	encPayload, encSig, ok := strings.Cut(rest, ".")
+	if !ok {
+		return claims{}, ErrInvalid
+	}
+	payload, err := enc.DecodeString(encPayload)
+	if err != nil {
+		return claims{}, ErrInvalid
+	}
+	sig, err := enc.DecodeString(encSig)
+	if err != nil || !hmac.Equal(sig, i.sign(prefix, payload)) {
+		return claims{}, ErrInvalid
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return claims{}, ErrInvalid
+	}
+	return c, nil
+}
+
+// sign covers the prefix too, so a token cannot be relabelled as the other
+// kind.
+func (i *Issuer) sign(prefix string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, i.key)
+	mac.Write([]byte(prefix))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func newID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(id)
+}
+
+// BearerToken returns the bearer token of the request's Authorization
+// header, or "".
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+type (
+	agentKey struct{}
+	tokenKey struct{}
+)
+
+// AgentFromContext returns the agent whose valid credential the request
+// carried, or "".
+func AgentFromContext(ctx context.Context) string {
+	agentID, _ := ctx.Value(agentKey{}).(string)
+	return agentID
+}
+
+// TokenFromContext returns the request's bearer token, for handlers that
+// accept bootstrap tokens.
+func TokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+// Middleware attaches the request's bearer token, and the agent its
+// credential belongs to, to the request context. Requests are never rejected
+// here; handlers decide what they require. A nil issuer only attaches the
+// token.
+func Middleware(issuer *Issuer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := BearerToken(r)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := context.WithValue(r.Context(), tokenKey{}, token)
+			if issuer != nil {
+				if agentID, err := issuer.Verify(token); err == nil {
+					ctx = context.WithValue(ctx, agentKey{}, agentID)
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package agentauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIssuer(t *testing.T, key string) *Issuer {
+	t.Helper()
+	issuer, err := NewIssuer([]byte(strings.Repeat(key, MinKeyLength)), 0, 0)
+	require.NoError(t, err)
+	issuer.Records = probestore.NewMemoryProbeStore()
+	return issuer
+}
+
+func TestNewIssuer(t *testing.T) {
+	_, err := NewIssuer([]byte("short"), 0, 0)
+	assert.Error(t, err)
+	_, err = NewIssuer([]byte(strings.Repeat("k", MinKeyLength)), -time.Hour, 0)
+	assert.Error(t, err)
+	_, err = NewIssuer([]byte(strings.Repeat("k", MinKeyLength)), 0, -time.Hour)
+	assert.Error(t, err)
+}
+
+func TestIssuer_MintBootstrap(t *testing.T) {
+	issuer := newTestIssuer(t, "k")
+
+	token, err := issuer.MintBootstrap("", 0)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token.Token, bootstrapPrefix))
+	assert.WithinDuration(t, time.Now().Add(DefaultBootstrapTTL), token.ExpiresAt, 2*time.Second)
+
+	_, err = issuer.MintBootstrap("", -time.Minute)
+	assert.Error(t, err)
+	_, err = issuer.MintBootstrap("", DefaultMaxBootstrapTTL+time.Second)
+	assert.Error(t, err)
+}
+
+func TestIssuer_Exchange(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "k")
+
+	t.Run("any agent", func(t *testing.T) {
+		token, err := issuer.MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		cred, err := issuer.Exchange(ctx, token.Token, "agent-1")
+		require.NoError(t, err)
+		assert.Equal(t, "agent-1", cred.AgentID)
+
+		agentID, err := issuer.Verify(cred.Credential)
+		require.NoError(t, err)
+		assert.Equal(t, "agent-1", agentID)
+		assert.Equal(t, cred.IssuedAt.Add(DefaultCredentialTTL), cred.ExpiresAt)
+	})
+
+	t.Run("any agent once", func(t *testing.T) {
+		token, err := issuer.MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		_, err = issuer.Exchange(ctx, token.Token, "agent-2")
+		require.NoError(t, err)
+		_, err = issuer.Exchange(ctx, token.Token, "agent-3")
+		assert.ErrorIs(t, err, ErrUsed)
+
+		other := newTestIssuer(t, "k")
+		other.Records = issuer.Records
+		_, err = other.Exchange(ctx, token.Token, "agent-3")
+		assert.ErrorIs(t, err, ErrUsed, "replicas share the used tokens")
+	})
+
+	t.Run("any agent but registered ones", func(t *testing.T) {
+		registered := newTestIssuer(t, "k")
+		registered.Registered = func(_ context.Context, agentID string) (bool, error) {
+			return agentID == "agent-1", nil
+		}
+		token, err := registered.MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		_, err = registered.Exchange(ctx, token.Token, "agent-1")
+		assert.ErrorIs(t, err, ErrRegistered)
+		_, err = registered.Exchange(ctx, token.Token, "agent-2")
+		assert.NoError(t, err, "refused exchanges do not use the token up")
+
+		token, err = registered.MintBootstrap("agent-1", time.Minute)
+		require.NoError(t, err)
+		_, err = registered.Exchange(ctx, token.Token, "agent-1")
+		assert.NoError(t, err, "tokens minted for the agent are accepted")
+
+		failing := newTestIssuer(t, "k")
+		failing.Registered = func(context.Context, string) (bool, error) { return false, errors.New("boom") }
+		token, err = failing.MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		_, err = failing.Exchange(ctx, token.Token, "agent-2")
+		assert.EqualError(t, err, "boom")
+	})
+
+	t.Run("any agent without records", func(t *testing.T) {
+		stateless := newTestIssuer(t, "k")
+		stateless.Records = nil
+		token, err := stateless.MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		_, err = stateless.Exchange(ctx, token.Token, "agent-1")
+		assert.ErrorIs(t, err, ErrNoRecords)
+
+		token, err = stateless.MintBootstrap("agent-1", time.Minute)
+		require.NoError(t, err)
+		_, err = stateless.Exchange(ctx, token.Token, "agent-1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("pinned agent", func(t *testing.T) {
+		token, err := issuer.MintBootstrap("agent-1", time.Minute)
+		require.NoError(t, err)
+		_, err = issuer.Exchange(ctx, token.Token, "agent-2")
+		assert.ErrorIs(t, err, ErrOtherAgent)
+		assert.EqualError(t, err, "bootstrap token was minted for agent agent-1")
+		_, err = issuer.Exchange(ctx, token.Token, "agent-1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now := time.Now().UTC()
+		token, err := issuer.encode(bootstrapPrefix, claims{ID: newID(), IssuedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)})
+		require.NoError(t, err)
+		_, err = issuer.Exchange(ctx, token, "agent-1")
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("tokens are not interchangeable", func(t *testing.T) {
+		token, err := issuer.MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		_, err = issuer.Verify(token.Token)
+		assert.ErrorIs(t, err, ErrInvalid, "bootstrap tokens are not credentials")
+
+		relabelled := credentialPrefix + strings.TrimPrefix(token.Token, bootstrapPrefix)
+		_, err = issuer.Verify(relabelled)
+		assert.ErrorIs(t, err, ErrInvalid, "the signature covers the kind of token")
+
+		cred, err := issuer.Exchange(ctx, token.Token, "agent-1")
+		require.NoError(t, err)
+		_, err = issuer.Exchange(ctx, cred.Credential, "agent-1")
+		assert.ErrorIs(t, err, ErrInvalid, "credentials are not bootstrap tokens")
+	})
+
+	t.Run("other keys", func(t *testing.T) {
+		token, err := newTestIssuer(t, "x").MintBootstrap("", time.Minute)
+		require.NoError(t, err)
+		_, err = issuer.Exchange(ctx, token.Token, "agent-1")
+		assert.ErrorIs(t, err, ErrInvalid)
+	})
+}
+
+func TestIssuer_Expiry(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "k")
+	now := time.Now()
+	issuer.now = func() time.Time { return now }
+
+	token, err := issuer.MintBootstrap("agent-1", time.Minute)
+	require.NoError(t, err)
+	cred, err := issuer.Exchange(ctx, token.Token, "agent-1")
+	require.NoError(t, err)
+
+	now = now.Add(DefaultCredentialTTL - time.Minute)
+	renewed, err := issuer.Renew(cred.Credential)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-1", renewed.AgentID)
+	assert.True(t, renewed.ExpiresAt.After(cred.ExpiresAt))
+
+	now = now.Add(time.Minute)
+	_, err = issuer.Verify(cred.Credential)
+	assert.ErrorIs(t, err, ErrExpired)
+	_, err = issuer.Renew(cred.Credential)
+	assert.ErrorIs(t, err, ErrExpired, "expired credentials cannot be renewed")
+	_, err = issuer.Verify(renewed.Credential)
+	assert.NoError(t, err)
+
+	legacy, err := issuer.encode(credentialPrefix, claims{ID: newID(), AgentID: "agent-1", IssuedAt: now.Add(-DefaultCredentialTTL)})
+	require.NoError(t, err)
+	_, err = issuer.Verify(legacy)
+	assert.ErrorIs(t, err, ErrExpired, "credentials without an expiry expire a lifetime after they were issued")
+}
+
+func TestIssuer_Revoke(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "k")
+	now := time.Now()
+	issuer.now = func() time.Time { return now }
+	other := newTestIssuer(t, "k")
+	other.Records = issuer.Records
+	other.now = issuer.now
+
+	issue := func(agentID string) string {
+		t.Helper()
+		token, err := issuer.MintBootstrap(agentID, time.Minute)
+		require.NoError(t, err)
+		cred, err := issuer.Exchange(ctx, token.Token, agentID)
+		require.NoError(t, err)
+		return cred.Credential
+	}
+	revoked, kept := issue("agent-1"), issue("agent-2")
+
+	now = now.Add(time.Second)
+	_, err := issuer.Revoke(ctx, "agent-1")
+	require.NoError(t, err)
+	_, err = issuer.Verify(revoked)
+	assert.ErrorIs(t, err, ErrRevoked)
+	_, err = issuer.Renew(revoked)
+	assert.ErrorIs(t, err, ErrRevoked, "revoked credentials cannot be renewed")
+	_, err = issuer.Verify(kept)
+	assert.NoError(t, err)
+
+	_, err = other.Verify(revoked)
+	assert.NoError(t, err, "other replicas learn of revocations when they refresh")
+	require.NoError(t, other.Refresh(ctx))
+	_, err = other.Verify(revoked)
+	assert.ErrorIs(t, err, ErrRevoked)
+
+	now = now.Add(time.Second)
+	_, err = issuer.Verify(issue("agent-1"))
+	assert.NoError(t, err, "credentials issued after the revocation are accepted")
+
+	now = now.Add(DefaultCredentialTTL + time.Second)
+	require.NoError(t, other.Refresh(ctx))
+	records, err := issuer.Records.ListRecords(ctx, revocationKind)
+	require.NoError(t, err)
+	assert.Empty(t, records, "revocations are removed once the credentials they cover have expired")
+
+	stateless := newTestIssuer(t, "k")
+	stateless.Records = nil
+	_, err = stateless.Revoke(ctx, "agent-1")
+	assert.ErrorIs(t, err, ErrNoRecords)
+}
+
+func TestIssuer_RefreshRemovesExpiredTokens(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "k")
+	now := time.Now()
+	issuer.now = func() time.Time { return now }
+
+	token, err := issuer.MintBootstrap("", time.Minute)
+	require.NoError(t, err)
+	_, err = issuer.Exchange(ctx, token.Token, "agent-1")
+	require.NoError(t, err)
+
+	require.NoError(t, issuer.Refresh(ctx))
+	records, err := issuer.Records.ListRecords(ctx, usedTokenKind)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, issuer.Refresh(ctx))
+	records, err = issuer.Records.ListRecords(ctx, usedTokenKind)
+	require.NoError(t, err)
+	assert.Empty(t, records, "expired tokens cannot be exchanged again anyway")
+}
+
+func TestMiddleware(t *testing.T) {
+	issuer := newTestIssuer(t, "k")
+	token, err := issuer.MintBootstrap("", time.Minute)
+	require.NoError(t, err)
+	cred, err := issuer.Exchange(context.Background(), token.Token, "agent-1")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		authorization string
+		expectedAgent string
+		expectedToken string
+	}{
+		{name: "no token"},
+		{name: "other scheme", authorization: "Basic dXNlcjpwYXNz"},
+		{name: "credential", authorization: "Bearer " + cred.Credential, expectedAgent: "agent-1", expectedToken: cred.Credential},
+		{name: "bootstrap token", authorization: "bearer " + token.Token, expectedToken: token.Token},
+		{name: "foreign token", authorization: "Bearer abc", expectedToken: "abc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var agentID, bearer string
+			handler := Middleware(issuer)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				agentID, bearer = AgentFromContext(r.Context()), TokenFromContext(r.Context())
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expectedAgent, agentID)
+			assert.Equal(t, tc.expectedToken, bearer)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// (PUT /agents/{agent_id})
+func (s Server) RegisterAgent(ctx context.Context, request v1.RegisterAgentRequestObject) (v1.RegisterAgentResponseObject, error) {
+	switch status, msg := s.authorizeAgent(ctx, request.AgentId); status {
+	case http.StatusUnauthorized:
+		return v1.RegisterAgent401JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	case http.StatusForbidden:
+		return v1.RegisterAgent403JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	}
+
+	agent := assignment.Agent{ID: request.AgentId}
+	if request.Body.MaxProbes != nil {
+		if *request.Body.MaxProbes < 0 {
+			return v1.RegisterAgent400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: fmt.Sprintf("max_probes must not be negative, got %d", *request.Body.MaxProbes),
+				},
+			}, nil
+		}
+		agent.MaxProbes = *request.Body.MaxProbes
+	}
+	if request.Body.Labels != nil {
+		agent.Labels = *request.Body.Labels
+	}
+	if region, ok := agent.Labels[assignment.RegionLabelKey]; ok && !v1.IsRegion(region) {
+		return v1.RegisterAgent400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("invalid %s label %q, expected a lowercase name such as us-east-1", assignment.RegionLabelKey, region),
+			},
+		}, nil
+	}
+
+	registered, err := s.Assignments.Heartbeat(ctx, agent)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error registering agent", "agent_id", request.AgentId, "error", err)
+		return nil, err
+	}
+	return v1.RegisterAgent200JSONResponse(agentObject(registered)), nil
+}
+
+// (GET /agents/{agent_id}/probes)
+func (s Server) ListAgentProbes(ctx context.Context, request v1.ListAgentProbesRequestObject) (v1.ListAgentProbesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("list_agent_probes", time.Now())
+	ctx = logging.With(ctx, "agent_id", request.AgentId)
+
+	switch status, msg := s.authorizeAgent(ctx, request.AgentId); status {
+	case http.StatusUnauthorized:
+		return v1.ListAgentProbes401JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	case http.StatusForbidden:
+		return v1.ListAgentProbes403JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	}
+
+	_, registered, err := s.Assignments.Agent(ctx, request.AgentId)
+	if err != nil {
+		metrics.RecordProbestoreError("list_agent_probes")
+		slog.ErrorContext(ctx, "Error reading agent", "agent_id", request.AgentId, "error", err)
+		return nil, err
+	}
+	if !registered {
+		return v1.ListAgentProbes404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("agent %s is not registered", request.AgentId),
+			},
+		}, nil
+	}
+
+	probes, err := s.Assignments.AssignedProbes(ctx, request.AgentId)
+	if err != nil {
+		metrics.RecordProbestoreError("list_agent_probes")
+		slog.ErrorContext(ctx, "Error listing probes for agent", "agent_id", request.AgentId, "error", err)
+		return nil, fmt.Errorf("failed to list probes for agent: %w", err)
+	}
+
+	return v1.ListAgentProbes200JSONResponse(v1.ProbesArrayResponse{Probes: probes}), nil
+}
+
+// (POST /agents/{agent_id}/credentials)
+func (s Server) CreateAgentCredential(ctx context.Context, request v1.CreateAgentCredentialRequestObject) (v1.CreateAgentCredentialResponseObject, error) {
+	if s.AgentAuth == nil {
+		return v1.CreateAgentCredential501JSONResponse{
+			Error: v1.ErrorObject{Message: "agent credentials are not configured"},
+		}, nil
+	}
+
+	token := agentauth.TokenFromContext(ctx)
+	if token == "" {
+		return v1.CreateAgentCredential401JSONResponse{
+			Error: v1.ErrorObject{Message: "a bootstrap token or the agent's credential is required"},
+		}, nil
+	}
+	var cred agentauth.Credential
+	var err error
+	switch caller := agentauth.AgentFromContext(ctx); {
+	case caller == request.AgentId:
+		cred, err = s.AgentAuth.Renew(token)
+	case caller != "":
+		return v1.CreateAgentCredential403JSONResponse{
+			Error: v1.ErrorObject{Message: fmt.Sprintf("credential belongs to agent %s, not %s", caller, request.AgentId)},
+		}, nil
+	default:
+		cred, err = s.AgentAuth.Exchange(ctx, token, request.AgentId)
+	}
+	switch {
+	case errors.Is(err, agentauth.ErrInvalid), errors.Is(err, agentauth.ErrExpired),
+		errors.Is(err, agentauth.ErrUsed), errors.Is(err, agentauth.ErrRevoked):
+		return v1.CreateAgentCredential401JSONResponse{
+			Error: v1.ErrorObject{Message: err.Error()},
+		}, nil
+	case errors.Is(err, agentauth.ErrNoRecords):
+		return v1.CreateAgentCredential501JSONResponse{
+			Error: v1.ErrorObject{Message: err.Error()},
+		}, nil
+	case errors.Is(err, agentauth.ErrRegistered):
+		return v1.CreateAgentCredential403JSONResponse{
+			Error: v1.ErrorObject{Message: fmt.Sprintf("agent %s: %v", request.AgentId, err)},
+		}, nil
+	case errors.Is(err, agentauth.ErrOtherAgent):
+		return v1.CreateAgentCredential403JSONResponse{
+			Error: v1.ErrorObject{Message: err.Error()},
+		}, nil
+	case err != nil:
+		slog.ErrorContext(ctx, "Error issuing agent credential", "agent_id", request.AgentId, "error", err)
+		return nil, fmt.Errorf("failed to issue agent credential: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Issued agent credential", "agent_id", cred.AgentID, "credential_id", cred.ID, "expires_at", cred.ExpiresAt)
+	return v1.CreateAgentCredential201JSONResponse{
+		Id:         cred.ID,
+		AgentId:    cred.AgentID,
+		Credential: cred.Credential,
+		IssuedAt:   cred.IssuedAt,
+		ExpiresAt:  cred.ExpiresAt,
+	}, nil
+}
+
+// (DELETE /agents/{agent_id}/credentials)
+func (s Server) RevokeAgentCredentials(ctx context.Context, request v1.RevokeAgentCredentialsRequestObject) (v1.RevokeAgentCredentialsResponseObject, error) {
+	if s.AgentAuth == nil {
+		return v1.RevokeAgentCredentials501JSONResponse{
+			Error: v1.ErrorObject{Message: "agent credentials are not configured"},
+		}, nil
+	}
+	if agentauth.AgentFromContext(ctx) != "" || s.callerTenant(ctx) != "" {
+		return v1.RevokeAgentCredentials403JSONResponse{
+			Error: v1.ErrorObject{Message: "only operators may revoke agent credentials"},
+		}, nil
+	}
+
+	revokedAt, err := s.AgentAuth.Revoke(ctx, request.AgentId)
+	switch {
+	case errors.Is(err, agentauth.ErrNoRecords):
+		return v1.RevokeAgentCredentials501JSONResponse{
+			Error: v1.ErrorObject{Message: err.Error()},
+		}, nil
+	case err != nil:
+		slog.ErrorContext(ctx, "Error revoking agent credentials", "agent_id", request.AgentId, "error", err)
+		return nil, fmt.Errorf("failed to revoke agent credentials: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Revoked agent credentials", "agent_id", request.AgentId, "revoked_at", revokedAt)
+	return v1.RevokeAgentCredentials200JSONResponse{
+		AgentId:   request.AgentId,
+		RevokedAt: revokedAt,
+	}, nil
+}
+
+// (POST /agent-bootstrap-tokens)
+func (s Server) CreateAgentBootstrapToken(ctx context.Context, request v1.CreateAgentBootstrapTokenRequestObject) (v1.CreateAgentBootstrapTokenResponseObject, error) {
+	if s.AgentAuth == nil {
+		return v1.CreateAgentBootstrapToken501JSONResponse{
+			Error: v1.ErrorObject{Message: "agent credentials are not configured"},
+		}, nil
+	}
+	// Agents and tenants must not mint tokens that would let them register
+	// more agents.
+	if agentauth.AgentFromContext(ctx) != "" || s.callerTenant(ctx) != "" {
+		return v1.CreateAgentBootstrapToken403JSONResponse{
+			Error: v1.ErrorObject{Message: "only operators may mint bootstrap tokens"},
+		}, nil
+	}
+
+	var agentID string
+	var ttl time.Duration
+	if request.Body != nil {
+		if request.Body.AgentId != nil {
+			agentID = *request.Body.AgentId
+		}
+		if request.Body.Ttl != nil {
+			d, err := time.ParseDuration(*request.Body.Ttl)
+			if err != nil {
+				return v1.CreateAgentBootstrapToken400JSONResponse{
+					Error: v1.ErrorObject{Message: fmt.Sprintf("invalid ttl: %v", err)},
+				}, nil
+			}
+			ttl = d
+		}
+	}
+	token, err := s.AgentAuth.MintBootstrap(agentID, ttl)
+	if err != nil {
+		return v1.CreateAgentBootstrapToken400JSONResponse{
+			Error: v1.ErrorObject{Message: err.Error()},
+		}, nil
+	}
+
+	slog.InfoContext(ctx, "Minted agent bootstrap token", "agent_id", agentID, "token_id", token.ID, "expires_at", token.ExpiresAt)
+	res := v1.CreateAgentBootstrapToken201JSONResponse{
+		Id:        token.ID,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+	}
+	if agentID != "" {
+		res.AgentId = &agentID
+	}
+	return res, nil
+}
+
+// authorizeAgent checks the credential of a request acting as the agent. It
+// returns the HTTP status to reject the request with and why, or 0.
+func (s Server) authorizeAgent(ctx context.Context, agentID string) (int, string) {
+	caller := agentauth.AgentFromContext(ctx)
+	switch {
+	case caller != "" && caller != agentID:
+		return http.StatusForbidden, fmt.Sprintf("credential belongs to agent %s, not %s", caller, agentID)
+	case caller == "" && s.RequireAgentCredentials:
+		return http.StatusUnauthorized, "an agent credential is required"
+	}
+	return 0, ""
+}
+
+func agentObject(agent assignment.Agent) v1.AgentObject {
+	obj := v1.AgentObject{
+		Id:            agent.ID,
+		MaxProbes:     agent.MaxProbes,
+		LastHeartbeat: agent.LastHeartbeat,
+	}
+	if len(agent.Labels) > 0 {
+		agentLabels := v1.LabelsSchema(maps.Clone(agent.Labels))
+		obj.Labels = &agentLabels
+	}
+	return obj
+}
@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAgent(t *testing.T) {
+	maxProbes := 50
+	negative := -1
+
+	testCases := []struct {
+		name             string
+		body             v1.RegisterAgentJSONRequestBody
+		expectedResponse v1.RegisterAgentResponseObject
+	}{
+		{
+			name: "registers an agent",
+			body: v1.RegisterAgentJSONRequestBody{
+				Labels:    &v1.LabelsSchema{"region": "us-east-1"},
+				MaxProbes: &maxProbes,
+			},
+			expectedResponse: v1.RegisterAgent200JSONResponse{},
+		},
+		{
+			name: "returns 400 for negative max_probes",
+			body: v1.RegisterAgentJSONRequestBody{MaxProbes: &negative},
+			expectedResponse: v1.RegisterAgent400JSONResponse{
+				Error: v1.ErrorObject{Message: "max_probes must not be negative, got -1"},
+			},
+		},
+		{
+			name: "returns 400 for an invalid region label",
+			body: v1.RegisterAgentJSONRequestBody{Labels: &v1.LabelsSchema{"region": "US East"}},
+			expectedResponse: v1.RegisterAgent400JSONResponse{
+				Error: v1.ErrorObject{Message: `invalid region label "US East", expected a lowercase name such as us-east-1`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := NewServer(probestore.NewMemoryProbeStore())
+			req := v1.RegisterAgentRequestObject{AgentId: "agent-1", Body: &tc.body}
+
+			res, err := server.RegisterAgent(context.Background(), req)
+			require.NoError(t, err)
+			assert.IsType(t, tc.expectedResponse, res)
+
+			if resp200, ok := res.(v1.RegisterAgent200JSONResponse); ok {
+				assert.Equal(t, "agent-1", resp200.Id)
+				assert.Equal(t, maxProbes, resp200.MaxProbes)
+				assert.Equal(t, &v1.LabelsSchema{"region": "us-east-1"}, resp200.Labels)
+				assert.False(t, resp200.LastHeartbeat.IsZero())
+
+				_, registered, err := server.Assignments.Agent(context.Background(), "agent-1")
+				require.NoError(t, err)
+				assert.True(t, registered)
+			} else {
+				assert.Equal(t, tc.expectedResponse, res)
+			}
+		})
+	}
+}
+
+func TestListAgentProbes(t *testing.T) {
+	probeID := uuid.New()
+	store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Labels: &v1.LabelsSchema{assignment.AgentLabelKey: "agent-1"}})
+
+	t.Run("returns 404 for an unregistered agent", func(t *testing.T) {
+		server := NewServer(store)
+		res, err := server.ListAgentProbes(context.Background(), v1.ListAgentProbesRequestObject{AgentId: "agent-1"})
+		require.NoError(t, err)
+		assert.Equal(t, v1.ListAgentProbes404JSONResponse{
+			Warning: v1.WarningObject{Message: "agent agent-1 is not registered"},
+		}, res)
+	})
+
+	t.Run("lists probes for a registered agent", func(t *testing.T) {
+		server := NewServer(store)
+		_, err := server.Assignments.Heartbeat(context.Background(), assignment.Agent{ID: "agent-1"})
+		require.NoError(t, err)
+
+		res, err := server.ListAgentProbes(context.Background(), v1.ListAgentProbesRequestObject{AgentId: "agent-1"})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListAgentProbes200JSONResponse)
+		require.True(t, ok)
+		require.Len(t, resp.Probes, 1)
+		assert.Equal(t, probeID, resp.Probes[0].Id)
+	})
+}
+
+// bearerContext returns the context agentauth.Middleware gives a request
+// carrying the bearer token.
+func bearerContext(issuer *agentauth.Issuer, token string) context.Context {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	var ctx context.Context
+	agentauth.Middleware(issuer)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), r)
+	return ctx
+}
+
+func TestAgentCredentials(t *testing.T) {
+	issuer, err := agentauth.NewIssuer([]byte(strings.Repeat("k", agentauth.MinKeyLength)), time.Hour, 0)
+	require.NoError(t, err)
+	store := probestore.NewMemoryProbeStore()
+	issuer.Records = store
+	server := NewServer(store)
+	issuer.Registered = func(ctx context.Context, agentID string) (bool, error) {
+		_, ok, err := server.Assignments.Agent(ctx, agentID)
+		return ok, err
+	}
+	server.AgentAuth = issuer
+	server.RequireAgentCredentials = true
+
+	agentID := "agent-1"
+	res, err := server.CreateAgentBootstrapToken(context.Background(), v1.CreateAgentBootstrapTokenRequestObject{
+		Body: &v1.CreateAgentBootstrapTokenJSONRequestBody{AgentId: &agentID},
+	})
+	require.NoError(t, err)
+	bootstrap, ok := res.(v1.CreateAgentBootstrapToken201JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, &agentID, bootstrap.AgentId)
+	assert.WithinDuration(t, time.Now().Add(agentauth.DefaultBootstrapTTL), bootstrap.ExpiresAt, time.Minute)
+
+	t.Run("bootstrap tokens are only exchanged for their agent", func(t *testing.T) {
+		res, err := server.CreateAgentCredential(bearerContext(issuer, bootstrap.Token), v1.CreateAgentCredentialRequestObject{AgentId: "agent-2"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentCredential403JSONResponse{}, res)
+	})
+
+	t.Run("requests without a bootstrap token are rejected", func(t *testing.T) {
+		res, err := server.CreateAgentCredential(context.Background(), v1.CreateAgentCredentialRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentCredential401JSONResponse{}, res)
+	})
+
+	res2, err := server.CreateAgentCredential(bearerContext(issuer, bootstrap.Token), v1.CreateAgentCredentialRequestObject{AgentId: agentID})
+	require.NoError(t, err)
+	cred, ok := res2.(v1.CreateAgentCredential201JSONResponse)
+	require.True(t, ok)
+	assert.Equal(t, agentID, cred.AgentId)
+	ctx := bearerContext(issuer, cred.Credential)
+
+	t.Run("credentials authenticate their agent", func(t *testing.T) {
+		res, err := server.RegisterAgent(ctx, v1.RegisterAgentRequestObject{AgentId: agentID, Body: &v1.RegisterAgentJSONRequestBody{}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.RegisterAgent200JSONResponse{}, res)
+
+		res2, err := server.ListAgentProbes(ctx, v1.ListAgentProbesRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListAgentProbes200JSONResponse{}, res2)
+	})
+
+	t.Run("credentials do not authenticate other agents", func(t *testing.T) {
+		res, err := server.RegisterAgent(ctx, v1.RegisterAgentRequestObject{AgentId: "agent-2", Body: &v1.RegisterAgentJSONRequestBody{}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.RegisterAgent403JSONResponse{}, res)
+	})
+
+	t.Run("agents cannot mint bootstrap tokens", func(t *testing.T) {
+		res, err := server.CreateAgentBootstrapToken(ctx, v1.CreateAgentBootstrapTokenRequestObject{})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentBootstrapToken403JSONResponse{}, res)
+	})
+
+	t.Run("credentials are required", func(t *testing.T) {
+		res, err := server.ListAgentProbes(context.Background(), v1.ListAgentProbesRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListAgentProbes401JSONResponse{}, res)
+	})
+
+	t.Run("agents renew their credential", func(t *testing.T) {
+		res, err := server.CreateAgentCredential(ctx, v1.CreateAgentCredentialRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		renewed, ok := res.(v1.CreateAgentCredential201JSONResponse)
+		require.True(t, ok)
+		assert.Equal(t, agentID, renewed.AgentId)
+		assert.False(t, renewed.ExpiresAt.Before(cred.ExpiresAt))
+
+		res, err = server.CreateAgentCredential(ctx, v1.CreateAgentCredentialRequestObject{AgentId: "agent-2"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentCredential403JSONResponse{}, res)
+	})
+
+	t.Run("bootstrap tokens for any agent are used once and not for registered agents", func(t *testing.T) {
+		res, err := server.CreateAgentBootstrapToken(context.Background(), v1.CreateAgentBootstrapTokenRequestObject{})
+		require.NoError(t, err)
+		anyAgent, ok := res.(v1.CreateAgentBootstrapToken201JSONResponse)
+		require.True(t, ok)
+
+		res2, err := server.CreateAgentCredential(bearerContext(issuer, anyAgent.Token), v1.CreateAgentCredentialRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentCredential403JSONResponse{}, res2, "registered agents cannot be taken over")
+
+		res2, err = server.CreateAgentCredential(bearerContext(issuer, anyAgent.Token), v1.CreateAgentCredentialRequestObject{AgentId: "agent-3"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentCredential201JSONResponse{}, res2)
+
+		res2, err = server.CreateAgentCredential(bearerContext(issuer, anyAgent.Token), v1.CreateAgentCredentialRequestObject{AgentId: "agent-4"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentCredential401JSONResponse{}, res2)
+	})
+
+	t.Run("only operators revoke credentials", func(t *testing.T) {
+		res, err := server.RevokeAgentCredentials(ctx, v1.RevokeAgentCredentialsRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.RevokeAgentCredentials403JSONResponse{}, res)
+
+		res, err = server.RevokeAgentCredentials(context.Background(), v1.RevokeAgentCredentialsRequestObject{AgentId: "agent-3"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.RevokeAgentCredentials200JSONResponse{}, res)
+	})
+
+	t.Run("ttl is limited", func(t *testing.T) {
+		ttl := "2h"
+		res, err := server.CreateAgentBootstrapToken(context.Background(), v1.CreateAgentBootstrapTokenRequestObject{
+			Body: &v1.CreateAgentBootstrapTokenJSONRequestBody{Ttl: &ttl},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentBootstrapToken400JSONResponse{}, res)
+	})
+
+	t.Run("returns 501 when not configured", func(t *testing.T) {
+		res, err := NewServer(probestore.NewMemoryProbeStore()).CreateAgentBootstrapToken(context.Background(), v1.CreateAgentBootstrapTokenRequestObject{})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAgentBootstrapToken501JSONResponse{}, res)
+
+		res2, err := NewServer(probestore.NewMemoryProbeStore()).RevokeAgentCredentials(context.Background(), v1.RevokeAgentCredentialsRequestObject{AgentId: agentID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.RevokeAgentCredentials501JSONResponse{}, res2)
+	})
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// validateAlerting checks the alert routing metadata of a probe, if it has
+// any.
+func validateAlerting(alerting *v1.AlertingSchema) error {
+	if alerting == nil {
+		return nil
+	}
+	return alerting.Validate()
+}
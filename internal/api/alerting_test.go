@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAlerting(t *testing.T) {
+	testCases := []struct {
+		name        string
+		runbookURL  string
+		expectedErr string
+	}{
+		{name: "https runbook", runbookURL: "https://runbooks.example.com/api-down"},
+		{name: "http runbook", runbookURL: "http://wiki.internal/runbooks/api"},
+		{name: "relative runbook", runbookURL: "/runbooks/api", expectedErr: `runbook_url "/runbooks/api" must be an absolute http or https URL`},
+		{name: "other scheme", runbookURL: "ftp://runbooks.example.com/api", expectedErr: `runbook_url "ftp://runbooks.example.com/api" must be an absolute http or https URL`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateAlerting(&v1.AlertingSchema{RunbookUrl: &tc.runbookURL})
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+
+	assert.NoError(t, validateAlerting(nil))
+}
+
+func TestProbeAlerting(t *testing.T) {
+	severity, runbook, silence := v1.Warning, "https://runbooks.example.com/api", true
+	badRunbook := "runbooks/api"
+	store := newProbeStore(t)
+	server := NewServer(store)
+
+	res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://example.com",
+		Alerting:  &v1.AlertingSchema{Severity: &severity, RunbookUrl: &runbook},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	probeID := res.(v1.CreateProbe201JSONResponse).Id
+	assert.Equal(t, &v1.AlertingSchema{Severity: &severity, RunbookUrl: &runbook}, storedProbe(t, store, probeID).Alerting)
+
+	t.Run("update replaces the alerting metadata", func(t *testing.T) {
+		res, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Alerting: &v1.AlertingSchema{SilenceDuringMaintenance: &silence},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		assert.Equal(t, &v1.AlertingSchema{SilenceDuringMaintenance: &silence}, storedProbe(t, store, probeID).Alerting)
+	})
+
+	t.Run("invalid runbook is rejected", func(t *testing.T) {
+		res, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Alerting: &v1.AlertingSchema{RunbookUrl: &badRunbook},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, v1.UpdateProbe400JSONResponse{Error: v1.ErrorObject{Message: `runbook_url "runbooks/api" must be an absolute http or https URL`}}, res)
+	})
+
+	t.Run("invalid runbook is rejected on create", func(t *testing.T) {
+		res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			StaticUrl: "https://other.example.com",
+			Alerting:  &v1.AlertingSchema{RunbookUrl: &badRunbook},
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe400JSONResponse{}, res)
+		assert.Equal(t, 1, storedProbes(t, store))
+	})
+}
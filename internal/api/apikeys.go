@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// (GET /apikeys)
+func (s Server) ListAPIKeys(ctx context.Context, request v1.ListAPIKeysRequestObject) (v1.ListAPIKeysResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("list_api_keys", time.Now())
+
+	switch status, msg := s.authorizeAPIKeyAdmin(ctx); status {
+	case http.StatusUnauthorized:
+		return v1.ListAPIKeys401JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	case http.StatusNotImplemented:
+		return v1.ListAPIKeys501JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	}
+
+	keys, err := s.APIKeys.List(ctx)
+	if errors.Is(err, probestore.ErrAPIKeysUnsupported) {
+		return v1.ListAPIKeys501JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing API keys", "error", err)
+		metrics.RecordProbestoreError("list_api_keys")
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	objs := make([]v1.APIKeyObject, 0, len(keys))
+	for _, key := range keys {
+		objs = append(objs, apiKeyObject(key))
+	}
+	return v1.ListAPIKeys200JSONResponse{Keys: objs}, nil
+}
+
+// (POST /apikeys)
+func (s Server) CreateAPIKey(ctx context.Context, request v1.CreateAPIKeyRequestObject) (v1.CreateAPIKeyResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("create_api_key", time.Now())
+
+	switch status, msg := s.authorizeAPIKeyAdmin(ctx); status {
+	case http.StatusUnauthorized:
+		return v1.CreateAPIKey401JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	case http.StatusNotImplemented:
+		return v1.CreateAPIKey501JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	}
+
+	var rateLimit float64
+	if request.Body.RateLimit != nil {
+		if *request.Body.RateLimit <= 0 {
+			return v1.CreateAPIKey400JSONResponse{
+				Error: v1.ErrorObject{Message: fmt.Sprintf("rate_limit must be positive, got %v", *request.Body.RateLimit)},
+			}, nil
+		}
+		rateLimit = *request.Body.RateLimit
+	}
+	var ttl time.Duration
+	if request.Body.Ttl != nil {
+		d, err := time.ParseDuration(*request.Body.Ttl)
+		if err != nil || d <= 0 {
+			return v1.CreateAPIKey400JSONResponse{
+				Error: v1.ErrorObject{Message: fmt.Sprintf("invalid ttl %q: must be a positive duration", *request.Body.Ttl)},
+			}, nil
+		}
+		ttl = d
+	}
+
+	key, secret, err := s.APIKeys.Mint(ctx, request.Body.Name, rateLimit, ttl)
+	if errors.Is(err, probestore.ErrAPIKeysUnsupported) {
+		return v1.CreateAPIKey501JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error minting API key", "name", request.Body.Name, "error", err)
+		metrics.RecordProbestoreError("create_api_key")
+		return nil, fmt.Errorf("failed to mint API key: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Minted API key", "key_id", key.ID, "name", key.Name)
+	obj := apiKeyObject(key)
+	obj.Key = &secret
+	return v1.CreateAPIKey201JSONResponse(obj), nil
+}
+
+// (DELETE /apikeys/{key_id})
+func (s Server) DeleteAPIKey(ctx context.Context, request v1.DeleteAPIKeyRequestObject) (v1.DeleteAPIKeyResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("delete_api_key", time.Now())
+
+	switch status, msg := s.authorizeAPIKeyAdmin(ctx); status {
+	case http.StatusUnauthorized:
+		return v1.DeleteAPIKey401JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	case http.StatusNotImplemented:
+		return v1.DeleteAPIKey501JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	}
+
+	err := s.APIKeys.Revoke(ctx, request.KeyId)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return v1.DeleteAPIKey404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("API key with ID %s not found", request.KeyId),
+			},
+		}, nil
+	case errors.Is(err, probestore.ErrAPIKeysUnsupported):
+		return v1.DeleteAPIKey501JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	case err != nil:
+		slog.ErrorContext(ctx, "Error revoking API key", "key_id", request.KeyId, "error", err)
+		metrics.RecordProbestoreError("delete_api_key")
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Revoked API key", "key_id", request.KeyId)
+	return v1.DeleteAPIKey204Response{}, nil
+}
+
+// authorizeAPIKeyAdmin checks that API keys can be managed and that the
+// request carries the admin token, so API keys cannot mint more keys. It
+// returns the HTTP status to reject the request with and why, or 0.
+func (s Server) authorizeAPIKeyAdmin(ctx context.Context) (int, string) {
+	switch {
+	case s.APIKeys == nil:
+		return http.StatusNotImplemented, "API keys are not configured"
+	case s.AdminToken == "":
+		return http.StatusNotImplemented, "API keys are managed with the admin token, which is not configured"
+	case !s.hasAdminToken(ctx):
+		return http.StatusUnauthorized, "a valid admin token is required"
+	}
+	return 0, ""
+}
+
+// hasAdminToken reports whether the request carries the admin token, which
+// is never the case when none is configured.
+func (s Server) hasAdminToken(ctx context.Context) bool {
+	return s.AdminToken != "" && subtle.ConstantTimeCompare([]byte(agentauth.TokenFromContext(ctx)), []byte(s.AdminToken)) == 1
+}
+
+// apiKeyObject returns the API view of a key, which leaves out the hash of
+// its secret.
+func apiKeyObject(key probestore.APIKey) v1.APIKeyObject {
+	obj := v1.APIKeyObject{
+		Id:        key.ID,
+		Name:      key.Name,
+		CreatedAt: key.CreatedAt,
+	}
+	if key.RateLimit > 0 {
+		obj.RateLimit = &key.RateLimit
+	}
+	if !key.ExpiresAt.IsZero() {
+		obj.ExpiresAt = &key.ExpiresAt
+	}
+	return obj
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAdminToken = "admin-token"
+
+func TestAPIKeys(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	manager, err := apikeys.NewManager(store, 0)
+	require.NoError(t, err)
+	server := NewServer(store)
+	server.APIKeys = manager
+	server.AdminToken = testAdminToken
+	admin := bearerContext(nil, testAdminToken)
+
+	t.Run("the admin token is required", func(t *testing.T) {
+		res, err := server.ListAPIKeys(context.Background(), v1.ListAPIKeysRequestObject{})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListAPIKeys401JSONResponse{}, res)
+
+		res2, err := server.CreateAPIKey(bearerContext(nil, "not-the-token"), v1.CreateAPIKeyRequestObject{Body: &v1.CreateAPIKeyJSONRequestBody{Name: "ci"}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAPIKey401JSONResponse{}, res2)
+	})
+
+	rateLimit, ttl := 2.5, "1h"
+	res, err := server.CreateAPIKey(admin, v1.CreateAPIKeyRequestObject{Body: &v1.CreateAPIKeyJSONRequestBody{Name: "ci", RateLimit: &rateLimit, Ttl: &ttl}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateAPIKey201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	require.NotNil(t, created.Key)
+	assert.Equal(t, "ci", created.Name)
+	assert.Equal(t, &rateLimit, created.RateLimit)
+	require.NotNil(t, created.ExpiresAt)
+	assert.Equal(t, created.CreatedAt.Add(time.Hour), *created.ExpiresAt)
+
+	key, err := manager.Verify(*created.Key)
+	require.NoError(t, err, "minted keys are accepted")
+	assert.Equal(t, created.Id, key.ID)
+
+	t.Run("invalid requests get 400", func(t *testing.T) {
+		negative := -1.0
+		res, err := server.CreateAPIKey(admin, v1.CreateAPIKeyRequestObject{Body: &v1.CreateAPIKeyJSONRequestBody{Name: "ci", RateLimit: &negative}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateAPIKey400JSONResponse{}, res)
+	})
+
+	t.Run("list leaves out the key", func(t *testing.T) {
+		res, err := server.ListAPIKeys(admin, v1.ListAPIKeysRequestObject{})
+		require.NoError(t, err)
+		listed := v1.APIKeyObject(created)
+		listed.Key = nil
+		assert.Equal(t, v1.ListAPIKeys200JSONResponse{Keys: []v1.APIKeyObject{listed}}, res)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		res, err := server.DeleteAPIKey(admin, v1.DeleteAPIKeyRequestObject{KeyId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteAPIKey204Response{}, res)
+
+		_, err = manager.Verify(*created.Key)
+		assert.ErrorIs(t, err, apikeys.ErrInvalid, "revoked keys are rejected")
+
+		res, err = server.DeleteAPIKey(admin, v1.DeleteAPIKeyRequestObject{KeyId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteAPIKey404JSONResponse{}, res)
+	})
+
+	t.Run("API keys are not managed without an admin token", func(t *testing.T) {
+		server := server
+		server.AdminToken = ""
+		res, err := server.ListAPIKeys(bearerContext(nil, ""), v1.ListAPIKeysRequestObject{})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListAPIKeys501JSONResponse{}, res)
+	})
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// setAssertions replaces the response assertions of a probe, an empty
+// object removing them.
+func setAssertions(probe *v1.ProbeObject, assertions v1.AssertionsSchema) {
+	probe.Assertions = nil
+	if !assertions.IsZero() {
+		probe.Assertions = &assertions
+	}
+}
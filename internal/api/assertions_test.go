@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeAssertions(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	assertions := v1.AssertionsSchema{
+		StatusCodes:   &[]v1.StatusCodeRangeSchema{"200", "3xx"},
+		Headers:       &[]v1.HeaderAssertion{{Name: "Content-Type", ValueRegex: new("^application/json")}},
+		TlsExpiryDays: new(14),
+	}
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl:  "https://api.example.com",
+		Assertions: &assertions,
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, &assertions, created.Assertions)
+
+	update := func(body v1.UpdateProbeJSONRequestBody) v1.UpdateProbeResponseObject {
+		t.Helper()
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: created.Id, Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("invalid assertions", func(t *testing.T) {
+		for message, body := range map[string]v1.CreateProbeJSONRequestBody{
+			`status code range "299-200" ends before`:       {StaticUrl: "https://a.example.com", Assertions: &v1.AssertionsSchema{StatusCodes: &[]v1.StatusCodeRangeSchema{"299-200"}}},
+			"invalid assertions body_regex":                 {StaticUrl: "https://b.example.com", Assertions: &v1.AssertionsSchema{BodyRegex: new("[")}},
+			"tls_expiry_days requires an https static_url":  {StaticUrl: "http://c.example.com", Assertions: &v1.AssertionsSchema{TlsExpiryDays: new(7)}},
+			"only apply to probes of the http_2xx module":   {StaticUrl: "https://d.example.com", Module: new(v1.Tcp), Assertions: &v1.AssertionsSchema{BodyRegex: new("ok")}},
+			"assertions only apply to http probes, not tcp": {Tcp: &v1.TcpSchema{Address: "db.example.com:5432"}, Assertions: &v1.AssertionsSchema{BodyRegex: new("ok")}},
+		} {
+			res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &body})
+			require.NoError(t, err)
+			require.IsType(t, v1.CreateProbe400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.CreateProbe400JSONResponse).Error.Message, message)
+		}
+	})
+
+	t.Run("updates replace the assertions", func(t *testing.T) {
+		replaced := v1.AssertionsSchema{BodyRegex: new(`"status":\s*"ok"`)}
+		res := update(v1.UpdateProbeJSONRequestBody{Assertions: &replaced})
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, &replaced, updated.Body.Assertions)
+		assert.Equal(t, *created.Generation+1, *updated.Body.Generation)
+
+		res = update(v1.UpdateProbeJSONRequestBody{Module: new(v1.Icmp)})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "only apply to probes of the http_2xx module, not icmp")
+
+		res = update(v1.UpdateProbeJSONRequestBody{Assertions: &v1.AssertionsSchema{}})
+		updated, ok = res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, updated.Body.Assertions, "an empty object removes them")
+	})
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"maps"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// (GET /audit)
+func (s Server) ListAuditEntries(ctx context.Context, request v1.ListAuditEntriesRequestObject) (v1.ListAuditEntriesResponseObject, error) {
+	params := request.Params
+	filter := audit.Filter{}
+	if params.ProbeId != nil {
+		filter.ProbeID = *params.ProbeId
+	}
+	if params.Actor != nil {
+		filter.Actor = *params.Actor
+	}
+	if params.Operation != nil {
+		filter.Operation = *params.Operation
+	}
+	if params.Since != nil {
+		filter.Since = *params.Since
+	}
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	// Tenants only see the changes to their own probes.
+	if tenant := s.callerTenant(ctx); tenant != "" {
+		filter.Allow = func(entry v1.AuditEntry) bool {
+			return (entry.Before != nil && ownedBy(*entry.Before, tenant)) || (entry.After != nil && ownedBy(*entry.After, tenant))
+		}
+	}
+	return v1.ListAuditEntries200JSONResponse{Entries: s.Audit.List(filter)}, nil
+}
+
+// snapshot returns a copy of the probe that changes to the original's labels
+// do not affect, to record the probe as it was before an update.
+func snapshot(probe v1.ProbeObject) *v1.ProbeObject {
+	if probe.Labels != nil {
+		probeLabels := maps.Clone(*probe.Labels)
+		probe.Labels = &probeLabels
+	}
+	return &probe
+}
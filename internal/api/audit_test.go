@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	server.TenantIsolation = true
+
+	alice := audit.WithActor(limits.WithTenant(context.Background(), "team-a"), "alice")
+	bob := audit.WithActor(limits.WithTenant(context.Background(), "team-b"), "bob")
+	operator := context.Background()
+
+	res, err := server.CreateProbe(alice, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://a.example.com",
+		Labels:    &v1.LabelsSchema{"env": "prod"},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	probeA := res.(v1.CreateProbe201JSONResponse)
+
+	res, err = server.CreateProbe(bob, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://b.example.com"}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+
+	update, err := server.UpdateProbe(alice, v1.UpdateProbeRequestObject{ProbeId: probeA.Id, Body: &v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"env": "staging"}}})
+	require.NoError(t, err)
+	require.IsType(t, v1.UpdateProbe200JSONResponse{}, update)
+
+	del, err := server.DeleteProbe(alice, v1.DeleteProbeRequestObject{ProbeId: probeA.Id})
+	require.NoError(t, err)
+	require.IsType(t, v1.DeleteProbe204Response{}, del)
+
+	list := func(ctx context.Context, params v1.ListAuditEntriesParams) []v1.AuditEntry {
+		res, err := server.ListAuditEntries(ctx, v1.ListAuditEntriesRequestObject{Params: params})
+		require.NoError(t, err)
+		require.IsType(t, v1.ListAuditEntries200JSONResponse{}, res)
+		return res.(v1.ListAuditEntries200JSONResponse).Entries
+	}
+
+	t.Run("records each change with its diff", func(t *testing.T) {
+		entries := list(operator, v1.ListAuditEntriesParams{ProbeId: &probeA.Id})
+		require.Len(t, entries, 3)
+
+		deleted, updated, created := entries[0], entries[1], entries[2]
+		assert.Equal(t, v1.CreateProbe, created.Operation)
+		assert.Nil(t, created.Before)
+		require.NotNil(t, created.After)
+		assert.Equal(t, "https://a.example.com", created.After.StaticUrl)
+
+		assert.Equal(t, v1.UpdateProbe, updated.Operation)
+		assert.Equal(t, []string{"generation", "labels.env"}, updated.Changes)
+		assert.Equal(t, "prod", (*updated.Before.Labels)["env"])
+		assert.Equal(t, "staging", (*updated.After.Labels)["env"])
+
+		assert.Equal(t, v1.DeleteProbe, deleted.Operation)
+		require.NotNil(t, deleted.Actor)
+		assert.Equal(t, "alice", *deleted.Actor)
+		require.NotNil(t, deleted.Before)
+		assert.Equal(t, v1.Pending, deleted.Before.Status)
+		assert.Nil(t, deleted.After, "pending probes are removed right away")
+	})
+
+	t.Run("filters entries", func(t *testing.T) {
+		actor := "bob"
+		assert.Len(t, list(operator, v1.ListAuditEntriesParams{Actor: &actor}), 1)
+		operation := v1.DeleteProbe
+		assert.Len(t, list(operator, v1.ListAuditEntriesParams{Operation: &operation}), 1)
+		limit := 2
+		assert.Len(t, list(operator, v1.ListAuditEntriesParams{Limit: &limit}), 2)
+	})
+
+	t.Run("tenants only see changes to their probes", func(t *testing.T) {
+		assert.Len(t, list(alice, v1.ListAuditEntriesParams{}), 3)
+		entries := list(bob, v1.ListAuditEntriesParams{})
+		require.Len(t, entries, 1)
+		assert.Equal(t, v1.CreateProbe, entries[0].Operation)
+		assert.Equal(t, "https://b.example.com", entries[0].After.StaticUrl)
+	})
+}
@@ -0,0 +1,521 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/fieldselector"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// bundleVersion is the version of the probe bundle format.
+const bundleVersion = 1
+
+// maintainedLabels are the labels the store sets on every probe, and the
+// heartbeat agents send. Imports neither restore nor overwrite them.
+var maintainedLabels = []string{baseAppLabelKey, probeStatusLabelKey, probeURLHashLabelKey, probestore.LastReconciledLabel}
+
+// maxReportedConflicts caps the URLs listed when an import fails on
+// conflicts.
+const maxReportedConflicts = 5
+
+// (GET /probes/export)
+func (s Server) ExportProbes(ctx context.Context, request v1.ExportProbesRequestObject) (v1.ExportProbesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("export_probes", time.Now())
+	params := request.Params
+
+	format := v1.Json
+	if params.Format != nil {
+		format = *params.Format
+	}
+	if format != v1.Json && format != v1.Yaml && format != v1.Ndjson {
+		return v1.ExportProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid format %q, expected json, yaml or ndjson", format)}}, nil
+	}
+	selector, err := s.probeSelector(ctx, params.LabelSelector)
+	if err != nil {
+		return v1.ExportProbes400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	}
+	statuses, err := parseStatuses(params.Status)
+	if err != nil {
+		return v1.ExportProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid status: %v", err)}}, nil
+	}
+	fields, err := parseFields(params.Fields, bundledProbeFields)
+	if err != nil {
+		return v1.ExportProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid fields: %v", err)}}, nil
+	}
+	// The statuses are filtered on as ListProbes filters on those of its
+	// field selector: by the store through the status label, then here.
+	byStatus := fieldselector.Selector{Statuses: statuses}
+	probes, err := s.Store.ListProbes(ctx, pushDownFields(selector, byStatus))
+	if err != nil {
+		metrics.RecordProbestoreError("export_probes")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	if !byStatus.Empty() {
+		probes = slices.DeleteFunc(probes, func(p v1.ProbeObject) bool { return !byStatus.Matches(p) })
+	}
+	slices.SortFunc(probes, func(a, b v1.ProbeObject) int { return strings.Compare(a.Id.String(), b.Id.String()) })
+
+	bundle := v1.ProbeBundle{Version: bundleVersion, ExportedAt: new(clock.Stamp()), Probes: make([]v1.BundledProbe, 0, len(probes))}
+	for _, probe := range probes {
+		bundle.Probes = append(bundle.Probes, bundled(probe))
+	}
+	if format == v1.Ndjson || fields != nil {
+		return bundleResponse{ProbeBundle: bundle, format: format, fields: fields}, nil
+	}
+	if format == v1.Yaml {
+		data, err := yaml.Marshal(bundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode bundle: %w", err)
+		}
+		return v1.ExportProbes200ApplicationyamlResponse{Body: bytes.NewReader(data), ContentLength: int64(len(data))}, nil
+	}
+	return v1.ExportProbes200JSONResponse(bundle), nil
+}
+
+// parseStatuses parses the status parameter of ExportProbes. It returns nil
+// when probes of every status are exported.
+func parseStatuses(param *string) ([]v1.StatusSchema, error) {
+	if param == nil || strings.TrimSpace(*param) == "" {
+		return nil, nil
+	}
+	var statuses []v1.StatusSchema
+	for value := range strings.SplitSeq(*param, ",") {
+		status := v1.StatusSchema(strings.TrimSpace(value))
+		if !status.Valid() {
+			return nil, fmt.Errorf("unknown status %q, expected one of %v", status, v1.ProbeStatuses())
+		}
+		if !slices.Contains(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// bundleResponse is an ExportProbes response that the generated responses
+// cannot express: a bundle whose probes only hold the selected fields, or
+// one probe per line as NDJSON. NDJSON is written probe by probe, without
+// encoding the whole bundle first.
+type bundleResponse struct {
+	v1.ProbeBundle
+	format v1.BundleFormat
+	fields []string
+}
+
+func (response bundleResponse) VisitExportProbesResponse(w http.ResponseWriter) error {
+	// probe returns a probe of the bundle as it is written.
+	probe := func(probe v1.BundledProbe) (any, error) {
+		if response.fields == nil {
+			return probe, nil
+		}
+		return trimFields(probe, response.fields)
+	}
+
+	if response.format == v1.Ndjson {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(200)
+		enc := json.NewEncoder(w)
+		for _, bundled := range response.Probes {
+			line, err := probe(bundled)
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	probes := make([]any, 0, len(response.Probes))
+	for _, bundled := range response.Probes {
+		trimmed, err := probe(bundled)
+		if err != nil {
+			return err
+		}
+		probes = append(probes, trimmed)
+	}
+	data, err := json.Marshal(struct {
+		ExportedAt *time.Time `json:"exported_at,omitempty"`
+		Probes     []any      `json:"probes"`
+		Version    int        `json:"version"`
+	}{response.ExportedAt, probes, response.Version})
+	if err != nil {
+		return err
+	}
+	contentType := "application/json"
+	if response.format == v1.Yaml {
+		if data, err = yaml.JSONToYAML(data); err != nil {
+			return err
+		}
+		contentType = "application/yaml"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(200)
+	_, err = w.Write(data)
+	return err
+}
+
+// decodeBundle returns the bundle sent as JSON or YAML.
+func decodeBundle(request v1.ImportProbesRequestObject) (v1.ProbeBundle, error) {
+	if request.JSONBody != nil {
+		return *request.JSONBody, nil
+	}
+	if request.Body == nil {
+		return v1.ProbeBundle{}, fmt.Errorf("the bundle must be sent as application/json or application/yaml")
+	}
+	data, err := io.ReadAll(request.Body)
+	if err != nil {
+		return v1.ProbeBundle{}, fmt.Errorf("failed to read the bundle: %w", err)
+	}
+	var bundle v1.ProbeBundle
+	if err := yaml.Unmarshal(data, &bundle); err != nil {
+		return v1.ProbeBundle{}, fmt.Errorf("invalid bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+// portableLabels returns the labels less the maintained ones.
+func portableLabels(labels *v1.LabelsSchema) v1.LabelsSchema {
+	portable := v1.LabelsSchema{}
+	if labels != nil {
+		portable = maps.Clone(*labels)
+	}
+	for _, key := range maintainedLabels {
+		delete(portable, key)
+	}
+	return portable
+}
+
+// bundled returns the probe as it is exported.
+func bundled(probe v1.ProbeObject) v1.BundledProbe {
+	labels := portableLabels(probe.Labels)
+	return v1.BundledProbe{
+		Id:             probe.Id,
+		StaticUrl:      probe.StaticUrl,
+		AdditionalUrls: probe.AdditionalUrls,
+		Regions:        probe.Regions,
+		Labels:         &labels,
+		Status:         &probe.Status,
+		Interval:       probe.Interval,
+		Timeout:        probe.Timeout,
+		Module:         probe.Module,
+		Alerting:       probe.Alerting,
+		Owner:          probe.Owner,
+		Assertions:     probe.Assertions,
+		Dns:            probe.Dns,
+		Tcp:            probe.Tcp,
+		Icmp:           probe.Icmp,
+		Paused:         probe.Paused,
+	}
+}
+
+// importedLabels returns the labels a probe of a bundle is imported with:
+// its own, less the maintained ones, which the store and the agents of this
+// environment set. Callers scoped to a tenant import probes into their
+// tenant.
+func importedLabels(labels *v1.LabelsSchema, tenant string) v1.LabelsSchema {
+	imported := portableLabels(labels)
+	if tenant != "" {
+		delete(imported, tenantLabelKey)
+	}
+	return imported
+}
+
+// importedProbe returns the probe a probe of a bundle is imported as, with
+// the hooks and schedule defaults of this environment applied. The
+// validators are run on it by the caller, which knows whether it overwrites
+// a stored probe.
+func (s Server) importedProbe(ctx context.Context, probe v1.BundledProbe, labels v1.LabelsSchema, tenant string) (v1.ProbeObject, error) {
+	imported := v1.ProbeObject{
+		Id:        probe.Id,
+		StaticUrl: probe.StaticUrl,
+		Labels:    &labels,
+		Status:    v1.Pending,
+		Interval:  probe.Interval,
+		Timeout:   probe.Timeout,
+		Module:    probe.Module,
+		Alerting:  probe.Alerting,
+		Owner:     probe.Owner,
+	}
+	if probe.Paused != nil && *probe.Paused {
+		imported.Paused = probe.Paused
+	}
+	if imported.StaticUrl == "" {
+		return v1.ProbeObject{}, inField("static_url", fmt.Errorf("static_url is required"))
+	}
+	if err := s.DefaultLabels.apply(&imported); err != nil {
+		return v1.ProbeObject{}, inField("labels", err)
+	}
+	if err := s.Mutations.Mutate(ctx, &imported); err != nil {
+		return v1.ProbeObject{}, err
+	}
+	if probe.AdditionalUrls != nil {
+		if err := setAdditionalURLs(&imported, *probe.AdditionalUrls); err != nil {
+			return v1.ProbeObject{}, inField("additional_urls", err)
+		}
+	}
+	if probe.Regions != nil {
+		if err := setRegions(&imported, *probe.Regions); err != nil {
+			return v1.ProbeObject{}, inField("regions", err)
+		}
+	}
+	if err := importProbeType(&imported, probe); err != nil {
+		return v1.ProbeObject{}, inField(typeField(probe), err)
+	}
+	if err := s.Schedule.apply(&imported); err != nil {
+		return v1.ProbeObject{}, err
+	}
+	if probe.Assertions != nil {
+		setAssertions(&imported, *probe.Assertions)
+	}
+	if tenant != "" {
+		(*imported.Labels)[tenantLabelKey] = tenant
+	}
+	urlHash := probestore.URLHash(imported.StaticUrl)
+	imported.UrlHash = &urlHash
+	return imported, nil
+}
+
+// typeField returns the field holding the dns, tcp or icmp settings of a
+// probe of a bundle, "" for other probes.
+func typeField(probe v1.BundledProbe) string {
+	switch {
+	case probe.Dns != nil:
+		return "dns"
+	case probe.Tcp != nil:
+		return "tcp"
+	case probe.Icmp != nil:
+		return "icmp"
+	}
+	return ""
+}
+
+// importError returns the error reported for the probe at index i of a
+// bundle, with the path of the field err was marked with.
+func importError(i int, probe v1.BundledProbe, code int, err error) v1.ImportError {
+	failure := v1.ImportError{Index: i, Code: code, Message: err.Error()}
+	if probe.Id != uuid.Nil {
+		failure.Id = &probe.Id
+	}
+	if probe.StaticUrl != "" {
+		failure.StaticUrl = &probe.StaticUrl
+	}
+	if field := errorField(err); field != "" {
+		failure.Field = new(fmt.Sprintf("probes[%d].%s", i, field))
+	}
+	return failure
+}
+
+// overwrite returns the stored probe with the settings and labels of the
+// imported one. The maintained labels of the stored probe are kept.
+func overwrite(stored, imported v1.ProbeObject) v1.ProbeObject {
+	updated := stored
+	labels := maps.Clone(*imported.Labels)
+	if stored.Labels != nil {
+		for _, key := range maintainedLabels {
+			if value, ok := (*stored.Labels)[key]; ok {
+				labels[key] = value
+			}
+		}
+	}
+	updated.Labels = &labels
+	updated.AdditionalUrls = imported.AdditionalUrls
+	pruneTargetStatuses(&updated)
+	updated.Regions = imported.Regions
+	updated.Interval = imported.Interval
+	updated.Timeout = imported.Timeout
+	updated.Module = imported.Module
+	updated.Alerting = imported.Alerting
+	updated.Owner = imported.Owner
+	updated.Assertions = imported.Assertions
+	updated.Dns = imported.Dns
+	updated.Tcp = imported.Tcp
+	updated.Icmp = imported.Icmp
+	updated.Paused = imported.Paused
+	return updated
+}
+
+// (POST /probes/import)
+func (s Server) ImportProbes(ctx context.Context, request v1.ImportProbesRequestObject) (v1.ImportProbesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("import_probes", time.Now())
+	params := request.Params
+
+	strategy := v1.Fail
+	if params.OnConflict != nil {
+		strategy = *params.OnConflict
+	}
+	if !slices.Contains([]v1.ImportConflictStrategy{v1.Skip, v1.Overwrite, v1.Fail}, strategy) {
+		return v1.ImportProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid on_conflict %q, expected skip, overwrite or fail", strategy)}}, nil
+	}
+	bundle, err := decodeBundle(request)
+	if err != nil {
+		return v1.ImportProbes400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	}
+	if bundle.Version != bundleVersion {
+		return v1.ImportProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("unsupported bundle version %d, expected %d", bundle.Version, bundleVersion)}}, nil
+	}
+	tenant := s.callerTenant(ctx)
+	if tenant != "" {
+		if err := validateTenant(tenant); err != nil {
+			return v1.ImportProbes400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+		}
+	}
+
+	// Conflicts are looked for among every live probe, whatever its tenant,
+	// as the store keeps one probe per URL.
+	stored, err := s.Store.ListProbes(ctx, fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue))
+	if err != nil {
+		metrics.RecordProbestoreError("import_probes")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	byURL := make(map[string]v1.ProbeObject, len(stored))
+	storedIDs := make(map[uuid.UUID]bool, len(stored))
+	for _, probe := range stored {
+		storedIDs[probe.Id] = true
+		if probe.Status != v1.Terminating && probe.Status != v1.Deleted {
+			byURL[probeURLHash(probe.StaticUrl)] = probe
+		}
+	}
+
+	response := v1.ProbeImportResponse{Created: []v1.ImportedProbe{}, Overwritten: []v1.ImportedProbe{}, Skipped: []v1.ImportedProbe{}, Errors: []v1.ImportError{}}
+	var creates, overwrites []v1.ProbeObject
+	var conflicts []string
+	inBundle := make(map[string]int, len(bundle.Probes))
+	for i, probe := range bundle.Probes {
+		if probe.Status != nil && (*probe.Status == v1.Terminating || *probe.Status == v1.Deleted) {
+			response.Skipped = append(response.Skipped, v1.ImportedProbe{Id: probe.Id, StaticUrl: probe.StaticUrl})
+			continue
+		}
+		// The tenant label is restored like the probe's other labels for
+		// callers not scoped to a tenant, who see the probes of every tenant,
+		// so it is not among those the label policy checks.
+		labels := importedLabels(probe.Labels, tenant)
+		checked := maps.Clone(labels)
+		delete(checked, tenantLabelKey)
+		imported, err := s.importedProbe(ctx, probe, labels, tenant)
+		if err != nil {
+			response.Errors = append(response.Errors, importError(i, probe, http.StatusBadRequest, err))
+			continue
+		}
+		hash := probeURLHash(imported.StaticUrl)
+		if j, ok := inBundle[hash]; ok {
+			err := fmt.Errorf("probes[%d] has the same static_url %q", j, imported.StaticUrl)
+			response.Errors = append(response.Errors, importError(i, probe, http.StatusBadRequest, inField("static_url", err)))
+			continue
+		}
+		inBundle[hash] = i
+
+		// Skipped and conflicting probes are not written, so only the
+		// probes created or overwritten are validated.
+		review := ProbeReview{Probe: imported, Labels: checked, Tenant: tenant, Pending: len(creates)}
+		existing, conflict := byURL[hash]
+		switch {
+		case !conflict:
+		case strategy == v1.Skip:
+			response.Skipped = append(response.Skipped, v1.ImportedProbe{Id: probe.Id, StaticUrl: probe.StaticUrl})
+			continue
+		case strategy == v1.Overwrite && ownedBy(existing, tenant):
+			review.Probe, review.Existing = overwrite(existing, imported), &existing
+		default:
+			conflicts = append(conflicts, imported.StaticUrl)
+			continue
+		}
+		if err := s.validators().Validate(ctx, review); err != nil {
+			if isFailed(err) {
+				metrics.RecordProbestoreError("import_probes")
+				slog.ErrorContext(ctx, "Error validating probe", "error", err)
+				return nil, fmt.Errorf("failed to validate probe: %w", err)
+			}
+			code := http.StatusBadRequest
+			if isForbidden(err) {
+				code = http.StatusForbidden
+			}
+			response.Errors = append(response.Errors, importError(i, probe, code, err))
+			continue
+		}
+		if !review.IsCreate() {
+			overwrites = append(overwrites, review.Probe)
+			response.Overwritten = append(response.Overwritten, v1.ImportedProbe{Id: existing.Id, StaticUrl: existing.StaticUrl})
+			continue
+		}
+		if imported.Id == uuid.Nil || storedIDs[imported.Id] {
+			imported.Id = uuid.New()
+		}
+		storedIDs[imported.Id] = true
+		creates = append(creates, imported)
+		response.Created = append(response.Created, v1.ImportedProbe{Id: imported.Id, StaticUrl: imported.StaticUrl})
+	}
+	if len(conflicts) > 0 {
+		listed := strings.Join(conflicts[:min(len(conflicts), maxReportedConflicts)], ", ")
+		if more := len(conflicts) - maxReportedConflicts; more > 0 {
+			listed += fmt.Sprintf(" and %d more", more)
+		}
+		return v1.ImportProbes409JSONResponse{Error: v1.ErrorObject{
+			Message: fmt.Sprintf("%d probes of the bundle conflict with stored probes for the same static_url: %s", len(conflicts), listed),
+		}}, nil
+	}
+	if len(response.Errors) > 0 && params.AllOrNothing != nil && *params.AllOrNothing {
+		return v1.ImportProbes422JSONResponse(response), nil
+	}
+	if isDryRun(params.DryRun) {
+		return importResponse(response), nil
+	}
+
+	// Probes written before a store error are kept; the import can be run
+	// again with on_conflict=skip to finish it.
+	for _, probe := range overwrites {
+		before := byURL[probeURLHash(probe.StaticUrl)]
+		updated, err := s.Store.UpdateProbe(s.withStatusChange(ctx, "overwritten by import"), probe)
+		if err != nil {
+			metrics.RecordProbestoreError("import_probes")
+			slog.ErrorContext(ctx, "Error updating probe in storage", "probe_id", probe.Id, "error", err)
+			return nil, fmt.Errorf("failed to overwrite probe %s: %w", probe.Id, err)
+		}
+		s.Audit.Record(ctx, v1.UpdateProbe, updated.Id, &before, updated)
+	}
+	for _, probe := range creates {
+		created, err := s.Store.CreateProbe(ctx, probe, probeURLHash(probe.StaticUrl))
+		if err != nil {
+			metrics.RecordProbestoreError("import_probes")
+			if k8serrors.IsAlreadyExists(err) {
+				return v1.ImportProbes409JSONResponse{Error: v1.ErrorObject{
+					Message: fmt.Sprintf("a probe for static_url %q was created during the import; probes imported before it are kept", probe.StaticUrl),
+				}}, nil
+			}
+			slog.ErrorContext(ctx, "Error creating probe", "probe_id", probe.Id, "error", err)
+			return nil, fmt.Errorf("failed to create probe %s: %w", probe.Id, err)
+		}
+		s.Audit.Record(ctx, v1.CreateProbe, created.Id, nil, created)
+		s.Webhooks.Notify(ctx, v1.ProbeCreated, *created, nil)
+		s.Events.Publish(ctx, v1.ProbeCreated, *created, nil)
+	}
+	slog.InfoContext(ctx, "Imported probes", "created", len(response.Created), "overwritten", len(response.Overwritten), "skipped", len(response.Skipped), "failed", len(response.Errors))
+	return importResponse(response), nil
+}
+
+// importResponse answers an import with 207 when some probes of the bundle
+// failed validation and were left out, and 200 when none did.
+func importResponse(response v1.ProbeImportResponse) v1.ImportProbesResponseObject {
+	if len(response.Errors) > 0 {
+		return v1.ImportProbes207JSONResponse(response)
+	}
+	return v1.ImportProbes200JSONResponse(response)
+}
@@ -0,0 +1,350 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportProbes(t *testing.T) {
+	first, second := uuid.MustParse("00000000-0000-0000-0000-000000000001"), uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	store := newProbeStore(t,
+		v1.ProbeObject{Id: second, StaticUrl: "https://two.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{
+			"last-reconciled": "20260301T120000Z", "team": "sre",
+		}, Paused: new(true), AdditionalUrls: &v1.AdditionalUrlsSchema{"https://console.two.example.com"}},
+		v1.ProbeObject{Id: first, StaticUrl: "https://one.example.com", Status: v1.Pending},
+	)
+	server := NewServer(store)
+
+	res, err := server.ExportProbes(context.Background(), v1.ExportProbesRequestObject{})
+	require.NoError(t, err)
+	require.IsType(t, v1.ExportProbes200JSONResponse{}, res)
+	bundle := res.(v1.ExportProbes200JSONResponse)
+	assert.Equal(t, 1, bundle.Version)
+	require.Len(t, bundle.Probes, 2)
+	assert.Equal(t, first, bundle.Probes[0].Id, "probes are sorted by ID")
+	assert.Equal(t, &v1.LabelsSchema{"team": "sre"}, bundle.Probes[1].Labels, "maintained labels are left out")
+	assert.Equal(t, new(true), bundle.Probes[1].Paused)
+	assert.Equal(t, &v1.AdditionalUrlsSchema{"https://console.two.example.com"}, bundle.Probes[1].AdditionalUrls)
+
+	format := v1.Yaml
+	res, err = server.ExportProbes(context.Background(), v1.ExportProbesRequestObject{Params: v1.ExportProbesParams{Format: &format}})
+	require.NoError(t, err)
+	require.IsType(t, v1.ExportProbes200ApplicationyamlResponse{}, res)
+	data, err := io.ReadAll(res.(v1.ExportProbes200ApplicationyamlResponse).Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "static_url: https://two.example.com")
+}
+
+func TestExportProbes_Filters(t *testing.T) {
+	first, second := uuid.MustParse("00000000-0000-0000-0000-000000000001"), uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	store := newProbeStore(t,
+		v1.ProbeObject{Id: first, StaticUrl: "https://one.example.com", Status: v1.Pending, Labels: &v1.LabelsSchema{"team": "sre"}},
+		v1.ProbeObject{Id: second, StaticUrl: "https://two.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"team": "web"}},
+	)
+	server := NewServer(store)
+	export := func(params v1.ExportProbesParams) *httptest.ResponseRecorder {
+		t.Helper()
+		res, err := server.ExportProbes(context.Background(), v1.ExportProbesRequestObject{Params: params})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		require.NoError(t, res.VisitExportProbesResponse(w))
+		return w
+	}
+
+	t.Run("status", func(t *testing.T) {
+		res, err := server.ExportProbes(context.Background(), v1.ExportProbesRequestObject{Params: v1.ExportProbesParams{Status: new("active, failed")}})
+		require.NoError(t, err)
+		require.IsType(t, v1.ExportProbes200JSONResponse{}, res)
+		bundle := res.(v1.ExportProbes200JSONResponse)
+		require.Len(t, bundle.Probes, 1)
+		assert.Equal(t, second, bundle.Probes[0].Id)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		w := export(v1.ExportProbesParams{Fields: new("id,static_url")})
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var bundle struct {
+			Version int              `json:"version"`
+			Probes  []map[string]any `json:"probes"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+		assert.Equal(t, 1, bundle.Version)
+		assert.Equal(t, []map[string]any{
+			{"id": first.String(), "static_url": "https://one.example.com"},
+			{"id": second.String(), "static_url": "https://two.example.com"},
+		}, bundle.Probes)
+
+		format := v1.Yaml
+		w = export(v1.ExportProbesParams{Fields: new("static_url"), Format: &format})
+		assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "- static_url: https://one.example.com")
+		assert.NotContains(t, w.Body.String(), "labels")
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		format := v1.Ndjson
+		w := export(v1.ExportProbesParams{Format: &format, Status: new("pending")})
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		var probe v1.BundledProbe
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &probe), "a single line holds the one pending probe")
+		assert.Equal(t, first, probe.Id)
+		assert.Equal(t, &v1.LabelsSchema{"team": "sre"}, probe.Labels)
+
+		w = export(v1.ExportProbesParams{Format: &format, Fields: new("id")})
+		assert.Equal(t, `{"id":"`+first.String()+`"}`+"\n"+`{"id":"`+second.String()+`"}`+"\n", w.Body.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for message, params := range map[string]v1.ExportProbesParams{
+			`invalid status: unknown status "running"`:     {Status: new("active,running")},
+			`invalid fields: unknown probe field "secret"`: {Fields: new("id,secret")},
+		} {
+			res, err := server.ExportProbes(context.Background(), v1.ExportProbesRequestObject{Params: params})
+			require.NoError(t, err)
+			require.IsType(t, v1.ExportProbes400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.ExportProbes400JSONResponse).Error.Message, message)
+		}
+	})
+}
+
+func TestImportProbes(t *testing.T) {
+	existingID := uuid.New()
+	newStore := func() *probestore.MemoryProbeStore {
+		return newProbeStore(t, v1.ProbeObject{
+			Id: existingID, StaticUrl: "https://existing.example.com", Status: v1.Active,
+			Labels: &v1.LabelsSchema{"team": "old"},
+		})
+	}
+	interval := "1m"
+	terminating := v1.Terminating
+	bundle := v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+		{Id: existingID, StaticUrl: "https://new.example.com", Labels: &v1.LabelsSchema{"team": "sre", "last-reconciled": "20260301T120000Z"}, Paused: new(true), AdditionalUrls: &v1.AdditionalUrlsSchema{"https://console.new.example.com"}},
+		{Id: uuid.New(), StaticUrl: "https://existing.example.com", Labels: &v1.LabelsSchema{"team": "new"}, Interval: &interval},
+		{Id: uuid.New(), StaticUrl: "https://gone.example.com", Status: &terminating},
+	}}
+	importProbes := func(server Server, ctx context.Context, strategy v1.ImportConflictStrategy, bundle v1.ProbeBundle) v1.ImportProbesResponseObject {
+		t.Helper()
+		res, err := server.ImportProbes(ctx, v1.ImportProbesRequestObject{Params: v1.ImportProbesParams{OnConflict: &strategy}, JSONBody: &bundle})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("fail rejects the import on a conflict", func(t *testing.T) {
+		store := newStore()
+		res := importProbes(NewServer(store), context.Background(), v1.Fail, bundle)
+		assert.Equal(t, v1.ImportProbes409JSONResponse{Error: v1.ErrorObject{
+			Message: "1 probes of the bundle conflict with stored probes for the same static_url: https://existing.example.com",
+		}}, res)
+		assert.Equal(t, 1, storedProbes(t, store), "nothing is imported")
+	})
+
+	t.Run("skip leaves conflicting probes out", func(t *testing.T) {
+		store := newStore()
+		res := importProbes(NewServer(store), context.Background(), v1.Skip, bundle)
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		result := res.(v1.ImportProbes200JSONResponse)
+		require.Len(t, result.Created, 1)
+		assert.Empty(t, result.Overwritten)
+		assert.Len(t, result.Skipped, 2, "the conflicting and the terminating probes")
+
+		created := storedProbe(t, store, result.Created[0].Id)
+		assert.NotEqual(t, existingID, created.Id, "a taken ID is replaced")
+		assert.Equal(t, v1.Pending, created.Status)
+		assert.Equal(t, "sre", (*created.Labels)["team"])
+		assert.NotContains(t, *created.Labels, "last-reconciled", "the heartbeat is not imported")
+		assert.Equal(t, new(true), created.Paused, "paused probes stay paused")
+		assert.Equal(t, &v1.AdditionalUrlsSchema{"https://console.new.example.com"}, created.AdditionalUrls)
+		assert.Equal(t, "old", (*storedProbe(t, store, existingID).Labels)["team"])
+	})
+
+	t.Run("overwrite replaces the settings of conflicting probes", func(t *testing.T) {
+		store := newStore()
+		res := importProbes(NewServer(store), context.Background(), v1.Overwrite, bundle)
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		result := res.(v1.ImportProbes200JSONResponse)
+		assert.Equal(t, []v1.ImportedProbe{{Id: existingID, StaticUrl: "https://existing.example.com"}}, result.Overwritten)
+
+		overwritten := storedProbe(t, store, existingID)
+		assert.Equal(t, "new", (*overwritten.Labels)["team"])
+		assert.Equal(t, "active", (*overwritten.Labels)[probeStatusLabelKey])
+		assert.Equal(t, &interval, overwritten.Interval)
+		assert.Equal(t, v1.Active, overwritten.Status)
+	})
+
+	t.Run("dry runs change nothing", func(t *testing.T) {
+		store := newStore()
+		res, err := NewServer(store).ImportProbes(context.Background(), v1.ImportProbesRequestObject{
+			Params:   v1.ImportProbesParams{DryRun: new(true), OnConflict: new(v1.Overwrite)},
+			JSONBody: &bundle,
+		})
+		require.NoError(t, err)
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		assert.Len(t, res.(v1.ImportProbes200JSONResponse).Created, 1)
+		assert.Equal(t, 1, storedProbes(t, store))
+		assert.Equal(t, "old", (*storedProbe(t, store, existingID).Labels)["team"])
+	})
+
+	t.Run("YAML bundles", func(t *testing.T) {
+		store := newStore()
+		yamlBundle := "version: 1\nprobes:\n- id: " + uuid.NewString() + "\n  static_url: https://yaml.example.com\n"
+		res, err := NewServer(store).ImportProbes(context.Background(), v1.ImportProbesRequestObject{Body: strings.NewReader(yamlBundle)})
+		require.NoError(t, err)
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		assert.Len(t, res.(v1.ImportProbes200JSONResponse).Created, 1)
+	})
+
+	t.Run("dns probes", func(t *testing.T) {
+		store := newStore()
+		query := v1.DnsSchema{QueryName: "api.example.com", RecordType: v1.A}
+		res := importProbes(NewServer(store), context.Background(), v1.Fail, v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+			{Id: uuid.New(), StaticUrl: query.URL(), Dns: &query},
+		}})
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		created := storedProbe(t, store, res.(v1.ImportProbes200JSONResponse).Created[0].Id)
+		assert.Equal(t, &query, created.Dns)
+		assert.Equal(t, v1.Dns, *created.Module)
+
+		res = importProbes(NewServer(store), context.Background(), v1.Fail, v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+			{Id: uuid.New(), StaticUrl: "https://dns.example.com", Dns: &query},
+		}})
+		require.IsType(t, v1.ImportProbes207JSONResponse{}, res)
+		failures := res.(v1.ImportProbes207JSONResponse).Errors
+		require.Len(t, failures, 1)
+		assert.Contains(t, failures[0].Message, "derived from its settings")
+		assert.Equal(t, new("probes[0].dns"), failures[0].Field)
+	})
+
+	t.Run("tenants import into their tenant", func(t *testing.T) {
+		store := newStore()
+		server := NewServer(store)
+		server.TenantIsolation = true
+		teamA := limits.WithTenant(context.Background(), "team-a")
+
+		res := importProbes(server, teamA, v1.Overwrite, bundle)
+		assert.IsType(t, v1.ImportProbes409JSONResponse{}, res, "probes of other tenants are not overwritten")
+
+		res = importProbes(server, teamA, v1.Skip, v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+			{Id: uuid.New(), StaticUrl: "https://team.example.com", Labels: &v1.LabelsSchema{tenantLabelKey: "team-b"}},
+		}})
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		created := storedProbe(t, store, res.(v1.ImportProbes200JSONResponse).Created[0].Id)
+		assert.Equal(t, "team-a", (*created.Labels)[tenantLabelKey])
+
+		res = importProbes(server, context.Background(), v1.Skip, v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+			{Id: uuid.New(), StaticUrl: "https://other-team.example.com", Labels: &v1.LabelsSchema{tenantLabelKey: "team-b"}},
+		}})
+		require.IsType(t, v1.ImportProbes200JSONResponse{}, res)
+		created = storedProbe(t, store, res.(v1.ImportProbes200JSONResponse).Created[0].Id)
+		assert.Equal(t, "team-b", (*created.Labels)[tenantLabelKey], "operators restore the tenant of probes")
+	})
+
+	t.Run("invalid probes are reported and the others imported", func(t *testing.T) {
+		invalidID := uuid.New()
+		mixed := v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+			{Id: uuid.New(), StaticUrl: "https://a.example.com"},
+			{Id: invalidID, StaticUrl: "https://b.example.com", Interval: new("1x")},
+			{Id: uuid.New(), StaticUrl: "https://c.example.com", Labels: &v1.LabelsSchema{"private": "true"}},
+			{Id: uuid.New(), StaticUrl: "https://a.example.com"},
+			{Id: uuid.New(), StaticUrl: "https://d.example.com"},
+		}}
+		expected := []v1.ImportError{
+			{Index: 1, Id: &invalidID, StaticUrl: new("https://b.example.com"), Code: http.StatusBadRequest, Field: new("probes[1].interval"),
+				Message: `invalid interval "1x": time: unknown unit "x" in duration "1x"`},
+			{Index: 2, Id: &mixed.Probes[2].Id, StaticUrl: new("https://c.example.com"), Code: http.StatusForbidden, Field: new("probes[2].labels"),
+				Message: "creation of system-managed label 'private' is forbidden"},
+			{Index: 3, Id: &mixed.Probes[3].Id, StaticUrl: new("https://a.example.com"), Code: http.StatusBadRequest, Field: new("probes[3].static_url"),
+				Message: `probes[0] has the same static_url "https://a.example.com"`},
+		}
+
+		store := newStore()
+		res := importProbes(NewServer(store), context.Background(), v1.Fail, mixed)
+		require.IsType(t, v1.ImportProbes207JSONResponse{}, res)
+		result := res.(v1.ImportProbes207JSONResponse)
+		assert.Equal(t, expected, result.Errors)
+		assert.Equal(t, []v1.ImportedProbe{
+			{Id: mixed.Probes[0].Id, StaticUrl: "https://a.example.com"},
+			{Id: mixed.Probes[4].Id, StaticUrl: "https://d.example.com"},
+		}, result.Created)
+		assert.Equal(t, 3, storedProbes(t, store), "the valid probes are imported")
+
+		store = newStore()
+		res, err := NewServer(store).ImportProbes(context.Background(), v1.ImportProbesRequestObject{
+			Params:   v1.ImportProbesParams{AllOrNothing: new(true)},
+			JSONBody: &mixed,
+		})
+		require.NoError(t, err)
+		require.IsType(t, v1.ImportProbes422JSONResponse{}, res)
+		assert.Equal(t, expected, res.(v1.ImportProbes422JSONResponse).Errors)
+		assert.Equal(t, 1, storedProbes(t, store), "nothing is imported with all_or_nothing")
+	})
+
+	t.Run("validators apply to imported probes", func(t *testing.T) {
+		store := newStore()
+		server := NewServer(store)
+		required, err := RequiredLabels([]string{"team"})
+		require.NoError(t, err)
+		quota, err := Quota(store, 3, nil)
+		require.NoError(t, err)
+		policy, err := URLPolicy(nil, nil, []string{"denied.example.com"})
+		require.NoError(t, err)
+		server.Validators = Validators{required, quota, policy}
+
+		res := importProbes(server, context.Background(), v1.Overwrite, v1.ProbeBundle{Version: 1, Probes: []v1.BundledProbe{
+			{Id: uuid.New(), StaticUrl: "https://a.example.com", Labels: &v1.LabelsSchema{"team": "sre"}},
+			{Id: uuid.New(), StaticUrl: "https://b.example.com"},
+			{Id: uuid.New(), StaticUrl: "https://denied.example.com", Labels: &v1.LabelsSchema{"team": "sre"}},
+			{Id: uuid.New(), StaticUrl: "https://c.example.com", Labels: &v1.LabelsSchema{"team": "sre"}},
+			{Id: uuid.New(), StaticUrl: "https://d.example.com", Labels: &v1.LabelsSchema{"team": "sre"}},
+			{Id: uuid.New(), StaticUrl: "https://existing.example.com", Labels: &v1.LabelsSchema{"team": "sre"}, Owner: &v1.OwnerSchema{Team: new("")}},
+		}})
+		require.IsType(t, v1.ImportProbes207JSONResponse{}, res)
+		result := res.(v1.ImportProbes207JSONResponse)
+		assert.Equal(t, []v1.ImportedProbe{
+			{Id: result.Created[0].Id, StaticUrl: "https://a.example.com"},
+			{Id: result.Created[1].Id, StaticUrl: "https://c.example.com"},
+		}, result.Created)
+		assert.Empty(t, result.Overwritten)
+
+		type failure struct {
+			index int
+			code  int
+			field string
+		}
+		var failures []failure
+		for _, e := range result.Errors {
+			field := ""
+			if e.Field != nil {
+				field = *e.Field
+			}
+			failures = append(failures, failure{e.Index, e.Code, field})
+		}
+		assert.Equal(t, []failure{
+			{1, http.StatusBadRequest, "probes[1].labels"},
+			{2, http.StatusForbidden, "probes[2].static_url"},
+			{4, http.StatusForbidden, ""},
+			{5, http.StatusBadRequest, "probes[5].owner"},
+		}, failures, "required_labels, url_policy, the quota counting the probes created before, and owner on overwrite")
+		assert.Contains(t, result.Errors[2].Message, "3 of 3 probes in use")
+		assert.Equal(t, 3, storedProbes(t, store))
+	})
+
+	t.Run("unsupported bundle versions", func(t *testing.T) {
+		store := newStore()
+		res := importProbes(NewServer(store), context.Background(), v1.Fail, v1.ProbeBundle{Version: 2})
+		assert.Equal(t, v1.ImportProbes400JSONResponse{Error: v1.ErrorObject{Message: "unsupported bundle version 2, expected 1"}}, res)
+		assert.Equal(t, 1, storedProbes(t, store))
+	})
+}
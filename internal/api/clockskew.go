@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// skewTolerance returns how far ahead of the server clock the timestamps
+// clients supply may be.
+func (s Server) skewTolerance() time.Duration {
+	if s.ClockSkewTolerance <= 0 {
+		return clock.DefaultSkewTolerance
+	}
+	return s.ClockSkewTolerance
+}
+
+// checkSkew checks the timestamp named field supplied by the client against
+// the server clock. Rejections are counted and logged, so clients whose clock
+// runs ahead can be found.
+func (s Server) checkSkew(ctx context.Context, field string, t time.Time) error {
+	err := clock.CheckSkew(field, t, clock.Now(), s.skewTolerance())
+	if skewErr, ok := errors.AsType[*clock.SkewError](err); ok {
+		metrics.RecordClockSkewRejection(field)
+		slog.WarnContext(ctx, "Rejected a timestamp ahead of the server clock", "field", field, "skew", skewErr.Skew())
+	}
+	return err
+}
+
+// checkHeartbeat checks the last-reconciled heartbeat among the labels set by
+// the client, if any. A heartbeat ahead of the server clock would keep the
+// garbage collector from finding the probe stale until the clocks met.
+// Heartbeats that do not parse are left to the garbage collector, which
+// skips them.
+func (s Server) checkHeartbeat(ctx context.Context, labels v1.LabelsSchema) error {
+	lastReconciled, ok := probestore.LastReconciled(v1.ProbeObject{Labels: &labels})
+	if !ok {
+		return nil
+	}
+	return s.checkSkew(ctx, "labels."+probestore.LastReconciledLabel, lastReconciled)
+}
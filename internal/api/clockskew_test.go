@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_ClockSkew(t *testing.T) {
+	ctx := context.Background()
+	probeID := uuid.New()
+	store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Labels: &v1.LabelsSchema{}})
+	server := NewServer(store)
+	server.ClockSkewTolerance = time.Minute
+	heartbeat := func(at time.Time) *v1.LabelsSchema {
+		return &v1.LabelsSchema{"last-reconciled": at.UTC().Format("20060102T150405Z")}
+	}
+
+	t.Run("results may run slightly ahead of the server clock", func(t *testing.T) {
+		ranAt := time.Now().Add(30 * time.Second)
+		res, err := server.ReportProbeResult(ctx, v1.ReportProbeResultRequestObject{ProbeId: probeID, Body: &v1.ReportProbeResultJSONRequestBody{Success: true, StatusCode: 200, Timestamp: &ranAt}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ReportProbeResult201JSONResponse{}, res)
+	})
+
+	t.Run("results further ahead are rejected", func(t *testing.T) {
+		ranAt := time.Now().Add(time.Hour)
+		res, err := server.ReportProbeResult(ctx, v1.ReportProbeResultRequestObject{ProbeId: probeID, Body: &v1.ReportProbeResultJSONRequestBody{Success: true, StatusCode: 200, Timestamp: &ranAt}})
+		require.NoError(t, err)
+		require.IsType(t, v1.ReportProbeResult400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.ReportProbeResult400JSONResponse).Error.Message, "ahead of the server clock")
+		assert.Len(t, server.Results.List(probeID), 1, "rejected results are not kept")
+	})
+
+	t.Run("heartbeats ahead of the server clock are rejected", func(t *testing.T) {
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{Labels: heartbeat(time.Now().Add(time.Hour))}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "labels.last-reconciled")
+		assert.NotContains(t, *storedProbe(t, store, probeID).Labels, "last-reconciled")
+
+		res, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{Labels: heartbeat(time.Now())}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+	})
+
+	t.Run("new probes cannot carry a heartbeat from the future", func(t *testing.T) {
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com/other", Labels: heartbeat(time.Now().Add(time.Hour))}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe400JSONResponse{}, res)
+	})
+}
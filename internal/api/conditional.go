@@ -0,0 +1,51 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// listETag returns the ETag of a ListProbes response: a fingerprint of the
+// probes' version, of everything else the response holds and of the query
+// that shaped it, quoted. The query counts because the version only covers
+// whole probes: a sparse listing of the fields projection, or the same probes
+// matched by another selector, is another representation and must not be
+// revalidated by the ETag of this one.
+func listETag(response v1.ProbesArrayResponse, selector, fieldSelector string, fields []string) (string, error) {
+	// The projection is a set; the order it was named in does not change the
+	// response.
+	fields = slices.Sorted(slices.Values(fields))
+	data, err := json.Marshal(struct {
+		Features      *v1.FeaturesSchema `json:"features,omitempty"`
+		NextPageToken *string            `json:"next_page_token,omitempty"`
+		Version       *string            `json:"version,omitempty"`
+		Selector      string             `json:"selector"`
+		FieldSelector string             `json:"field_selector,omitempty"`
+		Fields        []string           `json:"fields,omitempty"`
+	}{response.Features, response.NextPageToken, response.Version, selector, fieldSelector, fields})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// ifNoneMatch reports whether an If-None-Match header lists the ETag, or is
+// "*". ETags are compared weakly, ignoring a W/ prefix, as RFC 9110 asks.
+func ifNoneMatch(header *string, etag string) bool {
+	if header == nil {
+		return false
+	}
+	for tag := range strings.SplitSeq(*header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
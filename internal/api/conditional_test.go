@@ -0,0 +1,59 @@
+package api
+
+import (
+	"testing"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListETag(t *testing.T) {
+	version, other := "a1", "b2"
+	selector := "app=rhobs-synthetics-probe"
+	tag, err := listETag(v1.ProbesArrayResponse{Version: &version}, selector, "", nil)
+	require.NoError(t, err)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, tag)
+
+	changed, err := listETag(v1.ProbesArrayResponse{Version: &other}, selector, "", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, changed)
+
+	paged, err := listETag(v1.ProbesArrayResponse{Version: &version, NextPageToken: &other}, selector, "", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, paged, "the next page token is part of the response")
+
+	selected, err := listETag(v1.ProbesArrayResponse{Version: &version}, selector+",env=prod", "", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, selected, "the label selector is part of the query")
+
+	filtered, err := listETag(v1.ProbesArrayResponse{Version: &version}, selector, "status=active", nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, filtered, "the field selector is part of the query")
+
+	sparse, err := listETag(v1.ProbesArrayResponse{Version: &version}, selector, "", []string{"id", "static_url"})
+	require.NoError(t, err)
+	assert.NotEqual(t, tag, sparse, "a sparse listing is another representation")
+
+	reordered, err := listETag(v1.ProbesArrayResponse{Version: &version}, selector, "", []string{"static_url", "id"})
+	require.NoError(t, err)
+	assert.Equal(t, sparse, reordered, "the order fields are named in does not matter")
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	tag := `"abc"`
+	for header, expected := range map[string]bool{
+		`"abc"`:           true,
+		`W/"abc"`:         true,
+		`"xyz", "abc"`:    true,
+		`*`:               true,
+		`"xyz"`:           false,
+		`abc`:             false,
+		``:                false,
+		`"abc-gzip"`:      false,
+		` "xyz" ,W/"abc"`: true,
+	} {
+		assert.Equal(t, expected, ifNoneMatch(&header, tag), header)
+	}
+	assert.False(t, ifNoneMatch(nil, tag))
+}
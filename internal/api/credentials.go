@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/secrets"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// secretPruneGrace is how long before a probe listing the values of a probe
+// missing from it must have been written to be pruned. It covers probes
+// created while the listing ran and the second precision of Secret
+// timestamps.
+const secretPruneGrace = time.Minute
+
+// splitAuth splits the credentials of a request into those stored with the
+// probe, holding secrets.Redacted in place of the secret values, and the
+// values themselves. Values sent as secrets.Redacted are kept from current,
+// the values stored for the probe so far. Credentials with no field set
+// remove those of the probe.
+func (s Server) splitAuth(auth v1.ProbeAuthSchema, current map[string]string) (*v1.ProbeAuthSchema, map[string]string, error) {
+	set := func(value *string) bool { return value != nil && *value != "" }
+	if !set(auth.Username) && !set(auth.Password) && !set(auth.BearerToken) {
+		return nil, nil, nil
+	}
+	if set(auth.BearerToken) && (set(auth.Username) || set(auth.Password)) {
+		return nil, nil, errors.New("auth takes either a username and password or a bearer_token, not both")
+	}
+	if set(auth.Password) && !set(auth.Username) {
+		return nil, nil, errors.New("auth password requires a username")
+	}
+
+	stored := &v1.ProbeAuthSchema{}
+	if set(auth.Username) {
+		stored.Username = auth.Username
+	}
+	values := map[string]string{}
+	for key, value := range map[string]*string{secrets.PasswordKey: auth.Password, secrets.BearerTokenKey: auth.BearerToken} {
+		if !set(value) {
+			continue
+		}
+		if *value == secrets.Redacted {
+			kept, ok := current[key]
+			if !ok {
+				return nil, nil, fmt.Errorf("auth %s is %s but the probe has no stored value to keep", key, secrets.Redacted)
+			}
+			values[key] = kept
+		} else {
+			values[key] = *value
+		}
+		switch key {
+		case secrets.PasswordKey:
+			stored.Password = new(secrets.Redacted)
+		case secrets.BearerTokenKey:
+			stored.BearerToken = new(secrets.Redacted)
+		}
+	}
+	if len(values) > 0 && s.Secrets == nil {
+		return nil, nil, errors.New("auth passwords and bearer tokens need a secret store, which is not configured for this storage engine")
+	}
+	return stored, values, nil
+}
+
+// storedAuth returns the secret values stored for a probe, or nil.
+func (s Server) storedAuth(ctx context.Context, probeID uuid.UUID) (map[string]string, error) {
+	if s.Secrets == nil {
+		return nil, nil
+	}
+	return s.Secrets.Get(ctx, probeID)
+}
+
+// putAuth stores the secret values of a probe, removing them when there are
+// none.
+func (s Server) putAuth(ctx context.Context, probeID uuid.UUID, values map[string]string) error {
+	if s.Secrets == nil {
+		return nil
+	}
+	if len(values) == 0 {
+		return s.Secrets.Delete(ctx, probeID)
+	}
+	return s.Secrets.Put(ctx, probeID, values)
+}
+
+// pruneSecrets removes the secret values of probes that no longer exist:
+// those missing from a listing of every probe, started at listed, and
+// written well before it.
+func (s Server) pruneSecrets(ctx context.Context, existing map[uuid.UUID]bool, listed time.Time) {
+	if s.Secrets == nil {
+		return
+	}
+	written, err := s.Secrets.List(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing probe secrets", "error", err)
+		return
+	}
+	for probeID, at := range written {
+		if existing[probeID] || !at.Before(listed.Add(-secretPruneGrace)) {
+			continue
+		}
+		if err := s.Secrets.Delete(ctx, probeID); err != nil {
+			slog.ErrorContext(ctx, "Error deleting secret of removed probe", "probe_id", probeID, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "Deleted secret of removed probe", "probe_id", probeID)
+	}
+}
+
+// (GET /probes/{probe_id}/auth)
+func (s Server) GetProbeAuth(ctx context.Context, request v1.GetProbeAuthRequestObject) (v1.GetProbeAuthResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("get_probe_auth", time.Now())
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+
+	if s.AgentAuth == nil {
+		return v1.GetProbeAuth501JSONResponse{
+			Error: v1.ErrorObject{Message: "agent credentials are not configured"},
+		}, nil
+	}
+	agent := agentauth.AgentFromContext(ctx)
+	if agent == "" {
+		return v1.GetProbeAuth403JSONResponse{
+			Error: v1.ErrorObject{Message: "only agents may read probe credentials"},
+		}, nil
+	}
+	probe, err := s.getProbe(ctx, request.ProbeId)
+	if err != nil {
+		metrics.RecordProbestoreError("get_probe_auth")
+		if k8serrors.IsNotFound(err) {
+			return v1.GetProbeAuth404JSONResponse{
+				Warning: v1.WarningObject{
+					Message: fmt.Sprintf("probe with ID %s not found", request.ProbeId),
+				},
+			}, nil
+		}
+		slog.ErrorContext(ctx, "Error getting probe from storage", "error", err)
+		return nil, fmt.Errorf("failed to get probe from storage: %w", err)
+	}
+	// Credentials are only handed to the agent running the probe.
+	if assigned := assignment.AssignedAgent(*probe); assigned != agent {
+		slog.WarnContext(ctx, "Agent denied the credentials of a probe it is not assigned", "agent", agent, "assigned_agent", assigned)
+		return v1.GetProbeAuth403JSONResponse{
+			Error: v1.ErrorObject{Message: fmt.Sprintf("probe %s is not assigned to agent %s", request.ProbeId, agent)},
+		}, nil
+	}
+	if probe.Auth == nil {
+		return v1.GetProbeAuth200JSONResponse{}, nil
+	}
+
+	values, err := s.storedAuth(ctx, request.ProbeId)
+	if err != nil {
+		metrics.RecordProbestoreError("get_probe_auth")
+		slog.ErrorContext(ctx, "Error getting probe secret", "error", err)
+		return nil, fmt.Errorf("failed to get probe secret: %w", err)
+	}
+	auth := v1.GetProbeAuth200JSONResponse{Username: probe.Auth.Username}
+	if value, ok := values[secrets.PasswordKey]; ok {
+		auth.Password = &value
+	}
+	if value, ok := values[secrets.BearerTokenKey]; ok {
+		auth.BearerToken = &value
+	}
+	return auth, nil
+}
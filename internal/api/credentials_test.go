@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/secrets"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeCredentials(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	secretStore, err := secrets.NewFileStore(dir, []byte(strings.Repeat("k", secrets.MinKeyLength)))
+	require.NoError(t, err)
+	issuer, err := agentauth.NewIssuer([]byte(strings.Repeat("k", agentauth.MinKeyLength)), time.Hour, 0)
+	require.NoError(t, err)
+	store := newProbeStore(t)
+	server := NewServer(store)
+	server.Secrets = secretStore
+	server.AgentAuth = issuer
+
+	bootstrap, err := issuer.MintBootstrap("agent-1", 0)
+	require.NoError(t, err)
+	cred, err := issuer.Exchange(context.Background(), bootstrap.Token, "agent-1")
+	require.NoError(t, err)
+	agentCtx := bearerContext(issuer, cred.Credential)
+
+	username, password, redacted := "prober", "s3cret", secrets.Redacted
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://api.internal.example.com",
+		Auth:      &v1.ProbeAuthSchema{Username: &username, Password: &password},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	created := res.(v1.CreateProbe201JSONResponse)
+	probeID := created.Id
+	assert.Equal(t, &v1.ProbeAuthSchema{Username: &username, Password: &redacted}, created.Auth)
+	assert.Equal(t, &v1.ProbeAuthSchema{Username: &username, Password: &redacted}, storedProbe(t, store, probeID).Auth)
+
+	getAuth := func(ctx context.Context) v1.GetProbeAuthResponseObject {
+		t.Helper()
+		res, err := server.GetProbeAuth(ctx, v1.GetProbeAuthRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("only the assigned agent reads the values", func(t *testing.T) {
+		assert.IsType(t, v1.GetProbeAuth403JSONResponse{}, getAuth(ctx))
+		assert.Equal(t, v1.GetProbeAuth403JSONResponse{Error: v1.ErrorObject{Message: "probe " + probeID.String() + " is not assigned to agent agent-1"}}, getAuth(agentCtx),
+			"unassigned probes hand their credentials to no agent")
+
+		probe := storedProbe(t, store, probeID)
+		(*probe.Labels)[assignment.AgentLabelKey] = "agent-1"
+		_, err := store.UpdateProbe(context.Background(), probe)
+		require.NoError(t, err)
+		assert.Equal(t, v1.GetProbeAuth200JSONResponse{Username: &username, Password: &password}, getAuth(agentCtx))
+
+		bootstrap, err := issuer.MintBootstrap("agent-2", 0)
+		require.NoError(t, err)
+		other, err := issuer.Exchange(context.Background(), bootstrap.Token, "agent-2")
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeAuth403JSONResponse{}, getAuth(bearerContext(issuer, other.Credential)))
+
+		withoutIssuer := server
+		withoutIssuer.AgentAuth = nil
+		res, err := withoutIssuer.GetProbeAuth(agentCtx, v1.GetProbeAuthRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeAuth501JSONResponse{}, res)
+	})
+
+	t.Run("redacted values are kept on update", func(t *testing.T) {
+		other := "operator"
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Auth: &v1.ProbeAuthSchema{Username: &other, Password: &redacted},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		assert.Equal(t, v1.GetProbeAuth200JSONResponse{Username: &other, Password: &password}, getAuth(agentCtx))
+	})
+
+	t.Run("invalid credentials are rejected", func(t *testing.T) {
+		token := "token"
+		for _, auth := range []v1.ProbeAuthSchema{
+			{Username: &username, BearerToken: &token},
+			{Password: &password},
+			{BearerToken: &redacted},
+		} {
+			res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{Auth: &auth}})
+			require.NoError(t, err)
+			assert.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		}
+	})
+
+	t.Run("an empty auth removes the credentials", func(t *testing.T) {
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Auth: &v1.ProbeAuthSchema{},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		assert.Nil(t, storedProbe(t, store, probeID).Auth)
+		assert.Equal(t, v1.GetProbeAuth200JSONResponse{}, getAuth(agentCtx))
+		values, err := secretStore.Get(ctx, probeID)
+		require.NoError(t, err)
+		assert.Nil(t, values)
+	})
+
+	t.Run("secret values need a secret store", func(t *testing.T) {
+		withoutSecrets := server
+		withoutSecrets.Secrets = nil
+		res, err := withoutSecrets.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			StaticUrl: "https://other.internal.example.com",
+			Auth:      &v1.ProbeAuthSchema{Username: &username, Password: &password},
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe400JSONResponse{}, res)
+	})
+
+	t.Run("secrets of removed probes are pruned", func(t *testing.T) {
+		removed, recent := uuid.New(), uuid.New()
+		require.NoError(t, secretStore.Put(ctx, probeID, map[string]string{secrets.PasswordKey: password}))
+		require.NoError(t, secretStore.Put(ctx, removed, map[string]string{secrets.PasswordKey: password}))
+		require.NoError(t, secretStore.Put(ctx, recent, map[string]string{secrets.PasswordKey: password}))
+		old := time.Now().Add(-time.Hour)
+		for _, id := range []uuid.UUID{probeID, removed} {
+			require.NoError(t, os.Chtimes(filepath.Join(dir, id.String()+".secret"), old, old))
+		}
+
+		server.pruneSecrets(ctx, map[uuid.UUID]bool{probeID: true}, time.Now())
+		written, err := secretStore.List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, written, 2)
+		assert.Contains(t, written, probeID)
+		assert.Contains(t, written, recent)
+	})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// LabelConflict is what happens when a new probe sets a default label to
+// another value.
+type LabelConflict string
+
+const (
+	// KeepProbeLabel keeps the value the probe sets.
+	KeepProbeLabel LabelConflict = "keep"
+	// OverrideProbeLabel replaces it with the default.
+	OverrideProbeLabel LabelConflict = "override"
+	// RejectProbeLabel rejects the probe.
+	RejectProbeLabel LabelConflict = "reject"
+)
+
+// DefaultLabels are labels merged into every probe created, such as the
+// environment or region of the deployment, so that clients need not know
+// them.
+type DefaultLabels struct {
+	Labels map[string]string `mapstructure:"labels"`
+	// Conflict is applied to probes setting a default label to another
+	// value. Empty means KeepProbeLabel.
+	Conflict LabelConflict `mapstructure:"conflict"`
+}
+
+// Validate reports labels the stores could not keep, labels the policy
+// protects, which only the API sets, and unknown conflict rules.
+func (d DefaultLabels) Validate(policy LabelPolicy) error {
+	switch d.Conflict {
+	case "", KeepProbeLabel, OverrideProbeLabel, RejectProbeLabel:
+	default:
+		return fmt.Errorf("invalid conflict %q, expected one of %s, %s, %s", d.Conflict, KeepProbeLabel, OverrideProbeLabel, RejectProbeLabel)
+	}
+	for _, key := range slices.Sorted(maps.Keys(d.Labels)) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, errs[0])
+		}
+		if errs := validation.IsValidLabelValue(d.Labels[key]); len(errs) > 0 {
+			return fmt.Errorf("invalid value %q for label %q: %s", d.Labels[key], key, errs[0])
+		}
+		if policy.IsProtected(key) {
+			return fmt.Errorf("label %q is managed by the system and cannot have a default", key)
+		}
+	}
+	return nil
+}
+
+// apply merges the default labels into a probe being created, resolving the
+// labels it sets to other values by the conflict rule.
+func (d DefaultLabels) apply(probe *v1.ProbeObject) error {
+	if len(d.Labels) == 0 {
+		return nil
+	}
+	probeLabels := v1.LabelsSchema{}
+	if probe.Labels != nil {
+		probeLabels = maps.Clone(*probe.Labels)
+	}
+	// Keys are visited in a stable order so the reported label is
+	// deterministic.
+	for _, key := range slices.Sorted(maps.Keys(d.Labels)) {
+		value, set := probeLabels[key]
+		switch {
+		case !set || value == d.Labels[key]:
+		case d.Conflict == RejectProbeLabel:
+			return fmt.Errorf("label %q must be %q on this server, got %q", key, d.Labels[key], value)
+		case d.Conflict != OverrideProbeLabel:
+			continue
+		}
+		probeLabels[key] = d.Labels[key]
+	}
+	probe.Labels = &probeLabels
+	return nil
+}
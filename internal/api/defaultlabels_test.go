@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultLabels(t *testing.T) {
+	defaults := map[string]string{"environment": "production", "region": "us-east"}
+	for _, tc := range []struct {
+		name     string
+		conflict LabelConflict
+		labels   *v1.LabelsSchema
+		want     v1.LabelsSchema
+		err      string
+	}{
+		{
+			name: "probes without labels get the defaults",
+			want: v1.LabelsSchema{"environment": "production", "region": "us-east"},
+		},
+		{
+			name:   "other labels are kept",
+			labels: &v1.LabelsSchema{"team": "sre", "region": "us-east"},
+			want:   v1.LabelsSchema{"environment": "production", "region": "us-east", "team": "sre"},
+		},
+		{
+			name:   "keep",
+			labels: &v1.LabelsSchema{"environment": "staging"},
+			want:   v1.LabelsSchema{"environment": "staging", "region": "us-east"},
+		},
+		{
+			name:     "override",
+			conflict: OverrideProbeLabel,
+			labels:   &v1.LabelsSchema{"environment": "staging"},
+			want:     v1.LabelsSchema{"environment": "production", "region": "us-east"},
+		},
+		{
+			name:     "reject",
+			conflict: RejectProbeLabel,
+			labels:   &v1.LabelsSchema{"environment": "staging"},
+			err:      `label "environment" must be "production" on this server, got "staging"`,
+		},
+		{
+			name:     "reject accepts the default value",
+			conflict: RejectProbeLabel,
+			labels:   &v1.LabelsSchema{"environment": "production"},
+			want:     v1.LabelsSchema{"environment": "production", "region": "us-east"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := probestore.NewMemoryProbeStore()
+			server := NewServer(store)
+			server.DefaultLabels = DefaultLabels{Labels: defaults, Conflict: tc.conflict}
+
+			res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+				StaticUrl: "https://example.com",
+				Labels:    tc.labels,
+			}})
+			require.NoError(t, err)
+			if tc.err != "" {
+				require.IsType(t, v1.CreateProbe400JSONResponse{}, res)
+				assert.Equal(t, tc.err, res.(v1.CreateProbe400JSONResponse).Error.Message)
+				return
+			}
+			created, ok := res.(v1.CreateProbe201JSONResponse)
+			require.True(t, ok, "got %T", res)
+			assert.Subset(t, *created.Labels, tc.want)
+		})
+	}
+}
+
+func TestDefaultLabels_Update(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	server.DefaultLabels = DefaultLabels{Labels: map[string]string{"environment": "production"}, Conflict: RejectProbeLabel}
+	ctx := context.Background()
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}})
+	require.NoError(t, err)
+	created := res.(v1.CreateProbe201JSONResponse)
+
+	updated, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: created.Id, Body: &v1.UpdateProbeJSONRequestBody{
+		Labels: &v1.LabelsSchema{"environment": "staging"},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.UpdateProbe200JSONResponse{}, updated)
+	assert.Equal(t, "staging", (*updated.(v1.UpdateProbe200JSONResponse).Body.Labels)["environment"], "defaults only apply to new probes")
+}
+
+func TestDefaultLabels_Validate(t *testing.T) {
+	policy := DefaultLabelPolicy().WithReservedPrefixes("example.com/")
+	for _, tc := range []struct {
+		name     string
+		defaults DefaultLabels
+		err      string
+	}{
+		{name: "none"},
+		{name: "valid", defaults: DefaultLabels{Labels: map[string]string{"environment": "production"}, Conflict: RejectProbeLabel}},
+		{name: "unknown conflict", defaults: DefaultLabels{Conflict: "merge"}, err: `invalid conflict "merge", expected one of keep, override, reject`},
+		{name: "invalid key", defaults: DefaultLabels{Labels: map[string]string{"bad key": "x"}}, err: `invalid label key "bad key"`},
+		{name: "invalid value", defaults: DefaultLabels{Labels: map[string]string{"region": "us east"}}, err: `invalid value "us east" for label "region"`},
+		{name: "protected label", defaults: DefaultLabels{Labels: map[string]string{"private": "true"}}, err: `label "private" is managed by the system and cannot have a default`},
+		{name: "reserved prefix", defaults: DefaultLabels{Labels: map[string]string{"example.com/owner": "sre"}}, err: `label "example.com/owner" is managed by the system and cannot have a default`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.defaults.Validate(policy)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// (GET /probes/diff)
+func (s Server) DiffProbes(ctx context.Context, request v1.DiffProbesRequestObject) (v1.DiffProbesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("diff_probes", time.Now())
+	params := request.Params
+
+	var matchLabel string
+	if params.MatchLabel != nil {
+		matchLabel = *params.MatchLabel
+		if errs := validation.IsQualifiedName(matchLabel); len(errs) > 0 {
+			return v1.DiffProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid match_label: %s", strings.Join(errs, "; "))}}, nil
+		}
+	}
+	// Labels the selectors or the pairing rely on differ by design.
+	ignored := map[string]bool{matchLabel: true}
+
+	var sides [2][]v1.ProbeObject
+	for i, side := range []struct {
+		name     string
+		selector string
+	}{
+		{name: "left_selector", selector: params.LeftSelector},
+		{name: "right_selector", selector: params.RightSelector},
+	} {
+		parsed, err := labels.Parse(side.selector)
+		if err != nil {
+			return v1.DiffProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid %s: %v", side.name, err)}}, nil
+		}
+		requirements, _ := parsed.Requirements()
+		for _, r := range requirements {
+			ignored[r.Key()] = true
+		}
+		selector, err := s.probeSelector(ctx, &side.selector)
+		if err != nil {
+			return v1.DiffProbes400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+		}
+		sides[i], err = s.Store.ListProbes(ctx, selector)
+		if err != nil {
+			metrics.RecordProbestoreError("diff_probes")
+			slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+			return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+		}
+	}
+
+	var keyed [2]map[string]v1.ProbeObject
+	for i, probes := range sides {
+		keyed[i] = make(map[string]v1.ProbeObject, len(probes))
+		for _, probe := range probes {
+			key, ok := diffKey(probe, matchLabel)
+			if !ok {
+				continue
+			}
+			if other, ok := keyed[i][key]; ok {
+				return v1.DiffProbes409JSONResponse{Error: v1.ErrorObject{
+					Message: fmt.Sprintf("probes %s and %s have the same match key %q, so they cannot be paired", other.Id, probe.Id, key),
+				}}, nil
+			}
+			keyed[i][key] = probe
+		}
+	}
+	left, right := keyed[0], keyed[1]
+
+	diff := v1.ProbeDiffResponse{Added: []v1.ProbeObject{}, Removed: []v1.ProbeObject{}, Changed: []v1.ProbeChange{}}
+	for _, key := range slices.Sorted(maps.Keys(left)) {
+		r, ok := right[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, left[key])
+			continue
+		}
+		fields := changedFields(left[key], r, ignored)
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, v1.ProbeChange{Key: key, Left: left[key], Right: r, Fields: fields})
+	}
+	for _, key := range slices.Sorted(maps.Keys(right)) {
+		if _, ok := left[key]; !ok {
+			diff.Added = append(diff.Added, right[key])
+		}
+	}
+	return v1.DiffProbes200JSONResponse(diff), nil
+}
+
+// diffKey returns the key the probe is paired by: the value of matchLabel,
+// or its URL when matchLabel is empty. Probes without the label are not
+// paired.
+func diffKey(probe v1.ProbeObject, matchLabel string) (string, bool) {
+	if matchLabel == "" {
+		return probe.StaticUrl, true
+	}
+	if probe.Labels == nil {
+		return "", false
+	}
+	value, ok := (*probe.Labels)[matchLabel]
+	return value, ok
+}
+
+// changedFields returns the settings that differ between two probes, then
+// the differing labels, each sorted. System-managed labels and the ignored
+// ones are not compared.
+func changedFields(left, right v1.ProbeObject, ignored map[string]bool) []string {
+	var fields []string
+	for _, f := range []struct {
+		name        string
+		left, right any
+	}{
+		{name: "additional_urls", left: additionalURLs(left), right: additionalURLs(right)},
+		{name: "alerting", left: left.Alerting, right: right.Alerting},
+		{name: "assertions", left: left.Assertions, right: right.Assertions},
+		{name: "auth", left: left.Auth, right: right.Auth},
+		{name: "dns", left: left.Dns, right: right.Dns},
+		{name: "icmp", left: left.Icmp, right: right.Icmp},
+		{name: "interval", left: left.Interval, right: right.Interval},
+		{name: "module", left: left.Module, right: right.Module},
+		{name: "owner", left: left.Owner, right: right.Owner},
+		{name: "paused", left: isPaused(left), right: isPaused(right)},
+		{name: "regions", left: sortedRegions(left), right: sortedRegions(right)},
+		{name: "static_url", left: left.StaticUrl, right: right.StaticUrl},
+		{name: "tcp", left: left.Tcp, right: right.Tcp},
+		{name: "timeout", left: left.Timeout, right: right.Timeout},
+	} {
+		if !reflect.DeepEqual(f.left, f.right) {
+			fields = append(fields, f.name)
+		}
+	}
+
+	compared := func(probe v1.ProbeObject) map[string]string {
+		out := map[string]string{}
+		if probe.Labels == nil {
+			return out
+		}
+		for key, value := range *probe.Labels {
+			if ignored[key] || strings.HasPrefix(key, reservedLabelPrefix) || key == probestore.LastReconciledLabel {
+				continue
+			}
+			out[key] = value
+		}
+		return out
+	}
+	leftLabels, rightLabels := compared(left), compared(right)
+	keys := slices.Collect(maps.Keys(leftLabels))
+	for key := range rightLabels {
+		if _, ok := leftLabels[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		lv, lok := leftLabels[key]
+		rv, rok := rightLabels[key]
+		if lv != rv || lok != rok {
+			fields = append(fields, "labels."+key)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffProbes(t *testing.T) {
+	interval, otherInterval := "1m", "30s"
+	probe := func(source, cluster string, extra v1.LabelsSchema) v1.ProbeObject {
+		labels := v1.LabelsSchema{
+			"app":                     "rhobs-synthetics-probe",
+			"source":                  source,
+			"cluster-id":              cluster,
+			"rhobs-synthetics/status": "active",
+			"last-reconciled":         uuid.NewString(),
+		}
+		for k, v := range extra {
+			labels[k] = v
+		}
+		return v1.ProbeObject{
+			Id:        uuid.New(),
+			StaticUrl: "https://api." + cluster + "." + source + ".example.com/livez",
+			Status:    v1.Active,
+			Interval:  &interval,
+			Labels:    &labels,
+		}
+	}
+
+	oldA := probe("v1", "a", v1.LabelsSchema{"team": "sre"})
+	newA := probe("v2", "a", v1.LabelsSchema{"team": "sre"})
+	oldB := probe("v1", "b", v1.LabelsSchema{"team": "sre"})
+	newB := probe("v2", "b", v1.LabelsSchema{"team": "obs"})
+	newB.Interval = &otherInterval
+	newB.AdditionalUrls = &v1.AdditionalUrlsSchema{"https://console.b.example.com"}
+	oldC := probe("v1", "c", nil)
+	newD := probe("v2", "d", nil)
+	unlabelled := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://x.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"app": "rhobs-synthetics-probe", "source": "v2"}}
+
+	// The selectors are applied by the store.
+	store := probestore.NewMemoryProbeStore()
+	for _, p := range []v1.ProbeObject{oldA, newA, oldB, newB, oldC, newD, unlabelled} {
+		_, err := store.CreateProbe(context.Background(), p, probeURLHash(p.StaticUrl))
+		require.NoError(t, err)
+	}
+	server := NewServer(store)
+	matchLabel := "cluster-id"
+
+	res, err := server.DiffProbes(context.Background(), v1.DiffProbesRequestObject{Params: v1.DiffProbesParams{
+		LeftSelector:  "source=v1",
+		RightSelector: "source=v2",
+		MatchLabel:    &matchLabel,
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.DiffProbes200JSONResponse{}, res)
+	diff := res.(v1.DiffProbes200JSONResponse)
+
+	require.Len(t, diff.Added, 1)
+	assert.Equal(t, newD.Id, diff.Added[0].Id)
+	require.Len(t, diff.Removed, 1)
+	assert.Equal(t, oldC.Id, diff.Removed[0].Id)
+	require.Len(t, diff.Changed, 2)
+	assert.Equal(t, "a", diff.Changed[0].Key)
+	assert.Equal(t, []string{"static_url"}, diff.Changed[0].Fields, "selector, system and heartbeat labels are not compared")
+	assert.Equal(t, "b", diff.Changed[1].Key)
+	assert.Equal(t, oldB.Id, diff.Changed[1].Left.Id)
+	assert.Equal(t, newB.Id, diff.Changed[1].Right.Id)
+	assert.Equal(t, []string{"additional_urls", "interval", "static_url", "labels.team"}, diff.Changed[1].Fields)
+	assert.Zero(t, diff.Unchanged)
+
+	t.Run("pairs by URL by default", func(t *testing.T) {
+		res, err := server.DiffProbes(context.Background(), v1.DiffProbesRequestObject{Params: v1.DiffProbesParams{
+			LeftSelector:  "cluster-id=a",
+			RightSelector: "team=sre",
+		}})
+		require.NoError(t, err)
+		diff := res.(v1.DiffProbes200JSONResponse)
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, oldB.Id, diff.Added[0].Id)
+		assert.Empty(t, diff.Removed)
+		assert.Empty(t, diff.Changed)
+		assert.Equal(t, 2, diff.Unchanged, "probes matched by both selectors are the same")
+
+		res, err = server.DiffProbes(context.Background(), v1.DiffProbesRequestObject{Params: v1.DiffProbesParams{
+			LeftSelector:  "source=v1",
+			RightSelector: "team=sre",
+		}})
+		require.NoError(t, err)
+		diff = res.(v1.DiffProbes200JSONResponse)
+		require.Len(t, diff.Added, 1)
+		assert.Equal(t, newA.Id, diff.Added[0].Id)
+		require.Len(t, diff.Removed, 1)
+		assert.Equal(t, oldC.Id, diff.Removed[0].Id)
+		assert.Equal(t, 2, diff.Unchanged)
+	})
+
+	t.Run("duplicate match keys", func(t *testing.T) {
+		team := "team"
+		res, err := server.DiffProbes(context.Background(), v1.DiffProbesRequestObject{Params: v1.DiffProbesParams{
+			LeftSelector:  "source=v1",
+			RightSelector: "source=v2",
+			MatchLabel:    &team,
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DiffProbes409JSONResponse{}, res)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		bad := "not a label"
+		for _, params := range []v1.DiffProbesParams{
+			{LeftSelector: "source=", RightSelector: "!!"},
+			{LeftSelector: "source=v1", RightSelector: "source=v2", MatchLabel: &bad},
+		} {
+			res, err := server.DiffProbes(context.Background(), v1.DiffProbesRequestObject{Params: params})
+			require.NoError(t, err)
+			assert.IsType(t, v1.DiffProbes400JSONResponse{}, res)
+		}
+	})
+
+	t.Run("storage errors", func(t *testing.T) {
+		server := NewServer(&faultyStore{ProbeStorage: newProbeStore(t), listErr: errors.New("boom")})
+		_, err := server.DiffProbes(context.Background(), v1.DiffProbesRequestObject{Params: v1.DiffProbesParams{
+			LeftSelector:  "source=v1",
+			RightSelector: "source=v2",
+		}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/events"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeLifecycleEvents(t *testing.T) {
+	ctx := context.Background()
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	server.Events = events.NewWithSink(events.Config{}, store, nil)
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}})
+	require.NoError(t, err)
+	probe := v1.ProbeObject(res.(v1.CreateProbe201JSONResponse))
+	_, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"team": "sre"}}})
+	require.NoError(t, err)
+	active := v1.Active
+	_, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Status: &active}})
+	require.NoError(t, err)
+	_, err = server.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: probe.Id})
+	require.NoError(t, err)
+	deleted := v1.Deleted
+	_, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Status: &deleted}})
+	require.NoError(t, err)
+
+	// Nothing relays the outbox, so every event is still in it, in order.
+	stored, err := store.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	var types []string
+	for _, s := range stored {
+		var event events.Event
+		require.NoError(t, json.Unmarshal(s.Event, &event))
+		assert.Equal(t, probe.Id.String(), event.Subject)
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{
+		"io.rhobs.synthetics.probe.created",
+		"io.rhobs.synthetics.probe.status_changed",
+		"io.rhobs.synthetics.probe.status_changed",
+		"io.rhobs.synthetics.probe.deleted",
+	}, types, "label changes are not published")
+}
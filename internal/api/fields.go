@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// probeFields are the JSON names of the top-level probe fields, which the
+// fields parameter of ListProbes may select.
+var probeFields = jsonFieldNames(reflect.TypeFor[v1.ProbeObject]())
+
+// bundledProbeFields are the JSON names of the fields of bundled probes,
+// which the fields parameter of ExportProbes may select.
+var bundledProbeFields = jsonFieldNames(reflect.TypeFor[v1.BundledProbe]())
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for field := range t.Fields() {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// parseFields parses a fields parameter naming some of the known fields. It
+// returns nil when every field should be returned.
+func parseFields(param *string, known []string) ([]string, error) {
+	if param == nil || strings.TrimSpace(*param) == "" {
+		return nil, nil
+	}
+	var fields []string
+	for name := range strings.SplitSeq(*param, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown probe field %q, expected one of %v", name, known)
+		}
+		if !slices.Contains(fields, name) {
+			fields = append(fields, name)
+		}
+	}
+	return fields, nil
+}
+
+// sparseProbesResponse is a ListProbes response whose probes only hold the
+// selected fields. The probes are trimmed as they are written, after every
+// other step of the request has seen them whole.
+type sparseProbesResponse struct {
+	v1.ProbesArrayResponse
+	fields []string
+	etag   string
+}
+
+func (response sparseProbesResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	probes := make([]map[string]json.RawMessage, 0, len(response.Probes))
+	for _, probe := range response.Probes {
+		trimmed, err := trimFields(probe, response.fields)
+		if err != nil {
+			return err
+		}
+		probes = append(probes, trimmed)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", response.etag)
+	w.WriteHeader(200)
+	return json.NewEncoder(w).Encode(struct {
+		Features      *v1.FeaturesSchema           `json:"features,omitempty"`
+		NextPageToken *string                      `json:"next_page_token,omitempty"`
+		Probes        []map[string]json.RawMessage `json:"probes"`
+		Version       *string                      `json:"version,omitempty"`
+	}{response.Features, response.NextPageToken, probes, response.Version})
+}
+
+// trimFields returns the JSON fields of v named in fields. Unset fields stay
+// absent.
+func trimFields(v any, fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	trimmed := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if value, ok := all[name]; ok {
+			trimmed[name] = value
+		}
+	}
+	return trimmed, nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	param := func(s string) *string { return &s }
+
+	testCases := []struct {
+		name     string
+		param    *string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "absent", param: nil},
+		{name: "empty", param: param(" ")},
+		{name: "fields in order", param: param("static_url, id,status"), expected: []string{"static_url", "id", "status"}},
+		{name: "duplicates", param: param("id,id"), expected: []string{"id"}},
+		{name: "unknown field", param: param("id,manager"), wantErr: true},
+		{name: "nested field", param: param("alerting.severity"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields, err := parseFields(tc.param, probeFields)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, fields)
+		})
+	}
+}
+
+func TestListProbes_Fields(t *testing.T) {
+	probeID := uuid.New()
+	interval := "30s"
+	store := newProbeStore(t, v1.ProbeObject{
+		Id:        probeID,
+		StaticUrl: "https://example.com",
+		Status:    v1.Active,
+		Interval:  &interval,
+		Labels:    &v1.LabelsSchema{"env": "prod"},
+	})
+	server := NewServer(store)
+	server.Features = v1.FeaturesSchema{"results": "v1"}
+
+	t.Run("trims probes", func(t *testing.T) {
+		fields := "id,static_url,timeout"
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{Fields: &fields}})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		require.NoError(t, res.VisitListProbesResponse(w))
+
+		assert.Equal(t, 200, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		version, err := probesVersion([]v1.ProbeObject{storedProbe(t, store, probeID)})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"features":{"results":"v1"},"probes":[{"id":"`+probeID.String()+`","static_url":"https://example.com"}],"version":"`+version+`"}`, w.Body.String(),
+			"unset fields stay absent")
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		fields := "id,secret"
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{Fields: &fields}})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListProbes400JSONResponse)
+		require.True(t, ok)
+		assert.Contains(t, resp.Error.Message, `invalid fields: unknown probe field "secret"`)
+	})
+
+	t.Run("returns whole probes without fields", func(t *testing.T) {
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListProbes200JSONResponse)
+		require.True(t, ok)
+		assert.Equal(t, &interval, resp.Body.Probes[0].Interval)
+	})
+
+	t.Run("sparse and full listings have different ETags", func(t *testing.T) {
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{})
+		require.NoError(t, err)
+		full, ok := res.(v1.ListProbes200JSONResponse)
+		require.True(t, ok)
+
+		fields := "id,static_url"
+		res, err = server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{Fields: &fields, IfNoneMatch: &full.Headers.ETag}})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		require.NoError(t, res.VisitListProbesResponse(w))
+		assert.Equal(t, 200, w.Code, "the ETag of the full listing must not revalidate a sparse one")
+		sparseTag := w.Header().Get("ETag")
+		assert.NotEqual(t, full.Headers.ETag, sparseTag)
+
+		res, err = server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{Fields: &fields, IfNoneMatch: &sparseTag}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbes304Response{}, res)
+	})
+}
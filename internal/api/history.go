@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// withStatusChange returns a copy of ctx under which the store records the
+// status changes it makes in the probes' history as made by the caller, for
+// reason: by the agent whose credential the request carries, or else by the
+// actor of the audit log.
+func (s Server) withStatusChange(ctx context.Context, reason string) context.Context {
+	actor := agentauth.AgentFromContext(ctx)
+	if actor == "" {
+		actor = s.Audit.Actor(ctx)
+	}
+	return probestore.WithStatusChange(ctx, actor, reason)
+}
+
+// (GET /probes/{probe_id}/history)
+func (s Server) GetProbeHistory(ctx context.Context, request v1.GetProbeHistoryRequestObject) (v1.GetProbeHistoryResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("get_probe_history", time.Now())
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+
+	probe, err := s.getProbe(ctx, request.ProbeId)
+	if err != nil {
+		metrics.RecordProbestoreError("get_probe_history")
+		if k8serrors.IsNotFound(err) {
+			return v1.GetProbeHistory404JSONResponse{
+				Warning: v1.WarningObject{
+					Message: fmt.Sprintf("probe with ID %s not found", request.ProbeId),
+				},
+			}, nil
+		}
+		slog.ErrorContext(ctx, "Error getting probe from storage", "error", err)
+		return nil, fmt.Errorf("failed to get probe from storage: %w", err)
+	}
+
+	history := []v1.StatusTransition{}
+	if probe.StatusHistory != nil {
+		history = *probe.StatusHistory
+	}
+	return v1.GetProbeHistory200JSONResponse{History: history}, nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProbeHistory(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	issuer, err := agentauth.NewIssuer([]byte(strings.Repeat("k", agentauth.MinKeyLength)), time.Hour, 0)
+	require.NoError(t, err)
+	server := NewServer(store)
+	server.AgentAuth = issuer
+
+	bootstrap, err := issuer.MintBootstrap("agent-1", 0)
+	require.NoError(t, err)
+	cred, err := issuer.Exchange(context.Background(), bootstrap.Token, "agent-1")
+	require.NoError(t, err)
+	agentCtx := bearerContext(issuer, cred.Credential)
+	operator := audit.WithActor(context.Background(), "alice")
+
+	res, err := server.CreateProbe(operator, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	probeID := res.(v1.CreateProbe201JSONResponse).Id
+
+	history := func() []v1.StatusTransition {
+		t.Helper()
+		res, err := server.GetProbeHistory(context.Background(), v1.GetProbeHistoryRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		require.IsType(t, v1.GetProbeHistory200JSONResponse{}, res)
+		return res.(v1.GetProbeHistory200JSONResponse).History
+	}
+	assert.Empty(t, history())
+
+	for _, status := range []v1.StatusSchema{v1.Active, v1.Failed, v1.Active} {
+		update, err := server.UpdateProbe(agentCtx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{Status: &status}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, update)
+	}
+	del, err := server.DeleteProbe(operator, v1.DeleteProbeRequestObject{ProbeId: probeID})
+	require.NoError(t, err)
+	require.IsType(t, v1.DeleteProbe204Response{}, del)
+
+	got := history()
+	require.Len(t, got, 4)
+	assert.Equal(t, v1.Pending, got[0].From)
+	assert.Equal(t, v1.Failed, got[1].To)
+	for _, transition := range got[:3] {
+		assert.Equal(t, "agent-1", *transition.Actor, "agents are named by their credential")
+	}
+	assert.Equal(t, v1.Terminating, got[3].To)
+	assert.Equal(t, "alice", *got[3].Actor)
+	assert.NotNil(t, got[3].Reason)
+
+	res404, err := server.GetProbeHistory(context.Background(), v1.GetProbeHistoryRequestObject{ProbeId: uuid.New()})
+	require.NoError(t, err)
+	assert.IsType(t, v1.GetProbeHistory404JSONResponse{}, res404)
+}
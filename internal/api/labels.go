@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// reservedLabelPrefix is the label namespace owned by the synthetics API.
+const reservedLabelPrefix = "rhobs-synthetics/"
+
+// LabelPolicy describes the system-managed labels that API clients may not
+// set or change. A label is protected if it is listed in ProtectedLabels or
+// starts with one of ReservedPrefixes.
+type LabelPolicy struct {
+	ProtectedLabels  []string
+	ReservedPrefixes []string
+}
+
+// DefaultLabelPolicy returns the policy for the labels managed by the API
+// itself.
+func DefaultLabelPolicy() LabelPolicy {
+	return LabelPolicy{
+		ProtectedLabels: []string{
+			baseAppLabelKey,
+			probeStatusLabelKey,
+			probeURLHashLabelKey,
+			privateProbeLabelKey,
+		},
+		ReservedPrefixes: []string{reservedLabelPrefix},
+	}
+}
+
+// WithReservedPrefixes returns a copy of the policy that additionally reserves
+// the given label prefixes, e.g. operator-defined namespaces such as
+// "example.com/". Empty and duplicate prefixes are ignored.
+func (p LabelPolicy) WithReservedPrefixes(prefixes ...string) LabelPolicy {
+	out := LabelPolicy{
+		ProtectedLabels:  slices.Clone(p.ProtectedLabels),
+		ReservedPrefixes: slices.Clone(p.ReservedPrefixes),
+	}
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" || slices.Contains(out.ReservedPrefixes, prefix) {
+			continue
+		}
+		out.ReservedPrefixes = append(out.ReservedPrefixes, prefix)
+	}
+	return out
+}
+
+// LabelPolicy returns the label policy requests are checked against.
+func (s Server) LabelPolicy() LabelPolicy {
+	return *s.labelPolicy.Load()
+}
+
+// SetLabelPolicy replaces the label policy, including for requests being
+// served by a running server.
+func (s Server) SetLabelPolicy(p LabelPolicy) {
+	s.labelPolicy.Store(&p)
+}
+
+// IsProtected reports whether the label key is managed by the system.
+func (p LabelPolicy) IsProtected(key string) bool {
+	if slices.Contains(p.ProtectedLabels, key) {
+		return true
+	}
+	for _, prefix := range p.ReservedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks if the user is trying to set or modify protected system
+// labels. On create, old is empty, so any protected label is rejected.
+func (p LabelPolicy) validate(new, old v1.LabelsSchema) error {
+	// Check keys in a stable order so the reported label is deterministic.
+	for _, key := range slices.Sorted(maps.Keys(new)) {
+		if !p.IsProtected(key) {
+			continue
+		}
+		oldValue, oldExists := old[key]
+
+		// Disallow users from setting previously unset system-managed labels
+		if !oldExists {
+			return fmt.Errorf("creation of system-managed label '%s' is forbidden", key)
+		}
+
+		// Disallow users from changing the value of existing system-managed labels
+		if new[key] != oldValue {
+			return fmt.Errorf("modification of system-managed label '%s' is forbidden", key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+const (
+	// DefaultWaitTimeout is how long a ListProbes request with
+	// wait_for_change blocks when it sets no timeout.
+	DefaultWaitTimeout = 30 * time.Second
+	// MaxWaitTimeout is the longest timeout such a request may set.
+	MaxWaitTimeout = 2 * time.Minute
+	// waitRecheckInterval is how often a waiting request lists the probes
+	// again even though the store reported no change, which catches changes
+	// made by other replicas to stores that do not watch for them.
+	waitRecheckInterval = 5 * time.Second
+	// waitDeadlineMargin is the time a waiting request leaves itself before
+	// the deadline of its context, such as the timeout of the caller's
+	// tenant, to list the probes and write the response.
+	waitDeadlineMargin = time.Second
+)
+
+// waitTimeout returns how long a ListProbes request waits for a change, or
+// zero if it does not.
+func waitTimeout(params v1.ListProbesParams) (time.Duration, error) {
+	if params.WaitForChange == nil || !*params.WaitForChange {
+		return 0, nil
+	}
+	if params.Timeout == nil || *params.Timeout == "" {
+		return DefaultWaitTimeout, nil
+	}
+	timeout, err := time.ParseDuration(*params.Timeout)
+	if err != nil || timeout <= 0 || timeout > MaxWaitTimeout {
+		return 0, fmt.Errorf("invalid timeout %q: expected a duration such as 30s, greater than zero and at most %s", *params.Timeout, MaxWaitTimeout)
+	}
+	return timeout, nil
+}
+
+// probesVersion fingerprints the probes of a ListProbes response.
+func probesVersion(probes []v1.ProbeObject) (string, error) {
+	data, err := json.Marshal(probes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// waitForChange lists the probes until their version differs from version,
+// or from that of the first listing if version is empty, and returns the
+// last listing with its version. It lists again whenever the store reports a
+// change, and every waitRecheckInterval. When timeout expires, the request
+// is cancelled or the server shuts down first, it returns the unchanged
+// probes. The timeout is cut short to end waitDeadlineMargin before the
+// deadline of ctx, so that the unchanged probes are returned rather than the
+// request timing out.
+func (s Server) waitForChange(ctx context.Context, version string, timeout time.Duration, list func() ([]v1.ProbeObject, *string, error)) ([]v1.ProbeObject, *string, string, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, max(time.Until(deadline)-waitDeadlineMargin, 0))
+	}
+	notifier, _ := probestore.Implements[probestore.ChangeNotifier](s.Store)
+	expired := time.NewTimer(timeout)
+	defer expired.Stop()
+	recheck := time.NewTicker(waitRecheckInterval)
+	defer recheck.Stop()
+
+	for {
+		// The channel is taken before listing, so a change made while the
+		// probes are listed is not missed.
+		var changed <-chan struct{}
+		if notifier != nil {
+			changed = notifier.ProbesChanged()
+		}
+		probes, nextPageToken, err := list()
+		if err != nil {
+			return nil, nil, "", err
+		}
+		current, err := probesVersion(probes)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if version == "" {
+			version = current
+		}
+		if current != version {
+			return probes, nextPageToken, current, nil
+		}
+
+		select {
+		case <-changed:
+		case <-recheck.C:
+		case <-expired.C:
+			return probes, nextPageToken, current, nil
+		case <-ctx.Done():
+			return probes, nextPageToken, current, nil
+		case <-s.WaitDone:
+			return probes, nextPageToken, current, nil
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProbesWaitForChange(t *testing.T) {
+	ctx := context.Background()
+	local := probestore.NewMemoryProbeStore()
+	server := NewServer(probestore.NewIndexedProbeStore(local))
+
+	list := func(ctx context.Context, params v1.ListProbesParams) v1.ListProbesResponseObject {
+		t.Helper()
+		res, err := server.ListProbes(ctx, v1.ListProbesRequestObject{Params: params})
+		require.NoError(t, err)
+		return res
+	}
+	wait := true
+	res := list(ctx, v1.ListProbesParams{})
+	require.IsType(t, v1.ListProbes200JSONResponse{}, res)
+	version := res.(v1.ListProbes200JSONResponse).Body.Version
+	require.NotNil(t, version)
+
+	t.Run("returns the unchanged probes on timeout", func(t *testing.T) {
+		timeout := "50ms"
+		start := time.Now()
+		res := list(ctx, v1.ListProbesParams{WaitForChange: &wait, Timeout: &timeout})
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		require.IsType(t, v1.ListProbes200JSONResponse{}, res)
+		assert.Equal(t, version, res.(v1.ListProbes200JSONResponse).Body.Version)
+	})
+
+	t.Run("returns once the probes change", func(t *testing.T) {
+		timeout := "1m"
+		done := make(chan v1.ListProbesResponseObject)
+		go func() {
+			res, _ := server.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{WaitForChange: &wait, Timeout: &timeout, Version: version}})
+			done <- res
+		}()
+		res := list(ctx, v1.ListProbesParams{})
+		assert.Equal(t, version, res.(v1.ListProbes200JSONResponse).Body.Version)
+		created, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}})
+		require.NoError(t, err)
+		require.IsType(t, v1.CreateProbe201JSONResponse{}, created)
+
+		select {
+		case res := <-done:
+			require.IsType(t, v1.ListProbes200JSONResponse{}, res)
+			changed := res.(v1.ListProbes200JSONResponse)
+			assert.Len(t, changed.Body.Probes, 1)
+			assert.NotEqual(t, version, changed.Body.Version)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the request kept waiting after the probes changed")
+		}
+	})
+
+	t.Run("returns at once given an outdated version", func(t *testing.T) {
+		timeout := "1m"
+		res := list(ctx, v1.ListProbesParams{WaitForChange: &wait, Timeout: &timeout, Version: version})
+		require.IsType(t, v1.ListProbes200JSONResponse{}, res)
+		assert.Len(t, res.(v1.ListProbes200JSONResponse).Body.Probes, 1)
+	})
+
+	t.Run("returns when the server shuts down", func(t *testing.T) {
+		waitDone := make(chan struct{})
+		close(waitDone)
+		stopping := server
+		stopping.WaitDone = waitDone
+		timeout := "1m"
+		res, err := stopping.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{WaitForChange: &wait, Timeout: &timeout}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbes200JSONResponse{}, res)
+	})
+
+	t.Run("returns the unchanged probes before the request's deadline", func(t *testing.T) {
+		// As when the caller's tenant has a shorter timeout than the wait.
+		deadlineCtx, cancel := context.WithTimeout(ctx, waitDeadlineMargin+100*time.Millisecond)
+		defer cancel()
+		timeout := "1m"
+		res := list(deadlineCtx, v1.ListProbesParams{WaitForChange: &wait, Timeout: &timeout})
+		assert.NoError(t, deadlineCtx.Err(), "the wait ran until the deadline")
+		assert.IsType(t, v1.ListProbes200JSONResponse{}, res)
+	})
+
+	t.Run("rejects invalid timeouts", func(t *testing.T) {
+		for _, timeout := range []string{"soon", "0s", "1h"} {
+			assert.IsType(t, v1.ListProbes400JSONResponse{}, list(ctx, v1.ListProbesParams{WaitForChange: &wait, Timeout: &timeout}), timeout)
+		}
+	})
+}
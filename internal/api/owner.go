@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// ownerFields are the fields of a probe's owner RequiredOwner can require.
+var ownerFields = []string{"team", "escalation_contact"}
+
+// validateOwner checks the owner of a probe, if it has one.
+func validateOwner(owner *v1.OwnerSchema) error {
+	if owner == nil {
+		return nil
+	}
+	return owner.Validate()
+}
+
+// RequiredOwner returns a validator that rejects new probes whose owner does
+// not set each of fields, team or escalation_contact, so that alerts raised
+// by every probe can be routed.
+func RequiredOwner(fields []string) (Validator, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("fields must list at least one owner field")
+	}
+	for _, field := range fields {
+		if !slices.Contains(ownerFields, field) {
+			return nil, fmt.Errorf("unknown owner field %q, expected one of team, escalation_contact", field)
+		}
+	}
+	fields = slices.Clone(fields)
+	return ValidatorFunc("required_owner", func(_ context.Context, review ProbeReview) error {
+		if !review.IsCreate() {
+			return nil
+		}
+		owner := v1.OwnerSchema{}
+		if review.Probe.Owner != nil {
+			owner = *review.Probe.Owner
+		}
+		for _, field := range fields {
+			value := owner.Team
+			if field == "escalation_contact" {
+				value = owner.EscalationContact
+			}
+			if value == nil || *value == "" {
+				return inField("owner."+field, fmt.Errorf("owner.%s is required", field))
+			}
+		}
+		return nil
+	}), nil
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/events"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeOwner(t *testing.T) {
+	team, contact := "sre", "sre-oncall@example.com"
+	store := newProbeStore(t)
+	server := NewServer(store)
+
+	res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://example.com",
+		Owner:     &v1.OwnerSchema{Team: &team, EscalationContact: &contact},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	probeID := res.(v1.CreateProbe201JSONResponse).Id
+	assert.Equal(t, &v1.OwnerSchema{Team: &team, EscalationContact: &contact}, storedProbe(t, store, probeID).Owner)
+
+	t.Run("update replaces the owner", func(t *testing.T) {
+		res, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Owner: &v1.OwnerSchema{Team: &team},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		assert.Equal(t, &v1.OwnerSchema{Team: &team}, storedProbe(t, store, probeID).Owner)
+	})
+
+	t.Run("invalid team is rejected", func(t *testing.T) {
+		badTeam := "site reliability"
+		res, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Owner: &v1.OwnerSchema{Team: &badTeam},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, v1.UpdateProbe400JSONResponse{Error: v1.ErrorObject{Message: `invalid owner.team "site reliability", expected a name of letters, digits, '-', '_' and '.' of at most 63 characters`}}, res)
+		assert.Equal(t, &v1.OwnerSchema{Team: &team}, storedProbe(t, store, probeID).Owner)
+	})
+
+	t.Run("an empty owner removes it", func(t *testing.T) {
+		res, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &v1.UpdateProbeJSONRequestBody{
+			Owner: &v1.OwnerSchema{},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		assert.Nil(t, storedProbe(t, store, probeID).Owner)
+	})
+}
+
+func TestRequiredOwner(t *testing.T) {
+	ctx := context.Background()
+	v, err := RequiredOwner([]string{"team", "escalation_contact"})
+	require.NoError(t, err)
+
+	team, contact := "sre", "#sre-oncall"
+	assert.EqualError(t, v.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{}}), "owner.team is required")
+	assert.EqualError(t, v.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{Owner: &v1.OwnerSchema{Team: &team}}}), "owner.escalation_contact is required")
+	assert.NoError(t, v.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{Owner: &v1.OwnerSchema{Team: &team, EscalationContact: &contact}}}))
+	existing := v1.ProbeObject{Id: uuid.New()}
+	assert.NoError(t, v.Validate(ctx, ProbeReview{Probe: existing, Existing: &existing}), "probes created before the requirement can be updated")
+
+	_, err = RequiredOwner(nil)
+	assert.EqualError(t, err, "fields must list at least one owner field")
+	_, err = RequiredOwner([]string{"manager"})
+	assert.EqualError(t, err, `unknown owner field "manager", expected one of team, escalation_contact`)
+}
+
+func TestProbeOwner_ListAndEvents(t *testing.T) {
+	ctx := context.Background()
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	server.Events = events.NewWithSink(events.Config{}, store, nil)
+	required, err := RequiredOwner([]string{"team"})
+	require.NoError(t, err)
+	server.Validators = Validators{required}
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://unowned.example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, v1.CreateProbe400JSONResponse{Error: v1.ErrorObject{Message: "owner.team is required"}}, res)
+
+	var ids []string
+	for _, team := range []string{"sre", "payments"} {
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			StaticUrl: "https://" + team + ".example.com",
+			Owner:     &v1.OwnerSchema{Team: new(team)},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+		ids = append(ids, res.(v1.CreateProbe201JSONResponse).Id.String())
+	}
+
+	selector := "owner.team=sre"
+	listRes, err := server.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{FieldSelector: &selector}})
+	require.NoError(t, err)
+	list, ok := listRes.(v1.ListProbes200JSONResponse)
+	require.True(t, ok, "got %T", listRes)
+	require.Len(t, list.Body.Probes, 1)
+	assert.Equal(t, "https://sre.example.com", list.Body.Probes[0].StaticUrl)
+
+	// Events carry the probe, so consumers can route on its owner.
+	stored, err := store.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	var event events.Event
+	require.NoError(t, json.Unmarshal(stored[0].Event, &event))
+	assert.Equal(t, ids[0], event.Subject)
+	var data struct {
+		Probe v1.ProbeObject `json:"probe"`
+	}
+	require.NoError(t, json.Unmarshal(event.Data, &data))
+	require.NotNil(t, data.Probe.Owner)
+	assert.Equal(t, "sre", *data.Probe.Owner.Team)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// (POST /probes/{probe_id}/pause)
+func (s Server) PauseProbe(ctx context.Context, request v1.PauseProbeRequestObject) (v1.PauseProbeResponseObject, error) {
+	res, err := s.setPaused(ctx, request.ProbeId, request.Params.IfMatch, true)
+	if err != nil {
+		return nil, err
+	}
+	switch r := res.(type) {
+	case v1.UpdateProbe200JSONResponse:
+		return v1.PauseProbe200JSONResponse{Body: r.Body, Headers: v1.PauseProbe200ResponseHeaders(r.Headers)}, nil
+	case v1.UpdateProbe400JSONResponse:
+		return v1.PauseProbe400JSONResponse(r), nil
+	case v1.UpdateProbe404JSONResponse:
+		return v1.PauseProbe404JSONResponse(r), nil
+	case v1.UpdateProbe409JSONResponse:
+		return v1.PauseProbe409JSONResponse(r), nil
+	case v1.UpdateProbe412JSONResponse:
+		return v1.PauseProbe412JSONResponse(r), nil
+	}
+	return nil, fmt.Errorf("failed to pause probe %s: unexpected response %T", request.ProbeId, res)
+}
+
+// (POST /probes/{probe_id}/resume)
+func (s Server) ResumeProbe(ctx context.Context, request v1.ResumeProbeRequestObject) (v1.ResumeProbeResponseObject, error) {
+	res, err := s.setPaused(ctx, request.ProbeId, request.Params.IfMatch, false)
+	if err != nil {
+		return nil, err
+	}
+	switch r := res.(type) {
+	case v1.UpdateProbe200JSONResponse:
+		return v1.ResumeProbe200JSONResponse{Body: r.Body, Headers: v1.ResumeProbe200ResponseHeaders(r.Headers)}, nil
+	case v1.UpdateProbe400JSONResponse:
+		return v1.ResumeProbe400JSONResponse(r), nil
+	case v1.UpdateProbe404JSONResponse:
+		return v1.ResumeProbe404JSONResponse(r), nil
+	case v1.UpdateProbe409JSONResponse:
+		return v1.ResumeProbe409JSONResponse(r), nil
+	case v1.UpdateProbe412JSONResponse:
+		return v1.ResumeProbe412JSONResponse(r), nil
+	}
+	return nil, fmt.Errorf("failed to resume probe %s: unexpected response %T", request.ProbeId, res)
+}
+
+// setPaused pauses or resumes a probe as a PATCH setting only paused would,
+// so it is checked, audited and stored the same way.
+func (s Server) setPaused(ctx context.Context, probeID v1.ProbeIdPathParam, ifMatch *v1.IfMatchHeaderParam, paused bool) (v1.UpdateProbeResponseObject, error) {
+	return s.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+		ProbeId: probeID,
+		Params:  v1.UpdateProbeParams{IfMatch: ifMatch},
+		Body:    &v1.UpdateProbeJSONRequestBody{Paused: &paused},
+	})
+}
+
+// setPausedField sets whether the probe is paused. Probes that are not
+// paused leave the field out, so every store returns them alike.
+func setPausedField(probe *v1.ProbeObject, paused bool) {
+	probe.Paused = nil
+	if paused {
+		probe.Paused = &paused
+	}
+}
+
+// isPaused reports whether the probe is paused.
+func isPaused(probe v1.ProbeObject) bool {
+	return probe.Paused != nil && *probe.Paused
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseProbe(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	create := func(url string, status v1.StatusSchema) *v1.ProbeObject {
+		t.Helper()
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: url, Status: status}, probeURLHash(url))
+		require.NoError(t, err)
+		return probe
+	}
+	probe := create("https://example.com", v1.Active)
+
+	res, err := server.PauseProbe(ctx, v1.PauseProbeRequestObject{ProbeId: probe.Id})
+	require.NoError(t, err)
+	paused, ok := res.(v1.PauseProbe200JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.True(t, isPaused(paused.Body))
+	assert.Equal(t, v1.Active, paused.Body.Status, "the probe keeps its status")
+	assert.Equal(t, int64(2), *paused.Body.Generation)
+	assert.Equal(t, etag(paused.Body), paused.Headers.ETag)
+	assert.Len(t, server.Audit.List(audit.Filter{ProbeID: probe.Id, Operation: v1.UpdateProbe}), 1)
+
+	t.Run("pausing a paused probe changes nothing", func(t *testing.T) {
+		res, err := server.PauseProbe(ctx, v1.PauseProbeRequestObject{ProbeId: probe.Id})
+		require.NoError(t, err)
+		again, ok := res.(v1.PauseProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, *paused.Body.Generation, *again.Body.Generation)
+	})
+
+	t.Run("stale If-Match", func(t *testing.T) {
+		stale := `"0"`
+		res, err := server.ResumeProbe(ctx, v1.ResumeProbeRequestObject{ProbeId: probe.Id, Params: v1.ResumeProbeParams{IfMatch: &stale}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ResumeProbe412JSONResponse{}, res)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		res, err := server.ResumeProbe(ctx, v1.ResumeProbeRequestObject{ProbeId: probe.Id, Params: v1.ResumeProbeParams{IfMatch: &paused.Headers.ETag}})
+		require.NoError(t, err)
+		resumed, ok := res.(v1.ResumeProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, resumed.Body.Paused)
+		assert.Equal(t, int64(3), *resumed.Body.Generation)
+	})
+
+	t.Run("PATCH sets paused", func(t *testing.T) {
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Paused: new(true)}})
+		require.NoError(t, err)
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.True(t, isPaused(updated.Body))
+
+		res, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Paused: new(false)}})
+		require.NoError(t, err)
+		updated, ok = res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, updated.Body.Paused, "probes that are not paused leave the field out")
+	})
+
+	t.Run("terminating probes cannot be paused", func(t *testing.T) {
+		terminating := create("https://terminating.example.com", v1.Terminating)
+		res, err := server.PauseProbe(ctx, v1.PauseProbeRequestObject{ProbeId: terminating.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.PauseProbe409JSONResponse{}, res)
+
+		res2, err := server.ResumeProbe(ctx, v1.ResumeProbeRequestObject{ProbeId: terminating.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ResumeProbe200JSONResponse{}, res2)
+	})
+
+	t.Run("unknown probe", func(t *testing.T) {
+		res, err := server.PauseProbe(ctx, v1.PauseProbeRequestObject{ProbeId: uuid.New()})
+		require.NoError(t, err)
+		assert.IsType(t, v1.PauseProbe404JSONResponse{}, res)
+	})
+}
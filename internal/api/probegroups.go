@@ -0,0 +1,322 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// groupLabelKey records the probe group a probe was created in. It falls
+// under the reserved prefix, so clients cannot set or change it.
+const groupLabelKey = reservedLabelPrefix + "group"
+
+// maxGroupProbes caps the probes created with a group, as the probes are
+// created one by one within the request.
+const maxGroupProbes = 100
+
+type probeGroupKey struct{}
+
+// withProbeGroup returns a copy of ctx under which CreateProbe labels the
+// probe with the group. The label is added after the protected labels are
+// checked, as the tenant label is.
+func withProbeGroup(ctx context.Context, groupID string) context.Context {
+	return context.WithValue(ctx, probeGroupKey{}, groupID)
+}
+
+// probeGroupFromContext returns the group set by withProbeGroup, or "".
+func probeGroupFromContext(ctx context.Context) string {
+	groupID, _ := ctx.Value(probeGroupKey{}).(string)
+	return groupID
+}
+
+// (GET /probe-groups)
+func (s Server) ListProbeGroups(ctx context.Context, request v1.ListProbeGroupsRequestObject) (v1.ListProbeGroupsResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("list_probe_groups", time.Now())
+
+	probes, err := s.groupProbes(ctx, groupLabelKey)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probe_groups")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	members := map[string][]v1.ProbeObject{}
+	for _, probe := range probes {
+		groupID := (*probe.Labels)[groupLabelKey]
+		members[groupID] = append(members[groupID], probe)
+	}
+	groups := make([]v1.ProbeGroupObject, 0, len(members))
+	for _, groupID := range slices.Sorted(maps.Keys(members)) {
+		groups = append(groups, probeGroupObject(groupID, members[groupID], false))
+	}
+	return v1.ListProbeGroups200JSONResponse{Groups: groups}, nil
+}
+
+// (POST /probe-groups)
+func (s Server) CreateProbeGroup(ctx context.Context, request v1.CreateProbeGroupRequestObject) (v1.CreateProbeGroupResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("create_probe_group", time.Now())
+	groupID := request.Body.Id
+	ctx = logging.With(ctx, "group_id", groupID)
+
+	if errs := validation.IsValidLabelValue(groupID); groupID == "" || len(errs) > 0 {
+		msg := "id is required"
+		if len(errs) > 0 {
+			msg = fmt.Sprintf("invalid id %q: %s", groupID, errs[0])
+		}
+		return v1.CreateProbeGroup400JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+	}
+	if n := len(request.Body.Probes); n == 0 || n > maxGroupProbes {
+		return v1.CreateProbeGroup400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("a probe group must have 1 to %d probes, got %d", maxGroupProbes, n),
+			},
+		}, nil
+	}
+	if request.Body.Labels != nil {
+		if err := s.LabelPolicy().validate(*request.Body.Labels, nil); err != nil {
+			return v1.CreateProbeGroup403JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+		}
+	}
+
+	// Group IDs are unique across tenants: untenanted callers see the
+	// probes of every tenant, and would otherwise see two groups as one.
+	existing, err := s.Store.ListProbes(ctx, fmt.Sprintf("%s=%s,%s=%s", baseAppLabelKey, baseAppLabelValue, groupLabelKey, groupID))
+	if err != nil {
+		metrics.RecordProbestoreError("create_probe_group")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	if len(existing) > 0 {
+		return v1.CreateProbeGroup409JSONResponse{
+			Error: v1.ErrorObject{Message: fmt.Sprintf("probe group %s already exists", groupID)},
+		}, nil
+	}
+
+	created := make([]v1.ProbeObject, 0, len(request.Body.Probes))
+	for i, body := range request.Body.Probes {
+		if request.Body.Labels != nil {
+			probeLabels := maps.Clone(*request.Body.Labels)
+			if body.Labels != nil {
+				maps.Copy(probeLabels, *body.Labels)
+			}
+			body.Labels = &probeLabels
+		}
+		res, err := s.CreateProbe(withProbeGroup(ctx, groupID), v1.CreateProbeRequestObject{Body: &body})
+		if r, ok := res.(v1.CreateProbe201JSONResponse); ok && err == nil {
+			created = append(created, v1.ProbeObject(r))
+			continue
+		}
+
+		// The group is created as a whole or not at all.
+		s.removeGroupProbes(ctx, created)
+		switch r := res.(type) {
+		case v1.CreateProbe400JSONResponse:
+			return v1.CreateProbeGroup400JSONResponse{Error: groupProbeError(i, r.Error)}, nil
+		case v1.CreateProbe403JSONResponse:
+			return v1.CreateProbeGroup403JSONResponse{Error: groupProbeError(i, r.Error)}, nil
+		case v1.CreateProbe409JSONResponse:
+			return v1.CreateProbeGroup409JSONResponse{Error: groupProbeError(i, r.Error)}, nil
+		case v1.CreateProbe422JSONResponse:
+			return v1.CreateProbeGroup400JSONResponse{Error: groupProbeError(i, r.Error)}, nil
+		case v1.CreateProbe500JSONResponse:
+			err = fmt.Errorf("%s", r.Error.Message)
+		}
+		metrics.RecordProbestoreError("create_probe_group")
+		slog.ErrorContext(ctx, "Error creating probe of group", "index", i, "error", err)
+		return nil, fmt.Errorf("failed to create probes[%d]: %w", i, err)
+	}
+
+	slog.InfoContext(ctx, "Created probe group", "probes", len(created))
+	return v1.CreateProbeGroup201JSONResponse(probeGroupObject(groupID, created, true)), nil
+}
+
+// (GET /probe-groups/{group_id})
+func (s Server) GetProbeGroup(ctx context.Context, request v1.GetProbeGroupRequestObject) (v1.GetProbeGroupResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("get_probe_group", time.Now())
+
+	probes, err := s.groupProbes(ctx, groupLabelKey+"="+request.GroupId)
+	if err != nil {
+		metrics.RecordProbestoreError("get_probe_group")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "group_id", request.GroupId, "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	if len(probes) == 0 {
+		return v1.GetProbeGroup404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("probe group %s not found", request.GroupId),
+			},
+		}, nil
+	}
+	return v1.GetProbeGroup200JSONResponse(probeGroupObject(request.GroupId, probes, true)), nil
+}
+
+// (PATCH /probe-groups/{group_id})
+func (s Server) UpdateProbeGroup(ctx context.Context, request v1.UpdateProbeGroupRequestObject) (v1.UpdateProbeGroupResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("update_probe_group", time.Now())
+	ctx = logging.With(ctx, "group_id", request.GroupId)
+
+	probes, err := s.groupProbes(ctx, groupLabelKey+"="+request.GroupId)
+	if err != nil {
+		metrics.RecordProbestoreError("update_probe_group")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	if len(probes) == 0 {
+		return v1.UpdateProbeGroup404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("probe group %s not found", request.GroupId),
+			},
+		}, nil
+	}
+
+	// Probes are written one by one, each only if unchanged since the
+	// listing. A failed request may leave some probes changed; repeating it
+	// changes the rest.
+	for i, probe := range probes {
+		if probe.Status == v1.Terminating || isPaused(probe) == request.Body.Paused {
+			continue
+		}
+		before := snapshot(probe)
+		setPausedField(&probe, request.Body.Paused)
+
+		writeCtx := ctx
+		if probe.ResourceVersion != nil {
+			writeCtx = probestore.WithResourceVersion(ctx, *probe.ResourceVersion)
+		}
+		updated, err := s.Store.UpdateProbe(writeCtx, probe)
+		if err != nil {
+			metrics.RecordProbestoreError("update_probe_group")
+			if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) {
+				return v1.UpdateProbeGroup409JSONResponse{Error: concurrentChangeError(probe.Id)}, nil
+			}
+			slog.ErrorContext(ctx, "Error updating probe in storage", "probe_id", probe.Id, "error", err)
+			return nil, fmt.Errorf("failed to update probe in storage: %w", err)
+		}
+		s.Audit.Record(ctx, v1.UpdateProbeGroup, probe.Id, before, updated)
+		probes[i] = *updated
+	}
+
+	slog.InfoContext(ctx, "Updated probe group", "paused", request.Body.Paused)
+	return v1.UpdateProbeGroup200JSONResponse(probeGroupObject(request.GroupId, probes, true)), nil
+}
+
+// (DELETE /probe-groups/{group_id})
+func (s Server) DeleteProbeGroup(ctx context.Context, request v1.DeleteProbeGroupRequestObject) (v1.DeleteProbeGroupResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("delete_probe_group", time.Now())
+	ctx = logging.With(ctx, "group_id", request.GroupId)
+
+	probes, err := s.groupProbes(ctx, groupLabelKey+"="+request.GroupId)
+	if err != nil {
+		metrics.RecordProbestoreError("delete_probe_group")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+	if len(probes) == 0 {
+		return v1.DeleteProbeGroup404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("probe group %s not found", request.GroupId),
+			},
+		}, nil
+	}
+
+	for _, probe := range probes {
+		res, err := s.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: probe.Id})
+		if err != nil {
+			return nil, err
+		}
+		switch r := res.(type) {
+		case v1.DeleteProbe204Response, v1.DeleteProbe404JSONResponse:
+			// Deleted now, or by someone else since the listing.
+		case v1.DeleteProbe409JSONResponse:
+			return v1.DeleteProbeGroup409JSONResponse(r), nil
+		default:
+			return nil, fmt.Errorf("failed to delete probe %s: unexpected response %T", probe.Id, res)
+		}
+	}
+
+	slog.InfoContext(ctx, "Deleted probe group", "probes", len(probes))
+	return v1.DeleteProbeGroup204Response{}, nil
+}
+
+// groupProbes lists the probes the caller may see matching the group
+// selector, oldest first.
+func (s Server) groupProbes(ctx context.Context, groupSelector string) ([]v1.ProbeObject, error) {
+	selector, err := s.probeSelector(ctx, &groupSelector)
+	if err != nil {
+		return nil, err
+	}
+	probes, err := s.Store.ListProbes(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	probeOrder{by: v1.ListProbesParamsSortByCreatedAt}.sort(probes)
+	return probes, nil
+}
+
+// removeGroupProbes removes the probes created for a group that could not
+// be created as a whole. Their secrets are pruned with those of other
+// removed probes.
+func (s Server) removeGroupProbes(ctx context.Context, probes []v1.ProbeObject) {
+	for _, probe := range probes {
+		if err := s.Store.DeleteProbeStorage(ctx, probe.Id); err != nil {
+			slog.ErrorContext(ctx, "Error removing probe of group that was not created", "probe_id", probe.Id, "error", err)
+			continue
+		}
+		s.recordDeletion(ctx, probe)
+	}
+}
+
+// groupProbeError points the error of creating a probe of a group at the
+// probe.
+func groupProbeError(i int, err v1.ErrorObject) v1.ErrorObject {
+	err.Message = fmt.Sprintf("probes[%d]: %s", i, err.Message)
+	return err
+}
+
+// probeGroupObject returns the API view of a group, computing its status from
+// its probes, which are included if withProbes is set.
+func probeGroupObject(groupID string, probes []v1.ProbeObject, withProbes bool) v1.ProbeGroupObject {
+	obj := v1.ProbeGroupObject{
+		Id:           groupID,
+		ProbeCount:   len(probes),
+		StatusCounts: map[string]int{},
+	}
+	running, paused := 0, 0
+	for _, probe := range probes {
+		obj.StatusCounts[string(probe.Status)]++
+		if probe.Status == v1.Terminating {
+			continue
+		}
+		running++
+		if isPaused(probe) {
+			paused++
+		}
+	}
+	obj.Paused = running > 0 && paused == running
+
+	switch {
+	case running == 0:
+		obj.Status = v1.Deleting
+	case obj.StatusCounts[string(v1.Failed)] > 0:
+		obj.Status = v1.Degraded
+	case obj.Paused:
+		obj.Status = v1.Paused
+	case obj.StatusCounts[string(v1.Pending)] > 0:
+		obj.Status = v1.Progressing
+	default:
+		obj.Status = v1.Ready
+	}
+	if withProbes {
+		obj.Probes = &probes
+	}
+	return obj
+}
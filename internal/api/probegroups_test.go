@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeGroups(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	res, err := server.CreateProbeGroup(ctx, v1.CreateProbeGroupRequestObject{Body: &v1.CreateProbeGroupJSONRequestBody{
+		Id:     "hc-1",
+		Labels: &v1.LabelsSchema{"cluster-id": "hc-1", "env": "prod"},
+		Probes: []v1.CreateProbeRequest{
+			{StaticUrl: "https://api.hc-1.example.com/livez"},
+			{StaticUrl: "https://console.hc-1.example.com", Labels: &v1.LabelsSchema{"env": "stage"}},
+		},
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbeGroup201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, v1.Progressing, created.Status)
+	assert.Equal(t, 2, created.ProbeCount)
+	assert.Equal(t, map[string]int{"pending": 2}, created.StatusCounts)
+	require.NotNil(t, created.Probes)
+	probes := *created.Probes
+	require.Len(t, probes, 2)
+	assert.Equal(t, "hc-1", (*probes[0].Labels)[groupLabelKey])
+	assert.Equal(t, "prod", (*probes[0].Labels)["env"])
+	assert.Equal(t, "stage", (*probes[1].Labels)["env"], "probe labels override the group's")
+
+	markStatus := func(t *testing.T, probe v1.ProbeObject, status v1.StatusSchema) {
+		t.Helper()
+		current, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		current.Status = status
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+	}
+	getGroup := func(t *testing.T) v1.ProbeGroupObject {
+		t.Helper()
+		res, err := server.GetProbeGroup(ctx, v1.GetProbeGroupRequestObject{GroupId: "hc-1"})
+		require.NoError(t, err)
+		group, ok := res.(v1.GetProbeGroup200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		return v1.ProbeGroupObject(group)
+	}
+	markStatus(t, probes[0], v1.Active)
+	markStatus(t, probes[1], v1.Active)
+	assert.Equal(t, v1.Ready, getGroup(t).Status)
+
+	t.Run("invalid groups are not created", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			body v1.CreateProbeGroupJSONRequestBody
+			want v1.CreateProbeGroupResponseObject
+		}{
+			"invalid id": {
+				body: v1.CreateProbeGroupJSONRequestBody{Id: "not a label", Probes: []v1.CreateProbeRequest{{StaticUrl: "https://a.example.com"}}},
+				want: v1.CreateProbeGroup400JSONResponse{},
+			},
+			"no probes": {
+				body: v1.CreateProbeGroupJSONRequestBody{Id: "hc-2"},
+				want: v1.CreateProbeGroup400JSONResponse{},
+			},
+			"protected labels": {
+				body: v1.CreateProbeGroupJSONRequestBody{Id: "hc-2", Labels: &v1.LabelsSchema{groupLabelKey: "hc-3"}, Probes: []v1.CreateProbeRequest{{StaticUrl: "https://a.example.com"}}},
+				want: v1.CreateProbeGroup403JSONResponse{},
+			},
+			"existing group": {
+				body: v1.CreateProbeGroupJSONRequestBody{Id: "hc-1", Probes: []v1.CreateProbeRequest{{StaticUrl: "https://a.example.com"}}},
+				want: v1.CreateProbeGroup409JSONResponse{},
+			},
+		} {
+			res, err := server.CreateProbeGroup(ctx, v1.CreateProbeGroupRequestObject{Body: &tc.body})
+			require.NoError(t, err)
+			assert.IsType(t, tc.want, res, name)
+		}
+	})
+
+	t.Run("groups are created as a whole", func(t *testing.T) {
+		res, err := server.CreateProbeGroup(ctx, v1.CreateProbeGroupRequestObject{Body: &v1.CreateProbeGroupJSONRequestBody{
+			Id: "hc-2",
+			Probes: []v1.CreateProbeRequest{
+				{StaticUrl: "https://api.hc-2.example.com/livez"},
+				{StaticUrl: "https://api.hc-1.example.com/livez"},
+			},
+		}})
+		require.NoError(t, err)
+		conflict, ok := res.(v1.CreateProbeGroup409JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Contains(t, conflict.Error.Message, "probes[1]: ")
+
+		res2, err := server.GetProbeGroup(ctx, v1.GetProbeGroupRequestObject{GroupId: "hc-2"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeGroup404JSONResponse{}, res2, "the probes created before the failure are removed")
+	})
+
+	t.Run("pause and resume", func(t *testing.T) {
+		res, err := server.UpdateProbeGroup(ctx, v1.UpdateProbeGroupRequestObject{GroupId: "hc-1", Body: &v1.UpdateProbeGroupJSONRequestBody{Paused: true}})
+		require.NoError(t, err)
+		paused, ok := res.(v1.UpdateProbeGroup200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.True(t, paused.Paused)
+		assert.Equal(t, v1.Paused, paused.Status)
+		for _, probe := range *paused.Probes {
+			assert.True(t, isPaused(probe))
+		}
+		assert.Len(t, server.Audit.List(audit.Filter{Operation: v1.UpdateProbeGroup}), 2)
+
+		_, err = server.UpdateProbeGroup(ctx, v1.UpdateProbeGroupRequestObject{GroupId: "hc-1", Body: &v1.UpdateProbeGroupJSONRequestBody{Paused: true}})
+		require.NoError(t, err)
+		assert.Len(t, server.Audit.List(audit.Filter{Operation: v1.UpdateProbeGroup}), 2, "probes already paused are left alone")
+
+		res, err = server.UpdateProbeGroup(ctx, v1.UpdateProbeGroupRequestObject{GroupId: "hc-1", Body: &v1.UpdateProbeGroupJSONRequestBody{Paused: false}})
+		require.NoError(t, err)
+		resumed, ok := res.(v1.UpdateProbeGroup200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.False(t, resumed.Paused)
+		assert.Nil(t, (*resumed.Probes)[0].Paused)
+
+		res, err = server.UpdateProbeGroup(ctx, v1.UpdateProbeGroupRequestObject{GroupId: "hc-9", Body: &v1.UpdateProbeGroupJSONRequestBody{Paused: true}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbeGroup404JSONResponse{}, res)
+	})
+
+	t.Run("failed probes degrade the group", func(t *testing.T) {
+		markStatus(t, probes[1], v1.Failed)
+		group := getGroup(t)
+		assert.Equal(t, v1.Degraded, group.Status)
+		assert.Equal(t, map[string]int{"active": 1, "failed": 1}, group.StatusCounts)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		res, err := server.ListProbeGroups(ctx, v1.ListProbeGroupsRequestObject{})
+		require.NoError(t, err)
+		list, ok := res.(v1.ListProbeGroups200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		require.Len(t, list.Groups, 1)
+		assert.Equal(t, "hc-1", list.Groups[0].Id)
+		assert.Nil(t, list.Groups[0].Probes, "listings leave out the probes")
+	})
+
+	t.Run("other tenants do not see the group", func(t *testing.T) {
+		server := server
+		server.TenantIsolation = true
+		res, err := server.GetProbeGroup(limits.WithTenant(ctx, "team-b"), v1.GetProbeGroupRequestObject{GroupId: "hc-1"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeGroup404JSONResponse{}, res)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		res, err := server.DeleteProbeGroup(ctx, v1.DeleteProbeGroupRequestObject{GroupId: "hc-1"})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteProbeGroup204Response{}, res)
+
+		group := getGroup(t)
+		assert.Equal(t, v1.Deleting, group.Status, "active probes terminate until their agent cleans up")
+		assert.Equal(t, map[string]int{"terminating": 1}, group.StatusCounts, "failed probes are removed")
+	})
+}
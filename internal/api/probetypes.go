@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// applyProbeType sets up a probe created with the settings of a dns, tcp or
+// icmp probe: its static URL is derived from them, so that such probes are
+// stored and checked for duplicates like http ones, and its module defaults
+// to theirs.
+func applyProbeType(probe *v1.ProbeObject, body v1.CreateProbeRequest) error {
+	settings, err := v1.TypeSettings(body.Dns, body.Tcp, body.Icmp)
+	if err != nil || settings == nil {
+		return err
+	}
+	switch {
+	case body.StaticUrl != "":
+		return fmt.Errorf("static_url cannot be set together with %s, it is derived from its settings", settings.Module())
+	case body.TemplateId != nil:
+		return fmt.Errorf("template_id cannot be set together with %s", settings.Module())
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	probe.SetTypeSettings(settings)
+	probe.StaticUrl = settings.URL()
+	if probe.Module == nil {
+		probe.Module = new(settings.Module())
+	}
+	return checkProbeTypeModule(*probe)
+}
+
+// importProbeType sets the dns, tcp or icmp settings of a probe imported
+// from a bundle, whose static URL must be the one derived from them.
+func importProbeType(probe *v1.ProbeObject, bundled v1.BundledProbe) error {
+	settings, err := v1.TypeSettings(bundled.Dns, bundled.Tcp, bundled.Icmp)
+	if err != nil || settings == nil {
+		return err
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if probe.StaticUrl != settings.URL() {
+		return fmt.Errorf("static_url %q of a %s probe must be %q, derived from its settings", probe.StaticUrl, settings.Module(), settings.URL())
+	}
+	probe.SetTypeSettings(settings)
+	if probe.Module == nil {
+		probe.Module = new(settings.Module())
+	}
+	return checkProbeTypeModule(*probe)
+}
+
+// updateProbeType replaces the settings of a dns, tcp or icmp probe that may
+// change: the expected answers, whether to check TLS and the packet count.
+// The rest make up its static URL, so they cannot.
+func updateProbeType(probe *v1.ProbeObject, body v1.UpdateProbeRequest) error {
+	settings, err := v1.TypeSettings(body.Dns, body.Tcp, body.Icmp)
+	if err != nil || settings == nil {
+		return err
+	}
+	current, err := probe.TypeSettings()
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Module() != settings.Module() {
+		return fmt.Errorf("probe with ID %s has no %s settings, they can only be set on creation", probe.Id, settings.Module())
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if settings.URL() != current.URL() {
+		return fmt.Errorf("the %s settings of probe with ID %s that make up its static_url cannot change", settings.Module(), probe.Id)
+	}
+	switch updated := current.(type) {
+	case v1.DnsSchema:
+		updated.ExpectedAnswers = nil
+		if answers := body.Dns.ExpectedAnswers; answers != nil && len(*answers) > 0 {
+			updated.ExpectedAnswers = answers
+		}
+		current = updated
+	case v1.TcpSchema:
+		updated.Tls = body.Tcp.Tls
+		current = updated
+	case v1.IcmpSchema:
+		updated.PacketCount = body.Icmp.PacketCount
+		current = updated
+	}
+	probe.SetTypeSettings(current)
+	return nil
+}
+
+// checkProbeTypeModule rejects dns, tcp and icmp probes run with another
+// module than their own.
+func checkProbeTypeModule(probe v1.ProbeObject) error {
+	settings, err := probe.TypeSettings()
+	if err != nil || settings == nil {
+		return err
+	}
+	if probe.Module != nil && *probe.Module != settings.Module() {
+		return fmt.Errorf("probes with %s settings must use the %s module, not %s", settings.Module(), settings.Module(), *probe.Module)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSProbes(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	create := func(body v1.CreateProbeJSONRequestBody) v1.CreateProbeResponseObject {
+		t.Helper()
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+	query := v1.DnsSchema{
+		QueryName:       "api.example.com",
+		RecordType:      v1.A,
+		ExpectedAnswers: &[]string{"203.0.113.7"},
+		Resolver:        new("10.0.0.10"),
+	}
+	res := create(v1.CreateProbeJSONRequestBody{Dns: &query})
+	created, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, "dns://10.0.0.10/api.example.com?type=A", created.StaticUrl)
+	assert.Equal(t, v1.Dns, *created.Module, "the module defaults to dns")
+	assert.Equal(t, query, *created.Dns)
+
+	t.Run("duplicate queries", func(t *testing.T) {
+		duplicate := query
+		duplicate.QueryName = "API.example.com."
+		assert.IsType(t, v1.CreateProbe409JSONResponse{}, create(v1.CreateProbeJSONRequestBody{Dns: &duplicate}))
+	})
+
+	t.Run("invalid creations", func(t *testing.T) {
+		for message, body := range map[string]v1.CreateProbeJSONRequestBody{
+			"static_url cannot be set together with dns": {StaticUrl: "https://example.com", Dns: &query},
+			"must use the dns module, not http_2xx":      {Dns: &query, Module: new(v1.Http2xx)},
+			"invalid dns query_name":                     {Dns: &v1.DnsSchema{QueryName: "not a name", RecordType: v1.A}},
+			"unknown dns record_type":                    {Dns: &v1.DnsSchema{QueryName: "example.com", RecordType: "ANY"}},
+		} {
+			res := create(body)
+			require.IsType(t, v1.CreateProbe400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.CreateProbe400JSONResponse).Error.Message, message)
+		}
+	})
+
+	update := func(id v1.ProbeIdSchema, body v1.UpdateProbeJSONRequestBody) v1.UpdateProbeResponseObject {
+		t.Helper()
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: id, Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("expected answers change", func(t *testing.T) {
+		changed := query
+		changed.ExpectedAnswers = &[]string{"203.0.113.8"}
+		res := update(created.Id, v1.UpdateProbeJSONRequestBody{Dns: &changed})
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, changed, *updated.Body.Dns)
+		assert.Equal(t, *created.Generation+1, *updated.Body.Generation)
+
+		changed.ExpectedAnswers = &[]string{}
+		res = update(created.Id, v1.UpdateProbeJSONRequestBody{Dns: &changed})
+		updated, ok = res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, updated.Body.Dns.ExpectedAnswers, "an empty list removes them")
+	})
+
+	t.Run("invalid updates", func(t *testing.T) {
+		changed := query
+		changed.RecordType = v1.AAAA
+		res := update(created.Id, v1.UpdateProbeJSONRequestBody{Dns: &changed})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "that make up its static_url cannot change")
+
+		res = update(created.Id, v1.UpdateProbeJSONRequestBody{Module: new(v1.Tcp)})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "must use the dns module, not tcp")
+
+		plain, ok := create(v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}).(v1.CreateProbe201JSONResponse)
+		require.True(t, ok)
+		res = update(plain.Id, v1.UpdateProbeJSONRequestBody{Dns: &query})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "has no dns settings")
+	})
+}
+
+func TestTCPAndICMPProbes(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	create := func(body v1.CreateProbeJSONRequestBody) v1.CreateProbeResponseObject {
+		t.Helper()
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+	update := func(id v1.ProbeIdSchema, body v1.UpdateProbeJSONRequestBody) v1.UpdateProbeResponseObject {
+		t.Helper()
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: id, Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := create(v1.CreateProbeJSONRequestBody{Tcp: &v1.TcpSchema{Address: "db.example.com:5432"}})
+	tcp, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, "tcp://db.example.com:5432", tcp.StaticUrl)
+	assert.Equal(t, v1.Tcp, *tcp.Module)
+
+	res = create(v1.CreateProbeJSONRequestBody{Icmp: &v1.IcmpSchema{Host: "203.0.113.7", PacketCount: new(3)}})
+	icmp, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, "icmp://203.0.113.7", icmp.StaticUrl)
+	assert.Equal(t, v1.Icmp, *icmp.Module)
+	assert.Equal(t, new(3), icmp.Icmp.PacketCount)
+
+	t.Run("invalid creations", func(t *testing.T) {
+		for message, body := range map[string]v1.CreateProbeJSONRequestBody{
+			"only one of dns, tcp and icmp can be set":   {Tcp: &v1.TcpSchema{Address: "db.example.com:5432"}, Icmp: &v1.IcmpSchema{Host: "db.example.com"}},
+			"must use the icmp module, not tcp":          {Icmp: &v1.IcmpSchema{Host: "db.example.com"}, Module: new(v1.Tcp)},
+			"static_url cannot be set together with tcp": {StaticUrl: "https://example.com", Tcp: &v1.TcpSchema{Address: "db.example.com:5432"}},
+			`invalid tcp address "db.example.com"`:       {Tcp: &v1.TcpSchema{Address: "db.example.com"}},
+			"icmp packet_count must be between 1 and 10": {Icmp: &v1.IcmpSchema{Host: "db.example.com", PacketCount: new(0)}},
+		} {
+			res := create(body)
+			require.IsType(t, v1.CreateProbe400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.CreateProbe400JSONResponse).Error.Message, message)
+		}
+	})
+
+	t.Run("updates", func(t *testing.T) {
+		res := update(tcp.Id, v1.UpdateProbeJSONRequestBody{Tcp: &v1.TcpSchema{Address: "DB.example.com:5432", Tls: new(true)}})
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, v1.TcpSchema{Address: "db.example.com:5432", Tls: new(true)}, *updated.Body.Tcp, "the address is kept as created")
+
+		res = update(icmp.Id, v1.UpdateProbeJSONRequestBody{Icmp: &v1.IcmpSchema{Host: "203.0.113.7"}})
+		updated, ok = res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, updated.Body.Icmp.PacketCount)
+
+		res = update(tcp.Id, v1.UpdateProbeJSONRequestBody{Tcp: &v1.TcpSchema{Address: "db.example.com:5433"}})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "that make up its static_url cannot change")
+
+		res = update(tcp.Id, v1.UpdateProbeJSONRequestBody{Icmp: &v1.IcmpSchema{Host: "db.example.com"}})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "has no icmp settings")
+	})
+}
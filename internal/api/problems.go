@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// defaultProblemAge is how long a probe may be pending, terminating or failed
+// without a check before ListProbeProblems reports it, unless the request
+// says otherwise.
+const defaultProblemAge = 15 * time.Minute
+
+// problemThresholds are the ages at which ListProbeProblems reports a probe.
+type problemThresholds struct {
+	pending     time.Duration
+	terminating time.Duration
+	stale       time.Duration
+}
+
+// (GET /probes/problems)
+func (s Server) ListProbeProblems(ctx context.Context, request v1.ListProbeProblemsRequestObject) (v1.ListProbeProblemsResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("list_probe_problems", time.Now())
+	params := request.Params
+
+	var thresholds problemThresholds
+	for _, t := range []struct {
+		name  string
+		value *v1.DurationSchema
+		into  *time.Duration
+	}{
+		{name: "pending_for", value: params.PendingFor, into: &thresholds.pending},
+		{name: "terminating_for", value: params.TerminatingFor, into: &thresholds.terminating},
+		{name: "stale_after", value: params.StaleAfter, into: &thresholds.stale},
+	} {
+		age, err := problemAge(t.value)
+		if err != nil {
+			return v1.ListProbeProblems400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid %s: %v", t.name, err)}}, nil
+		}
+		*t.into = age
+	}
+
+	selector, err := s.probeSelector(ctx, params.LabelSelector)
+	if err != nil {
+		return v1.ListProbeProblems400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	}
+	probes, err := s.Store.ListProbes(ctx, selector)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probe_problems")
+		slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	}
+
+	now := time.Now()
+	problems := []v1.ProbeProblem{}
+	for _, probe := range probes {
+		if problem, ok := s.probeProblem(probe, thresholds, now); ok {
+			problems = append(problems, problem)
+		}
+	}
+	// Failed probes that were never checked have no time and come first.
+	slices.SortStableFunc(problems, func(a, b v1.ProbeProblem) int {
+		switch {
+		case a.Since == nil && b.Since == nil:
+			return 0
+		case a.Since == nil:
+			return -1
+		case b.Since == nil:
+			return 1
+		}
+		return a.Since.Compare(*b.Since)
+	})
+	return v1.ListProbeProblems200JSONResponse{Problems: problems}, nil
+}
+
+// notifyProblems sends a ProbeStuck notification for every probe that
+// ListProbeProblems reports with its default thresholds.
+func (s Server) notifyProblems(ctx context.Context, probes []v1.ProbeObject, now time.Time) {
+	if s.Notifications == nil {
+		return
+	}
+	thresholds := problemThresholds{pending: defaultProblemAge, terminating: defaultProblemAge, stale: defaultProblemAge}
+	for _, probe := range probes {
+		problem, ok := s.probeProblem(probe, thresholds, now)
+		if !ok {
+			continue
+		}
+		var tenant string
+		if probe.Labels != nil {
+			tenant = (*probe.Labels)[tenantLabelKey]
+		}
+		s.Notifications.Notify(ctx, notify.Event{
+			Type:    notify.ProbeStuck,
+			Key:     probe.Id.String() + "/" + string(problem.Reason),
+			Tenant:  tenant,
+			Summary: fmt.Sprintf("Probe %s is %s", probe.Id, problem.Message),
+			Details: map[string]string{"reason": string(problem.Reason), "static_url": probe.StaticUrl},
+		})
+	}
+}
+
+// probeProblem reports whether the probe has been in an anomalous state for
+// longer than the thresholds allow, and why. Pending probes without a
+// creation timestamp and terminating probes without a deletion timestamp are
+// not reported, as their age is unknown.
+func (s Server) probeProblem(probe v1.ProbeObject, thresholds problemThresholds, now time.Time) (v1.ProbeProblem, bool) {
+	problem := v1.ProbeProblem{Probe: probe}
+	switch probe.Status {
+	case v1.Pending:
+		if probe.CreationTimestamp == nil || now.Sub(*probe.CreationTimestamp) < thresholds.pending {
+			return problem, false
+		}
+		problem.Reason = v1.StuckPending
+		problem.Since = probe.CreationTimestamp
+		problem.Message = fmt.Sprintf("pending for %s without being picked up by an agent", now.Sub(*probe.CreationTimestamp).Truncate(time.Second))
+	case v1.Terminating:
+		if probe.DeletionTimestamp == nil || now.Sub(*probe.DeletionTimestamp) < thresholds.terminating {
+			return problem, false
+		}
+		problem.Reason = v1.TerminatingTooLong
+		problem.Since = probe.DeletionTimestamp
+		problem.Message = fmt.Sprintf("terminating for %s without its agent confirming the deletion", now.Sub(*probe.DeletionTimestamp).Truncate(time.Second))
+	case v1.Failed:
+		lastCheck, checked := s.lastCheck(probe)
+		if checked && now.Sub(lastCheck) < thresholds.stale {
+			return problem, false
+		}
+		problem.Reason = v1.StaleFailed
+		problem.Message = "failed and never checked"
+		if checked {
+			problem.Since = &lastCheck
+			problem.Message = fmt.Sprintf("failed and not checked for %s", now.Sub(lastCheck).Truncate(time.Second))
+		}
+	default:
+		return problem, false
+	}
+	return problem, true
+}
+
+// lastCheck returns the later of the probe's last-reconciled heartbeat and
+// the newest result reported to this replica.
+func (s Server) lastCheck(probe v1.ProbeObject) (time.Time, bool) {
+	lastCheck, checked := probestore.LastReconciled(probe)
+	if results := s.Results.List(probe.Id); len(results) > 0 && results[0].Timestamp.After(lastCheck) {
+		lastCheck, checked = results[0].Timestamp, true
+	}
+	return lastCheck, checked
+}
+
+// problemAge returns the given threshold, or defaultProblemAge when unset.
+func problemAge(value *v1.DurationSchema) (time.Duration, error) {
+	if value == nil {
+		return defaultProblemAge, nil
+	}
+	age, err := time.ParseDuration(*value)
+	if err != nil {
+		return 0, err
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("must be positive, got %s", age)
+	}
+	return age, nil
+}
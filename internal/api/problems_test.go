@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProbeProblems(t *testing.T) {
+	now := time.Now().UTC()
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	heartbeat := func(d time.Duration) *v1.LabelsSchema {
+		return &v1.LabelsSchema{"last-reconciled": now.Add(-d).Format("20060102T150405Z")}
+	}
+
+	stuckPending := v1.ProbeObject{Id: uuid.New(), Status: v1.Pending, CreationTimestamp: ago(time.Hour)}
+	newPending := v1.ProbeObject{Id: uuid.New(), Status: v1.Pending, CreationTimestamp: ago(time.Minute)}
+	legacyPending := v1.ProbeObject{Id: uuid.New(), Status: v1.Pending}
+	stuckTerminating := v1.ProbeObject{Id: uuid.New(), Status: v1.Terminating, DeletionTimestamp: ago(30 * time.Minute)}
+	staleFailed := v1.ProbeObject{Id: uuid.New(), Status: v1.Failed, Labels: heartbeat(2 * time.Hour)}
+	neverChecked := v1.ProbeObject{Id: uuid.New(), Status: v1.Failed}
+	reportedFailed := v1.ProbeObject{Id: uuid.New(), Status: v1.Failed, Labels: heartbeat(2 * time.Hour)}
+	checkedFailed := v1.ProbeObject{Id: uuid.New(), Status: v1.Failed, Labels: heartbeat(time.Minute)}
+	active := v1.ProbeObject{Id: uuid.New(), Status: v1.Active, CreationTimestamp: ago(time.Hour)}
+
+	server := NewServer(restoredProbeStore(t, stuckPending, newPending, legacyPending, stuckTerminating, staleFailed, neverChecked, reportedFailed, checkedFailed, active))
+	server.Results.Add(reportedFailed.Id, v1.ProbeResultObject{Timestamp: now.Add(-time.Minute)})
+
+	duration := func(d string) *v1.DurationSchema { return &d }
+
+	testCases := []struct {
+		name     string
+		params   v1.ListProbeProblemsParams
+		expected []v1.ProbeProblem
+	}{
+		{
+			name:   "default thresholds",
+			params: v1.ListProbeProblemsParams{},
+			expected: []v1.ProbeProblem{
+				{Probe: neverChecked, Reason: v1.StaleFailed},
+				{Probe: staleFailed, Reason: v1.StaleFailed, Since: ago(2 * time.Hour)},
+				{Probe: stuckPending, Reason: v1.StuckPending, Since: stuckPending.CreationTimestamp},
+				{Probe: stuckTerminating, Reason: v1.TerminatingTooLong, Since: stuckTerminating.DeletionTimestamp},
+			},
+		},
+		{
+			name:   "longer thresholds",
+			params: v1.ListProbeProblemsParams{PendingFor: duration("2h"), TerminatingFor: duration("1h"), StaleAfter: duration("3h")},
+			expected: []v1.ProbeProblem{
+				{Probe: neverChecked, Reason: v1.StaleFailed},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := server.ListProbeProblems(context.Background(), v1.ListProbeProblemsRequestObject{Params: tc.params})
+			require.NoError(t, err)
+			require.IsType(t, v1.ListProbeProblems200JSONResponse{}, res)
+			problems := res.(v1.ListProbeProblems200JSONResponse).Problems
+			require.Len(t, problems, len(tc.expected))
+			for i, expected := range tc.expected {
+				assert.Equal(t, expected.Probe.Id, problems[i].Probe.Id)
+				assert.Equal(t, expected.Reason, problems[i].Reason)
+				if expected.Since == nil {
+					assert.Nil(t, problems[i].Since)
+				} else {
+					require.NotNil(t, problems[i].Since)
+					// Heartbeats have second precision.
+					assert.WithinDuration(t, *expected.Since, *problems[i].Since, time.Second)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid thresholds", func(t *testing.T) {
+		for _, params := range []v1.ListProbeProblemsParams{
+			{PendingFor: duration("soon")},
+			{StaleAfter: duration("0s")},
+		} {
+			res, err := server.ListProbeProblems(context.Background(), v1.ListProbeProblemsRequestObject{Params: params})
+			require.NoError(t, err)
+			assert.IsType(t, v1.ListProbeProblems400JSONResponse{}, res)
+		}
+	})
+
+	t.Run("storage errors", func(t *testing.T) {
+		server := NewServer(&faultyStore{ProbeStorage: newProbeStore(t), listErr: errors.New("boom")})
+		_, err := server.ListProbeProblems(context.Background(), v1.ListProbeProblemsRequestObject{})
+		assert.Error(t, err)
+	})
+}
+
+// eventReceiver passes the events it is sent to a channel.
+type eventReceiver chan notify.Event
+
+func (r eventReceiver) Name() string { return "test" }
+
+func (r eventReceiver) Send(_ context.Context, event notify.Event) error {
+	r <- event
+	return nil
+}
+
+func TestNotifyProblems(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Now().UTC()
+	created := now.Add(-time.Hour)
+	stuck := v1.ProbeObject{
+		Id:                uuid.New(),
+		StaticUrl:         "https://example.com",
+		Status:            v1.Pending,
+		CreationTimestamp: &created,
+		Labels:            &v1.LabelsSchema{tenantLabelKey: "team-a"},
+	}
+	healthy := v1.ProbeObject{Id: uuid.New(), Status: v1.Active, CreationTimestamp: &created}
+
+	events := make(eventReceiver, 10)
+	notifier, err := notify.New(notify.Config{}, events)
+	require.NoError(t, err)
+	go notifier.Run(ctx)
+	server := NewServer(newProbeStore(t))
+	server.Notifications = notifier
+
+	server.notifyProblems(ctx, []v1.ProbeObject{stuck, healthy}, now)
+	server.notifyProblems(ctx, []v1.ProbeObject{stuck, healthy}, now)
+	select {
+	case event := <-events:
+		assert.Equal(t, notify.ProbeStuck, event.Type)
+		assert.Equal(t, stuck.Id.String()+"/stuck_pending", event.Key)
+		assert.Equal(t, "team-a", event.Tenant)
+		assert.Equal(t, map[string]string{"reason": "stuck_pending", "static_url": "https://example.com"}, event.Details)
+	case <-time.After(time.Second):
+		t.Fatal("no notification")
+	}
+	assert.Never(t, func() bool { return len(events) > 0 }, 50*time.Millisecond, 10*time.Millisecond, "the problem is notified once")
+}
+
+func TestProbeProblem_Message(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	thresholds := problemThresholds{pending: time.Minute, terminating: time.Minute, stale: time.Minute}
+	server := NewServer(probestore.NewMemoryProbeStore())
+
+	testCases := []struct {
+		probe    v1.ProbeObject
+		expected string
+	}{
+		{probe: v1.ProbeObject{Status: v1.Pending, CreationTimestamp: ago(90*time.Minute + 500*time.Millisecond)}, expected: "pending for 1h30m0s without being picked up by an agent"},
+		{probe: v1.ProbeObject{Status: v1.Terminating, DeletionTimestamp: ago(30 * time.Minute)}, expected: "terminating for 30m0s without its agent confirming the deletion"},
+		{probe: v1.ProbeObject{Status: v1.Failed, Labels: &v1.LabelsSchema{"last-reconciled": "20260301T100000Z"}}, expected: "failed and not checked for 2h0m0s"},
+		{probe: v1.ProbeObject{Status: v1.Failed}, expected: "failed and never checked"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.probe.Status), func(t *testing.T) {
+			problem, ok := server.probeProblem(tc.probe, thresholds, now)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, problem.Message)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"slices"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// setRegions replaces the regions a probe is limited to, an empty list
+// removing the limit.
+func setRegions(probe *v1.ProbeObject, regions v1.RegionsSchema) error {
+	if err := v1.ValidateRegions(regions); err != nil {
+		return err
+	}
+	probe.Regions = nil
+	if len(regions) > 0 {
+		probe.Regions = new(slices.Clone(regions))
+	}
+	return nil
+}
+
+// sortedRegions returns the regions a probe is limited to in order, nil if
+// it is not limited, so that probes listing the same regions compare equal.
+func sortedRegions(probe v1.ProbeObject) []string {
+	if probe.Regions == nil || len(*probe.Regions) == 0 {
+		return nil
+	}
+	return slices.Sorted(slices.Values(*probe.Regions))
+}
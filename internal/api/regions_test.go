@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeRegions(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	regions := v1.RegionsSchema{"us-east-1", "eu-west-1"}
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://api.example.com",
+		Regions:   &regions,
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, &regions, created.Regions)
+
+	update := func(body v1.UpdateProbeJSONRequestBody) v1.UpdateProbeResponseObject {
+		t.Helper()
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: created.Id, Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("invalid regions", func(t *testing.T) {
+		for message, regions := range map[string]v1.RegionsSchema{
+			`invalid regions[0] "US-East"`:           {"US-East"},
+			`regions[1] "us-east-1" is listed twice`: {"us-east-1", "us-east-1"},
+		} {
+			res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+				StaticUrl: "https://other.example.com",
+				Regions:   &regions,
+			}})
+			require.NoError(t, err)
+			require.IsType(t, v1.CreateProbe400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.CreateProbe400JSONResponse).Error.Message, message)
+		}
+	})
+
+	t.Run("agents only get probes of their region", func(t *testing.T) {
+		_, err := server.Assignments.Heartbeat(ctx, assignment.Agent{ID: "east", Labels: map[string]string{assignment.RegionLabelKey: "us-east-1"}})
+		require.NoError(t, err)
+		_, err = server.Assignments.Heartbeat(ctx, assignment.Agent{ID: "west", Labels: map[string]string{assignment.RegionLabelKey: "us-west-2"}})
+		require.NoError(t, err)
+		_, err = server.Assignments.Reconcile(ctx)
+		require.NoError(t, err)
+
+		listed := func(agentID string) []v1.ProbeObject {
+			t.Helper()
+			res, err := server.ListAgentProbes(ctx, v1.ListAgentProbesRequestObject{AgentId: agentID})
+			require.NoError(t, err)
+			require.IsType(t, v1.ListAgentProbes200JSONResponse{}, res)
+			return res.(v1.ListAgentProbes200JSONResponse).Probes
+		}
+		require.Len(t, listed("east"), 1)
+		assert.Empty(t, listed("west"))
+
+		// Limiting the probe to another region takes it from its agent at once.
+		res := update(v1.UpdateProbeJSONRequestBody{Regions: &v1.RegionsSchema{"us-west-2"}})
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		assert.Empty(t, listed("east"))
+
+		_, err = server.Assignments.Reconcile(ctx)
+		require.NoError(t, err)
+		require.Len(t, listed("west"), 1)
+	})
+
+	t.Run("an empty list removes the limit", func(t *testing.T) {
+		res := update(v1.UpdateProbeJSONRequestBody{Regions: &v1.RegionsSchema{}})
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, updated.Body.Regions)
+	})
+}
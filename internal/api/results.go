@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/results"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// (POST /probes/{probe_id}/results)
+func (s Server) ReportProbeResult(ctx context.Context, request v1.ReportProbeResultRequestObject) (v1.ReportProbeResultResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("report_probe_result", time.Now())
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+
+	body := request.Body
+	if body.StatusCode < 0 || body.StatusCode > 599 {
+		return v1.ReportProbeResult400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("status_code must be between 0 and 599, got %d", body.StatusCode),
+			},
+		}, nil
+	}
+	if body.LatencyMs < 0 {
+		return v1.ReportProbeResult400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("latency_ms must not be negative, got %v", body.LatencyMs),
+			},
+		}, nil
+	}
+	if body.Timestamp != nil {
+		if err := s.checkSkew(ctx, "timestamp", *body.Timestamp); err != nil {
+			return v1.ReportProbeResult400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+		}
+	}
+
+	if _, err := s.getProbe(ctx, request.ProbeId); err != nil {
+		metrics.RecordProbestoreError("report_probe_result")
+		if k8serrors.IsNotFound(err) {
+			return v1.ReportProbeResult404JSONResponse{
+				Warning: v1.WarningObject{
+					Message: fmt.Sprintf("probe with ID %s not found", request.ProbeId),
+				},
+			}, nil
+		}
+		slog.ErrorContext(ctx, "Error getting probe from storage for result", "error", err)
+		return nil, fmt.Errorf("failed to get probe from storage: %w", err)
+	}
+
+	result := v1.ProbeResultObject{
+		Success:    body.Success,
+		StatusCode: body.StatusCode,
+		LatencyMs:  body.LatencyMs,
+		Timestamp:  clock.Now(),
+	}
+	if body.Timestamp != nil {
+		result.Timestamp = body.Timestamp.UTC()
+	}
+
+	s.Results.Add(request.ProbeId, result)
+	metrics.RecordProbeResult(result.Success)
+	if ratio, ok := s.Results.SuccessRatio(); ok {
+		metrics.SetProbeSuccessRatio(ratio)
+	}
+	slog.DebugContext(ctx, "Recorded probe result", "success", result.Success, "status_code", result.StatusCode, "latency_ms", result.LatencyMs)
+
+	return v1.ReportProbeResult201JSONResponse(result), nil
+}
+
+// (GET /probes/{probe_id}/results)
+func (s Server) ListProbeResults(ctx context.Context, request v1.ListProbeResultsRequestObject) (v1.ListProbeResultsResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("list_probe_results", time.Now())
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+
+	if _, err := s.getProbe(ctx, request.ProbeId); err != nil {
+		metrics.RecordProbestoreError("list_probe_results")
+		if k8serrors.IsNotFound(err) {
+			return v1.ListProbeResults404JSONResponse{
+				Warning: v1.WarningObject{
+					Message: fmt.Sprintf("probe with ID %s not found", request.ProbeId),
+				},
+			}, nil
+		}
+		slog.ErrorContext(ctx, "Error getting probe from storage for results", "error", err)
+		return nil, fmt.Errorf("failed to get probe from storage: %w", err)
+	}
+
+	return v1.ListProbeResults200JSONResponse{Results: s.Results.List(request.ProbeId)}, nil
+}
+
+// defaultSummaryWindow is the window of result summaries when none is given.
+const defaultSummaryWindow = "24h"
+
+// (GET /probes/{probe_id}/results/summary)
+func (s Server) SummarizeProbeResults(ctx context.Context, request v1.SummarizeProbeResultsRequestObject) (v1.SummarizeProbeResultsResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("summarize_probe_results", time.Now())
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+	params := request.Params
+
+	window := defaultSummaryWindow
+	if params.Window != nil {
+		window = *params.Window
+	}
+	d, err := parseWindow(window)
+	if err != nil || d <= 0 || d > results.DailyRetention {
+		return v1.SummarizeProbeResults400JSONResponse{Error: v1.ErrorObject{
+			Message: fmt.Sprintf("invalid window %q, expected a positive duration such as 12h or 7d, at most 90d", window),
+		}}, nil
+	}
+	resolution := v1.Hour
+	if d > results.HourlyRetention {
+		resolution = v1.Day
+	}
+	if params.Resolution != nil {
+		resolution = *params.Resolution
+	}
+	period := results.Day
+	switch resolution {
+	case v1.Hour:
+		if d > results.HourlyRetention {
+			return v1.SummarizeProbeResults400JSONResponse{Error: v1.ErrorObject{
+				Message: fmt.Sprintf("hourly summaries are only kept for %s, use resolution=day for window %q", results.HourlyRetention, window),
+			}}, nil
+		}
+		period = results.Hour
+	case v1.Day:
+	default:
+		return v1.SummarizeProbeResults400JSONResponse{Error: v1.ErrorObject{
+			Message: fmt.Sprintf("invalid resolution %q, expected hour or day", resolution),
+		}}, nil
+	}
+
+	if _, err := s.getProbe(ctx, request.ProbeId); err != nil {
+		metrics.RecordProbestoreError("summarize_probe_results")
+		if k8serrors.IsNotFound(err) {
+			return v1.SummarizeProbeResults404JSONResponse{
+				Warning: v1.WarningObject{
+					Message: fmt.Sprintf("probe with ID %s not found", request.ProbeId),
+				},
+			}, nil
+		}
+		slog.ErrorContext(ctx, "Error getting probe from storage for result summary", "error", err)
+		return nil, fmt.Errorf("failed to get probe from storage: %w", err)
+	}
+
+	summary := s.Results.Summarize(request.ProbeId, d, period)
+	res := v1.ProbeResultSummary{
+		ProbeId:    request.ProbeId,
+		Window:     window,
+		Resolution: resolution,
+		Since:      summary.Since,
+		Total:      summary.Total,
+		Successes:  summary.Successes,
+		Buckets:    []v1.ResultBucket{},
+	}
+	if summary.Total > 0 {
+		ratio := float64(summary.Successes) / float64(summary.Total)
+		res.SuccessRatio = &ratio
+		res.LatencyMs = &v1.LatencyPercentiles{P50: summary.Latency.P50, P90: summary.Latency.P90, P99: summary.Latency.P99, Max: summary.Latency.Max}
+	}
+	for _, b := range summary.Buckets {
+		res.Buckets = append(res.Buckets, v1.ResultBucket{Start: b.Start, Total: b.Total, Successes: b.Successes})
+	}
+	return v1.SummarizeProbeResults200JSONResponse(res), nil
+}
+
+// parseWindow parses a duration, also accepting a whole number of days such
+// as 7d.
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		if n > math.MaxInt64/int(24*time.Hour) {
+			return 0, fmt.Errorf("window %q is too long", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
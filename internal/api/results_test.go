@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportProbeResult(t *testing.T) {
+	probeID := uuid.New()
+	ranAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	testCases := []struct {
+		name             string
+		probeID          uuid.UUID
+		body             v1.ReportProbeResultJSONRequestBody
+		store            probestore.ProbeStorage
+		expectedResponse v1.ReportProbeResultResponseObject
+		expectedErr      string
+	}{
+		{
+			name:    "records a result",
+			probeID: probeID,
+			body:    v1.ReportProbeResultJSONRequestBody{Success: true, StatusCode: 200, LatencyMs: 12.5, Timestamp: &ranAt},
+			expectedResponse: v1.ReportProbeResult201JSONResponse{
+				Success: true, StatusCode: 200, LatencyMs: 12.5, Timestamp: ranAt.UTC(),
+			},
+		},
+		{
+			name:    "records a run without a response",
+			probeID: probeID,
+			body:    v1.ReportProbeResultJSONRequestBody{Success: false, StatusCode: 0, LatencyMs: 5000},
+			expectedResponse: v1.ReportProbeResult201JSONResponse{
+				Success: false, StatusCode: 0, LatencyMs: 5000,
+			},
+		},
+		{
+			name:    "returns 400 for an invalid status code",
+			probeID: probeID,
+			body:    v1.ReportProbeResultJSONRequestBody{StatusCode: 600},
+			expectedResponse: v1.ReportProbeResult400JSONResponse{
+				Error: v1.ErrorObject{Message: "status_code must be between 0 and 599, got 600"},
+			},
+		},
+		{
+			name:    "returns 400 for a negative latency",
+			probeID: probeID,
+			body:    v1.ReportProbeResultJSONRequestBody{StatusCode: 200, LatencyMs: -1},
+			expectedResponse: v1.ReportProbeResult400JSONResponse{
+				Error: v1.ErrorObject{Message: "latency_ms must not be negative, got -1"},
+			},
+		},
+		{
+			name:    "returns 404 for an unknown probe",
+			probeID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+			body:    v1.ReportProbeResultJSONRequestBody{Success: true, StatusCode: 200},
+			expectedResponse: v1.ReportProbeResult404JSONResponse{
+				Warning: v1.WarningObject{Message: "probe with ID 00000000-0000-0000-0000-000000000001 not found"},
+			},
+		},
+		{
+			name:        "returns error when the store fails",
+			probeID:     probeID,
+			body:        v1.ReportProbeResultJSONRequestBody{Success: true, StatusCode: 200},
+			store:       &faultyStore{ProbeStorage: probestore.NewMemoryProbeStore(), getErr: errors.New("store down")},
+			expectedErr: "failed to get probe from storage: store down",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := tc.store
+			if store == nil {
+				store = newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com"})
+			}
+			server := NewServer(store)
+			req := v1.ReportProbeResultRequestObject{ProbeId: tc.probeID, Body: &tc.body}
+
+			res, err := server.ReportProbeResult(context.Background(), req)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			resp201, ok := res.(v1.ReportProbeResult201JSONResponse)
+			if !ok {
+				assert.Equal(t, tc.expectedResponse, res)
+				assert.Empty(t, server.Results.List(tc.probeID), "rejected results are not kept")
+				return
+			}
+			if tc.body.Timestamp == nil {
+				assert.WithinDuration(t, time.Now(), resp201.Timestamp, time.Minute, "the timestamp defaults to the time of receipt")
+				resp201.Timestamp = time.Time{}
+			}
+			assert.Equal(t, tc.expectedResponse, resp201)
+			assert.Len(t, server.Results.List(tc.probeID), 1)
+		})
+	}
+}
+
+func TestListProbeResults(t *testing.T) {
+	probeID := uuid.New()
+	server := NewServer(newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com"}))
+	ctx := context.Background()
+
+	t.Run("returns an empty list before any result is reported", func(t *testing.T) {
+		res, err := server.ListProbeResults(ctx, v1.ListProbeResultsRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		assert.Equal(t, v1.ListProbeResults200JSONResponse{Results: []v1.ProbeResultObject{}}, res)
+	})
+
+	t.Run("lists results newest first", func(t *testing.T) {
+		for _, code := range []int{500, 200} {
+			_, err := server.ReportProbeResult(ctx, v1.ReportProbeResultRequestObject{
+				ProbeId: probeID,
+				Body:    &v1.ReportProbeResultJSONRequestBody{Success: code == 200, StatusCode: code, LatencyMs: 10},
+			})
+			require.NoError(t, err)
+		}
+
+		res, err := server.ListProbeResults(ctx, v1.ListProbeResultsRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		resp200, ok := res.(v1.ListProbeResults200JSONResponse)
+		require.True(t, ok)
+		require.Len(t, resp200.Results, 2)
+		assert.Equal(t, 200, resp200.Results[0].StatusCode)
+		assert.Equal(t, 500, resp200.Results[1].StatusCode)
+	})
+
+	t.Run("returns 404 for an unknown probe", func(t *testing.T) {
+		unknown := uuid.New()
+		res, err := server.ListProbeResults(ctx, v1.ListProbeResultsRequestObject{ProbeId: unknown})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbeResults404JSONResponse{}, res)
+	})
+
+	t.Run("results of deleted probes are dropped by the metrics loop", func(t *testing.T) {
+		require.NoError(t, server.Store.DeleteProbeStorage(ctx, probeID))
+		server.updateProbeMetrics(ctx)
+		assert.Empty(t, server.Results.List(probeID))
+	})
+}
+
+func TestSummarizeProbeResults(t *testing.T) {
+	probeID := uuid.New()
+	server := NewServer(newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com"}))
+	ctx := context.Background()
+	summarize := func(params v1.SummarizeProbeResultsParams) v1.SummarizeProbeResultsResponseObject {
+		t.Helper()
+		res, err := server.SummarizeProbeResults(ctx, v1.SummarizeProbeResultsRequestObject{ProbeId: probeID, Params: params})
+		require.NoError(t, err)
+		return res
+	}
+
+	res := summarize(v1.SummarizeProbeResultsParams{})
+	require.IsType(t, v1.SummarizeProbeResults200JSONResponse{}, res)
+	summary := res.(v1.SummarizeProbeResults200JSONResponse)
+	assert.Equal(t, "24h", summary.Window)
+	assert.Equal(t, v1.Hour, summary.Resolution)
+	assert.Zero(t, summary.Total)
+	assert.Nil(t, summary.SuccessRatio, "no ratio without results")
+	assert.Nil(t, summary.LatencyMs)
+	assert.NotNil(t, summary.Buckets)
+
+	for i, success := range []bool{true, true, true, false} {
+		at := time.Now().Add(-time.Duration(i) * 24 * time.Hour)
+		_, err := server.ReportProbeResult(ctx, v1.ReportProbeResultRequestObject{
+			ProbeId: probeID,
+			Body:    &v1.ReportProbeResultJSONRequestBody{Success: success, StatusCode: 200, LatencyMs: 100, Timestamp: &at},
+		})
+		require.NoError(t, err)
+	}
+
+	window := "7d"
+	res = summarize(v1.SummarizeProbeResultsParams{Window: &window})
+	require.IsType(t, v1.SummarizeProbeResults200JSONResponse{}, res)
+	summary = res.(v1.SummarizeProbeResults200JSONResponse)
+	assert.Equal(t, v1.Day, summary.Resolution, "long windows are summarized per day")
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 3, summary.Successes)
+	require.NotNil(t, summary.SuccessRatio)
+	assert.InDelta(t, 0.75, *summary.SuccessRatio, 1e-9)
+	require.NotNil(t, summary.LatencyMs)
+	assert.Equal(t, 100.0, summary.LatencyMs.Max)
+	assert.Len(t, summary.Buckets, 4)
+
+	for _, params := range []v1.SummarizeProbeResultsParams{
+		{Window: new("0s")},
+		{Window: new("91d")},
+		{Window: new("a week")},
+		{Window: new("9999999999999d")},
+		{Window: new("3d"), Resolution: new(v1.Hour)},
+		{Resolution: new(v1.ResultResolution("minute"))},
+	} {
+		assert.IsType(t, v1.SummarizeProbeResults400JSONResponse{}, summarize(params))
+	}
+
+	res, err := server.SummarizeProbeResults(ctx, v1.SummarizeProbeResultsRequestObject{ProbeId: uuid.New()})
+	require.NoError(t, err)
+	assert.IsType(t, v1.SummarizeProbeResults404JSONResponse{}, res)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// probeModules are the blackbox exporter modules a probe may be run with.
+var probeModules = v1.ProbeModules()
+
+// Schedule holds the interval, timeout and module given to probes that do not
+// set them.
+type Schedule struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	Module   v1.ProbeModuleSchema
+}
+
+// DefaultSchedule returns the schedule used when none is configured.
+func DefaultSchedule() Schedule {
+	return Schedule{
+		Interval: 30 * time.Second,
+		Timeout:  10 * time.Second,
+		Module:   v1.Http2xx,
+	}
+}
+
+// Validate reports whether the schedule could be applied to a probe.
+func (s Schedule) Validate() error {
+	if s.Interval <= 0 || s.Timeout <= 0 {
+		return fmt.Errorf("probe interval and timeout must be positive, got %s and %s", s.Interval, s.Timeout)
+	}
+	if s.Timeout > s.Interval {
+		return fmt.Errorf("probe timeout %s is longer than the interval %s", s.Timeout, s.Interval)
+	}
+	if !slices.Contains(probeModules, s.Module) {
+		return fmt.Errorf("unknown probe module %q, expected one of %v", s.Module, probeModules)
+	}
+	return nil
+}
+
+// apply sets the fields the probe leaves empty from the schedule and checks
+// the result, so a probe never runs longer than its interval.
+func (s Schedule) apply(probe *v1.ProbeObject) error {
+	if probe.Interval == nil {
+		interval := s.Interval.String()
+		probe.Interval = &interval
+	}
+	if probe.Timeout == nil {
+		timeout := s.Timeout.String()
+		probe.Timeout = &timeout
+	}
+	if probe.Module == nil {
+		module := s.Module
+		probe.Module = &module
+	}
+
+	interval, err := time.ParseDuration(*probe.Interval)
+	if err != nil {
+		return inField("interval", fmt.Errorf("invalid interval %q: %w", *probe.Interval, err))
+	}
+	timeout, err := time.ParseDuration(*probe.Timeout)
+	if err != nil {
+		return inField("timeout", fmt.Errorf("invalid timeout %q: %w", *probe.Timeout, err))
+	}
+	err = Schedule{Interval: interval, Timeout: timeout, Module: *probe.Module}.Validate()
+	switch {
+	case err == nil:
+		return nil
+	case !slices.Contains(probeModules, *probe.Module):
+		return inField("module", err)
+	case interval <= 0:
+		return inField("interval", err)
+	}
+	return inField("timeout", err)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		schedule    Schedule
+		expectedErr string
+	}{
+		{name: "default schedule", schedule: DefaultSchedule()},
+		{name: "timeout equal to interval", schedule: Schedule{Interval: time.Minute, Timeout: time.Minute, Module: v1.Icmp}},
+		{
+			name:        "timeout longer than interval",
+			schedule:    Schedule{Interval: 10 * time.Second, Timeout: 30 * time.Second, Module: v1.Http2xx},
+			expectedErr: "probe timeout 30s is longer than the interval 10s",
+		},
+		{
+			name:        "zero interval",
+			schedule:    Schedule{Timeout: time.Second, Module: v1.Http2xx},
+			expectedErr: "probe interval and timeout must be positive, got 0s and 1s",
+		},
+		{
+			name:        "unknown module",
+			schedule:    Schedule{Interval: time.Minute, Timeout: time.Second, Module: "grpc"},
+			expectedErr: `unknown probe module "grpc", expected one of [http_2xx tcp icmp dns]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.schedule.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCreateProbe_Schedule(t *testing.T) {
+	oneMinute, fiveSeconds, twoMinutes := "1m", "5s", "2m"
+	tcp := v1.Tcp
+
+	testCases := []struct {
+		name             string
+		reqBody          v1.CreateProbeJSONRequestBody
+		expectedInterval string
+		expectedTimeout  string
+		expectedModule   v1.ProbeModuleSchema
+		expectedErr      string
+	}{
+		{
+			name:             "defaults fill every field",
+			reqBody:          v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"},
+			expectedInterval: "30s",
+			expectedTimeout:  "10s",
+			expectedModule:   v1.Http2xx,
+		},
+		{
+			name:             "given fields are kept",
+			reqBody:          v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com", Interval: &oneMinute, Timeout: &fiveSeconds, Module: &tcp},
+			expectedInterval: "1m",
+			expectedTimeout:  "5s",
+			expectedModule:   v1.Tcp,
+		},
+		{
+			name:        "timeout longer than the interval",
+			reqBody:     v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com", Timeout: &twoMinutes},
+			expectedErr: "probe timeout 2m0s is longer than the interval 30s",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newProbeStore(t)
+			server := NewServer(store)
+
+			res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &tc.reqBody})
+			require.NoError(t, err)
+
+			if tc.expectedErr != "" {
+				assert.Equal(t, v1.CreateProbe400JSONResponse{Error: v1.ErrorObject{Message: tc.expectedErr}}, res)
+				assert.Zero(t, storedProbes(t, store), "rejected probe must not be stored")
+				return
+			}
+			require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+			created := res.(v1.CreateProbe201JSONResponse)
+			require.NotNil(t, created.Interval)
+			require.NotNil(t, created.Timeout)
+			require.NotNil(t, created.Module)
+			assert.Equal(t, tc.expectedInterval, *created.Interval)
+			assert.Equal(t, tc.expectedTimeout, *created.Timeout)
+			assert.Equal(t, tc.expectedModule, *created.Module)
+		})
+	}
+}
+
+func TestUpdateProbe_Schedule(t *testing.T) {
+	probeID := uuid.New()
+	fiveSeconds, twoMinutes := "5s", "2m"
+	dns := v1.Dns
+
+	testCases := []struct {
+		name             string
+		reqBody          v1.UpdateProbeJSONRequestBody
+		expectedInterval string
+		expectedTimeout  string
+		expectedModule   v1.ProbeModuleSchema
+		expectedErr      string
+	}{
+		{
+			name:             "missing fields are filled from the defaults",
+			reqBody:          v1.UpdateProbeJSONRequestBody{Timeout: &fiveSeconds},
+			expectedInterval: "30s",
+			expectedTimeout:  "5s",
+			expectedModule:   v1.Http2xx,
+		},
+		{
+			name:             "module only",
+			reqBody:          v1.UpdateProbeJSONRequestBody{Module: &dns},
+			expectedInterval: "30s",
+			expectedTimeout:  "10s",
+			expectedModule:   v1.Dns,
+		},
+		{
+			name:        "timeout longer than the interval",
+			reqBody:     v1.UpdateProbeJSONRequestBody{Timeout: &twoMinutes},
+			expectedErr: "probe timeout 2m0s is longer than the interval 30s",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The stored probe predates scheduling fields.
+			store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Status: v1.Active})
+			server := NewServer(store)
+
+			res, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &tc.reqBody})
+			require.NoError(t, err)
+
+			if tc.expectedErr != "" {
+				assert.Equal(t, v1.UpdateProbe400JSONResponse{Error: v1.ErrorObject{Message: tc.expectedErr}}, res)
+				assert.Nil(t, storedProbe(t, store, probeID).Timeout, "rejected update must not be stored")
+				return
+			}
+			require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+			stored := storedProbe(t, store, probeID)
+			require.NotNil(t, stored.Interval)
+			require.NotNil(t, stored.Timeout)
+			require.NotNil(t, stored.Module)
+			assert.Equal(t, tc.expectedInterval, *stored.Interval)
+			assert.Equal(t, tc.expectedTimeout, *stored.Timeout)
+			assert.Equal(t, tc.expectedModule, *stored.Module)
+		})
+	}
+}
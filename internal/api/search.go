@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/pagetoken"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// (GET /probes/search)
+func (s Server) SearchProbes(ctx context.Context, request v1.SearchProbesRequestObject) (v1.SearchProbesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("search_probes", time.Now())
+	params := request.Params
+
+	query := probestore.SearchQuery{Text: params.Q}
+	// The query is part of the page token, which tells text from patterns.
+	cursorQuery := "text:" + params.Q
+	if params.Regex != nil && *params.Regex {
+		pattern, err := regexp.Compile("(?i)" + params.Q)
+		if err != nil {
+			metrics.RecordProbestoreError("search_probes")
+			return v1.SearchProbes400JSONResponse{Error: v1.ErrorObject{Message: fmt.Sprintf("invalid q: %v", err)}}, nil
+		}
+		query = probestore.SearchQuery{Pattern: pattern}
+		cursorQuery = "regex:" + params.Q
+	}
+
+	selector, err := s.probeSelector(ctx, params.LabelSelector)
+	if err != nil {
+		metrics.RecordProbestoreError("search_probes")
+		return v1.SearchProbes400JSONResponse{Error: v1.ErrorObject{Message: err.Error()}}, nil
+	}
+
+	cursor := pagetoken.Cursor{Selector: selector, Query: cursorQuery, Tenant: limits.TenantFromContext(ctx)}
+	if params.PageToken != nil && *params.PageToken != "" {
+		prev, err := s.PageTokens.Decode(*params.PageToken)
+		if err != nil || prev.Selector != cursor.Selector || prev.Query != cursor.Query || prev.Tenant != cursor.Tenant {
+			metrics.RecordProbestoreError("search_probes")
+			return v1.SearchProbes400JSONResponse{Error: v1.ErrorObject{
+				Message: "invalid page_token: it is malformed or was issued for a different search",
+			}}, nil
+		}
+		cursor.After = prev.After
+	}
+
+	probes, err := probestore.Search(ctx, s.Store, selector, query)
+	if err != nil {
+		metrics.RecordProbestoreError("search_probes")
+		slog.ErrorContext(ctx, "Error searching probes in storage", "error", err)
+		return nil, fmt.Errorf("failed to search probes in storage: %w", err)
+	}
+
+	// Searches are always sorted by ID, so pages are stable.
+	probes, _, cursor.After = pageProbes(probes, probeOrder{}, "", cursor.After, params.Limit)
+	var nextPageToken *string
+	if cursor.After != "" {
+		token, err := s.PageTokens.Encode(cursor)
+		if err != nil {
+			metrics.RecordProbestoreError("search_probes")
+			return nil, err
+		}
+		nextPageToken = &token
+	}
+
+	if maxItems, exceeded := s.exceedsListItems(ctx, len(probes)); exceeded {
+		metrics.RecordProbestoreError("search_probes")
+		return v1.SearchProbes413JSONResponse{Error: v1.ErrorObject{
+			Message: fmt.Sprintf("search matched %d probes, more than the %d allowed for this caller; set a limit of at most %d", len(probes), maxItems, maxItems),
+		}}, nil
+	}
+	return v1.SearchProbes200JSONResponse{Probes: probes, NextPageToken: nextPageToken}, nil
+}
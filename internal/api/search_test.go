@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchProbes(t *testing.T) {
+	ctx := context.Background()
+	store := probestore.NewMemoryProbeStore()
+	var ids []uuid.UUID
+	for _, p := range []struct {
+		url, team string
+	}{
+		{url: "https://api.a.openshiftapps.com/livez", team: "sre"},
+		{url: "https://api.b.OpenShiftApps.com/livez", team: "sre"},
+		{url: "https://console.example.com", team: "openshiftapps-migration"},
+		{url: "https://other.example.com", team: "sre"},
+	} {
+		probe := v1.ProbeObject{
+			Id:        uuid.New(),
+			StaticUrl: p.url,
+			Status:    v1.Active,
+			Labels:    &v1.LabelsSchema{baseAppLabelKey: baseAppLabelValue, "team": p.team},
+		}
+		_, err := store.CreateProbe(ctx, probe, probeURLHash(p.url))
+		require.NoError(t, err)
+		ids = append(ids, probe.Id)
+	}
+	server := NewServer(store)
+
+	search := func(t *testing.T, params v1.SearchProbesParams) []uuid.UUID {
+		t.Helper()
+		res, err := server.SearchProbes(ctx, v1.SearchProbesRequestObject{Params: params})
+		require.NoError(t, err)
+		require.IsType(t, v1.SearchProbes200JSONResponse{}, res)
+		var found []uuid.UUID
+		for _, p := range res.(v1.SearchProbes200JSONResponse).Probes {
+			found = append(found, p.Id)
+		}
+		return found
+	}
+
+	assert.ElementsMatch(t, ids[:3], search(t, v1.SearchProbesParams{Q: "openshiftapps"}), "URLs and label values, ignoring case")
+
+	regex := true
+	assert.ElementsMatch(t, ids[:2], search(t, v1.SearchProbesParams{Q: `\.openshiftapps\.com/`, Regex: &regex}))
+
+	selector := "team=sre"
+	assert.ElementsMatch(t, ids[:2], search(t, v1.SearchProbesParams{Q: "openshiftapps", LabelSelector: &selector}))
+
+	t.Run("pages by ID", func(t *testing.T) {
+		limit := 2
+		res, err := server.SearchProbes(ctx, v1.SearchProbesRequestObject{Params: v1.SearchProbesParams{Q: "openshiftapps", Limit: &limit}})
+		require.NoError(t, err)
+		first := res.(v1.SearchProbes200JSONResponse)
+		require.Len(t, first.Probes, 2)
+		require.NotNil(t, first.NextPageToken)
+		assert.Less(t, first.Probes[0].Id.String(), first.Probes[1].Id.String())
+
+		res, err = server.SearchProbes(ctx, v1.SearchProbesRequestObject{Params: v1.SearchProbesParams{Q: "openshiftapps", Limit: &limit, PageToken: first.NextPageToken}})
+		require.NoError(t, err)
+		second := res.(v1.SearchProbes200JSONResponse)
+		require.Len(t, second.Probes, 1)
+		assert.Nil(t, second.NextPageToken)
+		assert.ElementsMatch(t, ids[:3], []uuid.UUID{first.Probes[0].Id, first.Probes[1].Id, second.Probes[0].Id})
+
+		res, err = server.SearchProbes(ctx, v1.SearchProbesRequestObject{Params: v1.SearchProbesParams{Q: "example", Limit: &limit, PageToken: first.NextPageToken}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.SearchProbes400JSONResponse{}, res, "page tokens are bound to the query")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		invalid := "team in (sre"
+		for _, params := range []v1.SearchProbesParams{
+			{Q: "(", Regex: &regex},
+			{Q: "x", LabelSelector: &invalid},
+		} {
+			res, err := server.SearchProbes(ctx, v1.SearchProbesRequestObject{Params: params})
+			require.NoError(t, err)
+			assert.IsType(t, v1.SearchProbes400JSONResponse{}, res)
+		}
+	})
+
+	t.Run("storage errors", func(t *testing.T) {
+		server := NewServer(&faultyStore{ProbeStorage: newProbeStore(t), listErr: errors.New("boom")})
+		_, err := server.SearchProbes(ctx, v1.SearchProbesRequestObject{Params: v1.SearchProbesParams{Q: "x"}})
+		assert.Error(t, err)
+	})
+}
@@ -2,16 +2,36 @@ package api
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
-	"log"
+	"log/slog"
 	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/assignment"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/events"
+	"github.com/rhobs/rhobs-synthetics-api/internal/fieldselector"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
 	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/mutation"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/pagetoken"
 	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/internal/results"
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+	"github.com/rhobs/rhobs-synthetics-api/internal/secrets"
+	"github.com/rhobs/rhobs-synthetics-api/internal/targetcheck"
+	"github.com/rhobs/rhobs-synthetics-api/internal/templates"
+	"github.com/rhobs/rhobs-synthetics-api/internal/webhooks"
 	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
@@ -28,110 +48,536 @@ const (
 // Server is the main API server object.
 type Server struct {
 	Store probestore.ProbeStorage
+	// labelPolicy is shared by the copies of the Server, so SetLabelPolicy
+	// applies to all of them.
+	labelPolicy *atomic.Pointer[LabelPolicy]
+	// Features are the capability hints advertised to agents in list
+	// responses. Nil or empty means none are advertised.
+	Features v1.FeaturesSchema
+	// Assignments tracks registered agents and which probes they run.
+	Assignments *assignment.Engine
+	// PageTokens seals and opens ListProbes pagination tokens.
+	PageTokens *pagetoken.Codec
+	// Results keeps the recent run results agents report for each probe.
+	Results *results.Store
+	// ClockSkewTolerance is how far ahead of the server clock the timestamps
+	// clients supply may be. Zero means clock.DefaultSkewTolerance.
+	ClockSkewTolerance time.Duration
+	// MaxListItems caps the probes a single ListProbes response may contain,
+	// whatever the caller's tenant policy allows. Zero means no cap.
+	MaxListItems int
+	// TenantIsolation scopes requests that carry a tenant to the probes that
+	// tenant created.
+	TenantIsolation bool
+	// Schedule fills in the interval, timeout and module of probes that do
+	// not set them.
+	Schedule Schedule
+	// MonitorInterval is how often MonitorProbes lists every probe to refresh
+	// the probe metrics.
+	MonitorInterval time.Duration
+	// TerminatingGracePeriod is how long a probe may stay terminating before
+	// ReconcileTerminatingProbes removes it without its agent's confirmation.
+	TerminatingGracePeriod time.Duration
+	// StatusTransitions are the status changes UpdateProbe accepts. Nil
+	// means DefaultStatusTransitions.
+	StatusTransitions StatusTransitions
+	// Mutations change probes before they are created or updated. Nil means
+	// probes are stored as requested.
+	Mutations mutation.Chain
+	// DefaultLabels are merged into every probe created or imported.
+	DefaultLabels DefaultLabels
+	// Validators run after the built-in validators on every probe being
+	// created or updated. Nil means only the built-in ones run.
+	Validators Validators
+	// Webhooks keeps the webhook subscriptions and notifies them of probe
+	// lifecycle events.
+	Webhooks *webhooks.Notifier
+	// Events records probe lifecycle events in the store's outbox, to be
+	// published as CloudEvents. Nil publishes none.
+	Events *events.Publisher
+	// Audit records every change made to a probe.
+	Audit *audit.Log
+	// Templates are the probe templates CreateProbe can fill probes in from.
+	// NewServer keeps those created through the API in the store when it
+	// keeps records.
+	Templates *templates.Store
+	// AgentAuth mints bootstrap tokens and exchanges them for agent
+	// credentials. Nil disables both.
+	AgentAuth *agentauth.Issuer
+	// RequireAgentCredentials rejects agent requests that carry no
+	// credential. Requests carrying another agent's credential are rejected
+	// either way.
+	RequireAgentCredentials bool
+	// Notifications tells people about probes stuck in a state, found while
+	// MonitorProbes refreshes the metrics. Nil sends nothing.
+	Notifications *notify.Notifier
+	// Secrets keeps the passwords and bearer tokens of probe credentials,
+	// which probes only hold redacted. Nil rejects probes setting them.
+	Secrets secrets.Store
+	// TargetCheck checks the targets of probes created with
+	// validate=connectivity.
+	TargetCheck *targetcheck.Checker
+	// WaitDone, once closed, ends ListProbes requests waiting for a change,
+	// so they return before the server shuts down. Nil never ends them.
+	WaitDone <-chan struct{}
+	// APIKeys mints, lists and revokes API keys. Nil disables the /apikeys
+	// operations.
+	APIKeys *apikeys.Manager
+	// AdminToken is the bearer token the /apikeys operations require. Empty
+	// disables them.
+	AdminToken string
 }
 
-// NewServer creates a new API server.
+// DefaultMonitorInterval is the MonitorInterval of servers made by NewServer.
+const DefaultMonitorInterval = time.Minute
+
+// NewServer creates a new API server using the default label policy and
+// assignment settings. Its page tokens are sealed with a random key; set
+// PageTokens to share tokens between replicas.
 func NewServer(store probestore.ProbeStorage) Server {
-	return Server{
-		Store: store,
+	s := Server{
+		Store:                  store,
+		labelPolicy:            new(atomic.Pointer[LabelPolicy]),
+		Assignments:            assignment.NewEngine(store),
+		PageTokens:             pagetoken.NewRandomCodec(),
+		Results:                results.NewStore(results.DefaultRetention),
+		ClockSkewTolerance:     clock.DefaultSkewTolerance,
+		Schedule:               DefaultSchedule(),
+		MonitorInterval:        DefaultMonitorInterval,
+		TerminatingGracePeriod: DefaultTerminatingGracePeriod,
+		StatusTransitions:      DefaultStatusTransitions(),
+		Webhooks:               webhooks.NewNotifier(webhooks.Config{}),
+		Audit:                  audit.NewLog(audit.DefaultHistory),
+		Templates:              templates.NewStore(),
+		TargetCheck:            targetcheck.New(targetcheck.Config{}),
+	}
+	if records, ok := probestore.Implements[probestore.RecordStore](store); ok {
+		s.Templates.Records = records
 	}
+	s.SetLabelPolicy(DefaultLabelPolicy())
+	return s
 }
 
-// validateProtectedLabels checks if the user is trying to modify protected system labels
-func validateProtectedLabels(new, old v1.LabelsSchema) error {
-	if new == nil {
-		return nil
+// (GET /probes)
+func (s Server) ListProbes(ctx context.Context, request v1.ListProbesRequestObject) (v1.ListProbesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("list_probes", time.Now())
+	finalSelector, err := s.probeSelector(ctx, request.Params.LabelSelector)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return v1.ListProbes400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	var fieldSelector string
+	if request.Params.FieldSelector != nil {
+		fieldSelector = *request.Params.FieldSelector
 	}
+	fields, err := fieldselector.Parse(fieldSelector)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return v1.ListProbes400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("invalid field_selector: %v", err),
+			},
+		}, nil
+	}
+	finalSelector = pushDownFields(finalSelector, fields)
 
-	protectedLabels := []string{
-		baseAppLabelKey,
-		probeStatusLabelKey,
-		probeURLHashLabelKey,
-		privateProbeLabelKey,
+	returnedFields, err := parseFields(request.Params.Fields, probeFields)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return v1.ListProbes400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("invalid fields: %v", err),
+			},
+		}, nil
 	}
 
-	for _, protectedLabel := range protectedLabels {
-		newValue, newExists := new[protectedLabel]
-		oldValue, oldExists := old[protectedLabel]
+	order, err := parseProbeOrder(request.Params.SortBy, request.Params.Order)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return v1.ListProbes400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
 
-		if newExists {
-			// Disallow users from setting previously unset system-managed labels
-			if !oldExists {
-				return fmt.Errorf("creation of system-managed label '%s' is forbidden", protectedLabel)
-			}
+	timeout, err := waitTimeout(request.Params)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return v1.ListProbes400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
 
-			// Disallow users from changing the value of existing system-managed labels
-			if newValue != oldValue {
-				return fmt.Errorf("modification of system-managed label '%s' is forbidden", protectedLabel)
+	// Page tokens are bound to the query and caller they were issued for, so
+	// they cannot be replayed against another selector or tenant.
+	cursor := pagetoken.Cursor{
+		Selector: finalSelector,
+		Fields:   fieldSelector,
+		SortBy:   string(order.by),
+		Tenant:   limits.TenantFromContext(ctx),
+	}
+	if order.desc {
+		cursor.Order = string(v1.ListProbesParamsOrderDesc)
+	}
+	if request.Params.MinGeneration != nil {
+		cursor.MinGeneration = *request.Params.MinGeneration
+	}
+	if request.Params.PageToken != nil && *request.Params.PageToken != "" {
+		prev, err := s.PageTokens.Decode(*request.Params.PageToken)
+		if err != nil || prev.Selector != cursor.Selector || prev.Fields != cursor.Fields || prev.MinGeneration != cursor.MinGeneration ||
+			prev.SortBy != cursor.SortBy || prev.Order != cursor.Order || prev.Tenant != cursor.Tenant {
+			metrics.RecordProbestoreError("list_probes")
+			return v1.ListProbes400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: "invalid page_token: it is malformed or was issued for a different query",
+				},
+			}, nil
+		}
+		cursor.After, cursor.AfterKey = prev.After, prev.AfterKey
+	}
+
+	list := func() ([]v1.ProbeObject, *string, error) {
+		probes, err := s.Store.ListProbes(ctx, finalSelector)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error listing probes from storage", "error", err)
+			return nil, nil, fmt.Errorf("failed to list probes from storage: %w", err)
+		}
+		// Conditions the label selector cannot express, such as IDs and URL
+		// prefixes, are applied here.
+		if !fields.Empty() {
+			probes = slices.DeleteFunc(probes, func(p v1.ProbeObject) bool { return !fields.Matches(p) })
+		}
+		if cursor.MinGeneration > 0 {
+			probes = slices.DeleteFunc(probes, func(p v1.ProbeObject) bool { return probeGeneration(p) < cursor.MinGeneration })
+		}
+
+		if request.Params.Limit == nil && cursor.After == "" {
+			if order.explicit() {
+				order.sort(probes)
 			}
+			return probes, nil, nil
+		}
+		next := cursor
+		probes, next.AfterKey, next.After = pageProbes(probes, order, cursor.AfterKey, cursor.After, request.Params.Limit)
+		if next.After == "" {
+			return probes, nil, nil
+		}
+		token, err := s.PageTokens.Encode(next)
+		if err != nil {
+			return nil, nil, err
 		}
+		return probes, &token, nil
 	}
 
-	return nil
+	var probes []v1.ProbeObject
+	var nextPageToken *string
+	var version string
+	if timeout > 0 {
+		var since string
+		if request.Params.Version != nil {
+			since = *request.Params.Version
+		}
+		probes, nextPageToken, version, err = s.waitForChange(ctx, since, timeout, list)
+	} else {
+		probes, nextPageToken, err = list()
+		if err == nil {
+			version, err = probesVersion(probes)
+		}
+	}
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return nil, err
+	}
+
+	if maxItems, exceeded := s.exceedsListItems(ctx, len(probes)); exceeded {
+		metrics.RecordProbestoreError("list_probes")
+		return v1.ListProbes413JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("selector matched %d probes, more than the %d allowed for this caller; narrow the label_selector or set a limit of at most %d", len(probes), maxItems, maxItems),
+			},
+		}, nil
+	}
+
+	response := v1.ProbesArrayResponse{Probes: probes, NextPageToken: nextPageToken, Version: &version}
+	if len(s.Features) > 0 {
+		features := maps.Clone(s.Features)
+		response.Features = &features
+	}
+
+	tag, err := listETag(response, finalSelector, fieldSelector, returnedFields)
+	if err != nil {
+		metrics.RecordProbestoreError("list_probes")
+		return nil, fmt.Errorf("failed to compute the ETag of the probe list: %w", err)
+	}
+	if ifNoneMatch(request.Params.IfNoneMatch, tag) {
+		return v1.ListProbes304Response{Headers: v1.ListProbes304ResponseHeaders{ETag: tag}}, nil
+	}
+
+	if returnedFields != nil {
+		return sparseProbesResponse{ProbesArrayResponse: response, fields: returnedFields, etag: tag}, nil
+	}
+	return v1.ListProbes200JSONResponse{Body: response, Headers: v1.ListProbes200ResponseHeaders{ETag: tag}}, nil
 }
 
-// (GET /probes)
-func (s Server) ListProbes(ctx context.Context, request v1.ListProbesRequestObject) (v1.ListProbesResponseObject, error) {
-	defer metrics.RecordProbestoreRequest("list_probes", time.Now())
+// pageProbes sorts probes in the given order and returns those after the
+// given position, up to limit, along with the sort key and ID to resume from
+// (both "" on the last page).
+func pageProbes(probes []v1.ProbeObject, order probeOrder, afterKey, after string, limit *int) ([]v1.ProbeObject, string, string) {
+	order.sort(probes)
+	if after != "" {
+		start, found := slices.BinarySearchFunc(probes, after, func(p v1.ProbeObject, id string) int {
+			return order.compare(order.key(p), p.Id.String(), afterKey, id)
+		})
+		if found {
+			start++
+		}
+		probes = probes[start:]
+	}
+	if limit == nil || *limit <= 0 || len(probes) <= *limit {
+		return probes, "", ""
+	}
+	probes = probes[:*limit]
+	last := probes[len(probes)-1]
+	return probes, order.key(last), last.Id.String()
+}
+
+// probeGeneration returns the probe's generation, treating probes stored
+// before generations were tracked as generation 1.
+func probeGeneration(probe v1.ProbeObject) int64 {
+	if probe.Generation == nil {
+		return 1
+	}
+	return *probe.Generation
+}
+
+// probeSelector returns the label selector matching the probes the caller
+// may list, narrowed by the labelSelector the caller gave, if any.
+func (s Server) probeSelector(ctx context.Context, labelSelector *v1.LabelSelectorQueryParam) (string, error) {
 	baseSelector := fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue)
 	finalSelector := baseSelector
 
-	// If the user provided a selector, validate and append it
-	if request.Params.LabelSelector != nil && *request.Params.LabelSelector != "" {
-		userSelector := *request.Params.LabelSelector
-		// Validate the user-provided selector syntax
-		_, err := labels.Parse(userSelector)
+	// If the user provided a selector, validate and append it. The parsed form
+	// is appended rather than the raw input, so that input which parses as
+	// "everything" (e.g. only whitespace) does not leave a dangling comma.
+	if labelSelector != nil && *labelSelector != "" {
+		userSelector, err := labels.Parse(*labelSelector)
 		if err != nil {
-			metrics.RecordProbestoreError("list_probes")
-			return v1.ListProbes400JSONResponse{
-				Error: v1.ErrorObject{
-					Message: fmt.Sprintf("invalid label_selector: %v", err),
-				},
-			}, nil
+			return "", fmt.Errorf("invalid label_selector: %w", err)
+		}
+		if !userSelector.Empty() {
+			finalSelector = fmt.Sprintf("%s,%s", baseSelector, userSelector)
 		}
-		finalSelector = fmt.Sprintf("%s,%s", baseSelector, userSelector)
 	}
 
-	probes, err := s.Store.ListProbes(ctx, finalSelector)
-	if err != nil {
-		metrics.RecordProbestoreError("list_probes")
-		log.Printf("Error listing probes from storage: %v", err)
-		return nil, fmt.Errorf("failed to list probes from storage: %w", err)
+	if tenant := s.callerTenant(ctx); tenant != "" {
+		if err := validateTenant(tenant); err != nil {
+			return "", err
+		}
+		finalSelector = fmt.Sprintf("%s,%s=%s", finalSelector, tenantLabelKey, tenant)
+	}
+	return finalSelector, nil
+}
+
+// pushDownFields adds the label requirements equivalent to the field
+// selector's status and exact static_url conditions to the label selector,
+// so that stores filter on them instead of returning every probe.
+func pushDownFields(selector string, fields fieldselector.Selector) string {
+	statusValues := func(statuses []v1.StatusSchema) string {
+		values := make([]string, len(statuses))
+		for i, status := range statuses {
+			values[i] = string(status)
+		}
+		return strings.Join(values, ",")
 	}
 
-	return v1.ListProbes200JSONResponse(v1.ProbesArrayResponse{Probes: probes}), nil
+	if len(fields.Statuses) > 0 {
+		selector += fmt.Sprintf(",%s in (%s)", probeStatusLabelKey, statusValues(fields.Statuses))
+	}
+	if len(fields.ExcludedStatuses) > 0 {
+		selector += fmt.Sprintf(",%s notin (%s)", probeStatusLabelKey, statusValues(fields.ExcludedStatuses))
+	}
+	if fields.URL != "" {
+		selector += fmt.Sprintf(",%s=%s", probeURLHashLabelKey, probeURLHash(fields.URL))
+	}
+	return selector
+}
+
+// exceedsListItems reports whether a list response of n probes would break
+// the caller's tenant policy or the server-wide cap, returning the lower of
+// the two limits.
+func (s Server) exceedsListItems(ctx context.Context, n int) (int, bool) {
+	maxItems, exceeded := limits.Exceeds(ctx, n)
+	if s.MaxListItems > 0 && (maxItems == 0 || s.MaxListItems < maxItems) {
+		return s.MaxListItems, n > s.MaxListItems
+	}
+	return maxItems, exceeded
 }
 
 // (GET /probes/{probe_id})
 func (s Server) GetProbeById(ctx context.Context, request v1.GetProbeByIdRequestObject) (v1.GetProbeByIdResponseObject, error) {
 	defer metrics.RecordProbestoreRequest("get_probe", time.Now())
-	probe, err := s.Store.GetProbe(ctx, request.ProbeId)
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+	probe, err := s.getProbe(ctx, request.ProbeId)
 	if err != nil {
 		metrics.RecordProbestoreError("get_probe")
 		if k8serrors.IsNotFound(err) {
+			if tombstone := s.tombstone(ctx, request.ProbeId); tombstone != nil {
+				return v1.GetProbeById410JSONResponse{
+					Warning: v1.WarningObject{
+						Message: fmt.Sprintf("probe with ID %s was deleted at %s", request.ProbeId, clock.Format(tombstone.DeletedAt)),
+					},
+					ProbeId:   tombstone.ProbeID,
+					DeletedAt: tombstone.DeletedAt,
+				}, nil
+			}
 			return v1.GetProbeById404JSONResponse{
 				Warning: v1.WarningObject{
 					Message: fmt.Sprintf("probe with ID %s not found", request.ProbeId),
 				},
 			}, nil
 		}
-		log.Printf("Error getting probe %s from storage: %v", request.ProbeId, err)
+		slog.ErrorContext(ctx, "Error getting probe from storage", "probe_id", request.ProbeId, "error", err)
 		return nil, fmt.Errorf("failed to get probe from storage: %w", err)
 	}
 
-	return v1.GetProbeById200JSONResponse(*probe), nil
+	return v1.GetProbeById200JSONResponse{
+		Body:    *probe,
+		Headers: v1.GetProbeById200ResponseHeaders{ETag: etag(*probe)},
+	}, nil
 }
 
 // (POST /probes)
 func (s Server) CreateProbe(ctx context.Context, request v1.CreateProbeRequestObject) (v1.CreateProbeResponseObject, error) {
 	defer metrics.RecordProbestoreRequest("create_probe", time.Now())
-	urlHash := sha256.Sum256([]byte(request.Body.StaticUrl))
-	urlHashString := hex.EncodeToString(urlHash[:])[:63]
+
+	if msg := serverTimestampsSet(request.Body.CreationTimestamp, request.Body.UpdateTimestamp); msg != "" {
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: msg,
+			},
+		}, nil
+	}
+
+	probeToStore := v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: request.Body.StaticUrl,
+		Labels:    request.Body.Labels,
+		Status:    v1.Pending, // Default status to pending
+		Interval:  request.Body.Interval,
+		Timeout:   request.Body.Timeout,
+		Module:    request.Body.Module,
+		Alerting:  request.Body.Alerting,
+		Owner:     request.Body.Owner,
+	}
+	if probeToStore.Labels != nil {
+		probeLabels := maps.Clone(*probeToStore.Labels)
+		probeToStore.Labels = &probeLabels
+	}
+	if err := applyProbeType(&probeToStore, *request.Body); err != nil {
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if err := s.applyTemplate(ctx, &probeToStore, *request.Body); err != nil {
+		if isFailed(err) {
+			metrics.RecordProbestoreError("create_probe")
+			slog.ErrorContext(ctx, "Error getting probe template", "error", err)
+			return nil, fmt.Errorf("failed to get probe template: %w", err)
+		}
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if err := s.DefaultLabels.apply(&probeToStore); err != nil {
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	// Hooks run before the URL is hashed, so a normalized URL is the one
+	// checked for duplicates.
+	if err := s.Mutations.Mutate(ctx, &probeToStore); err != nil {
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if request.Body.AdditionalUrls != nil {
+		if err := setAdditionalURLs(&probeToStore, *request.Body.AdditionalUrls); err != nil {
+			return v1.CreateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+	if request.Body.Regions != nil {
+		if err := setRegions(&probeToStore, *request.Body.Regions); err != nil {
+			return v1.CreateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+
+	if err := s.Schedule.apply(&probeToStore); err != nil {
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if request.Body.Assertions != nil {
+		setAssertions(&probeToStore, *request.Body.Assertions)
+	}
+
+	review := ProbeReview{Probe: probeToStore, Tenant: s.callerTenant(ctx)}
+	if request.Body.Labels != nil {
+		review.Labels = *request.Body.Labels
+	}
+	if err := s.validators().Validate(ctx, review); err != nil {
+		switch {
+		case isFailed(err):
+			metrics.RecordProbestoreError("create_probe")
+			slog.ErrorContext(ctx, "Error validating probe", "error", err)
+			return nil, fmt.Errorf("failed to validate probe: %w", err)
+		case isForbidden(err):
+			return v1.CreateProbe403JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+		return v1.CreateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	urlHash := probestore.URLHash(probeToStore.StaticUrl)
+	probeToStore.UrlHash = &urlHash
+	urlHashString := probestore.URLHashLabel(urlHash)
 
 	exists, err := s.Store.ProbeWithURLHashExists(ctx, urlHashString)
 	if err != nil {
 		metrics.RecordProbestoreError("create_probe")
-		log.Printf("Error checking for existing probes with URL hash %s: %v", urlHashString, err)
+		slog.ErrorContext(ctx, "Error checking for existing probes", "url_hash", urlHashString, "error", err)
 		return nil, fmt.Errorf("failed to check for existing probes: %w", err)
 	}
 
@@ -139,38 +585,170 @@ func (s Server) CreateProbe(ctx context.Context, request v1.CreateProbeRequestOb
 		metrics.RecordProbestoreError("create_probe")
 		return v1.CreateProbe409JSONResponse{
 			Error: v1.ErrorObject{
-				Message: fmt.Sprintf("a probe for static_url %q already exists", request.Body.StaticUrl),
+				Message:           fmt.Sprintf("a probe for static_url %q already exists", probeToStore.StaticUrl),
+				RetryAfterSeconds: retryafter.Seconds(http.StatusConflict),
 			},
 		}, nil
 	}
 
-	probeToStore := v1.ProbeObject{
-		Id:        uuid.New(),
-		StaticUrl: request.Body.StaticUrl,
-		Labels:    request.Body.Labels,
-		Status:    v1.Pending, // Default status to pending
+	var authValues map[string]string
+	if request.Body.Auth != nil {
+		probeToStore.Auth, authValues, err = s.splitAuth(*request.Body.Auth, nil)
+		if err != nil {
+			return v1.CreateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+	if tenant := review.Tenant; tenant != "" {
+		probeLabels := v1.LabelsSchema{}
+		if probeToStore.Labels != nil {
+			probeLabels = maps.Clone(*probeToStore.Labels)
+		}
+		probeLabels[tenantLabelKey] = tenant
+		probeToStore.Labels = &probeLabels
+	}
+	if groupID := probeGroupFromContext(ctx); groupID != "" {
+		probeLabels := v1.LabelsSchema{}
+		if probeToStore.Labels != nil {
+			probeLabels = maps.Clone(*probeToStore.Labels)
+		}
+		probeLabels[groupLabelKey] = groupID
+		probeToStore.Labels = &probeLabels
+	}
+	ctx = logging.With(ctx, "probe_id", probeToStore.Id)
+
+	if request.Params.Validate != nil && *request.Params.Validate == v1.Connectivity {
+		for _, target := range targetURLs(probeToStore) {
+			if failures := s.TargetCheck.Check(ctx, target); len(failures) > 0 {
+				return v1.CreateProbe422JSONResponse{
+					Error: v1.ErrorObject{
+						Message:            fmt.Sprintf("probe target %q failed validation", target),
+						ValidationFailures: &failures,
+					},
+				}, nil
+			}
+		}
+	}
+
+	if isDryRun(request.Params.DryRun) {
+		return v1.CreateProbe201JSONResponse(probeToStore), nil
 	}
 
 	createdProbe, err := s.Store.CreateProbe(ctx, probeToStore, urlHashString)
 	if err != nil {
 		metrics.RecordProbestoreError("create_probe")
-		log.Printf("Error creating probe %s: %v", probeToStore.Id, err)
+		slog.ErrorContext(ctx, "Error creating probe", "probe_id", probeToStore.Id, "error", err)
 		return v1.CreateProbe500JSONResponse{
 			Error: v1.ErrorObject{
 				Message: fmt.Sprintf("failed to create probe: %v", err),
 			},
 		}, nil
 	}
+	// The probe is stored first, so values written before a listing of the
+	// probes belong to a probe in it and are not pruned.
+	if err := s.putAuth(ctx, createdProbe.Id, authValues); err != nil {
+		metrics.RecordProbestoreError("create_probe")
+		slog.ErrorContext(ctx, "Error storing probe secret", "error", err)
+		if err := s.Store.DeleteProbeStorage(ctx, createdProbe.Id); err != nil {
+			slog.ErrorContext(ctx, "Error removing probe whose secret was not stored", "error", err)
+		}
+		return v1.CreateProbe500JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("failed to store probe credentials: %v", err),
+			},
+		}, nil
+	}
+	s.Audit.Record(ctx, v1.CreateProbe, createdProbe.Id, nil, createdProbe)
+	s.Webhooks.Notify(ctx, v1.ProbeCreated, *createdProbe, nil)
+	s.Events.Publish(ctx, v1.ProbeCreated, *createdProbe, nil)
 
 	return v1.CreateProbe201JSONResponse(*createdProbe), nil
 }
 
+// probeURLHash returns the value of the static-url-hash label for a probe
+// URL: the hex SHA-256 of the URL, truncated to the 63 characters allowed in
+// a Kubernetes label value.
+func probeURLHash(staticURL string) string {
+	return probestore.URLHashLabel(probestore.URLHash(staticURL))
+}
+
+// etag returns the ETag header value for a probe: its resource version,
+// quoted.
+func etag(probe v1.ProbeObject) string {
+	if probe.ResourceVersion == nil {
+		return ""
+	}
+	return `"` + *probe.ResourceVersion + `"`
+}
+
+// isDryRun reports whether the dry_run parameter asks for the request to be
+// validated without changing anything.
+func isDryRun(dryRun *v1.DryRunQueryParam) bool {
+	return dryRun != nil && *dryRun
+}
+
+// serverTimestampsSet returns why a request setting the timestamps the store
+// maintains is rejected, or "" if it sets neither.
+func serverTimestampsSet(creationTimestamp, updateTimestamp *time.Time) string {
+	switch {
+	case creationTimestamp != nil:
+		return "creation_timestamp is set by the server and cannot be given"
+	case updateTimestamp != nil:
+		return "update_timestamp is set by the server and cannot be given"
+	}
+	return ""
+}
+
+// ifMatchVersion returns the resource version an If-Match header asks for.
+// ok is false when the header is absent or "*", which match any version.
+func ifMatchVersion(ifMatch *string) (version string, ok bool) {
+	if ifMatch == nil {
+		return "", false
+	}
+	tag := strings.TrimSpace(*ifMatch)
+	if tag == "" || tag == "*" {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(tag, `"`), `"`), true
+}
+
+// ifMatchMismatch returns the error message for a 412 when the probe is not
+// at the version If-Match asks for, and "" when it is.
+func ifMatchMismatch(probe v1.ProbeObject, version string) string {
+	if probe.ResourceVersion != nil && *probe.ResourceVersion == version {
+		return ""
+	}
+	return fmt.Sprintf("probe with ID %s has ETag %s, which does not match If-Match %q", probe.Id, etag(probe), `"`+version+`"`)
+}
+
+// concurrentChangeError is the error body for a 409 when a conditional write
+// lost a race with another writer.
+func concurrentChangeError(probeID uuid.UUID) v1.ErrorObject {
+	return v1.ErrorObject{
+		Message:           fmt.Sprintf("probe with ID %s changed while the request was applied; get it again and retry", probeID),
+		RetryAfterSeconds: retryafter.Seconds(http.StatusConflict),
+	}
+}
+
 // (PATCH /probes/{probe_id})
 func (s Server) UpdateProbe(ctx context.Context, request v1.UpdateProbeRequestObject) (v1.UpdateProbeResponseObject, error) {
 	defer metrics.RecordProbestoreRequest("update_probe", time.Now())
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+	version, conditional := ifMatchVersion(request.Params.IfMatch)
+
+	if msg := serverTimestampsSet(request.Body.CreationTimestamp, request.Body.UpdateTimestamp); msg != "" {
+		return v1.UpdateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: msg,
+			},
+		}, nil
+	}
 
 	// First, get the existing probe.
-	existingProbe, err := s.Store.GetProbe(ctx, request.ProbeId)
+	existingProbe, err := s.getProbe(ctx, request.ProbeId)
 	if err != nil {
 		metrics.RecordProbestoreError("update_probe")
 		if k8serrors.IsNotFound(err) {
@@ -180,64 +758,317 @@ func (s Server) UpdateProbe(ctx context.Context, request v1.UpdateProbeRequestOb
 				},
 			}, nil
 		}
-		log.Printf("Error getting probe %s from storage for update: %v", request.ProbeId, err)
+		slog.ErrorContext(ctx, "Error getting probe from storage for update", "probe_id", request.ProbeId, "error", err)
 		return nil, fmt.Errorf("failed to get probe from storage for update: %w", err)
 	}
+	previousStatus := existingProbe.Status
+	before := snapshot(*existingProbe)
+
+	// The store re-checks the version when writing, so a change made after
+	// this read is reported as a 409 rather than overwritten.
+	if conditional {
+		if msg := ifMatchMismatch(*existingProbe, version); msg != "" {
+			return v1.UpdateProbe412JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+		}
+		ctx = probestore.WithResourceVersion(ctx, version)
+	}
+
+	if request.Body.Status != nil {
+		if !slices.Contains(probeStatuses, *request.Body.Status) {
+			return v1.UpdateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: fmt.Sprintf("invalid status %q, expected one of %v", *request.Body.Status, probeStatuses),
+				},
+			}, nil
+		}
+		transitions := s.StatusTransitions
+		if transitions == nil {
+			transitions = DefaultStatusTransitions()
+		}
+		if !transitions.allows(existingProbe.Status, *request.Body.Status) {
+			allowed := append([]v1.StatusSchema{}, transitions[existingProbe.Status]...)
+			return v1.UpdateProbe409JSONResponse{
+				Error: v1.ErrorObject{
+					Message:         fmt.Sprintf("probe with ID %s cannot move from status %s to %s", request.ProbeId, existingProbe.Status, *request.Body.Status),
+					AllowedStatuses: &allowed,
+				},
+			}, nil
+		}
+	}
 
-	// Validate that protected labels are not being modified - return 403 if they are
-	// Note: Status field modifications are allowed (RMO can set terminating, agents can set active/failed)
+	// The labels are checked against the policy by the validators, once the
+	// rest of the update is applied. They are merged into a copy, so the
+	// probe the store returned keeps those the validators compare against.
 	if request.Body.Labels != nil {
-		if existingProbe.Labels == nil {
-			existingProbe.Labels = &v1.LabelsSchema{}
+		probeLabels := v1.LabelsSchema{}
+		if existingProbe.Labels != nil {
+			probeLabels = maps.Clone(*existingProbe.Labels)
 		}
+		maps.Copy(probeLabels, *request.Body.Labels)
+		existingProbe.Labels = &probeLabels
+	}
 
-		err := validateProtectedLabels(*request.Body.Labels, *existingProbe.Labels)
-		if err != nil {
-			response := v1.UpdateProbe403JSONResponse{
+	// Scheduling fields the probe has never had are filled in alongside the
+	// ones being changed, so the timeout can be checked against the interval.
+	if request.Body.Interval != nil || request.Body.Timeout != nil || request.Body.Module != nil {
+		if request.Body.Interval != nil {
+			existingProbe.Interval = request.Body.Interval
+		}
+		if request.Body.Timeout != nil {
+			existingProbe.Timeout = request.Body.Timeout
+		}
+		if request.Body.Module != nil {
+			existingProbe.Module = request.Body.Module
+		}
+		if err := s.Schedule.apply(existingProbe); err != nil {
+			return v1.UpdateProbe400JSONResponse{
 				Error: v1.ErrorObject{
 					Message: err.Error(),
 				},
-			}
-			return response, nil
+			}, nil
 		}
+	}
 
-		maps.Copy(*existingProbe.Labels, *request.Body.Labels)
+	if err := updateProbeType(existingProbe, *request.Body); err != nil {
+		return v1.UpdateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if err := checkProbeTypeModule(*existingProbe); err != nil {
+		return v1.UpdateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	// Alerting, owner and assertions are checked by the validators.
+	if request.Body.Alerting != nil {
+		existingProbe.Alerting = request.Body.Alerting
+	}
+	if request.Body.Owner != nil {
+		existingProbe.Owner = request.Body.Owner
+		if *request.Body.Owner == (v1.OwnerSchema{}) {
+			existingProbe.Owner = nil
+		}
+	}
+	if request.Body.Assertions != nil {
+		setAssertions(existingProbe, *request.Body.Assertions)
+	}
+
+	// URLs are changed first, so agents may report on those just added.
+	if request.Body.AdditionalUrls != nil {
+		if err := setAdditionalURLs(existingProbe, *request.Body.AdditionalUrls); err != nil {
+			return v1.UpdateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+	if request.Body.Regions != nil {
+		if err := setRegions(existingProbe, *request.Body.Regions); err != nil {
+			return v1.UpdateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+	if request.Body.TargetStatuses != nil {
+		if err := setTargetStatuses(existingProbe, *request.Body.TargetStatuses); err != nil {
+			return v1.UpdateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+
+	var authValues map[string]string
+	if request.Body.Auth != nil {
+		current, err := s.storedAuth(ctx, request.ProbeId)
+		if err != nil {
+			metrics.RecordProbestoreError("update_probe")
+			slog.ErrorContext(ctx, "Error getting probe secret for update", "error", err)
+			return nil, fmt.Errorf("failed to get probe secret for update: %w", err)
+		}
+		existingProbe.Auth, authValues, err = s.splitAuth(*request.Body.Auth, current)
+		if err != nil {
+			return v1.UpdateProbe400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
 	}
 
 	// Now, update the fields from the request.
+	status := existingProbe.Status
+	if request.Body.Status != nil {
+		status = *request.Body.Status
+	}
+	review := ProbeReview{Probe: *existingProbe, Existing: before, Tenant: s.callerTenant(ctx)}
+	review.Probe.Status = status
+	if request.Body.Labels != nil {
+		review.Labels = *request.Body.Labels
+	}
+	if err := s.validators().Validate(ctx, review); err != nil {
+		switch {
+		case isFailed(err):
+			metrics.RecordProbestoreError("update_probe")
+			slog.ErrorContext(ctx, "Error validating probe", "error", err)
+			return nil, fmt.Errorf("failed to validate probe: %w", err)
+		case isForbidden(err):
+			return v1.UpdateProbe403JSONResponse{
+				Error: v1.ErrorObject{
+					Message: err.Error(),
+				},
+			}, nil
+		}
+		return v1.UpdateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if err := validateStatusDetails(status, request.Body.StatusReason, request.Body.StatusMessage); err != nil {
+		return v1.UpdateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if request.Body.Paused != nil {
+		if *request.Body.Paused && !isPaused(*existingProbe) && status == v1.Terminating {
+			return v1.UpdateProbe409JSONResponse{
+				Error: v1.ErrorObject{
+					Message: fmt.Sprintf("probe with ID %s is terminating and cannot be paused", request.ProbeId),
+				},
+			}, nil
+		}
+		setPausedField(existingProbe, *request.Body.Paused)
+	}
+	// Details replace those of the probe as a whole, and a new status
+	// without any clears them.
+	if request.Body.StatusReason != nil || request.Body.StatusMessage != nil || status != previousStatus {
+		existingProbe.StatusReason = request.Body.StatusReason
+		existingProbe.StatusMessage = request.Body.StatusMessage
+	}
 	if request.Body.Status != nil {
 		existingProbe.Status = *request.Body.Status
 
 		// If status is being set to "deleted", actually delete the probe
-		if *request.Body.Status == v1.Deleted {
+		if *request.Body.Status == v1.Deleted && !isDryRun(request.Params.DryRun) {
 			err := s.Store.DeleteProbeStorage(ctx, request.ProbeId)
 			if err != nil {
-				log.Printf("Error deleting probe %s from storage: %v", request.ProbeId, err)
+				if k8serrors.IsConflict(err) {
+					return v1.UpdateProbe409JSONResponse{Error: concurrentChangeError(request.ProbeId)}, nil
+				}
+				slog.ErrorContext(ctx, "Error deleting probe from storage", "probe_id", request.ProbeId, "error", err)
 				return nil, fmt.Errorf("failed to delete probe from storage: %w", err)
 			}
+			s.Audit.Record(ctx, v1.UpdateProbe, request.ProbeId, before, nil)
+			s.Webhooks.Notify(ctx, v1.ProbeDeleted, *existingProbe, &previousStatus)
+			s.Events.Publish(ctx, v1.ProbeDeleted, *existingProbe, &previousStatus)
 
 			// Return the probe as it was before deletion
-			return v1.UpdateProbe200JSONResponse(*existingProbe), nil
+			return v1.UpdateProbe200JSONResponse{
+				Body:    *existingProbe,
+				Headers: v1.UpdateProbe200ResponseHeaders{ETag: etag(*existingProbe)},
+			}, nil
 		}
 	}
 
+	// The static URL cannot change after creation, so hooks may only change
+	// the rest of the probe.
+	staticURL := existingProbe.StaticUrl
+	if err := s.Mutations.Mutate(ctx, existingProbe); err != nil {
+		return v1.UpdateProbe400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	existingProbe.StaticUrl = staticURL
+
+	if isDryRun(request.Params.DryRun) {
+		return v1.UpdateProbe200JSONResponse{
+			Body:    *existingProbe,
+			Headers: v1.UpdateProbe200ResponseHeaders{ETag: etag(*existingProbe)},
+		}, nil
+	}
+
 	// Persist the updated probe (for non-deleted status changes).
-	updatedProbe, err := s.Store.UpdateProbe(ctx, *existingProbe)
+	updatedProbe, err := s.Store.UpdateProbe(s.withStatusChange(ctx, statusChangeReason(request.Body.StatusReason, request.Body.StatusMessage)), *existingProbe)
 	if err != nil {
 		metrics.RecordProbestoreError("update_probe")
-		log.Printf("Error updating probe %s in storage: %v", request.ProbeId, err)
+		if k8serrors.IsConflict(err) {
+			return v1.UpdateProbe409JSONResponse{Error: concurrentChangeError(request.ProbeId)}, nil
+		}
+		if k8serrors.IsAlreadyExists(err) {
+			return v1.UpdateProbe409JSONResponse{
+				Error: v1.ErrorObject{
+					Message: fmt.Sprintf("another live probe already monitors %s", existingProbe.StaticUrl),
+				},
+			}, nil
+		}
+		slog.ErrorContext(ctx, "Error updating probe in storage", "probe_id", request.ProbeId, "error", err)
 		return nil, fmt.Errorf("failed to update probe in storage: %w", err)
 	}
+	if request.Body.Auth != nil {
+		if err := s.putAuth(ctx, request.ProbeId, authValues); err != nil {
+			metrics.RecordProbestoreError("update_probe")
+			slog.ErrorContext(ctx, "Error storing probe secret", "error", err)
+			return nil, fmt.Errorf("failed to store probe credentials: %w", err)
+		}
+	}
+	s.Audit.Record(ctx, v1.UpdateProbe, request.ProbeId, before, updatedProbe)
+	if updatedProbe.Status != previousStatus {
+		s.Webhooks.Notify(ctx, v1.ProbeStatusChanged, *updatedProbe, &previousStatus)
+		s.Events.Publish(ctx, v1.ProbeStatusChanged, *updatedProbe, &previousStatus)
+	}
 
-	return v1.UpdateProbe200JSONResponse(*updatedProbe), nil
+	return v1.UpdateProbe200JSONResponse{
+		Body:    *updatedProbe,
+		Headers: v1.UpdateProbe200ResponseHeaders{ETag: etag(*updatedProbe)},
+	}, nil
 }
 
 // (DELETE /probes/{probe_id})
 func (s Server) DeleteProbe(ctx context.Context, request v1.DeleteProbeRequestObject) (v1.DeleteProbeResponseObject, error) {
 	defer metrics.RecordProbestoreRequest("delete_probe", time.Now())
-	err := s.Store.DeleteProbe(ctx, request.ProbeId)
+	ctx = logging.With(ctx, "probe_id", request.ProbeId)
+
+	// Read the probe first: its owner and ETag are checked, and the audit log
+	// and webhooks are told what the deletion did. A stale ETag gets a 412;
+	// the store re-checks the version, turning a later change into a 409.
+	version, conditional := ifMatchVersion(request.Params.IfMatch)
+	probe, err := s.getProbe(ctx, request.ProbeId)
+	if err == nil && conditional {
+		if msg := ifMatchMismatch(*probe, version); msg != "" {
+			return v1.DeleteProbe412JSONResponse{Error: v1.ErrorObject{Message: msg}}, nil
+		}
+		ctx = probestore.WithResourceVersion(ctx, version)
+	} else if err != nil && !k8serrors.IsNotFound(err) {
+		metrics.RecordProbestoreError("delete_probe")
+		slog.ErrorContext(ctx, "Error getting probe from storage for delete", "error", err)
+		return nil, fmt.Errorf("failed to get probe from storage for delete: %w", err)
+	}
+
+	if err == nil && isDryRun(request.Params.DryRun) {
+		return v1.DeleteProbe204Response{}, nil
+	}
+	if err == nil {
+		err = s.Store.DeleteProbe(s.withStatusChange(ctx, ""), request.ProbeId)
+	}
 	if err != nil {
 		metrics.RecordProbestoreError("delete_probe")
+		if k8serrors.IsConflict(err) {
+			return v1.DeleteProbe409JSONResponse{Error: concurrentChangeError(request.ProbeId)}, nil
+		}
 		if k8serrors.IsNotFound(err) {
 			return v1.DeleteProbe404JSONResponse{
 				Warning: v1.WarningObject{
@@ -245,16 +1076,44 @@ func (s Server) DeleteProbe(ctx context.Context, request v1.DeleteProbeRequestOb
 				},
 			}, nil
 		}
-		log.Printf("Error deleting probe %s from storage: %v", request.ProbeId, err)
+		slog.ErrorContext(ctx, "Error deleting probe from storage", "probe_id", request.ProbeId, "error", err)
 		return nil, fmt.Errorf("failed to delete probe from storage: %w", err)
 	}
 
+	s.recordDeletion(ctx, *probe)
+
 	return v1.DeleteProbe204Response{}, nil
 }
 
+// recordDeletion tells the audit log and webhooks what deleting the probe
+// did: stores remove some probes right away and make others terminating
+// until their agent confirms the cleanup.
+func (s Server) recordDeletion(ctx context.Context, probe v1.ProbeObject) {
+	previousStatus := probe.Status
+	current, err := s.Store.GetProbe(ctx, probe.Id)
+	switch {
+	case k8serrors.IsNotFound(err):
+		s.Audit.Record(ctx, v1.DeleteProbe, probe.Id, &probe, nil)
+		s.Webhooks.Notify(ctx, v1.ProbeDeleted, probe, &previousStatus)
+		s.Events.Publish(ctx, v1.ProbeDeleted, probe, &previousStatus)
+	case err != nil:
+		// The deletion went through, so it is recorded even though its
+		// outcome is unknown.
+		slog.WarnContext(ctx, "Error reading deleted probe", "error", err)
+		s.Audit.Record(ctx, v1.DeleteProbe, probe.Id, &probe, nil)
+	default:
+		s.Audit.Record(ctx, v1.DeleteProbe, probe.Id, &probe, current)
+		if current.Status != previousStatus {
+			s.Webhooks.Notify(ctx, v1.ProbeStatusChanged, *current, &previousStatus)
+			s.Events.Publish(ctx, v1.ProbeStatusChanged, *current, &previousStatus)
+		}
+	}
+}
+
 func (s Server) MonitorProbes(ctx context.Context) {
-	log.Printf("Starting probe monitoring")
-	ticker := time.NewTicker(1 * time.Minute)
+	ctx = logging.With(ctx, "operation", "monitor_probes")
+	slog.InfoContext(ctx, "Starting probe monitoring", "interval", s.MonitorInterval)
+	ticker := time.NewTicker(s.MonitorInterval)
 	defer ticker.Stop()
 	s.updateProbeMetrics(ctx)
 	for {
@@ -262,22 +1121,44 @@ func (s Server) MonitorProbes(ctx context.Context) {
 		case <-ticker.C:
 			s.updateProbeMetrics(ctx)
 		case <-ctx.Done():
-			log.Printf("Stopping probe monitoring")
+			slog.InfoContext(ctx, "Stopping probe monitoring")
 			return
 		}
 	}
 }
 
 func (s Server) updateProbeMetrics(ctx context.Context) {
+	start := time.Now()
 	probes, err := s.Store.ListProbes(ctx, "")
 	if err != nil {
-		log.Printf("error listing probes for metrics: %v", err)
+		metrics.RecordProbeInventoryRefresh(start, 0, err)
+		slog.ErrorContext(ctx, "Error listing probes for metrics", "error", err)
 		return
 	}
+	defer metrics.RecordProbeInventoryRefresh(start, len(probes), nil)
+
 	// Group probes by state and private label
 	counts := make(map[string]map[string]int)
+	tenantCounts := make(map[string]int)
+	regionCounts := make(map[string]int)
+	existing := make(map[uuid.UUID]bool, len(probes))
+	var statuses []metrics.ProbeStatus
 	for _, probe := range probes {
-		state := string(probe.Status)
+		existing[probe.Id] = true
+		if status, ok := probeStatusMetric(probe); ok {
+			statuses = append(statuses, status)
+		}
+		if probe.Labels != nil {
+			if tenant := (*probe.Labels)[tenantLabelKey]; tenant != "" {
+				tenantCounts[tenant]++
+			}
+		}
+		if probe.Regions != nil {
+			for _, region := range *probe.Regions {
+				regionCounts[region]++
+			}
+		}
+		state := statusMetricLabel(probe.Status)
 		if _, ok := counts[state]; !ok {
 			counts[state] = make(map[string]int)
 		}
@@ -294,6 +1175,17 @@ func (s Server) updateProbeMetrics(ctx context.Context) {
 			metrics.SetProbesTotal(state, private, count)
 		}
 	}
+	metrics.SetTenantProbes(tenantCounts)
+	metrics.SetRegionProbes(regionCounts)
+	metrics.SetProbeStatusInfo(statuses)
+	s.notifyProblems(ctx, probes, time.Now())
+
+	// Forget the results and secrets of deleted probes.
+	s.Results.Retain(existing)
+	s.pruneSecrets(ctx, existing, start)
+	if ratio, ok := s.Results.SuccessRatio(); ok {
+		metrics.SetProbeSuccessRatio(ratio)
+	}
 }
 
 // GarbageCollectProbes runs a periodic loop that deletes stale probe ConfigMaps.
@@ -302,23 +1194,24 @@ func (s Server) updateProbeMetrics(ctx context.Context) {
 // clusters, so a stale timestamp means the cluster was deleted.
 func (s Server) GarbageCollectProbes(ctx context.Context) {
 	const gcInterval = 15 * time.Minute
-	log.Printf("Starting probe garbage collection (interval: %s)", gcInterval)
+	ctx = logging.With(ctx, "operation", "garbage_collection")
+	slog.InfoContext(ctx, "Starting probe garbage collection", "interval", gcInterval)
 	ticker := time.NewTicker(gcInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			deleted, err := s.Store.GarbageCollectStaleProbes(ctx)
+			deleted, err := s.Store.GarbageCollectStaleProbes(probestore.WithStatusChange(ctx, audit.SystemActor, ""))
 			if err != nil {
-				log.Printf("GC: error during garbage collection: %v", err)
+				slog.ErrorContext(ctx, "Garbage collection failed", "error", err)
 				continue
 			}
 			if deleted > 0 {
-				log.Printf("GC: deleted %d stale probe(s)", deleted)
+				slog.InfoContext(ctx, "Garbage collection deleted stale probes", "count", deleted)
 			}
 		case <-ctx.Done():
-			log.Printf("Stopping probe garbage collection")
+			slog.InfoContext(ctx, "Stopping probe garbage collection")
 			return
 		}
 	}
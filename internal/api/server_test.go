@@ -1,183 +1,284 @@
 package api
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/mutation"
 	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
 	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-// mockProbeStore is a mock implementation of the ProbeStorage interface for testing.
-type mockProbeStore struct {
-	probes                    map[uuid.UUID]v1.ProbeObject
-	getProbeErr               error
-	updateProbeErr            error
-	listProbesErr             error
-	createProbeErr            error
-	deleteProbeErr            error
-	probeWithURLHashExistsErr error
-	urlHashes                 map[string]bool
+// newProbeStore returns a memory store holding the probes, created as the
+// API creates them, so the store adds its system labels and versions. Probes
+// without a status are stored pending.
+func newProbeStore(t *testing.T, probes ...v1.ProbeObject) *probestore.MemoryProbeStore {
+	t.Helper()
+	store := probestore.NewMemoryProbeStore()
+	for _, probe := range probes {
+		if probe.Status == "" {
+			probe.Status = v1.Pending
+		}
+		_, err := store.CreateProbe(context.Background(), probe, probestore.URLHashLabel(probestore.URLHash(probe.StaticUrl)))
+		require.NoError(t, err)
+	}
+	return store
 }
 
-// Enforce that mockProbeStore implements the ProbeStorage interface.
-var _ probestore.ProbeStorage = (*mockProbeStore)(nil)
-
-func (m *mockProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
-	if m.getProbeErr != nil {
-		return nil, m.getProbeErr
-	}
-	probe, ok := m.probes[probeID]
-	if !ok {
-		return nil, k8serrors.NewNotFound(schema.GroupResource{}, probeID.String())
+// restoredProbeStore returns a memory store holding the probes exactly as
+// given, as restored from a snapshot, for probes CreateProbe would not take
+// as they are: with timestamps set, terminating, or without a generation. Like
+// the stores, it labels them with the app and their status.
+func restoredProbeStore(t *testing.T, probes ...v1.ProbeObject) *probestore.MemoryProbeStore {
+	t.Helper()
+	for i, probe := range probes {
+		labels := v1.LabelsSchema{baseAppLabelKey: baseAppLabelValue, probeStatusLabelKey: string(probe.Status)}
+		if probe.Labels != nil {
+			maps.Copy(labels, *probe.Labels)
+		}
+		probes[i].Labels = &labels
 	}
-	return &probe, nil
+	snapshot, err := json.Marshal(map[string]any{"probes": probes})
+	require.NoError(t, err)
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(snapshotFile, snapshot, 0o644))
+	store, err := probestore.OpenMemoryProbeStore(probestore.MemoryConfig{SnapshotFile: snapshotFile})
+	require.NoError(t, err)
+	return store
 }
 
-func (m *mockProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
-	if m.updateProbeErr != nil {
-		return nil, m.updateProbeErr
-	}
-	if _, ok := m.probes[probe.Id]; !ok {
-		return nil, k8serrors.NewNotFound(schema.GroupResource{}, probe.Id.String())
-	}
-	m.probes[probe.Id] = probe
-	return &probe, nil
+// storedProbe returns the probe as the store holds it.
+func storedProbe(t *testing.T, store probestore.ProbeStorage, id uuid.UUID) v1.ProbeObject {
+	t.Helper()
+	probe, err := store.GetProbe(context.Background(), id)
+	require.NoError(t, err)
+	return *probe
 }
 
-func (m *mockProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
-	if m.listProbesErr != nil {
-		return nil, m.listProbesErr
-	}
-	var res []v1.ProbeObject
-	for _, p := range m.probes {
-		res = append(res, p)
-	}
-	return res, nil
+// storedProbes returns the number of probes the store holds.
+func storedProbes(t *testing.T, store probestore.ProbeStorage) int {
+	t.Helper()
+	probes, err := store.ListProbes(context.Background(), "")
+	require.NoError(t, err)
+	return len(probes)
 }
 
-func (m *mockProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
-	if m.createProbeErr != nil {
-		return nil, m.createProbeErr
+// probeIDs returns the IDs of the probes, in order.
+func probeIDs(probes []v1.ProbeObject) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(probes))
+	for _, probe := range probes {
+		ids = append(ids, probe.Id)
 	}
-	if m.probes == nil {
-		m.probes = make(map[uuid.UUID]v1.ProbeObject)
+	return ids
+}
+
+// assertProbe checks the ID, URL and status of the probe, and that it has
+// the expected labels, next to those the store adds.
+func assertProbe(t *testing.T, expected, actual v1.ProbeObject) {
+	t.Helper()
+	assert.Equal(t, expected.Id, actual.Id)
+	assert.Equal(t, expected.StaticUrl, actual.StaticUrl)
+	assert.Equal(t, expected.Status, actual.Status)
+	if expected.Labels != nil {
+		require.NotNil(t, actual.Labels)
+		for key, value := range *expected.Labels {
+			assert.Equal(t, value, (*actual.Labels)[key], "label %s", key)
+		}
 	}
-	if m.urlHashes == nil {
-		m.urlHashes = make(map[string]bool)
+}
+
+// faultyStore wraps a probe store and fails the calls whose error is set,
+// for the tests of store failures.
+type faultyStore struct {
+	probestore.ProbeStorage
+	getErr, updateErr, listErr, createErr, deleteErr, urlHashErr error
+}
+
+func (f *faultyStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
 	}
-	m.probes[probe.Id] = probe
-	m.urlHashes[urlHashString] = true
-	return &probe, nil
+	return f.ProbeStorage.GetProbe(ctx, probeID)
 }
 
-func (m *mockProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
-	if m.deleteProbeErr != nil {
-		return m.deleteProbeErr
+func (f *faultyStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	if f.updateErr != nil {
+		return nil, f.updateErr
 	}
-	if _, ok := m.probes[probeID]; !ok {
-		return k8serrors.NewNotFound(schema.GroupResource{}, probeID.String())
+	return f.ProbeStorage.UpdateProbe(ctx, probe)
+}
+
+func (f *faultyStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
 	}
-	// Set status to terminating instead of deleting
-	probe := m.probes[probeID]
-	probe.Status = v1.Terminating
-	m.probes[probeID] = probe
-	return nil
+	return f.ProbeStorage.ListProbes(ctx, selector)
 }
 
-func (m *mockProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
-	if m.deleteProbeErr != nil {
-		return m.deleteProbeErr
+func (f *faultyStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if f.createErr != nil {
+		return nil, f.createErr
 	}
-	if _, ok := m.probes[probeID]; !ok {
-		return k8serrors.NewNotFound(schema.GroupResource{}, probeID.String())
+	return f.ProbeStorage.CreateProbe(ctx, probe, urlHashString)
+}
+
+func (f *faultyStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
 	}
-	delete(m.probes, probeID)
-	return nil
+	return f.ProbeStorage.DeleteProbe(ctx, probeID)
 }
 
-func (m *mockProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
-	if m.probeWithURLHashExistsErr != nil {
-		return false, m.probeWithURLHashExistsErr
+func (f *faultyStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
 	}
-	_, exists := m.urlHashes[urlHashString]
-	return exists, nil
+	return f.ProbeStorage.DeleteProbeStorage(ctx, probeID)
 }
 
-func (m *mockProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
-	return 0, nil
+func (f *faultyStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	if f.urlHashErr != nil {
+		return false, f.urlHashErr
+	}
+	return f.ProbeStorage.ProbeWithURLHashExists(ctx, urlHashString)
 }
 
 func TestListProbes(t *testing.T) {
 	probe1ID := uuid.New()
 	probe2ID := uuid.New()
 	probes := []v1.ProbeObject{
-		{Id: probe1ID, StaticUrl: "https://example.com/1"},
+		{Id: probe1ID, StaticUrl: "https://example.com/1", Labels: &v1.LabelsSchema{"env": "prod"}},
 		{Id: probe2ID, StaticUrl: "https://example.com/2"},
 	}
+	// Pages are ordered by probe ID.
+	firstByID := probes[0]
+	if probe2ID.String() < probe1ID.String() {
+		firstByID = probes[1]
+	}
 
 	testCases := []struct {
 		name             string
 		params           v1.ListProbesParams
+		ctx              context.Context
+		maxListItems     int
 		store            probestore.ProbeStorage
 		expectedResponse v1.ListProbesResponseObject
 		expectedErr      string
 	}{
 		{
-			name:   "successfully lists probes",
-			params: v1.ListProbesParams{},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{
-					probe1ID: probes[0],
-					probe2ID: probes[1],
-				},
-			},
-			expectedResponse: v1.ListProbes200JSONResponse(v1.ProbesArrayResponse{Probes: probes}),
+			name:             "successfully lists probes",
+			params:           v1.ListProbesParams{},
+			store:            newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes200JSONResponse{Body: v1.ProbesArrayResponse{Probes: probes}},
 		},
 		{
 			name:   "returns 400 for invalid label selector",
 			params: v1.ListProbesParams{LabelSelector: func() *string { s := "invalid selector"; return &s }()},
-			store:  &mockProbeStore{},
+			store:  newProbeStore(t),
 			expectedResponse: v1.ListProbes400JSONResponse{
 				Error: v1.ErrorObject{Message: "invalid label_selector: unable to parse requirement: found 'invalid', expected: identifier, '!', 'in', 'notin', '=', '==', '!='"},
 			},
 		},
 		{
-			name:   "successfully lists probes with valid label selector",
-			params: v1.ListProbesParams{LabelSelector: func() *string { s := "env=prod"; return &s }()},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{
-					probe1ID: probes[0],
-				},
-			},
-			expectedResponse: v1.ListProbes200JSONResponse(v1.ProbesArrayResponse{Probes: []v1.ProbeObject{probes[0]}}),
+			name:             "successfully lists probes with valid label selector",
+			params:           v1.ListProbesParams{LabelSelector: func() *string { s := "env=prod"; return &s }()},
+			store:            newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes200JSONResponse{Body: v1.ProbesArrayResponse{Probes: []v1.ProbeObject{probes[0]}}},
+		},
+		{
+			name:        "returns error when listing fails",
+			params:      v1.ListProbesParams{},
+			store:       &faultyStore{ProbeStorage: newProbeStore(t), listErr: errors.New("generic list error")},
+			expectedErr: "failed to list probes from storage: generic list error",
 		},
 		{
-			name:   "returns error when listing fails",
+			name:   "returns 413 when the caller's item limit is exceeded",
 			params: v1.ListProbesParams{},
-			store: &mockProbeStore{
-				listProbesErr: errors.New("generic list error"),
+			ctx:    limits.WithPolicy(context.Background(), limits.Policy{MaxItems: 1}),
+			store:  newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes413JSONResponse{
+				Error: v1.ErrorObject{Message: "selector matched 2 probes, more than the 1 allowed for this caller; narrow the label_selector or set a limit of at most 1"},
+			},
+		},
+		{
+			name:         "returns 413 when the server-wide cap is exceeded",
+			params:       v1.ListProbesParams{},
+			maxListItems: 1,
+			store:        newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes413JSONResponse{
+				Error: v1.ErrorObject{Message: "selector matched 2 probes, more than the 1 allowed for this caller; narrow the label_selector or set a limit of at most 1"},
 			},
-			expectedErr: "failed to list probes from storage: generic list error",
+		},
+		{
+			name:         "the server-wide cap applies when it is below the caller's item limit",
+			params:       v1.ListProbesParams{},
+			ctx:          limits.WithPolicy(context.Background(), limits.Policy{MaxItems: 5}),
+			maxListItems: 1,
+			store:        newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes413JSONResponse{
+				Error: v1.ErrorObject{Message: "selector matched 2 probes, more than the 1 allowed for this caller; narrow the label_selector or set a limit of at most 1"},
+			},
+		},
+		{
+			name:         "a lower caller item limit applies under the server-wide cap",
+			params:       v1.ListProbesParams{},
+			ctx:          limits.WithPolicy(context.Background(), limits.Policy{MaxItems: 1}),
+			maxListItems: 5,
+			store:        newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes413JSONResponse{
+				Error: v1.ErrorObject{Message: "selector matched 2 probes, more than the 1 allowed for this caller; narrow the label_selector or set a limit of at most 1"},
+			},
+		},
+		{
+			name:             "pages within the server-wide cap are served",
+			params:           v1.ListProbesParams{Limit: func() *int { n := 1; return &n }()},
+			maxListItems:     1,
+			store:            newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes200JSONResponse{Body: v1.ProbesArrayResponse{Probes: []v1.ProbeObject{firstByID}}},
+		},
+		{
+			name:             "lists probes within the caller's item limit",
+			params:           v1.ListProbesParams{},
+			ctx:              limits.WithPolicy(context.Background(), limits.Policy{MaxItems: 2}),
+			store:            newProbeStore(t, probes...),
+			expectedResponse: v1.ListProbes200JSONResponse{Body: v1.ProbesArrayResponse{Probes: probes}},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			server := NewServer(tc.store)
+			server.MaxListItems = tc.maxListItems
 			req := v1.ListProbesRequestObject{Params: tc.params}
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
 
-			res, err := server.ListProbes(context.Background(), req)
+			res, err := server.ListProbes(ctx, req)
 
 			if tc.expectedErr != "" {
 				require.Error(t, err)
@@ -189,7 +290,7 @@ func TestListProbes(t *testing.T) {
 				} else if resp200, ok := res.(v1.ListProbes200JSONResponse); ok {
 					expectedResp, expectedOk := tc.expectedResponse.(v1.ListProbes200JSONResponse)
 					require.True(t, expectedOk)
-					assert.ElementsMatch(t, expectedResp.Probes, resp200.Probes)
+					assert.ElementsMatch(t, probeIDs(expectedResp.Body.Probes), probeIDs(resp200.Body.Probes))
 				} else {
 					assert.Equal(t, tc.expectedResponse, res)
 				}
@@ -198,9 +299,332 @@ func TestListProbes(t *testing.T) {
 	}
 }
 
+func TestListProbes_Features(t *testing.T) {
+	probeID := uuid.New()
+	store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com"})
+
+	t.Run("omits features when none are configured", func(t *testing.T) {
+		res, err := NewServer(store).ListProbes(context.Background(), v1.ListProbesRequestObject{})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListProbes200JSONResponse)
+		require.True(t, ok)
+		assert.Nil(t, resp.Body.Features)
+	})
+
+	t.Run("advertises configured features", func(t *testing.T) {
+		server := NewServer(store)
+		server.Features = v1.FeaturesSchema{"delta-sync-token": "v2"}
+
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListProbes200JSONResponse)
+		require.True(t, ok)
+		require.NotNil(t, resp.Body.Features)
+		assert.Equal(t, v1.FeaturesSchema{"delta-sync-token": "v2"}, *resp.Body.Features)
+	})
+}
+
+func TestListProbes_Pagination(t *testing.T) {
+	const probeCount = 5
+	var probes []v1.ProbeObject
+	for i := range probeCount {
+		probes = append(probes, v1.ProbeObject{Id: uuid.New(), StaticUrl: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	store := newProbeStore(t, probes...)
+	server := NewServer(store)
+	limit := 2
+
+	listPage := func(t *testing.T, ctx context.Context, params v1.ListProbesParams) v1.ListProbes200JSONResponse {
+		t.Helper()
+		res, err := server.ListProbes(ctx, v1.ListProbesRequestObject{Params: params})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListProbes200JSONResponse)
+		require.True(t, ok, "unexpected response %#v", res)
+		return resp
+	}
+
+	t.Run("pages through every probe once", func(t *testing.T) {
+		ctx := limits.WithTenant(context.Background(), "dashboards")
+		seen := map[uuid.UUID]bool{}
+		params := v1.ListProbesParams{Limit: &limit}
+		for pages := 1; ; pages++ {
+			require.LessOrEqual(t, pages, 3, "expected at most 3 pages")
+			resp := listPage(t, ctx, params)
+			assert.LessOrEqual(t, len(resp.Body.Probes), limit)
+			for _, p := range resp.Body.Probes {
+				assert.False(t, seen[p.Id], "probe %s returned twice", p.Id)
+				seen[p.Id] = true
+			}
+			if resp.Body.NextPageToken == nil {
+				break
+			}
+			params.PageToken = resp.Body.NextPageToken
+		}
+		assert.Len(t, seen, probeCount)
+	})
+
+	t.Run("omits the token when everything fits", func(t *testing.T) {
+		resp := listPage(t, context.Background(), v1.ListProbesParams{})
+		assert.Len(t, resp.Body.Probes, probeCount)
+		assert.Nil(t, resp.Body.NextPageToken)
+	})
+
+	first := listPage(t, limits.WithTenant(context.Background(), "dashboards"), v1.ListProbesParams{Limit: &limit})
+	require.NotNil(t, first.Body.NextPageToken)
+	otherSelector := "env=prod"
+	otherSort := v1.ListProbesParamsSortByStaticUrl
+	tampered := "x" + *first.Body.NextPageToken
+
+	otherServer := NewServer(store)
+
+	testCases := []struct {
+		name   string
+		server Server
+		ctx    context.Context
+		params v1.ListProbesParams
+	}{
+		{
+			name:   "rejects a tampered token",
+			server: server,
+			ctx:    limits.WithTenant(context.Background(), "dashboards"),
+			params: v1.ListProbesParams{PageToken: &tampered},
+		},
+		{
+			name:   "rejects a token from another tenant",
+			server: server,
+			ctx:    limits.WithTenant(context.Background(), "other"),
+			params: v1.ListProbesParams{PageToken: first.Body.NextPageToken},
+		},
+		{
+			name:   "rejects a token for another selector",
+			server: server,
+			ctx:    limits.WithTenant(context.Background(), "dashboards"),
+			params: v1.ListProbesParams{LabelSelector: &otherSelector, PageToken: first.Body.NextPageToken},
+		},
+		{
+			name:   "rejects a token for another order",
+			server: server,
+			ctx:    limits.WithTenant(context.Background(), "dashboards"),
+			params: v1.ListProbesParams{SortBy: &otherSort, PageToken: first.Body.NextPageToken},
+		},
+		{
+			name:   "rejects a token signed by another server",
+			server: otherServer,
+			ctx:    limits.WithTenant(context.Background(), "dashboards"),
+			params: v1.ListProbesParams{PageToken: first.Body.NextPageToken},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := tc.server.ListProbes(tc.ctx, v1.ListProbesRequestObject{Params: tc.params})
+			require.NoError(t, err)
+			assert.Equal(t, v1.ListProbes400JSONResponse{
+				Error: v1.ErrorObject{Message: "invalid page_token: it is malformed or was issued for a different query"},
+			}, res)
+		})
+	}
+}
+
+// selectorRecorder records the label selector of the last ListProbes call.
+type selectorRecorder struct {
+	probestore.ProbeStorage
+	selector string
+}
+
+func (r *selectorRecorder) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	r.selector = selector
+	return r.ProbeStorage.ListProbes(ctx, selector)
+}
+
+func TestListProbes_FieldSelector(t *testing.T) {
+	local := probestore.NewMemoryProbeStore()
+	ctx := context.Background()
+
+	byURL := map[string]v1.ProbeObject{}
+	for _, p := range []struct {
+		url    string
+		status v1.StatusSchema
+	}{
+		{"https://api.one.example.com", v1.Active},
+		{"https://api.two.example.com", v1.Pending},
+		{"https://console.example.com", v1.Active},
+		{"https://api.three.example.com", v1.Failed},
+	} {
+		probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: p.url, Status: p.status}
+		_, err := local.CreateProbe(ctx, probe, probeURLHash(p.url))
+		require.NoError(t, err)
+		byURL[p.url] = probe
+	}
+	store := &selectorRecorder{ProbeStorage: local}
+	server := NewServer(store)
+
+	testCases := []struct {
+		name             string
+		fieldSelector    string
+		expectedURLs     []string
+		expectedSelector string
+		expectedErr      string
+	}{
+		{
+			name:             "status is pushed down as a label requirement",
+			fieldSelector:    "status==active",
+			expectedURLs:     []string{"https://api.one.example.com", "https://console.example.com"},
+			expectedSelector: "app=rhobs-synthetics-probe,rhobs-synthetics/status in (active)",
+		},
+		{
+			name:             "status exclusions are pushed down",
+			fieldSelector:    "status notin (active,failed)",
+			expectedURLs:     []string{"https://api.two.example.com"},
+			expectedSelector: "app=rhobs-synthetics-probe,rhobs-synthetics/status notin (active,failed)",
+		},
+		{
+			name:             "an exact static_url is pushed down as its hash",
+			fieldSelector:    "static_url=https://console.example.com",
+			expectedURLs:     []string{"https://console.example.com"},
+			expectedSelector: "app=rhobs-synthetics-probe,rhobs-synthetics/static-url-hash=" + probeURLHash("https://console.example.com"),
+		},
+		{
+			name:             "url prefix is matched after listing",
+			fieldSelector:    "static_url^=https://api.,status in (active,pending)",
+			expectedURLs:     []string{"https://api.one.example.com", "https://api.two.example.com"},
+			expectedSelector: "app=rhobs-synthetics-probe,rhobs-synthetics/status in (active,pending)",
+		},
+		{
+			name:             "ids are matched after listing",
+			fieldSelector:    fmt.Sprintf("id in (%s,%s)", byURL["https://api.three.example.com"].Id, uuid.New()),
+			expectedURLs:     []string{"https://api.three.example.com"},
+			expectedSelector: "app=rhobs-synthetics-probe",
+		},
+		{
+			name:          "invalid field selector",
+			fieldSelector: "status=running",
+			expectedErr:   `invalid field_selector: invalid status "running", expected one of pending, active, failed, terminating, deleted`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := server.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{FieldSelector: &tc.fieldSelector}})
+			require.NoError(t, err)
+
+			if tc.expectedErr != "" {
+				assert.Equal(t, v1.ListProbes400JSONResponse{Error: v1.ErrorObject{Message: tc.expectedErr}}, res)
+				return
+			}
+			resp, ok := res.(v1.ListProbes200JSONResponse)
+			require.True(t, ok, "unexpected response %#v", res)
+			var urls []string
+			for _, p := range resp.Body.Probes {
+				urls = append(urls, p.StaticUrl)
+			}
+			assert.ElementsMatch(t, tc.expectedURLs, urls)
+			assert.Equal(t, tc.expectedSelector, store.selector)
+		})
+	}
+
+	t.Run("page tokens are bound to the field selector", func(t *testing.T) {
+		limit, fieldSelector := 1, "status=active"
+		res, err := server.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{FieldSelector: &fieldSelector, Limit: &limit}})
+		require.NoError(t, err)
+		token := res.(v1.ListProbes200JSONResponse).Body.NextPageToken
+		require.NotNil(t, token)
+
+		other := "status=active,static_url^=https://"
+		res, err = server.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{FieldSelector: &other, Limit: &limit, PageToken: token}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbes400JSONResponse{}, res)
+	})
+}
+
+func TestListProbes_MinGeneration(t *testing.T) {
+	// Probes stored before generations were kept have none.
+	legacyID := uuid.New()
+	store := restoredProbeStore(t, v1.ProbeObject{Id: legacyID, StaticUrl: "https://legacy.example.com", Status: v1.Active})
+	require.Nil(t, storedProbe(t, store, legacyID).Generation)
+
+	// Probes start at generation 1, and each change of their spec moves it on.
+	for url, generation := range map[string]int64{
+		"https://first.example.com":   1,
+		"https://changed.example.com": 3,
+		"https://latest.example.com":  5,
+	} {
+		probe, err := store.CreateProbe(context.Background(), v1.ProbeObject{Id: uuid.New(), StaticUrl: url, Status: v1.Active}, probestore.URLHashLabel(probestore.URLHash(url)))
+		require.NoError(t, err)
+		for i := int64(1); i < generation; i++ {
+			probe.Interval = new(fmt.Sprintf("%ds", 30+i))
+			probe, err = store.UpdateProbe(context.Background(), *probe)
+			require.NoError(t, err)
+		}
+		require.Equal(t, generation, *probe.Generation)
+	}
+	server := NewServer(store)
+
+	listURLs := func(t *testing.T, minGeneration int64) []string {
+		t.Helper()
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{MinGeneration: &minGeneration}})
+		require.NoError(t, err)
+		resp, ok := res.(v1.ListProbes200JSONResponse)
+		require.True(t, ok, "unexpected response %#v", res)
+		var urls []string
+		for _, p := range resp.Body.Probes {
+			urls = append(urls, p.StaticUrl)
+		}
+		return urls
+	}
+
+	assert.Len(t, listURLs(t, 1), 4, "probes without a generation are at generation 1")
+	assert.ElementsMatch(t, []string{"https://changed.example.com", "https://latest.example.com"}, listURLs(t, 2))
+	assert.Equal(t, []string{"https://latest.example.com"}, listURLs(t, 4))
+	assert.Empty(t, listURLs(t, 6))
+
+	t.Run("page tokens are bound to min_generation", func(t *testing.T) {
+		limit, minGeneration := 1, int64(1)
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{MinGeneration: &minGeneration, Limit: &limit}})
+		require.NoError(t, err)
+		token := res.(v1.ListProbes200JSONResponse).Body.NextPageToken
+		require.NotNil(t, token)
+
+		other := int64(2)
+		res, err = server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{MinGeneration: &other, Limit: &limit, PageToken: token}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbes400JSONResponse{}, res)
+	})
+}
+
+// listCounter counts ListProbes calls.
+type listCounter struct {
+	probestore.ProbeStorage
+	calls atomic.Int32
+}
+
+func (c *listCounter) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	c.calls.Add(1)
+	return c.ProbeStorage.ListProbes(ctx, selector)
+}
+
+func TestMonitorProbes(t *testing.T) {
+	store := &listCounter{ProbeStorage: newProbeStore(t)}
+	server := NewServer(store)
+	assert.Equal(t, DefaultMonitorInterval, server.MonitorInterval)
+	server.MonitorInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		server.MonitorProbes(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.calls.Load() >= 3 }, time.Second, 5*time.Millisecond,
+		"the inventory should be refreshed at every interval")
+	cancel()
+	<-done
+}
+
 func TestGetProbeById(t *testing.T) {
 	probeID := uuid.New()
-	probe := v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com"}
+	store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com"})
 
 	testCases := []struct {
 		name             string
@@ -212,19 +636,19 @@ func TestGetProbeById(t *testing.T) {
 		{
 			name:             "successfully gets a probe",
 			probeID:          probeID,
-			store:            &mockProbeStore{probes: map[uuid.UUID]v1.ProbeObject{probeID: probe}},
-			expectedResponse: v1.GetProbeById200JSONResponse(probe),
+			store:            store,
+			expectedResponse: v1.GetProbeById200JSONResponse{Body: storedProbe(t, store, probeID), Headers: v1.GetProbeById200ResponseHeaders{ETag: `"1"`}},
 		},
 		{
 			name:             "returns 404 when probe not found",
 			probeID:          uuid.New(),
-			store:            &mockProbeStore{probes: map[uuid.UUID]v1.ProbeObject{}},
+			store:            store,
 			expectedResponse: v1.GetProbeById404JSONResponse{},
 		},
 		{
 			name:        "returns error when getting fails",
 			probeID:     probeID,
-			store:       &mockProbeStore{getProbeErr: errors.New("generic get error")},
+			store:       &faultyStore{ProbeStorage: store, getErr: errors.New("generic get error")},
 			expectedErr: "failed to get probe from storage: generic get error",
 		},
 	}
@@ -253,8 +677,6 @@ func TestGetProbeById(t *testing.T) {
 
 func TestCreateProbe(t *testing.T) {
 	newURL := "https://example.com/new"
-	urlHashBytes := sha256.Sum256([]byte(newURL))
-	urlHashString := hex.EncodeToString(urlHashBytes[:])[:63]
 
 	testCases := []struct {
 		name             string
@@ -266,31 +688,65 @@ func TestCreateProbe(t *testing.T) {
 		{
 			name:             "successfully creates a probe",
 			reqBody:          v1.CreateProbeJSONRequestBody{StaticUrl: newURL},
-			store:            &mockProbeStore{},
+			store:            newProbeStore(t),
 			expectedResponse: v1.CreateProbe201JSONResponse{},
 		},
 		{
 			name:             "returns 409 when url hash exists",
 			reqBody:          v1.CreateProbeJSONRequestBody{StaticUrl: newURL},
-			store:            &mockProbeStore{urlHashes: map[string]bool{urlHashString: true}},
+			store:            newProbeStore(t, v1.ProbeObject{Id: uuid.New(), StaticUrl: newURL}),
 			expectedResponse: v1.CreateProbe409JSONResponse{},
 		},
 		{
-			name:    "returns error when checking url hash fails",
-			reqBody: v1.CreateProbeJSONRequestBody{StaticUrl: newURL},
-			store: &mockProbeStore{
-				probeWithURLHashExistsErr: errors.New("generic hash check error"),
-			},
+			name:        "returns error when checking url hash fails",
+			reqBody:     v1.CreateProbeJSONRequestBody{StaticUrl: newURL},
+			store:       &faultyStore{ProbeStorage: newProbeStore(t), urlHashErr: errors.New("generic hash check error")},
 			expectedErr: "failed to check for existing probes: generic hash check error",
 		},
 		{
-			name:    "returns error when creating probe fails",
-			reqBody: v1.CreateProbeJSONRequestBody{StaticUrl: newURL},
-			store: &mockProbeStore{
-				createProbeErr: errors.New("generic create error"),
-			},
+			name:             "returns error when creating probe fails",
+			reqBody:          v1.CreateProbeJSONRequestBody{StaticUrl: newURL},
+			store:            &faultyStore{ProbeStorage: newProbeStore(t), createErr: errors.New("generic create error")},
 			expectedResponse: v1.CreateProbe500JSONResponse{Error: v1.ErrorObject{Message: "failed to create probe: generic create error"}},
 		},
+		{
+			name:             "successfully creates a probe with unprotected labels",
+			reqBody:          v1.CreateProbeJSONRequestBody{StaticUrl: newURL, Labels: &v1.LabelsSchema{"env": "prod"}},
+			store:            newProbeStore(t),
+			expectedResponse: v1.CreateProbe201JSONResponse{},
+		},
+		{
+			name:    "returns 400 when setting the creation timestamp",
+			reqBody: v1.CreateProbeJSONRequestBody{StaticUrl: newURL, CreationTimestamp: &time.Time{}},
+			store:   newProbeStore(t),
+			expectedResponse: v1.CreateProbe400JSONResponse{
+				Error: v1.ErrorObject{Message: "creation_timestamp is set by the server and cannot be given"},
+			},
+		},
+		{
+			name:    "returns 403 when setting protected label: app",
+			reqBody: v1.CreateProbeJSONRequestBody{StaticUrl: newURL, Labels: &v1.LabelsSchema{"app": "malicious-app"}},
+			store:   newProbeStore(t),
+			expectedResponse: v1.CreateProbe403JSONResponse{
+				Error: v1.ErrorObject{Message: "creation of system-managed label 'app' is forbidden"},
+			},
+		},
+		{
+			name:    "returns 403 when setting protected label: rhobs-synthetics/status",
+			reqBody: v1.CreateProbeJSONRequestBody{StaticUrl: newURL, Labels: &v1.LabelsSchema{"rhobs-synthetics/status": "active"}},
+			store:   newProbeStore(t),
+			expectedResponse: v1.CreateProbe403JSONResponse{
+				Error: v1.ErrorObject{Message: "creation of system-managed label 'rhobs-synthetics/status' is forbidden"},
+			},
+		},
+		{
+			name:    "returns 403 when setting protected label: private",
+			reqBody: v1.CreateProbeJSONRequestBody{StaticUrl: newURL, Labels: &v1.LabelsSchema{"env": "prod", "private": "true"}},
+			store:   newProbeStore(t),
+			expectedResponse: v1.CreateProbe403JSONResponse{
+				Error: v1.ErrorObject{Message: "creation of system-managed label 'private' is forbidden"},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -308,6 +764,17 @@ func TestCreateProbe(t *testing.T) {
 				assert.IsType(t, tc.expectedResponse, res)
 				if resp201, ok := res.(v1.CreateProbe201JSONResponse); ok {
 					assert.Equal(t, newURL, resp201.StaticUrl)
+					require.NotNil(t, resp201.UrlHash)
+					assert.Equal(t, probestore.URLHash(newURL), *resp201.UrlHash)
+					assert.Equal(t, probeURLHash(newURL), (*resp201.UrlHash)[:63], "the label is a prefix of the full hash")
+				}
+				if resp409, ok := res.(v1.CreateProbe409JSONResponse); ok {
+					require.NotNil(t, resp409.Error.RetryAfterSeconds)
+					assert.Equal(t, 5, *resp409.Error.RetryAfterSeconds)
+				}
+				if _, ok := res.(v1.CreateProbe403JSONResponse); ok {
+					assert.Equal(t, tc.expectedResponse, res)
+					assert.Zero(t, storedProbes(t, tc.store), "rejected probe must not be stored")
 				}
 			}
 		})
@@ -316,6 +783,7 @@ func TestCreateProbe(t *testing.T) {
 
 func TestDeleteProbe(t *testing.T) {
 	probeID := uuid.New()
+	probe := v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Status: v1.Active}
 
 	testCases := []struct {
 		name             string
@@ -327,19 +795,19 @@ func TestDeleteProbe(t *testing.T) {
 		{
 			name:             "successfully deletes a probe",
 			probeID:          probeID,
-			store:            &mockProbeStore{probes: map[uuid.UUID]v1.ProbeObject{probeID: {}}},
+			store:            newProbeStore(t, probe),
 			expectedResponse: v1.DeleteProbe204Response{},
 		},
 		{
 			name:             "returns 404 when probe not found",
 			probeID:          uuid.New(),
-			store:            &mockProbeStore{probes: map[uuid.UUID]v1.ProbeObject{}},
+			store:            newProbeStore(t),
 			expectedResponse: v1.DeleteProbe404JSONResponse{},
 		},
 		{
 			name:        "returns error when deleting fails",
 			probeID:     probeID,
-			store:       &mockProbeStore{deleteProbeErr: errors.New("generic delete error")},
+			store:       &faultyStore{ProbeStorage: newProbeStore(t, probe), deleteErr: errors.New("generic delete error")},
 			expectedErr: "failed to delete probe from storage: generic delete error",
 		},
 	}
@@ -384,126 +852,120 @@ func TestUpdateProbe(t *testing.T) {
 			name:    "allows status field updates (RMO can set terminating, agents can set active/failed)",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Status: &newStatus},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
-			expectedResponse: v1.UpdateProbe200JSONResponse{
+			store:   newProbeStore(t, initialProbe),
+			expectedResponse: v1.UpdateProbe200JSONResponse{Body: v1.ProbeObject{
 				Id:        probeID,
 				StaticUrl: "https://example.com",
 				Status:    newStatus,
+			}},
+		},
+		{
+			name:    "returns 400 when setting the update timestamp",
+			probeID: probeID,
+			reqBody: v1.UpdateProbeJSONRequestBody{Status: &newStatus, UpdateTimestamp: &time.Time{}},
+			store:   newProbeStore(t, initialProbe),
+			expectedResponse: v1.UpdateProbe400JSONResponse{
+				Error: v1.ErrorObject{Message: "update_timestamp is set by the server and cannot be given"},
 			},
 		},
 		{
 			name:    "returns 404 when probe does not exist (testing with labels)",
 			probeID: uuid.New(),
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"environment": "test"}},
-			store:   &mockProbeStore{probes: map[uuid.UUID]v1.ProbeObject{}},
+			store:   newProbeStore(t),
 			expectedResponse: v1.UpdateProbe404JSONResponse{
 				Warning: v1.WarningObject{Message: fmt.Sprintf("probe with ID %s not found", uuid.New().String())}, // Message is dynamic, we'll check the type
 			},
 		},
 		{
-			name:    "returns error when getting probe fails",
-			probeID: probeID,
-			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"environment": "test"}},
-			store: &mockProbeStore{
-				probes:      map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-				getProbeErr: errors.New("generic get error"),
-			},
+			name:        "returns error when getting probe fails",
+			probeID:     probeID,
+			reqBody:     v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"environment": "test"}},
+			store:       &faultyStore{ProbeStorage: newProbeStore(t, initialProbe), getErr: errors.New("generic get error")},
 			expectedErr: "failed to get probe from storage for update: generic get error",
 		},
 		{
-			name:    "returns error when updating probe fails",
+			name:        "returns error when updating probe fails",
+			probeID:     probeID,
+			reqBody:     v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"environment": "test"}},
+			store:       &faultyStore{ProbeStorage: newProbeStore(t, initialProbe), updateErr: errors.New("generic update error")},
+			expectedErr: "failed to update probe in storage: generic update error",
+		},
+		{
+			name:    "returns 409 when another live probe monitors the url",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"environment": "test"}},
-			store: &mockProbeStore{
-				probes:         map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-				updateProbeErr: errors.New("generic update error"),
+			store:   &faultyStore{ProbeStorage: newProbeStore(t, initialProbe), updateErr: k8serrors.NewAlreadyExists(schema.GroupResource{}, "https://example.com")},
+			expectedResponse: v1.UpdateProbe409JSONResponse{
+				Error: v1.ErrorObject{Message: "another live probe already monitors https://example.com"},
 			},
-			expectedErr: "failed to update probe in storage: generic update error",
 		},
 		{
 			name:    "successfully deletes probe when status set to deleted",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Status: &[]v1.StatusSchema{v1.Deleted}[0]},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{
-					probeID: {Id: probeID, StaticUrl: "https://example.com", Status: v1.Terminating},
-				},
-			},
-			expectedResponse: v1.UpdateProbe200JSONResponse{
+			store:   newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Status: v1.Terminating}),
+			expectedResponse: v1.UpdateProbe200JSONResponse{Body: v1.ProbeObject{
 				Id:        probeID,
 				StaticUrl: "https://example.com",
 				Status:    v1.Deleted,
-			},
+			}},
 			postCheck: func(t *testing.T, store probestore.ProbeStorage) {
 				// Verify the probe was actually deleted from the store
-				s := store.(*mockProbeStore)
-				_, exists := s.probes[probeID]
-				assert.False(t, exists, "Probe should have been actually deleted from store")
+				_, err := store.GetProbe(context.Background(), probeID)
+				assert.True(t, k8serrors.IsNotFound(err), "Probe should have been actually deleted from store")
 			},
 		},
 		{
 			name:    "successfully updates user labels",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"environment": "prod", "team": "sre"}},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
-			expectedResponse: v1.UpdateProbe200JSONResponse{
+			store:   newProbeStore(t, initialProbe),
+			expectedResponse: v1.UpdateProbe200JSONResponse{Body: v1.ProbeObject{
 				Id:        probeID,
 				StaticUrl: "https://example.com",
 				Status:    v1.Pending,
 				Labels:    &v1.LabelsSchema{"environment": "prod", "team": "sre"},
-			},
+			}},
 			postCheck: func(t *testing.T, store probestore.ProbeStorage) {
-				s := store.(*mockProbeStore)
-				labels := s.probes[probeID].Labels
+				labels := storedProbe(t, store, probeID).Labels
 				assert.NotNil(t, labels)
 				assert.Equal(t, "prod", (*labels)["environment"])
 				assert.Equal(t, "sre", (*labels)["team"])
 			},
 		},
 		{
-			name:    "returns 403 when trying to create protected label: app",
+			name:    "returns 403 when trying to modify protected label: app",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"app": "malicious-app"}},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
+			store:   newProbeStore(t, initialProbe),
 			expectedResponse: v1.UpdateProbe403JSONResponse{
-				Error: v1.ErrorObject{Message: "creation of system-managed label 'app' is forbidden"},
+				Error: v1.ErrorObject{Message: "modification of system-managed label 'app' is forbidden"},
 			},
 		},
 		{
-			name:    "returns 403 when trying to create protected label: rhobs-synthetics/status",
+			name:    "returns 403 when trying to modify protected label: rhobs-synthetics/status",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"rhobs-synthetics/status": "hacked"}},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
+			store:   newProbeStore(t, initialProbe),
 			expectedResponse: v1.UpdateProbe403JSONResponse{
-				Error: v1.ErrorObject{Message: "creation of system-managed label 'rhobs-synthetics/status' is forbidden"},
+				Error: v1.ErrorObject{Message: "modification of system-managed label 'rhobs-synthetics/status' is forbidden"},
 			},
 		},
 		{
-			name:    "returns 403 when trying to create protected label: rhobs-synthetics/static-url-hash",
+			name:    "returns 403 when trying to modify protected label: rhobs-synthetics/static-url-hash",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"rhobs-synthetics/static-url-hash": "fakehash"}},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
+			store:   newProbeStore(t, initialProbe),
 			expectedResponse: v1.UpdateProbe403JSONResponse{
-				Error: v1.ErrorObject{Message: "creation of system-managed label 'rhobs-synthetics/static-url-hash' is forbidden"},
+				Error: v1.ErrorObject{Message: "modification of system-managed label 'rhobs-synthetics/static-url-hash' is forbidden"},
 			},
 		},
 		{
 			name:    "returns 403 when trying to modify protected label: private",
 			probeID: probeID,
 			reqBody: v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"private": ""}},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
+			store:   newProbeStore(t, initialProbe),
 			expectedResponse: v1.UpdateProbe403JSONResponse{
 				Error: v1.ErrorObject{Message: "creation of system-managed label 'private' is forbidden"},
 			},
@@ -515,15 +977,13 @@ func TestUpdateProbe(t *testing.T) {
 				Status: &newStatus,
 				Labels: &v1.LabelsSchema{"environment": "prod"},
 			},
-			store: &mockProbeStore{
-				probes: map[uuid.UUID]v1.ProbeObject{probeID: initialProbe},
-			},
-			expectedResponse: v1.UpdateProbe200JSONResponse{
+			store: newProbeStore(t, initialProbe),
+			expectedResponse: v1.UpdateProbe200JSONResponse{Body: v1.ProbeObject{
 				Id:        probeID,
 				StaticUrl: "https://example.com",
 				Status:    newStatus,
 				Labels:    &v1.LabelsSchema{"environment": "prod"},
-			},
+			}},
 		},
 	}
 
@@ -547,6 +1007,9 @@ func TestUpdateProbe(t *testing.T) {
 				require.NoError(t, err)
 				if _, ok := res.(v1.UpdateProbe404JSONResponse); ok {
 					require.IsType(t, tc.expectedResponse, res)
+				} else if expected, ok := tc.expectedResponse.(v1.UpdateProbe200JSONResponse); ok {
+					require.IsType(t, expected, res)
+					assertProbe(t, expected.Body, res.(v1.UpdateProbe200JSONResponse).Body)
 				} else {
 					assert.Equal(t, tc.expectedResponse, res)
 				}
@@ -559,9 +1022,50 @@ func TestUpdateProbe(t *testing.T) {
 	}
 }
 
+// TestProtectedLabelsOnCreateAndUpdate checks that create and update reject
+// the same labels. Update tells a change to a label the store keeps on the
+// probe from adding a new one.
+func TestProtectedLabelsOnCreateAndUpdate(t *testing.T) {
+	protected := []string{
+		baseAppLabelKey,
+		probeStatusLabelKey,
+		probeURLHashLabelKey,
+		privateProbeLabelKey,
+		"rhobs-synthetics/anything",
+	}
+
+	for _, key := range protected {
+		t.Run(key, func(t *testing.T) {
+			probeID := uuid.New()
+			store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Status: v1.Active})
+			server := NewServer(store)
+			labels := &v1.LabelsSchema{key: "value"}
+
+			createRes, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{
+				Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com/other", Labels: labels},
+			})
+			require.NoError(t, err)
+			createResp, ok := createRes.(v1.CreateProbe403JSONResponse)
+			require.True(t, ok, "create: expected 403, got %T", createRes)
+
+			updateRes, err := server.UpdateProbe(context.Background(), v1.UpdateProbeRequestObject{
+				ProbeId: probeID,
+				Body:    &v1.UpdateProbeJSONRequestBody{Labels: labels},
+			})
+			require.NoError(t, err)
+			updateResp, ok := updateRes.(v1.UpdateProbe403JSONResponse)
+			require.True(t, ok, "update: expected 403, got %T", updateRes)
+
+			assert.Equal(t, "creation of system-managed label '"+key+"' is forbidden", createResp.Error.Message)
+			assert.Contains(t, updateResp.Error.Message, "system-managed label '"+key+"' is forbidden")
+		})
+	}
+}
+
 func Test_validateProtectedLabels(t *testing.T) {
 	tests := []struct {
 		name      string
+		policy    *LabelPolicy
 		old       v1.LabelsSchema
 		new       v1.LabelsSchema
 		expectErr bool
@@ -614,11 +1118,41 @@ func Test_validateProtectedLabels(t *testing.T) {
 			new:       v1.LabelsSchema{"unprotectedLabel": "true"},
 			expectErr: false,
 		},
+		{
+			name:      "labels under the rhobs-synthetics/ prefix are reserved",
+			old:       v1.LabelsSchema{},
+			new:       v1.LabelsSchema{"rhobs-synthetics/owner": "me"},
+			expectErr: true,
+		},
+		{
+			name:      "creation with no existing labels rejects protected labels",
+			old:       nil,
+			new:       v1.LabelsSchema{baseAppLabelKey: baseAppLabelValue},
+			expectErr: true,
+		},
+		{
+			name:      "operator-defined prefix is reserved",
+			policy:    func() *LabelPolicy { p := DefaultLabelPolicy().WithReservedPrefixes("example.com/"); return &p }(),
+			old:       v1.LabelsSchema{"example.com/team": "sre"},
+			new:       v1.LabelsSchema{"example.com/team": "other"},
+			expectErr: true,
+		},
+		{
+			name:      "operator-defined prefix does not affect other labels",
+			policy:    func() *LabelPolicy { p := DefaultLabelPolicy().WithReservedPrefixes("example.com/"); return &p }(),
+			old:       v1.LabelsSchema{},
+			new:       v1.LabelsSchema{"example.org/team": "sre"},
+			expectErr: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateProtectedLabels(tt.new, tt.old)
+			policy := DefaultLabelPolicy()
+			if tt.policy != nil {
+				policy = *tt.policy
+			}
+			err := policy.validate(tt.new, tt.old)
 
 			if (err != nil) != tt.expectErr {
 				t.Errorf("unexpected test result: expectedErr=%t, got err=%v", tt.expectErr, err)
@@ -626,3 +1160,474 @@ func Test_validateProtectedLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestLabelPolicy_WithReservedPrefixes(t *testing.T) {
+	base := DefaultLabelPolicy()
+	policy := base.WithReservedPrefixes("example.com/", "", " example.com/ ", reservedLabelPrefix)
+
+	assert.Equal(t, []string{reservedLabelPrefix, "example.com/"}, policy.ReservedPrefixes)
+	assert.Equal(t, []string{reservedLabelPrefix}, base.ReservedPrefixes, "base policy must not be modified")
+	assert.True(t, policy.IsProtected("example.com/team"))
+	assert.False(t, base.IsProtected("example.com/team"))
+}
+
+func TestStoreLogsCarryRequestContext(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := logging.NewHandler(&buf, "info", "json")
+	require.NoError(t, err)
+	prev := slog.Default()
+	slog.SetDefault(slog.New(h))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	store := probestore.NewMemoryProbeStore()
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}
+	_, err = store.CreateProbe(context.Background(), probe, "hash")
+	require.NoError(t, err)
+	buf.Reset()
+
+	mux := http.NewServeMux()
+	v1.HandlerFromMux(v1.NewStrictHandler(NewServer(store), []v1.StrictMiddlewareFunc{logging.StrictMiddleware}), mux)
+	handler := logging.Middleware(mux)
+
+	req := httptest.NewRequest(http.MethodDelete, "/probes/"+probe.Id.String(), nil)
+	req.Header.Set(logging.RequestIDHeader, "req-1")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record), buf.String())
+	assert.Equal(t, "Deleted pending probe immediately, it was never processed by an agent", record["msg"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "DeleteProbe", record["operation"])
+	assert.Equal(t, probe.Id.String(), record["probe_id"])
+}
+
+func TestProbeETags(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}
+	_, err := store.CreateProbe(context.Background(), probe, "hash")
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	v1.HandlerFromMux(v1.NewStrictHandler(NewServer(store), nil), mux)
+	do := func(method, ifMatch, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/probes/"+probe.Id.String(), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	get := do(http.MethodGet, "", "")
+	require.Equal(t, http.StatusOK, get.Code)
+	var got v1.ProbeObject
+	require.NoError(t, json.Unmarshal(get.Body.Bytes(), &got))
+	require.NotNil(t, got.ResourceVersion)
+	original := get.Header().Get("ETag")
+	assert.Equal(t, `"`+*got.ResourceVersion+`"`, original)
+
+	t.Run("PATCH with a matching If-Match applies and returns the new ETag", func(t *testing.T) {
+		rr := do(http.MethodPatch, original, `{"labels":{"team":"sre"}}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.NotEmpty(t, rr.Header().Get("ETag"))
+		assert.NotEqual(t, original, rr.Header().Get("ETag"))
+	})
+
+	t.Run("PATCH and DELETE with a stale If-Match get 412", func(t *testing.T) {
+		rr := do(http.MethodPatch, original, `{"labels":{"team":"other"}}`)
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+		assert.Contains(t, rr.Body.String(), "does not match If-Match")
+
+		rr = do(http.MethodDelete, original, "")
+		assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+		current, err := store.GetProbe(context.Background(), probe.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "sre", (*current.Labels)["team"], "the probe is unchanged")
+		assert.Equal(t, v1.Active, current.Status)
+	})
+
+	t.Run("If-Match * and no If-Match apply unconditionally", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(http.MethodPatch, "*", `{"labels":{"team":"a"}}`).Code)
+		assert.Equal(t, http.StatusOK, do(http.MethodPatch, "", `{"labels":{"team":"b"}}`).Code)
+	})
+
+	t.Run("DELETE with a matching If-Match applies", func(t *testing.T) {
+		current := do(http.MethodGet, "", "").Header().Get("ETag")
+		assert.Equal(t, http.StatusNoContent, do(http.MethodDelete, current, "").Code)
+	})
+}
+
+func TestProbes_DryRun(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	ctx := context.Background()
+	existing, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://existing.example.com", Status: v1.Active}, probeURLHash("https://existing.example.com"))
+	require.NoError(t, err)
+
+	server := NewServer(store)
+	dryRun := true
+
+	t.Run("create", func(t *testing.T) {
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{
+			Params: v1.CreateProbeParams{DryRun: &dryRun},
+			Body:   &v1.CreateProbeJSONRequestBody{StaticUrl: "https://new.example.com", Labels: &v1.LabelsSchema{"env": "prod"}},
+		})
+		require.NoError(t, err)
+		created, ok := res.(v1.CreateProbe201JSONResponse)
+		require.True(t, ok, "unexpected response %#v", res)
+		assert.Equal(t, v1.Pending, created.Status)
+		require.NotNil(t, created.Interval, "defaults are filled in")
+		assert.Equal(t, server.Schedule.Interval.String(), *created.Interval)
+
+		res, err = server.CreateProbe(ctx, v1.CreateProbeRequestObject{
+			Params: v1.CreateProbeParams{DryRun: &dryRun},
+			Body:   &v1.CreateProbeJSONRequestBody{StaticUrl: existing.StaticUrl},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe409JSONResponse{}, res, "duplicates are detected")
+
+		res, err = server.CreateProbe(ctx, v1.CreateProbeRequestObject{
+			Params: v1.CreateProbeParams{DryRun: &dryRun},
+			Body:   &v1.CreateProbeJSONRequestBody{StaticUrl: "https://new.example.com", Labels: &v1.LabelsSchema{"app": "other"}},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe403JSONResponse{}, res, "protected labels are checked")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		failed := v1.Failed
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+			ProbeId: existing.Id,
+			Params:  v1.UpdateProbeParams{DryRun: &dryRun},
+			Body:    &v1.UpdateProbeJSONRequestBody{Status: &failed, Labels: &v1.LabelsSchema{"team": "sre"}},
+		})
+		require.NoError(t, err)
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "unexpected response %#v", res)
+		assert.Equal(t, v1.Failed, updated.Body.Status)
+		assert.Equal(t, "sre", (*updated.Body.Labels)["team"])
+
+		pending := v1.Pending
+		res, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+			ProbeId: existing.Id,
+			Params:  v1.UpdateProbeParams{DryRun: &dryRun},
+			Body:    &v1.UpdateProbeJSONRequestBody{Status: &pending},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbe409JSONResponse{}, res, "status transitions are checked")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		res, err := server.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: existing.Id, Params: v1.DeleteProbeParams{DryRun: &dryRun}})
+		require.NoError(t, err)
+		assert.Equal(t, v1.DeleteProbe204Response{}, res)
+
+		res, err = server.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: uuid.New(), Params: v1.DeleteProbeParams{DryRun: &dryRun}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteProbe404JSONResponse{}, res)
+	})
+
+	probes, err := store.ListProbes(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, probes, 1, "nothing was created or deleted")
+	assert.Equal(t, v1.Active, probes[0].Status)
+	assert.Equal(t, existing.ResourceVersion, probes[0].ResourceVersion, "nothing was updated")
+	assert.Empty(t, server.Audit.List(audit.Filter{}), "dry runs are not audited")
+}
+
+// racingStore simulates another writer changing a probe between the read
+// and the write of a request.
+type racingStore struct {
+	probestore.ProbeStorage
+	race func()
+}
+
+func (r racingStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	r.race()
+	return r.ProbeStorage.UpdateProbe(ctx, probe)
+}
+
+func TestUpdateProbe_ConcurrentChange(t *testing.T) {
+	local := probestore.NewMemoryProbeStore()
+	ctx := context.Background()
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}
+	created, err := local.CreateProbe(ctx, probe, "hash")
+	require.NoError(t, err)
+
+	store := racingStore{ProbeStorage: local, race: func() {
+		other := *created
+		other.Status = v1.Failed
+		_, err := local.UpdateProbe(ctx, other)
+		require.NoError(t, err)
+	}}
+	server := NewServer(store)
+	active := v1.Active
+	ifMatch := etag(*created)
+
+	res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+		ProbeId: probe.Id,
+		Params:  v1.UpdateProbeParams{IfMatch: &ifMatch},
+		Body:    &v1.UpdateProbeJSONRequestBody{Status: &active},
+	})
+	require.NoError(t, err)
+	resp409, ok := res.(v1.UpdateProbe409JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Contains(t, resp409.Error.Message, "changed while the request was applied")
+	assert.NotNil(t, resp409.Error.RetryAfterSeconds)
+
+	current, err := local.GetProbe(ctx, probe.Id)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Failed, current.Status, "the other writer's change is kept")
+}
+
+func TestUpdateProbe_StatusTransitions(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	ctx := context.Background()
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}
+	_, err := store.CreateProbe(ctx, probe, "hash")
+	require.NoError(t, err)
+	update := func(server Server, status v1.StatusSchema) v1.UpdateProbeResponseObject {
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+			ProbeId: probe.Id,
+			Body:    &v1.UpdateProbeJSONRequestBody{Status: &status},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("a disallowed transition gets 409 with the allowed statuses", func(t *testing.T) {
+		res := update(NewServer(store), v1.Pending)
+		resp409, ok := res.(v1.UpdateProbe409JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, fmt.Sprintf("probe with ID %s cannot move from status active to pending", probe.Id), resp409.Error.Message)
+		require.NotNil(t, resp409.Error.AllowedStatuses)
+		assert.Equal(t, []v1.StatusSchema{v1.Failed, v1.Terminating}, *resp409.Error.AllowedStatuses)
+		assert.Nil(t, resp409.Error.RetryAfterSeconds, "retrying cannot succeed")
+
+		current, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1.Active, current.Status)
+	})
+
+	t.Run("an unknown status gets 400", func(t *testing.T) {
+		res := update(NewServer(store), "act ive")
+		resp400, ok := res.(v1.UpdateProbe400JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, `invalid status "act ive", expected one of [pending active failed terminating deleted]`, resp400.Error.Message)
+	})
+
+	t.Run("active probes cannot skip terminating", func(t *testing.T) {
+		assert.IsType(t, v1.UpdateProbe409JSONResponse{}, update(NewServer(store), v1.Deleted))
+		_, err := store.GetProbe(ctx, probe.Id)
+		assert.NoError(t, err, "the probe is kept")
+	})
+
+	t.Run("keeping the status is allowed", func(t *testing.T) {
+		assert.IsType(t, v1.UpdateProbe200JSONResponse{}, update(NewServer(store), v1.Active))
+	})
+
+	t.Run("configured transitions", func(t *testing.T) {
+		server := NewServer(store)
+		server.StatusTransitions = StatusTransitions{v1.Active: {v1.Pending}}
+
+		assert.IsType(t, v1.UpdateProbe200JSONResponse{}, update(server, v1.Pending))
+		res := update(server, v1.Active)
+		resp409, ok := res.(v1.UpdateProbe409JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, []v1.StatusSchema{}, *resp409.Error.AllowedStatuses, "pending has no transitions")
+	})
+}
+
+func TestMutationHooks(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	ctx := context.Background()
+	defaults, err := mutation.DefaultLabels(map[string]string{"team": "sre"})
+	require.NoError(t, err)
+	server := NewServer(store)
+	server.Mutations = mutation.Chain{mutation.NormalizeURL(), defaults}
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{
+		Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "HTTPS://Example.com:443/health"},
+	})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, "https://example.com/health", created.StaticUrl)
+	assert.Equal(t, "sre", (*created.Labels)["team"])
+
+	t.Run("duplicates are found after normalization", func(t *testing.T) {
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{
+			Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://EXAMPLE.com/health"},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe409JSONResponse{}, res)
+	})
+
+	t.Run("hooks run on updates", func(t *testing.T) {
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+			ProbeId: created.Id,
+			Body:    &v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"env": "prod"}},
+		})
+		require.NoError(t, err)
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, "sre", (*updated.Body.Labels)["team"])
+		assert.Equal(t, "prod", (*updated.Body.Labels)["env"])
+	})
+
+	t.Run("a failing hook rejects the request", func(t *testing.T) {
+		failing := NewServer(store)
+		failing.Mutations = mutation.Chain{mutation.Func("reject", func(context.Context, *v1.ProbeObject) error {
+			return errors.New("not allowed")
+		})}
+
+		res, err := failing.CreateProbe(ctx, v1.CreateProbeRequestObject{
+			Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://other.example.com"},
+		})
+		require.NoError(t, err)
+		resp400, ok := res.(v1.CreateProbe400JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, "mutation hook reject: not allowed", resp400.Error.Message)
+
+		active := v1.Active
+		updateRes, err := failing.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+			ProbeId: created.Id,
+			Body:    &v1.UpdateProbeJSONRequestBody{Status: &active},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbe400JSONResponse{}, updateRes)
+
+		current, err := store.GetProbe(ctx, created.Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1.Pending, current.Status, "the probe is unchanged")
+	})
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		header      string
+		version     string
+		conditional bool
+	}{
+		{name: "any version", header: "*"},
+		{name: "quoted", header: `"42"`, version: "42", conditional: true},
+		{name: "unquoted", header: "42", version: "42", conditional: true},
+		{name: "surrounding space", header: ` "42" `, version: "42", conditional: true},
+	}
+
+	version, conditional := ifMatchVersion(nil)
+	assert.Empty(t, version)
+	assert.False(t, conditional, "an absent header matches any version")
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			version, conditional := ifMatchVersion(&tc.header)
+			assert.Equal(t, tc.version, version)
+			assert.Equal(t, tc.conditional, conditional)
+		})
+	}
+}
+
+func FuzzListProbes_LabelSelector(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"env=prod",
+		"env==prod,team!=sre",
+		"env in (prod,staging),!private",
+		"region notin (us-east-1)",
+		"private",
+		"version>2",
+		"app=other",
+		"env in (prod",
+		"=value",
+		"a=b,",
+		",",
+		"example.com/owner=team-a",
+		"key=" + strings.Repeat("v", 64),
+		"env=pröd",
+	} {
+		f.Add(seed)
+	}
+
+	store := probestore.NewMemoryProbeStore()
+	_, err := store.CreateProbe(context.Background(), v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: "https://example.com",
+		Status:    v1.Active,
+		Labels:    &v1.LabelsSchema{"env": "prod", "version": "3"},
+	}, probeURLHash("https://example.com"))
+	require.NoError(f, err)
+	server := NewServer(store)
+
+	f.Fuzz(func(t *testing.T, selector string) {
+		resp, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{
+			Params: v1.ListProbesParams{LabelSelector: &selector},
+		})
+		// A selector is either rejected as a client error or served; it must
+		// never reach the store in a form the store cannot parse.
+		require.NoError(t, err)
+
+		_, parseErr := labels.Parse(selector)
+		switch resp.(type) {
+		case v1.ListProbes200JSONResponse:
+			assert.NoError(t, parseErr)
+		case v1.ListProbes400JSONResponse:
+			assert.Error(t, parseErr)
+		default:
+			t.Fatalf("unexpected response %T for selector %q", resp, selector)
+		}
+	})
+}
+
+func FuzzProbeURLHash(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"https://example.com",
+		"https://example.com/path?query=1#fragment",
+		"http://[::1]:8080/",
+		"https://例え.jp/パス",
+		"not a url\x00\xff",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, staticURL string) {
+		hash := probeURLHash(staticURL)
+		// The hash is stored as a label value on ConfigMaps and Probe resources.
+		assert.Empty(t, validation.IsValidLabelValue(hash))
+		assert.Len(t, hash, 63)
+		assert.Equal(t, hash, probeURLHash(staticURL))
+	})
+}
+
+func TestCreateProbeValidateConnectivity(t *testing.T) {
+	store := newProbeStore(t)
+	server := NewServer(store)
+	validate := v1.Connectivity
+
+	res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{
+		Params: v1.CreateProbeParams{Validate: &validate},
+		Body:   &v1.CreateProbeJSONRequestBody{StaticUrl: "http://169.254.169.254/latest/meta-data"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, v1.CreateProbe422JSONResponse{Error: v1.ErrorObject{
+		Message: `probe target "http://169.254.169.254/latest/meta-data" failed validation`,
+		ValidationFailures: &[]v1.TargetValidationFailure{{
+			Check:   v1.PrivateIp,
+			Message: "169.254.169.254 is a private or special-purpose address",
+		}},
+	}}, res)
+	assert.Zero(t, storedProbes(t, store))
+
+	// Without validate the target is not checked.
+	res, err = server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{
+		Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "http://169.254.169.254/latest/meta-data"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// sortKeyTimeFormat has a fixed width, so that creation timestamps sort the
+// same as strings and as times.
+const sortKeyTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// probeOrder is the order ListProbes returns probes in. The zero value sorts
+// by ID, ascending.
+type probeOrder struct {
+	by   v1.ListProbesParamsSortBy
+	desc bool
+}
+
+// parseProbeOrder parses the sort_by and order parameters of ListProbes.
+func parseProbeOrder(sortBy *v1.ListProbesParamsSortBy, order *v1.ListProbesParamsOrder) (probeOrder, error) {
+	var o probeOrder
+	if sortBy != nil {
+		switch *sortBy {
+		case v1.ListProbesParamsSortByCreatedAt, v1.ListProbesParamsSortByStaticUrl, v1.ListProbesParamsSortByStatus:
+			o.by = *sortBy
+		default:
+			return probeOrder{}, fmt.Errorf("invalid sort_by %q, expected one of created_at, static_url or status", *sortBy)
+		}
+	}
+	if order != nil {
+		switch *order {
+		case v1.ListProbesParamsOrderAsc:
+		case v1.ListProbesParamsOrderDesc:
+			o.desc = true
+		default:
+			return probeOrder{}, fmt.Errorf("invalid order %q, expected asc or desc", *order)
+		}
+	}
+	return o, nil
+}
+
+// explicit reports whether the caller asked for an order, in which case
+// probes are sorted even when the response is not paginated.
+func (o probeOrder) explicit() bool {
+	return o != probeOrder{}
+}
+
+// key returns the value the probe is sorted by, besides its ID.
+func (o probeOrder) key(probe v1.ProbeObject) string {
+	switch o.by {
+	case v1.ListProbesParamsSortByCreatedAt:
+		if probe.CreationTimestamp == nil {
+			return ""
+		}
+		return probe.CreationTimestamp.UTC().Format(sortKeyTimeFormat)
+	case v1.ListProbesParamsSortByStaticUrl:
+		return probe.StaticUrl
+	case v1.ListProbesParamsSortByStatus:
+		return string(probe.Status)
+	}
+	return ""
+}
+
+// compare orders two positions, each a sort key and an ID.
+func (o probeOrder) compare(aKey, aID, bKey, bID string) int {
+	c := cmp.Or(strings.Compare(aKey, bKey), strings.Compare(aID, bID))
+	if o.desc {
+		return -c
+	}
+	return c
+}
+
+// sort sorts probes in the order.
+func (o probeOrder) sort(probes []v1.ProbeObject) {
+	slices.SortFunc(probes, func(a, b v1.ProbeObject) int {
+		return o.compare(o.key(a), a.Id.String(), o.key(b), b.Id.String())
+	})
+}
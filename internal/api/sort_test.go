@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListProbes_Sort(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := created.Add(d)
+		return &t
+	}
+	probes := []v1.ProbeObject{
+		{Id: uuid.New(), StaticUrl: "https://c.example.com", Status: v1.Active, CreationTimestamp: at(time.Hour)},
+		{Id: uuid.New(), StaticUrl: "https://a.example.com", Status: v1.Pending, CreationTimestamp: at(2 * time.Hour)},
+		{Id: uuid.New(), StaticUrl: "https://d.example.com", Status: v1.Active},
+		{Id: uuid.New(), StaticUrl: "https://b.example.com", Status: v1.Failed, CreationTimestamp: at(time.Nanosecond)},
+	}
+	server := NewServer(restoredProbeStore(t, probes...))
+
+	list := func(t *testing.T, params v1.ListProbesParams) []string {
+		t.Helper()
+		var urls []string
+		for pages := 1; ; pages++ {
+			require.LessOrEqual(t, pages, len(probes)+1)
+			res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: params})
+			require.NoError(t, err)
+			resp, ok := res.(v1.ListProbes200JSONResponse)
+			require.True(t, ok, "unexpected response %#v", res)
+			for _, p := range resp.Body.Probes {
+				urls = append(urls, p.StaticUrl)
+			}
+			if resp.Body.NextPageToken == nil {
+				return urls
+			}
+			params.PageToken = resp.Body.NextPageToken
+		}
+	}
+	sortBy := func(s v1.ListProbesParamsSortBy) *v1.ListProbesParamsSortBy { return &s }
+	desc := v1.ListProbesParamsOrderDesc
+	one := 1
+
+	t.Run("by url", func(t *testing.T) {
+		expected := []string{"https://a.example.com", "https://b.example.com", "https://c.example.com", "https://d.example.com"}
+		assert.Equal(t, expected, list(t, v1.ListProbesParams{SortBy: sortBy(v1.ListProbesParamsSortByStaticUrl)}))
+		assert.Equal(t, expected, list(t, v1.ListProbesParams{SortBy: sortBy(v1.ListProbesParamsSortByStaticUrl), Limit: &one}), "pages keep the order")
+	})
+
+	t.Run("by creation, descending", func(t *testing.T) {
+		expected := []string{"https://a.example.com", "https://c.example.com", "https://b.example.com", "https://d.example.com"}
+		params := v1.ListProbesParams{SortBy: sortBy(v1.ListProbesParamsSortByCreatedAt), Order: &desc}
+		assert.Equal(t, expected, list(t, params), "probes without a creation timestamp sort first ascending")
+		params.Limit = &one
+		assert.Equal(t, expected, list(t, params))
+	})
+
+	t.Run("by status, ties by id", func(t *testing.T) {
+		params := v1.ListProbesParams{SortBy: sortBy(v1.ListProbesParamsSortByStatus), Limit: &one}
+		urls := list(t, params)
+		require.Len(t, urls, len(probes))
+		assert.ElementsMatch(t, []string{"https://c.example.com", "https://d.example.com"}, urls[:2])
+		assert.Equal(t, []string{"https://b.example.com", "https://a.example.com"}, urls[2:])
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		res, err := server.ListProbes(context.Background(), v1.ListProbesRequestObject{Params: v1.ListProbesParams{SortBy: sortBy("generation")}})
+		require.NoError(t, err)
+		assert.Equal(t, v1.ListProbes400JSONResponse{
+			Error: v1.ErrorObject{Message: `invalid sort_by "generation", expected one of created_at, static_url or status`},
+		}, res)
+	})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// probeStatuses are the statuses a probe can have.
+var probeStatuses = v1.ProbeStatuses()
+
+// statusReasonPattern matches the CamelCase tokens accepted as status_reason.
+var statusReasonPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+const (
+	maxStatusReasonLength  = 64
+	maxStatusMessageLength = 256
+)
+
+// validateStatusDetails checks the status_reason and status_message of an
+// update leaving the probe with status, which must be failed or terminating
+// for them to be accepted.
+func validateStatusDetails(status v1.StatusSchema, reason, message *string) error {
+	if reason == nil && message == nil {
+		return nil
+	}
+	if status != v1.Failed && status != v1.Terminating {
+		return fmt.Errorf("status_reason and status_message are only accepted with a %s or %s status, not %s", v1.Failed, v1.Terminating, status)
+	}
+	if reason != nil && (len(*reason) > maxStatusReasonLength || !statusReasonPattern.MatchString(*reason)) {
+		return fmt.Errorf("status_reason %q must be a CamelCase token of at most %d letters and digits", *reason, maxStatusReasonLength)
+	}
+	if message != nil && len(*message) > maxStatusMessageLength {
+		return fmt.Errorf("status_message must be at most %d characters long", maxStatusMessageLength)
+	}
+	return nil
+}
+
+// statusChangeReason returns the reason recorded in the probe's history for
+// a status change with the given details, or "".
+func statusChangeReason(reason, message *string) string {
+	switch {
+	case reason != nil && message != nil:
+		return *reason + ": " + *message
+	case reason != nil:
+		return *reason
+	case message != nil:
+		return *message
+	}
+	return ""
+}
+
+// StatusTransitions maps each probe status to the statuses an update may move
+// the probe to. A status without an entry cannot be left, and keeping the
+// current status is always allowed.
+type StatusTransitions map[v1.StatusSchema][]v1.StatusSchema
+
+// DefaultStatusTransitions returns the transitions used when none are
+// configured: agents move pending and active probes between active and
+// failed, the deletion of a probe makes it terminating, and its agent
+// confirms the cleanup by setting it to deleted.
+func DefaultStatusTransitions() StatusTransitions {
+	return StatusTransitions{
+		v1.Pending:     {v1.Active, v1.Failed, v1.Terminating},
+		v1.Active:      {v1.Failed, v1.Terminating},
+		v1.Failed:      {v1.Active, v1.Terminating},
+		v1.Terminating: {v1.Deleted},
+	}
+}
+
+// Validate reports transitions from or to unknown statuses.
+func (t StatusTransitions) Validate() error {
+	for _, from := range slices.Sorted(maps.Keys(t)) {
+		if !slices.Contains(probeStatuses, from) {
+			return fmt.Errorf("unknown probe status %q, expected one of %v", from, probeStatuses)
+		}
+		for _, to := range t[from] {
+			if !slices.Contains(probeStatuses, to) {
+				return fmt.Errorf("unknown probe status %q in the transitions from %q, expected one of %v", to, from, probeStatuses)
+			}
+		}
+	}
+	return nil
+}
+
+// statusMetricLabel returns the state label probes with the status are
+// counted under. Statuses read back from storage that are not known ones are
+// counted as unknown, so they cannot add arbitrary label values.
+func statusMetricLabel(status v1.StatusSchema) string {
+	if !slices.Contains(probeStatuses, status) {
+		return "unknown"
+	}
+	return string(status)
+}
+
+// probeStatusMetric returns the status details of a failed or terminating
+// probe reported by rhobs_synthetics_api_probe_status_info, if it has any.
+func probeStatusMetric(probe v1.ProbeObject) (metrics.ProbeStatus, bool) {
+	if probe.Status != v1.Failed && probe.Status != v1.Terminating {
+		return metrics.ProbeStatus{}, false
+	}
+	if probe.StatusReason == nil && probe.StatusMessage == nil {
+		return metrics.ProbeStatus{}, false
+	}
+	status := metrics.ProbeStatus{ProbeID: probe.Id.String(), State: string(probe.Status)}
+	if probe.StatusReason != nil {
+		status.Reason = *probe.StatusReason
+	}
+	if probe.StatusMessage != nil {
+		status.Message = *probe.StatusMessage
+	}
+	return status, true
+}
+
+// allows reports whether a probe may move from one status to another.
+func (t StatusTransitions) allows(from, to v1.StatusSchema) bool {
+	return from == to || slices.Contains(t[from], to)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusTransitions_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		transitions StatusTransitions
+		expectedErr string
+	}{
+		{name: "defaults", transitions: DefaultStatusTransitions()},
+		{name: "empty", transitions: StatusTransitions{}},
+		{
+			name:        "unknown source status",
+			transitions: StatusTransitions{"running": {v1.Active}},
+			expectedErr: `unknown probe status "running", expected one of [pending active failed terminating deleted]`,
+		},
+		{
+			name:        "unknown target status",
+			transitions: StatusTransitions{v1.Active: {v1.Failed, "paused"}},
+			expectedErr: `unknown probe status "paused" in the transitions from "active", expected one of [pending active failed terminating deleted]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.transitions.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestDefaultStatusTransitions(t *testing.T) {
+	transitions := DefaultStatusTransitions()
+	testCases := []struct {
+		from, to v1.StatusSchema
+		allowed  bool
+	}{
+		{from: v1.Pending, to: v1.Active, allowed: true},
+		{from: v1.Pending, to: v1.Failed, allowed: true},
+		{from: v1.Pending, to: v1.Terminating, allowed: true},
+		{from: v1.Pending, to: v1.Deleted, allowed: false},
+		{from: v1.Active, to: v1.Failed, allowed: true},
+		{from: v1.Active, to: v1.Terminating, allowed: true},
+		{from: v1.Active, to: v1.Pending, allowed: false},
+		{from: v1.Active, to: v1.Deleted, allowed: false},
+		{from: v1.Failed, to: v1.Active, allowed: true},
+		{from: v1.Terminating, to: v1.Deleted, allowed: true},
+		{from: v1.Terminating, to: v1.Active, allowed: false},
+		{from: v1.Active, to: v1.Active, allowed: true},
+		{from: v1.Deleted, to: v1.Pending, allowed: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+" to "+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.allowed, transitions.allows(tc.from, tc.to))
+		})
+	}
+}
+
+func TestStatusMetricLabel(t *testing.T) {
+	assert.Equal(t, "active", statusMetricLabel(v1.Active))
+	assert.Equal(t, "terminating", statusMetricLabel(v1.Terminating))
+	assert.Equal(t, "unknown", statusMetricLabel(""))
+	assert.Equal(t, "unknown", statusMetricLabel("act ive,status!=x"))
+}
+
+func TestUpdateProbeStatusDetails(t *testing.T) {
+	ctx := context.Background()
+	probeID := uuid.New()
+	store := newProbeStore(t, v1.ProbeObject{Id: probeID, StaticUrl: "https://example.com", Status: v1.Active})
+	server := NewServer(store)
+
+	update := func(body v1.UpdateProbeJSONRequestBody) v1.UpdateProbeResponseObject {
+		t.Helper()
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probeID, Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+	failed, active := v1.Failed, v1.Active
+	reason, message := "TargetUnreachable", "dial tcp 203.0.113.7:443: i/o timeout"
+
+	res := update(v1.UpdateProbeJSONRequestBody{Status: &failed, StatusReason: &reason, StatusMessage: &message})
+	require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+	assert.Equal(t, &reason, res.(v1.UpdateProbe200JSONResponse).Body.StatusReason)
+	assert.Equal(t, &message, storedProbe(t, store, probeID).StatusMessage)
+
+	update(v1.UpdateProbeJSONRequestBody{Status: &failed})
+	assert.Equal(t, &reason, storedProbe(t, store, probeID).StatusReason, "keeping the status keeps the details")
+
+	other := "CertificateExpired"
+	update(v1.UpdateProbeJSONRequestBody{StatusReason: &other})
+	assert.Equal(t, &other, storedProbe(t, store, probeID).StatusReason)
+	assert.Nil(t, storedProbe(t, store, probeID).StatusMessage, "details are replaced as a whole")
+
+	update(v1.UpdateProbeJSONRequestBody{Status: &active})
+	assert.Nil(t, storedProbe(t, store, probeID).StatusReason, "a new status clears the details")
+
+	for name, body := range map[string]v1.UpdateProbeJSONRequestBody{
+		"active status":  {Status: &active, StatusReason: &reason},
+		"invalid reason": {Status: &failed, StatusReason: new("target unreachable")},
+		"long message":   {Status: &failed, StatusMessage: new(strings.Repeat("x", maxStatusMessageLength+1))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.IsType(t, v1.UpdateProbe400JSONResponse{}, update(body))
+		})
+	}
+}
+
+func TestProbeStatusMetric(t *testing.T) {
+	probeID := uuid.New()
+	reason := "TargetUnreachable"
+	status, ok := probeStatusMetric(v1.ProbeObject{Id: probeID, Status: v1.Failed, StatusReason: &reason})
+	require.True(t, ok)
+	assert.Equal(t, metrics.ProbeStatus{ProbeID: probeID.String(), State: "failed", Reason: reason}, status)
+
+	_, ok = probeStatusMetric(v1.ProbeObject{Id: probeID, Status: v1.Failed})
+	assert.False(t, ok, "probes without details are not reported")
+	_, ok = probeStatusMetric(v1.ProbeObject{Id: probeID, Status: v1.Active, StatusReason: &reason})
+	assert.False(t, ok, "only failed and terminating probes are reported")
+}
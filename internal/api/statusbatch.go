@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// maxStatusBatch is the maxItems of ProbeStatusBatchRequest.updates.
+const maxStatusBatch = 100
+
+// snapshotMinBatch is the smallest batch whose probes are read from one
+// snapshot of the store. A snapshot lists every probe, while each update of
+// a smaller batch only gets its probe, so below it the gets are cheaper.
+const snapshotMinBatch = 10
+
+// (PATCH /probes)
+func (s Server) UpdateProbeStatuses(ctx context.Context, request v1.UpdateProbeStatusesRequestObject) (v1.UpdateProbeStatusesResponseObject, error) {
+	defer metrics.RecordProbestoreRequest("update_probe_statuses", time.Now())
+
+	updates := request.Body.Updates
+	if len(updates) == 0 || len(updates) > maxStatusBatch {
+		return v1.UpdateProbeStatuses400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: fmt.Sprintf("a batch must have between 1 and %d updates, got %d", maxStatusBatch, len(updates)),
+			},
+		}, nil
+	}
+	seen := make(map[uuid.UUID]bool, len(updates))
+	for i, update := range updates {
+		if seen[update.Id] {
+			return v1.UpdateProbeStatuses400JSONResponse{
+				Error: v1.ErrorObject{
+					Message: fmt.Sprintf("updates[%d] updates probe %s a second time", i, update.Id),
+				},
+			}, nil
+		}
+		seen[update.Id] = true
+	}
+
+	// The probes of large batches are read from one snapshot of the store
+	// instead of one by one; a probe changed by someone else since still
+	// fails its write.
+	if len(updates) >= snapshotMinBatch {
+		ctx = probestore.WithSnapshot(ctx)
+	}
+	results := make([]v1.ProbeStatusUpdateResult, 0, len(updates))
+	failed := 0
+	for _, update := range updates {
+		result := s.updateProbeStatus(ctx, update)
+		if result.Code != http.StatusOK {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	slog.InfoContext(ctx, "Updated probe statuses", "count", len(updates), "failed", failed)
+	return v1.UpdateProbeStatuses207JSONResponse{Results: results}, nil
+}
+
+// updateProbeStatus applies one update of a batch as PATCH /probes/{probe_id}
+// would, and reports how it went.
+func (s Server) updateProbeStatus(ctx context.Context, update v1.ProbeStatusUpdate) v1.ProbeStatusUpdateResult {
+	result := v1.ProbeStatusUpdateResult{Id: update.Id}
+	res, err := s.UpdateProbe(ctx, v1.UpdateProbeRequestObject{
+		ProbeId: update.Id,
+		Body: &v1.UpdateProbeJSONRequestBody{
+			Status:        &update.Status,
+			StatusReason:  update.Reason,
+			StatusMessage: update.Message,
+		},
+	})
+	if err != nil {
+		result.Code = http.StatusInternalServerError
+		result.Message = new(err.Error())
+		return result
+	}
+	return statusUpdateResult(update.Id, res)
+}
+
+// statusUpdateResult reports the response UpdateProbe gave to one update of
+// a batch.
+func statusUpdateResult(probeID uuid.UUID, res v1.UpdateProbeResponseObject) v1.ProbeStatusUpdateResult {
+	result := v1.ProbeStatusUpdateResult{Id: probeID}
+	switch r := res.(type) {
+	case v1.UpdateProbe200JSONResponse:
+		result.Code = http.StatusOK
+		result.Probe = &r.Body
+	case v1.UpdateProbe400JSONResponse:
+		result.Code = http.StatusBadRequest
+		result.Message = &r.Error.Message
+	case v1.UpdateProbe403JSONResponse:
+		result.Code = http.StatusForbidden
+		result.Message = &r.Error.Message
+	case v1.UpdateProbe404JSONResponse:
+		result.Code = http.StatusNotFound
+		result.Message = &r.Warning.Message
+	case v1.UpdateProbe409JSONResponse:
+		result.Code = http.StatusConflict
+		result.Message = &r.Error.Message
+	case v1.UpdateProbe412JSONResponse:
+		result.Code = http.StatusPreconditionFailed
+		result.Message = &r.Error.Message
+	default:
+		result.Code = http.StatusInternalServerError
+		result.Message = new(fmt.Sprintf("unexpected response %T", res))
+	}
+	return result
+}
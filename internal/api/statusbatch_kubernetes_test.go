@@ -0,0 +1,66 @@
+//go:build !nokube
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUpdateProbeStatuses_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	const namespace = "rhobs"
+	clientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	store, err := probestore.NewKubernetesProbeStore(ctx, clientset, namespace)
+	require.NoError(t, err)
+	server := NewServer(store)
+
+	var updates []v1.ProbeStatusUpdate
+	for i := range snapshotMinBatch {
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: fmt.Sprintf("https://%d.example.com", i)}})
+		require.NoError(t, err)
+		require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+		updates = append(updates, v1.ProbeStatusUpdate{Id: res.(v1.CreateProbe201JSONResponse).Id, Status: v1.Active})
+	}
+
+	// configMapVerbs applies the updates and counts the requests they made
+	// for ConfigMaps, by verb.
+	configMapVerbs := func(updates []v1.ProbeStatusUpdate) map[string]int {
+		t.Helper()
+		clientset.ClearActions()
+		res, err := server.UpdateProbeStatuses(ctx, v1.UpdateProbeStatusesRequestObject{Body: &v1.UpdateProbeStatusesJSONRequestBody{Updates: updates}})
+		require.NoError(t, err)
+		for _, result := range res.(v1.UpdateProbeStatuses207JSONResponse).Results {
+			require.Equal(t, http.StatusOK, result.Code, result.Message)
+		}
+		verbs := map[string]int{}
+		for _, action := range clientset.Actions() {
+			if action.GetResource().Resource == "configmaps" {
+				verbs[action.GetVerb()]++
+			}
+		}
+		return verbs
+	}
+
+	small := configMapVerbs(updates[:2])
+	assert.Zero(t, small["list"], "small batches get their probes instead of listing them all")
+	assert.NotZero(t, small["get"])
+
+	for i := range updates {
+		updates[i].Status = v1.Failed
+	}
+	large := configMapVerbs(updates)
+	assert.Equal(t, 1, large["list"], "large batches list the probes once")
+	assert.Zero(t, large["get"])
+	assert.Equal(t, snapshotMinBatch, large["update"])
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateProbeStatuses(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	create := func(url string) uuid.UUID {
+		t.Helper()
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: url}})
+		require.NoError(t, err)
+		require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+		return res.(v1.CreateProbe201JSONResponse).Id
+	}
+	first, second := create("https://a.example.com"), create("https://b.example.com")
+	missing := uuid.New()
+
+	res, err := server.UpdateProbeStatuses(ctx, v1.UpdateProbeStatusesRequestObject{Body: &v1.UpdateProbeStatusesJSONRequestBody{
+		Updates: []v1.ProbeStatusUpdate{
+			{Id: first, Status: v1.Active},
+			{Id: second, Status: v1.Failed, Reason: new("TargetUnreachable"), Message: new("dial tcp: i/o timeout")},
+			{Id: missing, Status: v1.Active},
+		},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.UpdateProbeStatuses207JSONResponse{}, res)
+	results := res.(v1.UpdateProbeStatuses207JSONResponse).Results
+	require.Len(t, results, 3)
+
+	assert.Equal(t, first, results[0].Id)
+	assert.Equal(t, http.StatusOK, results[0].Code)
+	require.NotNil(t, results[0].Probe)
+	assert.Equal(t, v1.Active, results[0].Probe.Status)
+
+	assert.Equal(t, http.StatusOK, results[1].Code)
+	assert.Equal(t, new("TargetUnreachable"), results[1].Probe.StatusReason)
+
+	assert.Equal(t, http.StatusNotFound, results[2].Code)
+	assert.Nil(t, results[2].Probe)
+	assert.Contains(t, *results[2].Message, "not found")
+
+	stored, err := store.GetProbe(ctx, second)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Failed, stored.Status, "updates are stored even when others of the batch fail")
+
+	t.Run("updates are checked like single ones", func(t *testing.T) {
+		res, err := server.UpdateProbeStatuses(ctx, v1.UpdateProbeStatusesRequestObject{Body: &v1.UpdateProbeStatusesJSONRequestBody{
+			Updates: []v1.ProbeStatusUpdate{
+				{Id: first, Status: v1.Pending},
+				{Id: second, Status: v1.Active, Reason: new("Recovered")},
+			},
+		}})
+		require.NoError(t, err)
+		results := res.(v1.UpdateProbeStatuses207JSONResponse).Results
+		assert.Equal(t, http.StatusConflict, results[0].Code)
+		assert.Contains(t, *results[0].Message, "cannot move from status active to pending")
+		assert.Equal(t, http.StatusBadRequest, results[1].Code)
+	})
+
+	t.Run("invalid batches", func(t *testing.T) {
+		for message, updates := range map[string][]v1.ProbeStatusUpdate{
+			"between 1 and 100 updates, got 0":           nil,
+			"between 1 and 100 updates, got 101":         make([]v1.ProbeStatusUpdate, 101),
+			"updates[1] updates probe " + first.String(): {{Id: first, Status: v1.Active}, {Id: first, Status: v1.Failed}},
+		} {
+			res, err := server.UpdateProbeStatuses(ctx, v1.UpdateProbeStatusesRequestObject{Body: &v1.UpdateProbeStatusesJSONRequestBody{Updates: updates}})
+			require.NoError(t, err)
+			require.IsType(t, v1.UpdateProbeStatuses400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.UpdateProbeStatuses400JSONResponse).Error.Message, message)
+		}
+	})
+}
+
+func TestStatusUpdateResult(t *testing.T) {
+	id := uuid.New()
+	message := "went wrong"
+	for _, test := range []struct {
+		res  v1.UpdateProbeResponseObject
+		code int
+	}{
+		{v1.UpdateProbe400JSONResponse{Error: v1.ErrorObject{Message: message}}, http.StatusBadRequest},
+		{v1.UpdateProbe403JSONResponse{Error: v1.ErrorObject{Message: message}}, http.StatusForbidden},
+		{v1.UpdateProbe404JSONResponse{Warning: v1.WarningObject{Message: message}}, http.StatusNotFound},
+		{v1.UpdateProbe409JSONResponse{Error: v1.ErrorObject{Message: message}}, http.StatusConflict},
+		{v1.UpdateProbe412JSONResponse{Error: v1.ErrorObject{Message: message}}, http.StatusPreconditionFailed},
+	} {
+		result := statusUpdateResult(id, test.res)
+		assert.Equal(t, id, result.Id)
+		assert.Equal(t, test.code, result.Code, "%T", test.res)
+		assert.Equal(t, &message, result.Message, "%T", test.res)
+		assert.Nil(t, result.Probe)
+	}
+
+	result := statusUpdateResult(id, v1.UpdateProbe200JSONResponse{Body: v1.ProbeObject{Id: id}})
+	assert.Equal(t, http.StatusOK, result.Code)
+	assert.Equal(t, id, result.Probe.Id)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// targetURLs returns the URLs a probe checks: its static URL, then its
+// additional URLs.
+func targetURLs(probe v1.ProbeObject) []string {
+	return append([]string{probe.StaticUrl}, additionalURLs(probe)...)
+}
+
+// additionalURLs returns the additional URLs of a probe, nil if it has none.
+func additionalURLs(probe v1.ProbeObject) []string {
+	if probe.AdditionalUrls == nil || len(*probe.AdditionalUrls) == 0 {
+		return nil
+	}
+	return *probe.AdditionalUrls
+}
+
+// setAdditionalURLs replaces the additional URLs of a probe, an empty list
+// removing them, and drops the statuses of the URLs it no longer checks.
+func setAdditionalURLs(probe *v1.ProbeObject, urls v1.AdditionalUrlsSchema) error {
+	for i, u := range urls {
+		if u == "" {
+			return fmt.Errorf("additional_urls[%d] is empty", i)
+		}
+		if u == probe.StaticUrl {
+			return fmt.Errorf("additional_urls[%d] %q is the probe's static_url", i, u)
+		}
+		if slices.Contains(urls[:i], u) {
+			return fmt.Errorf("additional_urls[%d] %q is listed twice", i, u)
+		}
+	}
+	probe.AdditionalUrls = nil
+	if len(urls) > 0 {
+		probe.AdditionalUrls = new(slices.Clone(urls))
+	}
+	pruneTargetStatuses(probe)
+	return nil
+}
+
+// pruneTargetStatuses drops the statuses of the URLs a probe no longer
+// checks.
+func pruneTargetStatuses(probe *v1.ProbeObject) {
+	if probe.TargetStatuses == nil {
+		return
+	}
+	checked := targetURLs(*probe)
+	statuses := slices.DeleteFunc(slices.Clone(*probe.TargetStatuses), func(status v1.TargetStatus) bool {
+		return !slices.Contains(checked, status.Url)
+	})
+	probe.TargetStatuses = nil
+	if len(statuses) > 0 {
+		probe.TargetStatuses = &statuses
+	}
+}
+
+// setTargetStatuses replaces the statuses of a probe's URLs with those
+// reported by its agent, an empty list removing them.
+func setTargetStatuses(probe *v1.ProbeObject, statuses v1.TargetStatusesSchema) error {
+	checked := targetURLs(*probe)
+	for i, status := range statuses {
+		if !slices.Contains(checked, status.Url) {
+			return fmt.Errorf("target_statuses[%d] reports %q, which the probe does not check", i, status.Url)
+		}
+		if slices.ContainsFunc(statuses[:i], func(other v1.TargetStatus) bool { return other.Url == status.Url }) {
+			return fmt.Errorf("target_statuses[%d] reports %q twice", i, status.Url)
+		}
+	}
+	probe.TargetStatuses = nil
+	if len(statuses) > 0 {
+		probe.TargetStatuses = new(slices.Clone(statuses))
+	}
+	return nil
+}
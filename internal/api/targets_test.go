@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeTargets(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl:      "https://api.example.com",
+		AdditionalUrls: &v1.AdditionalUrlsSchema{"https://console.example.com"},
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, &v1.AdditionalUrlsSchema{"https://console.example.com"}, created.AdditionalUrls)
+
+	update := func(body v1.UpdateProbeJSONRequestBody) v1.UpdateProbeResponseObject {
+		t.Helper()
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: created.Id, Body: &body})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("invalid additional URLs", func(t *testing.T) {
+		for message, urls := range map[string]v1.AdditionalUrlsSchema{
+			"is the probe's static_url": {"https://api.example.com"},
+			"is listed twice":           {"https://console.example.com", "https://console.example.com"},
+			"is empty":                  {""},
+		} {
+			res := update(v1.UpdateProbeJSONRequestBody{AdditionalUrls: &urls})
+			require.IsType(t, v1.UpdateProbe400JSONResponse{}, res, message)
+			assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, message)
+		}
+
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			StaticUrl:      "https://other.example.com",
+			AdditionalUrls: &v1.AdditionalUrlsSchema{"https://other.example.com"},
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe400JSONResponse{}, res)
+	})
+
+	t.Run("agents report the status of each URL", func(t *testing.T) {
+		statuses := v1.TargetStatusesSchema{
+			{Url: "https://api.example.com", State: v1.Up},
+			{Url: "https://console.example.com", State: v1.Down, Message: new("connection refused")},
+		}
+		res := update(v1.UpdateProbeJSONRequestBody{TargetStatuses: &statuses})
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, &statuses, updated.Body.TargetStatuses)
+		assert.Equal(t, *created.Generation, *updated.Body.Generation, "reports leave the generation unchanged")
+	})
+
+	t.Run("reports on URLs the probe does not check", func(t *testing.T) {
+		res := update(v1.UpdateProbeJSONRequestBody{TargetStatuses: &v1.TargetStatusesSchema{{Url: "https://oauth.example.com", State: v1.Up}}})
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+		assert.Contains(t, res.(v1.UpdateProbe400JSONResponse).Error.Message, "which the probe does not check")
+
+		res = update(v1.UpdateProbeJSONRequestBody{TargetStatuses: &v1.TargetStatusesSchema{{Url: "https://api.example.com", State: v1.Up}, {Url: "https://api.example.com", State: v1.Down}}})
+		assert.IsType(t, v1.UpdateProbe400JSONResponse{}, res)
+	})
+
+	t.Run("changing the URLs drops the statuses of those removed", func(t *testing.T) {
+		res := update(v1.UpdateProbeJSONRequestBody{
+			AdditionalUrls: &v1.AdditionalUrlsSchema{"https://oauth.example.com"},
+		})
+		updated, ok := res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, &v1.AdditionalUrlsSchema{"https://oauth.example.com"}, updated.Body.AdditionalUrls)
+		assert.Equal(t, &v1.TargetStatusesSchema{{Url: "https://api.example.com", State: v1.Up}}, updated.Body.TargetStatuses)
+		assert.Equal(t, *created.Generation+1, *updated.Body.Generation)
+
+		res = update(v1.UpdateProbeJSONRequestBody{
+			AdditionalUrls: &v1.AdditionalUrlsSchema{},
+			TargetStatuses: &v1.TargetStatusesSchema{},
+		})
+		updated, ok = res.(v1.UpdateProbe200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Nil(t, updated.Body.AdditionalUrls)
+		assert.Nil(t, updated.Body.TargetStatuses)
+	})
+}
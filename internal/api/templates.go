@@ -0,0 +1,255 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/templates"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// templateLabelKey records the template a probe was created from, so its
+// probes can be selected. It falls under the reserved prefix, so clients
+// cannot set or change it.
+const templateLabelKey = reservedLabelPrefix + "template"
+
+// (GET /probe-templates)
+func (s Server) ListProbeTemplates(ctx context.Context, request v1.ListProbeTemplatesRequestObject) (v1.ListProbeTemplatesResponseObject, error) {
+	list, err := s.Templates.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]v1.ProbeTemplateObject, 0, len(list))
+	for _, t := range list {
+		objs = append(objs, templateObject(t))
+	}
+	return v1.ListProbeTemplates200JSONResponse{Templates: objs}, nil
+}
+
+// (POST /probe-templates)
+func (s Server) CreateProbeTemplate(ctx context.Context, request v1.CreateProbeTemplateRequestObject) (v1.CreateProbeTemplateResponseObject, error) {
+	t := probeTemplate(*request.Body)
+	if err := s.validateTemplate(t); err != nil {
+		return v1.CreateProbeTemplate400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	created, err := s.Templates.Create(ctx, t)
+	if errors.Is(err, templates.ErrExists) {
+		return v1.CreateProbeTemplate400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v1.CreateProbeTemplate201JSONResponse(templateObject(created)), nil
+}
+
+// (GET /probe-templates/{template_id})
+func (s Server) GetProbeTemplate(ctx context.Context, request v1.GetProbeTemplateRequestObject) (v1.GetProbeTemplateResponseObject, error) {
+	t, ok, err := s.Templates.Get(ctx, request.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return v1.GetProbeTemplate404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("probe template with ID %s not found", request.TemplateId),
+			},
+		}, nil
+	}
+	return v1.GetProbeTemplate200JSONResponse(templateObject(t)), nil
+}
+
+// (PUT /probe-templates/{template_id})
+func (s Server) UpdateProbeTemplate(ctx context.Context, request v1.UpdateProbeTemplateRequestObject) (v1.UpdateProbeTemplateResponseObject, error) {
+	t := probeTemplate(*request.Body)
+	if err := s.validateTemplate(t); err != nil {
+		return v1.UpdateProbeTemplate400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	updated, ok, err := s.Templates.Replace(ctx, request.TemplateId, t)
+	switch {
+	case errors.Is(err, templates.ErrExists):
+		return v1.UpdateProbeTemplate400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	case errors.Is(err, templates.ErrConfigured):
+		return v1.UpdateProbeTemplate409JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	case err != nil:
+		return nil, err
+	}
+	if !ok {
+		return v1.UpdateProbeTemplate404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("probe template with ID %s not found", request.TemplateId),
+			},
+		}, nil
+	}
+	return v1.UpdateProbeTemplate200JSONResponse(templateObject(updated)), nil
+}
+
+// (DELETE /probe-templates/{template_id})
+func (s Server) DeleteProbeTemplate(ctx context.Context, request v1.DeleteProbeTemplateRequestObject) (v1.DeleteProbeTemplateResponseObject, error) {
+	ok, err := s.Templates.Delete(ctx, request.TemplateId)
+	if errors.Is(err, templates.ErrConfigured) {
+		return v1.DeleteProbeTemplate409JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return v1.DeleteProbeTemplate404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("probe template with ID %s not found", request.TemplateId),
+			},
+		}, nil
+	}
+	return v1.DeleteProbeTemplate204Response{}, nil
+}
+
+// AddTemplates validates and adds templates given at startup.
+func (s Server) AddTemplates(list []templates.Template) error {
+	for i, t := range list {
+		if err := s.validateTemplate(t); err != nil {
+			return fmt.Errorf("probe_templates[%d]: %w", i, err)
+		}
+		if _, err := s.Templates.Add(t); err != nil {
+			return fmt.Errorf("probe_templates[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateTemplate checks what templates.Template.Validate leaves to the
+// API: the module, and that the labels leave system-managed ones alone.
+func (s Server) validateTemplate(t templates.Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if t.Module != "" && !slices.Contains(probeModules, t.Module) {
+		return fmt.Errorf("template %q: unknown probe module %q, expected one of %v", t.Name, t.Module, probeModules)
+	}
+	return s.LabelPolicy().validate(t.Labels, nil)
+}
+
+// applyTemplate fills in the probe from the template the request names: the
+// URL from its pattern, and the labels, interval, timeout and module the
+// request leaves out. Labels set by the request win over the template's.
+// Failing to get the template is marked with Failed.
+func (s Server) applyTemplate(ctx context.Context, probe *v1.ProbeObject, body v1.CreateProbeRequest) error {
+	if body.TemplateId == nil {
+		if body.StaticUrl == "" && body.Dns == nil && body.Tcp == nil && body.Icmp == nil {
+			return fmt.Errorf("static_url is required unless template_id, dns, tcp or icmp is set")
+		}
+		if body.Variables != nil {
+			return fmt.Errorf("variables can only be used with template_id")
+		}
+		return nil
+	}
+	if body.StaticUrl != "" {
+		return fmt.Errorf("static_url cannot be set together with template_id, it is built from the template")
+	}
+	t, ok, err := s.Templates.Get(ctx, *body.TemplateId)
+	if err != nil {
+		return Failed(err)
+	}
+	if !ok {
+		return fmt.Errorf("probe template with ID %s not found", *body.TemplateId)
+	}
+
+	var values map[string]string
+	if body.Variables != nil {
+		values = *body.Variables
+	}
+	staticURL, err := t.URL(values)
+	if err != nil {
+		return err
+	}
+	probe.StaticUrl = staticURL
+
+	probeLabels := v1.LabelsSchema{}
+	maps.Copy(probeLabels, t.Labels)
+	if probe.Labels != nil {
+		maps.Copy(probeLabels, *probe.Labels)
+	}
+	probeLabels[templateLabelKey] = t.ID.String()
+	probe.Labels = &probeLabels
+
+	if probe.Interval == nil && t.Interval != "" {
+		interval := t.Interval
+		probe.Interval = &interval
+	}
+	if probe.Timeout == nil && t.Timeout != "" {
+		timeout := t.Timeout
+		probe.Timeout = &timeout
+	}
+	if probe.Module == nil && t.Module != "" {
+		module := t.Module
+		probe.Module = &module
+	}
+	return nil
+}
+
+func probeTemplate(req v1.ProbeTemplateRequest) templates.Template {
+	t := templates.Template{Name: req.Name, URLPattern: req.UrlPattern}
+	if req.Labels != nil {
+		t.Labels = *req.Labels
+	}
+	if req.Interval != nil {
+		t.Interval = *req.Interval
+	}
+	if req.Timeout != nil {
+		t.Timeout = *req.Timeout
+	}
+	if req.Module != nil {
+		t.Module = *req.Module
+	}
+	return t
+}
+
+// templateObject returns the API view of a template.
+func templateObject(t templates.Template) v1.ProbeTemplateObject {
+	obj := v1.ProbeTemplateObject{
+		Id:         t.ID,
+		Name:       t.Name,
+		UrlPattern: t.URLPattern,
+		Variables:  t.Variables(),
+		CreatedAt:  t.CreatedAt,
+	}
+	if t.Labels != nil {
+		labels := v1.LabelsSchema(t.Labels)
+		obj.Labels = &labels
+	}
+	if t.Interval != "" {
+		obj.Interval = &t.Interval
+	}
+	if t.Timeout != "" {
+		obj.Timeout = &t.Timeout
+	}
+	if t.Module != "" {
+		obj.Module = &t.Module
+	}
+	return obj
+}
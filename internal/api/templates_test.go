@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/internal/templates"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeTemplateCRUD(t *testing.T) {
+	server := NewServer(probestore.NewMemoryProbeStore())
+	ctx := context.Background()
+	interval := "1m"
+
+	res, err := server.CreateProbeTemplate(ctx, v1.CreateProbeTemplateRequestObject{Body: &v1.CreateProbeTemplateJSONRequestBody{
+		Name:       "console",
+		UrlPattern: "https://console.{region}.example.com",
+		Interval:   &interval,
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbeTemplate201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, []string{"region"}, created.Variables)
+	assert.Equal(t, &interval, created.Interval)
+
+	t.Run("invalid templates get 400", func(t *testing.T) {
+		module := v1.ProbeModuleSchema("ftp")
+		labels := v1.LabelsSchema{"rhobs-synthetics/status": "active"}
+		for _, body := range []v1.CreateProbeTemplateJSONRequestBody{
+			{Name: "broken", UrlPattern: "https://{region"},
+			{Name: "ftp", UrlPattern: "https://example.com", Module: &module},
+			{Name: "reserved", UrlPattern: "https://example.com", Labels: &labels},
+			{Name: "console", UrlPattern: "https://example.com"},
+		} {
+			res, err := server.CreateProbeTemplate(ctx, v1.CreateProbeTemplateRequestObject{Body: &body})
+			require.NoError(t, err)
+			assert.IsType(t, v1.CreateProbeTemplate400JSONResponse{}, res, body.Name)
+		}
+	})
+
+	t.Run("get and list", func(t *testing.T) {
+		res, err := server.GetProbeTemplate(ctx, v1.GetProbeTemplateRequestObject{TemplateId: created.Id})
+		require.NoError(t, err)
+		assert.Equal(t, v1.GetProbeTemplate200JSONResponse(created), res)
+
+		list, err := server.ListProbeTemplates(ctx, v1.ListProbeTemplatesRequestObject{})
+		require.NoError(t, err)
+		assert.Equal(t, v1.ListProbeTemplates200JSONResponse{Templates: []v1.ProbeTemplateObject{v1.ProbeTemplateObject(created)}}, list)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		res, err := server.UpdateProbeTemplate(ctx, v1.UpdateProbeTemplateRequestObject{
+			TemplateId: created.Id,
+			Body:       &v1.UpdateProbeTemplateJSONRequestBody{Name: "console", UrlPattern: "https://{cluster}.{region}.example.com"},
+		})
+		require.NoError(t, err)
+		updated, ok := res.(v1.UpdateProbeTemplate200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, []string{"cluster", "region"}, updated.Variables)
+		assert.Nil(t, updated.Interval)
+
+		res, err = server.UpdateProbeTemplate(ctx, v1.UpdateProbeTemplateRequestObject{
+			TemplateId: uuid.New(),
+			Body:       &v1.UpdateProbeTemplateJSONRequestBody{Name: "other", UrlPattern: "https://example.com"},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbeTemplate404JSONResponse{}, res)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		res, err := server.DeleteProbeTemplate(ctx, v1.DeleteProbeTemplateRequestObject{TemplateId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteProbeTemplate204Response{}, res)
+
+		res, err = server.DeleteProbeTemplate(ctx, v1.DeleteProbeTemplateRequestObject{TemplateId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteProbeTemplate404JSONResponse{}, res)
+		get, err := server.GetProbeTemplate(ctx, v1.GetProbeTemplateRequestObject{TemplateId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeTemplate404JSONResponse{}, get)
+	})
+}
+
+func TestCreateProbeFromTemplate(t *testing.T) {
+	store := newProbeStore(t)
+	server := NewServer(store)
+	module := v1.Tcp
+	tmpl := templates.Template{
+		ID:         uuid.New(),
+		Name:       "console",
+		URLPattern: "https://console.{region}.example.com",
+		Labels:     map[string]string{"team": "observability", "env": "prod"},
+		Interval:   "1m",
+		Module:     module,
+	}
+	require.NoError(t, server.AddTemplates([]templates.Template{tmpl}))
+
+	t.Run("fills in the probe", func(t *testing.T) {
+		variables := map[string]string{"region": "eu-west-1"}
+		labels := v1.LabelsSchema{"env": "stage"}
+		timeout := "5s"
+		res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			TemplateId: &tmpl.ID,
+			Variables:  &variables,
+			Labels:     &labels,
+			Timeout:    &timeout,
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+		probe := storedProbe(t, store, res.(v1.CreateProbe201JSONResponse).Id)
+		assert.Equal(t, "https://console.eu-west-1.example.com", probe.StaticUrl)
+		assert.Equal(t, "stage", (*probe.Labels)["env"], "request labels win")
+		assert.Equal(t, "observability", (*probe.Labels)["team"])
+		assert.Equal(t, tmpl.ID.String(), (*probe.Labels)[templateLabelKey])
+		assert.Equal(t, "1m", *probe.Interval)
+		assert.Equal(t, "5s", *probe.Timeout)
+		assert.Equal(t, module, *probe.Module)
+		assert.Equal(t, v1.LabelsSchema{"env": "stage"}, labels, "the request is left alone")
+	})
+
+	testCases := []struct {
+		name        string
+		body        v1.CreateProbeJSONRequestBody
+		expectedErr string
+	}{
+		{
+			name:        "neither URL nor template",
+			body:        v1.CreateProbeJSONRequestBody{},
+			expectedErr: "static_url is required unless template_id, dns, tcp or icmp is set",
+		},
+		{
+			name:        "variables without template",
+			body:        v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com", Variables: &map[string]string{"region": "eu"}},
+			expectedErr: "variables can only be used with template_id",
+		},
+		{
+			name:        "URL and template",
+			body:        v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com", TemplateId: &tmpl.ID},
+			expectedErr: "static_url cannot be set together with template_id, it is built from the template",
+		},
+		{
+			name:        "missing variables",
+			body:        v1.CreateProbeJSONRequestBody{TemplateId: &tmpl.ID},
+			expectedErr: `template "console" requires variables [region]`,
+		},
+		{
+			name:        "unknown template",
+			body:        v1.CreateProbeJSONRequestBody{TemplateId: &uuid.Nil},
+			expectedErr: "probe template with ID 00000000-0000-0000-0000-000000000000 not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := server.CreateProbe(context.Background(), v1.CreateProbeRequestObject{Body: &tc.body})
+			require.NoError(t, err)
+			assert.Equal(t, v1.CreateProbe400JSONResponse{Error: v1.ErrorObject{Message: tc.expectedErr}}, res)
+		})
+	}
+}
+
+func TestAddTemplates(t *testing.T) {
+	server := NewServer(probestore.NewMemoryProbeStore())
+	err := server.AddTemplates([]templates.Template{
+		{Name: "a", URLPattern: "https://a.example.com"},
+		{Name: "b", URLPattern: "https://{host"},
+	})
+	assert.ErrorContains(t, err, "probe_templates[1]: ")
+	list, err := server.Templates.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	res, err := server.DeleteProbeTemplate(context.Background(), v1.DeleteProbeTemplateRequestObject{TemplateId: list[0].ID})
+	require.NoError(t, err)
+	assert.IsType(t, v1.DeleteProbeTemplate409JSONResponse{}, res, "templates given at startup are held by every replica")
+}
+
+func TestProbeTemplatesSharedByReplicas(t *testing.T) {
+	ctx := context.Background()
+	store := probestore.NewMemoryProbeStore()
+	first, second := NewServer(store), NewServer(store)
+
+	res, err := first.CreateProbeTemplate(ctx, v1.CreateProbeTemplateRequestObject{Body: &v1.CreateProbeTemplateJSONRequestBody{
+		Name:       "console",
+		UrlPattern: "https://console.{region}.example.com",
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbeTemplate201JSONResponse)
+	require.True(t, ok, "got %T", res)
+
+	list, err := second.ListProbeTemplates(ctx, v1.ListProbeTemplatesRequestObject{})
+	require.NoError(t, err)
+	assert.Equal(t, v1.ListProbeTemplates200JSONResponse{Templates: []v1.ProbeTemplateObject{v1.ProbeTemplateObject(created)}}, list)
+
+	variables := map[string]string{"region": "eu-west-1"}
+	probe, err := second.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		TemplateId: &created.Id,
+		Variables:  &variables,
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, probe)
+	assert.Equal(t, "https://console.eu-west-1.example.com", probe.(v1.CreateProbe201JSONResponse).StaticUrl)
+
+	res, err = second.CreateProbeTemplate(ctx, v1.CreateProbeTemplateRequestObject{Body: &v1.CreateProbeTemplateJSONRequestBody{
+		Name:       "console",
+		UrlPattern: "https://example.com",
+	}})
+	require.NoError(t, err)
+	assert.IsType(t, v1.CreateProbeTemplate400JSONResponse{}, res, "names are unique across replicas")
+
+	deleted, err := second.DeleteProbeTemplate(ctx, v1.DeleteProbeTemplateRequestObject{TemplateId: created.Id})
+	require.NoError(t, err)
+	assert.IsType(t, v1.DeleteProbeTemplate204Response{}, deleted)
+	get, err := first.GetProbeTemplate(ctx, v1.GetProbeTemplateRequestObject{TemplateId: created.Id})
+	require.NoError(t, err)
+	assert.IsType(t, v1.GetProbeTemplate404JSONResponse{}, get)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// tenantLabelKey records the tenant that created a probe. It falls under the
+// reserved prefix, so clients cannot set or change it.
+const tenantLabelKey = reservedLabelPrefix + "tenant"
+
+// callerTenant returns the tenant the request is scoped to, or "" when tenant
+// isolation is off or the caller sent no tenant. Untenanted callers, agents
+// and operators that RequireTenant let through, see every probe.
+func (s Server) callerTenant(ctx context.Context) string {
+	if !s.TenantIsolation {
+		return ""
+	}
+	return limits.TenantFromContext(ctx)
+}
+
+// RequireTenant is a strict middleware that, with tenant isolation on,
+// answers 403 to requests without a tenant unless they authenticate as an
+// operator, with the admin token or an API key, or as an agent, with its
+// credential. Otherwise any caller could leave out the tenant to see and
+// change every probe. Agents exchanging a bootstrap token for a credential
+// do not have one yet, so that operation is let through.
+func (s Server) RequireTenant(f strictnethttp.StrictHTTPHandlerFunc, operationID string) strictnethttp.StrictHTTPHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error) {
+		if s.callerTenant(ctx) != "" || !s.TenantIsolation || operationID == "CreateAgentCredential" || s.untenantedCaller(ctx) {
+			return f(ctx, w, r, request)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		err := json.NewEncoder(w).Encode(v1.ErrorObject{Message: "a tenant is required, unless the request carries the admin token, an API key or an agent credential"})
+		return nil, err
+	}
+}
+
+// untenantedCaller reports whether the request authenticates as a caller that
+// may act without a tenant.
+func (s Server) untenantedCaller(ctx context.Context) bool {
+	if _, ok := apikeys.FromContext(ctx); ok {
+		return true
+	}
+	return agentauth.AgentFromContext(ctx) != "" || s.hasAdminToken(ctx)
+}
+
+// validateTenant reports a tenant that cannot be stored as a label value.
+func validateTenant(tenant string) error {
+	if errs := validation.IsValidLabelValue(tenant); len(errs) > 0 {
+		return fmt.Errorf("invalid tenant %q: %s", tenant, errs[0])
+	}
+	return nil
+}
+
+// ownedBy reports whether the tenant may see the probe.
+func ownedBy(probe v1.ProbeObject, tenant string) bool {
+	if tenant == "" {
+		return true
+	}
+	return probe.Labels != nil && (*probe.Labels)[tenantLabelKey] == tenant
+}
+
+// getProbe reads a probe on behalf of the caller. Probes of other tenants are
+// reported as not found, so their existence is not revealed.
+func (s Server) getProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	probe, err := s.Store.GetProbe(ctx, probeID)
+	if err != nil {
+		return nil, err
+	}
+	if !ownedBy(*probe, s.callerTenant(ctx)) {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+	}
+	return probe, nil
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	server.TenantIsolation = true
+
+	teamA := limits.WithTenant(context.Background(), "team-a")
+	teamB := limits.WithTenant(context.Background(), "team-b")
+	operator := context.Background()
+
+	res, err := server.CreateProbe(teamA, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://a.example.com",
+		Labels:    &v1.LabelsSchema{"env": "prod"},
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	probeA := res.(v1.CreateProbe201JSONResponse)
+	require.NotNil(t, probeA.Labels)
+	assert.Equal(t, "team-a", (*probeA.Labels)[tenantLabelKey])
+	assert.Equal(t, "prod", (*probeA.Labels)["env"])
+
+	res, err = server.CreateProbe(teamB, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://b.example.com"}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+
+	listURLs := func(ctx context.Context) []string {
+		res, err := server.ListProbes(ctx, v1.ListProbesRequestObject{})
+		require.NoError(t, err)
+		require.IsType(t, v1.ListProbes200JSONResponse{}, res)
+		var urls []string
+		for _, p := range res.(v1.ListProbes200JSONResponse).Body.Probes {
+			urls = append(urls, p.StaticUrl)
+		}
+		return urls
+	}
+
+	t.Run("lists only the caller's probes", func(t *testing.T) {
+		assert.Equal(t, []string{"https://a.example.com"}, listURLs(teamA))
+		assert.Equal(t, []string{"https://b.example.com"}, listURLs(teamB))
+		assert.ElementsMatch(t, []string{"https://a.example.com", "https://b.example.com"}, listURLs(operator))
+	})
+
+	t.Run("probes of other tenants are not found", func(t *testing.T) {
+		get, err := server.GetProbeById(teamB, v1.GetProbeByIdRequestObject{ProbeId: probeA.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeById404JSONResponse{}, get)
+
+		active := v1.Active
+		update, err := server.UpdateProbe(teamB, v1.UpdateProbeRequestObject{ProbeId: probeA.Id, Body: &v1.UpdateProbeJSONRequestBody{Status: &active}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbe404JSONResponse{}, update)
+
+		del, err := server.DeleteProbe(teamB, v1.DeleteProbeRequestObject{ProbeId: probeA.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteProbe404JSONResponse{}, del)
+
+		results, err := server.ListProbeResults(teamB, v1.ListProbeResultsRequestObject{ProbeId: probeA.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbeResults404JSONResponse{}, results)
+
+		stored, err := store.GetProbe(context.Background(), probeA.Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1.Pending, stored.Status, "another tenant must not change the probe")
+	})
+
+	t.Run("the owner and untenanted callers can read the probe", func(t *testing.T) {
+		for _, ctx := range []context.Context{teamA, operator} {
+			get, err := server.GetProbeById(ctx, v1.GetProbeByIdRequestObject{ProbeId: probeA.Id})
+			require.NoError(t, err)
+			assert.IsType(t, v1.GetProbeById200JSONResponse{}, get)
+		}
+	})
+
+	t.Run("the tenant label cannot be changed", func(t *testing.T) {
+		update, err := server.UpdateProbe(teamA, v1.UpdateProbeRequestObject{ProbeId: probeA.Id, Body: &v1.UpdateProbeJSONRequestBody{
+			Labels: &v1.LabelsSchema{tenantLabelKey: "team-b"},
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateProbe403JSONResponse{}, update)
+	})
+
+	t.Run("an invalid tenant is rejected", func(t *testing.T) {
+		invalid := limits.WithTenant(context.Background(), "team a")
+		list, err := server.ListProbes(invalid, v1.ListProbesRequestObject{})
+		require.NoError(t, err)
+		assert.IsType(t, v1.ListProbes400JSONResponse{}, list)
+	})
+
+	t.Run("the owner can delete the probe", func(t *testing.T) {
+		del, err := server.DeleteProbe(teamA, v1.DeleteProbeRequestObject{ProbeId: probeA.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteProbe204Response{}, del)
+	})
+}
+
+func TestRequireTenant(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	manager, err := apikeys.NewManager(store, 0)
+	require.NoError(t, err)
+	issuer, err := agentauth.NewIssuer([]byte(strings.Repeat("k", agentauth.MinKeyLength)), time.Hour, 0)
+	require.NoError(t, err)
+	server := NewServer(store)
+	server.TenantIsolation = true
+	server.AdminToken = testAdminToken
+	server.APIKeys = manager
+
+	_, secret, err := manager.Mint(context.Background(), "ci", 0, 0)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodGet, "/probes", nil)
+	r.Header.Set("Authorization", "Bearer "+secret)
+	var apiKey context.Context
+	audit.Middleware(apikeys.Middleware(manager)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		apiKey = r.Context()
+	}))).ServeHTTP(httptest.NewRecorder(), r)
+	require.NotNil(t, apiKey)
+	bootstrap, err := issuer.MintBootstrap("agent-1", 0)
+	require.NoError(t, err)
+	cred, err := issuer.Exchange(context.Background(), bootstrap.Token, "agent-1")
+	require.NoError(t, err)
+
+	call := func(ctx context.Context, operationID string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler := server.RequireTenant(func(context.Context, http.ResponseWriter, *http.Request, any) (any, error) {
+			return "handled", nil
+		}, operationID)
+		res, err := handler(ctx, w, httptest.NewRequest(http.MethodGet, "/probes", nil), nil)
+		require.NoError(t, err)
+		if res == nil {
+			return w.Code
+		}
+		return http.StatusOK
+	}
+
+	t.Run("requests without a tenant or credential are rejected", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, call(context.Background(), "ListProbes"))
+		assert.Equal(t, http.StatusForbidden, call(bearerContext(issuer, "not-the-token"), "ListProbes"))
+	})
+
+	t.Run("tenants, operators and agents are let through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(limits.WithTenant(context.Background(), "team-a"), "ListProbes"))
+		assert.Equal(t, http.StatusOK, call(bearerContext(issuer, testAdminToken), "ListProbes"))
+		assert.Equal(t, http.StatusOK, call(apiKey, "ListProbes"))
+		assert.Equal(t, http.StatusOK, call(bearerContext(issuer, cred.Credential), "ListProbes"))
+	})
+
+	t.Run("agents exchange bootstrap tokens without a tenant", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, call(context.Background(), "CreateAgentCredential"))
+	})
+
+	t.Run("nothing is rejected without tenant isolation", func(t *testing.T) {
+		server.TenantIsolation = false
+		defer func() { server.TenantIsolation = true }()
+		assert.Equal(t, http.StatusOK, call(context.Background(), "ListProbes"))
+	})
+}
+
+func TestTenantIsolationDisabled(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+
+	res, err := server.CreateProbe(limits.WithTenant(context.Background(), "dashboards"), v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://a.example.com",
+	}})
+	require.NoError(t, err)
+	require.IsType(t, v1.CreateProbe201JSONResponse{}, res)
+	assert.NotContains(t, *res.(v1.CreateProbe201JSONResponse).Labels, tenantLabelKey, "tenants of limit policies must not label probes")
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultTerminatingGracePeriod is the TerminatingGracePeriod of servers made
+// by NewServer.
+const DefaultTerminatingGracePeriod = time.Hour
+
+// terminatingReconcileInterval is how often terminating probes are checked.
+const terminatingReconcileInterval = time.Minute
+
+// ReconcileTerminatingProbes runs a periodic loop that removes probes which
+// have been terminating for longer than the grace period. A terminating probe
+// is normally removed when its agent confirms the cleanup by setting the
+// status to deleted; this catches probes whose agent is gone.
+func (s Server) ReconcileTerminatingProbes(ctx context.Context) {
+	ctx = logging.With(ctx, "operation", "terminating_probes")
+	slog.InfoContext(ctx, "Starting terminating probe reconciliation", "grace_period", s.TerminatingGracePeriod)
+	ticker := time.NewTicker(terminatingReconcileInterval)
+	defer ticker.Stop()
+	s.reconcileTerminatingProbes(ctx, time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			s.reconcileTerminatingProbes(ctx, now)
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "Stopping terminating probe reconciliation")
+			return
+		}
+	}
+}
+
+func (s Server) reconcileTerminatingProbes(ctx context.Context, now time.Time) {
+	selector := fmt.Sprintf("%s=%s,%s=%s", baseAppLabelKey, baseAppLabelValue, probeStatusLabelKey, v1.Terminating)
+	probes, err := s.Store.ListProbes(ctx, selector)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error listing terminating probes", "error", err)
+		return
+	}
+
+	pending := 0
+	for _, probe := range probes {
+		probeCtx := logging.With(ctx, "probe_id", probe.Id)
+		// The probe is only changed at the listed version, so an agent that
+		// confirms the deletion meanwhile is not overridden.
+		if probe.ResourceVersion != nil {
+			probeCtx = probestore.WithResourceVersion(probeCtx, *probe.ResourceVersion)
+		}
+
+		if probe.DeletionTimestamp == nil {
+			// Probes moved to terminating by store garbage collection have no
+			// timestamp yet; the store records the current time.
+			probe.Status = v1.Terminating
+			gcCtx := probestore.WithStatusChange(probeCtx, audit.SystemActor, "marked terminating by garbage collection")
+			if _, err := s.Store.UpdateProbe(gcCtx, probe); err != nil && !k8serrors.IsConflict(err) && !k8serrors.IsNotFound(err) {
+				slog.ErrorContext(probeCtx, "Error recording deletion timestamp", "error", err)
+			}
+			pending++
+			continue
+		}
+
+		if now.Sub(*probe.DeletionTimestamp) < s.TerminatingGracePeriod {
+			pending++
+			continue
+		}
+		if err := s.Store.DeleteProbeStorage(probeCtx, probe.Id); err != nil {
+			if !k8serrors.IsConflict(err) && !k8serrors.IsNotFound(err) {
+				slog.ErrorContext(probeCtx, "Error deleting terminating probe", "error", err)
+				pending++
+			}
+			continue
+		}
+		previousStatus := probe.Status
+		s.Audit.Record(audit.WithActor(probeCtx, audit.SystemActor), v1.RemoveTerminatingProbe, probe.Id, &probe, nil)
+		s.Webhooks.Notify(probeCtx, v1.ProbeDeleted, probe, &previousStatus)
+		s.Events.Publish(probeCtx, v1.ProbeDeleted, probe, &previousStatus)
+		slog.InfoContext(probeCtx, "Deleted probe that stayed terminating past the grace period", "deletion_timestamp", probe.DeletionTimestamp, "grace_period", s.TerminatingGracePeriod)
+	}
+	metrics.SetProbesPendingDeletion(pending)
+}
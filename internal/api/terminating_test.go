@@ -0,0 +1,79 @@
+//go:build !nokube
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcileTerminatingProbes(t *testing.T) {
+	ctx := context.Background()
+	const namespace = "rhobs"
+	clientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}})
+	store, err := probestore.NewKubernetesProbeStore(ctx, clientset, namespace)
+	require.NoError(t, err)
+	server := NewServer(store)
+	server.TerminatingGracePeriod = time.Hour
+
+	create := func(url string) *v1.ProbeObject {
+		t.Helper()
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: url, Status: v1.Active}, probeURLHash(url))
+		require.NoError(t, err)
+		return probe
+	}
+	active := create("https://active.example.com")
+	terminating := create("https://terminating.example.com")
+	terminating.Status = v1.Terminating
+	_, err = store.UpdateProbe(ctx, *terminating)
+	require.NoError(t, err)
+
+	// Store garbage collection only relabels the ConfigMap, so the probe has
+	// no deletion timestamp until the reconciler records one.
+	collected := create("https://collected.example.com")
+	cmName := fmt.Sprintf("probe-config-%s", collected.Id)
+	cm, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, cmName, metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Labels[probeStatusLabelKey] = string(v1.Terminating)
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	t.Run("records missing deletion timestamps", func(t *testing.T) {
+		server.reconcileTerminatingProbes(ctx, time.Now())
+		probe, err := store.GetProbe(ctx, collected.Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1.Terminating, probe.Status)
+		require.NotNil(t, probe.DeletionTimestamp)
+		assert.WithinDuration(t, time.Now(), *probe.DeletionTimestamp, 2*time.Second)
+	})
+
+	t.Run("keeps probes within the grace period", func(t *testing.T) {
+		server.reconcileTerminatingProbes(ctx, time.Now().Add(30*time.Minute))
+		for _, id := range []uuid.UUID{active.Id, terminating.Id, collected.Id} {
+			_, err := store.GetProbe(ctx, id)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("deletes probes past the grace period", func(t *testing.T) {
+		server.reconcileTerminatingProbes(ctx, time.Now().Add(2*time.Hour))
+		for _, id := range []uuid.UUID{terminating.Id, collected.Id} {
+			_, err := store.GetProbe(ctx, id)
+			assert.True(t, k8serrors.IsNotFound(err), "terminating probe %s should be deleted, got %v", id, err)
+		}
+		_, err := store.GetProbe(ctx, active.Id)
+		assert.NoError(t, err, "active probes are never deleted")
+	})
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// tombstone returns the tombstone of a probe that is not in the store, or nil
+// if it has none or the store keeps none. Tombstones do not record tenants,
+// so callers scoped to a tenant never get one; otherwise they would learn
+// about the probes of other tenants.
+func (s Server) tombstone(ctx context.Context, probeID uuid.UUID) *probestore.Tombstone {
+	tombstones, ok := probestore.Implements[probestore.TombstoneStore](s.Store)
+	if !ok || s.callerTenant(ctx) != "" {
+		return nil
+	}
+	tombstone, err := tombstones.GetTombstone(ctx, probeID)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			slog.WarnContext(ctx, "Error getting probe tombstone", "probe_id", probeID, "error", err)
+		}
+		return nil
+	}
+	return tombstone
+}
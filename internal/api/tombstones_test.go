@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProbeById_Tombstone(t *testing.T) {
+	ctx := context.Background()
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}})
+	require.NoError(t, err)
+	probeID := res.(v1.CreateProbe201JSONResponse).Id
+	_, err = server.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: probeID})
+	require.NoError(t, err)
+
+	t.Run("recently deleted probes are gone", func(t *testing.T) {
+		res, err := server.GetProbeById(ctx, v1.GetProbeByIdRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		gone, ok := res.(v1.GetProbeById410JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, probeID, gone.ProbeId)
+		assert.False(t, gone.DeletedAt.IsZero())
+		assert.Contains(t, gone.Warning.Message, "was deleted at")
+	})
+
+	t.Run("unknown probes are not found", func(t *testing.T) {
+		res, err := server.GetProbeById(ctx, v1.GetProbeByIdRequestObject{ProbeId: uuid.New()})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeById404JSONResponse{}, res)
+	})
+
+	t.Run("tenants get no tombstones", func(t *testing.T) {
+		server := server
+		server.TenantIsolation = true
+		res, err := server.GetProbeById(limits.WithTenant(ctx, "team-a"), v1.GetProbeByIdRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeById404JSONResponse{}, res)
+	})
+
+	t.Run("stores without tombstones", func(t *testing.T) {
+		server := NewServer(probestore.NewMemoryProbeStore())
+		res, err := server.GetProbeById(ctx, v1.GetProbeByIdRequestObject{ProbeId: probeID})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetProbeById404JSONResponse{}, res)
+	})
+}
@@ -0,0 +1,396 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// ProbeReview is what a Validator decides on: a probe about to be created or
+// updated.
+type ProbeReview struct {
+	// Probe is the probe as it would be stored, with the status an update
+	// moves it to.
+	Probe v1.ProbeObject
+	// Existing is the probe before an update, nil on create.
+	Existing *v1.ProbeObject
+	// Labels are the labels the request sets, before templates and mutation
+	// hooks added any.
+	Labels v1.LabelsSchema
+	// Tenant is the tenant the request is scoped to, "" for untenanted
+	// callers.
+	Tenant string
+	// Pending is how many probes the same request creates before this one,
+	// which the store does not hold yet: those of an import ahead of it in
+	// the bundle.
+	Pending int
+}
+
+// IsCreate reports whether the review is of a probe being created.
+func (r ProbeReview) IsCreate() bool {
+	return r.Existing == nil
+}
+
+// Validator accepts or rejects probes before they are created or updated,
+// like validating admission webhooks do for Kubernetes objects.
+type Validator interface {
+	// Name identifies the validator in metrics.
+	Name() string
+	// Validate returns why the probe is rejected, nil to accept it. Errors
+	// wrapped with Forbidden are reported as 403, those wrapped with Failed
+	// as 500, and others as 400.
+	Validate(ctx context.Context, review ProbeReview) error
+}
+
+type funcValidator struct {
+	name string
+	fn   func(context.Context, ProbeReview) error
+}
+
+func (v funcValidator) Name() string { return v.name }
+
+func (v funcValidator) Validate(ctx context.Context, review ProbeReview) error {
+	return v.fn(ctx, review)
+}
+
+// ValidatorFunc returns a validator that calls fn.
+func ValidatorFunc(name string, fn func(ctx context.Context, review ProbeReview) error) Validator {
+	return funcValidator{name: name, fn: fn}
+}
+
+// forbiddenError marks a rejection of what the caller may do, such as setting
+// a protected label or exceeding a quota, rather than of a malformed request.
+type forbiddenError struct {
+	err error
+}
+
+func (e forbiddenError) Error() string { return e.err.Error() }
+
+func (e forbiddenError) Unwrap() error { return e.err }
+
+// Forbidden marks err as a rejection reported with 403 rather than 400.
+func Forbidden(err error) error {
+	return forbiddenError{err: err}
+}
+
+// isForbidden reports whether err was marked with Forbidden.
+func isForbidden(err error) bool {
+	_, ok := errors.AsType[forbiddenError](err)
+	return ok
+}
+
+// failedError marks an error that kept a validator from deciding, such as a
+// store it reads being unavailable.
+type failedError struct {
+	err error
+}
+
+func (e failedError) Error() string { return e.err.Error() }
+
+func (e failedError) Unwrap() error { return e.err }
+
+// Failed marks err as a failure to validate rather than a rejection, so the
+// request fails with 500.
+func Failed(err error) error {
+	return failedError{err: err}
+}
+
+// isFailed reports whether err was marked with Failed.
+func isFailed(err error) bool {
+	_, ok := errors.AsType[failedError](err)
+	return ok
+}
+
+// fieldError marks an error about one field of a probe, which imports report
+// with the position of the probe in the bundle.
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e fieldError) Error() string { return e.err.Error() }
+
+func (e fieldError) Unwrap() error { return e.err }
+
+// inField marks err as about the named field of the probe, keeping the
+// message as is. It returns nil for a nil err.
+func inField(field string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fieldError{field: field, err: err}
+}
+
+// errorField returns the field err was marked with by inField, "" when it
+// was not.
+func errorField(err error) string {
+	if e, ok := errors.AsType[fieldError](err); ok {
+		return e.field
+	}
+	return ""
+}
+
+// Validators run in order on every probe being created or updated.
+type Validators []Validator
+
+// Validate runs every validator on the review, stopping at the first that
+// rejects it, whose error is returned as is.
+func (vs Validators) Validate(ctx context.Context, review ProbeReview) error {
+	for _, v := range vs {
+		start := time.Now()
+		err := v.Validate(ctx, review)
+		result := "accepted"
+		switch {
+		case err == nil:
+		case isFailed(err):
+			result = "error"
+		default:
+			result = "rejected"
+		}
+		metrics.RecordProbeValidation(v.Name(), result, start)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validators returns the built-in validators, which every probe must pass,
+// followed by the configured ones.
+//
+// Creates, updates and imports run them once the rest of the request is
+// applied to the probe, so they see it as it would be stored. A few checks
+// stay outside them. The static_url conflict is not a
+// rejection of the probe but a race with the store, answered 409 and checked
+// as close to the write as possible. Schedule defaults and the auth split
+// change the probe rather than judge it: they fill in fields and move
+// credentials to the secret store, failing only on values they cannot use.
+func (s Server) validators() Validators {
+	return append(Validators{
+		ValidatorFunc("tenant", func(_ context.Context, review ProbeReview) error {
+			if review.Tenant == "" {
+				return nil
+			}
+			return validateTenant(review.Tenant)
+		}),
+		ValidatorFunc("label_policy", func(_ context.Context, review ProbeReview) error {
+			// A new probe has no existing labels, so any system-managed label
+			// is rejected.
+			var old v1.LabelsSchema
+			if review.Existing != nil && review.Existing.Labels != nil {
+				old = *review.Existing.Labels
+			}
+			if err := s.LabelPolicy().validate(review.Labels, old); err != nil {
+				return Forbidden(inField("labels", err))
+			}
+			return nil
+		}),
+		ValidatorFunc("heartbeat", func(ctx context.Context, review ProbeReview) error {
+			return s.checkHeartbeat(ctx, review.Labels)
+		}),
+		// Alerting and owner are only checked when they change, so probes
+		// stored before a check was tightened can still be updated.
+		ValidatorFunc("alerting", func(_ context.Context, review ProbeReview) error {
+			if review.Existing != nil && reflect.DeepEqual(review.Probe.Alerting, review.Existing.Alerting) {
+				return nil
+			}
+			return inField("alerting", validateAlerting(review.Probe.Alerting))
+		}),
+		ValidatorFunc("owner", func(_ context.Context, review ProbeReview) error {
+			if review.Existing != nil && reflect.DeepEqual(review.Probe.Owner, review.Existing.Owner) {
+				return nil
+			}
+			return inField("owner", validateOwner(review.Probe.Owner))
+		}),
+		// Assertions are checked against the module the probe ends up with,
+		// which an update may change.
+		ValidatorFunc("assertions", func(_ context.Context, review ProbeReview) error {
+			return inField("assertions", review.Probe.ValidateAssertions())
+		}),
+	}, s.Validators...)
+}
+
+// ValidatorConfig selects a built-in validator in the probe_validators list
+// of the config file.
+type ValidatorConfig struct {
+	// Name is one of url_policy, quota, required_labels or required_owner.
+	Name string `mapstructure:"name"`
+	// AllowedSchemes are the URL schemes url_policy accepts; empty accepts
+	// any.
+	AllowedSchemes []string `mapstructure:"allowed_schemes"`
+	// AllowedHosts are the hosts url_policy accepts, "*.example.com"
+	// matching any subdomain of example.com; empty accepts any host not
+	// denied.
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+	// DeniedHosts are the hosts url_policy rejects, in the same form.
+	DeniedHosts []string `mapstructure:"denied_hosts"`
+	// MaxProbes is how many probes quota lets each tenant have, and callers
+	// without a tenant have in all. Zero means no limit.
+	MaxProbes int `mapstructure:"max_probes"`
+	// Tenants override MaxProbes for the tenants they name.
+	Tenants map[string]int `mapstructure:"tenants"`
+	// Labels are the label keys required_labels requires of new probes.
+	Labels []string `mapstructure:"labels"`
+	// Fields are the owner fields required_owner requires of new probes:
+	// team, escalation_contact or both.
+	Fields []string `mapstructure:"fields"`
+}
+
+// BuildValidators returns the built-in validators described by configs, in
+// order. The quota validator counts the probes of store.
+func BuildValidators(configs []ValidatorConfig, store probestore.ProbeStorage) (Validators, error) {
+	validators := make(Validators, 0, len(configs))
+	for i, cfg := range configs {
+		var (
+			v   Validator
+			err error
+		)
+		switch cfg.Name {
+		case "url_policy":
+			v, err = URLPolicy(cfg.AllowedSchemes, cfg.AllowedHosts, cfg.DeniedHosts)
+		case "quota":
+			v, err = Quota(store, cfg.MaxProbes, cfg.Tenants)
+		case "required_labels":
+			v, err = RequiredLabels(cfg.Labels)
+		case "required_owner":
+			v, err = RequiredOwner(cfg.Fields)
+		default:
+			err = fmt.Errorf("unknown validator %q, expected one of url_policy, quota, required_labels, required_owner", cfg.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("probe_validators[%d]: %w", i, err)
+		}
+		validators = append(validators, v)
+	}
+	return validators, nil
+}
+
+// URLPolicy returns a validator that rejects probes checking URLs whose
+// scheme is not allowed or whose host is denied or not allowed. Only the URLs
+// a request adds are checked, so probes created before the policy changed
+// can still be updated.
+func URLPolicy(allowedSchemes, allowedHosts, deniedHosts []string) (Validator, error) {
+	for _, pattern := range slices.Concat(allowedHosts, deniedHosts) {
+		if host := strings.TrimPrefix(pattern, "*."); host == "" || strings.Contains(host, "*") {
+			return nil, fmt.Errorf("invalid host pattern %q, expected a host or *.domain", pattern)
+		}
+	}
+	return ValidatorFunc("url_policy", func(_ context.Context, review ProbeReview) error {
+		var existing []string
+		if review.Existing != nil {
+			existing = targetURLs(*review.Existing)
+		}
+		for _, target := range targetURLs(review.Probe) {
+			if slices.Contains(existing, target) {
+				continue
+			}
+			field := "static_url"
+			if target != review.Probe.StaticUrl {
+				field = "additional_urls"
+			}
+			scheme, host := targetHost(target)
+			if len(allowedSchemes) > 0 && !slices.ContainsFunc(allowedSchemes, func(s string) bool { return strings.EqualFold(s, scheme) }) {
+				return Forbidden(inField(field, fmt.Errorf("probe target %q uses scheme %q, expected one of %s", target, scheme, strings.Join(allowedSchemes, ", "))))
+			}
+			if matchesHost(deniedHosts, host) || (len(allowedHosts) > 0 && !matchesHost(allowedHosts, host)) {
+				return Forbidden(inField(field, fmt.Errorf("probe target %q is not allowed by the URL policy", target)))
+			}
+		}
+		return nil
+	}), nil
+}
+
+// targetHost returns the lower-cased scheme and host of a probe target. The
+// host of dns targets without a resolver is the name they query.
+func targetHost(target string) (scheme, host string) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", ""
+	}
+	host = u.Hostname()
+	if host == "" {
+		host, _, _ = strings.Cut(u.Opaque, "/")
+	}
+	return strings.ToLower(u.Scheme), strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// matchesHost reports whether host is one of patterns, "*.example.com"
+// matching the subdomains of example.com but not example.com itself.
+func matchesHost(patterns []string, host string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		pattern = strings.ToLower(pattern)
+		if domain, ok := strings.CutPrefix(pattern, "*."); ok {
+			return strings.HasSuffix(host, "."+domain)
+		}
+		return host == pattern
+	})
+}
+
+// Quota returns a validator that rejects new probes once their tenant has
+// maxProbes probes that are not terminating, or the count tenants sets for
+// it. Callers without a tenant are held to maxProbes across all probes.
+func Quota(store probestore.ProbeStorage, maxProbes int, tenants map[string]int) (Validator, error) {
+	if maxProbes < 0 {
+		return nil, fmt.Errorf("max_probes must not be negative, got %d", maxProbes)
+	}
+	for tenant, limit := range tenants {
+		if limit < 0 {
+			return nil, fmt.Errorf("the quota of tenant %q must not be negative, got %d", tenant, limit)
+		}
+	}
+	return ValidatorFunc("quota", func(ctx context.Context, review ProbeReview) error {
+		if !review.IsCreate() {
+			return nil
+		}
+		limit, ok := tenants[review.Tenant]
+		if !ok {
+			limit = maxProbes
+		}
+		if limit == 0 {
+			return nil
+		}
+		selector := fmt.Sprintf("%s=%s,%s notin (%s)", baseAppLabelKey, baseAppLabelValue, probeStatusLabelKey, v1.Terminating)
+		if review.Tenant != "" {
+			selector += fmt.Sprintf(",%s=%s", tenantLabelKey, review.Tenant)
+		}
+		probes, err := store.ListProbes(ctx, selector)
+		if err != nil {
+			return Failed(fmt.Errorf("failed to count probes for the quota: %w", err))
+		}
+		if inUse := len(probes) + review.Pending; inUse >= limit {
+			if review.Tenant == "" {
+				return Forbidden(fmt.Errorf("probe quota exceeded: %d of %d probes in use", inUse, limit))
+			}
+			return Forbidden(fmt.Errorf("probe quota of tenant %q exceeded: %d of %d probes in use", review.Tenant, inUse, limit))
+		}
+		return nil
+	}), nil
+}
+
+// RequiredLabels returns a validator that rejects new probes missing any of
+// the label keys, such as an owning team.
+func RequiredLabels(keys []string) (Validator, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("labels must list at least one label key")
+	}
+	return ValidatorFunc("required_labels", func(_ context.Context, review ProbeReview) error {
+		if !review.IsCreate() {
+			return nil
+		}
+		for _, key := range keys {
+			if review.Probe.Labels == nil || (*review.Probe.Labels)[key] == "" {
+				return inField("labels", fmt.Errorf("label %q is required", key))
+			}
+		}
+		return nil
+	}), nil
+}
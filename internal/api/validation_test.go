@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidators(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx := context.Background()
+
+	var reviews []ProbeReview
+	server.Validators = Validators{
+		ValidatorFunc("recorder", func(_ context.Context, review ProbeReview) error {
+			reviews = append(reviews, review)
+			return nil
+		}),
+		ValidatorFunc("no_staging", func(_ context.Context, review ProbeReview) error {
+			if review.Probe.Labels != nil && (*review.Probe.Labels)["env"] == "staging" {
+				return errors.New("staging probes are not allowed")
+			}
+			return nil
+		}),
+	}
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+		StaticUrl: "https://example.com",
+		Labels:    &v1.LabelsSchema{"env": "prod"},
+	}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateProbe201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	require.Len(t, reviews, 1)
+	assert.True(t, reviews[0].IsCreate())
+	assert.Equal(t, "https://example.com", reviews[0].Probe.StaticUrl)
+	assert.Equal(t, v1.LabelsSchema{"env": "prod"}, reviews[0].Labels)
+
+	t.Run("rejections are reported as 400", func(t *testing.T) {
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			StaticUrl: "https://staging.example.com",
+			Labels:    &v1.LabelsSchema{"env": "staging"},
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.CreateProbe400JSONResponse{}, res)
+		assert.Equal(t, "staging probes are not allowed", res.(v1.CreateProbe400JSONResponse).Error.Message)
+
+		status := v1.Active
+		updateRes, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: created.Id, Body: &v1.UpdateProbeJSONRequestBody{
+			Labels: &v1.LabelsSchema{"env": "staging"},
+			Status: &status,
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe400JSONResponse{}, updateRes)
+		stored, err := store.GetProbe(ctx, created.Id)
+		require.NoError(t, err)
+		assert.Equal(t, "prod", (*stored.Labels)["env"], "rejected updates change nothing")
+	})
+
+	t.Run("updates are reviewed with the probe they replace", func(t *testing.T) {
+		reviews = nil
+		status := v1.Active
+		res, err := server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: created.Id, Body: &v1.UpdateProbeJSONRequestBody{
+			Labels: &v1.LabelsSchema{"team": "sre"},
+			Status: &status,
+		}})
+		require.NoError(t, err)
+		require.IsType(t, v1.UpdateProbe200JSONResponse{}, res)
+		require.Len(t, reviews, 1)
+		review := reviews[0]
+		assert.False(t, review.IsCreate())
+		assert.Equal(t, v1.Pending, review.Existing.Status)
+		assert.Equal(t, v1.Active, review.Probe.Status)
+		assert.NotContains(t, *review.Existing.Labels, "team")
+		assert.Equal(t, "sre", (*review.Probe.Labels)["team"])
+		assert.Equal(t, v1.LabelsSchema{"team": "sre"}, review.Labels)
+	})
+
+	t.Run("built-in validators run first", func(t *testing.T) {
+		reviews = nil
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{
+			StaticUrl: "https://example.com/protected",
+			Labels:    &v1.LabelsSchema{baseAppLabelKey: "other"},
+		}})
+		require.NoError(t, err)
+		assert.IsType(t, v1.CreateProbe403JSONResponse{}, res)
+		assert.Empty(t, reviews)
+	})
+
+	t.Run("failures to validate are errors", func(t *testing.T) {
+		server := server
+		server.Validators = Validators{ValidatorFunc("broken", func(context.Context, ProbeReview) error {
+			return Failed(errors.New("store down"))
+		})}
+		_, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com/broken"}})
+		assert.ErrorContains(t, err, "failed to validate probe: store down")
+	})
+}
+
+func TestURLPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy, err := URLPolicy([]string{"https", "tcp", "dns"}, []string{"*.example.com", "example.com"}, []string{"internal.example.com"})
+	require.NoError(t, err)
+
+	for target, allowed := range map[string]bool{
+		"https://example.com/healthz":             true,
+		"https://API.Example.com:8443/healthz":    true,
+		"tcp://db.example.com:5432":               true,
+		"dns:www.example.com?type=A":              true,
+		"http://example.com":                      false,
+		"https://internal.example.com":            false,
+		"https://example.org":                     false,
+		"https://badexample.com":                  false,
+		"icmp://example.com":                      false,
+		"dns://1.1.1.1/www.example.com?type=AAAA": false,
+	} {
+		err := policy.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{StaticUrl: target}})
+		if allowed {
+			assert.NoError(t, err, target)
+		} else {
+			assert.True(t, isForbidden(err), "%s: %v", target, err)
+		}
+	}
+
+	additional := v1.AdditionalUrlsSchema{"https://example.org"}
+	err = policy.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{StaticUrl: "https://example.com", AdditionalUrls: &additional}})
+	assert.EqualError(t, err, `probe target "https://example.org" is not allowed by the URL policy`, "additional URLs are checked")
+
+	existing := v1.ProbeObject{StaticUrl: "https://example.org"}
+	err = policy.Validate(ctx, ProbeReview{Probe: existing, Existing: &existing})
+	assert.NoError(t, err, "URLs the probe already checks are accepted")
+
+	_, err = URLPolicy(nil, []string{"*.*.example.com"}, nil)
+	assert.EqualError(t, err, `invalid host pattern "*.*.example.com", expected a host or *.domain`)
+}
+
+func TestQuota(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	server.TenantIsolation = true
+	quota, err := Quota(store, 1, map[string]int{"team-b": 2})
+	require.NoError(t, err)
+	server.Validators = Validators{quota}
+
+	create := func(ctx context.Context, url string) v1.CreateProbeResponseObject {
+		t.Helper()
+		res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: url}})
+		require.NoError(t, err)
+		return res
+	}
+	teamA := limits.WithTenant(context.Background(), "team-a")
+	teamB := limits.WithTenant(context.Background(), "team-b")
+
+	first, ok := create(teamA, "https://a.example.com/1").(v1.CreateProbe201JSONResponse)
+	require.True(t, ok)
+	res := create(teamA, "https://a.example.com/2")
+	require.IsType(t, v1.CreateProbe403JSONResponse{}, res)
+	assert.Equal(t, `probe quota of tenant "team-a" exceeded: 1 of 1 probes in use`, res.(v1.CreateProbe403JSONResponse).Error.Message)
+
+	assert.IsType(t, v1.CreateProbe201JSONResponse{}, create(teamB, "https://b.example.com/1"))
+	assert.IsType(t, v1.CreateProbe201JSONResponse{}, create(teamB, "https://b.example.com/2"), "tenants may have their own quota")
+	assert.IsType(t, v1.CreateProbe403JSONResponse{}, create(context.Background(), "https://ops.example.com"), "untenanted callers count every probe")
+
+	require.NoError(t, store.DeleteProbe(teamA, first.Id))
+	assert.IsType(t, v1.CreateProbe201JSONResponse{}, create(teamA, "https://a.example.com/2"), "terminating probes do not count")
+
+	_, err = Quota(store, -1, nil)
+	assert.EqualError(t, err, "max_probes must not be negative, got -1")
+}
+
+func TestRequiredLabels(t *testing.T) {
+	ctx := context.Background()
+	v, err := RequiredLabels([]string{"team"})
+	require.NoError(t, err)
+
+	assert.EqualError(t, v.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{}}), `label "team" is required`)
+	assert.NoError(t, v.Validate(ctx, ProbeReview{Probe: v1.ProbeObject{Labels: &v1.LabelsSchema{"team": "sre"}}}))
+	existing := v1.ProbeObject{Id: uuid.New()}
+	assert.NoError(t, v.Validate(ctx, ProbeReview{Probe: existing, Existing: &existing}), "probes created before the requirement can be updated")
+}
+
+func TestBuildValidators(t *testing.T) {
+	validators, err := BuildValidators([]ValidatorConfig{
+		{Name: "url_policy", AllowedSchemes: []string{"https"}},
+		{Name: "quota", MaxProbes: 100},
+		{Name: "required_labels", Labels: []string{"team"}},
+		{Name: "required_owner", Fields: []string{"team"}},
+	}, nil)
+	require.NoError(t, err)
+	var names []string
+	for _, v := range validators {
+		names = append(names, v.Name())
+	}
+	assert.Equal(t, []string{"url_policy", "quota", "required_labels", "required_owner"}, names)
+
+	_, err = BuildValidators([]ValidatorConfig{{Name: "quota"}, {Name: "opa"}}, nil)
+	assert.EqualError(t, err, `probe_validators[1]: unknown validator "opa", expected one of url_policy, quota, required_labels, required_owner`)
+	_, err = BuildValidators([]ValidatorConfig{{Name: "required_labels"}}, nil)
+	assert.EqualError(t, err, "probe_validators[0]: labels must list at least one label key")
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/webhooks"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// (GET /webhooks)
+func (s Server) ListWebhooks(ctx context.Context, request v1.ListWebhooksRequestObject) (v1.ListWebhooksResponseObject, error) {
+	subs := s.Webhooks.List()
+	objs := make([]v1.WebhookObject, 0, len(subs))
+	for _, sub := range subs {
+		objs = append(objs, webhookObject(sub))
+	}
+	return v1.ListWebhooks200JSONResponse{Webhooks: objs}, nil
+}
+
+// (POST /webhooks)
+func (s Server) CreateWebhook(ctx context.Context, request v1.CreateWebhookRequestObject) (v1.CreateWebhookResponseObject, error) {
+	sub, err := s.Webhooks.Subscribe(webhookSubscription(*request.Body))
+	if err != nil {
+		return v1.CreateWebhook400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	return v1.CreateWebhook201JSONResponse(webhookObject(sub)), nil
+}
+
+// (GET /webhooks/{webhook_id})
+func (s Server) GetWebhook(ctx context.Context, request v1.GetWebhookRequestObject) (v1.GetWebhookResponseObject, error) {
+	sub, ok := s.Webhooks.Get(request.WebhookId)
+	if !ok {
+		return v1.GetWebhook404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("webhook with ID %s not found", request.WebhookId),
+			},
+		}, nil
+	}
+	return v1.GetWebhook200JSONResponse(webhookObject(sub)), nil
+}
+
+// (PUT /webhooks/{webhook_id})
+func (s Server) UpdateWebhook(ctx context.Context, request v1.UpdateWebhookRequestObject) (v1.UpdateWebhookResponseObject, error) {
+	sub, ok, err := s.Webhooks.Replace(request.WebhookId, webhookSubscription(*request.Body))
+	if err != nil {
+		return v1.UpdateWebhook400JSONResponse{
+			Error: v1.ErrorObject{
+				Message: err.Error(),
+			},
+		}, nil
+	}
+	if !ok {
+		return v1.UpdateWebhook404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("webhook with ID %s not found", request.WebhookId),
+			},
+		}, nil
+	}
+	return v1.UpdateWebhook200JSONResponse(webhookObject(sub)), nil
+}
+
+// (DELETE /webhooks/{webhook_id})
+func (s Server) DeleteWebhook(ctx context.Context, request v1.DeleteWebhookRequestObject) (v1.DeleteWebhookResponseObject, error) {
+	if !s.Webhooks.Unsubscribe(request.WebhookId) {
+		return v1.DeleteWebhook404JSONResponse{
+			Warning: v1.WarningObject{
+				Message: fmt.Sprintf("webhook with ID %s not found", request.WebhookId),
+			},
+		}, nil
+	}
+	return v1.DeleteWebhook204Response{}, nil
+}
+
+func webhookSubscription(req v1.WebhookRequest) webhooks.Subscription {
+	sub := webhooks.Subscription{URL: req.Url}
+	if req.Events != nil {
+		sub.Events = *req.Events
+	}
+	if req.Secret != nil {
+		sub.Secret = *req.Secret
+	}
+	return sub
+}
+
+// webhookObject returns the API view of a subscription, which leaves out the
+// secret and lists the events it receives.
+func webhookObject(sub webhooks.Subscription) v1.WebhookObject {
+	events := slices.Clone(sub.Events)
+	if len(events) == 0 {
+		events = slices.Clone(webhooks.EventTypes)
+	}
+	return v1.WebhookObject{
+		Id:        sub.ID,
+		Url:       sub.URL,
+		Events:    events,
+		CreatedAt: sub.CreatedAt,
+	}
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "0123456789abcdef"
+
+func TestWebhookCRUD(t *testing.T) {
+	server := NewServer(probestore.NewMemoryProbeStore())
+	ctx := context.Background()
+	secret := testWebhookSecret
+
+	res, err := server.CreateWebhook(ctx, v1.CreateWebhookRequestObject{Body: &v1.CreateWebhookJSONRequestBody{Url: "https://hooks.example.com", Secret: &secret}})
+	require.NoError(t, err)
+	created, ok := res.(v1.CreateWebhook201JSONResponse)
+	require.True(t, ok, "got %T", res)
+	assert.Equal(t, "https://hooks.example.com", created.Url)
+	assert.Equal(t, []v1.WebhookEventType{v1.ProbeCreated, v1.ProbeStatusChanged, v1.ProbeDeleted}, created.Events, "all events when none are given")
+
+	t.Run("invalid subscriptions get 400", func(t *testing.T) {
+		short := "short"
+		res, err := server.CreateWebhook(ctx, v1.CreateWebhookRequestObject{Body: &v1.CreateWebhookJSONRequestBody{Url: "https://hooks.example.com", Secret: &short}})
+		require.NoError(t, err)
+		assert.Equal(t, v1.CreateWebhook400JSONResponse{Error: v1.ErrorObject{Message: "webhook secret must be at least 16 characters long"}}, res)
+	})
+
+	t.Run("get and list", func(t *testing.T) {
+		res, err := server.GetWebhook(ctx, v1.GetWebhookRequestObject{WebhookId: created.Id})
+		require.NoError(t, err)
+		assert.Equal(t, v1.GetWebhook200JSONResponse(created), res)
+
+		list, err := server.ListWebhooks(ctx, v1.ListWebhooksRequestObject{})
+		require.NoError(t, err)
+		assert.Equal(t, v1.ListWebhooks200JSONResponse{Webhooks: []v1.WebhookObject{v1.WebhookObject(created)}}, list)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		events := []v1.WebhookEventType{v1.ProbeDeleted}
+		res, err := server.UpdateWebhook(ctx, v1.UpdateWebhookRequestObject{
+			WebhookId: created.Id,
+			Body:      &v1.UpdateWebhookJSONRequestBody{Url: "https://other.example.com", Events: &events, Secret: &secret},
+		})
+		require.NoError(t, err)
+		updated, ok := res.(v1.UpdateWebhook200JSONResponse)
+		require.True(t, ok, "got %T", res)
+		assert.Equal(t, "https://other.example.com", updated.Url)
+		assert.Equal(t, events, updated.Events)
+
+		res, err = server.UpdateWebhook(ctx, v1.UpdateWebhookRequestObject{
+			WebhookId: uuid.New(),
+			Body:      &v1.UpdateWebhookJSONRequestBody{Url: "https://other.example.com", Secret: &secret},
+		})
+		require.NoError(t, err)
+		assert.IsType(t, v1.UpdateWebhook404JSONResponse{}, res)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		res, err := server.DeleteWebhook(ctx, v1.DeleteWebhookRequestObject{WebhookId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteWebhook204Response{}, res)
+
+		res, err = server.DeleteWebhook(ctx, v1.DeleteWebhookRequestObject{WebhookId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.DeleteWebhook404JSONResponse{}, res)
+		get, err := server.GetWebhook(ctx, v1.GetWebhookRequestObject{WebhookId: created.Id})
+		require.NoError(t, err)
+		assert.IsType(t, v1.GetWebhook404JSONResponse{}, get)
+	})
+}
+
+// eventRecorder is a webhook endpoint that keeps the events it receives.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []v1.WebhookEvent
+}
+
+func (r *eventRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var event v1.WebhookEvent
+	body, _ := io.ReadAll(req.Body)
+	if err := json.Unmarshal(body, &event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) received() []v1.WebhookEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]v1.WebhookEvent(nil), r.events...)
+}
+
+func TestProbeLifecycleWebhooks(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	server := NewServer(store)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Webhooks.Run(ctx)
+
+	rec := &eventRecorder{}
+	ts := httptest.NewServer(rec)
+	defer ts.Close()
+	secret := testWebhookSecret
+	_, err := server.CreateWebhook(ctx, v1.CreateWebhookRequestObject{Body: &v1.CreateWebhookJSONRequestBody{Url: ts.URL, Secret: &secret}})
+	require.NoError(t, err)
+
+	// Events are delivered concurrently, so they are waited for one by one.
+	waitFor := func(n int) v1.WebhookEvent {
+		require.Eventually(t, func() bool { return len(rec.received()) >= n }, 5*time.Second, 10*time.Millisecond)
+		return rec.received()[n-1]
+	}
+
+	res, err := server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://example.com"}})
+	require.NoError(t, err)
+	probe := v1.ProbeObject(res.(v1.CreateProbe201JSONResponse))
+	event := waitFor(1)
+	assert.Equal(t, v1.ProbeCreated, event.Type)
+	assert.Equal(t, probe.Id, event.Probe.Id)
+	assert.Nil(t, event.PreviousStatus)
+
+	_, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Labels: &v1.LabelsSchema{"team": "sre"}}})
+	require.NoError(t, err)
+	active := v1.Active
+	_, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Status: &active}})
+	require.NoError(t, err)
+	event = waitFor(2)
+	assert.Equal(t, v1.ProbeStatusChanged, event.Type, "label changes are not notified")
+	assert.Equal(t, v1.Active, event.Probe.Status)
+	assert.Equal(t, v1.Pending, *event.PreviousStatus)
+
+	// Deleting an active probe makes it terminating.
+	_, err = server.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: probe.Id})
+	require.NoError(t, err)
+	event = waitFor(3)
+	assert.Equal(t, v1.ProbeStatusChanged, event.Type)
+	assert.Equal(t, v1.Terminating, event.Probe.Status)
+	assert.Equal(t, v1.Active, *event.PreviousStatus)
+
+	deleted := v1.Deleted
+	_, err = server.UpdateProbe(ctx, v1.UpdateProbeRequestObject{ProbeId: probe.Id, Body: &v1.UpdateProbeJSONRequestBody{Status: &deleted}})
+	require.NoError(t, err)
+	event = waitFor(4)
+	assert.Equal(t, v1.ProbeDeleted, event.Type)
+	assert.Equal(t, probe.Id, event.Probe.Id)
+	assert.Equal(t, v1.Terminating, *event.PreviousStatus)
+
+	// Pending probes are removed right away.
+	res, err = server.CreateProbe(ctx, v1.CreateProbeRequestObject{Body: &v1.CreateProbeJSONRequestBody{StaticUrl: "https://other.example.com"}})
+	require.NoError(t, err)
+	other := v1.ProbeObject(res.(v1.CreateProbe201JSONResponse))
+	waitFor(5)
+	_, err = server.DeleteProbe(ctx, v1.DeleteProbeRequestObject{ProbeId: other.Id})
+	require.NoError(t, err)
+	event = waitFor(6)
+	assert.Equal(t, v1.ProbeDeleted, event.Type)
+	assert.Equal(t, other.Id, event.Probe.Id)
+	assert.Equal(t, v1.Pending, *event.PreviousStatus)
+}
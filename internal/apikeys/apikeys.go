@@ -0,0 +1,251 @@
+// Package apikeys mints and verifies the API keys that automation clients
+// which cannot use OIDC authenticate with, and rate limits each key.
+//
+// A key is "rhobs-apikey.<id>.<secret>". The probe store keeps only the
+// SHA-256 hash of the secret, so reading the store does not reveal keys.
+// Each replica verifies keys against its copy of the stored keys, refreshed
+// every refresh interval; keys minted or revoked on another replica are
+// accepted or rejected here from the next refresh.
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"golang.org/x/time/rate"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// Prefix starts every API key, so bearer tokens of other kinds are told
+	// apart without a lookup.
+	Prefix = "rhobs-apikey."
+	// DefaultRefreshInterval is how often Run reloads the stored keys.
+	DefaultRefreshInterval = 30 * time.Second
+	// DefaultRateLimit is the requests per second keys minted without a
+	// rate limit may send, unless the server is configured otherwise.
+	DefaultRateLimit = 10
+	// MaxNameLength bounds key names, which end up in audit entries.
+	MaxNameLength = 128
+)
+
+var (
+	// ErrInvalid is returned for keys that are malformed, unknown or revoked.
+	ErrInvalid = errors.New("invalid API key")
+	// ErrExpired is returned for keys past their expiry.
+	ErrExpired = errors.New("API key has expired")
+)
+
+// Manager mints, lists, revokes and verifies API keys.
+type Manager struct {
+	store probestore.APIKeyStore
+	// defaultRateLimit applies to keys minted without a rate limit; zero
+	// leaves them unlimited.
+	defaultRateLimit float64
+
+	mu       sync.RWMutex
+	keys     map[string]probestore.APIKey
+	limiters map[string]*rate.Limiter
+}
+
+// NewManager returns a Manager keeping keys in store, limiting keys minted
+// without a rate limit to defaultRateLimit requests per second, or not at all
+// when it is zero. Call Refresh or Run to load the stored keys.
+func NewManager(store probestore.APIKeyStore, defaultRateLimit float64) (*Manager, error) {
+	if defaultRateLimit < 0 || math.IsNaN(defaultRateLimit) || math.IsInf(defaultRateLimit, 0) {
+		return nil, fmt.Errorf("API key rate limit must not be negative, got %v", defaultRateLimit)
+	}
+	return &Manager{
+		store:            store,
+		defaultRateLimit: defaultRateLimit,
+		keys:             map[string]probestore.APIKey{},
+		limiters:         map[string]*rate.Limiter{},
+	}, nil
+}
+
+// Mint stores a new key for name and returns it with the key itself, which
+// is not kept. A zero rateLimit applies the default; a zero ttl mints a key
+// valid until revoked.
+func (m *Manager) Mint(ctx context.Context, name string, rateLimit float64, ttl time.Duration) (probestore.APIKey, string, error) {
+	switch {
+	case name == "" || len(name) > MaxNameLength:
+		return probestore.APIKey{}, "", fmt.Errorf("API key name must be 1 to %d characters long", MaxNameLength)
+	case rateLimit < 0 || math.IsNaN(rateLimit) || math.IsInf(rateLimit, 0):
+		return probestore.APIKey{}, "", fmt.Errorf("API key rate limit must be positive, got %v", rateLimit)
+	case ttl < 0:
+		return probestore.APIKey{}, "", fmt.Errorf("API key lifetime must be positive, got %s", ttl)
+	}
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret) // crypto/rand.Read never returns an error
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+
+	key := probestore.APIKey{
+		ID:         newID(),
+		Name:       name,
+		SecretHash: hash(encoded),
+		RateLimit:  rateLimit,
+		CreatedAt:  clock.Stamp(),
+	}
+	if ttl > 0 {
+		key.ExpiresAt = key.CreatedAt.Add(ttl)
+	}
+	if err := m.store.CreateAPIKey(ctx, key); err != nil {
+		return probestore.APIKey{}, "", err
+	}
+	m.mu.Lock()
+	m.keys[key.ID] = key
+	m.mu.Unlock()
+	return key, Prefix + key.ID + "." + encoded, nil
+}
+
+// List returns the stored keys, and refreshes the keys verified against.
+func (m *Manager) List(ctx context.Context) ([]probestore.APIKey, error) {
+	keys, err := m.store.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.replace(keys)
+	return keys, nil
+}
+
+// Revoke removes a key, which is rejected from then on. It returns a
+// NotFound error for unknown keys.
+func (m *Manager) Revoke(ctx context.Context, id string) error {
+	err := m.store.DeleteAPIKey(ctx, id)
+	if err == nil || k8serrors.IsNotFound(err) {
+		m.mu.Lock()
+		delete(m.keys, id)
+		delete(m.limiters, id)
+		m.mu.Unlock()
+	}
+	return err
+}
+
+// Refresh reloads the stored keys.
+func (m *Manager) Refresh(ctx context.Context) error {
+	_, err := m.List(ctx)
+	return err
+}
+
+// Run refreshes the keys every interval until ctx is cancelled. Failed
+// refreshes keep the previous keys.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := m.Refresh(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to refresh API keys, keeping the previous ones", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Len returns the number of keys verified against.
+func (m *Manager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.keys)
+}
+
+// replace makes keys the keys verified against, dropping the rate limiters
+// of keys that are gone.
+func (m *Manager) replace(keys []probestore.APIKey) {
+	byID := make(map[string]probestore.APIKey, len(keys))
+	for _, key := range keys {
+		byID[key.ID] = key
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keys = byID
+	for id := range m.limiters {
+		if _, ok := byID[id]; !ok {
+			delete(m.limiters, id)
+		}
+	}
+}
+
+// Verify returns the stored key a key belongs to. Expired keys are returned
+// with ErrExpired.
+func (m *Manager) Verify(token string) (probestore.APIKey, error) {
+	rest, ok := strings.CutPrefix(token, Prefix)
+	if !ok {
+		return probestore.APIKey{}, ErrInvalid
+	}
+	id, secret, ok := strings.Cut(rest, ".")
+	if !ok || id == "" || secret == "" {
+		return probestore.APIKey{}, ErrInvalid
+	}
+	m.mu.RLock()
+	key, ok := m.keys[id]
+	m.mu.RUnlock()
+	if !ok || subtle.ConstantTimeCompare([]byte(hash(secret)), []byte(key.SecretHash)) != 1 {
+		return probestore.APIKey{}, ErrInvalid
+	}
+	if key.Expired(clock.Now()) {
+		return key, ErrExpired
+	}
+	return key, nil
+}
+
+// RateLimit returns the requests per second key may send, or zero when it is
+// not limited.
+func (m *Manager) RateLimit(key probestore.APIKey) float64 {
+	if key.RateLimit > 0 {
+		return key.RateLimit
+	}
+	return m.defaultRateLimit
+}
+
+// reserve takes a request from the key's rate limit. It returns zero when
+// the request may go ahead, and otherwise how long until it could.
+func (m *Manager) reserve(key probestore.APIKey) time.Duration {
+	limit := m.RateLimit(key)
+	if limit <= 0 {
+		return 0
+	}
+	m.mu.Lock()
+	limiter, ok := m.limiters[key.ID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit), max(1, int(math.Ceil(limit))))
+		m.limiters[key.ID] = limiter
+	}
+	m.mu.Unlock()
+
+	reservation := limiter.Reserve()
+	delay := reservation.Delay()
+	if delay > 0 {
+		reservation.Cancel()
+	}
+	return delay
+}
+
+// hash returns the hex SHA-256 hash of a key's secret. Secrets are 256
+// random bits, so a plain hash cannot be reversed by guessing.
+func hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id) // crypto/rand.Read never returns an error
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,147 @@
+package apikeys
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func newTestManager(t *testing.T, store probestore.APIKeyStore, defaultRateLimit float64) *Manager {
+	t.Helper()
+	m, err := NewManager(store, defaultRateLimit)
+	require.NoError(t, err)
+	return m
+}
+
+func newTestStore(t *testing.T) *probestore.LocalProbeStore {
+	t.Helper()
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	return store
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	store := newTestStore(t)
+	m := newTestManager(t, store, 0)
+
+	key, token, err := m.Mint(ctx, "ci", 0, 0)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, Prefix+key.ID+"."))
+	assert.NotContains(t, key.SecretHash, strings.TrimPrefix(token, Prefix+key.ID+"."), "only the hash is kept")
+
+	got, err := m.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, key, got)
+
+	for name, token := range map[string]string{
+		"other prefix":   "rhobs-agent." + key.ID + ".secret",
+		"no secret":      Prefix + key.ID,
+		"wrong secret":   Prefix + key.ID + ".secret",
+		"unknown key ID": Prefix + "0000000000000000" + strings.TrimPrefix(token, Prefix+key.ID),
+	} {
+		_, err := m.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalid, name)
+	}
+
+	t.Run("keys minted on other replicas are accepted once refreshed", func(t *testing.T) {
+		other := newTestManager(t, store, 0)
+		_, err := other.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalid)
+
+		require.NoError(t, other.Refresh(ctx))
+		_, err = other.Verify(token)
+		assert.NoError(t, err)
+
+		require.NoError(t, m.Revoke(ctx, key.ID))
+		require.NoError(t, other.Refresh(ctx))
+		_, err = other.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalid, "revoked keys are dropped by the refresh")
+		assert.True(t, k8serrors.IsNotFound(m.Revoke(ctx, key.ID)))
+	})
+
+	t.Run("expired keys", func(t *testing.T) {
+		key, token, err := m.Mint(ctx, "short-lived", 0, time.Second)
+		require.NoError(t, err)
+		key.ExpiresAt = time.Now().Add(-time.Second)
+		m.replace([]probestore.APIKey{key})
+
+		_, err = m.Verify(token)
+		assert.ErrorIs(t, err, ErrExpired)
+	})
+
+	t.Run("invalid keys are not minted", func(t *testing.T) {
+		_, _, err := m.Mint(ctx, "", 0, 0)
+		assert.Error(t, err)
+		_, _, err = m.Mint(ctx, "ci", -1, 0)
+		assert.Error(t, err)
+		_, _, err = m.Mint(ctx, "ci", 0, -time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, newTestStore(t), 0)
+	limited, limitedToken, err := m.Mint(ctx, "ci", 1, 0)
+	require.NoError(t, err)
+	_, unlimitedToken, err := m.Mint(ctx, "backup", 0, 0)
+	require.NoError(t, err)
+
+	var actor string
+	var seen probestore.APIKey
+	handler := audit.Middleware(Middleware(m)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = audit.NewLog(1).Actor(r.Context())
+		seen, _ = FromContext(r.Context())
+	})))
+	serve := func(token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/probes", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(limitedToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ActorPrefix+"ci", actor, "changes are audited as the key")
+	assert.Equal(t, limited.ID, seen.ID)
+
+	w = serve(limitedToken)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "the key's rate limit applies")
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":{"message":"API key rate limit exceeded","retry_after_seconds":1}}`, w.Body.String())
+
+	for range 3 {
+		assert.Equal(t, http.StatusOK, serve(unlimitedToken).Code, "keys without a rate limit are not limited without a default")
+	}
+
+	w = serve(Prefix + "0000000000000000.secret")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+
+	seen = probestore.APIKey{}
+	assert.Equal(t, http.StatusOK, serve("some-other-token").Code, "other bearer tokens pass through")
+	assert.Equal(t, http.StatusOK, serve("").Code)
+	assert.Empty(t, seen.ID)
+}
+
+func TestManager_DefaultRateLimit(t *testing.T) {
+	m := newTestManager(t, newTestStore(t), 5)
+	assert.Equal(t, 5.0, m.RateLimit(probestore.APIKey{}))
+	assert.Equal(t, 2.0, m.RateLimit(probestore.APIKey{RateLimit: 2}))
+
+	_, err := NewManager(newTestStore(t), -1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+)
+
+// ActorPrefix starts the audit actor of changes made with an API key,
+// followed by the key's name.
+const ActorPrefix = "apikey:"
+
+type keyKey struct{}
+
+// FromContext returns the API key the request was authenticated with.
+func FromContext(ctx context.Context) (probestore.APIKey, bool) {
+	key, ok := ctx.Value(keyKey{}).(probestore.APIKey)
+	return key, ok
+}
+
+// Middleware authenticates requests carrying an API key as a bearer token.
+// Requests with an invalid, revoked or expired key are answered with 401,
+// and requests above the key's rate limit with 429. Accepted requests carry
+// the key in their context and are audited as made by ActorPrefix plus the
+// key's name; they must run inside audit.Middleware for that. Requests
+// without an API key pass through, as do all requests when m is nil.
+func Middleware(m *Manager) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if m == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := agentauth.BearerToken(r)
+			if !strings.HasPrefix(token, Prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key, err := m.Verify(token)
+			switch {
+			case errors.Is(err, ErrExpired):
+				metrics.RecordAPIKeyRequest(key.ID, "expired")
+				writeError(w, http.StatusUnauthorized, err.Error(), 0)
+				return
+			case err != nil:
+				metrics.RecordAPIKeyRequest("", "invalid")
+				writeError(w, http.StatusUnauthorized, err.Error(), 0)
+				return
+			}
+			if delay := m.reserve(key); delay > 0 {
+				metrics.RecordAPIKeyRequest(key.ID, "rate_limited")
+				writeError(w, http.StatusTooManyRequests, "API key rate limit exceeded", int(math.Ceil(delay.Seconds())))
+				return
+			}
+			metrics.RecordAPIKeyRequest(key.ID, "accepted")
+			ctx := context.WithValue(r.Context(), keyKey{}, key)
+			ctx = audit.WithActor(ctx, ActorPrefix+key.Name)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// writeError writes an ErrorResponse, with the backoff hint when
+// retryAfter is positive.
+func writeError(w http.ResponseWriter, code int, message string, retryAfter int) {
+	type errorObject struct {
+		Message           string `json:"message"`
+		RetryAfterSeconds *int   `json:"retry_after_seconds,omitempty"`
+	}
+	body := struct {
+		Error errorObject `json:"error"`
+	}{Error: errorObject{Message: message}}
+	if code == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	if retryAfter > 0 {
+		body.Error.RetryAfterSeconds = &retryAfter
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,124 @@
+// Package apiv2 serves version 2 of the API under /api/v2. Its handlers call
+// those of version 1 and convert their responses, so both versions share the
+// checks, tenancy and storage of probes while the probe schema of version 2
+// evolves, e.g. towards several targets per probe.
+package apiv2
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	v2 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v2"
+)
+
+// Prefix is the path version 2 is served under.
+const Prefix = "/api/v2"
+
+// Server implements v2.StrictServerInterface on top of the version 1 server.
+type Server struct {
+	V1 v1.StrictServerInterface
+}
+
+var _ v2.StrictServerInterface = Server{}
+
+// NewServer returns the version 2 server of a version 1 server.
+func NewServer(server v1.StrictServerInterface) Server {
+	return Server{V1: server}
+}
+
+// (GET /probes)
+func (s Server) ListProbes(ctx context.Context, request v2.ListProbesRequestObject) (v2.ListProbesResponseObject, error) {
+	res, err := s.V1.ListProbes(ctx, v1.ListProbesRequestObject{Params: v1.ListProbesParams{
+		LabelSelector: request.Params.LabelSelector,
+		Limit:         request.Params.Limit,
+		PageToken:     request.Params.PageToken,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	switch r := res.(type) {
+	case v1.ListProbes200JSONResponse:
+		probes := make([]v2.ProbeObject, 0, len(r.Body.Probes))
+		for _, probe := range r.Body.Probes {
+			probes = append(probes, probeObject(probe))
+		}
+		return v2.ListProbes200JSONResponse{Probes: probes, NextPageToken: r.Body.NextPageToken}, nil
+	case v1.ListProbes400JSONResponse:
+		return v2.ListProbes400JSONResponse{Error: errorObject(r.Error)}, nil
+	case v1.ListProbes413JSONResponse:
+		return v2.ListProbes413JSONResponse{Error: errorObject(r.Error)}, nil
+	}
+	return nil, fmt.Errorf("failed to list probes: unexpected response %T", res)
+}
+
+// (GET /probes/{probe_id})
+func (s Server) GetProbeById(ctx context.Context, request v2.GetProbeByIdRequestObject) (v2.GetProbeByIdResponseObject, error) {
+	res, err := s.V1.GetProbeById(ctx, v1.GetProbeByIdRequestObject{ProbeId: request.ProbeId})
+	if err != nil {
+		return nil, err
+	}
+	switch r := res.(type) {
+	case v1.GetProbeById200JSONResponse:
+		return v2.GetProbeById200JSONResponse(probeObject(r.Body)), nil
+	case v1.GetProbeById404JSONResponse:
+		return v2.GetProbeById404JSONResponse{Warning: v2.WarningObject{Message: r.Warning.Message}}, nil
+	case v1.GetProbeById410JSONResponse:
+		return v2.GetProbeById410JSONResponse{Warning: v2.WarningObject{Message: r.Warning.Message}}, nil
+	}
+	return nil, fmt.Errorf("failed to get probe %s: unexpected response %T", request.ProbeId, res)
+}
+
+// probeObject converts a version 1 probe, whose static URL and additional
+// URLs become its targets.
+func probeObject(probe v1.ProbeObject) v2.ProbeObject {
+	converted := v2.ProbeObject{
+		Id:                probe.Id,
+		Targets:           targets(probe),
+		Status:            v2.StatusSchema(probe.Status),
+		Paused:            probe.Paused,
+		Interval:          probe.Interval,
+		Timeout:           probe.Timeout,
+		Generation:        probe.Generation,
+		CreationTimestamp: probe.CreationTimestamp,
+		UpdateTimestamp:   probe.UpdateTimestamp,
+	}
+	if probe.Labels != nil {
+		labels := v2.LabelsSchema(*probe.Labels)
+		converted.Labels = &labels
+	}
+	if probe.Module != nil {
+		module := v2.ProbeModuleSchema(*probe.Module)
+		converted.Module = &module
+	}
+	return converted
+}
+
+// targets returns the targets of a version 1 probe with the statuses its
+// agent reported.
+func targets(probe v1.ProbeObject) []v2.ProbeTarget {
+	urls := []string{probe.StaticUrl}
+	if probe.AdditionalUrls != nil {
+		urls = append(urls, *probe.AdditionalUrls...)
+	}
+	targets := make([]v2.ProbeTarget, 0, len(urls))
+	for _, u := range urls {
+		target := v2.ProbeTarget{Url: u}
+		if probe.TargetStatuses != nil {
+			for _, status := range *probe.TargetStatuses {
+				if status.Url == u {
+					target.State = new(v2.ProbeTargetState(status.State))
+					target.Message = status.Message
+					target.Timestamp = status.Timestamp
+				}
+			}
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// errorObject converts a version 1 error.
+func errorObject(err v1.ErrorObject) v2.ErrorObject {
+	return v2.ErrorObject{Message: err.Message, RetryAfterSeconds: err.RetryAfterSeconds}
+}
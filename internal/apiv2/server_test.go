@@ -0,0 +1,100 @@
+package apiv2
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	v2 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeV1 answers the version 1 calls made by the version 2 server; the
+// others panic.
+type fakeV1 struct {
+	v1.StrictServerInterface
+	list v1.ListProbesResponseObject
+	get  v1.GetProbeByIdResponseObject
+	err  error
+
+	listRequest v1.ListProbesRequestObject
+}
+
+func (f *fakeV1) ListProbes(_ context.Context, request v1.ListProbesRequestObject) (v1.ListProbesResponseObject, error) {
+	f.listRequest = request
+	return f.list, f.err
+}
+
+func (f *fakeV1) GetProbeById(context.Context, v1.GetProbeByIdRequestObject) (v1.GetProbeByIdResponseObject, error) {
+	return f.get, f.err
+}
+
+func TestListProbes(t *testing.T) {
+	id := uuid.New()
+	labels := v1.LabelsSchema{"env": "prod"}
+	module := v1.Tcp
+	fake := &fakeV1{list: v1.ListProbes200JSONResponse{Body: v1.ProbesArrayResponse{
+		Probes:        []v1.ProbeObject{{Id: id, StaticUrl: "https://example.com", Labels: &labels, Status: v1.Active, Module: &module}},
+		NextPageToken: new("next"),
+	}}}
+	selector := "env=prod"
+	res, err := NewServer(fake).ListProbes(context.Background(), v2.ListProbesRequestObject{Params: v2.ListProbesParams{LabelSelector: &selector, Limit: new(1)}})
+	require.NoError(t, err)
+
+	assert.Equal(t, v1.ListProbesParams{LabelSelector: &selector, Limit: new(1)}, fake.listRequest.Params)
+	expectedLabels := v2.LabelsSchema{"env": "prod"}
+	expectedModule := v2.Tcp
+	assert.Equal(t, v2.ListProbes200JSONResponse{
+		Probes:        []v2.ProbeObject{{Id: id, Targets: []v2.ProbeTarget{{Url: "https://example.com"}}, Labels: &expectedLabels, Status: v2.Active, Module: &expectedModule}},
+		NextPageToken: new("next"),
+	}, res)
+}
+
+func TestListProbes_Errors(t *testing.T) {
+	server := NewServer(&fakeV1{list: v1.ListProbes400JSONResponse{Error: v1.ErrorObject{Message: "bad selector"}}})
+	res, err := server.ListProbes(context.Background(), v2.ListProbesRequestObject{})
+	require.NoError(t, err)
+	assert.Equal(t, v2.ListProbes400JSONResponse{Error: v2.ErrorObject{Message: "bad selector"}}, res)
+
+	server = NewServer(&fakeV1{list: v1.ListProbes304Response{}})
+	_, err = server.ListProbes(context.Background(), v2.ListProbesRequestObject{})
+	assert.ErrorContains(t, err, "unexpected response v1.ListProbes304Response")
+
+	server = NewServer(&fakeV1{err: errors.New("store down")})
+	_, err = server.ListProbes(context.Background(), v2.ListProbesRequestObject{})
+	assert.ErrorContains(t, err, "store down")
+}
+
+func TestTargets(t *testing.T) {
+	checked := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	probe := v1.ProbeObject{
+		StaticUrl:      "https://api.example.com",
+		AdditionalUrls: &v1.AdditionalUrlsSchema{"https://console.example.com", "https://oauth.example.com"},
+		TargetStatuses: &v1.TargetStatusesSchema{
+			{Url: "https://console.example.com", State: v1.Down, Message: new("connection refused"), Timestamp: &checked},
+			{Url: "https://api.example.com", State: v1.Up, Timestamp: &checked},
+		},
+	}
+	assert.Equal(t, []v2.ProbeTarget{
+		{Url: "https://api.example.com", State: new(v2.Up), Timestamp: &checked},
+		{Url: "https://console.example.com", State: new(v2.Down), Message: new("connection refused"), Timestamp: &checked},
+		{Url: "https://oauth.example.com"},
+	}, targets(probe))
+}
+
+func TestGetProbeById(t *testing.T) {
+	id := uuid.New()
+	server := NewServer(&fakeV1{get: v1.GetProbeById200JSONResponse{Body: v1.ProbeObject{Id: id, StaticUrl: "https://example.com", Status: v1.Pending}}})
+	res, err := server.GetProbeById(context.Background(), v2.GetProbeByIdRequestObject{ProbeId: id})
+	require.NoError(t, err)
+	assert.Equal(t, v2.GetProbeById200JSONResponse{Id: id, Targets: []v2.ProbeTarget{{Url: "https://example.com"}}, Status: v2.Pending}, res)
+
+	server = NewServer(&fakeV1{get: v1.GetProbeById404JSONResponse{Warning: v1.WarningObject{Message: "not found"}}})
+	res, err = server.GetProbeById(context.Background(), v2.GetProbeByIdRequestObject{ProbeId: id})
+	require.NoError(t, err)
+	assert.Equal(t, v2.GetProbeById404JSONResponse{Warning: v2.WarningObject{Message: "not found"}}, res)
+}
@@ -0,0 +1,101 @@
+// Package apiversion routes API requests to the version they ask for and
+// tells clients which version served them. Version 1 is served at the root,
+// e.g. /probes, and later versions under /api/<version>, e.g. /api/v2/probes.
+// Clients may also keep the unversioned paths and ask for a version with the
+// API-Version header. Once version 1 is deprecated, its responses say so
+// with the Deprecation, Sunset and Link headers of RFC 9745 and RFC 8594.
+package apiversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Header is the request header asking for a version, and the response
+	// header naming the version that served the request.
+	Header = "API-Version"
+	// V1 is the version served at the root.
+	V1 = "v1"
+	// V2 is the version served under /api/v2.
+	V2 = "v2"
+)
+
+// Versions are the supported versions, oldest first.
+var Versions = []string{V1, V2}
+
+// Deprecation announces the retirement of version 1.
+type Deprecation struct {
+	// At is when version 1 was, or will be, deprecated. Responses carry no
+	// deprecation headers when it is zero.
+	At time.Time
+	// Sunset is when version 1 is expected to stop being served; optional.
+	Sunset time.Time
+}
+
+// Middleware serves requests with the API-Version header set to a later
+// version as if they were sent to its prefix, rejects unknown versions with
+// 400, and labels every response with the version that served it.
+func Middleware(deprecation Deprecation) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			version := pathVersion(r.URL.Path)
+			if version == "" {
+				requested := strings.ToLower(strings.TrimSpace(r.Header.Get(Header)))
+				switch requested {
+				case "", V1:
+					version = V1
+				case V2:
+					version = requested
+					r = withPrefix(r, "/api/"+requested)
+				default:
+					writeError(w, fmt.Sprintf("unsupported %s %q, expected one of %s", Header, requested, strings.Join(Versions, ", ")))
+					return
+				}
+			}
+
+			w.Header().Set(Header, version)
+			if version == V1 && !deprecation.At.IsZero() {
+				w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecation.At.Unix(), 10))
+				if !deprecation.Sunset.IsZero() {
+					w.Header().Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+				}
+				w.Header().Add("Link", `</api/v2/openapi.json>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// pathVersion returns the version whose prefix the path starts with, or ""
+// for unversioned paths.
+func pathVersion(path string) string {
+	for _, version := range Versions[1:] {
+		prefix := "/api/" + version
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return version
+		}
+	}
+	return ""
+}
+
+// withPrefix returns a copy of the request sent to the path under prefix.
+func withPrefix(r *http.Request, prefix string) *http.Request {
+	r2 := r.Clone(r.Context())
+	r2.URL.Path = prefix + r.URL.Path
+	if r.URL.RawPath != "" {
+		r2.URL.RawPath = prefix + r.URL.RawPath
+	}
+	return r2
+}
+
+// writeError writes a 400 with the body of the API's error responses.
+func writeError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]map[string]string{"error": {"message": message}})
+}
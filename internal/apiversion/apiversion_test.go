@@ -0,0 +1,74 @@
+package apiversion
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	var gotPath string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+	})
+	serve := func(handler http.Handler, path, version string) *httptest.ResponseRecorder {
+		gotPath = ""
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if version != "" {
+			req.Header.Set(Header, version)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("routing", func(t *testing.T) {
+		handler := Middleware(Deprecation{})(next)
+		for _, tc := range []struct{ path, header, wantPath, wantVersion string }{
+			{"/probes", "", "/probes", V1},
+			{"/probes", "v1", "/probes", V1},
+			{"/probes", "V2", "/api/v2/probes", V2},
+			{"/api/v2/probes", "", "/api/v2/probes", V2},
+			{"/api/v2/probes", "v1", "/api/v2/probes", V2},
+			{"/api/v2x/probes", "", "/api/v2x/probes", V1},
+		} {
+			rec := serve(handler, tc.path, tc.header)
+			assert.Equal(t, http.StatusOK, rec.Code, tc.path)
+			assert.Equal(t, tc.wantPath, gotPath, tc.path)
+			assert.Equal(t, tc.wantVersion, rec.Header().Get(Header), tc.path)
+			assert.Empty(t, rec.Header().Get("Deprecation"), tc.path)
+		}
+	})
+
+	t.Run("unknown versions are rejected", func(t *testing.T) {
+		rec := serve(Middleware(Deprecation{})(next), "/probes", "v3")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Empty(t, gotPath)
+		assert.JSONEq(t, `{"error":{"message":"unsupported API-Version \"v3\", expected one of v1, v2"}}`, rec.Body.String())
+	})
+
+	t.Run("deprecation headers", func(t *testing.T) {
+		handler := Middleware(Deprecation{
+			At:     time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+			Sunset: time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC),
+		})(next)
+		rec := serve(handler, "/probes", "")
+		assert.Equal(t, "@1793491200", rec.Header().Get("Deprecation"))
+		assert.Equal(t, "Sat, 01 May 2027 00:00:00 GMT", rec.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v2/openapi.json>; rel="successor-version"`, rec.Header().Get("Link"))
+
+		rec = serve(handler, "/probes", V2)
+		assert.Empty(t, rec.Header().Get("Deprecation"))
+		assert.Empty(t, rec.Header().Get("Sunset"))
+		assert.Empty(t, rec.Header().Get("Link"))
+	})
+
+	t.Run("deprecation without a sunset", func(t *testing.T) {
+		rec := serve(Middleware(Deprecation{At: time.Unix(1793491200, 0)})(next), "/probes", "")
+		assert.Equal(t, "@1793491200", rec.Header().Get("Deprecation"))
+		assert.Empty(t, rec.Header().Get("Sunset"))
+	})
+}
@@ -0,0 +1,404 @@
+// Package assignment distributes probes across registered agents.
+//
+// Agents register (and keep registering as a heartbeat) with a set of labels
+// and a capacity. A reconcile loop assigns pending and active probes to live
+// agents, honouring the probes' regions, label affinity and capacity, and
+// moves probes off agents whose heartbeat has expired or that are no longer
+// in one of the probe's regions. The assignment is recorded on the probe itself
+// under AgentLabelKey. Registrations are kept in the probe store when it keeps
+// records, so the replica running the reconcile loop sees the agents that
+// registered with any replica.
+package assignment
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// AgentLabelKey is the probe label holding the ID of the assigned agent.
+	// An empty value means the probe is unassigned.
+	AgentLabelKey = "rhobs-synthetics/agent"
+
+	// RegionLabelKey is the agent label naming the region the agent runs in.
+	RegionLabelKey = "region"
+
+	// DefaultHeartbeatTTL is how long an agent stays assigned probes without
+	// registering again.
+	DefaultHeartbeatTTL = 2 * time.Minute
+
+	probeSelector = "app=rhobs-synthetics-probe"
+
+	// agentRecordKind is the kind of the store records agents are
+	// registered in.
+	agentRecordKind = "agents"
+)
+
+// DefaultAffinityKeys are the probe labels that must match the agent's labels
+// when the probe sets them.
+var DefaultAffinityKeys = []string{"region"}
+
+// Agent is a registered probing agent.
+type Agent struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// MaxProbes caps the number of probes assigned to the agent; 0 means no limit.
+	MaxProbes     int       `json:"max_probes,omitempty"`
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+}
+
+// Region returns the region the agent runs in, "" if it did not say.
+func (a Agent) Region() string {
+	return a.Labels[RegionLabelKey]
+}
+
+// Engine keeps the agent registry and assigns probes to agents. The registry
+// is kept in the store when it is a probestore.RecordStore, and in memory
+// otherwise; then, after a restart agents are re-learned from their next
+// heartbeat, and existing assignments are kept for one heartbeat TTL so that
+// a restart does not reshuffle every probe.
+type Engine struct {
+	Store        probestore.ProbeStorage
+	HeartbeatTTL time.Duration
+	AffinityKeys []string
+	// Notifications is told about agents nearly at their capacity. Nil
+	// sends nothing.
+	Notifications *notify.Notifier
+
+	mu      sync.RWMutex
+	agents  map[string]Agent
+	started time.Time
+	now     func() time.Time
+}
+
+// NewEngine creates an Engine with the default heartbeat TTL and affinity keys.
+func NewEngine(store probestore.ProbeStorage) *Engine {
+	return &Engine{
+		Store:        store,
+		HeartbeatTTL: DefaultHeartbeatTTL,
+		AffinityKeys: slices.Clone(DefaultAffinityKeys),
+		agents:       make(map[string]Agent),
+		started:      time.Now(),
+		now:          time.Now,
+	}
+}
+
+// records returns the store the registry is kept in, if the store keeps
+// records.
+func (e *Engine) records() (probestore.RecordStore, bool) {
+	return probestore.Implements[probestore.RecordStore](e.Store)
+}
+
+// Heartbeat registers the agent, or refreshes it if it is already known, and
+// returns the stored record.
+func (e *Engine) Heartbeat(ctx context.Context, agent Agent) (Agent, error) {
+	agent.Labels = maps.Clone(agent.Labels)
+	agent.LastHeartbeat = e.now().UTC()
+	if records, ok := e.records(); ok {
+		data, err := json.Marshal(agent)
+		if err != nil {
+			return Agent{}, fmt.Errorf("failed to marshal agent: %w", err)
+		}
+		if err := records.PutRecord(ctx, agentRecordKind, probestore.Record{ID: agent.ID, Data: data}); err != nil {
+			return Agent{}, fmt.Errorf("failed to register agent: %w", err)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.agents[agent.ID] = agent
+	return agent, nil
+}
+
+// Agent returns the registered agent with the given ID, if it is still live.
+// An agent that last registered with another replica is read from the store.
+func (e *Engine) Agent(ctx context.Context, id string) (Agent, bool, error) {
+	now := e.now()
+	e.mu.RLock()
+	agent, ok := e.agents[id]
+	e.mu.RUnlock()
+	if ok && now.Sub(agent.LastHeartbeat) <= e.HeartbeatTTL {
+		return agent, true, nil
+	}
+
+	records, ok := e.records()
+	if !ok {
+		return Agent{}, false, nil
+	}
+	record, err := records.GetRecord(ctx, agentRecordKind, id)
+	if k8serrors.IsNotFound(err) {
+		return Agent{}, false, nil
+	}
+	if err != nil {
+		return Agent{}, false, fmt.Errorf("failed to read agent: %w", err)
+	}
+	if err := json.Unmarshal(record.Data, &agent); err != nil {
+		return Agent{}, false, fmt.Errorf("failed to unmarshal agent: %w", err)
+	}
+	if now.Sub(agent.LastHeartbeat) > e.HeartbeatTTL {
+		return Agent{}, false, nil
+	}
+	return agent, true, nil
+}
+
+// AssignedProbes returns the probes currently assigned to the agent. Probes
+// whose regions no longer include the agent's are left out, so the agent
+// stops running them before the next reconcile moves them.
+func (e *Engine) AssignedProbes(ctx context.Context, agentID string) ([]v1.ProbeObject, error) {
+	probes, err := e.Store.ListProbes(ctx, fmt.Sprintf("%s,%s=%s", probeSelector, AgentLabelKey, agentID))
+	if err != nil {
+		return nil, err
+	}
+	agent, _, err := e.Agent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(probes, func(probe v1.ProbeObject) bool {
+		return !probe.InRegion(agent.Region())
+	}), nil
+}
+
+// Run reconciles assignments every interval until ctx is cancelled. The
+// grace period for existing assignments starts over, so a replica taking
+// over leadership learns the agents from their heartbeats first.
+func (e *Engine) Run(ctx context.Context, interval time.Duration) {
+	e.mu.Lock()
+	e.started = e.now()
+	e.mu.Unlock()
+	ctx = logging.With(ctx, "operation", "probe_assignment")
+	slog.InfoContext(ctx, "Starting probe assignment", "interval", interval, "heartbeat_ttl", e.HeartbeatTTL)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := e.Reconcile(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "Probe assignment failed", "error", err)
+				continue
+			}
+			if changed > 0 {
+				slog.InfoContext(ctx, "Updated probe assignments", "count", changed)
+			}
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "Stopping probe assignment")
+			return
+		}
+	}
+}
+
+// Reconcile assigns unassigned probes and reassigns probes whose agent is
+// gone or outside the probe's regions. It returns the number of probes whose assignment changed.
+func (e *Engine) Reconcile(ctx context.Context) (int, error) {
+	probes, err := e.Store.ListProbes(ctx, probeSelector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list probes for assignment: %w", err)
+	}
+
+	now := e.now()
+	live, pastGrace, err := e.liveAgents(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	// Count the probes each live agent already holds.
+	load := make(map[string]int, len(live))
+	var pending []v1.ProbeObject
+	for _, probe := range probes {
+		if probe.Status != v1.Pending && probe.Status != v1.Active {
+			continue
+		}
+		assigned := AssignedAgent(probe)
+		agent, ok := live[assigned]
+		if ok && probe.InRegion(agent.Region()) {
+			load[assigned]++
+			continue
+		}
+		// Before the grace period ends an unknown agent may simply not have
+		// heartbeated since a restart; leave its probes where they are.
+		if !ok && assigned != "" && !pastGrace {
+			continue
+		}
+		pending = append(pending, probe)
+	}
+
+	changed := 0
+	for _, probe := range pending {
+		previous := AssignedAgent(probe)
+		next := e.pickAgent(probe, live, load)
+		if next == previous {
+			continue
+		}
+
+		if probe.Labels == nil {
+			probe.Labels = &v1.LabelsSchema{}
+		}
+		(*probe.Labels)[AgentLabelKey] = next
+		if _, err := e.Store.UpdateProbe(ctx, probe); err != nil {
+			slog.ErrorContext(ctx, "Failed to assign probe", "probe_id", probe.Id, "agent_id", next, "error", err)
+			continue
+		}
+		if next != "" {
+			load[next]++
+			slog.InfoContext(ctx, "Assigned probe", "probe_id", probe.Id, "agent_id", next)
+		} else {
+			slog.WarnContext(ctx, "Unassigned probe, no eligible agent", "probe_id", probe.Id, "agent_id", previous)
+		}
+		changed++
+	}
+
+	e.notifyCapacity(ctx, live, load)
+	return changed, nil
+}
+
+// notifyCapacity sends a QuotaNearlyExhausted notification for every agent
+// holding at least the notifier's share of its capacity.
+func (e *Engine) notifyCapacity(ctx context.Context, live map[string]Agent, load map[string]int) {
+	if e.Notifications == nil {
+		return
+	}
+	threshold := e.Notifications.QuotaThreshold()
+	for _, agent := range live {
+		if agent.MaxProbes <= 0 || float64(load[agent.ID]) < threshold*float64(agent.MaxProbes) {
+			continue
+		}
+		e.Notifications.Notify(ctx, notify.Event{
+			Type:    notify.QuotaNearlyExhausted,
+			Key:     agent.ID,
+			Summary: fmt.Sprintf("Agent %s holds %d of its %d probes", agent.ID, load[agent.ID], agent.MaxProbes),
+			Details: map[string]string{"agent_id": agent.ID, "probes": strconv.Itoa(load[agent.ID]), "max_probes": strconv.Itoa(agent.MaxProbes)},
+		})
+	}
+}
+
+// liveAgents returns the agents whose heartbeat is within the TTL, dropping
+// expired ones from the registry, and whether the post-start grace period
+// is over. When the registry is kept in the store, the agents registered
+// with other replicas are learned from it first.
+func (e *Engine) liveAgents(ctx context.Context, now time.Time) (map[string]Agent, bool, error) {
+	records, shared := e.records()
+	var stored []probestore.Record
+	if shared {
+		var err error
+		if stored, err = records.ListRecords(ctx, agentRecordKind); err != nil {
+			return nil, false, fmt.Errorf("failed to list agents for assignment: %w", err)
+		}
+	}
+
+	e.mu.Lock()
+	for _, record := range stored {
+		var agent Agent
+		if err := json.Unmarshal(record.Data, &agent); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling agent", "agent_id", record.ID, "error", err)
+			continue
+		}
+		if known, ok := e.agents[agent.ID]; !ok || agent.LastHeartbeat.After(known.LastHeartbeat) {
+			e.agents[agent.ID] = agent
+		}
+	}
+	live := make(map[string]Agent, len(e.agents))
+	var expired []string
+	for id, agent := range e.agents {
+		if now.Sub(agent.LastHeartbeat) > e.HeartbeatTTL {
+			slog.WarnContext(ctx, "Agent missed its heartbeat", "agent_id", id, "last_heartbeat", agent.LastHeartbeat)
+			delete(e.agents, id)
+			expired = append(expired, id)
+			continue
+		}
+		live[id] = agent
+	}
+	pastGrace := now.Sub(e.started) > e.HeartbeatTTL
+	e.mu.Unlock()
+
+	if shared {
+		for _, id := range expired {
+			if err := e.removeExpired(ctx, records, id, now); err != nil {
+				slog.WarnContext(ctx, "Failed to remove expired agent", "agent_id", id, "error", err)
+			}
+		}
+	}
+	return live, pastGrace, nil
+}
+
+// removeExpired deletes the stored registration of an agent, unless the agent
+// registered again with another replica since the registry was listed.
+func (e *Engine) removeExpired(ctx context.Context, records probestore.RecordStore, id string, now time.Time) error {
+	record, err := records.GetRecord(ctx, agentRecordKind, id)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var agent Agent
+	if err := json.Unmarshal(record.Data, &agent); err == nil && now.Sub(agent.LastHeartbeat) <= e.HeartbeatTTL {
+		return nil
+	}
+	if err := records.DeleteRecord(ctx, agentRecordKind, id); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// pickAgent returns the least-loaded live agent in one of the probe's regions
+// that matches its affinity labels and has spare capacity, or "" if there is
+// none.
+func (e *Engine) pickAgent(probe v1.ProbeObject, live map[string]Agent, load map[string]int) string {
+	var candidates []Agent
+	for _, agent := range live {
+		if agent.MaxProbes > 0 && load[agent.ID] >= agent.MaxProbes {
+			continue
+		}
+		if !probe.InRegion(agent.Region()) || !e.matchesAffinity(probe, agent) {
+			continue
+		}
+		candidates = append(candidates, agent)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	best := slices.MinFunc(candidates, func(a, b Agent) int {
+		return cmp.Or(cmp.Compare(load[a.ID], load[b.ID]), cmp.Compare(a.ID, b.ID))
+	})
+	return best.ID
+}
+
+func (e *Engine) matchesAffinity(probe v1.ProbeObject, agent Agent) bool {
+	if probe.Labels == nil {
+		return true
+	}
+	for _, key := range e.AffinityKeys {
+		want, ok := (*probe.Labels)[key]
+		if !ok || want == "" {
+			continue
+		}
+		if agent.Labels[key] != want {
+			return false
+		}
+	}
+	return true
+}
+
+// AssignedAgent returns the ID of the agent a probe is assigned to, or "".
+func AssignedAgent(probe v1.ProbeObject) string {
+	if probe.Labels == nil {
+		return ""
+	}
+	return (*probe.Labels)[AgentLabelKey]
+}
@@ -0,0 +1,299 @@
+package assignment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestEngine returns an engine backed by a local store whose clock starts
+// past the post-restart grace period.
+func newTestEngine(t *testing.T) (*Engine, *time.Time) {
+	t.Helper()
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEngine(store)
+	e.started = now.Add(-time.Hour)
+	e.now = func() time.Time { return now }
+	return e, &now
+}
+
+func createProbe(t *testing.T, e *Engine, status v1.StatusSchema, labels v1.LabelsSchema) uuid.UUID {
+	t.Helper()
+	probe := v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: "https://example.com/" + uuid.NewString(),
+		Status:    status,
+		Labels:    &labels,
+	}
+	_, err := e.Store.CreateProbe(context.Background(), probe, uuid.NewString()[:8])
+	require.NoError(t, err)
+	return probe.Id
+}
+
+func heartbeat(t *testing.T, e *Engine, agent Agent) Agent {
+	t.Helper()
+	registered, err := e.Heartbeat(context.Background(), agent)
+	require.NoError(t, err)
+	return registered
+}
+
+func assignedTo(t *testing.T, e *Engine, id uuid.UUID) string {
+	t.Helper()
+	probe, err := e.Store.GetProbe(context.Background(), id)
+	require.NoError(t, err)
+	return AssignedAgent(*probe)
+}
+
+func TestEngine_Reconcile_AffinityAndCapacity(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newTestEngine(t)
+
+	heartbeat(t, e, Agent{ID: "east-1", Labels: map[string]string{"region": "us-east-1"}, MaxProbes: 1})
+	heartbeat(t, e, Agent{ID: "west-1", Labels: map[string]string{"region": "us-west-2"}})
+
+	east1 := createProbe(t, e, v1.Pending, v1.LabelsSchema{"region": "us-east-1"})
+	east2 := createProbe(t, e, v1.Pending, v1.LabelsSchema{"region": "us-east-1"})
+	west := createProbe(t, e, v1.Active, v1.LabelsSchema{"region": "us-west-2"})
+	terminating := createProbe(t, e, v1.Terminating, v1.LabelsSchema{})
+
+	changed, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+
+	// Only one east probe fits on east-1 and west-1 has the wrong region.
+	eastAssignments := []string{assignedTo(t, e, east1), assignedTo(t, e, east2)}
+	assert.ElementsMatch(t, []string{"east-1", ""}, eastAssignments)
+	assert.Equal(t, "west-1", assignedTo(t, e, west))
+	assert.Equal(t, "", assignedTo(t, e, terminating))
+	assert.Equal(t, 2, changed)
+
+	probes, err := e.AssignedProbes(ctx, "west-1")
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.Equal(t, west, probes[0].Id)
+
+	// A second pass is a no-op.
+	changed, err = e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+}
+
+// eventReceiver passes the events it is sent to a channel.
+type eventReceiver chan notify.Event
+
+func (r eventReceiver) Name() string { return "test" }
+
+func (r eventReceiver) Send(_ context.Context, event notify.Event) error {
+	r <- event
+	return nil
+}
+
+func TestEngine_Reconcile_NotifiesCapacity(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e, _ := newTestEngine(t)
+	events := make(eventReceiver, 10)
+	notifier, err := notify.New(notify.Config{QuotaThreshold: 0.5}, events)
+	require.NoError(t, err)
+	go notifier.Run(ctx)
+	e.Notifications = notifier
+
+	heartbeat(t, e, Agent{ID: "small", Labels: map[string]string{"size": "small"}, MaxProbes: 4})
+	heartbeat(t, e, Agent{ID: "unbounded", Labels: map[string]string{"size": "unbounded"}})
+	createProbe(t, e, v1.Pending, v1.LabelsSchema{"size": "small"})
+	createProbe(t, e, v1.Pending, v1.LabelsSchema{"size": "unbounded"})
+
+	_, err = e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Never(t, func() bool { return len(events) > 0 }, 50*time.Millisecond, 10*time.Millisecond, "one of four probes is below the threshold")
+
+	createProbe(t, e, v1.Pending, v1.LabelsSchema{"size": "small"})
+	_, err = e.Reconcile(ctx)
+	require.NoError(t, err)
+	select {
+	case event := <-events:
+		assert.Equal(t, notify.QuotaNearlyExhausted, event.Type)
+		assert.Equal(t, "small", event.Key)
+		assert.Equal(t, "Agent small holds 2 of its 4 probes", event.Summary)
+	case <-time.After(time.Second):
+		t.Fatal("no notification")
+	}
+}
+
+func TestEngine_Reconcile_BalancesLoad(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newTestEngine(t)
+	heartbeat(t, e, Agent{ID: "a"})
+	heartbeat(t, e, Agent{ID: "b"})
+
+	for range 4 {
+		createProbe(t, e, v1.Pending, v1.LabelsSchema{})
+	}
+
+	_, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+
+	for _, id := range []string{"a", "b"} {
+		probes, err := e.AssignedProbes(ctx, id)
+		require.NoError(t, err)
+		assert.Len(t, probes, 2, "agent %s", id)
+	}
+}
+
+func TestEngine_Reconcile_ReassignsFromExpiredAgent(t *testing.T) {
+	ctx := context.Background()
+	e, now := newTestEngine(t)
+
+	heartbeat(t, e, Agent{ID: "old"})
+	probeID := createProbe(t, e, v1.Active, v1.LabelsSchema{})
+	_, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "old", assignedTo(t, e, probeID))
+
+	*now = now.Add(e.HeartbeatTTL + time.Second)
+	heartbeat(t, e, Agent{ID: "new"})
+
+	changed, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "new", assignedTo(t, e, probeID))
+
+	_, ok, err := e.Agent(ctx, "old")
+	require.NoError(t, err)
+	assert.False(t, ok, "expired agent should be dropped")
+}
+
+func TestEngine_Reconcile_UnassignsWhenNoAgentIsEligible(t *testing.T) {
+	ctx := context.Background()
+	e, now := newTestEngine(t)
+
+	heartbeat(t, e, Agent{ID: "only"})
+	probeID := createProbe(t, e, v1.Active, v1.LabelsSchema{})
+	_, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+
+	*now = now.Add(e.HeartbeatTTL + time.Second)
+	_, err = e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "", assignedTo(t, e, probeID))
+}
+
+func TestEngine_Reconcile_Regions(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newTestEngine(t)
+
+	heartbeat(t, e, Agent{ID: "east", Labels: map[string]string{RegionLabelKey: "us-east-1"}})
+	heartbeat(t, e, Agent{ID: "west", Labels: map[string]string{RegionLabelKey: "us-west-2"}})
+	heartbeat(t, e, Agent{ID: "unlabelled"})
+
+	createRegional := func(regions ...string) uuid.UUID {
+		probe := v1.ProbeObject{
+			Id:        uuid.New(),
+			StaticUrl: "https://example.com/" + uuid.NewString(),
+			Status:    v1.Active,
+			Regions:   &regions,
+		}
+		_, err := e.Store.CreateProbe(ctx, probe, uuid.NewString()[:8])
+		require.NoError(t, err)
+		return probe.Id
+	}
+	west := createRegional("us-west-2")
+	either := createRegional("eu-west-1", "us-east-1")
+	nowhere := createRegional("ap-south-1")
+
+	_, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "west", assignedTo(t, e, west))
+	assert.Equal(t, "east", assignedTo(t, e, either))
+	assert.Equal(t, "", assignedTo(t, e, nowhere), "no agent runs in the probe's region")
+
+	// An agent moving to another region loses the probes it no longer
+	// matches, right away for its own listing and on the next reconcile.
+	heartbeat(t, e, Agent{ID: "west", Labels: map[string]string{RegionLabelKey: "ap-south-1"}})
+	probes, err := e.AssignedProbes(ctx, "west")
+	require.NoError(t, err)
+	assert.Empty(t, probes)
+
+	_, err = e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "", assignedTo(t, e, west))
+	assert.Equal(t, "west", assignedTo(t, e, nowhere))
+	assert.Equal(t, "east", assignedTo(t, e, either))
+}
+
+func TestEngine_Reconcile_KeepsAssignmentsDuringStartupGrace(t *testing.T) {
+	ctx := context.Background()
+	e, now := newTestEngine(t)
+	e.started = *now
+
+	probeID := createProbe(t, e, v1.Active, v1.LabelsSchema{AgentLabelKey: "before-restart"})
+	heartbeat(t, e, Agent{ID: "other"})
+
+	changed, err := e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+	assert.Equal(t, "before-restart", assignedTo(t, e, probeID))
+
+	*now = now.Add(e.HeartbeatTTL + time.Second)
+	heartbeat(t, e, Agent{ID: "other"})
+	_, err = e.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "other", assignedTo(t, e, probeID))
+}
+
+func TestEngine_Agent(t *testing.T) {
+	ctx := context.Background()
+	e, now := newTestEngine(t)
+
+	registered := heartbeat(t, e, Agent{ID: "a", Labels: map[string]string{"region": "eu"}, MaxProbes: 10})
+	assert.Equal(t, *now, registered.LastHeartbeat)
+
+	got, ok, err := e.Agent(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, registered, got)
+
+	_, ok, err = e.Agent(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	*now = now.Add(e.HeartbeatTTL + time.Second)
+	_, ok, err = e.Agent(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEngine_SharesAgentsThroughTheStore(t *testing.T) {
+	ctx := context.Background()
+	leader, now := newTestEngine(t)
+	replica := NewEngine(leader.Store)
+	replica.now = leader.now
+
+	registered := heartbeat(t, replica, Agent{ID: "a", Labels: map[string]string{RegionLabelKey: "eu-west-1"}})
+	got, ok, err := leader.Agent(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok, "agents registered with another replica are read from the store")
+	assert.Equal(t, registered, got)
+
+	probeID := createProbe(t, leader, v1.Pending, v1.LabelsSchema{})
+	_, err = leader.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", assignedTo(t, leader, probeID))
+
+	*now = now.Add(leader.HeartbeatTTL + time.Second)
+	_, err = leader.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "", assignedTo(t, leader, probeID))
+	records, err := leader.Store.(probestore.RecordStore).ListRecords(ctx, agentRecordKind)
+	require.NoError(t, err)
+	assert.Empty(t, records, "expired agents are removed from the store")
+}
@@ -0,0 +1,309 @@
+// Package audit records who changed which probe, when and how. Every
+// creation, update and deletion of a probe is kept as an entry holding the
+// probe before and after the change.
+//
+// The most recent entries are kept in memory for GET /audit, so each replica
+// only lists the changes it made itself. Sinks, such as a JSON lines file or
+// Kubernetes Events, keep the full history.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+const (
+	// ActorHeader names the caller when it has no client certificate. It is
+	// set by authenticating proxies such as oauth-proxy, and can only be
+	// trusted when the API is reachable through such a proxy alone.
+	ActorHeader = "X-Forwarded-User"
+
+	// SystemActor is the actor of changes the server makes by itself.
+	SystemActor = "system"
+
+	// DefaultHistory is the number of entries kept in memory.
+	DefaultHistory = 1000
+
+	// maxActorLength bounds actors taken from request headers.
+	maxActorLength = 256
+)
+
+// Sink durably stores audit entries.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	Write(ctx context.Context, entry v1.AuditEntry) error
+}
+
+// Filter selects audit entries. Zero fields match every entry.
+type Filter struct {
+	ProbeID   uuid.UUID
+	Actor     string
+	Operation v1.AuditOperation
+	Since     time.Time
+	// Allow, if set, must also accept the entry.
+	Allow func(v1.AuditEntry) bool
+	// Limit caps the number of entries returned.
+	Limit int
+
+	// hashedActor is Actor as recorded when actors are hashed.
+	hashedActor string
+}
+
+func (f Filter) matches(entry v1.AuditEntry) bool {
+	switch {
+	case f.ProbeID != uuid.Nil && entry.ProbeId != f.ProbeID:
+		return false
+	case f.Actor != "" && (entry.Actor == nil || *entry.Actor != f.Actor && *entry.Actor != f.hashedActor):
+		return false
+	case f.Operation != "" && entry.Operation != f.Operation:
+		return false
+	case !f.Since.IsZero() && entry.Timestamp.Before(f.Since):
+		return false
+	case f.Allow != nil && !f.Allow(entry):
+		return false
+	}
+	return true
+}
+
+// Log records audit entries, writing each to its sinks and keeping the most
+// recent ones in memory.
+type Log struct {
+	history int
+	sinks   []Sink
+
+	mu      sync.RWMutex
+	privacy Privacy
+	entries []v1.AuditEntry
+}
+
+// NewLog creates a Log keeping the last history entries in memory and
+// writing every entry to sinks. A non-positive history uses DefaultHistory.
+func NewLog(history int, sinks ...Sink) *Log {
+	if history <= 0 {
+		history = DefaultHistory
+	}
+	return &Log{history: history, sinks: sinks}
+}
+
+// SetPrivacy changes how much personal data new entries keep and how long
+// entries are kept in memory. Entries already recorded keep their actor and
+// address.
+func (l *Log) SetPrivacy(p Privacy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.privacy = p.withDefaults()
+}
+
+// Record adds an entry for a change to a probe. before is nil for probes
+// being created and after is nil for probes that were removed. The actor,
+// tenant and request ID are taken from ctx.
+//
+// The actor and address are recorded as the privacy settings allow. Sinks are
+// written to before Record returns. A sink that fails does not fail
+// the change being recorded; the error is logged and counted instead.
+func (l *Log) Record(ctx context.Context, operation v1.AuditOperation, probeID uuid.UUID, before, after *v1.ProbeObject) {
+	entry := v1.AuditEntry{
+		Id:        uuid.New(),
+		Timestamp: clock.Now(),
+		Operation: operation,
+		ProbeId:   probeID,
+		Before:    before,
+		After:     after,
+		Changes:   changes(before, after),
+	}
+	l.mu.RLock()
+	privacy := l.privacy
+	l.mu.RUnlock()
+	c := callerFromContext(ctx)
+	if actor := privacy.actor(c.actor); actor != "" {
+		entry.Actor = &actor
+	}
+	if remoteAddr := privacy.remoteAddr(c.remoteAddr); remoteAddr != "" {
+		entry.RemoteAddr = &remoteAddr
+	}
+	if tenant := limits.TenantFromContext(ctx); tenant != "" {
+		entry.Tenant = &tenant
+	}
+	if id := logging.RequestID(ctx); id != "" {
+		entry.RequestId = &id
+	}
+
+	l.mu.Lock()
+	l.prune(entry.Timestamp)
+	if len(l.entries) >= l.history {
+		l.entries = slices.Delete(l.entries, 0, len(l.entries)-l.history+1)
+	}
+	l.entries = append(l.entries, entry)
+	l.mu.Unlock()
+
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, entry); err != nil {
+			metrics.RecordAuditSinkError(sink.Name())
+			slog.ErrorContext(ctx, "Error writing audit entry", "sink", sink.Name(), "audit_id", entry.Id, "operation", operation, "error", err)
+		}
+	}
+}
+
+// Actor returns the actor of the changes made under ctx as entries record
+// it, or "" if unknown.
+func (l *Log) Actor(ctx context.Context) string {
+	l.mu.RLock()
+	privacy := l.privacy
+	l.mu.RUnlock()
+	return privacy.actor(callerFromContext(ctx).actor)
+}
+
+// Len returns the number of entries kept in memory.
+func (l *Log) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.entries)
+}
+
+// List returns the entries kept in memory that match the filter, newest
+// first. When actors are hashed, the filter's actor matches by name as well
+// as by hash.
+func (l *Log) List(filter Filter) []v1.AuditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(clock.Now())
+	if filter.Actor != "" {
+		filter.hashedActor = l.privacy.actor(filter.Actor)
+	}
+
+	out := []v1.AuditEntry{}
+	for _, entry := range slices.Backward(l.entries) {
+		if filter.Limit > 0 && len(out) == filter.Limit {
+			break
+		}
+		if filter.matches(entry) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// prune drops the entries past the retention. Entries are kept in the order
+// they were recorded, so the expired ones come first. l.mu must be held.
+func (l *Log) prune(now time.Time) {
+	expired := 0
+	for expired < len(l.entries) && l.privacy.expired(l.entries[expired].Timestamp, now) {
+		expired++
+	}
+	l.entries = slices.Delete(l.entries, 0, expired)
+}
+
+// changes returns the JSON fields that differ between before and after, with
+// labels compared one by one. The resource version changes with every write,
+// so it is left out.
+func changes(before, after *v1.ProbeObject) []string {
+	b, a := fields(before), fields(after)
+	changed := []string{}
+	for _, key := range unionKeys(b, a) {
+		switch {
+		case key == "resource_version" || bytes.Equal(b[key], a[key]):
+		case key == "labels":
+			bl, al := labelsOf(before), labelsOf(after)
+			for _, label := range unionKeys(bl, al) {
+				bv, bok := bl[label]
+				av, aok := al[label]
+				if bv != av || bok != aok {
+					changed = append(changed, "labels."+label)
+				}
+			}
+		default:
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// fields returns the probe's JSON fields, or none for a nil probe.
+func fields(probe *v1.ProbeObject) map[string]json.RawMessage {
+	out := map[string]json.RawMessage{}
+	if probe == nil {
+		return out
+	}
+	if data, err := json.Marshal(probe); err == nil {
+		_ = json.Unmarshal(data, &out)
+	}
+	return out
+}
+
+func labelsOf(probe *v1.ProbeObject) map[string]string {
+	if probe == nil || probe.Labels == nil {
+		return nil
+	}
+	return *probe.Labels
+}
+
+// unionKeys returns the keys of both maps, sorted.
+func unionKeys[V any](x, y map[string]V) []string {
+	keys := slices.Collect(maps.Keys(x))
+	for key := range y {
+		if _, ok := x[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+type callerKey struct{}
+
+// caller identifies who sent a request.
+type caller struct {
+	actor      string
+	remoteAddr string
+}
+
+// WithActor returns a copy of ctx whose changes are recorded as made by
+// actor, such as SystemActor for background work.
+func WithActor(ctx context.Context, actor string) context.Context {
+	c := callerFromContext(ctx)
+	c.actor = actor
+	return context.WithValue(ctx, callerKey{}, c)
+}
+
+func callerFromContext(ctx context.Context) caller {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c
+}
+
+// Middleware attaches the caller of each request to its context, for the
+// entries of the changes it makes. The actor is the common name of the
+// verified client certificate, or else the ActorHeader.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := caller{actor: requestActor(r), remoteAddr: r.RemoteAddr}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey{}, c)))
+	})
+}
+
+func requestActor(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		if cn := r.TLS.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+			return cn
+		}
+	}
+	actor := r.Header.Get(ActorHeader)
+	if len(actor) > maxActorLength {
+		actor = actor[:maxActorLength]
+	}
+	return actor
+}
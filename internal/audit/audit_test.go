@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingSink fails every write.
+type failingSink struct{}
+
+func (failingSink) Name() string { return "failing" }
+
+func (failingSink) Write(ctx context.Context, entry v1.AuditEntry) error {
+	return errors.New("sink down")
+}
+
+// recordingSink keeps the entries written to it.
+type recordingSink struct {
+	entries []v1.AuditEntry
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Write(ctx context.Context, entry v1.AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func probe(status v1.StatusSchema, labels v1.LabelsSchema) *v1.ProbeObject {
+	return &v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: status, Labels: &labels}
+}
+
+func TestLog_Record(t *testing.T) {
+	sink := &recordingSink{}
+	log := NewLog(10, failingSink{}, sink)
+
+	ctx := WithActor(context.Background(), "alice")
+	ctx = limits.WithTenant(ctx, "team-a")
+	ctx = logging.WithRequestID(ctx, "req-1")
+	before := probe(v1.Active, v1.LabelsSchema{"env": "prod", "team": "sre"})
+	after := *before
+	after.Status = v1.Terminating
+	after.Labels = &v1.LabelsSchema{"env": "staging", "owner": "bob"}
+	version := "2"
+	after.ResourceVersion = &version
+
+	log.Record(ctx, v1.UpdateProbe, before.Id, before, &after)
+
+	require.Len(t, sink.entries, 1, "a failing sink does not stop the others")
+	entry := sink.entries[0]
+	assert.NotEqual(t, uuid.Nil, entry.Id)
+	assert.WithinDuration(t, time.Now(), entry.Timestamp, time.Minute)
+	assert.Equal(t, v1.UpdateProbe, entry.Operation)
+	assert.Equal(t, before.Id, entry.ProbeId)
+	assert.Equal(t, "alice", *entry.Actor)
+	assert.Equal(t, "team-a", *entry.Tenant)
+	assert.Equal(t, "req-1", *entry.RequestId)
+	assert.Nil(t, entry.RemoteAddr)
+	assert.Equal(t, []string{"labels.env", "labels.owner", "labels.team", "status"}, entry.Changes)
+	assert.Equal(t, []v1.AuditEntry{entry}, log.List(Filter{}))
+}
+
+func TestChanges(t *testing.T) {
+	p := probe(v1.Pending, v1.LabelsSchema{"env": "prod"})
+	empty := *p
+	empty.Labels = &v1.LabelsSchema{"env": ""}
+
+	testCases := []struct {
+		name          string
+		before, after *v1.ProbeObject
+		expected      []string
+	}{
+		{name: "created", after: p, expected: []string{"id", "labels.env", "static_url", "status"}},
+		{name: "removed", before: p, expected: []string{"id", "labels.env", "static_url", "status"}},
+		{name: "unchanged", before: p, after: p, expected: []string{}},
+		{name: "label emptied", before: p, after: &empty, expected: []string{"labels.env"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, changes(tc.before, tc.after))
+		})
+	}
+}
+
+func TestLog_List(t *testing.T) {
+	log := NewLog(3)
+	first, second := probe(v1.Pending, nil), probe(v1.Pending, nil)
+	alice := WithActor(context.Background(), "alice")
+	bob := WithActor(context.Background(), "bob")
+
+	log.Record(alice, v1.CreateProbe, first.Id, nil, first)
+	log.Record(alice, v1.CreateProbe, second.Id, nil, second)
+	start := time.Now()
+	log.Record(bob, v1.DeleteProbe, first.Id, first, nil)
+	log.Record(bob, v1.DeleteProbe, second.Id, second, nil)
+
+	all := log.List(Filter{})
+	require.Len(t, all, 3, "the oldest entry is dropped")
+	assert.Equal(t, second.Id, all[0].ProbeId, "newest first")
+
+	testCases := []struct {
+		name     string
+		filter   Filter
+		expected int
+	}{
+		{name: "probe", filter: Filter{ProbeID: first.Id}, expected: 1},
+		{name: "actor", filter: Filter{Actor: "bob"}, expected: 2},
+		{name: "unknown actor", filter: Filter{Actor: "carol"}, expected: 0},
+		{name: "operation", filter: Filter{Operation: v1.CreateProbe}, expected: 1},
+		{name: "since", filter: Filter{Since: start}, expected: 2},
+		{name: "allow", filter: Filter{Allow: func(e v1.AuditEntry) bool { return e.After != nil }}, expected: 1},
+		{name: "limit", filter: Filter{Limit: 2}, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Len(t, log.List(tc.filter), tc.expected)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cn       string
+		header   string
+		expected string
+	}{
+		{name: "client certificate", cn: "rmo", header: "alice", expected: "rmo"},
+		{name: "proxy header", header: "alice", expected: "alice"},
+		{name: "long header", header: strings.Repeat("a", 300), expected: strings.Repeat("a", maxActorLength)},
+		{name: "anonymous"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got caller
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = callerFromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodPost, "/probes", nil)
+			if tc.cn != "" {
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: tc.cn}}}}}
+			}
+			if tc.header != "" {
+				req.Header.Set(ActorHeader, tc.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.expected, got.actor)
+			assert.Equal(t, req.RemoteAddr, got.remoteAddr)
+		})
+	}
+}
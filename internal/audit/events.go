@@ -0,0 +1,77 @@
+//go:build !nokube
+
+package audit
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const eventComponent = "rhobs-synthetics-api"
+
+// EventSink records each entry as a Kubernetes Event on the object the probe
+// is stored in. The entry is kept as JSON in the EntryAnnotation. Events are
+// removed by the API server after its event TTL, one hour by default.
+type EventSink struct {
+	Client    kubernetes.Interface
+	Namespace string
+	// Object returns the reference to the object storing the probe. The
+	// Event is created in the namespace of the object, which may differ from
+	// Namespace when the store shards probes over several namespaces.
+	Object func(ctx context.Context, namespace string, probeID uuid.UUID) corev1.ObjectReference
+}
+
+func (s *EventSink) Name() string {
+	return "events"
+}
+
+func (s *EventSink) Write(ctx context.Context, entry v1.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	message := fmt.Sprintf("%s of probe %s", entry.Operation, entry.ProbeId)
+	if entry.Actor != nil {
+		message += " by " + *entry.Actor
+	}
+	when := metav1.NewTime(entry.Timestamp)
+	object := s.Object(ctx, s.Namespace, entry.ProbeId)
+	namespace := cmp.Or(object.Namespace, s.Namespace)
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "rhobs-synthetics-audit-" + entry.Id.String(),
+			Namespace:   namespace,
+			Labels:      map[string]string{ProbeIDLabel: entry.ProbeId.String()},
+			Annotations: map[string]string{EntryAnnotation: string(data)},
+		},
+		InvolvedObject: object,
+		Reason:         eventReason(entry.Operation),
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: eventComponent},
+		FirstTimestamp: when,
+		LastTimestamp:  when,
+		Count:          1,
+	}
+	_, err = s.Client.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{})
+	return err
+}
+
+// eventReason turns an operation into the UpperCamelCase reason Kubernetes
+// Events use, e.g. DeleteProbe.
+func eventReason(operation v1.AuditOperation) string {
+	op := string(operation)
+	if op == "" {
+		return ""
+	}
+	return strings.ToUpper(op[:1]) + op[1:]
+}
@@ -0,0 +1,56 @@
+//go:build !nokube
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEventSink(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := &EventSink{
+		Client:    client,
+		Namespace: "rhobs",
+		Object: func(_ context.Context, namespace string, probeID uuid.UUID) corev1.ObjectReference {
+			return corev1.ObjectReference{Kind: "ConfigMap", Namespace: namespace, Name: "probe-config-" + probeID.String()}
+		},
+	}
+	actor := "alice"
+	entry := v1.AuditEntry{Id: uuid.New(), ProbeId: uuid.New(), Operation: v1.DeleteProbe, Actor: &actor}
+
+	require.NoError(t, sink.Write(context.Background(), entry))
+
+	events, err := client.CoreV1().Events("rhobs").List(context.Background(), metav1.ListOptions{LabelSelector: ProbeIDLabel + "=" + entry.ProbeId.String()})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 1)
+	event := events.Items[0]
+	assert.Equal(t, "DeleteProbe", event.Reason)
+	assert.Equal(t, "deleteProbe of probe "+entry.ProbeId.String()+" by alice", event.Message)
+	assert.Equal(t, "probe-config-"+entry.ProbeId.String(), event.InvolvedObject.Name)
+	var stored v1.AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(event.Annotations[EntryAnnotation]), &stored))
+	assert.Equal(t, entry.Id, stored.Id)
+
+	t.Run("objects in other namespaces", func(t *testing.T) {
+		sink := *sink
+		sink.Object = func(_ context.Context, _ string, probeID uuid.UUID) corev1.ObjectReference {
+			return corev1.ObjectReference{Kind: "ConfigMap", Namespace: "probes-1", Name: "probe-config-" + probeID.String()}
+		}
+		entry := v1.AuditEntry{Id: uuid.New(), ProbeId: uuid.New(), Operation: v1.CreateProbe}
+		require.NoError(t, sink.Write(context.Background(), entry))
+
+		events, err := client.CoreV1().Events("probes-1").List(context.Background(), metav1.ListOptions{LabelSelector: ProbeIDLabel + "=" + entry.ProbeId.String()})
+		require.NoError(t, err)
+		assert.Len(t, events.Items, 1, "events are created next to the object")
+	})
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+)
+
+const (
+	// hashedActorPrefix marks actors replaced by their keyed hash.
+	hashedActorPrefix = "hmac-sha256:"
+	// minHashKeyLength is the shortest key actors may be hashed with.
+	minHashKeyLength = 32
+)
+
+// Privacy controls how much personal data audit entries keep, and for how
+// long. The zero value keeps entries as recorded until the history is full.
+type Privacy struct {
+	// Retention is how long entries are kept in memory; zero keeps them
+	// until newer ones push them out. Sinks keep entries as long as their
+	// storage does: Kubernetes Events expire, files must be rotated.
+	Retention time.Duration
+	// TruncateIPs records the caller's network, a /24 for IPv4 and a /48 for
+	// IPv6, instead of its address and port.
+	TruncateIPs bool
+	// HashActors records a keyed hash of the actor instead of its name, so
+	// the changes of one actor can still be told apart and filtered on. The
+	// system actor is kept as is.
+	HashActors bool
+	// HashKey keys the actor hashes. It must be the same on every replica
+	// for hashes to match across them; a random key is used when empty.
+	HashKey []byte
+}
+
+// Validate reports whether entries could be recorded with the settings.
+func (p Privacy) Validate() error {
+	if p.Retention < 0 {
+		return fmt.Errorf("audit retention must be positive, got %s", p.Retention)
+	}
+	if len(p.HashKey) > 0 && len(p.HashKey) < minHashKeyLength {
+		return fmt.Errorf("audit hash key must be at least %d bytes long, got %d", minHashKeyLength, len(p.HashKey))
+	}
+	return nil
+}
+
+// withDefaults fills in a random hash key when actors are hashed without one.
+func (p Privacy) withDefaults() Privacy {
+	if p.HashActors && len(p.HashKey) == 0 {
+		p.HashKey = make([]byte, minHashKeyLength)
+		_, _ = rand.Read(p.HashKey) // crypto/rand.Read never returns an error
+	}
+	return p
+}
+
+// actor returns the actor as recorded.
+func (p Privacy) actor(actor string) string {
+	if !p.HashActors || actor == "" || actor == SystemActor {
+		return actor
+	}
+	mac := hmac.New(sha256.New, p.HashKey)
+	mac.Write([]byte(actor))
+	return hashedActorPrefix + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// remoteAddr returns the caller's address as recorded. Addresses that cannot
+// be parsed are dropped when truncating, rather than kept whole.
+func (p Privacy) remoteAddr(addr string) string {
+	if !p.TruncateIPs || addr == "" {
+		return addr
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return ""
+	}
+	bits := 48
+	if ip.Unmap().Is4() {
+		ip, bits = ip.Unmap(), 24
+	}
+	prefix, _ := ip.WithZone("").Prefix(bits)
+	return prefix.String()
+}
+
+// expired reports whether an entry recorded at t is past the retention.
+func (p Privacy) expired(t, now time.Time) bool {
+	return p.Retention > 0 && now.Sub(t) > p.Retention
+}
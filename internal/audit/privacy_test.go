@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivacy_Validate(t *testing.T) {
+	assert.NoError(t, Privacy{}.Validate())
+	assert.NoError(t, Privacy{Retention: time.Hour, HashActors: true, HashKey: []byte(strings.Repeat("k", 32))}.Validate())
+	assert.Error(t, Privacy{Retention: -time.Hour}.Validate())
+	assert.Error(t, Privacy{HashActors: true, HashKey: []byte("short")}.Validate())
+}
+
+func TestPrivacy_RemoteAddr(t *testing.T) {
+	testCases := []struct {
+		addr     string
+		expected string
+	}{
+		{addr: "10.128.0.12:48120", expected: "10.128.0.0/24"},
+		{addr: "10.128.0.12", expected: "10.128.0.0/24"},
+		{addr: "[2001:db8:1234:5678::1]:443", expected: "2001:db8:1234::/48"},
+		{addr: "[::ffff:192.0.2.7]:80", expected: "192.0.2.0/24"},
+		{addr: "[fe80::1%eth0]:80", expected: "fe80::/48"},
+		{addr: "not-an-address", expected: ""},
+		{addr: "", expected: ""},
+	}
+
+	p := Privacy{TruncateIPs: true}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, p.remoteAddr(tc.addr), tc.addr)
+	}
+	assert.Equal(t, "10.128.0.12:48120", Privacy{}.remoteAddr("10.128.0.12:48120"), "addresses are kept unless truncated")
+}
+
+func TestPrivacy_Actor(t *testing.T) {
+	key := []byte(strings.Repeat("k", 32))
+	p := Privacy{HashActors: true, HashKey: key}
+
+	hashed := p.actor("alice")
+	assert.True(t, strings.HasPrefix(hashed, hashedActorPrefix))
+	assert.NotContains(t, hashed, "alice")
+	assert.Equal(t, hashed, Privacy{HashActors: true, HashKey: key}.actor("alice"), "the same key gives the same hash")
+	assert.NotEqual(t, hashed, p.actor("bob"))
+	assert.NotEqual(t, hashed, Privacy{HashActors: true, HashKey: []byte(strings.Repeat("x", 32))}.actor("alice"))
+	assert.Equal(t, SystemActor, p.actor(SystemActor))
+	assert.Equal(t, "", p.actor(""))
+	assert.Equal(t, "alice", Privacy{}.actor("alice"))
+}
+
+func TestLog_Privacy(t *testing.T) {
+	log := NewLog(10)
+	log.SetPrivacy(Privacy{Retention: time.Hour, TruncateIPs: true, HashActors: true})
+	ctx := context.WithValue(context.Background(), callerKey{}, caller{actor: "alice", remoteAddr: "10.128.0.12:48120"})
+
+	probeID := uuid.New()
+	log.Record(ctx, v1.CreateProbe, probeID, nil, &v1.ProbeObject{Id: probeID})
+
+	entries := log.List(Filter{})
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].Actor)
+	assert.True(t, strings.HasPrefix(*entries[0].Actor, hashedActorPrefix), "a random key is used without one")
+	require.NotNil(t, entries[0].RemoteAddr)
+	assert.Equal(t, "10.128.0.0/24", *entries[0].RemoteAddr)
+
+	assert.Len(t, log.List(Filter{Actor: "alice"}), 1, "actors can be filtered by name")
+	assert.Len(t, log.List(Filter{Actor: *entries[0].Actor}), 1, "and by hash")
+	assert.Empty(t, log.List(Filter{Actor: "bob"}))
+
+	t.Run("retention", func(t *testing.T) {
+		log.mu.Lock()
+		log.entries[0].Timestamp = time.Now().UTC().Add(-2 * time.Hour)
+		log.mu.Unlock()
+		assert.Empty(t, log.List(Filter{}))
+
+		log.mu.RLock()
+		defer log.mu.RUnlock()
+		assert.Empty(t, log.entries, "expired entries are dropped from memory")
+	})
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+const (
+	// EntryAnnotation holds the JSON audit entry on the Events written by
+	// EventSink.
+	EntryAnnotation = "rhobs-synthetics/audit-entry"
+	// ProbeIDLabel holds the probe ID on the Events written by EventSink, so
+	// the changes to a probe can be selected.
+	ProbeIDLabel = "rhobs-synthetics/probe-id"
+)
+
+// WriterSink writes each entry to a writer as a line of JSON, e.g. to stdout
+// or an append-only file.
+type WriterSink struct {
+	name string
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink creates a WriterSink identified by name.
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{name: name, w: w}
+}
+
+func (s *WriterSink) Name() string {
+	return s.name
+}
+
+func (s *WriterSink) Write(ctx context.Context, entry v1.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink("stdout", &buf)
+	assert.Equal(t, "stdout", sink.Name())
+
+	first, second := v1.AuditEntry{Id: uuid.New(), Operation: v1.CreateProbe}, v1.AuditEntry{Id: uuid.New(), Operation: v1.DeleteProbe}
+	require.NoError(t, sink.Write(context.Background(), first))
+	require.NoError(t, sink.Write(context.Background(), second))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2, "one JSON line per entry")
+	var got v1.AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, second.Id, got.Id)
+}
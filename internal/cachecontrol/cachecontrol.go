@@ -0,0 +1,133 @@
+// Package cachecontrol tells HTTP caches, such as intermediary proxies and
+// client libraries, how long successful GET responses may be reused. Each
+// route gets its own Cache-Control policy.
+package cachecontrol
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rule sets the Cache-Control header of successful GET and HEAD responses on
+// a route.
+type Rule struct {
+	// Route is a net/http ServeMux pattern without a method or host, such as
+	// "/probes/{probe_id}" or "/" for every route no other rule matches.
+	Route string `mapstructure:"route"`
+	// MaxAge is how long a response may be reused. Zero means caches must
+	// revalidate it with the server before every reuse.
+	MaxAge time.Duration `mapstructure:"max_age"`
+	// Private keeps responses out of shared caches, for responses that
+	// depend on who is asking.
+	Private bool `mapstructure:"private"`
+}
+
+// Header returns the Cache-Control value the rule sets.
+func (r Rule) Header() string {
+	scope := "public"
+	if r.Private {
+		scope = "private"
+	}
+	if r.MaxAge <= 0 {
+		return scope + ", no-cache"
+	}
+	return scope + ", max-age=" + strconv.Itoa(int(r.MaxAge.Seconds()))
+}
+
+// DefaultRules lets the API documentation be cached for a few minutes, as it
+// only changes on upgrades, and has probe data revalidated on every use.
+// Probe listings depend on the caller's tenant, so they are private.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Route: "/api/v1/openapi.json", MaxAge: 5 * time.Minute},
+		{Route: "/api/v2/openapi.json", MaxAge: 5 * time.Minute},
+		{Route: "/docs", MaxAge: 5 * time.Minute},
+		{Route: "/", Private: true},
+	}
+}
+
+// Middleware sets the Cache-Control header of successful GET and HEAD
+// responses to that of the rule whose route matches the request, following
+// the ServeMux precedence rules. Handlers that set the header themselves
+// keep theirs, and responses no rule matches get none.
+func Middleware(rules []Rule) (func(http.Handler) http.Handler, error) {
+	routes := http.NewServeMux()
+	for i, rule := range rules {
+		if rule.MaxAge < 0 {
+			return nil, fmt.Errorf("cache_control[%d]: max_age must not be negative, got %s", i, rule.MaxAge)
+		}
+		if err := handle(routes, rule.Route, rule.Header()); err != nil {
+			return nil, fmt.Errorf("cache_control[%d]: %w", i, err)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// Unmatched requests get a not found or redirect handler instead.
+			matched, _ := routes.Handler(r)
+			p, ok := matched.(policy)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(&responseWriter{ResponseWriter: w, value: p.value}, r)
+		})
+	}, nil
+}
+
+// policy is registered for each route to carry its header value; it is only
+// looked up, never served.
+type policy struct {
+	value string
+}
+
+func (p policy) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// handle registers the route, reporting invalid or duplicate patterns, which
+// ServeMux panics on, as errors.
+func handle(routes *http.ServeMux, route, value string) (err error) {
+	if route == "" || route[0] != '/' {
+		return fmt.Errorf("route %q must start with /", route)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid route %q: %v", route, r)
+		}
+	}()
+	routes.Handle(route, policy{value: value})
+	return nil
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.wroteHeader = true
+		if (code >= 200 && code < 300 || code == http.StatusNotModified) && rw.Header().Get("Cache-Control") == "" {
+			rw.Header().Set("Cache-Control", rw.value)
+		}
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,103 @@
+package cachecontrol
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Header(t *testing.T) {
+	testCases := []struct {
+		rule     Rule
+		expected string
+	}{
+		{rule: Rule{MaxAge: time.Hour}, expected: "public, max-age=3600"},
+		{rule: Rule{MaxAge: 30 * time.Second, Private: true}, expected: "private, max-age=30"},
+		{rule: Rule{}, expected: "public, no-cache"},
+		{rule: Rule{Private: true}, expected: "private, no-cache"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.rule.Header())
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	middleware, err := Middleware([]Rule{
+		{Route: "/api/v1/openapi.json", MaxAge: time.Hour},
+		{Route: "/probes/{probe_id}", MaxAge: time.Minute, Private: true},
+		{Route: "/probes/{probe_id}/results", Private: true},
+	})
+	require.NoError(t, err)
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/explicit":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		expected string
+	}{
+		{name: "exact route", method: http.MethodGet, path: "/api/v1/openapi.json", expected: "public, max-age=3600"},
+		{name: "wildcard route", method: http.MethodGet, path: "/probes/d290f1ee-6c54-4b01-90e6-d701748f0851", expected: "private, max-age=60"},
+		{name: "more specific route", method: http.MethodHead, path: "/probes/d290f1ee-6c54-4b01-90e6-d701748f0851/results", expected: "private, no-cache"},
+		{name: "writes", method: http.MethodPatch, path: "/probes/d290f1ee-6c54-4b01-90e6-d701748f0851"},
+		{name: "unmatched route", method: http.MethodGet, path: "/probes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.expected, rec.Header().Get("Cache-Control"))
+		})
+	}
+
+	t.Run("errors and explicit headers", func(t *testing.T) {
+		middleware, err := Middleware([]Rule{{Route: "/", MaxAge: time.Hour}})
+		require.NoError(t, err)
+		handler := middleware(handler)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		assert.Empty(t, rec.Header().Get("Cache-Control"), "errors are not cached")
+
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/explicit", nil))
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	})
+}
+
+func TestMiddleware_InvalidRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rules       []Rule
+		expectedErr string
+	}{
+		{name: "relative route", rules: []Rule{{Route: "probes"}}, expectedErr: `cache_control[0]: route "probes" must start with /`},
+		{name: "method in route", rules: []Rule{{Route: "GET /probes"}}, expectedErr: `cache_control[0]: route "GET /probes" must start with /`},
+		{name: "negative max age", rules: []Rule{{Route: "/", MaxAge: -time.Second}}, expectedErr: "cache_control[0]: max_age must not be negative, got -1s"},
+		{name: "duplicate route", rules: []Rule{{Route: "/docs"}, {Route: "/docs"}}, expectedErr: `cache_control[1]: invalid route "/docs"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Middleware(tc.rules)
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
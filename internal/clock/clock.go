@@ -0,0 +1,72 @@
+// Package clock centralizes how the API handles time. Timestamps are kept in
+// UTC without a monotonic clock reading, so they compare the same way before
+// and after a round trip through storage, and are exchanged as RFC 3339.
+// Timestamps supplied by clients are checked against the server clock, so a
+// client whose clock runs ahead cannot order its records after those written
+// later.
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSkewTolerance is how far ahead of the server clock a timestamp
+// supplied by a client may be.
+const DefaultSkewTolerance = time.Minute
+
+// Now returns the current time in UTC. Converting to UTC drops the monotonic
+// clock reading; measure durations with time.Now and time.Since instead.
+func Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Stamp returns the current time in UTC truncated to seconds, the precision
+// of the timestamps the probe stores keep.
+func Stamp() time.Time {
+	return Now().Truncate(time.Second)
+}
+
+// Format formats t in UTC as RFC 3339.
+func Format(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Parse parses an RFC 3339 timestamp and returns it in UTC.
+func Parse(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// SkewError reports a timestamp supplied by a client that is further ahead
+// of the server clock than tolerated.
+type SkewError struct {
+	// Field names the timestamp in the request.
+	Field     string
+	Time      time.Time
+	Now       time.Time
+	Tolerance time.Duration
+}
+
+// Skew is how far ahead of the server clock the timestamp is.
+func (e *SkewError) Skew() time.Duration {
+	return e.Time.Sub(e.Now)
+}
+
+func (e *SkewError) Error() string {
+	return fmt.Sprintf("%s %s is %s ahead of the server clock (%s), more than the %s tolerated",
+		e.Field, Format(e.Time), e.Skew().Round(time.Second), Format(e.Now), e.Tolerance)
+}
+
+// CheckSkew returns a *SkewError if t, the timestamp named field supplied by
+// a client, is ahead of now by more than tolerance. Timestamps in the past
+// are always accepted.
+func CheckSkew(field string, t, now time.Time, tolerance time.Duration) error {
+	if t.Sub(now) <= tolerance {
+		return nil
+	}
+	return &SkewError{Field: field, Time: t.UTC(), Now: now.UTC(), Tolerance: tolerance}
+}
@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNow(t *testing.T) {
+	now := Now()
+	assert.Equal(t, time.UTC, now.Location())
+	assert.Equal(t, now, now.Round(0), "the monotonic clock reading is dropped")
+	assert.Zero(t, Stamp().Nanosecond())
+}
+
+func TestFormatParse(t *testing.T) {
+	local := time.Date(2026, 3, 10, 14, 30, 0, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, "2026-03-10T13:30:00Z", Format(local))
+
+	parsed, err := Parse("2026-03-10T14:30:00+01:00")
+	require.NoError(t, err)
+	assert.Equal(t, time.UTC, parsed.Location())
+	assert.True(t, parsed.Equal(local))
+
+	_, err = Parse("10 March 2026")
+	assert.Error(t, err)
+}
+
+func TestCheckSkew(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		t     time.Time
+		valid bool
+	}{
+		{name: "past", t: now.Add(-24 * time.Hour), valid: true},
+		{name: "now", t: now, valid: true},
+		{name: "within tolerance", t: now.Add(time.Minute), valid: true},
+		{name: "beyond tolerance", t: now.Add(time.Minute + time.Second)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckSkew("timestamp", tc.t, now, time.Minute)
+			if tc.valid {
+				assert.NoError(t, err)
+				return
+			}
+			var skewErr *SkewError
+			require.True(t, errors.As(err, &skewErr), "got %v", err)
+			assert.Equal(t, time.Minute+time.Second, skewErr.Skew())
+			assert.EqualError(t, err, "timestamp 2026-03-10T12:01:01Z is 1m1s ahead of the server clock (2026-03-10T12:00:00Z), more than the 1m0s tolerated")
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Package compression gzips responses for clients that accept it, so that
+// large probe listings polled by agents cost a fraction of their size on the
+// wire. Small responses are sent as they are, since gzip would barely shrink
+// them.
+package compression
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MinSize is the size a response body must reach to be compressed.
+const MinSize = 1024
+
+var writers = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+
+// Middleware gzips the responses of requests whose Accept-Encoding allows
+// gzip, once their body reaches MinSize. Responses that already carry a
+// Content-Encoding, and those without a body, are left alone.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		rw := &responseWriter{ResponseWriter: w}
+		defer rw.close()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, naming
+// it or "*" without a zero quality.
+func acceptsGzip(header string) bool {
+	for coding := range strings.SplitSeq(header, ",") {
+		name, params, _ := strings.Cut(coding, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		if quality, err := strconv.ParseFloat(q, 64); err == nil && quality > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// responseWriter holds the start of the body back until it is known whether
+// the response is compressed: as soon as MinSize bytes are written, or with
+// whatever was written when the handler returns or flushes.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if rw.status != 0 || code < http.StatusOK {
+		if rw.status == 0 {
+			rw.ResponseWriter.WriteHeader(code)
+		}
+		return
+	}
+	rw.status = code
+	if code == http.StatusNoContent || code == http.StatusNotModified || rw.Header().Get("Content-Encoding") != "" {
+		rw.decide(false)
+	}
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.decided {
+		if rw.gz != nil {
+			return rw.gz.Write(b)
+		}
+		return rw.ResponseWriter.Write(b)
+	}
+	rw.buf = append(rw.buf, b...)
+	if len(rw.buf) >= MinSize {
+		if err := rw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// decide sends the headers, compressed or not, and the body held back.
+func (rw *responseWriter) decide(compress bool) error {
+	rw.decided = true
+	if compress {
+		rw.Header().Set("Content-Encoding", "gzip")
+		rw.Header().Del("Content-Length")
+		rw.gz = writers.Get().(*gzip.Writer)
+		rw.gz.Reset(rw.ResponseWriter)
+	}
+	rw.ResponseWriter.WriteHeader(rw.status)
+	buf := rw.buf
+	rw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if rw.gz != nil {
+		_, err := rw.gz.Write(buf)
+		return err
+	}
+	_, err := rw.ResponseWriter.Write(buf)
+	return err
+}
+
+// Flush sends what was written so far, uncompressed if it is still below
+// MinSize.
+func (rw *responseWriter) Flush() {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if !rw.decided {
+		_ = rw.decide(len(rw.buf) >= MinSize)
+	}
+	if rw.gz != nil {
+		_ = rw.gz.Flush()
+	}
+	_ = http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// close ends the response once the handler returns.
+func (rw *responseWriter) close() {
+	if rw.status == 0 {
+		return
+	}
+	if !rw.decided {
+		_ = rw.decide(false)
+	}
+	if rw.gz != nil {
+		_ = rw.gz.Close()
+		rw.gz.Reset(nil)
+		writers.Put(rw.gz)
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,110 @@
+package compression
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	for header, expected := range map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, gzip;q=0.5":  true,
+		"GZIP":                 true,
+		"*":                    true,
+		"br, gzip;q=0":         false,
+		"identity":             false,
+		"gzip;q=0.0, deflate":  false,
+		"deflate, *;q=0.1, br": true,
+	} {
+		assert.Equal(t, expected, acceptsGzip(header), header)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"static_url":"https://example.com"},`, 100)
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", "3700")
+			for chunk := range strings.SplitSeq(large, ",") {
+				_, _ = io.WriteString(w, chunk+",")
+			}
+		case "/small":
+			_, _ = io.WriteString(w, "{}")
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = io.WriteString(w, large)
+		case "/flushed":
+			_, _ = io.WriteString(w, "{")
+			http.NewResponseController(w).Flush() //nolint:errcheck
+			_, _ = io.WriteString(w, large)
+		}
+	}))
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("large responses are compressed", func(t *testing.T) {
+		rec := get("/large", "gzip")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Empty(t, rec.Header().Get("Content-Length"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		assert.Less(t, rec.Body.Len(), len(large))
+		gz, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, large+",", string(body))
+	})
+
+	t.Run("clients that do not accept gzip", func(t *testing.T) {
+		rec := get("/large", "")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, large+",", rec.Body.String())
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+	})
+
+	t.Run("small responses are sent as they are", func(t *testing.T) {
+		rec := get("/small", "gzip")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "{}", rec.Body.String())
+	})
+
+	t.Run("responses without a body", func(t *testing.T) {
+		rec := get("/not-modified", "gzip")
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Zero(t, rec.Body.Len())
+	})
+
+	t.Run("responses already encoded", func(t *testing.T) {
+		rec := get("/encoded", "gzip")
+		assert.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, rec.Body.String())
+	})
+
+	t.Run("flushes send what was written", func(t *testing.T) {
+		rec := get("/flushed", "gzip")
+		assert.True(t, rec.Flushed)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"), "the body was below the minimum size when flushed")
+		assert.Equal(t, "{"+large, rec.Body.String())
+	})
+}
@@ -0,0 +1,303 @@
+// Package events publishes probe lifecycle changes as CloudEvents to a
+// message bus, so downstream systems such as alert routing or a CMDB can
+// react to probes being created, changing status and being deleted without
+// polling the API.
+//
+// Unlike webhook deliveries, events are not queued in memory: each one is
+// written to an outbox kept in the probe store, right after the change it
+// records, and relayed from there to the sink. The relay deletes an event only
+// once the sink acknowledged it, and sends events in the order they were
+// recorded, so every event is published at least once, even across restarts
+// and sink outages. Consumers drop duplicates by the event ID.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// SpecVersion is the CloudEvents version of the events.
+	SpecVersion = "1.0"
+	// TypePrefix is prepended to the webhook event types, such as
+	// probe.created, to form the CloudEvents types.
+	TypePrefix = "io.rhobs.synthetics."
+	// DefaultSource is the Source used when none is configured.
+	DefaultSource = "/rhobs-synthetics-api"
+
+	// DefaultTimeout is the Timeout used when none is configured.
+	DefaultTimeout = 10 * time.Second
+	// DefaultPollInterval is the PollInterval used when none is configured.
+	DefaultPollInterval = 10 * time.Second
+	// DefaultMaxBackoff is the MaxBackoff used when none is configured.
+	DefaultMaxBackoff = time.Minute
+)
+
+// Event is a CloudEvent in its JSON format, which is how every sink sends
+// it.
+type Event struct {
+	SpecVersion string `json:"specversion"`
+	ID          string `json:"id"`
+	Source      string `json:"source"`
+	Type        string `json:"type"`
+	// Subject is the ID of the probe the event is about.
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// Data is the data of every probe event.
+type Data struct {
+	Probe v1.ProbeObject `json:"probe"`
+	// PreviousStatus is the status of the probe before the change, unset for
+	// created probes.
+	PreviousStatus *v1.StatusSchema `json:"previous_status,omitempty"`
+}
+
+// Config is the events section of the config file.
+type Config struct {
+	// Sink is where events are published: "http", "kafka" or "nats". Empty
+	// disables events.
+	Sink string `mapstructure:"sink"`
+	// URL addresses the sink: the URL events are POSTed to, the Kafka
+	// brokers (kafka:// or kafka+tls://, with optional SASL user and
+	// password), or the NATS server (nats:// or tls://, with optional user and
+	// password or token).
+	URL string `mapstructure:"url"`
+	// Topic is the Kafka topic or NATS subject events are published to.
+	Topic string `mapstructure:"topic"`
+	// JetStream waits for a NATS JetStream stream to store each event,
+	// rather than only for the server to receive it.
+	JetStream bool `mapstructure:"jetstream"`
+	// Headers are added to the requests of the http sink, e.g. to
+	// authenticate.
+	Headers map[string]string `mapstructure:"headers"`
+	// Source is the CloudEvents source of the events; DefaultSource when
+	// empty.
+	Source string `mapstructure:"source"`
+	// Timeout bounds each attempt to publish an event.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// PollInterval is how often the outbox is read for events recorded by
+	// other replicas; events recorded by this one are relayed at once.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// MaxBackoff caps the wait between attempts while the sink fails,
+	// doubled from one second.
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+}
+
+// Enabled reports whether a sink is configured.
+func (c Config) Enabled() bool {
+	return c.Sink != ""
+}
+
+func (c Config) withDefaults() Config {
+	if c.Source == "" {
+		c.Source = DefaultSource
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultPollInterval
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultMaxBackoff
+	}
+	return c
+}
+
+// Sink publishes events to a message bus.
+type Sink interface {
+	// Publish returns once the bus acknowledged the event.
+	Publish(ctx context.Context, event Event) error
+}
+
+// newSink returns the sink the config selects.
+func newSink(cfg Config) (Sink, error) {
+	switch cfg.Sink {
+	case "http":
+		return NewHTTPSink(cfg.URL, cfg.Headers)
+	case "kafka":
+		return NewKafkaSink(cfg.URL, cfg.Topic)
+	case "nats":
+		return NewNATSSink(cfg.URL, cfg.Topic, cfg.JetStream)
+	}
+	return nil, fmt.Errorf("unknown event sink %q, expected one of http, kafka, nats", cfg.Sink)
+}
+
+// Publisher records probe events in the outbox and relays them to the sink.
+type Publisher struct {
+	outbox probestore.OutboxStore
+	sink   Sink
+	config Config
+	// wake is signalled when an event is recorded, so Run relays it without
+	// waiting for the next poll.
+	wake chan struct{}
+}
+
+// New creates a Publisher for the sink the config selects, keeping its
+// outbox in the store. Events are only relayed while Run is running.
+func New(cfg Config, outbox probestore.OutboxStore) (*Publisher, error) {
+	sink, err := newSink(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewWithSink(cfg, outbox, sink), nil
+}
+
+// NewWithSink creates a Publisher for sink, ignoring the sink settings of
+// the config.
+func NewWithSink(cfg Config, outbox probestore.OutboxStore, sink Sink) *Publisher {
+	return &Publisher{
+		outbox: outbox,
+		sink:   sink,
+		config: cfg.withDefaults(),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Publish records a probe event in the outbox, without waiting for it to be
+// relayed. Events the outbox fails to store are logged and dropped. A nil
+// Publisher records nothing.
+func (p *Publisher) Publish(ctx context.Context, eventType v1.WebhookEventType, probe v1.ProbeObject, previousStatus *v1.StatusSchema) {
+	if p == nil {
+		return
+	}
+	event, err := p.newEvent(eventType, probe, previousStatus)
+	if err != nil {
+		metrics.RecordEvent(TypePrefix+string(eventType), "dropped")
+		slog.ErrorContext(ctx, "Error encoding probe event", "event", eventType, "probe_id", probe.Id, "error", err)
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		metrics.RecordEvent(event.Type, "dropped")
+		slog.ErrorContext(ctx, "Error encoding probe event", "event", event.Type, "probe_id", probe.Id, "error", err)
+		return
+	}
+	// The change is made; record its event even if the request was cancelled.
+	ctx = context.WithoutCancel(ctx)
+	if err := p.outbox.AddOutboxEvent(ctx, probestore.OutboxEvent{ID: event.ID, CreatedAt: event.Time, Event: body}); err != nil {
+		metrics.RecordEvent(event.Type, "dropped")
+		slog.ErrorContext(ctx, "Dropped probe event, the outbox could not store it", "event", event.Type, "event_id", event.ID, "probe_id", probe.Id, "error", err)
+		return
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// newEvent returns the CloudEvent of a probe change. Its ID is a
+// time-ordered UUID, so the outbox lists events in the order they happened.
+func (p *Publisher) newEvent(eventType v1.WebhookEventType, probe v1.ProbeObject, previousStatus *v1.StatusSchema) (Event, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Event{}, err
+	}
+	data, err := json.Marshal(Data{Probe: probe, PreviousStatus: previousStatus})
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              id.String(),
+		Source:          p.config.Source,
+		Type:            TypePrefix + string(eventType),
+		Subject:         probe.Id.String(),
+		Time:            clock.Now(),
+		DataContentType: "application/json",
+		Data:            data,
+	}, nil
+}
+
+// Run relays the events of the outbox to the sink until ctx is cancelled:
+// at once when this replica records one, and every poll interval otherwise.
+// While the sink fails, it retries with exponential backoff, keeping the
+// events in the outbox. Only one replica should run it, such as the leader,
+// or events are published once per replica running it.
+func (p *Publisher) Run(ctx context.Context) {
+	backoff := time.Second
+	for {
+		wait := p.config.PollInterval
+		if err := p.relay(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.WarnContext(ctx, "Failed to relay probe events, retrying", "backoff", backoff, "error", err)
+			wait = backoff
+			backoff = min(2*backoff, p.config.MaxBackoff)
+		} else {
+			backoff = time.Second
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-p.wake:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// relay publishes the events of the outbox in order, deleting each once the
+// sink acknowledged it. It stops at the first event the sink fails to take,
+// so that later events are not published before it.
+func (p *Publisher) relay(ctx context.Context) error {
+	pending, err := p.outbox.ListOutboxEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	metrics.SetEventOutboxSize(len(pending))
+	for i, stored := range pending {
+		var event Event
+		if err := json.Unmarshal(stored.Event, &event); err != nil {
+			// It can never be published; do not let it hold up the others.
+			slog.ErrorContext(ctx, "Removing undecodable event from the outbox", "event_id", stored.ID, "error", err)
+		} else if err := p.publish(ctx, event); err != nil {
+			return fmt.Errorf("failed to publish event %s: %w", event.ID, err)
+		} else {
+			metrics.RecordEvent(event.Type, "published")
+		}
+		if err := p.outbox.DeleteOutboxEvent(ctx, stored.ID); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove published event %s from the outbox: %w", stored.ID, err)
+		}
+		metrics.SetEventOutboxSize(len(pending) - i - 1)
+	}
+	return nil
+}
+
+// Close closes the sink's connections, if it keeps any. A nil Publisher has
+// nothing to close.
+func (p *Publisher) Close() error {
+	if p == nil {
+		return nil
+	}
+	if closer, ok := p.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// publish makes one attempt to publish the event, giving up after the
+// timeout.
+func (p *Publisher) publish(ctx context.Context, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+	return p.sink.Publish(ctx, event)
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps the events published to it, failing while err is set.
+type recordingSink struct {
+	mu     sync.Mutex
+	err    error
+	events []Event
+}
+
+func (s *recordingSink) Publish(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *recordingSink) published() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+func newTestPublisher(t *testing.T) (*Publisher, *recordingSink, *probestore.LocalProbeStore) {
+	t.Helper()
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	sink := &recordingSink{}
+	return NewWithSink(Config{Source: "/test"}, store, sink), sink, store
+}
+
+func TestPublisher_Relay(t *testing.T) {
+	ctx := context.Background()
+	p, sink, store := newTestPublisher(t)
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}
+	pending := v1.Pending
+
+	p.Publish(ctx, v1.ProbeCreated, probe, nil)
+	p.Publish(ctx, v1.ProbeStatusChanged, probe, &pending)
+	stored, err := store.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2, "events wait in the outbox")
+
+	sink.setErr(errors.New("bus down"))
+	require.Error(t, p.relay(ctx))
+	stored, err = store.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stored, 2, "events the sink fails to take are kept")
+
+	sink.setErr(nil)
+	require.NoError(t, p.relay(ctx))
+	stored, err = store.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stored, "published events are removed")
+
+	published := sink.published()
+	require.Len(t, published, 2)
+	created, changed := published[0], published[1]
+	assert.Equal(t, "io.rhobs.synthetics.probe.created", created.Type, "events are published in order")
+	assert.Equal(t, "io.rhobs.synthetics.probe.status_changed", changed.Type)
+	assert.Equal(t, SpecVersion, created.SpecVersion)
+	assert.Equal(t, "/test", created.Source)
+	assert.Equal(t, probe.Id.String(), created.Subject)
+	assert.Equal(t, "application/json", created.DataContentType)
+	assert.NotEqual(t, created.ID, changed.ID)
+
+	var data Data
+	require.NoError(t, json.Unmarshal(changed.Data, &data))
+	assert.Equal(t, probe.Id, data.Probe.Id)
+	assert.Equal(t, &pending, data.PreviousStatus)
+}
+
+func TestPublisher_RelaySkipsUndecodableEvents(t *testing.T) {
+	ctx := context.Background()
+	p, sink, store := newTestPublisher(t)
+	require.NoError(t, store.AddOutboxEvent(ctx, probestore.OutboxEvent{ID: "0", Event: json.RawMessage(`"garbage"`)}))
+	p.Publish(ctx, v1.ProbeCreated, v1.ProbeObject{Id: uuid.New()}, nil)
+
+	require.NoError(t, p.relay(ctx))
+	assert.Len(t, sink.published(), 1)
+	stored, err := store.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stored, "undecodable events do not hold up the others")
+}
+
+func TestPublisher_Run(t *testing.T) {
+	p, sink, _ := newTestPublisher(t)
+	p.config.PollInterval = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Run(ctx)
+	}()
+
+	p.Publish(ctx, v1.ProbeCreated, v1.ProbeObject{Id: uuid.New()}, nil)
+	require.Eventually(t, func() bool { return len(sink.published()) == 1 }, 5*time.Second, 10*time.Millisecond,
+		"recorded events are relayed without waiting for the poll interval")
+	cancel()
+	<-done
+}
+
+func TestPublisher_Nil(t *testing.T) {
+	var p *Publisher
+	assert.NotPanics(t, func() { p.Publish(context.Background(), v1.ProbeCreated, v1.ProbeObject{}, nil) })
+}
+
+func TestNew(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{name: "http", cfg: Config{Sink: "http", URL: "https://events.example.com"}},
+		{name: "kafka", cfg: Config{Sink: "kafka", URL: "kafka://kafka:9092", Topic: "probes"}},
+		{name: "nats", cfg: Config{Sink: "nats", URL: "nats://nats:4222", Topic: "probes.events"}},
+		{name: "unknown sink", cfg: Config{Sink: "sqs"}, err: `unknown event sink "sqs", expected one of http, kafka, nats`},
+		{name: "relative url", cfg: Config{Sink: "http", URL: "/events"}, err: `invalid events url: "/events" is not an absolute http or https URL`},
+		{name: "kafka without topic", cfg: Config{Sink: "kafka", URL: "kafka://kafka:9092"}, err: "a topic is required for the kafka event sink"},
+		{name: "nats wildcard", cfg: Config{Sink: "nats", URL: "nats://nats", Topic: "probes.>"}, err: `invalid NATS subject "probes.>", expected a subject without wildcards or spaces`},
+		{name: "nats http url", cfg: Config{Sink: "nats", URL: "http://nats", Topic: "probes"}, err: `invalid events url "http://nats", expected a nats:// or tls:// URL`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := New(tc.cfg, store)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.NoError(t, p.Close())
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
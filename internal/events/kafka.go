@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// kafkaDefaultPort is the port of brokers that name none.
+const kafkaDefaultPort = "9092"
+
+// KafkaSink produces each event to a Kafka topic with the Kafka client. Events
+// are keyed by probe ID, so the events of a probe land on one partition in
+// order, and count as published once every in-sync replica stored them.
+type KafkaSink struct {
+	client *kgo.Client
+}
+
+// NewKafkaSink returns a sink producing to topic on the brokers of rawURL,
+// kafka://[user:password@]host[:port][,host[:port]...], or kafka+tls:// to
+// use TLS. A user authenticates with SASL, PLAIN unless the mechanism query
+// parameter selects SCRAM-SHA-256 or SCRAM-SHA-512. The client connects on
+// the first publish.
+func NewKafkaSink(rawURL, topic string) (*KafkaSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "kafka" && u.Scheme != "kafka+tls") || u.Host == "" {
+		return nil, fmt.Errorf("invalid events url %q, expected a kafka:// or kafka+tls:// URL", rawURL)
+	}
+	if topic == "" {
+		return nil, errors.New("a topic is required for the kafka event sink")
+	}
+	var brokers []string
+	for broker := range strings.SplitSeq(u.Host, ",") {
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			broker = net.JoinHostPort(broker, kafkaDefaultPort)
+		}
+		brokers = append(brokers, broker)
+	}
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.DefaultProduceTopic(topic),
+		kgo.ClientID("rhobs-synthetics-api"),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+	}
+	if u.Scheme == "kafka+tls" {
+		opts = append(opts, kgo.DialTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	if u.User != nil {
+		mechanism, err := kafkaSASL(u.User, u.Query().Get("mechanism"))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kafka settings: %w", err)
+	}
+	return &KafkaSink{client: client}, nil
+}
+
+// kafkaSASL returns the SASL mechanism authenticating user.
+func kafkaSASL(user *url.Userinfo, mechanism string) (sasl.Mechanism, error) {
+	pass, _ := user.Password()
+	switch strings.ToUpper(mechanism) {
+	case "", "PLAIN":
+		return plain.Auth{User: user.Username(), Pass: pass}.AsMechanism(), nil
+	case "SCRAM-SHA-256":
+		return scram.Auth{User: user.Username(), Pass: pass}.AsSha256Mechanism(), nil
+	case "SCRAM-SHA-512":
+		return scram.Auth{User: user.Username(), Pass: pass}.AsSha512Mechanism(), nil
+	}
+	return nil, fmt.Errorf("unknown kafka SASL mechanism %q, expected one of PLAIN, SCRAM-SHA-256, SCRAM-SHA-512", mechanism)
+}
+
+// Publish produces the event and waits for the brokers to acknowledge it.
+func (s *KafkaSink) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	record := &kgo.Record{
+		Key:     []byte(event.Subject),
+		Value:   body,
+		Headers: []kgo.RecordHeader{{Key: "content-type", Value: []byte("application/cloudevents+json")}},
+	}
+	if err := s.client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("kafka did not store the event: %w", err)
+	}
+	return nil
+}
+
+// Close closes the client.
+func (s *KafkaSink) Close() error {
+	s.client.Close()
+	return nil
+}
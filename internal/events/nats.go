@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// NATSSink publishes each event to a NATS subject. It connects on the first
+// publish, so replicas that never relay events never connect, and the client
+// reconnects by itself from then on. Without JetStream, an event counts as
+// published once the server answered a flush after it, so it was received;
+// with JetStream, once the stream acknowledged storing it. JetStream
+// publishes carry the event ID as their message ID, so the stream drops the
+// duplicates of retried publishes within its duplicate window.
+type NATSSink struct {
+	url       string
+	subject   string
+	jetStream bool
+
+	mu   sync.Mutex
+	conn *nats.Conn
+	js   jetstream.JetStream
+}
+
+// NewNATSSink returns a sink publishing to subject on the server at rawURL,
+// nats://[user[:password]@]host[:port], or tls:// to require TLS. A user
+// without a password is sent as a token.
+func NewNATSSink(rawURL, subject string, jetStream bool) (*NATSSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "nats" && u.Scheme != "tls") || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid events url %q, expected a nats:// or tls:// URL", rawURL)
+	}
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n*>") {
+		return nil, fmt.Errorf("invalid NATS subject %q, expected a subject without wildcards or spaces", subject)
+	}
+	return &NATSSink{url: rawURL, subject: subject, jetStream: jetStream}, nil
+}
+
+// Publish sends the event and waits for the server, or the stream, to
+// acknowledge it. Events are not buffered while the client reconnects; they
+// fail, to be retried from the outbox.
+func (s *NATSSink) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	conn, js, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+	if js != nil {
+		if _, err := js.Publish(ctx, s.subject, body, jetstream.WithMsgID(event.ID)); err != nil {
+			return fmt.Errorf("JetStream did not store the event: %w", err)
+		}
+		return nil
+	}
+	if err := conn.Publish(s.subject, body); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return conn.Flush()
+	}
+	return conn.FlushWithContext(ctx)
+}
+
+// connect returns the connection, made on the first call; a failed attempt
+// is made again by the next call.
+func (s *NATSSink) connect(ctx context.Context) (*nats.Conn, jetstream.JetStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn, s.js, nil
+	}
+	opts := []nats.Option{
+		nats.Name("rhobs-synthetics-api"),
+		nats.MaxReconnects(-1),
+		nats.ReconnectBufSize(-1),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		opts = append(opts, nats.Timeout(time.Until(deadline)))
+	}
+	conn, err := nats.Connect(s.url, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	var js jetstream.JetStream
+	if s.jetStream {
+		if js, err = jetstream.New(conn); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("failed to use JetStream: %w", err)
+		}
+	}
+	s.conn, s.js = conn, js
+	return conn, js, nil
+}
+
+// Close closes the connection, if any.
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn, s.js = nil, nil
+	}
+	return nil
+}
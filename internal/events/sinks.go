@@ -0,0 +1,67 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// HTTPSink POSTs each event to a URL in the structured CloudEvents mode.
+type HTTPSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPSink returns a sink posting to rawURL with the extra headers.
+func NewHTTPSink(rawURL string, headers map[string]string) (*HTTPSink, error) {
+	if err := checkHTTPURL(rawURL); err != nil {
+		return nil, fmt.Errorf("invalid events url: %w", err)
+	}
+	return &HTTPSink{url: rawURL, headers: headers, client: &http.Client{}}, nil
+}
+
+// Publish POSTs the event, which the endpoint acknowledges with any 2xx
+// status.
+func (s *HTTPSink) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.client, s.url, "application/cloudevents+json", s.headers, body)
+}
+
+// checkHTTPURL reports whether rawURL is an absolute http or https URL.
+func checkHTTPURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute http or https URL", rawURL)
+	}
+	return nil
+}
+
+// post sends body to rawURL. Statuses other than 2xx are errors.
+func post(ctx context.Context, client *http.Client, rawURL, contentType string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", contentType)
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", rawURL, res.Status)
+	}
+	return nil
+}
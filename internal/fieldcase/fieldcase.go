@@ -0,0 +1,229 @@
+// Package fieldcase lets clients exchange JSON with camelCase field names
+// instead of the API's snake_case ones, for generated clients that expect
+// them. The casing is negotiated per request with the X-Field-Casing header
+// or the field_casing query parameter.
+//
+// Request bodies are rewritten to snake_case before they reach the API, and
+// JSON responses to camelCase on the way out. Keys of labels, features and
+// template variables are data rather than field names and are left as they
+// are.
+package fieldcase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// Header selects the field casing of a request.
+	Header = "X-Field-Casing"
+	// QueryParam selects the field casing of a request where headers cannot
+	// be set; it takes precedence over the header.
+	QueryParam = "field_casing"
+)
+
+// Casing is a way of spelling JSON field names.
+type Casing string
+
+const (
+	// SnakeCase is the API's own casing, e.g. static_url.
+	SnakeCase Casing = "snake_case"
+	// CamelCase spells field names like staticUrl.
+	CamelCase Casing = "camelCase"
+)
+
+// freeFormFields hold maps whose keys are chosen by clients.
+var freeFormFields = map[string]bool{
+	"labels":    true,
+	"features":  true,
+	"variables": true,
+}
+
+// FromRequest returns the casing the request asks for, SnakeCase when it asks
+// for none.
+func FromRequest(r *http.Request) (Casing, error) {
+	value := r.URL.Query().Get(QueryParam)
+	if value == "" {
+		value = r.Header.Get(Header)
+	}
+	switch Casing(value) {
+	case "", SnakeCase:
+		return SnakeCase, nil
+	case CamelCase:
+		return CamelCase, nil
+	}
+	return "", fmt.Errorf("unknown field casing %q, expected %s or %s", value, SnakeCase, CamelCase)
+}
+
+// Middleware converts the bodies of requests asking for camelCase. The
+// query parameter is removed before the request is passed on.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The body depends on the casing, so caches must key on it.
+		w.Header().Add("Vary", Header)
+		casing, err := FromRequest(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if r.URL.Query().Has(QueryParam) {
+			r = r.Clone(r.Context())
+			query := r.URL.Query()
+			query.Del(QueryParam)
+			r.URL.RawQuery = query.Encode()
+			r.RequestURI = r.URL.RequestURI()
+		}
+		if casing == SnakeCase {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Body != nil && isJSON(r.Header.Get("Content-Type")) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			if converted, err := convert(body, camelToSnake); err == nil {
+				body = converted
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		}
+
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+		rw.flush()
+	})
+}
+
+// responseWriter holds the response back so that its body can be converted.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	rw.status = code
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	return rw.body.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *responseWriter) flush() {
+	body := rw.body.Bytes()
+	if isJSON(rw.Header().Get("Content-Type")) {
+		if converted, err := convert(body, snakeToCamel); err == nil {
+			body = converted
+		}
+	}
+	if rw.Header().Get("Content-Length") != "" {
+		rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	rw.ResponseWriter.WriteHeader(rw.status)
+	_, _ = rw.ResponseWriter.Write(body)
+}
+
+// isJSON reports whether the media type is JSON, including types such as
+// application/merge-patch+json.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// convert renames the fields of a JSON document with rename. Invalid or empty
+// documents are returned as an error so callers pass them on unchanged, for
+// the API to reject or the client to see as sent.
+func convert(data []byte, rename func(string) string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Numbers are kept as written rather than rounded through float64.
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	converted, err := json.Marshal(renameKeys(doc, rename))
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasSuffix(data, []byte("\n")) {
+		converted = append(converted, '\n')
+	}
+	return converted, nil
+}
+
+func renameKeys(value any, rename func(string) string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		renamed := make(map[string]any, len(v))
+		for key, field := range v {
+			if freeFormFields[camelToSnake(key)] {
+				renamed[rename(key)] = field
+				continue
+			}
+			renamed[rename(key)] = renameKeys(field, rename)
+		}
+		return renamed
+	case []any:
+		for i, item := range v {
+			v[i] = renameKeys(item, rename)
+		}
+		return v
+	}
+	return value
+}
+
+// snakeToCamel turns static_url into staticUrl.
+func snakeToCamel(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+	parts := strings.Split(key, "_")
+	var b strings.Builder
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// camelToSnake turns staticUrl into static_url. Keys that are already
+// snake_case are left as they are.
+func camelToSnake(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	body, _ := json.Marshal(map[string]any{"error": map[string]string{"message": message}})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
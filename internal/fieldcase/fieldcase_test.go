@@ -0,0 +1,112 @@
+package fieldcase
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		target   string
+		header   string
+		expected Casing
+		wantErr  bool
+	}{
+		{name: "default", target: "/probes", expected: SnakeCase},
+		{name: "header", target: "/probes", header: "camelCase", expected: CamelCase},
+		{name: "query parameter", target: "/probes?field_casing=camelCase", expected: CamelCase},
+		{name: "query parameter wins over header", target: "/probes?field_casing=snake_case", header: "camelCase", expected: SnakeCase},
+		{name: "unknown casing", target: "/probes", header: "PascalCase", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.header != "" {
+				r.Header.Set(Header, tc.header)
+			}
+			casing, err := FromRequest(r)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, casing)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var received string
+	var receivedQuery string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		receivedQuery = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"probes":[{"static_url":"https://example.com","labels":{"cluster_id":"abc"},"resource_version":"7"}],"next_page_token":"t","total":12345678901234567890}`))
+	})
+
+	t.Run("camelCase", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/probes?field_casing=camelCase&label_selector=env%3Dprod", strings.NewReader(`{"staticUrl":"https://example.com","labels":{"clusterId":"abc"},"alerting":{"runbookUrl":"https://runbooks"}}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		Middleware(next).ServeHTTP(w, r)
+
+		assert.JSONEq(t, `{"static_url":"https://example.com","labels":{"clusterId":"abc"},"alerting":{"runbook_url":"https://runbooks"}}`, received, "label keys are left alone")
+		assert.Equal(t, "label_selector=env%3Dprod", receivedQuery, "the casing parameter is not passed on")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"probes":[{"staticUrl":"https://example.com","labels":{"cluster_id":"abc"},"resourceVersion":"7"}],"nextPageToken":"t","total":12345678901234567890}`, w.Body.String())
+		assert.Equal(t, Header, w.Header().Get("Vary"))
+	})
+
+	t.Run("snake_case is passed through", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/probes", strings.NewReader(`{"static_url":"https://example.com"}`))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		Middleware(next).ServeHTTP(w, r)
+
+		assert.Equal(t, `{"static_url":"https://example.com"}`, received)
+		assert.Contains(t, w.Body.String(), `"next_page_token":"t"`)
+	})
+
+	t.Run("unknown casing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Middleware(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/probes?field_casing=kebab-case", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"message":"unknown field casing \"kebab-case\", expected snake_case or camelCase"}}`, w.Body.String())
+	})
+
+	t.Run("non-JSON responses are untouched", func(t *testing.T) {
+		plain := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`{"static_url":"x"}`))
+		})
+		r := httptest.NewRequest(http.MethodGet, "/probes", nil)
+		r.Header.Set(Header, "camelCase")
+		w := httptest.NewRecorder()
+		Middleware(plain).ServeHTTP(w, r)
+		assert.Equal(t, `{"static_url":"x"}`, w.Body.String())
+	})
+}
+
+func TestKeyConversion(t *testing.T) {
+	for snake, camel := range map[string]string{
+		"id":                         "id",
+		"static_url":                 "staticUrl",
+		"next_page_token":            "nextPageToken",
+		"silence_during_maintenance": "silenceDuringMaintenance",
+	} {
+		assert.Equal(t, camel, snakeToCamel(snake))
+		assert.Equal(t, snake, camelToSnake(camel))
+	}
+	assert.Equal(t, "static_url", camelToSnake("static_url"), "snake_case keys are accepted in camelCase requests")
+}
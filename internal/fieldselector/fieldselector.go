@@ -0,0 +1,229 @@
+// Package fieldselector parses the field_selector parameter of ListProbes,
+// which filters probes by their fields rather than their labels.
+//
+// A field selector is a comma-separated list of terms, all of which must
+// match:
+//
+//	status=active              status==active      status!=failed
+//	status in (active,pending) status notin (failed,deleted)
+//	id=<uuid>                  id in (<uuid>,<uuid>)
+//	static_url=<url>           static_url^=<prefix>
+//	owner.team=<team>          owner.team in (<team>,<team>)
+//
+// Probes without an owner team match owner.team!= and notin terms only.
+// Commas separate terms, so static_url values cannot contain one.
+package fieldselector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// Selector is a parsed field selector. The zero value matches every probe.
+type Selector struct {
+	// Statuses, when not empty, are the statuses a probe may have.
+	Statuses []v1.StatusSchema
+	// ExcludedStatuses are statuses a probe may not have.
+	ExcludedStatuses []v1.StatusSchema
+	// IDs, when not empty, are the IDs a probe may have.
+	IDs []uuid.UUID
+	// URL, when set, is the exact static_url a probe must have.
+	URL string
+	// URLPrefix, when set, is a prefix the probe's static_url must start with.
+	URLPrefix string
+	// OwnerTeams, when not empty, are the teams that may own a probe.
+	OwnerTeams []string
+	// ExcludedOwnerTeams are teams that may not own a probe.
+	ExcludedOwnerTeams []string
+}
+
+// Parse parses a field selector. An empty string yields the zero Selector.
+func Parse(selector string) (Selector, error) {
+	var sel Selector
+	seen := make(map[string]bool)
+
+	for _, term := range splitTerms(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		field, op, values, err := parseTerm(term)
+		if err != nil {
+			return Selector{}, err
+		}
+
+		// Positive constraints may be given once per field; their
+		// combination would otherwise have to be intersected.
+		if op != "!=" && op != "notin" {
+			key := field
+			if op == "^=" {
+				key += op
+			}
+			if seen[key] {
+				return Selector{}, fmt.Errorf("field %q is matched more than once", field)
+			}
+			seen[key] = true
+		}
+
+		switch field {
+		case "status":
+			statuses, err := parseStatuses(values)
+			if err != nil {
+				return Selector{}, err
+			}
+			switch op {
+			case "=", "==", "in":
+				sel.Statuses = statuses
+			case "!=", "notin":
+				sel.ExcludedStatuses = append(sel.ExcludedStatuses, statuses...)
+			default:
+				return Selector{}, fmt.Errorf("operator %q is not supported for status", op)
+			}
+		case "id":
+			if op != "=" && op != "==" && op != "in" {
+				return Selector{}, fmt.Errorf("operator %q is not supported for id", op)
+			}
+			for _, v := range values {
+				id, err := uuid.Parse(v)
+				if err != nil {
+					return Selector{}, fmt.Errorf("invalid id %q: %w", v, err)
+				}
+				sel.IDs = append(sel.IDs, id)
+			}
+		case "static_url":
+			switch op {
+			case "=", "==":
+				sel.URL = values[0]
+			case "^=":
+				sel.URLPrefix = values[0]
+			default:
+				return Selector{}, fmt.Errorf("operator %q is not supported for static_url", op)
+			}
+		case "owner.team":
+			switch op {
+			case "=", "==", "in":
+				sel.OwnerTeams = values
+			case "!=", "notin":
+				sel.ExcludedOwnerTeams = append(sel.ExcludedOwnerTeams, values...)
+			default:
+				return Selector{}, fmt.Errorf("operator %q is not supported for owner.team", op)
+			}
+		default:
+			return Selector{}, fmt.Errorf("unknown field %q, expected one of status, id, static_url, owner.team", field)
+		}
+	}
+	return sel, nil
+}
+
+// Empty reports whether the selector matches every probe.
+func (s Selector) Empty() bool {
+	return len(s.Statuses) == 0 && len(s.ExcludedStatuses) == 0 && len(s.IDs) == 0 && s.URL == "" && s.URLPrefix == "" &&
+		len(s.OwnerTeams) == 0 && len(s.ExcludedOwnerTeams) == 0
+}
+
+// Matches reports whether the probe satisfies every term of the selector.
+func (s Selector) Matches(probe v1.ProbeObject) bool {
+	if len(s.Statuses) > 0 && !slices.Contains(s.Statuses, probe.Status) {
+		return false
+	}
+	if slices.Contains(s.ExcludedStatuses, probe.Status) {
+		return false
+	}
+	if len(s.IDs) > 0 && !slices.Contains(s.IDs, probe.Id) {
+		return false
+	}
+	if s.URL != "" && probe.StaticUrl != s.URL {
+		return false
+	}
+	var team string
+	if probe.Owner != nil && probe.Owner.Team != nil {
+		team = *probe.Owner.Team
+	}
+	if len(s.OwnerTeams) > 0 && (team == "" || !slices.Contains(s.OwnerTeams, team)) {
+		return false
+	}
+	if team != "" && slices.Contains(s.ExcludedOwnerTeams, team) {
+		return false
+	}
+	return strings.HasPrefix(probe.StaticUrl, s.URLPrefix)
+}
+
+// splitTerms splits on commas outside parentheses.
+func splitTerms(selector string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+// parseTerm splits a term into its field, operator and values.
+func parseTerm(term string) (field, op string, values []string, err error) {
+	// The operator is the first of "=", "!" or "^"; anything after it,
+	// including parentheses, belongs to the value.
+	opAt := strings.IndexAny(term, "=!^")
+
+	// Set-based terms: "<field> in (<v>,...)" and "<field> notin (<v>,...)".
+	if open := strings.IndexByte(term, '('); open >= 0 && (opAt < 0 || open < opAt) {
+		head := strings.Fields(term[:open])
+		if len(head) != 2 || (head[1] != "in" && head[1] != "notin") || !strings.HasSuffix(term, ")") {
+			return "", "", nil, fmt.Errorf("invalid term %q, expected <field> in (<value>,...)", term)
+		}
+		for v := range strings.SplitSeq(term[open+1:len(term)-1], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			return "", "", nil, fmt.Errorf("invalid term %q, expected at least one value", term)
+		}
+		return head[0], head[1], values, nil
+	}
+
+	// Comparison terms; two-character operators are tried first so "==" is
+	// not read as "=" followed by "=value".
+	if opAt > 0 {
+		for _, op := range []string{"==", "!=", "^=", "="} {
+			if strings.HasPrefix(term[opAt:], op) {
+				field = strings.TrimSpace(term[:opAt])
+				value := strings.TrimSpace(term[opAt+len(op):])
+				if value == "" {
+					return "", "", nil, fmt.Errorf("invalid term %q, expected a value after %q", term, op)
+				}
+				return field, op, []string{value}, nil
+			}
+		}
+	}
+	return "", "", nil, fmt.Errorf("invalid term %q, expected <field><operator><value>", term)
+}
+
+func parseStatuses(values []string) ([]v1.StatusSchema, error) {
+	statuses := make([]v1.StatusSchema, 0, len(values))
+	for _, v := range values {
+		status := v1.StatusSchema(v)
+		switch status {
+		case v1.Pending, v1.Active, v1.Failed, v1.Terminating, v1.Deleted:
+		default:
+			return nil, fmt.Errorf("invalid status %q, expected one of pending, active, failed, terminating, deleted", v)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,129 @@
+package fieldselector
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	id1 := uuid.MustParse("d290f1ee-6c54-4b01-90e6-d701748f0851")
+	id2 := uuid.MustParse("0f8fad5b-d9cb-469f-a165-70867728950e")
+
+	testCases := []struct {
+		name      string
+		selector  string
+		expected  Selector
+		expectErr string
+	}{
+		{name: "empty", selector: ""},
+		{name: "status equality", selector: "status==active", expected: Selector{Statuses: []v1.StatusSchema{v1.Active}}},
+		{name: "status set", selector: "status in (active, pending)", expected: Selector{Statuses: []v1.StatusSchema{v1.Active, v1.Pending}}},
+		{
+			name:     "status exclusions accumulate",
+			selector: "status!=failed,status notin (deleted,terminating)",
+			expected: Selector{ExcludedStatuses: []v1.StatusSchema{v1.Failed, v1.Deleted, v1.Terminating}},
+		},
+		{name: "id set", selector: "id in (" + id1.String() + "," + id2.String() + ")", expected: Selector{IDs: []uuid.UUID{id1, id2}}},
+		{
+			name:     "url prefix with operators and parentheses in the value",
+			selector: "static_url^=https://example.com/a==b/(x), status=active",
+			expected: Selector{URLPrefix: "https://example.com/a==b/(x)", Statuses: []v1.StatusSchema{v1.Active}},
+		},
+		{name: "exact url", selector: "static_url=https://example.com", expected: Selector{URL: "https://example.com"}},
+		{name: "exact url and prefix", selector: "static_url=https://example.com/a,static_url^=https://", expected: Selector{URL: "https://example.com/a", URLPrefix: "https://"}},
+		{name: "owner team set", selector: "owner.team in (sre, payments)", expected: Selector{OwnerTeams: []string{"sre", "payments"}}},
+		{name: "owner team exclusion", selector: "owner.team!=sre", expected: Selector{ExcludedOwnerTeams: []string{"sre"}}},
+		{name: "unsupported owner team operator", selector: "owner.team^=s", expectErr: `operator "^=" is not supported for owner.team`},
+		{name: "unknown field", selector: "name=probe", expectErr: `unknown field "name"`},
+		{name: "unknown status", selector: "status=running", expectErr: `invalid status "running"`},
+		{name: "invalid id", selector: "id=probe-1", expectErr: `invalid id "probe-1"`},
+		{name: "unsupported operator", selector: "id!=" + id1.String(), expectErr: `operator "!=" is not supported for id`},
+		{name: "missing value", selector: "status=", expectErr: "expected a value"},
+		{name: "missing operator", selector: "status", expectErr: "expected <field><operator><value>"},
+		{name: "empty set", selector: "status in ()", expectErr: "at least one value"},
+		{name: "unclosed set", selector: "status in (active", expectErr: "expected <field> in"},
+		{name: "repeated field", selector: "status=active,status=pending", expectErr: `field "status" is matched more than once`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sel, err := Parse(tc.selector)
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sel)
+			assert.Equal(t, tc.selector == "", sel.Empty())
+		})
+	}
+}
+
+func TestSelector_Matches(t *testing.T) {
+	id := uuid.New()
+	probe := v1.ProbeObject{Id: id, StaticUrl: "https://api.example.com/health", Status: v1.Active, Owner: &v1.OwnerSchema{Team: new("sre")}}
+
+	testCases := []struct {
+		selector string
+		matches  bool
+	}{
+		{"", true},
+		{"status=active", true},
+		{"status in (pending,failed)", false},
+		{"status!=active", false},
+		{"id=" + id.String(), true},
+		{"id=" + uuid.NewString(), false},
+		{"static_url^=https://api.", true},
+		{"static_url^=http://", false},
+		{"static_url=https://api.example.com/health", true},
+		{"static_url=https://api.example.com", false},
+		{"status=active,static_url^=https://other.", false},
+		{"owner.team=sre", true},
+		{"owner.team in (payments,sre)", true},
+		{"owner.team notin (sre)", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.selector, func(t *testing.T) {
+			sel, err := Parse(tc.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matches, sel.Matches(probe))
+		})
+	}
+
+	t.Run("probes without an owner team", func(t *testing.T) {
+		unowned := v1.ProbeObject{Id: id, StaticUrl: "https://api.example.com/health", Status: v1.Active}
+		for selector, matches := range map[string]bool{"owner.team=sre": false, "owner.team!=sre": true} {
+			sel, err := Parse(selector)
+			require.NoError(t, err)
+			assert.Equal(t, matches, sel.Matches(unowned), selector)
+		}
+	})
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"status==active",
+		"status in (active,pending),id=d290f1ee-6c54-4b01-90e6-d701748f0851",
+		"static_url^=https://example.com/(a,b)",
+		"status notin (",
+		"=)",
+		",,,",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, selector string) {
+		sel, err := Parse(selector)
+		if err != nil {
+			return
+		}
+		// Whatever parses must be usable without panicking.
+		sel.Matches(v1.ProbeObject{})
+	})
+}
@@ -0,0 +1,303 @@
+// Package grpcapi serves the probe operations of the API over gRPC. Each
+// call is handled as the REST request it mirrors, by the same handler and
+// middleware as REST requests, so both protocols validate, limit, audit and
+// store probes alike.
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1/probespb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server implements probespb.ProbesServer on top of the REST API handler.
+type Server struct {
+	probespb.UnimplementedProbesServer
+	handler http.Handler
+	// waitDone, once closed, ends Watch calls.
+	waitDone <-chan struct{}
+}
+
+// NewServer returns a Server handling calls with handler, the REST API with
+// its middleware. Closing waitDone ends Watch calls, as it ends REST requests
+// waiting for a change.
+func NewServer(handler http.Handler, waitDone <-chan struct{}) *Server {
+	return &Server{handler: handler, waitDone: waitDone}
+}
+
+// NewGRPCServer returns a gRPC server serving s, with interceptors recording
+// the gRPC metrics.
+func NewGRPCServer(s *Server, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			resp, err := handler(ctx, req)
+			metrics.RecordGRPCRequest(path.Base(info.FullMethod), status.Code(err).String())
+			return resp, err
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := handler(srv, stream)
+			metrics.RecordGRPCRequest(path.Base(info.FullMethod), status.Code(err).String())
+			return err
+		}),
+	)
+	g := grpc.NewServer(opts...)
+	probespb.RegisterProbesServer(g, s)
+	return g
+}
+
+var (
+	marshal   = protojson.MarshalOptions{UseProtoNames: true}
+	unmarshal = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// call handles a call as the REST request method path?query, with body as
+// its JSON body if not nil and header on top of the call's metadata, and
+// decodes the response body into out if not nil. Statuses of 300 and above
+// are returned as the matching gRPC error.
+func (s *Server) call(ctx context.Context, method, target string, query url.Values, body proto.Message, header http.Header, out proto.Message) error {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		data, err := marshal.Marshal(body)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to encode the request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	r, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	r.RequestURI = target
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		if strings.HasPrefix(key, ":") || strings.HasPrefix(key, "grpc-") || strings.HasSuffix(key, "-bin") ||
+			key == "content-type" || key == "te" {
+			continue
+		}
+		for _, value := range values {
+			r.Header.Add(key, value)
+		}
+	}
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	if body != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	// The caller's address and client certificate identify the tenant and
+	// actor as they do for REST requests.
+	if p, ok := peer.FromContext(ctx); ok {
+		r.RemoteAddr = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			r.TLS = &info.State
+		}
+	}
+
+	w := &responseRecorder{header: http.Header{}}
+	s.handler.ServeHTTP(w, r)
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	if w.code >= http.StatusMultipleChoices {
+		return status.Error(codeFor(method, w.code), errorMessage(w.code, w.body.Bytes()))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := unmarshal.Unmarshal(w.body.Bytes(), out); err != nil {
+		return status.Errorf(codes.Internal, "failed to decode the response: %v", err)
+	}
+	return nil
+}
+
+// codeFor returns the gRPC code of a REST status.
+func codeFor(method string, code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		// A create conflicts with an existing probe; other writes with a
+		// concurrent change or the probe's status.
+		if method == http.MethodPost {
+			return codes.AlreadyExists
+		}
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if code >= http.StatusInternalServerError {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// errorMessage returns the message of an error or warning response body, or
+// the body itself if it has none.
+func errorMessage(code int, body []byte) string {
+	var response struct {
+		Error   *struct{ Message string } `json:"error"`
+		Warning *struct{ Message string } `json:"warning"`
+	}
+	if json.Unmarshal(body, &response) == nil {
+		switch {
+		case response.Error != nil && response.Error.Message != "":
+			return response.Error.Message
+		case response.Warning != nil && response.Warning.Message != "":
+			return response.Warning.Message
+		}
+	}
+	if message := strings.TrimSpace(string(body)); message != "" {
+		return message
+	}
+	return http.StatusText(code)
+}
+
+// responseRecorder keeps the response of a REST request.
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (w *responseRecorder) Header() http.Header { return w.header }
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(data)
+}
+
+// ListProbes implements probespb.ProbesServer.
+func (s *Server) ListProbes(ctx context.Context, req *probespb.ListProbesRequest) (*probespb.ListProbesResponse, error) {
+	query := selectorQuery(req.LabelSelector, req.FieldSelector)
+	if req.MinGeneration != 0 {
+		query.Set("min_generation", strconv.FormatInt(req.MinGeneration, 10))
+	}
+	if req.Limit != 0 {
+		query.Set("limit", strconv.Itoa(int(req.Limit)))
+	}
+	for key, value := range map[string]string{"page_token": req.PageToken, "sort_by": req.SortBy, "order": req.Order} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	resp := &probespb.ListProbesResponse{}
+	if err := s.call(ctx, http.MethodGet, "/probes", query, nil, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetProbe implements probespb.ProbesServer.
+func (s *Server) GetProbe(ctx context.Context, req *probespb.GetProbeRequest) (*probespb.Probe, error) {
+	probe := &probespb.Probe{}
+	if err := s.call(ctx, http.MethodGet, probePath(req.Id), nil, nil, nil, probe); err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// CreateProbe implements probespb.ProbesServer.
+func (s *Server) CreateProbe(ctx context.Context, req *probespb.CreateProbeRequest) (*probespb.Probe, error) {
+	query := url.Values{}
+	if req.DryRun {
+		query.Set("dry_run", "true")
+	}
+	if req.Validate != "" {
+		query.Set("validate", req.Validate)
+	}
+	body := proto.CloneOf(req)
+	body.DryRun, body.Validate = false, ""
+	probe := &probespb.Probe{}
+	if err := s.call(ctx, http.MethodPost, "/probes", query, body, nil, probe); err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// UpdateProbe implements probespb.ProbesServer.
+func (s *Server) UpdateProbe(ctx context.Context, req *probespb.UpdateProbeRequest) (*probespb.Probe, error) {
+	body := proto.CloneOf(req)
+	body.Id, body.ResourceVersion, body.DryRun = "", "", false
+	probe := &probespb.Probe{}
+	if err := s.call(ctx, http.MethodPatch, probePath(req.Id), dryRunQuery(req.DryRun), body, ifMatch(req.ResourceVersion), probe); err != nil {
+		return nil, err
+	}
+	return probe, nil
+}
+
+// DeleteProbe implements probespb.ProbesServer.
+func (s *Server) DeleteProbe(ctx context.Context, req *probespb.DeleteProbeRequest) (*probespb.DeleteProbeResponse, error) {
+	if err := s.call(ctx, http.MethodDelete, probePath(req.Id), dryRunQuery(req.DryRun), nil, ifMatch(req.ResourceVersion), nil); err != nil {
+		return nil, err
+	}
+	return &probespb.DeleteProbeResponse{}, nil
+}
+
+func probePath(id string) string {
+	return "/probes/" + url.PathEscape(id)
+}
+
+func selectorQuery(labelSelector, fieldSelector string) url.Values {
+	query := url.Values{}
+	if labelSelector != "" {
+		query.Set("label_selector", labelSelector)
+	}
+	if fieldSelector != "" {
+		query.Set("field_selector", fieldSelector)
+	}
+	return query
+}
+
+func dryRunQuery(dryRun bool) url.Values {
+	if !dryRun {
+		return nil
+	}
+	return url.Values{"dry_run": {"true"}}
+}
+
+func ifMatch(resourceVersion string) http.Header {
+	if resourceVersion == "" {
+		return nil
+	}
+	return http.Header{"If-Match": {`"` + resourceVersion + `"`}}
+}
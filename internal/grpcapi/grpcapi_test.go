@@ -0,0 +1,72 @@
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1/probespb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCall(t *testing.T) {
+	var got *http.Request
+	var gotBody string
+	respond := func(code int, body string) *Server {
+		return NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			data, _ := io.ReadAll(r.Body)
+			gotBody = string(data)
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(body))
+		}), nil)
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer token", "x-tenant", "team-a", "grpc-timeout", "1S", "content-type", "application/grpc"))
+
+	t.Run("requests mirror the call", func(t *testing.T) {
+		server := respond(http.StatusOK, `{"id":"probe-1","static_url":"https://example.com","generation":2,"unknown":true}`)
+		interval := "30s"
+		probe, err := server.UpdateProbe(ctx, &probespb.UpdateProbeRequest{Id: "probe-1", ResourceVersion: "7", DryRun: true, Interval: &interval})
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com", probe.StaticUrl)
+		assert.Equal(t, int64(2), probe.Generation)
+
+		assert.Equal(t, http.MethodPatch, got.Method)
+		assert.Equal(t, "/probes/probe-1?dry_run=true", got.RequestURI)
+		assert.JSONEq(t, `{"interval":"30s"}`, gotBody, "parameters are not sent in the body")
+		assert.Equal(t, `"7"`, got.Header.Get("If-Match"))
+		assert.Equal(t, "Bearer token", got.Header.Get("Authorization"))
+		assert.Equal(t, "team-a", got.Header.Get("X-Tenant"))
+		assert.Empty(t, got.Header.Get("Grpc-Timeout"))
+		assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	})
+
+	t.Run("statuses map to codes", func(t *testing.T) {
+		for _, tc := range []struct {
+			code    int
+			body    string
+			want    codes.Code
+			message string
+		}{
+			{http.StatusBadRequest, `{"error":{"message":"invalid labels"}}`, codes.InvalidArgument, "invalid labels"},
+			{http.StatusNotFound, `{"warning":{"message":"probe with ID probe-1 not found"}}`, codes.NotFound, "probe with ID probe-1 not found"},
+			{http.StatusConflict, `{"error":{"message":"changed"}}`, codes.Aborted, "changed"},
+			{http.StatusPreconditionFailed, `{"error":{"message":"stale"}}`, codes.FailedPrecondition, "stale"},
+			{http.StatusServiceUnavailable, "timed out\n", codes.Unavailable, "timed out"},
+			{http.StatusInternalServerError, "", codes.Internal, "Internal Server Error"},
+		} {
+			_, err := respond(tc.code, tc.body).DeleteProbe(ctx, &probespb.DeleteProbeRequest{Id: "probe-1"})
+			assert.Equal(t, tc.want, status.Code(err), tc.body)
+			assert.Equal(t, tc.message, status.Convert(err).Message())
+		}
+
+		_, err := respond(http.StatusConflict, `{"error":{"message":"exists"}}`).CreateProbe(ctx, &probespb.CreateProbeRequest{StaticUrl: "https://example.com"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err), "a create conflicts with an existing probe")
+	})
+}
@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/api"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1/probespb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Watch implements probespb.ProbesServer. It lists the matching probes, then
+// waits for them to change as GET /probes?wait_for_change=true does, and
+// sends the differences between consecutive listings.
+func (s *Server) Watch(req *probespb.WatchRequest, stream grpc.ServerStreamingServer[probespb.WatchEvent]) error {
+	ctx := stream.Context()
+	seen := map[string]*probespb.Probe{}
+	var version string
+	for {
+		query := selectorQuery(req.LabelSelector, req.FieldSelector)
+		if version != "" {
+			query.Set("wait_for_change", "true")
+			query.Set("timeout", api.MaxWaitTimeout.String())
+			query.Set("version", version)
+		}
+		list := &probespb.ListProbesResponse{}
+		if err := s.call(ctx, http.MethodGet, "/probes", query, nil, nil, list); err != nil {
+			return err
+		}
+		version = list.Version
+
+		current := make(map[string]*probespb.Probe, len(list.Probes))
+		for _, probe := range list.Probes {
+			current[probe.Id] = probe
+			eventType := probespb.WatchEvent_MODIFIED
+			if previous, ok := seen[probe.Id]; !ok {
+				eventType = probespb.WatchEvent_ADDED
+			} else if proto.Equal(previous, probe) {
+				continue
+			}
+			if err := stream.Send(&probespb.WatchEvent{Type: eventType, Probe: probe, Version: version}); err != nil {
+				return err
+			}
+		}
+		for _, id := range slices.Sorted(maps.Keys(seen)) {
+			if _, ok := current[id]; ok {
+				continue
+			}
+			if err := stream.Send(&probespb.WatchEvent{Type: probespb.WatchEvent_DELETED, Probe: seen[id], Version: version}); err != nil {
+				return err
+			}
+		}
+		seen = current
+
+		select {
+		case <-s.waitDone:
+			return nil
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		default:
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package health checks the backends the API depends on for /readyz.
+//
+// Readiness probes hit /readyz every few seconds on every replica, so the
+// backends are not checked on every request: a Prober caches each result and
+// checks a backend again only once its interval has passed. A failing backend
+// is checked less and less often, doubling the interval up to a maximum, so an
+// outage is not made worse by the probes waiting for it to end.
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultInterval is how long a result is cached while a backend is
+	// healthy.
+	DefaultInterval = 10 * time.Second
+	// DefaultMaxInterval is the longest a failing backend goes unchecked.
+	DefaultMaxInterval = 2 * time.Minute
+	// DefaultLatencyBudget is how long a check may take when its Budget is
+	// zero.
+	DefaultLatencyBudget = 2 * time.Second
+
+	// checkTimeout bounds a single check, so a hanging backend is reported as
+	// failing instead of stalling readiness probes.
+	checkTimeout = 5 * time.Second
+)
+
+// Check is a named backend check.
+type Check struct {
+	// Name identifies the backend in diagnostics, e.g. "storage".
+	Name string
+	// Func returns nil while the backend is healthy.
+	Func func(ctx context.Context) error
+	// Budget is how long the check may take: a backend answering slower is
+	// reported as failing, as requests would likely time out on it too.
+	// Zero selects DefaultLatencyBudget.
+	Budget time.Duration
+}
+
+// Result is the last outcome of a check.
+type Result struct {
+	Name string
+	// Err is nil while the backend is healthy.
+	Err error
+	// CheckedAt is when the check last ran.
+	CheckedAt time.Time
+	// Latency is how long the check took.
+	Latency time.Duration
+	// Failures counts the checks failed in a row.
+	Failures int
+}
+
+// Prober runs checks and caches their results.
+type Prober struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	checks      []*check
+	now         func() time.Time
+}
+
+type check struct {
+	Check
+	// run serializes runs of the check, so concurrent readiness probes wait
+	// for one run instead of each starting their own.
+	run sync.Mutex
+
+	mu     sync.Mutex
+	result Result
+	// checked is false until the check first ran.
+	checked bool
+}
+
+// NewProber returns a Prober checking each backend at most once per interval
+// while healthy, and backing off up to maxInterval while failing. Zero values
+// select DefaultInterval and DefaultMaxInterval; maxInterval is raised to
+// interval if shorter.
+func NewProber(interval, maxInterval time.Duration, checks ...Check) *Prober {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = DefaultMaxInterval
+	}
+	p := &Prober{interval: interval, maxInterval: max(interval, maxInterval), now: time.Now}
+	for _, c := range checks {
+		p.checks = append(p.checks, &check{Check: c})
+	}
+	return p
+}
+
+// Check returns the result of every check, in the order they were given,
+// running those that are due. A nil Prober has no checks.
+func (p *Prober) Check(ctx context.Context) []Result {
+	if p == nil {
+		return nil
+	}
+	results := make([]Result, len(p.checks))
+	var wg sync.WaitGroup
+	for i, c := range p.checks {
+		wg.Go(func() { results[i] = p.check(ctx, c) })
+	}
+	wg.Wait()
+	return results
+}
+
+func (p *Prober) check(ctx context.Context, c *check) Result {
+	c.run.Lock()
+	defer c.run.Unlock()
+
+	if result, fresh := p.cached(c); fresh {
+		return result
+	}
+
+	// The result is shared with other requests, so it must not depend on
+	// whether this one is cancelled.
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), checkTimeout)
+	defer cancel()
+	start := time.Now()
+	err := c.Func(checkCtx)
+	latency := time.Since(start)
+	budget := c.Budget
+	if budget <= 0 {
+		budget = DefaultLatencyBudget
+	}
+	if err == nil && latency > budget {
+		err = fmt.Errorf("took %s, over the %s latency budget", latency.Round(time.Millisecond), budget)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	failures := 0
+	if err != nil {
+		failures = c.result.Failures + 1
+		slog.WarnContext(ctx, "Backend health check failed", "backend", c.Name, "failures", failures, "next_check", p.next(failures), "error", err)
+	} else if c.result.Err != nil {
+		slog.InfoContext(ctx, "Backend health check recovered", "backend", c.Name)
+	}
+	c.result = Result{Name: c.Name, Err: err, CheckedAt: p.now(), Latency: latency, Failures: failures}
+	c.checked = true
+	return c.result
+}
+
+// cached returns the last result of c, and whether it is recent enough to be
+// returned without checking again.
+func (p *Prober) cached(c *check) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked {
+		return Result{}, false
+	}
+	return c.result, p.now().Sub(c.result.CheckedAt) < p.next(c.result.Failures)
+}
+
+// next returns how long a result is cached after the given number of failures
+// in a row: the interval, doubled for each failure past the first.
+func (p *Prober) next(failures int) time.Duration {
+	next := p.interval
+	for i := 1; i < failures && next < p.maxInterval; i++ {
+		next *= 2
+	}
+	return min(next, p.maxInterval)
+}
@@ -0,0 +1,127 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock lets tests move the prober's time forward.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestProber_CachesResults(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+	clock := &fakeClock{now: time.Now()}
+	p := NewProber(10*time.Second, time.Minute, Check{Name: "storage", Func: func(context.Context) error {
+		calls.Add(1)
+		if failing.Load() {
+			return errors.New("unreachable")
+		}
+		return nil
+	}})
+	p.now = clock.Now
+
+	results := p.Check(context.Background())
+	require.Len(t, results, 1)
+	assert.Equal(t, "storage", results[0].Name)
+	assert.NoError(t, results[0].Err)
+	assert.EqualValues(t, 1, calls.Load())
+
+	p.Check(context.Background())
+	assert.EqualValues(t, 1, calls.Load(), "healthy results are cached for the interval")
+
+	clock.Advance(10 * time.Second)
+	failing.Store(true)
+	results = p.Check(context.Background())
+	assert.EqualValues(t, 2, calls.Load())
+	assert.EqualError(t, results[0].Err, "unreachable")
+	assert.Equal(t, 1, results[0].Failures)
+
+	// Failures back off: 10s, then 20s, 40s and 60s at most.
+	for _, wait := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute} {
+		before := calls.Load()
+		clock.Advance(wait - time.Second)
+		p.Check(context.Background())
+		assert.Equal(t, before, calls.Load(), "checked before %s", wait)
+		clock.Advance(time.Second)
+		p.Check(context.Background())
+		assert.Equal(t, before+1, calls.Load(), "not checked after %s", wait)
+	}
+
+	failing.Store(false)
+	clock.Advance(time.Minute)
+	results = p.Check(context.Background())
+	assert.NoError(t, results[0].Err)
+	assert.Zero(t, results[0].Failures, "recovery resets the backoff")
+}
+
+func TestProber_ConcurrentChecks(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	p := NewProber(time.Minute, 0, Check{Name: "kubernetes", Func: func(context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	}})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() { p.Check(context.Background()) })
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, 1, calls.Load(), "concurrent probes share one check")
+}
+
+func TestProber_Order(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	p := NewProber(0, 0, Check{Name: "storage", Func: ok}, Check{Name: "kubernetes", Func: ok})
+	results := p.Check(context.Background())
+	require.Len(t, results, 2)
+	assert.Equal(t, "storage", results[0].Name)
+	assert.Equal(t, "kubernetes", results[1].Name)
+
+	var nilProber *Prober
+	assert.Empty(t, nilProber.Check(context.Background()))
+}
+
+func TestProber_LatencyBudget(t *testing.T) {
+	slow := func(context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+	p := NewProber(time.Minute, 0,
+		Check{Name: "storage", Func: slow, Budget: time.Millisecond},
+		Check{Name: "kubernetes", Func: slow, Budget: time.Second},
+	)
+	results := p.Check(context.Background())
+	require.Len(t, results, 2)
+	require.Error(t, results[0].Err, "slow backends fail")
+	assert.Contains(t, results[0].Err.Error(), "over the 1ms latency budget")
+	assert.Equal(t, 1, results[0].Failures)
+	assert.NoError(t, results[1].Err)
+	assert.GreaterOrEqual(t, results[1].Latency, 20*time.Millisecond)
+}
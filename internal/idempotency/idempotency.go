@@ -0,0 +1,354 @@
+// Package idempotency makes probe creation safe to retry. A POST /probes, or
+// POST /api/v2/probes, carrying an Idempotency-Key header is run once;
+// retries with the same key get the recorded response replayed instead of a
+// 409 for the probe the first attempt created.
+//
+// Keys are kept per tenant and caller for a configurable time. They are kept
+// in the probe store when it keeps records, so retries are recognized by
+// every replica using the store, and in memory otherwise, where each replica
+// only recognizes the retries of the requests it handled.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apiv2"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+)
+
+const (
+	// Header is the request header carrying the idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses replayed for a retried request.
+	ReplayedHeader = "Idempotent-Replayed"
+	// MaxKeyLength is the longest key accepted; longer ones are passed on to
+	// request validation, which rejects them.
+	MaxKeyLength = 255
+	// DefaultTTL is how long keys are kept when no TTL is configured.
+	DefaultTTL = 24 * time.Hour
+	// DefaultMaxKeys is how many keys are kept at most; the oldest are
+	// forgotten first.
+	DefaultMaxKeys = 10000
+)
+
+// inProgressBody is the error returned for a retry that arrives while the
+// first attempt is still being handled.
+var inProgressBody = fmt.Sprintf(`{"error":{"message":"a request with this Idempotency-Key is still in progress","retry_after_seconds":%d}}`,
+	*retryafter.Seconds(http.StatusConflict))
+
+const (
+	// mismatchBody is the error returned when a key is reused for a
+	// different request.
+	mismatchBody = `{"error":{"message":"the Idempotency-Key was already used for a different request"}}`
+	// unreadableBody is the error returned when the request body cannot be
+	// read to be fingerprinted.
+	unreadableBody = `{"error":{"message":"failed to read request body"}}`
+)
+
+// Cache records the responses of requests made with an idempotency key.
+type Cache struct {
+	// Records keeps the keys when set, so that every replica using the
+	// store recognizes retries. Otherwise they are kept in memory, at most
+	// DefaultMaxKeys of them.
+	Records probestore.RecordStore
+
+	ttl     time.Duration
+	maxKeys int
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[entryKey]*entry
+	// order lists the entries oldest first, to expire and evict them. Every
+	// entry lives for the same TTL, so this is also the order they expire in.
+	// Entries forgotten, or replaced after being forgotten, stay listed until
+	// they come up and are skipped.
+	order []queued
+}
+
+// entryKey scopes keys to a tenant and a caller, so neither tenants nor the
+// callers of one tenant can replay each other's responses.
+type entryKey struct {
+	tenant string
+	caller string
+	key    string
+}
+
+// queued is an entry listed in the order, with the key it was recorded
+// under.
+type queued struct {
+	id entryKey
+	e  *entry
+}
+
+type entry struct {
+	// fingerprint identifies the request the key was first used for.
+	fingerprint [sha256.Size]byte
+	created     time.Time
+	// response is nil while the first attempt is being handled.
+	response *response
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// prior is what begin found recorded for a key already used.
+type prior struct {
+	// mismatch is set when the key was used for a different request.
+	mismatch bool
+	// response is nil while the first attempt is being handled.
+	response *response
+}
+
+// claim is a key recorded in progress for a request, which records its
+// response under it once handled, or forgets it so it can be retried.
+type claim interface {
+	finish(ctx context.Context, resp *response)
+	forget(ctx context.Context)
+}
+
+// NewCache returns a Cache keeping keys for ttl; zero selects DefaultTTL.
+func NewCache(ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{
+		ttl:     ttl,
+		maxKeys: DefaultMaxKeys,
+		now:     time.Now,
+		entries: make(map[entryKey]*entry),
+	}
+}
+
+// Middleware runs probe creations with an Idempotency-Key once per key: a
+// retry of a finished request gets its response again, a retry of one still
+// being handled gets 409, and a key reused for a different request gets 422.
+// Server errors and 429s are not recorded, so those requests can be retried
+// for real. Other requests pass through untouched.
+func (c *Cache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(Header)
+		if r.Method != http.MethodPost || !isProbeCreation(r.URL.Path) || key == "" || len(key) > MaxKeyLength {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, unreadableBody)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := sha256.Sum256(append([]byte(r.URL.RawQuery+"\n"), body...))
+
+		id := entryKey{tenant: limits.TenantFromContext(r.Context()), caller: caller(r), key: key}
+		cl, found, err := c.begin(r.Context(), id, fingerprint)
+		switch {
+		case err != nil:
+			// The probe the first attempt created still makes a retry
+			// fail with 409, as without a key.
+			slog.WarnContext(r.Context(), "Failed to record idempotency key, handling the request without it", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		case found != nil && found.mismatch:
+			writeError(w, http.StatusUnprocessableEntity, mismatchBody)
+			return
+		case found != nil && found.response == nil:
+			writeError(w, http.StatusConflict, inProgressBody)
+			return
+		case found != nil:
+			found.response.replay(w)
+			return
+		}
+
+		// The key is settled even if the client goes away meanwhile.
+		ctx := context.WithoutCancel(r.Context())
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			// A panicking handler must not leave the key in progress.
+			if !completed {
+				cl.forget(ctx)
+			}
+		}()
+		next.ServeHTTP(rec, r)
+		completed = true
+		if rec.status >= http.StatusInternalServerError || rec.status == http.StatusTooManyRequests {
+			cl.forget(ctx)
+			return
+		}
+		cl.finish(ctx, &response{status: rec.status, header: rec.header, body: rec.body.Bytes()})
+	})
+}
+
+// isProbeCreation reports whether path is that of a probe creation, in
+// either API version.
+func isProbeCreation(path string) bool {
+	return path == "/probes" || path == apiv2.Prefix+"/probes"
+}
+
+// caller identifies the credential a request was made with: the API key, the
+// agent, or else a hash of the bearer token or the client certificate's
+// common name. It is empty for anonymous requests.
+func caller(r *http.Request) string {
+	if key, ok := apikeys.FromContext(r.Context()); ok {
+		return "apikey:" + key.ID
+	}
+	if agentID := agentauth.AgentFromContext(r.Context()); agentID != "" {
+		return "agent:" + agentID
+	}
+	if token := agentauth.BearerToken(r); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "bearer:" + hex.EncodeToString(sum[:])
+	}
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		return "cert:" + r.TLS.VerifiedChains[0][0].Subject.CommonName
+	}
+	return ""
+}
+
+// Len returns the number of keys kept in memory; those kept in the store are
+// not counted.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// begin returns what is recorded for a key already used. Otherwise it
+// records the key in progress for the request and returns the claim on it.
+func (c *Cache) begin(ctx context.Context, id entryKey, fingerprint [sha256.Size]byte) (claim, *prior, error) {
+	if c.Records != nil {
+		return c.beginRecord(ctx, id, fingerprint)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.expire(now)
+	if e, ok := c.entries[id]; ok {
+		return nil, &prior{mismatch: e.fingerprint != fingerprint, response: e.response}, nil
+	}
+	e := &entry{fingerprint: fingerprint, created: now}
+	c.entries[id] = e
+	c.order = append(c.order, queued{id: id, e: e})
+	for len(c.entries) > c.maxKeys {
+		c.evictOldest()
+	}
+	return memoryClaim{c: c, id: id, e: e}, nil, nil
+}
+
+// memoryClaim is a claim on a key kept in memory.
+type memoryClaim struct {
+	c  *Cache
+	id entryKey
+	e  *entry
+}
+
+func (m memoryClaim) finish(_ context.Context, resp *response) {
+	m.c.mu.Lock()
+	defer m.c.mu.Unlock()
+	m.e.response = resp
+}
+
+func (m memoryClaim) forget(context.Context) {
+	m.c.forget(m.id, m.e)
+}
+
+// forget drops the key if it still belongs to e, so the request can be
+// retried.
+func (c *Cache) forget(id entryKey, e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[id] == e {
+		delete(c.entries, id)
+	}
+}
+
+// expire drops the keys older than the TTL. Entries no longer recorded are
+// skipped as they come up in the order.
+func (c *Cache) expire(now time.Time) {
+	for len(c.order) > 0 {
+		q := c.order[0]
+		live := c.entries[q.id] == q.e
+		if live && now.Sub(q.e.created) < c.ttl {
+			return
+		}
+		if live {
+			delete(c.entries, q.id)
+		}
+		c.order = c.order[1:]
+	}
+}
+
+// evictOldest drops the oldest entry still recorded. A key forgotten and
+// recorded again is listed twice; only its later listing drops it.
+func (c *Cache) evictOldest() {
+	for len(c.order) > 0 {
+		q := c.order[0]
+		c.order = c.order[1:]
+		if c.entries[q.id] == q.e {
+			delete(c.entries, q.id)
+			return
+		}
+	}
+}
+
+func (resp *response) replay(w http.ResponseWriter) {
+	maps.Copy(w.Header(), resp.header)
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
+
+func writeError(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+// recorder passes the response through while keeping a copy to replay.
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.status = code
+		rec.header = rec.Header().Clone()
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,299 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/apiv2"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// creator answers every request with 201 and a body numbering the calls.
+func creator(calls *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"call":%d}`, n)
+	})
+}
+
+func post(h http.Handler, key, body, tenant string) *httptest.ResponseRecorder {
+	return postAs(h, "/probes", key, body, tenant, "")
+}
+
+// postAs posts to path with the bearer token, if any.
+func postAs(h http.Handler, path, key, body, tenant, bearer string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(Header, key)
+	}
+	if bearer != "" {
+		r.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	r = r.WithContext(limits.WithTenant(r.Context(), tenant))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	h := NewCache(time.Hour).Middleware(creator(&calls))
+
+	first := post(h, "key-1", `{"static_url":"https://example.com"}`, "")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(ReplayedHeader))
+
+	retry := post(h, "key-1", `{"static_url":"https://example.com"}`, "")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, `{"call":1}`, retry.Body.String(), "the first response is replayed")
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, "true", retry.Header().Get(ReplayedHeader))
+	assert.EqualValues(t, 1, calls.Load())
+
+	t.Run("rejects a key reused for another request", func(t *testing.T) {
+		w := post(h, "key-1", `{"static_url":"https://other.example.com"}`, "")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.EqualValues(t, 1, calls.Load())
+	})
+
+	t.Run("scopes keys to the tenant", func(t *testing.T) {
+		w := post(h, "key-1", `{"static_url":"https://example.com"}`, "team-a")
+		assert.Equal(t, `{"call":2}`, w.Body.String())
+	})
+
+	t.Run("scopes keys to the caller", func(t *testing.T) {
+		w := postAs(h, "/probes", "key-2", `{}`, "team-a", "token-a")
+		assert.Equal(t, `{"call":3}`, w.Body.String())
+		w = postAs(h, "/probes", "key-2", `{}`, "team-a", "token-b")
+		assert.Equal(t, `{"call":4}`, w.Body.String(), "another caller of the tenant does not get the response")
+		w = postAs(h, "/probes", "key-2", `{}`, "team-a", "token-a")
+		assert.Equal(t, `{"call":3}`, w.Body.String())
+	})
+
+	t.Run("covers creations in version 2", func(t *testing.T) {
+		w := postAs(h, apiv2.Prefix+"/probes", "key-3", `{}`, "", "")
+		assert.Equal(t, `{"call":5}`, w.Body.String())
+		w = postAs(h, apiv2.Prefix+"/probes", "key-3", `{}`, "", "")
+		assert.Equal(t, `{"call":5}`, w.Body.String())
+		assert.Equal(t, "true", w.Header().Get(ReplayedHeader))
+	})
+
+	t.Run("passes requests without a key", func(t *testing.T) {
+		before := calls.Load()
+		post(h, "", `{}`, "")
+		post(h, "", `{}`, "")
+		assert.Equal(t, before+2, calls.Load())
+	})
+
+	t.Run("passes other requests", func(t *testing.T) {
+		before := calls.Load()
+		for range 2 {
+			r := httptest.NewRequest(http.MethodPatch, "/probes/x", strings.NewReader(`{}`))
+			r.Header.Set(Header, "key-1")
+			h.ServeHTTP(httptest.NewRecorder(), r)
+		}
+		assert.Equal(t, before+2, calls.Load())
+	})
+}
+
+func TestMiddleware_ServerErrorsAreNotRecorded(t *testing.T) {
+	var calls atomic.Int32
+	h := NewCache(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	assert.Equal(t, http.StatusInternalServerError, post(h, "key", `{}`, "").Code)
+	assert.Equal(t, http.StatusCreated, post(h, "key", `{}`, "").Code, "the retry runs again")
+	assert.Equal(t, http.StatusCreated, post(h, "key", `{}`, "").Code)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := NewCache(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan int)
+	go func() { done <- post(h, "key", `{}`, "").Code }()
+	<-started
+
+	w := post(h, "key", `{}`, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"error":{"message":"a request with this Idempotency-Key is still in progress","retry_after_seconds":5}}`, w.Body.String())
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, <-done)
+}
+
+func TestCache_Expiry(t *testing.T) {
+	var calls atomic.Int32
+	c := NewCache(time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	h := c.Middleware(creator(&calls))
+
+	post(h, "key", `{}`, "")
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, `{"call":1}`, post(h, "key", `{}`, "").Body.String())
+
+	now = now.Add(time.Second)
+	assert.Equal(t, `{"call":2}`, post(h, "key", `{}`, "").Body.String(), "expired keys run again")
+
+	t.Run("evicts the oldest keys past the maximum", func(t *testing.T) {
+		c.maxKeys = 2
+		post(h, "a", `{}`, "")
+		post(h, "b", `{}`, "")
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		assert.Len(t, c.entries, 2)
+		_, ok := c.entries[entryKey{key: "key"}]
+		assert.False(t, ok)
+	})
+}
+
+func TestCache_RetriedKeys(t *testing.T) {
+	// setup records "first", then "retried" after a failed attempt, with
+	// "other" recorded in between the two attempts, a second apart each.
+	setup := func(t *testing.T) (*Cache, http.Handler, *time.Time) {
+		var calls atomic.Int32
+		c := NewCache(time.Minute)
+		c.maxKeys = 3
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		post(h, "first", `{}`, "")
+		now = now.Add(time.Second)
+		require.Equal(t, http.StatusServiceUnavailable, post(h, "retried", `{}`, "").Code)
+		now = now.Add(time.Second)
+		post(h, "other", `{}`, "")
+		now = now.Add(time.Second)
+		require.Equal(t, http.StatusCreated, post(h, "retried", `{}`, "").Code)
+		return c, h, &now
+	}
+	recorded := func(c *Cache, key string) bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_, ok := c.entries[entryKey{key: key}]
+		return ok
+	}
+
+	t.Run("the oldest key is evicted", func(t *testing.T) {
+		c, h, _ := setup(t)
+		post(h, "a", `{}`, "")
+		assert.False(t, recorded(c, "first"))
+		post(h, "b", `{}`, "")
+		assert.False(t, recorded(c, "other"))
+		assert.Equal(t, "true", post(h, "retried", `{}`, "").Header().Get(ReplayedHeader),
+			"the listing of the failed attempt does not evict the retry")
+	})
+
+	t.Run("older keys expire", func(t *testing.T) {
+		c, h, now := setup(t)
+		*now = now.Add(time.Minute - time.Second)
+		post(h, "newest", `{}`, "")
+		assert.False(t, recorded(c, "first"))
+		assert.False(t, recorded(c, "other"), "the listing of the failed attempt does not hold back expiry")
+		assert.True(t, recorded(c, "retried"))
+	})
+}
+
+func TestMiddleware_PanicForgetsKey(t *testing.T) {
+	var calls atomic.Int32
+	h := NewCache(time.Hour).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			panic(http.ErrAbortHandler)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	require.Panics(t, func() { post(h, "key", `{}`, "") })
+	assert.Equal(t, http.StatusCreated, post(h, "key", `{}`, "").Code)
+}
+
+func TestCache_Records(t *testing.T) {
+	store := probestore.NewMemoryProbeStore()
+	now := time.Now()
+	newCache := func() *Cache {
+		c := NewCache(time.Hour)
+		c.Records = store
+		c.now = func() time.Time { return now }
+		return c
+	}
+	var calls atomic.Int32
+	first, second := newCache().Middleware(creator(&calls)), newCache().Middleware(creator(&calls))
+
+	assert.Equal(t, `{"call":1}`, post(first, "key", `{}`, "").Body.String())
+	retry := post(second, "key", `{}`, "")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, `{"call":1}`, retry.Body.String(), "another replica replays the response")
+	assert.Equal(t, "application/json", retry.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusUnprocessableEntity, post(second, "key", `{"other":true}`, "").Code)
+	assert.EqualValues(t, 1, calls.Load())
+
+	t.Run("keys in progress on another replica", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		slow := newCache().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		}))
+		done := make(chan int)
+		go func() { done <- post(slow, "slow", `{}`, "").Code }()
+		<-started
+		assert.Equal(t, http.StatusConflict, post(second, "slow", `{}`, "").Code)
+		close(release)
+		assert.Equal(t, http.StatusCreated, <-done)
+	})
+
+	t.Run("abandoned keys run again", func(t *testing.T) {
+		c := newCache()
+		// The replica stops after recording the key, before handling the
+		// request.
+		claimed, _, err := c.begin(context.Background(), entryKey{key: "abandoned"}, sha256.Sum256([]byte("\n")))
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.Equal(t, http.StatusConflict, post(second, "abandoned", ``, "").Code)
+		now = now.Add(abandonedAfter)
+		assert.Equal(t, http.StatusCreated, post(second, "abandoned", ``, "").Code)
+	})
+
+	t.Run("expired keys are pruned", func(t *testing.T) {
+		c := newCache()
+		records, err := store.ListRecords(context.Background(), recordKind)
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		now = now.Add(time.Hour)
+		post(first, "new", `{}`, "")
+		require.NoError(t, c.Prune(context.Background()))
+		records, err = store.ListRecords(context.Background(), recordKind)
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+}
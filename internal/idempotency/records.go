@@ -0,0 +1,187 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// recordKind is the kind of the store records of the keys.
+	recordKind = "idempotency-keys"
+	// abandonedAfter is how long a key stays in progress in the store before
+	// it is taken to belong to a replica that stopped while handling the
+	// request, and a retry is handled again.
+	abandonedAfter = 5 * time.Minute
+	// DefaultPruneInterval is how often the keys past their TTL are removed
+	// from the store.
+	DefaultPruneInterval = 10 * time.Minute
+)
+
+// keyRecord is the data of the store record of a key.
+type keyRecord struct {
+	// Fingerprint is the hex SHA-256 of the request the key was first used
+	// for.
+	Fingerprint string `json:"fingerprint"`
+	// Attempt identifies the request that recorded the key, so it only
+	// forgets its own.
+	Attempt string    `json:"attempt"`
+	Created time.Time `json:"created"`
+	// Response is nil while the first attempt is being handled.
+	Response *recordedResponse `json:"response,omitempty"`
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// recordID returns the ID of the record of a key: a hash of its scope and
+// the key, which keeps the credentials of callers out of the store.
+func recordID(id entryKey) string {
+	sum := sha256.Sum256([]byte(id.tenant + "\x00" + id.caller + "\x00" + id.key))
+	return hex.EncodeToString(sum[:])
+}
+
+// stale reports whether a stored key no longer counts: past the TTL, or
+// abandoned in progress.
+func (c *Cache) stale(record keyRecord, now time.Time) bool {
+	age := now.Sub(record.Created)
+	return age >= c.ttl || (record.Response == nil && age >= abandonedAfter)
+}
+
+// beginRecord is begin for keys kept in the store. The record is created
+// only if it does not exist, so of the replicas handling the same key at
+// once only one runs the request.
+func (c *Cache) beginRecord(ctx context.Context, id entryKey, fingerprint [sha256.Size]byte) (claim, *prior, error) {
+	claimed := recordClaim{
+		c:  c,
+		id: recordID(id),
+		record: keyRecord{
+			Fingerprint: hex.EncodeToString(fingerprint[:]),
+			Attempt:     uuid.NewString(),
+			Created:     c.now().UTC(),
+		},
+	}
+	data, err := json.Marshal(claimed.record)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode idempotency key: %w", err)
+	}
+	// A stale record is removed and the key claimed again, once.
+	for range 2 {
+		err := c.Records.CreateRecord(ctx, recordKind, probestore.Record{ID: claimed.id, Data: data})
+		if err == nil {
+			return claimed, nil, nil
+		}
+		if !k8serrors.IsAlreadyExists(err) {
+			return nil, nil, fmt.Errorf("failed to store idempotency key: %w", err)
+		}
+		stored, err := c.Records.GetRecord(ctx, recordKind, claimed.id)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+		var existing keyRecord
+		if err := json.Unmarshal(stored.Data, &existing); err == nil && !c.stale(existing, claimed.record.Created) {
+			found := &prior{mismatch: existing.Fingerprint != claimed.record.Fingerprint}
+			if r := existing.Response; r != nil {
+				found.response = &response{status: r.Status, header: r.Header, body: r.Body}
+			}
+			return nil, found, nil
+		}
+		if err := c.Records.DeleteRecord(ctx, recordKind, claimed.id); err != nil && !k8serrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to remove stale idempotency key: %w", err)
+		}
+	}
+	return nil, nil, fmt.Errorf("idempotency key was claimed and removed again while it was being recorded")
+}
+
+// recordClaim is a claim on a key kept in the store.
+type recordClaim struct {
+	c      *Cache
+	id     string
+	record keyRecord
+}
+
+func (rc recordClaim) finish(ctx context.Context, resp *response) {
+	rc.record.Response = &recordedResponse{Status: resp.status, Header: resp.header, Body: resp.body}
+	data, err := json.Marshal(rc.record)
+	if err == nil {
+		err = rc.c.Records.PutRecord(ctx, recordKind, probestore.Record{ID: rc.id, Data: data})
+	}
+	if err != nil {
+		// Retries are handled again rather than told the key is in progress
+		// until it is abandoned.
+		slog.WarnContext(ctx, "Failed to store the response of an idempotent request", "error", err)
+		rc.forget(ctx)
+	}
+}
+
+func (rc recordClaim) forget(ctx context.Context) {
+	stored, err := rc.c.Records.GetRecord(ctx, recordKind, rc.id)
+	if k8serrors.IsNotFound(err) {
+		return
+	}
+	if err == nil {
+		var existing keyRecord
+		if json.Unmarshal(stored.Data, &existing) == nil && existing.Attempt != rc.record.Attempt {
+			return
+		}
+		err = rc.c.Records.DeleteRecord(ctx, recordKind, rc.id)
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		slog.WarnContext(ctx, "Failed to remove idempotency key", "error", err)
+	}
+}
+
+// Prune removes the keys past their TTL, or abandoned in progress, from the
+// store. Keys kept in memory expire on their own.
+func (c *Cache) Prune(ctx context.Context) error {
+	if c.Records == nil {
+		return nil
+	}
+	records, err := c.Records.ListRecords(ctx, recordKind)
+	if err != nil {
+		return fmt.Errorf("failed to list idempotency keys: %w", err)
+	}
+	now := c.now()
+	for _, record := range records {
+		var existing keyRecord
+		if err := json.Unmarshal(record.Data, &existing); err == nil && !c.stale(existing, now) {
+			continue
+		}
+		if err := c.Records.DeleteRecord(ctx, recordKind, record.ID); err != nil && !k8serrors.IsNotFound(err) {
+			slog.WarnContext(ctx, "Failed to remove expired idempotency key", "error", err)
+		}
+	}
+	return nil
+}
+
+// Run prunes the store every interval until ctx is cancelled.
+func (c *Cache) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.Prune(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to prune idempotency keys", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package leader elects one replica of the API as the leader with a
+// Kubernetes Lease, and tells the other replicas which one it is.
+package leader
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Defaults of the Lease timings, those of Kubernetes controllers.
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// Config locates the Lease replicas campaign for.
+type Config struct {
+	// Namespace and Name locate the Lease. Leader election is disabled when
+	// Name is empty.
+	Namespace string
+	Name      string
+	// Identity names this replica in the Lease and must differ between
+	// replicas.
+	Identity string
+	// LeaseDuration is how long the other replicas wait after the leader
+	// stopped renewing the Lease before taking it over; zero selects
+	// DefaultLeaseDuration. The leader gives the Lease up when it could not
+	// renew it for two thirds of that, and replicas retry every fifth of
+	// the remainder, capped at the defaults.
+	LeaseDuration time.Duration
+}
+
+// Enabled reports whether leader election is configured.
+func (c Config) Enabled() bool {
+	return c.Name != ""
+}
+
+func (c Config) validate() error {
+	if c.Name == "" || c.Namespace == "" {
+		return errors.New("leader election requires a lease name and namespace")
+	}
+	if c.Identity == "" {
+		return errors.New("leader election requires an identity")
+	}
+	if c.LeaseDuration < 0 {
+		return fmt.Errorf("leader election lease duration must be positive, got %s", c.LeaseDuration)
+	}
+	return nil
+}
+
+// timings returns the lease duration, renew deadline and retry period.
+func (c Config) timings() (time.Duration, time.Duration, time.Duration) {
+	lease := c.LeaseDuration
+	if lease == 0 {
+		lease = DefaultLeaseDuration
+	}
+	renew := min(DefaultRenewDeadline, lease*2/3)
+	return lease, renew, min(DefaultRetryPeriod, renew/5)
+}
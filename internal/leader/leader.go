@@ -0,0 +1,164 @@
+//go:build !nokube
+
+package leader
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+)
+
+// Elector campaigns for the Lease and tracks its holder. A nil Elector stands
+// for a replica running alone, which always leads.
+type Elector struct {
+	elector  *leaderelection.LeaderElector
+	identity string
+	leading  atomic.Bool
+
+	mu       sync.Mutex
+	leader   string
+	lead     func(context.Context)
+	stop     context.Context
+	stopping bool
+	loops    sync.WaitGroup
+}
+
+// New returns an Elector campaigning for the Lease of cfg with client.
+func New(client kubernetes.Interface, cfg Config) (*Elector, error) {
+	if client == nil {
+		return nil, errors.New("leader election requires a Kubernetes clientset")
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	leaseDuration, renewDeadline, retryPeriod := cfg.timings()
+
+	e := &Elector{identity: cfg.Identity}
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Namespace: cfg.Namespace, Name: cfg.Name},
+			Client:     client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: cfg.Identity},
+		},
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            cfg.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				e.mu.Lock()
+				if e.stopping {
+					e.mu.Unlock()
+					return
+				}
+				e.loops.Add(1)
+				lead, stop := e.lead, e.stop
+				e.mu.Unlock()
+				defer e.loops.Done()
+
+				e.leading.Store(true)
+				metrics.RecordLeadership(true)
+				slog.Info("Started leading", "lease", cfg.Name, "identity", cfg.Identity)
+				ctx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				defer context.AfterFunc(stop, cancel)()
+				lead(ctx)
+			},
+			OnStoppedLeading: func() {
+				if e.leading.Swap(false) {
+					metrics.RecordLeadership(false)
+					slog.Info("Stopped leading", "lease", cfg.Name, "identity", cfg.Identity)
+				}
+			},
+			OnNewLeader: func(identity string) {
+				e.mu.Lock()
+				e.leader = identity
+				e.mu.Unlock()
+				if identity != cfg.Identity {
+					slog.Info("New leader elected", "lease", cfg.Name, "leader", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.elector = elector
+	return e, nil
+}
+
+// Run campaigns for the Lease until ctx is cancelled, campaigning again
+// whenever leadership is lost, and calls lead each time this replica becomes
+// the leader. The context passed to lead is cancelled when leadership is lost
+// or ctx is cancelled. On cancellation Run waits for lead to return before
+// releasing the Lease, so another replica takes over without waiting for it
+// to expire, and never runs lead alongside this one. A nil Elector calls lead
+// with ctx.
+func (e *Elector) Run(ctx context.Context, lead func(context.Context)) {
+	if e == nil {
+		metrics.RecordLeadership(true)
+		lead(ctx)
+		return
+	}
+	e.mu.Lock()
+	e.lead, e.stop, e.stopping = lead, ctx, false
+	e.mu.Unlock()
+
+	// The election outlives ctx until lead has returned.
+	electionCtx, stopElection := context.WithCancel(context.WithoutCancel(ctx))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			e.elector.Run(electionCtx)
+			select {
+			case <-electionCtx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+		}
+	}()
+
+	<-ctx.Done()
+	e.mu.Lock()
+	e.stopping = true
+	e.mu.Unlock()
+	e.loops.Wait()
+	stopElection()
+	<-done
+}
+
+// IsLeader reports whether this replica holds the Lease.
+func (e *Elector) IsLeader() bool {
+	return e == nil || e.leading.Load()
+}
+
+// Leader returns the identity of the replica holding the Lease, or "" while
+// it is unknown.
+func (e *Elector) Leader() string {
+	if e == nil {
+		return ""
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Identity returns the identity this replica campaigns with.
+func (e *Elector) Identity() string {
+	if e == nil {
+		return ""
+	}
+	return e.identity
+}
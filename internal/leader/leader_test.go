@@ -0,0 +1,78 @@
+//go:build !nokube
+
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestElector(t *testing.T) {
+	client := fake.NewClientset()
+	newElector := func(identity string) *Elector {
+		e, err := New(client, Config{Namespace: "rhobs", Name: "synthetics-api", Identity: identity, LeaseDuration: time.Second})
+		require.NoError(t, err)
+		return e
+	}
+	first, second := newElector("http://first:8080"), newElector("http://second:8080")
+
+	var running atomic.Int32
+	var firstStopped atomic.Bool
+	secondLed := make(chan struct{})
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		first.Run(firstCtx, func(ctx context.Context) {
+			running.Add(1)
+			<-ctx.Done()
+			// The lease is only released once the loops stopped.
+			time.Sleep(100 * time.Millisecond)
+			firstStopped.Store(true)
+			running.Add(-1)
+		})
+		close(firstDone)
+	}()
+	require.Eventually(t, first.IsLeader, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return running.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go second.Run(ctx, func(ctx context.Context) {
+		assert.True(t, firstStopped.Load(), "the leaders' loops overlap")
+		close(secondLed)
+		<-ctx.Done()
+	})
+	require.Eventually(t, func() bool { return second.Leader() == "http://first:8080" }, 5*time.Second, 10*time.Millisecond)
+	assert.False(t, second.IsLeader())
+
+	// The lease is released on shutdown, so the other replica takes over.
+	stopFirst()
+	<-firstDone
+	assert.False(t, first.IsLeader())
+	assert.Zero(t, running.Load())
+	require.Eventually(t, second.IsLeader, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "http://second:8080", second.Leader())
+	<-secondLed
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, Config{Namespace: "rhobs", Name: "lease", Identity: "a"})
+	assert.ErrorContains(t, err, "requires a Kubernetes clientset")
+	_, err = New(fake.NewClientset(), Config{Name: "lease", Identity: "a"})
+	assert.ErrorContains(t, err, "lease name and namespace")
+	_, err = New(fake.NewClientset(), Config{Namespace: "rhobs", Name: "lease"})
+	assert.ErrorContains(t, err, "requires an identity")
+
+	var e *Elector
+	assert.True(t, e.IsLeader(), "replicas without leader election lead")
+	assert.Empty(t, e.Leader())
+	led := false
+	e.Run(context.Background(), func(context.Context) { led = true })
+	assert.True(t, led)
+}
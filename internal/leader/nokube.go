@@ -0,0 +1,35 @@
+//go:build nokube
+
+package leader
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+)
+
+// Elector is not available in binaries built without Kubernetes support; a
+// nil Elector stands for a replica running alone, which always leads.
+type Elector struct{}
+
+// New fails: the Lease needs a Kubernetes client, which this binary is built
+// without.
+func New(any, Config) (*Elector, error) {
+	return nil, errors.New("leader election is not supported: the binary is built without Kubernetes support (nokube)")
+}
+
+// Run calls lead with ctx.
+func (e *Elector) Run(ctx context.Context, lead func(context.Context)) {
+	metrics.RecordLeadership(true)
+	lead(ctx)
+}
+
+// IsLeader always reports true.
+func (e *Elector) IsLeader() bool { return true }
+
+// Leader returns "".
+func (e *Elector) Leader() string { return "" }
+
+// Identity returns "".
+func (e *Elector) Identity() string { return "" }
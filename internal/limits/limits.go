@@ -0,0 +1,178 @@
+// Package limits enforces per-tenant request budgets: a maximum request
+// duration and a maximum number of items a list call may return.
+package limits
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+)
+
+// DefaultTenantHeader is the request header used to identify the caller's
+// tenant or role when Config.Header is not set.
+const DefaultTenantHeader = "X-Tenant"
+
+// Policy is the budget applied to a single class of callers. Zero values
+// mean "unlimited".
+type Policy struct {
+	// Timeout bounds the total time spent handling a request.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxItems bounds the number of items a list response may contain.
+	MaxItems int `mapstructure:"max_items"`
+}
+
+// Config maps tenants to their policies. Requests without a tenant header, or
+// with a tenant that has no entry, get the Default policy.
+type Config struct {
+	Header  string            `mapstructure:"header"`
+	Default Policy            `mapstructure:"default"`
+	Tenants map[string]Policy `mapstructure:"tenants"`
+}
+
+// tenantMismatchBody is the error returned when the tenant header names a
+// different tenant than the caller's client certificate.
+const tenantMismatchBody = `{"error":{"message":"the tenant header does not match the tenant of the client certificate"}}`
+
+// timeoutBody is the error returned when a request exceeds its time budget.
+var timeoutBody = fmt.Sprintf(`{"error":{"message":"request exceeded the time budget for this tenant","retry_after_seconds":%d}}`,
+	*retryafter.Seconds(http.StatusServiceUnavailable))
+
+type (
+	policyKey struct{}
+	tenantKey struct{}
+)
+
+// WithPolicy returns a copy of ctx carrying the given policy.
+func WithPolicy(ctx context.Context, p Policy) context.Context {
+	return context.WithValue(ctx, policyKey{}, p)
+}
+
+// FromContext returns the policy attached to ctx by the middleware.
+func FromContext(ctx context.Context) (Policy, bool) {
+	p, ok := ctx.Value(policyKey{}).(Policy)
+	return p, ok
+}
+
+// WithTenant returns a copy of ctx carrying the caller's tenant.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant attached to ctx by the middleware, or
+// "" if the caller sent none.
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Exceeds reports whether n items would break the policy on ctx, returning
+// the limit that applies.
+func Exceeds(ctx context.Context, n int) (int, bool) {
+	p, ok := FromContext(ctx)
+	if !ok || p.MaxItems <= 0 {
+		return 0, false
+	}
+	return p.MaxItems, n > p.MaxItems
+}
+
+// PolicyFor returns the policy that applies to the given tenant.
+func (c Config) PolicyFor(tenant string) Policy {
+	if p, ok := c.Tenants[tenant]; ok && tenant != "" {
+		return p
+	}
+	return c.Default
+}
+
+// Middleware attaches the caller's tenant and policy to the request context and, when the
+// policy has a timeout, answers 503 once it is exceeded. The tenant is taken from
+// a verified client certificate if there is one, and from the header otherwise;
+// a header naming another tenant than the certificate is rejected with 403.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	header := cfg.Header
+	if header == "" {
+		header = DefaultTenantHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		// http.TimeoutHandler owns the response once the deadline passes, so
+		// build one per distinct timeout up front rather than per request.
+		timeoutHandlers := make(map[time.Duration]http.Handler)
+		for _, p := range append([]Policy{cfg.Default}, policies(cfg.Tenants)...) {
+			if p.Timeout > 0 {
+				if _, ok := timeoutHandlers[p.Timeout]; !ok {
+					timeoutHandlers[p.Timeout] = http.TimeoutHandler(next, p.Timeout, timeoutBody)
+				}
+			}
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant := r.Header.Get(header)
+			if certTenant := certificateTenant(r); certTenant != "" {
+				if tenant != "" && tenant != certTenant {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(tenantMismatchBody))
+					return
+				}
+				tenant = certTenant
+			}
+			policy := cfg.PolicyFor(tenant)
+			r = r.WithContext(WithPolicy(WithTenant(r.Context(), tenant), policy))
+
+			if h, ok := timeoutHandlers[policy.Timeout]; ok {
+				h.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Limiter is the Middleware of a Config that can be replaced while serving.
+// Requests already being handled keep the policy they started with.
+type Limiter struct {
+	next    http.Handler
+	handler atomic.Pointer[http.Handler]
+}
+
+// NewLimiter returns a Limiter applying cfg to the requests passed on to next.
+func NewLimiter(cfg Config, next http.Handler) *Limiter {
+	l := &Limiter{next: next}
+	l.Update(cfg)
+	return l
+}
+
+// Update makes later requests use cfg.
+func (l *Limiter) Update(cfg Config) {
+	h := Middleware(cfg)(l.next)
+	l.handler.Store(&h)
+}
+
+func (l *Limiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(*l.handler.Load()).ServeHTTP(w, r)
+}
+
+// certificateTenant returns the tenant named by the Organization (O) of the
+// caller's verified client certificate, or "" if there is none. Unlike the
+// header, it cannot be chosen by the caller.
+func certificateTenant(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	if org := r.TLS.VerifiedChains[0][0].Subject.Organization; len(org) > 0 {
+		return org[0]
+	}
+	return ""
+}
+
+func policies(m map[string]Policy) []Policy {
+	out := make([]Policy, 0, len(m))
+	for _, p := range m {
+		out = append(out, p)
+	}
+	return out
+}
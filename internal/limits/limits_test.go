@@ -0,0 +1,165 @@
+package limits
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_PolicyFor(t *testing.T) {
+	cfg := Config{
+		Default: Policy{Timeout: 30 * time.Second, MaxItems: 100},
+		Tenants: map[string]Policy{
+			"dashboards": {Timeout: 10 * time.Second, MaxItems: 500},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		tenant   string
+		expected Policy
+	}{
+		{name: "known tenant", tenant: "dashboards", expected: Policy{Timeout: 10 * time.Second, MaxItems: 500}},
+		{name: "unknown tenant falls back to default", tenant: "other", expected: cfg.Default},
+		{name: "no tenant falls back to default", tenant: "", expected: cfg.Default},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, cfg.PolicyFor(tc.tenant))
+		})
+	}
+}
+
+func TestExceeds(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		n           int
+		expectedMax int
+		expectedHit bool
+	}{
+		{name: "no policy", ctx: context.Background(), n: 1000},
+		{name: "unlimited policy", ctx: WithPolicy(context.Background(), Policy{}), n: 1000},
+		{name: "within limit", ctx: WithPolicy(context.Background(), Policy{MaxItems: 10}), n: 10, expectedMax: 10},
+		{name: "over limit", ctx: WithPolicy(context.Background(), Policy{MaxItems: 10}), n: 11, expectedMax: 10, expectedHit: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			max, hit := Exceeds(tc.ctx, tc.n)
+			assert.Equal(t, tc.expectedMax, max)
+			assert.Equal(t, tc.expectedHit, hit)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	cfg := Config{
+		Header:  "X-Role",
+		Default: Policy{MaxItems: 100},
+		Tenants: map[string]Policy{
+			"slow": {Timeout: 10 * time.Millisecond},
+		},
+	}
+
+	t.Run("attaches the resolved policy to the context", func(t *testing.T) {
+		var got Policy
+		handler := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ok bool
+			got, ok = FromContext(r.Context())
+			require.True(t, ok)
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/probes", nil))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, cfg.Default, got)
+	})
+
+	t.Run("attaches the tenant to the context", func(t *testing.T) {
+		var got string
+		handler := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = TenantFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/probes", nil)
+		req.Header.Set("X-Role", "dashboards")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		assert.Equal(t, "dashboards", got)
+	})
+
+	t.Run("answers 503 when the tenant's timeout passes", func(t *testing.T) {
+		handler := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/probes", nil)
+		req.Header.Set("X-Role", "slow")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+		assert.JSONEq(t, `{"error":{"message":"request exceeded the time budget for this tenant","retry_after_seconds":10}}`, rr.Body.String())
+	})
+
+	t.Run("takes the tenant from the client certificate", func(t *testing.T) {
+		testCases := []struct {
+			name           string
+			header         string
+			expectedStatus int
+			expectedTenant string
+		}{
+			{name: "no header", expectedStatus: http.StatusOK, expectedTenant: "team-a"},
+			{name: "matching header", header: "team-a", expectedStatus: http.StatusOK, expectedTenant: "team-a"},
+			{name: "other tenant in header", header: "team-b", expectedStatus: http.StatusForbidden},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				var got string
+				handler := Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					got = TenantFromContext(r.Context())
+				}))
+
+				req := httptest.NewRequest(http.MethodGet, "/probes", nil)
+				req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+					{Subject: pkix.Name{CommonName: "client", Organization: []string{"team-a"}}},
+				}}}
+				if tc.header != "" {
+					req.Header.Set("X-Role", tc.header)
+				}
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				assert.Equal(t, tc.expectedStatus, rr.Code)
+				assert.Equal(t, tc.expectedTenant, got)
+			})
+		}
+	})
+}
+
+func TestLimiter_Update(t *testing.T) {
+	var got Policy
+	limiter := NewLimiter(Config{Default: Policy{MaxItems: 10}}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+
+	limiter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/probes", nil))
+	assert.Equal(t, 10, got.MaxItems)
+
+	limiter.Update(Config{Default: Policy{MaxItems: 20}})
+	limiter.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/probes", nil))
+	assert.Equal(t, 20, got.MaxItems)
+}
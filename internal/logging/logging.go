@@ -0,0 +1,209 @@
+// Package logging configures the process-wide structured logger and tags log
+// records with the request they were written for. Attributes attached to a
+// context with With, such as the request ID, operation and probe ID, are added
+// to every record logged with that context, including from the probe stores.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+)
+
+// RequestIDHeader carries the request's correlation ID. A valid ID sent by the
+// caller is reused; otherwise one is generated. Either way it is echoed in the
+// response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds caller-supplied request IDs.
+const maxRequestIDLength = 128
+
+// ParseLevel converts a --log-level value to a slog level.
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q, expected one of debug, info, warn, error", level)
+}
+
+// NewHandler returns a handler writing to w in the given format ("text" or
+// "json") that adds the request ID and attributes from the context to every
+// record.
+func NewHandler(w io.Writer, level, format string) (slog.Handler, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return newHandler(w, lvl, format)
+}
+
+func newHandler(w io.Writer, level slog.Leveler, format string) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected text or json", format)
+	}
+	return contextHandler{h}, nil
+}
+
+// level is the level of the default logger installed by Setup.
+var level slog.LevelVar
+
+// Setup installs a handler built like NewHandler's as the default logger, at
+// a level SetLevel can change later. Output from the standard log package is
+// routed through it as well.
+func Setup(w io.Writer, lvl, format string) error {
+	parsed, err := ParseLevel(lvl)
+	if err != nil {
+		return err
+	}
+	h, err := newHandler(w, &level, format)
+	if err != nil {
+		return err
+	}
+	level.Set(parsed)
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// SetLevel changes the level of the default logger installed by Setup.
+func SetLevel(lvl string) error {
+	parsed, err := ParseLevel(lvl)
+	if err != nil {
+		return err
+	}
+	level.Set(parsed)
+	return nil
+}
+
+type (
+	requestIDKey struct{}
+	attrsKey     struct{}
+)
+
+// With returns a copy of ctx whose log records carry the given attributes, in
+// addition to any already attached. args are key-value pairs or slog.Attr
+// values, as accepted by slog.Logger.With.
+func With(ctx context.Context, args ...any) context.Context {
+	r := slog.Record{}
+	r.Add(args...)
+	attrs := slices.Clone(contextAttrs(ctx))
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID attached to ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Middleware assigns every request a correlation ID, attaches it to the
+// request context for logging and returns it in the X-Request-ID header.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// StrictMiddleware tags the request's log records with the OpenAPI operation
+// it was routed to.
+func StrictMiddleware(f strictnethttp.StrictHTTPHandlerFunc, operationID string) strictnethttp.StrictHTTPHandlerFunc {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (any, error) {
+		return f(With(ctx, "operation", operationID), w, r, request)
+	}
+}
+
+// validRequestID accepts short IDs made of characters that are safe to log
+// and echo back in a header.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// contextHandler adds the request ID and attributes from the record's
+// context. Attributes the call site already set win over context ones with
+// the same key, so a store logging probe_id does not repeat it.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	var extra []slog.Attr
+	if id := RequestID(ctx); id != "" {
+		extra = append(extra, slog.String("request_id", id))
+	}
+	extra = append(extra, contextAttrs(ctx)...)
+	if len(extra) == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	set := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		set[a.Key] = true
+		return true
+	})
+	for _, a := range extra {
+		if !set[a.Key] {
+			r.AddAttrs(a)
+			set[a.Key] = true
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
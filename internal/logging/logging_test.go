@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler(t *testing.T) {
+	testCases := []struct {
+		name         string
+		level        string
+		format       string
+		expectErr    bool
+		expectDebug  bool
+		expectPrefix string
+	}{
+		{name: "defaults to info text", expectPrefix: "time="},
+		{name: "debug json", level: "debug", format: "json", expectDebug: true, expectPrefix: "{"},
+		{name: "level is case insensitive", level: "DEBUG", expectDebug: true, expectPrefix: "time="},
+		{name: "unknown level", level: "verbose", expectErr: true},
+		{name: "unknown format", format: "xml", expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h, err := NewHandler(&buf, tc.level, tc.format)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			logger := slog.New(h)
+			logger.Debug("debug message")
+			logger.Info("info message")
+
+			assert.Equal(t, tc.expectDebug, strings.Contains(buf.String(), "debug message"))
+			assert.Contains(t, buf.String(), "info message")
+			assert.True(t, strings.HasPrefix(buf.String(), tc.expectPrefix), buf.String())
+		})
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	defaultLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	var buf bytes.Buffer
+	require.NoError(t, Setup(&buf, "info", "text"))
+	slog.Debug("hidden")
+
+	require.NoError(t, SetLevel("debug"))
+	slog.Debug("shown")
+	require.Error(t, SetLevel("verbose"))
+	slog.Debug("still shown")
+
+	assert.NotContains(t, buf.String(), "hidden")
+	assert.Contains(t, buf.String(), "msg=shown")
+	assert.Contains(t, buf.String(), "still shown")
+}
+
+func TestHandler_AddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(&buf, "info", "json")
+	require.NoError(t, err)
+	logger := slog.New(h).With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "abc-123"), "handled")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "abc-123", record["request_id"])
+	assert.Equal(t, "test", record["component"])
+}
+
+func TestWith(t *testing.T) {
+	var buf bytes.Buffer
+	h, err := NewHandler(&buf, "info", "json")
+	require.NoError(t, err)
+	logger := slog.New(h)
+
+	ctx := WithRequestID(context.Background(), "abc-123")
+	ctx = With(ctx, "operation", "DeleteProbe")
+	child := With(ctx, slog.String("probe_id", "p-1"))
+
+	logger.InfoContext(child, "deleted", "probe_id", "p-1", "status", "pending")
+	logger.InfoContext(ctx, "parent")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var first, second map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &first))
+	require.NoError(t, json.Unmarshal(lines[1], &second))
+
+	assert.Equal(t, "abc-123", first["request_id"])
+	assert.Equal(t, "DeleteProbe", first["operation"])
+	assert.Equal(t, "p-1", first["probe_id"])
+	assert.Equal(t, 1, bytes.Count(lines[0], []byte(`"probe_id"`)), "keys set at the call site are not repeated")
+
+	assert.Equal(t, "DeleteProbe", second["operation"])
+	assert.NotContains(t, second, "probe_id", "attributes added to a child context do not leak to the parent")
+}
+
+func TestMiddleware(t *testing.T) {
+	var seen string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	testCases := []struct {
+		name     string
+		incoming string
+		reuse    bool
+	}{
+		{name: "generates an ID when none is sent"},
+		{name: "reuses a valid caller ID", incoming: "req-42.a_b:c", reuse: true},
+		{name: "replaces an ID with unsafe characters", incoming: "bad id\r\nX-Injected: 1"},
+		{name: "replaces an overly long ID", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/probes", nil)
+			if tc.incoming != "" {
+				req.Header.Set(RequestIDHeader, tc.incoming)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			got := rr.Header().Get(RequestIDHeader)
+			require.NotEmpty(t, got)
+			assert.Equal(t, got, seen, "the context and the response carry the same ID")
+			if tc.reuse {
+				assert.Equal(t, tc.incoming, got)
+			} else {
+				assert.NotEqual(t, tc.incoming, got)
+			}
+		})
+	}
+}
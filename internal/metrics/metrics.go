@@ -3,6 +3,7 @@ package metrics
 import (
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -13,18 +14,18 @@ var (
 	httpRequestsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "rhobs_synthetics_api_http_requests_total",
-			Help: "The total number of HTTP requests handled by the API.",
+			Help: "The total number of HTTP requests handled by the API, by API operation.",
 		},
-		[]string{"code", "method"},
+		[]string{"code", "method", "operation"},
 	)
 
 	httpRequestDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "rhobs_synthetics_api_http_request_duration_seconds",
-			Help:    "A histogram of the request latencies.",
+			Help:    "A histogram of the request latencies, by API operation.",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"method"},
+		[]string{"method", "operation"},
 	)
 
 	httpRequestsInFlight = prometheus.NewGauge(
@@ -34,6 +35,14 @@ var (
 		},
 	)
 
+	grpcRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_grpc_requests_total",
+			Help: "The total number of gRPC calls handled by the API, by method and status code.",
+		},
+		[]string{"code", "method"},
+	)
+
 	probestoreRequestDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "rhobs_synthetics_api_probestore_request_duration_seconds",
@@ -51,6 +60,62 @@ var (
 		[]string{"operation"},
 	)
 
+	probestoreIndexLookupsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_probestore_index_lookups_total",
+			Help: "The total number of lookups in the probe store indexes, by index and result: hit when the index answered, miss when the backend was read instead.",
+		},
+		[]string{"index", "result"},
+	)
+
+	probestoreIndexObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probestore_index_objects",
+			Help: "The number of probes held by each probe store index.",
+		},
+		[]string{"index"},
+	)
+
+	probestoreIndexMemory = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probestore_index_memory_bytes",
+			Help: "An estimate of the memory taken by the entries of each probe store index.",
+		},
+		[]string{"index"},
+	)
+
+	probestoreIndexSyncAge = &syncAgeCollector{
+		desc: prometheus.NewDesc(
+			"rhobs_synthetics_api_probestore_index_sync_age_seconds",
+			"The time since each probe store index was last brought up to date with the backend.",
+			[]string{"index"}, nil,
+		),
+		synced: make(map[string]time.Time),
+	}
+
+	shadowRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_shadow_requests_total",
+			Help: "The total number of requests mirrored to the shadow endpoint, by comparison result.",
+		},
+		[]string{"result"},
+	)
+
+	probeResultsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_probe_results_total",
+			Help: "The total number of probe run results reported by agents, by outcome.",
+		},
+		[]string{"result"},
+	)
+
+	probeSuccessRatio = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probe_success_ratio",
+			Help: "The share of successful results among the recent probe results kept by this replica.",
+		},
+	)
+
 	probesTotal = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "rhobs_synthetics_api_probes_total",
@@ -58,17 +123,251 @@ var (
 		},
 		[]string{"state", "private"},
 	)
+
+	probeStatusInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probe_status_info",
+			Help: "Why failed and terminating probes are in their state, as reported by status_reason and status_message; 1 for each such probe.",
+		},
+		[]string{"probe_id", "state", "reason", "message"},
+	)
+
+	// Listing every probe can take seconds with large ConfigMap backends, so
+	// the buckets reach further than the request buckets.
+	probeInventoryRefreshDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rhobs_synthetics_api_probe_inventory_refresh_duration_seconds",
+			Help:    "The time taken to list every probe and refresh the probe metrics, by result.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+		},
+		[]string{"result"},
+	)
+
+	probeInventoryProbes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probe_inventory_probes",
+			Help: "The number of probes listed by the last successful probe inventory refresh.",
+		},
+	)
+
+	probesPendingDeletion = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probes_pending_deletion",
+			Help: "The number of terminating probes waiting for their agent to confirm deletion or for the grace period to pass.",
+		},
+	)
+
+	webhookDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_webhook_deliveries_total",
+			Help: "The total number of probe events delivered to webhooks, by event and outcome after retries.",
+		},
+		[]string{"event", "result"},
+	)
+
+	webhookDeliveryAttemptDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rhobs_synthetics_api_webhook_delivery_attempt_duration_seconds",
+			Help:    "The time taken by each attempt to POST an event to a webhook, by result.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"result"},
+	)
+
+	eventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_events_total",
+			Help: "The total number of probe lifecycle CloudEvents, by event and outcome: published to the sink, or dropped because the outbox could not store them.",
+		},
+		[]string{"event", "result"},
+	)
+
+	eventOutboxSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_event_outbox_events",
+			Help: "The number of events waiting in the outbox to be published, as of the last relay.",
+		},
+	)
+
+	notificationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_notifications_total",
+			Help: "The total number of policy event notifications, by event, receiver and outcome.",
+		},
+		[]string{"event", "receiver", "result"},
+	)
+
+	auditSinkErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_audit_sink_errors_total",
+			Help: "The total number of audit entries that could not be written to the audit sink, by sink.",
+		},
+		[]string{"sink"},
+	)
+
+	tenantProbesTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_tenant_probes_total",
+			Help: "The number of probe configs created by each tenant.",
+		},
+		[]string{"tenant"},
+	)
+
+	regionProbesTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_region_probes_total",
+			Help: "The number of probe configs limited to each region; probes without regions run in any region and are not counted.",
+		},
+		[]string{"region"},
+	)
+
+	configReloadsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_config_reloads_total",
+			Help: "The total number of configuration reloads, by result.",
+		},
+		[]string{"result"},
+	)
+
+	clockSkewRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_clock_skew_rejections_total",
+			Help: "The total number of requests rejected for a timestamp too far ahead of the server clock, by field.",
+		},
+		[]string{"field"},
+	)
+
+	standbyWritesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_standby_writes_total",
+			Help: "The total number of writes received by a standby replica, by whether they were forwarded to the leader.",
+		},
+		[]string{"result"},
+	)
+
+	leaderGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_leader",
+			Help: "Whether this replica is the leader and runs the background loops (1) or not (0).",
+		},
+	)
+
+	leaderTransitionsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_leader_transitions_total",
+			Help: "The total number of times this replica started or stopped leading.",
+		},
+	)
+
+	probeWritesInFlight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probe_writes_in_flight",
+			Help: "The number of probe creates, updates and deletes currently running against the store, when writes are limited.",
+		},
+	)
+
+	probeWriteQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "rhobs_synthetics_api_probe_write_queue_depth",
+			Help: "The number of probe writes waiting for the write limit.",
+		},
+	)
+
+	probeWritesRejectedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_probe_writes_rejected_total",
+			Help: "The total number of probe writes rejected by the write limit, by reason.",
+		},
+		[]string{"reason"},
+	)
+
+	targetValidationFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_probe_target_validation_failures_total",
+			Help: "The total number of checks probe targets failed when validated on create, by check.",
+		},
+		[]string{"check"},
+	)
+
+	probeValidationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_probe_validations_total",
+			Help: "The total number of probes checked by each validator on create and update, by validator and result.",
+		},
+		[]string{"validator", "result"},
+	)
+
+	probeValidationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "rhobs_synthetics_api_probe_validation_duration_seconds",
+			Help:    "The time each validator took to check a probe, by validator.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"validator"},
+	)
+
+	apiKeyRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "rhobs_synthetics_api_apikey_requests_total",
+			Help: "The total number of requests carrying an API key, by key ID and result.",
+		},
+		[]string{"key_id", "result"},
+	)
 )
 
+var registerOnce sync.Once
+
+// RegisterMetrics registers the API metrics with the default registry. It may
+// be called more than once, e.g. by several servers embedded in one process.
 func RegisterMetrics() {
-	prometheus.MustRegister(
-		httpRequestsTotal,
-		httpRequestDuration,
-		httpRequestsInFlight,
-		probestoreRequestDuration,
-		probestoreErrorsTotal,
-		probesTotal,
-	)
+	registerOnce.Do(func() {
+		prometheus.MustRegister(
+			httpRequestsTotal,
+			httpRequestDuration,
+			httpRequestsInFlight,
+			grpcRequestsTotal,
+			probestoreRequestDuration,
+			probestoreErrorsTotal,
+			probestoreIndexLookupsTotal,
+			probestoreIndexObjects,
+			probestoreIndexMemory,
+			probestoreIndexSyncAge,
+			shadowRequestsTotal,
+			probeResultsTotal,
+			probeSuccessRatio,
+			probesTotal,
+			probeStatusInfo,
+			probeInventoryRefreshDuration,
+			probeInventoryProbes,
+			probesPendingDeletion,
+			webhookDeliveriesTotal,
+			webhookDeliveryAttemptDuration,
+			eventsTotal,
+			eventOutboxSize,
+			notificationsTotal,
+			auditSinkErrorsTotal,
+			tenantProbesTotal,
+			regionProbesTotal,
+			configReloadsTotal,
+			clockSkewRejectionsTotal,
+			standbyWritesTotal,
+			leaderGauge,
+			leaderTransitionsTotal,
+			probeWritesInFlight,
+			probeWriteQueueDepth,
+			probeWritesRejectedTotal,
+			targetValidationFailuresTotal,
+			probeValidationsTotal,
+			probeValidationDuration,
+			apiKeyRequestsTotal,
+		)
+	})
+}
+
+// RecordGRPCRequest counts a gRPC call by its method, such as "ListProbes",
+// and status code, such as "NotFound".
+func RecordGRPCRequest(method, code string) {
+	grpcRequestsTotal.WithLabelValues(code, method).Inc()
 }
 
 func RecordProbestoreRequest(operation string, start time.Time) {
@@ -79,10 +378,245 @@ func RecordProbestoreError(operation string) {
 	probestoreErrorsTotal.WithLabelValues(operation).Inc()
 }
 
+// RecordProbestoreIndexLookup counts a lookup in a probe store index, such
+// as "url_hash"; hit is false when the index could not answer it and the
+// backend was read instead.
+func RecordProbestoreIndexLookup(index string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	probestoreIndexLookupsTotal.WithLabelValues(index, result).Inc()
+}
+
+// SetProbestoreIndexSize sets the number of probes a probe store index holds
+// and an estimate of the memory they take, in bytes.
+func SetProbestoreIndexSize(index string, objects, bytes int) {
+	probestoreIndexObjects.WithLabelValues(index).Set(float64(objects))
+	probestoreIndexMemory.WithLabelValues(index).Set(float64(bytes))
+}
+
+// RecordProbestoreIndexSync records that a probe store index was brought up
+// to date with the backend at at.
+func RecordProbestoreIndexSync(index string, at time.Time) {
+	probestoreIndexSyncAge.mu.Lock()
+	defer probestoreIndexSyncAge.mu.Unlock()
+	probestoreIndexSyncAge.synced[index] = at
+}
+
+// ResetProbestoreIndex stops reporting a probe store index that is no longer
+// relied on, so that its size and sync age do not linger.
+func ResetProbestoreIndex(index string) {
+	probestoreIndexObjects.DeleteLabelValues(index)
+	probestoreIndexMemory.DeleteLabelValues(index)
+	probestoreIndexSyncAge.mu.Lock()
+	defer probestoreIndexSyncAge.mu.Unlock()
+	delete(probestoreIndexSyncAge.synced, index)
+}
+
+// syncAgeCollector reports the time since each index was last synced as of
+// the scrape, which a gauge set when syncing could not.
+type syncAgeCollector struct {
+	desc   *prometheus.Desc
+	mu     sync.Mutex
+	synced map[string]time.Time
+}
+
+func (c *syncAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *syncAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for index, at := range c.synced {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, time.Since(at).Seconds(), index)
+	}
+}
+
+// RecordShadowResult counts a mirrored request; result is one of "match",
+// "mismatch" or "error".
+func RecordShadowResult(result string) {
+	shadowRequestsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordProbeResult counts a probe result reported by an agent.
+func RecordProbeResult(success bool) {
+	result := "failure"
+	if success {
+		result = "success"
+	}
+	probeResultsTotal.WithLabelValues(result).Inc()
+}
+
+// SetProbeSuccessRatio sets the share of recent probe results that succeeded.
+func SetProbeSuccessRatio(ratio float64) {
+	probeSuccessRatio.Set(ratio)
+}
+
 func SetProbesTotal(state, private string, count int) {
 	probesTotal.WithLabelValues(state, private).Set(float64(count))
 }
 
+// ProbeStatus holds the status details of a probe reported by
+// rhobs_synthetics_api_probe_status_info.
+type ProbeStatus struct {
+	ProbeID string
+	State   string
+	Reason  string
+	Message string
+}
+
+// SetProbeStatusInfo replaces the status details of failed and terminating
+// probes, so probes that recovered or were removed are no longer reported.
+func SetProbeStatusInfo(statuses []ProbeStatus) {
+	probeStatusInfo.Reset()
+	for _, status := range statuses {
+		probeStatusInfo.WithLabelValues(status.ProbeID, status.State, status.Reason, status.Message).Set(1)
+	}
+}
+
+// RecordProbeInventoryRefresh records a probe inventory refresh that started
+// at start and listed probes probes, or failed with err.
+func RecordProbeInventoryRefresh(start time.Time, probes int, err error) {
+	if err != nil {
+		probeInventoryRefreshDuration.WithLabelValues("error").Observe(time.Since(start).Seconds())
+		return
+	}
+	probeInventoryRefreshDuration.WithLabelValues("success").Observe(time.Since(start).Seconds())
+	probeInventoryProbes.Set(float64(probes))
+}
+
+// SetProbesPendingDeletion sets the number of terminating probes left after
+// the last reconciliation.
+func SetProbesPendingDeletion(count int) {
+	probesPendingDeletion.Set(float64(count))
+}
+
+// RecordWebhookDelivery counts an event delivery to a webhook; result is one
+// of "success", "failure" (retries exhausted) or "dropped" (queue full).
+func RecordWebhookDelivery(event, result string) {
+	webhookDeliveriesTotal.WithLabelValues(event, result).Inc()
+}
+
+// RecordEvent counts a probe lifecycle event; result is "published" or
+// "dropped" (not stored in the outbox).
+func RecordEvent(event, result string) {
+	eventsTotal.WithLabelValues(event, result).Inc()
+}
+
+// SetEventOutboxSize sets the number of events waiting in the outbox.
+func SetEventOutboxSize(count int) {
+	eventOutboxSize.Set(float64(count))
+}
+
+// RecordNotification counts a policy event notification; result is one of
+// "success", "failure" or "dropped" (queue full).
+func RecordNotification(event, receiver, result string) {
+	notificationsTotal.WithLabelValues(event, receiver, result).Inc()
+}
+
+// RecordWebhookAttempt records a webhook delivery attempt that started at
+// start and failed with err, if not nil.
+func RecordWebhookAttempt(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	webhookDeliveryAttemptDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+}
+
+// RecordAuditSinkError counts an audit entry the sink failed to write.
+func RecordAuditSinkError(sink string) {
+	auditSinkErrorsTotal.WithLabelValues(sink).Inc()
+}
+
+// SetTenantProbes replaces the per-tenant probe counts, so tenants without
+// probes left are no longer reported.
+func SetTenantProbes(counts map[string]int) {
+	tenantProbesTotal.Reset()
+	for tenant, count := range counts {
+		tenantProbesTotal.WithLabelValues(tenant).Set(float64(count))
+	}
+}
+
+// SetRegionProbes replaces the per-region probe counts, so regions without
+// probes left are no longer reported.
+func SetRegionProbes(counts map[string]int) {
+	regionProbesTotal.Reset()
+	for region, count := range counts {
+		regionProbesTotal.WithLabelValues(region).Set(float64(count))
+	}
+}
+
+// RecordConfigReload counts a configuration reload, which failed with err if
+// not nil.
+func RecordConfigReload(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	configReloadsTotal.WithLabelValues(result).Inc()
+}
+
+// RecordClockSkewRejection counts a request rejected because the timestamp
+// field it supplied was too far ahead of the server clock.
+func RecordClockSkewRejection(field string) {
+	clockSkewRejectionsTotal.WithLabelValues(field).Inc()
+}
+
+// RecordStandbyForward counts a write received by a standby replica; result
+// is one of "forwarded", "rejected" (no leader to forward it to) or "error"
+// (the leader could not be reached).
+func RecordStandbyForward(result string) {
+	standbyWritesTotal.WithLabelValues(result).Inc()
+}
+
+// RecordLeadership records that this replica started or stopped leading.
+func RecordLeadership(leading bool) {
+	if leading {
+		leaderGauge.Set(1)
+	} else {
+		leaderGauge.Set(0)
+	}
+	leaderTransitionsTotal.Inc()
+}
+
+// RecordProbeWriteLoad sets the number of probe writes running and waiting
+// under the write limit.
+func RecordProbeWriteLoad(inFlight, queued int) {
+	probeWritesInFlight.Set(float64(inFlight))
+	probeWriteQueueDepth.Set(float64(queued))
+}
+
+// RecordProbeWriteRejected counts a probe write rejected by the write limit;
+// reason is one of "queue_full", "timeout" or "canceled" (the caller went
+// away while waiting).
+func RecordProbeWriteRejected(reason string) {
+	probeWritesRejectedTotal.WithLabelValues(reason).Inc()
+}
+
+// RecordTargetValidationFailure counts a check a probe target failed when
+// validated on create, e.g. "resolution" or "private_ip".
+func RecordTargetValidationFailure(check string) {
+	targetValidationFailuresTotal.WithLabelValues(check).Inc()
+}
+
+// RecordProbeValidation records a probe checked by a validator that started
+// at start; result is one of "accepted", "rejected" or "error" (the validator
+// could not decide).
+func RecordProbeValidation(validator, result string, start time.Time) {
+	probeValidationsTotal.WithLabelValues(validator, result).Inc()
+	probeValidationDuration.WithLabelValues(validator).Observe(time.Since(start).Seconds())
+}
+
+// RecordAPIKeyRequest counts a request carrying an API key; result is one of
+// "accepted", "rate_limited", "expired" or "invalid". Invalid keys are
+// counted with an empty key ID, so unknown IDs do not multiply the series.
+func RecordAPIKeyRequest(keyID, result string) {
+	apiKeyRequestsTotal.WithLabelValues(keyID, result).Inc()
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -97,22 +631,35 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-func Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		rw := NewResponseWriter(w)
+// OtherOperation labels requests that match no API operation.
+const OtherOperation = "other"
 
-		httpRequestsInFlight.Inc()
-		defer httpRequestsInFlight.Dec()
+// Middleware records the HTTP metrics of each request by the API operation
+// operation names, such as "ListProbes". Operations come from the spec rather
+// than the request path, so probe IDs do not multiply the series; requests
+// operation returns "" for are labelled OtherOperation.
+func Middleware(operation func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := NewResponseWriter(w)
+			op := operation(r)
+			if op == "" {
+				op = OtherOperation
+			}
 
-		next.ServeHTTP(rw, r)
+			httpRequestsInFlight.Inc()
+			defer httpRequestsInFlight.Dec()
 
-		duration := time.Since(start)
-		statusCode := strconv.Itoa(rw.statusCode)
+			next.ServeHTTP(rw, r)
 
-		httpRequestsTotal.WithLabelValues(statusCode, r.Method).Inc()
-		httpRequestDuration.WithLabelValues(r.Method).Observe(duration.Seconds())
-	})
+			duration := time.Since(start)
+			statusCode := strconv.Itoa(rw.statusCode)
+
+			httpRequestsTotal.WithLabelValues(statusCode, r.Method, op).Inc()
+			httpRequestDuration.WithLabelValues(r.Method, op).Observe(duration.Seconds())
+		})
+	}
 }
 
 func Handler() http.Handler {
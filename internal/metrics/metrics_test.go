@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -19,20 +20,27 @@ func TestMiddleware(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(httpRequestsTotal)
 
-	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	operation := func(r *http.Request) string {
+		if strings.HasPrefix(r.URL.Path, "/probes/") {
+			return "GetProbeById"
+		}
+		return ""
+	}
+	handler := Middleware(operation)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
-	req := httptest.NewRequest("GET", "/test", nil)
-	rr := httptest.NewRecorder()
-	handler.ServeHTTP(rr, req)
-
-	assert.Equal(t, http.StatusOK, rr.Code)
+	for _, path := range []string{"/test", "/probes/1", "/probes/2"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
 
 	expectedCounter := `
-		# HELP rhobs_synthetics_api_http_requests_total The total number of HTTP requests handled by the API.
+		# HELP rhobs_synthetics_api_http_requests_total The total number of HTTP requests handled by the API, by API operation.
 		# TYPE rhobs_synthetics_api_http_requests_total counter
-		rhobs_synthetics_api_http_requests_total{code="200",method="GET"} 1
+		rhobs_synthetics_api_http_requests_total{code="200",method="GET",operation="GetProbeById"} 2
+		rhobs_synthetics_api_http_requests_total{code="200",method="GET",operation="other"} 1
 	`
 	err := testutil.CollectAndCompare(httpRequestsTotal, strings.NewReader(expectedCounter))
 	assert.NoError(t, err)
@@ -58,6 +66,26 @@ func TestRecordProbestoreMetrics(t *testing.T) {
 	assert.Equal(t, 1, count)
 }
 
+func TestProbestoreIndexMetrics(t *testing.T) {
+	RecordProbestoreIndexLookup("url_hash", true)
+	RecordProbestoreIndexLookup("url_hash", true)
+	RecordProbestoreIndexLookup("url_hash", false)
+	SetProbestoreIndexSize("url_hash", 3, 672)
+	RecordProbestoreIndexSync("url_hash", time.Now().Add(-time.Minute))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(probestoreIndexLookupsTotal.WithLabelValues("url_hash", "hit")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(probestoreIndexLookupsTotal.WithLabelValues("url_hash", "miss")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(probestoreIndexObjects.WithLabelValues("url_hash")))
+	assert.Equal(t, float64(672), testutil.ToFloat64(probestoreIndexMemory.WithLabelValues("url_hash")))
+	age := testutil.ToFloat64(probestoreIndexSyncAge)
+	assert.GreaterOrEqual(t, age, time.Minute.Seconds(), "the age is taken at scrape time")
+	assert.Less(t, age, time.Hour.Seconds())
+
+	ResetProbestoreIndex("url_hash")
+	assert.Zero(t, testutil.CollectAndCount(probestoreIndexObjects))
+	assert.Zero(t, testutil.CollectAndCount(probestoreIndexSyncAge), "an index no longer relied on has no sync age")
+}
+
 func TestSetProbesTotal(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(probesTotal)
@@ -73,6 +101,107 @@ func TestSetProbesTotal(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSetTenantProbes(t *testing.T) {
+	SetTenantProbes(map[string]int{"team-a": 3, "team-b": 1})
+	SetTenantProbes(map[string]int{"team-a": 2})
+
+	expectedGauge := `
+		# HELP rhobs_synthetics_api_tenant_probes_total The number of probe configs created by each tenant.
+		# TYPE rhobs_synthetics_api_tenant_probes_total gauge
+		rhobs_synthetics_api_tenant_probes_total{tenant="team-a"} 2
+	`
+	err := testutil.CollectAndCompare(tenantProbesTotal, strings.NewReader(expectedGauge))
+	assert.NoError(t, err)
+}
+
+func TestSetRegionProbes(t *testing.T) {
+	SetRegionProbes(map[string]int{"us-east-1": 4, "eu-west-1": 1})
+	SetRegionProbes(map[string]int{"us-east-1": 3})
+
+	expectedGauge := `
+		# HELP rhobs_synthetics_api_region_probes_total The number of probe configs limited to each region; probes without regions run in any region and are not counted.
+		# TYPE rhobs_synthetics_api_region_probes_total gauge
+		rhobs_synthetics_api_region_probes_total{region="us-east-1"} 3
+	`
+	err := testutil.CollectAndCompare(regionProbesTotal, strings.NewReader(expectedGauge))
+	assert.NoError(t, err)
+}
+
+func TestRecordProbeInventoryRefresh(t *testing.T) {
+	RecordProbeInventoryRefresh(time.Now(), 42, nil)
+	RecordProbeInventoryRefresh(time.Now(), 0, errors.New("list failed"))
+
+	assert.Equal(t, 2, testutil.CollectAndCount(probeInventoryRefreshDuration), "one series per result")
+	assert.Equal(t, float64(42), testutil.ToFloat64(probeInventoryProbes), "a failed refresh keeps the last count")
+}
+
+func TestSetProbesPendingDeletion(t *testing.T) {
+	SetProbesPendingDeletion(3)
+	assert.Equal(t, float64(3), testutil.ToFloat64(probesPendingDeletion))
+}
+
+func TestRecordWebhookDelivery(t *testing.T) {
+	RecordWebhookDelivery("probe.created", "success")
+	RecordWebhookDelivery("probe.created", "success")
+	RecordWebhookDelivery("probe.deleted", "dropped")
+	RecordWebhookAttempt(time.Now(), nil)
+	RecordWebhookAttempt(time.Now(), errors.New("connection refused"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(webhookDeliveriesTotal.WithLabelValues("probe.created", "success")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(webhookDeliveriesTotal.WithLabelValues("probe.deleted", "dropped")))
+	assert.Equal(t, 2, testutil.CollectAndCount(webhookDeliveryAttemptDuration), "one series per result")
+}
+
+func TestRecordEvent(t *testing.T) {
+	RecordEvent("io.rhobs.synthetics.probe.created", "published")
+	RecordEvent("io.rhobs.synthetics.probe.created", "published")
+	RecordEvent("io.rhobs.synthetics.probe.deleted", "dropped")
+	SetEventOutboxSize(3)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(eventsTotal.WithLabelValues("io.rhobs.synthetics.probe.created", "published")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(eventsTotal.WithLabelValues("io.rhobs.synthetics.probe.deleted", "dropped")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(eventOutboxSize))
+}
+
+func TestRecordProbeValidation(t *testing.T) {
+	RecordProbeValidation("url_policy", "accepted", time.Now())
+	RecordProbeValidation("url_policy", "rejected", time.Now())
+	RecordProbeValidation("quota", "error", time.Now())
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(probeValidationsTotal.WithLabelValues("url_policy", "rejected")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(probeValidationsTotal.WithLabelValues("quota", "error")))
+	assert.Equal(t, 2, testutil.CollectAndCount(probeValidationDuration), "one series per validator")
+}
+
+func TestRecordAuditSinkError(t *testing.T) {
+	RecordAuditSinkError("events")
+	RecordAuditSinkError("events")
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(auditSinkErrorsTotal.WithLabelValues("events")))
+}
+
+func TestRecordProbeResult(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(probeResultsTotal, probeSuccessRatio)
+
+	RecordProbeResult(true)
+	RecordProbeResult(true)
+	RecordProbeResult(false)
+	SetProbeSuccessRatio(0.75)
+
+	expected := `
+		# HELP rhobs_synthetics_api_probe_results_total The total number of probe run results reported by agents, by outcome.
+		# TYPE rhobs_synthetics_api_probe_results_total counter
+		rhobs_synthetics_api_probe_results_total{result="failure"} 1
+		rhobs_synthetics_api_probe_results_total{result="success"} 2
+		# HELP rhobs_synthetics_api_probe_success_ratio The share of successful results among the recent probe results kept by this replica.
+		# TYPE rhobs_synthetics_api_probe_success_ratio gauge
+		rhobs_synthetics_api_probe_success_ratio 0.75
+	`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected))
+	assert.NoError(t, err)
+}
+
 func TestHandler(t *testing.T) {
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(httpRequestsTotal)
@@ -0,0 +1,185 @@
+// Package mutation changes probes before they are stored, like mutating
+// admission webhooks do for Kubernetes objects. Operators configure an ordered
+// chain of hooks, such as URL normalization or default labels, instead of
+// changing the API handlers.
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Hook changes a probe before it is created or updated.
+type Hook interface {
+	// Name identifies the hook in errors.
+	Name() string
+	// Mutate changes the probe in place. An error rejects the request.
+	Mutate(ctx context.Context, probe *v1.ProbeObject) error
+}
+
+type funcHook struct {
+	name string
+	fn   func(context.Context, *v1.ProbeObject) error
+}
+
+func (h funcHook) Name() string { return h.name }
+
+func (h funcHook) Mutate(ctx context.Context, probe *v1.ProbeObject) error {
+	return h.fn(ctx, probe)
+}
+
+// Func returns a hook that calls fn.
+func Func(name string, fn func(ctx context.Context, probe *v1.ProbeObject) error) Hook {
+	return funcHook{name: name, fn: fn}
+}
+
+// Chain runs hooks in order, each seeing the changes of the ones before it.
+type Chain []Hook
+
+// Mutate runs every hook on the probe, stopping at the first error.
+func (c Chain) Mutate(ctx context.Context, probe *v1.ProbeObject) error {
+	for _, hook := range c {
+		if err := hook.Mutate(ctx, probe); err != nil {
+			return fmt.Errorf("mutation hook %s: %w", hook.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Config selects a built-in hook in the mutation_hooks list of the config
+// file.
+type Config struct {
+	// Name is one of normalize_url, default_labels or rename_labels.
+	Name string `mapstructure:"name"`
+	// Labels are the labels to add for default_labels, and the old to new
+	// key mapping for rename_labels.
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+// Build returns the chain of built-in hooks described by configs, in order.
+func Build(configs []Config) (Chain, error) {
+	chain := make(Chain, 0, len(configs))
+	for i, cfg := range configs {
+		var (
+			hook Hook
+			err  error
+		)
+		switch cfg.Name {
+		case "normalize_url":
+			hook = NormalizeURL()
+		case "default_labels":
+			hook, err = DefaultLabels(cfg.Labels)
+		case "rename_labels":
+			hook, err = RenameLabels(cfg.Labels)
+		default:
+			err = fmt.Errorf("unknown hook %q, expected one of normalize_url, default_labels, rename_labels", cfg.Name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mutation_hooks[%d]: %w", i, err)
+		}
+		chain = append(chain, hook)
+	}
+	return chain, nil
+}
+
+// NormalizeURL returns a hook that lowercases the scheme and host of the
+// static URL and drops the scheme's default port, so that spellings of the
+// same URL cannot create duplicate probes.
+func NormalizeURL() Hook {
+	return Func("normalize_url", func(_ context.Context, probe *v1.ProbeObject) error {
+		u, err := url.Parse(probe.StaticUrl)
+		if err != nil {
+			return fmt.Errorf("invalid static_url %q: %w", probe.StaticUrl, err)
+		}
+		u.Scheme = strings.ToLower(u.Scheme)
+		host := strings.ToLower(u.Hostname())
+		port := u.Port()
+		if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			port = ""
+		}
+		if port != "" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		u.Host = host
+		probe.StaticUrl = u.String()
+		return nil
+	})
+}
+
+// DefaultLabels returns a hook that adds the given labels to probes that do
+// not set them.
+func DefaultLabels(defaults map[string]string) (Hook, error) {
+	if len(defaults) == 0 {
+		return nil, fmt.Errorf("default_labels needs at least one label")
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaults)) {
+		if err := validateLabel(key, defaults[key]); err != nil {
+			return nil, err
+		}
+	}
+	defaults = maps.Clone(defaults)
+	return Func("default_labels", func(_ context.Context, probe *v1.ProbeObject) error {
+		if probe.Labels == nil {
+			probe.Labels = &v1.LabelsSchema{}
+		}
+		for key, value := range defaults {
+			if _, ok := (*probe.Labels)[key]; !ok {
+				(*probe.Labels)[key] = value
+			}
+		}
+		return nil
+	}), nil
+}
+
+// RenameLabels returns a hook that moves labels from legacy keys to their
+// replacements, given as old to new key. A label already set under the new
+// key wins, and the legacy label is dropped either way.
+func RenameLabels(renames map[string]string) (Hook, error) {
+	if len(renames) == 0 {
+		return nil, fmt.Errorf("rename_labels needs at least one label")
+	}
+	for _, oldKey := range slices.Sorted(maps.Keys(renames)) {
+		if err := validateLabel(renames[oldKey], ""); err != nil {
+			return nil, err
+		}
+	}
+	renames = maps.Clone(renames)
+	return Func("rename_labels", func(_ context.Context, probe *v1.ProbeObject) error {
+		if probe.Labels == nil {
+			return nil
+		}
+		labels := *probe.Labels
+		for oldKey, newKey := range renames {
+			value, ok := labels[oldKey]
+			if !ok {
+				continue
+			}
+			delete(labels, oldKey)
+			if _, exists := labels[newKey]; !exists {
+				labels[newKey] = value
+			}
+		}
+		return nil
+	}), nil
+}
+
+// validateLabel reports a label the stores could not keep.
+func validateLabel(key, value string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid label key %q: %s", key, errs[0])
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return fmt.Errorf("invalid value %q for label %q: %s", value, key, errs[0])
+	}
+	return nil
+}
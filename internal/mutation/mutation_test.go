@@ -0,0 +1,162 @@
+package mutation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string, err error) Hook {
+		return Func(name, func(_ context.Context, probe *v1.ProbeObject) error {
+			calls = append(calls, name)
+			probe.StaticUrl += "/" + name
+			return err
+		})
+	}
+
+	t.Run("runs hooks in order", func(t *testing.T) {
+		calls = nil
+		probe := v1.ProbeObject{StaticUrl: "https://example.com"}
+		err := Chain{record("a", nil), record("b", nil)}.Mutate(context.Background(), &probe)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, calls)
+		assert.Equal(t, "https://example.com/a/b", probe.StaticUrl)
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		calls = nil
+		probe := v1.ProbeObject{}
+		err := Chain{record("a", errors.New("boom")), record("b", nil)}.Mutate(context.Background(), &probe)
+		assert.EqualError(t, err, "mutation hook a: boom")
+		assert.Equal(t, []string{"a"}, calls)
+	})
+
+	t.Run("nil chain", func(t *testing.T) {
+		probe := v1.ProbeObject{StaticUrl: "https://example.com"}
+		require.NoError(t, Chain(nil).Mutate(context.Background(), &probe))
+		assert.Equal(t, "https://example.com", probe.StaticUrl)
+	})
+}
+
+func TestNormalizeURL(t *testing.T) {
+	testCases := []struct {
+		url         string
+		expected    string
+		expectedErr string
+	}{
+		{url: "HTTPS://Example.COM/Path?q=A", expected: "https://example.com/Path?q=A"},
+		{url: "https://example.com:443/", expected: "https://example.com/"},
+		{url: "http://example.com:80", expected: "http://example.com"},
+		{url: "http://example.com:443", expected: "http://example.com:443"},
+		{url: "https://[::1]:443/health", expected: "https://[::1]/health"},
+		{url: "https://[::1]:8443/health", expected: "https://[::1]:8443/health"},
+		{url: "://bad", expectedErr: `invalid static_url "://bad"`},
+	}
+
+	hook := NormalizeURL()
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			probe := v1.ProbeObject{StaticUrl: tc.url}
+			err := hook.Mutate(context.Background(), &probe)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, probe.StaticUrl)
+		})
+	}
+}
+
+func TestDefaultLabels(t *testing.T) {
+	hook, err := DefaultLabels(map[string]string{"team": "sre", "env": "prod"})
+	require.NoError(t, err)
+
+	probe := v1.ProbeObject{}
+	require.NoError(t, hook.Mutate(context.Background(), &probe))
+	assert.Equal(t, v1.LabelsSchema{"team": "sre", "env": "prod"}, *probe.Labels)
+
+	probe = v1.ProbeObject{Labels: &v1.LabelsSchema{"env": "stage"}}
+	require.NoError(t, hook.Mutate(context.Background(), &probe))
+	assert.Equal(t, v1.LabelsSchema{"team": "sre", "env": "stage"}, *probe.Labels, "labels set on the probe win")
+
+	_, err = DefaultLabels(nil)
+	assert.EqualError(t, err, "default_labels needs at least one label")
+	_, err = DefaultLabels(map[string]string{"bad key": "x"})
+	assert.ErrorContains(t, err, `invalid label key "bad key"`)
+	_, err = DefaultLabels(map[string]string{"team": "not valid!"})
+	assert.ErrorContains(t, err, `invalid value "not valid!" for label "team"`)
+}
+
+func TestRenameLabels(t *testing.T) {
+	hook, err := RenameLabels(map[string]string{"cluster": "cluster_id"})
+	require.NoError(t, err)
+
+	probe := v1.ProbeObject{Labels: &v1.LabelsSchema{"cluster": "abc", "env": "prod"}}
+	require.NoError(t, hook.Mutate(context.Background(), &probe))
+	assert.Equal(t, v1.LabelsSchema{"cluster_id": "abc", "env": "prod"}, *probe.Labels)
+
+	probe = v1.ProbeObject{Labels: &v1.LabelsSchema{"cluster": "old", "cluster_id": "new"}}
+	require.NoError(t, hook.Mutate(context.Background(), &probe))
+	assert.Equal(t, v1.LabelsSchema{"cluster_id": "new"}, *probe.Labels, "the new key wins")
+
+	probe = v1.ProbeObject{}
+	require.NoError(t, hook.Mutate(context.Background(), &probe))
+	assert.Nil(t, probe.Labels)
+
+	_, err = RenameLabels(map[string]string{"cluster": "bad key"})
+	assert.ErrorContains(t, err, `invalid label key "bad key"`)
+}
+
+func TestBuild(t *testing.T) {
+	testCases := []struct {
+		name          string
+		configs       []Config
+		expectedHooks []string
+		expectedErr   string
+	}{
+		{name: "no hooks"},
+		{
+			name: "hooks in order",
+			configs: []Config{
+				{Name: "rename_labels", Labels: map[string]string{"cluster": "cluster_id"}},
+				{Name: "normalize_url"},
+				{Name: "default_labels", Labels: map[string]string{"team": "sre"}},
+			},
+			expectedHooks: []string{"rename_labels", "normalize_url", "default_labels"},
+		},
+		{
+			name:        "unknown hook",
+			configs:     []Config{{Name: "normalize_url"}, {Name: "lowercase"}},
+			expectedErr: `mutation_hooks[1]: unknown hook "lowercase", expected one of normalize_url, default_labels, rename_labels`,
+		},
+		{
+			name:        "missing labels",
+			configs:     []Config{{Name: "default_labels"}},
+			expectedErr: "mutation_hooks[0]: default_labels needs at least one label",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chain, err := Build(tc.configs)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []string
+			for _, hook := range chain {
+				names = append(names, hook.Name())
+			}
+			assert.Equal(t, tc.expectedHooks, names)
+		})
+	}
+}
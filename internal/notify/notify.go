@@ -0,0 +1,332 @@
+// Package notify tells people about policy events, such as agents running
+// out of capacity, probes stuck in a state, or Prometheus Probe resources
+// changed behind the API's back, through Slack incoming webhooks or email.
+// It is meant for small deployments without an alerting pipeline; larger
+// ones should alert on the metrics instead.
+//
+// Events are routed to receivers by rules matching their type and tenant,
+// and the same event is sent at most once per repeat interval, so a probe
+// stuck for a day does not message every monitoring round. Like webhook
+// subscriptions, everything is held in memory, so each replica notifies of
+// the events it sees itself.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+)
+
+// EventType is the kind of a policy event.
+type EventType string
+
+const (
+	// QuotaNearlyExhausted is sent when an agent holds nearly as many probes
+	// as its max_probes capacity allows.
+	QuotaNearlyExhausted EventType = "quota_nearly_exhausted"
+	// ProbeStuck is sent for probes reported by GET /probes/problems.
+	ProbeStuck EventType = "probe_stuck"
+	// ReconciliationDrift is sent when a Prometheus Probe resource managed
+	// by the API was changed by something else.
+	ReconciliationDrift EventType = "reconciliation_drift"
+)
+
+// EventTypes are the events receivers can be routed.
+var EventTypes = []EventType{QuotaNearlyExhausted, ProbeStuck, ReconciliationDrift}
+
+const (
+	// DefaultRepeatInterval is how long the same event is not sent again.
+	DefaultRepeatInterval = 4 * time.Hour
+	// DefaultQuotaThreshold is the share of an agent's capacity at which
+	// QuotaNearlyExhausted is sent.
+	DefaultQuotaThreshold = 0.9
+	// DefaultTimeout bounds each attempt to send a notification.
+	DefaultTimeout = 10 * time.Second
+
+	// queueSize bounds the notifications waiting to be sent; events are
+	// dropped while it is full.
+	queueSize = 100
+)
+
+// Event is a policy event.
+type Event struct {
+	Type EventType
+	// Key identifies what the event is about within its type, such as a
+	// probe ID. Events with the same type and key are sent once per repeat
+	// interval.
+	Key string
+	// Tenant is the tenant the event concerns, if any; routes can match it.
+	Tenant string
+	// Summary is a one-line description of the event.
+	Summary string
+	// Details are extra facts, listed by name below the summary.
+	Details map[string]string
+	Time    time.Time
+}
+
+// text renders the event for humans.
+func (e Event) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", e.Type, e.Summary)
+	if e.Tenant != "" {
+		fmt.Fprintf(&b, "\ntenant: %s", e.Tenant)
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Details)) {
+		fmt.Fprintf(&b, "\n%s: %s", name, e.Details[name])
+	}
+	fmt.Fprintf(&b, "\ntime: %s", e.Time.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// Receiver sends events somewhere people see them.
+type Receiver interface {
+	// Name identifies the receiver in routes, logs and metrics.
+	Name() string
+	Send(ctx context.Context, event Event) error
+}
+
+// Route sends the events it matches to its receivers. Routes are tried in
+// order and the first match wins, unless it sets Continue.
+type Route struct {
+	// Events are the event types matched; empty matches every type.
+	Events []EventType `mapstructure:"events"`
+	// Tenants are the tenants matched; empty matches every event, including
+	// those without a tenant.
+	Tenants []string `mapstructure:"tenants"`
+	// Receivers are the names of the receivers the events are sent to.
+	Receivers []string `mapstructure:"receivers"`
+	// Continue tries the following routes too after this one matched.
+	Continue bool `mapstructure:"continue"`
+}
+
+func (r Route) matches(event Event) bool {
+	return (len(r.Events) == 0 || slices.Contains(r.Events, event.Type)) &&
+		(len(r.Tenants) == 0 || slices.Contains(r.Tenants, event.Tenant))
+}
+
+// Config is the notifications section of the config file.
+type Config struct {
+	Slack []SlackConfig `mapstructure:"slack"`
+	SMTP  []SMTPConfig  `mapstructure:"smtp"`
+	// Routes pick the receivers of each event; without any, every event is
+	// sent to every receiver.
+	Routes []Route `mapstructure:"routes"`
+	// RepeatInterval is how long the same event is not sent again; zero
+	// selects DefaultRepeatInterval.
+	RepeatInterval time.Duration `mapstructure:"repeat_interval"`
+	// QuotaThreshold is the share of an agent's capacity, between 0 and 1,
+	// at which QuotaNearlyExhausted is sent; zero selects
+	// DefaultQuotaThreshold.
+	QuotaThreshold float64 `mapstructure:"quota_threshold"`
+}
+
+// Enabled reports whether any receiver is configured.
+func (c Config) Enabled() bool {
+	return len(c.Slack) > 0 || len(c.SMTP) > 0
+}
+
+// Notifier routes events to receivers.
+type Notifier struct {
+	receivers      map[string]Receiver
+	routes         []Route
+	repeatInterval time.Duration
+	quotaThreshold float64
+	queue          chan delivery
+	now            func() time.Time
+
+	mu   sync.Mutex
+	sent map[sentKey]time.Time
+}
+
+type sentKey struct {
+	event EventType
+	key   string
+}
+
+type delivery struct {
+	receiver Receiver
+	event    Event
+}
+
+// New builds the receivers of cfg, adds the given ones, which lets embedders
+// plug in their own, and checks that the routes only name them. It returns
+// nil, and notifying is a no-op, when there is no receiver. Events are only
+// sent while Run is running.
+func New(cfg Config, extra ...Receiver) (*Notifier, error) {
+	if cfg.RepeatInterval < 0 {
+		return nil, fmt.Errorf("notification repeat interval must be positive, got %s", cfg.RepeatInterval)
+	}
+	if cfg.QuotaThreshold < 0 || cfg.QuotaThreshold > 1 {
+		return nil, fmt.Errorf("notification quota threshold must be between 0 and 1, got %g", cfg.QuotaThreshold)
+	}
+	if !cfg.Enabled() && len(extra) == 0 {
+		if len(cfg.Routes) > 0 {
+			return nil, fmt.Errorf("notification routes are configured without any receiver")
+		}
+		return nil, nil
+	}
+
+	receivers := make(map[string]Receiver)
+	add := func(r Receiver, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, ok := receivers[r.Name()]; ok {
+			return fmt.Errorf("duplicate notification receiver %q", r.Name())
+		}
+		receivers[r.Name()] = r
+		return nil
+	}
+	for i, c := range cfg.Slack {
+		if err := add(NewSlack(c)); err != nil {
+			return nil, fmt.Errorf("notifications.slack[%d]: %w", i, err)
+		}
+	}
+	for i, c := range cfg.SMTP {
+		if err := add(NewSMTP(c)); err != nil {
+			return nil, fmt.Errorf("notifications.smtp[%d]: %w", i, err)
+		}
+	}
+	for _, r := range extra {
+		if err := add(r, nil); err != nil {
+			return nil, err
+		}
+	}
+	for i, route := range cfg.Routes {
+		if len(route.Receivers) == 0 {
+			return nil, fmt.Errorf("notifications.routes[%d]: at least one receiver is required", i)
+		}
+		for _, name := range route.Receivers {
+			if _, ok := receivers[name]; !ok {
+				return nil, fmt.Errorf("notifications.routes[%d]: unknown receiver %q", i, name)
+			}
+		}
+		for _, event := range route.Events {
+			if !slices.Contains(EventTypes, event) {
+				return nil, fmt.Errorf("notifications.routes[%d]: unknown event %q, expected one of %v", i, event, EventTypes)
+			}
+		}
+	}
+
+	n := &Notifier{
+		receivers:      receivers,
+		routes:         slices.Clone(cfg.Routes),
+		repeatInterval: cfg.RepeatInterval,
+		quotaThreshold: cfg.QuotaThreshold,
+		queue:          make(chan delivery, queueSize),
+		now:            time.Now,
+		sent:           make(map[sentKey]time.Time),
+	}
+	if n.repeatInterval == 0 {
+		n.repeatInterval = DefaultRepeatInterval
+	}
+	if n.quotaThreshold == 0 {
+		n.quotaThreshold = DefaultQuotaThreshold
+	}
+	return n, nil
+}
+
+// QuotaThreshold returns the share of an agent's capacity at which
+// QuotaNearlyExhausted is sent.
+func (n *Notifier) QuotaThreshold() float64 {
+	if n == nil {
+		return DefaultQuotaThreshold
+	}
+	return n.quotaThreshold
+}
+
+// Notify queues the event for the receivers its routes pick, unless the same
+// event was sent within the repeat interval. It does not wait for the event
+// to be sent, and does nothing on a nil Notifier.
+func (n *Notifier) Notify(ctx context.Context, event Event) {
+	if n == nil {
+		return
+	}
+	now := n.now()
+	if event.Time.IsZero() {
+		event.Time = now
+	}
+
+	n.mu.Lock()
+	key := sentKey{event: event.Type, key: event.Key}
+	if last, ok := n.sent[key]; ok && now.Sub(last) < n.repeatInterval {
+		n.mu.Unlock()
+		return
+	}
+	n.sent[key] = now
+	for k, last := range n.sent {
+		if now.Sub(last) >= n.repeatInterval {
+			delete(n.sent, k)
+		}
+	}
+	n.mu.Unlock()
+
+	for _, receiver := range n.route(event) {
+		select {
+		case n.queue <- delivery{receiver: receiver, event: event}:
+		default:
+			metrics.RecordNotification(string(event.Type), receiver.Name(), "dropped")
+			slog.WarnContext(ctx, "Dropped notification, the queue is full", "event", event.Type, "receiver", receiver.Name())
+		}
+	}
+}
+
+// route returns the receivers of the event, each once.
+func (n *Notifier) route(event Event) []Receiver {
+	if len(n.routes) == 0 {
+		return slices.SortedFunc(maps.Values(n.receivers), func(a, b Receiver) int {
+			return strings.Compare(a.Name(), b.Name())
+		})
+	}
+	var names []string
+	for _, route := range n.routes {
+		if !route.matches(event) {
+			continue
+		}
+		for _, name := range route.Receivers {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		if !route.Continue {
+			break
+		}
+	}
+	receivers := make([]Receiver, 0, len(names))
+	for _, name := range names {
+		receivers = append(receivers, n.receivers[name])
+	}
+	return receivers
+}
+
+// Run sends queued notifications until ctx is cancelled. A notification that
+// fails is logged and not retried: the event is sent again once the repeat
+// interval passes if it still holds.
+func (n *Notifier) Run(ctx context.Context) {
+	if n == nil {
+		return
+	}
+	for {
+		select {
+		case d := <-n.queue:
+			sendCtx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+			err := d.receiver.Send(sendCtx, d.event)
+			cancel()
+			if err != nil {
+				metrics.RecordNotification(string(d.event.Type), d.receiver.Name(), "failure")
+				slog.WarnContext(ctx, "Failed to send notification", "event", d.event.Type, "key", d.event.Key, "receiver", d.receiver.Name(), "error", err)
+				continue
+			}
+			metrics.RecordNotification(string(d.event.Type), d.receiver.Name(), "success")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
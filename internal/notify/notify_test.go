@@ -0,0 +1,264 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeReceiver records the events it is sent.
+type fakeReceiver struct {
+	name string
+
+	mu     sync.Mutex
+	events []Event
+}
+
+func (f *fakeReceiver) Name() string { return f.name }
+
+func (f *fakeReceiver) Send(_ context.Context, event Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, event)
+	return nil
+}
+
+// newNotifier returns a Notifier sending to the fake receivers, without
+// building real ones.
+func newNotifier(routes []Route, receivers ...*fakeReceiver) *Notifier {
+	n := &Notifier{
+		receivers:      make(map[string]Receiver),
+		routes:         routes,
+		repeatInterval: DefaultRepeatInterval,
+		quotaThreshold: DefaultQuotaThreshold,
+		queue:          make(chan delivery, queueSize),
+		now:            time.Now,
+		sent:           make(map[sentKey]time.Time),
+	}
+	for _, r := range receivers {
+		n.receivers[r.name] = r
+	}
+	return n
+}
+
+// queued returns the names of the receivers of the queued deliveries.
+func queued(n *Notifier) []string {
+	var names []string
+	for {
+		select {
+		case d := <-n.queue:
+			names = append(names, d.receiver.Name())
+		default:
+			return names
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	slack := SlackConfig{Name: "ops", WebhookURL: "https://hooks.slack.com/services/T/B/X"}
+	mail := SMTPConfig{Name: "mail", Host: "smtp.example.com", From: "api@example.com", To: []string{"team@example.com"}}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantNil bool
+		wantErr string
+	}{
+		{name: "no receivers", wantNil: true},
+		{name: "slack and email", cfg: Config{Slack: []SlackConfig{slack}, SMTP: []SMTPConfig{mail}}},
+		{name: "routes", cfg: Config{Slack: []SlackConfig{slack}, Routes: []Route{{Events: []EventType{ProbeStuck}, Receivers: []string{"ops"}}}}},
+		{name: "routes without receivers", cfg: Config{Routes: []Route{{Receivers: []string{"ops"}}}}, wantErr: "without any receiver"},
+		{name: "unknown receiver", cfg: Config{Slack: []SlackConfig{slack}, Routes: []Route{{Receivers: []string{"pager"}}}}, wantErr: `unknown receiver "pager"`},
+		{name: "unknown event", cfg: Config{Slack: []SlackConfig{slack}, Routes: []Route{{Events: []EventType{"disk_full"}, Receivers: []string{"ops"}}}}, wantErr: `unknown event "disk_full"`},
+		{name: "route without receivers", cfg: Config{Slack: []SlackConfig{slack}, Routes: []Route{{}}}, wantErr: "at least one receiver"},
+		{name: "duplicate receiver", cfg: Config{Slack: []SlackConfig{slack, slack}}, wantErr: `duplicate notification receiver "ops"`},
+		{name: "plain http webhook", cfg: Config{Slack: []SlackConfig{{Name: "ops", WebhookURL: "http://hooks.slack.com/x"}}}, wantErr: "https"},
+		{name: "email without recipients", cfg: Config{SMTP: []SMTPConfig{{Name: "mail", Host: "smtp.example.com", From: "api@example.com"}}}, wantErr: "notifications.smtp[0]"},
+		{name: "header injection", cfg: Config{SMTP: []SMTPConfig{{Name: "mail", Host: "smtp.example.com", From: "api@example.com", To: []string{"a@example.com\r\nBcc: b@example.com"}}}}, wantErr: "invalid email address"},
+		{name: "negative repeat interval", cfg: Config{RepeatInterval: -time.Minute}, wantErr: "must be positive"},
+		{name: "threshold above one", cfg: Config{QuotaThreshold: 1.5}, wantErr: "between 0 and 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := New(tt.cfg)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNil, n == nil)
+		})
+	}
+}
+
+func TestNotifier_Route(t *testing.T) {
+	ops, mail, team := &fakeReceiver{name: "ops"}, &fakeReceiver{name: "mail"}, &fakeReceiver{name: "team"}
+
+	t.Run("without routes every receiver gets every event", func(t *testing.T) {
+		n := newNotifier(nil, ops, mail)
+		n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "a"})
+		assert.Equal(t, []string{"mail", "ops"}, queued(n))
+	})
+
+	t.Run("first matching route wins", func(t *testing.T) {
+		n := newNotifier([]Route{
+			{Tenants: []string{"team-a"}, Receivers: []string{"team"}},
+			{Events: []EventType{ProbeStuck}, Receivers: []string{"ops"}},
+			{Receivers: []string{"mail"}},
+		}, ops, mail, team)
+		n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "a", Tenant: "team-a"})
+		assert.Equal(t, []string{"team"}, queued(n))
+		n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "b"})
+		assert.Equal(t, []string{"ops"}, queued(n))
+		n.Notify(context.Background(), Event{Type: ReconciliationDrift, Key: "c"})
+		assert.Equal(t, []string{"mail"}, queued(n))
+	})
+
+	t.Run("continue tries the following routes", func(t *testing.T) {
+		n := newNotifier([]Route{
+			{Receivers: []string{"ops"}, Continue: true},
+			{Events: []EventType{QuotaNearlyExhausted}, Receivers: []string{"ops", "mail"}},
+		}, ops, mail)
+		n.Notify(context.Background(), Event{Type: QuotaNearlyExhausted, Key: "agent-1"})
+		assert.Equal(t, []string{"ops", "mail"}, queued(n), "each receiver gets the event once")
+	})
+
+	t.Run("unmatched events are not sent", func(t *testing.T) {
+		n := newNotifier([]Route{{Tenants: []string{"team-a"}, Receivers: []string{"team"}}}, team)
+		n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "a"})
+		assert.Empty(t, queued(n))
+	})
+}
+
+func TestNotifier_RepeatInterval(t *testing.T) {
+	n := newNotifier(nil, &fakeReceiver{name: "ops"})
+	now := time.Now()
+	n.now = func() time.Time { return now }
+
+	n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "a"})
+	n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "a"})
+	assert.Len(t, queued(n), 1, "the same event is sent once")
+
+	n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "b"})
+	n.Notify(context.Background(), Event{Type: ReconciliationDrift, Key: "a"})
+	assert.Len(t, queued(n), 2, "other keys and types are sent")
+
+	now = now.Add(DefaultRepeatInterval)
+	n.Notify(context.Background(), Event{Type: ProbeStuck, Key: "a"})
+	assert.Len(t, queued(n), 1, "the event is sent again after the repeat interval")
+}
+
+func TestNotifier_Nil(t *testing.T) {
+	var n *Notifier
+	n.Notify(context.Background(), Event{Type: ProbeStuck})
+	n.Run(context.Background())
+	assert.Equal(t, DefaultQuotaThreshold, n.QuotaThreshold())
+}
+
+func TestNotifier_Run(t *testing.T) {
+	ops := &fakeReceiver{name: "ops"}
+	n := newNotifier(nil, ops)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.Run(ctx)
+		close(done)
+	}()
+
+	n.Notify(ctx, Event{Type: ProbeStuck, Key: "a", Summary: "stuck"})
+	assert.Eventually(t, func() bool {
+		ops.mu.Lock()
+		defer ops.mu.Unlock()
+		return len(ops.events) == 1
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	ops.mu.Lock()
+	defer ops.mu.Unlock()
+	assert.Equal(t, "stuck", ops.events[0].Summary)
+	assert.False(t, ops.events[0].Time.IsZero(), "the event time is set")
+}
+
+func TestSlack_Send(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	s, err := NewSlack(SlackConfig{Name: "ops", WebhookURL: srv.URL + "/ok"})
+	require.NoError(t, err)
+	s.client = srv.Client()
+
+	event := Event{
+		Type:    ProbeStuck,
+		Summary: "Probe p1 is stuck",
+		Tenant:  "team-a",
+		Details: map[string]string{"reason": "pending_too_long", "probe_id": "p1"},
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	require.NoError(t, s.Send(context.Background(), event))
+	assert.Equal(t, "[probe_stuck] Probe p1 is stuck\ntenant: team-a\nprobe_id: p1\nreason: pending_too_long\ntime: 2026-01-02T03:04:05Z", got["text"])
+
+	s.url = srv.URL + "/fail"
+	assert.ErrorContains(t, s.Send(context.Background(), event), "404")
+
+	t.Run("errors do not reveal the webhook URL", func(t *testing.T) {
+		s, err := NewSlack(SlackConfig{Name: "ops", WebhookURL: "https://127.0.0.1:1/services/secret"})
+		require.NoError(t, err)
+		err = s.Send(context.Background(), event)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "secret")
+	})
+}
+
+func TestSMTP_Send(t *testing.T) {
+	s, err := NewSMTP(SMTPConfig{
+		Name:     "mail",
+		Host:     "smtp.example.com",
+		Username: "api",
+		Password: "secret",
+		From:     "api@example.com",
+		To:       []string{"a@example.com", "b@example.com"},
+	})
+	require.NoError(t, err)
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg string
+	s.sendMail = func(_ context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		assert.NotNil(t, auth)
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, string(msg)
+		return nil
+	}
+
+	require.NoError(t, s.Send(context.Background(), Event{
+		Type:    QuotaNearlyExhausted,
+		Summary: "Agent a1 holds 9 of its 10 probes\r\nBcc: x@example.com",
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}))
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, "api@example.com", gotFrom)
+	assert.Equal(t, []string{"a@example.com", "b@example.com"}, gotTo)
+
+	header, body, ok := strings.Cut(gotMsg, "\r\n\r\n")
+	require.True(t, ok)
+	assert.Contains(t, header, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, header, "Subject: [rhobs-synthetics] Agent a1 holds 9 of its 10 probes  Bcc: x@example.com\r\n", "line breaks cannot add headers")
+	assert.NotContains(t, header, "\r\nBcc:")
+	assert.True(t, strings.HasPrefix(body, "[quota_nearly_exhausted] "))
+}
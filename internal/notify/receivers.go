@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SlackConfig is a Slack incoming webhook.
+type SlackConfig struct {
+	Name string `mapstructure:"name"`
+	// WebhookURL is the incoming webhook URL Slack gave for the channel.
+	WebhookURL string `mapstructure:"webhook_url"`
+}
+
+// Slack posts events to a Slack incoming webhook.
+type Slack struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewSlack returns a receiver posting to the webhook.
+func NewSlack(cfg SlackConfig) (*Slack, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("a receiver name is required")
+	}
+	u, err := url.Parse(cfg.WebhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("invalid slack webhook_url, expected an absolute https URL")
+	}
+	return &Slack{name: cfg.Name, url: cfg.WebhookURL, client: &http.Client{Timeout: DefaultTimeout}}, nil
+}
+
+func (s *Slack) Name() string { return s.name }
+
+// Send posts the event as the text of a message.
+func (s *Slack) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string]string{"text": event.text()})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		// The URL is a secret; do not let it reach the logs.
+		if urlErr, ok := errors.AsType[*url.Error](err); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("slack webhook request failed: %w", err)
+	}
+	defer res.Body.Close() //nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook answered %s", res.Status)
+	}
+	return nil
+}
+
+// SMTPConfig is a mail server events are emailed through.
+type SMTPConfig struct {
+	Name string `mapstructure:"name"`
+	// Host and Port address the server, which must offer STARTTLS when
+	// credentials are set. Port defaults to 587.
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port"`
+	// Username and Password, when set, authenticate with PLAIN.
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+}
+
+// SMTP emails events.
+type SMTP struct {
+	name string
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	// sendMail is replaced in tests.
+	sendMail func(ctx context.Context, addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTP returns a receiver emailing through the server.
+func NewSMTP(cfg SMTPConfig) (*SMTP, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("a receiver name is required")
+	}
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("an smtp host is required")
+	}
+	if cfg.From == "" || len(cfg.To) == 0 {
+		return nil, fmt.Errorf("smtp from and to addresses are required")
+	}
+	for _, addr := range append([]string{cfg.From}, cfg.To...) {
+		if strings.ContainsAny(addr, "\r\n") {
+			return nil, fmt.Errorf("invalid email address %q", addr)
+		}
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+	s := &SMTP{
+		name:     cfg.Name,
+		addr:     net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		from:     cfg.From,
+		to:       cfg.To,
+		sendMail: sendMail,
+	}
+	if cfg.Username != "" {
+		s.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return s, nil
+}
+
+func (s *SMTP) Name() string { return s.name }
+
+// Send emails the event, with its summary as the subject.
+func (s *SMTP) Send(ctx context.Context, event Event) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(&msg, "Subject: [rhobs-synthetics] %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(event.Summary))
+	fmt.Fprintf(&msg, "Date: %s\r\n", event.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(event.text(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	if err := s.sendMail(ctx, s.addr, s.auth, s.from, s.to, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email through %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+// sendMail does what smtp.SendMail does, but gives up when ctx is done, so a
+// hanging server cannot hold up the notifications behind it.
+func sendMail(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close() //nolint:errcheck
+		return err
+	}
+	defer c.Close() //nolint:errcheck
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		// PlainAuth refuses to send credentials without TLS.
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,85 @@
+package pagetoken
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCodec(t *testing.T) {
+	_, err := NewCodec([]byte("too-short"))
+	require.Error(t, err)
+
+	_, err = NewCodec([]byte(strings.Repeat("k", MinKeyLength)))
+	require.NoError(t, err)
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec, err := NewCodec([]byte(strings.Repeat("k", MinKeyLength)))
+	require.NoError(t, err)
+
+	cur := Cursor{After: "d290f1ee-6c54-4b01-90e6-d701748f0851", Selector: "app=rhobs-synthetics-probe", Tenant: "dashboards"}
+	token, err := codec.Encode(cur)
+	require.NoError(t, err)
+
+	got, err := codec.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, cur, got)
+
+	// The token does not reveal the query or tenant it was issued for.
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "dashboards")
+	assert.NotContains(t, string(raw), "rhobs-synthetics-probe")
+
+	again, err := codec.Encode(cur)
+	require.NoError(t, err)
+	assert.NotEqual(t, token, again, "every token is sealed with a fresh nonce")
+
+	// Another replica with the same key accepts the token.
+	other, err := NewCodec([]byte(strings.Repeat("k", MinKeyLength)))
+	require.NoError(t, err)
+	got, err = other.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, cur, got)
+}
+
+func TestCodec_DecodeRejectsInvalidTokens(t *testing.T) {
+	codec, err := NewCodec([]byte(strings.Repeat("k", MinKeyLength)))
+	require.NoError(t, err)
+	token, err := codec.Encode(Cursor{After: "a", Tenant: "dashboards"})
+	require.NoError(t, err)
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 1
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+	unsealed := base64.RawURLEncoding.EncodeToString([]byte(`{"after":"a","tenant":"other"}`))
+
+	otherKey, err := NewCodec([]byte(strings.Repeat("x", MinKeyLength)))
+	require.NoError(t, err)
+	foreign, err := otherKey.Encode(Cursor{After: "a", Tenant: "dashboards"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "truncated", token: token[:10]},
+		{name: "not base64", token: "!!!"},
+		{name: "tampered", token: tampered},
+		{name: "not sealed", token: unsealed},
+		{name: "sealed with another key", token: foreign},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := codec.Decode(tc.token)
+			assert.ErrorIs(t, err, ErrInvalid)
+		})
+	}
+}
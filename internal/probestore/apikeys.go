@@ -0,0 +1,57 @@
+package probestore
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ErrAPIKeysUnsupported is returned by wrappers whose store keeps no API
+// keys.
+var ErrAPIKeysUnsupported = errors.New("the probe store does not keep API keys")
+
+// APIKey is an API key automation clients authenticate with. Only the
+// SHA-256 hash of its secret is stored.
+type APIKey struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	SecretHash string `json:"secret_hash"`
+	// RateLimit is the requests per second the key may send; zero leaves
+	// the limit to the server.
+	RateLimit float64   `json:"rate_limit,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt is when the key stops being accepted; zero for keys valid
+	// until revoked.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// Expired reports whether the key has expired at now.
+func (k APIKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// APIKeyStore is implemented by stores that keep API keys, so every replica
+// sharing the store accepts the same keys.
+type APIKeyStore interface {
+	// CreateAPIKey stores a new key.
+	CreateAPIKey(ctx context.Context, key APIKey) error
+	// ListAPIKeys returns every stored key, ordered by ID.
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+	// DeleteAPIKey removes a key, or returns a NotFound error.
+	DeleteAPIKey(ctx context.Context, id string) error
+}
+
+// apiKeyNotFound is the error DeleteAPIKey returns for unknown keys.
+func apiKeyNotFound(id string) error {
+	return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "apikeys"}, id)
+}
+
+// sortAPIKeys orders keys by ID, as ListAPIKeys returns them.
+func sortAPIKeys(keys []APIKey) {
+	slices.SortFunc(keys, func(a, b APIKey) int { return strings.Compare(a.ID, b.ID) })
+}
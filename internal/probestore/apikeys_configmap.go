@@ -0,0 +1,94 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+// apiKeyConfigMapName is the ConfigMap the Kubernetes-backed stores keep API
+// keys in, as key ID keys with JSON values. Like the tombstone ConfigMap, it
+// holds no probe-config.json key, so probe listings skip it.
+const apiKeyConfigMapName = "probe-api-keys"
+
+// createConfigMapAPIKey adds the key to the API key ConfigMap. Concurrent
+// writers are retried.
+func createConfigMapAPIKey(ctx context.Context, client configMapClient, namespace string, key APIKey) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, apiKeyConfigMapName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: apiKeyConfigMapName, Namespace: namespace}}
+			cm.Data = map[string]string{key.ID: string(value)}
+			_, err = client.Create(ctx, cm, metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				// Created concurrently; retry as an update.
+				return k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, apiKeyConfigMapName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key.ID] = string(value)
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// listConfigMapAPIKeys reads the keys of the API key ConfigMap.
+func listConfigMapAPIKeys(ctx context.Context, client configMapClient) ([]APIKey, error) {
+	cm, err := client.Get(ctx, apiKeyConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return []APIKey{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	keys := make([]APIKey, 0, len(cm.Data))
+	for id, value := range cm.Data {
+		var key APIKey
+		if err := json.Unmarshal([]byte(value), &key); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling API key from configmap", "key_id", id, "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sortAPIKeys(keys)
+	return keys, nil
+}
+
+// deleteConfigMapAPIKey removes a key from the API key ConfigMap.
+// Concurrent writers are retried.
+func deleteConfigMapAPIKey(ctx context.Context, client configMapClient, id string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, apiKeyConfigMapName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return apiKeyNotFound(id)
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := cm.Data[id]; !ok {
+			return apiKeyNotFound(id)
+		}
+		delete(cm.Data, id)
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
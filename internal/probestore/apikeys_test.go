@@ -0,0 +1,70 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestAPIKeys(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			keys, ok := store.(APIKeyStore)
+			require.True(t, ok, "%T keeps API keys", store)
+
+			list, err := keys.ListAPIKeys(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, list)
+
+			created := time.Now().UTC().Truncate(time.Second)
+			ci := APIKey{ID: "b000000000000002", Name: "ci", SecretHash: "hash-ci", RateLimit: 5, CreatedAt: created}
+			backup := APIKey{ID: "a000000000000001", Name: "backup", SecretHash: "hash-backup", CreatedAt: created, ExpiresAt: created.Add(time.Hour)}
+			require.NoError(t, keys.CreateAPIKey(ctx, ci))
+			require.NoError(t, keys.CreateAPIKey(ctx, backup))
+			_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+			require.NoError(t, err)
+
+			list, err = keys.ListAPIKeys(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []APIKey{backup, ci}, list, "keys are listed by ID")
+
+			probes, err := store.ListProbes(ctx, "")
+			require.NoError(t, err)
+			assert.Len(t, probes, 1, "API keys are not listed as probes")
+
+			require.NoError(t, keys.DeleteAPIKey(ctx, backup.ID))
+			assert.True(t, k8serrors.IsNotFound(keys.DeleteAPIKey(ctx, backup.ID)), "revoked keys are not found")
+			list, err = keys.ListAPIKeys(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []APIKey{ci}, list)
+		})
+	}
+}
+
+func TestTracedProbeStore_APIKeysUnsupported(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	// Hiding the local store's methods leaves only the ProbeStorage ones.
+	traced := NewTracedProbeStore(struct{ ProbeStorage }{local}, "plain")
+	assert.ErrorIs(t, traced.CreateAPIKey(ctx, APIKey{ID: "a000000000000001"}), ErrAPIKeysUnsupported)
+	_, err = traced.ListAPIKeys(ctx)
+	assert.ErrorIs(t, err, ErrAPIKeysUnsupported)
+	assert.ErrorIs(t, traced.DeleteAPIKey(ctx, "a000000000000001"), ErrAPIKeysUnsupported)
+}
+
+func TestAPIKey_Expired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, APIKey{}.Expired(now), "keys without expiry never expire")
+	assert.False(t, APIKey{ExpiresAt: now.Add(time.Second)}.Expired(now))
+	assert.True(t, APIKey{ExpiresAt: now}.Expired(now))
+}
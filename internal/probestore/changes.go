@@ -0,0 +1,40 @@
+package probestore
+
+import "sync"
+
+// ChangeNotifier is implemented by stores that tell when their probes may
+// have changed, so callers waiting for a change need not list the probes
+// over and over.
+type ChangeNotifier interface {
+	// ProbesChanged returns a channel closed at the next change to a probe.
+	// A change may be reported more than once, and only changes the store
+	// sees are reported.
+	ProbesChanged() <-chan struct{}
+}
+
+// changeSignal closes a channel at each change, waking everyone waiting for
+// it. The zero value is ready to use.
+type changeSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns a channel closed at the next notify.
+func (c *changeSignal) wait() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ch == nil {
+		c.ch = make(chan struct{})
+	}
+	return c.ch
+}
+
+// notify closes the channel returned by wait, if anyone waits.
+func (c *changeSignal) notify() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ch != nil {
+		close(c.ch)
+		c.ch = nil
+	}
+}
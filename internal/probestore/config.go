@@ -0,0 +1,77 @@
+package probestore
+
+import "time"
+
+// Config holds the settings of every storage backend, one stanza per engine.
+// Only the stanza of the configured engine is used; each backend's constructor
+// validates its own settings.
+type Config struct {
+	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
+	Local      LocalConfig      `mapstructure:"local"`
+	Memory     MemoryConfig     `mapstructure:"memory"`
+	Postgres   PostgresConfig   `mapstructure:"postgres"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	S3         S3Config         `mapstructure:"s3"`
+}
+
+// KubernetesConfig configures the etcd (ConfigMap) and crd engines.
+type KubernetesConfig struct {
+	// Namespace is where probe ConfigMaps or Probe resources are stored.
+	Namespace string `mapstructure:"namespace"`
+	// ShardNamespaces spreads the probe ConfigMaps of the etcd engine over
+	// several namespaces by probe ID; probes are kept in Namespace when empty.
+	ShardNamespaces []string `mapstructure:"shard_namespaces"`
+	// Kubeconfig is the path to a kubeconfig file; in-cluster config is used when empty.
+	Kubeconfig string `mapstructure:"kubeconfig"`
+}
+
+// LocalConfig configures the local engine.
+type LocalConfig struct {
+	// DataDir is the directory probes are written to; "data" when empty.
+	DataDir string `mapstructure:"data_dir"`
+}
+
+// MemoryConfig configures the memory engine.
+type MemoryConfig struct {
+	// SnapshotFile is the JSON file probes are restored from at startup and
+	// written to periodically; probes are lost on restart when empty.
+	SnapshotFile string `mapstructure:"snapshot_file"`
+	// SnapshotInterval is how often the snapshot file is written;
+	// DefaultSnapshotInterval when zero.
+	SnapshotInterval time.Duration `mapstructure:"snapshot_interval"`
+}
+
+// PostgresConfig configures the postgres engine.
+type PostgresConfig struct {
+	// DSN is the connection string of the database.
+	DSN string `mapstructure:"dsn"`
+}
+
+// RedisConfig configures the redis engine.
+type RedisConfig struct {
+	// URL locates the server, as redis://[user:password@]host:port/db, or
+	// rediss:// for TLS.
+	URL string `mapstructure:"url"`
+	// Prefix is prepended to every key, to share a database; "rhobs-synthetics:"
+	// when empty.
+	Prefix string `mapstructure:"prefix"`
+}
+
+// S3Config configures the s3 engine.
+type S3Config struct {
+	// Bucket is the bucket probes are stored in.
+	Bucket string `mapstructure:"bucket"`
+	// Endpoint is the URL of the S3-compatible service; AWS S3 in Region when empty.
+	Endpoint string `mapstructure:"endpoint"`
+	// Region is the region requests are signed for; "us-east-1" when empty.
+	Region string `mapstructure:"region"`
+	// Prefix is prepended to every object key, to share a bucket.
+	Prefix string `mapstructure:"prefix"`
+	// AccessKeyID and SecretAccessKey are the credentials requests are signed
+	// with; requests are anonymous when both are empty.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	// PathStyle addresses the bucket as a path of the endpoint instead of a
+	// subdomain, which most self-hosted services require.
+	PathStyle bool `mapstructure:"path_style"`
+}
@@ -7,3 +7,6 @@ const (
 	probeURLHashLabelKey = "rhobs-synthetics/static-url-hash"
 	probeStatusLabelKey  = "rhobs-synthetics/status"
 )
+
+// ProbeSelector is the label selector matching every probe of a store.
+const ProbeSelector = baseAppLabelKey + "=" + baseAppLabelValue
@@ -0,0 +1,926 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	probeCRDGroup   = "synthetics.rhobs.io"
+	probeCRDVersion = "v1alpha1"
+	probeCRDKind    = "Probe"
+)
+
+// ProbeGVR is the resource served by the Probe CustomResourceDefinition in
+// config/crd. Probe objects are named after the probe ID.
+var ProbeGVR = schema.GroupVersionResource{
+	Group:    probeCRDGroup,
+	Version:  probeCRDVersion,
+	Resource: "probes",
+}
+
+// ProbeResourceReference returns a reference to the Probe resource a
+// CRDProbeStore keeps the probe in.
+func ProbeResourceReference(namespace string, probeID uuid.UUID) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: ProbeGVR.GroupVersion().String(),
+		Kind:       probeCRDKind,
+		Namespace:  namespace,
+		Name:       probeID.String(),
+	}
+}
+
+// probeCRSpec is the spec of a Probe custom resource. The probe status is kept
+// in the status subresource rather than the spec.
+type probeCRSpec struct {
+	ID             string             `json:"id"`
+	StaticURL      string             `json:"staticUrl"`
+	AdditionalURLs []string           `json:"additionalUrls,omitempty"`
+	Regions        []string           `json:"regions,omitempty"`
+	Labels         map[string]string  `json:"labels,omitempty"`
+	Interval       string             `json:"interval,omitempty"`
+	Timeout        string             `json:"timeout,omitempty"`
+	Module         string             `json:"module,omitempty"`
+	Alerting       *probeCRAlerting   `json:"alerting,omitempty"`
+	Owner          *probeCROwner      `json:"owner,omitempty"`
+	Assertions     *probeCRAssertions `json:"assertions,omitempty"`
+	Auth           *probeCRAuth       `json:"auth,omitempty"`
+	DNS            *probeCRDNS        `json:"dns,omitempty"`
+	TCP            *probeCRTCP        `json:"tcp,omitempty"`
+	ICMP           *probeCRICMP       `json:"icmp,omitempty"`
+	Paused         bool               `json:"paused,omitempty"`
+	// Generation is the probe's generation. The resource's metadata.generation
+	// cannot stand in for it, as it also counts heartbeats in spec.labels.
+	Generation int64 `json:"generation,omitempty"`
+	// URLHash is the hex SHA-256 of StaticURL. The static-url-hash label
+	// only holds a truncated copy.
+	URLHash string `json:"urlHash,omitempty"`
+}
+
+// probeCRAlerting is the alert routing metadata in a Probe spec.
+type probeCRAlerting struct {
+	Severity                 string `json:"severity,omitempty"`
+	RunbookURL               string `json:"runbookUrl,omitempty"`
+	SilenceDuringMaintenance *bool  `json:"silenceDuringMaintenance,omitempty"`
+}
+
+// probeCROwner is the owning team and escalation contact in a Probe spec.
+type probeCROwner struct {
+	Team              string `json:"team,omitempty"`
+	EscalationContact string `json:"escalationContact,omitempty"`
+}
+
+// probeCRAssertions is the response assertions of an http probe in a Probe
+// spec.
+type probeCRAssertions struct {
+	StatusCodes   []string                 `json:"statusCodes,omitempty"`
+	BodyRegex     string                   `json:"bodyRegex,omitempty"`
+	Headers       []probeCRHeaderAssertion `json:"headers,omitempty"`
+	TLSExpiryDays *int                     `json:"tlsExpiryDays,omitempty"`
+}
+
+// probeCRHeaderAssertion is a response header a probe requires.
+type probeCRHeaderAssertion struct {
+	Name       string `json:"name"`
+	ValueRegex string `json:"valueRegex,omitempty"`
+}
+
+// probeCRAuth is the credentials of the probe in a Probe spec. The password
+// and bearer token only ever hold the redaction marker; their values are kept
+// in Secrets.
+type probeCRAuth struct {
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	BearerToken string `json:"bearerToken,omitempty"`
+}
+
+// probeCRDNS is the query of a dns probe in a Probe spec.
+type probeCRDNS struct {
+	QueryName       string   `json:"queryName"`
+	RecordType      string   `json:"recordType"`
+	ExpectedAnswers []string `json:"expectedAnswers,omitempty"`
+	Resolver        string   `json:"resolver,omitempty"`
+}
+
+// probeCRTCP is the address of a tcp probe in a Probe spec.
+type probeCRTCP struct {
+	Address string `json:"address"`
+	TLS     *bool  `json:"tls,omitempty"`
+}
+
+// probeCRICMP is the host of an icmp probe in a Probe spec.
+type probeCRICMP struct {
+	Host        string `json:"host"`
+	PacketCount *int   `json:"packetCount,omitempty"`
+}
+
+// CRDProbeStore implements the ProbeStorage interface using Probe custom
+// resources accessed through the dynamic client.
+type CRDProbeStore struct {
+	Client              dynamic.Interface
+	Namespace           string
+	StaleProbeTTL       time.Duration
+	NoHeartbeatProbeTTL time.Duration
+	TombstoneTTL        time.Duration
+}
+
+// NewCRDProbeStore creates a new CRDProbeStore. The Probe CRD is expected to be
+// installed already; like the ConfigMap store, nothing is checked up front.
+func NewCRDProbeStore(ctx context.Context, client dynamic.Interface, namespace string) (*CRDProbeStore, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("kubernetes namespace cannot be empty")
+	}
+	staleTTL, noHeartbeatTTL := staleProbeTTLsFromEnv()
+	slog.InfoContext(ctx, "Initializing CRD probe store", "namespace", namespace, "stale_ttl", staleTTL, "no_heartbeat_ttl", noHeartbeatTTL)
+	return &CRDProbeStore{
+		Client:              client,
+		Namespace:           namespace,
+		StaleProbeTTL:       staleTTL,
+		NoHeartbeatProbeTTL: noHeartbeatTTL,
+		TombstoneTTL:        tombstoneTTLFromEnv(),
+	}, nil
+}
+
+func (c *CRDProbeStore) resource() dynamic.ResourceInterface {
+	return c.Client.Resource(ProbeGVR).Namespace(c.Namespace)
+}
+
+// CheckHealth checks that probe resources can be listed, fetching at most one.
+func (c *CRDProbeStore) CheckHealth(ctx context.Context) error {
+	if _, err := c.resource().List(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		return fmt.Errorf("failed to list probe resources: %w", err)
+	}
+	return nil
+}
+
+func (c *CRDProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	list, err := c.resource().List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list probe resources: %w", err)
+	}
+
+	probes := []v1.ProbeObject{}
+	for i := range list.Items {
+		probe, err := probeFromUnstructured(&list.Items[i])
+		if err != nil {
+			slog.ErrorContext(ctx, "Error decoding probe resource", "resource", list.Items[i].GetName(), "error", err)
+			continue
+		}
+		probes = append(probes, *probe)
+	}
+	return probes, nil
+}
+
+func (c *CRDProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	obj, err := c.resource().Get(ctx, probeID.String(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err // Pass the error up, including not found errors
+	}
+	return probeFromUnstructured(obj)
+}
+
+func (c *CRDProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ProbeGVR.GroupVersion().String())
+	obj.SetKind(probeCRDKind)
+	obj.SetName(probe.Id.String())
+	obj.SetNamespace(c.Namespace)
+
+	objLabels := make(map[string]string)
+	objAnnotations := make(map[string]string)
+	if probe.Labels != nil {
+		for key, val := range *probe.Labels {
+			if key == lastReconciledKey {
+				objAnnotations[key] = val
+			} else {
+				objLabels[key] = val
+			}
+		}
+	}
+	objLabels[baseAppLabelKey] = baseAppLabelValue
+	objLabels[probeURLHashLabelKey] = urlHashString
+	objLabels[probeStatusLabelKey] = string(probe.Status)
+	obj.SetLabels(objLabels)
+	obj.SetAnnotations(objAnnotations)
+
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
+	// The API server replaces it with the time it stores the object.
+	obj.SetCreationTimestamp(metav1.NewTime(*probe.CreationTimestamp))
+	if err := setProbeSpec(obj, probe); err != nil {
+		return nil, err
+	}
+
+	created, err := c.resource().Create(ctx, obj, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The API server drops status on create, so it is written separately.
+	// Should that fail, the resource is removed again, so that the probe is
+	// either created as a whole or not at all and the call can be retried.
+	withStatus, err := c.writeStatus(ctx, created, probe)
+	if err != nil {
+		uid := created.GetUID()
+		if delErr := c.resource().Delete(context.WithoutCancel(ctx), created.GetName(), metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}}); delErr != nil && !k8serrors.IsNotFound(delErr) {
+			slog.ErrorContext(ctx, "Failed to remove probe resource whose status could not be written", "probe_id", probe.Id, "error", delErr)
+		}
+		return nil, err
+	}
+	created = withStatus
+
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	probe.ResourceVersion = nil
+	return withResourceVersion(&probe, created.GetResourceVersion()), nil
+}
+
+func (c *CRDProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	name := probe.Id.String()
+
+	obj, err := c.resource().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err // Let the caller handle not found errors
+	}
+	if err := checkResourceVersion(ctx, probe.Id, obj.GetResourceVersion()); err != nil {
+		return nil, err
+	}
+
+	stored, err := probeFromUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode probe resource %s: %w", name, err)
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+	withNextGeneration(&probe, *stored)
+	keepCreationTimestamp(&probe, *stored)
+	withUpdateTimestamp(&probe, *stored)
+	withDeletionTimestamp(&probe, *stored)
+	withStatusHistory(ctx, &probe, *stored)
+	withURLHash(&probe, *stored)
+	if err := setProbeSpec(obj, probe); err != nil {
+		return nil, err
+	}
+
+	// Update labels and annotations, ensuring base labels are preserved.
+	// last-reconciled goes to annotations to avoid Prometheus label churn.
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = make(map[string]string)
+	}
+	objAnnotations := obj.GetAnnotations()
+	if objAnnotations == nil {
+		objAnnotations = make(map[string]string)
+	}
+	if probe.Labels != nil {
+		for key, val := range *probe.Labels {
+			if key == lastReconciledKey {
+				objAnnotations[key] = val
+			} else {
+				objLabels[key] = val
+			}
+		}
+	}
+	objLabels[baseAppLabelKey] = baseAppLabelValue
+	objLabels[probeStatusLabelKey] = string(probe.Status)
+	obj.SetLabels(objLabels)
+	obj.SetAnnotations(objAnnotations)
+
+	updated, err := c.resource().Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update probe resource %s: %w", name, err)
+	}
+
+	updated, err = c.writeStatus(ctx, updated, probe)
+	if err != nil {
+		return nil, err
+	}
+
+	finalProbe, err := probeFromUnstructured(updated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode updated probe resource: %w", err)
+	}
+
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return finalProbe, nil
+}
+
+func (c *CRDProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	obj, err := c.resource().Get(ctx, probeID.String(), metav1.GetOptions{})
+	if err != nil {
+		return err // Pass the error up, including not found errors
+	}
+	if err := checkResourceVersion(ctx, probeID, obj.GetResourceVersion()); err != nil {
+		return err
+	}
+
+	probe, err := probeFromUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to decode probe resource %s: %w", obj.GetName(), err)
+	}
+
+	// Handle deletion based on current probe status
+	switch probe.Status {
+	case v1.Pending:
+		// Probe was never picked up by an agent, delete immediately
+		err = c.DeleteProbeStorage(ctx, probeID)
+		if err != nil {
+			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
+		return nil
+
+	case v1.Active:
+		// Probe is active, set to terminating and wait for agent cleanup
+		if err := c.transitionToTerminating(withStatusReason(ctx, deletionReason), obj); err != nil {
+			return fmt.Errorf("failed to update probe resource %s to terminating status: %w", obj.GetName(), err)
+		}
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
+		return nil
+
+	case v1.Terminating:
+		// Already terminating, no action needed
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
+		return nil
+
+	case v1.Failed:
+		// Failed probe, delete immediately as agent likely won't process it
+		err = c.DeleteProbeStorage(ctx, probeID)
+		if err != nil {
+			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
+		return nil
+
+	default:
+		// Unknown status, treat as pending and delete immediately
+		err = c.DeleteProbeStorage(ctx, probeID)
+		if err != nil {
+			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), probe.Status, err)
+		}
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", probe.Status)
+		return nil
+	}
+}
+
+func (c *CRDProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	slog.DebugContext(ctx, "Deleting probe resource", "probe_id", probeID)
+	if err := c.resource().Delete(ctx, probeID.String(), deleteOptions(ctx)); err != nil {
+		return err
+	}
+	if err := writeConfigMapTombstone(ctx, c.configMaps(), c.Namespace, newTombstone(probeID), c.TombstoneTTL); err != nil {
+		slog.WarnContext(ctx, "Failed to write probe tombstone", "probe_id", probeID, "error", err)
+	}
+	return nil
+}
+
+// configMaps returns the ConfigMaps of the namespace, where tombstones, API
+// keys, the event outbox and records are kept as for the ConfigMap store.
+func (c *CRDProbeStore) configMaps() configMapClient {
+	return dynamicConfigMaps{client: c.Client.Resource(corev1.SchemeGroupVersion.WithResource("configmaps")).Namespace(c.Namespace)}
+}
+
+// GetTombstone returns the tombstone of a recently removed probe.
+func (c *CRDProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	return getConfigMapTombstone(ctx, c.configMaps(), probeID, c.TombstoneTTL)
+}
+
+// CreateAPIKey stores a new API key in the API key ConfigMap.
+func (c *CRDProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	return createConfigMapAPIKey(ctx, c.configMaps(), c.Namespace, key)
+}
+
+// ListAPIKeys returns the keys of the API key ConfigMap.
+func (c *CRDProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	return listConfigMapAPIKeys(ctx, c.configMaps())
+}
+
+// DeleteAPIKey removes a key from the API key ConfigMap.
+func (c *CRDProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	return deleteConfigMapAPIKey(ctx, c.configMaps(), id)
+}
+
+// AddOutboxEvent stores an event in the outbox ConfigMap.
+func (c *CRDProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	return addConfigMapOutboxEvent(ctx, c.configMaps(), c.Namespace, event)
+}
+
+// ListOutboxEvents returns the events of the outbox ConfigMap.
+func (c *CRDProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	return listConfigMapOutboxEvents(ctx, c.configMaps())
+}
+
+// DeleteOutboxEvent removes an event from the outbox ConfigMap.
+func (c *CRDProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	return deleteConfigMapOutboxEvent(ctx, c.configMaps(), id)
+}
+
+// PutRecord stores a record in the ConfigMap of its kind.
+func (c *CRDProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	return putConfigMapRecord(ctx, c.configMaps(), c.Namespace, kind, record)
+}
+
+// CreateRecord adds a record to the ConfigMap of its kind.
+func (c *CRDProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	return createConfigMapRecord(ctx, c.configMaps(), c.Namespace, kind, record)
+}
+
+// GetRecord returns a record of the ConfigMap of its kind.
+func (c *CRDProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	return getConfigMapRecord(ctx, c.configMaps(), kind, id)
+}
+
+// ListRecords returns the records of the ConfigMap of the kind.
+func (c *CRDProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	return listConfigMapRecords(ctx, c.configMaps(), kind)
+}
+
+// DeleteRecord removes a record from the ConfigMap of its kind.
+func (c *CRDProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	return deleteConfigMapRecord(ctx, c.configMaps(), kind, id)
+}
+
+func (c *CRDProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	hashLabelSelector := fmt.Sprintf("%s=%s", probeURLHashLabelKey, urlHashString)
+	existing, err := c.resource().List(ctx, metav1.ListOptions{
+		LabelSelector: hashLabelSelector,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing probes: %w", err)
+	}
+	// Exclude probes in terminating or failed status -- these are effectively
+	// inactive and should not block creation of a new probe for the same URL.
+	for _, obj := range existing.Items {
+		status := obj.GetLabels()[probeStatusLabelKey]
+		if status != string(v1.Terminating) && status != string(v1.Failed) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GarbageCollectStaleProbes applies the same staleness rules as the ConfigMap
+// store (see KubernetesProbeStore.GarbageCollectStaleProbes) to Probe resources.
+func (c *CRDProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	selector := fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue)
+	list, err := c.resource().List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list probe resources for GC: %w", err)
+	}
+
+	now := clock.Now()
+	deleted := 0
+
+	for i := range list.Items {
+		obj := &list.Items[i]
+		lastReconciledStr, ok := obj.GetAnnotations()[lastReconciledKey]
+		if !ok {
+			created := obj.GetCreationTimestamp()
+			if !created.IsZero() && now.Sub(created.Time) > c.NoHeartbeatProbeTTL {
+				if err := c.gcProbe(ctx, obj, "no heartbeat ever received"); err != nil {
+					slog.ErrorContext(ctx, "GC: failed to transition no-heartbeat probe to terminating", "resource", obj.GetName(), "error", err)
+					continue
+				}
+				deleted++
+			}
+			continue
+		}
+
+		lastReconciled, err := time.Parse(lastReconciledLayout, lastReconciledStr)
+		if err != nil {
+			slog.WarnContext(ctx, "GC: could not parse last-reconciled, skipping", "resource", obj.GetName(), "last_reconciled", lastReconciledStr, "error", err)
+			continue
+		}
+
+		if now.Sub(lastReconciled) <= c.StaleProbeTTL {
+			continue // still fresh
+		}
+
+		if err := c.gcProbe(ctx, obj, fmt.Sprintf("stale heartbeat %s", lastReconciledStr)); err != nil {
+			slog.ErrorContext(ctx, "GC: failed to transition stale probe to terminating", "resource", obj.GetName(), "error", err)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// gcProbe moves a stale probe to terminating, or deletes it if it is already
+// terminating (the agent had its chance to clean up).
+func (c *CRDProbeStore) gcProbe(ctx context.Context, obj *unstructured.Unstructured, reason string) error {
+	if obj.GetLabels()[probeStatusLabelKey] == string(v1.Terminating) {
+		slog.InfoContext(ctx, "GC: deleting already-terminating probe", "resource", obj.GetName(), "reason", reason)
+		return c.resource().Delete(ctx, obj.GetName(), metav1.DeleteOptions{})
+	}
+
+	if err := c.transitionToTerminating(withStatusReason(ctx, reason), obj); err != nil {
+		return fmt.Errorf("failed to update status to terminating: %w", err)
+	}
+	slog.InfoContext(ctx, "GC: transitioned probe to terminating", "resource", obj.GetName(), "reason", reason)
+	return nil
+}
+
+// transitionToTerminating updates both the status label used by selectors and
+// the status subresource, recording the change in the probe's history.
+func (c *CRDProbeStore) transitionToTerminating(ctx context.Context, obj *unstructured.Unstructured) error {
+	probe, err := probeFromUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to decode probe resource %s: %w", obj.GetName(), err)
+	}
+	from := probe.Status
+	probe.Status = v1.Terminating
+	recordStatusChange(ctx, probe, from)
+
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = make(map[string]string)
+	}
+	objLabels[probeStatusLabelKey] = string(v1.Terminating)
+	obj.SetLabels(objLabels)
+
+	updated, err := c.resource().Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	probe.DeletionTimestamp = deletionTime()
+	probe.UpdateTimestamp = probe.DeletionTimestamp
+	_, err = c.writeStatus(ctx, updated, *probe)
+	return err
+}
+
+// writeStatus writes the phase, status details, deletion timestamp, update
+// timestamp, status history and target statuses of a probe to the status
+// subresource. Those the probe does not have are removed.
+func (c *CRDProbeStore) writeStatus(ctx context.Context, obj *unstructured.Unstructured, probe v1.ProbeObject) (*unstructured.Unstructured, error) {
+	if err := unstructured.SetNestedField(obj.Object, string(probe.Status), "status", "phase"); err != nil {
+		return nil, fmt.Errorf("failed to set probe status: %w", err)
+	}
+	for field, value := range map[string]*string{"reason": probe.StatusReason, "message": probe.StatusMessage} {
+		if value == nil {
+			unstructured.RemoveNestedField(obj.Object, "status", field)
+			continue
+		}
+		if err := unstructured.SetNestedField(obj.Object, *value, "status", field); err != nil {
+			return nil, fmt.Errorf("failed to set probe status %s: %w", field, err)
+		}
+	}
+	if probe.DeletionTimestamp != nil {
+		if err := unstructured.SetNestedField(obj.Object, clock.Format(*probe.DeletionTimestamp), "status", "deletionTimestamp"); err != nil {
+			return nil, fmt.Errorf("failed to set probe deletion timestamp: %w", err)
+		}
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "status", "deletionTimestamp")
+	}
+	if probe.UpdateTimestamp != nil {
+		if err := unstructured.SetNestedField(obj.Object, clock.Format(*probe.UpdateTimestamp), "status", "updateTimestamp"); err != nil {
+			return nil, fmt.Errorf("failed to set probe update timestamp: %w", err)
+		}
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "status", "updateTimestamp")
+	}
+	if history := probe.StatusHistory; history != nil {
+		raw, err := json.Marshal(*history)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal probe status history: %w", err)
+		}
+		var entries []any
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal probe status history: %w", err)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, entries, "status", "history"); err != nil {
+			return nil, fmt.Errorf("failed to set probe status history: %w", err)
+		}
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "status", "history")
+	}
+	if statuses := probe.TargetStatuses; statuses != nil {
+		raw, err := json.Marshal(*statuses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal probe target statuses: %w", err)
+		}
+		var entries []any
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal probe target statuses: %w", err)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, entries, "status", "targets"); err != nil {
+			return nil, fmt.Errorf("failed to set probe target statuses: %w", err)
+		}
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "status", "targets")
+	}
+	updated, err := c.resource().UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status of probe resource %s: %w", obj.GetName(), err)
+	}
+	return updated, nil
+}
+
+func setProbeSpec(obj *unstructured.Unstructured, probe v1.ProbeObject) error {
+	spec := probeCRSpec{
+		ID:        probe.Id.String(),
+		StaticURL: probe.StaticUrl,
+	}
+	if probe.Generation != nil {
+		spec.Generation = *probe.Generation
+	}
+	if probe.AdditionalUrls != nil {
+		spec.AdditionalURLs = *probe.AdditionalUrls
+	}
+	if probe.Regions != nil {
+		spec.Regions = *probe.Regions
+	}
+	if probe.UrlHash != nil {
+		spec.URLHash = *probe.UrlHash
+	}
+	if probe.Labels != nil {
+		spec.Labels = *probe.Labels
+	}
+	if probe.Interval != nil {
+		spec.Interval = *probe.Interval
+	}
+	if probe.Timeout != nil {
+		spec.Timeout = *probe.Timeout
+	}
+	if probe.Module != nil {
+		spec.Module = string(*probe.Module)
+	}
+	if a := probe.Alerting; a != nil {
+		spec.Alerting = &probeCRAlerting{SilenceDuringMaintenance: a.SilenceDuringMaintenance}
+		if a.Severity != nil {
+			spec.Alerting.Severity = string(*a.Severity)
+		}
+		if a.RunbookUrl != nil {
+			spec.Alerting.RunbookURL = *a.RunbookUrl
+		}
+	}
+	if o := probe.Owner; o != nil {
+		spec.Owner = &probeCROwner{}
+		if o.Team != nil {
+			spec.Owner.Team = *o.Team
+		}
+		if o.EscalationContact != nil {
+			spec.Owner.EscalationContact = *o.EscalationContact
+		}
+	}
+	if a := probe.Assertions; a != nil {
+		spec.Assertions = &probeCRAssertions{TLSExpiryDays: a.TlsExpiryDays}
+		if a.StatusCodes != nil {
+			spec.Assertions.StatusCodes = *a.StatusCodes
+		}
+		if a.BodyRegex != nil {
+			spec.Assertions.BodyRegex = *a.BodyRegex
+		}
+		if a.Headers != nil {
+			for _, header := range *a.Headers {
+				h := probeCRHeaderAssertion{Name: header.Name}
+				if header.ValueRegex != nil {
+					h.ValueRegex = *header.ValueRegex
+				}
+				spec.Assertions.Headers = append(spec.Assertions.Headers, h)
+			}
+		}
+	}
+	if probe.Paused != nil {
+		spec.Paused = *probe.Paused
+	}
+	if a := probe.Auth; a != nil {
+		spec.Auth = &probeCRAuth{}
+		if a.Username != nil {
+			spec.Auth.Username = *a.Username
+		}
+		if a.Password != nil {
+			spec.Auth.Password = *a.Password
+		}
+		if a.BearerToken != nil {
+			spec.Auth.BearerToken = *a.BearerToken
+		}
+	}
+	if d := probe.Dns; d != nil {
+		spec.DNS = &probeCRDNS{QueryName: d.QueryName, RecordType: string(d.RecordType)}
+		if d.ExpectedAnswers != nil {
+			spec.DNS.ExpectedAnswers = *d.ExpectedAnswers
+		}
+		if d.Resolver != nil {
+			spec.DNS.Resolver = *d.Resolver
+		}
+	}
+	if t := probe.Tcp; t != nil {
+		spec.TCP = &probeCRTCP{Address: t.Address, TLS: t.Tls}
+	}
+	if i := probe.Icmp; i != nil {
+		spec.ICMP = &probeCRICMP{Host: i.Host, PacketCount: i.PacketCount}
+	}
+
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal probe spec: %w", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("failed to convert probe spec: %w", err)
+	}
+	return unstructured.SetNestedMap(obj.Object, fields, "spec")
+}
+
+// probeFromUnstructured decodes a Probe resource. The status subresource is
+// authoritative; the status label is used if it has not been written yet.
+func probeFromUnstructured(obj *unstructured.Unstructured) (*v1.ProbeObject, error) {
+	fields, found, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("missing spec")
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	var spec probeCRSpec
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal spec: %w", err)
+	}
+
+	id, err := uuid.Parse(spec.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid probe id %q: %w", spec.ID, err)
+	}
+
+	probe := &v1.ProbeObject{
+		Id:        id,
+		StaticUrl: spec.StaticURL,
+	}
+	if len(spec.AdditionalURLs) > 0 {
+		probe.AdditionalUrls = &spec.AdditionalURLs
+	}
+	if len(spec.Regions) > 0 {
+		probe.Regions = &spec.Regions
+	}
+	if spec.Labels != nil {
+		probeLabels := v1.LabelsSchema(spec.Labels)
+		probe.Labels = &probeLabels
+	}
+	if spec.Generation != 0 {
+		probe.Generation = &spec.Generation
+	}
+	if created := obj.GetCreationTimestamp(); !created.IsZero() {
+		creationTimestamp := created.UTC()
+		probe.CreationTimestamp = &creationTimestamp
+	}
+	if spec.URLHash != "" {
+		probe.UrlHash = &spec.URLHash
+	}
+	if spec.Interval != "" {
+		probe.Interval = &spec.Interval
+	}
+	if spec.Timeout != "" {
+		probe.Timeout = &spec.Timeout
+	}
+	if spec.Module != "" {
+		module := v1.ProbeModuleSchema(spec.Module)
+		probe.Module = &module
+	}
+	if a := spec.Alerting; a != nil {
+		probe.Alerting = &v1.AlertingSchema{SilenceDuringMaintenance: a.SilenceDuringMaintenance}
+		if a.Severity != "" {
+			severity := v1.SeveritySchema(a.Severity)
+			probe.Alerting.Severity = &severity
+		}
+		if a.RunbookURL != "" {
+			probe.Alerting.RunbookUrl = &a.RunbookURL
+		}
+	}
+	if o := spec.Owner; o != nil {
+		probe.Owner = &v1.OwnerSchema{}
+		if o.Team != "" {
+			probe.Owner.Team = &o.Team
+		}
+		if o.EscalationContact != "" {
+			probe.Owner.EscalationContact = &o.EscalationContact
+		}
+	}
+	if a := spec.Assertions; a != nil {
+		probe.Assertions = &v1.AssertionsSchema{TlsExpiryDays: a.TLSExpiryDays}
+		if len(a.StatusCodes) > 0 {
+			probe.Assertions.StatusCodes = &a.StatusCodes
+		}
+		if a.BodyRegex != "" {
+			probe.Assertions.BodyRegex = &a.BodyRegex
+		}
+		if len(a.Headers) > 0 {
+			headers := make([]v1.HeaderAssertion, 0, len(a.Headers))
+			for _, h := range a.Headers {
+				header := v1.HeaderAssertion{Name: h.Name}
+				if h.ValueRegex != "" {
+					header.ValueRegex = new(h.ValueRegex)
+				}
+				headers = append(headers, header)
+			}
+			probe.Assertions.Headers = &headers
+		}
+	}
+	if spec.Paused {
+		probe.Paused = &spec.Paused
+	}
+	if a := spec.Auth; a != nil {
+		probe.Auth = &v1.ProbeAuthSchema{}
+		if a.Username != "" {
+			probe.Auth.Username = &a.Username
+		}
+		if a.Password != "" {
+			probe.Auth.Password = &a.Password
+		}
+		if a.BearerToken != "" {
+			probe.Auth.BearerToken = &a.BearerToken
+		}
+	}
+	if d := spec.DNS; d != nil {
+		probe.Dns = &v1.DnsSchema{QueryName: d.QueryName, RecordType: v1.DnsRecordTypeSchema(d.RecordType)}
+		if len(d.ExpectedAnswers) > 0 {
+			probe.Dns.ExpectedAnswers = &d.ExpectedAnswers
+		}
+		if d.Resolver != "" {
+			probe.Dns.Resolver = &d.Resolver
+		}
+	}
+	if t := spec.TCP; t != nil {
+		probe.Tcp = &v1.TcpSchema{Address: t.Address, Tls: t.TLS}
+	}
+	if i := spec.ICMP; i != nil {
+		probe.Icmp = &v1.IcmpSchema{Host: i.Host, PacketCount: i.PacketCount}
+	}
+
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if phase == "" {
+		phase = obj.GetLabels()[probeStatusLabelKey]
+	}
+	probe.Status = v1.StatusSchema(phase)
+	if reason, _, _ := unstructured.NestedString(obj.Object, "status", "reason"); reason != "" {
+		probe.StatusReason = &reason
+	}
+	if message, _, _ := unstructured.NestedString(obj.Object, "status", "message"); message != "" {
+		probe.StatusMessage = &message
+	}
+	if deletionTimestamp, _, _ := unstructured.NestedString(obj.Object, "status", "deletionTimestamp"); deletionTimestamp != "" {
+		ts, err := clock.Parse(deletionTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deletion timestamp %q: %w", deletionTimestamp, err)
+		}
+		probe.DeletionTimestamp = &ts
+	}
+	if updateTimestamp, _, _ := unstructured.NestedString(obj.Object, "status", "updateTimestamp"); updateTimestamp != "" {
+		ts, err := clock.Parse(updateTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid update timestamp %q: %w", updateTimestamp, err)
+		}
+		probe.UpdateTimestamp = &ts
+	}
+	if entries, found, _ := unstructured.NestedSlice(obj.Object, "status", "history"); found {
+		raw, err := json.Marshal(entries)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal status history: %w", err)
+		}
+		var history []v1.StatusTransition
+		if err := json.Unmarshal(raw, &history); err != nil {
+			return nil, fmt.Errorf("invalid status history: %w", err)
+		}
+		probe.StatusHistory = &history
+	}
+	if entries, found, _ := unstructured.NestedSlice(obj.Object, "status", "targets"); found {
+		raw, err := json.Marshal(entries)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal target statuses: %w", err)
+		}
+		var statuses []v1.TargetStatus
+		if err := json.Unmarshal(raw, &statuses); err != nil {
+			return nil, fmt.Errorf("invalid target statuses: %w", err)
+		}
+		probe.TargetStatuses = &statuses
+	}
+
+	return withResourceVersion(probe, obj.GetResourceVersion()), nil
+}
@@ -0,0 +1,284 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestCRDProbeStore(objects ...runtime.Object) *CRDProbeStore {
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{ProbeGVR: "ProbeList"},
+		objects...,
+	)
+	return &CRDProbeStore{
+		Client:              client,
+		Namespace:           testNamespace,
+		StaleProbeTTL:       defaultStaleProbeTTL,
+		NoHeartbeatProbeTTL: defaultNoHeartbeatProbeTTL,
+	}
+}
+
+func makeProbeResource(t *testing.T, probe v1.ProbeObject, objLabels, annotations map[string]string, createdAt time.Time) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ProbeGVR.GroupVersion().String())
+	obj.SetKind(probeCRDKind)
+	obj.SetName(probe.Id.String())
+	obj.SetNamespace(testNamespace)
+	obj.SetLabels(objLabels)
+	obj.SetAnnotations(annotations)
+	obj.SetCreationTimestamp(metav1.NewTime(createdAt))
+	require.NoError(t, setProbeSpec(obj, probe))
+	require.NoError(t, unstructured.SetNestedField(obj.Object, string(probe.Status), "status", "phase"))
+	return obj
+}
+
+func TestNewCRDProbeStore_EmptyNamespace(t *testing.T) {
+	store, err := NewCRDProbeStore(context.Background(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), "")
+	require.Error(t, err)
+	assert.Nil(t, store)
+}
+
+func TestCRDProbeStore_CreateAndGetProbe(t *testing.T) {
+	ctx := context.Background()
+	store := newTestCRDProbeStore()
+
+	interval, timeout, module := "1m0s", "5s", v1.Tcp
+	severity, runbook, silence := v1.Critical, "https://runbooks.example.com/api", true
+	username, redacted := "prober", "REDACTED"
+	team, contact := "sre", "sre-oncall@example.com"
+	probe := v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: "https://example.com",
+		Status:    v1.Pending,
+		Labels:    &v1.LabelsSchema{"env": "prod", lastReconciledKey: "20250101T000000Z"},
+		Interval:  &interval,
+		Timeout:   &timeout,
+		Module:    &module,
+		Alerting:  &v1.AlertingSchema{Severity: &severity, RunbookUrl: &runbook, SilenceDuringMaintenance: &silence},
+		Owner:     &v1.OwnerSchema{Team: &team, EscalationContact: &contact},
+		Auth:      &v1.ProbeAuthSchema{Username: &username, Password: &redacted},
+	}
+	_, err := store.CreateProbe(ctx, probe, "test-hash")
+	require.NoError(t, err)
+
+	obj, err := store.Client.Resource(ProbeGVR).Namespace(testNamespace).Get(ctx, probe.Id.String(), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"env":                "prod",
+		baseAppLabelKey:      baseAppLabelValue,
+		probeURLHashLabelKey: "test-hash",
+		probeStatusLabelKey:  string(v1.Pending),
+	}, obj.GetLabels())
+	assert.Equal(t, "20250101T000000Z", obj.GetAnnotations()[lastReconciledKey])
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	assert.Equal(t, string(v1.Pending), phase)
+	specInterval, _, _ := unstructured.NestedString(obj.Object, "spec", "interval")
+	assert.Equal(t, "1m0s", specInterval)
+	specModule, _, _ := unstructured.NestedString(obj.Object, "spec", "module")
+	assert.Equal(t, "tcp", specModule)
+	specRunbook, _, _ := unstructured.NestedString(obj.Object, "spec", "alerting", "runbookUrl")
+	assert.Equal(t, runbook, specRunbook)
+	specContact, _, _ := unstructured.NestedString(obj.Object, "spec", "owner", "escalationContact")
+	assert.Equal(t, contact, specContact)
+	specUsername, _, _ := unstructured.NestedString(obj.Object, "spec", "auth", "username")
+	assert.Equal(t, username, specUsername)
+
+	got, err := store.GetProbe(ctx, probe.Id)
+	require.NoError(t, err)
+	generation := int64(1)
+	probe.Generation = &generation
+	require.NotNil(t, got.CreationTimestamp)
+	probe.CreationTimestamp = got.CreationTimestamp
+	require.NotNil(t, got.UpdateTimestamp)
+	probe.UpdateTimestamp = got.UpdateTimestamp
+	assert.Equal(t, probe, *got)
+
+	_, err = store.CreateProbe(ctx, probe, "test-hash")
+	assert.True(t, k8serrors.IsAlreadyExists(err))
+
+	_, err = store.GetProbe(ctx, uuid.New())
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestCRDProbeStore_CreateProbe_StatusFailure(t *testing.T) {
+	ctx := context.Background()
+	store := newTestCRDProbeStore()
+	client := store.Client.(*dynamicfake.FakeDynamicClient)
+	fail := true
+	client.PrependReactor("update", "probes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "status" && fail {
+			return true, nil, errors.New("status unavailable")
+		}
+		return false, nil, nil
+	})
+
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}
+	_, err := store.CreateProbe(ctx, probe, "test-hash")
+	require.ErrorContains(t, err, "status unavailable")
+	_, err = store.Client.Resource(ProbeGVR).Namespace(testNamespace).Get(ctx, probe.Id.String(), metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err), "the resource is removed when its status cannot be written, got %v", err)
+
+	fail = false
+	_, err = store.CreateProbe(ctx, probe, "test-hash")
+	require.NoError(t, err, "the create can be retried")
+}
+
+func TestCRDProbeStore_ListProbes(t *testing.T) {
+	ctx := context.Background()
+	prod := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/1", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "prod"}}
+	dev := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/2", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "dev"}}
+	store := newTestCRDProbeStore(
+		makeProbeResource(t, prod, map[string]string{baseAppLabelKey: baseAppLabelValue, "env": "prod"}, nil, time.Now()),
+		makeProbeResource(t, dev, map[string]string{baseAppLabelKey: baseAppLabelValue, "env": "dev"}, nil, time.Now()),
+	)
+
+	testCases := []struct {
+		name          string
+		selector      string
+		expectedCount int
+	}{
+		{name: "all probes", selector: "app=rhobs-synthetics-probe", expectedCount: 2},
+		{name: "label selector", selector: "env=prod", expectedCount: 1},
+		{name: "no matches", selector: "env=staging", expectedCount: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			probes, err := store.ListProbes(ctx, tc.selector)
+			require.NoError(t, err)
+			assert.Len(t, probes, tc.expectedCount)
+		})
+	}
+}
+
+func TestCRDProbeStore_UpdateProbe(t *testing.T) {
+	ctx := context.Background()
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending, Labels: &v1.LabelsSchema{"env": "prod"}}
+	store := newTestCRDProbeStore(makeProbeResource(t, probe,
+		map[string]string{baseAppLabelKey: baseAppLabelValue, probeStatusLabelKey: string(v1.Pending), "env": "prod"}, nil, time.Now()))
+
+	probe.Status = v1.Active
+	probe.Labels = &v1.LabelsSchema{"env": "prod", lastReconciledKey: "20250101T000000Z"}
+	updated, err := store.UpdateProbe(ctx, probe)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Active, updated.Status)
+
+	obj, err := store.Client.Resource(ProbeGVR).Namespace(testNamespace).Get(ctx, probe.Id.String(), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, string(v1.Active), obj.GetLabels()[probeStatusLabelKey])
+	assert.NotContains(t, obj.GetLabels(), lastReconciledKey)
+	assert.Equal(t, "20250101T000000Z", obj.GetAnnotations()[lastReconciledKey])
+
+	_, err = store.UpdateProbe(ctx, v1.ProbeObject{Id: uuid.New()})
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func TestCRDProbeStore_DeleteProbe(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         v1.StatusSchema
+		expectDeleted  bool
+		expectedStatus v1.StatusSchema
+	}{
+		{name: "pending probe is deleted", status: v1.Pending, expectDeleted: true},
+		{name: "active probe transitions to terminating", status: v1.Active, expectedStatus: v1.Terminating},
+		{name: "terminating probe is left alone", status: v1.Terminating, expectedStatus: v1.Terminating},
+		{name: "failed probe is deleted", status: v1.Failed, expectDeleted: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: tc.status}
+			store := newTestCRDProbeStore(makeProbeResource(t, probe,
+				map[string]string{baseAppLabelKey: baseAppLabelValue, probeStatusLabelKey: string(tc.status)}, nil, time.Now()))
+
+			require.NoError(t, store.DeleteProbe(ctx, probe.Id))
+
+			got, err := store.GetProbe(ctx, probe.Id)
+			if tc.expectDeleted {
+				assert.True(t, k8serrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, got.Status)
+		})
+	}
+}
+
+func TestCRDProbeStore_ProbeWithURLHashExists(t *testing.T) {
+	ctx := context.Background()
+	live := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/live", Status: v1.Active}
+	terminating := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/gone", Status: v1.Terminating}
+	store := newTestCRDProbeStore(
+		makeProbeResource(t, live, map[string]string{probeURLHashLabelKey: "live-hash", probeStatusLabelKey: string(v1.Active)}, nil, time.Now()),
+		makeProbeResource(t, terminating, map[string]string{probeURLHashLabelKey: "gone-hash", probeStatusLabelKey: string(v1.Terminating)}, nil, time.Now()),
+	)
+
+	exists, err := store.ProbeWithURLHashExists(ctx, "live-hash")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = store.ProbeWithURLHashExists(ctx, "gone-hash")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestCRDProbeStore_GarbageCollectStaleProbes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	baseLabels := func(status v1.StatusSchema) map[string]string {
+		return map[string]string{baseAppLabelKey: baseAppLabelValue, probeStatusLabelKey: string(status)}
+	}
+
+	fresh := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/fresh", Status: v1.Active}
+	stale := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/stale", Status: v1.Active}
+	staleTerminating := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/stale-terminating", Status: v1.Terminating}
+	noHeartbeat := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/no-heartbeat", Status: v1.Active}
+
+	store := newTestCRDProbeStore(
+		makeProbeResource(t, fresh, baseLabels(v1.Active),
+			map[string]string{lastReconciledKey: now.Format("20060102T150405Z")}, now),
+		makeProbeResource(t, stale, baseLabels(v1.Active),
+			map[string]string{lastReconciledKey: now.Add(-time.Hour).Format("20060102T150405Z")}, now.Add(-2*time.Hour)),
+		makeProbeResource(t, staleTerminating, baseLabels(v1.Terminating),
+			map[string]string{lastReconciledKey: now.Add(-time.Hour).Format("20060102T150405Z")}, now.Add(-2*time.Hour)),
+		makeProbeResource(t, noHeartbeat, baseLabels(v1.Active), nil, now.Add(-48*time.Hour)),
+	)
+
+	count, err := store.GarbageCollectStaleProbes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	got, err := store.GetProbe(ctx, fresh.Id)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Active, got.Status)
+
+	got, err = store.GetProbe(ctx, stale.Id)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Terminating, got.Status)
+
+	got, err = store.GetProbe(ctx, noHeartbeat.Id)
+	require.NoError(t, err)
+	assert.Equal(t, v1.Terminating, got.Status)
+
+	_, err = store.GetProbe(ctx, staleTerminating.Id)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
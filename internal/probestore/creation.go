@@ -0,0 +1,22 @@
+package probestore
+
+import (
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// withCreationTimestamp sets the creation and update timestamps of a probe
+// being created to the current time, truncated to seconds like deletion
+// timestamps. The timestamps given by the caller are ignored.
+func withCreationTimestamp(probe *v1.ProbeObject) {
+	now := clock.Stamp()
+	probe.CreationTimestamp = &now
+	updated := now
+	probe.UpdateTimestamp = &updated
+}
+
+// keepCreationTimestamp sets the creation timestamp of a probe being updated
+// to that of the stored probe. The timestamp given by the caller is ignored.
+func keepCreationTimestamp(probe *v1.ProbeObject, stored v1.ProbeObject) {
+	probe.CreationTimestamp = stored.CreationTimestamp
+}
@@ -0,0 +1,71 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestProbeCreationTimestamp(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			requested := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending, CreationTimestamp: &requested}, "hash")
+			require.NoError(t, err)
+			require.NotNil(t, created.CreationTimestamp)
+			assert.WithinDuration(t, time.Now(), *created.CreationTimestamp, 2*time.Second, "the caller's timestamp is ignored")
+
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			require.NotNil(t, stored.CreationTimestamp)
+			assert.True(t, created.CreationTimestamp.Equal(*stored.CreationTimestamp))
+
+			stored.Status = v1.Active
+			stored.CreationTimestamp = &requested
+			updated, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			require.NotNil(t, updated.CreationTimestamp)
+			assert.True(t, created.CreationTimestamp.Equal(*updated.CreationTimestamp), "updates keep the creation time")
+		})
+	}
+}
+
+func TestProbeCreationTimestamp_LegacyConfigMap(t *testing.T) {
+	probeID := uuid.New()
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "probe-config-" + probeID.String(),
+			Namespace:         testNamespace,
+			Labels:            map[string]string{baseAppLabelKey: baseAppLabelValue},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Data: map[string]string{"probe-config.json": `{"id":"` + probeID.String() + `","static_url":"https://example.com","status":"active"}`},
+	})
+	store, err := NewKubernetesProbeStore(context.Background(), clientset, testNamespace)
+	require.NoError(t, err)
+
+	probe, err := store.GetProbe(context.Background(), probeID)
+	require.NoError(t, err)
+	require.NotNil(t, probe.CreationTimestamp, "payloads without one use the ConfigMap's")
+	assert.True(t, created.Equal(*probe.CreationTimestamp))
+
+	probes, err := store.ListProbes(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	require.NotNil(t, probes[0].CreationTimestamp)
+	assert.True(t, created.Equal(*probes[0].CreationTimestamp))
+}
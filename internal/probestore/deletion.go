@@ -0,0 +1,30 @@
+package probestore
+
+import (
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// deletionTime returns the deletion timestamp for a probe becoming terminating
+// now. It is truncated to seconds so that every backend stores the same value.
+func deletionTime() *time.Time {
+	now := clock.Stamp()
+	return &now
+}
+
+// withDeletionTimestamp sets the deletion timestamp of a probe being updated:
+// a probe that stays terminating keeps the stored timestamp, one that becomes
+// terminating gets the current time, and any other probe has none. The
+// timestamp given by the caller is ignored.
+func withDeletionTimestamp(probe *v1.ProbeObject, stored v1.ProbeObject) {
+	switch {
+	case probe.Status != v1.Terminating:
+		probe.DeletionTimestamp = nil
+	case stored.Status == v1.Terminating && stored.DeletionTimestamp != nil:
+		probe.DeletionTimestamp = stored.DeletionTimestamp
+	default:
+		probe.DeletionTimestamp = deletionTime()
+	}
+}
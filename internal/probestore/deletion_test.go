@@ -0,0 +1,53 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeDeletionTimestamp(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}, "hash")
+			require.NoError(t, err)
+			assert.Nil(t, created.DeletionTimestamp)
+
+			setStatus := func(status v1.StatusSchema) *v1.ProbeObject {
+				t.Helper()
+				probe, err := store.GetProbe(ctx, created.Id)
+				require.NoError(t, err)
+				probe.Status = status
+				_, err = store.UpdateProbe(ctx, *probe)
+				require.NoError(t, err)
+				stored, err := store.GetProbe(ctx, created.Id)
+				require.NoError(t, err)
+				return stored
+			}
+
+			terminating := setStatus(v1.Terminating)
+			require.NotNil(t, terminating.DeletionTimestamp, "becoming terminating records the time")
+			assert.WithinDuration(t, time.Now(), *terminating.DeletionTimestamp, 2*time.Second)
+
+			again := setStatus(v1.Terminating)
+			require.NotNil(t, again.DeletionTimestamp)
+			assert.True(t, terminating.DeletionTimestamp.Equal(*again.DeletionTimestamp), "staying terminating keeps the time")
+
+			assert.Nil(t, setStatus(v1.Active).DeletionTimestamp, "leaving terminating clears the time")
+
+			require.NoError(t, store.DeleteProbe(ctx, created.Id))
+			deleted, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, v1.Terminating, deleted.Status)
+			assert.NotNil(t, deleted.DeletionTimestamp, "deleting an active probe records the time")
+		})
+	}
+}
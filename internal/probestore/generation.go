@@ -0,0 +1,87 @@
+package probestore
+
+import (
+	"reflect"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// probeSpec is the part of a probe that agents apply. Changing any of it
+// bumps the probe's generation.
+type probeSpec struct {
+	StaticURL      string
+	AdditionalURLs []string
+	Regions        []string
+	Labels         map[string]string
+	Interval       *string
+	Timeout        *string
+	Module         *v1.ProbeModuleSchema
+	Alerting       *v1.AlertingSchema
+	Owner          *v1.OwnerSchema
+	Assertions     *v1.AssertionsSchema
+	Auth           *v1.ProbeAuthSchema
+	DNS            *v1.DnsSchema
+	TCP            *v1.TcpSchema
+	ICMP           *v1.IcmpSchema
+	Paused         bool
+}
+
+func specOf(probe v1.ProbeObject) probeSpec {
+	spec := probeSpec{
+		StaticURL:  probe.StaticUrl,
+		Labels:     map[string]string{},
+		Interval:   probe.Interval,
+		Timeout:    probe.Timeout,
+		Module:     probe.Module,
+		Alerting:   probe.Alerting,
+		Owner:      probe.Owner,
+		Assertions: probe.Assertions,
+		Auth:       probe.Auth,
+		DNS:        probe.Dns,
+		TCP:        probe.Tcp,
+		ICMP:       probe.Icmp,
+		Paused:     probe.Paused != nil && *probe.Paused,
+	}
+	if probe.AdditionalUrls != nil && len(*probe.AdditionalUrls) > 0 {
+		spec.AdditionalURLs = *probe.AdditionalUrls
+	}
+	if probe.Regions != nil && len(*probe.Regions) > 0 {
+		spec.Regions = *probe.Regions
+	}
+	// The status label mirrors the status, and the heartbeat changes without
+	// the configuration changing, so system labels are left out.
+	if probe.Labels != nil {
+		for key, val := range *probe.Labels {
+			if !isSystemLabel(key) {
+				spec.Labels[key] = val
+			}
+		}
+	}
+	return spec
+}
+
+// generationOf returns the probe's generation. Probes stored before
+// generations were tracked are at generation 1.
+func generationOf(probe v1.ProbeObject) int64 {
+	if probe.Generation == nil || *probe.Generation < 1 {
+		return 1
+	}
+	return *probe.Generation
+}
+
+// withFirstGeneration sets the generation of a probe being created.
+func withFirstGeneration(probe *v1.ProbeObject) {
+	generation := int64(1)
+	probe.Generation = &generation
+}
+
+// withNextGeneration sets the generation of a probe being updated: that of
+// the stored probe, incremented if the update changes the probe's spec. The
+// generation given by the caller is ignored.
+func withNextGeneration(probe *v1.ProbeObject, stored v1.ProbeObject) {
+	generation := generationOf(stored)
+	if !reflect.DeepEqual(specOf(*probe), specOf(stored)) {
+		generation++
+	}
+	probe.Generation = &generation
+}
@@ -0,0 +1,275 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStores returns constructors of the stores that can run without external
+// services, by engine name.
+func testStores() map[string]func(t *testing.T) ProbeStorage {
+	stores := map[string]func(t *testing.T) ProbeStorage{
+		"local": func(t *testing.T) ProbeStorage {
+			store, err := NewLocalProbeStoreWithDir(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+		"memory": func(t *testing.T) ProbeStorage {
+			return NewMemoryProbeStore()
+		},
+		"s3": func(t *testing.T) ProbeStorage {
+			store, _ := newTestS3ProbeStore(t)
+			return store
+		},
+		"redis": func(t *testing.T) ProbeStorage {
+			store, _ := newTestRedisProbeStore(t)
+			return store
+		},
+	}
+	addKubernetesTestStores(stores)
+	return stores
+}
+
+func TestProbeGeneration(t *testing.T) {
+	interval := "1m"
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{
+				Id:        uuid.New(),
+				StaticUrl: "https://example.com",
+				Labels:    &v1.LabelsSchema{"env": "prod"},
+				Status:    v1.Pending,
+			}, "hash")
+			require.NoError(t, err)
+			require.NotNil(t, created.Generation)
+			assert.Equal(t, int64(1), *created.Generation)
+
+			steps := []struct {
+				name     string
+				change   func(p *v1.ProbeObject)
+				expected int64
+			}{
+				{name: "status change", change: func(p *v1.ProbeObject) { p.Status = v1.Active }, expected: 1},
+				{name: "heartbeat", change: func(p *v1.ProbeObject) { (*p.Labels)[lastReconciledKey] = "2025-01-01T00:00:00Z" }, expected: 1},
+				{name: "label change", change: func(p *v1.ProbeObject) { (*p.Labels)["env"] = "stage" }, expected: 2},
+				{name: "interval change", change: func(p *v1.ProbeObject) { p.Interval = &interval }, expected: 3},
+				{name: "unchanged spec", change: func(p *v1.ProbeObject) {}, expected: 3},
+				{name: "generation given by the caller", change: func(p *v1.ProbeObject) {
+					generation := int64(100)
+					p.Generation = &generation
+				}, expected: 3},
+				{name: "additional url", change: func(p *v1.ProbeObject) { p.AdditionalUrls = &v1.AdditionalUrlsSchema{"https://console.example.com"} }, expected: 4},
+				{name: "target status", change: func(p *v1.ProbeObject) {
+					p.TargetStatuses = &v1.TargetStatusesSchema{{Url: "https://console.example.com", State: v1.Down}}
+				}, expected: 4},
+			}
+			for _, step := range steps {
+				probe, err := store.GetProbe(ctx, created.Id)
+				require.NoError(t, err)
+				if probe.Labels == nil {
+					probe.Labels = &v1.LabelsSchema{}
+				}
+				step.change(probe)
+
+				_, err = store.UpdateProbe(ctx, *probe)
+				require.NoError(t, err, step.name)
+
+				stored, err := store.GetProbe(ctx, created.Id)
+				require.NoError(t, err, step.name)
+				require.NotNil(t, stored.Generation, step.name)
+				assert.Equal(t, step.expected, *stored.Generation, step.name)
+			}
+		})
+	}
+}
+
+func TestGenerationOf(t *testing.T) {
+	zero, three := int64(0), int64(3)
+	assert.Equal(t, int64(1), generationOf(v1.ProbeObject{}), "probes stored before generations were tracked")
+	assert.Equal(t, int64(1), generationOf(v1.ProbeObject{Generation: &zero}))
+	assert.Equal(t, int64(3), generationOf(v1.ProbeObject{Generation: &three}))
+}
+
+func TestProbePaused(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{
+				Id:        uuid.New(),
+				StaticUrl: "https://example.com",
+				Status:    v1.Active,
+			}, "hash")
+			require.NoError(t, err)
+			assert.Nil(t, created.Paused)
+
+			created.Paused = new(true)
+			_, err = store.UpdateProbe(ctx, *created)
+			require.NoError(t, err)
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, new(true), stored.Paused)
+			assert.Equal(t, int64(2), *stored.Generation, "pausing changes the spec agents apply")
+
+			stored.Paused = nil
+			_, err = store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			stored, err = store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Nil(t, stored.Paused)
+			assert.Equal(t, int64(3), *stored.Generation)
+		})
+	}
+}
+
+func TestProbeTargets(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			additional := v1.AdditionalUrlsSchema{"https://console.example.com"}
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{
+				Id:             uuid.New(),
+				StaticUrl:      "https://example.com",
+				AdditionalUrls: &additional,
+				Status:         v1.Active,
+			}, "hash")
+			require.NoError(t, err)
+			assert.Equal(t, &additional, created.AdditionalUrls)
+
+			checked := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			statuses := v1.TargetStatusesSchema{
+				{Url: "https://example.com", State: v1.Up, Timestamp: &checked},
+				{Url: "https://console.example.com", State: v1.Down, Message: new("connection refused"), Timestamp: &checked},
+			}
+			created.TargetStatuses = &statuses
+			_, err = store.UpdateProbe(ctx, *created)
+			require.NoError(t, err)
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &additional, stored.AdditionalUrls)
+			assert.Equal(t, &statuses, stored.TargetStatuses)
+			assert.Equal(t, created.UpdateTimestamp, stored.UpdateTimestamp, "reports leave the update timestamp unchanged")
+		})
+	}
+}
+
+func TestProbeDNS(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			dns := v1.DnsSchema{
+				QueryName:       "api.example.com",
+				RecordType:      v1.A,
+				ExpectedAnswers: &[]string{"203.0.113.7"},
+				Resolver:        new("10.0.0.10:53"),
+			}
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{
+				Id:        uuid.New(),
+				StaticUrl: dns.URL(),
+				Module:    new(v1.Dns),
+				Dns:       &dns,
+				Status:    v1.Active,
+			}, "hash")
+			require.NoError(t, err)
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &dns, stored.Dns)
+
+			answers := []string{"203.0.113.7", "203.0.113.8"}
+			stored.Dns = &v1.DnsSchema{QueryName: dns.QueryName, RecordType: dns.RecordType, ExpectedAnswers: &answers, Resolver: dns.Resolver}
+			updated, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			assert.Equal(t, *created.Generation+1, *updated.Generation, "changing the expected answers bumps the generation")
+			assert.Equal(t, &answers, updated.Dns.ExpectedAnswers)
+		})
+	}
+}
+
+func TestProbeTCPAndICMP(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			tcp := v1.TcpSchema{Address: "db.example.com:5432", Tls: new(true)}
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: tcp.URL(), Module: new(v1.Tcp), Tcp: &tcp, Status: v1.Active}, "tcp")
+			require.NoError(t, err)
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &tcp, stored.Tcp)
+
+			icmp := v1.IcmpSchema{Host: "203.0.113.7", PacketCount: new(3)}
+			created, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: icmp.URL(), Module: new(v1.Icmp), Icmp: &icmp, Status: v1.Active}, "icmp")
+			require.NoError(t, err)
+			stored, err = store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &icmp, stored.Icmp)
+
+			stored.Icmp = &v1.IcmpSchema{Host: icmp.Host, PacketCount: new(5)}
+			updated, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			assert.Equal(t, *created.Generation+1, *updated.Generation, "changing the packet count bumps the generation")
+		})
+	}
+}
+
+func TestProbeAssertions(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			assertions := v1.AssertionsSchema{
+				StatusCodes:   &[]v1.StatusCodeRangeSchema{"200-299", "301"},
+				BodyRegex:     new("ok"),
+				Headers:       &[]v1.HeaderAssertion{{Name: "Content-Type", ValueRegex: new("^application/json")}, {Name: "X-Request-Id"}},
+				TlsExpiryDays: new(14),
+			}
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Assertions: &assertions, Status: v1.Active}, "hash")
+			require.NoError(t, err)
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &assertions, stored.Assertions)
+
+			stored.Assertions = &v1.AssertionsSchema{BodyRegex: new("healthy")}
+			updated, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			assert.Equal(t, *created.Generation+1, *updated.Generation, "changing the assertions bumps the generation")
+		})
+	}
+}
+
+func TestProbeRegions(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			regions := v1.RegionsSchema{"us-east-1", "eu-west-1"}
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Regions: &regions, Status: v1.Active}, "hash")
+			require.NoError(t, err)
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &regions, stored.Regions)
+
+			stored.Regions = &v1.RegionsSchema{"us-east-1"}
+			updated, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			assert.Equal(t, *created.Generation+1, *updated.Generation, "changing the regions bumps the generation")
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package probestore
+
+import (
+	"log/slog"
+	"os"
+	"time"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+const (
+	// lastReconciledKey is the key used to stamp a heartbeat timestamp on each
+	// probe ConfigMap during reconciliation. Stored as an annotation (not a label)
+	// to avoid Prometheus metric label churn.
+	lastReconciledKey = LastReconciledLabel
+
+	// lastReconciledLayout is the time layout of the last-reconciled heartbeat.
+	lastReconciledLayout = "20060102T150405Z"
+
+	// defaultStaleProbeTTL is how long a probe can go without being reconciled
+	// before the GC loop considers it stale and deletes it.
+	// Override with PROBE_STALE_TTL env var (e.g., "15m", "1h").
+	defaultStaleProbeTTL = 15 * time.Minute
+
+	// defaultNoHeartbeatProbeTTL is how long a probe can exist without ever
+	// receiving a last-reconciled heartbeat before GC deletes it.
+	// Override with PROBE_UNLABELED_TTL env var (e.g., "24h", "48h").
+	defaultNoHeartbeatProbeTTL = 24 * time.Hour
+)
+
+// staleProbeTTLsFromEnv returns the GC TTLs, honouring the PROBE_STALE_TTL and
+// PROBE_UNLABELED_TTL overrides. Invalid values fall back to the defaults.
+func staleProbeTTLsFromEnv() (staleTTL, noHeartbeatTTL time.Duration) {
+	staleTTL = defaultStaleProbeTTL
+	if v := os.Getenv("PROBE_STALE_TTL"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Warn("Invalid PROBE_STALE_TTL, using default", "value", v, "default", defaultStaleProbeTTL, "error", err)
+		} else {
+			staleTTL = parsed
+			slog.Info("Using custom PROBE_STALE_TTL", "ttl", staleTTL)
+		}
+	}
+	noHeartbeatTTL = defaultNoHeartbeatProbeTTL
+	if v := os.Getenv("PROBE_UNLABELED_TTL"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			slog.Warn("Invalid PROBE_UNLABELED_TTL, using default", "value", v, "default", defaultNoHeartbeatProbeTTL, "error", err)
+		} else {
+			noHeartbeatTTL = parsed
+			slog.Info("Using custom PROBE_UNLABELED_TTL", "ttl", noHeartbeatTTL)
+		}
+	}
+	return staleTTL, noHeartbeatTTL
+}
+
+// LastReconciledLabel is the label the last-reconciled heartbeat of a probe is
+// exposed under.
+const LastReconciledLabel = "last-reconciled"
+
+// LastReconciled returns the time of the probe's last-reconciled heartbeat,
+// and false if it has none or it cannot be parsed.
+func LastReconciled(probe v1.ProbeObject) (time.Time, bool) {
+	if probe.Labels == nil {
+		return time.Time{}, false
+	}
+	value, ok := (*probe.Labels)[lastReconciledKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	lastReconciled, err := time.Parse(lastReconciledLayout, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return lastReconciled, true
+}
+
+// isSystemLabel reports whether key is managed by the store rather than set
+// by callers.
+func isSystemLabel(key string) bool {
+	switch key {
+	case baseAppLabelKey, probeStatusLabelKey, probeURLHashLabelKey, lastReconciledKey:
+		return true
+	}
+	return false
+}
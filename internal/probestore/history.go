@@ -0,0 +1,78 @@
+package probestore
+
+import (
+	"context"
+	"slices"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// MaxStatusHistory is the number of status changes kept with each probe.
+const MaxStatusHistory = 20
+
+// deletionReason is the reason recorded for probes DeleteProbe makes
+// terminating.
+const deletionReason = "deleted, waiting for agent cleanup"
+
+type statusChangeKey struct{}
+
+// statusChange describes the status changes made under a context.
+type statusChange struct {
+	actor  string
+	reason string
+}
+
+// WithStatusChange returns a context under which the status changes made by
+// UpdateProbe, DeleteProbe and GarbageCollectStaleProbes are recorded in the
+// probe's history as made by actor for reason. Either may be empty; stores
+// give the reason of the changes they decide on themselves.
+func WithStatusChange(ctx context.Context, actor, reason string) context.Context {
+	return context.WithValue(ctx, statusChangeKey{}, statusChange{actor: actor, reason: reason})
+}
+
+// withStatusReason returns a context under which status changes are recorded
+// for reason, unless WithStatusChange gave one.
+func withStatusReason(ctx context.Context, reason string) context.Context {
+	change, _ := ctx.Value(statusChangeKey{}).(statusChange)
+	if change.reason != "" {
+		return ctx
+	}
+	change.reason = reason
+	return context.WithValue(ctx, statusChangeKey{}, change)
+}
+
+// withStatusHistory sets the status history of a probe being updated: that of
+// the stored probe, with the change of status the update makes, if any. A
+// stored probe without a status, whose payload could not be decoded, has no
+// change recorded. The history given by the caller is ignored.
+func withStatusHistory(ctx context.Context, probe *v1.ProbeObject, stored v1.ProbeObject) {
+	probe.StatusHistory = stored.StatusHistory
+	if stored.Status != "" && probe.Status != stored.Status {
+		recordStatusChange(ctx, probe, stored.Status)
+	}
+}
+
+// recordStatusChange adds the change of a probe from status from to its
+// current status to its history, as made by the actor and for the reason set
+// on ctx, dropping the oldest changes beyond MaxStatusHistory.
+func recordStatusChange(ctx context.Context, probe *v1.ProbeObject, from v1.StatusSchema) {
+	change, _ := ctx.Value(statusChangeKey{}).(statusChange)
+	transition := v1.StatusTransition{From: from, To: probe.Status, Timestamp: clock.Stamp()}
+	if change.actor != "" {
+		transition.Actor = &change.actor
+	}
+	if change.reason != "" {
+		transition.Reason = &change.reason
+	}
+
+	var history []v1.StatusTransition
+	if probe.StatusHistory != nil {
+		history = slices.Clone(*probe.StatusHistory)
+	}
+	history = append(history, transition)
+	if len(history) > MaxStatusHistory {
+		history = history[len(history)-MaxStatusHistory:]
+	}
+	probe.StatusHistory = &history
+}
@@ -0,0 +1,81 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeStatusHistory(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+			require.NoError(t, err)
+			assert.Nil(t, created.StatusHistory)
+
+			update := func(ctx context.Context, status v1.StatusSchema) []v1.StatusTransition {
+				t.Helper()
+				probe, err := store.GetProbe(ctx, created.Id)
+				require.NoError(t, err)
+				probe.Status = status
+				probe.StatusHistory = nil
+				_, err = store.UpdateProbe(ctx, *probe)
+				require.NoError(t, err)
+				stored, err := store.GetProbe(ctx, created.Id)
+				require.NoError(t, err)
+				require.NotNil(t, stored.StatusHistory)
+				return *stored.StatusHistory
+			}
+
+			history := update(WithStatusChange(ctx, "agent-1", "cluster unreachable"), v1.Failed)
+			require.Len(t, history, 1)
+			assert.Equal(t, v1.Pending, history[0].From)
+			assert.Equal(t, v1.Failed, history[0].To)
+			assert.Equal(t, "agent-1", *history[0].Actor)
+			assert.Equal(t, "cluster unreachable", *history[0].Reason)
+			assert.False(t, history[0].Timestamp.IsZero())
+
+			history = update(ctx, v1.Failed)
+			assert.Len(t, history, 1, "updates keeping the status add nothing")
+
+			history = update(WithStatusChange(ctx, "agent-1", ""), v1.Active)
+			require.Len(t, history, 2)
+			assert.Equal(t, v1.Active, history[1].To)
+			assert.Nil(t, history[1].Reason)
+
+			require.NoError(t, store.DeleteProbe(WithStatusChange(ctx, "operator", ""), created.Id))
+			deleted, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			require.NotNil(t, deleted.StatusHistory)
+			require.Len(t, *deleted.StatusHistory, 3)
+			last := (*deleted.StatusHistory)[2]
+			assert.Equal(t, v1.Active, last.From)
+			assert.Equal(t, v1.Terminating, last.To)
+			assert.Equal(t, "operator", *last.Actor)
+			assert.Equal(t, deletionReason, *last.Reason)
+		})
+	}
+}
+
+func TestRecordStatusChangeBound(t *testing.T) {
+	probe := v1.ProbeObject{Status: v1.Pending}
+	statuses := []v1.StatusSchema{v1.Active, v1.Failed}
+	for i := range MaxStatusHistory + 5 {
+		from := probe.Status
+		probe.Status = statuses[i%2]
+		recordStatusChange(withStatusReason(context.Background(), "flapping"), &probe, from)
+	}
+	require.Len(t, *probe.StatusHistory, MaxStatusHistory)
+	assert.Equal(t, probe.Status, (*probe.StatusHistory)[MaxStatusHistory-1].To, "the newest change is kept last")
+	assert.Equal(t, "flapping", *(*probe.StatusHistory)[0].Reason)
+
+	ctx := WithStatusChange(context.Background(), "agent-1", "given")
+	assert.Equal(t, ctx, withStatusReason(ctx, "default"), "a given reason takes precedence")
+}
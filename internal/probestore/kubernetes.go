@@ -1,14 +1,16 @@
+//go:build !nokube
+
 package probestore
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
 	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,29 +19,30 @@ import (
 
 const (
 	probeConfigMapNameFormat = "probe-config-%s"
-
-	// lastReconciledKey is the key used to stamp a heartbeat timestamp on each
-	// probe ConfigMap during reconciliation. Stored as an annotation (not a label)
-	// to avoid Prometheus metric label churn.
-	lastReconciledKey = "last-reconciled"
-
-	// defaultStaleProbeTTL is how long a probe can go without being reconciled
-	// before the GC loop considers it stale and deletes it.
-	// Override with PROBE_STALE_TTL env var (e.g., "15m", "1h").
-	defaultStaleProbeTTL = 15 * time.Minute
-
-	// defaultNoHeartbeatProbeTTL is how long a probe can exist without ever
-	// receiving a last-reconciled heartbeat before GC deletes it.
-	// Override with PROBE_UNLABELED_TTL env var (e.g., "24h", "48h").
-	defaultNoHeartbeatProbeTTL = 24 * time.Hour
 )
 
+// ConfigMapReference returns a reference to the ConfigMap a
+// KubernetesProbeStore keeps the probe in.
+func ConfigMapReference(namespace string, probeID uuid.UUID) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Namespace:  namespace,
+		Name:       fmt.Sprintf(probeConfigMapNameFormat, probeID),
+	}
+}
+
 // KubernetesProbeStore implements the ProbeStorage interface using Kubernetes ConfigMaps.
 type KubernetesProbeStore struct {
-	Client             kubernetes.Interface
-	Namespace          string
-	StaleProbeTTL      time.Duration
+	Client    kubernetes.Interface
+	Namespace string
+	// ShardNamespaces spreads the probe ConfigMaps over several namespaces,
+	// each probe being created in the one its ID hashes to. Namespace keeps
+	// the API keys and tombstones, and the probes created before sharding.
+	ShardNamespaces     []string
+	StaleProbeTTL       time.Duration
 	NoHeartbeatProbeTTL time.Duration
+	TombstoneTTL        time.Duration
 }
 
 // NewKubernetesProbeStore creates a new KubernetesProbeStore.
@@ -47,37 +50,42 @@ type KubernetesProbeStore struct {
 // RBAC permissions for the service account only allow for namespaced resource access,
 // so a cluster-level check for a namespace is not possible and also redundant.
 func NewKubernetesProbeStore(ctx context.Context, client kubernetes.Interface, namespace string) (*KubernetesProbeStore, error) {
-	staleTTL := defaultStaleProbeTTL
-	if v := os.Getenv("PROBE_STALE_TTL"); v != "" {
-		parsed, err := time.ParseDuration(v)
-		if err != nil {
-			log.Printf("Warning: invalid PROBE_STALE_TTL %q, using default %s: %v", v, defaultStaleProbeTTL, err)
-		} else {
-			staleTTL = parsed
-			log.Printf("Using custom PROBE_STALE_TTL: %s", staleTTL)
-		}
+	return NewShardedKubernetesProbeStore(ctx, client, namespace, nil)
+}
+
+// NewShardedKubernetesProbeStore creates a KubernetesProbeStore that spreads
+// the probes over the shard namespaces. The order of the shards decides
+// where probes go; probes created before a change are still found.
+func NewShardedKubernetesProbeStore(ctx context.Context, client kubernetes.Interface, namespace string, shards []string) (*KubernetesProbeStore, error) {
+	if namespace == "" {
+		return nil, fmt.Errorf("kubernetes namespace cannot be empty")
 	}
-	noHeartbeatTTL := defaultNoHeartbeatProbeTTL
-	if v := os.Getenv("PROBE_UNLABELED_TTL"); v != "" {
-		parsed, err := time.ParseDuration(v)
-		if err != nil {
-			log.Printf("Warning: invalid PROBE_UNLABELED_TTL %q, using default %s: %v", v, defaultNoHeartbeatProbeTTL, err)
-		} else {
-			noHeartbeatTTL = parsed
-			log.Printf("Using custom PROBE_UNLABELED_TTL: %s", noHeartbeatTTL)
-		}
+	if err := validateShardNamespaces(shards); err != nil {
+		return nil, err
 	}
-	log.Printf("Initializing Kubernetes probe store in namespace %q (stale TTL: %s, no-heartbeat TTL: %s)", namespace, staleTTL, noHeartbeatTTL)
+	staleTTL, noHeartbeatTTL := staleProbeTTLsFromEnv()
+	slog.InfoContext(ctx, "Initializing Kubernetes probe store", "namespace", namespace, "shard_namespaces", shards, "stale_ttl", staleTTL, "no_heartbeat_ttl", noHeartbeatTTL)
 	return &KubernetesProbeStore{
-		Client:             client,
-		Namespace:          namespace,
-		StaleProbeTTL:      staleTTL,
+		Client:              client,
+		Namespace:           namespace,
+		ShardNamespaces:     shards,
+		StaleProbeTTL:       staleTTL,
 		NoHeartbeatProbeTTL: noHeartbeatTTL,
+		TombstoneTTL:        tombstoneTTLFromEnv(),
 	}, nil
 }
 
+// CheckHealth checks that config maps can be listed in every probe
+// namespace, fetching at most one from each.
+func (k *KubernetesProbeStore) CheckHealth(ctx context.Context) error {
+	if _, err := k.listProbeConfigMaps(ctx, metav1.ListOptions{Limit: 1}); err != nil {
+		return fmt.Errorf("failed to list config maps: %w", err)
+	}
+	return nil
+}
+
 func (k *KubernetesProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
-	configMaps, err := k.Client.CoreV1().ConfigMaps(k.Namespace).List(ctx, metav1.ListOptions{
+	configMaps, err := k.listProbeConfigMaps(ctx, metav1.ListOptions{
 		LabelSelector: selector,
 	})
 	if err != nil {
@@ -85,23 +93,22 @@ func (k *KubernetesProbeStore) ListProbes(ctx context.Context, selector string)
 	}
 
 	probes := []v1.ProbeObject{}
-	for _, cm := range configMaps.Items {
+	for _, cm := range configMaps {
 		probe := v1.ProbeObject{}
 		if probeData, ok := cm.Data["probe-config.json"]; ok {
 			err := json.Unmarshal([]byte(probeData), &probe)
 			if err != nil {
-				log.Printf("Error unmarshaling probe from configmap %s: %v", cm.Name, err)
+				slog.ErrorContext(ctx, "Error unmarshaling probe from configmap", "configmap", cm.Name, "error", err)
 				continue // Or handle error more gracefully
 			}
-			probes = append(probes, probe)
+			probes = append(probes, *withResourceVersion(withConfigMapCreationTimestamp(&probe, &cm), cm.ResourceVersion))
 		}
 	}
 	return probes, nil
 }
 
 func (k *KubernetesProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
-	configMapName := fmt.Sprintf(probeConfigMapNameFormat, probeID)
-	cm, err := k.Client.CoreV1().ConfigMaps(k.Namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	_, cm, err := k.probeConfigMap(ctx, probeID)
 	if err != nil {
 		return nil, err // Pass the error up, including not found errors
 	}
@@ -111,10 +118,28 @@ func (k *KubernetesProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal probe from configmap: %w", err)
 	}
-	return probe, nil
+	return withResourceVersion(withConfigMapCreationTimestamp(probe, cm), cm.ResourceVersion), nil
+}
+
+// withConfigMapCreationTimestamp gives a probe whose payload predates the
+// creation timestamp the creation time of its ConfigMap.
+func withConfigMapCreationTimestamp(probe *v1.ProbeObject, cm *corev1.ConfigMap) *v1.ProbeObject {
+	if probe.CreationTimestamp == nil && !cm.CreationTimestamp.IsZero() {
+		created := cm.CreationTimestamp.UTC()
+		probe.CreationTimestamp = &created
+	}
+	return probe
 }
 
 func (k *KubernetesProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	// The resource version belongs to the ConfigMap, not its payload.
+	probe.ResourceVersion = nil
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
 	payloadBytes, err := json.Marshal(probe)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal payload: %w", err)
@@ -137,10 +162,11 @@ func (k *KubernetesProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeOb
 	cmLabels[probeURLHashLabelKey] = urlHashString
 	cmLabels[probeStatusLabelKey] = string(probe.Status)
 
+	namespace := k.probeNamespace(probe.Id)
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        configMapName,
-			Namespace:   k.Namespace,
+			Namespace:   namespace,
 			Labels:      cmLabels,
 			Annotations: cmAnnotations,
 		},
@@ -149,31 +175,51 @@ func (k *KubernetesProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeOb
 		},
 	}
 
-	_, err = k.Client.CoreV1().ConfigMaps(k.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	created, err := k.Client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: Tune logging level for this
-	log.Printf("Created probe %s with URL hash %s", probe.Id.String(), urlHashString)
-	return &probe, nil
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	return withResourceVersion(&probe, created.ResourceVersion), nil
 }
 
 func (k *KubernetesProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
 	configMapName := fmt.Sprintf(probeConfigMapNameFormat, probe.Id)
 
 	// We need to fetch the existing ConfigMap to get its resource version for the update.
-	cm, err := k.Client.CoreV1().ConfigMaps(k.Namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	configMaps, cm, err := k.probeConfigMap(ctx, probe.Id)
 	if err != nil {
 		return nil, err // Let the caller handle not found errors
 	}
+	if err := checkResourceVersion(ctx, probe.Id, cm.ResourceVersion); err != nil {
+		return nil, err
+	}
+
+	// A payload that cannot be decoded restarts the generation count, which
+	// agents see as a change.
+	var stored v1.ProbeObject
+	_ = json.Unmarshal([]byte(cm.Data["probe-config.json"]), &stored)
+	withConfigMapCreationTimestamp(&stored, cm)
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+	withNextGeneration(&probe, stored)
+	keepCreationTimestamp(&probe, stored)
+	withUpdateTimestamp(&probe, stored)
+	withDeletionTimestamp(&probe, stored)
+	withStatusHistory(ctx, &probe, stored)
+	withURLHash(&probe, stored)
 
 	// Marshal the updated probe object
+	probe.ResourceVersion = nil
 	payloadBytes, err := json.Marshal(probe)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal updated payload: %w", err)
 	}
 
+	removedLabels := removedUserLabels(cm, probe)
+
 	// Update the data
 	cm.Data["probe-config.json"] = string(payloadBytes)
 
@@ -194,15 +240,21 @@ func (k *KubernetesProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeOb
 			}
 		}
 	}
+	for _, key := range removedLabels {
+		delete(cm.Labels, key)
+	}
 	// Migrate: remove last-reconciled from labels if it was there before
 	delete(cm.Labels, lastReconciledKey)
 	cm.Labels[baseAppLabelKey] = baseAppLabelValue
 	cm.Labels[probeStatusLabelKey] = string(probe.Status)
 
-	updatedCM, err := k.Client.CoreV1().ConfigMaps(k.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	updatedCM, err := configMaps.Update(ctx, cm, metav1.UpdateOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update configmap %s: %w", configMapName, err)
 	}
+	if snap := snapshotFrom(ctx); snap != nil {
+		snap.put(probe.Id, updatedCM)
+	}
 
 	// Return the fully updated probe object
 	var finalProbe v1.ProbeObject
@@ -210,19 +262,46 @@ func (k *KubernetesProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeOb
 		return nil, fmt.Errorf("failed to unmarshal probe from updated configmap: %w", err)
 	}
 
-	// TODO: Tune logging level for this
-	log.Printf("Updated probe %s", probe.Id.String())
-	return &finalProbe, nil
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return withResourceVersion(&finalProbe, updatedCM.ResourceVersion), nil
+}
+
+// removedUserLabels returns the labels of the probe stored in cm that the
+// updated probe no longer has. Only labels from the stored probe are
+// considered, so system labels and labels added to the ConfigMap by other
+// tools are never pruned.
+func removedUserLabels(cm *corev1.ConfigMap, probe v1.ProbeObject) []string {
+	var previous v1.ProbeObject
+	if err := json.Unmarshal([]byte(cm.Data["probe-config.json"]), &previous); err != nil || previous.Labels == nil {
+		return nil
+	}
+
+	var removed []string
+	for key := range *previous.Labels {
+		if isSystemLabel(key) {
+			continue
+		}
+		if probe.Labels != nil {
+			if _, ok := (*probe.Labels)[key]; ok {
+				continue
+			}
+		}
+		removed = append(removed, key)
+	}
+	return removed
 }
 
 func (k *KubernetesProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
 	configMapName := fmt.Sprintf(probeConfigMapNameFormat, probeID)
 
 	// Get the existing ConfigMap to check its current status
-	cm, err := k.Client.CoreV1().ConfigMaps(k.Namespace).Get(ctx, configMapName, metav1.GetOptions{})
+	configMaps, cm, err := k.probeConfigMap(ctx, probeID)
 	if err != nil {
 		return err // Pass the error up, including not found errors
 	}
+	if err := checkResourceVersion(ctx, probeID, cm.ResourceVersion); err != nil {
+		return err
+	}
 
 	// Unmarshal the existing probe object to check its status
 	probe := &v1.ProbeObject{}
@@ -239,12 +318,15 @@ func (k *KubernetesProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUI
 		if err != nil {
 			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
 		}
-		log.Printf("Deleted pending probe %s immediately (never processed by agent)", probeID.String())
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
 		return nil
 
 	case v1.Active:
 		// Probe is active, set to terminating and wait for agent cleanup
 		probe.Status = v1.Terminating
+		probe.DeletionTimestamp = deletionTime()
+		probe.UpdateTimestamp = probe.DeletionTimestamp
+		recordStatusChange(withStatusReason(ctx, deletionReason), probe, v1.Active)
 
 		// Marshal the updated probe object
 		payloadBytes, err := json.Marshal(probe)
@@ -262,17 +344,20 @@ func (k *KubernetesProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUI
 		cm.Labels[probeStatusLabelKey] = string(v1.Terminating)
 
 		// Update the ConfigMap instead of deleting it
-		_, err = k.Client.CoreV1().ConfigMaps(k.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		updatedCM, err := configMaps.Update(ctx, cm, metav1.UpdateOptions{})
 		if err != nil {
 			return fmt.Errorf("failed to update configmap %s to terminating status: %w", configMapName, err)
 		}
+		if snap := snapshotFrom(ctx); snap != nil {
+			snap.put(probeID, updatedCM)
+		}
 
-		log.Printf("Set active probe %s status to terminating (waiting for agent cleanup)", probeID.String())
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
 		return nil
 
 	case v1.Terminating:
 		// Already terminating, no action needed
-		log.Printf("Probe %s is already in terminating state", probeID.String())
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
 		return nil
 
 	case v1.Failed:
@@ -281,7 +366,7 @@ func (k *KubernetesProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUI
 		if err != nil {
 			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
 		}
-		log.Printf("Deleted failed probe %s immediately", probeID.String())
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
 		return nil
 
 	default:
@@ -290,7 +375,7 @@ func (k *KubernetesProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUI
 		if err != nil {
 			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), probe.Status, err)
 		}
-		log.Printf("Deleted probe %s with unknown status %s immediately", probeID.String(), probe.Status)
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", probe.Status)
 		return nil
 	}
 }
@@ -298,14 +383,86 @@ func (k *KubernetesProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUI
 func (k *KubernetesProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
 	configMapName := fmt.Sprintf(probeConfigMapNameFormat, probeID)
 
-	// TODO: Tune logging level for this
-	log.Printf("Deleting probe configmap: %s", probeID.String())
-	return k.Client.CoreV1().ConfigMaps(k.Namespace).Delete(ctx, configMapName, metav1.DeleteOptions{})
+	slog.DebugContext(ctx, "Deleting probe configmap", "probe_id", probeID)
+	configMaps, _, err := k.probeConfigMap(ctx, probeID)
+	if err != nil {
+		return err
+	}
+	if err := configMaps.Delete(ctx, configMapName, deleteOptions(ctx)); err != nil {
+		return err
+	}
+	if snap := snapshotFrom(ctx); snap != nil {
+		snap.remove(probeID)
+	}
+	if err := writeConfigMapTombstone(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), k.Namespace, newTombstone(probeID), k.TombstoneTTL); err != nil {
+		slog.WarnContext(ctx, "Failed to write probe tombstone", "probe_id", probeID, "error", err)
+	}
+	return nil
+}
+
+// GetTombstone returns the tombstone of a recently removed probe.
+func (k *KubernetesProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	return getConfigMapTombstone(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), probeID, k.TombstoneTTL)
+}
+
+// CreateAPIKey stores a new API key in the API key ConfigMap.
+func (k *KubernetesProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	return createConfigMapAPIKey(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), k.Namespace, key)
+}
+
+// ListAPIKeys returns the keys of the API key ConfigMap.
+func (k *KubernetesProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	return listConfigMapAPIKeys(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace))
+}
+
+// DeleteAPIKey removes a key from the API key ConfigMap.
+func (k *KubernetesProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	return deleteConfigMapAPIKey(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), id)
+}
+
+// AddOutboxEvent stores an event in the outbox ConfigMap.
+func (k *KubernetesProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	return addConfigMapOutboxEvent(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), k.Namespace, event)
+}
+
+// ListOutboxEvents returns the events of the outbox ConfigMap.
+func (k *KubernetesProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	return listConfigMapOutboxEvents(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace))
+}
+
+// DeleteOutboxEvent removes an event from the outbox ConfigMap.
+func (k *KubernetesProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	return deleteConfigMapOutboxEvent(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), id)
+}
+
+// PutRecord stores a record in the ConfigMap of its kind.
+func (k *KubernetesProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	return putConfigMapRecord(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), k.Namespace, kind, record)
+}
+
+// CreateRecord adds a record to the ConfigMap of its kind.
+func (k *KubernetesProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	return createConfigMapRecord(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), k.Namespace, kind, record)
+}
+
+// GetRecord returns a record of the ConfigMap of its kind.
+func (k *KubernetesProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	return getConfigMapRecord(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), kind, id)
+}
+
+// ListRecords returns the records of the ConfigMap of the kind.
+func (k *KubernetesProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	return listConfigMapRecords(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), kind)
+}
+
+// DeleteRecord removes a record from the ConfigMap of its kind.
+func (k *KubernetesProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	return deleteConfigMapRecord(ctx, k.Client.CoreV1().ConfigMaps(k.Namespace), kind, id)
 }
 
 func (k *KubernetesProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
 	hashLabelSelector := fmt.Sprintf("%s=%s", probeURLHashLabelKey, urlHashString)
-	existingProbes, err := k.Client.CoreV1().ConfigMaps(k.Namespace).List(ctx, metav1.ListOptions{
+	existingProbes, err := k.listProbeConfigMaps(ctx, metav1.ListOptions{
 		LabelSelector: hashLabelSelector,
 	})
 	if err != nil {
@@ -313,7 +470,7 @@ func (k *KubernetesProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHa
 	}
 	// Exclude probes in terminating or failed status -- these are effectively
 	// inactive and should not block creation of a new probe for the same URL.
-	for _, cm := range existingProbes.Items {
+	for _, cm := range existingProbes {
 		status := cm.Labels[probeStatusLabelKey]
 		if status != string(v1.Terminating) && status != string(v1.Failed) {
 			return true, nil
@@ -330,17 +487,17 @@ func (k *KubernetesProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHa
 // Case 2 catches probes from non-RHOBS-enabled sectors that never get heartbeats.
 func (k *KubernetesProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
 	selector := fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue)
-	configMaps, err := k.Client.CoreV1().ConfigMaps(k.Namespace).List(ctx, metav1.ListOptions{
+	configMaps, err := k.listProbeConfigMaps(ctx, metav1.ListOptions{
 		LabelSelector: selector,
 	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to list probe configmaps for GC: %w", err)
 	}
 
-	now := time.Now().UTC()
+	now := clock.Now()
 	deleted := 0
 
-	for _, cm := range configMaps.Items {
+	for _, cm := range configMaps {
 		// Check annotations first (current), fall back to labels (pre-migration)
 		lastReconciledStr, ok := cm.Annotations[lastReconciledKey]
 		if !ok {
@@ -352,7 +509,7 @@ func (k *KubernetesProbeStore) GarbageCollectStaleProbes(ctx context.Context) (i
 			// that will never get heartbeats).
 			if !cm.CreationTimestamp.IsZero() && now.Sub(cm.CreationTimestamp.Time) > k.NoHeartbeatProbeTTL {
 				if err := k.transitionToTerminating(ctx, &cm, "no heartbeat ever received"); err != nil {
-					log.Printf("GC: failed to transition no-heartbeat probe %s to terminating: %v", cm.Name, err)
+					slog.ErrorContext(ctx, "GC: failed to transition no-heartbeat probe to terminating", "configmap", cm.Name, "error", err)
 					continue
 				}
 				deleted++
@@ -360,9 +517,9 @@ func (k *KubernetesProbeStore) GarbageCollectStaleProbes(ctx context.Context) (i
 			continue
 		}
 
-		lastReconciled, err := time.Parse("20060102T150405Z", lastReconciledStr)
+		lastReconciled, err := time.Parse(lastReconciledLayout, lastReconciledStr)
 		if err != nil {
-			log.Printf("GC: could not parse last-reconciled %q on configmap %s, skipping: %v", lastReconciledStr, cm.Name, err)
+			slog.WarnContext(ctx, "GC: could not parse last-reconciled, skipping", "configmap", cm.Name, "last_reconciled", lastReconciledStr, "error", err)
 			continue
 		}
 
@@ -372,7 +529,7 @@ func (k *KubernetesProbeStore) GarbageCollectStaleProbes(ctx context.Context) (i
 
 		// Probe is stale -- transition to terminating so the agent can clean up the Probe CR
 		if err := k.transitionToTerminating(ctx, &cm, fmt.Sprintf("stale heartbeat %s", lastReconciledStr)); err != nil {
-			log.Printf("GC: failed to transition stale probe %s to terminating: %v", cm.Name, err)
+			slog.ErrorContext(ctx, "GC: failed to transition stale probe to terminating", "configmap", cm.Name, "error", err)
 			continue
 		}
 		deleted++
@@ -389,16 +546,16 @@ func (k *KubernetesProbeStore) transitionToTerminating(ctx context.Context, cm *
 	currentStatus := cm.Labels[probeStatusLabelKey]
 	if currentStatus == string(v1.Terminating) {
 		// Already terminating -- delete it (agent had its chance)
-		log.Printf("GC: deleting already-terminating probe %s (%s)", cm.Name, reason)
-		return k.Client.CoreV1().ConfigMaps(k.Namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{})
+		slog.InfoContext(ctx, "GC: deleting already-terminating probe", "configmap", cm.Name, "reason", reason)
+		return k.Client.CoreV1().ConfigMaps(cm.Namespace).Delete(ctx, cm.Name, metav1.DeleteOptions{})
 	}
 
 	// Transition to terminating
 	cm.Labels[probeStatusLabelKey] = string(v1.Terminating)
-	_, err := k.Client.CoreV1().ConfigMaps(k.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	_, err := k.Client.CoreV1().ConfigMaps(cm.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to update status to terminating: %w", err)
 	}
-	log.Printf("GC: transitioned probe %s to terminating (%s)", cm.Name, reason)
+	slog.InfoContext(ctx, "GC: transitioned probe to terminating", "configmap", cm.Name, "reason", reason)
 	return nil
 }
@@ -0,0 +1,193 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// ShardNamespace returns the namespace of shards a probe's ConfigMap is
+// created in, or namespace when probes are not sharded.
+func ShardNamespace(namespace string, shards []string, probeID uuid.UUID) string {
+	if len(shards) == 0 {
+		return namespace
+	}
+	h := fnv.New32a()
+	h.Write(probeID[:])
+	return shards[h.Sum32()%uint32(len(shards))]
+}
+
+// validateShardNamespaces checks that every shard is named once.
+func validateShardNamespaces(shards []string) error {
+	seen := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		if shard == "" {
+			return fmt.Errorf("shard namespaces cannot be empty")
+		}
+		if seen[shard] {
+			return fmt.Errorf("shard namespace %q is listed twice", shard)
+		}
+		seen[shard] = true
+	}
+	return nil
+}
+
+// probeNamespace returns the namespace a new probe's ConfigMap is created in.
+func (k *KubernetesProbeStore) probeNamespace(probeID uuid.UUID) string {
+	return ShardNamespace(k.Namespace, k.ShardNamespaces, probeID)
+}
+
+// ProbeConfigMapReference returns a reference to the ConfigMap holding a
+// probe, in the namespace it is found in, which for probes created before
+// sharding is Namespace rather than their shard. Probes that no longer exist
+// are referenced in the namespace they hash to.
+func (k *KubernetesProbeStore) ProbeConfigMapReference(ctx context.Context, probeID uuid.UUID) corev1.ObjectReference {
+	namespace := k.probeNamespace(probeID)
+	if _, cm, err := k.probeConfigMap(ctx, probeID); err == nil {
+		namespace = cm.Namespace
+	}
+	return ConfigMapReference(namespace, probeID)
+}
+
+// probeNamespaces returns every namespace probe ConfigMaps may be in: the
+// shards, and Namespace, which keeps the probes created before sharding was
+// enabled.
+func (k *KubernetesProbeStore) probeNamespaces() []string {
+	if slices.Contains(k.ShardNamespaces, k.Namespace) {
+		return k.ShardNamespaces
+	}
+	return append(slices.Clip(k.ShardNamespaces), k.Namespace)
+}
+
+// probeConfigMap returns the ConfigMap holding a probe, along with the client
+// of its namespace. The namespace the probe hashes to is tried first, then
+// the others, so probes stay reachable when the shards change. The not found
+// error of the first namespace is returned when no namespace has the probe.
+// Under WithSnapshot, the ConfigMap is taken from the snapshot when it has
+// the probe; probes created since the snapshot are looked up as usual.
+func (k *KubernetesProbeStore) probeConfigMap(ctx context.Context, probeID uuid.UUID) (typedcorev1.ConfigMapInterface, *corev1.ConfigMap, error) {
+	name := fmt.Sprintf(probeConfigMapNameFormat, probeID)
+	if snap := snapshotFrom(ctx); snap != nil {
+		obj, ok, err := snap.get(probeID, func() (map[uuid.UUID]any, error) { return k.snapshotConfigMaps(ctx) })
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			// Callers modify the ConfigMap they get before writing it.
+			cm := obj.(*corev1.ConfigMap).DeepCopy()
+			return k.Client.CoreV1().ConfigMaps(cm.Namespace), cm, nil
+		}
+	}
+	first := k.probeNamespace(probeID)
+	configMaps := k.Client.CoreV1().ConfigMaps(first)
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if err == nil || !k8serrors.IsNotFound(err) {
+		return configMaps, cm, err
+	}
+	for _, namespace := range k.probeNamespaces() {
+		if namespace == first {
+			continue
+		}
+		other := k.Client.CoreV1().ConfigMaps(namespace)
+		found, otherErr := other.Get(ctx, name, metav1.GetOptions{})
+		if otherErr == nil {
+			return other, found, nil
+		}
+		if !k8serrors.IsNotFound(otherErr) {
+			return nil, nil, otherErr
+		}
+	}
+	return nil, nil, err
+}
+
+// snapshotConfigMaps lists the probe ConfigMaps of every namespace for a
+// snapshot, by the ID of their probe.
+func (k *KubernetesProbeStore) snapshotConfigMaps(ctx context.Context) (map[uuid.UUID]any, error) {
+	configMaps, err := k.listProbeConfigMaps(ctx, metav1.ListOptions{LabelSelector: ProbeSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config maps: %w", err)
+	}
+	objects := make(map[uuid.UUID]any, len(configMaps))
+	for i := range configMaps {
+		cm := &configMaps[i]
+		rawID, ok := strings.CutPrefix(cm.Name, strings.TrimSuffix(probeConfigMapNameFormat, "%s"))
+		if !ok {
+			continue
+		}
+		id, err := uuid.Parse(rawID)
+		if err != nil {
+			continue
+		}
+		// Should two namespaces hold the probe, the one it hashes to wins,
+		// as it is the one probeConfigMap tries first.
+		if _, taken := objects[id]; taken && cm.Namespace != k.probeNamespace(id) {
+			continue
+		}
+		objects[id] = cm
+	}
+	return objects, nil
+}
+
+// listProbeConfigMaps lists the ConfigMaps matching opts in every probe
+// namespace at once, in the order of the namespaces.
+func (k *KubernetesProbeStore) listProbeConfigMaps(ctx context.Context, opts metav1.ListOptions) ([]corev1.ConfigMap, error) {
+	namespaces := k.probeNamespaces()
+	lists := make([][]corev1.ConfigMap, len(namespaces))
+	errs := make([]error, len(namespaces))
+	var wg sync.WaitGroup
+	for i, namespace := range namespaces {
+		wg.Go(func() {
+			list, err := k.Client.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+			if err != nil {
+				errs[i] = fmt.Errorf("namespace %s: %w", namespace, err)
+				return
+			}
+			lists[i] = list.Items
+		})
+	}
+	wg.Wait()
+
+	var items []corev1.ConfigMap
+	for i := range namespaces {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		items = append(items, lists[i]...)
+	}
+	return items, nil
+}
+
+// mergeURLHashChanges forwards the changes of every watch to one channel.
+// The first watch to end cancels the others and the channel is closed once
+// they have all ended, so the index lists the probes again.
+func mergeURLHashChanges(ctx context.Context, cancel context.CancelFunc, watches []<-chan urlHashChange) <-chan urlHashChange {
+	merged := make(chan urlHashChange)
+	var wg sync.WaitGroup
+	for _, changes := range watches {
+		wg.Go(func() {
+			defer cancel()
+			for change := range changes {
+				select {
+				case merged <- change:
+				case <-ctx.Done():
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
+}
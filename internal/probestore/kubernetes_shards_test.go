@@ -0,0 +1,144 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var testShardNamespaces = []string{"probes-0", "probes-1", "probes-2"}
+
+func TestNewShardedKubernetesProbeStore_InvalidShards(t *testing.T) {
+	for name, shards := range map[string][]string{
+		"empty name": {"probes-0", ""},
+		"duplicate":  {"probes-0", "probes-1", "probes-0"},
+	} {
+		store, err := NewShardedKubernetesProbeStore(context.Background(), fake.NewSimpleClientset(), testNamespace, shards)
+		assert.Error(t, err, name)
+		assert.Nil(t, store, name)
+	}
+}
+
+func TestShardNamespace(t *testing.T) {
+	id := uuid.New()
+	assert.Equal(t, testNamespace, ShardNamespace(testNamespace, nil, id), "probes are not sharded without shards")
+	assert.Equal(t, ShardNamespace(testNamespace, testShardNamespaces, id), ShardNamespace("other", testShardNamespaces, id), "the shard only depends on the probe ID")
+
+	used := map[string]bool{}
+	for range 100 {
+		used[ShardNamespace(testNamespace, testShardNamespaces, uuid.New())] = true
+	}
+	assert.Len(t, used, len(testShardNamespaces), "probes are spread over every shard")
+}
+
+func TestKubernetesProbeStore_Shards(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+	store, err := NewShardedKubernetesProbeStore(ctx, clientset, testNamespace, testShardNamespaces)
+	require.NoError(t, err)
+
+	var probes []v1.ProbeObject
+	for i := range 12 {
+		probe, hash := probeFor(fmt.Sprintf("https://%d.example.com", i))
+		created, err := store.CreateProbe(ctx, probe, hash)
+		require.NoError(t, err)
+		probes = append(probes, *created)
+
+		namespace := ShardNamespace(testNamespace, testShardNamespaces, probe.Id)
+		_, err = clientset.CoreV1().ConfigMaps(namespace).Get(ctx, fmt.Sprintf(probeConfigMapNameFormat, probe.Id), metav1.GetOptions{})
+		require.NoError(t, err, "the probe is stored in the namespace it hashes to")
+	}
+
+	// A probe created before sharding was enabled stays in the store's
+	// namespace.
+	legacy := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://legacy.example.com", Status: v1.Active}
+	_, err = clientset.CoreV1().ConfigMaps(testNamespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(probeConfigMapNameFormat, legacy.Id),
+			Namespace: testNamespace,
+			Labels:    map[string]string{baseAppLabelKey: baseAppLabelValue, probeURLHashLabelKey: "legacy", probeStatusLabelKey: string(v1.Active)},
+		},
+		Data: map[string]string{"probe-config.json": mustMarshal(t, legacy)},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	t.Run("list fans out to every namespace", func(t *testing.T) {
+		listed, err := store.ListProbes(ctx, probeListOptions().LabelSelector)
+		require.NoError(t, err)
+		assert.Len(t, listed, len(probes)+1)
+	})
+
+	t.Run("probes outside their shard are found", func(t *testing.T) {
+		got, err := store.GetProbe(ctx, legacy.Id)
+		require.NoError(t, err)
+		assert.Equal(t, legacy.StaticUrl, got.StaticUrl)
+
+		got.Labels = &v1.LabelsSchema{"env": "prod"}
+		_, err = store.UpdateProbe(ctx, *got)
+		require.NoError(t, err)
+		cm, err := clientset.CoreV1().ConfigMaps(testNamespace).Get(ctx, fmt.Sprintf(probeConfigMapNameFormat, legacy.Id), metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "prod", cm.Labels["env"], "the probe is updated where it is")
+
+		exists, err := store.ProbeWithURLHashExists(ctx, "legacy")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("references name the namespace the probe is in", func(t *testing.T) {
+		assert.Equal(t, ConfigMapReference(testNamespace, legacy.Id), store.ProbeConfigMapReference(ctx, legacy.Id))
+		sharded := probes[1].Id
+		assert.Equal(t, ConfigMapReference(ShardNamespace(testNamespace, testShardNamespaces, sharded), sharded), store.ProbeConfigMapReference(ctx, sharded))
+		removed := uuid.New()
+		assert.Equal(t, ConfigMapReference(ShardNamespace(testNamespace, testShardNamespaces, removed), removed), store.ProbeConfigMapReference(ctx, removed))
+	})
+
+	t.Run("removal", func(t *testing.T) {
+		probe := probes[0]
+		require.NoError(t, store.DeleteProbeStorage(ctx, probe.Id))
+		_, err := store.GetProbe(ctx, probe.Id)
+		assert.True(t, k8serrors.IsNotFound(err), "got %v", err)
+
+		tombstone, err := store.GetTombstone(ctx, probe.Id)
+		require.NoError(t, err)
+		assert.NotNil(t, tombstone)
+		_, err = clientset.CoreV1().ConfigMaps(testNamespace).Get(ctx, tombstoneConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err, "tombstones are kept in the store's namespace")
+	})
+
+	t.Run("unknown probe", func(t *testing.T) {
+		_, err := store.GetProbe(ctx, uuid.New())
+		assert.True(t, k8serrors.IsNotFound(err), "got %v", err)
+	})
+}
+
+func TestKubernetesProbeStore_ShardListError(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "probes-1" {
+			return true, nil, fmt.Errorf("forbidden")
+		}
+		return false, nil, nil
+	})
+	store, err := NewShardedKubernetesProbeStore(ctx, clientset, testNamespace, testShardNamespaces)
+	require.NoError(t, err)
+
+	_, err = store.ListProbes(ctx, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "namespace probes-1", "a namespace that cannot be listed fails the list")
+	assert.Error(t, store.CheckHealth(ctx))
+}
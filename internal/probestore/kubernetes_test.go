@@ -1,3 +1,5 @@
+//go:build !nokube
+
 package probestore
 
 import (
@@ -24,12 +26,18 @@ const (
 	testNamespace = "test-namespace"
 )
 
-func mustMarshal(t *testing.T, v interface{}) string {
+func mustMarshal(t testing.TB, v interface{}) string {
 	bytes, err := json.Marshal(v)
 	require.NoError(t, err)
 	return string(bytes)
 }
 
+func TestNewKubernetesProbeStore_EmptyNamespace(t *testing.T) {
+	store, err := NewKubernetesProbeStore(context.Background(), fake.NewSimpleClientset(), "")
+	require.Error(t, err)
+	assert.Nil(t, store)
+}
+
 func TestKubernetesProbeStore_ListProbes(t *testing.T) {
 	ctx := context.Background()
 	probe1ID := uuid.New()
@@ -218,6 +226,9 @@ func TestKubernetesProbeStore_CreateProbe(t *testing.T) {
 		Labels:    &v1.LabelsSchema{"team": "sre"},
 	}
 	urlHash := "testhash"
+	firstGeneration := int64(1)
+	createdProbe := probeToCreate
+	createdProbe.Generation = &firstGeneration
 
 	successClientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}})
 
@@ -250,7 +261,7 @@ func TestKubernetesProbeStore_CreateProbe(t *testing.T) {
 				var probeFromData v1.ProbeObject
 				err = json.Unmarshal([]byte(cm.Data["probe-config.json"]), &probeFromData)
 				require.NoError(t, err)
-				assert.Equal(t, probeToCreate, probeFromData)
+				assert.Equal(t, createdProbe, probeFromData)
 			},
 		},
 		{
@@ -268,7 +279,7 @@ func TestKubernetesProbeStore_CreateProbe(t *testing.T) {
 			store, err := NewKubernetesProbeStore(ctx, tc.clientset, testNamespace)
 			require.NoError(t, err)
 
-			createdProbe, err := store.CreateProbe(ctx, probeToCreate, urlHash)
+			created, err := store.CreateProbe(ctx, probeToCreate, urlHash)
 
 			if tc.expectErr {
 				require.Error(t, err)
@@ -277,7 +288,10 @@ func TestKubernetesProbeStore_CreateProbe(t *testing.T) {
 				}
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, &probeToCreate, createdProbe)
+				require.NotNil(t, created.CreationTimestamp)
+				createdProbe.CreationTimestamp = created.CreationTimestamp
+				createdProbe.UpdateTimestamp = created.UpdateTimestamp
+				assert.Equal(t, &createdProbe, created)
 			}
 
 			if tc.postCheck != nil {
@@ -333,6 +347,63 @@ func TestKubernetesProbeStore_UpdateProbe(t *testing.T) {
 				assert.Equal(t, "label", cm.Labels["new"])
 			},
 		},
+		{
+			name: "prunes labels removed from the probe but keeps system and external labels",
+			probeToUpdate: func() v1.ProbeObject {
+				p := initialProbe
+				p.Status = v1.Active
+				p.Labels = &v1.LabelsSchema{"keep": "yes"}
+				return p
+			}(),
+			clientset: func() *fake.Clientset {
+				previous := initialProbe
+				previous.Labels = &v1.LabelsSchema{
+					"keep":               "yes",
+					"drop":               "me",
+					probeURLHashLabelKey: "hash",
+				}
+				cm := initialConfigMap.DeepCopy()
+				cm.Labels["keep"] = "yes"
+				cm.Labels["drop"] = "me"
+				cm.Labels[probeURLHashLabelKey] = "hash"
+				cm.Labels["external.example.com/owner"] = "team-a"
+				cm.Data["probe-config.json"] = mustMarshal(t, previous)
+				return fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}, cm)
+			}(),
+			postCheck: func(t *testing.T, cs *fake.Clientset) {
+				cm, err := cs.CoreV1().ConfigMaps(testNamespace).Get(ctx, fmt.Sprintf(probeConfigMapNameFormat, probeID), metav1.GetOptions{})
+				require.NoError(t, err)
+				assert.Equal(t, map[string]string{
+					baseAppLabelKey:              baseAppLabelValue,
+					probeStatusLabelKey:          string(v1.Active),
+					probeURLHashLabelKey:         "hash",
+					"keep":                       "yes",
+					"external.example.com/owner": "team-a",
+				}, cm.Labels)
+			},
+		},
+		{
+			name: "prunes all user labels when the probe has none",
+			probeToUpdate: func() v1.ProbeObject {
+				p := initialProbe
+				p.Status = v1.Active
+				return p
+			}(),
+			clientset: func() *fake.Clientset {
+				previous := initialProbe
+				previous.Labels = &v1.LabelsSchema{"drop": "me"}
+				cm := initialConfigMap.DeepCopy()
+				cm.Labels["drop"] = "me"
+				cm.Data["probe-config.json"] = mustMarshal(t, previous)
+				return fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}, cm)
+			}(),
+			postCheck: func(t *testing.T, cs *fake.Clientset) {
+				cm, err := cs.CoreV1().ConfigMaps(testNamespace).Get(ctx, fmt.Sprintf(probeConfigMapNameFormat, probeID), metav1.GetOptions{})
+				require.NoError(t, err)
+				assert.NotContains(t, cm.Labels, "drop")
+				assert.Equal(t, baseAppLabelValue, cm.Labels[baseAppLabelKey])
+			},
+		},
 		{
 			name:          "error updating non-existent probe",
 			probeToUpdate: v1.ProbeObject{Id: uuid.New()},
@@ -355,7 +426,18 @@ func TestKubernetesProbeStore_UpdateProbe(t *testing.T) {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				assert.Equal(t, tc.probeToUpdate, *updatedProbe)
+				// Every case changes the labels of a probe at generation 1.
+				expected := tc.probeToUpdate
+				secondGeneration := int64(2)
+				expected.Generation = &secondGeneration
+				require.NotNil(t, updatedProbe.UpdateTimestamp, "label changes update the probe")
+				expected.UpdateTimestamp = updatedProbe.UpdateTimestamp
+				// The stored probe is pending, so its activation is recorded.
+				require.NotNil(t, updatedProbe.StatusHistory)
+				require.Len(t, *updatedProbe.StatusHistory, 1)
+				assert.Equal(t, v1.Pending, (*updatedProbe.StatusHistory)[0].From)
+				expected.StatusHistory = updatedProbe.StatusHistory
+				assert.Equal(t, expected, *updatedProbe)
 			}
 
 			if tc.postCheck != nil {
@@ -832,3 +914,69 @@ func TestKubernetesProbeStore_GarbageCollectStaleProbes_UpdateError(t *testing.T
 	require.NoError(t, err)
 	assert.Equal(t, 1, len(remaining.Items))
 }
+
+func FuzzKubernetesProbeStore_ProbeJSON(f *testing.F) {
+	probeID := uuid.New()
+	valid := v1.ProbeObject{Id: probeID, StaticUrl: "http://example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "prod"}}
+	for _, seed := range []string{
+		mustMarshal(f, valid),
+		`{}`,
+		`{"labels":{"app":"other","rhobs-synthetics/status":"x","env":""}}`,
+		`{"labels":"env"}`,
+		`{"status":"deleted","labels":null}`,
+		`null`,
+		`{not-a-valid-json`,
+		"",
+	} {
+		f.Add(seed)
+	}
+	ctx := context.Background()
+
+	f.Fuzz(func(t *testing.T, data string) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf(probeConfigMapNameFormat, probeID),
+				Namespace: testNamespace,
+				Labels:    map[string]string{baseAppLabelKey: baseAppLabelValue, "env": "prod"},
+			},
+			Data: map[string]string{"probe-config.json": data},
+		}
+		store, err := NewKubernetesProbeStore(ctx, fake.NewSimpleClientset(cm), testNamespace)
+		require.NoError(t, err)
+
+		var want v1.ProbeObject
+		decodeErr := json.Unmarshal([]byte(data), &want)
+
+		probe, err := store.GetProbe(ctx, probeID)
+		if decodeErr != nil {
+			assert.Error(t, err)
+		} else {
+			require.NoError(t, err)
+			assert.Equal(t, want, *probe)
+		}
+
+		// Corrupted ConfigMaps are skipped, never fatal to a listing.
+		probes, err := store.ListProbes(ctx, "")
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(probes), 1)
+
+		// Whatever was stored before, an update replaces it with valid data and
+		// keeps the system labels.
+		updated, err := store.UpdateProbe(ctx, valid)
+		require.NoError(t, err)
+		require.NotNil(t, updated.Generation)
+		assert.GreaterOrEqual(t, *updated.Generation, int64(1))
+		expected := valid
+		expected.Generation = updated.Generation
+		expected.UpdateTimestamp = updated.UpdateTimestamp
+		// The history depends on the status stored before.
+		expected.StatusHistory = updated.StatusHistory
+		assert.Equal(t, expected, *updated)
+		got, err := store.Client.CoreV1().ConfigMaps(testNamespace).Get(ctx, cm.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, baseAppLabelValue, got.Labels[baseAppLabelKey])
+		assert.Equal(t, "prod", got.Labels["env"])
+
+		_ = store.DeleteProbe(ctx, probeID)
+	})
+}
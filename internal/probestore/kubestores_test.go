@@ -0,0 +1,32 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// addKubernetesTestStores adds the stores backed by fake Kubernetes clients
+// to the test stores.
+func addKubernetesTestStores(stores map[string]func(t *testing.T) ProbeStorage) {
+	stores["kubernetes"] = func(t *testing.T) ProbeStorage {
+		clientset := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}})
+		store, err := NewKubernetesProbeStore(context.Background(), clientset, testNamespace)
+		require.NoError(t, err)
+		return store
+	}
+	stores["sharded kubernetes"] = func(t *testing.T) ProbeStorage {
+		store, err := NewShardedKubernetesProbeStore(context.Background(), fake.NewSimpleClientset(), testNamespace, testShardNamespaces)
+		require.NoError(t, err)
+		return store
+	}
+	stores["crd"] = func(t *testing.T) ProbeStorage {
+		return newTestCRDProbeStore()
+	}
+}
@@ -2,15 +2,21 @@ package probestore
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io/fs"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
 	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -18,12 +24,23 @@ import (
 
 const (
 	localProbeStoreDir = "data"
+	// localRecordsDir is the directory, inside the store's, records are
+	// kept in, in a directory per kind.
+	localRecordsDir = ".records"
 )
 
 // LocalProbeStore implements the ProbeStorage interface using the local filesystem.
 // It stores each probe as a separate JSON file in a directory.
 type LocalProbeStore struct {
 	Directory string
+	// TombstoneTTL is how long tombstones of removed probes are kept, next
+	// to the probe files; zero selects the default.
+	TombstoneTTL time.Duration
+
+	// index keeps the labels, URL hash, status and static URL of each probe
+	// file for ListProbes, SearchProbes and ProbeWithURLHashExists, and
+	// serializes probe writes.
+	index localIndex
 }
 
 // NewLocalProbeStore creates a new LocalProbeStore with the default data directory.
@@ -44,85 +61,64 @@ func NewLocalProbeStoreWithDir(dataDir string) (*LocalProbeStore, error) {
 			if err := os.MkdirAll(dataDir, 0755); err != nil {
 				return nil, fmt.Errorf("failed to create probe store directory: %w", err)
 			}
-			log.Printf("Created local probe store directory %q", dataDir)
+			slog.Info("Created local probe store directory", "directory", dataDir)
 		} else {
 			// Some other error occurred while checking
 			return nil, fmt.Errorf("failed to check probe store directory: %w", err)
 		}
 	} else {
-		log.Printf("Using existing local probe store directory %q", dataDir)
+		slog.Info("Using existing local probe store directory", "directory", dataDir)
 	}
 
-	// Validate that the directory is writable
-	testFile := filepath.Join(dataDir, ".write_test")
+	store := &LocalProbeStore{Directory: dataDir, TombstoneTTL: tombstoneTTLFromEnv()}
+	if err := store.CheckWritable(context.Background()); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// CheckWritable checks that a file can be written to the store's directory.
+func (l *LocalProbeStore) CheckWritable(ctx context.Context) error {
+	testFile := filepath.Join(l.Directory, ".write_test")
 	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-		return nil, fmt.Errorf("probe store directory is not writable: %w", err)
+		return fmt.Errorf("probe store directory is not writable: %w", err)
 	}
 	os.Remove(testFile) //nolint:errcheck
+	return nil
+}
 
-	return &LocalProbeStore{Directory: dataDir}, nil
+// CheckHealth checks that the store's directory can be read.
+func (l *LocalProbeStore) CheckHealth(ctx context.Context) error {
+	dir, err := os.Open(l.Directory)
+	if err != nil {
+		return fmt.Errorf("probe store directory is not readable: %w", err)
+	}
+	defer dir.Close() //nolint:errcheck
+	if _, err := dir.ReadDir(1); err != nil && err != io.EOF {
+		return fmt.Errorf("probe store directory is not readable: %w", err)
+	}
+	return nil
 }
 
-// ListProbes lists all probes that match the given label selector.
+// ListProbes lists all probes that match the given label selector. The
+// selector is matched against the index, so only matching files are read.
 func (l *LocalProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
 	sel, err := labels.Parse(selector)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse label selector: %w", err)
 	}
 
-	probes := []v1.ProbeObject{}
-	var skippedFiles []string
-
-	walkErr := filepath.WalkDir(l.Directory, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return fmt.Errorf("error accessing path %s: %w", path, err)
-		}
-		if d.IsDir() || filepath.Ext(path) != ".json" {
-			return nil
-		}
-
-		data, err := os.ReadFile(path)
-		if err != nil {
-			log.Printf("Warning: Error reading probe file %s: %v", path, err)
-			skippedFiles = append(skippedFiles, path)
-			return nil // Continue walking, but track skipped files
-		}
-
-		var probe v1.ProbeObject
-		if err := json.Unmarshal(data, &probe); err != nil {
-			log.Printf("Warning: Error unmarshaling probe from file %s: %v", path, err)
-			skippedFiles = append(skippedFiles, path)
-			return nil // Continue walking, but track skipped files
-		}
-
-		// Handle nil labels gracefully
-		probeLabels := labels.Set{}
-		if probe.Labels != nil {
-			probeLabels = labels.Set(*probe.Labels)
-		}
-
-		if sel.Matches(probeLabels) {
-			probes = append(probes, probe)
-		}
-
-		return nil
+	return l.readIndexed(ctx, "local.ListIndex", func(probe v1.ProbeObject) bool {
+		return sel.Matches(probeLabelSet(probe))
 	})
-
-	if walkErr != nil {
-		return nil, fmt.Errorf("error walking probe store directory: %w", walkErr)
-	}
-
-	if len(skippedFiles) > 0 {
-		log.Printf("Warning: Skipped %d corrupted or unreadable probe files", len(skippedFiles))
-	}
-
-	return probes, nil
 }
 
 // GetProbe retrieves a single probe by its ID.
 func (l *LocalProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
 	filePath := filepath.Join(l.Directory, probeID.String()+".json")
+	_, span := tracing.Tracer().Start(ctx, "local.ReadFile", trace.WithAttributes(attribute.String("local.path", filePath)))
 	data, err := os.ReadFile(filePath)
+	end(span, err)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
@@ -135,7 +131,7 @@ func (l *LocalProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.
 		return nil, fmt.Errorf("failed to unmarshal probe: %w", err)
 	}
 
-	return &probe, nil
+	return withResourceVersion(&probe, fileVersion(data)), nil
 }
 
 // CreateProbe creates a new probe, storing it as a JSON file.
@@ -147,13 +143,19 @@ func (l *LocalProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject,
 	if urlHashString == "" {
 		return nil, fmt.Errorf("URL hash cannot be empty")
 	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
 
-	// Check for existing probe with same URL hash
-	exists, err := l.ProbeWithURLHashExists(ctx, urlHashString)
-	if err != nil {
+	l.index.mu.Lock()
+	defer l.index.mu.Unlock()
+	if err := l.syncIndex(ctx); err != nil {
 		return nil, fmt.Errorf("failed to check for existing probe with URL hash: %w", err)
 	}
-	if exists {
+
+	// Check for existing probe with same URL hash, unless an
+	// IndexedProbeStore already did
+	if !urlHashChecked(ctx) && l.index.urlHashLive(urlHashString) {
 		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
 	}
 
@@ -164,6 +166,8 @@ func (l *LocalProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject,
 	(*probe.Labels)[probeURLHashLabelKey] = urlHashString
 	(*probe.Labels)[baseAppLabelKey] = baseAppLabelValue
 	(*probe.Labels)[probeStatusLabelKey] = string(probe.Status)
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
 
 	filePath := filepath.Join(l.Directory, probe.Id.String()+".json")
 
@@ -173,25 +177,20 @@ func (l *LocalProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject,
 	}
 
 	// Marshal to JSON
+	probe.ResourceVersion = nil
 	data, err := json.MarshalIndent(probe, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal probe: %w", err)
 	}
 
 	// Write file atomically by writing to temp file then renaming
-	tempPath := filePath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	if err := writeFileAtomic(ctx, filePath, data); err != nil {
 		return nil, fmt.Errorf("failed to write probe file: %w", err)
 	}
+	l.indexProbeFile(ctx, filePath, &probe)
 
-	if err := os.Rename(tempPath, filePath); err != nil {
-		os.Remove(tempPath) //nolint:errcheck
-		return nil, fmt.Errorf("failed to finalize probe file: %w", err)
-	}
-
-	// TODO: Tune logging level for this
-	log.Printf("Created probe %s with URL hash %s", probe.Id.String(), urlHashString)
-	return &probe, nil
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	return withResourceVersion(&probe, fileVersion(data)), nil
 }
 
 // UpdateProbe updates an existing probe's JSON file.
@@ -203,6 +202,12 @@ func (l *LocalProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject)
 
 	filePath := filepath.Join(l.Directory, probe.Id.String()+".json")
 
+	l.index.mu.Lock()
+	defer l.index.mu.Unlock()
+	if err := l.syncIndex(ctx); err != nil {
+		return nil, fmt.Errorf("failed to index probe store directory: %w", err)
+	}
+
 	// Check if probe exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String())
@@ -213,6 +218,18 @@ func (l *LocalProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read existing probe: %w", err)
 	}
+	if err := checkResourceVersion(ctx, probe.Id, resourceVersionOf(existingProbe)); err != nil {
+		return nil, err
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+	withNextGeneration(&probe, *existingProbe)
+	keepCreationTimestamp(&probe, *existingProbe)
+	withUpdateTimestamp(&probe, *existingProbe)
+	withDeletionTimestamp(&probe, *existingProbe)
+	withStatusHistory(ctx, &probe, *existingProbe)
+	withURLHash(&probe, *existingProbe)
 
 	// Ensure system labels are preserved/updated
 	if probe.Labels == nil {
@@ -231,25 +248,20 @@ func (l *LocalProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject)
 	}
 
 	// Marshal to JSON
+	probe.ResourceVersion = nil
 	data, err := json.MarshalIndent(probe, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal updated probe: %w", err)
 	}
 
 	// Write file atomically
-	tempPath := filePath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+	if err := writeFileAtomic(ctx, filePath, data); err != nil {
 		return nil, fmt.Errorf("failed to write updated probe file: %w", err)
 	}
+	l.indexProbeFile(ctx, filePath, &probe)
 
-	if err := os.Rename(tempPath, filePath); err != nil {
-		os.Remove(tempPath) //nolint:errcheck
-		return nil, fmt.Errorf("failed to finalize updated probe file: %w", err)
-	}
-
-	// TODO: Tune logging level for this
-	log.Printf("Updated probe %s", probe.Id.String())
-	return &probe, nil
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return withResourceVersion(&probe, fileVersion(data)), nil
 }
 
 
@@ -265,6 +277,9 @@ func (l *LocalProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) er
 	if err != nil {
 		return err // Pass the error up, including not found errors
 	}
+	if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+		return err
+	}
 
 	// Handle deletion based on current probe status
 	switch existingProbe.Status {
@@ -274,22 +289,22 @@ func (l *LocalProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) er
 		if err != nil {
 			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
 		}
-		log.Printf("Deleted pending probe %s immediately (never processed by agent)", probeID.String())
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
 		return nil
 
 	case v1.Active:
 		// Probe is active, set to terminating and wait for agent cleanup
 		existingProbe.Status = v1.Terminating
-		_, err := l.UpdateProbe(ctx, *existingProbe)
+		_, err := l.UpdateProbe(withStatusReason(ctx, deletionReason), *existingProbe)
 		if err != nil {
 			return fmt.Errorf("failed to update probe %s to terminating status: %w", probeID.String(), err)
 		}
-		log.Printf("Set active probe %s status to terminating (waiting for agent cleanup)", probeID.String())
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
 		return nil
 
 	case v1.Terminating:
 		// Already terminating, no action needed
-		log.Printf("Probe %s is already in terminating state", probeID.String())
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
 		return nil
 
 	case v1.Failed:
@@ -298,7 +313,7 @@ func (l *LocalProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) er
 		if err != nil {
 			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
 		}
-		log.Printf("Deleted failed probe %s immediately", probeID.String())
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
 		return nil
 
 	default:
@@ -307,7 +322,7 @@ func (l *LocalProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) er
 		if err != nil {
 			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), existingProbe.Status, err)
 		}
-		log.Printf("Deleted probe %s with unknown status %s immediately", probeID.String(), existingProbe.Status)
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", existingProbe.Status)
 		return nil
 	}
 }
@@ -321,65 +336,323 @@ func (l *LocalProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.U
 
 	filePath := filepath.Join(l.Directory, probeID.String()+".json")
 
+	l.index.mu.Lock()
+	defer l.index.mu.Unlock()
+	if err := l.syncIndex(ctx); err != nil {
+		return fmt.Errorf("failed to index probe store directory: %w", err)
+	}
+
 	// Check if file exists before attempting deletion
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
 	}
+	if _, ok := expectedResourceVersion(ctx); ok {
+		existingProbe, err := l.GetProbe(ctx, probeID)
+		if err != nil {
+			return err
+		}
+		if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+			return err
+		}
+	}
 
 	// Attempt to delete the file
+	_, span := tracing.Tracer().Start(ctx, "local.Remove", trace.WithAttributes(attribute.String("local.path", filePath)))
 	err := os.Remove(filePath)
+	end(span, err)
 	if err != nil {
 		return fmt.Errorf("failed to delete probe file: %w", err)
 	}
+	l.indexProbeFile(ctx, filePath, nil)
+	if err := l.writeTombstone(ctx, newTombstone(probeID)); err != nil {
+		slog.WarnContext(ctx, "Failed to write probe tombstone", "probe_id", probeID, "error", err)
+	}
 
-	// TODO: Tune logging level for this
-	log.Printf("Deleted probe file: %s", probeID.String())
+	slog.DebugContext(ctx, "Deleted probe file", "probe_id", probeID)
 	return nil
 }
 
-// ProbeWithURLHashExists checks if a probe with the given URL hash already exists.
-// This is optimized to stop at the first match rather than scanning all files.
-func (l *LocalProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
-	var found bool
-	walkErr := filepath.WalkDir(l.Directory, func(path string, d fs.DirEntry, err error) error {
+func (l *LocalProbeStore) tombstonePath(probeID uuid.UUID) string {
+	return filepath.Join(l.Directory, probeID.String()+".tombstone")
+}
+
+// writeTombstone stores the tombstone next to the probe files. Its extension
+// keeps it out of probe listings.
+func (l *LocalProbeStore) writeTombstone(ctx context.Context, tombstone Tombstone) error {
+	data, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	return writeFileAtomic(ctx, l.tombstonePath(tombstone.ProbeID), data)
+}
+
+// GetTombstone returns the tombstone of a recently removed probe. Expired
+// tombstones are removed when they are read.
+func (l *LocalProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	path := l.tombstonePath(probeID)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tombstone: %w", err)
+	}
+	var tombstone Tombstone
+	if err := json.Unmarshal(data, &tombstone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+	}
+	if tombstone.expired(l.TombstoneTTL) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			slog.WarnContext(ctx, "Failed to remove expired tombstone", "probe_id", probeID, "error", err)
+		}
+		return nil, tombstoneNotFound(probeID)
+	}
+	return &tombstone, nil
+}
+
+func (l *LocalProbeStore) apiKeyPath(id string) string {
+	return filepath.Join(l.Directory, id+".apikey")
+}
+
+// CreateAPIKey stores the key next to the probe files. Its extension keeps
+// it out of probe listings.
+func (l *LocalProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	return writeFileAtomic(ctx, l.apiKeyPath(key.ID), data)
+}
+
+// ListAPIKeys returns the stored API keys.
+func (l *LocalProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	paths, err := filepath.Glob(filepath.Join(l.Directory, "*.apikey"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	keys := make([]APIKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
 		if err != nil {
-			return err
+			if os.IsNotExist(err) {
+				continue // Revoked while listing
+			}
+			return nil, fmt.Errorf("failed to read API key: %w", err)
 		}
-		if d.IsDir() || filepath.Ext(path) != ".json" {
-			return nil
+		var key APIKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling API key from file", "path", path, "error", err)
+			continue
 		}
+		keys = append(keys, key)
+	}
+	sortAPIKeys(keys)
+	return keys, nil
+}
+
+// DeleteAPIKey removes a stored API key.
+func (l *LocalProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	err := os.Remove(l.apiKeyPath(id))
+	if os.IsNotExist(err) {
+		return apiKeyNotFound(id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove API key: %w", err)
+	}
+	return nil
+}
+
+func (l *LocalProbeStore) outboxEventPath(id string) string {
+	return filepath.Join(l.Directory, id+".event")
+}
+
+// AddOutboxEvent stores the event next to the probe files. Its extension
+// keeps it out of probe listings.
+func (l *LocalProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+	return writeFileAtomic(ctx, l.outboxEventPath(event.ID), data)
+}
 
+// ListOutboxEvents returns the events waiting in the outbox.
+func (l *LocalProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	paths, err := filepath.Glob(filepath.Join(l.Directory, "*.event"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	events := make([]OutboxEvent, 0, len(paths))
+	for _, path := range paths {
 		data, err := os.ReadFile(path)
 		if err != nil {
-			log.Printf("Warning: Error reading probe file %s: %v", path, err)
-			return nil // Continue walking
+			if os.IsNotExist(err) {
+				continue // Published while listing
+			}
+			return nil, fmt.Errorf("failed to read outbox event: %w", err)
 		}
+		var event OutboxEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling outbox event from file", "path", path, "error", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	sortOutboxEvents(events)
+	return events, nil
+}
+
+// DeleteOutboxEvent removes a published event.
+func (l *LocalProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	err := os.Remove(l.outboxEventPath(id))
+	if os.IsNotExist(err) {
+		return outboxEventNotFound(id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove outbox event: %w", err)
+	}
+	return nil
+}
 
-		var probe v1.ProbeObject
-		if err := json.Unmarshal(data, &probe); err != nil {
-			log.Printf("Warning: Error unmarshaling probe from file %s: %v", path, err)
-			return nil // Continue walking
+func (l *LocalProbeStore) recordPath(kind, id string) string {
+	return filepath.Join(l.Directory, localRecordsDir, kind, recordName(id)+".record")
+}
+
+// PutRecord stores the record in the directory of its kind, under the
+// records directory, so that writing it does not change the store's
+// directory.
+func (l *LocalProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	path := l.recordPath(kind, record.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create record directory: %w", err)
+	}
+	return writeFileAtomic(ctx, path, data)
+}
+
+// CreateRecord stores the record unless one with the same ID is stored. The
+// file is linked into place, which fails if it exists.
+func (l *LocalProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	path := l.recordPath(kind, record.ID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create record directory: %w", err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), ".create-*")
+	if err != nil {
+		return fmt.Errorf("failed to create record: %w", err)
+	}
+	defer os.Remove(temp.Name()) //nolint:errcheck
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write record: %w", err)
+	}
+	if err := os.Link(temp.Name(), path); err != nil {
+		if os.IsExist(err) {
+			return recordExists(kind, record.ID)
 		}
+		return fmt.Errorf("failed to finalize record: %w", err)
+	}
+	return nil
+}
 
-		// Check if this probe has the URL hash we're looking for
-		// Exclude probes in terminating or failed status
-		if probe.Labels != nil {
-			if hashValue, exists := (*probe.Labels)[probeURLHashLabelKey]; exists && hashValue == urlHashString {
-				if probe.Status != v1.Terminating && probe.Status != v1.Failed {
-					found = true
-					return filepath.SkipAll // Stop walking, we found what we need
-				}
+// GetRecord returns a stored record.
+func (l *LocalProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	data, err := os.ReadFile(l.recordPath(kind, id))
+	if os.IsNotExist(err) {
+		return nil, recordNotFound(kind, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record: %w", err)
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return &record, nil
+}
+
+// ListRecords returns the stored records of the kind.
+func (l *LocalProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(l.Directory, localRecordsDir, kind, "*.record"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	records := make([]Record, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Deleted while listing
 			}
+			return nil, fmt.Errorf("failed to read record: %w", err)
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling record from file", "path", path, "error", err)
+			continue
 		}
+		records = append(records, record)
+	}
+	sortRecords(records)
+	return records, nil
+}
 
-		return nil
-	})
+// DeleteRecord removes a stored record.
+func (l *LocalProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	err := os.Remove(l.recordPath(kind, id))
+	if os.IsNotExist(err) {
+		return recordNotFound(kind, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove record: %w", err)
+	}
+	return nil
+}
 
-	if walkErr != nil {
-		return false, fmt.Errorf("error checking for existing probe with URL hash: %w", walkErr)
+// ProbeWithURLHashExists checks if a live probe with the given URL hash
+// already exists. It is answered from the index, without reading any file.
+// Behind an IndexedProbeStore it is only called until that index is built.
+func (l *LocalProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	l.index.mu.Lock()
+	defer l.index.mu.Unlock()
+	if err := l.syncIndex(ctx); err != nil {
+		return false, fmt.Errorf("error checking for existing probe with URL hash: %w", err)
 	}
+	return l.index.urlHashLive(urlHashString), nil
+}
+
+// fileVersion is the resource version of a probe file: a hash of its
+// contents, so any change to the file changes the version.
+func fileVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
 
-	return found, nil
+// writeFileAtomic writes data to a temporary file and renames it over path,
+// so readers never see a partially written probe.
+func writeFileAtomic(ctx context.Context, path string, data []byte) (err error) {
+	_, span := tracing.Tracer().Start(ctx, "local.WriteFile", trace.WithAttributes(attribute.String("local.path", path)))
+	defer func() { end(span, err) }()
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath) //nolint:errcheck
+		return fmt.Errorf("failed to finalize file: %w", err)
+	}
+	return nil
 }
 
 // GarbageCollectStaleProbes is a no-op for the local probe store since it's
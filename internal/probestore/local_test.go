@@ -2,6 +2,7 @@ package probestore
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -699,3 +700,53 @@ func TestLocalProbeStore_DeleteProbe(t *testing.T) {
 		})
 	}
 }
+
+func FuzzLocalProbeStore_ProbeJSON(f *testing.F) {
+	valid, err := json.Marshal(createTestProbe(uuid.New()))
+	require.NoError(f, err)
+	for _, seed := range [][]byte{
+		valid,
+		[]byte(`{}`),
+		[]byte(`{"id":"not-a-uuid"}`),
+		[]byte(`{"status":"terminating","labels":{"env":null}}`),
+		[]byte(`{"labels":["env"]}`),
+		[]byte(`null`),
+		[]byte(`{not-a-valid-json`),
+		{},
+	} {
+		f.Add(seed)
+	}
+
+	store, err := NewLocalProbeStoreWithDir(f.TempDir())
+	require.NoError(f, err)
+	ctx := context.Background()
+	probeID := uuid.New()
+	filePath := filepath.Join(store.Directory, probeID.String()+".json")
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		require.NoError(t, os.WriteFile(filePath, data, 0o644))
+		t.Cleanup(func() { os.Remove(filePath) }) //nolint:errcheck
+
+		var want v1.ProbeObject
+		decodeErr := json.Unmarshal(data, &want)
+		withResourceVersion(&want, fileVersion(data))
+
+		probe, err := store.GetProbe(ctx, probeID)
+		if decodeErr != nil {
+			assert.Error(t, err)
+		} else {
+			require.NoError(t, err)
+			assert.Equal(t, want, *probe)
+		}
+
+		// Corrupted files are skipped, never fatal to a listing.
+		probes, err := store.ListProbes(ctx, "")
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(probes), 1)
+
+		_, err = store.ProbeWithURLHashExists(ctx, "hash")
+		require.NoError(t, err)
+
+		_ = store.DeleteProbe(ctx, probeID)
+	})
+}
@@ -0,0 +1,474 @@
+package probestore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// localIndexDir is the directory, inside the store's, the index files are
+	// written to, so that writing them does not change the store's directory.
+	localIndexDir  = ".index"
+	localIndexFile = "probes.index"
+	// localIndexLog is the file the changes made since the index file was
+	// written are appended to.
+	localIndexLog = "probes.log"
+	// localIndexVersion is the version of the index file's format. Index
+	// files of another version are rebuilt from the probe files.
+	localIndexVersion = 2
+	// localIndexMinCompaction is the number of logged changes below which
+	// the log is not folded into the index file.
+	localIndexMinCompaction = 1024
+
+	// localIndexName labels the metrics of the index.
+	localIndexName = "local"
+	// localIndexEntryBytes and localIndexLabelBytes estimate the memory taken
+	// by an entry and by each of its labels, besides their strings, with the
+	// overhead of the maps holding them.
+	localIndexEntryBytes = 176
+	localIndexLabelBytes = 48
+)
+
+// localIndex keeps the labels, URL hash, status and static URL of each probe
+// file, by probe ID, so that listings and searches only read the files that
+// match, and URL hash checks none.
+//
+// It is persisted as the index file, written in full, and a log the changes
+// made since are appended to, one line each. Appending keeps the cost of a
+// probe write independent of the number of probes; the log is folded into
+// a new index file once it holds as many changes as there are probes, so
+// the full rewrites amount to a constant cost per write. The index is
+// trusted while the directory's modification time is the one it was
+// persisted with. When the directory changed otherwise, on restart, after a
+// crash or when a file was added or removed by hand, the probe files are
+// stated and only those whose modification time or size changed are read
+// again.
+//
+// Each use of the index counts as a hit of the
+// rhobs_synthetics_api_probestore_index metrics, labelled index="local",
+// when the directory is unchanged, and as a miss when it has to be synced.
+type localIndex struct {
+	mu     sync.Mutex
+	loaded bool
+	// dirModTime is the modification time of the directory the entries
+	// match.
+	dirModTime time.Time
+	entries    map[string]localIndexEntry
+	// generation identifies the index file, so that the log lines written
+	// for another one are ignored.
+	generation string
+	// logging is set while the index file and the log hold every change, so
+	// that the next one can be appended. It is cleared when persisting a
+	// change fails, so that the next one rewrites the index file instead of
+	// leaving a gap in the log.
+	logging bool
+	// logged is the number of changes in the log.
+	logged int
+	// bytes estimates the memory taken by the entries.
+	bytes int
+}
+
+// localIndexEntry is what the index knows of a probe file as of its
+// modification time and size. Files that cannot be read or decoded are
+// indexed as invalid, so that they are not read again until they change.
+type localIndexEntry struct {
+	ModTime   time.Time         `json:"mod_time"`
+	Size      int64             `json:"size"`
+	Invalid   bool              `json:"invalid,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	URLHash   string            `json:"url_hash,omitempty"`
+	Status    v1.StatusSchema   `json:"status,omitempty"`
+	StaticURL string            `json:"static_url,omitempty"`
+}
+
+// localIndexSnapshot is the content of the index file.
+type localIndexSnapshot struct {
+	Version    int                        `json:"version"`
+	Generation string                     `json:"generation"`
+	DirModTime time.Time                  `json:"dir_mod_time"`
+	Probes     map[string]localIndexEntry `json:"probes"`
+}
+
+// localIndexChange is a line of the log. A nil entry removes the probe.
+type localIndexChange struct {
+	Generation string           `json:"generation"`
+	ID         string           `json:"id"`
+	Entry      *localIndexEntry `json:"entry,omitempty"`
+	DirModTime time.Time        `json:"dir_mod_time"`
+}
+
+func newLocalIndexEntry(info fs.FileInfo, probe *v1.ProbeObject) localIndexEntry {
+	entry := localIndexEntry{ModTime: info.ModTime(), Size: info.Size(), Invalid: probe == nil}
+	if probe != nil {
+		entry.Status = probe.Status
+		entry.StaticURL = probe.StaticUrl
+		if probe.Labels != nil {
+			entry.Labels = maps.Clone(*probe.Labels)
+			entry.URLHash = (*probe.Labels)[probeURLHashLabelKey]
+		}
+	}
+	return entry
+}
+
+func (e localIndexEntry) live() bool {
+	return !e.Invalid && e.URLHash != "" && isLiveStatus(e.Status)
+}
+
+// size estimates the memory taken by the entry of the probe id.
+func (e localIndexEntry) size(id string) int {
+	n := localIndexEntryBytes + len(id) + len(e.URLHash) + len(e.Status) + len(e.StaticURL)
+	for key, value := range e.Labels {
+		n += localIndexLabelBytes + len(key) + len(value)
+	}
+	return n
+}
+
+// probe returns the indexed fields of the probe, for matching.
+func (e localIndexEntry) probe() v1.ProbeObject {
+	probeLabels := v1.LabelsSchema(e.Labels)
+	return v1.ProbeObject{StaticUrl: e.StaticURL, Status: e.Status, Labels: &probeLabels}
+}
+
+// urlHashLive reports whether a live probe has the URL hash. It scans the
+// entries: an IndexedProbeStore answers creates from its own index of URL
+// hashes once it is built, so this only serves until then.
+func (x *localIndex) urlHashLive(urlHash string) bool {
+	for _, entry := range x.entries {
+		if entry.live() && entry.URLHash == urlHash {
+			return true
+		}
+	}
+	return false
+}
+
+// put indexes the entry of the probe id, replacing any other.
+func (x *localIndex) put(id string, entry localIndexEntry) {
+	x.drop(id)
+	x.entries[id] = entry
+	x.bytes += entry.size(id)
+}
+
+// drop removes the probe id from the index.
+func (x *localIndex) drop(id string) {
+	if entry, ok := x.entries[id]; ok {
+		x.bytes -= entry.size(id)
+		delete(x.entries, id)
+	}
+}
+
+// report exports the size of the index.
+func (x *localIndex) report() {
+	metrics.SetProbestoreIndexSize(localIndexName, len(x.entries), x.bytes)
+}
+
+func (l *LocalProbeStore) indexPath() string {
+	return filepath.Join(l.Directory, localIndexDir, localIndexFile)
+}
+
+func (l *LocalProbeStore) indexLogPath() string {
+	return filepath.Join(l.Directory, localIndexDir, localIndexLog)
+}
+
+// loadIndex reads the index file and replays the log, if any, the first
+// time the index is used. An unreadable index file is rebuilt from the
+// probe files.
+func (l *LocalProbeStore) loadIndex(ctx context.Context) {
+	x := &l.index
+	x.loaded = true
+	x.entries = make(map[string]localIndexEntry)
+	x.bytes = 0
+	// Created before the directory's modification time is first read, as
+	// creating it changes it.
+	if err := os.MkdirAll(filepath.Dir(l.indexPath()), 0755); err != nil {
+		slog.WarnContext(ctx, "Failed to create local probe index directory", "error", err)
+		return
+	}
+
+	data, err := os.ReadFile(l.indexPath())
+	if os.IsNotExist(err) {
+		return
+	}
+	var snapshot localIndexSnapshot
+	if err == nil {
+		err = json.Unmarshal(data, &snapshot)
+	}
+	if err == nil && snapshot.Version != localIndexVersion {
+		err = fmt.Errorf("index version %d, expected %d", snapshot.Version, localIndexVersion)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Rebuilding unreadable local probe index", "path", l.indexPath(), "error", err)
+		return
+	}
+	for id, entry := range snapshot.Probes {
+		x.put(id, entry)
+	}
+	x.dirModTime = snapshot.DirModTime
+	x.generation = snapshot.Generation
+	x.logging = true
+
+	data, err = os.ReadFile(l.indexLogPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.WarnContext(ctx, "Failed to read local probe index log", "path", l.indexLogPath(), "error", err)
+			x.logging = false
+		}
+		return
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		var change localIndexChange
+		if err := json.Unmarshal(scanner.Bytes(), &change); err != nil {
+			// A line cut short by a crash ends the log. The directory has
+			// changed since the last whole line, so it is synced, and the
+			// next change rewrites the index file.
+			x.logging = false
+			break
+		}
+		if change.Generation != x.generation {
+			continue
+		}
+		if change.Entry == nil {
+			x.drop(change.ID)
+		} else {
+			x.put(change.ID, *change.Entry)
+		}
+		x.dirModTime = change.DirModTime
+		x.logged++
+	}
+}
+
+// syncIndex brings the index up to date with the directory; l.index.mu must
+// be held.
+func (l *LocalProbeStore) syncIndex(ctx context.Context) error {
+	x := &l.index
+	if !x.loaded {
+		l.loadIndex(ctx)
+	}
+	dir, err := os.Stat(l.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to check probe store directory: %w", err)
+	}
+	if dir.ModTime().Equal(x.dirModTime) {
+		metrics.RecordProbestoreIndexLookup(localIndexName, true)
+		metrics.RecordProbestoreIndexSync(localIndexName, time.Now())
+		return nil
+	}
+	metrics.RecordProbestoreIndexLookup(localIndexName, false)
+
+	_, span := tracing.Tracer().Start(ctx, "local.SyncIndex", trace.WithAttributes(attribute.String("local.directory", l.Directory)))
+	entries, err := os.ReadDir(l.Directory)
+	if err != nil {
+		end(span, err)
+		return fmt.Errorf("failed to read probe store directory: %w", err)
+	}
+	seen := make(map[string]bool, len(entries))
+	filesRead := 0
+	for _, d := range entries {
+		name := d.Name()
+		if d.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Removed since the directory was read.
+			continue
+		}
+		id := strings.TrimSuffix(name, ".json")
+		seen[id] = true
+		if entry, ok := x.entries[id]; ok && entry.ModTime.Equal(info.ModTime()) && entry.Size == info.Size() {
+			continue
+		}
+		filesRead++
+		probe, _ := readProbeFile(ctx, filepath.Join(l.Directory, name))
+		x.put(id, newLocalIndexEntry(info, probe))
+	}
+	removed := 0
+	for id := range x.entries {
+		if !seen[id] {
+			x.drop(id)
+			removed++
+		}
+	}
+	span.SetAttributes(attribute.Int("local.files_read", filesRead), attribute.Int("local.files_indexed", len(x.entries)))
+	end(span, nil)
+
+	x.dirModTime = dir.ModTime()
+	x.report()
+	metrics.RecordProbestoreIndexSync(localIndexName, time.Now())
+	if filesRead > 0 || removed > 0 || !x.logging {
+		l.saveIndex(ctx)
+	}
+	return nil
+}
+
+// indexProbeFile records a probe file the store wrote, or removed when probe
+// is nil; l.index.mu must be held, and the index synced before the write.
+//
+// The change is appended to the log, and the index file rewritten instead
+// once the log holds as many changes as there are probes. A failure to
+// persist the change is only logged: the directory then no longer matches
+// the persisted index, which is synced again on restart.
+func (l *LocalProbeStore) indexProbeFile(ctx context.Context, path string, probe *v1.ProbeObject) {
+	x := &l.index
+	id := strings.TrimSuffix(filepath.Base(path), ".json")
+	change := localIndexChange{Generation: x.generation, ID: id}
+	info, err := os.Stat(path)
+	if probe == nil || err != nil {
+		x.drop(id)
+	} else {
+		entry := newLocalIndexEntry(info, probe)
+		x.put(id, entry)
+		change.Entry = &entry
+	}
+	x.report()
+	if dir, err := os.Stat(l.Directory); err == nil {
+		x.dirModTime = dir.ModTime()
+	}
+	change.DirModTime = x.dirModTime
+
+	if x.logging && x.logged < max(localIndexMinCompaction, len(x.entries)) {
+		err := l.appendIndexLog(change)
+		if err == nil {
+			x.logged++
+			return
+		}
+		slog.WarnContext(ctx, "Failed to append to local probe index log, rewriting the index", "path", l.indexLogPath(), "error", err)
+	}
+	l.saveIndex(ctx)
+}
+
+func (l *LocalProbeStore) appendIndexLog(change localIndexChange) error {
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.indexLogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return errors.Join(err, f.Close())
+}
+
+// saveIndex writes the index file under a new generation, which leaves the
+// lines of the log behind, and empties the log.
+func (l *LocalProbeStore) saveIndex(ctx context.Context) {
+	x := &l.index
+	generation := uuid.NewString()
+	data, err := json.Marshal(localIndexSnapshot{Version: localIndexVersion, Generation: generation, DirModTime: x.dirModTime, Probes: x.entries})
+	if err == nil {
+		err = writeFileAtomic(ctx, l.indexPath(), data)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to write local probe index", "path", l.indexPath(), "error", err)
+		x.logging = false
+		return
+	}
+	x.generation = generation
+	x.logging = true
+	x.logged = 0
+	if err := os.Truncate(l.indexLogPath(), 0); err != nil && !os.IsNotExist(err) {
+		slog.WarnContext(ctx, "Failed to empty local probe index log", "path", l.indexLogPath(), "error", err)
+	}
+}
+
+// readIndexed reads the probe files whose indexed fields match, and keeps
+// the probes that still match once read, in case a file changed since it
+// was indexed. The span, named spanName, records how many files were read.
+func (l *LocalProbeStore) readIndexed(ctx context.Context, spanName string, matches func(v1.ProbeObject) bool) ([]v1.ProbeObject, error) {
+	l.index.mu.Lock()
+	if err := l.syncIndex(ctx); err != nil {
+		l.index.mu.Unlock()
+		return nil, fmt.Errorf("failed to index probe store directory: %w", err)
+	}
+	var matching []string
+	skippedFiles := 0
+	for _, id := range slices.Sorted(maps.Keys(l.index.entries)) {
+		entry := l.index.entries[id]
+		if entry.Invalid {
+			skippedFiles++
+		} else if matches(entry.probe()) {
+			matching = append(matching, id)
+		}
+	}
+	filesIndexed := len(l.index.entries)
+	l.index.mu.Unlock()
+
+	probes := []v1.ProbeObject{}
+	_, span := tracing.Tracer().Start(ctx, spanName, trace.WithAttributes(attribute.String("local.directory", l.Directory)))
+	for _, id := range matching {
+		probe, err := readProbeFile(ctx, filepath.Join(l.Directory, id+".json"))
+		if err != nil {
+			skippedFiles++
+			continue
+		}
+		if matches(*probe) {
+			probes = append(probes, *probe)
+		}
+	}
+	span.SetAttributes(attribute.Int("local.files_read", len(matching)), attribute.Int("local.files_indexed", filesIndexed))
+	end(span, nil)
+
+	if skippedFiles > 0 {
+		slog.WarnContext(ctx, "Skipped corrupted or unreadable probe files", "count", skippedFiles)
+	}
+	return probes, nil
+}
+
+// SearchProbes returns the probes matching the label selector and the query.
+// Both are matched against the index, so only the matching files are read.
+func (l *LocalProbeStore) SearchProbes(ctx context.Context, selector string, query SearchQuery) ([]v1.ProbeObject, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector: %w", err)
+	}
+	return l.readIndexed(ctx, "local.SearchIndex", func(probe v1.ProbeObject) bool {
+		return sel.Matches(probeLabelSet(probe)) && query.matches(searchValues(probe))
+	})
+}
+
+// probeLabelSet returns the probe's labels as a label set.
+func probeLabelSet(probe v1.ProbeObject) labels.Set {
+	if probe.Labels == nil {
+		return labels.Set{}
+	}
+	return labels.Set(*probe.Labels)
+}
+
+// readProbeFile reads and decodes a probe file, logging why it cannot.
+func readProbeFile(ctx context.Context, path string) (*v1.ProbeObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		slog.WarnContext(ctx, "Error reading probe file", "path", path, "error", err)
+		return nil, err
+	}
+	var probe v1.ProbeObject
+	if err := json.Unmarshal(data, &probe); err != nil {
+		slog.WarnContext(ctx, "Error unmarshaling probe from file", "path", path, "error", err)
+		return nil, err
+	}
+	withResourceVersion(&probe, fileVersion(data))
+	return &probe, nil
+}
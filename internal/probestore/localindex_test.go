@@ -0,0 +1,155 @@
+package probestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalProbeStore_Index(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewLocalProbeStoreWithDir(dir)
+	require.NoError(t, err)
+
+	prod, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://prod.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "prod"}}, "prod-hash")
+	require.NoError(t, err)
+	stage, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://stage.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "stage"}}, "stage-hash")
+	require.NoError(t, err)
+	assert.FileExists(t, store.indexPath())
+
+	t.Run("writes are indexed", func(t *testing.T) {
+		stage.Status = v1.Failed
+		stage, err = store.UpdateProbe(ctx, *stage)
+		require.NoError(t, err)
+		exists, err := store.ProbeWithURLHashExists(ctx, "stage-hash")
+		require.NoError(t, err)
+		assert.False(t, exists, "failed probes do not hold their URL")
+		exists, err = store.ProbeWithURLHashExists(ctx, "prod-hash")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		probes, err := store.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		require.Len(t, probes, 1)
+		assert.Equal(t, prod.Id, probes[0].Id)
+		assert.Equal(t, prod.ResourceVersion, probes[0].ResourceVersion)
+	})
+
+	t.Run("files unchanged since they were indexed are not read again", func(t *testing.T) {
+		reopened, err := NewLocalProbeStoreWithDir(dir)
+		require.NoError(t, err)
+		// Overwritten with the same size and modification time, the file
+		// looks unchanged, so the index answers for it without reading it.
+		path := filepath.Join(dir, prod.Id.String()+".json")
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte(" "), int(info.Size())), 0644))
+		require.NoError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+
+		exists, err := reopened.ProbeWithURLHashExists(ctx, "prod-hash")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Len(t, reopened.index.entries, 2)
+		require.NoError(t, os.Remove(path))
+	})
+
+	t.Run("files changed by hand are indexed again", func(t *testing.T) {
+		added := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://added.example.com", Status: v1.Pending, Labels: &v1.LabelsSchema{"env": "prod", probeURLHashLabelKey: "added-hash"}}
+		data, err := json.Marshal(added)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, added.Id.String()+".json"), data, 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupted.json"), []byte("{"), 0644))
+
+		probes, err := store.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		require.Len(t, probes, 1, "the removed probe is gone and the corrupted file skipped")
+		assert.Equal(t, added.Id, probes[0].Id)
+		exists, err := store.ProbeWithURLHashExists(ctx, "added-hash")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		exists, err = store.ProbeWithURLHashExists(ctx, "prod-hash")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("writes are appended to the log and folded into the index file", func(t *testing.T) {
+		reopened, err := NewLocalProbeStoreWithDir(dir)
+		require.NoError(t, err)
+		_, err = reopened.ListProbes(ctx, "")
+		require.NoError(t, err)
+		generation := reopened.index.generation
+		probe, err := reopened.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://logged.example.com", Status: v1.Pending}, "logged-hash")
+		require.NoError(t, err)
+		assert.Equal(t, generation, reopened.index.generation, "the index file is not rewritten")
+		assert.Equal(t, 1, reopened.index.logged)
+
+		// Replayed from the log, the index matches the directory, so the
+		// probe files are not stated again.
+		replayed := &LocalProbeStore{Directory: dir}
+		replayed.index.mu.Lock()
+		replayed.loadIndex(ctx)
+		replayed.index.mu.Unlock()
+		require.Contains(t, replayed.index.entries, probe.Id.String())
+		assert.Equal(t, "logged-hash", replayed.index.entries[probe.Id.String()].URLHash)
+		assert.Len(t, replayed.index.entries, len(reopened.index.entries))
+		assert.True(t, reopened.index.dirModTime.Equal(replayed.index.dirModTime))
+
+		for range localIndexMinCompaction {
+			probe, err = reopened.UpdateProbe(ctx, *probe)
+			require.NoError(t, err)
+		}
+		assert.NotEqual(t, generation, reopened.index.generation, "a full log is folded into a new index file")
+		assert.Less(t, reopened.index.logged, localIndexMinCompaction)
+		require.NoError(t, reopened.DeleteProbeStorage(ctx, probe.Id))
+		assert.Equal(t, indexedBytes(reopened.index.entries), reopened.index.bytes, "the memory estimate follows the writes")
+		assert.Equal(t, indexedBytes(replayed.index.entries), replayed.index.bytes, "and the replayed log")
+	})
+
+	t.Run("a log line cut short ends the log", func(t *testing.T) {
+		f, err := os.OpenFile(store.indexLogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		require.NoError(t, err)
+		_, err = f.WriteString(`{"generation":`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		reopened, err := NewLocalProbeStoreWithDir(dir)
+		require.NoError(t, err)
+		probes, err := reopened.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		assert.Len(t, probes, 1)
+		assert.True(t, reopened.index.logging, "the index file is rewritten rather than appended to after the cut")
+		data, err := os.ReadFile(store.indexLogPath())
+		require.NoError(t, err)
+		assert.Empty(t, data)
+	})
+
+	t.Run("an unreadable index file is rebuilt", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(store.indexPath(), []byte("{"), 0644))
+		reopened, err := NewLocalProbeStoreWithDir(dir)
+		require.NoError(t, err)
+		probes, err := reopened.ListProbes(ctx, "")
+		require.NoError(t, err)
+		assert.Len(t, probes, 2)
+		exists, err := reopened.ProbeWithURLHashExists(ctx, "added-hash")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
+
+// indexedBytes adds the sizes of the entries up.
+func indexedBytes(entries map[string]localIndexEntry) int {
+	n := 0
+	for id, entry := range entries {
+		n += entry.size(id)
+	}
+	return n
+}
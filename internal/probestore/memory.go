@@ -0,0 +1,674 @@
+package probestore
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultSnapshotInterval is how often a memory store with a snapshot file
+// writes it when no interval is set.
+const DefaultSnapshotInterval = 30 * time.Second
+
+// Persister is implemented by stores that keep their probes in memory and
+// write them out in the background. RunPersistence writes them periodically
+// until ctx is cancelled, and once more before it returns.
+type Persister interface {
+	RunPersistence(ctx context.Context)
+}
+
+// MemoryProbeStore implements the ProbeStorage interface in memory, for tests
+// and demos: it reads no files on each request, lists probes in a stable
+// order, and gives each write the next of a single sequence of resource
+// versions. Probes are copied in and out, so callers never share them with
+// the store. It also keeps tombstones, API keys, the event outbox and
+// records.
+//
+// With a snapshot file, everything it holds is written to that file every
+// SnapshotInterval while RunPersistence runs, and restored from it by
+// OpenMemoryProbeStore. Changes made since the last snapshot are lost if the
+// process dies.
+type MemoryProbeStore struct {
+	// SnapshotFile is the JSON file the store is written to; empty keeps
+	// nothing across restarts.
+	SnapshotFile string
+	// SnapshotInterval is how often the snapshot file is written; zero
+	// selects DefaultSnapshotInterval.
+	SnapshotInterval time.Duration
+	// TombstoneTTL is how long tombstones of removed probes are kept; zero
+	// selects the default.
+	TombstoneTTL time.Duration
+
+	mu         sync.RWMutex
+	probes     map[uuid.UUID]v1.ProbeObject
+	tombstones map[uuid.UUID]Tombstone
+	apiKeys    map[string]APIKey
+	outbox     map[string]OutboxEvent
+	records    map[string]map[string]Record
+	// version is the last resource version given to a probe.
+	version int64
+	// dirty is set by writes made since the last snapshot.
+	dirty bool
+}
+
+// memorySnapshot is the content of a snapshot file. The probes keep their
+// resource versions.
+type memorySnapshot struct {
+	Version      int64            `json:"version"`
+	Probes       []v1.ProbeObject `json:"probes"`
+	Tombstones   []Tombstone      `json:"tombstones,omitempty"`
+	APIKeys      []APIKey         `json:"api_keys,omitempty"`
+	OutboxEvents []OutboxEvent    `json:"outbox_events,omitempty"`
+	// Records are the records of each kind.
+	Records map[string][]Record `json:"records,omitempty"`
+}
+
+// NewMemoryProbeStore creates an empty MemoryProbeStore without a snapshot
+// file.
+func NewMemoryProbeStore() *MemoryProbeStore {
+	return &MemoryProbeStore{
+		TombstoneTTL: tombstoneTTLFromEnv(),
+		probes:       map[uuid.UUID]v1.ProbeObject{},
+		tombstones:   map[uuid.UUID]Tombstone{},
+		apiKeys:      map[string]APIKey{},
+		outbox:       map[string]OutboxEvent{},
+		records:      map[string]map[string]Record{},
+	}
+}
+
+// OpenMemoryProbeStore creates a MemoryProbeStore from its config, restoring
+// the snapshot file if it exists. The file is written right away, so a path
+// that cannot be written is reported now rather than at the first snapshot.
+func OpenMemoryProbeStore(cfg MemoryConfig) (*MemoryProbeStore, error) {
+	if cfg.SnapshotInterval < 0 {
+		return nil, fmt.Errorf("snapshot interval must not be negative, got %s", cfg.SnapshotInterval)
+	}
+	store := NewMemoryProbeStore()
+	store.SnapshotFile = cfg.SnapshotFile
+	store.SnapshotInterval = cfg.SnapshotInterval
+	if store.SnapshotFile == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(store.SnapshotFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		slog.Info("No probe store snapshot yet, starting empty", "file", store.SnapshotFile)
+	case err != nil:
+		return nil, fmt.Errorf("failed to read probe store snapshot: %w", err)
+	default:
+		if err := store.restore(data); err != nil {
+			return nil, fmt.Errorf("failed to restore probe store snapshot %s: %w", store.SnapshotFile, err)
+		}
+		slog.Info("Restored probe store snapshot", "file", store.SnapshotFile, "probes", len(store.probes))
+	}
+	store.dirty = true
+	if err := store.SaveSnapshot(context.Background()); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// restore replaces the content of the store with a snapshot.
+func (m *MemoryProbeStore) restore(data []byte) error {
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.version = snapshot.Version
+	for _, probe := range snapshot.Probes {
+		if probe.Id == (uuid.UUID{}) {
+			return fmt.Errorf("probe without an ID")
+		}
+		m.probes[probe.Id] = probe
+	}
+	for _, tombstone := range snapshot.Tombstones {
+		m.tombstones[tombstone.ProbeID] = tombstone
+	}
+	for _, key := range snapshot.APIKeys {
+		m.apiKeys[key.ID] = key
+	}
+	for _, event := range snapshot.OutboxEvents {
+		m.outbox[event.ID] = event
+	}
+	for kind, records := range snapshot.Records {
+		for _, record := range records {
+			m.putRecord(kind, record)
+		}
+	}
+	return nil
+}
+
+// SaveSnapshot writes the store to its snapshot file if it changed since the
+// last snapshot. It does nothing without a snapshot file.
+func (m *MemoryProbeStore) SaveSnapshot(ctx context.Context) error {
+	if m.SnapshotFile == "" {
+		return nil
+	}
+	m.mu.Lock()
+	if !m.dirty {
+		m.mu.Unlock()
+		return nil
+	}
+	snapshot := memorySnapshot{
+		Version:      m.version,
+		Probes:       slices.SortedFunc(maps.Values(m.probes), compareProbes),
+		APIKeys:      slices.Collect(maps.Values(m.apiKeys)),
+		OutboxEvents: slices.Collect(maps.Values(m.outbox)),
+	}
+	for kind, records := range m.records {
+		if len(records) == 0 {
+			continue
+		}
+		if snapshot.Records == nil {
+			snapshot.Records = map[string][]Record{}
+		}
+		snapshot.Records[kind] = slices.Collect(maps.Values(records))
+	}
+	for id, tombstone := range m.tombstones {
+		if tombstone.expired(m.TombstoneTTL) {
+			delete(m.tombstones, id)
+			continue
+		}
+		snapshot.Tombstones = append(snapshot.Tombstones, tombstone)
+	}
+	m.dirty = false
+	m.mu.Unlock()
+
+	slices.SortFunc(snapshot.Tombstones, func(a, b Tombstone) int { return cmp.Compare(a.ProbeID.String(), b.ProbeID.String()) })
+	sortAPIKeys(snapshot.APIKeys)
+	sortOutboxEvents(snapshot.OutboxEvents)
+	for _, records := range snapshot.Records {
+		sortRecords(records)
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(ctx, m.SnapshotFile, data)
+	}
+	if err != nil {
+		// The changes are written with the next snapshot instead.
+		m.mu.Lock()
+		m.dirty = true
+		m.mu.Unlock()
+		return fmt.Errorf("failed to write probe store snapshot: %w", err)
+	}
+	slog.DebugContext(ctx, "Wrote probe store snapshot", "file", m.SnapshotFile, "probes", len(snapshot.Probes))
+	return nil
+}
+
+// RunPersistence writes the snapshot file every SnapshotInterval until ctx is
+// cancelled, and once more before it returns. It returns right away without
+// a snapshot file.
+func (m *MemoryProbeStore) RunPersistence(ctx context.Context) {
+	if m.SnapshotFile == "" {
+		return
+	}
+	interval := cmp.Or(m.SnapshotInterval, DefaultSnapshotInterval)
+	slog.InfoContext(ctx, "Starting probe store snapshots", "file", m.SnapshotFile, "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := m.SaveSnapshot(context.WithoutCancel(ctx)); err != nil {
+				slog.ErrorContext(ctx, "Failed to write the final probe store snapshot", "error", err)
+			}
+			return
+		case <-ticker.C:
+			if err := m.SaveSnapshot(ctx); err != nil {
+				slog.ErrorContext(ctx, "Failed to write probe store snapshot", "error", err)
+			}
+		}
+	}
+}
+
+// copyProbe returns a deep copy of probe, so that the store and its callers
+// never share labels or any other field.
+func copyProbe(probe v1.ProbeObject) (v1.ProbeObject, error) {
+	data, err := json.Marshal(probe)
+	if err != nil {
+		return v1.ProbeObject{}, fmt.Errorf("failed to marshal probe: %w", err)
+	}
+	var copied v1.ProbeObject
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return v1.ProbeObject{}, fmt.Errorf("failed to unmarshal probe: %w", err)
+	}
+	return copied, nil
+}
+
+// ListProbes lists all probes that match the given label selector, ordered by
+// creation, then ID.
+func (m *MemoryProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector: %w", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	probes := []v1.ProbeObject{}
+	for _, probe := range m.probes {
+		probeLabels := labels.Set{}
+		if probe.Labels != nil {
+			probeLabels = labels.Set(*probe.Labels)
+		}
+		if !sel.Matches(probeLabels) {
+			continue
+		}
+		copied, err := copyProbe(probe)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, copied)
+	}
+	slices.SortFunc(probes, compareProbes)
+	return probes, nil
+}
+
+// GetProbe retrieves a single probe by its ID.
+func (m *MemoryProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getProbe(probeID)
+}
+
+// getProbe returns a copy of a probe; m.mu must be held.
+func (m *MemoryProbeStore) getProbe(probeID uuid.UUID) (*v1.ProbeObject, error) {
+	probe, ok := m.probes[probeID]
+	if !ok {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+	}
+	copied, err := copyProbe(probe)
+	if err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// put stores a copy of probe at the next resource version and returns
+// another copy; m.mu must be held. Like Kubernetes, writing a probe unchanged
+// keeps its version.
+func (m *MemoryProbeStore) put(probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	if existing, ok := m.probes[probe.Id]; ok {
+		probe.ResourceVersion = existing.ResourceVersion
+		stored, err := copyProbe(probe)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.DeepEqual(stored, existing) {
+			return &stored, nil
+		}
+	}
+	m.version++
+	probe.ResourceVersion = new(strconv.FormatInt(m.version, 10))
+	stored, err := copyProbe(probe)
+	if err != nil {
+		return nil, err
+	}
+	m.probes[probe.Id] = stored
+	m.dirty = true
+	return &probe, nil
+}
+
+// liveProbeWithURLHash reports whether a live probe other than except has
+// the URL hash; m.mu must be held.
+func (m *MemoryProbeStore) liveProbeWithURLHash(urlHashString string, except uuid.UUID) bool {
+	for id, probe := range m.probes {
+		if id != except && isLiveStatus(probe.Status) && probe.Labels != nil && (*probe.Labels)[probeURLHashLabelKey] == urlHashString {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateProbe stores a new probe. A live probe with the same URL hash is
+// reported as an AlreadyExists error.
+func (m *MemoryProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+	if urlHashString == "" {
+		return nil, fmt.Errorf("URL hash cannot be empty")
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	probeLabels := v1.LabelsSchema{}
+	if probe.Labels != nil {
+		maps.Copy(probeLabels, *probe.Labels)
+	}
+	probeLabels[probeURLHashLabelKey] = urlHashString
+	probeLabels[baseAppLabelKey] = baseAppLabelValue
+	probeLabels[probeStatusLabelKey] = string(probe.Status)
+	probe.Labels = &probeLabels
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.probes[probe.Id]; ok {
+		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String())
+	}
+	if m.liveProbeWithURLHash(urlHashString, probe.Id) {
+		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+	}
+	created, err := m.put(probe)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	return created, nil
+}
+
+// UpdateProbe replaces an existing probe, provided it is at the version ctx
+// expects, if any. The URL hash label is immutable, and a probe cannot become
+// live again while another live probe has its URL.
+func (m *MemoryProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	existingProbe, err := m.getProbe(probe.Id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResourceVersion(ctx, probe.Id, resourceVersionOf(existingProbe)); err != nil {
+		return nil, err
+	}
+	withNextGeneration(&probe, *existingProbe)
+	keepCreationTimestamp(&probe, *existingProbe)
+	withUpdateTimestamp(&probe, *existingProbe)
+	withDeletionTimestamp(&probe, *existingProbe)
+	withStatusHistory(ctx, &probe, *existingProbe)
+	withURLHash(&probe, *existingProbe)
+
+	newLabels := v1.LabelsSchema{}
+	if probe.Labels != nil {
+		maps.Copy(newLabels, *probe.Labels)
+	}
+	newLabels[baseAppLabelKey] = baseAppLabelValue
+	newLabels[probeStatusLabelKey] = string(probe.Status)
+	delete(newLabels, probeURLHashLabelKey)
+	if existingProbe.Labels != nil {
+		if urlHash, ok := (*existingProbe.Labels)[probeURLHashLabelKey]; ok {
+			newLabels[probeURLHashLabelKey] = urlHash
+			if isLiveStatus(probe.Status) && !isLiveStatus(existingProbe.Status) && m.liveProbeWithURLHash(urlHash, probe.Id) {
+				return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+			}
+		}
+	}
+	probe.Labels = &newLabels
+
+	updated, err := m.put(probe)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return updated, nil
+}
+
+// DeleteProbe handles deletion based on probe status.
+func (m *MemoryProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	if probeID == (uuid.UUID{}) {
+		return fmt.Errorf("probe ID cannot be empty")
+	}
+
+	existingProbe, err := m.GetProbe(ctx, probeID)
+	if err != nil {
+		return err // Pass the error up, including not found errors
+	}
+	if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+		return err
+	}
+
+	switch existingProbe.Status {
+	case v1.Pending:
+		// Probe was never picked up by an agent, delete immediately
+		if err := m.DeleteProbeStorage(WithResourceVersion(ctx, resourceVersionOf(existingProbe)), probeID); err != nil {
+			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
+		return nil
+
+	case v1.Active:
+		// Probe is active, set to terminating and wait for agent cleanup
+		existingProbe.Status = v1.Terminating
+		ctx = WithResourceVersion(withStatusReason(ctx, deletionReason), resourceVersionOf(existingProbe))
+		if _, err := m.UpdateProbe(ctx, *existingProbe); err != nil {
+			return fmt.Errorf("failed to update probe %s to terminating status: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
+		return nil
+
+	case v1.Terminating:
+		// Already terminating, no action needed
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
+		return nil
+
+	case v1.Failed:
+		// Failed probe, delete immediately as agent likely won't process it
+		if err := m.DeleteProbeStorage(WithResourceVersion(ctx, resourceVersionOf(existingProbe)), probeID); err != nil {
+			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
+		return nil
+
+	default:
+		// Unknown status, treat as pending and delete immediately
+		if err := m.DeleteProbeStorage(WithResourceVersion(ctx, resourceVersionOf(existingProbe)), probeID); err != nil {
+			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), existingProbe.Status, err)
+		}
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", existingProbe.Status)
+		return nil
+	}
+}
+
+// DeleteProbeStorage removes a probe and leaves a tombstone.
+func (m *MemoryProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	if probeID == (uuid.UUID{}) {
+		return fmt.Errorf("probe ID cannot be empty")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	probe, ok := m.probes[probeID]
+	if !ok {
+		return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+	}
+	if err := checkResourceVersion(ctx, probeID, resourceVersionOf(&probe)); err != nil {
+		return err
+	}
+	delete(m.probes, probeID)
+	m.tombstones[probeID] = newTombstone(probeID)
+	m.dirty = true
+
+	slog.DebugContext(ctx, "Deleted probe", "probe_id", probeID)
+	return nil
+}
+
+// GetTombstone returns the tombstone of a recently removed probe. Expired
+// tombstones are removed when they are read.
+func (m *MemoryProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tombstone, ok := m.tombstones[probeID]
+	if !ok {
+		return nil, tombstoneNotFound(probeID)
+	}
+	if tombstone.expired(m.TombstoneTTL) {
+		delete(m.tombstones, probeID)
+		m.dirty = true
+		return nil, tombstoneNotFound(probeID)
+	}
+	return &tombstone, nil
+}
+
+// CreateAPIKey stores a new API key.
+func (m *MemoryProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.apiKeys[key.ID]; ok {
+		return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "apikeys"}, key.ID)
+	}
+	m.apiKeys[key.ID] = key
+	m.dirty = true
+	return nil
+}
+
+// ListAPIKeys returns the stored API keys.
+func (m *MemoryProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	m.mu.RLock()
+	keys := slices.Collect(maps.Values(m.apiKeys))
+	m.mu.RUnlock()
+	sortAPIKeys(keys)
+	return keys, nil
+}
+
+// DeleteAPIKey removes a stored API key.
+func (m *MemoryProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.apiKeys[id]; !ok {
+		return apiKeyNotFound(id)
+	}
+	delete(m.apiKeys, id)
+	m.dirty = true
+	return nil
+}
+
+// AddOutboxEvent stores an event to publish.
+func (m *MemoryProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outbox[event.ID] = event
+	m.dirty = true
+	return nil
+}
+
+// ListOutboxEvents returns the events waiting in the outbox.
+func (m *MemoryProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	m.mu.RLock()
+	events := slices.Collect(maps.Values(m.outbox))
+	m.mu.RUnlock()
+	sortOutboxEvents(events)
+	return events, nil
+}
+
+// DeleteOutboxEvent removes a published event.
+func (m *MemoryProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.outbox[id]; !ok {
+		return outboxEventNotFound(id)
+	}
+	delete(m.outbox, id)
+	m.dirty = true
+	return nil
+}
+
+// putRecord stores a copy of a record; m.mu must be held.
+func (m *MemoryProbeStore) putRecord(kind string, record Record) {
+	if m.records[kind] == nil {
+		m.records[kind] = map[string]Record{}
+	}
+	record.Data = slices.Clone(record.Data)
+	m.records[kind][record.ID] = record
+}
+
+// PutRecord stores a record.
+func (m *MemoryProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putRecord(kind, record)
+	m.dirty = true
+	return nil
+}
+
+// CreateRecord stores a record unless one with the same ID is stored.
+func (m *MemoryProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[kind][record.ID]; ok {
+		return recordExists(kind, record.ID)
+	}
+	m.putRecord(kind, record)
+	m.dirty = true
+	return nil
+}
+
+// GetRecord returns a stored record.
+func (m *MemoryProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	record, ok := m.records[kind][id]
+	if !ok {
+		return nil, recordNotFound(kind, id)
+	}
+	record.Data = slices.Clone(record.Data)
+	return &record, nil
+}
+
+// ListRecords returns the stored records of the kind.
+func (m *MemoryProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	m.mu.RLock()
+	records := make([]Record, 0, len(m.records[kind]))
+	for _, record := range m.records[kind] {
+		record.Data = slices.Clone(record.Data)
+		records = append(records, record)
+	}
+	m.mu.RUnlock()
+	sortRecords(records)
+	return records, nil
+}
+
+// DeleteRecord removes a stored record.
+func (m *MemoryProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[kind][id]; !ok {
+		return recordNotFound(kind, id)
+	}
+	delete(m.records[kind], id)
+	m.dirty = true
+	return nil
+}
+
+// ProbeWithURLHashExists checks if a live probe with the given URL hash
+// exists.
+func (m *MemoryProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.liveProbeWithURLHash(urlHashString, uuid.UUID{}), nil
+}
+
+// GarbageCollectStaleProbes is a no-op for the memory probe store, which like
+// the local one is only used for tests and demos.
+func (m *MemoryProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	return 0, nil
+}
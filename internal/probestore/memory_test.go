@@ -0,0 +1,153 @@
+package probestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestMemoryProbeStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryProbeStore()
+
+	var created []*v1.ProbeObject
+	for i, env := range []string{"prod", "stage", "prod"} {
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{
+			Id:        uuid.New(),
+			StaticUrl: "https://example.com/" + env,
+			Labels:    &v1.LabelsSchema{"env": env},
+			Status:    v1.Pending,
+		}, "hash-"+string(rune('a'+i)))
+		require.NoError(t, err)
+		created = append(created, probe)
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, []string{*created[0].ResourceVersion, *created[1].ResourceVersion, *created[2].ResourceVersion},
+		"writes take the next version of a single sequence")
+
+	t.Run("probes are listed in a stable order", func(t *testing.T) {
+		probes, err := store.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		require.Len(t, probes, 2)
+		assert.True(t, slices.IsSortedFunc(probes, compareProbes))
+		again, err := store.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		assert.Equal(t, probes, again)
+	})
+
+	t.Run("probes are copied in and out", func(t *testing.T) {
+		(*created[0].Labels)["env"] = "changed"
+		got, err := store.GetProbe(ctx, created[0].Id)
+		require.NoError(t, err)
+		assert.Equal(t, "prod", (*got.Labels)["env"])
+		(*got.Labels)["env"] = "changed"
+		again, err := store.GetProbe(ctx, created[0].Id)
+		require.NoError(t, err)
+		assert.Equal(t, "prod", (*again.Labels)["env"])
+	})
+
+	t.Run("a probe cannot become live while another has its URL", func(t *testing.T) {
+		stopped, err := store.GetProbe(ctx, created[1].Id)
+		require.NoError(t, err)
+		stopped.Status = v1.Failed
+		_, err = store.UpdateProbe(ctx, *stopped)
+		require.NoError(t, err)
+		_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/stage", Status: v1.Pending}, "hash-b")
+		require.NoError(t, err)
+
+		failed, err := store.GetProbe(ctx, created[1].Id)
+		require.NoError(t, err)
+		failed.Status = v1.Pending
+		_, err = store.UpdateProbe(ctx, *failed)
+		assert.True(t, k8serrors.IsAlreadyExists(err), "got %v", err)
+	})
+}
+
+func TestMemoryProbeStore_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "probes.json")
+	store, err := OpenMemoryProbeStore(MemoryConfig{SnapshotFile: file})
+	require.NoError(t, err)
+	_, err = os.Stat(file)
+	require.NoError(t, err, "the snapshot file is written when the store is opened")
+
+	probe, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+	require.NoError(t, err)
+	removed, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/removed", Status: v1.Pending}, "hash-removed")
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteProbe(ctx, removed.Id))
+	require.NoError(t, store.CreateAPIKey(ctx, APIKey{ID: "key", Name: "ci"}))
+	require.NoError(t, store.AddOutboxEvent(ctx, OutboxEvent{ID: "event", Event: []byte(`{}`)}))
+	require.NoError(t, store.SaveSnapshot(ctx))
+
+	restored, err := OpenMemoryProbeStore(MemoryConfig{SnapshotFile: file})
+	require.NoError(t, err)
+	got, err := restored.GetProbe(ctx, probe.Id)
+	require.NoError(t, err)
+	assert.Equal(t, probe, got)
+	_, err = restored.GetTombstone(ctx, removed.Id)
+	assert.NoError(t, err)
+	keys, err := restored.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []APIKey{{ID: "key", Name: "ci"}}, keys)
+	events, err := restored.ListOutboxEvents(ctx)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	unchanged, err := restored.UpdateProbe(ctx, *got)
+	require.NoError(t, err)
+	assert.Equal(t, probe.ResourceVersion, unchanged.ResourceVersion, "writing a probe unchanged keeps its version")
+	got.Status = v1.Active
+	updated, err := restored.UpdateProbe(ctx, *got)
+	require.NoError(t, err)
+	assert.Equal(t, "3", *updated.ResourceVersion, "versions carry on from the snapshot")
+
+	t.Run("unchanged stores are not written", func(t *testing.T) {
+		require.NoError(t, restored.SaveSnapshot(ctx))
+		require.NoError(t, os.Remove(file))
+		require.NoError(t, restored.SaveSnapshot(ctx))
+		_, err := os.Stat(file)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("invalid snapshots are reported", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(file, []byte("{"), 0644))
+		_, err := OpenMemoryProbeStore(MemoryConfig{SnapshotFile: file})
+		assert.ErrorContains(t, err, "failed to restore probe store snapshot")
+	})
+
+	_, err = OpenMemoryProbeStore(MemoryConfig{SnapshotFile: filepath.Join(t.TempDir(), "missing", "probes.json")})
+	assert.ErrorContains(t, err, "failed to write probe store snapshot")
+	_, err = OpenMemoryProbeStore(MemoryConfig{SnapshotInterval: -time.Second})
+	assert.EqualError(t, err, "snapshot interval must not be negative, got -1s")
+}
+
+func TestMemoryProbeStore_RunPersistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "probes.json")
+	store, err := OpenMemoryProbeStore(MemoryConfig{SnapshotFile: file, SnapshotInterval: time.Hour})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		NewTracedProbeStore(NewIndexedProbeStore(store), "memory").RunPersistence(ctx)
+	}()
+	probe, err := store.CreateProbe(context.Background(), v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+	require.NoError(t, err)
+	cancel()
+	<-done
+
+	restored, err := OpenMemoryProbeStore(MemoryConfig{SnapshotFile: file})
+	require.NoError(t, err)
+	_, err = restored.GetProbe(context.Background(), probe.Id)
+	assert.NoError(t, err, "the store is written once more when persistence stops")
+}
@@ -0,0 +1,8 @@
+//go:build nokube
+
+package probestore
+
+import "testing"
+
+// addKubernetesTestStores adds nothing: the Kubernetes stores are not built.
+func addKubernetesTestStores(map[string]func(t *testing.T) ProbeStorage) {}
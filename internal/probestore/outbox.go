@@ -0,0 +1,52 @@
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ErrOutboxUnsupported is returned by wrappers whose store keeps no event
+// outbox.
+var ErrOutboxUnsupported = errors.New("the probe store does not keep an event outbox")
+
+// OutboxEvent is an event waiting in the outbox until it is published.
+type OutboxEvent struct {
+	// ID identifies the event. IDs sort in the order events were added, so
+	// time-ordered UUIDs suit them.
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Event is the serialized event, published as is.
+	Event json.RawMessage `json:"event"`
+}
+
+// OutboxStore is implemented by stores that keep an outbox of events next to
+// the probes, so events recorded by any replica survive restarts and are
+// published at least once.
+type OutboxStore interface {
+	// AddOutboxEvent stores an event to publish.
+	AddOutboxEvent(ctx context.Context, event OutboxEvent) error
+	// ListOutboxEvents returns the events waiting to be published, ordered
+	// by ID.
+	ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error)
+	// DeleteOutboxEvent removes a published event, or returns a NotFound
+	// error.
+	DeleteOutboxEvent(ctx context.Context, id string) error
+}
+
+// outboxEventNotFound is the error DeleteOutboxEvent returns for unknown
+// events.
+func outboxEventNotFound(id string) error {
+	return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "outboxevents"}, id)
+}
+
+// sortOutboxEvents orders events by ID, as ListOutboxEvents returns them.
+func sortOutboxEvents(events []OutboxEvent) {
+	slices.SortFunc(events, func(a, b OutboxEvent) int { return strings.Compare(a.ID, b.ID) })
+}
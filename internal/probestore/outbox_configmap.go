@@ -0,0 +1,96 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+// outboxConfigMapName is the ConfigMap the Kubernetes-backed stores keep the
+// event outbox in, as event ID keys with JSON values. Like the tombstone
+// ConfigMap, it holds no probe-config.json key, so probe listings skip it.
+// ConfigMaps hold at most 1MiB, a few hundred events, so the outbox only
+// absorbs short sink outages.
+const outboxConfigMapName = "probe-event-outbox"
+
+// addConfigMapOutboxEvent adds the event to the outbox ConfigMap. Concurrent
+// writers are retried.
+func addConfigMapOutboxEvent(ctx context.Context, client configMapClient, namespace string, event OutboxEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, outboxConfigMapName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: outboxConfigMapName, Namespace: namespace}}
+			cm.Data = map[string]string{event.ID: string(value)}
+			_, err = client.Create(ctx, cm, metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				// Created concurrently; retry as an update.
+				return k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, outboxConfigMapName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[event.ID] = string(value)
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// listConfigMapOutboxEvents reads the events of the outbox ConfigMap.
+func listConfigMapOutboxEvents(ctx context.Context, client configMapClient) ([]OutboxEvent, error) {
+	cm, err := client.Get(ctx, outboxConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return []OutboxEvent{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox events: %w", err)
+	}
+	events := make([]OutboxEvent, 0, len(cm.Data))
+	for id, value := range cm.Data {
+		var event OutboxEvent
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling outbox event from configmap", "event_id", id, "error", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	sortOutboxEvents(events)
+	return events, nil
+}
+
+// deleteConfigMapOutboxEvent removes an event from the outbox ConfigMap.
+// Concurrent writers are retried.
+func deleteConfigMapOutboxEvent(ctx context.Context, client configMapClient, id string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, outboxConfigMapName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return outboxEventNotFound(id)
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := cm.Data[id]; !ok {
+			return outboxEventNotFound(id)
+		}
+		delete(cm.Data, id)
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
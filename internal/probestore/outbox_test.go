@@ -0,0 +1,64 @@
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestOutbox(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			outbox, ok := store.(OutboxStore)
+			require.True(t, ok, "%T keeps an event outbox", store)
+
+			list, err := outbox.ListOutboxEvents(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, list)
+
+			created := time.Now().UTC().Truncate(time.Second)
+			second := OutboxEvent{ID: "0192f3a0-0000-7000-8000-000000000002", CreatedAt: created, Event: json.RawMessage(`{"id":"2"}`)}
+			first := OutboxEvent{ID: "0192f3a0-0000-7000-8000-000000000001", CreatedAt: created, Event: json.RawMessage(`{"id":"1"}`)}
+			require.NoError(t, outbox.AddOutboxEvent(ctx, second))
+			require.NoError(t, outbox.AddOutboxEvent(ctx, first))
+			_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+			require.NoError(t, err)
+
+			list, err = outbox.ListOutboxEvents(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []OutboxEvent{first, second}, list, "events are listed by ID")
+
+			probes, err := store.ListProbes(ctx, "")
+			require.NoError(t, err)
+			assert.Len(t, probes, 1, "outbox events are not listed as probes")
+
+			require.NoError(t, outbox.DeleteOutboxEvent(ctx, first.ID))
+			assert.True(t, k8serrors.IsNotFound(outbox.DeleteOutboxEvent(ctx, first.ID)), "published events are not found")
+			list, err = outbox.ListOutboxEvents(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []OutboxEvent{second}, list)
+		})
+	}
+}
+
+func TestTracedProbeStore_OutboxUnsupported(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	// Hiding the local store's methods leaves only the ProbeStorage ones.
+	traced := NewTracedProbeStore(struct{ ProbeStorage }{local}, "plain")
+	assert.ErrorIs(t, traced.AddOutboxEvent(ctx, OutboxEvent{ID: "1"}), ErrOutboxUnsupported)
+	_, err = traced.ListOutboxEvents(ctx)
+	assert.ErrorIs(t, err, ErrOutboxUnsupported)
+	assert.ErrorIs(t, traced.DeleteOutboxEvent(ctx, "1"), ErrOutboxUnsupported)
+}
@@ -0,0 +1,797 @@
+package probestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	// postgresMigrationLockID is the key of the advisory lock held while
+	// migrations run, so that replicas starting concurrently don't race.
+	postgresMigrationLockID = 7246_0001
+
+	// pgUniqueViolation is the SQLSTATE code for unique constraint violations.
+	pgUniqueViolation = "23505"
+)
+
+// postgresMigrations are applied in order and recorded in schema_migrations.
+// Never edit or reorder an existing entry; append a new one instead.
+var postgresMigrations = []string{
+	`CREATE TABLE probes (
+		id              UUID PRIMARY KEY,
+		static_url      TEXT NOT NULL,
+		url_hash        TEXT NOT NULL,
+		status          TEXT NOT NULL,
+		labels          JSONB NOT NULL DEFAULT '{}'::jsonb,
+		last_reconciled TEXT,
+		probe           JSONB NOT NULL,
+		created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+		updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`,
+	// Only one probe per URL may be live at a time. Terminating and failed
+	// probes are excluded, matching ProbeWithURLHashExists in the other stores.
+	`CREATE UNIQUE INDEX probes_live_url_hash_idx ON probes (url_hash)
+		WHERE status NOT IN ('terminating', 'failed')`,
+	`CREATE INDEX probes_labels_idx ON probes USING GIN (labels)`,
+	// version is the probe's resource version, bumped by every update.
+	`ALTER TABLE probes ADD COLUMN version BIGINT NOT NULL DEFAULT 1`,
+	// Removed probes leave a tombstone until the tombstone TTL expires.
+	`CREATE TABLE probe_tombstones (
+		id         UUID PRIMARY KEY,
+		deleted_at TIMESTAMPTZ NOT NULL
+	)`,
+	// API keys keep only the hash of their secret.
+	`CREATE TABLE api_keys (
+		id          TEXT PRIMARY KEY,
+		name        TEXT NOT NULL,
+		secret_hash TEXT NOT NULL,
+		rate_limit  DOUBLE PRECISION NOT NULL DEFAULT 0,
+		created_at  TIMESTAMPTZ NOT NULL,
+		expires_at  TIMESTAMPTZ
+	)`,
+	// Events wait in the outbox until they are published.
+	`CREATE TABLE event_outbox (
+		id         TEXT PRIMARY KEY,
+		created_at TIMESTAMPTZ NOT NULL,
+		event      BYTEA NOT NULL
+	)`,
+	// Records of other kinds, such as agent registrations. The data is kept
+	// as written, rather than as JSONB, so it reads back unchanged.
+	`CREATE TABLE records (
+		kind TEXT NOT NULL,
+		id   TEXT NOT NULL,
+		data BYTEA NOT NULL,
+		PRIMARY KEY (kind, id)
+	)`,
+}
+
+// PostgresProbeStore implements the ProbeStorage interface using PostgreSQL.
+// Each probe is stored as a JSONB document, with the URL hash, status and
+// labels kept in dedicated columns so lookups and label selectors can be
+// answered by the database.
+type PostgresProbeStore struct {
+	DB                  *sql.DB
+	StaleProbeTTL       time.Duration
+	NoHeartbeatProbeTTL time.Duration
+	TombstoneTTL        time.Duration
+}
+
+// NewPostgresProbeStore connects to the database identified by dsn and applies
+// any pending schema migrations.
+func NewPostgresProbeStore(ctx context.Context, dsn string) (*PostgresProbeStore, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("postgres DSN cannot be empty")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close() //nolint:errcheck
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if err := migratePostgres(ctx, db); err != nil {
+		db.Close() //nolint:errcheck
+		return nil, fmt.Errorf("failed to migrate postgres schema: %w", err)
+	}
+
+	staleTTL, noHeartbeatTTL := staleProbeTTLsFromEnv()
+	slog.InfoContext(ctx, "Initializing postgres probe store", "stale_ttl", staleTTL, "no_heartbeat_ttl", noHeartbeatTTL)
+	return &PostgresProbeStore{
+		DB:                  db,
+		StaleProbeTTL:       staleTTL,
+		NoHeartbeatProbeTTL: noHeartbeatTTL,
+		TombstoneTTL:        tombstoneTTLFromEnv(),
+	}, nil
+}
+
+// CheckWritable checks that the database accepts writes, which a hot standby
+// or a database set to default_transaction_read_only does not.
+func (p *PostgresProbeStore) CheckWritable(ctx context.Context) error {
+	var readOnly string
+	if err := p.DB.QueryRowContext(ctx, `SHOW transaction_read_only`).Scan(&readOnly); err != nil {
+		return fmt.Errorf("failed to check postgres write access: %w", err)
+	}
+	if readOnly == "on" {
+		return fmt.Errorf("postgres is read-only")
+	}
+	return nil
+}
+
+// CheckHealth checks that the database can be reached.
+func (p *PostgresProbeStore) CheckHealth(ctx context.Context) error {
+	if err := p.DB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to reach postgres: %w", err)
+	}
+	return nil
+}
+
+// migratePostgres applies every migration newer than the recorded schema version.
+func migratePostgres(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, postgresMigrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := current; i < len(postgresMigrations); i++ {
+		version := i + 1
+		if _, err := tx.ExecContext(ctx, postgresMigrations[i]); err != nil {
+			return fmt.Errorf("migration %d failed: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+		slog.InfoContext(ctx, "Applied postgres schema migration", "version", version)
+	}
+
+	return tx.Commit()
+}
+
+// ListProbes lists all probes that match the given label selector.
+func (p *PostgresProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector: %w", err)
+	}
+
+	where, args := labelSelectorToSQL(sel)
+	rows, err := p.DB.QueryContext(ctx, `SELECT probe, labels, version FROM probes WHERE `+where+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query probes: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	probes := []v1.ProbeObject{}
+	for rows.Next() {
+		var probeData, labelData []byte
+		var version int64
+		if err := rows.Scan(&probeData, &labelData, &version); err != nil {
+			return nil, fmt.Errorf("failed to scan probe row: %w", err)
+		}
+
+		// Operators that can't be pushed down (gt, lt) are only applied here,
+		// so every row is re-checked against the full selector.
+		var rowLabels map[string]string
+		if err := json.Unmarshal(labelData, &rowLabels); err != nil {
+			slog.ErrorContext(ctx, "Error unmarshaling labels from probe row", "error", err)
+			continue
+		}
+		if !sel.Matches(labels.Set(rowLabels)) {
+			continue
+		}
+
+		var probe v1.ProbeObject
+		if err := json.Unmarshal(probeData, &probe); err != nil {
+			slog.ErrorContext(ctx, "Error unmarshaling probe from row", "error", err)
+			continue
+		}
+		probes = append(probes, *withResourceVersion(&probe, strconv.FormatInt(version, 10)))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate probe rows: %w", err)
+	}
+
+	return probes, nil
+}
+
+// GetProbe retrieves a single probe by its ID.
+func (p *PostgresProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	var probeData []byte
+	var version int64
+	err := p.DB.QueryRowContext(ctx, `SELECT probe, version FROM probes WHERE id = $1`, probeID).Scan(&probeData, &version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+		}
+		return nil, fmt.Errorf("failed to query probe: %w", err)
+	}
+
+	probe := &v1.ProbeObject{}
+	if err := json.Unmarshal(probeData, probe); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal probe: %w", err)
+	}
+	return withResourceVersion(probe, strconv.FormatInt(version, 10)), nil
+}
+
+// CreateProbe inserts a new probe row. A live probe with the same URL hash is
+// rejected by the unique index and reported as an AlreadyExists error.
+func (p *PostgresProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+	if urlHashString == "" {
+		return nil, fmt.Errorf("URL hash cannot be empty")
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	probe.ResourceVersion = nil
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
+	probeData, err := json.Marshal(probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	rowLabels, lastReconciled := postgresRowLabels(probe, urlHashString)
+	labelData, err := json.Marshal(rowLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+
+	_, err = p.DB.ExecContext(ctx,
+		`INSERT INTO probes (id, static_url, url_hash, status, labels, last_reconciled, probe)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		probe.Id, probe.StaticUrl, urlHashString, string(probe.Status), labelData, lastReconciled, probeData)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String())
+		}
+		return nil, fmt.Errorf("failed to insert probe: %w", err)
+	}
+
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	return withResourceVersion(&probe, "1"), nil
+}
+
+// UpdateProbe replaces the stored document, labels and status of an existing
+// probe and bumps its version. The URL hash is immutable and carried over
+// from the existing row. Making a terminating or failed probe live again while
+// another live probe has its URL returns an AlreadyExists error.
+func (p *PostgresProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+
+	var urlHash string
+	var storedData []byte
+	err := p.DB.QueryRowContext(ctx, `SELECT url_hash, probe FROM probes WHERE id = $1`, probe.Id).Scan(&urlHash, &storedData)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String())
+		}
+		return nil, fmt.Errorf("failed to read existing probe: %w", err)
+	}
+	var stored v1.ProbeObject
+	if err := json.Unmarshal(storedData, &stored); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal existing probe: %w", err)
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+	withNextGeneration(&probe, stored)
+	keepCreationTimestamp(&probe, stored)
+	withUpdateTimestamp(&probe, stored)
+	withDeletionTimestamp(&probe, stored)
+	withStatusHistory(ctx, &probe, stored)
+	withURLHash(&probe, stored)
+
+	probe.ResourceVersion = nil
+	probeData, err := json.Marshal(probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal updated payload: %w", err)
+	}
+	rowLabels, lastReconciled := postgresRowLabels(probe, urlHash)
+	labelData, err := json.Marshal(rowLabels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal labels: %w", err)
+	}
+
+	var version int64
+	err = p.DB.QueryRowContext(ctx,
+		`UPDATE probes
+		 SET static_url = $2, status = $3, labels = $4,
+		     last_reconciled = COALESCE($5, last_reconciled), probe = $6, updated_at = now(),
+		     version = version + 1
+		 WHERE id = $1 AND ($7::BIGINT IS NULL OR version = $7)
+		 RETURNING version`,
+		probe.Id, probe.StaticUrl, string(probe.Status), labelData, lastReconciled, probeData, expectedPostgresVersion(ctx)).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, p.missingRowError(ctx, probe.Id)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.StaticUrl)
+		}
+		return nil, fmt.Errorf("failed to update probe %s: %w", probe.Id, err)
+	}
+
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return withResourceVersion(&probe, strconv.FormatInt(version, 10)), nil
+}
+
+// DeleteProbe handles deletion based on probe status.
+func (p *PostgresProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	existingProbe, err := p.GetProbe(ctx, probeID)
+	if err != nil {
+		return err // Pass the error up, including not found errors
+	}
+
+	switch existingProbe.Status {
+	case v1.Pending:
+		// Probe was never picked up by an agent, delete immediately
+		if err := p.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
+		return nil
+
+	case v1.Active:
+		// Probe is active, set to terminating and wait for agent cleanup
+		existingProbe.Status = v1.Terminating
+		if _, err := p.UpdateProbe(withStatusReason(ctx, deletionReason), *existingProbe); err != nil {
+			return fmt.Errorf("failed to update probe %s to terminating status: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
+		return nil
+
+	case v1.Terminating:
+		// Already terminating, no action needed
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
+		return nil
+
+	case v1.Failed:
+		// Failed probe, delete immediately as agent likely won't process it
+		if err := p.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
+		return nil
+
+	default:
+		// Unknown status, treat as pending and delete immediately
+		if err := p.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), existingProbe.Status, err)
+		}
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", existingProbe.Status)
+		return nil
+	}
+}
+
+// DeleteProbeStorage removes a probe row, leaving a tombstone in the same
+// statement.
+func (p *PostgresProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	result, err := p.DB.ExecContext(ctx, `WITH deleted AS (
+			DELETE FROM probes WHERE id = $1 AND ($2::BIGINT IS NULL OR version = $2) RETURNING id
+		)
+		INSERT INTO probe_tombstones (id, deleted_at) SELECT id, $3 FROM deleted
+		ON CONFLICT (id) DO UPDATE SET deleted_at = EXCLUDED.deleted_at`,
+		probeID, expectedPostgresVersion(ctx), newTombstone(probeID).DeletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to delete probe: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return p.missingRowError(ctx, probeID)
+	}
+
+	slog.DebugContext(ctx, "Deleted probe row", "probe_id", probeID)
+	return nil
+}
+
+// expectedPostgresVersion returns the version expected by ctx as a query
+// argument: nil when no version is expected, so any row matches, and -1 when
+// the expected version is not one this store issued, so no row matches.
+func expectedPostgresVersion(ctx context.Context) any {
+	want, ok := expectedResourceVersion(ctx)
+	if !ok {
+		return nil
+	}
+	version, err := strconv.ParseInt(want, 10, 64)
+	if err != nil {
+		return int64(-1)
+	}
+	return version
+}
+
+// missingRowError explains why an update or delete of the probe matched no
+// row: the probe is gone, or it is not at the version ctx expects.
+func (p *PostgresProbeStore) missingRowError(ctx context.Context, probeID uuid.UUID) error {
+	var version int64
+	err := p.DB.QueryRowContext(ctx, `SELECT version FROM probes WHERE id = $1`, probeID).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read probe %s: %w", probeID, err)
+	}
+	if err := checkResourceVersion(ctx, probeID, strconv.FormatInt(version, 10)); err != nil {
+		return err
+	}
+	// The row was replaced between the write and this read.
+	return k8serrors.NewConflict(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String(),
+		fmt.Errorf("the probe changed concurrently"))
+}
+
+// GetTombstone returns the tombstone of a recently removed probe.
+func (p *PostgresProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	tombstone := Tombstone{ProbeID: probeID}
+	err := p.DB.QueryRowContext(ctx, `SELECT deleted_at FROM probe_tombstones WHERE id = $1`, probeID).Scan(&tombstone.DeletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tombstone: %w", err)
+	}
+	tombstone.DeletedAt = tombstone.DeletedAt.UTC()
+	if tombstone.expired(p.TombstoneTTL) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	return &tombstone, nil
+}
+
+// CreateAPIKey stores a new API key.
+func (p *PostgresProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	var expiresAt sql.NullTime
+	if !key.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: key.ExpiresAt, Valid: true}
+	}
+	_, err := p.DB.ExecContext(ctx, `
+		INSERT INTO api_keys (id, name, secret_hash, rate_limit, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		key.ID, key.Name, key.SecretHash, key.RateLimit, key.CreatedAt, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert API key: %w", err)
+	}
+	return nil
+}
+
+// ListAPIKeys returns the stored API keys.
+func (p *PostgresProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	rows, err := p.DB.QueryContext(ctx, `
+		SELECT id, name, secret_hash, rate_limit, created_at, expires_at FROM api_keys ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	keys := []APIKey{}
+	for rows.Next() {
+		var key APIKey
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&key.ID, &key.Name, &key.SecretHash, &key.RateLimit, &key.CreatedAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan API key row: %w", err)
+		}
+		key.CreatedAt = key.CreatedAt.UTC()
+		if expiresAt.Valid {
+			key.ExpiresAt = expiresAt.Time.UTC()
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// DeleteAPIKey removes a stored API key.
+func (p *PostgresProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	result, err := p.DB.ExecContext(ctx, `DELETE FROM api_keys WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return apiKeyNotFound(id)
+	}
+	return nil
+}
+
+// AddOutboxEvent stores an event to publish.
+func (p *PostgresProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	_, err := p.DB.ExecContext(ctx, `INSERT INTO event_outbox (id, created_at, event) VALUES ($1, $2, $3)`,
+		event.ID, event.CreatedAt, []byte(event.Event))
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox event: %w", err)
+	}
+	return nil
+}
+
+// ListOutboxEvents returns the events waiting in the outbox.
+func (p *PostgresProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT id, created_at, event FROM event_outbox ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query outbox events: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	events := []OutboxEvent{}
+	for rows.Next() {
+		var event OutboxEvent
+		var data []byte
+		if err := rows.Scan(&event.ID, &event.CreatedAt, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event row: %w", err)
+		}
+		event.CreatedAt = event.CreatedAt.UTC()
+		event.Event = data
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// DeleteOutboxEvent removes a published event.
+func (p *PostgresProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	result, err := p.DB.ExecContext(ctx, `DELETE FROM event_outbox WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return outboxEventNotFound(id)
+	}
+	return nil
+}
+
+// PutRecord stores a record.
+func (p *PostgresProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	_, err := p.DB.ExecContext(ctx, `
+		INSERT INTO records (kind, id, data) VALUES ($1, $2, $3)
+		ON CONFLICT (kind, id) DO UPDATE SET data = EXCLUDED.data`,
+		kind, record.ID, []byte(record.Data))
+	if err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+	return nil
+}
+
+// CreateRecord stores a record unless one with the same ID is stored.
+func (p *PostgresProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	_, err := p.DB.ExecContext(ctx, `INSERT INTO records (kind, id, data) VALUES ($1, $2, $3)`,
+		kind, record.ID, []byte(record.Data))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return recordExists(kind, record.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+	return nil
+}
+
+// GetRecord returns a stored record.
+func (p *PostgresProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	var data []byte
+	err := p.DB.QueryRowContext(ctx, `SELECT data FROM records WHERE kind = $1 AND id = $2`, kind, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, recordNotFound(kind, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query record: %w", err)
+	}
+	return &Record{ID: id, Data: data}, nil
+}
+
+// ListRecords returns the stored records of the kind.
+func (p *PostgresProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	rows, err := p.DB.QueryContext(ctx, `SELECT id, data FROM records WHERE kind = $1 ORDER BY id`, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query records: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	records := []Record{}
+	for rows.Next() {
+		var record Record
+		var data []byte
+		if err := rows.Scan(&record.ID, &data); err != nil {
+			return nil, fmt.Errorf("failed to scan record row: %w", err)
+		}
+		record.Data = data
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// DeleteRecord removes a stored record.
+func (p *PostgresProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	result, err := p.DB.ExecContext(ctx, `DELETE FROM records WHERE kind = $1 AND id = $2`, kind, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return recordNotFound(kind, id)
+	}
+	return nil
+}
+
+// ProbeWithURLHashExists checks if a live probe with the given URL hash exists.
+func (p *PostgresProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	var exists bool
+	err := p.DB.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM probes WHERE url_hash = $1 AND status NOT IN ('terminating', 'failed'))`,
+		urlHashString).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing probes: %w", err)
+	}
+	return exists, nil
+}
+
+// GarbageCollectStaleProbes applies the same heartbeat rules as the Kubernetes
+// store: stale or never-reconciled probes are moved to terminating, and probes
+// that are already terminating are deleted. Expired tombstones are removed.
+func (p *PostgresProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	if _, err := p.DB.ExecContext(ctx, `DELETE FROM probe_tombstones WHERE deleted_at < $1`, tombstoneCutoff(p.TombstoneTTL)); err != nil {
+		slog.ErrorContext(ctx, "GC: failed to remove expired tombstones", "error", err)
+	}
+
+	rows, err := p.DB.QueryContext(ctx, `SELECT id, status, last_reconciled, created_at FROM probes`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list probes for GC: %w", err)
+	}
+
+	type gcCandidate struct {
+		id     uuid.UUID
+		status string
+		reason string
+	}
+	var candidates []gcCandidate
+	now := clock.Now()
+	for rows.Next() {
+		var (
+			id             uuid.UUID
+			status         string
+			lastReconciled sql.NullString
+			createdAt      time.Time
+		)
+		if err := rows.Scan(&id, &status, &lastReconciled, &createdAt); err != nil {
+			rows.Close() //nolint:errcheck
+			return 0, fmt.Errorf("failed to scan probe row for GC: %w", err)
+		}
+
+		if !lastReconciled.Valid {
+			if now.Sub(createdAt) > p.NoHeartbeatProbeTTL {
+				candidates = append(candidates, gcCandidate{id, status, "no heartbeat ever received"})
+			}
+			continue
+		}
+
+		ts, err := time.Parse(lastReconciledLayout, lastReconciled.String)
+		if err != nil {
+			slog.WarnContext(ctx, "GC: could not parse last-reconciled, skipping", "probe_id", id, "last_reconciled", lastReconciled.String, "error", err)
+			continue
+		}
+		if now.Sub(ts) > p.StaleProbeTTL {
+			candidates = append(candidates, gcCandidate{id, status, fmt.Sprintf("stale heartbeat %s", lastReconciled.String)})
+		}
+	}
+	rows.Close() //nolint:errcheck
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate probe rows for GC: %w", err)
+	}
+
+	deleted := 0
+	for _, c := range candidates {
+		if c.status == string(v1.Terminating) {
+			// Already terminating -- delete it (agent had its chance)
+			if err := p.DeleteProbeStorage(ctx, c.id); err != nil {
+				slog.ErrorContext(ctx, "GC: failed to delete terminating probe", "probe_id", c.id, "error", err)
+				continue
+			}
+			slog.InfoContext(ctx, "GC: deleted already-terminating probe", "probe_id", c.id, "reason", c.reason)
+			deleted++
+			continue
+		}
+
+		probe, err := p.GetProbe(ctx, c.id)
+		if err != nil {
+			slog.ErrorContext(ctx, "GC: failed to read probe", "probe_id", c.id, "error", err)
+			continue
+		}
+		probe.Status = v1.Terminating
+		if _, err := p.UpdateProbe(withStatusReason(ctx, c.reason), *probe); err != nil {
+			slog.ErrorContext(ctx, "GC: failed to transition probe to terminating", "probe_id", c.id, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "GC: transitioned probe to terminating", "probe_id", c.id, "reason", c.reason)
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// postgresRowLabels builds the label set used for selector matching, adding
+// the system labels the API relies on. The last-reconciled heartbeat is kept
+// in its own column, mirroring the annotation used by the Kubernetes store.
+func postgresRowLabels(probe v1.ProbeObject, urlHashString string) (map[string]string, *string) {
+	rowLabels := make(map[string]string)
+	var lastReconciled *string
+	if probe.Labels != nil {
+		for key, val := range *probe.Labels {
+			if key == lastReconciledKey {
+				v := val
+				lastReconciled = &v
+				continue
+			}
+			rowLabels[key] = val
+		}
+	}
+	rowLabels[baseAppLabelKey] = baseAppLabelValue
+	rowLabels[probeURLHashLabelKey] = urlHashString
+	rowLabels[probeStatusLabelKey] = string(probe.Status)
+	return rowLabels, lastReconciled
+}
+
+// labelSelectorToSQL translates the requirements of a parsed label selector
+// into a WHERE clause over the labels JSONB column. Requirements that have no
+// direct SQL equivalent are left out and must be applied by the caller.
+func labelSelectorToSQL(sel labels.Selector) (string, []any) {
+	reqs, _ := sel.Requirements()
+	clause := "TRUE"
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	for _, req := range reqs {
+		key := arg(req.Key()) + "::text"
+		values := req.Values().List()
+		switch req.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			clause += fmt.Sprintf(" AND labels->>%s = %s", key, arg(values[0]))
+		case selection.NotEquals:
+			clause += fmt.Sprintf(" AND (labels->>%s IS NULL OR labels->>%s <> %s)", key, key, arg(values[0]))
+		case selection.In:
+			clause += fmt.Sprintf(" AND labels->>%s = ANY(%s)", key, arg(values))
+		case selection.NotIn:
+			clause += fmt.Sprintf(" AND (labels->>%s IS NULL OR NOT (labels->>%s = ANY(%s)))", key, key, arg(values))
+		case selection.Exists:
+			clause += fmt.Sprintf(" AND labels->>%s IS NOT NULL", key)
+		case selection.DoesNotExist:
+			clause += fmt.Sprintf(" AND labels->>%s IS NULL", key)
+		default:
+			// gt/lt: drop the unused key argument and filter in Go.
+			args = args[:len(args)-1]
+		}
+	}
+	return clause, args
+}
@@ -18,3 +18,17 @@ type ProbeStorage interface {
 	ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error)
 	GarbageCollectStaleProbes(ctx context.Context) (int, error)
 }
+
+// WriteChecker is implemented by stores that can tell whether they currently
+// accept writes, for example a database that failed over to a read-only
+// replica. It backs the write readiness check.
+type WriteChecker interface {
+	CheckWritable(ctx context.Context) error
+}
+
+// HealthChecker is implemented by stores that can cheaply tell whether their
+// backend is reachable, without reading every probe. It backs the read
+// readiness check.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
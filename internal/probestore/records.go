@@ -0,0 +1,66 @@
+package probestore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ErrRecordsUnsupported is returned by wrappers whose store keeps no
+// records.
+var ErrRecordsUnsupported = errors.New("the probe store does not keep records")
+
+// Record is a JSON document of another kind than probes, such as the
+// registration of an agent, kept next to the probes.
+type Record struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// RecordStore is implemented by stores that keep records of other kinds next
+// to the probes, so state such as agent registrations is shared by every
+// replica using the store and survives restarts. Kinds are short lowercase
+// names chosen by the callers; IDs may be any non-empty string.
+type RecordStore interface {
+	// PutRecord creates the record, or replaces the one of the kind with
+	// the same ID.
+	PutRecord(ctx context.Context, kind string, record Record) error
+	// CreateRecord creates the record, or returns an AlreadyExists error
+	// if the kind has one with the same ID, also when another replica
+	// creates it at the same time.
+	CreateRecord(ctx context.Context, kind string, record Record) error
+	// GetRecord returns a record, or a NotFound error.
+	GetRecord(ctx context.Context, kind, id string) (*Record, error)
+	// ListRecords returns every record of the kind, ordered by ID.
+	ListRecords(ctx context.Context, kind string) ([]Record, error)
+	// DeleteRecord removes a record, or returns a NotFound error.
+	DeleteRecord(ctx context.Context, kind, id string) error
+}
+
+// recordNotFound is the error GetRecord and DeleteRecord return for unknown
+// records.
+func recordNotFound(kind, id string) error {
+	return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: kind}, id)
+}
+
+// recordExists is the error CreateRecord returns for records that exist.
+func recordExists(kind, id string) error {
+	return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: kind}, id)
+}
+
+// recordName encodes a record ID for use in file names, object keys and
+// ConfigMap keys, which only allow some characters.
+func recordName(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+// sortRecords orders records by ID, as ListRecords returns them.
+func sortRecords(records []Record) {
+	slices.SortFunc(records, func(a, b Record) int { return strings.Compare(a.ID, b.ID) })
+}
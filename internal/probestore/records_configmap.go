@@ -0,0 +1,131 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+// recordConfigMapPrefix is prepended to the kind to name the ConfigMap the
+// Kubernetes-backed stores keep the records of the kind in, as encoded ID keys
+// with JSON values. Like the tombstone ConfigMap, it holds no
+// probe-config.json key, so probe listings skip it.
+const recordConfigMapPrefix = "probe-records-"
+
+// putConfigMapRecord adds or replaces the record in the ConfigMap of its
+// kind. Concurrent writers are retried.
+func putConfigMapRecord(ctx context.Context, client configMapClient, namespace, kind string, record Record) error {
+	return writeConfigMapRecord(ctx, client, namespace, kind, record, true)
+}
+
+// createConfigMapRecord adds the record to the ConfigMap of its kind, unless
+// it holds one with the same ID. Concurrent writers are retried, so one
+// of two writers creating the same record at once gets AlreadyExists.
+func createConfigMapRecord(ctx context.Context, client configMapClient, namespace, kind string, record Record) error {
+	return writeConfigMapRecord(ctx, client, namespace, kind, record, false)
+}
+
+func writeConfigMapRecord(ctx context.Context, client configMapClient, namespace, kind string, record Record, replace bool) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	name := recordConfigMapPrefix + kind
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+			cm.Data = map[string]string{recordName(record.ID): string(value)}
+			_, err = client.Create(ctx, cm, metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				// Created concurrently; retry as an update.
+				return k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, name, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := cm.Data[recordName(record.ID)]; ok && !replace {
+			return recordExists(kind, record.ID)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[recordName(record.ID)] = string(value)
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// getConfigMapRecord reads a record from the ConfigMap of its kind.
+func getConfigMapRecord(ctx context.Context, client configMapClient, kind, id string) (*Record, error) {
+	cm, err := client.Get(ctx, recordConfigMapPrefix+kind, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, recordNotFound(kind, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	value, ok := cm.Data[recordName(id)]
+	if !ok {
+		return nil, recordNotFound(kind, id)
+	}
+	var record Record
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return &record, nil
+}
+
+// listConfigMapRecords reads the records of the ConfigMap of the kind.
+func listConfigMapRecords(ctx context.Context, client configMapClient, kind string) ([]Record, error) {
+	cm, err := client.Get(ctx, recordConfigMapPrefix+kind, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return []Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	records := make([]Record, 0, len(cm.Data))
+	for key, value := range cm.Data {
+		var record Record
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling record from configmap", "kind", kind, "key", key, "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// deleteConfigMapRecord removes a record from the ConfigMap of its kind.
+// Concurrent writers are retried.
+func deleteConfigMapRecord(ctx context.Context, client configMapClient, kind, id string) error {
+	name := recordConfigMapPrefix + kind
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return recordNotFound(kind, id)
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := cm.Data[recordName(id)]; !ok {
+			return recordNotFound(kind, id)
+		}
+		delete(cm.Data, recordName(id))
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
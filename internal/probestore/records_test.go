@@ -0,0 +1,80 @@
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestRecords(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			records, ok := store.(RecordStore)
+			require.True(t, ok, "%T keeps records", store)
+
+			list, err := records.ListRecords(ctx, "agents")
+			require.NoError(t, err)
+			assert.Empty(t, list)
+
+			second := Record{ID: "agent/2", Data: json.RawMessage(`{"id":"agent/2"}`)}
+			first := Record{ID: "agent-1", Data: json.RawMessage(`{"id":"agent-1"}`)}
+			require.NoError(t, records.PutRecord(ctx, "agents", second))
+			require.NoError(t, records.PutRecord(ctx, "agents", first))
+			first.Data = json.RawMessage(`{"id":"agent-1","max_probes":3}`)
+			require.NoError(t, records.PutRecord(ctx, "agents", first))
+			require.NoError(t, records.PutRecord(ctx, "other", Record{ID: "agent-1", Data: json.RawMessage(`{}`)}))
+			_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+			require.NoError(t, err)
+
+			list, err = records.ListRecords(ctx, "agents")
+			require.NoError(t, err)
+			assert.Equal(t, []Record{first, second}, list, "records are listed by ID, and replaced by ID")
+			got, err := records.GetRecord(ctx, "agents", second.ID)
+			require.NoError(t, err)
+			assert.Equal(t, second, *got)
+
+			probes, err := store.ListProbes(ctx, "")
+			require.NoError(t, err)
+			assert.Len(t, probes, 1, "records are not listed as probes")
+
+			err = records.CreateRecord(ctx, "agents", Record{ID: second.ID, Data: json.RawMessage(`{}`)})
+			assert.True(t, k8serrors.IsAlreadyExists(err), "records are created only once, got %v", err)
+			created := Record{ID: "agent-3", Data: json.RawMessage(`{"id":"agent-3"}`)}
+			require.NoError(t, records.CreateRecord(ctx, "agents", created))
+			got, err = records.GetRecord(ctx, "agents", created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, created, *got)
+
+			require.NoError(t, records.DeleteRecord(ctx, "agents", first.ID))
+			assert.True(t, k8serrors.IsNotFound(records.DeleteRecord(ctx, "agents", first.ID)), "deleted records are not found")
+			_, err = records.GetRecord(ctx, "agents", first.ID)
+			assert.True(t, k8serrors.IsNotFound(err))
+			_, err = records.GetRecord(ctx, "other", first.ID)
+			assert.NoError(t, err, "kinds are kept apart")
+		})
+	}
+}
+
+func TestTracedProbeStore_RecordsUnsupported(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	// Hiding the local store's methods leaves only the ProbeStorage ones.
+	traced := NewTracedProbeStore(struct{ ProbeStorage }{local}, "plain")
+	assert.ErrorIs(t, traced.PutRecord(ctx, "agents", Record{ID: "1"}), ErrRecordsUnsupported)
+	assert.ErrorIs(t, traced.CreateRecord(ctx, "agents", Record{ID: "1"}), ErrRecordsUnsupported)
+	_, err = traced.GetRecord(ctx, "agents", "1")
+	assert.ErrorIs(t, err, ErrRecordsUnsupported)
+	_, err = traced.ListRecords(ctx, "agents")
+	assert.ErrorIs(t, err, ErrRecordsUnsupported)
+	assert.ErrorIs(t, traced.DeleteRecord(ctx, "agents", "1"), ErrRecordsUnsupported)
+}
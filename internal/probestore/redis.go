@@ -0,0 +1,868 @@
+package probestore
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+const (
+	// defaultRedisPrefix is prepended to every key when no prefix is set.
+	defaultRedisPrefix = "rhobs-synthetics:"
+
+	// redisProbesKey is the set of every probe ID.
+	redisProbesKey = "probes"
+	// redisAPIKeysKey is the hash of the API keys, by ID.
+	redisAPIKeysKey = "apikeys"
+	// redisOutboxKey is the hash of the events waiting in the outbox, by ID.
+	redisOutboxKey = "outbox"
+	// redisRecordsKey is prepended to the kind to name the hash of the
+	// records of the kind, by ID.
+	redisRecordsKey = "records:"
+
+	// redisTxAttempts bounds the retries of a write that keeps losing against
+	// concurrent writers of the same keys.
+	redisTxAttempts = 5
+)
+
+// RedisProbeStore implements the ProbeStorage interface on Redis, for
+// deployments whose agents list probes far more often than ConfigMap LISTs
+// allow. Each probe is a hash holding its JSON document and its version, and
+// sets index the probe IDs by status and by label, so label selectors only
+// read the probes that can match. A key per URL hash names the live probe
+// that has it. Writes are transactions watching the keys they read, so
+// concurrent replicas neither overwrite each other nor create two live
+// probes for the same URL.
+type RedisProbeStore struct {
+	client              *redis.Client
+	prefix              string
+	StaleProbeTTL       time.Duration
+	NoHeartbeatProbeTTL time.Duration
+	TombstoneTTL        time.Duration
+}
+
+// NewRedisProbeStore connects to the Redis server at cfg.URL and checks that
+// it accepts writes.
+func NewRedisProbeStore(ctx context.Context, cfg RedisConfig) (*RedisProbeStore, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("redis URL cannot be empty")
+	}
+	opts, err := redis.ParseURL(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+
+	staleTTL, noHeartbeatTTL := staleProbeTTLsFromEnv()
+	store := &RedisProbeStore{
+		client:              redis.NewClient(opts),
+		prefix:              prefix,
+		StaleProbeTTL:       staleTTL,
+		NoHeartbeatProbeTTL: noHeartbeatTTL,
+		TombstoneTTL:        tombstoneTTLFromEnv(),
+	}
+	if err := store.CheckWritable(ctx); err != nil {
+		store.client.Close() //nolint:errcheck
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Initializing redis probe store", "addr", opts.Addr, "db", opts.DB, "prefix", prefix,
+		"stale_ttl", staleTTL, "no_heartbeat_ttl", noHeartbeatTTL)
+	return store, nil
+}
+
+// CheckWritable checks that Redis accepts writes, which a replica does not.
+func (r *RedisProbeStore) CheckWritable(ctx context.Context) error {
+	if err := r.client.Set(ctx, r.prefix+"write_test", "test", time.Minute).Err(); err != nil {
+		return fmt.Errorf("redis is not writable: %w", err)
+	}
+	return nil
+}
+
+// CheckHealth checks that Redis can be reached.
+func (r *RedisProbeStore) CheckHealth(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to reach redis: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisProbeStore) probeKey(probeID uuid.UUID) string {
+	return r.probeIDKey(probeID.String())
+}
+
+func (r *RedisProbeStore) probeIDKey(id string) string {
+	return r.prefix + "probe:" + id
+}
+
+func (r *RedisProbeStore) statusKey(status string) string {
+	return r.prefix + "status:" + status
+}
+
+func (r *RedisProbeStore) labelKey(key, value string) string {
+	return r.prefix + "label:" + key + "=" + value
+}
+
+func (r *RedisProbeStore) urlHashKey(urlHash string) string {
+	return r.prefix + "url-hash:" + urlHash
+}
+
+func (r *RedisProbeStore) tombstoneKey(probeID uuid.UUID) string {
+	return r.prefix + "tombstone:" + probeID.String()
+}
+
+// indexKeys returns the keys of the index sets a probe with the given labels
+// belongs to. The app label is on every probe and the last-reconciled
+// heartbeat changes at every reconcile, so neither is indexed.
+func (r *RedisProbeStore) indexKeys(probeLabels map[string]string) []string {
+	keys := []string{r.prefix + redisProbesKey}
+	for key, value := range probeLabels {
+		switch key {
+		case baseAppLabelKey, lastReconciledKey:
+		case probeStatusLabelKey:
+			keys = append(keys, r.statusKey(value))
+		default:
+			keys = append(keys, r.labelKey(key, value))
+		}
+	}
+	return keys
+}
+
+// requirementKeys returns the keys of the index sets holding every probe
+// that can match req, and false when req can't be answered from the index.
+func (r *RedisProbeStore) requirementKeys(req labels.Requirement) ([]string, bool) {
+	switch req.Operator() {
+	case selection.Equals, selection.DoubleEquals, selection.In:
+	default:
+		return nil, false
+	}
+	var keys []string
+	for _, value := range req.Values().List() {
+		switch req.Key() {
+		case baseAppLabelKey:
+			if value != baseAppLabelValue {
+				continue
+			}
+			keys = append(keys, r.prefix+redisProbesKey)
+		case lastReconciledKey:
+			return nil, false
+		case probeStatusLabelKey:
+			keys = append(keys, r.statusKey(value))
+		default:
+			keys = append(keys, r.labelKey(req.Key(), value))
+		}
+	}
+	return keys, true
+}
+
+// ListProbes lists all probes that match the given label selector. Only the
+// probes in the index sets of its equality and set-based requirements are
+// read, and each is checked against the full selector.
+func (r *RedisProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector: %w", err)
+	}
+
+	ids, err := r.candidateIDs(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []v1.ProbeObject{}, nil
+	}
+
+	cmds, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.HMGet(ctx, r.probeIDKey(id), "probe", "version")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read probes: %w", err)
+	}
+
+	probes := []v1.ProbeObject{}
+	for i, cmd := range cmds {
+		probe, err := redisProbe(cmd.(*redis.SliceCmd).Val())
+		if errors.Is(err, redis.Nil) {
+			continue // Deleted since the index was read
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "Error reading probe from redis", "probe_id", ids[i], "error", err)
+			continue
+		}
+		probeLabels := labels.Set{}
+		if probe.Labels != nil {
+			probeLabels = labels.Set(*probe.Labels)
+		}
+		if sel.Matches(probeLabels) {
+			probes = append(probes, *probe)
+		}
+	}
+	slices.SortFunc(probes, compareProbes)
+	return probes, nil
+}
+
+// candidateIDs returns the IDs of the probes in every index set sel requires:
+// the intersection, over its requirements that the index answers, of the
+// union of the sets of their values. Without such requirements, every probe
+// is a candidate.
+func (r *RedisProbeStore) candidateIDs(ctx context.Context, sel labels.Selector) ([]string, error) {
+	reqs, _ := sel.Requirements()
+	var candidates map[string]bool
+	for _, req := range reqs {
+		keys, ok := r.requirementKeys(req)
+		if !ok {
+			continue
+		}
+		var ids []string
+		if len(keys) > 0 {
+			var err error
+			if ids, err = r.client.SUnion(ctx, keys...).Result(); err != nil {
+				return nil, fmt.Errorf("failed to read the label index: %w", err)
+			}
+		}
+		matching := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			if candidates == nil || candidates[id] {
+				matching[id] = true
+			}
+		}
+		candidates = matching
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+	}
+	if candidates == nil {
+		ids, err := r.client.SMembers(ctx, r.prefix+redisProbesKey).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list probe IDs: %w", err)
+		}
+		return ids, nil
+	}
+	return slices.Collect(maps.Keys(candidates)), nil
+}
+
+// compareProbes orders probes by creation, then ID, as the postgres store
+// lists them.
+func compareProbes(a, b v1.ProbeObject) int {
+	var createdA, createdB time.Time
+	if a.CreationTimestamp != nil {
+		createdA = *a.CreationTimestamp
+	}
+	if b.CreationTimestamp != nil {
+		createdB = *b.CreationTimestamp
+	}
+	return cmp.Or(createdA.Compare(createdB), strings.Compare(a.Id.String(), b.Id.String()))
+}
+
+// redisProbe decodes the probe and version fields of a probe hash, or returns
+// redis.Nil if the probe does not exist.
+func redisProbe(fields []any) (*v1.ProbeObject, error) {
+	data, ok := fields[0].(string)
+	if !ok {
+		return nil, redis.Nil
+	}
+	version, _ := fields[1].(string)
+	var probe v1.ProbeObject
+	if err := json.Unmarshal([]byte(data), &probe); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal probe: %w", err)
+	}
+	return withResourceVersion(&probe, version), nil
+}
+
+// GetProbe retrieves a single probe by its ID.
+func (r *RedisProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	return r.readProbe(ctx, r.client, probeID)
+}
+
+// readProbe reads a probe with c, which is the client or a transaction.
+func (r *RedisProbeStore) readProbe(ctx context.Context, c redis.Cmdable, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	fields, err := c.HMGet(ctx, r.probeKey(probeID), "probe", "version").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read probe: %w", err)
+	}
+	probe, err := redisProbe(fields)
+	if errors.Is(err, redis.Nil) {
+		return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+	}
+	return probe, err
+}
+
+// CreateProbe stores a new probe and indexes it. A live probe with the same
+// URL hash, including one created concurrently by another replica, is
+// reported as an AlreadyExists error.
+func (r *RedisProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+	if urlHashString == "" {
+		return nil, fmt.Errorf("URL hash cannot be empty")
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	if probe.Labels == nil {
+		probe.Labels = &v1.LabelsSchema{}
+	}
+	(*probe.Labels)[probeURLHashLabelKey] = urlHashString
+	(*probe.Labels)[baseAppLabelKey] = baseAppLabelValue
+	(*probe.Labels)[probeStatusLabelKey] = string(probe.Status)
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
+
+	probe.ResourceVersion = nil
+	data, err := json.Marshal(probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal probe: %w", err)
+	}
+
+	probeKey, urlHashKey := r.probeKey(probe.Id), r.urlHashKey(urlHashString)
+	err = r.transaction(ctx, probe.Id, func(tx *redis.Tx) error {
+		if n, err := tx.Exists(ctx, probeKey).Result(); err != nil {
+			return fmt.Errorf("failed to check for an existing probe: %w", err)
+		} else if n > 0 {
+			return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String())
+		}
+		if n, err := tx.Exists(ctx, urlHashKey).Result(); err != nil {
+			return fmt.Errorf("failed to check for existing probe with URL hash: %w", err)
+		} else if n > 0 {
+			return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+		}
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, probeKey, "probe", data, "version", 1)
+			for _, key := range r.indexKeys(*probe.Labels) {
+				pipe.SAdd(ctx, key, probe.Id.String())
+			}
+			if isLiveStatus(probe.Status) {
+				pipe.Set(ctx, urlHashKey, probe.Id.String(), 0)
+			}
+			return nil
+		})
+		return err
+	}, probeKey, urlHashKey)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	return withResourceVersion(&probe, "1"), nil
+}
+
+// UpdateProbe replaces an existing probe and moves it between index sets,
+// provided it is at the version ctx expects, if any. The URL hash label is
+// immutable; the probe gives up its URL when it stops being live and claims
+// it again, if no other live probe has it, when it becomes live again.
+func (r *RedisProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	var updated *v1.ProbeObject
+	probeKey := r.probeKey(probe.Id)
+	err := r.transaction(ctx, probe.Id, func(tx *redis.Tx) error {
+		existingProbe, err := r.readProbe(ctx, tx, probe.Id)
+		if err != nil {
+			return err
+		}
+		version := resourceVersionOf(existingProbe)
+		if err := checkResourceVersion(ctx, probe.Id, version); err != nil {
+			return err
+		}
+
+		probe := probe
+		withNextGeneration(&probe, *existingProbe)
+		keepCreationTimestamp(&probe, *existingProbe)
+		withUpdateTimestamp(&probe, *existingProbe)
+		withDeletionTimestamp(&probe, *existingProbe)
+		withStatusHistory(ctx, &probe, *existingProbe)
+		withURLHash(&probe, *existingProbe)
+
+		newLabels := v1.LabelsSchema{}
+		if probe.Labels != nil {
+			maps.Copy(newLabels, *probe.Labels)
+		}
+		newLabels[baseAppLabelKey] = baseAppLabelValue
+		newLabels[probeStatusLabelKey] = string(probe.Status)
+		oldLabels := v1.LabelsSchema{}
+		if existingProbe.Labels != nil {
+			oldLabels = *existingProbe.Labels
+		}
+		urlHash, hasURLHash := oldLabels[probeURLHashLabelKey]
+		if hasURLHash {
+			newLabels[probeURLHashLabelKey] = urlHash
+		} else {
+			delete(newLabels, probeURLHashLabelKey)
+		}
+		probe.Labels = &newLabels
+
+		// The URL hash key is read after the probe, so watching it here
+		// still aborts the transaction if it changes before the write.
+		var claimURL, releaseURL bool
+		if hasURLHash && isLiveStatus(probe.Status) != isLiveStatus(existingProbe.Status) {
+			if err := tx.Watch(ctx, r.urlHashKey(urlHash)).Err(); err != nil {
+				return fmt.Errorf("failed to watch the URL hash: %w", err)
+			}
+			holder, err := tx.Get(ctx, r.urlHashKey(urlHash)).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return fmt.Errorf("failed to read the URL hash: %w", err)
+			}
+			switch {
+			case isLiveStatus(probe.Status) && holder != "" && holder != probe.Id.String():
+				return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+			case isLiveStatus(probe.Status):
+				claimURL = true
+			case holder == probe.Id.String():
+				releaseURL = true
+			}
+		}
+
+		probe.ResourceVersion = nil
+		data, err := json.Marshal(probe)
+		if err != nil {
+			return fmt.Errorf("failed to marshal updated probe: %w", err)
+		}
+		next, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q of probe %s: %w", version, probe.Id, err)
+		}
+		next++
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, probeKey, "probe", data, "version", next)
+			for _, key := range r.indexKeys(oldLabels) {
+				pipe.SRem(ctx, key, probe.Id.String())
+			}
+			for _, key := range r.indexKeys(newLabels) {
+				pipe.SAdd(ctx, key, probe.Id.String())
+			}
+			switch {
+			case claimURL:
+				pipe.Set(ctx, r.urlHashKey(urlHash), probe.Id.String(), 0)
+			case releaseURL:
+				pipe.Del(ctx, r.urlHashKey(urlHash))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		updated = withResourceVersion(&probe, strconv.FormatInt(next, 10))
+		return nil
+	}, probeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return updated, nil
+}
+
+// DeleteProbe handles deletion based on probe status.
+func (r *RedisProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	if probeID == (uuid.UUID{}) {
+		return fmt.Errorf("probe ID cannot be empty")
+	}
+
+	existingProbe, err := r.GetProbe(ctx, probeID)
+	if err != nil {
+		return err // Pass the error up, including not found errors
+	}
+	if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+		return err
+	}
+
+	switch existingProbe.Status {
+	case v1.Pending:
+		// Probe was never picked up by an agent, delete immediately
+		if err := r.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
+		return nil
+
+	case v1.Active:
+		// Probe is active, set to terminating and wait for agent cleanup
+		existingProbe.Status = v1.Terminating
+		if _, err := r.UpdateProbe(withStatusReason(ctx, deletionReason), *existingProbe); err != nil {
+			return fmt.Errorf("failed to update probe %s to terminating status: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
+		return nil
+
+	case v1.Terminating:
+		// Already terminating, no action needed
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
+		return nil
+
+	case v1.Failed:
+		// Failed probe, delete immediately as agent likely won't process it
+		if err := r.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
+		return nil
+
+	default:
+		// Unknown status, treat as pending and delete immediately
+		if err := r.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), existingProbe.Status, err)
+		}
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", existingProbe.Status)
+		return nil
+	}
+}
+
+// DeleteProbeStorage removes a probe from its hash and the index sets, frees
+// its URL if it holds it, and leaves a tombstone expiring after the tombstone
+// TTL, all in one transaction.
+func (r *RedisProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	if probeID == (uuid.UUID{}) {
+		return fmt.Errorf("probe ID cannot be empty")
+	}
+
+	probeKey := r.probeKey(probeID)
+	err := r.transaction(ctx, probeID, func(tx *redis.Tx) error {
+		existingProbe, err := r.readProbe(ctx, tx, probeID)
+		if err != nil {
+			return err
+		}
+		if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+			return err
+		}
+		oldLabels := v1.LabelsSchema{}
+		if existingProbe.Labels != nil {
+			oldLabels = *existingProbe.Labels
+		}
+		var releaseURL bool
+		urlHash, hasURLHash := oldLabels[probeURLHashLabelKey]
+		if hasURLHash {
+			if err := tx.Watch(ctx, r.urlHashKey(urlHash)).Err(); err != nil {
+				return fmt.Errorf("failed to watch the URL hash: %w", err)
+			}
+			holder, err := tx.Get(ctx, r.urlHashKey(urlHash)).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return fmt.Errorf("failed to read the URL hash: %w", err)
+			}
+			releaseURL = holder == probeID.String()
+		}
+
+		tombstone, err := json.Marshal(newTombstone(probeID))
+		if err != nil {
+			return fmt.Errorf("failed to marshal tombstone: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, probeKey)
+			for _, key := range r.indexKeys(oldLabels) {
+				pipe.SRem(ctx, key, probeID.String())
+			}
+			if releaseURL {
+				pipe.Del(ctx, r.urlHashKey(urlHash))
+			}
+			pipe.Set(ctx, r.tombstoneKey(probeID), tombstone, cmp.Or(r.TombstoneTTL, defaultTombstoneTTL))
+			return nil
+		})
+		return err
+	}, probeKey)
+	if err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "Deleted probe hash", "probe_id", probeID)
+	return nil
+}
+
+// transaction runs fn, which writes probeID, in a transaction watching keys.
+// It is retried while other clients change the keys fn read before its
+// writes are applied.
+func (r *RedisProbeStore) transaction(ctx context.Context, probeID uuid.UUID, fn func(tx *redis.Tx) error, keys ...string) error {
+	for range redisTxAttempts {
+		err := r.client.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return k8serrors.NewConflict(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String(),
+		fmt.Errorf("the probe kept changing concurrently"))
+}
+
+// isLiveStatus reports whether a probe with status holds its URL, so that no
+// other probe may be created for it.
+func isLiveStatus(status v1.StatusSchema) bool {
+	return status != v1.Terminating && status != v1.Failed
+}
+
+// GetTombstone returns the tombstone of a recently removed probe.
+func (r *RedisProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	data, err := r.client.Get(ctx, r.tombstoneKey(probeID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tombstone: %w", err)
+	}
+	var tombstone Tombstone
+	if err := json.Unmarshal(data, &tombstone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+	}
+	if tombstone.expired(r.TombstoneTTL) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	return &tombstone, nil
+}
+
+// CreateAPIKey stores a new API key.
+func (r *RedisProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	created, err := r.client.HSetNX(ctx, r.prefix+redisAPIKeysKey, key.ID, data).Result()
+	if err != nil {
+		return fmt.Errorf("failed to store API key: %w", err)
+	}
+	if !created {
+		return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "apikeys"}, key.ID)
+	}
+	return nil
+}
+
+// ListAPIKeys returns the stored API keys.
+func (r *RedisProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	values, err := r.client.HVals(ctx, r.prefix+redisAPIKeysKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	keys := make([]APIKey, 0, len(values))
+	for _, value := range values {
+		var key APIKey
+		if err := json.Unmarshal([]byte(value), &key); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling API key", "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sortAPIKeys(keys)
+	return keys, nil
+}
+
+// DeleteAPIKey removes a stored API key.
+func (r *RedisProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	n, err := r.client.HDel(ctx, r.prefix+redisAPIKeysKey, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+	if n == 0 {
+		return apiKeyNotFound(id)
+	}
+	return nil
+}
+
+// AddOutboxEvent stores an event to publish.
+func (r *RedisProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+	if err := r.client.HSet(ctx, r.prefix+redisOutboxKey, event.ID, data).Err(); err != nil {
+		return fmt.Errorf("failed to store outbox event: %w", err)
+	}
+	return nil
+}
+
+// ListOutboxEvents returns the events waiting in the outbox.
+func (r *RedisProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	values, err := r.client.HVals(ctx, r.prefix+redisOutboxKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	events := make([]OutboxEvent, 0, len(values))
+	for _, value := range values {
+		var event OutboxEvent
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling outbox event", "error", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	sortOutboxEvents(events)
+	return events, nil
+}
+
+// DeleteOutboxEvent removes a published event.
+func (r *RedisProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	n, err := r.client.HDel(ctx, r.prefix+redisOutboxKey, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete outbox event: %w", err)
+	}
+	if n == 0 {
+		return outboxEventNotFound(id)
+	}
+	return nil
+}
+
+// PutRecord stores a record.
+func (r *RedisProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	if err := r.client.HSet(ctx, r.prefix+redisRecordsKey+kind, record.ID, data).Err(); err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+	return nil
+}
+
+// CreateRecord stores a record unless one with the same ID is stored.
+func (r *RedisProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	created, err := r.client.HSetNX(ctx, r.prefix+redisRecordsKey+kind, record.ID, data).Result()
+	if err != nil {
+		return fmt.Errorf("failed to store record: %w", err)
+	}
+	if !created {
+		return recordExists(kind, record.ID)
+	}
+	return nil
+}
+
+// GetRecord returns a stored record.
+func (r *RedisProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	value, err := r.client.HGet(ctx, r.prefix+redisRecordsKey+kind, id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, recordNotFound(kind, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record: %w", err)
+	}
+	var record Record
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return &record, nil
+}
+
+// ListRecords returns the stored records of the kind.
+func (r *RedisProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	values, err := r.client.HVals(ctx, r.prefix+redisRecordsKey+kind).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	records := make([]Record, 0, len(values))
+	for _, value := range values {
+		var record Record
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling record", "kind", kind, "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// DeleteRecord removes a stored record.
+func (r *RedisProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	n, err := r.client.HDel(ctx, r.prefix+redisRecordsKey+kind, id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
+	}
+	if n == 0 {
+		return recordNotFound(kind, id)
+	}
+	return nil
+}
+
+// ProbeWithURLHashExists checks if a live probe with the given URL hash
+// exists, which is the case while its URL hash key is set.
+func (r *RedisProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	n, err := r.client.Exists(ctx, r.urlHashKey(urlHashString)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing probes: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GarbageCollectStaleProbes applies the same heartbeat rules as the Kubernetes
+// store: stale or never-reconciled probes are moved to terminating, and probes
+// that are already terminating are deleted. Probes without a heartbeat are
+// aged by their creation timestamp. Tombstones expire on their own.
+func (r *RedisProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	probes, err := r.ListProbes(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list probes for GC: %w", err)
+	}
+
+	deleted := 0
+	now := clock.Now()
+	for _, probe := range probes {
+		if probe.Labels == nil {
+			probe.Labels = &v1.LabelsSchema{}
+		}
+		var reason string
+		if value, ok := (*probe.Labels)[lastReconciledKey]; ok {
+			lastReconciled, err := time.Parse(lastReconciledLayout, value)
+			if err != nil {
+				slog.WarnContext(ctx, "GC: could not parse last-reconciled, skipping", "probe_id", probe.Id, "last_reconciled", value, "error", err)
+				continue
+			}
+			if now.Sub(lastReconciled) <= r.StaleProbeTTL {
+				continue
+			}
+			reason = fmt.Sprintf("stale heartbeat %s", value)
+		} else {
+			if probe.CreationTimestamp == nil || now.Sub(*probe.CreationTimestamp) <= r.NoHeartbeatProbeTTL {
+				continue
+			}
+			reason = "no heartbeat ever received"
+		}
+
+		if probe.Status == v1.Terminating {
+			// Already terminating -- delete it (agent had its chance)
+			if err := r.DeleteProbeStorage(WithResourceVersion(ctx, resourceVersionOf(&probe)), probe.Id); err != nil {
+				slog.ErrorContext(ctx, "GC: failed to delete terminating probe", "probe_id", probe.Id, "error", err)
+				continue
+			}
+			slog.InfoContext(ctx, "GC: deleted already-terminating probe", "probe_id", probe.Id, "reason", reason)
+			deleted++
+			continue
+		}
+
+		probe.Status = v1.Terminating
+		if _, err := r.UpdateProbe(WithResourceVersion(withStatusReason(ctx, reason), resourceVersionOf(&probe)), probe); err != nil {
+			slog.ErrorContext(ctx, "GC: failed to transition probe to terminating", "probe_id", probe.Id, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "GC: transitioned probe to terminating", "probe_id", probe.Id, "reason", reason)
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,250 @@
+package probestore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func newTestRedisProbeStore(t *testing.T) (*RedisProbeStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	store, err := NewRedisProbeStore(context.Background(), RedisConfig{URL: "redis://" + server.Addr(), Prefix: "synthetics:"})
+	require.NoError(t, err)
+	t.Cleanup(func() { store.client.Close() }) //nolint:errcheck
+	return store, server
+}
+
+func TestNewRedisProbeStore(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  RedisConfig
+	}{
+		{name: "no URL", cfg: RedisConfig{}},
+		{name: "invalid URL", cfg: RedisConfig{URL: "http://localhost:6379"}},
+		{name: "unreachable server", cfg: RedisConfig{URL: "redis://127.0.0.1:1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRedisProbeStore(context.Background(), tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRedisProbeStore(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestRedisProbeStore(t)
+
+	var created []*v1.ProbeObject
+	for i, env := range []string{"prod", "prod", "stage"} {
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{
+			Id:        uuid.New(),
+			StaticUrl: "https://example.com/" + env + string(rune('a'+i)),
+			Labels:    &v1.LabelsSchema{"env": env},
+			Status:    v1.Pending,
+		}, "hash-"+string(rune('a'+i)))
+		require.NoError(t, err)
+		require.NotNil(t, probe.ResourceVersion)
+		created = append(created, probe)
+	}
+	assert.True(t, server.Exists("synthetics:probe:"+created[0].Id.String()))
+	members, err := server.Members("synthetics:label:env=prod")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{created[0].Id.String(), created[1].Id.String()}, members)
+	members, err = server.Members("synthetics:status:pending")
+	require.NoError(t, err)
+	assert.Len(t, members, 3)
+
+	t.Run("list with selector", func(t *testing.T) {
+		for selector, expected := range map[string][]*v1.ProbeObject{
+			"":                    created,
+			"env=prod":            created[:2],
+			"env in (prod,stage)": created,
+			"env=prod,rhobs-synthetics/status=pending": created[:2],
+			"env!=prod":                      created[2:],
+			"env=prod,env!=prod":             nil,
+			"env=dev":                        nil,
+			ProbeSelector:                    created,
+			"rhobs-synthetics/status=active": nil,
+		} {
+			probes, err := store.ListProbes(ctx, selector)
+			require.NoError(t, err, selector)
+			var ids []uuid.UUID
+			for _, probe := range probes {
+				ids = append(ids, probe.Id)
+			}
+			var expectedIDs []uuid.UUID
+			for _, probe := range expected {
+				expectedIDs = append(expectedIDs, probe.Id)
+			}
+			assert.ElementsMatch(t, expectedIDs, ids, selector)
+		}
+	})
+
+	t.Run("updates move the probe between index sets", func(t *testing.T) {
+		probe, err := store.GetProbe(ctx, created[1].Id)
+		require.NoError(t, err)
+		probe.Status = v1.Active
+		(*probe.Labels)["env"] = "stage"
+		_, err = store.UpdateProbe(ctx, *probe)
+		require.NoError(t, err)
+
+		members, err := server.Members("synthetics:label:env=stage")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{created[1].Id.String(), created[2].Id.String()}, members)
+		members, err = server.Members("synthetics:status:active")
+		require.NoError(t, err)
+		assert.Equal(t, []string{created[1].Id.String()}, members)
+		probes, err := store.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		require.Len(t, probes, 1)
+		assert.Equal(t, created[0].Id, probes[0].Id)
+	})
+
+	t.Run("duplicate URL hash", func(t *testing.T) {
+		_, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/proda", Status: v1.Pending}, "hash-a")
+		assert.True(t, k8serrors.IsAlreadyExists(err))
+		_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: created[0].Id, StaticUrl: "https://example.com/other", Status: v1.Pending}, "hash-other")
+		assert.True(t, k8serrors.IsAlreadyExists(err), "probe IDs are unique too")
+	})
+
+	t.Run("stale resource version", func(t *testing.T) {
+		stale := *created[2]
+		current, err := store.GetProbe(ctx, stale.Id)
+		require.NoError(t, err)
+		current.Status = v1.Active
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+
+		_, err = store.UpdateProbe(WithResourceVersion(ctx, *stale.ResourceVersion), stale)
+		assert.True(t, k8serrors.IsConflict(err))
+		err = store.DeleteProbeStorage(WithResourceVersion(ctx, *stale.ResourceVersion), stale.Id)
+		assert.True(t, k8serrors.IsConflict(err))
+	})
+
+	t.Run("terminating probes free their URL until they are live again", func(t *testing.T) {
+		probe, err := store.GetProbe(ctx, created[2].Id)
+		require.NoError(t, err)
+		probe.Status = v1.Terminating
+		terminating, err := store.UpdateProbe(ctx, *probe)
+		require.NoError(t, err)
+		exists, err := store.ProbeWithURLHashExists(ctx, "hash-c")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		replacement, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/stagec", Status: v1.Pending}, "hash-c")
+		require.NoError(t, err)
+		terminating.Status = v1.Active
+		_, err = store.UpdateProbe(ctx, *terminating)
+		assert.True(t, k8serrors.IsAlreadyExists(err), "the URL is held by the replacement")
+
+		require.NoError(t, store.DeleteProbeStorage(ctx, terminating.Id))
+		exists, err = store.ProbeWithURLHashExists(ctx, "hash-c")
+		require.NoError(t, err)
+		assert.True(t, exists, "removing the old probe leaves the replacement's URL alone")
+		require.NoError(t, store.DeleteProbeStorage(ctx, replacement.Id))
+	})
+
+	t.Run("deletion frees the URL hash and the index sets", func(t *testing.T) {
+		require.NoError(t, store.DeleteProbe(ctx, created[0].Id))
+		_, err := store.GetProbe(ctx, created[0].Id)
+		assert.True(t, k8serrors.IsNotFound(err))
+		assert.True(t, k8serrors.IsNotFound(store.DeleteProbeStorage(ctx, created[0].Id)))
+
+		assert.False(t, server.Exists("synthetics:url-hash:hash-a"))
+		assert.False(t, server.Exists("synthetics:label:env=prod"), "Redis removes emptied sets")
+		_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/proda", Status: v1.Pending}, "hash-a")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRedisProbeStore_ConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRedisProbeStore(t)
+
+	const creators = 8
+	errs := make([]error, creators)
+	var wg sync.WaitGroup
+	for i := range creators {
+		wg.Go(func() {
+			_, errs[i] = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+		})
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.True(t, k8serrors.IsAlreadyExists(err), err)
+	}
+	assert.Equal(t, 1, succeeded)
+	probes, err := store.ListProbes(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, probes, 1)
+}
+
+func TestRedisProbeStore_GarbageCollectStaleProbes(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestRedisProbeStore(t)
+	store.StaleProbeTTL = time.Hour
+	store.NoHeartbeatProbeTTL = time.Hour
+	store.TombstoneTTL = time.Hour
+
+	heartbeat := func(d time.Duration) v1.LabelsSchema {
+		return v1.LabelsSchema{lastReconciledKey: time.Now().UTC().Add(-d).Format(lastReconciledLayout)}
+	}
+	create := func(status v1.StatusSchema, labels v1.LabelsSchema) uuid.UUID {
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending, Labels: &labels}, uuid.NewString())
+		require.NoError(t, err)
+		if status != v1.Pending {
+			probe.Status = status
+			_, err = store.UpdateProbe(ctx, *probe)
+			require.NoError(t, err)
+		}
+		return probe.Id
+	}
+	fresh := create(v1.Active, heartbeat(time.Minute))
+	stale := create(v1.Active, heartbeat(2*time.Hour))
+	staleTerminating := create(v1.Terminating, heartbeat(2*time.Hour))
+	unparseable := create(v1.Active, v1.LabelsSchema{lastReconciledKey: "not-a-timestamp"})
+	newWithoutHeartbeat := create(v1.Pending, v1.LabelsSchema{})
+
+	deleted, err := store.GarbageCollectStaleProbes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	for id, expected := range map[uuid.UUID]v1.StatusSchema{
+		fresh:               v1.Active,
+		stale:               v1.Terminating,
+		unparseable:         v1.Active,
+		newWithoutHeartbeat: v1.Pending,
+	} {
+		probe, err := store.GetProbe(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, expected, probe.Status)
+	}
+	_, err = store.GetProbe(ctx, staleTerminating)
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	t.Run("tombstones expire with the tombstone TTL", func(t *testing.T) {
+		_, err := store.GetTombstone(ctx, staleTerminating)
+		require.NoError(t, err)
+		server.FastForward(2 * time.Hour)
+		_, err = store.GetTombstone(ctx, staleTerminating)
+		assert.True(t, k8serrors.IsNotFound(err))
+		assert.False(t, server.Exists(store.tombstoneKey(staleTerminating)))
+	})
+}
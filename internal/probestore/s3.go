@@ -0,0 +1,834 @@
+package probestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+
+	// s3ProbesPrefix holds one JSON object per probe, named after its ID.
+	s3ProbesPrefix = "probes/"
+	// s3TombstonesPrefix holds the tombstones of removed probes, named after
+	// their ID.
+	s3TombstonesPrefix = "tombstones/"
+	// s3APIKeysPrefix holds the API keys, named after their ID.
+	s3APIKeysPrefix = "apikeys/"
+	// s3OutboxPrefix holds the events waiting in the outbox, named after
+	// their ID.
+	s3OutboxPrefix = "outbox/"
+	// s3RecordsPrefix holds the records, in a prefix per kind, named after
+	// their encoded ID.
+	s3RecordsPrefix = "records/"
+	// s3URLHashIndexKey is the object mapping URL hashes to the IDs of the
+	// probes that have them, so creating a probe need not read every probe.
+	s3URLHashIndexKey = "url-hash-index.json"
+
+	// s3IndexAttempts bounds the retries of an index update that keeps losing
+	// against concurrent writers.
+	s3IndexAttempts = 5
+	// s3ListConcurrency is how many probe objects ListProbes reads at once.
+	s3ListConcurrency = 16
+)
+
+// S3ProbeStore implements the ProbeStorage interface on an S3-compatible
+// object store. Each probe is stored as a JSON object whose ETag is its
+// resource version, and an index object maps URL hashes to probe IDs.
+// Writes are conditional on the ETag read, so concurrent replicas don't
+// overwrite each other.
+type S3ProbeStore struct {
+	client              *s3Client
+	prefix              string
+	StaleProbeTTL       time.Duration
+	NoHeartbeatProbeTTL time.Duration
+	TombstoneTTL        time.Duration
+}
+
+// NewS3ProbeStore creates an S3ProbeStore and checks that the bucket is
+// writable.
+func NewS3ProbeStore(ctx context.Context, cfg S3Config) (*S3ProbeStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket cannot be empty")
+	}
+	if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+		return nil, fmt.Errorf("s3 access key ID and secret access key must be set together")
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultS3Region
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q: must be an http or https URL", cfg.Endpoint)
+	}
+	prefix := cfg.Prefix
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	staleTTL, noHeartbeatTTL := staleProbeTTLsFromEnv()
+	store := &S3ProbeStore{
+		client:              newS3Client(cfg, endpoint.String(), nil),
+		prefix:              prefix,
+		StaleProbeTTL:       staleTTL,
+		NoHeartbeatProbeTTL: noHeartbeatTTL,
+		TombstoneTTL:        tombstoneTTLFromEnv(),
+	}
+	if err := store.CheckWritable(ctx); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Initializing s3 probe store", "endpoint", endpoint.Redacted(), "bucket", cfg.Bucket, "prefix", prefix,
+		"stale_ttl", staleTTL, "no_heartbeat_ttl", noHeartbeatTTL)
+	return store, nil
+}
+
+// CheckWritable checks that an object can be written to the bucket.
+func (s *S3ProbeStore) CheckWritable(ctx context.Context) error {
+	key := s.prefix + ".write_test"
+	if _, err := s.client.put(ctx, key, []byte("test"), ""); err != nil {
+		return fmt.Errorf("s3 bucket is not writable: %w", err)
+	}
+	if err := s.client.delete(ctx, key); err != nil {
+		slog.WarnContext(ctx, "Failed to remove s3 write test object", "key", key, "error", err)
+	}
+	return nil
+}
+
+// CheckHealth checks that the bucket can be listed, fetching at most one key.
+func (s *S3ProbeStore) CheckHealth(ctx context.Context) error {
+	if err := s.client.check(ctx, s.prefix); err != nil {
+		return fmt.Errorf("s3 bucket is not readable: %w", err)
+	}
+	return nil
+}
+
+func (s *S3ProbeStore) probeKey(probeID uuid.UUID) string {
+	return s.prefix + s3ProbesPrefix + probeID.String() + ".json"
+}
+
+func (s *S3ProbeStore) tombstoneKey(probeID uuid.UUID) string {
+	return s.prefix + s3TombstonesPrefix + probeID.String() + ".json"
+}
+
+func (s *S3ProbeStore) apiKeyKey(id string) string {
+	return s.prefix + s3APIKeysPrefix + id + ".json"
+}
+
+func (s *S3ProbeStore) outboxEventKey(id string) string {
+	return s.prefix + s3OutboxPrefix + id + ".json"
+}
+
+func (s *S3ProbeStore) recordsPrefix(kind string) string {
+	return s.prefix + s3RecordsPrefix + kind + "/"
+}
+
+func (s *S3ProbeStore) recordKey(kind, id string) string {
+	return s.recordsPrefix(kind) + recordName(id) + ".json"
+}
+
+// ListProbes lists all probes that match the given label selector.
+func (s *S3ProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	sel, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label selector: %w", err)
+	}
+
+	keys, err := s.client.list(ctx, s.prefix+s3ProbesPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list probe objects: %w", err)
+	}
+
+	read := make([]*v1.ProbeObject, len(keys))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, s3ListConcurrency)
+	for i, key := range keys {
+		if !strings.HasSuffix(key, ".json") {
+			continue
+		}
+		slots <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-slots }()
+			probe, err := s.readProbe(ctx, key)
+			if err != nil {
+				// Probes deleted since the listing are simply left out.
+				if !errors.Is(err, errS3NotFound) {
+					slog.WarnContext(ctx, "Skipped unreadable probe object", "key", key, "error", err)
+				}
+				return
+			}
+			read[i] = probe
+		})
+	}
+	wg.Wait()
+
+	probes := []v1.ProbeObject{}
+	for _, probe := range read {
+		if probe == nil {
+			continue
+		}
+		probeLabels := labels.Set{}
+		if probe.Labels != nil {
+			probeLabels = labels.Set(*probe.Labels)
+		}
+		if sel.Matches(probeLabels) {
+			probes = append(probes, *probe)
+		}
+	}
+	return probes, nil
+}
+
+// GetProbe retrieves a single probe by its ID.
+func (s *S3ProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	probe, err := s.readProbe(ctx, s.probeKey(probeID))
+	if err != nil {
+		if errors.Is(err, errS3NotFound) {
+			return nil, k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String())
+		}
+		return nil, err
+	}
+	return probe, nil
+}
+
+// readProbe reads the probe object at key, with its ETag as resource version.
+func (s *S3ProbeStore) readProbe(ctx context.Context, key string) (*v1.ProbeObject, error) {
+	data, etag, err := s.client.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	var probe v1.ProbeObject
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal probe: %w", err)
+	}
+	return withResourceVersion(&probe, etag), nil
+}
+
+// CreateProbe writes a new probe object and adds it to the URL hash index.
+// A live probe with the same URL hash, including one created concurrently by
+// another replica, is reported as an AlreadyExists error.
+func (s *S3ProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+	if urlHashString == "" {
+		return nil, fmt.Errorf("URL hash cannot be empty")
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.ProbeWithURLHashExists(ctx, urlHashString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing probe with URL hash: %w", err)
+	}
+	if exists {
+		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+	}
+
+	if probe.Labels == nil {
+		probe.Labels = &v1.LabelsSchema{}
+	}
+	(*probe.Labels)[probeURLHashLabelKey] = urlHashString
+	(*probe.Labels)[baseAppLabelKey] = baseAppLabelValue
+	(*probe.Labels)[probeStatusLabelKey] = string(probe.Status)
+	withFirstGeneration(&probe)
+	withCreationTimestamp(&probe)
+
+	probe.ResourceVersion = nil
+	data, err := json.Marshal(probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal probe: %w", err)
+	}
+	etag, err := s.client.create(ctx, s.probeKey(probe.Id), data)
+	if err != nil {
+		if errors.Is(err, errS3PreconditionFailed) {
+			return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String())
+		}
+		return nil, fmt.Errorf("failed to write probe object: %w", err)
+	}
+
+	// The probe object is written before it is indexed, so a concurrent
+	// create of the same URL that indexes first is seen here, and this
+	// probe is seen by one that indexes later.
+	if err := s.indexProbe(ctx, urlHashString, probe.Id); err != nil {
+		if deleteErr := s.client.delete(ctx, s.probeKey(probe.Id)); deleteErr != nil {
+			slog.ErrorContext(ctx, "Failed to remove unindexed probe object", "probe_id", probe.Id, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "Created probe", "probe_id", probe.Id, "url_hash", urlHashString)
+	return withResourceVersion(&probe, etag), nil
+}
+
+// indexProbe adds probeID to the index entry of urlHash, dropping IDs of
+// probes that no longer exist. It fails with an AlreadyExists error if
+// another live probe has the hash.
+func (s *S3ProbeStore) indexProbe(ctx context.Context, urlHash string, probeID uuid.UUID) error {
+	for range s3IndexAttempts {
+		index, etag, err := s.readIndex(ctx)
+		if err != nil {
+			return err
+		}
+		ids := []uuid.UUID{probeID}
+		for _, id := range index[urlHash] {
+			if id == probeID {
+				continue
+			}
+			other, err := s.GetProbe(ctx, id)
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read indexed probe %s: %w", id, err)
+			}
+			if other.Status != v1.Terminating && other.Status != v1.Failed {
+				return k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+			}
+			ids = append(ids, id)
+		}
+		index[urlHash] = ids
+
+		err = s.writeIndex(ctx, index, etag)
+		if !errors.Is(err, errS3PreconditionFailed) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update the URL hash index: it kept changing concurrently")
+}
+
+// unindexProbe removes probeID from the index entry of urlHash.
+func (s *S3ProbeStore) unindexProbe(ctx context.Context, urlHash string, probeID uuid.UUID) error {
+	for range s3IndexAttempts {
+		index, etag, err := s.readIndex(ctx)
+		if err != nil {
+			return err
+		}
+		ids := slices.DeleteFunc(slices.Clone(index[urlHash]), func(id uuid.UUID) bool { return id == probeID })
+		if len(ids) == len(index[urlHash]) {
+			return nil
+		}
+		if len(ids) == 0 {
+			delete(index, urlHash)
+		} else {
+			index[urlHash] = ids
+		}
+
+		err = s.writeIndex(ctx, index, etag)
+		if !errors.Is(err, errS3PreconditionFailed) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update the URL hash index: it kept changing concurrently")
+}
+
+// readIndex returns the URL hash index and its ETag, which is empty when the
+// index has not been written yet.
+func (s *S3ProbeStore) readIndex(ctx context.Context) (map[string][]uuid.UUID, string, error) {
+	index := map[string][]uuid.UUID{}
+	data, etag, err := s.client.get(ctx, s.prefix+s3URLHashIndexKey)
+	if errors.Is(err, errS3NotFound) {
+		return index, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read the URL hash index: %w", err)
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal the URL hash index: %w", err)
+	}
+	return index, etag, nil
+}
+
+// writeIndex replaces the index at etag, or creates it when etag is empty.
+// It returns errS3PreconditionFailed if the index changed since it was read.
+func (s *S3ProbeStore) writeIndex(ctx context.Context, index map[string][]uuid.UUID, etag string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the URL hash index: %w", err)
+	}
+	key := s.prefix + s3URLHashIndexKey
+	if etag == "" {
+		_, err = s.client.create(ctx, key, data)
+	} else {
+		_, err = s.client.put(ctx, key, data, etag)
+	}
+	if err != nil && !errors.Is(err, errS3PreconditionFailed) {
+		return fmt.Errorf("failed to write the URL hash index: %w", err)
+	}
+	return err
+}
+
+// UpdateProbe replaces an existing probe object, provided it did not change
+// since it was read.
+func (s *S3ProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	if probe.Id == (uuid.UUID{}) {
+		return nil, fmt.Errorf("probe ID cannot be empty")
+	}
+
+	existingProbe, err := s.GetProbe(ctx, probe.Id)
+	if err != nil {
+		return nil, err
+	}
+	etag := resourceVersionOf(existingProbe)
+	if err := checkResourceVersion(ctx, probe.Id, etag); err != nil {
+		return nil, err
+	}
+	if err := validateStatus(probe.Status); err != nil {
+		return nil, err
+	}
+	withNextGeneration(&probe, *existingProbe)
+	keepCreationTimestamp(&probe, *existingProbe)
+	withUpdateTimestamp(&probe, *existingProbe)
+	withDeletionTimestamp(&probe, *existingProbe)
+	withStatusHistory(ctx, &probe, *existingProbe)
+	withURLHash(&probe, *existingProbe)
+
+	// The URL hash label is immutable, as the index is keyed by it.
+	if probe.Labels == nil {
+		probe.Labels = &v1.LabelsSchema{}
+	}
+	(*probe.Labels)[baseAppLabelKey] = baseAppLabelValue
+	(*probe.Labels)[probeStatusLabelKey] = string(probe.Status)
+	if existingProbe.Labels != nil {
+		if urlHash, ok := (*existingProbe.Labels)[probeURLHashLabelKey]; ok {
+			(*probe.Labels)[probeURLHashLabelKey] = urlHash
+		}
+	}
+
+	probe.ResourceVersion = nil
+	data, err := json.Marshal(probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal updated probe: %w", err)
+	}
+	newETag, err := s.client.put(ctx, s.probeKey(probe.Id), data, etag)
+	if err != nil {
+		if errors.Is(err, errS3PreconditionFailed) {
+			return nil, k8serrors.NewConflict(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probe.Id.String(),
+				fmt.Errorf("the probe changed concurrently"))
+		}
+		return nil, fmt.Errorf("failed to write updated probe object: %w", err)
+	}
+
+	slog.DebugContext(ctx, "Updated probe", "probe_id", probe.Id)
+	return withResourceVersion(&probe, newETag), nil
+}
+
+// DeleteProbe handles deletion based on probe status.
+func (s *S3ProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	if probeID == (uuid.UUID{}) {
+		return fmt.Errorf("probe ID cannot be empty")
+	}
+
+	existingProbe, err := s.GetProbe(ctx, probeID)
+	if err != nil {
+		return err // Pass the error up, including not found errors
+	}
+	if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+		return err
+	}
+
+	switch existingProbe.Status {
+	case v1.Pending:
+		// Probe was never picked up by an agent, delete immediately
+		if err := s.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete pending probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted pending probe immediately, it was never processed by an agent", "probe_id", probeID)
+		return nil
+
+	case v1.Active:
+		// Probe is active, set to terminating and wait for agent cleanup
+		existingProbe.Status = v1.Terminating
+		if _, err := s.UpdateProbe(withStatusReason(ctx, deletionReason), *existingProbe); err != nil {
+			return fmt.Errorf("failed to update probe %s to terminating status: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Set active probe to terminating, waiting for agent cleanup", "probe_id", probeID)
+		return nil
+
+	case v1.Terminating:
+		// Already terminating, no action needed
+		slog.DebugContext(ctx, "Probe is already terminating", "probe_id", probeID)
+		return nil
+
+	case v1.Failed:
+		// Failed probe, delete immediately as agent likely won't process it
+		if err := s.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete failed probe %s: %w", probeID.String(), err)
+		}
+		slog.InfoContext(ctx, "Deleted failed probe immediately", "probe_id", probeID)
+		return nil
+
+	default:
+		// Unknown status, treat as pending and delete immediately
+		if err := s.DeleteProbeStorage(ctx, probeID); err != nil {
+			return fmt.Errorf("failed to delete probe %s with unknown status %s: %w", probeID.String(), existingProbe.Status, err)
+		}
+		slog.WarnContext(ctx, "Deleted probe with unknown status immediately", "probe_id", probeID, "status", existingProbe.Status)
+		return nil
+	}
+}
+
+// DeleteProbeStorage deletes a probe's object and removes it from the URL
+// hash index. An index entry left behind by a failed removal is harmless:
+// it is dropped the next time a probe with the same URL is created.
+func (s *S3ProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	if probeID == (uuid.UUID{}) {
+		return fmt.Errorf("probe ID cannot be empty")
+	}
+
+	existingProbe, err := s.GetProbe(ctx, probeID)
+	if err != nil {
+		return err
+	}
+	if err := checkResourceVersion(ctx, probeID, resourceVersionOf(existingProbe)); err != nil {
+		return err
+	}
+
+	if err := s.client.delete(ctx, s.probeKey(probeID)); err != nil {
+		return fmt.Errorf("failed to delete probe object: %w", err)
+	}
+	if err := s.writeTombstone(ctx, newTombstone(probeID)); err != nil {
+		slog.WarnContext(ctx, "Failed to write probe tombstone", "probe_id", probeID, "error", err)
+	}
+	if existingProbe.Labels != nil {
+		if urlHash, ok := (*existingProbe.Labels)[probeURLHashLabelKey]; ok {
+			if err := s.unindexProbe(ctx, urlHash, probeID); err != nil {
+				slog.WarnContext(ctx, "Failed to remove deleted probe from the URL hash index", "probe_id", probeID, "error", err)
+			}
+		}
+	}
+
+	slog.DebugContext(ctx, "Deleted probe object", "probe_id", probeID)
+	return nil
+}
+
+// writeTombstone stores the tombstone of a removed probe.
+func (s *S3ProbeStore) writeTombstone(ctx context.Context, tombstone Tombstone) error {
+	data, err := json.Marshal(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+	_, err = s.client.put(ctx, s.tombstoneKey(tombstone.ProbeID), data, "")
+	return err
+}
+
+// GetTombstone returns the tombstone of a recently removed probe.
+func (s *S3ProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	data, _, err := s.client.get(ctx, s.tombstoneKey(probeID))
+	if errors.Is(err, errS3NotFound) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tombstone Tombstone
+	if err := json.Unmarshal(data, &tombstone); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+	}
+	if tombstone.expired(s.TombstoneTTL) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	return &tombstone, nil
+}
+
+// removeExpiredTombstones deletes the tombstones older than the tombstone TTL.
+func (s *S3ProbeStore) removeExpiredTombstones(ctx context.Context) error {
+	keys, err := s.client.list(ctx, s.prefix+s3TombstonesPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list tombstones: %w", err)
+	}
+	for _, key := range keys {
+		data, _, err := s.client.get(ctx, key)
+		if errors.Is(err, errS3NotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var tombstone Tombstone
+		if err := json.Unmarshal(data, &tombstone); err == nil && !tombstone.expired(s.TombstoneTTL) {
+			continue
+		}
+		if err := s.client.delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateAPIKey stores a new API key.
+func (s *S3ProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+	_, err = s.client.create(ctx, s.apiKeyKey(key.ID), data)
+	return err
+}
+
+// ListAPIKeys returns the stored API keys.
+func (s *S3ProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	objects, err := s.client.list(ctx, s.prefix+s3APIKeysPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	keys := make([]APIKey, 0, len(objects))
+	for _, object := range objects {
+		data, _, err := s.client.get(ctx, object)
+		if errors.Is(err, errS3NotFound) {
+			continue // Revoked while listing
+		}
+		if err != nil {
+			return nil, err
+		}
+		var key APIKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling API key object", "key", object, "error", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sortAPIKeys(keys)
+	return keys, nil
+}
+
+// DeleteAPIKey removes a stored API key. S3 deletes succeed for missing
+// objects, so the key is read first to report unknown keys.
+func (s *S3ProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	_, _, err := s.client.get(ctx, s.apiKeyKey(id))
+	if errors.Is(err, errS3NotFound) {
+		return apiKeyNotFound(id)
+	}
+	if err != nil {
+		return err
+	}
+	return s.client.delete(ctx, s.apiKeyKey(id))
+}
+
+// AddOutboxEvent stores an event to publish.
+func (s *S3ProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+	_, err = s.client.create(ctx, s.outboxEventKey(event.ID), data)
+	return err
+}
+
+// ListOutboxEvents returns the events waiting in the outbox.
+func (s *S3ProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	objects, err := s.client.list(ctx, s.prefix+s3OutboxPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	events := make([]OutboxEvent, 0, len(objects))
+	for _, object := range objects {
+		data, _, err := s.client.get(ctx, object)
+		if errors.Is(err, errS3NotFound) {
+			continue // Published while listing
+		}
+		if err != nil {
+			return nil, err
+		}
+		var event OutboxEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling outbox event object", "key", object, "error", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	sortOutboxEvents(events)
+	return events, nil
+}
+
+// DeleteOutboxEvent removes a published event. S3 deletes succeed for
+// missing objects, so the event is read first to report unknown events.
+func (s *S3ProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	_, _, err := s.client.get(ctx, s.outboxEventKey(id))
+	if errors.Is(err, errS3NotFound) {
+		return outboxEventNotFound(id)
+	}
+	if err != nil {
+		return err
+	}
+	return s.client.delete(ctx, s.outboxEventKey(id))
+}
+
+// PutRecord stores a record.
+func (s *S3ProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	_, err = s.client.put(ctx, s.recordKey(kind, record.ID), data, "")
+	return err
+}
+
+// CreateRecord stores a record unless one with the same ID is stored, with a
+// conditional write.
+func (s *S3ProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	_, err = s.client.create(ctx, s.recordKey(kind, record.ID), data)
+	if errors.Is(err, errS3PreconditionFailed) {
+		return recordExists(kind, record.ID)
+	}
+	return err
+}
+
+// GetRecord returns a stored record.
+func (s *S3ProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	data, _, err := s.client.get(ctx, s.recordKey(kind, id))
+	if errors.Is(err, errS3NotFound) {
+		return nil, recordNotFound(kind, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	return &record, nil
+}
+
+// ListRecords returns the stored records of the kind.
+func (s *S3ProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	objects, err := s.client.list(ctx, s.recordsPrefix(kind))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records: %w", err)
+	}
+	records := make([]Record, 0, len(objects))
+	for _, object := range objects {
+		data, _, err := s.client.get(ctx, object)
+		if errors.Is(err, errS3NotFound) {
+			continue // Deleted while listing
+		}
+		if err != nil {
+			return nil, err
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			slog.WarnContext(ctx, "Error unmarshaling record object", "key", object, "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// DeleteRecord removes a stored record. S3 deletes succeed for missing
+// objects, so the record is read first to report unknown records.
+func (s *S3ProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	_, _, err := s.client.get(ctx, s.recordKey(kind, id))
+	if errors.Is(err, errS3NotFound) {
+		return recordNotFound(kind, id)
+	}
+	if err != nil {
+		return err
+	}
+	return s.client.delete(ctx, s.recordKey(kind, id))
+}
+
+// ProbeWithURLHashExists checks if a live probe with the given URL hash
+// exists, reading only the probes the index lists for it.
+func (s *S3ProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	index, _, err := s.readIndex(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range index[urlHashString] {
+		probe, err := s.GetProbe(ctx, id)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read indexed probe %s: %w", id, err)
+		}
+		if probe.Status != v1.Terminating && probe.Status != v1.Failed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GarbageCollectStaleProbes applies the same heartbeat rules as the Kubernetes
+// store: stale or never-reconciled probes are moved to terminating, and probes
+// that are already terminating are deleted. Probes without a heartbeat are
+// aged by their creation timestamp. Expired tombstones are removed.
+func (s *S3ProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	if err := s.removeExpiredTombstones(ctx); err != nil {
+		slog.ErrorContext(ctx, "GC: failed to remove expired tombstones", "error", err)
+	}
+
+	probes, err := s.ListProbes(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list probes for GC: %w", err)
+	}
+
+	deleted := 0
+	now := clock.Now()
+	for _, probe := range probes {
+		if probe.Labels == nil {
+			probe.Labels = &v1.LabelsSchema{}
+		}
+		var reason string
+		if value, ok := (*probe.Labels)[lastReconciledKey]; ok {
+			lastReconciled, err := time.Parse(lastReconciledLayout, value)
+			if err != nil {
+				slog.WarnContext(ctx, "GC: could not parse last-reconciled, skipping", "probe_id", probe.Id, "last_reconciled", value, "error", err)
+				continue
+			}
+			if now.Sub(lastReconciled) <= s.StaleProbeTTL {
+				continue
+			}
+			reason = fmt.Sprintf("stale heartbeat %s", value)
+		} else {
+			if probe.CreationTimestamp == nil || now.Sub(*probe.CreationTimestamp) <= s.NoHeartbeatProbeTTL {
+				continue
+			}
+			reason = "no heartbeat ever received"
+		}
+
+		if probe.Status == v1.Terminating {
+			// Already terminating -- delete it (agent had its chance)
+			if err := s.DeleteProbeStorage(ctx, probe.Id); err != nil {
+				slog.ErrorContext(ctx, "GC: failed to delete terminating probe", "probe_id", probe.Id, "error", err)
+				continue
+			}
+			slog.InfoContext(ctx, "GC: deleted already-terminating probe", "probe_id", probe.Id, "reason", reason)
+			deleted++
+			continue
+		}
+
+		probe.Status = v1.Terminating
+		if _, err := s.UpdateProbe(WithResourceVersion(withStatusReason(ctx, reason), resourceVersionOf(&probe)), probe); err != nil {
+			slog.ErrorContext(ctx, "GC: failed to transition probe to terminating", "probe_id", probe.Id, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "GC: transitioned probe to terminating", "probe_id", probe.Id, "reason", reason)
+		deleted++
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,276 @@
+package probestore
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const testBucket = "probes"
+
+// fakeS3 serves the subset of the S3 API the store uses, for a single bucket
+// addressed path-style. Listings are paginated two keys at a time.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func etagOf(data []byte) string {
+	sum := md5.Sum(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		http.Error(w, "unknown bucket", http.StatusNotFound)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, exists := f.objects[key]
+	switch {
+	case r.Method == http.MethodGet && key == "" && r.URL.Query().Get("list-type") == "2":
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		slices.Sort(keys)
+		type content struct {
+			Key string `xml:"Key"`
+		}
+		result := struct {
+			XMLName               xml.Name  `xml:"ListBucketResult"`
+			Contents              []content `xml:"Contents"`
+			IsTruncated           bool      `xml:"IsTruncated"`
+			NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		}{}
+		for i, k := range keys {
+			if i == 2 {
+				result.IsTruncated = true
+				result.NextContinuationToken = keys[1]
+				break
+			}
+			result.Contents = append(result.Contents, content{Key: k})
+		}
+		xml.NewEncoder(w).Encode(result) //nolint:errcheck
+	case r.Method == http.MethodGet:
+		if !exists {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etagOf(data))
+		w.Write(data) //nolint:errcheck
+	case r.Method == http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && exists ||
+			r.Header.Get("If-Match") != "" && (!exists || r.Header.Get("If-Match") != etagOf(data)) {
+			http.Error(w, "<Error><Code>PreconditionFailed</Code></Error>", http.StatusPreconditionFailed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		w.Header().Set("ETag", etagOf(body))
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "<Error><Code>NotImplemented</Code><Message>unsupported request</Message></Error>", http.StatusNotImplemented)
+	}
+}
+
+func newTestS3ProbeStore(t *testing.T) (*S3ProbeStore, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	store, err := NewS3ProbeStore(context.Background(), S3Config{
+		Bucket:          testBucket,
+		Endpoint:        server.URL,
+		Prefix:          "synthetics",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+		PathStyle:       true,
+	})
+	require.NoError(t, err)
+	return store, fake
+}
+
+func TestNewS3ProbeStore(t *testing.T) {
+	testCases := []struct {
+		name string
+		cfg  S3Config
+	}{
+		{name: "no bucket", cfg: S3Config{}},
+		{name: "access key without secret", cfg: S3Config{Bucket: testBucket, AccessKeyID: "test-key"}},
+		{name: "invalid endpoint", cfg: S3Config{Bucket: testBucket, Endpoint: "s3.example.com"}},
+		{name: "unreachable endpoint", cfg: S3Config{Bucket: testBucket, Endpoint: "http://127.0.0.1:1", PathStyle: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewS3ProbeStore(context.Background(), tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestS3ProbeStore(t *testing.T) {
+	ctx := context.Background()
+	store, fake := newTestS3ProbeStore(t)
+
+	var created []*v1.ProbeObject
+	for i, env := range []string{"prod", "prod", "stage"} {
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{
+			Id:        uuid.New(),
+			StaticUrl: "https://example.com/" + env + strings.Repeat("/", i),
+			Labels:    &v1.LabelsSchema{"env": env},
+			Status:    v1.Pending,
+		}, "hash-"+string(rune('a'+i)))
+		require.NoError(t, err)
+		require.NotNil(t, probe.ResourceVersion)
+		created = append(created, probe)
+	}
+	assert.Contains(t, fake.objects, "synthetics/probes/"+created[0].Id.String()+".json")
+	assert.Contains(t, fake.objects, "synthetics/url-hash-index.json")
+	assert.NotContains(t, fake.objects, "synthetics/.write_test")
+
+	t.Run("list with selector", func(t *testing.T) {
+		probes, err := store.ListProbes(ctx, "env=prod")
+		require.NoError(t, err)
+		assert.Len(t, probes, 2, "reads every page of the listing")
+		all, err := store.ListProbes(ctx, "")
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("duplicate URL hash", func(t *testing.T) {
+		_, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/prod", Status: v1.Pending}, "hash-a")
+		assert.True(t, k8serrors.IsAlreadyExists(err))
+	})
+
+	t.Run("stale resource version", func(t *testing.T) {
+		stale := *created[2]
+		current, err := store.GetProbe(ctx, stale.Id)
+		require.NoError(t, err)
+		current.Status = v1.Active
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+
+		_, err = store.UpdateProbe(WithResourceVersion(ctx, *stale.ResourceVersion), stale)
+		assert.True(t, k8serrors.IsConflict(err))
+	})
+
+	t.Run("deletion frees the URL hash", func(t *testing.T) {
+		require.NoError(t, store.DeleteProbe(ctx, created[0].Id))
+		_, err := store.GetProbe(ctx, created[0].Id)
+		assert.True(t, k8serrors.IsNotFound(err))
+		assert.True(t, k8serrors.IsNotFound(store.DeleteProbeStorage(ctx, created[0].Id)))
+
+		index, _, err := store.readIndex(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, index, "hash-a")
+		_, err = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/prod", Status: v1.Pending}, "hash-a")
+		assert.NoError(t, err)
+	})
+}
+
+func TestS3ProbeStore_ConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestS3ProbeStore(t)
+
+	const creators = 8
+	errs := make([]error, creators)
+	var wg sync.WaitGroup
+	for i := range creators {
+		wg.Go(func() {
+			_, errs[i] = store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+		})
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.True(t, k8serrors.IsAlreadyExists(err), err)
+	}
+	assert.Equal(t, 1, succeeded)
+	probes, err := store.ListProbes(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, probes, 1, "losing creates remove their probe object")
+}
+
+func TestS3ProbeStore_GarbageCollectStaleProbes(t *testing.T) {
+	ctx := context.Background()
+	store, fake := newTestS3ProbeStore(t)
+	store.StaleProbeTTL = time.Hour
+	store.NoHeartbeatProbeTTL = time.Hour
+	store.TombstoneTTL = time.Hour
+
+	heartbeat := func(d time.Duration) v1.LabelsSchema {
+		return v1.LabelsSchema{lastReconciledKey: time.Now().UTC().Add(-d).Format(lastReconciledLayout)}
+	}
+	create := func(status v1.StatusSchema, labels v1.LabelsSchema) uuid.UUID {
+		probe, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending, Labels: &labels}, uuid.NewString())
+		require.NoError(t, err)
+		if status != v1.Pending {
+			probe.Status = status
+			_, err = store.UpdateProbe(ctx, *probe)
+			require.NoError(t, err)
+		}
+		return probe.Id
+	}
+	fresh := create(v1.Active, heartbeat(time.Minute))
+	stale := create(v1.Active, heartbeat(2*time.Hour))
+	staleTerminating := create(v1.Terminating, heartbeat(2*time.Hour))
+	unparseable := create(v1.Active, v1.LabelsSchema{lastReconciledKey: "not-a-timestamp"})
+	newWithoutHeartbeat := create(v1.Pending, v1.LabelsSchema{})
+
+	expiredTombstone := Tombstone{ProbeID: uuid.New(), DeletedAt: time.Now().UTC().Add(-2 * time.Hour)}
+	require.NoError(t, store.writeTombstone(ctx, expiredTombstone))
+	liveTombstone := newTombstone(uuid.New())
+	require.NoError(t, store.writeTombstone(ctx, liveTombstone))
+
+	deleted, err := store.GarbageCollectStaleProbes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.NotContains(t, fake.objects, store.tombstoneKey(expiredTombstone.ProbeID), "expired tombstones are removed")
+	assert.Contains(t, fake.objects, store.tombstoneKey(liveTombstone.ProbeID))
+
+	for id, expected := range map[uuid.UUID]v1.StatusSchema{
+		fresh:               v1.Active,
+		stale:               v1.Terminating,
+		unparseable:         v1.Active,
+		newWithoutHeartbeat: v1.Pending,
+	} {
+		probe, err := store.GetProbe(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, expected, probe.Status)
+	}
+	_, err = store.GetProbe(ctx, staleTerminating)
+	assert.True(t, k8serrors.IsNotFound(err))
+}
@@ -0,0 +1,179 @@
+package probestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// errS3NotFound is returned for keys that do not exist.
+	errS3NotFound = errors.New("s3 object not found")
+	// errS3PreconditionFailed is returned when a conditional write lost
+	// against another writer.
+	errS3PreconditionFailed = errors.New("s3 precondition failed")
+)
+
+// s3Client covers the object operations the S3 store needs, on top of the
+// AWS SDK. Requests are signed with the static credentials of the
+// configuration, or sent anonymously when no access key is set.
+type s3Client struct {
+	client *s3.Client
+	bucket string
+}
+
+// newS3Client returns a client of the bucket of cfg at endpoint. httpClient
+// sends the requests; nil selects one with a 30s timeout.
+func newS3Client(cfg S3Config, endpoint string, httpClient s3.HTTPClient) *s3Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	var creds aws.CredentialsProvider = aws.AnonymousCredentials{}
+	if cfg.AccessKeyID != "" {
+		creds = credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+	}
+	return &s3Client{
+		client: s3.New(s3.Options{
+			BaseEndpoint: aws.String(endpoint),
+			Region:       cfg.Region,
+			Credentials:  creds,
+			UsePathStyle: cfg.PathStyle,
+			HTTPClient:   httpClient,
+			// A conditional write retried after it succeeded would fail its
+			// own precondition, so requests are not retried; the store
+			// retries the index updates it can.
+			Retryer: aws.NopRetryer{},
+			// Checksums are only sent and checked where S3 requires them, as
+			// not every S3-compatible service supports the others.
+			RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+			ResponseChecksumValidation: aws.ResponseChecksumValidationWhenRequired,
+		}),
+		bucket: cfg.Bucket,
+	}
+}
+
+// get returns the object's contents and ETag, without quotes.
+func (c *s3Client) get(ctx context.Context, key string) (data []byte, etag string, err error) {
+	ctx, span := c.start(ctx, "GetObject", key)
+	defer func() { end(span, err) }()
+
+	out, err := c.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &c.bucket, Key: &key})
+	if err != nil {
+		return nil, "", s3Error("GetObject", key, err)
+	}
+	defer out.Body.Close() //nolint:errcheck
+	data, err = io.ReadAll(out.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read s3 object %s: %w", key, err)
+	}
+	return data, strings.Trim(aws.ToString(out.ETag), `"`), nil
+}
+
+// put writes the object and returns its new ETag. When etag is set, the write
+// only succeeds if the object is still at that ETag.
+func (c *s3Client) put(ctx context.Context, key string, data []byte, etag string) (string, error) {
+	input := &s3.PutObjectInput{}
+	if etag != "" {
+		input.IfMatch = aws.String(`"` + etag + `"`)
+	}
+	return c.putObject(ctx, key, data, input)
+}
+
+// create writes the object only if no object exists at key.
+func (c *s3Client) create(ctx context.Context, key string, data []byte) (string, error) {
+	return c.putObject(ctx, key, data, &s3.PutObjectInput{IfNoneMatch: aws.String("*")})
+}
+
+func (c *s3Client) putObject(ctx context.Context, key string, data []byte, input *s3.PutObjectInput) (etag string, err error) {
+	ctx, span := c.start(ctx, "PutObject", key)
+	defer func() { end(span, err) }()
+
+	input.Bucket = &c.bucket
+	input.Key = &key
+	input.Body = bytes.NewReader(data)
+	input.ContentLength = aws.Int64(int64(len(data)))
+	input.ContentType = aws.String("application/json")
+	out, err := c.client.PutObject(ctx, input)
+	if err != nil {
+		return "", s3Error("PutObject", key, err)
+	}
+	return strings.Trim(aws.ToString(out.ETag), `"`), nil
+}
+
+// delete removes the object. Deleting a missing key is not an error.
+func (c *s3Client) delete(ctx context.Context, key string) (err error) {
+	ctx, span := c.start(ctx, "DeleteObject", key)
+	defer func() { end(span, err) }()
+
+	if _, err := c.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &c.bucket, Key: &key}); err != nil {
+		return s3Error("DeleteObject", key, err)
+	}
+	return nil
+}
+
+// list returns the keys starting with prefix, in lexicographic order.
+func (c *s3Client) list(ctx context.Context, prefix string) (keys []string, err error) {
+	ctx, span := c.start(ctx, "ListObjectsV2", "")
+	defer func() { end(span, err) }()
+
+	pages := s3.NewListObjectsV2Paginator(c.client, &s3.ListObjectsV2Input{Bucket: &c.bucket, Prefix: &prefix})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, s3Error("ListObjectsV2", prefix, err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
+
+// check lists at most one key starting with prefix.
+func (c *s3Client) check(ctx context.Context, prefix string) (err error) {
+	ctx, span := c.start(ctx, "ListObjectsV2", "")
+	defer func() { end(span, err) }()
+
+	if _, err := c.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: &c.bucket, Prefix: &prefix, MaxKeys: aws.Int32(1)}); err != nil {
+		return s3Error("ListObjectsV2", prefix, err)
+	}
+	return nil
+}
+
+// start starts the span of an S3 operation on key, or on the bucket when
+// key is empty.
+func (c *s3Client) start(ctx context.Context, op, key string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "s3."+op, trace.WithAttributes(
+		attribute.String("s3.bucket", c.bucket),
+		attribute.String("s3.key", key),
+	))
+}
+
+// s3Error turns the S3 responses the store acts on into errS3NotFound and
+// errS3PreconditionFailed.
+func s3Error(op, key string, err error) error {
+	if resp, ok := errors.AsType[*awshttp.ResponseError](err); ok {
+		switch resp.HTTPStatusCode() {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %s", errS3NotFound, key)
+		case http.StatusPreconditionFailed, http.StatusConflict:
+			// S3 answers 409 ConditionalRequestConflict when two conditional
+			// writes to the same key race.
+			return fmt.Errorf("%w: %s", errS3PreconditionFailed, key)
+		}
+	}
+	return fmt.Errorf("s3 %s %s failed: %w", op, key, err)
+}
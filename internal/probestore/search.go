@@ -0,0 +1,75 @@
+package probestore
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// systemLabelPrefix marks the labels the API manages, which are not searched.
+const systemLabelPrefix = "rhobs-synthetics/"
+
+// SearchQuery matches probes by their static_url and label values, ignoring
+// case: values containing Text, or matching Pattern when it is set. The
+// labels managed by the API, such as the URL hash and the heartbeat, are not
+// searched. Pattern is matched against the lowercased values, so it should
+// be compiled case-insensitive.
+type SearchQuery struct {
+	Text    string
+	Pattern *regexp.Regexp
+}
+
+// Matches reports whether the probe matches the query.
+func (q SearchQuery) Matches(probe v1.ProbeObject) bool {
+	return q.matches(searchValues(probe))
+}
+
+// matches reports whether any of the lowercase values matches the query.
+func (q SearchQuery) matches(values []string) bool {
+	if q.Pattern != nil {
+		return slices.ContainsFunc(values, q.Pattern.MatchString)
+	}
+	text := strings.ToLower(q.Text)
+	return slices.ContainsFunc(values, func(v string) bool { return strings.Contains(v, text) })
+}
+
+// searchValues returns the lowercase static_url and searched label values of
+// the probe.
+func searchValues(probe v1.ProbeObject) []string {
+	values := []string{strings.ToLower(probe.StaticUrl)}
+	if probe.Labels == nil {
+		return values
+	}
+	for key, value := range *probe.Labels {
+		if key == baseAppLabelKey || key == lastReconciledKey || strings.HasPrefix(key, systemLabelPrefix) {
+			continue
+		}
+		values = append(values, strings.ToLower(value))
+	}
+	return values
+}
+
+// Searcher is implemented by stores that can search probes without reading
+// all of them, for example from an index.
+type Searcher interface {
+	// SearchProbes returns the probes matching both the label selector and
+	// the query, in no particular order.
+	SearchProbes(ctx context.Context, selector string, query SearchQuery) ([]v1.ProbeObject, error)
+}
+
+// Search returns the probes of the store matching both the label selector
+// and the query, using the store's index when it is a Searcher, and listing
+// the selected probes and filtering them otherwise.
+func Search(ctx context.Context, store ProbeStorage, selector string, query SearchQuery) ([]v1.ProbeObject, error) {
+	if searcher, ok := store.(Searcher); ok {
+		return searcher.SearchProbes(ctx, selector, query)
+	}
+	probes, err := store.ListProbes(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(probes, func(p v1.ProbeObject) bool { return !query.Matches(p) }), nil
+}
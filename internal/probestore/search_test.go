@@ -0,0 +1,118 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSearchQuery_Matches(t *testing.T) {
+	probe := v1.ProbeObject{
+		StaticUrl: "https://api.Cluster-1.openshiftapps.com/livez",
+		Labels: &v1.LabelsSchema{
+			"app":                              "rhobs-synthetics-probe",
+			"team":                             "SRE-Platform",
+			"rhobs-synthetics/static-url-hash": "deadbeef",
+			"last-reconciled":                  "20260101T000000Z",
+		},
+	}
+	for _, tc := range []struct {
+		name  string
+		query SearchQuery
+		match bool
+	}{
+		{name: "URL substring ignoring case", query: SearchQuery{Text: "CLUSTER-1.OpenShiftApps"}, match: true},
+		{name: "label value", query: SearchQuery{Text: "sre-plat"}, match: true},
+		{name: "no match", query: SearchQuery{Text: "example.com"}},
+		{name: "system labels are not searched", query: SearchQuery{Text: "deadbeef"}},
+		{name: "the app label is not searched", query: SearchQuery{Text: "synthetics-probe"}},
+		{name: "heartbeat is not searched", query: SearchQuery{Text: "20260101"}},
+		{name: "pattern", query: SearchQuery{Pattern: regexp.MustCompile(`(?i)\.openshiftapps\.com/`)}, match: true},
+		{name: "anchored pattern", query: SearchQuery{Pattern: regexp.MustCompile(`(?i)^sre-`)}, match: true},
+		{name: "pattern without match", query: SearchQuery{Pattern: regexp.MustCompile(`(?i)^http://`)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.match, tc.query.Matches(probe))
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	matching := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://a.openshiftapps.com", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "prod"}}
+	other := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://b.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "prod"}}
+	staging := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://c.openshiftapps.com", Status: v1.Active, Labels: &v1.LabelsSchema{"env": "staging"}}
+
+	local := &LocalProbeStore{Directory: t.TempDir()}
+	kube := &KubernetesProbeStore{Client: fake.NewClientset(), Namespace: "default"}
+	for name, store := range map[string]ProbeStorage{"indexed": local, "listed": kube} {
+		t.Run(name, func(t *testing.T) {
+			for _, p := range []v1.ProbeObject{matching, other, staging} {
+				_, err := store.CreateProbe(ctx, p, URLHashLabel(URLHash(p.StaticUrl)))
+				require.NoError(t, err)
+			}
+			probes, err := Search(ctx, store, "env=prod", SearchQuery{Text: "OPENSHIFTAPPS"})
+			require.NoError(t, err)
+			require.Len(t, probes, 1)
+			assert.Equal(t, matching.Id, probes[0].Id)
+		})
+	}
+}
+
+func TestLocalProbeStore_SearchProbes_Index(t *testing.T) {
+	ctx := context.Background()
+	store := &LocalProbeStore{Directory: t.TempDir()}
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://a.example.com", Status: v1.Active, Labels: &v1.LabelsSchema{"team": "sre"}}
+	_, err := store.CreateProbe(ctx, probe, "hash")
+	require.NoError(t, err)
+
+	probes, err := store.SearchProbes(ctx, "", SearchQuery{Text: "sre"})
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.NotNil(t, probes[0].ResourceVersion, "probes are read like ListProbes reads them")
+	assert.Len(t, store.index.entries, 1)
+
+	t.Run("changed files are indexed again", func(t *testing.T) {
+		current, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		(*current.Labels)["team"] = "observability"
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+		// Make sure the modification time moves on coarse filesystems.
+		path := filepath.Join(store.Directory, probe.Id.String()+".json")
+		later := time.Now().Add(time.Minute)
+		require.NoError(t, os.Chtimes(path, later, later))
+
+		probes, err := store.SearchProbes(ctx, "", SearchQuery{Text: "sre"})
+		require.NoError(t, err)
+		assert.Empty(t, probes)
+		probes, err = store.SearchProbes(ctx, "", SearchQuery{Text: "observ"})
+		require.NoError(t, err)
+		require.Len(t, probes, 1)
+		assert.Equal(t, "observability", (*probes[0].Labels)["team"])
+	})
+
+	t.Run("removed files are dropped", func(t *testing.T) {
+		require.NoError(t, store.DeleteProbeStorage(ctx, probe.Id))
+		probes, err := store.SearchProbes(ctx, "", SearchQuery{Text: "example"})
+		require.NoError(t, err)
+		assert.Empty(t, probes)
+		assert.Empty(t, store.index.entries)
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		_, err := store.SearchProbes(ctx, "!!", SearchQuery{Text: "x"})
+		assert.Error(t, err)
+	})
+}
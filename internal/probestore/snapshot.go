@@ -0,0 +1,69 @@
+package probestore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type snapshotKey struct{}
+
+// WithSnapshot returns a context under which the Kubernetes store reads
+// probes from a single listing of their ConfigMaps, made on the first read,
+// instead of getting each one from the API server, and keeps that listing
+// current with its own writes. It backs large batches of updates, which would
+// otherwise read every probe twice. The resource version check of each
+// write still catches probes changed by others since the listing, and
+// probes created since are read as usual. Other stores ignore it.
+func WithSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, snapshotKey{}, &snapshot{})
+}
+
+// snapshotFrom returns the snapshot set by WithSnapshot, or nil.
+func snapshotFrom(ctx context.Context) *snapshot {
+	s, _ := ctx.Value(snapshotKey{}).(*snapshot)
+	return s
+}
+
+// snapshot holds the stored objects of the probes, by probe ID, as listed
+// once by a store.
+type snapshot struct {
+	mu      sync.Mutex
+	loaded  bool
+	objects map[uuid.UUID]any
+}
+
+// get returns the object of the probe, calling list for all of them on the
+// first call. A failed listing is tried again on the next call.
+func (s *snapshot) get(probeID uuid.UUID, list func() (map[uuid.UUID]any, error)) (any, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		objects, err := list()
+		if err != nil {
+			return nil, false, err
+		}
+		s.objects = objects
+		s.loaded = true
+	}
+	obj, ok := s.objects[probeID]
+	return obj, ok, nil
+}
+
+// put records the object of the probe a write returned.
+func (s *snapshot) put(probeID uuid.UUID, obj any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loaded {
+		s.objects[probeID] = obj
+	}
+}
+
+// remove forgets a probe that was removed.
+func (s *snapshot) remove(probeID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, probeID)
+}
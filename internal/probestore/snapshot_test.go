@@ -0,0 +1,74 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesProbeStore_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset()
+	store, err := NewShardedKubernetesProbeStore(ctx, client, testNamespace, testShardNamespaces)
+	require.NoError(t, err)
+
+	var probes []v1.ProbeObject
+	for i := range 5 {
+		probe, hash := probeFor(fmt.Sprintf("https://%d.example.com", i))
+		created, err := store.CreateProbe(ctx, probe, hash)
+		require.NoError(t, err)
+		probes = append(probes, *created)
+	}
+	client.ClearActions()
+
+	ctx = WithSnapshot(ctx)
+	for _, probe := range probes {
+		stored, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		stored.Status = v1.Active
+		_, err = store.UpdateProbe(ctx, *stored)
+		require.NoError(t, err)
+	}
+
+	verbs := map[string]int{}
+	for _, action := range client.Actions() {
+		verbs[action.GetVerb()]++
+	}
+	assert.Equal(t, map[string]int{"list": len(testShardNamespaces) + 1, "update": len(probes)}, verbs,
+		"the probes are listed once in every namespace and never fetched one by one")
+
+	t.Run("reads see the writes made under the snapshot", func(t *testing.T) {
+		stored, err := store.GetProbe(ctx, probes[0].Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1.Active, stored.Status)
+
+		stored.Status = v1.Failed
+		updated, err := store.UpdateProbe(ctx, *stored)
+		require.NoError(t, err)
+		assert.Equal(t, *stored.Generation, *updated.Generation)
+		assert.Equal(t, v1.Failed, updated.Status)
+	})
+
+	t.Run("removed probes are not found", func(t *testing.T) {
+		require.NoError(t, store.DeleteProbeStorage(ctx, probes[1].Id))
+		_, err := store.GetProbe(ctx, probes[1].Id)
+		assert.True(t, k8serrors.IsNotFound(err), "got %v", err)
+	})
+
+	t.Run("probes created since the listing are fetched", func(t *testing.T) {
+		probe, hash := probeFor("https://new.example.com")
+		_, err := store.CreateProbe(context.Background(), probe, hash)
+		require.NoError(t, err)
+		stored, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		assert.Equal(t, probe.StaticUrl, stored.StaticUrl)
+	})
+}
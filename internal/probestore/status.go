@@ -0,0 +1,21 @@
+package probestore
+
+import (
+	"fmt"
+	"slices"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// probeStatuses are the statuses a stored probe may have.
+var probeStatuses = v1.ProbeStatuses()
+
+// validateStatus rejects unknown statuses before they are written to the
+// status label, where they would silently drop the probe from status
+// selectors or fail the whole write.
+func validateStatus(status v1.StatusSchema) error {
+	if !slices.Contains(probeStatuses, status) {
+		return fmt.Errorf("invalid probe status %q, expected one of %v", status, probeStatuses)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeStatusValidation(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			_, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://bad.example.com", Status: "pending,app=x"}, "bad-hash")
+			assert.EqualError(t, err, `invalid probe status "pending,app=x", expected one of [pending active failed terminating deleted]`)
+			exists, err := store.ProbeWithURLHashExists(ctx, "bad-hash")
+			require.NoError(t, err)
+			assert.False(t, exists, "the probe is not stored")
+
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}, "hash")
+			require.NoError(t, err)
+
+			probe := *created
+			probe.Status = ""
+			_, err = store.UpdateProbe(ctx, probe)
+			assert.EqualError(t, err, `invalid probe status "", expected one of [pending active failed terminating deleted]`)
+
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, v1.Pending, stored.Status)
+			probes, err := store.ListProbes(ctx, probeStatusLabelKey+"=pending")
+			require.NoError(t, err)
+			assert.Len(t, probes, 1, "status selectors still find the probe")
+		})
+	}
+}
+
+func TestValidateStatus(t *testing.T) {
+	for _, status := range probeStatuses {
+		assert.NoError(t, validateStatus(status))
+	}
+	assert.Error(t, validateStatus(""))
+	assert.Error(t, validateStatus("Active"))
+}
+
+func TestProbeStatusDetails(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}, "hash")
+			require.NoError(t, err)
+
+			reason, message := "TargetUnreachable", "dial tcp 203.0.113.7:443: i/o timeout"
+			probe := *created
+			probe.Status = v1.Failed
+			probe.StatusReason, probe.StatusMessage = &reason, &message
+			_, err = store.UpdateProbe(ctx, probe)
+			require.NoError(t, err)
+
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Equal(t, &reason, stored.StatusReason)
+			assert.Equal(t, &message, stored.StatusMessage)
+
+			stored.Status = v1.Active
+			stored.StatusReason, stored.StatusMessage = nil, nil
+			_, err = store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			stored, err = store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			assert.Nil(t, stored.StatusReason)
+			assert.Nil(t, stored.StatusMessage)
+		})
+	}
+}
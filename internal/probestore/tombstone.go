@@ -0,0 +1,75 @@
+package probestore
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// defaultTombstoneTTL is how long a removed probe's tombstone is kept.
+// Override with PROBE_TOMBSTONE_TTL env var (e.g., "1h", "72h").
+const defaultTombstoneTTL = 24 * time.Hour
+
+// Tombstone records that a probe was removed from storage.
+type Tombstone struct {
+	ProbeID   uuid.UUID `json:"probe_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TombstoneStore is implemented by stores that keep a tombstone for every
+// probe they remove, so clients syncing probes can tell a probe that was
+// deleted while they were offline from one that never existed. Tombstones
+// expire after the store's tombstone TTL.
+type TombstoneStore interface {
+	// GetTombstone returns the tombstone of a probe removed less than the
+	// tombstone TTL ago, or a NotFound error.
+	GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error)
+}
+
+// tombstoneTTLFromEnv returns the tombstone TTL, honouring the
+// PROBE_TOMBSTONE_TTL override. Invalid values fall back to the default.
+func tombstoneTTLFromEnv() time.Duration {
+	v := os.Getenv("PROBE_TOMBSTONE_TTL")
+	if v == "" {
+		return defaultTombstoneTTL
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl <= 0 {
+		slog.Warn("Invalid PROBE_TOMBSTONE_TTL, using default", "value", v, "default", defaultTombstoneTTL, "error", err)
+		return defaultTombstoneTTL
+	}
+	slog.Info("Using custom PROBE_TOMBSTONE_TTL", "ttl", ttl)
+	return ttl
+}
+
+// newTombstone returns the tombstone of a probe removed now. The time is
+// truncated to seconds like deletion timestamps.
+func newTombstone(probeID uuid.UUID) Tombstone {
+	return Tombstone{ProbeID: probeID, DeletedAt: clock.Stamp()}
+}
+
+// tombstoneCutoff returns when tombstones must have been written to still be
+// live: ttl ago, or the default TTL ago when ttl is zero.
+func tombstoneCutoff(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		ttl = defaultTombstoneTTL
+	}
+	return clock.Now().Add(-ttl)
+}
+
+// expired reports whether the tombstone is older than ttl.
+func (t Tombstone) expired(ttl time.Duration) bool {
+	return t.DeletedAt.Before(tombstoneCutoff(ttl))
+}
+
+// tombstoneNotFound is the error GetTombstone returns when a probe has no
+// live tombstone.
+func tombstoneNotFound(probeID uuid.UUID) error {
+	return k8serrors.NewNotFound(schema.GroupResource{Group: "rhobs-synthetics", Resource: "tombstones"}, probeID.String())
+}
@@ -0,0 +1,145 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// tombstoneConfigMapName is the ConfigMap the Kubernetes-backed stores keep
+// tombstones in, as probe ID keys with RFC 3339 deletion times. It holds no
+// probe-config.json key, so probe listings skip it.
+const tombstoneConfigMapName = "probe-tombstones"
+
+// configMapClient is the part of the typed ConfigMap client the tombstone
+// ConfigMap is written through.
+type configMapClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error)
+	Create(ctx context.Context, cm *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error)
+	Update(ctx context.Context, cm *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error)
+}
+
+// writeConfigMapTombstone adds the tombstone to the tombstone ConfigMap,
+// dropping expired ones so it stays small. Concurrent writers are retried.
+func writeConfigMapTombstone(ctx context.Context, client configMapClient, namespace string, tombstone Tombstone, ttl time.Duration) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := client.Get(ctx, tombstoneConfigMapName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: tombstoneConfigMapName, Namespace: namespace}}
+			cm.Data = map[string]string{tombstone.ProbeID.String(): clock.Format(tombstone.DeletedAt)}
+			_, err = client.Create(ctx, cm, metav1.CreateOptions{})
+			if k8serrors.IsAlreadyExists(err) {
+				// Created concurrently; retry as an update.
+				return k8serrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, tombstoneConfigMapName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cutoff := tombstoneCutoff(ttl)
+		for id, value := range cm.Data {
+			if deletedAt, err := clock.Parse(value); err != nil || deletedAt.Before(cutoff) {
+				delete(cm.Data, id)
+			}
+		}
+		cm.Data[tombstone.ProbeID.String()] = clock.Format(tombstone.DeletedAt)
+		_, err = client.Update(ctx, cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// getConfigMapTombstone reads a tombstone from the tombstone ConfigMap.
+func getConfigMapTombstone(ctx context.Context, client configMapClient, probeID uuid.UUID, ttl time.Duration) (*Tombstone, error) {
+	cm, err := client.Get(ctx, tombstoneConfigMapName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tombstones: %w", err)
+	}
+	value, ok := cm.Data[probeID.String()]
+	if !ok {
+		return nil, tombstoneNotFound(probeID)
+	}
+	deletedAt, err := clock.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tombstone of probe %s: %w", probeID, err)
+	}
+	tombstone := Tombstone{ProbeID: probeID, DeletedAt: deletedAt.UTC()}
+	if tombstone.expired(ttl) {
+		return nil, tombstoneNotFound(probeID)
+	}
+	return &tombstone, nil
+}
+
+// dynamicConfigMaps adapts a dynamic client of ConfigMaps to configMapClient,
+// for the CRD store.
+type dynamicConfigMaps struct {
+	client dynamic.ResourceInterface
+}
+
+func (d dynamicConfigMaps) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	obj, err := d.client.Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return fromUnstructuredConfigMap(obj)
+}
+
+func (d dynamicConfigMaps) Create(ctx context.Context, cm *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	obj, err := toUnstructuredConfigMap(cm)
+	if err != nil {
+		return nil, err
+	}
+	if obj, err = d.client.Create(ctx, obj, opts); err != nil {
+		return nil, err
+	}
+	return fromUnstructuredConfigMap(obj)
+}
+
+func (d dynamicConfigMaps) Update(ctx context.Context, cm *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	obj, err := toUnstructuredConfigMap(cm)
+	if err != nil {
+		return nil, err
+	}
+	if obj, err = d.client.Update(ctx, obj, opts); err != nil {
+		return nil, err
+	}
+	return fromUnstructuredConfigMap(obj)
+}
+
+func toUnstructuredConfigMap(cm *corev1.ConfigMap) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert configmap: %w", err)
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	return obj, nil
+}
+
+func fromUnstructuredConfigMap(obj *unstructured.Unstructured) (*corev1.ConfigMap, error) {
+	var cm corev1.ConfigMap
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &cm); err != nil {
+		return nil, fmt.Errorf("failed to convert configmap: %w", err)
+	}
+	return &cm, nil
+}
@@ -0,0 +1,93 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTombstones(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			tombstones, ok := store.(TombstoneStore)
+			require.True(t, ok, "%T keeps tombstones", store)
+
+			kept, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/kept", Status: v1.Pending}, "kept")
+			require.NoError(t, err)
+			removed, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com/removed", Status: v1.Pending}, "removed")
+			require.NoError(t, err)
+			require.NoError(t, store.DeleteProbe(ctx, removed.Id))
+
+			tombstone, err := tombstones.GetTombstone(ctx, removed.Id)
+			require.NoError(t, err)
+			assert.Equal(t, removed.Id, tombstone.ProbeID)
+			assert.WithinDuration(t, time.Now(), tombstone.DeletedAt, 2*time.Second)
+
+			_, err = tombstones.GetTombstone(ctx, kept.Id)
+			assert.True(t, k8serrors.IsNotFound(err), "live probes have no tombstone")
+			_, err = tombstones.GetTombstone(ctx, uuid.New())
+			assert.True(t, k8serrors.IsNotFound(err), "unknown probes have no tombstone")
+
+			probes, err := store.ListProbes(ctx, "")
+			require.NoError(t, err)
+			assert.Len(t, probes, 1, "tombstones are not listed as probes")
+		})
+	}
+}
+
+func TestTracedProbeStore_GetTombstone(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	probe, err := local.CreateProbe(ctx, createTestProbe(uuid.Nil), "hash")
+	require.NoError(t, err)
+	require.NoError(t, local.DeleteProbeStorage(ctx, probe.Id))
+
+	tombstone, err := NewTracedProbeStore(local, "local").GetTombstone(ctx, probe.Id)
+	require.NoError(t, err)
+	assert.Equal(t, probe.Id, tombstone.ProbeID)
+
+	// Hiding the local store's methods leaves only the ProbeStorage ones.
+	_, err = NewTracedProbeStore(struct{ ProbeStorage }{local}, "plain").GetTombstone(ctx, probe.Id)
+	assert.True(t, k8serrors.IsNotFound(err), "stores without tombstones report none")
+}
+
+func TestConfigMapTombstones(t *testing.T) {
+	ctx := context.Background()
+	expired, invalid := uuid.New(), uuid.New()
+	clientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: tombstoneConfigMapName, Namespace: testNamespace},
+		Data: map[string]string{
+			expired.String(): time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+			invalid.String(): "yesterday",
+		},
+	})
+	client := clientset.CoreV1().ConfigMaps(testNamespace)
+
+	_, err := getConfigMapTombstone(ctx, client, expired, time.Hour)
+	assert.True(t, k8serrors.IsNotFound(err), "expired tombstones are not returned")
+
+	tombstone := newTombstone(uuid.New())
+	require.NoError(t, writeConfigMapTombstone(ctx, client, testNamespace, tombstone, time.Hour))
+	cm, err := client.Get(ctx, tombstoneConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{tombstone.ProbeID.String(): tombstone.DeletedAt.Format(time.RFC3339)}, cm.Data,
+		"writes drop expired and invalid tombstones")
+
+	got, err := getConfigMapTombstone(ctx, client, tombstone.ProbeID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, tombstone, *got)
+}
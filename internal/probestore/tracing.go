@@ -0,0 +1,335 @@
+package probestore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedProbeStore wraps a ProbeStorage and records a span for every call.
+// Backend requests made inside the call, such as Kubernetes API or file
+// operations, appear as its children. It implements every optional
+// interface; use Implements to find out which the wrapped store supports.
+type TracedProbeStore struct {
+	Store   ProbeStorage
+	Backend string
+}
+
+// NewTracedProbeStore wraps store, tagging its spans with the backend name.
+func NewTracedProbeStore(store ProbeStorage, backend string) *TracedProbeStore {
+	return &TracedProbeStore{Store: store, Backend: backend}
+}
+
+// Unwrap returns the wrapped store.
+func (t *TracedProbeStore) Unwrap() ProbeStorage {
+	return t.Store
+}
+
+// provides reports Searcher, which SearchProbes implements for any store.
+func (t *TracedProbeStore) provides(capability any) bool {
+	_, ok := capability.(*Searcher)
+	return ok
+}
+
+func (t *TracedProbeStore) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("probestore.backend", t.Backend))
+	return tracing.Tracer().Start(ctx, "probestore."+op, trace.WithAttributes(attrs...))
+}
+
+// end records err on span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *TracedProbeStore) ListProbes(ctx context.Context, selector string) ([]v1.ProbeObject, error) {
+	ctx, span := t.start(ctx, "ListProbes", attribute.String("probestore.selector", selector))
+	probes, err := t.Store.ListProbes(ctx, selector)
+	span.SetAttributes(attribute.Int("probestore.probe_count", len(probes)))
+	end(span, err)
+	return probes, err
+}
+
+func (t *TracedProbeStore) GetProbe(ctx context.Context, probeID uuid.UUID) (*v1.ProbeObject, error) {
+	ctx, span := t.start(ctx, "GetProbe", attribute.String("probe.id", probeID.String()))
+	probe, err := t.Store.GetProbe(ctx, probeID)
+	end(span, err)
+	return probe, err
+}
+
+func (t *TracedProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	ctx, span := t.start(ctx, "CreateProbe", attribute.String("probe.id", probe.Id.String()))
+	created, err := t.Store.CreateProbe(ctx, probe, urlHashString)
+	end(span, err)
+	return created, err
+}
+
+func (t *TracedProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	ctx, span := t.start(ctx, "UpdateProbe", attribute.String("probe.id", probe.Id.String()))
+	updated, err := t.Store.UpdateProbe(ctx, probe)
+	end(span, err)
+	return updated, err
+}
+
+func (t *TracedProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	ctx, span := t.start(ctx, "DeleteProbe", attribute.String("probe.id", probeID.String()))
+	err := t.Store.DeleteProbe(ctx, probeID)
+	end(span, err)
+	return err
+}
+
+func (t *TracedProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	ctx, span := t.start(ctx, "DeleteProbeStorage", attribute.String("probe.id", probeID.String()))
+	err := t.Store.DeleteProbeStorage(ctx, probeID)
+	end(span, err)
+	return err
+}
+
+func (t *TracedProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	ctx, span := t.start(ctx, "ProbeWithURLHashExists")
+	exists, err := t.Store.ProbeWithURLHashExists(ctx, urlHashString)
+	end(span, err)
+	return exists, err
+}
+
+func (t *TracedProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	ctx, span := t.start(ctx, "GarbageCollectStaleProbes")
+	n, err := t.Store.GarbageCollectStaleProbes(ctx)
+	span.SetAttributes(attribute.Int("probestore.collected_count", n))
+	end(span, err)
+	return n, err
+}
+
+// CheckWritable forwards to the wrapped store if it is a WriteChecker, and
+// reports no error otherwise.
+func (t *TracedProbeStore) CheckWritable(ctx context.Context) error {
+	checker, ok := t.Store.(WriteChecker)
+	if !ok {
+		return nil
+	}
+	ctx, span := t.start(ctx, "CheckWritable")
+	err := checker.CheckWritable(ctx)
+	end(span, err)
+	return err
+}
+
+// CheckHealth forwards to the wrapped store if it is a HealthChecker, and
+// reports no error otherwise.
+func (t *TracedProbeStore) CheckHealth(ctx context.Context) error {
+	checker, ok := t.Store.(HealthChecker)
+	if !ok {
+		return nil
+	}
+	ctx, span := t.start(ctx, "CheckHealth")
+	err := checker.CheckHealth(ctx)
+	end(span, err)
+	return err
+}
+
+// RunURLHashIndex forwards to the wrapped store if it is a URLHashIndexer,
+// and returns right away otherwise.
+func (t *TracedProbeStore) RunURLHashIndex(ctx context.Context) {
+	if indexer, ok := t.Store.(URLHashIndexer); ok {
+		indexer.RunURLHashIndex(ctx)
+	}
+}
+
+// RunPersistence forwards to the wrapped store if it is a Persister, and
+// returns right away otherwise.
+func (t *TracedProbeStore) RunPersistence(ctx context.Context) {
+	if persister, ok := t.Store.(Persister); ok {
+		persister.RunPersistence(ctx)
+	}
+}
+
+// ProbesChanged forwards to the wrapped store if it is a ChangeNotifier, and
+// returns nil, a channel never closed, otherwise.
+func (t *TracedProbeStore) ProbesChanged() <-chan struct{} {
+	if notifier, ok := t.Store.(ChangeNotifier); ok {
+		return notifier.ProbesChanged()
+	}
+	return nil
+}
+
+// URLHashIndexSize forwards to the wrapped store if it is a URLHashIndexer,
+// and returns zero otherwise.
+func (t *TracedProbeStore) URLHashIndexSize() int {
+	if indexer, ok := t.Store.(URLHashIndexer); ok {
+		return indexer.URLHashIndexSize()
+	}
+	return 0
+}
+
+// SearchProbes searches the wrapped store with Search, so stores that are not
+// a Searcher are listed and filtered.
+func (t *TracedProbeStore) SearchProbes(ctx context.Context, selector string, query SearchQuery) ([]v1.ProbeObject, error) {
+	ctx, span := t.start(ctx, "SearchProbes", attribute.String("probestore.selector", selector))
+	probes, err := Search(ctx, t.Store, selector, query)
+	span.SetAttributes(attribute.Int("probestore.probe_count", len(probes)))
+	end(span, err)
+	return probes, err
+}
+
+// GetTombstone forwards to the wrapped store if it is a TombstoneStore, and
+// reports every tombstone as not found otherwise.
+func (t *TracedProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	tombstones, ok := t.Store.(TombstoneStore)
+	if !ok {
+		return nil, tombstoneNotFound(probeID)
+	}
+	ctx, span := t.start(ctx, "GetTombstone", attribute.String("probe.id", probeID.String()))
+	tombstone, err := tombstones.GetTombstone(ctx, probeID)
+	end(span, err)
+	return tombstone, err
+}
+
+// CreateAPIKey forwards to the wrapped store if it is an APIKeyStore, and
+// returns ErrAPIKeysUnsupported otherwise.
+func (t *TracedProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	keys, ok := t.Store.(APIKeyStore)
+	if !ok {
+		return ErrAPIKeysUnsupported
+	}
+	ctx, span := t.start(ctx, "CreateAPIKey", attribute.String("apikey.id", key.ID))
+	err := keys.CreateAPIKey(ctx, key)
+	end(span, err)
+	return err
+}
+
+// ListAPIKeys forwards to the wrapped store if it is an APIKeyStore, and
+// returns ErrAPIKeysUnsupported otherwise.
+func (t *TracedProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	keys, ok := t.Store.(APIKeyStore)
+	if !ok {
+		return nil, ErrAPIKeysUnsupported
+	}
+	ctx, span := t.start(ctx, "ListAPIKeys")
+	list, err := keys.ListAPIKeys(ctx)
+	end(span, err)
+	return list, err
+}
+
+// DeleteAPIKey forwards to the wrapped store if it is an APIKeyStore, and
+// returns ErrAPIKeysUnsupported otherwise.
+func (t *TracedProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	keys, ok := t.Store.(APIKeyStore)
+	if !ok {
+		return ErrAPIKeysUnsupported
+	}
+	ctx, span := t.start(ctx, "DeleteAPIKey", attribute.String("apikey.id", id))
+	err := keys.DeleteAPIKey(ctx, id)
+	end(span, err)
+	return err
+}
+
+// AddOutboxEvent forwards to the wrapped store if it is an OutboxStore, and
+// returns ErrOutboxUnsupported otherwise.
+func (t *TracedProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	outbox, ok := t.Store.(OutboxStore)
+	if !ok {
+		return ErrOutboxUnsupported
+	}
+	ctx, span := t.start(ctx, "AddOutboxEvent", attribute.String("event.id", event.ID))
+	err := outbox.AddOutboxEvent(ctx, event)
+	end(span, err)
+	return err
+}
+
+// ListOutboxEvents forwards to the wrapped store if it is an OutboxStore, and
+// returns ErrOutboxUnsupported otherwise.
+func (t *TracedProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	outbox, ok := t.Store.(OutboxStore)
+	if !ok {
+		return nil, ErrOutboxUnsupported
+	}
+	ctx, span := t.start(ctx, "ListOutboxEvents")
+	events, err := outbox.ListOutboxEvents(ctx)
+	end(span, err)
+	return events, err
+}
+
+// DeleteOutboxEvent forwards to the wrapped store if it is an OutboxStore,
+// and returns ErrOutboxUnsupported otherwise.
+func (t *TracedProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	outbox, ok := t.Store.(OutboxStore)
+	if !ok {
+		return ErrOutboxUnsupported
+	}
+	ctx, span := t.start(ctx, "DeleteOutboxEvent", attribute.String("event.id", id))
+	err := outbox.DeleteOutboxEvent(ctx, id)
+	end(span, err)
+	return err
+}
+
+// PutRecord forwards to the wrapped store if it is a RecordStore, and returns
+// ErrRecordsUnsupported otherwise.
+func (t *TracedProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	records, ok := t.Store.(RecordStore)
+	if !ok {
+		return ErrRecordsUnsupported
+	}
+	ctx, span := t.start(ctx, "PutRecord", attribute.String("record.kind", kind), attribute.String("record.id", record.ID))
+	err := records.PutRecord(ctx, kind, record)
+	end(span, err)
+	return err
+}
+
+// CreateRecord forwards to the wrapped store if it is a RecordStore, and
+// returns ErrRecordsUnsupported otherwise.
+func (t *TracedProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	records, ok := t.Store.(RecordStore)
+	if !ok {
+		return ErrRecordsUnsupported
+	}
+	ctx, span := t.start(ctx, "CreateRecord", attribute.String("record.kind", kind), attribute.String("record.id", record.ID))
+	err := records.CreateRecord(ctx, kind, record)
+	end(span, err)
+	return err
+}
+
+// GetRecord forwards to the wrapped store if it is a RecordStore, and returns
+// ErrRecordsUnsupported otherwise.
+func (t *TracedProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	records, ok := t.Store.(RecordStore)
+	if !ok {
+		return nil, ErrRecordsUnsupported
+	}
+	ctx, span := t.start(ctx, "GetRecord", attribute.String("record.kind", kind), attribute.String("record.id", id))
+	record, err := records.GetRecord(ctx, kind, id)
+	end(span, err)
+	return record, err
+}
+
+// ListRecords forwards to the wrapped store if it is a RecordStore, and
+// returns ErrRecordsUnsupported otherwise.
+func (t *TracedProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	records, ok := t.Store.(RecordStore)
+	if !ok {
+		return nil, ErrRecordsUnsupported
+	}
+	ctx, span := t.start(ctx, "ListRecords", attribute.String("record.kind", kind))
+	list, err := records.ListRecords(ctx, kind)
+	end(span, err)
+	return list, err
+}
+
+// DeleteRecord forwards to the wrapped store if it is a RecordStore, and
+// returns ErrRecordsUnsupported otherwise.
+func (t *TracedProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	records, ok := t.Store.(RecordStore)
+	if !ok {
+		return ErrRecordsUnsupported
+	}
+	ctx, span := t.start(ctx, "DeleteRecord", attribute.String("record.kind", kind), attribute.String("record.id", id))
+	err := records.DeleteRecord(ctx, kind, id)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,135 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+// endedSpan returns the most recently ended span with the given name.
+func endedSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name() == name {
+			return spans[i]
+		}
+	}
+	require.Failf(t, "span not found", "no ended span named %q", name)
+	return nil
+}
+
+func TestTracedProbeStore(t *testing.T) {
+	recorder := newSpanRecorder(t)
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	store := NewTracedProbeStore(local, "local")
+	ctx := context.Background()
+
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending}
+	_, err = store.CreateProbe(ctx, probe, "hash")
+	require.NoError(t, err)
+
+	t.Run("ListProbes records the index listing as a child span", func(t *testing.T) {
+		probes, err := store.ListProbes(ctx, "app=rhobs-synthetics-probe")
+		require.NoError(t, err)
+		require.Len(t, probes, 1)
+
+		spans := recorder.Ended()
+		parent := endedSpan(t, spans, "probestore.ListProbes")
+		assert.Contains(t, parent.Attributes(), attribute.String("probestore.backend", "local"))
+		assert.Contains(t, parent.Attributes(), attribute.String("probestore.selector", "app=rhobs-synthetics-probe"))
+		assert.Contains(t, parent.Attributes(), attribute.Int("probestore.probe_count", 1))
+
+		walk := endedSpan(t, spans, "local.ListIndex")
+		assert.Equal(t, parent.SpanContext().SpanID(), walk.Parent().SpanID())
+		assert.Contains(t, walk.Attributes(), attribute.Int("local.files_read", 1))
+	})
+
+	t.Run("CreateProbe records the file write as a child span", func(t *testing.T) {
+		spans := recorder.Ended()
+		parent := endedSpan(t, spans, "probestore.CreateProbe")
+		write := endedSpan(t, spans, "local.WriteFile")
+		assert.Equal(t, parent.SpanContext().SpanID(), write.Parent().SpanID())
+	})
+
+	t.Run("GetProbe records errors on the span", func(t *testing.T) {
+		_, err := store.GetProbe(ctx, uuid.New())
+		require.Error(t, err)
+
+		span := endedSpan(t, recorder.Ended(), "probestore.GetProbe")
+		assert.Equal(t, codes.Error, span.Status().Code)
+	})
+
+	t.Run("CheckWritable forwards to the wrapped store", func(t *testing.T) {
+		require.NoError(t, store.CheckWritable(ctx))
+		endedSpan(t, recorder.Ended(), "probestore.CheckWritable")
+
+		require.NoError(t, os.Chmod(local.Directory, 0o500))
+		t.Cleanup(func() { os.Chmod(local.Directory, 0o700) }) //nolint:errcheck
+		if os.Geteuid() == 0 {
+			t.Skip("root can write to read-only directories")
+		}
+		assert.ErrorContains(t, store.CheckWritable(ctx), "not writable")
+	})
+
+	t.Run("CheckWritable accepts stores that cannot check", func(t *testing.T) {
+		assert.NoError(t, NewTracedProbeStore(&KubernetesProbeStore{}, "etcd").CheckWritable(ctx))
+	})
+
+	t.Run("CheckHealth forwards to the wrapped store", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "probes")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+		store := NewTracedProbeStore(&LocalProbeStore{Directory: dir}, "local")
+
+		require.NoError(t, store.CheckHealth(ctx))
+		endedSpan(t, recorder.Ended(), "probestore.CheckHealth")
+
+		require.NoError(t, os.Remove(dir))
+		assert.ErrorContains(t, store.CheckHealth(ctx), "not readable")
+	})
+
+	t.Run("SearchProbes filters stores without an index", func(t *testing.T) {
+		store := NewTracedProbeStore(&KubernetesProbeStore{Client: fake.NewClientset(), Namespace: "default"}, "etcd")
+		_, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://a.example.com", Status: v1.Active}, "hash")
+		require.NoError(t, err)
+
+		probes, err := store.SearchProbes(ctx, "", SearchQuery{Text: "a.example"})
+		require.NoError(t, err)
+		assert.Len(t, probes, 1)
+		endedSpan(t, recorder.Ended(), "probestore.SearchProbes")
+	})
+
+	t.Run("CheckHealth lists at most one config map", func(t *testing.T) {
+		client := fake.NewClientset()
+		store := NewTracedProbeStore(&KubernetesProbeStore{Client: client, Namespace: "default"}, "etcd")
+		require.NoError(t, store.CheckHealth(ctx))
+
+		actions := client.Actions()
+		require.Len(t, actions, 1)
+		assert.EqualValues(t, 1, actions[0].(k8stesting.ListActionImpl).ListOptions.Limit)
+	})
+}
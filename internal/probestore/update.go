@@ -0,0 +1,22 @@
+package probestore
+
+import (
+	"reflect"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// withUpdateTimestamp sets the update timestamp of a probe being updated: the
+// current time if the update changes the probe's spec, status or status
+// details, and that of the stored probe otherwise, so heartbeats leave it
+// unchanged. The timestamp given by the caller is ignored.
+func withUpdateTimestamp(probe *v1.ProbeObject, stored v1.ProbeObject) {
+	if probe.Status == stored.Status && reflect.DeepEqual(specOf(*probe), specOf(stored)) &&
+		reflect.DeepEqual(probe.StatusReason, stored.StatusReason) && reflect.DeepEqual(probe.StatusMessage, stored.StatusMessage) {
+		probe.UpdateTimestamp = stored.UpdateTimestamp
+		return
+	}
+	now := clock.Stamp()
+	probe.UpdateTimestamp = &now
+}
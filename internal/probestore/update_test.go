@@ -0,0 +1,46 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeUpdateTimestamp(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			requested := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Pending, UpdateTimestamp: &requested}, "hash")
+			require.NoError(t, err)
+			require.NotNil(t, created.UpdateTimestamp)
+			assert.True(t, created.CreationTimestamp.Equal(*created.UpdateTimestamp), "new probes were last updated when created")
+
+			stored, err := store.GetProbe(ctx, created.Id)
+			require.NoError(t, err)
+			require.NotNil(t, stored.UpdateTimestamp)
+			assert.True(t, created.UpdateTimestamp.Equal(*stored.UpdateTimestamp))
+
+			stored.Labels = &v1.LabelsSchema{lastReconciledKey: "20250101T000000Z"}
+			stored.UpdateTimestamp = &requested
+			heartbeat, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			require.NotNil(t, heartbeat.UpdateTimestamp)
+			assert.True(t, created.UpdateTimestamp.Equal(*heartbeat.UpdateTimestamp), "heartbeats keep the update time")
+
+			heartbeat.Status = v1.Active
+			heartbeat.UpdateTimestamp = &requested
+			updated, err := store.UpdateProbe(ctx, *heartbeat)
+			require.NoError(t, err)
+			require.NotNil(t, updated.UpdateTimestamp)
+			assert.WithinDuration(t, time.Now(), *updated.UpdateTimestamp, 2*time.Second, "status changes update it, ignoring the caller's timestamp")
+		})
+	}
+}
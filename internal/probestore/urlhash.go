@@ -0,0 +1,84 @@
+package probestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// maxLabelValueLength is the longest value a Kubernetes label may have.
+const maxLabelValueLength = 63
+
+// URLHash returns the full URL hash of a probe: the hex SHA-256 of its
+// static URL.
+func URLHash(staticURL string) string {
+	sum := sha256.Sum256([]byte(staticURL))
+	return hex.EncodeToString(sum[:])
+}
+
+// URLHashLabel returns the value of the static-url-hash label for a full URL
+// hash: the hash truncated to the length allowed in a label value.
+func URLHashLabel(urlHash string) string {
+	if len(urlHash) > maxLabelValueLength {
+		return urlHash[:maxLabelValueLength]
+	}
+	return urlHash
+}
+
+// withURLHash sets the full URL hash of a probe being updated to that of the
+// stored probe when the caller left it out. The URL of a probe never changes,
+// so neither does its hash once recorded.
+func withURLHash(probe *v1.ProbeObject, stored v1.ProbeObject) {
+	if probe.UrlHash == nil {
+		probe.UrlHash = stored.UrlHash
+	}
+}
+
+// BackfillURLHashes records the full URL hash of probes stored before it was
+// kept alongside the truncated label, returning how many probes were updated.
+// Each probe is updated only if it is unchanged since it was listed, so
+// replicas starting together don't overwrite each other or concurrent
+// updates; probes that fail to update are logged and picked up on the next
+// run. Probes whose label doesn't match their URL are left alone, as their
+// label would no longer be the prefix of the recorded hash.
+func BackfillURLHashes(ctx context.Context, store ProbeStorage) (int, error) {
+	probes, err := store.ListProbes(ctx, fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list probes: %w", err)
+	}
+
+	migrated := 0
+	for _, probe := range probes {
+		if probe.UrlHash != nil {
+			continue
+		}
+		urlHash := URLHash(probe.StaticUrl)
+		if probe.Labels != nil {
+			if label, ok := (*probe.Labels)[probeURLHashLabelKey]; ok && label != URLHashLabel(urlHash) {
+				slog.WarnContext(ctx, "Not backfilling URL hash, the probe's label does not match its URL", "probe_id", probe.Id, "url_hash", label)
+				continue
+			}
+		}
+		probe.UrlHash = &urlHash
+
+		probeCtx := ctx
+		if probe.ResourceVersion != nil {
+			probeCtx = WithResourceVersion(ctx, *probe.ResourceVersion)
+		}
+		if _, err := store.UpdateProbe(probeCtx, probe); err != nil {
+			if k8serrors.IsConflict(err) || k8serrors.IsNotFound(err) {
+				slog.DebugContext(ctx, "Skipped backfilling URL hash of a probe changed meanwhile", "probe_id", probe.Id, "error", err)
+			} else {
+				slog.WarnContext(ctx, "Error backfilling URL hash", "probe_id", probe.Id, "error", err)
+			}
+			continue
+		}
+		migrated++
+	}
+	return migrated, nil
+}
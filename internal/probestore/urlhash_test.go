@@ -0,0 +1,101 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLHash(t *testing.T) {
+	// echo -n https://example.com | sha256sum
+	urlHash := URLHash("https://example.com")
+	assert.Equal(t, "100680ad546ce6a577f42f52df33b4cfdca756859e664b8d7de329b150d09ce9", urlHash)
+	assert.Equal(t, "100680ad546ce6a577f42f52df33b4cfdca756859e664b8d7de329b150d09ce", URLHashLabel(urlHash))
+	assert.Equal(t, "short", URLHashLabel("short"))
+}
+
+func TestBackfillURLHashes(t *testing.T) {
+	for name, newStore := range testStores() {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+
+			create := func(staticURL string, status v1.StatusSchema, urlHash *string) *v1.ProbeObject {
+				created, err := store.CreateProbe(ctx, v1.ProbeObject{
+					Id:        uuid.New(),
+					StaticUrl: staticURL,
+					Labels:    &v1.LabelsSchema{"env": "prod"},
+					Status:    status,
+					UrlHash:   urlHash,
+				}, URLHashLabel(URLHash(staticURL)))
+				require.NoError(t, err)
+				return created
+			}
+			legacy := create("https://example.com", v1.Active, nil)
+			terminating := create("https://terminating.example.com", v1.Pending, nil)
+			terminating.Status = v1.Terminating
+			terminating, err := store.UpdateProbe(ctx, *terminating)
+			require.NoError(t, err)
+			recorded := "0000000000000000000000000000000000000000000000000000000000000000"
+			current := create("https://current.example.com", v1.Active, &recorded)
+
+			migrated, err := BackfillURLHashes(ctx, store)
+			require.NoError(t, err)
+			assert.Equal(t, 2, migrated)
+
+			for _, tc := range []struct {
+				probe    *v1.ProbeObject
+				expected string
+			}{
+				{probe: legacy, expected: URLHash("https://example.com")},
+				{probe: terminating, expected: URLHash("https://terminating.example.com")},
+				{probe: current, expected: recorded},
+			} {
+				stored, err := store.GetProbe(ctx, tc.probe.Id)
+				require.NoError(t, err)
+				require.NotNil(t, stored.UrlHash, tc.probe.StaticUrl)
+				assert.Equal(t, tc.expected, *stored.UrlHash, tc.probe.StaticUrl)
+				assert.Equal(t, tc.probe.Generation, stored.Generation, "the backfill is not a configuration change")
+				assert.Equal(t, tc.probe.Status, stored.Status)
+				assert.Equal(t, tc.probe.DeletionTimestamp, stored.DeletionTimestamp)
+			}
+
+			exists, err := store.ProbeWithURLHashExists(ctx, URLHashLabel(URLHash("https://example.com")))
+			require.NoError(t, err)
+			assert.True(t, exists, "the truncated label still selects the probe")
+
+			// Updates that leave the hash out keep it.
+			stored, err := store.GetProbe(ctx, legacy.Id)
+			require.NoError(t, err)
+			stored.UrlHash = nil
+			updated, err := store.UpdateProbe(ctx, *stored)
+			require.NoError(t, err)
+			require.NotNil(t, updated.UrlHash)
+			assert.Equal(t, URLHash("https://example.com"), *updated.UrlHash)
+
+			migrated, err = BackfillURLHashes(ctx, store)
+			require.NoError(t, err)
+			assert.Zero(t, migrated, "running again changes nothing")
+		})
+	}
+}
+
+func TestBackfillURLHashes_MismatchedLabel(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	created, err := store.CreateProbe(ctx, v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}, "other-hash")
+	require.NoError(t, err)
+
+	migrated, err := BackfillURLHashes(ctx, store)
+	require.NoError(t, err)
+	assert.Zero(t, migrated)
+	stored, err := store.GetProbe(ctx, created.Id)
+	require.NoError(t, err)
+	assert.Nil(t, stored.UrlHash)
+}
@@ -0,0 +1,588 @@
+package probestore
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DefaultURLHashResync is how often an IndexedProbeStore lists every
+	// probe to rebuild its index when Resync is not set.
+	DefaultURLHashResync = 5 * time.Minute
+
+	// urlHashRewatchDelay is the wait before watching again once a watch
+	// ends, so a backend closing every watch is not hammered.
+	urlHashRewatchDelay = time.Second
+
+	// urlHashIndexName labels the metrics of the index.
+	urlHashIndexName = "url_hash"
+	// urlHashIndexProbeBytes and urlHashIndexHashBytes estimate the memory
+	// taken by each indexed probe, its ID in both maps, and by each URL hash,
+	// the hash and the set of its probes, with the overhead of the maps.
+	urlHashIndexProbeBytes = 64
+	urlHashIndexHashBytes  = 160
+)
+
+// URLHashIndexer is implemented by stores keeping an in-process index of the
+// URL hashes of live probes. The index is only relied on while
+// RunURLHashIndex keeps it current, until ctx is cancelled.
+type URLHashIndexer interface {
+	RunURLHashIndex(ctx context.Context)
+	// URLHashIndexSize returns the number of probes indexed, zero until the
+	// index is built.
+	URLHashIndexSize() int
+}
+
+// urlHashChange is a change to a probe as far as the URL hash index is
+// concerned.
+type urlHashChange struct {
+	id   uuid.UUID
+	hash string
+	// live is false once the probe is terminating, failed or removed.
+	live bool
+}
+
+// urlHashWatcher is implemented by stores that can stream the changes made
+// to their probes, including by other replicas, keeping the index current
+// between resyncs. The channel is closed when the watch ends.
+type urlHashWatcher interface {
+	watchURLHashes(ctx context.Context) (<-chan urlHashChange, error)
+}
+
+type urlHashCheckedKey struct{}
+
+// withURLHashChecked marks a CreateProbe call whose URL hash was already
+// checked, and reserved, against the index.
+func withURLHashChecked(ctx context.Context) context.Context {
+	return context.WithValue(ctx, urlHashCheckedKey{}, true)
+}
+
+// urlHashChecked reports whether withURLHashChecked marked ctx.
+func urlHashChecked(ctx context.Context) bool {
+	checked, _ := ctx.Value(urlHashCheckedKey{}).(bool)
+	return checked
+}
+
+// IndexedProbeStore wraps a ProbeStorage and answers ProbeWithURLHashExists
+// from an in-process index of the URL hashes of live probes, instead of
+// listing (Kubernetes) or reading (local) every probe on each create.
+//
+// RunURLHashIndex builds the index by listing the store and rebuilds it
+// every Resync. In between, it is kept current from the changes made through
+// the wrapper and, for the Kubernetes and CRD stores, from a watch on the
+// probes, which includes those made by other replicas. Indexed probes are
+// read back before a URL is reported as taken, so a probe removed by
+// another replica never blocks a create. Until the index is built, calls
+// are passed on to the store.
+//
+// Lookups, the size of the index and the time since it was last rebuilt are
+// exported as the rhobs_synthetics_api_probestore_index metrics, labelled
+// index="url_hash".
+//
+// It is also a ChangeNotifier, reporting the same changes: those made
+// through it and those its watch sees. The other optional interfaces are
+// forwarded; use Implements to find out which the wrapped store supports.
+type IndexedProbeStore struct {
+	ProbeStorage
+	// Resync is how often the index is rebuilt; zero selects
+	// DefaultURLHashResync.
+	Resync time.Duration
+
+	mu    sync.Mutex
+	ready bool
+	// hashes are the live probes indexed under each URL hash, and probes
+	// the URL hash of each live probe.
+	hashes map[string]map[uuid.UUID]struct{}
+	probes map[uuid.UUID]string
+	// creating are the probes reserved by CreateProbe until the store has
+	// created them.
+	creating map[uuid.UUID]struct{}
+
+	changed changeSignal
+}
+
+// NewIndexedProbeStore wraps store with a URL hash index, which is used once
+// RunURLHashIndex runs.
+func NewIndexedProbeStore(store ProbeStorage) *IndexedProbeStore {
+	return &IndexedProbeStore{ProbeStorage: store}
+}
+
+// urlHashOf returns the static-url-hash label value of a probe: its label if
+// the store returned it, and the hash of its URL otherwise.
+func urlHashOf(probe v1.ProbeObject) string {
+	if probe.Labels != nil {
+		if hash, ok := (*probe.Labels)[probeURLHashLabelKey]; ok {
+			return hash
+		}
+	}
+	return URLHashLabel(URLHash(probe.StaticUrl))
+}
+
+// isLive reports whether a probe with the status blocks the creation of
+// another probe for its URL.
+func isLive(status v1.StatusSchema) bool {
+	return status != v1.Terminating && status != v1.Failed
+}
+
+// Unwrap returns the wrapped store.
+func (i *IndexedProbeStore) Unwrap() ProbeStorage {
+	return i.ProbeStorage
+}
+
+// provides reports the optional interfaces the index itself implements;
+// the others are forwarded.
+func (i *IndexedProbeStore) provides(capability any) bool {
+	switch capability.(type) {
+	case *URLHashIndexer, *ChangeNotifier, *Searcher:
+		return true
+	}
+	return false
+}
+
+// ProbesChanged implements ChangeNotifier.
+func (i *IndexedProbeStore) ProbesChanged() <-chan struct{} {
+	return i.changed.wait()
+}
+
+// URLHashIndexSize returns the number of probes indexed.
+func (i *IndexedProbeStore) URLHashIndexSize() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.probes)
+}
+
+// RunURLHashIndex builds the index and keeps it current until ctx is
+// cancelled. Calls are passed on to the store again once it returns.
+func (i *IndexedProbeStore) RunURLHashIndex(ctx context.Context) {
+	resync := cmp.Or(i.Resync, DefaultURLHashResync)
+	slog.InfoContext(ctx, "Starting URL hash index", "resync", resync)
+	defer i.reset()
+
+	ticker := time.NewTicker(resync)
+	defer ticker.Stop()
+	var changes <-chan urlHashChange
+	for {
+		if changes == nil {
+			changes = i.watch(ctx)
+		}
+		// The watch starts first, so changes made while listing are
+		// applied on top of the list rather than lost.
+		if err := i.sync(ctx); err != nil {
+			slog.WarnContext(ctx, "Failed to build the URL hash index, checking the store instead", "error", err)
+		}
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				break wait
+			case change, ok := <-changes:
+				if ok {
+					i.apply(change)
+					i.changed.notify()
+					continue
+				}
+				changes = nil
+				slog.DebugContext(ctx, "URL hash watch ended, watching again")
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(urlHashRewatchDelay):
+				}
+				break wait
+			}
+		}
+	}
+}
+
+// watch starts watching the store's probes, returning nil if it cannot.
+func (i *IndexedProbeStore) watch(ctx context.Context) <-chan urlHashChange {
+	watcher, ok := i.ProbeStorage.(urlHashWatcher)
+	if !ok {
+		return nil
+	}
+	changes, err := watcher.watchURLHashes(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to watch probes for the URL hash index, relying on resyncs", "error", err)
+		return nil
+	}
+	return changes
+}
+
+// sync rebuilds the index from a list of every probe. The index is not
+// relied on while the list fails.
+func (i *IndexedProbeStore) sync(ctx context.Context) error {
+	probes, err := i.ProbeStorage.ListProbes(ctx, fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue))
+	if err != nil {
+		i.reset()
+		return fmt.Errorf("failed to list probes: %w", err)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	reserved := make(map[uuid.UUID]string, len(i.creating))
+	for id := range i.creating {
+		reserved[id] = i.probes[id]
+	}
+	i.hashes = make(map[string]map[uuid.UUID]struct{})
+	i.probes = make(map[uuid.UUID]string)
+	for _, probe := range probes {
+		i.set(urlHashChange{id: probe.Id, hash: urlHashOf(probe), live: isLive(probe.Status)})
+	}
+	for id, hash := range reserved {
+		if _, listed := i.probes[id]; !listed && hash != "" {
+			i.set(urlHashChange{id: id, hash: hash, live: true})
+		}
+	}
+	i.ready = true
+	i.report()
+	metrics.RecordProbestoreIndexSync(urlHashIndexName, time.Now())
+	slog.DebugContext(ctx, "Built URL hash index", "live_probes", len(i.probes))
+	return nil
+}
+
+// report exports the size of the index; i.mu must be held.
+func (i *IndexedProbeStore) report() {
+	metrics.SetProbestoreIndexSize(urlHashIndexName, len(i.probes), len(i.probes)*urlHashIndexProbeBytes+len(i.hashes)*urlHashIndexHashBytes)
+}
+
+// reset stops relying on the index.
+func (i *IndexedProbeStore) reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.ready, i.hashes, i.probes = false, nil, nil
+	clear(i.creating)
+	metrics.ResetProbestoreIndex(urlHashIndexName)
+}
+
+// apply records a change in the index, if it is built.
+func (i *IndexedProbeStore) apply(change urlHashChange) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.ready {
+		i.set(change)
+		i.report()
+	}
+}
+
+// set records a change; i.mu must be held.
+func (i *IndexedProbeStore) set(change urlHashChange) {
+	if hash, ok := i.probes[change.id]; ok {
+		delete(i.hashes[hash], change.id)
+		if len(i.hashes[hash]) == 0 {
+			delete(i.hashes, hash)
+		}
+		delete(i.probes, change.id)
+	}
+	if !change.live {
+		return
+	}
+	if i.hashes[change.hash] == nil {
+		i.hashes[change.hash] = make(map[uuid.UUID]struct{})
+	}
+	i.hashes[change.hash][change.id] = struct{}{}
+	i.probes[change.id] = change.hash
+}
+
+// lookup returns the live probes indexed under the URL hash, and whether
+// one of them is being created. ok is false if the index is not built.
+func (i *IndexedProbeStore) lookup(hash string) (ids []uuid.UUID, creating, ok bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for id := range i.hashes[hash] {
+		if _, ok := i.creating[id]; ok {
+			creating = true
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, creating, i.ready
+}
+
+// reserve indexes a probe about to be created, unless another live probe is
+// indexed under its URL hash.
+func (i *IndexedProbeStore) reserve(id uuid.UUID, hash string) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.ready || len(i.hashes[hash]) > 0 {
+		return false
+	}
+	if i.creating == nil {
+		i.creating = make(map[uuid.UUID]struct{})
+	}
+	i.creating[id] = struct{}{}
+	i.set(urlHashChange{id: id, hash: hash, live: true})
+	i.report()
+	return true
+}
+
+// release ends the reservation of a probe, recording whether it was created.
+func (i *IndexedProbeStore) release(change urlHashChange) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.creating, change.id)
+	if i.ready {
+		i.set(change)
+		i.report()
+	}
+}
+
+// ProbeWithURLHashExists looks the URL hash up in the index once it is
+// built. The probes found are read back, and dropped from the index if they
+// are gone or no longer live.
+func (i *IndexedProbeStore) ProbeWithURLHashExists(ctx context.Context, urlHashString string) (bool, error) {
+	ids, creating, ok := i.lookup(urlHashString)
+	metrics.RecordProbestoreIndexLookup(urlHashIndexName, ok)
+	if !ok {
+		return i.ProbeStorage.ProbeWithURLHashExists(ctx, urlHashString)
+	}
+	if creating {
+		return true, nil
+	}
+	for _, id := range ids {
+		probe, err := i.ProbeStorage.GetProbe(ctx, id)
+		if k8serrors.IsNotFound(err) {
+			i.apply(urlHashChange{id: id})
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read indexed probe %s: %w", id, err)
+		}
+		if isLive(probe.Status) && urlHashOf(*probe) == urlHashString {
+			return true, nil
+		}
+		i.apply(urlHashChange{id: id, hash: urlHashOf(*probe), live: isLive(probe.Status)})
+	}
+	return false, nil
+}
+
+// CreateProbe reserves the URL hash in the index before creating the probe,
+// so concurrent creates for the same URL on this replica cannot both
+// succeed, and the local store need not read every probe to check it again.
+func (i *IndexedProbeStore) CreateProbe(ctx context.Context, probe v1.ProbeObject, urlHashString string) (*v1.ProbeObject, error) {
+	if _, _, ok := i.lookup(urlHashString); !ok {
+		metrics.RecordProbestoreIndexLookup(urlHashIndexName, false)
+		created, err := i.ProbeStorage.CreateProbe(ctx, probe, urlHashString)
+		if err == nil {
+			i.changed.notify()
+		}
+		return created, err
+	}
+	exists, err := i.ProbeWithURLHashExists(ctx, urlHashString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing probe with URL hash: %w", err)
+	}
+	if exists || !i.reserve(probe.Id, urlHashString) {
+		return nil, k8serrors.NewAlreadyExists(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, "probe with same static_url")
+	}
+
+	created, err := i.ProbeStorage.CreateProbe(withURLHashChecked(ctx), probe, urlHashString)
+	if err != nil {
+		i.release(urlHashChange{id: probe.Id})
+		return nil, err
+	}
+	i.release(urlHashChange{id: created.Id, hash: urlHashString, live: isLive(created.Status)})
+	i.changed.notify()
+	return created, nil
+}
+
+func (i *IndexedProbeStore) UpdateProbe(ctx context.Context, probe v1.ProbeObject) (*v1.ProbeObject, error) {
+	updated, err := i.ProbeStorage.UpdateProbe(ctx, probe)
+	if err == nil {
+		i.apply(urlHashChange{id: updated.Id, hash: urlHashOf(*updated), live: isLive(updated.Status)})
+		i.changed.notify()
+	}
+	return updated, err
+}
+
+// DeleteProbe drops the probe from the index: whether it is removed or
+// moved to terminating, it no longer blocks its URL.
+func (i *IndexedProbeStore) DeleteProbe(ctx context.Context, probeID uuid.UUID) error {
+	err := i.ProbeStorage.DeleteProbe(ctx, probeID)
+	if err == nil {
+		i.apply(urlHashChange{id: probeID})
+		i.changed.notify()
+	}
+	return err
+}
+
+func (i *IndexedProbeStore) DeleteProbeStorage(ctx context.Context, probeID uuid.UUID) error {
+	err := i.ProbeStorage.DeleteProbeStorage(ctx, probeID)
+	if err == nil {
+		i.apply(urlHashChange{id: probeID})
+		i.changed.notify()
+	}
+	return err
+}
+
+// GarbageCollectStaleProbes rebuilds the index after probes were collected,
+// as the store does not report which.
+func (i *IndexedProbeStore) GarbageCollectStaleProbes(ctx context.Context) (int, error) {
+	n, err := i.ProbeStorage.GarbageCollectStaleProbes(ctx)
+	if n > 0 {
+		i.changed.notify()
+	}
+	if n > 0 && i.built() {
+		if err := i.sync(ctx); err != nil {
+			slog.WarnContext(ctx, "Failed to rebuild the URL hash index after garbage collection", "error", err)
+		}
+	}
+	return n, err
+}
+
+func (i *IndexedProbeStore) built() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.ready
+}
+
+// CheckWritable forwards to the wrapped store if it is a WriteChecker, and
+// reports no error otherwise.
+func (i *IndexedProbeStore) CheckWritable(ctx context.Context) error {
+	if checker, ok := i.ProbeStorage.(WriteChecker); ok {
+		return checker.CheckWritable(ctx)
+	}
+	return nil
+}
+
+// CheckHealth forwards to the wrapped store if it is a HealthChecker, and
+// reports no error otherwise.
+func (i *IndexedProbeStore) CheckHealth(ctx context.Context) error {
+	if checker, ok := i.ProbeStorage.(HealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// RunPersistence forwards to the wrapped store if it is a Persister, and
+// returns right away otherwise.
+func (i *IndexedProbeStore) RunPersistence(ctx context.Context) {
+	if persister, ok := i.ProbeStorage.(Persister); ok {
+		persister.RunPersistence(ctx)
+	}
+}
+
+// SearchProbes searches the wrapped store with Search.
+func (i *IndexedProbeStore) SearchProbes(ctx context.Context, selector string, query SearchQuery) ([]v1.ProbeObject, error) {
+	return Search(ctx, i.ProbeStorage, selector, query)
+}
+
+// GetTombstone forwards to the wrapped store if it is a TombstoneStore, and
+// reports every tombstone as not found otherwise.
+func (i *IndexedProbeStore) GetTombstone(ctx context.Context, probeID uuid.UUID) (*Tombstone, error) {
+	if tombstones, ok := i.ProbeStorage.(TombstoneStore); ok {
+		return tombstones.GetTombstone(ctx, probeID)
+	}
+	return nil, tombstoneNotFound(probeID)
+}
+
+// CreateAPIKey forwards to the wrapped store if it is an APIKeyStore, and
+// returns ErrAPIKeysUnsupported otherwise.
+func (i *IndexedProbeStore) CreateAPIKey(ctx context.Context, key APIKey) error {
+	if keys, ok := i.ProbeStorage.(APIKeyStore); ok {
+		return keys.CreateAPIKey(ctx, key)
+	}
+	return ErrAPIKeysUnsupported
+}
+
+// ListAPIKeys forwards to the wrapped store if it is an APIKeyStore, and
+// returns ErrAPIKeysUnsupported otherwise.
+func (i *IndexedProbeStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	if keys, ok := i.ProbeStorage.(APIKeyStore); ok {
+		return keys.ListAPIKeys(ctx)
+	}
+	return nil, ErrAPIKeysUnsupported
+}
+
+// DeleteAPIKey forwards to the wrapped store if it is an APIKeyStore, and
+// returns ErrAPIKeysUnsupported otherwise.
+func (i *IndexedProbeStore) DeleteAPIKey(ctx context.Context, id string) error {
+	if keys, ok := i.ProbeStorage.(APIKeyStore); ok {
+		return keys.DeleteAPIKey(ctx, id)
+	}
+	return ErrAPIKeysUnsupported
+}
+
+// AddOutboxEvent forwards to the wrapped store if it is an OutboxStore, and
+// returns ErrOutboxUnsupported otherwise.
+func (i *IndexedProbeStore) AddOutboxEvent(ctx context.Context, event OutboxEvent) error {
+	if outbox, ok := i.ProbeStorage.(OutboxStore); ok {
+		return outbox.AddOutboxEvent(ctx, event)
+	}
+	return ErrOutboxUnsupported
+}
+
+// ListOutboxEvents forwards to the wrapped store if it is an OutboxStore, and
+// returns ErrOutboxUnsupported otherwise.
+func (i *IndexedProbeStore) ListOutboxEvents(ctx context.Context) ([]OutboxEvent, error) {
+	if outbox, ok := i.ProbeStorage.(OutboxStore); ok {
+		return outbox.ListOutboxEvents(ctx)
+	}
+	return nil, ErrOutboxUnsupported
+}
+
+// DeleteOutboxEvent forwards to the wrapped store if it is an OutboxStore,
+// and returns ErrOutboxUnsupported otherwise.
+func (i *IndexedProbeStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	if outbox, ok := i.ProbeStorage.(OutboxStore); ok {
+		return outbox.DeleteOutboxEvent(ctx, id)
+	}
+	return ErrOutboxUnsupported
+}
+
+// PutRecord forwards to the wrapped store if it is a RecordStore, and returns
+// ErrRecordsUnsupported otherwise.
+func (i *IndexedProbeStore) PutRecord(ctx context.Context, kind string, record Record) error {
+	if records, ok := i.ProbeStorage.(RecordStore); ok {
+		return records.PutRecord(ctx, kind, record)
+	}
+	return ErrRecordsUnsupported
+}
+
+// CreateRecord forwards to the wrapped store if it is a RecordStore, and
+// returns ErrRecordsUnsupported otherwise.
+func (i *IndexedProbeStore) CreateRecord(ctx context.Context, kind string, record Record) error {
+	if records, ok := i.ProbeStorage.(RecordStore); ok {
+		return records.CreateRecord(ctx, kind, record)
+	}
+	return ErrRecordsUnsupported
+}
+
+// GetRecord forwards to the wrapped store if it is a RecordStore, and returns
+// ErrRecordsUnsupported otherwise.
+func (i *IndexedProbeStore) GetRecord(ctx context.Context, kind, id string) (*Record, error) {
+	if records, ok := i.ProbeStorage.(RecordStore); ok {
+		return records.GetRecord(ctx, kind, id)
+	}
+	return nil, ErrRecordsUnsupported
+}
+
+// ListRecords forwards to the wrapped store if it is a RecordStore, and
+// returns ErrRecordsUnsupported otherwise.
+func (i *IndexedProbeStore) ListRecords(ctx context.Context, kind string) ([]Record, error) {
+	if records, ok := i.ProbeStorage.(RecordStore); ok {
+		return records.ListRecords(ctx, kind)
+	}
+	return nil, ErrRecordsUnsupported
+}
+
+// DeleteRecord forwards to the wrapped store if it is a RecordStore, and
+// returns ErrRecordsUnsupported otherwise.
+func (i *IndexedProbeStore) DeleteRecord(ctx context.Context, kind, id string) error {
+	if records, ok := i.ProbeStorage.(RecordStore); ok {
+		return records.DeleteRecord(ctx, kind, id)
+	}
+	return ErrRecordsUnsupported
+}
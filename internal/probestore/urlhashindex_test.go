@@ -0,0 +1,151 @@
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// runIndex runs the index of store until the test ends, once it is built.
+func runIndex(t *testing.T, store *IndexedProbeStore) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.RunURLHashIndex(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.Eventually(t, store.built, 5*time.Second, time.Millisecond)
+}
+
+func probeFor(url string) (v1.ProbeObject, string) {
+	return v1.ProbeObject{Id: uuid.New(), StaticUrl: url, Status: v1.Pending}, URLHashLabel(URLHash(url))
+}
+
+func TestIndexedProbeStore(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	store := NewIndexedProbeStore(local)
+
+	existing, existingHash := probeFor("https://existing.example.com")
+	_, err = store.CreateProbe(ctx, existing, existingHash)
+	require.NoError(t, err)
+	exists, err := store.ProbeWithURLHashExists(ctx, existingHash)
+	require.NoError(t, err)
+	assert.True(t, exists, "the store is checked until the index is built")
+
+	store.Resync = 50 * time.Millisecond
+	runIndex(t, store)
+	ids, _, _ := store.lookup(existingHash)
+	assert.Equal(t, []uuid.UUID{existing.Id}, ids, "probes stored before are indexed")
+
+	probe, hash := probeFor("https://new.example.com")
+	exists, err = store.ProbeWithURLHashExists(ctx, hash)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = store.CreateProbe(ctx, probe, hash)
+	require.NoError(t, err)
+	exists, err = store.ProbeWithURLHashExists(ctx, hash)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	duplicate, _ := probeFor("https://new.example.com")
+	_, err = store.CreateProbe(ctx, duplicate, hash)
+	assert.True(t, k8serrors.IsAlreadyExists(err), "got %v", err)
+
+	t.Run("terminating probes free their URL", func(t *testing.T) {
+		probe.Status = v1.Terminating
+		_, err := store.UpdateProbe(ctx, probe)
+		require.NoError(t, err)
+		exists, err := store.ProbeWithURLHashExists(ctx, hash)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("probes removed behind the index do not block their URL", func(t *testing.T) {
+		require.NoError(t, local.DeleteProbeStorage(ctx, existing.Id))
+		exists, err := store.ProbeWithURLHashExists(ctx, existingHash)
+		require.NoError(t, err)
+		assert.False(t, exists)
+		ids, _, _ := store.lookup(existingHash)
+		assert.Empty(t, ids, "the probe is dropped from the index")
+	})
+
+	t.Run("resyncs pick up probes created behind the index", func(t *testing.T) {
+		other, otherHash := probeFor("https://other.example.com")
+		_, err := local.CreateProbe(ctx, other, otherHash)
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			ids, _, _ := store.lookup(otherHash)
+			return len(ids) == 1
+		}, 5*time.Second, time.Millisecond)
+	})
+}
+
+func TestIndexedProbeStore_ConcurrentCreates(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	store := NewIndexedProbeStore(local)
+	runIndex(t, store)
+
+	_, hash := probeFor("https://example.com")
+	errs := make(chan error, 10)
+	for range cap(errs) {
+		go func() {
+			probe, _ := probeFor("https://example.com")
+			_, err := store.CreateProbe(ctx, probe, hash)
+			errs <- err
+		}()
+	}
+	created := 0
+	for range cap(errs) {
+		if err := <-errs; err == nil {
+			created++
+		} else {
+			assert.True(t, k8serrors.IsAlreadyExists(err), "got %v", err)
+		}
+	}
+	assert.Equal(t, 1, created)
+}
+
+func TestIndexedProbeStore_ProbesChanged(t *testing.T) {
+	ctx := context.Background()
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	store := NewIndexedProbeStore(local)
+
+	changed := store.ProbesChanged()
+	assert.Equal(t, changed, store.ProbesChanged(), "waiters share the channel until the next change")
+	probe, hash := probeFor("https://changed.example.com")
+	_, err = store.CreateProbe(ctx, probe, hash)
+	require.NoError(t, err)
+	assert.True(t, isClosed(changed), "a create is a change")
+
+	changed = store.ProbesChanged()
+	_, err = store.GetProbe(ctx, probe.Id)
+	require.NoError(t, err)
+	assert.False(t, isClosed(changed), "a read is not a change")
+	require.NoError(t, store.DeleteProbeStorage(ctx, probe.Id))
+	assert.True(t, isClosed(changed), "a delete is a change")
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,104 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// probeListOptions selects the objects holding probes.
+func probeListOptions() metav1.ListOptions {
+	return metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", baseAppLabelKey, baseAppLabelValue)}
+}
+
+// watchURLHashes watches the probe ConfigMaps of every probe namespace for
+// the URL hash index.
+func (k *KubernetesProbeStore) watchURLHashes(ctx context.Context) (<-chan urlHashChange, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	probeID := func(name string) (uuid.UUID, error) {
+		return uuid.Parse(strings.TrimPrefix(name, fmt.Sprintf(probeConfigMapNameFormat, "")))
+	}
+	var watches []<-chan urlHashChange
+	for _, namespace := range k.probeNamespaces() {
+		w, err := k.Client.CoreV1().ConfigMaps(namespace).Watch(ctx, probeListOptions())
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to watch probe configmaps in namespace %s: %w", namespace, err)
+		}
+		watches = append(watches, urlHashChanges(ctx, w, probeID))
+	}
+	return mergeURLHashChanges(ctx, cancel, watches), nil
+}
+
+// watchURLHashes watches the Probe resources for the URL hash index.
+func (c *CRDProbeStore) watchURLHashes(ctx context.Context) (<-chan urlHashChange, error) {
+	w, err := c.resource().Watch(ctx, probeListOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch probe resources: %w", err)
+	}
+	return urlHashChanges(ctx, w, uuid.Parse), nil
+}
+
+// urlHashChanges turns the events of a watch on objects holding probes into
+// index changes, reading the probe ID from the object name and its URL hash
+// and status from the labels. The channel is closed when the watch ends or
+// fails.
+func urlHashChanges(ctx context.Context, w watch.Interface, probeID func(name string) (uuid.UUID, error)) <-chan urlHashChange {
+	changes := make(chan urlHashChange)
+	go func() {
+		defer close(changes)
+		defer w.Stop()
+		for {
+			var event watch.Event
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+				event = e
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+			case watch.Error:
+				return
+			default:
+				continue
+			}
+			obj, err := meta.Accessor(event.Object)
+			if err != nil {
+				continue
+			}
+			id, err := probeID(obj.GetName())
+			if err != nil {
+				continue
+			}
+			labels := obj.GetLabels()
+			change := urlHashChange{
+				id:   id,
+				hash: labels[probeURLHashLabelKey],
+				live: event.Type != watch.Deleted && isLive(v1.StatusSchema(labels[probeStatusLabelKey])),
+			}
+			if change.live && change.hash == "" {
+				// Left for the next resync, which hashes the probe's URL.
+				continue
+			}
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes
+}
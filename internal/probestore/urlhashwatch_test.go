@@ -0,0 +1,50 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIndexedProbeStore_Watch(t *testing.T) {
+	kubernetesStore, err := NewKubernetesProbeStore(context.Background(), fake.NewSimpleClientset(), testNamespace)
+	require.NoError(t, err)
+	shardedStore, err := NewShardedKubernetesProbeStore(context.Background(), fake.NewSimpleClientset(), testNamespace, testShardNamespaces)
+	require.NoError(t, err)
+	stores := map[string]ProbeStorage{
+		"kubernetes":         kubernetesStore,
+		"sharded kubernetes": shardedStore,
+		"crd":                newTestCRDProbeStore(),
+	}
+
+	for name, backend := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := NewIndexedProbeStore(backend)
+			store.Resync = time.Hour
+			runIndex(t, store)
+
+			// Changes made behind the index, as by another replica, arrive
+			// through the watch.
+			probe, hash := probeFor("https://example.com")
+			_, err := backend.CreateProbe(ctx, probe, hash)
+			require.NoError(t, err)
+			assert.Eventually(t, func() bool {
+				ids, _, _ := store.lookup(hash)
+				return len(ids) == 1
+			}, 5*time.Second, time.Millisecond)
+
+			require.NoError(t, backend.DeleteProbeStorage(ctx, probe.Id))
+			assert.Eventually(t, func() bool {
+				ids, _, _ := store.lookup(hash)
+				return len(ids) == 0
+			}, 5*time.Second, time.Millisecond)
+		})
+	}
+}
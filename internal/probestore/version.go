@@ -0,0 +1,66 @@
+package probestore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type resourceVersionKey struct{}
+
+// WithResourceVersion returns a context under which UpdateProbe, DeleteProbe
+// and DeleteProbeStorage only change a probe whose current resource version
+// is version, and fail with a Conflict error otherwise. It backs If-Match on
+// the API.
+func WithResourceVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, resourceVersionKey{}, version)
+}
+
+// expectedResourceVersion returns the version set by WithResourceVersion.
+func expectedResourceVersion(ctx context.Context) (string, bool) {
+	version, ok := ctx.Value(resourceVersionKey{}).(string)
+	return version, ok
+}
+
+// checkResourceVersion returns a Conflict error if ctx expects a resource
+// version other than current.
+func checkResourceVersion(ctx context.Context, probeID uuid.UUID, current string) error {
+	want, ok := expectedResourceVersion(ctx)
+	if !ok || want == current {
+		return nil
+	}
+	return k8serrors.NewConflict(schema.GroupResource{Group: "rhobs-synthetics", Resource: "probes"}, probeID.String(),
+		fmt.Errorf("the probe is at resource version %s, not %s", current, want))
+}
+
+// withResourceVersion sets the probe's resource version, leaving it unset
+// when the backend did not report one.
+func withResourceVersion(probe *v1.ProbeObject, version string) *v1.ProbeObject {
+	if version != "" {
+		probe.ResourceVersion = &version
+	}
+	return probe
+}
+
+// deleteOptions passes the resource version expected by ctx, if any, as a
+// precondition, so the API server refuses to delete any other version.
+func deleteOptions(ctx context.Context) metav1.DeleteOptions {
+	var opts metav1.DeleteOptions
+	if version, ok := expectedResourceVersion(ctx); ok {
+		opts.Preconditions = &metav1.Preconditions{ResourceVersion: &version}
+	}
+	return opts
+}
+
+// resourceVersionOf returns the probe's resource version, or "" if unset.
+func resourceVersionOf(probe *v1.ProbeObject) string {
+	if probe.ResourceVersion == nil {
+		return ""
+	}
+	return *probe.ResourceVersion
+}
@@ -0,0 +1,110 @@
+//go:build !nokube
+
+package probestore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLocalProbeStore_ResourceVersion(t *testing.T) {
+	store, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	probe := createTestProbe(uuid.New())
+	probe.Status = v1.Active
+	created, err := store.CreateProbe(ctx, probe, "hash")
+	require.NoError(t, err)
+	require.NotNil(t, created.ResourceVersion)
+	v1Version := *created.ResourceVersion
+
+	t.Run("reads report the version of the file", func(t *testing.T) {
+		got, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1Version, resourceVersionOf(got))
+
+		listed, err := store.ListProbes(ctx, "")
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, v1Version, resourceVersionOf(&listed[0]))
+
+		data, err := os.ReadFile(filepath.Join(store.Directory, probe.Id.String()+".json"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "resource_version", "the version is derived, not stored")
+	})
+
+	t.Run("updates change the version", func(t *testing.T) {
+		(*probe.Labels)["team"] = "sre"
+		updated, err := store.UpdateProbe(WithResourceVersion(ctx, v1Version), probe)
+		require.NoError(t, err)
+		assert.NotEqual(t, v1Version, resourceVersionOf(updated))
+	})
+
+	t.Run("writes against a stale version conflict", func(t *testing.T) {
+		stale := WithResourceVersion(ctx, v1Version)
+
+		_, err := store.UpdateProbe(stale, probe)
+		assert.True(t, k8serrors.IsConflict(err), "got %v", err)
+		assert.True(t, k8serrors.IsConflict(store.DeleteProbe(stale, probe.Id)))
+		assert.True(t, k8serrors.IsConflict(store.DeleteProbeStorage(stale, probe.Id)))
+
+		_, err = store.GetProbe(ctx, probe.Id)
+		assert.NoError(t, err, "the probe is left alone")
+	})
+
+	t.Run("deletes at the current version succeed", func(t *testing.T) {
+		current, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		require.NoError(t, store.DeleteProbe(WithResourceVersion(ctx, resourceVersionOf(current)), probe.Id))
+
+		terminating, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		assert.Equal(t, v1.Terminating, terminating.Status)
+	})
+}
+
+func TestKubernetesProbeStore_ResourceVersion(t *testing.T) {
+	ctx := context.Background()
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "http://example.com/version", Status: v1.Pending}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf(probeConfigMapNameFormat, probe.Id),
+			Namespace:       testNamespace,
+			ResourceVersion: "7",
+			Labels:          map[string]string{baseAppLabelKey: baseAppLabelValue, probeStatusLabelKey: string(v1.Pending)},
+		},
+		Data: map[string]string{"probe-config.json": mustMarshal(t, probe)},
+	}
+	store, err := NewKubernetesProbeStore(ctx, fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}, cm), testNamespace)
+	require.NoError(t, err)
+
+	got, err := store.GetProbe(ctx, probe.Id)
+	require.NoError(t, err)
+	assert.Equal(t, "7", resourceVersionOf(got), "the ConfigMap's resourceVersion is the probe's")
+
+	stale := WithResourceVersion(ctx, "6")
+	_, err = store.UpdateProbe(stale, probe)
+	assert.True(t, k8serrors.IsConflict(err), "got %v", err)
+	assert.True(t, k8serrors.IsConflict(store.DeleteProbe(stale, probe.Id)))
+
+	probe.Status = v1.Active
+	_, err = store.UpdateProbe(WithResourceVersion(ctx, "7"), probe)
+	require.NoError(t, err)
+
+	stored, err := store.Client.CoreV1().ConfigMaps(testNamespace).Get(ctx, cm.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, stored.Data["probe-config.json"], "resource_version")
+}
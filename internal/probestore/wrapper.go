@@ -0,0 +1,46 @@
+package probestore
+
+// Wrapper is implemented by stores that wrap another, such as
+// TracedProbeStore and IndexedProbeStore. A wrapper implements every optional
+// interface, forwarding it to the wrapped store when that implements it too,
+// so callers use Implements rather than a type assertion to find out which of
+// them the store behind it supports.
+type Wrapper interface {
+	ProbeStorage
+	Unwrap() ProbeStorage
+}
+
+// capabilityProvider is implemented by wrappers that provide some optional
+// interfaces themselves, rather than forwarding them. provides is given a nil
+// pointer to the interface asked about.
+type capabilityProvider interface {
+	provides(capability any) bool
+}
+
+// Implements returns store as the optional interface T, such as OutboxStore,
+// if it supports it. Wrappers are looked through: a wrapper supports T when
+// it provides T itself or the store it wraps supports it.
+func Implements[T any](store ProbeStorage) (T, bool) {
+	capability, ok := store.(T)
+	if !ok || !supports[T](store) {
+		var zero T
+		return zero, false
+	}
+	return capability, true
+}
+
+func supports[T any](store ProbeStorage) bool {
+	for {
+		if _, ok := store.(T); !ok {
+			return false
+		}
+		wrapper, ok := store.(Wrapper)
+		if !ok {
+			return true
+		}
+		if provider, ok := store.(capabilityProvider); ok && provider.provides((*T)(nil)) {
+			return true
+		}
+		store = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,47 @@
+package probestore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplements(t *testing.T) {
+	memory := NewTracedProbeStore(NewIndexedProbeStore(NewMemoryProbeStore()), "memory")
+	local, err := NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	tracedLocal := NewTracedProbeStore(NewIndexedProbeStore(local), "local")
+
+	t.Run("wrappers only forward what the wrapped store supports", func(t *testing.T) {
+		_, ok := Implements[WriteChecker](memory)
+		assert.False(t, ok, "the memory store is always writable")
+		_, ok = Implements[HealthChecker](memory)
+		assert.False(t, ok)
+		checker, ok := Implements[WriteChecker](tracedLocal)
+		require.True(t, ok)
+		assert.Same(t, tracedLocal, checker, "calls still go through the wrappers")
+		_, ok = Implements[OutboxStore](memory)
+		assert.True(t, ok)
+		_, ok = Implements[RecordStore](tracedLocal)
+		assert.True(t, ok)
+	})
+
+	t.Run("wrappers provide their own interfaces", func(t *testing.T) {
+		for _, store := range []ProbeStorage{memory, tracedLocal} {
+			_, ok := Implements[URLHashIndexer](store)
+			assert.True(t, ok)
+			_, ok = Implements[ChangeNotifier](store)
+			assert.True(t, ok)
+			_, ok = Implements[Searcher](store)
+			assert.True(t, ok)
+		}
+	})
+
+	t.Run("stores that are not wrapped are asserted", func(t *testing.T) {
+		_, ok := Implements[URLHashIndexer](local)
+		assert.False(t, ok)
+		_, ok = Implements[WriteChecker](local)
+		assert.True(t, ok)
+	})
+}
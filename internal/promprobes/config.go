@@ -0,0 +1,92 @@
+// Package promprobes renders stored probes into Prometheus Operator Probe
+// resources (monitoring.coreos.com/v1), so that a blackbox exporter scraped by
+// Prometheus runs the checks in environments without a synthetics agent.
+//
+// The controller is level-triggered: every round it lists the stored probes
+// and the Probe resources it manages, then creates, updates and deletes
+// resources until they match. Every replica runs it, so conflicting writes by
+// another replica are left to the next round.
+package promprobes
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// DefaultInterval is the Interval used when none is configured.
+	DefaultInterval = 30 * time.Second
+	// DefaultJobName is the JobName used when none is configured.
+	DefaultJobName = "rhobs-synthetics"
+)
+
+// ProbeGVR is the Prometheus Operator Probe resource.
+var ProbeGVR = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "probes",
+}
+
+// Config selects where and how Probe resources are written. The controller is
+// disabled unless Namespace is set.
+type Config struct {
+	// Namespace is where the Probe resources are kept.
+	Namespace string
+	// ProberURL is the blackbox exporter's probe endpoint, e.g.
+	// "http://blackbox-exporter.monitoring.svc:9115/probe". The path
+	// defaults to /probe.
+	ProberURL string
+	// JobName is the job label of the scraped metrics.
+	JobName string
+	// Interval is how often the resources are reconciled with the store.
+	Interval time.Duration
+}
+
+// Enabled reports whether Probe resources should be written.
+func (c Config) Enabled() bool {
+	return c.Namespace != ""
+}
+
+// Validate reports whether the controller could run with the configuration.
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if _, err := parseProberURL(c.ProberURL); err != nil {
+		return err
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("prometheus probe sync interval must be positive, got %s", c.Interval)
+	}
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.JobName == "" {
+		c.JobName = DefaultJobName
+	}
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+	return c
+}
+
+// prober is the blackbox exporter endpoint as the Probe resource spells it.
+type prober struct {
+	scheme, host, path string
+}
+
+func parseProberURL(raw string) (prober, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return prober{}, fmt.Errorf("invalid prometheus prober url %q, expected an absolute http or https URL of the blackbox exporter", raw)
+	}
+	p := prober{scheme: u.Scheme, host: u.Host, path: u.Path}
+	if p.path == "" || p.path == "/" {
+		p.path = "/probe"
+	}
+	return p, nil
+}
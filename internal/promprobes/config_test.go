@@ -0,0 +1,33 @@
+package promprobes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "disabled", cfg: Config{}},
+		{name: "valid", cfg: Config{Namespace: "ns", ProberURL: "https://blackbox:9115/custom"}},
+		{name: "missing prober url", cfg: Config{Namespace: "ns"}, wantErr: true},
+		{name: "relative prober url", cfg: Config{Namespace: "ns", ProberURL: "blackbox:9115"}, wantErr: true},
+		{name: "negative interval", cfg: Config{Namespace: "ns", ProberURL: "http://blackbox:9115", Interval: -time.Second}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
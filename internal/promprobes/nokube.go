@@ -0,0 +1,25 @@
+//go:build nokube
+
+package promprobes
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+)
+
+// Controller is not available in binaries built without Kubernetes support.
+type Controller struct {
+	Notifications *notify.Notifier
+}
+
+// NewController fails: Probe resources need a Kubernetes client, which this
+// binary is built without.
+func NewController(_ any, _ probestore.ProbeStorage, _ Config) (*Controller, error) {
+	return nil, errors.New("prometheus probes are not supported: the binary is built without Kubernetes support (nokube)")
+}
+
+// Run does nothing.
+func (c *Controller) Run(context.Context) {}
@@ -0,0 +1,342 @@
+//go:build !nokube
+
+package promprobes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// managedByLabelKey marks the Probe resources the controller owns; others
+	// in the namespace are left alone.
+	managedByLabelKey   = "app.kubernetes.io/managed-by"
+	managedByLabelValue = "rhobs-synthetics-api"
+	// probeIDLabelKey holds the ID of the probe a resource was rendered from.
+	probeIDLabelKey = "rhobs-synthetics/probe-id"
+
+	// resourceNamePrefix is prepended to the probe ID to name its resource.
+	resourceNamePrefix = "rhobs-synthetics-"
+	// reservedLabelPrefix marks the system-managed probe labels, which are
+	// not copied to the targets.
+	reservedLabelPrefix = "rhobs-synthetics/"
+	// tenantLabelKey holds the tenant that created a probe.
+	tenantLabelKey = reservedLabelPrefix + "tenant"
+)
+
+// invalidLabelChars matches the characters Prometheus label names cannot hold.
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Controller keeps a Probe resource for every stored probe that is being run.
+type Controller struct {
+	// Notifications is told about resources changed or deleted by something
+	// other than the controller. Nil sends nothing.
+	Notifications *notify.Notifier
+
+	client dynamic.ResourceInterface
+	store  probestore.ProbeStorage
+	config Config
+	prober prober
+
+	// applied is the state of each resource as of the previous round, to
+	// tell changes of the probe from changes made to the resource by others.
+	// It is only used by Reconcile, which Run never calls concurrently.
+	applied map[string]resourceState
+}
+
+// resourceState is the part of a Probe resource the controller manages.
+type resourceState struct {
+	spec   any
+	labels map[string]string
+}
+
+func stateOf(obj *unstructured.Unstructured) resourceState {
+	return resourceState{spec: obj.Object["spec"], labels: obj.GetLabels()}
+}
+
+func (s resourceState) equal(other resourceState) bool {
+	return reflect.DeepEqual(s.spec, other.spec) && reflect.DeepEqual(s.labels, other.labels)
+}
+
+// NewController returns a controller writing Probe resources with client for
+// the probes in store.
+func NewController(client dynamic.Interface, store probestore.ProbeStorage, cfg Config) (*Controller, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("a namespace for prometheus probes is required")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	p, err := parseProberURL(cfg.ProberURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Controller{
+		client: client.Resource(ProbeGVR).Namespace(cfg.Namespace),
+		store:  store,
+		config: cfg.withDefaults(),
+		prober: p,
+	}, nil
+}
+
+// Run reconciles the Probe resources every Config.Interval until ctx is
+// cancelled.
+func (c *Controller) Run(ctx context.Context) {
+	ctx = logging.With(ctx, "operation", "prometheus_probes")
+	slog.InfoContext(ctx, "Starting prometheus probe sync", "namespace", c.config.Namespace, "interval", c.config.Interval)
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+	c.reconcile(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			c.reconcile(ctx)
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "Stopping prometheus probe sync")
+			return
+		}
+	}
+}
+
+func (c *Controller) reconcile(ctx context.Context) {
+	if err := c.Reconcile(ctx); err != nil {
+		slog.ErrorContext(ctx, "Error syncing prometheus probes", "error", err)
+	}
+}
+
+// Reconcile makes one pass: it creates or updates the resource of every
+// probe that is being run and deletes those of probes that are not.
+func (c *Controller) Reconcile(ctx context.Context) error {
+	probes, err := c.store.ListProbes(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list probes: %w", err)
+	}
+	list, err := c.client.List(ctx, metav1.ListOptions{LabelSelector: managedByLabelKey + "=" + managedByLabelValue})
+	if err != nil {
+		return fmt.Errorf("failed to list prometheus probes: %w", err)
+	}
+	existing := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		existing[list.Items[i].GetName()] = &list.Items[i]
+	}
+
+	var errs []error
+	applied := make(map[string]resourceState, len(probes))
+	for _, probe := range probes {
+		if probe.Status == v1.Terminating || probe.Status == v1.Deleted {
+			continue
+		}
+		if probe.Paused != nil && *probe.Paused {
+			continue
+		}
+		desired := c.render(probe)
+		name := desired.GetName()
+		current, ok := existing[name]
+		delete(existing, name)
+		applied[name] = stateOf(desired)
+		last, seen := c.applied[name]
+		if !ok {
+			if seen && last.equal(stateOf(desired)) {
+				c.notifyDrift(ctx, probe, name, "was deleted")
+			}
+			if _, err := c.client.Create(ctx, desired, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+				errs = append(errs, fmt.Errorf("failed to create prometheus probe for probe %s: %w", probe.Id, err))
+			}
+			continue
+		}
+		if stateOf(current).equal(stateOf(desired)) {
+			continue
+		}
+		// The resource differs from what the previous round left while the
+		// probe did not change: something else edited it.
+		if seen && last.equal(stateOf(desired)) {
+			c.notifyDrift(ctx, probe, name, "was changed")
+		}
+		desired.SetResourceVersion(current.GetResourceVersion())
+		if _, err := c.client.Update(ctx, desired, metav1.UpdateOptions{}); err != nil && !k8serrors.IsConflict(err) && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to update prometheus probe for probe %s: %w", probe.Id, err))
+		}
+	}
+
+	// What is left belongs to probes that were removed or stopped.
+	for name := range existing {
+		if err := c.client.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete prometheus probe %s: %w", name, err))
+		}
+	}
+	c.applied = applied
+	return errors.Join(errs...)
+}
+
+// notifyDrift sends a ReconciliationDrift notification for the resource of
+// the probe, which the controller is about to restore.
+func (c *Controller) notifyDrift(ctx context.Context, probe v1.ProbeObject, name, what string) {
+	if c.Notifications == nil {
+		return
+	}
+	slog.WarnContext(ctx, "Prometheus probe changed outside the API, restoring it", "name", name, "probe_id", probe.Id, "change", what)
+	var tenant string
+	if probe.Labels != nil {
+		tenant = (*probe.Labels)[tenantLabelKey]
+	}
+	c.Notifications.Notify(ctx, notify.Event{
+		Type:    notify.ReconciliationDrift,
+		Key:     name,
+		Tenant:  tenant,
+		Summary: fmt.Sprintf("Prometheus Probe %s/%s %s outside the API and is being restored", c.config.Namespace, name, what),
+		Details: map[string]string{"probe_id": probe.Id.String(), "static_url": probe.StaticUrl},
+	})
+}
+
+// ResourceName returns the name of the Probe resource of a probe.
+func ResourceName(probeID uuid.UUID) string {
+	return resourceNamePrefix + probeID.String()
+}
+
+// render returns the Probe resource of a probe. Nested values use the types
+// the API server returns them in, so that they compare equal when unchanged.
+func (c *Controller) render(probe v1.ProbeObject) *unstructured.Unstructured {
+	static := []interface{}{target(probe)}
+	if probe.AdditionalUrls != nil {
+		for _, u := range *probe.AdditionalUrls {
+			static = append(static, u)
+		}
+	}
+	spec := map[string]interface{}{
+		"jobName": c.config.JobName,
+		"prober": map[string]interface{}{
+			"url":    c.prober.host,
+			"scheme": c.prober.scheme,
+			"path":   c.prober.path,
+		},
+		"targets": map[string]interface{}{
+			"staticConfig": map[string]interface{}{
+				"static": static,
+				"labels": targetLabels(probe),
+			},
+		},
+	}
+	if probe.Module != nil {
+		spec["module"] = string(*probe.Module)
+	}
+	if probe.Interval != nil {
+		if d, err := time.ParseDuration(*probe.Interval); err == nil {
+			spec["interval"] = formatDuration(d)
+		}
+	}
+	if probe.Timeout != nil {
+		if d, err := time.ParseDuration(*probe.Timeout); err == nil {
+			spec["scrapeTimeout"] = formatDuration(d)
+		}
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetAPIVersion(ProbeGVR.GroupVersion().String())
+	obj.SetKind("Probe")
+	obj.SetNamespace(c.config.Namespace)
+	obj.SetName(ResourceName(probe.Id))
+	obj.SetLabels(map[string]string{
+		managedByLabelKey: managedByLabelValue,
+		probeIDLabelKey:   probe.Id.String(),
+	})
+	return obj
+}
+
+// target returns the target the blackbox exporter is asked to probe: the
+// address of tcp probes and the host of icmp probes, as its tcp and icmp
+// modules expect, and the static URL of the others.
+func target(probe v1.ProbeObject) string {
+	switch {
+	case probe.Tcp != nil:
+		return probe.Tcp.Address
+	case probe.Icmp != nil:
+		return probe.Icmp.Host
+	}
+	return probe.StaticUrl
+}
+
+// targetLabels returns the labels added to the probe's metrics: its own
+// labels as valid Prometheus label names, its ID, its alerting and owner
+// metadata the way agents expose it, and the query of dns probes.
+func targetLabels(probe v1.ProbeObject) map[string]interface{} {
+	labels := map[string]interface{}{}
+	if probe.Labels != nil {
+		for key, value := range *probe.Labels {
+			if key == "app" || strings.HasPrefix(key, reservedLabelPrefix) {
+				continue
+			}
+			labels[labelName(key)] = value
+		}
+	}
+	labels["probe_id"] = probe.Id.String()
+	if a := probe.Alerting; a != nil {
+		if a.Severity != nil {
+			labels["severity"] = string(*a.Severity)
+		}
+		if a.RunbookUrl != nil {
+			labels["runbook_url"] = *a.RunbookUrl
+		}
+		if a.SilenceDuringMaintenance != nil {
+			labels["silence_during_maintenance"] = fmt.Sprint(*a.SilenceDuringMaintenance)
+		}
+	}
+	if o := probe.Owner; o != nil {
+		if o.Team != nil {
+			labels["owner_team"] = *o.Team
+		}
+		if o.EscalationContact != nil {
+			labels["escalation_contact"] = *o.EscalationContact
+		}
+	}
+	if d := probe.Dns; d != nil {
+		labels["dns_query_name"] = d.QueryName
+		labels["dns_record_type"] = string(d.RecordType)
+	}
+	return labels
+}
+
+// labelName turns a probe label key into a Prometheus label name.
+func labelName(key string) string {
+	name := invalidLabelChars.ReplaceAllString(key, "_")
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// formatDuration spells d the way Prometheus durations are written, e.g.
+// "1m30s" or "500ms", which unlike Go's own format never has fractions or
+// zero units. Precision below a millisecond is dropped.
+func formatDuration(d time.Duration) string {
+	ms := d.Milliseconds()
+	if ms <= 0 {
+		return "0s"
+	}
+	var b strings.Builder
+	for _, unit := range []struct {
+		name string
+		ms   int64
+	}{{"h", 3600000}, {"m", 60000}, {"s", 1000}, {"ms", 1}} {
+		if n := ms / unit.ms; n > 0 {
+			fmt.Fprintf(&b, "%d%s", n, unit.name)
+			ms -= n * unit.ms
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,285 @@
+//go:build !nokube
+
+package promprobes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+const testNamespace = "monitoring"
+
+func newTestController(t *testing.T, objects ...runtime.Object) (*Controller, *probestore.LocalProbeStore) {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{ProbeGVR: "ProbeList"},
+		objects...,
+	)
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	controller, err := NewController(client, store, Config{
+		Namespace: testNamespace,
+		ProberURL: "http://blackbox-exporter.monitoring.svc:9115",
+	})
+	require.NoError(t, err)
+	return controller, store
+}
+
+func TestNewController_Disabled(t *testing.T) {
+	_, err := NewController(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), nil, Config{ProberURL: "http://blackbox:9115"})
+	assert.Error(t, err)
+}
+
+func TestController_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	unmanaged := &unstructured.Unstructured{}
+	unmanaged.SetAPIVersion(ProbeGVR.GroupVersion().String())
+	unmanaged.SetKind("Probe")
+	unmanaged.SetNamespace(testNamespace)
+	unmanaged.SetName("someone-elses-probe")
+	controller, store := newTestController(t, unmanaged)
+	resources := controller.client
+
+	interval, timeout, module := "1m30s", "5s", v1.Tcp
+	severity, silence, team := v1.Critical, true, "sre"
+	active, err := store.CreateProbe(ctx, v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: "https://example.com/health",
+		Labels:    &v1.LabelsSchema{"app": "rhobs-synthetics-probe", "cluster-id": "abc", "rhobs-synthetics/status": "active"},
+		Status:    v1.Active,
+		Interval:  &interval,
+		Timeout:   &timeout,
+		Module:    &module,
+		Alerting:  &v1.AlertingSchema{Severity: &severity, SilenceDuringMaintenance: &silence},
+		Owner:     &v1.OwnerSchema{Team: &team},
+	}, "hash-active")
+	require.NoError(t, err)
+	terminating, err := store.CreateProbe(ctx, v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: "https://example.com/gone",
+		Status:    v1.Terminating,
+	}, "hash-terminating")
+	require.NoError(t, err)
+
+	require.NoError(t, controller.Reconcile(ctx))
+
+	obj, err := resources.Get(ctx, ResourceName(active.Id), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, managedByLabelValue, obj.GetLabels()[managedByLabelKey])
+	assert.Equal(t, active.Id.String(), obj.GetLabels()[probeIDLabelKey])
+	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	assert.Equal(t, map[string]interface{}{
+		"jobName":       DefaultJobName,
+		"interval":      "1m30s",
+		"scrapeTimeout": "5s",
+		"module":        "tcp",
+		"prober": map[string]interface{}{
+			"url":    "blackbox-exporter.monitoring.svc:9115",
+			"scheme": "http",
+			"path":   "/probe",
+		},
+		"targets": map[string]interface{}{
+			"staticConfig": map[string]interface{}{
+				"static": []interface{}{"https://example.com/health"},
+				"labels": map[string]interface{}{
+					"cluster_id":                 "abc",
+					"probe_id":                   active.Id.String(),
+					"owner_team":                 "sre",
+					"severity":                   "critical",
+					"silence_during_maintenance": "true",
+				},
+			},
+		},
+	}, spec)
+	_, err = resources.Get(ctx, ResourceName(terminating.Id), metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err), "terminating probes are not rendered")
+
+	t.Run("paused", func(t *testing.T) {
+		current, err := store.GetProbe(ctx, active.Id)
+		require.NoError(t, err)
+		current.Paused = new(true)
+		current, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+
+		require.NoError(t, controller.Reconcile(ctx))
+		_, err = resources.Get(ctx, ResourceName(active.Id), metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err), "paused probes are not rendered")
+
+		current.Paused = nil
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+		require.NoError(t, controller.Reconcile(ctx))
+		_, err = resources.Get(ctx, ResourceName(active.Id), metav1.GetOptions{})
+		assert.NoError(t, err, "resumed probes are rendered again")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		current, err := store.GetProbe(ctx, active.Id)
+		require.NoError(t, err)
+		current.StaticUrl = "https://example.com/ready"
+		current.AdditionalUrls = &v1.AdditionalUrlsSchema{"https://console.example.com"}
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+
+		require.NoError(t, controller.Reconcile(ctx))
+		obj, err := resources.Get(ctx, ResourceName(active.Id), metav1.GetOptions{})
+		require.NoError(t, err)
+		static, _, _ := unstructured.NestedSlice(obj.Object, "spec", "targets", "staticConfig", "static")
+		assert.Equal(t, []interface{}{"https://example.com/ready", "https://console.example.com"}, static, "additional URLs are targets of the same resource")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.DeleteProbeStorage(ctx, active.Id))
+		require.NoError(t, controller.Reconcile(ctx))
+		_, err := resources.Get(ctx, ResourceName(active.Id), metav1.GetOptions{})
+		assert.True(t, k8serrors.IsNotFound(err))
+		_, err = resources.Get(ctx, unmanaged.GetName(), metav1.GetOptions{})
+		assert.NoError(t, err, "resources without the managed-by label are left alone")
+	})
+}
+
+// eventReceiver passes the events it is sent to a channel.
+type eventReceiver chan notify.Event
+
+func (r eventReceiver) Name() string { return "test" }
+
+func (r eventReceiver) Send(_ context.Context, event notify.Event) error {
+	r <- event
+	return nil
+}
+
+func TestController_Reconcile_Drift(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	controller, store := newTestController(t)
+	resources := controller.client
+	events := make(eventReceiver, 10)
+	notifier, err := notify.New(notify.Config{}, events)
+	require.NoError(t, err)
+	go notifier.Run(ctx)
+	controller.Notifications = notifier
+
+	probe, err := store.CreateProbe(ctx, v1.ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: "https://example.com/health",
+		Labels:    &v1.LabelsSchema{"rhobs-synthetics/tenant": "team-a"},
+		Status:    v1.Active,
+	}, "hash")
+	require.NoError(t, err)
+	name := ResourceName(probe.Id)
+
+	require.NoError(t, controller.Reconcile(ctx))
+	require.NoError(t, controller.Reconcile(ctx))
+	assert.Empty(t, events, "creating and keeping the resource is not drift")
+
+	obj, err := resources.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(obj.Object, "10s", "spec", "interval"))
+	_, err = resources.Update(ctx, obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, controller.Reconcile(ctx))
+	select {
+	case event := <-events:
+		assert.Equal(t, notify.ReconciliationDrift, event.Type)
+		assert.Equal(t, name, event.Key)
+		assert.Equal(t, "team-a", event.Tenant)
+		assert.Contains(t, event.Summary, "was changed")
+	case <-time.After(time.Second):
+		t.Fatal("no drift notification")
+	}
+	obj, err = resources.Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+	interval, _, _ := unstructured.NestedString(obj.Object, "spec", "interval")
+	assert.NotEqual(t, "10s", interval, "the change is reverted")
+
+	t.Run("changes of the probe are not drift", func(t *testing.T) {
+		current, err := store.GetProbe(ctx, probe.Id)
+		require.NoError(t, err)
+		current.StaticUrl = "https://example.com/ready"
+		_, err = store.UpdateProbe(ctx, *current)
+		require.NoError(t, err)
+		require.NoError(t, controller.Reconcile(ctx))
+		assert.Empty(t, events)
+	})
+
+	t.Run("deletion is drift", func(t *testing.T) {
+		require.NoError(t, resources.Delete(ctx, name, metav1.DeleteOptions{}))
+		// The same event is only sent once per repeat interval, so the
+		// notifier is fresh.
+		notifier, err := notify.New(notify.Config{}, events)
+		require.NoError(t, err)
+		go notifier.Run(ctx)
+		controller.Notifications = notifier
+
+		require.NoError(t, controller.Reconcile(ctx))
+		select {
+		case event := <-events:
+			assert.Contains(t, event.Summary, "was deleted")
+		case <-time.After(time.Second):
+			t.Fatal("no drift notification")
+		}
+		_, err = resources.Get(ctx, name, metav1.GetOptions{})
+		assert.NoError(t, err, "the resource is recreated")
+	})
+}
+
+func TestLabelName(t *testing.T) {
+	for key, expected := range map[string]string{
+		"env":                  "env",
+		"cluster-id":           "cluster_id",
+		"example.com/team":     "example_com_team",
+		"1st":                  "_1st",
+		"already_valid_Label9": "already_valid_Label9",
+	} {
+		assert.Equal(t, expected, labelName(key), key)
+	}
+}
+
+func TestTargetLabels_DNS(t *testing.T) {
+	probe := v1.NewDNSProbe(v1.DnsSchema{QueryName: "api.example.com", RecordType: v1.AAAA})
+	built, err := probe.Build()
+	require.NoError(t, err)
+	labels := targetLabels(built)
+	assert.Equal(t, "api.example.com", labels["dns_query_name"])
+	assert.Equal(t, "AAAA", labels["dns_record_type"])
+}
+
+func TestTarget(t *testing.T) {
+	tcp, err := v1.NewTCPProbe(v1.TcpSchema{Address: "db.example.com:5432"}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "db.example.com:5432", target(tcp))
+	icmp, err := v1.NewICMPProbe(v1.IcmpSchema{Host: "203.0.113.7"}).Build()
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.7", target(icmp))
+	assert.Equal(t, "https://example.com", target(v1.ProbeObject{StaticUrl: "https://example.com"}))
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                                   "0s",
+		500 * time.Millisecond:              "500ms",
+		1500 * time.Millisecond:             "1s500ms",
+		90 * time.Second:                    "1m30s",
+		time.Hour:                           "1h",
+		2*time.Hour + 5*time.Second:         "2h5s",
+		time.Millisecond + time.Microsecond: "1ms",
+	} {
+		assert.Equal(t, expected, formatDuration(d), d.String())
+	}
+}
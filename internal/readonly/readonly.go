@@ -0,0 +1,48 @@
+// Package readonly implements the API's read-only mode, used during
+// maintenance or backend migrations: probes can still be read, but requests
+// that would change them are answered with 503 Service Unavailable.
+package readonly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+)
+
+// ErrReadOnly is reported by write readiness checks while read-only mode is on.
+var ErrReadOnly = errors.New("the API is in read-only mode")
+
+// rejectedBody is the error returned for writes while read-only mode is on.
+var rejectedBody = fmt.Sprintf(`{"error":{"message":"%s; writes are rejected until it is turned off","retry_after_seconds":%d}}`,
+	ErrReadOnly, *retryafter.Seconds(http.StatusServiceUnavailable))
+
+// IsWrite reports whether the request may change state, which is any method
+// other than GET, HEAD and OPTIONS.
+func IsWrite(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// Middleware answers writes with 503 when enabled and passes every request
+// through otherwise.
+func Middleware(enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if IsWrite(r) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(rejectedBody))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
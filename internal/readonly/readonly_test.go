@@ -0,0 +1,41 @@
+package readonly
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	testCases := []struct {
+		name     string
+		enabled  bool
+		method   string
+		expected int
+	}{
+		{name: "passes reads when enabled", enabled: true, method: http.MethodGet, expected: http.StatusNoContent},
+		{name: "passes HEAD when enabled", enabled: true, method: http.MethodHead, expected: http.StatusNoContent},
+		{name: "rejects POST when enabled", enabled: true, method: http.MethodPost, expected: http.StatusServiceUnavailable},
+		{name: "rejects PATCH when enabled", enabled: true, method: http.MethodPatch, expected: http.StatusServiceUnavailable},
+		{name: "rejects DELETE when enabled", enabled: true, method: http.MethodDelete, expected: http.StatusServiceUnavailable},
+		{name: "passes writes when disabled", enabled: false, method: http.MethodDelete, expected: http.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Middleware(tc.enabled)(next).ServeHTTP(w, httptest.NewRequest(tc.method, "/probes", nil))
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected == http.StatusServiceUnavailable {
+				assert.JSONEq(t, `{"error":{"message":"the API is in read-only mode; writes are rejected until it is turned off","retry_after_seconds":10}}`, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Package results keeps the most recent run results that agents report for
+// each probe, and summaries of the older ones.
+//
+// Results are held in memory and are meant for debugging and for the
+// aggregate success rate exported as a metric; they are not persisted and
+// each replica only knows the results it received. Every result is also
+// counted in an hourly rollup of its probe, holding the number of runs and
+// successes and a latency histogram, so availability over the last weeks can
+// be answered without keeping every result. Hourly rollups are merged into
+// daily ones after HourlyRetention.
+package results
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// DefaultRetention is the number of results kept per probe.
+const DefaultRetention = 100
+
+// Store keeps up to Retention results per probe, dropping the oldest first.
+type Store struct {
+	retention int
+
+	mu        sync.Mutex
+	byProbe   map[uuid.UUID][]v1.ProbeResultObject
+	rollups   map[uuid.UUID]*rollups
+	total     int
+	successes int
+
+	now func() time.Time
+}
+
+// NewStore creates a Store keeping retention results per probe. A
+// non-positive retention uses DefaultRetention.
+func NewStore(retention int) *Store {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Store{
+		retention: retention,
+		byProbe:   make(map[uuid.UUID][]v1.ProbeResultObject),
+		rollups:   make(map[uuid.UUID]*rollups),
+		now:       time.Now,
+	}
+}
+
+// Add records a result for the probe, evicting its oldest result if the
+// probe is at the retention limit.
+func (s *Store) Add(probeID uuid.UUID, result v1.ProbeResultObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.byProbe[probeID]
+	if len(kept) >= s.retention {
+		s.forget(kept[:len(kept)-s.retention+1])
+		kept = slices.Delete(kept, 0, len(kept)-s.retention+1)
+	}
+	s.byProbe[probeID] = append(kept, result)
+	s.total++
+	if result.Success {
+		s.successes++
+	}
+
+	r := s.rollups[probeID]
+	if r == nil {
+		r = &rollups{}
+		s.rollups[probeID] = r
+	}
+	now := s.now()
+	r.add(now, result.Timestamp, result.Success, result.LatencyMs)
+	r.compact(now)
+}
+
+// List returns the results kept for the probe, newest first.
+func (s *Store) List(probeID uuid.UUID) []v1.ProbeResultObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := slices.Clone(s.byProbe[probeID])
+	slices.Reverse(out)
+	if out == nil {
+		out = []v1.ProbeResultObject{}
+	}
+	return out
+}
+
+// Retain drops the results and rollups of every probe not in keep, so those
+// of deleted probes do not accumulate.
+func (s *Store) Retain(keep map[uuid.UUID]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, kept := range s.byProbe {
+		if !keep[id] {
+			s.forget(kept)
+			delete(s.byProbe, id)
+		}
+	}
+	for id := range s.rollups {
+		if !keep[id] {
+			delete(s.rollups, id)
+		}
+	}
+}
+
+// SuccessRatio returns the share of kept results, across all probes, that
+// succeeded. ok is false when no results are kept.
+func (s *Store) SuccessRatio() (ratio float64, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.total == 0 {
+		return 0, false
+	}
+	return float64(s.successes) / float64(s.total), true
+}
+
+// Len returns the number of results kept across all probes.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// forget removes dropped results from the running totals. s.mu must be held.
+func (s *Store) forget(dropped []v1.ProbeResultObject) {
+	for _, r := range dropped {
+		s.total--
+		if r.Success {
+			s.successes--
+		}
+	}
+}
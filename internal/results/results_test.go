@@ -0,0 +1,71 @@
+package results
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func result(statusCode int, success bool) v1.ProbeResultObject {
+	return v1.ProbeResultObject{StatusCode: statusCode, Success: success, LatencyMs: 10}
+}
+
+func TestStore_AddAndList(t *testing.T) {
+	store := NewStore(3)
+	probeID := uuid.New()
+
+	assert.Empty(t, store.List(probeID))
+	assert.NotNil(t, store.List(probeID), "an unknown probe lists as an empty array")
+
+	for _, code := range []int{200, 201, 202, 203, 204} {
+		store.Add(probeID, result(code, true))
+	}
+
+	got := store.List(probeID)
+	assert.Equal(t, []v1.ProbeResultObject{result(204, true), result(203, true), result(202, true)}, got, "only the newest results are kept, newest first")
+
+	got[0].StatusCode = 500
+	assert.Equal(t, 204, store.List(probeID)[0].StatusCode, "callers get a copy")
+}
+
+func TestStore_SuccessRatio(t *testing.T) {
+	store := NewStore(2)
+	a, b := uuid.New(), uuid.New()
+
+	_, ok := store.SuccessRatio()
+	assert.False(t, ok)
+
+	store.Add(a, result(500, false))
+	store.Add(a, result(200, true))
+	store.Add(b, result(200, true))
+	ratio, ok := store.SuccessRatio()
+	assert.True(t, ok)
+	assert.InDelta(t, 2.0/3.0, ratio, 1e-9)
+
+	// Evicting a's failure leaves only successes.
+	store.Add(a, result(200, true))
+	ratio, _ = store.SuccessRatio()
+	assert.InDelta(t, 1.0, ratio, 1e-9)
+
+	store.Add(b, result(0, false))
+	store.Retain(map[uuid.UUID]bool{b: true})
+	assert.Empty(t, store.List(a))
+	ratio, ok = store.SuccessRatio()
+	assert.True(t, ok)
+	assert.InDelta(t, 0.5, ratio, 1e-9)
+
+	store.Retain(nil)
+	_, ok = store.SuccessRatio()
+	assert.False(t, ok)
+}
+
+func TestNewStore_DefaultRetention(t *testing.T) {
+	store := NewStore(0)
+	probeID := uuid.New()
+	for range DefaultRetention + 5 {
+		store.Add(probeID, result(200, true))
+	}
+	assert.Len(t, store.List(probeID), DefaultRetention)
+}
@@ -0,0 +1,202 @@
+package results
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// HourlyRetention is how long results are summarized per hour. Older
+	// hours are merged into their day.
+	HourlyRetention = 48 * time.Hour
+	// DailyRetention is how long results are summarized per day.
+	DailyRetention = 90 * 24 * time.Hour
+)
+
+// Resolution is the period a rollup summarizes.
+type Resolution time.Duration
+
+// The resolutions rollups are kept at.
+const (
+	Hour Resolution = Resolution(time.Hour)
+	Day  Resolution = Resolution(24 * time.Hour)
+)
+
+// latencyBounds are the upper bounds, in milliseconds, of the latency
+// histogram of a rollup; a last bucket holds the slower results. Percentiles
+// are interpolated within a bucket, so they are estimates.
+var latencyBounds = [...]float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+// rollup summarizes the results of a probe reported over an hour or a day.
+type rollup struct {
+	start      time.Time
+	total      int
+	successes  int
+	maxLatency float64
+	latencies  [len(latencyBounds) + 1]uint32
+}
+
+func (r *rollup) add(success bool, latencyMs float64) {
+	r.total++
+	if success {
+		r.successes++
+	}
+	r.maxLatency = max(r.maxLatency, latencyMs)
+	i, _ := slices.BinarySearch(latencyBounds[:], latencyMs)
+	r.latencies[i]++
+}
+
+func (r *rollup) merge(other rollup) {
+	r.total += other.total
+	r.successes += other.successes
+	r.maxLatency = max(r.maxLatency, other.maxLatency)
+	for i, n := range other.latencies {
+		r.latencies[i] += n
+	}
+}
+
+// percentile estimates the latency below which the share q of the results
+// fell.
+func (r *rollup) percentile(q float64) float64 {
+	rank := math.Ceil(q * float64(r.total))
+	var seen float64
+	for i, n := range r.latencies {
+		if n == 0 {
+			continue
+		}
+		if seen+float64(n) < rank {
+			seen += float64(n)
+			continue
+		}
+		lower, upper := 0.0, r.maxLatency
+		if i > 0 {
+			lower = latencyBounds[i-1]
+		}
+		if i < len(latencyBounds) {
+			upper = min(latencyBounds[i], r.maxLatency)
+		}
+		return lower + (upper-lower)*(rank-seen)/float64(n)
+	}
+	return r.maxLatency
+}
+
+// rollups are the hourly and daily rollups of a probe, each sorted by start.
+type rollups struct {
+	hours []rollup
+	days  []rollup
+}
+
+// add records a result in the hour it was reported in, or in its day if
+// that hour was already merged. Results older than DailyRetention are not
+// summarized.
+func (r *rollups) add(now, at time.Time, success bool, latencyMs float64) {
+	switch {
+	case at.Before(now.Add(-DailyRetention)):
+		return
+	case at.Before(now.Truncate(time.Hour).Add(-HourlyRetention)):
+		r.days = addTo(r.days, at.Truncate(24*time.Hour), success, latencyMs)
+	default:
+		r.hours = addTo(r.hours, at.Truncate(time.Hour), success, latencyMs)
+	}
+}
+
+func addTo(list []rollup, start time.Time, success bool, latencyMs float64) []rollup {
+	i, found := slices.BinarySearchFunc(list, start, func(r rollup, t time.Time) int { return r.start.Compare(t) })
+	if !found {
+		list = slices.Insert(list, i, rollup{start: start})
+	}
+	list[i].add(success, latencyMs)
+	return list
+}
+
+// compact merges the hours older than HourlyRetention into their days, and
+// drops the days older than DailyRetention.
+func (r *rollups) compact(now time.Time) {
+	hourCutoff := now.Truncate(time.Hour).Add(-HourlyRetention)
+	n := 0
+	for n < len(r.hours) && r.hours[n].start.Before(hourCutoff) {
+		n++
+	}
+	for _, hour := range r.hours[:n] {
+		day := hour.start.Truncate(24 * time.Hour)
+		i, found := slices.BinarySearchFunc(r.days, day, func(r rollup, t time.Time) int { return r.start.Compare(t) })
+		if !found {
+			r.days = slices.Insert(r.days, i, rollup{start: day})
+		}
+		r.days[i].merge(hour)
+	}
+	r.hours = slices.Delete(r.hours, 0, n)
+
+	dayCutoff := now.Truncate(24 * time.Hour).Add(-DailyRetention)
+	r.days = slices.DeleteFunc(r.days, func(day rollup) bool { return day.start.Before(dayCutoff) })
+}
+
+// Latency holds latency percentiles, in milliseconds.
+type Latency struct {
+	P50, P90, P99, Max float64
+}
+
+// Bucket summarizes the results reported over one period of a Summary.
+type Bucket struct {
+	Start     time.Time
+	Total     int
+	Successes int
+}
+
+// Summary summarizes the results of a probe over a window.
+type Summary struct {
+	// Since is the start of the first period summarized.
+	Since     time.Time
+	Total     int
+	Successes int
+	// Latency is the zero value when Total is zero.
+	Latency Latency
+	// Buckets are the periods with results, oldest first.
+	Buckets []Bucket
+}
+
+// Summarize returns the rollups of the probe over the last periods of the
+// given resolution spanning window, the current one included. Hourly
+// summaries cover at most HourlyRetention, daily ones DailyRetention.
+func (s *Store) Summarize(probeID uuid.UUID, window time.Duration, resolution Resolution) Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	period := time.Duration(resolution)
+	now := s.now()
+	periods := max(1, (window+period-1)/period)
+	summary := Summary{Since: now.Truncate(period).Add(-(periods - 1) * period), Buckets: []Bucket{}}
+
+	r := s.rollups[probeID]
+	if r == nil {
+		return summary
+	}
+	var total rollup
+	var buckets []rollup
+	for _, from := range [][]rollup{r.days, r.hours} {
+		for _, part := range from {
+			if part.start.Before(summary.Since) {
+				continue
+			}
+			total.merge(part)
+			start := part.start.Truncate(period)
+			i, found := slices.BinarySearchFunc(buckets, start, func(r rollup, t time.Time) int { return r.start.Compare(t) })
+			if !found {
+				buckets = slices.Insert(buckets, i, rollup{start: start})
+			}
+			buckets[i].merge(part)
+		}
+	}
+
+	summary.Total, summary.Successes = total.total, total.successes
+	if total.total > 0 {
+		summary.Latency = Latency{P50: total.percentile(0.5), P90: total.percentile(0.9), P99: total.percentile(0.99), Max: total.maxLatency}
+	}
+	for _, b := range buckets {
+		summary.Buckets = append(summary.Buckets, Bucket{Start: b.start, Total: b.total, Successes: b.successes})
+	}
+	return summary
+}
@@ -0,0 +1,78 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Summarize(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	store := NewStore(2)
+	store.now = func() time.Time { return now }
+	probeID := uuid.New()
+
+	add := func(at time.Time, success bool, latencyMs float64) {
+		store.Add(probeID, v1.ProbeResultObject{Success: success, StatusCode: 200, LatencyMs: latencyMs, Timestamp: at})
+	}
+	// Ten results an hour ago, one of them slow and failing.
+	for i := range 9 {
+		add(now.Add(-time.Hour), true, float64(10+i))
+	}
+	add(now.Add(-time.Hour), false, 3000)
+	// One result in the current hour, three days ago, and too long ago.
+	add(now, true, 40)
+	add(now.Add(-72*time.Hour), false, 100)
+	add(now.Add(-100*24*time.Hour), true, 1)
+	assert.Len(t, store.List(probeID), 2, "raw results are still capped")
+
+	hourly := store.Summarize(probeID, 24*time.Hour, Hour)
+	assert.Equal(t, time.Date(2026, 3, 9, 13, 0, 0, 0, time.UTC), hourly.Since, "the current hour and the 23 before it")
+	assert.Equal(t, 11, hourly.Total)
+	assert.Equal(t, 10, hourly.Successes)
+	require.Len(t, hourly.Buckets, 2)
+	assert.Equal(t, Bucket{Start: time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC), Total: 10, Successes: 9}, hourly.Buckets[0])
+	assert.Equal(t, Bucket{Start: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), Total: 1, Successes: 1}, hourly.Buckets[1])
+	assert.InDelta(t, 17.5, hourly.Latency.P50, 7.5, "within the histogram bucket of the median")
+	assert.Equal(t, 3000.0, hourly.Latency.P99)
+	assert.Equal(t, 3000.0, hourly.Latency.Max)
+
+	daily := store.Summarize(probeID, 7*24*time.Hour, Day)
+	assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), daily.Since)
+	assert.Equal(t, 12, daily.Total)
+	assert.Equal(t, 10, daily.Successes)
+	require.Len(t, daily.Buckets, 2)
+	assert.Equal(t, Bucket{Start: time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC), Total: 1, Successes: 0}, daily.Buckets[0])
+	assert.Equal(t, Bucket{Start: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Total: 11, Successes: 10}, daily.Buckets[1])
+
+	// Two days later the hours have been merged into their days.
+	now = now.Add(50 * time.Hour)
+	add(now, true, 20)
+	assert.Equal(t, 1, store.Summarize(probeID, 48*time.Hour, Hour).Total)
+	daily = store.Summarize(probeID, 7*24*time.Hour, Day)
+	assert.Equal(t, 13, daily.Total)
+	assert.Equal(t, 11, daily.Successes)
+	assert.Equal(t, 3000.0, daily.Latency.Max, "merged rollups keep their latencies")
+
+	empty := store.Summarize(uuid.New(), 24*time.Hour, Hour)
+	assert.Zero(t, empty.Total)
+	assert.Empty(t, empty.Buckets)
+	assert.NotNil(t, empty.Buckets)
+
+	store.Retain(map[uuid.UUID]bool{})
+	assert.Zero(t, store.Summarize(probeID, 7*24*time.Hour, Day).Total, "rollups of deleted probes are dropped")
+}
+
+func TestRollup_Percentile(t *testing.T) {
+	var r rollup
+	for i := 1; i <= 100; i++ {
+		r.add(true, float64(i))
+	}
+	assert.InDelta(t, 50, r.percentile(0.5), 10)
+	assert.InDelta(t, 90, r.percentile(0.9), 10)
+	assert.LessOrEqual(t, r.percentile(0.99), 100.0, "estimates never exceed the slowest result")
+}
@@ -0,0 +1,67 @@
+// Package retryafter standardizes backoff guidance on retryable error
+// responses (409, 429 and 503). The same hint is sent as a Retry-After header
+// and, for JSON error bodies, as error.retry_after_seconds.
+package retryafter
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// hints is the backoff suggested for each retryable status code.
+var hints = map[int]time.Duration{
+	http.StatusConflict:           5 * time.Second,
+	http.StatusTooManyRequests:    1 * time.Second,
+	http.StatusServiceUnavailable: 10 * time.Second,
+}
+
+// For returns the backoff hint for the status code, if it is retryable.
+func For(status int) (time.Duration, bool) {
+	d, ok := hints[status]
+	return d, ok
+}
+
+// Seconds returns the backoff hint for the status code in whole seconds, for
+// use in error bodies. It returns nil for non-retryable status codes.
+func Seconds(status int) *int {
+	d, ok := For(status)
+	if !ok {
+		return nil
+	}
+	s := int(d.Seconds())
+	return &s
+}
+
+// Middleware adds a Retry-After header to retryable responses that do not
+// already carry one.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&responseWriter{ResponseWriter: w}, r)
+	})
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	if !rw.wroteHeader {
+		rw.wroteHeader = true
+		if d, ok := For(code); ok && rw.Header().Get("Retry-After") == "" {
+			rw.Header().Set("Retry-After", strconv.Itoa(int(d.Seconds())))
+		}
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,79 @@
+package retryafter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeconds(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		expected *int
+	}{
+		{name: "conflict", status: http.StatusConflict, expected: intPtr(5)},
+		{name: "rate limited", status: http.StatusTooManyRequests, expected: intPtr(1)},
+		{name: "unavailable", status: http.StatusServiceUnavailable, expected: intPtr(10)},
+		{name: "not retryable", status: http.StatusBadRequest, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Seconds(tc.status))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	testCases := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected string
+	}{
+		{
+			name:     "sets header on 503",
+			handler:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) },
+			expected: "10",
+		},
+		{
+			name:     "sets header on 409",
+			handler:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusConflict) },
+			expected: "5",
+		},
+		{
+			name: "keeps an explicit header",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			expected: "30",
+		},
+		{
+			name:     "leaves other responses alone",
+			handler:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			expected: "",
+		},
+		{
+			name:     "implicit 200",
+			handler:  func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) },
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			Middleware(tc.handler).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/probes", nil))
+			require.NotZero(t, rr.Code)
+			assert.Equal(t, tc.expected, rr.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,144 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MinKeyLength is the shortest key accepted by NewFileStore.
+const MinKeyLength = 32
+
+// fileExt is the extension of the files holding the values of a probe.
+const fileExt = ".secret"
+
+// FileStore keeps the values of each probe in a file of a directory,
+// encrypted with AES-GCM. It backs the local probe store.
+type FileStore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+// NewFileStore returns a FileStore keeping its files in dir, which is created
+// if needed, encrypted with a key derived from key.
+func NewFileStore(dir string, key []byte) (*FileStore, error) {
+	if len(key) < MinKeyLength {
+		return nil, fmt.Errorf("probe secret key must be at least %d bytes, got %d", MinKeyLength, len(key))
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create probe secret directory: %w", err)
+	}
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, aead: aead}, nil
+}
+
+func (f *FileStore) path(probeID uuid.UUID) string {
+	return filepath.Join(f.dir, probeID.String()+fileExt)
+}
+
+// Put implements Store. The file is replaced atomically, so a reader never
+// sees half of it.
+func (f *FileStore) Put(_ context.Context, probeID uuid.UUID, values map[string]string) error {
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to marshal probe secret: %w", err)
+	}
+	nonce := make([]byte, f.aead.NonceSize())
+	_, _ = rand.Read(nonce) // crypto/rand.Read never returns an error
+	// The probe ID is authenticated along with the values, so a file copied
+	// to another probe's name is not accepted.
+	sealed := f.aead.Seal(nonce, nonce, plain, []byte(probeID.String()))
+
+	tmp, err := os.CreateTemp(f.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write probe secret: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // already renamed on success
+	if _, err := tmp.Write(sealed); err != nil {
+		tmp.Close() //nolint:errcheck
+		return fmt.Errorf("failed to write probe secret: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write probe secret: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path(probeID)); err != nil {
+		return fmt.Errorf("failed to write probe secret: %w", err)
+	}
+	return nil
+}
+
+// Get implements Store.
+func (f *FileStore) Get(_ context.Context, probeID uuid.UUID) (map[string]string, error) {
+	sealed, err := os.ReadFile(f.path(probeID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read probe secret: %w", err)
+	}
+	size := f.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, fmt.Errorf("probe secret of %s is truncated", probeID)
+	}
+	plain, err := f.aead.Open(nil, sealed[:size], sealed[size:], []byte(probeID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt probe secret of %s; was the key changed?", probeID)
+	}
+	var values map[string]string
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal probe secret: %w", err)
+	}
+	return values, nil
+}
+
+// Delete implements Store.
+func (f *FileStore) Delete(_ context.Context, probeID uuid.UUID) error {
+	if err := os.Remove(f.path(probeID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete probe secret: %w", err)
+	}
+	return nil
+}
+
+// List implements Store.
+func (f *FileStore) List(_ context.Context) (map[uuid.UUID]time.Time, error) {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list probe secrets: %w", err)
+	}
+	written := make(map[uuid.UUID]time.Time, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), fileExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		probeID, err := uuid.Parse(name)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue // removed since the directory was read
+		}
+		written[probeID] = info.ModTime()
+	}
+	return written, nil
+}
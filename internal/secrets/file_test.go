@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := []byte(strings.Repeat("k", MinKeyLength))
+
+	_, err := NewFileStore(dir, key[:MinKeyLength-1])
+	require.EqualError(t, err, "probe secret key must be at least 32 bytes, got 31")
+
+	store, err := NewFileStore(dir, key)
+	require.NoError(t, err)
+	probeID := uuid.New()
+
+	values, err := store.Get(ctx, probeID)
+	require.NoError(t, err)
+	assert.Nil(t, values)
+
+	require.NoError(t, store.Put(ctx, probeID, map[string]string{PasswordKey: "s3cret"}))
+	require.NoError(t, store.Put(ctx, probeID, map[string]string{BearerTokenKey: "token"}))
+	values, err = store.Get(ctx, probeID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{BearerTokenKey: "token"}, values)
+
+	t.Run("values are encrypted and private", func(t *testing.T) {
+		path := filepath.Join(dir, probeID.String()+fileExt)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "token")
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	})
+
+	t.Run("another key cannot read them", func(t *testing.T) {
+		other, err := NewFileStore(dir, []byte(strings.Repeat("x", MinKeyLength)))
+		require.NoError(t, err)
+		_, err = other.Get(ctx, probeID)
+		assert.ErrorContains(t, err, "was the key changed?")
+	})
+
+	t.Run("values are bound to their probe", func(t *testing.T) {
+		copied := uuid.New()
+		data, err := os.ReadFile(filepath.Join(dir, probeID.String()+fileExt))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, copied.String()+fileExt), data, 0o600))
+		_, err = store.Get(ctx, copied)
+		assert.Error(t, err)
+		require.NoError(t, store.Delete(ctx, copied))
+	})
+
+	written, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{probeID}, keys(written))
+
+	require.NoError(t, store.Delete(ctx, probeID))
+	require.NoError(t, store.Delete(ctx, probeID))
+	written, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, written)
+}
+
+func keys[V any](m map[uuid.UUID]V) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
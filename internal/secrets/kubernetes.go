@@ -0,0 +1,118 @@
+//go:build !nokube
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	secretNameFormat = "probe-auth-%s"
+	// ProbeIDLabel holds the probe ID on the Secrets written by
+	// KubernetesStore.
+	ProbeIDLabel = "rhobs-synthetics/probe-id"
+	appLabelKey  = "app"
+	appLabel     = "rhobs-synthetics-probe-auth"
+)
+
+// KubernetesStore keeps the values of each probe in a Secret, next to the
+// ConfigMap or Probe resource holding the probe. It backs the etcd and crd
+// engines.
+type KubernetesStore struct {
+	Client    kubernetes.Interface
+	Namespace string
+}
+
+func secretName(probeID uuid.UUID) string {
+	return fmt.Sprintf(secretNameFormat, probeID)
+}
+
+// Put implements Store.
+func (k *KubernetesStore) Put(ctx context.Context, probeID uuid.UUID, values map[string]string) error {
+	secrets := k.Client.CoreV1().Secrets(k.Namespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName(probeID),
+			Namespace: k.Namespace,
+			Labels: map[string]string{
+				appLabelKey:  appLabel,
+				ProbeIDLabel: probeID.String(),
+			},
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: values,
+	}
+	_, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		existing, getErr := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get probe secret: %w", getErr)
+		}
+		// StringData is merged into Data by the API server, so Data is
+		// cleared to drop the values that are no longer set.
+		existing.Data = nil
+		existing.StringData = values
+		_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write probe secret: %w", err)
+	}
+	return nil
+}
+
+// Get implements Store.
+func (k *KubernetesStore) Get(ctx context.Context, probeID uuid.UUID) (map[string]string, error) {
+	secret, err := k.Client.CoreV1().Secrets(k.Namespace).Get(ctx, secretName(probeID), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get probe secret: %w", err)
+	}
+	values := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for key, value := range secret.Data {
+		values[key] = string(value)
+	}
+	// Fake clientsets do not move StringData into Data.
+	for key, value := range secret.StringData {
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Delete implements Store.
+func (k *KubernetesStore) Delete(ctx context.Context, probeID uuid.UUID) error {
+	err := k.Client.CoreV1().Secrets(k.Namespace).Delete(ctx, secretName(probeID), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete probe secret: %w", err)
+	}
+	return nil
+}
+
+// List implements Store. A Secret is reported as written when it was
+// created.
+func (k *KubernetesStore) List(ctx context.Context) (map[uuid.UUID]time.Time, error) {
+	list, err := k.Client.CoreV1().Secrets(k.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: appLabelKey + "=" + appLabel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list probe secrets: %w", err)
+	}
+	written := make(map[uuid.UUID]time.Time, len(list.Items))
+	for _, secret := range list.Items {
+		probeID, err := uuid.Parse(secret.Labels[ProbeIDLabel])
+		if err != nil {
+			continue
+		}
+		written[probeID] = secret.CreationTimestamp.Time
+	}
+	return written, nil
+}
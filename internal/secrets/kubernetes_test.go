@@ -0,0 +1,48 @@
+//go:build !nokube
+
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesStore(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "rhobs"},
+	})
+	store := &KubernetesStore{Client: client, Namespace: "rhobs"}
+	probeID := uuid.New()
+
+	values, err := store.Get(ctx, probeID)
+	require.NoError(t, err)
+	assert.Nil(t, values)
+
+	require.NoError(t, store.Put(ctx, probeID, map[string]string{PasswordKey: "s3cret"}))
+	require.NoError(t, store.Put(ctx, probeID, map[string]string{BearerTokenKey: "token"}))
+	values, err = store.Get(ctx, probeID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{BearerTokenKey: "token"}, values)
+
+	secret, err := client.CoreV1().Secrets("rhobs").Get(ctx, "probe-auth-"+probeID.String(), metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, probeID.String(), secret.Labels[ProbeIDLabel])
+
+	written, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{probeID}, keys(written))
+
+	require.NoError(t, store.Delete(ctx, probeID))
+	require.NoError(t, store.Delete(ctx, probeID))
+	written, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, written)
+}
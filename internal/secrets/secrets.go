@@ -0,0 +1,34 @@
+// Package secrets keeps the secret values of probe credentials apart from
+// the probes themselves, so they never reach the ConfigMap JSON, the Probe
+// resources or any response. Probes only hold Redacted in their place.
+package secrets
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Redacted stands in for a secret value in stored probes and responses.
+const Redacted = "REDACTED"
+
+// Keys of the secret values of a probe.
+const (
+	PasswordKey    = "password"
+	BearerTokenKey = "bearer_token"
+)
+
+// Store keeps the secret values of each probe's credentials.
+type Store interface {
+	// Put replaces the values of the probe.
+	Put(ctx context.Context, probeID uuid.UUID, values map[string]string) error
+	// Get returns the values of the probe, or nil if it has none.
+	Get(ctx context.Context, probeID uuid.UUID) (map[string]string, error)
+	// Delete removes the values of the probe; removing none is not an error.
+	Delete(ctx context.Context, probeID uuid.UUID) error
+	// List returns the probes that have values, with when their values were
+	// written. Values of removed probes are pruned by comparing it with when
+	// the probes were listed, so it must not be earlier than the first Put.
+	List(ctx context.Context) (map[uuid.UUID]time.Time, error)
+}
@@ -0,0 +1,270 @@
+// Package shadow mirrors a sample of read requests to a secondary deployment
+// and logs where its responses differ from the primary's. It is meant for
+// validating a new storage backend under real traffic before cutting over.
+package shadow
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+)
+
+const (
+	// maxCapturedBody bounds how much of each response is kept for comparison.
+	maxCapturedBody = 1 << 20
+	// maxReportedDiffs bounds how many differing paths are logged per request.
+	maxReportedDiffs = 10
+)
+
+// Config controls request mirroring.
+type Config struct {
+	// URL is the base URL of the shadow deployment. Mirroring is disabled when
+	// it is empty.
+	URL string
+	// Percent is the share of read requests to mirror, from 0 to 100.
+	Percent float64
+	// Timeout bounds each mirrored request.
+	Timeout time.Duration
+}
+
+// Mirror sends sampled read requests to the shadow deployment after the
+// primary handler has answered them.
+type Mirror struct {
+	base    *url.URL
+	percent float64
+	client  *http.Client
+	sample  func() float64
+}
+
+// NewMirror validates cfg and returns a Mirror, or nil if mirroring is
+// disabled.
+func NewMirror(cfg Config) (*Mirror, error) {
+	if cfg.URL == "" || cfg.Percent <= 0 {
+		return nil, nil
+	}
+	if cfg.Percent > 100 {
+		return nil, fmt.Errorf("shadow percent must be between 0 and 100, got %v", cfg.Percent)
+	}
+	base, err := url.Parse(cfg.URL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("invalid shadow URL %q", cfg.URL)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Mirror{
+		base:    base,
+		percent: cfg.Percent,
+		client:  &http.Client{Timeout: timeout},
+		sample:  rand.Float64,
+	}, nil
+}
+
+// Middleware mirrors sampled GET requests. A nil Mirror passes requests
+// through untouched.
+func (m *Mirror) Middleware(next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || m.sample()*100 >= m.percent {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		req, err := m.shadowRequest(r)
+		if err != nil {
+			slog.ErrorContext(r.Context(), "Shadow: failed to build mirrored request", "path", r.URL.Path, "error", err)
+			metrics.RecordShadowResult("error")
+			return
+		}
+		go m.compare(req, rec.status, rec.body.Bytes(), rec.truncated)
+	})
+}
+
+func (m *Mirror) shadowRequest(r *http.Request) (*http.Request, error) {
+	target := m.base.JoinPath(r.URL.Path)
+	target.RawQuery = r.URL.RawQuery
+
+	// The primary request's context is cancelled once its response is
+	// written; keep its values, such as the request ID, but not its deadline.
+	req, err := http.NewRequestWithContext(context.WithoutCancel(r.Context()), http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range []string{"Accept", "Authorization", "X-Tenant", "X-Request-ID"} {
+		if v := r.Header.Get(h); v != "" {
+			req.Header.Set(h, v)
+		}
+	}
+	return req, nil
+}
+
+func (m *Mirror) compare(req *http.Request, primaryStatus int, primaryBody []byte, truncated bool) {
+	ctx := req.Context()
+	resp, err := m.client.Do(req)
+	if err != nil {
+		slog.WarnContext(ctx, "Shadow: mirrored request failed", "uri", req.URL.RequestURI(), "error", err)
+		metrics.RecordShadowResult("error")
+		return
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	shadowBody, err := io.ReadAll(io.LimitReader(resp.Body, maxCapturedBody+1))
+	if err != nil {
+		slog.WarnContext(ctx, "Shadow: reading mirrored response failed", "uri", req.URL.RequestURI(), "error", err)
+		metrics.RecordShadowResult("error")
+		return
+	}
+
+	var diffs []string
+	if primaryStatus != resp.StatusCode {
+		diffs = append(diffs, fmt.Sprintf("status: %d != %d", primaryStatus, resp.StatusCode))
+	}
+	if truncated || len(shadowBody) > maxCapturedBody {
+		slog.InfoContext(ctx, "Shadow: response too large to compare bodies, comparing status only", "uri", req.URL.RequestURI(), "max_bytes", maxCapturedBody)
+	} else {
+		diffs = append(diffs, diffBodies(primaryBody, shadowBody)...)
+	}
+
+	if len(diffs) == 0 {
+		metrics.RecordShadowResult("match")
+		return
+	}
+	metrics.RecordShadowResult("mismatch")
+	if len(diffs) > maxReportedDiffs {
+		diffs = append(diffs[:maxReportedDiffs], fmt.Sprintf("... and %d more", len(diffs)-maxReportedDiffs))
+	}
+	slog.WarnContext(ctx, "Shadow: GET differs from primary", "uri", req.URL.RequestURI(), "diffs", strings.Join(diffs, "; "))
+}
+
+// diffBodies compares two response bodies, structurally if both are JSON.
+func diffBodies(primary, shadow []byte) []string {
+	var p, s any
+	if json.Unmarshal(primary, &p) != nil || json.Unmarshal(shadow, &s) != nil {
+		if bytes.Equal(primary, shadow) {
+			return nil
+		}
+		return []string{"body: differs"}
+	}
+	var diffs []string
+	diffValues("$", normalize(p), normalize(s), &diffs)
+	return diffs
+}
+
+func diffValues(path string, p, s any, diffs *[]string) {
+	switch pv := p.(type) {
+	case map[string]any:
+		sv, ok := s.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make(map[string]struct{}, len(pv)+len(sv))
+		for k := range pv {
+			keys[k] = struct{}{}
+		}
+		for k := range sv {
+			keys[k] = struct{}{}
+		}
+		for _, k := range slices.Sorted(maps.Keys(keys)) {
+			diffValues(path+"."+k, pv[k], sv[k], diffs)
+		}
+		return
+	case []any:
+		sv, ok := s.([]any)
+		if !ok {
+			break
+		}
+		if len(pv) != len(sv) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: length %d != %d", path, len(pv), len(sv)))
+			return
+		}
+		for i := range pv {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), pv[i], sv[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(p, s) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: %v != %v", path, p, s))
+	}
+}
+
+// normalize sorts arrays of objects that carry an "id" so that backends
+// returning the same items in a different order compare equal, and drops
+// resource versions, which each backend assigns on its own.
+func normalize(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		delete(val, "resource_version")
+		for k, child := range val {
+			val[k] = normalize(child)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = normalize(child)
+		}
+		if slices.IndexFunc(val, func(e any) bool { _, ok := objectID(e); return !ok }) == -1 {
+			slices.SortFunc(val, func(a, b any) int {
+				ai, _ := objectID(a)
+				bi, _ := objectID(b)
+				return cmp.Compare(ai, bi)
+			})
+		}
+	}
+	return v
+}
+
+func objectID(v any) (string, bool) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	id, ok := obj["id"].(string)
+	return id, ok
+}
+
+// recorder passes the response through to the client while keeping a copy
+// of the status and up to maxCapturedBody bytes of the body.
+type recorder struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (r *recorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	room := maxCapturedBody - r.body.Len()
+	if len(b) > room {
+		r.truncated = true
+	}
+	r.body.Write(b[:min(len(b), room)])
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,168 @@
+package shadow
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMirror(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       Config
+		expectNil bool
+		expectErr bool
+	}{
+		{name: "disabled without url", cfg: Config{Percent: 10}, expectNil: true},
+		{name: "disabled with zero percent", cfg: Config{URL: "http://shadow:8080"}, expectNil: true},
+		{name: "enabled", cfg: Config{URL: "http://shadow:8080", Percent: 10}},
+		{name: "percent above 100", cfg: Config{URL: "http://shadow:8080", Percent: 101}, expectErr: true},
+		{name: "relative url", cfg: Config{URL: "shadow:8080/api", Percent: 10}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewMirror(tc.cfg)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectNil, m == nil)
+		})
+	}
+}
+
+func TestDiffBodies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		primary  string
+		shadow   string
+		expected []string
+	}{
+		{
+			name:    "same items in a different order",
+			primary: `{"probes":[{"id":"a","status":"active"},{"id":"b","status":"pending"}]}`,
+			shadow:  `{"probes":[{"id":"b","status":"pending"},{"id":"a","status":"active"}]}`,
+		},
+		{
+			name:    "resource versions differ between backends",
+			primary: `{"id":"a","resource_version":"8412"}`,
+			shadow:  `{"id":"a","resource_version":"3"}`,
+		},
+		{
+			name:     "changed field",
+			primary:  `{"probes":[{"id":"a","status":"active"}]}`,
+			shadow:   `{"probes":[{"id":"a","status":"pending"}]}`,
+			expected: []string{"$.probes[0].status: active != pending"},
+		},
+		{
+			name:     "missing item",
+			primary:  `{"probes":[{"id":"a"},{"id":"b"}]}`,
+			shadow:   `{"probes":[{"id":"a"}]}`,
+			expected: []string{"$.probes: length 2 != 1"},
+		},
+		{
+			name:     "extra field",
+			primary:  `{"id":"a"}`,
+			shadow:   `{"id":"a","labels":{"env":"prod"}}`,
+			expected: []string{"$.labels: <nil> != map[env:prod]"},
+		},
+		{
+			name:     "non-json bodies",
+			primary:  "ok",
+			shadow:   "not ok",
+			expected: []string{"body: differs"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, diffBodies([]byte(tc.primary), []byte(tc.shadow)))
+		})
+	}
+}
+
+// syncBuffer is a log sink safe for the mirror's background goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestMirror_Middleware(t *testing.T) {
+	var logs syncBuffer
+	orig := log.Writer()
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(orig) })
+
+	mirrored := make(chan *http.Request, 1)
+	shadowSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrored <- r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"probes":[{"id":"a","status":"pending"}]}`))
+	}))
+	defer shadowSrv.Close()
+
+	m, err := NewMirror(Config{URL: shadowSrv.URL, Percent: 100})
+	require.NoError(t, err)
+
+	primary := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"probes":[{"id":"a","status":"active"}]}`))
+	})
+	handler := m.Middleware(primary)
+
+	t.Run("mirrors reads and logs differences", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/probes?label_selector=env%3Dprod", nil)
+		req.Header.Set("X-Tenant", "dashboards")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"probes":[{"id":"a","status":"active"}]}`, rr.Body.String(), "client gets the primary response")
+
+		select {
+		case got := <-mirrored:
+			assert.Equal(t, "/probes", got.URL.Path)
+			assert.Equal(t, "label_selector=env%3Dprod", got.URL.RawQuery)
+			assert.Equal(t, "dashboards", got.Header.Get("X-Tenant"))
+		case <-time.After(5 * time.Second):
+			t.Fatal("request was not mirrored")
+		}
+
+		assert.Eventually(t, func() bool {
+			return strings.Contains(logs.String(), "$.probes[0].status: active != pending")
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("does not mirror writes", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/probes", nil))
+
+		select {
+		case <-mirrored:
+			t.Fatal("write request must not be mirrored")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
@@ -0,0 +1,87 @@
+// Package standby implements the warm standby mode of replicas that are not
+// the leader: they serve reads themselves and forward writes to the leader,
+// so clients can send every request to any replica, and reads keep being
+// served while the leader restarts.
+package standby
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/readonly"
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+)
+
+// ForwardedHeader marks requests forwarded by a standby replica, which are
+// never forwarded again, so replicas disagreeing on the leader cannot bounce
+// a request between them.
+const ForwardedHeader = "X-Synthetics-Forwarded-By"
+
+// Leader tells whether this replica leads, and the URL the leader is reached
+// at.
+type Leader interface {
+	IsLeader() bool
+	Leader() string
+	Identity() string
+}
+
+// Middleware forwards writes to the leader while this replica is not it, and
+// passes every other request through. A nil Leader passes every request
+// through.
+func Middleware(leader Leader) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if leader == nil {
+			return next
+		}
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				target, _ := url.Parse(leader.Leader())
+				r.SetURL(target)
+				r.SetXForwarded()
+				r.Out.Header.Set(ForwardedHeader, leader.Identity())
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				slog.WarnContext(r.Context(), "Error forwarding write to the leader", "leader", leader.Leader(), "error", err)
+				metrics.RecordStandbyForward("error")
+				unavailable(w, "the leader cannot be reached")
+			},
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !readonly.IsWrite(r) || leader.IsLeader() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			target := leader.Leader()
+			switch {
+			case r.Header.Get(ForwardedHeader) != "":
+				metrics.RecordStandbyForward("rejected")
+				unavailable(w, "the request was forwarded to a replica that is not the leader; leadership is changing")
+			case target == "" || target == leader.Identity():
+				metrics.RecordStandbyForward("rejected")
+				unavailable(w, "no leader is elected")
+			default:
+				if u, err := url.Parse(target); err != nil || u.Scheme == "" || u.Host == "" {
+					slog.ErrorContext(r.Context(), "Leader identity is not a URL", "leader", target)
+					metrics.RecordStandbyForward("rejected")
+					unavailable(w, "the leader cannot be reached")
+					return
+				}
+				metrics.RecordStandbyForward("forwarded")
+				proxy.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// unavailable answers a write that cannot reach the leader with 503, which
+// clients retry.
+func unavailable(w http.ResponseWriter, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = fmt.Fprintf(w, `{"error":{"message":"%s; writes are forwarded to the leader","retry_after_seconds":%d}}`,
+		reason, *retryafter.Seconds(http.StatusServiceUnavailable))
+}
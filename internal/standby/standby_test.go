@@ -0,0 +1,88 @@
+package standby
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLeader struct {
+	leading  bool
+	leader   string
+	identity string
+}
+
+func (f fakeLeader) IsLeader() bool   { return f.leading }
+func (f fakeLeader) Leader() string   { return f.leader }
+func (f fakeLeader) Identity() string { return f.identity }
+
+func TestMiddleware(t *testing.T) {
+	var forwarded *http.Request
+	var forwardedBody string
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r
+		body, _ := io.ReadAll(r.Body)
+		forwardedBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("from the leader"))
+	}))
+	defer primary.Close()
+	local := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("from this replica"))
+	})
+	serve := func(leader Leader, r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		Middleware(leader)(local).ServeHTTP(w, r)
+		return w
+	}
+	standby := fakeLeader{leader: primary.URL, identity: "http://standby:8080"}
+
+	t.Run("reads are served locally", func(t *testing.T) {
+		w := serve(standby, httptest.NewRequest(http.MethodGet, "/probes", nil))
+		assert.Equal(t, "from this replica", w.Body.String())
+	})
+
+	t.Run("writes are forwarded to the leader", func(t *testing.T) {
+		w := serve(standby, httptest.NewRequest(http.MethodPost, "/probes?dry_run=true", strings.NewReader(`{"static_url":"https://example.com"}`)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "from the leader", w.Body.String())
+		assert.Equal(t, "/probes", forwarded.URL.Path)
+		assert.Equal(t, "dry_run=true", forwarded.URL.RawQuery)
+		assert.Equal(t, `{"static_url":"https://example.com"}`, forwardedBody)
+		assert.Equal(t, "http://standby:8080", forwarded.Header.Get(ForwardedHeader))
+	})
+
+	t.Run("the leader serves writes itself", func(t *testing.T) {
+		w := serve(fakeLeader{leading: true, leader: "http://standby:8080", identity: "http://standby:8080"}, httptest.NewRequest(http.MethodPost, "/probes", nil))
+		assert.Equal(t, "from this replica", w.Body.String())
+	})
+
+	t.Run("writes are rejected without a leader to forward them to", func(t *testing.T) {
+		for name, leader := range map[string]Leader{
+			"no leader":   fakeLeader{identity: "http://standby:8080"},
+			"unreachable": fakeLeader{leader: "http://127.0.0.1:1", identity: "http://standby:8080"},
+			"not a URL":   fakeLeader{leader: "replica-0", identity: "http://standby:8080"},
+		} {
+			w := serve(leader, httptest.NewRequest(http.MethodDelete, "/probes/1", nil))
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code, name)
+			assert.Contains(t, w.Body.String(), `"retry_after_seconds":10`, name)
+		}
+	})
+
+	t.Run("forwarded writes are not forwarded again", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, "/probes/1", nil)
+		r.Header.Set(ForwardedHeader, "http://other:8080")
+		w := serve(standby, r)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "leadership is changing")
+	})
+
+	t.Run("nil leader", func(t *testing.T) {
+		w := serve(nil, httptest.NewRequest(http.MethodPost, "/probes", nil))
+		assert.Equal(t, "from this replica", w.Body.String())
+	})
+}
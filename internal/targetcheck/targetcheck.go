@@ -0,0 +1,233 @@
+// Package targetcheck checks the target of a probe before it is created, on
+// request: that its URL has an allowed scheme, that its host resolves to
+// public addresses only, so probes cannot be aimed at the cluster's own
+// network, and that it answers.
+package targetcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+// DefaultSchemes are the schemes targets may use unless configured
+// otherwise.
+var DefaultSchemes = []string{"http", "https"}
+
+// DefaultTimeout bounds the name lookup and the HEAD request unless
+// configured otherwise.
+const DefaultTimeout = 5 * time.Second
+
+// Config sets what the checks accept.
+type Config struct {
+	// Schemes are the URL schemes targets may use; nil selects
+	// DefaultSchemes.
+	Schemes []string
+	// Timeout bounds the name lookup and the HEAD request each; zero
+	// selects DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Validate reports settings the checks cannot run with.
+func (c Config) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("--probe-target-timeout must not be negative, got %s", c.Timeout)
+	}
+	for _, scheme := range c.Schemes {
+		if scheme == "" || scheme != strings.ToLower(scheme) || strings.Contains(scheme, ":") {
+			return fmt.Errorf("invalid scheme %q in --probe-target-schemes, expected a lower-case scheme such as https", scheme)
+		}
+	}
+	return nil
+}
+
+// Checker runs the checks.
+type Checker struct {
+	schemes []string
+	timeout time.Duration
+	lookup  func(ctx context.Context, host string) ([]netip.Addr, error)
+	// blocked reports the addresses targets may not resolve to. The HEAD
+	// request re-checks the address it connects to, so a name resolving
+	// differently the second time cannot reach a blocked one.
+	blocked func(netip.Addr) bool
+}
+
+// New returns a Checker with the settings of cfg.
+func New(cfg Config) *Checker {
+	c := &Checker{
+		schemes: cfg.Schemes,
+		timeout: cfg.Timeout,
+		blocked: isNonPublic,
+	}
+	if c.schemes == nil {
+		c.schemes = DefaultSchemes
+	}
+	if c.timeout == 0 {
+		c.timeout = DefaultTimeout
+	}
+	c.lookup = func(ctx context.Context, host string) ([]netip.Addr, error) {
+		return net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	}
+	return c
+}
+
+// nonPublicPrefixes are the IANA special-purpose ranges that are not
+// globally reachable, such as private and shared (CGNAT) space, which
+// cluster networks are carved from, and the documentation, benchmarking and
+// reserved ranges. See
+// https://www.iana.org/assignments/iana-ipv4-special-registry and
+// https://www.iana.org/assignments/iana-ipv6-special-registry.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "This network"
+	netip.MustParsePrefix("10.0.0.0/8"),      // Private
+	netip.MustParsePrefix("100.64.0.0/10"),   // Shared address space
+	netip.MustParsePrefix("127.0.0.0/8"),     // Loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // Link-local
+	netip.MustParsePrefix("172.16.0.0/12"),   // Private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentation (TEST-NET-1)
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // Private
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentation (TEST-NET-2)
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentation (TEST-NET-3)
+	netip.MustParsePrefix("224.0.0.0/4"),     // Multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reserved, and limited broadcast
+	netip.MustParsePrefix("::/128"),          // Unspecified
+	netip.MustParsePrefix("::1/128"),         // Loopback
+	netip.MustParsePrefix("::ffff:0:0/96"),   // IPv4-mapped, when not unmapped
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // Local-use IPv4/IPv6 translation
+	netip.MustParsePrefix("100::/64"),        // Discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("3fff::/20"),       // Documentation
+	netip.MustParsePrefix("5f00::/16"),       // Segment routing SIDs
+	netip.MustParsePrefix("fc00::/7"),        // Unique local
+	netip.MustParsePrefix("fe80::/10"),       // Link-local
+	netip.MustParsePrefix("fec0::/10"),       // Site-local, deprecated
+	netip.MustParsePrefix("ff00::/8"),        // Multicast
+}
+
+// nat64Prefix is the well-known NAT64 prefix, whose addresses reach the IPv4
+// address embedded in their last 32 bits.
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// isNonPublic reports addresses in one of nonPublicPrefixes. IPv4-mapped and
+// NAT64 addresses are judged by the IPv4 address they reach.
+func isNonPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if nat64Prefix.Contains(addr) {
+		a := addr.As16()
+		addr = netip.AddrFrom4([4]byte(a[12:]))
+	}
+	return slices.ContainsFunc(nonPublicPrefixes, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// Check runs the checks against the target URL of a probe and returns those
+// it fails, or nil. A failed check skips the ones depending on it.
+func (c *Checker) Check(ctx context.Context, target string) []v1.TargetValidationFailure {
+	failures := c.check(ctx, target)
+	for _, failure := range failures {
+		metrics.RecordTargetValidationFailure(string(failure.Check))
+	}
+	return failures
+}
+
+func (c *Checker) check(ctx context.Context, target string) []v1.TargetValidationFailure {
+	fail := func(check v1.TargetValidationFailureCheck, format string, args ...any) []v1.TargetValidationFailure {
+		return []v1.TargetValidationFailure{{Check: check, Message: fmt.Sprintf(format, args...)}}
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		return fail(v1.Url, "%q is not an absolute URL with a host", target)
+	}
+	if !slices.Contains(c.schemes, strings.ToLower(u.Scheme)) {
+		return fail(v1.Scheme, "scheme %q is not allowed, expected one of %v", u.Scheme, c.schemes)
+	}
+
+	host := u.Hostname()
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		lookupCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		addrs, err = c.lookup(lookupCtx, host)
+		cancel()
+		if err == nil && len(addrs) == 0 {
+			err = errors.New("no addresses")
+		}
+		if err != nil {
+			return fail(v1.Resolution, "%s does not resolve: %v", host, err)
+		}
+	}
+	if i := slices.IndexFunc(addrs, c.blocked); i >= 0 {
+		if addrs[i].String() == host {
+			return fail(v1.PrivateIp, "%s is a private or special-purpose address", host)
+		}
+		return fail(v1.PrivateIp, "%s resolves to %s, a private or special-purpose address", host, addrs[i].Unmap())
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+	if err := c.head(ctx, u); err != nil {
+		return fail(v1.Reachability, "HEAD %s failed: %v", u.Redacted(), err)
+	}
+	return nil
+}
+
+var errBlockedAddress = errors.New("connection to a private or special-purpose address refused")
+
+// head sends a HEAD request to the target. Any response counts, whatever its
+// status; redirects are not followed.
+func (c *Checker) head(ctx context.Context, u *url.URL) error {
+	dialer := &net.Dialer{
+		Timeout: c.timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || c.blocked(addrPort.Addr()) {
+				return errBlockedAddress
+			}
+			return nil
+		},
+	}
+	client := &http.Client{
+		Timeout: c.timeout,
+		Transport: &http.Transport{
+			// Probes reach their targets directly, and so does the check.
+			Proxy:           nil,
+			DialContext:     dialer.DialContext,
+			TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "rhobs-synthetics-api target check")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,133 @@
+package targetcheck
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		http.Redirect(w, r, "http://169.254.169.254/", http.StatusFound)
+	}))
+	defer target.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	checker := New(Config{Schemes: []string{"http", "https", "tcp"}, Timeout: time.Second})
+	checker.lookup = func(_ context.Context, host string) ([]netip.Addr, error) {
+		switch host {
+		case "public.example.com":
+			return []netip.Addr{netip.MustParseAddr("203.0.113.10")}, nil
+		case "internal.example.com":
+			return []netip.Addr{netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("10.0.3.7")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	// The test servers listen on loopback; only the addresses named here
+	// are blocked.
+	checker.blocked = func(addr netip.Addr) bool {
+		return addr.IsPrivate() || addr == netip.MustParseAddr("169.254.169.254")
+	}
+
+	testCases := []struct {
+		name    string
+		target  string
+		check   v1.TargetValidationFailureCheck
+		message string
+	}{
+		{name: "reachable", target: target.URL},
+		{name: "not http", target: "tcp://public.example.com:443"},
+		{name: "relative", target: "/healthz", check: v1.Url, message: `"/healthz" is not an absolute URL with a host`},
+		{name: "scheme", target: "file://public.example.com/etc/passwd", check: v1.Scheme, message: `scheme "file" is not allowed, expected one of [http https tcp]`},
+		{name: "unresolved", target: "https://missing.example.com", check: v1.Resolution, message: "missing.example.com does not resolve: no such host"},
+		{name: "private", target: "https://internal.example.com", check: v1.PrivateIp, message: "internal.example.com resolves to 10.0.3.7, a private or special-purpose address"},
+		{name: "private literal", target: "http://[::ffff:192.168.0.1]:8080", check: v1.PrivateIp, message: "::ffff:192.168.0.1 is a private or special-purpose address"},
+		{name: "unreachable", target: closed.URL, check: v1.Reachability},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failures := checker.Check(context.Background(), tc.target)
+			if tc.check == "" {
+				assert.Empty(t, failures)
+				return
+			}
+			require.Len(t, failures, 1)
+			assert.Equal(t, tc.check, failures[0].Check)
+			if tc.message != "" {
+				assert.Equal(t, tc.message, failures[0].Message)
+			}
+		})
+	}
+}
+
+func TestNonPublicAddresses(t *testing.T) {
+	testCases := []struct {
+		name    string
+		addr    string
+		blocked bool
+	}{
+		{name: "this network", addr: "0.1.2.3", blocked: true},
+		{name: "unspecified", addr: "0.0.0.0", blocked: true},
+		{name: "private 10/8", addr: "10.1.2.3", blocked: true},
+		{name: "shared address space", addr: "100.64.0.1", blocked: true},
+		{name: "shared address space end", addr: "100.127.255.254", blocked: true},
+		{name: "loopback", addr: "127.0.0.1", blocked: true},
+		{name: "link-local", addr: "169.254.169.254", blocked: true},
+		{name: "private 172.16/12", addr: "172.16.0.1", blocked: true},
+		{name: "IETF protocol assignments", addr: "192.0.0.8", blocked: true},
+		{name: "TEST-NET-1", addr: "192.0.2.1", blocked: true},
+		{name: "6to4 relay anycast", addr: "192.88.99.1", blocked: true},
+		{name: "private 192.168/16", addr: "192.168.1.1", blocked: true},
+		{name: "benchmarking", addr: "198.19.255.1", blocked: true},
+		{name: "TEST-NET-2", addr: "198.51.100.7", blocked: true},
+		{name: "TEST-NET-3", addr: "203.0.113.10", blocked: true},
+		{name: "multicast", addr: "224.0.0.251", blocked: true},
+		{name: "reserved", addr: "240.0.0.1", blocked: true},
+		{name: "limited broadcast", addr: "255.255.255.255", blocked: true},
+		{name: "IPv6 unspecified", addr: "::", blocked: true},
+		{name: "IPv6 loopback", addr: "::1", blocked: true},
+		{name: "IPv4-mapped private", addr: "::ffff:10.0.0.1", blocked: true},
+		{name: "NAT64 of private", addr: "64:ff9b::a00:1", blocked: true},
+		{name: "NAT64 of shared address space", addr: "64:ff9b::100.64.0.1", blocked: true},
+		{name: "local-use NAT64", addr: "64:ff9b:1::808:808", blocked: true},
+		{name: "discard-only", addr: "100::1", blocked: true},
+		{name: "IPv6 IETF protocol assignments", addr: "2001::1", blocked: true},
+		{name: "IPv6 documentation", addr: "2001:db8::1", blocked: true},
+		{name: "6to4", addr: "2002:a00:1::1", blocked: true},
+		{name: "IPv6 documentation 3fff::/20", addr: "3fff:0fff::1", blocked: true},
+		{name: "segment routing", addr: "5f00::1", blocked: true},
+		{name: "unique local", addr: "fd00::1", blocked: true},
+		{name: "IPv6 link-local", addr: "fe80::1", blocked: true},
+		{name: "site-local", addr: "fec0::1", blocked: true},
+		{name: "IPv6 multicast", addr: "ff02::1", blocked: true},
+
+		{name: "public", addr: "8.8.8.8"},
+		{name: "below shared address space", addr: "100.63.255.255"},
+		{name: "above shared address space", addr: "100.128.0.1"},
+		{name: "above benchmarking", addr: "198.20.0.1"},
+		{name: "IPv4-mapped public", addr: "::ffff:8.8.8.8"},
+		{name: "NAT64 of public", addr: "64:ff9b::8.8.8.8"},
+		{name: "IPv6 public", addr: "2001:4860:4860::8888"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.blocked, isNonPublic(netip.MustParseAddr(tc.addr)), tc.addr)
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.EqualError(t, Config{Timeout: -time.Second}.Validate(), "--probe-target-timeout must not be negative, got -1s")
+	assert.EqualError(t, Config{Schemes: []string{"HTTPS"}}.Validate(), `invalid scheme "HTTPS" in --probe-target-schemes, expected a lower-case scheme such as https`)
+}
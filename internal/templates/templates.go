@@ -0,0 +1,406 @@
+// Package templates keeps probe templates: shared defaults, such as labels
+// and a URL pattern, that probes can be created from by supplying only the
+// values that differ between them.
+//
+// Templates given at startup are held in memory on every replica and cannot
+// be changed through the API. Those created through the API are kept in the
+// probe store when it keeps records, so every replica sharing it sees them,
+// and in memory otherwise.
+package templates
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// recordKind is the kind of the store records of the templates created
+// through the API.
+const recordKind = "probe-templates"
+
+var (
+	// ErrExists is wrapped by the errors for templates whose ID or name
+	// another template has.
+	ErrExists = errors.New("already exists")
+	// ErrConfigured is wrapped by the errors for changing or removing a
+	// template given at startup, which every replica holds on its own.
+	ErrConfigured = errors.New("cannot be changed through the API")
+)
+
+// variablePattern matches the {name} placeholders of a URL pattern.
+var variablePattern = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_-]*)\}`)
+
+// Template holds the defaults of the probes created from it. Empty fields
+// leave the probe's value to the request or the server defaults.
+type Template struct {
+	ID   uuid.UUID `mapstructure:"id" json:"id"`
+	Name string    `mapstructure:"name" json:"name"`
+	// URLPattern is the probes' static URL, with a {name} placeholder for
+	// each variable.
+	URLPattern string               `mapstructure:"url_pattern" json:"url_pattern"`
+	Labels     map[string]string    `mapstructure:"labels" json:"labels,omitempty"`
+	Interval   string               `mapstructure:"interval" json:"interval,omitempty"`
+	Timeout    string               `mapstructure:"timeout" json:"timeout,omitempty"`
+	Module     v1.ProbeModuleSchema `mapstructure:"module" json:"module,omitempty"`
+	CreatedAt  time.Time            `mapstructure:"-" json:"created_at"`
+}
+
+// Variables returns the names of the URL pattern's variables, in order of
+// first appearance.
+func (t Template) Variables() []string {
+	variables := []string{}
+	for _, match := range variablePattern.FindAllStringSubmatch(t.URLPattern, -1) {
+		if !slices.Contains(variables, match[1]) {
+			variables = append(variables, match[1])
+		}
+	}
+	return variables
+}
+
+// URL returns the URL pattern with its variables replaced by values, which
+// must hold a non-empty value for each variable and nothing else.
+func (t Template) URL(values map[string]string) (string, error) {
+	variables := t.Variables()
+	for name, value := range values {
+		if !slices.Contains(variables, name) {
+			return "", fmt.Errorf("template %q has no variable %q, expected %v", t.Name, name, variables)
+		}
+		if value == "" {
+			return "", fmt.Errorf("variable %q of template %q is empty", name, t.Name)
+		}
+	}
+	var missing []string
+	for _, name := range variables {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("template %q requires variables %v", t.Name, missing)
+	}
+	return variablePattern.ReplaceAllStringFunc(t.URLPattern, func(placeholder string) string {
+		return values[placeholder[1:len(placeholder)-1]]
+	}), nil
+}
+
+// Validate reports whether probes could be created from the template. The
+// module and label policy are checked by the API.
+func (t Template) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+	if t.URLPattern == "" {
+		return fmt.Errorf("template %q: url_pattern cannot be empty", t.Name)
+	}
+	if rest := variablePattern.ReplaceAllString(t.URLPattern, ""); strings.ContainsAny(rest, "{}") {
+		return fmt.Errorf("template %q: url_pattern %q has an invalid placeholder, expected {name} with letters, digits, '_' and '-'", t.Name, t.URLPattern)
+	}
+	for _, field := range []struct{ name, value string }{{"interval", t.Interval}, {"timeout", t.Timeout}} {
+		if field.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(field.value); err != nil || d <= 0 {
+			return fmt.Errorf("template %q: invalid %s %q, expected a positive duration", t.Name, field.name, field.value)
+		}
+	}
+	return nil
+}
+
+// Store holds the templates. It is safe for concurrent use.
+type Store struct {
+	// Records keeps the templates created through the API. They are kept in
+	// memory when it is nil.
+	Records probestore.RecordStore
+
+	mu sync.RWMutex
+	// configured are the templates given at startup.
+	configured map[uuid.UUID]Template
+	// created are the templates created through the API while Records is
+	// nil.
+	created map[uuid.UUID]Template
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{configured: make(map[uuid.UUID]Template), created: make(map[uuid.UUID]Template)}
+}
+
+// Add validates and adds a template given at startup, keeping its ID if it
+// has one.
+func (s *Store) Add(t Template) (Template, error) {
+	if err := t.Validate(); err != nil {
+		return Template{}, err
+	}
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	t.CreatedAt = clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.configured[t.ID]; ok {
+		return Template{}, fmt.Errorf("a template with ID %s %w", t.ID, ErrExists)
+	}
+	if err := checkName(t, s.configured); err != nil {
+		return Template{}, err
+	}
+	s.configured[t.ID] = clone(t)
+	return clone(t), nil
+}
+
+// List returns every template, sorted by name.
+func (s *Store) List(ctx context.Context) ([]Template, error) {
+	created, err := s.listCreated(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	templates := slices.AppendSeq(created, maps.Values(s.configured))
+	s.mu.RUnlock()
+	for i := range templates {
+		templates[i] = clone(templates[i])
+	}
+	slices.SortFunc(templates, func(a, b Template) int { return cmp.Compare(a.Name, b.Name) })
+	return templates, nil
+}
+
+// Get returns the template with the given ID.
+func (s *Store) Get(ctx context.Context, id uuid.UUID) (Template, bool, error) {
+	s.mu.RLock()
+	t, ok := s.configured[id]
+	s.mu.RUnlock()
+	if ok {
+		return clone(t), true, nil
+	}
+	return s.getCreated(ctx, id)
+}
+
+// Create validates and adds a template created through the API, keeping its
+// ID if it has one.
+//
+// Names are checked against the templates listed beforehand, so two
+// replicas creating templates of the same name at once may both succeed.
+func (s *Store) Create(ctx context.Context, t Template) (Template, error) {
+	if err := t.Validate(); err != nil {
+		return Template{}, err
+	}
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	t.CreatedAt = clock.Now()
+	t = clone(t)
+
+	if s.Records == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err := s.checkMemory(t, true); err != nil {
+			return Template{}, err
+		}
+		s.created[t.ID] = t
+		return clone(t), nil
+	}
+
+	if err := s.checkStored(ctx, t); err != nil {
+		return Template{}, err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to encode probe template: %w", err)
+	}
+	if err := s.Records.CreateRecord(ctx, recordKind, probestore.Record{ID: t.ID.String(), Data: data}); err != nil {
+		if k8serrors.IsAlreadyExists(err) {
+			return Template{}, fmt.Errorf("a template with ID %s %w", t.ID, ErrExists)
+		}
+		return Template{}, fmt.Errorf("failed to store probe template: %w", err)
+	}
+	return clone(t), nil
+}
+
+// Replace validates t and stores it in place of the template with the given
+// ID, which keeps its ID and creation time. It reports false if there is no
+// such template.
+func (s *Store) Replace(ctx context.Context, id uuid.UUID, t Template) (Template, bool, error) {
+	if err := t.Validate(); err != nil {
+		return Template{}, false, err
+	}
+	existing, ok, err := s.Get(ctx, id)
+	if err != nil || !ok {
+		return Template{}, ok, err
+	}
+	if err := s.checkConfigured(id); err != nil {
+		return Template{}, true, err
+	}
+	t.ID = id
+	t.CreatedAt = existing.CreatedAt
+	t = clone(t)
+
+	if s.Records == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.created[id]; !ok {
+			return Template{}, false, nil
+		}
+		if err := s.checkMemory(t, false); err != nil {
+			return Template{}, true, err
+		}
+		s.created[id] = t
+		return clone(t), true, nil
+	}
+
+	if err := s.checkStored(ctx, t); err != nil {
+		return Template{}, true, err
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return Template{}, true, fmt.Errorf("failed to encode probe template: %w", err)
+	}
+	if err := s.Records.PutRecord(ctx, recordKind, probestore.Record{ID: id.String(), Data: data}); err != nil {
+		return Template{}, true, fmt.Errorf("failed to store probe template: %w", err)
+	}
+	return clone(t), true, nil
+}
+
+// Delete removes the template with the given ID, reporting whether it existed.
+func (s *Store) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	if err := s.checkConfigured(id); err != nil {
+		return true, err
+	}
+	if s.Records == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, ok := s.created[id]
+		delete(s.created, id)
+		return ok, nil
+	}
+	err := s.Records.DeleteRecord(ctx, recordKind, id.String())
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to remove probe template: %w", err)
+	}
+	return true, nil
+}
+
+// listCreated returns the templates created through the API.
+func (s *Store) listCreated(ctx context.Context) ([]Template, error) {
+	if s.Records == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return slices.Collect(maps.Values(s.created)), nil
+	}
+	records, err := s.Records.ListRecords(ctx, recordKind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list probe templates: %w", err)
+	}
+	templates := make([]Template, 0, len(records))
+	for _, record := range records {
+		var t Template
+		if err := json.Unmarshal(record.Data, &t); err != nil {
+			return nil, fmt.Errorf("failed to decode probe template %s: %w", record.ID, err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// getCreated returns the template created through the API with the given ID.
+func (s *Store) getCreated(ctx context.Context, id uuid.UUID) (Template, bool, error) {
+	if s.Records == nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		t, ok := s.created[id]
+		return clone(t), ok, nil
+	}
+	record, err := s.Records.GetRecord(ctx, recordKind, id.String())
+	if k8serrors.IsNotFound(err) {
+		return Template{}, false, nil
+	}
+	if err != nil {
+		return Template{}, false, fmt.Errorf("failed to get probe template: %w", err)
+	}
+	var t Template
+	if err := json.Unmarshal(record.Data, &t); err != nil {
+		return Template{}, false, fmt.Errorf("failed to decode probe template %s: %w", id, err)
+	}
+	return t, true, nil
+}
+
+// checkConfigured fails if the template with the given ID was given at
+// startup.
+func (s *Store) checkConfigured(id uuid.UUID) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if t, ok := s.configured[id]; ok {
+		return fmt.Errorf("template %q is given in the configuration and %w", t.Name, ErrConfigured)
+	}
+	return nil
+}
+
+// checkMemory fails if another template has t's name, or, for a new
+// template, its ID. s.mu must be held.
+func (s *Store) checkMemory(t Template, create bool) error {
+	if _, ok := s.configured[t.ID]; ok && create {
+		return fmt.Errorf("a template with ID %s %w", t.ID, ErrExists)
+	}
+	if _, ok := s.created[t.ID]; ok && create {
+		return fmt.Errorf("a template with ID %s %w", t.ID, ErrExists)
+	}
+	if err := checkName(t, s.configured); err != nil {
+		return err
+	}
+	return checkName(t, s.created)
+}
+
+// checkStored fails if another template has t's name, or a template given
+// at startup has its ID. Stored IDs are checked when the record is created.
+func (s *Store) checkStored(ctx context.Context, t Template) error {
+	created, err := s.listCreated(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.configured[t.ID]; ok {
+		return fmt.Errorf("a template with ID %s %w", t.ID, ErrExists)
+	}
+	if err := checkName(t, s.configured); err != nil {
+		return err
+	}
+	for _, other := range created {
+		if other.Name == t.Name && other.ID != t.ID {
+			return fmt.Errorf("a template named %q %w", t.Name, ErrExists)
+		}
+	}
+	return nil
+}
+
+// checkName fails if another of the templates has t's name.
+func checkName(t Template, templates map[uuid.UUID]Template) error {
+	for _, other := range templates {
+		if other.Name == t.Name && other.ID != t.ID {
+			return fmt.Errorf("a template named %q %w", t.Name, ErrExists)
+		}
+	}
+	return nil
+}
+
+// clone copies t so that callers cannot change stored labels.
+func clone(t Template) Template {
+	t.Labels = maps.Clone(t.Labels)
+	return t
+}
@@ -0,0 +1,183 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_URL(t *testing.T) {
+	tmpl := Template{Name: "console", URLPattern: "https://console.{region}.example.com/{cluster}/{region}/healthz"}
+	assert.Equal(t, []string{"region", "cluster"}, tmpl.Variables())
+
+	testCases := []struct {
+		name        string
+		values      map[string]string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "every variable",
+			values:   map[string]string{"region": "eu-west-1", "cluster": "c1"},
+			expected: "https://console.eu-west-1.example.com/c1/eu-west-1/healthz",
+		},
+		{
+			name:        "missing variable",
+			values:      map[string]string{"region": "eu-west-1"},
+			expectedErr: `template "console" requires variables [cluster]`,
+		},
+		{
+			name:        "unknown variable",
+			values:      map[string]string{"region": "eu-west-1", "cluster": "c1", "env": "prod"},
+			expectedErr: `template "console" has no variable "env", expected [region cluster]`,
+		},
+		{
+			name:        "empty value",
+			values:      map[string]string{"region": "", "cluster": "c1"},
+			expectedErr: `variable "region" of template "console" is empty`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			url, err := tmpl.URL(tc.values)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, url)
+		})
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		template    Template
+		expectedErr string
+	}{
+		{name: "valid", template: Template{Name: "api", URLPattern: "https://{host}/healthz", Interval: "1m", Timeout: "10s"}},
+		{name: "no variables", template: Template{Name: "api", URLPattern: "https://example.com"}},
+		{name: "no name", template: Template{URLPattern: "https://example.com"}, expectedErr: "template name cannot be empty"},
+		{name: "no pattern", template: Template{Name: "api"}, expectedErr: `template "api": url_pattern cannot be empty`},
+		{name: "unclosed placeholder", template: Template{Name: "api", URLPattern: "https://{host/healthz"}, expectedErr: "invalid placeholder"},
+		{name: "invalid placeholder name", template: Template{Name: "api", URLPattern: "https://{1host}/healthz"}, expectedErr: "invalid placeholder"},
+		{name: "invalid interval", template: Template{Name: "api", URLPattern: "https://example.com", Interval: "often"}, expectedErr: `invalid interval "often"`},
+		{name: "negative timeout", template: Template{Name: "api", URLPattern: "https://example.com", Timeout: "-1s"}, expectedErr: `invalid timeout "-1s"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.template.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		records probestore.RecordStore
+	}{
+		{name: "memory"},
+		{name: "records", records: probestore.NewMemoryProbeStore()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := NewStore()
+			store.Records = tc.records
+			testStore(t, store)
+		})
+	}
+}
+
+func testStore(t *testing.T, store *Store) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	created, err := store.Create(ctx, Template{ID: id, Name: "b", URLPattern: "https://{host}", Labels: map[string]string{"team": "a"}})
+	require.NoError(t, err)
+	assert.Equal(t, id, created.ID, "a given ID is kept")
+	assert.False(t, created.CreatedAt.IsZero())
+	other, err := store.Create(ctx, Template{Name: "a", URLPattern: "https://example.com"})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, other.ID)
+	configured, err := store.Add(Template{Name: "c", URLPattern: "https://{host}/c"})
+	require.NoError(t, err)
+
+	t.Run("duplicates are rejected", func(t *testing.T) {
+		_, err := store.Create(ctx, Template{ID: id, Name: "d", URLPattern: "https://example.com"})
+		assert.EqualError(t, err, "a template with ID "+id.String()+" already exists")
+		assert.ErrorIs(t, err, ErrExists)
+		_, err = store.Create(ctx, Template{ID: configured.ID, Name: "d", URLPattern: "https://example.com"})
+		assert.ErrorIs(t, err, ErrExists)
+		_, err = store.Create(ctx, Template{Name: "b", URLPattern: "https://example.com"})
+		assert.EqualError(t, err, `a template named "b" already exists`)
+		_, err = store.Create(ctx, Template{Name: "c", URLPattern: "https://example.com"})
+		assert.EqualError(t, err, `a template named "c" already exists`)
+	})
+
+	t.Run("list is sorted by name", func(t *testing.T) {
+		list, err := store.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, "a", list[0].Name)
+		assert.Equal(t, "b", list[1].Name)
+		assert.Equal(t, "c", list[2].Name)
+	})
+
+	t.Run("stored labels cannot be changed by callers", func(t *testing.T) {
+		got, ok, err := store.Get(ctx, id)
+		require.NoError(t, err)
+		require.True(t, ok)
+		got.Labels["team"] = "changed"
+		got, _, _ = store.Get(ctx, id)
+		assert.Equal(t, "a", got.Labels["team"])
+	})
+
+	t.Run("replace keeps the ID and creation time", func(t *testing.T) {
+		replaced, ok, err := store.Replace(ctx, id, Template{Name: "b2", URLPattern: "https://{host}/v2"})
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, id, replaced.ID)
+		assert.Equal(t, created.CreatedAt, replaced.CreatedAt)
+		got, _, err := store.Get(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, replaced, got)
+
+		_, _, err = store.Replace(ctx, id, Template{Name: "a", URLPattern: "https://example.com"})
+		assert.EqualError(t, err, `a template named "a" already exists`)
+		_, ok, err = store.Replace(ctx, uuid.New(), Template{Name: "d", URLPattern: "https://example.com"})
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("configured templates cannot be changed", func(t *testing.T) {
+		_, _, err := store.Replace(ctx, configured.ID, Template{Name: "c", URLPattern: "https://example.com"})
+		assert.EqualError(t, err, `template "c" is given in the configuration and cannot be changed through the API`)
+		assert.ErrorIs(t, err, ErrConfigured)
+		_, err = store.Delete(ctx, configured.ID)
+		assert.ErrorIs(t, err, ErrConfigured)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ok, err := store.Delete(ctx, id)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = store.Delete(ctx, id)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		_, ok, err = store.Get(ctx, id)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,166 @@
+// Package tlsreload serves TLS from certificate files that may be rotated
+// while the server is running.
+//
+// The files are re-read periodically and the new certificate (and client CA
+// bundle, when mutual TLS is enabled) is used for every handshake after the
+// change, so a rotated Kubernetes secret takes effect without a restart. If
+// a reload fails, for example because the key and certificate were caught
+// mid-rotation, the previous material keeps being served.
+package tlsreload
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultInterval is how often the certificate files are checked for changes.
+const DefaultInterval = time.Minute
+
+// Config names the files to serve TLS from.
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle of CAs that client certificates must chain
+	// to. When set, clients without a valid certificate are rejected.
+	ClientCAFile string
+}
+
+// Enabled reports whether TLS is configured.
+func (c Config) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Validate checks that the options are used together correctly.
+func (c Config) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("--tls-cert and --tls-key must be set together")
+	}
+	if c.ClientCAFile != "" && c.CertFile == "" {
+		return errors.New("--tls-client-ca requires --tls-cert and --tls-key")
+	}
+	return nil
+}
+
+// material is the loaded state of the files, kept with the raw bytes so that
+// an unchanged reload can be detected.
+type material struct {
+	raw       []byte
+	cert      tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// Reloader holds the current TLS material.
+type Reloader struct {
+	cfg     Config
+	current atomic.Pointer[material]
+}
+
+// New loads the configured files and returns a Reloader serving them.
+func New(cfg Config) (*Reloader, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	r := &Reloader{cfg: cfg}
+	m, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.current.Store(m)
+	return r, nil
+}
+
+// TLSConfig returns a server configuration that uses the latest loaded
+// certificate and client CAs for each new connection.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			m := r.current.Load()
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{m.cert},
+			}
+			if m.clientCAs != nil {
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				cfg.ClientCAs = m.clientCAs
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// Reload re-reads the files and reports whether their content changed. On
+// error the previously loaded material stays in use.
+func (r *Reloader) Reload() (bool, error) {
+	m, err := r.load()
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(m.raw, r.current.Load().raw) {
+		return false, nil
+	}
+	r.current.Store(m)
+	return true, nil
+}
+
+// Run reloads the files every interval until ctx is cancelled.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			changed, err := r.Reload()
+			if err != nil {
+				slog.ErrorContext(ctx, "Failed to reload TLS certificates, keeping the previous ones", "error", err)
+				continue
+			}
+			if changed {
+				slog.InfoContext(ctx, "Reloaded TLS certificates", "cert", r.cfg.CertFile, "client_ca", r.cfg.ClientCAFile)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Reloader) load() (*material, error) {
+	certPEM, err := os.ReadFile(r.cfg.CertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(r.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS key: %w", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	m := &material{cert: cert}
+	m.raw = append(append(m.raw, certPEM...), keyPEM...)
+
+	if r.cfg.ClientCAFile != "" {
+		caPEM, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in TLS client CA %s", r.cfg.ClientCAFile)
+		}
+		m.clientCAs = pool
+		m.raw = append(m.raw, caPEM...)
+	}
+	return m, nil
+}
@@ -0,0 +1,235 @@
+package tlsreload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCert is a certificate and key, signed by parent or self-signed.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, cn string, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		DNSNames:              []string{cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) write(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(certFile, c.certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, c.keyPEM, 0o600))
+}
+
+func (c *testCert) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.cert)
+	return pool
+}
+
+// serve starts an HTTPS server using the reloader and returns its URL.
+func serve(t *testing.T, r *Reloader) string {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = r.TLSConfig()
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func get(url string, roots *x509.CertPool, clientCert *testCert) (*http.Response, error) {
+	cfg := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	if clientCert != nil {
+		pair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg, DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       Config
+		expectErr bool
+	}{
+		{name: "disabled", cfg: Config{}},
+		{name: "cert and key", cfg: Config{CertFile: "tls.crt", KeyFile: "tls.key"}},
+		{name: "mutual TLS", cfg: Config{CertFile: "tls.crt", KeyFile: "tls.key", ClientCAFile: "ca.crt"}},
+		{name: "cert without key", cfg: Config{CertFile: "tls.crt"}, expectErr: true},
+		{name: "key without cert", cfg: Config{KeyFile: "tls.key"}, expectErr: true},
+		{name: "client CA without cert", cfg: Config{ClientCAFile: "ca.crt"}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNew_InvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	_, err := New(Config{CertFile: certFile, KeyFile: keyFile})
+	require.Error(t, err, "missing files")
+
+	newTestCert(t, "localhost", false, nil).write(t, certFile, keyFile)
+	other := newTestCert(t, "localhost", false, nil)
+	require.NoError(t, os.WriteFile(keyFile, other.keyPEM, 0o600))
+	_, err = New(Config{CertFile: certFile, KeyFile: keyFile})
+	require.Error(t, err, "mismatched key")
+
+	newTestCert(t, "localhost", false, nil).write(t, certFile, keyFile)
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+	_, err = New(Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	require.Error(t, err, "client CA without certificates")
+}
+
+func TestReloader_ReloadsRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first := newTestCert(t, "localhost", false, nil)
+	first.write(t, certFile, keyFile)
+
+	r, err := New(Config{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	url := serve(t, r)
+
+	resp, err := get(url, first.pool(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	changed, err := r.Reload()
+	require.NoError(t, err)
+	assert.False(t, changed, "unchanged files are not reported as a reload")
+
+	second := newTestCert(t, "localhost", false, nil)
+	second.write(t, certFile, keyFile)
+	changed, err = r.Reload()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	_, err = get(url, first.pool(), nil)
+	assert.Error(t, err, "the old certificate is no longer served")
+	resp, err = get(url, second.pool(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	t.Run("a failed reload keeps serving the previous certificate", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(keyFile, first.keyPEM, 0o600))
+		_, err := r.Reload()
+		require.Error(t, err)
+
+		resp, err := get(url, second.pool(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	server := newTestCert(t, "localhost", false, nil)
+	server.write(t, certFile, keyFile)
+	ca := newTestCert(t, "client-ca", true, nil)
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0o600))
+
+	r, err := New(Config{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile})
+	require.NoError(t, err)
+	url := serve(t, r)
+
+	client := newTestCert(t, "agent", false, ca)
+	resp, err := get(url, server.pool(), client)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = get(url, server.pool(), nil)
+	assert.Error(t, err, "clients without a certificate are rejected")
+
+	_, err = get(url, server.pool(), newTestCert(t, "agent", false, nil))
+	assert.Error(t, err, "clients with a certificate from another CA are rejected")
+
+	t.Run("a rotated client CA is picked up", func(t *testing.T) {
+		newCA := newTestCert(t, "client-ca-2", true, nil)
+		require.NoError(t, os.WriteFile(caFile, newCA.certPEM, 0o600))
+		changed, err := r.Reload()
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		_, err = get(url, server.pool(), client)
+		assert.Error(t, err)
+		resp, err := get(url, server.pool(), newTestCert(t, "agent", false, newCA))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       Config
+		expectErr bool
+	}{
+		{name: "disabled without endpoint", cfg: Config{SampleRatio: 2}},
+		{name: "enabled", cfg: Config{Endpoint: "http://otel-collector:4318", SampleRatio: 0.5}},
+		{name: "sample ratio above 1", cfg: Config{Endpoint: "http://otel-collector:4318", SampleRatio: 1.5}, expectErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prev := otel.GetTracerProvider()
+			t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+			shutdown, err := Setup(context.Background(), tc.cfg)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+// listProbesHandler is a minimal strict server that answers every ListProbes
+// call with an empty list.
+type listProbesHandler struct {
+	v1.StrictServerInterface
+}
+
+func (listProbesHandler) ListProbes(context.Context, v1.ListProbesRequestObject) (v1.ListProbesResponseObject, error) {
+	return v1.ListProbes200JSONResponse{Body: v1.ProbesArrayResponse{Probes: []v1.ProbeObject{}}}, nil
+}
+
+func TestMiddleware_NamesSpanAfterRoute(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	mux := http.NewServeMux()
+	v1.HandlerFromMux(v1.NewStrictHandler(listProbesHandler{}, []v1.StrictMiddlewareFunc{StrictMiddleware}), mux)
+	// Replace the request between the two, as the real middleware chain does,
+	// so the route is only visible to StrictMiddleware.
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mux.ServeHTTP(w, r.WithContext(r.Context()))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/probes", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /probes", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("api.operation", "ListProbes"))
+}
@@ -0,0 +1,380 @@
+// Package webhooks notifies subscribed URLs of probe lifecycle events: probes
+// being created, changing status and being deleted.
+//
+// Each event is POSTed as JSON, signed with the subscription's secret, and
+// retried with exponential backoff. Subscriptions are held in memory, so
+// each replica only notifies the webhooks it was given and only of the
+// changes it handled.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body,
+	// keyed with the subscription's secret.
+	SignatureHeader = "X-Rhobs-Synthetics-Signature"
+	// EventHeader carries the event type.
+	EventHeader = "X-Rhobs-Synthetics-Event"
+	// DeliveryHeader carries the event ID, which stays the same across
+	// retries so receivers can drop duplicates.
+	DeliveryHeader = "X-Rhobs-Synthetics-Delivery"
+
+	// minSecretLength is the shortest secret a subscription may use.
+	minSecretLength = 16
+)
+
+const (
+	// DefaultTimeout is the Timeout used when none is configured.
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxAttempts is the MaxAttempts used when none is configured.
+	DefaultMaxAttempts = 5
+)
+
+// EventTypes are the events a webhook can subscribe to.
+var EventTypes = []v1.WebhookEventType{v1.ProbeCreated, v1.ProbeStatusChanged, v1.ProbeDeleted}
+
+// Config controls event delivery. Zero values select the defaults.
+type Config struct {
+	// Timeout bounds each delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is how often an event is sent before it is given up on.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled for each
+	// further one up to MaxBackoff. Defaults to 1s and 1m.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// QueueSize bounds the deliveries waiting to be sent; events are dropped
+	// while it is full. Defaults to 1000.
+	QueueSize int
+	// Workers is the number of deliveries sent concurrently. Defaults to 4.
+	Workers int
+}
+
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Minute
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = 1000
+	}
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	return c
+}
+
+// Subscription is a URL notified of probe events.
+type Subscription struct {
+	ID  uuid.UUID
+	URL string
+	// Events are the events delivered to the URL; empty means all.
+	Events    []v1.WebhookEventType
+	Secret    string
+	CreatedAt time.Time
+}
+
+// Validate reports whether events could be delivered to the subscription.
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q, expected an absolute http or https URL", s.URL)
+	}
+	if len(s.Secret) < minSecretLength {
+		return fmt.Errorf("webhook secret must be at least %d characters long", minSecretLength)
+	}
+	for _, event := range s.Events {
+		if !slices.Contains(EventTypes, event) {
+			return fmt.Errorf("unknown webhook event %q, expected one of %v", event, EventTypes)
+		}
+	}
+	return nil
+}
+
+// wants reports whether the subscription receives events of the type.
+func (s Subscription) wants(event v1.WebhookEventType) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, event)
+}
+
+// delivery is an event waiting to be sent to one subscription.
+type delivery struct {
+	subscription Subscription
+	event        v1.WebhookEvent
+	body         []byte
+}
+
+// Notifier keeps the webhook subscriptions and delivers events to them.
+type Notifier struct {
+	config atomic.Pointer[Config]
+	client *http.Client
+	queue  chan delivery
+
+	mu            sync.RWMutex
+	subscriptions map[uuid.UUID]Subscription
+}
+
+// NewNotifier creates a Notifier without subscriptions. Events are only sent
+// while Run is running.
+func NewNotifier(cfg Config) *Notifier {
+	cfg = cfg.withDefaults()
+	n := &Notifier{
+		client:        &http.Client{},
+		queue:         make(chan delivery, cfg.QueueSize),
+		subscriptions: make(map[uuid.UUID]Subscription),
+	}
+	n.config.Store(&cfg)
+	return n
+}
+
+// Reconfigure changes the timeout, attempts and backoff of later delivery
+// attempts. The queue size and number of workers are fixed once the
+// Notifier is created and keep their values.
+func (n *Notifier) Reconfigure(cfg Config) {
+	current := n.config.Load()
+	cfg = cfg.withDefaults()
+	cfg.QueueSize, cfg.Workers = current.QueueSize, current.Workers
+	n.config.Store(&cfg)
+}
+
+// Subscribe adds a subscription, giving it a new ID and creation time.
+func (n *Notifier) Subscribe(sub Subscription) (Subscription, error) {
+	if err := sub.Validate(); err != nil {
+		return Subscription{}, err
+	}
+	sub.ID = uuid.New()
+	sub.CreatedAt = clock.Now()
+	sub.Events = slices.Clone(sub.Events)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.subscriptions[sub.ID] = sub
+	return sub, nil
+}
+
+// Replace changes the URL, events and secret of a subscription. ok is false
+// if there is no subscription with the ID.
+func (n *Notifier) Replace(id uuid.UUID, sub Subscription) (updated Subscription, ok bool, err error) {
+	if err := sub.Validate(); err != nil {
+		return Subscription{}, false, err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	existing, ok := n.subscriptions[id]
+	if !ok {
+		return Subscription{}, false, nil
+	}
+	existing.URL = sub.URL
+	existing.Events = slices.Clone(sub.Events)
+	existing.Secret = sub.Secret
+	n.subscriptions[id] = existing
+	return existing, true, nil
+}
+
+// Get returns the subscription with the ID.
+func (n *Notifier) Get(id uuid.UUID) (Subscription, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	sub, ok := n.subscriptions[id]
+	return sub, ok
+}
+
+// List returns every subscription, oldest first.
+func (n *Notifier) List() []Subscription {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	subs := make([]Subscription, 0, len(n.subscriptions))
+	for _, sub := range n.subscriptions {
+		subs = append(subs, sub)
+	}
+	slices.SortFunc(subs, func(a, b Subscription) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return subs
+}
+
+// Unsubscribe removes the subscription with the ID, reporting whether it
+// existed. Deliveries already queued for it are still sent.
+func (n *Notifier) Unsubscribe(id uuid.UUID) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.subscriptions[id]
+	delete(n.subscriptions, id)
+	return ok
+}
+
+// Subscribed reports whether any subscription receives events of the type,
+// so callers can skip work needed only to build the event.
+func (n *Notifier) Subscribed(event v1.WebhookEventType) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, sub := range n.subscriptions {
+		if sub.wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Notify queues a probe event for every subscription that wants it, without
+// waiting for delivery. Events that do not fit in the queue are dropped.
+func (n *Notifier) Notify(ctx context.Context, eventType v1.WebhookEventType, probe v1.ProbeObject, previousStatus *v1.StatusSchema) {
+	event := v1.WebhookEvent{
+		Id:             uuid.New(),
+		Type:           eventType,
+		Timestamp:      clock.Now(),
+		Probe:          probe,
+		PreviousStatus: previousStatus,
+	}
+
+	n.mu.RLock()
+	var subs []Subscription
+	for _, sub := range n.subscriptions {
+		if sub.wants(eventType) {
+			subs = append(subs, sub)
+		}
+	}
+	n.mu.RUnlock()
+	if len(subs) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error encoding webhook event", "event", eventType, "error", err)
+		return
+	}
+	for _, sub := range subs {
+		select {
+		case n.queue <- delivery{subscription: sub, event: event, body: body}:
+		default:
+			metrics.RecordWebhookDelivery(string(eventType), "dropped")
+			slog.WarnContext(ctx, "Dropped webhook event, the delivery queue is full", "event", eventType, "webhook_id", sub.ID)
+		}
+	}
+}
+
+// Run sends queued events until ctx is cancelled. Deliveries still queued or
+// waiting for a retry then are given up on.
+func (n *Notifier) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range n.config.Load().Workers {
+		wg.Go(func() {
+			for {
+				select {
+				case d := <-n.queue:
+					n.deliver(ctx, d)
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// deliver sends the event, retrying with exponential backoff until the
+// webhook accepts it, rejects it as invalid, or the attempts run out.
+func (n *Notifier) deliver(ctx context.Context, d delivery) {
+	backoff := n.config.Load().InitialBackoff
+	for attempt := 1; ; attempt++ {
+		cfg := n.config.Load()
+		retry, err := n.send(ctx, d, cfg.Timeout)
+		if err == nil {
+			metrics.RecordWebhookDelivery(string(d.event.Type), "success")
+			return
+		}
+		if !retry || attempt >= cfg.MaxAttempts {
+			metrics.RecordWebhookDelivery(string(d.event.Type), "failure")
+			slog.WarnContext(ctx, "Giving up on webhook delivery", "event", d.event.Type, "event_id", d.event.Id, "webhook_id", d.subscription.ID, "attempts", attempt, "error", err)
+			return
+		}
+		slog.DebugContext(ctx, "Retrying webhook delivery", "event", d.event.Type, "event_id", d.event.Id, "webhook_id", d.subscription.ID, "attempt", attempt, "backoff", backoff, "error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
+
+// send makes one delivery attempt, giving up after timeout. retry is false
+// when sending the same request again cannot succeed.
+func (n *Notifier) send(ctx context.Context, d delivery, timeout time.Duration) (retry bool, err error) {
+	start := time.Now()
+	defer func() { metrics.RecordWebhookAttempt(start, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.subscription.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(d.event.Type))
+	req.Header.Set(DeliveryHeader, d.event.Id.String())
+	req.Header.Set(SignatureHeader, Sign(d.subscription.Secret, d.body))
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close() //nolint:errcheck
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return false, nil
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusRequestTimeout || res.StatusCode >= 500:
+		return true, fmt.Errorf("webhook answered %s", res.Status)
+	default:
+		return false, fmt.Errorf("webhook answered %s", res.Status)
+	}
+}
+
+// Sign returns the signature header value of a delivery body: "sha256="
+// followed by the hex HMAC-SHA256 of the body keyed with the secret.
+// Receivers compute the same value and compare it in constant time.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
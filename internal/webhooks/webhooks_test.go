@@ -0,0 +1,240 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "0123456789abcdef"
+
+// receiver is a webhook endpoint that answers with the given status codes in
+// turn, then 200, and records what it received.
+type receiver struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	code := http.StatusOK
+	if len(r.codes) > 0 {
+		code, r.codes = r.codes[0], r.codes[1:]
+	}
+	w.WriteHeader(code)
+}
+
+func (r *receiver) received() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+// startNotifier runs a notifier with fast retries until the test ends.
+func startNotifier(t *testing.T) *Notifier {
+	n := NewNotifier(Config{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxAttempts: 3})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		n.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return n
+}
+
+func TestSubscription_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		sub         Subscription
+		expectedErr string
+	}{
+		{name: "valid", sub: Subscription{URL: "https://hooks.example.com/x", Secret: testSecret, Events: []v1.WebhookEventType{v1.ProbeDeleted}}},
+		{name: "all events", sub: Subscription{URL: "http://hooks.example.com", Secret: testSecret}},
+		{name: "relative url", sub: Subscription{URL: "/hooks", Secret: testSecret}, expectedErr: `invalid webhook url "/hooks", expected an absolute http or https URL`},
+		{name: "other scheme", sub: Subscription{URL: "ftp://hooks.example.com", Secret: testSecret}, expectedErr: `invalid webhook url "ftp://hooks.example.com", expected an absolute http or https URL`},
+		{name: "short secret", sub: Subscription{URL: "https://hooks.example.com", Secret: "short"}, expectedErr: "webhook secret must be at least 16 characters long"},
+		{
+			name:        "unknown event",
+			sub:         Subscription{URL: "https://hooks.example.com", Secret: testSecret, Events: []v1.WebhookEventType{"probe.updated"}},
+			expectedErr: `unknown webhook event "probe.updated", expected one of [probe.created probe.status_changed probe.deleted]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sub.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestNotifier_Subscriptions(t *testing.T) {
+	n := NewNotifier(Config{})
+
+	first, err := n.Subscribe(Subscription{URL: "https://a.example.com", Secret: testSecret})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+	second, err := n.Subscribe(Subscription{URL: "https://b.example.com", Secret: testSecret, Events: []v1.WebhookEventType{v1.ProbeDeleted}})
+	require.NoError(t, err)
+	_, err = n.Subscribe(Subscription{URL: "not a url", Secret: testSecret})
+	assert.Error(t, err)
+
+	assert.Equal(t, []Subscription{first, second}, n.List())
+	assert.True(t, n.Subscribed(v1.ProbeCreated))
+
+	updated, ok, err := n.Replace(first.ID, Subscription{URL: "https://c.example.com", Secret: testSecret, Events: []v1.WebhookEventType{v1.ProbeDeleted}})
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, first.ID, updated.ID)
+	assert.Equal(t, first.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, "https://c.example.com", updated.URL)
+	got, ok := n.Get(first.ID)
+	require.True(t, ok)
+	assert.Equal(t, updated, got)
+	assert.False(t, n.Subscribed(v1.ProbeCreated), "no subscription wants created events anymore")
+
+	_, ok, err = n.Replace(uuid.New(), Subscription{URL: "https://c.example.com", Secret: testSecret})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, n.Unsubscribe(first.ID))
+	assert.False(t, n.Unsubscribe(first.ID))
+	_, ok = n.Get(first.ID)
+	assert.False(t, ok)
+	assert.Equal(t, []Subscription{second}, n.List())
+}
+
+func TestNotifier_Deliver(t *testing.T) {
+	rcv := &receiver{}
+	ts := httptest.NewServer(rcv)
+	defer ts.Close()
+	n := startNotifier(t)
+	_, err := n.Subscribe(Subscription{URL: ts.URL, Secret: testSecret, Events: []v1.WebhookEventType{v1.ProbeStatusChanged}})
+	require.NoError(t, err)
+
+	probe := v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}
+	previous := v1.Pending
+	n.Notify(context.Background(), v1.ProbeCreated, probe, nil)
+	n.Notify(context.Background(), v1.ProbeStatusChanged, probe, &previous)
+
+	require.Eventually(t, func() bool { return rcv.received() == 1 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, 1, rcv.received(), "the created event is not subscribed to")
+
+	req, body := rcv.requests[0], rcv.bodies[0]
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "probe.status_changed", req.Header.Get(EventHeader))
+	assert.Equal(t, Sign(testSecret, body), req.Header.Get(SignatureHeader))
+
+	var event v1.WebhookEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, req.Header.Get(DeliveryHeader), event.Id.String())
+	assert.Equal(t, v1.ProbeStatusChanged, event.Type)
+	assert.Equal(t, probe, event.Probe)
+	require.NotNil(t, event.PreviousStatus)
+	assert.Equal(t, v1.Pending, *event.PreviousStatus)
+}
+
+func TestNotifier_Retry(t *testing.T) {
+	testCases := []struct {
+		name             string
+		codes            []int
+		expectedAttempts int
+	}{
+		{name: "succeeds after server errors", codes: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}, expectedAttempts: 3},
+		{name: "gives up after max attempts", codes: []int{500, 500, 500, 500}, expectedAttempts: 3},
+		{name: "client errors are not retried", codes: []int{http.StatusBadRequest}, expectedAttempts: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rcv := &receiver{codes: tc.codes}
+			ts := httptest.NewServer(rcv)
+			defer ts.Close()
+			n := startNotifier(t)
+			_, err := n.Subscribe(Subscription{URL: ts.URL, Secret: testSecret})
+			require.NoError(t, err)
+
+			n.Notify(context.Background(), v1.ProbeDeleted, v1.ProbeObject{Id: uuid.New()}, nil)
+
+			require.Eventually(t, func() bool { return rcv.received() == tc.expectedAttempts }, 5*time.Second, time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(t, tc.expectedAttempts, rcv.received())
+			ids := map[string]bool{}
+			for _, req := range rcv.requests {
+				ids[req.Header.Get(DeliveryHeader)] = true
+			}
+			assert.Len(t, ids, 1, "retries keep the delivery ID")
+		})
+	}
+}
+
+func TestNotifier_Reconfigure(t *testing.T) {
+	rcv := &receiver{codes: []int{500, 500, 500}}
+	ts := httptest.NewServer(rcv)
+	defer ts.Close()
+	n := startNotifier(t)
+	n.Reconfigure(Config{MaxAttempts: 1, QueueSize: 1, Workers: 1})
+	_, err := n.Subscribe(Subscription{URL: ts.URL, Secret: testSecret})
+	require.NoError(t, err)
+
+	n.Notify(context.Background(), v1.ProbeDeleted, v1.ProbeObject{Id: uuid.New()}, nil)
+
+	require.Eventually(t, func() bool { return rcv.received() == 1 }, 5*time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, rcv.received())
+	cfg := n.config.Load()
+	assert.Equal(t, 1000, cfg.QueueSize, "the queue size is kept")
+	assert.Equal(t, 4, cfg.Workers, "the workers are kept")
+}
+
+func TestNotifier_QueueFull(t *testing.T) {
+	n := NewNotifier(Config{QueueSize: 1})
+	_, err := n.Subscribe(Subscription{URL: "https://hooks.example.com", Secret: testSecret})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		for range 3 {
+			n.Notify(context.Background(), v1.ProbeCreated, v1.ProbeObject{Id: uuid.New()}, nil)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notify blocked on a full queue")
+	}
+	assert.Len(t, n.queue, 1)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"a":1}' | openssl dgst -sha256 -hmac 0123456789abcdef
+	assert.Equal(t, "sha256=1c76fbb930d3aa8b1633728fae212c68ca68a6752bb319b98be6fe17e86cf5b8", Sign(testSecret, []byte(`{"a":1}`)))
+}
@@ -0,0 +1,125 @@
+// Package writelimit bounds how many probe writes run against the store at
+// once. Bursts of creates, updates and deletes, such as a mass onboarding,
+// wait in a bounded queue instead of reaching the backend all together, which
+// the ConfigMap backend in particular cannot absorb. Reads are never limited.
+package writelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/readonly"
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+)
+
+// Defaults of the queue in front of the limit.
+const (
+	DefaultMaxQueued    = 100
+	DefaultQueueTimeout = 5 * time.Second
+)
+
+// Config sizes the limit and its queue.
+type Config struct {
+	// MaxConcurrent is the number of probe writes run at once; zero
+	// disables the limit.
+	MaxConcurrent int
+	// MaxQueued is the number of writes that may wait for one of the
+	// others to finish; further writes are rejected at once.
+	MaxQueued int
+	// QueueTimeout is how long a write waits before it is rejected.
+	QueueTimeout time.Duration
+}
+
+// Validate reports settings the limit cannot run with.
+func (c Config) Validate() error {
+	if c.MaxConcurrent < 0 {
+		return fmt.Errorf("--max-concurrent-writes must not be negative, got %d", c.MaxConcurrent)
+	}
+	if c.MaxQueued < 0 {
+		return fmt.Errorf("--write-queue-size must not be negative, got %d", c.MaxQueued)
+	}
+	if c.MaxConcurrent > 0 && c.QueueTimeout <= 0 {
+		return fmt.Errorf("--write-queue-timeout must be positive, got %s", c.QueueTimeout)
+	}
+	return nil
+}
+
+// IsProbeWrite reports whether the request creates, imports, updates or
+// deletes probes. Probe results are kept in memory and are not counted.
+func IsProbeWrite(r *http.Request) bool {
+	if !readonly.IsWrite(r) {
+		return false
+	}
+	path := r.URL.Path
+	return path == "/probes" || strings.HasPrefix(path, "/probes/") && !strings.HasSuffix(path, "/results")
+}
+
+// Middleware runs at most cfg.MaxConcurrent probe writes at once, and
+// answers writes that find the queue full, or wait longer than
+// cfg.QueueTimeout, with 503. Other requests pass through, as does every
+// request when the limit is disabled.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.MaxConcurrent <= 0 {
+			return next
+		}
+		slots := make(chan struct{}, cfg.MaxConcurrent)
+		var queued atomic.Int64
+		record := func() { metrics.RecordProbeWriteLoad(len(slots), int(queued.Load())) }
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !IsProbeWrite(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			select {
+			case slots <- struct{}{}:
+			default:
+				if queued.Add(1) > int64(cfg.MaxQueued) {
+					queued.Add(-1)
+					metrics.RecordProbeWriteRejected("queue_full")
+					saturated(w)
+					return
+				}
+				record()
+				timer := time.NewTimer(cfg.QueueTimeout)
+				var acquired bool
+				select {
+				case slots <- struct{}{}:
+					acquired = true
+				case <-timer.C:
+					metrics.RecordProbeWriteRejected("timeout")
+				case <-r.Context().Done():
+					metrics.RecordProbeWriteRejected("canceled")
+				}
+				timer.Stop()
+				queued.Add(-1)
+				if !acquired {
+					record()
+					saturated(w)
+					return
+				}
+			}
+			record()
+			defer func() {
+				<-slots
+				record()
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// saturatedBody is the error returned for writes that found no free slot.
+var saturatedBody = fmt.Sprintf(`{"error":{"message":"too many probe writes are in progress; retry later","retry_after_seconds":%d}}`,
+	*retryafter.Seconds(http.StatusServiceUnavailable))
+
+func saturated(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_, _ = w.Write([]byte(saturatedBody))
+}
@@ -0,0 +1,85 @@
+package writelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingHandler holds probe writes until released, signalling each one
+// that starts.
+func blockingHandler(cfg Config) (http.Handler, chan struct{}, chan struct{}) {
+	release, started := make(chan struct{}), make(chan struct{}, 10)
+	return Middleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsProbeWrite(r) {
+			started <- struct{}{}
+			<-release
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})), release, started
+}
+
+func serve(handler http.Handler, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	handler, release, started := blockingHandler(Config{MaxConcurrent: 1, QueueTimeout: time.Second})
+	var wg sync.WaitGroup
+	wg.Go(func() { assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodPost, "/probes").Code) })
+	<-started
+
+	// Without a queue, further writes are rejected at once.
+	w := serve(handler, http.MethodPatch, "/probes/1")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"retry_after_seconds":10`)
+
+	// Reads, agents and probe results are not limited.
+	assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodGet, "/probes").Code)
+	assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodPost, "/probes/1/results").Code)
+	assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodPut, "/agents/a").Code)
+
+	release <- struct{}{}
+	wg.Wait()
+	go func() { release <- struct{}{} }()
+	assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodDelete, "/probes/1").Code)
+}
+
+func TestMiddleware_Queue(t *testing.T) {
+	handler, release, started := blockingHandler(Config{MaxConcurrent: 1, MaxQueued: 1, QueueTimeout: 5 * time.Second})
+	var wg sync.WaitGroup
+	wg.Go(func() { assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodPost, "/probes").Code) })
+	<-started
+
+	// The queued write runs once the first one finished.
+	wg.Go(func() { assert.Equal(t, http.StatusNoContent, serve(handler, http.MethodDelete, "/probes/1").Code) })
+	release <- struct{}{}
+	<-started
+	release <- struct{}{}
+	wg.Wait()
+}
+
+func TestMiddleware_QueueTimeout(t *testing.T) {
+	handler, release, started := blockingHandler(Config{MaxConcurrent: 1, MaxQueued: 10, QueueTimeout: 20 * time.Millisecond})
+	var wg sync.WaitGroup
+	wg.Go(func() { serve(handler, http.MethodPost, "/probes") })
+	<-started
+
+	assert.Equal(t, http.StatusServiceUnavailable, serve(handler, http.MethodPost, "/probes").Code)
+	release <- struct{}{}
+	wg.Wait()
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{MaxConcurrent: 4, QueueTimeout: time.Second}.Validate())
+	assert.ErrorContains(t, Config{MaxConcurrent: -1}.Validate(), "--max-concurrent-writes")
+	assert.ErrorContains(t, Config{MaxQueued: -1}.Validate(), "--write-queue-size")
+	assert.ErrorContains(t, Config{MaxConcurrent: 4}.Validate(), "--write-queue-timeout")
+}
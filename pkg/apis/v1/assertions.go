@@ -0,0 +1,153 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Validate checks the assertions of an http probe: status codes, classes or
+// ranges of valid statuses, regular expressions RE2 compiles, headers named
+// once, and a certificate expiry threshold between 1 and 365 days.
+func (a AssertionsSchema) Validate() error {
+	if a.StatusCodes != nil {
+		if len(*a.StatusCodes) == 0 {
+			return errors.New("assertions status_codes cannot be empty, leave it out to accept any 2xx")
+		}
+		for i, codes := range *a.StatusCodes {
+			if _, _, err := statusRange(codes); err != nil {
+				return fmt.Errorf("assertions status_codes[%d]: %w", i, err)
+			}
+		}
+	}
+	if a.BodyRegex != nil {
+		if _, err := regexp.Compile(*a.BodyRegex); err != nil {
+			return fmt.Errorf("invalid assertions body_regex: %w", err)
+		}
+	}
+	if a.Headers != nil {
+		headers := *a.Headers
+		for i, header := range headers {
+			if header.Name == "" || strings.ContainsFunc(header.Name, func(r rune) bool { return !isTokenChar(r) }) {
+				return fmt.Errorf("invalid assertions headers[%d] name %q", i, header.Name)
+			}
+			for _, other := range headers[:i] {
+				if strings.EqualFold(other.Name, header.Name) {
+					return fmt.Errorf("assertions headers[%d] %q is listed twice", i, header.Name)
+				}
+			}
+			if header.ValueRegex != nil {
+				if _, err := regexp.Compile(*header.ValueRegex); err != nil {
+					return fmt.Errorf("invalid assertions headers[%d] value_regex: %w", i, err)
+				}
+			}
+		}
+	}
+	if a.TlsExpiryDays != nil && (*a.TlsExpiryDays < 1 || *a.TlsExpiryDays > 365) {
+		return errors.New("assertions tls_expiry_days must be between 1 and 365")
+	}
+	return nil
+}
+
+// IsZero reports whether the assertions assert nothing, as an empty object
+// sent to remove them does.
+func (a AssertionsSchema) IsZero() bool {
+	return a.StatusCodes == nil && a.BodyRegex == nil && a.Headers == nil && a.TlsExpiryDays == nil
+}
+
+// MatchesStatus reports whether a response status passes the assertions:
+// whether it is one of status_codes, or any 2xx when they leave it out.
+// Agents use it so that every agent reads the codes the same way.
+func (a AssertionsSchema) MatchesStatus(code int) bool {
+	if a.StatusCodes == nil {
+		return code >= 200 && code <= 299
+	}
+	for _, codes := range *a.StatusCodes {
+		low, high, err := statusRange(codes)
+		if err == nil && code >= low && code <= high {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRange returns the first and last status of a StatusCodeRangeSchema.
+func statusRange(codes StatusCodeRangeSchema) (low, high int, err error) {
+	invalid := fmt.Errorf("invalid status code range %q, expected a code such as 200, a class such as 2xx or a range such as 200-399", codes)
+	parse := func(s string) (int, error) {
+		code, err := strconv.Atoi(s)
+		if err != nil || len(s) != 3 || code < 100 || code > 599 {
+			return 0, invalid
+		}
+		return code, nil
+	}
+	if class, ok := strings.CutSuffix(codes, "xx"); ok {
+		low, err := parse(class + "00")
+		return low, low + 99, err
+	}
+	first, last, isRange := strings.Cut(codes, "-")
+	if low, err = parse(first); err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return low, low, nil
+	}
+	if high, err = parse(last); err != nil {
+		return 0, 0, err
+	}
+	if high < low {
+		return 0, 0, fmt.Errorf("status code range %q ends before it starts", codes)
+	}
+	return low, high, nil
+}
+
+// isTokenChar reports whether r may appear in an HTTP header name.
+func isTokenChar(r rune) bool {
+	return r < 127 && r > 32 && !strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r)
+}
+
+// ValidateAssertions checks the assertions of the probe, if it has any:
+// their own constraints, that the probe is an http probe, whose module is
+// http_2xx or left to the server, and that its static URL is https when the
+// certificate expiry is checked.
+func (p ProbeObject) ValidateAssertions() error {
+	if p.Assertions == nil {
+		return nil
+	}
+	if err := p.Assertions.Validate(); err != nil {
+		return err
+	}
+	if settings, _ := p.TypeSettings(); settings != nil {
+		return fmt.Errorf("assertions only apply to http probes, not %s probes", settings.Module())
+	}
+	if p.Module != nil && *p.Module != Http2xx {
+		return fmt.Errorf("assertions only apply to probes of the %s module, not %s", Http2xx, *p.Module)
+	}
+	if p.Assertions.TlsExpiryDays != nil {
+		if u, err := url.Parse(p.StaticUrl); err != nil || u.Scheme != "https" {
+			return fmt.Errorf("assertions tls_expiry_days requires an https static_url, got %q", p.StaticUrl)
+		}
+	}
+	return nil
+}
+
+// clone returns a copy of the assertions that shares nothing with them.
+func (a AssertionsSchema) clone() *AssertionsSchema {
+	if a.StatusCodes != nil {
+		a.StatusCodes = new(slices.Clone(*a.StatusCodes))
+	}
+	if a.BodyRegex != nil {
+		a.BodyRegex = new(*a.BodyRegex)
+	}
+	if a.Headers != nil {
+		a.Headers = new(slices.Clone(*a.Headers))
+	}
+	if a.TlsExpiryDays != nil {
+		a.TlsExpiryDays = new(*a.TlsExpiryDays)
+	}
+	return &a
+}
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertions(t *testing.T) {
+	assertions := AssertionsSchema{
+		StatusCodes:   &[]StatusCodeRangeSchema{"200", "3xx", "401-403"},
+		BodyRegex:     new(`"status":\s*"ok"`),
+		Headers:       &[]HeaderAssertion{{Name: "Content-Type", ValueRegex: new("^application/json")}, {Name: "X-Request-Id"}},
+		TlsExpiryDays: new(14),
+	}
+	require.NoError(t, assertions.Validate())
+	for code, expected := range map[int]bool{200: true, 201: false, 302: true, 402: true, 404: false} {
+		assert.Equal(t, expected, assertions.MatchesStatus(code), code)
+	}
+	assert.True(t, AssertionsSchema{}.MatchesStatus(204), "any 2xx by default")
+	assert.False(t, AssertionsSchema{}.MatchesStatus(301))
+	assert.True(t, AssertionsSchema{}.IsZero())
+	assert.False(t, assertions.IsZero())
+
+	for name, tc := range map[string]struct {
+		assertions AssertionsSchema
+		err        string
+	}{
+		"no status codes":    {AssertionsSchema{StatusCodes: &[]StatusCodeRangeSchema{}}, "status_codes cannot be empty"},
+		"unknown code":       {AssertionsSchema{StatusCodes: &[]StatusCodeRangeSchema{"700"}}, `invalid status code range "700"`},
+		"unknown class":      {AssertionsSchema{StatusCodes: &[]StatusCodeRangeSchema{"6xx"}}, `invalid status code range "6xx"`},
+		"backwards range":    {AssertionsSchema{StatusCodes: &[]StatusCodeRangeSchema{"399-200"}}, "ends before it starts"},
+		"invalid body regex": {AssertionsSchema{BodyRegex: new("(")}, "invalid assertions body_regex"},
+		"invalid header":     {AssertionsSchema{Headers: &[]HeaderAssertion{{Name: "Content Type"}}}, `invalid assertions headers[0] name "Content Type"`},
+		"duplicate header":   {AssertionsSchema{Headers: &[]HeaderAssertion{{Name: "Server"}, {Name: "server"}}}, `assertions headers[1] "server" is listed twice`},
+		"zero expiry":        {AssertionsSchema{TlsExpiryDays: new(0)}, "tls_expiry_days must be between 1 and 365"},
+	} {
+		assert.ErrorContains(t, tc.assertions.Validate(), tc.err, name)
+	}
+
+	probe, err := NewProbe("https://example.com").Assertions(assertions).Build()
+	require.NoError(t, err)
+	(*assertions.StatusCodes)[0] = "500"
+	assert.Equal(t, "200", (*probe.Assertions.StatusCodes)[0], "built probes do not share the assertions")
+
+	_, err = NewProbe("http://example.com").Assertions(AssertionsSchema{TlsExpiryDays: new(7)}).Build()
+	assert.ErrorContains(t, err, `tls_expiry_days requires an https static_url, got "http://example.com"`)
+	_, err = NewProbe("https://example.com").Module(Tcp).Assertions(AssertionsSchema{BodyRegex: new("ok")}).Build()
+	assert.ErrorContains(t, err, "assertions only apply to probes of the http_2xx module, not tcp")
+	_, err = NewICMPProbe(IcmpSchema{Host: "203.0.113.7"}).Assertions(AssertionsSchema{BodyRegex: new("ok")}).Build()
+	assert.ErrorContains(t, err, "assertions only apply to http probes, not icmp probes")
+}
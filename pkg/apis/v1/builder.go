@@ -0,0 +1,278 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProbeBuilder builds a ProbeObject, checking it against the spec. The zero
+// value is not usable; start from NewProbe.
+type ProbeBuilder struct {
+	probe ProbeObject
+}
+
+// NewProbe starts a pending probe of staticURL with a new ID.
+func NewProbe(staticURL string) *ProbeBuilder {
+	return &ProbeBuilder{probe: ProbeObject{
+		Id:        uuid.New(),
+		StaticUrl: staticURL,
+		Status:    Pending,
+	}}
+}
+
+// NewDNSProbe starts a pending dns probe of the query with a new ID. Its
+// static URL is derived from the query.
+func NewDNSProbe(dns DnsSchema) *ProbeBuilder {
+	return newTypedProbe(dns)
+}
+
+// NewTCPProbe starts a pending tcp probe of the address with a new ID. Its
+// static URL is derived from the address.
+func NewTCPProbe(tcp TcpSchema) *ProbeBuilder {
+	return newTypedProbe(tcp)
+}
+
+// NewICMPProbe starts a pending icmp probe of the host with a new ID. Its
+// static URL is derived from the host.
+func NewICMPProbe(icmp IcmpSchema) *ProbeBuilder {
+	return newTypedProbe(icmp)
+}
+
+func newTypedProbe(settings ProbeSettings) *ProbeBuilder {
+	b := NewProbe(settings.URL()).Module(settings.Module())
+	b.probe.SetTypeSettings(settings)
+	return b
+}
+
+// ID replaces the generated ID of the probe.
+func (b *ProbeBuilder) ID(id ProbeIdSchema) *ProbeBuilder {
+	b.probe.Id = id
+	return b
+}
+
+// Status sets the status of the probe.
+func (b *ProbeBuilder) Status(status StatusSchema) *ProbeBuilder {
+	b.probe.Status = status
+	return b
+}
+
+// Label sets one label of the probe.
+func (b *ProbeBuilder) Label(key, value string) *ProbeBuilder {
+	if b.probe.Labels == nil {
+		b.probe.Labels = &LabelsSchema{}
+	}
+	(*b.probe.Labels)[key] = value
+	return b
+}
+
+// Labels sets labels of the probe, keeping those already set under other keys.
+func (b *ProbeBuilder) Labels(labels map[string]string) *ProbeBuilder {
+	for key, value := range labels {
+		b.Label(key, value)
+	}
+	return b
+}
+
+// Schedule sets how often the probe runs and how long a run may take.
+func (b *ProbeBuilder) Schedule(interval, timeout time.Duration) *ProbeBuilder {
+	b.probe.Interval = new(interval.String())
+	b.probe.Timeout = new(timeout.String())
+	return b
+}
+
+// Module sets the blackbox exporter module the probe is run with.
+func (b *ProbeBuilder) Module(module ProbeModuleSchema) *ProbeBuilder {
+	b.probe.Module = &module
+	return b
+}
+
+// Alerting sets the routing metadata of the probe's alerts.
+func (b *ProbeBuilder) Alerting(alerting AlertingSchema) *ProbeBuilder {
+	b.probe.Alerting = &alerting
+	return b
+}
+
+// Owner sets who owns the probe and where its alerts are escalated.
+func (b *ProbeBuilder) Owner(owner OwnerSchema) *ProbeBuilder {
+	b.probe.Owner = &owner
+	return b
+}
+
+// Assertions sets what the responses of the probe's target must look like.
+func (b *ProbeBuilder) Assertions(assertions AssertionsSchema) *ProbeBuilder {
+	b.probe.Assertions = &assertions
+	return b
+}
+
+// Regions limits the probe to agents in the given regions.
+func (b *ProbeBuilder) Regions(regions ...RegionSchema) *ProbeBuilder {
+	b.probe.Regions = new(slices.Clone(regions))
+	return b
+}
+
+// Build returns the probe, or the first constraint of the spec it breaks.
+// The builder may be reused; later changes do not affect returned probes.
+func (b *ProbeBuilder) Build() (ProbeObject, error) {
+	probe := b.probe
+	if probe.Labels != nil {
+		probe.Labels = new(maps.Clone(*probe.Labels))
+	}
+	if probe.Alerting != nil {
+		probe.Alerting = new(*probe.Alerting)
+	}
+	if probe.Owner != nil {
+		probe.Owner = new(*probe.Owner)
+	}
+	if probe.Assertions != nil {
+		probe.Assertions = probe.Assertions.clone()
+	}
+	if probe.Regions != nil {
+		probe.Regions = new(slices.Clone(*probe.Regions))
+	}
+	if settings, err := probe.TypeSettings(); err == nil && settings != nil {
+		probe.SetTypeSettings(settings)
+	}
+	if err := probe.Validate(); err != nil {
+		return ProbeObject{}, err
+	}
+	return probe, nil
+}
+
+// CreateRequest returns the request creating the probe, or the first
+// constraint of the spec it breaks. The ID and status are assigned by the
+// server and left out, as is the static URL of dns, tcp and icmp probes, which
+// the server derives from their settings.
+func (b *ProbeBuilder) CreateRequest() (CreateProbeRequest, error) {
+	probe, err := b.Build()
+	if err != nil {
+		return CreateProbeRequest{}, err
+	}
+	request := CreateProbeRequest{
+		StaticUrl:  probe.StaticUrl,
+		Labels:     probe.Labels,
+		Interval:   probe.Interval,
+		Timeout:    probe.Timeout,
+		Module:     probe.Module,
+		Alerting:   probe.Alerting,
+		Owner:      probe.Owner,
+		Assertions: probe.Assertions,
+		Dns:        probe.Dns,
+		Tcp:        probe.Tcp,
+		Icmp:       probe.Icmp,
+		Regions:    probe.Regions,
+	}
+	if settings, _ := probe.TypeSettings(); settings != nil {
+		request.StaticUrl = ""
+	}
+	return request, nil
+}
+
+// Validate checks the probe against the constraints of the spec the server
+// enforces: a static URL, known status and module, a positive timeout no
+// longer than the interval, valid alerting metadata, owner, assertions and
+// regions, and for dns, tcp and icmp probes valid settings of a single type,
+// with its module and the static URL derived from them. Fields the server
+// sets are checked only when present.
+func (p ProbeObject) Validate() error {
+	if p.StaticUrl == "" {
+		return errors.New("static_url is required")
+	}
+	if _, err := url.Parse(p.StaticUrl); err != nil {
+		return fmt.Errorf("invalid static_url %q: %w", p.StaticUrl, err)
+	}
+	if !p.Status.Valid() {
+		return fmt.Errorf("invalid probe status %q, expected one of %v", p.Status, ProbeStatuses())
+	}
+	if p.Module != nil && !p.Module.Valid() {
+		return fmt.Errorf("unknown probe module %q, expected one of %v", *p.Module, ProbeModules())
+	}
+	if err := validateSchedule(p.Interval, p.Timeout); err != nil {
+		return err
+	}
+	if p.Alerting != nil {
+		if err := p.Alerting.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Owner != nil {
+		if err := p.Owner.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := p.ValidateAssertions(); err != nil {
+		return err
+	}
+	if p.Regions != nil {
+		if err := ValidateRegions(*p.Regions); err != nil {
+			return err
+		}
+	}
+	settings, err := p.TypeSettings()
+	if err != nil {
+		return err
+	}
+	if settings != nil {
+		if err := settings.Validate(); err != nil {
+			return err
+		}
+		if p.Module != nil && *p.Module != settings.Module() {
+			return fmt.Errorf("%s settings require the %s module, not %s", settings.Module(), settings.Module(), *p.Module)
+		}
+		if p.StaticUrl != settings.URL() {
+			return fmt.Errorf("static_url %q of a %s probe must be %q, derived from its settings", p.StaticUrl, settings.Module(), settings.URL())
+		}
+	}
+	if p.UrlHash != nil && !urlHashPattern().MatchString(*p.UrlHash) {
+		return fmt.Errorf("invalid url_hash %q, expected a hex SHA-256", *p.UrlHash)
+	}
+	return nil
+}
+
+// validateSchedule checks the interval and timeout of a probe, either of
+// which may be left for the server to default.
+func validateSchedule(interval, timeout *DurationSchema) error {
+	parse := func(field string, value *DurationSchema) (time.Duration, error) {
+		if value == nil {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(*value)
+		if err != nil || !IsDuration(*value) || d <= 0 {
+			return 0, fmt.Errorf("invalid %s %q, expected a positive duration such as 30s", field, *value)
+		}
+		return d, nil
+	}
+	i, err := parse("interval", interval)
+	if err != nil {
+		return err
+	}
+	t, err := parse("timeout", timeout)
+	if err != nil {
+		return err
+	}
+	if i > 0 && t > i {
+		return fmt.Errorf("probe timeout %s is longer than the interval %s", t, i)
+	}
+	return nil
+}
+
+// Validate checks the alert routing metadata. Agents copy it into target
+// labels, so the runbook must be a link alert receivers can open.
+func (a AlertingSchema) Validate() error {
+	if a.Severity != nil && !a.Severity.Valid() {
+		return fmt.Errorf("unknown severity %q, expected one of %v", *a.Severity, Severities())
+	}
+	if a.RunbookUrl == nil {
+		return nil
+	}
+	u, err := url.Parse(*a.RunbookUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("runbook_url %q must be an absolute http or https URL", *a.RunbookUrl)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaAccessors(t *testing.T) {
+	assert.Equal(t, []StatusSchema{Pending, Active, Failed, Terminating, Deleted}, ProbeStatuses())
+	assert.Equal(t, []ProbeModuleSchema{Http2xx, Tcp, Icmp, Dns}, ProbeModules())
+	assert.ElementsMatch(t, []SeveritySchema{Critical, Warning, Info}, Severities())
+	assert.Equal(t, []DnsRecordTypeSchema{A, AAAA, CNAME, MX, NS, PTR, SOA, SRV, TXT, CAA}, DnsRecordTypes())
+	assert.ElementsMatch(t, []WebhookEventType{ProbeCreated, ProbeStatusChanged, ProbeDeleted}, WebhookEventTypes())
+	assert.True(t, Active.Valid())
+	assert.False(t, StatusSchema("running").Valid())
+
+	c, ok := SchemaConstraints("DurationSchema")
+	require.True(t, ok)
+	assert.NotEmpty(t, c.Pattern)
+	assert.True(t, IsDuration("1m30s"))
+	assert.False(t, IsDuration("90"))
+	_, ok = SchemaConstraints("NoSuchSchema")
+	assert.False(t, ok)
+}
+
+func TestProbeBuilder(t *testing.T) {
+	b := NewProbe("https://example.com").
+		Label("team", "observability").
+		Schedule(time.Minute, 10*time.Second).
+		Module(Http2xx).
+		Alerting(AlertingSchema{Severity: new(Critical), RunbookUrl: new("https://runbooks.example.com/api")})
+	probe, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, Pending, probe.Status)
+	assert.Equal(t, "1m0s", *probe.Interval)
+	assert.Equal(t, "10s", *probe.Timeout)
+
+	// Changing the builder leaves probes already built alone.
+	b.Label("team", "other")
+	assert.Equal(t, "observability", (*probe.Labels)["team"])
+
+	request, err := b.CreateRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", request.StaticUrl)
+	assert.Equal(t, "other", (*request.Labels)["team"])
+
+	for name, tc := range map[string]struct {
+		builder *ProbeBuilder
+		err     string
+	}{
+		"no URL":           {NewProbe(""), "static_url is required"},
+		"unknown status":   {NewProbe("https://example.com").Status("running"), `invalid probe status "running"`},
+		"unknown module":   {NewProbe("https://example.com").Module("grpc"), `unknown probe module "grpc"`},
+		"zero interval":    {NewProbe("https://example.com").Schedule(0, time.Second), `invalid interval "0s"`},
+		"timeout too long": {NewProbe("https://example.com").Schedule(time.Second, time.Minute), "probe timeout 1m0s is longer than the interval 1s"},
+		"unknown severity": {NewProbe("https://example.com").Alerting(AlertingSchema{Severity: new(SeveritySchema("page"))}), `unknown severity "page"`},
+		"relative runbook": {NewProbe("https://example.com").Alerting(AlertingSchema{RunbookUrl: new("/runbooks")}), "must be an absolute http or https URL"},
+	} {
+		_, err := tc.builder.Build()
+		assert.ErrorContains(t, err, tc.err, name)
+	}
+
+	bad := probe
+	bad.UrlHash = new("not-a-hash")
+	assert.ErrorContains(t, bad.Validate(), "invalid url_hash")
+}
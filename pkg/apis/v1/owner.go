@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// maxEscalationContact is the maxLength of OwnerSchema's escalation_contact.
+const maxEscalationContact = 256
+
+// Validate checks the owner of a probe. Agents copy the team into a target
+// label, so it must be usable as a label value.
+func (o OwnerSchema) Validate() error {
+	if o.Team != nil && !ownerTeamPattern().MatchString(*o.Team) {
+		return fmt.Errorf("invalid owner.team %q, expected a name of letters, digits, '-', '_' and '.' of at most 63 characters", *o.Team)
+	}
+	if o.EscalationContact != nil {
+		if n := utf8.RuneCountInString(*o.EscalationContact); n == 0 || n > maxEscalationContact {
+			return fmt.Errorf("owner.escalation_contact must be 1 to %d characters long, got %d", maxEscalationContact, n)
+		}
+	}
+	return nil
+}
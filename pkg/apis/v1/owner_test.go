@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwner(t *testing.T) {
+	owner := OwnerSchema{Team: new("sre-platform"), EscalationContact: new("sre-oncall@example.com")}
+	probe, err := NewProbe("https://example.com").Owner(owner).Build()
+	require.NoError(t, err)
+	assert.Equal(t, &owner, probe.Owner)
+	assert.NotSame(t, &owner, probe.Owner, "built probes do not share the owner")
+
+	request, err := NewProbe("https://example.com").Owner(owner).CreateRequest()
+	require.NoError(t, err)
+	assert.Equal(t, &owner, request.Owner)
+
+	for owner, message := range map[OwnerSchema]string{
+		{Team: new("SRE team")}:                            `invalid owner.team "SRE team"`,
+		{Team: new(strings.Repeat("a", 64))}:               "invalid owner.team",
+		{EscalationContact: new("")}:                       "owner.escalation_contact must be 1 to 256 characters long, got 0",
+		{EscalationContact: new(strings.Repeat("a", 257))}: "owner.escalation_contact must be 1 to 256 characters long, got 257",
+	} {
+		_, err := NewProbe("https://example.com").Owner(owner).Build()
+		assert.ErrorContains(t, err, message)
+	}
+}
@@ -0,0 +1,2116 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: probes.proto
+
+package probespb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type WatchEvent_Type int32
+
+const (
+	WatchEvent_TYPE_UNSPECIFIED WatchEvent_Type = 0
+	WatchEvent_ADDED            WatchEvent_Type = 1
+	WatchEvent_MODIFIED         WatchEvent_Type = 2
+	WatchEvent_DELETED          WatchEvent_Type = 3
+)
+
+// Enum value maps for WatchEvent_Type.
+var (
+	WatchEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "ADDED",
+		2: "MODIFIED",
+		3: "DELETED",
+	}
+	WatchEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"ADDED":            1,
+		"MODIFIED":         2,
+		"DELETED":          3,
+	}
+)
+
+func (x WatchEvent_Type) Enum() *WatchEvent_Type {
+	p := new(WatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x WatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_probes_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_probes_proto_enumTypes[0]
+}
+
+func (x WatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEvent_Type.Descriptor instead.
+func (WatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{19, 0}
+}
+
+// Probe mirrors ProbeObject.
+type Probe struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StaticUrl         string                 `protobuf:"bytes,2,opt,name=static_url,json=staticUrl,proto3" json:"static_url,omitempty"`
+	Labels            map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	StatusReason      string                 `protobuf:"bytes,5,opt,name=status_reason,json=statusReason,proto3" json:"status_reason,omitempty"`
+	StatusMessage     string                 `protobuf:"bytes,6,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
+	Interval          string                 `protobuf:"bytes,7,opt,name=interval,proto3" json:"interval,omitempty"`
+	Timeout           string                 `protobuf:"bytes,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Module            string                 `protobuf:"bytes,9,opt,name=module,proto3" json:"module,omitempty"`
+	Alerting          *Alerting              `protobuf:"bytes,10,opt,name=alerting,proto3" json:"alerting,omitempty"`
+	Auth              *ProbeAuth             `protobuf:"bytes,11,opt,name=auth,proto3" json:"auth,omitempty"`
+	Generation        int64                  `protobuf:"varint,12,opt,name=generation,proto3" json:"generation,omitempty"`
+	ResourceVersion   string                 `protobuf:"bytes,13,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	UrlHash           string                 `protobuf:"bytes,14,opt,name=url_hash,json=urlHash,proto3" json:"url_hash,omitempty"`
+	CreationTimestamp *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=creation_timestamp,json=creationTimestamp,proto3" json:"creation_timestamp,omitempty"`
+	UpdateTimestamp   *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=update_timestamp,json=updateTimestamp,proto3" json:"update_timestamp,omitempty"`
+	DeletionTimestamp *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=deletion_timestamp,json=deletionTimestamp,proto3" json:"deletion_timestamp,omitempty"`
+	StatusHistory     []*StatusTransition    `protobuf:"bytes,18,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	Paused            bool                   `protobuf:"varint,19,opt,name=paused,proto3" json:"paused,omitempty"`
+	AdditionalUrls    []string               `protobuf:"bytes,20,rep,name=additional_urls,json=additionalUrls,proto3" json:"additional_urls,omitempty"`
+	TargetStatuses    []*TargetStatus        `protobuf:"bytes,21,rep,name=target_statuses,json=targetStatuses,proto3" json:"target_statuses,omitempty"`
+	Dns               *Dns                   `protobuf:"bytes,22,opt,name=dns,proto3" json:"dns,omitempty"`
+	Tcp               *Tcp                   `protobuf:"bytes,23,opt,name=tcp,proto3" json:"tcp,omitempty"`
+	Icmp              *Icmp                  `protobuf:"bytes,24,opt,name=icmp,proto3" json:"icmp,omitempty"`
+	Assertions        *Assertions            `protobuf:"bytes,25,opt,name=assertions,proto3" json:"assertions,omitempty"`
+	Regions           []string               `protobuf:"bytes,26,rep,name=regions,proto3" json:"regions,omitempty"`
+	Owner             *Owner                 `protobuf:"bytes,27,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Probe) Reset() {
+	*x = Probe{}
+	mi := &file_probes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Probe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Probe) ProtoMessage() {}
+
+func (x *Probe) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Probe.ProtoReflect.Descriptor instead.
+func (*Probe) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Probe) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Probe) GetStaticUrl() string {
+	if x != nil {
+		return x.StaticUrl
+	}
+	return ""
+}
+
+func (x *Probe) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Probe) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Probe) GetStatusReason() string {
+	if x != nil {
+		return x.StatusReason
+	}
+	return ""
+}
+
+func (x *Probe) GetStatusMessage() string {
+	if x != nil {
+		return x.StatusMessage
+	}
+	return ""
+}
+
+func (x *Probe) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *Probe) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *Probe) GetModule() string {
+	if x != nil {
+		return x.Module
+	}
+	return ""
+}
+
+func (x *Probe) GetAlerting() *Alerting {
+	if x != nil {
+		return x.Alerting
+	}
+	return nil
+}
+
+func (x *Probe) GetAuth() *ProbeAuth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *Probe) GetGeneration() int64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Probe) GetResourceVersion() string {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return ""
+}
+
+func (x *Probe) GetUrlHash() string {
+	if x != nil {
+		return x.UrlHash
+	}
+	return ""
+}
+
+func (x *Probe) GetCreationTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationTimestamp
+	}
+	return nil
+}
+
+func (x *Probe) GetUpdateTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdateTimestamp
+	}
+	return nil
+}
+
+func (x *Probe) GetDeletionTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletionTimestamp
+	}
+	return nil
+}
+
+func (x *Probe) GetStatusHistory() []*StatusTransition {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+func (x *Probe) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *Probe) GetAdditionalUrls() []string {
+	if x != nil {
+		return x.AdditionalUrls
+	}
+	return nil
+}
+
+func (x *Probe) GetTargetStatuses() []*TargetStatus {
+	if x != nil {
+		return x.TargetStatuses
+	}
+	return nil
+}
+
+func (x *Probe) GetDns() *Dns {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *Probe) GetTcp() *Tcp {
+	if x != nil {
+		return x.Tcp
+	}
+	return nil
+}
+
+func (x *Probe) GetIcmp() *Icmp {
+	if x != nil {
+		return x.Icmp
+	}
+	return nil
+}
+
+func (x *Probe) GetAssertions() *Assertions {
+	if x != nil {
+		return x.Assertions
+	}
+	return nil
+}
+
+func (x *Probe) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *Probe) GetOwner() *Owner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+// Alerting mirrors AlertingSchema.
+type Alerting struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	RunbookUrl               *string                `protobuf:"bytes,1,opt,name=runbook_url,json=runbookUrl,proto3,oneof" json:"runbook_url,omitempty"`
+	Severity                 *string                `protobuf:"bytes,2,opt,name=severity,proto3,oneof" json:"severity,omitempty"`
+	SilenceDuringMaintenance *bool                  `protobuf:"varint,3,opt,name=silence_during_maintenance,json=silenceDuringMaintenance,proto3,oneof" json:"silence_during_maintenance,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *Alerting) Reset() {
+	*x = Alerting{}
+	mi := &file_probes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alerting) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alerting) ProtoMessage() {}
+
+func (x *Alerting) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alerting.ProtoReflect.Descriptor instead.
+func (*Alerting) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Alerting) GetRunbookUrl() string {
+	if x != nil && x.RunbookUrl != nil {
+		return *x.RunbookUrl
+	}
+	return ""
+}
+
+func (x *Alerting) GetSeverity() string {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return ""
+}
+
+func (x *Alerting) GetSilenceDuringMaintenance() bool {
+	if x != nil && x.SilenceDuringMaintenance != nil {
+		return *x.SilenceDuringMaintenance
+	}
+	return false
+}
+
+// Owner mirrors OwnerSchema.
+type Owner struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Team              *string                `protobuf:"bytes,1,opt,name=team,proto3,oneof" json:"team,omitempty"`
+	EscalationContact *string                `protobuf:"bytes,2,opt,name=escalation_contact,json=escalationContact,proto3,oneof" json:"escalation_contact,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Owner) Reset() {
+	*x = Owner{}
+	mi := &file_probes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Owner) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Owner) ProtoMessage() {}
+
+func (x *Owner) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Owner.ProtoReflect.Descriptor instead.
+func (*Owner) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Owner) GetTeam() string {
+	if x != nil && x.Team != nil {
+		return *x.Team
+	}
+	return ""
+}
+
+func (x *Owner) GetEscalationContact() string {
+	if x != nil && x.EscalationContact != nil {
+		return *x.EscalationContact
+	}
+	return ""
+}
+
+// Assertions mirrors AssertionsSchema.
+type Assertions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatusCodes   []string               `protobuf:"bytes,1,rep,name=status_codes,json=statusCodes,proto3" json:"status_codes,omitempty"`
+	BodyRegex     *string                `protobuf:"bytes,2,opt,name=body_regex,json=bodyRegex,proto3,oneof" json:"body_regex,omitempty"`
+	Headers       []*HeaderAssertion     `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty"`
+	TlsExpiryDays *int32                 `protobuf:"varint,4,opt,name=tls_expiry_days,json=tlsExpiryDays,proto3,oneof" json:"tls_expiry_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Assertions) Reset() {
+	*x = Assertions{}
+	mi := &file_probes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Assertions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assertions) ProtoMessage() {}
+
+func (x *Assertions) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assertions.ProtoReflect.Descriptor instead.
+func (*Assertions) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Assertions) GetStatusCodes() []string {
+	if x != nil {
+		return x.StatusCodes
+	}
+	return nil
+}
+
+func (x *Assertions) GetBodyRegex() string {
+	if x != nil && x.BodyRegex != nil {
+		return *x.BodyRegex
+	}
+	return ""
+}
+
+func (x *Assertions) GetHeaders() []*HeaderAssertion {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *Assertions) GetTlsExpiryDays() int32 {
+	if x != nil && x.TlsExpiryDays != nil {
+		return *x.TlsExpiryDays
+	}
+	return 0
+}
+
+// HeaderAssertion mirrors HeaderAssertion.
+type HeaderAssertion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ValueRegex    *string                `protobuf:"bytes,2,opt,name=value_regex,json=valueRegex,proto3,oneof" json:"value_regex,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeaderAssertion) Reset() {
+	*x = HeaderAssertion{}
+	mi := &file_probes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeaderAssertion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeaderAssertion) ProtoMessage() {}
+
+func (x *HeaderAssertion) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeaderAssertion.ProtoReflect.Descriptor instead.
+func (*HeaderAssertion) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HeaderAssertion) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HeaderAssertion) GetValueRegex() string {
+	if x != nil && x.ValueRegex != nil {
+		return *x.ValueRegex
+	}
+	return ""
+}
+
+// Dns mirrors DnsSchema.
+type Dns struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	QueryName       string                 `protobuf:"bytes,1,opt,name=query_name,json=queryName,proto3" json:"query_name,omitempty"`
+	RecordType      string                 `protobuf:"bytes,2,opt,name=record_type,json=recordType,proto3" json:"record_type,omitempty"`
+	ExpectedAnswers []string               `protobuf:"bytes,3,rep,name=expected_answers,json=expectedAnswers,proto3" json:"expected_answers,omitempty"`
+	Resolver        *string                `protobuf:"bytes,4,opt,name=resolver,proto3,oneof" json:"resolver,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Dns) Reset() {
+	*x = Dns{}
+	mi := &file_probes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dns) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dns) ProtoMessage() {}
+
+func (x *Dns) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dns.ProtoReflect.Descriptor instead.
+func (*Dns) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Dns) GetQueryName() string {
+	if x != nil {
+		return x.QueryName
+	}
+	return ""
+}
+
+func (x *Dns) GetRecordType() string {
+	if x != nil {
+		return x.RecordType
+	}
+	return ""
+}
+
+func (x *Dns) GetExpectedAnswers() []string {
+	if x != nil {
+		return x.ExpectedAnswers
+	}
+	return nil
+}
+
+func (x *Dns) GetResolver() string {
+	if x != nil && x.Resolver != nil {
+		return *x.Resolver
+	}
+	return ""
+}
+
+// Tcp mirrors TcpSchema.
+type Tcp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Tls           *bool                  `protobuf:"varint,2,opt,name=tls,proto3,oneof" json:"tls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tcp) Reset() {
+	*x = Tcp{}
+	mi := &file_probes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tcp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tcp) ProtoMessage() {}
+
+func (x *Tcp) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tcp.ProtoReflect.Descriptor instead.
+func (*Tcp) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Tcp) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Tcp) GetTls() bool {
+	if x != nil && x.Tls != nil {
+		return *x.Tls
+	}
+	return false
+}
+
+// Icmp mirrors IcmpSchema.
+type Icmp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	PacketCount   *int32                 `protobuf:"varint,2,opt,name=packet_count,json=packetCount,proto3,oneof" json:"packet_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Icmp) Reset() {
+	*x = Icmp{}
+	mi := &file_probes_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Icmp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Icmp) ProtoMessage() {}
+
+func (x *Icmp) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Icmp.ProtoReflect.Descriptor instead.
+func (*Icmp) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Icmp) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *Icmp) GetPacketCount() int32 {
+	if x != nil && x.PacketCount != nil {
+		return *x.PacketCount
+	}
+	return 0
+}
+
+// ProbeAuth mirrors ProbeAuthSchema. Probes return the password and bearer
+// token as REDACTED.
+type ProbeAuth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      *string                `protobuf:"bytes,1,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	Password      *string                `protobuf:"bytes,2,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	BearerToken   *string                `protobuf:"bytes,3,opt,name=bearer_token,json=bearerToken,proto3,oneof" json:"bearer_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeAuth) Reset() {
+	*x = ProbeAuth{}
+	mi := &file_probes_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeAuth) ProtoMessage() {}
+
+func (x *ProbeAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeAuth.ProtoReflect.Descriptor instead.
+func (*ProbeAuth) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ProbeAuth) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *ProbeAuth) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *ProbeAuth) GetBearerToken() string {
+	if x != nil && x.BearerToken != nil {
+		return *x.BearerToken
+	}
+	return ""
+}
+
+// StatusTransition mirrors StatusTransition.
+type StatusTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Actor         string                 `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusTransition) Reset() {
+	*x = StatusTransition{}
+	mi := &file_probes_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusTransition) ProtoMessage() {}
+
+func (x *StatusTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusTransition.ProtoReflect.Descriptor instead.
+func (*StatusTransition) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatusTransition) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *StatusTransition) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *StatusTransition) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *StatusTransition) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *StatusTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// TargetStatus mirrors TargetStatus.
+type TargetStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TargetStatus) Reset() {
+	*x = TargetStatus{}
+	mi := &file_probes_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TargetStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TargetStatus) ProtoMessage() {}
+
+func (x *TargetStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TargetStatus.ProtoReflect.Descriptor instead.
+func (*TargetStatus) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TargetStatus) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *TargetStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *TargetStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TargetStatus) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type ListProbesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelSelector string                 `protobuf:"bytes,1,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	FieldSelector string                 `protobuf:"bytes,2,opt,name=field_selector,json=fieldSelector,proto3" json:"field_selector,omitempty"`
+	MinGeneration int64                  `protobuf:"varint,3,opt,name=min_generation,json=minGeneration,proto3" json:"min_generation,omitempty"`
+	// Zero returns every probe.
+	Limit         int32  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	SortBy        string `protobuf:"bytes,6,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Order         string `protobuf:"bytes,7,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProbesRequest) Reset() {
+	*x = ListProbesRequest{}
+	mi := &file_probes_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProbesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProbesRequest) ProtoMessage() {}
+
+func (x *ListProbesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProbesRequest.ProtoReflect.Descriptor instead.
+func (*ListProbesRequest) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListProbesRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *ListProbesRequest) GetFieldSelector() string {
+	if x != nil {
+		return x.FieldSelector
+	}
+	return ""
+}
+
+func (x *ListProbesRequest) GetMinGeneration() int64 {
+	if x != nil {
+		return x.MinGeneration
+	}
+	return 0
+}
+
+func (x *ListProbesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListProbesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProbesRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListProbesRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+type ListProbesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Probes        []*Probe               `protobuf:"bytes,1,rep,name=probes,proto3" json:"probes,omitempty"`
+	Features      map[string]string      `protobuf:"bytes,2,rep,name=features,proto3" json:"features,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Version       string                 `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProbesResponse) Reset() {
+	*x = ListProbesResponse{}
+	mi := &file_probes_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProbesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProbesResponse) ProtoMessage() {}
+
+func (x *ListProbesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProbesResponse.ProtoReflect.Descriptor instead.
+func (*ListProbesResponse) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListProbesResponse) GetProbes() []*Probe {
+	if x != nil {
+		return x.Probes
+	}
+	return nil
+}
+
+func (x *ListProbesResponse) GetFeatures() map[string]string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *ListProbesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListProbesResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type GetProbeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProbeRequest) Reset() {
+	*x = GetProbeRequest{}
+	mi := &file_probes_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProbeRequest) ProtoMessage() {}
+
+func (x *GetProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProbeRequest.ProtoReflect.Descriptor instead.
+func (*GetProbeRequest) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetProbeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateProbeRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	StaticUrl  string                 `protobuf:"bytes,1,opt,name=static_url,json=staticUrl,proto3" json:"static_url,omitempty"`
+	Labels     map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Interval   *string                `protobuf:"bytes,3,opt,name=interval,proto3,oneof" json:"interval,omitempty"`
+	Timeout    *string                `protobuf:"bytes,4,opt,name=timeout,proto3,oneof" json:"timeout,omitempty"`
+	Module     *string                `protobuf:"bytes,5,opt,name=module,proto3,oneof" json:"module,omitempty"`
+	Alerting   *Alerting              `protobuf:"bytes,6,opt,name=alerting,proto3" json:"alerting,omitempty"`
+	Auth       *ProbeAuth             `protobuf:"bytes,7,opt,name=auth,proto3" json:"auth,omitempty"`
+	TemplateId *string                `protobuf:"bytes,8,opt,name=template_id,json=templateId,proto3,oneof" json:"template_id,omitempty"`
+	Variables  map[string]string      `protobuf:"bytes,9,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DryRun     bool                   `protobuf:"varint,10,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Set to connectivity to check the target first.
+	Validate       string      `protobuf:"bytes,11,opt,name=validate,proto3" json:"validate,omitempty"`
+	AdditionalUrls []string    `protobuf:"bytes,12,rep,name=additional_urls,json=additionalUrls,proto3" json:"additional_urls,omitempty"`
+	Dns            *Dns        `protobuf:"bytes,13,opt,name=dns,proto3" json:"dns,omitempty"`
+	Tcp            *Tcp        `protobuf:"bytes,14,opt,name=tcp,proto3" json:"tcp,omitempty"`
+	Icmp           *Icmp       `protobuf:"bytes,15,opt,name=icmp,proto3" json:"icmp,omitempty"`
+	Assertions     *Assertions `protobuf:"bytes,16,opt,name=assertions,proto3" json:"assertions,omitempty"`
+	Regions        []string    `protobuf:"bytes,17,rep,name=regions,proto3" json:"regions,omitempty"`
+	Owner          *Owner      `protobuf:"bytes,18,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateProbeRequest) Reset() {
+	*x = CreateProbeRequest{}
+	mi := &file_probes_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateProbeRequest) ProtoMessage() {}
+
+func (x *CreateProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateProbeRequest.ProtoReflect.Descriptor instead.
+func (*CreateProbeRequest) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateProbeRequest) GetStaticUrl() string {
+	if x != nil {
+		return x.StaticUrl
+	}
+	return ""
+}
+
+func (x *CreateProbeRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetInterval() string {
+	if x != nil && x.Interval != nil {
+		return *x.Interval
+	}
+	return ""
+}
+
+func (x *CreateProbeRequest) GetTimeout() string {
+	if x != nil && x.Timeout != nil {
+		return *x.Timeout
+	}
+	return ""
+}
+
+func (x *CreateProbeRequest) GetModule() string {
+	if x != nil && x.Module != nil {
+		return *x.Module
+	}
+	return ""
+}
+
+func (x *CreateProbeRequest) GetAlerting() *Alerting {
+	if x != nil {
+		return x.Alerting
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetAuth() *ProbeAuth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetTemplateId() string {
+	if x != nil && x.TemplateId != nil {
+		return *x.TemplateId
+	}
+	return ""
+}
+
+func (x *CreateProbeRequest) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CreateProbeRequest) GetValidate() string {
+	if x != nil {
+		return x.Validate
+	}
+	return ""
+}
+
+func (x *CreateProbeRequest) GetAdditionalUrls() []string {
+	if x != nil {
+		return x.AdditionalUrls
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetDns() *Dns {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetTcp() *Tcp {
+	if x != nil {
+		return x.Tcp
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetIcmp() *Icmp {
+	if x != nil {
+		return x.Icmp
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetAssertions() *Assertions {
+	if x != nil {
+		return x.Assertions
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *CreateProbeRequest) GetOwner() *Owner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+type UpdateProbeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Applies the change only to this version of the probe, like If-Match.
+	ResourceVersion string `protobuf:"bytes,2,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	DryRun          bool   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Replaces the labels; an empty map leaves them unchanged.
+	Labels        map[string]string `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Status        *string           `protobuf:"bytes,5,opt,name=status,proto3,oneof" json:"status,omitempty"`
+	StatusReason  *string           `protobuf:"bytes,6,opt,name=status_reason,json=statusReason,proto3,oneof" json:"status_reason,omitempty"`
+	StatusMessage *string           `protobuf:"bytes,7,opt,name=status_message,json=statusMessage,proto3,oneof" json:"status_message,omitempty"`
+	Interval      *string           `protobuf:"bytes,8,opt,name=interval,proto3,oneof" json:"interval,omitempty"`
+	Timeout       *string           `protobuf:"bytes,9,opt,name=timeout,proto3,oneof" json:"timeout,omitempty"`
+	Module        *string           `protobuf:"bytes,10,opt,name=module,proto3,oneof" json:"module,omitempty"`
+	Alerting      *Alerting         `protobuf:"bytes,11,opt,name=alerting,proto3" json:"alerting,omitempty"`
+	Auth          *ProbeAuth        `protobuf:"bytes,12,opt,name=auth,proto3" json:"auth,omitempty"`
+	Paused        *bool             `protobuf:"varint,13,opt,name=paused,proto3,oneof" json:"paused,omitempty"`
+	// Replaces the additional URLs; an empty list leaves them unchanged.
+	AdditionalUrls []string `protobuf:"bytes,14,rep,name=additional_urls,json=additionalUrls,proto3" json:"additional_urls,omitempty"`
+	// Replaces the target statuses; an empty list leaves them unchanged.
+	TargetStatuses []*TargetStatus `protobuf:"bytes,15,rep,name=target_statuses,json=targetStatuses,proto3" json:"target_statuses,omitempty"`
+	// Replaces the expected answers of a dns probe.
+	Dns *Dns `protobuf:"bytes,16,opt,name=dns,proto3" json:"dns,omitempty"`
+	// Replaces the tls setting of a tcp probe.
+	Tcp *Tcp `protobuf:"bytes,17,opt,name=tcp,proto3" json:"tcp,omitempty"`
+	// Replaces the packet count of an icmp probe.
+	Icmp *Icmp `protobuf:"bytes,18,opt,name=icmp,proto3" json:"icmp,omitempty"`
+	// Replaces the assertions; an empty message removes them.
+	Assertions *Assertions `protobuf:"bytes,19,opt,name=assertions,proto3" json:"assertions,omitempty"`
+	// Replaces the regions; an empty list leaves them unchanged.
+	Regions []string `protobuf:"bytes,20,rep,name=regions,proto3" json:"regions,omitempty"`
+	// Replaces the owner; an empty message removes it.
+	Owner         *Owner `protobuf:"bytes,21,opt,name=owner,proto3" json:"owner,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProbeRequest) Reset() {
+	*x = UpdateProbeRequest{}
+	mi := &file_probes_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProbeRequest) ProtoMessage() {}
+
+func (x *UpdateProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProbeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProbeRequest) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateProbeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetResourceVersion() string {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *UpdateProbeRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetStatusReason() string {
+	if x != nil && x.StatusReason != nil {
+		return *x.StatusReason
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetStatusMessage() string {
+	if x != nil && x.StatusMessage != nil {
+		return *x.StatusMessage
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetInterval() string {
+	if x != nil && x.Interval != nil {
+		return *x.Interval
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetTimeout() string {
+	if x != nil && x.Timeout != nil {
+		return *x.Timeout
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetModule() string {
+	if x != nil && x.Module != nil {
+		return *x.Module
+	}
+	return ""
+}
+
+func (x *UpdateProbeRequest) GetAlerting() *Alerting {
+	if x != nil {
+		return x.Alerting
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetAuth() *ProbeAuth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetPaused() bool {
+	if x != nil && x.Paused != nil {
+		return *x.Paused
+	}
+	return false
+}
+
+func (x *UpdateProbeRequest) GetAdditionalUrls() []string {
+	if x != nil {
+		return x.AdditionalUrls
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetTargetStatuses() []*TargetStatus {
+	if x != nil {
+		return x.TargetStatuses
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetDns() *Dns {
+	if x != nil {
+		return x.Dns
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetTcp() *Tcp {
+	if x != nil {
+		return x.Tcp
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetIcmp() *Icmp {
+	if x != nil {
+		return x.Icmp
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetAssertions() *Assertions {
+	if x != nil {
+		return x.Assertions
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetRegions() []string {
+	if x != nil {
+		return x.Regions
+	}
+	return nil
+}
+
+func (x *UpdateProbeRequest) GetOwner() *Owner {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+type DeleteProbeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Deletes the probe only at this version, like If-Match.
+	ResourceVersion string `protobuf:"bytes,2,opt,name=resource_version,json=resourceVersion,proto3" json:"resource_version,omitempty"`
+	DryRun          bool   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteProbeRequest) Reset() {
+	*x = DeleteProbeRequest{}
+	mi := &file_probes_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProbeRequest) ProtoMessage() {}
+
+func (x *DeleteProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProbeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteProbeRequest) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteProbeRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteProbeRequest) GetResourceVersion() string {
+	if x != nil {
+		return x.ResourceVersion
+	}
+	return ""
+}
+
+func (x *DeleteProbeRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DeleteProbeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteProbeResponse) Reset() {
+	*x = DeleteProbeResponse{}
+	mi := &file_probes_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteProbeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteProbeResponse) ProtoMessage() {}
+
+func (x *DeleteProbeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteProbeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteProbeResponse) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{17}
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LabelSelector string                 `protobuf:"bytes,1,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	FieldSelector string                 `protobuf:"bytes,2,opt,name=field_selector,json=fieldSelector,proto3" json:"field_selector,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_probes_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetFieldSelector() string {
+	if x != nil {
+		return x.FieldSelector
+	}
+	return ""
+}
+
+type WatchEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  WatchEvent_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=rhobs.synthetics.v1.WatchEvent_Type" json:"type,omitempty"`
+	// The probe as changed; deleted probes as last seen.
+	Probe *Probe `protobuf:"bytes,2,opt,name=probe,proto3" json:"probe,omitempty"`
+	// The version of the listing the event was found in, as ListProbes
+	// returns it.
+	Version       string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_probes_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_probes_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_probes_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchEvent) GetType() WatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEvent_TYPE_UNSPECIFIED
+}
+
+func (x *WatchEvent) GetProbe() *Probe {
+	if x != nil {
+		return x.Probe
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+var File_probes_proto protoreflect.FileDescriptor
+
+const file_probes_proto_rawDesc = "" +
+	"\n" +
+	"\fprobes.proto\x12\x13rhobs.synthetics.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x84\n" +
+	"\n" +
+	"\x05Probe\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"static_url\x18\x02 \x01(\tR\tstaticUrl\x12>\n" +
+	"\x06labels\x18\x03 \x03(\v2&.rhobs.synthetics.v1.Probe.LabelsEntryR\x06labels\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12#\n" +
+	"\rstatus_reason\x18\x05 \x01(\tR\fstatusReason\x12%\n" +
+	"\x0estatus_message\x18\x06 \x01(\tR\rstatusMessage\x12\x1a\n" +
+	"\binterval\x18\a \x01(\tR\binterval\x12\x18\n" +
+	"\atimeout\x18\b \x01(\tR\atimeout\x12\x16\n" +
+	"\x06module\x18\t \x01(\tR\x06module\x129\n" +
+	"\balerting\x18\n" +
+	" \x01(\v2\x1d.rhobs.synthetics.v1.AlertingR\balerting\x122\n" +
+	"\x04auth\x18\v \x01(\v2\x1e.rhobs.synthetics.v1.ProbeAuthR\x04auth\x12\x1e\n" +
+	"\n" +
+	"generation\x18\f \x01(\x03R\n" +
+	"generation\x12)\n" +
+	"\x10resource_version\x18\r \x01(\tR\x0fresourceVersion\x12\x19\n" +
+	"\burl_hash\x18\x0e \x01(\tR\aurlHash\x12I\n" +
+	"\x12creation_timestamp\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\x11creationTimestamp\x12E\n" +
+	"\x10update_timestamp\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\x0fupdateTimestamp\x12I\n" +
+	"\x12deletion_timestamp\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\x11deletionTimestamp\x12L\n" +
+	"\x0estatus_history\x18\x12 \x03(\v2%.rhobs.synthetics.v1.StatusTransitionR\rstatusHistory\x12\x16\n" +
+	"\x06paused\x18\x13 \x01(\bR\x06paused\x12'\n" +
+	"\x0fadditional_urls\x18\x14 \x03(\tR\x0eadditionalUrls\x12J\n" +
+	"\x0ftarget_statuses\x18\x15 \x03(\v2!.rhobs.synthetics.v1.TargetStatusR\x0etargetStatuses\x12*\n" +
+	"\x03dns\x18\x16 \x01(\v2\x18.rhobs.synthetics.v1.DnsR\x03dns\x12*\n" +
+	"\x03tcp\x18\x17 \x01(\v2\x18.rhobs.synthetics.v1.TcpR\x03tcp\x12-\n" +
+	"\x04icmp\x18\x18 \x01(\v2\x19.rhobs.synthetics.v1.IcmpR\x04icmp\x12?\n" +
+	"\n" +
+	"assertions\x18\x19 \x01(\v2\x1f.rhobs.synthetics.v1.AssertionsR\n" +
+	"assertions\x12\x18\n" +
+	"\aregions\x18\x1a \x03(\tR\aregions\x120\n" +
+	"\x05owner\x18\x1b \x01(\v2\x1a.rhobs.synthetics.v1.OwnerR\x05owner\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd0\x01\n" +
+	"\bAlerting\x12$\n" +
+	"\vrunbook_url\x18\x01 \x01(\tH\x00R\n" +
+	"runbookUrl\x88\x01\x01\x12\x1f\n" +
+	"\bseverity\x18\x02 \x01(\tH\x01R\bseverity\x88\x01\x01\x12A\n" +
+	"\x1asilence_during_maintenance\x18\x03 \x01(\bH\x02R\x18silenceDuringMaintenance\x88\x01\x01B\x0e\n" +
+	"\f_runbook_urlB\v\n" +
+	"\t_severityB\x1d\n" +
+	"\x1b_silence_during_maintenance\"t\n" +
+	"\x05Owner\x12\x17\n" +
+	"\x04team\x18\x01 \x01(\tH\x00R\x04team\x88\x01\x01\x122\n" +
+	"\x12escalation_contact\x18\x02 \x01(\tH\x01R\x11escalationContact\x88\x01\x01B\a\n" +
+	"\x05_teamB\x15\n" +
+	"\x13_escalation_contact\"\xe3\x01\n" +
+	"\n" +
+	"Assertions\x12!\n" +
+	"\fstatus_codes\x18\x01 \x03(\tR\vstatusCodes\x12\"\n" +
+	"\n" +
+	"body_regex\x18\x02 \x01(\tH\x00R\tbodyRegex\x88\x01\x01\x12>\n" +
+	"\aheaders\x18\x03 \x03(\v2$.rhobs.synthetics.v1.HeaderAssertionR\aheaders\x12+\n" +
+	"\x0ftls_expiry_days\x18\x04 \x01(\x05H\x01R\rtlsExpiryDays\x88\x01\x01B\r\n" +
+	"\v_body_regexB\x12\n" +
+	"\x10_tls_expiry_days\"[\n" +
+	"\x0fHeaderAssertion\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12$\n" +
+	"\vvalue_regex\x18\x02 \x01(\tH\x00R\n" +
+	"valueRegex\x88\x01\x01B\x0e\n" +
+	"\f_value_regex\"\x9e\x01\n" +
+	"\x03Dns\x12\x1d\n" +
+	"\n" +
+	"query_name\x18\x01 \x01(\tR\tqueryName\x12\x1f\n" +
+	"\vrecord_type\x18\x02 \x01(\tR\n" +
+	"recordType\x12)\n" +
+	"\x10expected_answers\x18\x03 \x03(\tR\x0fexpectedAnswers\x12\x1f\n" +
+	"\bresolver\x18\x04 \x01(\tH\x00R\bresolver\x88\x01\x01B\v\n" +
+	"\t_resolver\">\n" +
+	"\x03Tcp\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x15\n" +
+	"\x03tls\x18\x02 \x01(\bH\x00R\x03tls\x88\x01\x01B\x06\n" +
+	"\x04_tls\"S\n" +
+	"\x04Icmp\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12&\n" +
+	"\fpacket_count\x18\x02 \x01(\x05H\x00R\vpacketCount\x88\x01\x01B\x0f\n" +
+	"\r_packet_count\"\xa0\x01\n" +
+	"\tProbeAuth\x12\x1f\n" +
+	"\busername\x18\x01 \x01(\tH\x00R\busername\x88\x01\x01\x12\x1f\n" +
+	"\bpassword\x18\x02 \x01(\tH\x01R\bpassword\x88\x01\x01\x12&\n" +
+	"\fbearer_token\x18\x03 \x01(\tH\x02R\vbearerToken\x88\x01\x01B\v\n" +
+	"\t_usernameB\v\n" +
+	"\t_passwordB\x0f\n" +
+	"\r_bearer_token\"\x9e\x01\n" +
+	"\x10StatusTransition\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
+	"\x05actor\x18\x04 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\"\x8a\x01\n" +
+	"\fTargetStatus\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xec\x01\n" +
+	"\x11ListProbesRequest\x12%\n" +
+	"\x0elabel_selector\x18\x01 \x01(\tR\rlabelSelector\x12%\n" +
+	"\x0efield_selector\x18\x02 \x01(\tR\rfieldSelector\x12%\n" +
+	"\x0emin_generation\x18\x03 \x01(\x03R\rminGeneration\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12\x17\n" +
+	"\asort_by\x18\x06 \x01(\tR\x06sortBy\x12\x14\n" +
+	"\x05order\x18\a \x01(\tR\x05order\"\x9a\x02\n" +
+	"\x12ListProbesResponse\x122\n" +
+	"\x06probes\x18\x01 \x03(\v2\x1a.rhobs.synthetics.v1.ProbeR\x06probes\x12Q\n" +
+	"\bfeatures\x18\x02 \x03(\v25.rhobs.synthetics.v1.ListProbesResponse.FeaturesEntryR\bfeatures\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x1a;\n" +
+	"\rFeaturesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"!\n" +
+	"\x0fGetProbeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xe7\a\n" +
+	"\x12CreateProbeRequest\x12\x1d\n" +
+	"\n" +
+	"static_url\x18\x01 \x01(\tR\tstaticUrl\x12K\n" +
+	"\x06labels\x18\x02 \x03(\v23.rhobs.synthetics.v1.CreateProbeRequest.LabelsEntryR\x06labels\x12\x1f\n" +
+	"\binterval\x18\x03 \x01(\tH\x00R\binterval\x88\x01\x01\x12\x1d\n" +
+	"\atimeout\x18\x04 \x01(\tH\x01R\atimeout\x88\x01\x01\x12\x1b\n" +
+	"\x06module\x18\x05 \x01(\tH\x02R\x06module\x88\x01\x01\x129\n" +
+	"\balerting\x18\x06 \x01(\v2\x1d.rhobs.synthetics.v1.AlertingR\balerting\x122\n" +
+	"\x04auth\x18\a \x01(\v2\x1e.rhobs.synthetics.v1.ProbeAuthR\x04auth\x12$\n" +
+	"\vtemplate_id\x18\b \x01(\tH\x03R\n" +
+	"templateId\x88\x01\x01\x12T\n" +
+	"\tvariables\x18\t \x03(\v26.rhobs.synthetics.v1.CreateProbeRequest.VariablesEntryR\tvariables\x12\x17\n" +
+	"\adry_run\x18\n" +
+	" \x01(\bR\x06dryRun\x12\x1a\n" +
+	"\bvalidate\x18\v \x01(\tR\bvalidate\x12'\n" +
+	"\x0fadditional_urls\x18\f \x03(\tR\x0eadditionalUrls\x12*\n" +
+	"\x03dns\x18\r \x01(\v2\x18.rhobs.synthetics.v1.DnsR\x03dns\x12*\n" +
+	"\x03tcp\x18\x0e \x01(\v2\x18.rhobs.synthetics.v1.TcpR\x03tcp\x12-\n" +
+	"\x04icmp\x18\x0f \x01(\v2\x19.rhobs.synthetics.v1.IcmpR\x04icmp\x12?\n" +
+	"\n" +
+	"assertions\x18\x10 \x01(\v2\x1f.rhobs.synthetics.v1.AssertionsR\n" +
+	"assertions\x12\x18\n" +
+	"\aregions\x18\x11 \x03(\tR\aregions\x120\n" +
+	"\x05owner\x18\x12 \x01(\v2\x1a.rhobs.synthetics.v1.OwnerR\x05owner\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
+	"\t_intervalB\n" +
+	"\n" +
+	"\b_timeoutB\t\n" +
+	"\a_moduleB\x0e\n" +
+	"\f_template_id\"\xb4\b\n" +
+	"\x12UpdateProbeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10resource_version\x18\x02 \x01(\tR\x0fresourceVersion\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12K\n" +
+	"\x06labels\x18\x04 \x03(\v23.rhobs.synthetics.v1.UpdateProbeRequest.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\x06status\x18\x05 \x01(\tH\x00R\x06status\x88\x01\x01\x12(\n" +
+	"\rstatus_reason\x18\x06 \x01(\tH\x01R\fstatusReason\x88\x01\x01\x12*\n" +
+	"\x0estatus_message\x18\a \x01(\tH\x02R\rstatusMessage\x88\x01\x01\x12\x1f\n" +
+	"\binterval\x18\b \x01(\tH\x03R\binterval\x88\x01\x01\x12\x1d\n" +
+	"\atimeout\x18\t \x01(\tH\x04R\atimeout\x88\x01\x01\x12\x1b\n" +
+	"\x06module\x18\n" +
+	" \x01(\tH\x05R\x06module\x88\x01\x01\x129\n" +
+	"\balerting\x18\v \x01(\v2\x1d.rhobs.synthetics.v1.AlertingR\balerting\x122\n" +
+	"\x04auth\x18\f \x01(\v2\x1e.rhobs.synthetics.v1.ProbeAuthR\x04auth\x12\x1b\n" +
+	"\x06paused\x18\r \x01(\bH\x06R\x06paused\x88\x01\x01\x12'\n" +
+	"\x0fadditional_urls\x18\x0e \x03(\tR\x0eadditionalUrls\x12J\n" +
+	"\x0ftarget_statuses\x18\x0f \x03(\v2!.rhobs.synthetics.v1.TargetStatusR\x0etargetStatuses\x12*\n" +
+	"\x03dns\x18\x10 \x01(\v2\x18.rhobs.synthetics.v1.DnsR\x03dns\x12*\n" +
+	"\x03tcp\x18\x11 \x01(\v2\x18.rhobs.synthetics.v1.TcpR\x03tcp\x12-\n" +
+	"\x04icmp\x18\x12 \x01(\v2\x19.rhobs.synthetics.v1.IcmpR\x04icmp\x12?\n" +
+	"\n" +
+	"assertions\x18\x13 \x01(\v2\x1f.rhobs.synthetics.v1.AssertionsR\n" +
+	"assertions\x12\x18\n" +
+	"\aregions\x18\x14 \x03(\tR\aregions\x120\n" +
+	"\x05owner\x18\x15 \x01(\v2\x1a.rhobs.synthetics.v1.OwnerR\x05owner\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\t\n" +
+	"\a_statusB\x10\n" +
+	"\x0e_status_reasonB\x11\n" +
+	"\x0f_status_messageB\v\n" +
+	"\t_intervalB\n" +
+	"\n" +
+	"\b_timeoutB\t\n" +
+	"\a_moduleB\t\n" +
+	"\a_paused\"h\n" +
+	"\x12DeleteProbeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10resource_version\x18\x02 \x01(\tR\x0fresourceVersion\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\x15\n" +
+	"\x13DeleteProbeResponse\"\\\n" +
+	"\fWatchRequest\x12%\n" +
+	"\x0elabel_selector\x18\x01 \x01(\tR\rlabelSelector\x12%\n" +
+	"\x0efield_selector\x18\x02 \x01(\tR\rfieldSelector\"\xd6\x01\n" +
+	"\n" +
+	"WatchEvent\x128\n" +
+	"\x04type\x18\x01 \x01(\x0e2$.rhobs.synthetics.v1.WatchEvent.TypeR\x04type\x120\n" +
+	"\x05probe\x18\x02 \x01(\v2\x1a.rhobs.synthetics.v1.ProbeR\x05probe\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"B\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\t\n" +
+	"\x05ADDED\x10\x01\x12\f\n" +
+	"\bMODIFIED\x10\x02\x12\v\n" +
+	"\aDELETED\x10\x032\x8e\x04\n" +
+	"\x06Probes\x12]\n" +
+	"\n" +
+	"ListProbes\x12&.rhobs.synthetics.v1.ListProbesRequest\x1a'.rhobs.synthetics.v1.ListProbesResponse\x12L\n" +
+	"\bGetProbe\x12$.rhobs.synthetics.v1.GetProbeRequest\x1a\x1a.rhobs.synthetics.v1.Probe\x12R\n" +
+	"\vCreateProbe\x12'.rhobs.synthetics.v1.CreateProbeRequest\x1a\x1a.rhobs.synthetics.v1.Probe\x12R\n" +
+	"\vUpdateProbe\x12'.rhobs.synthetics.v1.UpdateProbeRequest\x1a\x1a.rhobs.synthetics.v1.Probe\x12`\n" +
+	"\vDeleteProbe\x12'.rhobs.synthetics.v1.DeleteProbeRequest\x1a(.rhobs.synthetics.v1.DeleteProbeResponse\x12M\n" +
+	"\x05Watch\x12!.rhobs.synthetics.v1.WatchRequest\x1a\x1f.rhobs.synthetics.v1.WatchEvent0\x01BEZCgithub.com/rhobs/rhobs-synthetics-api/pkg/apis/v1/probespb;probespbb\x06proto3"
+
+var (
+	file_probes_proto_rawDescOnce sync.Once
+	file_probes_proto_rawDescData []byte
+)
+
+func file_probes_proto_rawDescGZIP() []byte {
+	file_probes_proto_rawDescOnce.Do(func() {
+		file_probes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_probes_proto_rawDesc), len(file_probes_proto_rawDesc)))
+	})
+	return file_probes_proto_rawDescData
+}
+
+var file_probes_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_probes_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_probes_proto_goTypes = []any{
+	(WatchEvent_Type)(0),          // 0: rhobs.synthetics.v1.WatchEvent.Type
+	(*Probe)(nil),                 // 1: rhobs.synthetics.v1.Probe
+	(*Alerting)(nil),              // 2: rhobs.synthetics.v1.Alerting
+	(*Owner)(nil),                 // 3: rhobs.synthetics.v1.Owner
+	(*Assertions)(nil),            // 4: rhobs.synthetics.v1.Assertions
+	(*HeaderAssertion)(nil),       // 5: rhobs.synthetics.v1.HeaderAssertion
+	(*Dns)(nil),                   // 6: rhobs.synthetics.v1.Dns
+	(*Tcp)(nil),                   // 7: rhobs.synthetics.v1.Tcp
+	(*Icmp)(nil),                  // 8: rhobs.synthetics.v1.Icmp
+	(*ProbeAuth)(nil),             // 9: rhobs.synthetics.v1.ProbeAuth
+	(*StatusTransition)(nil),      // 10: rhobs.synthetics.v1.StatusTransition
+	(*TargetStatus)(nil),          // 11: rhobs.synthetics.v1.TargetStatus
+	(*ListProbesRequest)(nil),     // 12: rhobs.synthetics.v1.ListProbesRequest
+	(*ListProbesResponse)(nil),    // 13: rhobs.synthetics.v1.ListProbesResponse
+	(*GetProbeRequest)(nil),       // 14: rhobs.synthetics.v1.GetProbeRequest
+	(*CreateProbeRequest)(nil),    // 15: rhobs.synthetics.v1.CreateProbeRequest
+	(*UpdateProbeRequest)(nil),    // 16: rhobs.synthetics.v1.UpdateProbeRequest
+	(*DeleteProbeRequest)(nil),    // 17: rhobs.synthetics.v1.DeleteProbeRequest
+	(*DeleteProbeResponse)(nil),   // 18: rhobs.synthetics.v1.DeleteProbeResponse
+	(*WatchRequest)(nil),          // 19: rhobs.synthetics.v1.WatchRequest
+	(*WatchEvent)(nil),            // 20: rhobs.synthetics.v1.WatchEvent
+	nil,                           // 21: rhobs.synthetics.v1.Probe.LabelsEntry
+	nil,                           // 22: rhobs.synthetics.v1.ListProbesResponse.FeaturesEntry
+	nil,                           // 23: rhobs.synthetics.v1.CreateProbeRequest.LabelsEntry
+	nil,                           // 24: rhobs.synthetics.v1.CreateProbeRequest.VariablesEntry
+	nil,                           // 25: rhobs.synthetics.v1.UpdateProbeRequest.LabelsEntry
+	(*timestamppb.Timestamp)(nil), // 26: google.protobuf.Timestamp
+}
+var file_probes_proto_depIdxs = []int32{
+	21, // 0: rhobs.synthetics.v1.Probe.labels:type_name -> rhobs.synthetics.v1.Probe.LabelsEntry
+	2,  // 1: rhobs.synthetics.v1.Probe.alerting:type_name -> rhobs.synthetics.v1.Alerting
+	9,  // 2: rhobs.synthetics.v1.Probe.auth:type_name -> rhobs.synthetics.v1.ProbeAuth
+	26, // 3: rhobs.synthetics.v1.Probe.creation_timestamp:type_name -> google.protobuf.Timestamp
+	26, // 4: rhobs.synthetics.v1.Probe.update_timestamp:type_name -> google.protobuf.Timestamp
+	26, // 5: rhobs.synthetics.v1.Probe.deletion_timestamp:type_name -> google.protobuf.Timestamp
+	10, // 6: rhobs.synthetics.v1.Probe.status_history:type_name -> rhobs.synthetics.v1.StatusTransition
+	11, // 7: rhobs.synthetics.v1.Probe.target_statuses:type_name -> rhobs.synthetics.v1.TargetStatus
+	6,  // 8: rhobs.synthetics.v1.Probe.dns:type_name -> rhobs.synthetics.v1.Dns
+	7,  // 9: rhobs.synthetics.v1.Probe.tcp:type_name -> rhobs.synthetics.v1.Tcp
+	8,  // 10: rhobs.synthetics.v1.Probe.icmp:type_name -> rhobs.synthetics.v1.Icmp
+	4,  // 11: rhobs.synthetics.v1.Probe.assertions:type_name -> rhobs.synthetics.v1.Assertions
+	3,  // 12: rhobs.synthetics.v1.Probe.owner:type_name -> rhobs.synthetics.v1.Owner
+	5,  // 13: rhobs.synthetics.v1.Assertions.headers:type_name -> rhobs.synthetics.v1.HeaderAssertion
+	26, // 14: rhobs.synthetics.v1.StatusTransition.timestamp:type_name -> google.protobuf.Timestamp
+	26, // 15: rhobs.synthetics.v1.TargetStatus.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 16: rhobs.synthetics.v1.ListProbesResponse.probes:type_name -> rhobs.synthetics.v1.Probe
+	22, // 17: rhobs.synthetics.v1.ListProbesResponse.features:type_name -> rhobs.synthetics.v1.ListProbesResponse.FeaturesEntry
+	23, // 18: rhobs.synthetics.v1.CreateProbeRequest.labels:type_name -> rhobs.synthetics.v1.CreateProbeRequest.LabelsEntry
+	2,  // 19: rhobs.synthetics.v1.CreateProbeRequest.alerting:type_name -> rhobs.synthetics.v1.Alerting
+	9,  // 20: rhobs.synthetics.v1.CreateProbeRequest.auth:type_name -> rhobs.synthetics.v1.ProbeAuth
+	24, // 21: rhobs.synthetics.v1.CreateProbeRequest.variables:type_name -> rhobs.synthetics.v1.CreateProbeRequest.VariablesEntry
+	6,  // 22: rhobs.synthetics.v1.CreateProbeRequest.dns:type_name -> rhobs.synthetics.v1.Dns
+	7,  // 23: rhobs.synthetics.v1.CreateProbeRequest.tcp:type_name -> rhobs.synthetics.v1.Tcp
+	8,  // 24: rhobs.synthetics.v1.CreateProbeRequest.icmp:type_name -> rhobs.synthetics.v1.Icmp
+	4,  // 25: rhobs.synthetics.v1.CreateProbeRequest.assertions:type_name -> rhobs.synthetics.v1.Assertions
+	3,  // 26: rhobs.synthetics.v1.CreateProbeRequest.owner:type_name -> rhobs.synthetics.v1.Owner
+	25, // 27: rhobs.synthetics.v1.UpdateProbeRequest.labels:type_name -> rhobs.synthetics.v1.UpdateProbeRequest.LabelsEntry
+	2,  // 28: rhobs.synthetics.v1.UpdateProbeRequest.alerting:type_name -> rhobs.synthetics.v1.Alerting
+	9,  // 29: rhobs.synthetics.v1.UpdateProbeRequest.auth:type_name -> rhobs.synthetics.v1.ProbeAuth
+	11, // 30: rhobs.synthetics.v1.UpdateProbeRequest.target_statuses:type_name -> rhobs.synthetics.v1.TargetStatus
+	6,  // 31: rhobs.synthetics.v1.UpdateProbeRequest.dns:type_name -> rhobs.synthetics.v1.Dns
+	7,  // 32: rhobs.synthetics.v1.UpdateProbeRequest.tcp:type_name -> rhobs.synthetics.v1.Tcp
+	8,  // 33: rhobs.synthetics.v1.UpdateProbeRequest.icmp:type_name -> rhobs.synthetics.v1.Icmp
+	4,  // 34: rhobs.synthetics.v1.UpdateProbeRequest.assertions:type_name -> rhobs.synthetics.v1.Assertions
+	3,  // 35: rhobs.synthetics.v1.UpdateProbeRequest.owner:type_name -> rhobs.synthetics.v1.Owner
+	0,  // 36: rhobs.synthetics.v1.WatchEvent.type:type_name -> rhobs.synthetics.v1.WatchEvent.Type
+	1,  // 37: rhobs.synthetics.v1.WatchEvent.probe:type_name -> rhobs.synthetics.v1.Probe
+	12, // 38: rhobs.synthetics.v1.Probes.ListProbes:input_type -> rhobs.synthetics.v1.ListProbesRequest
+	14, // 39: rhobs.synthetics.v1.Probes.GetProbe:input_type -> rhobs.synthetics.v1.GetProbeRequest
+	15, // 40: rhobs.synthetics.v1.Probes.CreateProbe:input_type -> rhobs.synthetics.v1.CreateProbeRequest
+	16, // 41: rhobs.synthetics.v1.Probes.UpdateProbe:input_type -> rhobs.synthetics.v1.UpdateProbeRequest
+	17, // 42: rhobs.synthetics.v1.Probes.DeleteProbe:input_type -> rhobs.synthetics.v1.DeleteProbeRequest
+	19, // 43: rhobs.synthetics.v1.Probes.Watch:input_type -> rhobs.synthetics.v1.WatchRequest
+	13, // 44: rhobs.synthetics.v1.Probes.ListProbes:output_type -> rhobs.synthetics.v1.ListProbesResponse
+	1,  // 45: rhobs.synthetics.v1.Probes.GetProbe:output_type -> rhobs.synthetics.v1.Probe
+	1,  // 46: rhobs.synthetics.v1.Probes.CreateProbe:output_type -> rhobs.synthetics.v1.Probe
+	1,  // 47: rhobs.synthetics.v1.Probes.UpdateProbe:output_type -> rhobs.synthetics.v1.Probe
+	18, // 48: rhobs.synthetics.v1.Probes.DeleteProbe:output_type -> rhobs.synthetics.v1.DeleteProbeResponse
+	20, // 49: rhobs.synthetics.v1.Probes.Watch:output_type -> rhobs.synthetics.v1.WatchEvent
+	44, // [44:50] is the sub-list for method output_type
+	38, // [38:44] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
+}
+
+func init() { file_probes_proto_init() }
+func file_probes_proto_init() {
+	if File_probes_proto != nil {
+		return
+	}
+	file_probes_proto_msgTypes[1].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[2].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[3].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[4].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[5].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[6].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[7].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[8].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[14].OneofWrappers = []any{}
+	file_probes_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_probes_proto_rawDesc), len(file_probes_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_probes_proto_goTypes,
+		DependencyIndexes: file_probes_proto_depIdxs,
+		EnumInfos:         file_probes_proto_enumTypes,
+		MessageInfos:      file_probes_proto_msgTypes,
+	}.Build()
+	File_probes_proto = out.File
+	file_probes_proto_goTypes = nil
+	file_probes_proto_depIdxs = nil
+}
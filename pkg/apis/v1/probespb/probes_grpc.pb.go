@@ -0,0 +1,345 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: probes.proto
+
+package probespb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Probes_ListProbes_FullMethodName  = "/rhobs.synthetics.v1.Probes/ListProbes"
+	Probes_GetProbe_FullMethodName    = "/rhobs.synthetics.v1.Probes/GetProbe"
+	Probes_CreateProbe_FullMethodName = "/rhobs.synthetics.v1.Probes/CreateProbe"
+	Probes_UpdateProbe_FullMethodName = "/rhobs.synthetics.v1.Probes/UpdateProbe"
+	Probes_DeleteProbe_FullMethodName = "/rhobs.synthetics.v1.Probes/DeleteProbe"
+	Probes_Watch_FullMethodName       = "/rhobs.synthetics.v1.Probes/Watch"
+)
+
+// ProbesClient is the client API for Probes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Probes serves the probe operations of the REST API over gRPC, plus Watch,
+// which streams changes to probes. Each call is handled as the REST request
+// it mirrors, so it is validated, limited and audited the same way, and
+// fails with the gRPC code matching the REST status. Metadata carries the
+// REST headers: authorization, x-tenant, x-forwarded-user and
+// idempotency-key.
+type ProbesClient interface {
+	// ListProbes mirrors GET /probes.
+	ListProbes(ctx context.Context, in *ListProbesRequest, opts ...grpc.CallOption) (*ListProbesResponse, error)
+	// GetProbe mirrors GET /probes/{probe_id}.
+	GetProbe(ctx context.Context, in *GetProbeRequest, opts ...grpc.CallOption) (*Probe, error)
+	// CreateProbe mirrors POST /probes.
+	CreateProbe(ctx context.Context, in *CreateProbeRequest, opts ...grpc.CallOption) (*Probe, error)
+	// UpdateProbe mirrors PATCH /probes/{probe_id}.
+	UpdateProbe(ctx context.Context, in *UpdateProbeRequest, opts ...grpc.CallOption) (*Probe, error)
+	// DeleteProbe mirrors DELETE /probes/{probe_id}.
+	DeleteProbe(ctx context.Context, in *DeleteProbeRequest, opts ...grpc.CallOption) (*DeleteProbeResponse, error)
+	// Watch sends an ADDED event for each matching probe, then an event for
+	// each change to the probes matching, until the call is cancelled or the
+	// server shuts down.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+}
+
+type probesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProbesClient(cc grpc.ClientConnInterface) ProbesClient {
+	return &probesClient{cc}
+}
+
+func (c *probesClient) ListProbes(ctx context.Context, in *ListProbesRequest, opts ...grpc.CallOption) (*ListProbesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProbesResponse)
+	err := c.cc.Invoke(ctx, Probes_ListProbes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *probesClient) GetProbe(ctx context.Context, in *GetProbeRequest, opts ...grpc.CallOption) (*Probe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Probe)
+	err := c.cc.Invoke(ctx, Probes_GetProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *probesClient) CreateProbe(ctx context.Context, in *CreateProbeRequest, opts ...grpc.CallOption) (*Probe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Probe)
+	err := c.cc.Invoke(ctx, Probes_CreateProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *probesClient) UpdateProbe(ctx context.Context, in *UpdateProbeRequest, opts ...grpc.CallOption) (*Probe, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Probe)
+	err := c.cc.Invoke(ctx, Probes_UpdateProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *probesClient) DeleteProbe(ctx context.Context, in *DeleteProbeRequest, opts ...grpc.CallOption) (*DeleteProbeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteProbeResponse)
+	err := c.cc.Invoke(ctx, Probes_DeleteProbe_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *probesClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Probes_ServiceDesc.Streams[0], Probes_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Probes_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
+// ProbesServer is the server API for Probes service.
+// All implementations must embed UnimplementedProbesServer
+// for forward compatibility.
+//
+// Probes serves the probe operations of the REST API over gRPC, plus Watch,
+// which streams changes to probes. Each call is handled as the REST request
+// it mirrors, so it is validated, limited and audited the same way, and
+// fails with the gRPC code matching the REST status. Metadata carries the
+// REST headers: authorization, x-tenant, x-forwarded-user and
+// idempotency-key.
+type ProbesServer interface {
+	// ListProbes mirrors GET /probes.
+	ListProbes(context.Context, *ListProbesRequest) (*ListProbesResponse, error)
+	// GetProbe mirrors GET /probes/{probe_id}.
+	GetProbe(context.Context, *GetProbeRequest) (*Probe, error)
+	// CreateProbe mirrors POST /probes.
+	CreateProbe(context.Context, *CreateProbeRequest) (*Probe, error)
+	// UpdateProbe mirrors PATCH /probes/{probe_id}.
+	UpdateProbe(context.Context, *UpdateProbeRequest) (*Probe, error)
+	// DeleteProbe mirrors DELETE /probes/{probe_id}.
+	DeleteProbe(context.Context, *DeleteProbeRequest) (*DeleteProbeResponse, error)
+	// Watch sends an ADDED event for each matching probe, then an event for
+	// each change to the probes matching, until the call is cancelled or the
+	// server shuts down.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	mustEmbedUnimplementedProbesServer()
+}
+
+// UnimplementedProbesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedProbesServer struct{}
+
+func (UnimplementedProbesServer) ListProbes(context.Context, *ListProbesRequest) (*ListProbesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProbes not implemented")
+}
+func (UnimplementedProbesServer) GetProbe(context.Context, *GetProbeRequest) (*Probe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProbe not implemented")
+}
+func (UnimplementedProbesServer) CreateProbe(context.Context, *CreateProbeRequest) (*Probe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProbe not implemented")
+}
+func (UnimplementedProbesServer) UpdateProbe(context.Context, *UpdateProbeRequest) (*Probe, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProbe not implemented")
+}
+func (UnimplementedProbesServer) DeleteProbe(context.Context, *DeleteProbeRequest) (*DeleteProbeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProbe not implemented")
+}
+func (UnimplementedProbesServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedProbesServer) mustEmbedUnimplementedProbesServer() {}
+func (UnimplementedProbesServer) testEmbeddedByValue()                {}
+
+// UnsafeProbesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProbesServer will
+// result in compilation errors.
+type UnsafeProbesServer interface {
+	mustEmbedUnimplementedProbesServer()
+}
+
+func RegisterProbesServer(s grpc.ServiceRegistrar, srv ProbesServer) {
+	// If the following call pancis, it indicates UnimplementedProbesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Probes_ServiceDesc, srv)
+}
+
+func _Probes_ListProbes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProbesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProbesServer).ListProbes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Probes_ListProbes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProbesServer).ListProbes(ctx, req.(*ListProbesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Probes_GetProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProbesServer).GetProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Probes_GetProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProbesServer).GetProbe(ctx, req.(*GetProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Probes_CreateProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProbesServer).CreateProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Probes_CreateProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProbesServer).CreateProbe(ctx, req.(*CreateProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Probes_UpdateProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProbesServer).UpdateProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Probes_UpdateProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProbesServer).UpdateProbe(ctx, req.(*UpdateProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Probes_DeleteProbe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProbeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProbesServer).DeleteProbe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Probes_DeleteProbe_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProbesServer).DeleteProbe(ctx, req.(*DeleteProbeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Probes_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProbesServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Probes_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
+// Probes_ServiceDesc is the grpc.ServiceDesc for Probes service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Probes_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rhobs.synthetics.v1.Probes",
+	HandlerType: (*ProbesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListProbes",
+			Handler:    _Probes_ListProbes_Handler,
+		},
+		{
+			MethodName: "GetProbe",
+			Handler:    _Probes_GetProbe_Handler,
+		},
+		{
+			MethodName: "CreateProbe",
+			Handler:    _Probes_CreateProbe_Handler,
+		},
+		{
+			MethodName: "UpdateProbe",
+			Handler:    _Probes_UpdateProbe_Handler,
+		},
+		{
+			MethodName: "DeleteProbe",
+			Handler:    _Probes_DeleteProbe_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Probes_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "probes.proto",
+}
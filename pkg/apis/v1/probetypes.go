@@ -0,0 +1,217 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ProbeSettings are the settings of a probe of a type other than http:
+// DnsSchema, TcpSchema or IcmpSchema. The probe's static URL is derived from
+// them, so such probes are stored and checked for duplicates like any other.
+type ProbeSettings interface {
+	// Validate checks the settings against the constraints of the spec.
+	Validate() error
+	// URL returns the static URL of probes with the settings.
+	URL() string
+	// Module returns the module probes with the settings are run with,
+	// which is also the name of the settings' field.
+	Module() ProbeModuleSchema
+}
+
+// TypeSettings returns the one of dns, tcp and icmp that is set, nil if none
+// is, as for http probes, or an error if several are.
+func TypeSettings(dns *DnsSchema, tcp *TcpSchema, icmp *IcmpSchema) (ProbeSettings, error) {
+	var set []ProbeSettings
+	if dns != nil {
+		set = append(set, *dns)
+	}
+	if tcp != nil {
+		set = append(set, *tcp)
+	}
+	if icmp != nil {
+		set = append(set, *icmp)
+	}
+	switch len(set) {
+	case 0:
+		return nil, nil
+	case 1:
+		return set[0], nil
+	}
+	return nil, fmt.Errorf("only one of dns, tcp and icmp can be set, got %s and %s", set[0].Module(), set[1].Module())
+}
+
+// TypeSettings returns the settings of the probe's type, nil for http probes.
+func (p ProbeObject) TypeSettings() (ProbeSettings, error) {
+	return TypeSettings(p.Dns, p.Tcp, p.Icmp)
+}
+
+// SetTypeSettings replaces the settings of the probe's type with a copy of
+// settings, nil turning it into an http probe. The static URL and module are
+// left alone.
+func (p *ProbeObject) SetTypeSettings(settings ProbeSettings) {
+	p.Dns, p.Tcp, p.Icmp = nil, nil, nil
+	switch s := settings.(type) {
+	case DnsSchema:
+		if s.ExpectedAnswers != nil {
+			s.ExpectedAnswers = new(slices.Clone(*s.ExpectedAnswers))
+		}
+		if s.Resolver != nil {
+			s.Resolver = new(*s.Resolver)
+		}
+		p.Dns = &s
+	case TcpSchema:
+		if s.Tls != nil {
+			s.Tls = new(*s.Tls)
+		}
+		p.Tcp = &s
+	case IcmpSchema:
+		if s.PacketCount != nil {
+			s.PacketCount = new(*s.PacketCount)
+		}
+		p.Icmp = &s
+	}
+}
+
+// Validate checks the query of a dns probe: a valid DNS name, a known record
+// type, non-empty expected answers listed once, and a resolver given as
+// host[:port].
+func (d DnsSchema) Validate() error {
+	if !isDNSName(d.QueryName) {
+		return fmt.Errorf("invalid dns query_name %q, expected a DNS name such as api.example.com", d.QueryName)
+	}
+	if !d.RecordType.Valid() {
+		return fmt.Errorf("unknown dns record_type %q, expected one of %v", d.RecordType, DnsRecordTypes())
+	}
+	if d.ExpectedAnswers != nil {
+		answers := *d.ExpectedAnswers
+		for i, answer := range answers {
+			if answer == "" {
+				return fmt.Errorf("dns expected_answers[%d] is empty", i)
+			}
+			if slices.Contains(answers[:i], answer) {
+				return fmt.Errorf("dns expected_answers[%d] %q is listed twice", i, answer)
+			}
+		}
+	}
+	if d.Resolver != nil {
+		host, port := *d.Resolver, ""
+		if h, p, err := net.SplitHostPort(*d.Resolver); err == nil {
+			host, port = h, p
+		}
+		if port != "" && !isPort(port) {
+			return fmt.Errorf("invalid dns resolver %q, expected host[:port]", *d.Resolver)
+		}
+		if !isHost(host) {
+			return fmt.Errorf("invalid dns resolver %q, expected host[:port]", *d.Resolver)
+		}
+	}
+	return nil
+}
+
+// URL returns the static URL of a dns probe: the RFC 4501 URI of its query,
+// e.g. dns://10.0.0.10:53/api.example.com?type=A, or dns:api.example.com?type=A
+// when it uses the agent's resolver. The name is lower-cased and loses its
+// trailing dot, so equivalent queries get the same URL.
+func (d DnsSchema) URL() string {
+	name := strings.ToLower(strings.TrimSuffix(d.QueryName, "."))
+	query := "type=" + string(d.RecordType)
+	if d.Resolver == nil {
+		return (&url.URL{Scheme: "dns", Opaque: name, RawQuery: query}).String()
+	}
+	return (&url.URL{Scheme: "dns", Host: urlHost(*d.Resolver), Path: "/" + name, RawQuery: query}).String()
+}
+
+// Module returns Dns.
+func (DnsSchema) Module() ProbeModuleSchema {
+	return Dns
+}
+
+// Validate checks the address of a tcp probe: a host name or IP address and
+// a port.
+func (t TcpSchema) Validate() error {
+	host, port, err := net.SplitHostPort(t.Address)
+	if err != nil || !isHost(host) || !isPort(port) {
+		return fmt.Errorf("invalid tcp address %q, expected host:port", t.Address)
+	}
+	return nil
+}
+
+// URL returns the static URL of a tcp probe, e.g. tcp://db.example.com:5432.
+// The host is lower-cased, so equivalent addresses get the same URL.
+func (t TcpSchema) URL() string {
+	return (&url.URL{Scheme: "tcp", Host: urlHost(t.Address)}).String()
+}
+
+// Module returns Tcp.
+func (TcpSchema) Module() ProbeModuleSchema {
+	return Tcp
+}
+
+// Validate checks the host of an icmp probe and the number of echo requests
+// it sends.
+func (i IcmpSchema) Validate() error {
+	if !isHost(i.Host) {
+		return fmt.Errorf("invalid icmp host %q, expected a host name or IP address", i.Host)
+	}
+	if i.PacketCount != nil && (*i.PacketCount < 1 || *i.PacketCount > 10) {
+		return errors.New("icmp packet_count must be between 1 and 10")
+	}
+	return nil
+}
+
+// URL returns the static URL of an icmp probe, e.g. icmp://203.0.113.7. The
+// host is lower-cased, so equivalent hosts get the same URL.
+func (i IcmpSchema) URL() string {
+	return (&url.URL{Scheme: "icmp", Host: urlHost(i.Host)}).String()
+}
+
+// Module returns Icmp.
+func (IcmpSchema) Module() ProbeModuleSchema {
+	return Icmp
+}
+
+// isHost reports whether s is an IP address or a DNS name.
+func isHost(s string) bool {
+	return net.ParseIP(s) != nil || isDNSName(s)
+}
+
+// isPort reports whether s is a port number.
+func isPort(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 1 && n <= 65535
+}
+
+// urlHost returns a host or host:port lower-cased for the authority of a
+// URL, with IPv6 addresses given without a port in brackets.
+func urlHost(host string) string {
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		host = "[" + host + "]"
+	}
+	return strings.ToLower(host)
+}
+
+// isDNSName reports whether s is a DNS name of at most 253 characters whose
+// labels are letters, digits, hyphens and underscores, the latter for names
+// such as _https._tcp.example.com, with an optional trailing dot.
+func isDNSName(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for label := range strings.SplitSeq(s, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
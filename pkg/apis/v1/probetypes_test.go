@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSProbe(t *testing.T) {
+	query := DnsSchema{QueryName: "API.example.com.", RecordType: A, ExpectedAnswers: &[]string{"203.0.113.7"}}
+	assert.Equal(t, "dns:api.example.com?type=A", query.URL())
+	withResolver := query
+	withResolver.Resolver = new("10.0.0.10:53")
+	assert.Equal(t, "dns://10.0.0.10:53/api.example.com?type=A", withResolver.URL())
+	withResolver.Resolver = new("2001:db8::53")
+	assert.Equal(t, "dns://[2001:db8::53]/api.example.com?type=A", withResolver.URL())
+
+	b := NewDNSProbe(query)
+	probe, err := b.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "dns:api.example.com?type=A", probe.StaticUrl)
+	assert.Equal(t, Dns, *probe.Module)
+	request, err := b.CreateRequest()
+	require.NoError(t, err)
+	assert.Empty(t, request.StaticUrl, "the server derives it")
+	assert.Equal(t, query, *request.Dns)
+
+	for name, tc := range map[string]struct {
+		query DnsSchema
+		err   string
+	}{
+		"empty name":         {DnsSchema{RecordType: A}, `invalid dns query_name ""`},
+		"invalid name":       {DnsSchema{QueryName: "api..example.com", RecordType: A}, `invalid dns query_name "api..example.com"`},
+		"unknown type":       {DnsSchema{QueryName: "example.com", RecordType: "ANY"}, `unknown dns record_type "ANY"`},
+		"empty answer":       {DnsSchema{QueryName: "example.com", RecordType: A, ExpectedAnswers: &[]string{""}}, "dns expected_answers[0] is empty"},
+		"duplicate answer":   {DnsSchema{QueryName: "example.com", RecordType: A, ExpectedAnswers: &[]string{"a", "a"}}, `dns expected_answers[1] "a" is listed twice`},
+		"invalid port":       {DnsSchema{QueryName: "example.com", RecordType: A, Resolver: new("10.0.0.10:dns")}, `invalid dns resolver "10.0.0.10:dns"`},
+		"invalid resolver":   {DnsSchema{QueryName: "example.com", RecordType: A, Resolver: new("https://10.0.0.10")}, `invalid dns resolver "https://10.0.0.10"`},
+		"valid SRV resolver": {DnsSchema{QueryName: "_https._tcp.example.com", RecordType: SRV, Resolver: new("ns1.example.com")}, ""},
+	} {
+		err := tc.query.Validate()
+		if tc.err == "" {
+			assert.NoError(t, err, name)
+			continue
+		}
+		assert.ErrorContains(t, err, tc.err, name)
+	}
+
+	wrongModule := NewDNSProbe(query).Module(Http2xx)
+	_, err = wrongModule.Build()
+	assert.ErrorContains(t, err, "dns settings require the dns module, not http_2xx")
+	probe.StaticUrl = "https://example.com"
+	assert.ErrorContains(t, probe.Validate(), `must be "dns:api.example.com?type=A"`)
+}
+
+func TestTCPAndICMPProbes(t *testing.T) {
+	tcp := TcpSchema{Address: "DB.example.com:5432", Tls: new(true)}
+	assert.Equal(t, "tcp://db.example.com:5432", tcp.URL())
+	assert.Equal(t, "tcp://[2001:db8::1]:5432", TcpSchema{Address: "[2001:db8::1]:5432"}.URL())
+	probe, err := NewTCPProbe(tcp).Build()
+	require.NoError(t, err)
+	assert.Equal(t, Tcp, *probe.Module)
+	assert.Equal(t, &tcp, probe.Tcp)
+
+	icmp := IcmpSchema{Host: "2001:db8::1", PacketCount: new(3)}
+	assert.Equal(t, "icmp://[2001:db8::1]", icmp.URL())
+	request, err := NewICMPProbe(icmp).CreateRequest()
+	require.NoError(t, err)
+	assert.Empty(t, request.StaticUrl, "the server derives it")
+	assert.Equal(t, Icmp, *request.Module)
+	assert.Equal(t, &icmp, request.Icmp)
+
+	for name, tc := range map[string]struct {
+		settings ProbeSettings
+		err      string
+	}{
+		"tcp without port":      {TcpSchema{Address: "db.example.com"}, `invalid tcp address "db.example.com"`},
+		"tcp with invalid port": {TcpSchema{Address: "db.example.com:0"}, `invalid tcp address "db.example.com:0"`},
+		"tcp without host":      {TcpSchema{Address: ":5432"}, `invalid tcp address ":5432"`},
+		"icmp with port":        {IcmpSchema{Host: "203.0.113.7:80"}, `invalid icmp host "203.0.113.7:80"`},
+		"icmp packet count":     {IcmpSchema{Host: "203.0.113.7", PacketCount: new(11)}, "icmp packet_count must be between 1 and 10"},
+	} {
+		assert.ErrorContains(t, tc.settings.Validate(), tc.err, name)
+	}
+
+	_, err = TypeSettings(&DnsSchema{QueryName: "example.com", RecordType: A}, &tcp, nil)
+	assert.EqualError(t, err, "only one of dns, tcp and icmp can be set, got dns and tcp")
+	settings, err := TypeSettings(nil, nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, settings, "http probes have none")
+
+	both := probe
+	both.Icmp = &icmp
+	assert.ErrorContains(t, both.Validate(), "only one of dns, tcp and icmp can be set")
+	probe.Module = new(Icmp)
+	assert.ErrorContains(t, probe.Validate(), "tcp settings require the tcp module, not icmp")
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"fmt"
+	"slices"
+)
+
+// maxRegions is the maxItems of RegionsSchema.
+const maxRegions = 20
+
+// ValidateRegions checks the regions a probe is limited to: valid region
+// names, each listed once.
+func ValidateRegions(regions RegionsSchema) error {
+	if len(regions) > maxRegions {
+		return fmt.Errorf("a probe can be limited to at most %d regions, got %d", maxRegions, len(regions))
+	}
+	for i, region := range regions {
+		if !IsRegion(region) {
+			return fmt.Errorf("invalid regions[%d] %q, expected a lowercase name such as us-east-1", i, region)
+		}
+		if slices.Contains(regions[:i], region) {
+			return fmt.Errorf("regions[%d] %q is listed twice", i, region)
+		}
+	}
+	return nil
+}
+
+// InRegion reports whether agents in region may run the probe: any agent if
+// the probe is not limited to regions, otherwise only agents in one of them.
+// Agents without a region only run probes that are not limited.
+func (p ProbeObject) InRegion(region string) bool {
+	if p.Regions == nil || len(*p.Regions) == 0 {
+		return true
+	}
+	return slices.Contains(*p.Regions, region)
+}
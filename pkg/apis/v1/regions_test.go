@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegions(t *testing.T) {
+	probe, err := NewProbe("https://example.com").Regions("us-east-1", "eu-west-1").Build()
+	require.NoError(t, err)
+	assert.True(t, probe.InRegion("eu-west-1"))
+	assert.False(t, probe.InRegion("us-west-2"))
+	assert.False(t, probe.InRegion(""), "agents without a region only run probes without regions")
+
+	unlimited, err := NewProbe("https://example.com").Build()
+	require.NoError(t, err)
+	assert.True(t, unlimited.InRegion("us-west-2"))
+	assert.True(t, unlimited.InRegion(""))
+
+	for name, tc := range map[string]struct {
+		regions RegionsSchema
+		err     string
+	}{
+		"uppercase": {RegionsSchema{"US-EAST-1"}, `invalid regions[0] "US-EAST-1"`},
+		"empty":     {RegionsSchema{"us-east-1", ""}, `invalid regions[1] ""`},
+		"duplicate": {RegionsSchema{"us-east-1", "us-east-1"}, `regions[1] "us-east-1" is listed twice`},
+		"too many":  {make(RegionsSchema, 21), "at most 20 regions, got 21"},
+	} {
+		assert.ErrorContains(t, ValidateRegions(tc.regions), tc.err, name)
+	}
+	_, err = NewProbe("https://example.com").Regions("us_east").Build()
+	assert.ErrorContains(t, err, `invalid regions[0] "us_east"`)
+}
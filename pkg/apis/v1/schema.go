@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Constraints are the validation keywords of a schema of the OpenAPI spec.
+type Constraints struct {
+	Format    string
+	Pattern   string
+	Enum      []string
+	MinLength uint64
+	MaxLength *uint64
+	Minimum   *float64
+	Maximum   *float64
+}
+
+// embeddedSchemas are the component schemas of the embedded spec, parsed once.
+var embeddedSchemas = sync.OnceValue(func() openapi3.Schemas {
+	swagger, err := GetSwagger()
+	if err != nil {
+		// The spec is embedded at build time, so this only fails on a broken build.
+		panic(fmt.Sprintf("failed to load the embedded OpenAPI spec: %v", err))
+	}
+	return swagger.Components.Schemas
+})
+
+// SchemaConstraints returns the constraints of the component schema name,
+// e.g. "DurationSchema", as given by the spec this package is generated from.
+func SchemaConstraints(name string) (Constraints, bool) {
+	ref, ok := embeddedSchemas()[name]
+	if !ok || ref.Value == nil {
+		return Constraints{}, false
+	}
+	s := ref.Value
+	c := Constraints{
+		Format:    s.Format,
+		Pattern:   s.Pattern,
+		MinLength: s.MinLength,
+		MaxLength: s.MaxLength,
+		Minimum:   s.Min,
+		Maximum:   s.Max,
+	}
+	for _, value := range s.Enum {
+		c.Enum = append(c.Enum, fmt.Sprint(value))
+	}
+	return c, true
+}
+
+// enum returns the values of the enum schema name, in the order of the spec.
+func enum[T ~string](name string) []T {
+	c, _ := SchemaConstraints(name)
+	values := make([]T, len(c.Enum))
+	for i, value := range c.Enum {
+		values[i] = T(value)
+	}
+	return values
+}
+
+var (
+	durationPattern = sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(embeddedSchemas()["DurationSchema"].Value.Pattern)
+	})
+	ownerTeamPattern = sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(embeddedSchemas()["OwnerSchema"].Value.Properties["team"].Value.Pattern)
+	})
+	regionPattern = sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(embeddedSchemas()["RegionSchema"].Value.Pattern)
+	})
+	urlHashPattern = sync.OnceValue(func() *regexp.Regexp {
+		return regexp.MustCompile(embeddedSchemas()["ProbeObject"].Value.Properties["url_hash"].Value.Pattern)
+	})
+)
+
+// ProbeStatuses returns the statuses a probe may have.
+func ProbeStatuses() []StatusSchema {
+	return enum[StatusSchema]("StatusSchema")
+}
+
+// ProbeModules returns the blackbox exporter modules a probe may be run with.
+func ProbeModules() []ProbeModuleSchema {
+	return enum[ProbeModuleSchema]("ProbeModuleSchema")
+}
+
+// Severities returns the severities of the alerts raised by a probe.
+func Severities() []SeveritySchema {
+	return enum[SeveritySchema]("SeveritySchema")
+}
+
+// DnsRecordTypes returns the record types a dns probe may query.
+func DnsRecordTypes() []DnsRecordTypeSchema {
+	return enum[DnsRecordTypeSchema]("DnsRecordTypeSchema")
+}
+
+// WebhookEventTypes returns the events a webhook can subscribe to.
+func WebhookEventTypes() []WebhookEventType {
+	return enum[WebhookEventType]("WebhookEventType")
+}
+
+// Valid reports whether the status is one the spec defines.
+func (s StatusSchema) Valid() bool {
+	return slices.Contains(ProbeStatuses(), s)
+}
+
+// Valid reports whether the module is one the spec defines.
+func (m ProbeModuleSchema) Valid() bool {
+	return slices.Contains(ProbeModules(), m)
+}
+
+// Valid reports whether the severity is one the spec defines.
+func (s SeveritySchema) Valid() bool {
+	return slices.Contains(Severities(), s)
+}
+
+// Valid reports whether the record type is one the spec defines.
+func (t DnsRecordTypeSchema) Valid() bool {
+	return slices.Contains(DnsRecordTypes(), t)
+}
+
+// Valid reports whether the event type is one the spec defines.
+func (e WebhookEventType) Valid() bool {
+	return slices.Contains(WebhookEventTypes(), e)
+}
+
+// IsDuration reports whether s is a duration in the format of DurationSchema.
+func IsDuration(s string) bool {
+	return durationPattern().MatchString(s)
+}
+
+// IsRegion reports whether s is a region name in the format of RegionSchema.
+func IsRegion(s string) bool {
+	return regionPattern().MatchString(s)
+}
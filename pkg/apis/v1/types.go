@@ -12,10 +12,12 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/oapi-codegen/runtime"
@@ -23,6 +25,80 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+// Defines values for AuditOperation.
+const (
+	CreateProbe            AuditOperation = "createProbe"
+	DeleteProbe            AuditOperation = "deleteProbe"
+	RemoveTerminatingProbe AuditOperation = "removeTerminatingProbe"
+	UpdateProbe            AuditOperation = "updateProbe"
+	UpdateProbeGroup       AuditOperation = "updateProbeGroup"
+)
+
+// Defines values for BundleFormat.
+const (
+	Json   BundleFormat = "json"
+	Ndjson BundleFormat = "ndjson"
+	Yaml   BundleFormat = "yaml"
+)
+
+// Defines values for DnsRecordTypeSchema.
+const (
+	A     DnsRecordTypeSchema = "A"
+	AAAA  DnsRecordTypeSchema = "AAAA"
+	CAA   DnsRecordTypeSchema = "CAA"
+	CNAME DnsRecordTypeSchema = "CNAME"
+	MX    DnsRecordTypeSchema = "MX"
+	NS    DnsRecordTypeSchema = "NS"
+	PTR   DnsRecordTypeSchema = "PTR"
+	SOA   DnsRecordTypeSchema = "SOA"
+	SRV   DnsRecordTypeSchema = "SRV"
+	TXT   DnsRecordTypeSchema = "TXT"
+)
+
+// Defines values for ImportConflictStrategy.
+const (
+	Fail      ImportConflictStrategy = "fail"
+	Overwrite ImportConflictStrategy = "overwrite"
+	Skip      ImportConflictStrategy = "skip"
+)
+
+// Defines values for ProbeGroupStatus.
+const (
+	Degraded    ProbeGroupStatus = "degraded"
+	Deleting    ProbeGroupStatus = "deleting"
+	Paused      ProbeGroupStatus = "paused"
+	Progressing ProbeGroupStatus = "progressing"
+	Ready       ProbeGroupStatus = "ready"
+)
+
+// Defines values for ProbeModuleSchema.
+const (
+	Dns     ProbeModuleSchema = "dns"
+	Http2xx ProbeModuleSchema = "http_2xx"
+	Icmp    ProbeModuleSchema = "icmp"
+	Tcp     ProbeModuleSchema = "tcp"
+)
+
+// Defines values for ProbeProblemReason.
+const (
+	StaleFailed        ProbeProblemReason = "stale_failed"
+	StuckPending       ProbeProblemReason = "stuck_pending"
+	TerminatingTooLong ProbeProblemReason = "terminating_too_long"
+)
+
+// Defines values for ResultResolution.
+const (
+	Day  ResultResolution = "day"
+	Hour ResultResolution = "hour"
+)
+
+// Defines values for SeveritySchema.
+const (
+	Critical SeveritySchema = "critical"
+	Info     SeveritySchema = "info"
+	Warning  SeveritySchema = "warning"
+)
+
 // Defines values for StatusSchema.
 const (
 	Active      StatusSchema = "active"
@@ -32,594 +108,5890 @@ const (
 	Terminating StatusSchema = "terminating"
 )
 
-// CreateProbeRequest defines model for CreateProbeRequest.
-type CreateProbeRequest struct {
-	// Labels A set of key-value pairs that can be used to organize and select probes.
-	Labels *LabelsSchema `json:"labels,omitempty"`
+// Defines values for TargetStatusState.
+const (
+	Down TargetStatusState = "down"
+	Up   TargetStatusState = "up"
+)
 
-	// StaticUrl The static URL to be probed.
-	StaticUrl StaticUrlSchema `json:"static_url"`
+// Defines values for TargetValidationFailureCheck.
+const (
+	PrivateIp    TargetValidationFailureCheck = "private_ip"
+	Reachability TargetValidationFailureCheck = "reachability"
+	Resolution   TargetValidationFailureCheck = "resolution"
+	Scheme       TargetValidationFailureCheck = "scheme"
+	Url          TargetValidationFailureCheck = "url"
+)
+
+// Defines values for WebhookEventType.
+const (
+	ProbeCreated       WebhookEventType = "probe.created"
+	ProbeDeleted       WebhookEventType = "probe.deleted"
+	ProbeStatusChanged WebhookEventType = "probe.status_changed"
+)
+
+// Defines values for OrderQueryParam.
+const (
+	OrderQueryParamAsc  OrderQueryParam = "asc"
+	OrderQueryParamDesc OrderQueryParam = "desc"
+)
+
+// Defines values for SortByQueryParam.
+const (
+	SortByQueryParamCreatedAt SortByQueryParam = "created_at"
+	SortByQueryParamStaticUrl SortByQueryParam = "static_url"
+	SortByQueryParamStatus    SortByQueryParam = "status"
+)
+
+// Defines values for ListProbesParamsSortBy.
+const (
+	ListProbesParamsSortByCreatedAt ListProbesParamsSortBy = "created_at"
+	ListProbesParamsSortByStaticUrl ListProbesParamsSortBy = "static_url"
+	ListProbesParamsSortByStatus    ListProbesParamsSortBy = "status"
+)
+
+// Defines values for ListProbesParamsOrder.
+const (
+	ListProbesParamsOrderAsc  ListProbesParamsOrder = "asc"
+	ListProbesParamsOrderDesc ListProbesParamsOrder = "desc"
+)
+
+// Defines values for CreateProbeParamsValidate.
+const (
+	Connectivity CreateProbeParamsValidate = "connectivity"
+)
+
+// APIKeyObject defines model for APIKeyObject.
+type APIKeyObject struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt When the key stops being accepted. Not set for keys valid until revoked.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Id Identifies the key in logs and metrics; it cannot be used in its place.
+	Id string `json:"id"`
+
+	// Key The key, to send as "Authorization: Bearer <key>". Only returned when the key is minted.
+	Key  *string `json:"key,omitempty"`
+	Name string  `json:"name"`
+
+	// RateLimit Requests per second the key may send. Not set for keys limited by the server's API key rate limit.
+	RateLimit *float64 `json:"rate_limit,omitempty"`
 }
 
-// ErrorObject defines model for ErrorObject.
-type ErrorObject struct {
-	// Message A human-readable error message.
-	Message string `json:"message"`
+// APIKeyRequest ttl is the key's lifetime; without it the key is valid until revoked.
+type APIKeyRequest struct {
+	// Name Who the key is for. Changes made with the key are audited as apikey:<name>.
+	Name string `json:"name"`
+
+	// RateLimit Requests per second the key may send, with bursts of as many; must be positive. Defaults to the server's API key rate limit.
+	RateLimit *float64 `json:"rate_limit,omitempty"`
+
+	// Ttl A positive duration such as "30s", "1m30s" or "500ms".
+	Ttl *DurationSchema `json:"ttl,omitempty"`
 }
 
-// ErrorResponse defines model for ErrorResponse.
-type ErrorResponse struct {
-	Error ErrorObject `json:"error"`
+// APIKeysArrayResponse defines model for APIKeysArrayResponse.
+type APIKeysArrayResponse struct {
+	Keys []APIKeyObject `json:"keys"`
 }
 
-// LabelsSchema A set of key-value pairs that can be used to organize and select probes.
-type LabelsSchema map[string]string
+// AdditionalUrlsSchema Further URLs checked together with static_url, e.g. the console of the cluster whose API server is static_url. They share the probe's labels, schedule, module, alerting and credentials. Unlike static_url, they may change after creation and are not checked for duplicates against other probes. Updates replace the list as a whole; an empty list removes them.
+type AdditionalUrlsSchema = []StaticUrlSchema
 
-// ProbeIdSchema The unique identifier of a probe (UUID format).
-type ProbeIdSchema = openapi_types.UUID
+// AgentBootstrapTokenObject defines model for AgentBootstrapTokenObject.
+type AgentBootstrapTokenObject struct {
+	// AgentId The identifier of a probing agent; must be a valid label value.
+	AgentId *AgentIdSchema `json:"agent_id,omitempty"`
 
-// ProbeObject Represents a single probe configuration.
-type ProbeObject struct {
-	// Id The unique identifier of a probe (UUID format).
-	Id ProbeIdSchema `json:"id"`
+	// ExpiresAt When the token stops being accepted.
+	ExpiresAt time.Time `json:"expires_at"`
 
-	// Labels A set of key-value pairs that can be used to organize and select probes.
-	Labels *LabelsSchema `json:"labels,omitempty"`
+	// Id Identifies the token in logs; it cannot be used in its place.
+	Id string `json:"id"`
 
-	// StaticUrl The static URL to be probed.
-	StaticUrl StaticUrlSchema `json:"static_url"`
+	// Token The bootstrap token, to hand to the agents.
+	Token string `json:"token"`
+}
 
-	// Status The current status of the probe.
-	Status StatusSchema `json:"status"`
+// AgentBootstrapTokenRequest agent_id restricts the token to one agent, which may exchange it until it expires; without it any agent may exchange it once, unless that agent is registered. ttl is the token's lifetime, 1h by default and at most the server's maximum.
+type AgentBootstrapTokenRequest struct {
+	// AgentId The identifier of a probing agent; must be a valid label value.
+	AgentId *AgentIdSchema `json:"agent_id,omitempty"`
+
+	// Ttl A positive duration such as "30s", "1m30s" or "500ms".
+	Ttl *DurationSchema `json:"ttl,omitempty"`
 }
 
-// ProbesArrayResponse defines model for ProbesArrayResponse.
-type ProbesArrayResponse struct {
-	// Probes Array containing one or more probe objects.
-	Probes []ProbeObject `json:"probes"`
+// AgentCredentialObject defines model for AgentCredentialObject.
+type AgentCredentialObject struct {
+	// AgentId The identifier of a probing agent; must be a valid label value.
+	AgentId AgentIdSchema `json:"agent_id"`
+
+	// Credential The agent's credential, to send as "Authorization: Bearer <credential>".
+	Credential string `json:"credential"`
+
+	// ExpiresAt When the credential stops being accepted; renew it before then.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Id Identifies the credential in logs; it cannot be used in its place.
+	Id       string    `json:"id"`
+	IssuedAt time.Time `json:"issued_at"`
 }
 
-// StaticUrlSchema The static URL to be probed.
-type StaticUrlSchema = string
+// AgentCredentialRevocationObject defines model for AgentCredentialRevocationObject.
+type AgentCredentialRevocationObject struct {
+	// AgentId The identifier of a probing agent; must be a valid label value.
+	AgentId AgentIdSchema `json:"agent_id"`
 
-// StatusSchema The current status of the probe.
-type StatusSchema string
+	// RevokedAt Credentials of the agent issued at or before this time are rejected.
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// AgentIdSchema The identifier of a probing agent; must be a valid label value.
+type AgentIdSchema = string
+
+// AgentObject defines model for AgentObject.
+type AgentObject struct {
+	// Id The identifier of a probing agent; must be a valid label value.
+	Id AgentIdSchema `json:"id"`
 
-// UpdateProbeRequest Fields to update for a probe.
-type UpdateProbeRequest struct {
 	// Labels A set of key-value pairs that can be used to organize and select probes.
 	Labels *LabelsSchema `json:"labels,omitempty"`
 
-	// Status The current status of the probe.
-	Status *StatusSchema `json:"status,omitempty"`
-}
+	// LastHeartbeat When the agent last registered.
+	LastHeartbeat time.Time `json:"last_heartbeat"`
 
-// WarningObject defines model for WarningObject.
-type WarningObject struct {
-	// Message A human-readable error message indicating the resource was not found.
-	Message string `json:"message"`
+	// MaxProbes Maximum number of probes to assign to this agent; 0 means no limit.
+	MaxProbes int `json:"max_probes"`
 }
 
-// WarningResponse defines model for WarningResponse.
-type WarningResponse struct {
-	Warning WarningObject `json:"warning"`
+// AgentRegistrationRequest defines model for AgentRegistrationRequest.
+type AgentRegistrationRequest struct {
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// MaxProbes Maximum number of probes to assign to this agent; 0 means no limit.
+	MaxProbes *int `json:"max_probes,omitempty"`
 }
 
-// LabelSelectorQueryParam defines model for LabelSelectorQueryParam.
-type LabelSelectorQueryParam = string
+// AlertingSchema Routing metadata for the alerts raised by the probe. Agents expose it as the severity, runbook_url and silence_during_maintenance labels of the probe's targets.
+type AlertingSchema struct {
+	// RunbookUrl An http or https link to the runbook for the probe's alerts.
+	RunbookUrl *string `json:"runbook_url,omitempty"`
 
-// ProbeIdPathParam The unique identifier of a probe (UUID format).
-type ProbeIdPathParam = ProbeIdSchema
+	// Severity Severity of the alerts raised when the probe fails.
+	Severity *SeveritySchema `json:"severity,omitempty"`
 
-// ListProbesParams defines parameters for ListProbes.
-type ListProbesParams struct {
-	// LabelSelector A comma-separated list of key=value labels to filter on.
-	LabelSelector *LabelSelectorQueryParam `form:"label_selector,omitempty" json:"label_selector,omitempty"`
+	// SilenceDuringMaintenance Whether alerts of the probe are silenced while its target is in maintenance.
+	SilenceDuringMaintenance *bool `json:"silence_during_maintenance,omitempty"`
 }
 
-// CreateProbeJSONRequestBody defines body for CreateProbe for application/json ContentType.
-type CreateProbeJSONRequestBody = CreateProbeRequest
+// AssertionsSchema What an HTTP response must look like for the probe to pass, enforced by the agents instead of their default of any 2xx status. Only probes of the http_2xx module may have them. Updates replace them as a whole; an empty object removes them.
+type AssertionsSchema struct {
+	// BodyRegex An RE2 regular expression the response body must match.
+	BodyRegex *string `json:"body_regex,omitempty"`
 
-// UpdateProbeJSONRequestBody defines body for UpdateProbe for application/json ContentType.
-type UpdateProbeJSONRequestBody = UpdateProbeRequest
+	// Headers Headers the response must have, each name at most once.
+	Headers *[]HeaderAssertion `json:"headers,omitempty"`
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Get a list of all configured probes
-	// (GET /probes)
-	ListProbes(w http.ResponseWriter, r *http.Request, params ListProbesParams)
-	// Creates a new probe
-	// (POST /probes)
-	CreateProbe(w http.ResponseWriter, r *http.Request)
-	// Deletes a probe matching provided ID
-	// (DELETE /probes/{probe_id})
-	DeleteProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
-	// Get a probe by its ID
-	// (GET /probes/{probe_id})
-	GetProbeById(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
-	// Updates a probe by its ID
-	// (PATCH /probes/{probe_id})
-	UpdateProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+	// StatusCodes The statuses accepted; any 2xx when absent.
+	StatusCodes *[]StatusCodeRangeSchema `json:"status_codes,omitempty"`
+
+	// TlsExpiryDays Fail when the certificate the target presents expires within this many days. Requires an https static_url.
+	TlsExpiryDays *int `json:"tls_expiry_days,omitempty"`
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+// AuditEntriesArrayResponse defines model for AuditEntriesArrayResponse.
+type AuditEntriesArrayResponse struct {
+	Entries []AuditEntry `json:"entries"`
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
+// AuditEntry A change made to a probe.
+type AuditEntry struct {
+	// Actor Who made the change: the common name of the caller's client certificate, or else the X-Forwarded-User header set by an authenticating proxy. "system" for changes the server made itself. Absent when the caller could not be identified. A keyed hash prefixed with "hmac-sha256:" when the server hashes actors.
+	Actor *string `json:"actor,omitempty"`
 
-// ListProbes operation middleware
-func (siw *ServerInterfaceWrapper) ListProbes(w http.ResponseWriter, r *http.Request) {
+	// After Represents a single probe configuration.
+	After *ProbeObject `json:"after,omitempty"`
 
-	var err error
+	// Before Represents a single probe configuration.
+	Before *ProbeObject `json:"before,omitempty"`
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListProbesParams
+	// Changes The fields that differ between before and after, with changed labels listed as labels.<key>. The resource version is left out, as every change updates it.
+	Changes []string `json:"changes"`
 
-	// ------------- Optional query parameter "label_selector" -------------
+	// Id Unique ID of the entry.
+	Id openapi_types.UUID `json:"id"`
 
-	err = runtime.BindQueryParameter("form", true, false, "label_selector", r.URL.Query(), &params.LabelSelector)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label_selector", Err: err})
-		return
-	}
+	// Operation What changed the probe: one of the API operations, or removeTerminatingProbe when the server removed a probe whose terminating grace period had passed.
+	Operation AuditOperation `json:"operation"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListProbes(w, r, params)
-	}))
+	// ProbeId The unique identifier of a probe (UUID format).
+	ProbeId ProbeIdSchema `json:"probe_id"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// RemoteAddr The network address the request came from, or its /24 (IPv4) or /48 (IPv6) network when the server truncates addresses.
+	RemoteAddr *string `json:"remote_addr,omitempty"`
 
-	handler.ServeHTTP(w, r)
+	// RequestId The X-Request-ID of the request that made the change.
+	RequestId *string `json:"request_id,omitempty"`
+
+	// Tenant The tenant the caller acted for, if any.
+	Tenant *string `json:"tenant,omitempty"`
+
+	// Timestamp When the change was made.
+	Timestamp time.Time `json:"timestamp"`
 }
 
-// CreateProbe operation middleware
-func (siw *ServerInterfaceWrapper) CreateProbe(w http.ResponseWriter, r *http.Request) {
+// AuditOperation What changed the probe: one of the API operations, or removeTerminatingProbe when the server removed a probe whose terminating grace period had passed.
+type AuditOperation string
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateProbe(w, r)
-	}))
+// BundleFormat defines model for BundleFormat.
+type BundleFormat string
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+// BundledProbe A probe in a bundle: its configuration, without the fields and labels the store maintains.
+type BundledProbe struct {
+	// AdditionalUrls Further URLs checked together with static_url, e.g. the console of the cluster whose API server is static_url. They share the probe's labels, schedule, module, alerting and credentials. Unlike static_url, they may change after creation and are not checked for duplicates against other probes. Updates replace the list as a whole; an empty list removes them.
+	AdditionalUrls *AdditionalUrlsSchema `json:"additional_urls,omitempty"`
 
-	handler.ServeHTTP(w, r)
-}
+	// Alerting Routing metadata for the alerts raised by the probe. Agents expose it as the severity, runbook_url and silence_during_maintenance labels of the probe's targets.
+	Alerting *AlertingSchema `json:"alerting,omitempty"`
 
-// DeleteProbe operation middleware
-func (siw *ServerInterfaceWrapper) DeleteProbe(w http.ResponseWriter, r *http.Request) {
+	// Assertions What an HTTP response must look like for the probe to pass, enforced by the agents instead of their default of any 2xx status. Only probes of the http_2xx module may have them. Updates replace them as a whole; an empty object removes them.
+	Assertions *AssertionsSchema `json:"assertions,omitempty"`
 
-	var err error
+	// Dns What a dns probe resolves. It requires the dns module, which is set when the module is left out. Its static_url is derived from the query as an RFC 4501 URI, e.g. dns://10.0.0.10:53/api.example.com?type=A, and cannot be given; like any static_url it is checked for duplicates, so the same query against the same resolver is probed once. Only expected_answers may change after creation.
+	Dns *DnsSchema `json:"dns,omitempty"`
 
-	// ------------- Path parameter "probe_id" -------------
-	var probeId ProbeIdPathParam
+	// Icmp What an icmp probe pings. It requires the icmp module, which is set when the module is left out. Its static_url is derived from the host, e.g. icmp://203.0.113.7, and cannot be given; like any static_url it is checked for duplicates. Only packet_count may change after creation.
+	Icmp *IcmpSchema `json:"icmp,omitempty"`
 
-	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
-		return
-	}
+	// Id The unique identifier of a probe (UUID format).
+	Id ProbeIdSchema `json:"id"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteProbe(w, r, probeId)
-	}))
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
 
-	handler.ServeHTTP(w, r)
-}
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
 
-// GetProbeById operation middleware
-func (siw *ServerInterfaceWrapper) GetProbeById(w http.ResponseWriter, r *http.Request) {
+	// Owner Who owns the probe, so alerts raised by its failures can be routed to them. Agents expose it as the owner_team and escalation_contact labels of the probe's targets, and it is part of the probe in webhook and CloudEvents payloads. Servers may require either field on new probes.
+	Owner *OwnerSchema `json:"owner,omitempty"`
 
-	var err error
+	// Paused Whether the probe is paused, e.g. for a maintenance window. Agents must not run paused probes, but keep reconciling them so their heartbeat continues; the probe keeps its status. Terminating probes cannot be paused. Absent when the probe is not paused.
+	Paused *PausedSchema `json:"paused,omitempty"`
 
-	// ------------- Path parameter "probe_id" -------------
-	var probeId ProbeIdPathParam
+	// Regions The regions whose agents may run the probe. A probe without regions runs in any region. Updates replace the list as a whole; an empty list removes it.
+	Regions *RegionsSchema `json:"regions,omitempty"`
 
-	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
-		return
-	}
+	// StaticUrl The static URL to be probed.
+	StaticUrl StaticUrlSchema `json:"static_url"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetProbeById(w, r, probeId)
-	}))
+	// Status The current status of the probe.
+	Status *StatusSchema `json:"status,omitempty"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Tcp What a tcp probe connects to. It requires the tcp module, which is set when the module is left out. Its static_url is derived from the address, e.g. tcp://db.example.com:5432, and cannot be given; like any static_url it is checked for duplicates. Only tls may change after creation.
+	Tcp *TcpSchema `json:"tcp,omitempty"`
 
-	handler.ServeHTTP(w, r)
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout *DurationSchema `json:"timeout,omitempty"`
 }
 
-// UpdateProbe operation middleware
-func (siw *ServerInterfaceWrapper) UpdateProbe(w http.ResponseWriter, r *http.Request) {
+// CreateProbeRequest Either static_url, template_id or the settings of one probe type, dns, tcp or icmp, must be set. A probe created from a template gets the URL built from the template's url_pattern and variables, and the template's labels, interval, timeout and module where the request leaves them out.
+type CreateProbeRequest struct {
+	// AdditionalUrls Further URLs checked together with static_url, e.g. the console of the cluster whose API server is static_url. They share the probe's labels, schedule, module, alerting and credentials. Unlike static_url, they may change after creation and are not checked for duplicates against other probes. Updates replace the list as a whole; an empty list removes them.
+	AdditionalUrls *AdditionalUrlsSchema `json:"additional_urls,omitempty"`
 
-	var err error
+	// Alerting Routing metadata for the alerts raised by the probe. Agents expose it as the severity, runbook_url and silence_during_maintenance labels of the probe's targets.
+	Alerting *AlertingSchema `json:"alerting,omitempty"`
 
-	// ------------- Path parameter "probe_id" -------------
-	var probeId ProbeIdPathParam
+	// Assertions What an HTTP response must look like for the probe to pass, enforced by the agents instead of their default of any 2xx status. Only probes of the http_2xx module may have them. Updates replace them as a whole; an empty object removes them.
+	Assertions *AssertionsSchema `json:"assertions,omitempty"`
 
-	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
-		return
-	}
+	// Auth Credentials the probe presents to its target: a basic auth username and password, or a bearer token. The password and token are kept in a Kubernetes Secret, or in an encrypted file for the local store, not with the probe, and responses carry them as REDACTED. Sending REDACTED back keeps the stored value, and an empty object removes the credentials. Agents fetch the values from /probes/{probe_id}/auth.
+	Auth *ProbeAuthSchema `json:"auth,omitempty"`
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateProbe(w, r, probeId)
-	}))
+	// CreationTimestamp Set by the server; requests that set it are rejected with 400 Bad Request.
+	CreationTimestamp *ServerTimestampSchema `json:"creation_timestamp,omitempty"`
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+	// Dns What a dns probe resolves. It requires the dns module, which is set when the module is left out. Its static_url is derived from the query as an RFC 4501 URI, e.g. dns://10.0.0.10:53/api.example.com?type=A, and cannot be given; like any static_url it is checked for duplicates, so the same query against the same resolver is probed once. Only expected_answers may change after creation.
+	Dns *DnsSchema `json:"dns,omitempty"`
 
-	handler.ServeHTTP(w, r)
-}
+	// Icmp What an icmp probe pings. It requires the icmp module, which is set when the module is left out. Its static_url is derived from the host, e.g. icmp://203.0.113.7, and cannot be given; like any static_url it is checked for duplicates. Only packet_count may change after creation.
+	Icmp *IcmpSchema `json:"icmp,omitempty"`
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
-}
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
-}
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
-}
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
-}
+	// Owner Who owns the probe, so alerts raised by its failures can be routed to them. Agents expose it as the owner_team and escalation_contact labels of the probe's targets, and it is part of the probe in webhook and CloudEvents payloads. Servers may require either field on new probes.
+	Owner *OwnerSchema `json:"owner,omitempty"`
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
-}
+	// Regions The regions whose agents may run the probe. A probe without regions runs in any region. Updates replace the list as a whole; an empty list removes it.
+	Regions *RegionsSchema `json:"regions,omitempty"`
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
+	// StaticUrl The static URL to be probed.
+	StaticUrl string `json:"static_url,omitempty"`
+
+	// Tcp What a tcp probe connects to. It requires the tcp module, which is set when the module is left out. Its static_url is derived from the address, e.g. tcp://db.example.com:5432, and cannot be given; like any static_url it is checked for duplicates. Only tls may change after creation.
+	Tcp *TcpSchema `json:"tcp,omitempty"`
+
+	// TemplateId The probe template to create the probe from.
+	TemplateId *openapi_types.UUID `json:"template_id,omitempty"`
+
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout *DurationSchema `json:"timeout,omitempty"`
+
+	// UpdateTimestamp Set by the server; requests that set it are rejected with 400 Bad Request.
+	UpdateTimestamp *ServerTimestampSchema `json:"update_timestamp,omitempty"`
+
+	// Variables Values of the variables in the template's url_pattern, by name.
+	Variables *map[string]string `json:"variables,omitempty"`
 }
 
-type RequiredParamError struct {
-	ParamName string
+// DnsRecordTypeSchema The type of the DNS records a dns probe queries.
+type DnsRecordTypeSchema string
+
+// DnsSchema What a dns probe resolves. It requires the dns module, which is set when the module is left out. Its static_url is derived from the query as an RFC 4501 URI, e.g. dns://10.0.0.10:53/api.example.com?type=A, and cannot be given; like any static_url it is checked for duplicates, so the same query against the same resolver is probed once. Only expected_answers may change after creation.
+type DnsSchema struct {
+	// ExpectedAnswers Values the answer must contain, e.g. addresses for A records, for the probe to pass. Any answer passes when empty or absent. Updates replace the list as a whole.
+	ExpectedAnswers *[]string `json:"expected_answers,omitempty"`
+
+	// QueryName The fully qualified name to resolve; a trailing dot is optional.
+	QueryName string `json:"query_name"`
+
+	// RecordType The type of the DNS records a dns probe queries.
+	RecordType DnsRecordTypeSchema `json:"record_type"`
+
+	// Resolver The host[:port] of the DNS server to query, port 53 by default. The agent's resolver when absent.
+	Resolver *string `json:"resolver,omitempty"`
 }
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+// DurationSchema A positive duration such as "30s", "1m30s" or "500ms".
+type DurationSchema = string
+
+// ErrorObject defines model for ErrorObject.
+type ErrorObject struct {
+	// AllowedStatuses Set when a status change is rejected: the statuses the probe may move to from its current status.
+	AllowedStatuses *[]StatusSchema `json:"allowed_statuses,omitempty"`
+
+	// Message A human-readable error message.
+	Message string `json:"message"`
+
+	// RetryAfterSeconds Suggested number of seconds to wait before retrying. Set on retryable errors (409, 429, 503) and always equal to the Retry-After response header.
+	RetryAfterSeconds *int `json:"retry_after_seconds,omitempty"`
+
+	// ValidationFailures Set when a probe target fails validation on create, one item per failed check.
+	ValidationFailures *[]TargetValidationFailure `json:"validation_failures,omitempty"`
 }
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error ErrorObject `json:"error"`
 }
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+// FeaturesSchema Server-controlled capability hints for agents, keyed by feature name with the supported version as value. Agents should ignore features they do not know and treat a missing feature as unsupported.
+type FeaturesSchema map[string]string
+
+// HeaderAssertion A response header the probe requires.
+type HeaderAssertion struct {
+	// Name The header name, matched case-insensitively.
+	Name string `json:"name"`
+
+	// ValueRegex An RE2 regular expression one of the header's values must match. The header only needs to be present when absent.
+	ValueRegex *string `json:"value_regex,omitempty"`
 }
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
+// IcmpSchema What an icmp probe pings. It requires the icmp module, which is set when the module is left out. Its static_url is derived from the host, e.g. icmp://203.0.113.7, and cannot be given; like any static_url it is checked for duplicates. Only packet_count may change after creation.
+type IcmpSchema struct {
+	// Host The host name or IP address to send echo requests to.
+	Host string `json:"host"`
+
+	// PacketCount How many echo requests each run sends; 1 when absent.
+	PacketCount *int `json:"packet_count,omitempty"`
 }
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+// ImportConflictStrategy defines model for ImportConflictStrategy.
+type ImportConflictStrategy string
+
+// ImportError Why a probe of a bundle was not imported.
+type ImportError struct {
+	// Code The status code POST /probes would have answered the probe with: 400 when it is invalid, 403 when it sets labels the caller may not.
+	Code int `json:"code"`
+
+	// Field The field of the bundle the error is about, when it is about one.
+	Field *string `json:"field,omitempty"`
+
+	// Id The unique identifier of a probe (UUID format).
+	Id *ProbeIdSchema `json:"id,omitempty"`
+
+	// Index The position of the probe in the bundle, from 0.
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+
+	// StaticUrl The static_url of the probe in the bundle, which may be missing.
+	StaticUrl *string `json:"static_url,omitempty"`
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+// ImportedProbe defines model for ImportedProbe.
+type ImportedProbe struct {
+	// Id The unique identifier of a probe (UUID format).
+	Id ProbeIdSchema `json:"id"`
+
+	// StaticUrl The static URL to be probed.
+	StaticUrl StaticUrlSchema `json:"static_url"`
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+// LabelsSchema A set of key-value pairs that can be used to organize and select probes.
+type LabelsSchema map[string]string
+
+// LatencyPercentiles Latency percentiles in milliseconds, estimated from a histogram: they are exact to within the histogram bucket, whose bounds grow from 1ms to 30s.
+type LatencyPercentiles struct {
+	Max float64 `json:"max"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+// OwnerSchema Who owns the probe, so alerts raised by its failures can be routed to them. Agents expose it as the owner_team and escalation_contact labels of the probe's targets, and it is part of the probe in webhook and CloudEvents payloads. Servers may require either field on new probes.
+type OwnerSchema struct {
+	// EscalationContact Where to escalate the probe's alerts, such as an email address, a pager rotation or a chat channel.
+	EscalationContact *string `json:"escalation_contact,omitempty"`
+
+	// Team The team owning the probe, usable as a label value.
+	Team *string `json:"team,omitempty"`
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+// PausedSchema Whether the probe is paused, e.g. for a maintenance window. Agents must not run paused probes, but keep reconciling them so their heartbeat continues; the probe keeps its status. Terminating probes cannot be paused. Absent when the probe is not paused.
+type PausedSchema = bool
+
+// ProbeAuthSchema Credentials the probe presents to its target: a basic auth username and password, or a bearer token. The password and token are kept in a Kubernetes Secret, or in an encrypted file for the local store, not with the probe, and responses carry them as REDACTED. Sending REDACTED back keeps the stored value, and an empty object removes the credentials. Agents fetch the values from /probes/{probe_id}/auth.
+type ProbeAuthSchema struct {
+	// BearerToken The bearer token; cannot be combined with basic auth.
+	BearerToken *string `json:"bearer_token,omitempty"`
+
+	// Password The basic auth password; requires username.
+	Password *string `json:"password,omitempty"`
+
+	// Username The basic auth username.
+	Username *string `json:"username,omitempty"`
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{})
+// ProbeBundle A set of probes exported to be imported elsewhere.
+type ProbeBundle struct {
+	// ExportedAt When the bundle was exported.
+	ExportedAt *time.Time     `json:"exported_at,omitempty"`
+	Probes     []BundledProbe `json:"probes"`
+
+	// Version The version of the bundle format; 1 is the only one.
+	Version int `json:"version"`
 }
 
-// ServeMux is an abstraction of http.ServeMux.
-type ServeMux interface {
-	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
-	ServeHTTP(w http.ResponseWriter, r *http.Request)
+// ProbeChange defines model for ProbeChange.
+type ProbeChange struct {
+	// Fields The settings that differ: additional_urls, alerting, assertions, auth, dns, icmp, interval, module, owner, paused, regions, static_url, tcp or timeout, then labels.<key> for each differing label, each sorted. Status and timestamps are not compared, nor are the redacted values of auth.
+	Fields []string `json:"fields"`
+
+	// Key The static_url or match_label value the two probes were paired by.
+	Key string `json:"key"`
+
+	// Left Represents a single probe configuration.
+	Left ProbeObject `json:"left"`
+
+	// Right Represents a single probe configuration.
+	Right ProbeObject `json:"right"`
 }
 
-type StdHTTPServerOptions struct {
-	BaseURL          string
-	BaseRouter       ServeMux
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+// ProbeDiffResponse defines model for ProbeDiffResponse.
+type ProbeDiffResponse struct {
+	// Added The probes matched by right_selector only, by match key.
+	Added []ProbeObject `json:"added"`
+
+	// Changed The paired probes whose settings differ, by match key.
+	Changed []ProbeChange `json:"changed"`
+
+	// Removed The probes matched by left_selector only, by match key.
+	Removed []ProbeObject `json:"removed"`
+
+	// Unchanged How many paired probes have the same settings.
+	Unchanged int `json:"unchanged"`
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseRouter: m,
-	})
+// ProbeGroupIdSchema The ID of a probe group, such as a hosted cluster ID. It must be a valid label value.
+type ProbeGroupIdSchema = string
+
+// ProbeGroupObject defines model for ProbeGroupObject.
+type ProbeGroupObject struct {
+	// Id The ID of a probe group, such as a hosted cluster ID. It must be a valid label value.
+	Id ProbeGroupIdSchema `json:"id"`
+
+	// Paused Whether the group's probes are paused. Terminating probes are not counted.
+	Paused bool `json:"paused"`
+
+	// ProbeCount The number of probes in the group.
+	ProbeCount int `json:"probe_count"`
+
+	// Probes The probes of the group, sorted by creation time. Left out of listings.
+	Probes *[]ProbeObject `json:"probes,omitempty"`
+
+	// Status The status of the group, computed from its probes: deleting when all of them are terminating, degraded when any has failed, paused when the group is paused, progressing when any is pending, and ready when all are active. Terminating probes are left out otherwise.
+	Status ProbeGroupStatus `json:"status"`
+
+	// StatusCounts The number of probes of the group in each status.
+	StatusCounts map[string]int `json:"status_counts"`
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: m,
-	})
+// ProbeGroupRequest defines model for ProbeGroupRequest.
+type ProbeGroupRequest struct {
+	// Id The ID of a probe group, such as a hosted cluster ID. It must be a valid label value.
+	Id ProbeGroupIdSchema `json:"id"`
+
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// Probes The probes of the group. Each gets the group's labels, which its own labels override.
+	Probes []CreateProbeRequest `json:"probes"`
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
-	m := options.BaseRouter
+// ProbeGroupStatus The status of the group, computed from its probes: deleting when all of them are terminating, degraded when any has failed, paused when the group is paused, progressing when any is pending, and ready when all are active. Terminating probes are left out otherwise.
+type ProbeGroupStatus string
 
-	if m == nil {
-		m = http.NewServeMux()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
+// ProbeGroupUpdateRequest defines model for ProbeGroupUpdateRequest.
+type ProbeGroupUpdateRequest struct {
+	// Paused Whether the group's probes are paused.
+	Paused bool `json:"paused"`
+}
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+// ProbeGroupsArrayResponse defines model for ProbeGroupsArrayResponse.
+type ProbeGroupsArrayResponse struct {
+	Groups []ProbeGroupObject `json:"groups"`
+}
 
-	m.HandleFunc("GET "+options.BaseURL+"/probes", wrapper.ListProbes)
-	m.HandleFunc("POST "+options.BaseURL+"/probes", wrapper.CreateProbe)
-	m.HandleFunc("DELETE "+options.BaseURL+"/probes/{probe_id}", wrapper.DeleteProbe)
-	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}", wrapper.GetProbeById)
-	m.HandleFunc("PATCH "+options.BaseURL+"/probes/{probe_id}", wrapper.UpdateProbe)
+// ProbeHistoryResponse defines model for ProbeHistoryResponse.
+type ProbeHistoryResponse struct {
+	// History The probe's status changes, oldest first.
+	History []StatusTransition `json:"history"`
+}
 
-	return m
+// ProbeIdSchema The unique identifier of a probe (UUID format).
+type ProbeIdSchema = openapi_types.UUID
+
+// ProbeImportResponse defines model for ProbeImportResponse.
+type ProbeImportResponse struct {
+	// Created The probes created, with their ID in this environment.
+	Created []ImportedProbe `json:"created"`
+
+	// Errors The probes of the bundle that failed validation, in the order of the bundle.
+	Errors []ImportError `json:"errors"`
+
+	// Overwritten The stored probes overwritten by a conflicting probe of the bundle.
+	Overwritten []ImportedProbe `json:"overwritten"`
+
+	// Skipped The probes of the bundle left out, with their ID in the bundle: terminating and deleted ones, and conflicting ones with on_conflict=skip.
+	Skipped []ImportedProbe `json:"skipped"`
 }
 
-type ListProbesRequestObject struct {
-	Params ListProbesParams
+// ProbeModuleSchema The blackbox exporter module the probe is run with.
+type ProbeModuleSchema string
+
+// ProbeObject Represents a single probe configuration.
+type ProbeObject struct {
+	// AdditionalUrls Further URLs checked together with static_url, e.g. the console of the cluster whose API server is static_url. They share the probe's labels, schedule, module, alerting and credentials. Unlike static_url, they may change after creation and are not checked for duplicates against other probes. Updates replace the list as a whole; an empty list removes them.
+	AdditionalUrls *AdditionalUrlsSchema `json:"additional_urls,omitempty"`
+
+	// Alerting Routing metadata for the alerts raised by the probe. Agents expose it as the severity, runbook_url and silence_during_maintenance labels of the probe's targets.
+	Alerting *AlertingSchema `json:"alerting,omitempty"`
+
+	// Assertions What an HTTP response must look like for the probe to pass, enforced by the agents instead of their default of any 2xx status. Only probes of the http_2xx module may have them. Updates replace them as a whole; an empty object removes them.
+	Assertions *AssertionsSchema `json:"assertions,omitempty"`
+
+	// Auth Credentials the probe presents to its target: a basic auth username and password, or a bearer token. The password and token are kept in a Kubernetes Secret, or in an encrypted file for the local store, not with the probe, and responses carry them as REDACTED. Sending REDACTED back keeps the stored value, and an empty object removes the credentials. Agents fetch the values from /probes/{probe_id}/auth.
+	Auth *ProbeAuthSchema `json:"auth,omitempty"`
+
+	// CreationTimestamp When the probe was created. Absent for probes created before it was recorded in the ConfigMap payload, local files or PostgreSQL rows.
+	CreationTimestamp *time.Time `json:"creation_timestamp,omitempty"`
+
+	// DeletionTimestamp When the probe became terminating. A terminating probe whose deletion is not confirmed by its agent is removed once the server's grace period has passed since this time. Absent for probes in other states.
+	DeletionTimestamp *time.Time `json:"deletion_timestamp,omitempty"`
+
+	// Dns What a dns probe resolves. It requires the dns module, which is set when the module is left out. Its static_url is derived from the query as an RFC 4501 URI, e.g. dns://10.0.0.10:53/api.example.com?type=A, and cannot be given; like any static_url it is checked for duplicates, so the same query against the same resolver is probed once. Only expected_answers may change after creation.
+	Dns *DnsSchema `json:"dns,omitempty"`
+
+	// Generation Starts at 1 and is incremented by every change to the probe's configuration: its URLs, labels, schedule, alerting or whether it is paused. Status changes, heartbeats and other labels the system maintains leave it unchanged.
+	Generation *int64 `json:"generation,omitempty"`
+
+	// Icmp What an icmp probe pings. It requires the icmp module, which is set when the module is left out. Its static_url is derived from the host, e.g. icmp://203.0.113.7, and cannot be given; like any static_url it is checked for duplicates. Only packet_count may change after creation.
+	Icmp *IcmpSchema `json:"icmp,omitempty"`
+
+	// Id The unique identifier of a probe (UUID format).
+	Id ProbeIdSchema `json:"id"`
+
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
+
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
+
+	// Owner Who owns the probe, so alerts raised by its failures can be routed to them. Agents expose it as the owner_team and escalation_contact labels of the probe's targets, and it is part of the probe in webhook and CloudEvents payloads. Servers may require either field on new probes.
+	Owner *OwnerSchema `json:"owner,omitempty"`
+
+	// Paused Whether the probe is paused, e.g. for a maintenance window. Agents must not run paused probes, but keep reconciling them so their heartbeat continues; the probe keeps its status. Terminating probes cannot be paused. Absent when the probe is not paused.
+	Paused *PausedSchema `json:"paused,omitempty"`
+
+	// Regions The regions whose agents may run the probe. A probe without regions runs in any region. Updates replace the list as a whole; an empty list removes it.
+	Regions *RegionsSchema `json:"regions,omitempty"`
+
+	// ResourceVersion Opaque version of the stored probe, changed by every update. It is the probe's ETag, unquoted.
+	ResourceVersion *string `json:"resource_version,omitempty"`
+
+	// StaticUrl The static URL to be probed.
+	StaticUrl StaticUrlSchema `json:"static_url"`
+
+	// Status The current status of the probe.
+	Status StatusSchema `json:"status"`
+
+	// StatusHistory The probe's last 20 status changes, oldest first. Also served by /probes/{probe_id}/history. Absent for probes whose status never changed since it was recorded.
+	StatusHistory *[]StatusTransition `json:"status_history,omitempty"`
+
+	// StatusMessage Human-readable details of status_reason. Only accepted with a failed or terminating status.
+	StatusMessage *StatusMessageSchema `json:"status_message,omitempty"`
+
+	// StatusReason Why the probe is failed or terminating, as a CamelCase token alerts and dashboards can match on. Only accepted with a failed or terminating status. Sending status_reason or status_message replaces both; a status change without them clears them.
+	StatusReason *StatusReasonSchema `json:"status_reason,omitempty"`
+
+	// TargetStatuses The outcome of the last check of each of the probe's URLs, reported by the agent that runs it. Each url must be static_url or one of additional_urls, at most once. A report replaces the list as a whole; like heartbeats, it leaves the generation and update_timestamp unchanged. Outcomes of URLs removed from additional_urls are dropped.
+	TargetStatuses *TargetStatusesSchema `json:"target_statuses,omitempty"`
+
+	// Tcp What a tcp probe connects to. It requires the tcp module, which is set when the module is left out. Its static_url is derived from the address, e.g. tcp://db.example.com:5432, and cannot be given; like any static_url it is checked for duplicates. Only tls may change after creation.
+	Tcp *TcpSchema `json:"tcp,omitempty"`
+
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout *DurationSchema `json:"timeout,omitempty"`
+
+	// UpdateTimestamp When the probe's configuration or status last changed; its creation time until then. Heartbeats and other labels the system maintains leave it unchanged. Absent for probes not changed since before it was recorded.
+	UpdateTimestamp *time.Time `json:"update_timestamp,omitempty"`
+
+	// UrlHash The hex SHA-256 of static_url. The rhobs-synthetics/static-url-hash label holds its first 63 characters, the most a label value may have, and stays the way probes are selected by URL. Probes stored before it was recorded get it on the next server start.
+	UrlHash *string `json:"url_hash,omitempty"`
 }
 
-type ListProbesResponseObject interface {
-	VisitListProbesResponse(w http.ResponseWriter) error
+// ProbeProblem defines model for ProbeProblem.
+type ProbeProblem struct {
+	// Message The reason, worded for humans.
+	Message string `json:"message"`
+
+	// Probe Represents a single probe configuration.
+	Probe ProbeObject `json:"probe"`
+
+	// Reason Why a probe is reported: stuck_pending probes were never picked up by an agent, terminating_too_long probes are waiting for their agent to confirm the deletion, and stale_failed probes failed and have not been checked since.
+	Reason ProbeProblemReason `json:"reason"`
+
+	// Since When the probe entered the state it is reported for: its creation, its deletion or its last check. Absent for failed probes that were never checked.
+	Since *time.Time `json:"since,omitempty"`
 }
 
-type ListProbes200JSONResponse ProbesArrayResponse
+// ProbeProblemReason Why a probe is reported: stuck_pending probes were never picked up by an agent, terminating_too_long probes are waiting for their agent to confirm the deletion, and stale_failed probes failed and have not been checked since.
+type ProbeProblemReason string
 
-func (response ListProbes200JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+// ProbeProblemsArrayResponse defines model for ProbeProblemsArrayResponse.
+type ProbeProblemsArrayResponse struct {
+	// Problems The reported probes, oldest problem first.
+	Problems []ProbeProblem `json:"problems"`
+}
 
-	return json.NewEncoder(w).Encode(response)
+// ProbeResultObject The outcome of a single probe run.
+type ProbeResultObject struct {
+	// LatencyMs Time until the response was received, in milliseconds.
+	LatencyMs float64 `json:"latency_ms"`
+
+	// StatusCode HTTP status code returned by the target; 0 if no response was received.
+	StatusCode int `json:"status_code"`
+
+	// Success Whether the probe run passed.
+	Success bool `json:"success"`
+
+	// Timestamp When the probe ran.
+	Timestamp time.Time `json:"timestamp"`
 }
 
-type ListProbes400JSONResponse ErrorResponse
+// ProbeResultRequest defines model for ProbeResultRequest.
+type ProbeResultRequest struct {
+	// LatencyMs Time until the response was received, in milliseconds.
+	LatencyMs float64 `json:"latency_ms"`
 
-func (response ListProbes400JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	// StatusCode HTTP status code returned by the target; 0 if no response was received.
+	StatusCode int `json:"status_code"`
 
-	return json.NewEncoder(w).Encode(response)
+	// Success Whether the probe run passed.
+	Success bool `json:"success"`
+
+	// Timestamp When the probe ran. Defaults to the time the result is received. Rejected when more than the server's clock skew tolerance ahead of its clock.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
 
-type CreateProbeRequestObject struct {
-	Body *CreateProbeJSONRequestBody
+// ProbeResultSummary defines model for ProbeResultSummary.
+type ProbeResultSummary struct {
+	// Buckets The periods with results, oldest first.
+	Buckets []ResultBucket `json:"buckets"`
+
+	// LatencyMs Latency percentiles in milliseconds, estimated from a histogram: they are exact to within the histogram bucket, whose bounds grow from 1ms to 30s.
+	LatencyMs  *LatencyPercentiles `json:"latency_ms,omitempty"`
+	ProbeId    openapi_types.UUID  `json:"probe_id"`
+	Resolution ResultResolution    `json:"resolution"`
+
+	// Since The start of the first period summarized.
+	Since time.Time `json:"since"`
+
+	// SuccessRatio The share of results that passed; absent when there were none.
+	SuccessRatio *float64 `json:"success_ratio,omitempty"`
+
+	// Successes The number of results that passed.
+	Successes int `json:"successes"`
+
+	// Total The number of results reported.
+	Total int `json:"total"`
+
+	// Window The window summarized, as requested.
+	Window string `json:"window"`
 }
 
-type CreateProbeResponseObject interface {
-	VisitCreateProbeResponse(w http.ResponseWriter) error
+// ProbeResultsArrayResponse defines model for ProbeResultsArrayResponse.
+type ProbeResultsArrayResponse struct {
+	// Results Recent results, newest first.
+	Results []ProbeResultObject `json:"results"`
 }
 
-type CreateProbe201JSONResponse ProbeObject
+// ProbeStatusBatchRequest defines model for ProbeStatusBatchRequest.
+type ProbeStatusBatchRequest struct {
+	// Updates The updates to apply, each probe at most once.
+	Updates []ProbeStatusUpdate `json:"updates"`
+}
 
-func (response CreateProbe201JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(201)
+// ProbeStatusBatchResponse defines model for ProbeStatusBatchResponse.
+type ProbeStatusBatchResponse struct {
+	Results []ProbeStatusUpdateResult `json:"results"`
+}
 
-	return json.NewEncoder(w).Encode(response)
+// ProbeStatusUpdate A status reported for one probe.
+type ProbeStatusUpdate struct {
+	// Id The unique identifier of a probe (UUID format).
+	Id ProbeIdSchema `json:"id"`
+
+	// Message Human-readable details of status_reason. Only accepted with a failed or terminating status.
+	Message *StatusMessageSchema `json:"message,omitempty"`
+
+	// Reason Why the probe is failed or terminating, as a CamelCase token alerts and dashboards can match on. Only accepted with a failed or terminating status. Sending status_reason or status_message replaces both; a status change without them clears them.
+	Reason *StatusReasonSchema `json:"reason,omitempty"`
+
+	// Status The current status of the probe.
+	Status StatusSchema `json:"status"`
 }
 
-type CreateProbe409JSONResponse ErrorResponse
+// ProbeStatusUpdateResult The outcome of one update of a batch.
+type ProbeStatusUpdateResult struct {
+	// Code The status code PATCH /probes/{probe_id} would have answered the update with, e.g. 200, 404 or 409.
+	Code int `json:"code"`
 
-func (response CreateProbe409JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(409)
+	// Id The unique identifier of a probe (UUID format).
+	Id ProbeIdSchema `json:"id"`
+
+	// Message Why the update failed, when it did.
+	Message *string `json:"message,omitempty"`
+
+	// Probe Represents a single probe configuration.
+	Probe *ProbeObject `json:"probe,omitempty"`
+}
+
+// ProbeTemplateObject defines model for ProbeTemplateObject.
+type ProbeTemplateObject struct {
+	// CreatedAt When the template was created.
+	CreatedAt time.Time          `json:"created_at"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
+
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
+	Name   string             `json:"name"`
+
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout    *DurationSchema `json:"timeout,omitempty"`
+	UrlPattern string          `json:"url_pattern"`
+
+	// Variables The variables of url_pattern, in order of appearance.
+	Variables []string `json:"variables"`
+}
+
+// ProbeTemplateRequest defines model for ProbeTemplateRequest.
+type ProbeTemplateRequest struct {
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
+
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
+
+	// Name A name unique among the templates.
+	Name string `json:"name"`
+
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout *DurationSchema `json:"timeout,omitempty"`
+
+	// UrlPattern The static URL of the probes created from the template, with {name} placeholders for the variables each probe supplies.
+	UrlPattern string `json:"url_pattern"`
+}
+
+// ProbeTemplatesArrayResponse defines model for ProbeTemplatesArrayResponse.
+type ProbeTemplatesArrayResponse struct {
+	Templates []ProbeTemplateObject `json:"templates"`
+}
+
+// ProbeTombstoneResponse defines model for ProbeTombstoneResponse.
+type ProbeTombstoneResponse struct {
+	// DeletedAt When the probe was removed from storage.
+	DeletedAt time.Time `json:"deleted_at"`
+
+	// ProbeId The ID of the removed probe.
+	ProbeId openapi_types.UUID `json:"probe_id"`
+	Warning WarningObject      `json:"warning"`
+}
+
+// ProbesArrayResponse defines model for ProbesArrayResponse.
+type ProbesArrayResponse struct {
+	// Features Server-controlled capability hints for agents, keyed by feature name with the supported version as value. Agents should ignore features they do not know and treat a missing feature as unsupported.
+	Features *FeaturesSchema `json:"features,omitempty"`
+
+	// NextPageToken Opaque token to pass as page_token to fetch the next page. Absent on the last page.
+	NextPageToken *string `json:"next_page_token,omitempty"`
+
+	// Probes Array containing one or more probe objects.
+	Probes []ProbeObject `json:"probes"`
+
+	// Version Opaque fingerprint of the probes returned, changing whenever they do. Pass it as version with wait_for_change to wait for the next change.
+	Version *string `json:"version,omitempty"`
+}
+
+// RegionSchema The name of a region or zone agents run in, as given by the region label the agents register with.
+type RegionSchema = string
+
+// RegionsSchema The regions whose agents may run the probe. A probe without regions runs in any region. Updates replace the list as a whole; an empty list removes it.
+type RegionsSchema = []RegionSchema
+
+// ResultBucket The results reported over one period.
+type ResultBucket struct {
+	Start     time.Time `json:"start"`
+	Successes int       `json:"successes"`
+	Total     int       `json:"total"`
+}
+
+// ResultResolution defines model for ResultResolution.
+type ResultResolution string
+
+// ServerTimestampSchema Set by the server; requests that set it are rejected with 400 Bad Request.
+type ServerTimestampSchema = time.Time
+
+// SeveritySchema Severity of the alerts raised when the probe fails.
+type SeveritySchema string
+
+// StaticUrlSchema The static URL to be probed.
+type StaticUrlSchema = string
+
+// StatusCodeRangeSchema An HTTP status code, a class such as 2xx, or an inclusive range such as 200-399.
+type StatusCodeRangeSchema = string
+
+// StatusMessageSchema Human-readable details of status_reason. Only accepted with a failed or terminating status.
+type StatusMessageSchema = string
+
+// StatusReasonSchema Why the probe is failed or terminating, as a CamelCase token alerts and dashboards can match on. Only accepted with a failed or terminating status. Sending status_reason or status_message replaces both; a status change without them clears them.
+type StatusReasonSchema = string
+
+// StatusSchema The current status of the probe.
+type StatusSchema string
+
+// StatusTransition A change of a probe's status.
+type StatusTransition struct {
+	// Actor Who changed it: the agent whose credential the request carried, or else the actor the audit log records, which is "system" for changes the server makes by itself. Absent when the caller is unknown.
+	Actor *string `json:"actor,omitempty"`
+
+	// From The current status of the probe.
+	From StatusSchema `json:"from"`
+
+	// Reason Why the status changed, when known.
+	Reason *string `json:"reason,omitempty"`
+
+	// Timestamp When the status changed.
+	Timestamp time.Time `json:"timestamp"`
+
+	// To The current status of the probe.
+	To StatusSchema `json:"to"`
+}
+
+// TargetStatus The outcome of the last check of one of the probe's URLs.
+type TargetStatus struct {
+	// Message Human-readable details of the outcome, typically why the URL is down.
+	Message *string `json:"message,omitempty"`
+
+	// State Whether the last check of the URL succeeded.
+	State TargetStatusState `json:"state"`
+
+	// Timestamp When the URL was checked.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Url The static URL to be probed.
+	Url StaticUrlSchema `json:"url"`
+}
+
+// TargetStatusState Whether the last check of the URL succeeded.
+type TargetStatusState string
+
+// TargetStatusesSchema The outcome of the last check of each of the probe's URLs, reported by the agent that runs it. Each url must be static_url or one of additional_urls, at most once. A report replaces the list as a whole; like heartbeats, it leaves the generation and update_timestamp unchanged. Outcomes of URLs removed from additional_urls are dropped.
+type TargetStatusesSchema = []TargetStatus
+
+// TargetValidationFailure defines model for TargetValidationFailure.
+type TargetValidationFailure struct {
+	// Check The check the target failed.
+	Check TargetValidationFailureCheck `json:"check"`
+
+	// Message Why the target failed it.
+	Message string `json:"message"`
+}
+
+// TargetValidationFailureCheck The check the target failed.
+type TargetValidationFailureCheck string
+
+// TcpSchema What a tcp probe connects to. It requires the tcp module, which is set when the module is left out. Its static_url is derived from the address, e.g. tcp://db.example.com:5432, and cannot be given; like any static_url it is checked for duplicates. Only tls may change after creation.
+type TcpSchema struct {
+	// Address The host:port to open a TCP connection to.
+	Address string `json:"address"`
+
+	// Tls Whether the probe also completes a TLS handshake once connected.
+	Tls *bool `json:"tls,omitempty"`
+}
+
+// UpdateProbeRequest Fields to update for a probe.
+type UpdateProbeRequest struct {
+	// AdditionalUrls Further URLs checked together with static_url, e.g. the console of the cluster whose API server is static_url. They share the probe's labels, schedule, module, alerting and credentials. Unlike static_url, they may change after creation and are not checked for duplicates against other probes. Updates replace the list as a whole; an empty list removes them.
+	AdditionalUrls *AdditionalUrlsSchema `json:"additional_urls,omitempty"`
+
+	// Alerting Routing metadata for the alerts raised by the probe. Agents expose it as the severity, runbook_url and silence_during_maintenance labels of the probe's targets.
+	Alerting *AlertingSchema `json:"alerting,omitempty"`
+
+	// Assertions What an HTTP response must look like for the probe to pass, enforced by the agents instead of their default of any 2xx status. Only probes of the http_2xx module may have them. Updates replace them as a whole; an empty object removes them.
+	Assertions *AssertionsSchema `json:"assertions,omitempty"`
+
+	// Auth Credentials the probe presents to its target: a basic auth username and password, or a bearer token. The password and token are kept in a Kubernetes Secret, or in an encrypted file for the local store, not with the probe, and responses carry them as REDACTED. Sending REDACTED back keeps the stored value, and an empty object removes the credentials. Agents fetch the values from /probes/{probe_id}/auth.
+	Auth *ProbeAuthSchema `json:"auth,omitempty"`
+
+	// CreationTimestamp Set by the server; requests that set it are rejected with 400 Bad Request.
+	CreationTimestamp *ServerTimestampSchema `json:"creation_timestamp,omitempty"`
+
+	// Dns What a dns probe resolves. It requires the dns module, which is set when the module is left out. Its static_url is derived from the query as an RFC 4501 URI, e.g. dns://10.0.0.10:53/api.example.com?type=A, and cannot be given; like any static_url it is checked for duplicates, so the same query against the same resolver is probed once. Only expected_answers may change after creation.
+	Dns *DnsSchema `json:"dns,omitempty"`
+
+	// Icmp What an icmp probe pings. It requires the icmp module, which is set when the module is left out. Its static_url is derived from the host, e.g. icmp://203.0.113.7, and cannot be given; like any static_url it is checked for duplicates. Only packet_count may change after creation.
+	Icmp *IcmpSchema `json:"icmp,omitempty"`
+
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
+
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
+
+	// Owner Who owns the probe, so alerts raised by its failures can be routed to them. Agents expose it as the owner_team and escalation_contact labels of the probe's targets, and it is part of the probe in webhook and CloudEvents payloads. Servers may require either field on new probes.
+	Owner *OwnerSchema `json:"owner,omitempty"`
+
+	// Paused Whether the probe is paused, e.g. for a maintenance window. Agents must not run paused probes, but keep reconciling them so their heartbeat continues; the probe keeps its status. Terminating probes cannot be paused. Absent when the probe is not paused.
+	Paused *PausedSchema `json:"paused,omitempty"`
+
+	// Regions The regions whose agents may run the probe. A probe without regions runs in any region. Updates replace the list as a whole; an empty list removes it.
+	Regions *RegionsSchema `json:"regions,omitempty"`
+
+	// Status The current status of the probe.
+	Status *StatusSchema `json:"status,omitempty"`
+
+	// StatusMessage Human-readable details of status_reason. Only accepted with a failed or terminating status.
+	StatusMessage *StatusMessageSchema `json:"status_message,omitempty"`
+
+	// StatusReason Why the probe is failed or terminating, as a CamelCase token alerts and dashboards can match on. Only accepted with a failed or terminating status. Sending status_reason or status_message replaces both; a status change without them clears them.
+	StatusReason *StatusReasonSchema `json:"status_reason,omitempty"`
+
+	// TargetStatuses The outcome of the last check of each of the probe's URLs, reported by the agent that runs it. Each url must be static_url or one of additional_urls, at most once. A report replaces the list as a whole; like heartbeats, it leaves the generation and update_timestamp unchanged. Outcomes of URLs removed from additional_urls are dropped.
+	TargetStatuses *TargetStatusesSchema `json:"target_statuses,omitempty"`
+
+	// Tcp What a tcp probe connects to. It requires the tcp module, which is set when the module is left out. Its static_url is derived from the address, e.g. tcp://db.example.com:5432, and cannot be given; like any static_url it is checked for duplicates. Only tls may change after creation.
+	Tcp *TcpSchema `json:"tcp,omitempty"`
+
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout *DurationSchema `json:"timeout,omitempty"`
+
+	// UpdateTimestamp Set by the server; requests that set it are rejected with 400 Bad Request.
+	UpdateTimestamp *ServerTimestampSchema `json:"update_timestamp,omitempty"`
+}
+
+// WarningObject defines model for WarningObject.
+type WarningObject struct {
+	// Message A human-readable error message indicating the resource was not found.
+	Message string `json:"message"`
+}
+
+// WarningResponse defines model for WarningResponse.
+type WarningResponse struct {
+	Warning WarningObject `json:"warning"`
+}
+
+// WebhookEvent The body POSTed to webhooks for each probe lifecycle event.
+type WebhookEvent struct {
+	// Id Unique ID of the event, also sent in the X-Rhobs-Synthetics-Delivery header.
+	Id openapi_types.UUID `json:"id"`
+
+	// PreviousStatus The current status of the probe.
+	PreviousStatus *StatusSchema `json:"previous_status,omitempty"`
+
+	// Probe Represents a single probe configuration.
+	Probe ProbeObject `json:"probe"`
+
+	// Timestamp When the event happened.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Type A probe lifecycle event a webhook can subscribe to.
+	Type WebhookEventType `json:"type"`
+}
+
+// WebhookEventType A probe lifecycle event a webhook can subscribe to.
+type WebhookEventType string
+
+// WebhookObject defines model for WebhookObject.
+type WebhookObject struct {
+	// CreatedAt When the webhook was subscribed.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Events The events delivered to the URL.
+	Events []WebhookEventType `json:"events"`
+	Id     openapi_types.UUID `json:"id"`
+
+	// Url The URL events are POSTed to.
+	Url string `json:"url"`
+}
+
+// WebhookRequest defines model for WebhookRequest.
+type WebhookRequest struct {
+	// Events The events to deliver. All events are delivered when empty or omitted.
+	Events *[]WebhookEventType `json:"events,omitempty"`
+
+	// Secret Key of the HMAC-SHA256 signature sent in the X-Rhobs-Synthetics-Signature header of each delivery. It is never returned.
+	Secret *string `json:"secret,omitempty"`
+
+	// Url The http or https URL events are POSTed to.
+	Url string `json:"url"`
+}
+
+// WebhooksArrayResponse defines model for WebhooksArrayResponse.
+type WebhooksArrayResponse struct {
+	Webhooks []WebhookObject `json:"webhooks"`
+}
+
+// APIKeyIdPathParam defines model for APIKeyIdPathParam.
+type APIKeyIdPathParam = string
+
+// AgentIdPathParam The identifier of a probing agent; must be a valid label value.
+type AgentIdPathParam = AgentIdSchema
+
+// DryRunQueryParam defines model for DryRunQueryParam.
+type DryRunQueryParam = bool
+
+// FieldSelectorQueryParam defines model for FieldSelectorQueryParam.
+type FieldSelectorQueryParam = string
+
+// FieldsQueryParam defines model for FieldsQueryParam.
+type FieldsQueryParam = string
+
+// GroupIdPathParam The ID of a probe group, such as a hosted cluster ID. It must be a valid label value.
+type GroupIdPathParam = ProbeGroupIdSchema
+
+// IdempotencyKeyHeaderParam defines model for IdempotencyKeyHeaderParam.
+type IdempotencyKeyHeaderParam = string
+
+// IfMatchHeaderParam defines model for IfMatchHeaderParam.
+type IfMatchHeaderParam = string
+
+// IfNoneMatchHeaderParam defines model for IfNoneMatchHeaderParam.
+type IfNoneMatchHeaderParam = string
+
+// LabelSelectorQueryParam defines model for LabelSelectorQueryParam.
+type LabelSelectorQueryParam = string
+
+// LimitQueryParam defines model for LimitQueryParam.
+type LimitQueryParam = int
+
+// MinGenerationQueryParam defines model for MinGenerationQueryParam.
+type MinGenerationQueryParam = int64
+
+// OrderQueryParam defines model for OrderQueryParam.
+type OrderQueryParam string
+
+// PageTokenQueryParam defines model for PageTokenQueryParam.
+type PageTokenQueryParam = string
+
+// ProbeIdPathParam The unique identifier of a probe (UUID format).
+type ProbeIdPathParam = ProbeIdSchema
+
+// SortByQueryParam defines model for SortByQueryParam.
+type SortByQueryParam string
+
+// TemplateIdPathParam defines model for TemplateIdPathParam.
+type TemplateIdPathParam = openapi_types.UUID
+
+// WebhookIdPathParam defines model for WebhookIdPathParam.
+type WebhookIdPathParam = openapi_types.UUID
+
+// ListAuditEntriesParams defines parameters for ListAuditEntries.
+type ListAuditEntriesParams struct {
+	// ProbeId Only return changes to this probe.
+	ProbeId *ProbeIdSchema `form:"probe_id,omitempty" json:"probe_id,omitempty"`
+
+	// Actor Only return changes made by this actor, given by name or, when actors are hashed, by hash.
+	Actor *string `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Operation Only return changes made by this operation.
+	Operation *AuditOperation `form:"operation,omitempty" json:"operation,omitempty"`
+
+	// Since Only return changes made at or after this time.
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Limit Maximum number of entries to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListProbesParams defines parameters for ListProbes.
+type ListProbesParams struct {
+	// LabelSelector A comma-separated list of key=value labels to filter on.
+	LabelSelector *LabelSelectorQueryParam `form:"label_selector,omitempty" json:"label_selector,omitempty"`
+
+	// FieldSelector A comma-separated list of conditions on probe fields, all of which must match. Supported are status (=, ==, !=, in, notin), id (=, ==, in), owner.team (=, ==, !=, in, notin) and static_url (= for an exact match, ^= for a prefix match; the URL may not contain a comma).
+	FieldSelector *FieldSelectorQueryParam `form:"field_selector,omitempty" json:"field_selector,omitempty"`
+
+	// MinGeneration Only return probes whose generation is at least this value. Generations are counted per probe.
+	MinGeneration *MinGenerationQueryParam `form:"min_generation,omitempty" json:"min_generation,omitempty"`
+
+	// Limit Maximum number of probes to return. When more match, the response carries a next_page_token.
+	Limit *LimitQueryParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// PageToken Opaque token from a previous response's next_page_token. It is only valid with the same label_selector, field_selector, min_generation, sort_by, order and tenant it was issued for.
+	PageToken *PageTokenQueryParam `form:"page_token,omitempty" json:"page_token,omitempty"`
+
+	// Fields A comma-separated list of the probe fields to return, to shrink large listings. Any top-level field of a probe may be named; probes hold every field when it is absent. Selection happens after filtering, so field_selector may use fields left out here.
+	Fields *FieldsQueryParam `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// SortBy The field probes are sorted by, with ties broken by id. Without it, probes are sorted by id when order is set or the response is paginated, and returned in storage order otherwise. Probes without a creation_timestamp sort before all others by created_at.
+	SortBy *ListProbesParamsSortBy `form:"sort_by,omitempty" json:"sort_by,omitempty"`
+
+	// Order Whether probes are sorted in ascending or descending order.
+	Order *ListProbesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// WaitForChange Block until the probes the request returns differ from those identified by version, or from those matching when the request arrived if version is not set, and return them then. When timeout expires first, the unchanged probes are returned. Meant for agents polling for their probes.
+	WaitForChange *bool `form:"wait_for_change,omitempty" json:"wait_for_change,omitempty"`
+
+	// Timeout How long a wait_for_change request blocks at most, as a duration such as 30s. At most 2m.
+	Timeout *string `form:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// Version The version of an earlier response. A wait_for_change request returns as soon as the probes differ from those it returned, including when they already do.
+	Version *string `form:"version,omitempty" json:"version,omitempty"`
+
+	// IfNoneMatch ETags returned by previous requests with the same query, separated by commas. When the response would have one of them, 304 is returned without a body instead.
+	IfNoneMatch *IfNoneMatchHeaderParam `json:"If-None-Match,omitempty"`
+}
+
+// ListProbesParamsSortBy defines parameters for ListProbes.
+type ListProbesParamsSortBy string
+
+// ListProbesParamsOrder defines parameters for ListProbes.
+type ListProbesParamsOrder string
+
+// CreateProbeParams defines parameters for CreateProbe.
+type CreateProbeParams struct {
+	// DryRun Validate the request as usual, including protected labels, status transitions, duplicate URLs, mutation hooks and If-Match, and answer with the would-be result without storing anything, recording an audit entry or notifying webhooks. Fields the store maintains, such as generation, resource_version and the timestamps, are left as they are stored, or absent for probes that would be created.
+	DryRun *DryRunQueryParam `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// Validate Check the probe's target before creating it. With connectivity, the URL must use one of the server's allowed schemes, its host must resolve, none of its addresses may be private or in another special-purpose range, and an http or https target must answer a HEAD request within the server's timeout. Redirects are not followed. Failed checks are returned in validation_failures with 422.
+	Validate *CreateProbeParamsValidate `form:"validate,omitempty" json:"validate,omitempty"`
+
+	// IdempotencyKey A unique key, such as a UUID, that makes the request safe to retry. Retries with the same key and body get the response of the first attempt, marked with "Idempotent-Replayed: true", instead of creating the probe again. Keys are kept per tenant and caller credential for the configured time.
+	IdempotencyKey *IdempotencyKeyHeaderParam `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateProbeParamsValidate defines parameters for CreateProbe.
+type CreateProbeParamsValidate string
+
+// DiffProbesParams defines parameters for DiffProbes.
+type DiffProbesParams struct {
+	// LeftSelector The label selector of the probes compared against, e.g. the old ones.
+	LeftSelector string `form:"left_selector" json:"left_selector"`
+
+	// RightSelector The label selector of the probes compared, e.g. the new ones.
+	RightSelector string `form:"right_selector" json:"right_selector"`
+
+	// MatchLabel The label whose value pairs probes across the two sides. Probes are paired by static_url when omitted; probes without the label are then not paired.
+	MatchLabel *string `form:"match_label,omitempty" json:"match_label,omitempty"`
+}
+
+// ExportProbesParams defines parameters for ExportProbes.
+type ExportProbesParams struct {
+	// LabelSelector A comma-separated list of key=value labels to filter on.
+	LabelSelector *LabelSelectorQueryParam `form:"label_selector,omitempty" json:"label_selector,omitempty"`
+
+	// Status A comma-separated list of statuses; only probes with one of them are exported.
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+
+	// Fields A comma-separated list of the fields of bundled probes to export, every field when absent. Keep static_url in it for the bundle to be importable.
+	Fields *string `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// Format The encoding of the bundle. ndjson streams one probe per line, without the version and exported_at of the bundle, for tools processing large inventories line by line.
+	Format *BundleFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ImportProbesParams defines parameters for ImportProbes.
+type ImportProbesParams struct {
+	// DryRun Validate the request as usual, including protected labels, status transitions, duplicate URLs, mutation hooks and If-Match, and answer with the would-be result without storing anything, recording an audit entry or notifying webhooks. Fields the store maintains, such as generation, resource_version and the timestamps, are left as they are stored, or absent for probes that would be created.
+	DryRun *DryRunQueryParam `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// OnConflict What to do with a probe conflicting with a stored one: skip it, overwrite the settings and labels of the stored probe with its own, or fail the import without changing anything.
+	OnConflict *ImportConflictStrategy `form:"on_conflict,omitempty" json:"on_conflict,omitempty"`
+
+	// AllOrNothing Import nothing if any probe of the bundle fails validation, answering 422 with the errors, instead of importing the valid ones.
+	AllOrNothing *bool `form:"all_or_nothing,omitempty" json:"all_or_nothing,omitempty"`
+}
+
+// ListProbeProblemsParams defines parameters for ListProbeProblems.
+type ListProbeProblemsParams struct {
+	// LabelSelector A comma-separated list of key=value labels to filter on.
+	LabelSelector *LabelSelectorQueryParam `form:"label_selector,omitempty" json:"label_selector,omitempty"`
+
+	// PendingFor How long after its creation a pending probe is reported. Defaults to 15m.
+	PendingFor *DurationSchema `form:"pending_for,omitempty" json:"pending_for,omitempty"`
+
+	// TerminatingFor How long after its deletion a terminating probe is reported. Defaults to 15m.
+	TerminatingFor *DurationSchema `form:"terminating_for,omitempty" json:"terminating_for,omitempty"`
+
+	// StaleAfter How long after its last check a failed probe is reported. A probe is checked when its last-reconciled heartbeat is renewed or a result is reported for it. Defaults to 15m.
+	StaleAfter *DurationSchema `form:"stale_after,omitempty" json:"stale_after,omitempty"`
+}
+
+// SearchProbesParams defines parameters for SearchProbes.
+type SearchProbesParams struct {
+	// Q The text to search for, or an RE2 regular expression with regex=true.
+	Q string `form:"q" json:"q"`
+
+	// Regex Match q as a case-insensitive RE2 regular expression instead of as text.
+	Regex *bool `form:"regex,omitempty" json:"regex,omitempty"`
+
+	// LabelSelector A comma-separated list of key=value labels to filter on.
+	LabelSelector *LabelSelectorQueryParam `form:"label_selector,omitempty" json:"label_selector,omitempty"`
+
+	// Limit Maximum number of probes to return. When more match, the response carries a next_page_token.
+	Limit *LimitQueryParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// PageToken Opaque token from a previous response's next_page_token. It is only valid with the same q, regex, label_selector and tenant it was issued for.
+	PageToken *string `form:"page_token,omitempty" json:"page_token,omitempty"`
+}
+
+// DeleteProbeParams defines parameters for DeleteProbe.
+type DeleteProbeParams struct {
+	// DryRun Validate the request as usual, including protected labels, status transitions, duplicate URLs, mutation hooks and If-Match, and answer with the would-be result without storing anything, recording an audit entry or notifying webhooks. Fields the store maintains, such as generation, resource_version and the timestamps, are left as they are stored, or absent for probes that would be created.
+	DryRun *DryRunQueryParam `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// IfMatch ETag returned by a previous GET or PATCH of the probe. The request is only applied if the probe has not changed since; "*" matches any version.
+	IfMatch *IfMatchHeaderParam `json:"If-Match,omitempty"`
+}
+
+// UpdateProbeParams defines parameters for UpdateProbe.
+type UpdateProbeParams struct {
+	// DryRun Validate the request as usual, including protected labels, status transitions, duplicate URLs, mutation hooks and If-Match, and answer with the would-be result without storing anything, recording an audit entry or notifying webhooks. Fields the store maintains, such as generation, resource_version and the timestamps, are left as they are stored, or absent for probes that would be created.
+	DryRun *DryRunQueryParam `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// IfMatch ETag returned by a previous GET or PATCH of the probe. The request is only applied if the probe has not changed since; "*" matches any version.
+	IfMatch *IfMatchHeaderParam `json:"If-Match,omitempty"`
+}
+
+// PauseProbeParams defines parameters for PauseProbe.
+type PauseProbeParams struct {
+	// IfMatch ETag returned by a previous GET or PATCH of the probe. The request is only applied if the probe has not changed since; "*" matches any version.
+	IfMatch *IfMatchHeaderParam `json:"If-Match,omitempty"`
+}
+
+// SummarizeProbeResultsParams defines parameters for SummarizeProbeResults.
+type SummarizeProbeResultsParams struct {
+	// Window How far back to summarize, as a duration such as 12h or 7d, at most 90d. The summary covers the current period and enough whole periods before it. Defaults to 24h.
+	Window *string `form:"window,omitempty" json:"window,omitempty"`
+
+	// Resolution The period of each bucket. Hourly buckets are kept for 48 hours only. Defaults to hour for windows of up to 48 hours, and day otherwise.
+	Resolution *ResultResolution `form:"resolution,omitempty" json:"resolution,omitempty"`
+}
+
+// ResumeProbeParams defines parameters for ResumeProbe.
+type ResumeProbeParams struct {
+	// IfMatch ETag returned by a previous GET or PATCH of the probe. The request is only applied if the probe has not changed since; "*" matches any version.
+	IfMatch *IfMatchHeaderParam `json:"If-Match,omitempty"`
+}
+
+// CreateAgentBootstrapTokenJSONRequestBody defines body for CreateAgentBootstrapToken for application/json ContentType.
+type CreateAgentBootstrapTokenJSONRequestBody = AgentBootstrapTokenRequest
+
+// RegisterAgentJSONRequestBody defines body for RegisterAgent for application/json ContentType.
+type RegisterAgentJSONRequestBody = AgentRegistrationRequest
+
+// CreateAPIKeyJSONRequestBody defines body for CreateAPIKey for application/json ContentType.
+type CreateAPIKeyJSONRequestBody = APIKeyRequest
+
+// CreateProbeGroupJSONRequestBody defines body for CreateProbeGroup for application/json ContentType.
+type CreateProbeGroupJSONRequestBody = ProbeGroupRequest
+
+// UpdateProbeGroupJSONRequestBody defines body for UpdateProbeGroup for application/json ContentType.
+type UpdateProbeGroupJSONRequestBody = ProbeGroupUpdateRequest
+
+// CreateProbeTemplateJSONRequestBody defines body for CreateProbeTemplate for application/json ContentType.
+type CreateProbeTemplateJSONRequestBody = ProbeTemplateRequest
+
+// UpdateProbeTemplateJSONRequestBody defines body for UpdateProbeTemplate for application/json ContentType.
+type UpdateProbeTemplateJSONRequestBody = ProbeTemplateRequest
+
+// UpdateProbeStatusesJSONRequestBody defines body for UpdateProbeStatuses for application/json ContentType.
+type UpdateProbeStatusesJSONRequestBody = ProbeStatusBatchRequest
+
+// CreateProbeJSONRequestBody defines body for CreateProbe for application/json ContentType.
+type CreateProbeJSONRequestBody = CreateProbeRequest
+
+// ImportProbesJSONRequestBody defines body for ImportProbes for application/json ContentType.
+type ImportProbesJSONRequestBody = ProbeBundle
+
+// UpdateProbeJSONRequestBody defines body for UpdateProbe for application/json ContentType.
+type UpdateProbeJSONRequestBody = UpdateProbeRequest
+
+// ReportProbeResultJSONRequestBody defines body for ReportProbeResult for application/json ContentType.
+type ReportProbeResultJSONRequestBody = ProbeResultRequest
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = WebhookRequest
+
+// UpdateWebhookJSONRequestBody defines body for UpdateWebhook for application/json ContentType.
+type UpdateWebhookJSONRequestBody = WebhookRequest
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Mint a bootstrap token for agents to register with
+	// (POST /agent-bootstrap-tokens)
+	CreateAgentBootstrapToken(w http.ResponseWriter, r *http.Request)
+	// Register an agent or refresh its heartbeat
+	// (PUT /agents/{agent_id})
+	RegisterAgent(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam)
+	// Revoke the credentials of an agent
+	// (DELETE /agents/{agent_id}/credentials)
+	RevokeAgentCredentials(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam)
+	// Exchange a bootstrap token for an agent credential, or renew one
+	// (POST /agents/{agent_id}/credentials)
+	CreateAgentCredential(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam)
+	// Get the probes assigned to an agent
+	// (GET /agents/{agent_id}/probes)
+	ListAgentProbes(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam)
+	// Get the API keys
+	// (GET /apikeys)
+	ListAPIKeys(w http.ResponseWriter, r *http.Request)
+	// Mint an API key
+	// (POST /apikeys)
+	CreateAPIKey(w http.ResponseWriter, r *http.Request)
+	// Revoke an API key
+	// (DELETE /apikeys/{key_id})
+	DeleteAPIKey(w http.ResponseWriter, r *http.Request, keyId APIKeyIdPathParam)
+	// Get the recorded changes to probes
+	// (GET /audit)
+	ListAuditEntries(w http.ResponseWriter, r *http.Request, params ListAuditEntriesParams)
+	// Get the probe groups
+	// (GET /probe-groups)
+	ListProbeGroups(w http.ResponseWriter, r *http.Request)
+	// Create a probe group
+	// (POST /probe-groups)
+	CreateProbeGroup(w http.ResponseWriter, r *http.Request)
+	// Delete a probe group
+	// (DELETE /probe-groups/{group_id})
+	DeleteProbeGroup(w http.ResponseWriter, r *http.Request, groupId GroupIdPathParam)
+	// Get a probe group by its ID
+	// (GET /probe-groups/{group_id})
+	GetProbeGroup(w http.ResponseWriter, r *http.Request, groupId GroupIdPathParam)
+	// Pause or resume a probe group
+	// (PATCH /probe-groups/{group_id})
+	UpdateProbeGroup(w http.ResponseWriter, r *http.Request, groupId GroupIdPathParam)
+	// Get the probe templates
+	// (GET /probe-templates)
+	ListProbeTemplates(w http.ResponseWriter, r *http.Request)
+	// Create a probe template
+	// (POST /probe-templates)
+	CreateProbeTemplate(w http.ResponseWriter, r *http.Request)
+	// Delete a probe template
+	// (DELETE /probe-templates/{template_id})
+	DeleteProbeTemplate(w http.ResponseWriter, r *http.Request, templateId TemplateIdPathParam)
+	// Get a probe template by its ID
+	// (GET /probe-templates/{template_id})
+	GetProbeTemplate(w http.ResponseWriter, r *http.Request, templateId TemplateIdPathParam)
+	// Replace a probe template
+	// (PUT /probe-templates/{template_id})
+	UpdateProbeTemplate(w http.ResponseWriter, r *http.Request, templateId TemplateIdPathParam)
+	// Get a list of all configured probes
+	// (GET /probes)
+	ListProbes(w http.ResponseWriter, r *http.Request, params ListProbesParams)
+	// Update the statuses of several probes
+	// (PATCH /probes)
+	UpdateProbeStatuses(w http.ResponseWriter, r *http.Request)
+	// Creates a new probe
+	// (POST /probes)
+	CreateProbe(w http.ResponseWriter, r *http.Request, params CreateProbeParams)
+	// Compare the probes matched by two label selectors
+	// (GET /probes/diff)
+	DiffProbes(w http.ResponseWriter, r *http.Request, params DiffProbesParams)
+	// Export probes as a bundle
+	// (GET /probes/export)
+	ExportProbes(w http.ResponseWriter, r *http.Request, params ExportProbesParams)
+	// Import probes from a bundle
+	// (POST /probes/import)
+	ImportProbes(w http.ResponseWriter, r *http.Request, params ImportProbesParams)
+	// Get the probes in an anomalous state
+	// (GET /probes/problems)
+	ListProbeProblems(w http.ResponseWriter, r *http.Request, params ListProbeProblemsParams)
+	// Search probes by URL and label values
+	// (GET /probes/search)
+	SearchProbes(w http.ResponseWriter, r *http.Request, params SearchProbesParams)
+	// Deletes a probe matching provided ID
+	// (DELETE /probes/{probe_id})
+	DeleteProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params DeleteProbeParams)
+	// Get a probe by its ID
+	// (GET /probes/{probe_id})
+	GetProbeById(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+	// Updates a probe by its ID
+	// (PATCH /probes/{probe_id})
+	UpdateProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params UpdateProbeParams)
+	// Get the credentials of a probe
+	// (GET /probes/{probe_id}/auth)
+	GetProbeAuth(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+	// Get the status history of a probe
+	// (GET /probes/{probe_id}/history)
+	GetProbeHistory(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+	// Pause a probe
+	// (POST /probes/{probe_id}/pause)
+	PauseProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params PauseProbeParams)
+	// Get the most recent results reported for a probe
+	// (GET /probes/{probe_id}/results)
+	ListProbeResults(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+	// Report the outcome of a probe run
+	// (POST /probes/{probe_id}/results)
+	ReportProbeResult(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+	// Summarize the results reported for a probe
+	// (GET /probes/{probe_id}/results/summary)
+	SummarizeProbeResults(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params SummarizeProbeResultsParams)
+	// Resume a paused probe
+	// (POST /probes/{probe_id}/resume)
+	ResumeProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params ResumeProbeParams)
+	// Get the webhook subscriptions
+	// (GET /webhooks)
+	ListWebhooks(w http.ResponseWriter, r *http.Request)
+	// Subscribe a webhook to probe lifecycle events
+	// (POST /webhooks)
+	CreateWebhook(w http.ResponseWriter, r *http.Request)
+	// Unsubscribe a webhook
+	// (DELETE /webhooks/{webhook_id})
+	DeleteWebhook(w http.ResponseWriter, r *http.Request, webhookId WebhookIdPathParam)
+	// Get a webhook subscription by its ID
+	// (GET /webhooks/{webhook_id})
+	GetWebhook(w http.ResponseWriter, r *http.Request, webhookId WebhookIdPathParam)
+	// Replace a webhook subscription
+	// (PUT /webhooks/{webhook_id})
+	UpdateWebhook(w http.ResponseWriter, r *http.Request, webhookId WebhookIdPathParam)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// CreateAgentBootstrapToken operation middleware
+func (siw *ServerInterfaceWrapper) CreateAgentBootstrapToken(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAgentBootstrapToken(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RegisterAgent operation middleware
+func (siw *ServerInterfaceWrapper) RegisterAgent(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "agent_id" -------------
+	var agentId AgentIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "agent_id", r.PathValue("agent_id"), &agentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "agent_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RegisterAgent(w, r, agentId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeAgentCredentials operation middleware
+func (siw *ServerInterfaceWrapper) RevokeAgentCredentials(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "agent_id" -------------
+	var agentId AgentIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "agent_id", r.PathValue("agent_id"), &agentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "agent_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeAgentCredentials(w, r, agentId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateAgentCredential operation middleware
+func (siw *ServerInterfaceWrapper) CreateAgentCredential(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "agent_id" -------------
+	var agentId AgentIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "agent_id", r.PathValue("agent_id"), &agentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "agent_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAgentCredential(w, r, agentId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAgentProbes operation middleware
+func (siw *ServerInterfaceWrapper) ListAgentProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "agent_id" -------------
+	var agentId AgentIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "agent_id", r.PathValue("agent_id"), &agentId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "agent_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAgentProbes(w, r, agentId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAPIKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAPIKeys(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateAPIKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateAPIKey(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteAPIKey operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "key_id" -------------
+	var keyId APIKeyIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "key_id", r.PathValue("key_id"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "key_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteAPIKey(w, r, keyId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAuditEntries operation middleware
+func (siw *ServerInterfaceWrapper) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditEntriesParams
+
+	// ------------- Optional query parameter "probe_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "probe_id", r.URL.Query(), &params.ProbeId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "actor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor", r.URL.Query(), &params.Actor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "actor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "operation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "operation", r.URL.Query(), &params.Operation)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "operation", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAuditEntries(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListProbeGroups operation middleware
+func (siw *ServerInterfaceWrapper) ListProbeGroups(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProbeGroups(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateProbeGroup operation middleware
+func (siw *ServerInterfaceWrapper) CreateProbeGroup(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateProbeGroup(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteProbeGroup operation middleware
+func (siw *ServerInterfaceWrapper) DeleteProbeGroup(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "group_id" -------------
+	var groupId GroupIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "group_id", r.PathValue("group_id"), &groupId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "group_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteProbeGroup(w, r, groupId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProbeGroup operation middleware
+func (siw *ServerInterfaceWrapper) GetProbeGroup(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "group_id" -------------
+	var groupId GroupIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "group_id", r.PathValue("group_id"), &groupId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "group_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProbeGroup(w, r, groupId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateProbeGroup operation middleware
+func (siw *ServerInterfaceWrapper) UpdateProbeGroup(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "group_id" -------------
+	var groupId GroupIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "group_id", r.PathValue("group_id"), &groupId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "group_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateProbeGroup(w, r, groupId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListProbeTemplates operation middleware
+func (siw *ServerInterfaceWrapper) ListProbeTemplates(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProbeTemplates(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateProbeTemplate operation middleware
+func (siw *ServerInterfaceWrapper) CreateProbeTemplate(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateProbeTemplate(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteProbeTemplate operation middleware
+func (siw *ServerInterfaceWrapper) DeleteProbeTemplate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "template_id" -------------
+	var templateId TemplateIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "template_id", r.PathValue("template_id"), &templateId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "template_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteProbeTemplate(w, r, templateId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProbeTemplate operation middleware
+func (siw *ServerInterfaceWrapper) GetProbeTemplate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "template_id" -------------
+	var templateId TemplateIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "template_id", r.PathValue("template_id"), &templateId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "template_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProbeTemplate(w, r, templateId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateProbeTemplate operation middleware
+func (siw *ServerInterfaceWrapper) UpdateProbeTemplate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "template_id" -------------
+	var templateId TemplateIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "template_id", r.PathValue("template_id"), &templateId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "template_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateProbeTemplate(w, r, templateId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListProbes operation middleware
+func (siw *ServerInterfaceWrapper) ListProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListProbesParams
+
+	// ------------- Optional query parameter "label_selector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_selector", r.URL.Query(), &params.LabelSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "field_selector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "field_selector", r.URL.Query(), &params.FieldSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "field_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "min_generation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_generation", r.URL.Query(), &params.MinGeneration)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "min_generation", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort_by" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort_by", r.URL.Query(), &params.SortBy)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort_by", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "wait_for_change" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "wait_for_change", r.URL.Query(), &params.WaitForChange)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "wait_for_change", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "timeout" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "timeout", r.URL.Query(), &params.Timeout)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "timeout", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "version" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "version", r.URL.Query(), &params.Version)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "version", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatchHeaderParam
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProbes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateProbeStatuses operation middleware
+func (siw *ServerInterfaceWrapper) UpdateProbeStatuses(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateProbeStatuses(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateProbe operation middleware
+func (siw *ServerInterfaceWrapper) CreateProbe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateProbeParams
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "validate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "validate", r.URL.Query(), &params.Validate)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "validate", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKeyHeaderParam
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateProbe(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DiffProbes operation middleware
+func (siw *ServerInterfaceWrapper) DiffProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DiffProbesParams
+
+	// ------------- Required query parameter "left_selector" -------------
+
+	if paramValue := r.URL.Query().Get("left_selector"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "left_selector"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "left_selector", r.URL.Query(), &params.LeftSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "left_selector", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "right_selector" -------------
+
+	if paramValue := r.URL.Query().Get("right_selector"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "right_selector"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "right_selector", r.URL.Query(), &params.RightSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "right_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "match_label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "match_label", r.URL.Query(), &params.MatchLabel)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "match_label", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DiffProbes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportProbes operation middleware
+func (siw *ServerInterfaceWrapper) ExportProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportProbesParams
+
+	// ------------- Optional query parameter "label_selector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_selector", r.URL.Query(), &params.LabelSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportProbes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ImportProbes operation middleware
+func (siw *ServerInterfaceWrapper) ImportProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportProbesParams
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "on_conflict" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "on_conflict", r.URL.Query(), &params.OnConflict)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "on_conflict", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "all_or_nothing" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "all_or_nothing", r.URL.Query(), &params.AllOrNothing)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "all_or_nothing", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportProbes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListProbeProblems operation middleware
+func (siw *ServerInterfaceWrapper) ListProbeProblems(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListProbeProblemsParams
+
+	// ------------- Optional query parameter "label_selector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_selector", r.URL.Query(), &params.LabelSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "pending_for" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pending_for", r.URL.Query(), &params.PendingFor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "pending_for", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "terminating_for" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "terminating_for", r.URL.Query(), &params.TerminatingFor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "terminating_for", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "stale_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "stale_after", r.URL.Query(), &params.StaleAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "stale_after", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProbeProblems(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SearchProbes operation middleware
+func (siw *ServerInterfaceWrapper) SearchProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchProbesParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "regex" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "regex", r.URL.Query(), &params.Regex)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "regex", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "label_selector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_selector", r.URL.Query(), &params.LabelSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchProbes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteProbe operation middleware
+func (siw *ServerInterfaceWrapper) DeleteProbe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteProbeParams
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeaderParam
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteProbe(w, r, probeId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProbeById operation middleware
+func (siw *ServerInterfaceWrapper) GetProbeById(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProbeById(w, r, probeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateProbe operation middleware
+func (siw *ServerInterfaceWrapper) UpdateProbe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateProbeParams
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeaderParam
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateProbe(w, r, probeId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProbeAuth operation middleware
+func (siw *ServerInterfaceWrapper) GetProbeAuth(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProbeAuth(w, r, probeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProbeHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetProbeHistory(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProbeHistory(w, r, probeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PauseProbe operation middleware
+func (siw *ServerInterfaceWrapper) PauseProbe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PauseProbeParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeaderParam
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PauseProbe(w, r, probeId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListProbeResults operation middleware
+func (siw *ServerInterfaceWrapper) ListProbeResults(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProbeResults(w, r, probeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReportProbeResult operation middleware
+func (siw *ServerInterfaceWrapper) ReportProbeResult(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReportProbeResult(w, r, probeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SummarizeProbeResults operation middleware
+func (siw *ServerInterfaceWrapper) SummarizeProbeResults(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SummarizeProbeResultsParams
+
+	// ------------- Optional query parameter "window" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "window", r.URL.Query(), &params.Window)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "window", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "resolution" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "resolution", r.URL.Query(), &params.Resolution)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "resolution", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SummarizeProbeResults(w, r, probeId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ResumeProbe operation middleware
+func (siw *ServerInterfaceWrapper) ResumeProbe(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ResumeProbeParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatchHeaderParam
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResumeProbe(w, r, probeId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListWebhooks(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateWebhook(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteWebhook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", r.PathValue("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteWebhook(w, r, webhookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetWebhook operation middleware
+func (siw *ServerInterfaceWrapper) GetWebhook(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", r.PathValue("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetWebhook(w, r, webhookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateWebhook operation middleware
+func (siw *ServerInterfaceWrapper) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", r.PathValue("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "webhook_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateWebhook(w, r, webhookId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("POST "+options.BaseURL+"/agent-bootstrap-tokens", wrapper.CreateAgentBootstrapToken)
+	m.HandleFunc("PUT "+options.BaseURL+"/agents/{agent_id}", wrapper.RegisterAgent)
+	m.HandleFunc("DELETE "+options.BaseURL+"/agents/{agent_id}/credentials", wrapper.RevokeAgentCredentials)
+	m.HandleFunc("POST "+options.BaseURL+"/agents/{agent_id}/credentials", wrapper.CreateAgentCredential)
+	m.HandleFunc("GET "+options.BaseURL+"/agents/{agent_id}/probes", wrapper.ListAgentProbes)
+	m.HandleFunc("GET "+options.BaseURL+"/apikeys", wrapper.ListAPIKeys)
+	m.HandleFunc("POST "+options.BaseURL+"/apikeys", wrapper.CreateAPIKey)
+	m.HandleFunc("DELETE "+options.BaseURL+"/apikeys/{key_id}", wrapper.DeleteAPIKey)
+	m.HandleFunc("GET "+options.BaseURL+"/audit", wrapper.ListAuditEntries)
+	m.HandleFunc("GET "+options.BaseURL+"/probe-groups", wrapper.ListProbeGroups)
+	m.HandleFunc("POST "+options.BaseURL+"/probe-groups", wrapper.CreateProbeGroup)
+	m.HandleFunc("DELETE "+options.BaseURL+"/probe-groups/{group_id}", wrapper.DeleteProbeGroup)
+	m.HandleFunc("GET "+options.BaseURL+"/probe-groups/{group_id}", wrapper.GetProbeGroup)
+	m.HandleFunc("PATCH "+options.BaseURL+"/probe-groups/{group_id}", wrapper.UpdateProbeGroup)
+	m.HandleFunc("GET "+options.BaseURL+"/probe-templates", wrapper.ListProbeTemplates)
+	m.HandleFunc("POST "+options.BaseURL+"/probe-templates", wrapper.CreateProbeTemplate)
+	m.HandleFunc("DELETE "+options.BaseURL+"/probe-templates/{template_id}", wrapper.DeleteProbeTemplate)
+	m.HandleFunc("GET "+options.BaseURL+"/probe-templates/{template_id}", wrapper.GetProbeTemplate)
+	m.HandleFunc("PUT "+options.BaseURL+"/probe-templates/{template_id}", wrapper.UpdateProbeTemplate)
+	m.HandleFunc("GET "+options.BaseURL+"/probes", wrapper.ListProbes)
+	m.HandleFunc("PATCH "+options.BaseURL+"/probes", wrapper.UpdateProbeStatuses)
+	m.HandleFunc("POST "+options.BaseURL+"/probes", wrapper.CreateProbe)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/diff", wrapper.DiffProbes)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/export", wrapper.ExportProbes)
+	m.HandleFunc("POST "+options.BaseURL+"/probes/import", wrapper.ImportProbes)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/problems", wrapper.ListProbeProblems)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/search", wrapper.SearchProbes)
+	m.HandleFunc("DELETE "+options.BaseURL+"/probes/{probe_id}", wrapper.DeleteProbe)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}", wrapper.GetProbeById)
+	m.HandleFunc("PATCH "+options.BaseURL+"/probes/{probe_id}", wrapper.UpdateProbe)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}/auth", wrapper.GetProbeAuth)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}/history", wrapper.GetProbeHistory)
+	m.HandleFunc("POST "+options.BaseURL+"/probes/{probe_id}/pause", wrapper.PauseProbe)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}/results", wrapper.ListProbeResults)
+	m.HandleFunc("POST "+options.BaseURL+"/probes/{probe_id}/results", wrapper.ReportProbeResult)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}/results/summary", wrapper.SummarizeProbeResults)
+	m.HandleFunc("POST "+options.BaseURL+"/probes/{probe_id}/resume", wrapper.ResumeProbe)
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks", wrapper.ListWebhooks)
+	m.HandleFunc("POST "+options.BaseURL+"/webhooks", wrapper.CreateWebhook)
+	m.HandleFunc("DELETE "+options.BaseURL+"/webhooks/{webhook_id}", wrapper.DeleteWebhook)
+	m.HandleFunc("GET "+options.BaseURL+"/webhooks/{webhook_id}", wrapper.GetWebhook)
+	m.HandleFunc("PUT "+options.BaseURL+"/webhooks/{webhook_id}", wrapper.UpdateWebhook)
+
+	return m
+}
+
+type CreateAgentBootstrapTokenRequestObject struct {
+	Body *CreateAgentBootstrapTokenJSONRequestBody
+}
+
+type CreateAgentBootstrapTokenResponseObject interface {
+	VisitCreateAgentBootstrapTokenResponse(w http.ResponseWriter) error
+}
+
+type CreateAgentBootstrapToken201JSONResponse AgentBootstrapTokenObject
+
+func (response CreateAgentBootstrapToken201JSONResponse) VisitCreateAgentBootstrapTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentBootstrapToken400JSONResponse ErrorResponse
+
+func (response CreateAgentBootstrapToken400JSONResponse) VisitCreateAgentBootstrapTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentBootstrapToken403JSONResponse ErrorResponse
+
+func (response CreateAgentBootstrapToken403JSONResponse) VisitCreateAgentBootstrapTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentBootstrapToken501JSONResponse ErrorResponse
+
+func (response CreateAgentBootstrapToken501JSONResponse) VisitCreateAgentBootstrapTokenResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterAgentRequestObject struct {
+	AgentId AgentIdPathParam `json:"agent_id"`
+	Body    *RegisterAgentJSONRequestBody
+}
+
+type RegisterAgentResponseObject interface {
+	VisitRegisterAgentResponse(w http.ResponseWriter) error
+}
+
+type RegisterAgent200JSONResponse AgentObject
+
+func (response RegisterAgent200JSONResponse) VisitRegisterAgentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterAgent400JSONResponse ErrorResponse
+
+func (response RegisterAgent400JSONResponse) VisitRegisterAgentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterAgent401JSONResponse ErrorResponse
+
+func (response RegisterAgent401JSONResponse) VisitRegisterAgentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RegisterAgent403JSONResponse ErrorResponse
+
+func (response RegisterAgent403JSONResponse) VisitRegisterAgentResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAgentCredentialsRequestObject struct {
+	AgentId AgentIdPathParam `json:"agent_id"`
+}
+
+type RevokeAgentCredentialsResponseObject interface {
+	VisitRevokeAgentCredentialsResponse(w http.ResponseWriter) error
+}
+
+type RevokeAgentCredentials200JSONResponse AgentCredentialRevocationObject
+
+func (response RevokeAgentCredentials200JSONResponse) VisitRevokeAgentCredentialsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAgentCredentials403JSONResponse ErrorResponse
+
+func (response RevokeAgentCredentials403JSONResponse) VisitRevokeAgentCredentialsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RevokeAgentCredentials501JSONResponse ErrorResponse
+
+func (response RevokeAgentCredentials501JSONResponse) VisitRevokeAgentCredentialsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentCredentialRequestObject struct {
+	AgentId AgentIdPathParam `json:"agent_id"`
+}
+
+type CreateAgentCredentialResponseObject interface {
+	VisitCreateAgentCredentialResponse(w http.ResponseWriter) error
+}
+
+type CreateAgentCredential201JSONResponse AgentCredentialObject
+
+func (response CreateAgentCredential201JSONResponse) VisitCreateAgentCredentialResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentCredential401JSONResponse ErrorResponse
+
+func (response CreateAgentCredential401JSONResponse) VisitCreateAgentCredentialResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentCredential403JSONResponse ErrorResponse
+
+func (response CreateAgentCredential403JSONResponse) VisitCreateAgentCredentialResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAgentCredential501JSONResponse ErrorResponse
+
+func (response CreateAgentCredential501JSONResponse) VisitCreateAgentCredentialResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAgentProbesRequestObject struct {
+	AgentId AgentIdPathParam `json:"agent_id"`
+}
+
+type ListAgentProbesResponseObject interface {
+	VisitListAgentProbesResponse(w http.ResponseWriter) error
+}
+
+type ListAgentProbes200JSONResponse ProbesArrayResponse
+
+func (response ListAgentProbes200JSONResponse) VisitListAgentProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAgentProbes401JSONResponse ErrorResponse
+
+func (response ListAgentProbes401JSONResponse) VisitListAgentProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAgentProbes403JSONResponse ErrorResponse
+
+func (response ListAgentProbes403JSONResponse) VisitListAgentProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAgentProbes404JSONResponse WarningResponse
+
+func (response ListAgentProbes404JSONResponse) VisitListAgentProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPIKeysRequestObject struct {
+}
+
+type ListAPIKeysResponseObject interface {
+	VisitListAPIKeysResponse(w http.ResponseWriter) error
+}
+
+type ListAPIKeys200JSONResponse APIKeysArrayResponse
+
+func (response ListAPIKeys200JSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPIKeys401JSONResponse ErrorResponse
+
+func (response ListAPIKeys401JSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAPIKeys501JSONResponse ErrorResponse
+
+func (response ListAPIKeys501JSONResponse) VisitListAPIKeysResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAPIKeyRequestObject struct {
+	Body *CreateAPIKeyJSONRequestBody
+}
+
+type CreateAPIKeyResponseObject interface {
+	VisitCreateAPIKeyResponse(w http.ResponseWriter) error
+}
+
+type CreateAPIKey201JSONResponse APIKeyObject
+
+func (response CreateAPIKey201JSONResponse) VisitCreateAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAPIKey400JSONResponse ErrorResponse
+
+func (response CreateAPIKey400JSONResponse) VisitCreateAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAPIKey401JSONResponse ErrorResponse
+
+func (response CreateAPIKey401JSONResponse) VisitCreateAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateAPIKey501JSONResponse ErrorResponse
+
+func (response CreateAPIKey501JSONResponse) VisitCreateAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIKeyRequestObject struct {
+	KeyId APIKeyIdPathParam `json:"key_id"`
+}
+
+type DeleteAPIKeyResponseObject interface {
+	VisitDeleteAPIKeyResponse(w http.ResponseWriter) error
+}
+
+type DeleteAPIKey204Response struct {
+}
+
+func (response DeleteAPIKey204Response) VisitDeleteAPIKeyResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteAPIKey401JSONResponse ErrorResponse
+
+func (response DeleteAPIKey401JSONResponse) VisitDeleteAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIKey404JSONResponse WarningResponse
+
+func (response DeleteAPIKey404JSONResponse) VisitDeleteAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteAPIKey501JSONResponse ErrorResponse
+
+func (response DeleteAPIKey501JSONResponse) VisitDeleteAPIKeyResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListAuditEntriesRequestObject struct {
+	Params ListAuditEntriesParams
+}
+
+type ListAuditEntriesResponseObject interface {
+	VisitListAuditEntriesResponse(w http.ResponseWriter) error
+}
+
+type ListAuditEntries200JSONResponse AuditEntriesArrayResponse
+
+func (response ListAuditEntries200JSONResponse) VisitListAuditEntriesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbeGroupsRequestObject struct {
+}
+
+type ListProbeGroupsResponseObject interface {
+	VisitListProbeGroupsResponse(w http.ResponseWriter) error
+}
+
+type ListProbeGroups200JSONResponse ProbeGroupsArrayResponse
+
+func (response ListProbeGroups200JSONResponse) VisitListProbeGroupsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeGroupRequestObject struct {
+	Body *CreateProbeGroupJSONRequestBody
+}
+
+type CreateProbeGroupResponseObject interface {
+	VisitCreateProbeGroupResponse(w http.ResponseWriter) error
+}
+
+type CreateProbeGroup201JSONResponse ProbeGroupObject
+
+func (response CreateProbeGroup201JSONResponse) VisitCreateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeGroup400JSONResponse ErrorResponse
+
+func (response CreateProbeGroup400JSONResponse) VisitCreateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeGroup403JSONResponse ErrorResponse
+
+func (response CreateProbeGroup403JSONResponse) VisitCreateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeGroup409JSONResponse ErrorResponse
+
+func (response CreateProbeGroup409JSONResponse) VisitCreateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbeGroupRequestObject struct {
+	GroupId GroupIdPathParam `json:"group_id"`
+}
+
+type DeleteProbeGroupResponseObject interface {
+	VisitDeleteProbeGroupResponse(w http.ResponseWriter) error
+}
+
+type DeleteProbeGroup204Response struct {
+}
+
+func (response DeleteProbeGroup204Response) VisitDeleteProbeGroupResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteProbeGroup404JSONResponse WarningResponse
+
+func (response DeleteProbeGroup404JSONResponse) VisitDeleteProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbeGroup409JSONResponse ErrorResponse
+
+func (response DeleteProbeGroup409JSONResponse) VisitDeleteProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeGroupRequestObject struct {
+	GroupId GroupIdPathParam `json:"group_id"`
+}
+
+type GetProbeGroupResponseObject interface {
+	VisitGetProbeGroupResponse(w http.ResponseWriter) error
+}
+
+type GetProbeGroup200JSONResponse ProbeGroupObject
+
+func (response GetProbeGroup200JSONResponse) VisitGetProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeGroup404JSONResponse WarningResponse
+
+func (response GetProbeGroup404JSONResponse) VisitGetProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeGroupRequestObject struct {
+	GroupId GroupIdPathParam `json:"group_id"`
+	Body    *UpdateProbeGroupJSONRequestBody
+}
+
+type UpdateProbeGroupResponseObject interface {
+	VisitUpdateProbeGroupResponse(w http.ResponseWriter) error
+}
+
+type UpdateProbeGroup200JSONResponse ProbeGroupObject
+
+func (response UpdateProbeGroup200JSONResponse) VisitUpdateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeGroup404JSONResponse WarningResponse
+
+func (response UpdateProbeGroup404JSONResponse) VisitUpdateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeGroup409JSONResponse ErrorResponse
+
+func (response UpdateProbeGroup409JSONResponse) VisitUpdateProbeGroupResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbeTemplatesRequestObject struct {
+}
+
+type ListProbeTemplatesResponseObject interface {
+	VisitListProbeTemplatesResponse(w http.ResponseWriter) error
+}
+
+type ListProbeTemplates200JSONResponse ProbeTemplatesArrayResponse
+
+func (response ListProbeTemplates200JSONResponse) VisitListProbeTemplatesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeTemplateRequestObject struct {
+	Body *CreateProbeTemplateJSONRequestBody
+}
+
+type CreateProbeTemplateResponseObject interface {
+	VisitCreateProbeTemplateResponse(w http.ResponseWriter) error
+}
+
+type CreateProbeTemplate201JSONResponse ProbeTemplateObject
+
+func (response CreateProbeTemplate201JSONResponse) VisitCreateProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeTemplate400JSONResponse ErrorResponse
+
+func (response CreateProbeTemplate400JSONResponse) VisitCreateProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbeTemplateRequestObject struct {
+	TemplateId TemplateIdPathParam `json:"template_id"`
+}
+
+type DeleteProbeTemplateResponseObject interface {
+	VisitDeleteProbeTemplateResponse(w http.ResponseWriter) error
+}
+
+type DeleteProbeTemplate204Response struct {
+}
+
+func (response DeleteProbeTemplate204Response) VisitDeleteProbeTemplateResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteProbeTemplate404JSONResponse WarningResponse
+
+func (response DeleteProbeTemplate404JSONResponse) VisitDeleteProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbeTemplate409JSONResponse ErrorResponse
+
+func (response DeleteProbeTemplate409JSONResponse) VisitDeleteProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeTemplateRequestObject struct {
+	TemplateId TemplateIdPathParam `json:"template_id"`
+}
+
+type GetProbeTemplateResponseObject interface {
+	VisitGetProbeTemplateResponse(w http.ResponseWriter) error
+}
+
+type GetProbeTemplate200JSONResponse ProbeTemplateObject
+
+func (response GetProbeTemplate200JSONResponse) VisitGetProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeTemplate404JSONResponse WarningResponse
+
+func (response GetProbeTemplate404JSONResponse) VisitGetProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeTemplateRequestObject struct {
+	TemplateId TemplateIdPathParam `json:"template_id"`
+	Body       *UpdateProbeTemplateJSONRequestBody
+}
+
+type UpdateProbeTemplateResponseObject interface {
+	VisitUpdateProbeTemplateResponse(w http.ResponseWriter) error
+}
+
+type UpdateProbeTemplate200JSONResponse ProbeTemplateObject
+
+func (response UpdateProbeTemplate200JSONResponse) VisitUpdateProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeTemplate400JSONResponse ErrorResponse
+
+func (response UpdateProbeTemplate400JSONResponse) VisitUpdateProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeTemplate404JSONResponse WarningResponse
+
+func (response UpdateProbeTemplate404JSONResponse) VisitUpdateProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeTemplate409JSONResponse ErrorResponse
+
+func (response UpdateProbeTemplate409JSONResponse) VisitUpdateProbeTemplateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbesRequestObject struct {
+	Params ListProbesParams
+}
+
+type ListProbesResponseObject interface {
+	VisitListProbesResponse(w http.ResponseWriter) error
+}
+
+type ListProbes200ResponseHeaders struct {
+	ETag string
+}
+
+type ListProbes200JSONResponse struct {
+	Body    ProbesArrayResponse
+	Headers ListProbes200ResponseHeaders
+}
+
+func (response ListProbes200JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListProbes304ResponseHeaders struct {
+	ETag string
+}
+
+type ListProbes304Response struct {
+	Headers ListProbes304ResponseHeaders
+}
+
+func (response ListProbes304Response) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type ListProbes400JSONResponse ErrorResponse
+
+func (response ListProbes400JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbes413JSONResponse ErrorResponse
+
+func (response ListProbes413JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(413)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeStatusesRequestObject struct {
+	Body *UpdateProbeStatusesJSONRequestBody
+}
+
+type UpdateProbeStatusesResponseObject interface {
+	VisitUpdateProbeStatusesResponse(w http.ResponseWriter) error
+}
+
+type UpdateProbeStatuses207JSONResponse ProbeStatusBatchResponse
+
+func (response UpdateProbeStatuses207JSONResponse) VisitUpdateProbeStatusesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(207)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeStatuses400JSONResponse ErrorResponse
+
+func (response UpdateProbeStatuses400JSONResponse) VisitUpdateProbeStatusesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbeRequestObject struct {
+	Params CreateProbeParams
+	Body   *CreateProbeJSONRequestBody
+}
+
+type CreateProbeResponseObject interface {
+	VisitCreateProbeResponse(w http.ResponseWriter) error
+}
+
+type CreateProbe201JSONResponse ProbeObject
+
+func (response CreateProbe201JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbe400JSONResponse ErrorResponse
+
+func (response CreateProbe400JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbe403JSONResponse ErrorResponse
+
+func (response CreateProbe403JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbe409JSONResponse ErrorResponse
+
+func (response CreateProbe409JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbe422JSONResponse ErrorResponse
+
+func (response CreateProbe422JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateProbe500JSONResponse ErrorResponse
+
+func (response CreateProbe500JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(500)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DiffProbesRequestObject struct {
+	Params DiffProbesParams
+}
+
+type DiffProbesResponseObject interface {
+	VisitDiffProbesResponse(w http.ResponseWriter) error
+}
+
+type DiffProbes200JSONResponse ProbeDiffResponse
+
+func (response DiffProbes200JSONResponse) VisitDiffProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DiffProbes400JSONResponse ErrorResponse
+
+func (response DiffProbes400JSONResponse) VisitDiffProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DiffProbes409JSONResponse ErrorResponse
+
+func (response DiffProbes409JSONResponse) VisitDiffProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportProbesRequestObject struct {
+	Params ExportProbesParams
+}
+
+type ExportProbesResponseObject interface {
+	VisitExportProbesResponse(w http.ResponseWriter) error
+}
+
+type ExportProbes200JSONResponse ProbeBundle
+
+func (response ExportProbes200JSONResponse) VisitExportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ExportProbes200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ExportProbes200ApplicationxNdjsonResponse) VisitExportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportProbes200ApplicationyamlResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response ExportProbes200ApplicationyamlResponse) VisitExportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/yaml")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportProbes400JSONResponse ErrorResponse
+
+func (response ExportProbes400JSONResponse) VisitExportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportProbesRequestObject struct {
+	Params   ImportProbesParams
+	JSONBody *ImportProbesJSONRequestBody
+	Body     io.Reader
+}
+
+type ImportProbesResponseObject interface {
+	VisitImportProbesResponse(w http.ResponseWriter) error
+}
+
+type ImportProbes200JSONResponse ProbeImportResponse
+
+func (response ImportProbes200JSONResponse) VisitImportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportProbes207JSONResponse ProbeImportResponse
+
+func (response ImportProbes207JSONResponse) VisitImportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(207)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportProbes400JSONResponse ErrorResponse
+
+func (response ImportProbes400JSONResponse) VisitImportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportProbes409JSONResponse ErrorResponse
+
+func (response ImportProbes409JSONResponse) VisitImportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ImportProbes422JSONResponse ProbeImportResponse
+
+func (response ImportProbes422JSONResponse) VisitImportProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbeProblemsRequestObject struct {
+	Params ListProbeProblemsParams
+}
+
+type ListProbeProblemsResponseObject interface {
+	VisitListProbeProblemsResponse(w http.ResponseWriter) error
+}
+
+type ListProbeProblems200JSONResponse ProbeProblemsArrayResponse
+
+func (response ListProbeProblems200JSONResponse) VisitListProbeProblemsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbeProblems400JSONResponse ErrorResponse
+
+func (response ListProbeProblems400JSONResponse) VisitListProbeProblemsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchProbesRequestObject struct {
+	Params SearchProbesParams
+}
+
+type SearchProbesResponseObject interface {
+	VisitSearchProbesResponse(w http.ResponseWriter) error
+}
+
+type SearchProbes200JSONResponse ProbesArrayResponse
+
+func (response SearchProbes200JSONResponse) VisitSearchProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchProbes400JSONResponse ErrorResponse
+
+func (response SearchProbes400JSONResponse) VisitSearchProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SearchProbes413JSONResponse ErrorResponse
+
+func (response SearchProbes413JSONResponse) VisitSearchProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(413)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbeRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+	Params  DeleteProbeParams
+}
+
+type DeleteProbeResponseObject interface {
+	VisitDeleteProbeResponse(w http.ResponseWriter) error
+}
+
+type DeleteProbe204Response struct {
+}
+
+func (response DeleteProbe204Response) VisitDeleteProbeResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteProbe404JSONResponse WarningResponse
+
+func (response DeleteProbe404JSONResponse) VisitDeleteProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbe409JSONResponse ErrorResponse
+
+func (response DeleteProbe409JSONResponse) VisitDeleteProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteProbe412JSONResponse ErrorResponse
+
+func (response DeleteProbe412JSONResponse) VisitDeleteProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeByIdRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+}
+
+type GetProbeByIdResponseObject interface {
+	VisitGetProbeByIdResponse(w http.ResponseWriter) error
+}
+
+type GetProbeById200ResponseHeaders struct {
+	ETag string
+}
+
+type GetProbeById200JSONResponse struct {
+	Body    ProbeObject
+	Headers GetProbeById200ResponseHeaders
+}
+
+func (response GetProbeById200JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetProbeById404JSONResponse WarningResponse
+
+func (response GetProbeById404JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeById410JSONResponse ProbeTombstoneResponse
+
+func (response GetProbeById410JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(410)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbeRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+	Params  UpdateProbeParams
+	Body    *UpdateProbeJSONRequestBody
+}
+
+type UpdateProbeResponseObject interface {
+	VisitUpdateProbeResponse(w http.ResponseWriter) error
+}
+
+type UpdateProbe200ResponseHeaders struct {
+	ETag string
+}
+
+type UpdateProbe200JSONResponse struct {
+	Body    ProbeObject
+	Headers UpdateProbe200ResponseHeaders
+}
+
+func (response UpdateProbe200JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateProbe400JSONResponse ErrorResponse
+
+func (response UpdateProbe400JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbe403JSONResponse ErrorResponse
+
+func (response UpdateProbe403JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbe404JSONResponse WarningResponse
+
+func (response UpdateProbe404JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbe409JSONResponse ErrorResponse
+
+func (response UpdateProbe409JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProbe412JSONResponse ErrorResponse
+
+func (response UpdateProbe412JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeAuthRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+}
+
+type GetProbeAuthResponseObject interface {
+	VisitGetProbeAuthResponse(w http.ResponseWriter) error
+}
+
+type GetProbeAuth200JSONResponse ProbeAuthSchema
+
+func (response GetProbeAuth200JSONResponse) VisitGetProbeAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeAuth403JSONResponse ErrorResponse
+
+func (response GetProbeAuth403JSONResponse) VisitGetProbeAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeAuth404JSONResponse WarningResponse
+
+func (response GetProbeAuth404JSONResponse) VisitGetProbeAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeAuth501JSONResponse ErrorResponse
+
+func (response GetProbeAuth501JSONResponse) VisitGetProbeAuthResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(501)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeHistoryRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+}
+
+type GetProbeHistoryResponseObject interface {
+	VisitGetProbeHistoryResponse(w http.ResponseWriter) error
+}
+
+type GetProbeHistory200JSONResponse ProbeHistoryResponse
+
+func (response GetProbeHistory200JSONResponse) VisitGetProbeHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeHistory404JSONResponse WarningResponse
+
+func (response GetProbeHistory404JSONResponse) VisitGetProbeHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PauseProbeRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+	Params  PauseProbeParams
+}
+
+type PauseProbeResponseObject interface {
+	VisitPauseProbeResponse(w http.ResponseWriter) error
+}
+
+type PauseProbe200ResponseHeaders struct {
+	ETag string
+}
+
+type PauseProbe200JSONResponse struct {
+	Body    ProbeObject
+	Headers PauseProbe200ResponseHeaders
+}
+
+func (response PauseProbe200JSONResponse) VisitPauseProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type PauseProbe400JSONResponse ErrorResponse
+
+func (response PauseProbe400JSONResponse) VisitPauseProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PauseProbe404JSONResponse WarningResponse
+
+func (response PauseProbe404JSONResponse) VisitPauseProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PauseProbe409JSONResponse ErrorResponse
+
+func (response PauseProbe409JSONResponse) VisitPauseProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PauseProbe412JSONResponse ErrorResponse
+
+func (response PauseProbe412JSONResponse) VisitPauseProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbeResultsRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+}
+
+type ListProbeResultsResponseObject interface {
+	VisitListProbeResultsResponse(w http.ResponseWriter) error
+}
+
+type ListProbeResults200JSONResponse ProbeResultsArrayResponse
+
+func (response ListProbeResults200JSONResponse) VisitListProbeResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbeResults404JSONResponse WarningResponse
+
+func (response ListProbeResults404JSONResponse) VisitListProbeResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReportProbeResultRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+	Body    *ReportProbeResultJSONRequestBody
+}
+
+type ReportProbeResultResponseObject interface {
+	VisitReportProbeResultResponse(w http.ResponseWriter) error
+}
+
+type ReportProbeResult201JSONResponse ProbeResultObject
+
+func (response ReportProbeResult201JSONResponse) VisitReportProbeResultResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReportProbeResult400JSONResponse ErrorResponse
+
+func (response ReportProbeResult400JSONResponse) VisitReportProbeResultResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ReportProbeResult404JSONResponse WarningResponse
+
+func (response ReportProbeResult404JSONResponse) VisitReportProbeResultResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SummarizeProbeResultsRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+	Params  SummarizeProbeResultsParams
+}
+
+type SummarizeProbeResultsResponseObject interface {
+	VisitSummarizeProbeResultsResponse(w http.ResponseWriter) error
+}
+
+type SummarizeProbeResults200JSONResponse ProbeResultSummary
+
+func (response SummarizeProbeResults200JSONResponse) VisitSummarizeProbeResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SummarizeProbeResults400JSONResponse ErrorResponse
+
+func (response SummarizeProbeResults400JSONResponse) VisitSummarizeProbeResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type SummarizeProbeResults404JSONResponse WarningResponse
+
+func (response SummarizeProbeResults404JSONResponse) VisitSummarizeProbeResultsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResumeProbeRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+	Params  ResumeProbeParams
+}
+
+type ResumeProbeResponseObject interface {
+	VisitResumeProbeResponse(w http.ResponseWriter) error
+}
+
+type ResumeProbe200ResponseHeaders struct {
+	ETag string
+}
+
+type ResumeProbe200JSONResponse struct {
+	Body    ProbeObject
+	Headers ResumeProbe200ResponseHeaders
+}
+
+func (response ResumeProbe200JSONResponse) VisitResumeProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ResumeProbe400JSONResponse ErrorResponse
+
+func (response ResumeProbe400JSONResponse) VisitResumeProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResumeProbe404JSONResponse WarningResponse
+
+func (response ResumeProbe404JSONResponse) VisitResumeProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResumeProbe409JSONResponse ErrorResponse
+
+func (response ResumeProbe409JSONResponse) VisitResumeProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ResumeProbe412JSONResponse ErrorResponse
+
+func (response ResumeProbe412JSONResponse) VisitResumeProbeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListWebhooksRequestObject struct {
+}
+
+type ListWebhooksResponseObject interface {
+	VisitListWebhooksResponse(w http.ResponseWriter) error
+}
+
+type ListWebhooks200JSONResponse WebhooksArrayResponse
+
+func (response ListWebhooks200JSONResponse) VisitListWebhooksResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateWebhookRequestObject struct {
+	Body *CreateWebhookJSONRequestBody
+}
+
+type CreateWebhookResponseObject interface {
+	VisitCreateWebhookResponse(w http.ResponseWriter) error
+}
+
+type CreateWebhook201JSONResponse WebhookObject
+
+func (response CreateWebhook201JSONResponse) VisitCreateWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CreateWebhook400JSONResponse ErrorResponse
+
+func (response CreateWebhook400JSONResponse) VisitCreateWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type DeleteWebhookRequestObject struct {
+	WebhookId WebhookIdPathParam `json:"webhook_id"`
+}
+
+type DeleteWebhookResponseObject interface {
+	VisitDeleteWebhookResponse(w http.ResponseWriter) error
+}
+
+type DeleteWebhook204Response struct {
+}
+
+func (response DeleteWebhook204Response) VisitDeleteWebhookResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type DeleteWebhook404JSONResponse WarningResponse
+
+func (response DeleteWebhook404JSONResponse) VisitDeleteWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWebhookRequestObject struct {
+	WebhookId WebhookIdPathParam `json:"webhook_id"`
+}
+
+type GetWebhookResponseObject interface {
+	VisitGetWebhookResponse(w http.ResponseWriter) error
+}
+
+type GetWebhook200JSONResponse WebhookObject
+
+func (response GetWebhook200JSONResponse) VisitGetWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetWebhook404JSONResponse WarningResponse
+
+func (response GetWebhook404JSONResponse) VisitGetWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateWebhookRequestObject struct {
+	WebhookId WebhookIdPathParam `json:"webhook_id"`
+	Body      *UpdateWebhookJSONRequestBody
+}
+
+type UpdateWebhookResponseObject interface {
+	VisitUpdateWebhookResponse(w http.ResponseWriter) error
+}
+
+type UpdateWebhook200JSONResponse WebhookObject
+
+func (response UpdateWebhook200JSONResponse) VisitUpdateWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateWebhook400JSONResponse ErrorResponse
+
+func (response UpdateWebhook400JSONResponse) VisitUpdateWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateWebhook404JSONResponse WarningResponse
+
+func (response UpdateWebhook404JSONResponse) VisitUpdateWebhookResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Mint a bootstrap token for agents to register with
+	// (POST /agent-bootstrap-tokens)
+	CreateAgentBootstrapToken(ctx context.Context, request CreateAgentBootstrapTokenRequestObject) (CreateAgentBootstrapTokenResponseObject, error)
+	// Register an agent or refresh its heartbeat
+	// (PUT /agents/{agent_id})
+	RegisterAgent(ctx context.Context, request RegisterAgentRequestObject) (RegisterAgentResponseObject, error)
+	// Revoke the credentials of an agent
+	// (DELETE /agents/{agent_id}/credentials)
+	RevokeAgentCredentials(ctx context.Context, request RevokeAgentCredentialsRequestObject) (RevokeAgentCredentialsResponseObject, error)
+	// Exchange a bootstrap token for an agent credential, or renew one
+	// (POST /agents/{agent_id}/credentials)
+	CreateAgentCredential(ctx context.Context, request CreateAgentCredentialRequestObject) (CreateAgentCredentialResponseObject, error)
+	// Get the probes assigned to an agent
+	// (GET /agents/{agent_id}/probes)
+	ListAgentProbes(ctx context.Context, request ListAgentProbesRequestObject) (ListAgentProbesResponseObject, error)
+	// Get the API keys
+	// (GET /apikeys)
+	ListAPIKeys(ctx context.Context, request ListAPIKeysRequestObject) (ListAPIKeysResponseObject, error)
+	// Mint an API key
+	// (POST /apikeys)
+	CreateAPIKey(ctx context.Context, request CreateAPIKeyRequestObject) (CreateAPIKeyResponseObject, error)
+	// Revoke an API key
+	// (DELETE /apikeys/{key_id})
+	DeleteAPIKey(ctx context.Context, request DeleteAPIKeyRequestObject) (DeleteAPIKeyResponseObject, error)
+	// Get the recorded changes to probes
+	// (GET /audit)
+	ListAuditEntries(ctx context.Context, request ListAuditEntriesRequestObject) (ListAuditEntriesResponseObject, error)
+	// Get the probe groups
+	// (GET /probe-groups)
+	ListProbeGroups(ctx context.Context, request ListProbeGroupsRequestObject) (ListProbeGroupsResponseObject, error)
+	// Create a probe group
+	// (POST /probe-groups)
+	CreateProbeGroup(ctx context.Context, request CreateProbeGroupRequestObject) (CreateProbeGroupResponseObject, error)
+	// Delete a probe group
+	// (DELETE /probe-groups/{group_id})
+	DeleteProbeGroup(ctx context.Context, request DeleteProbeGroupRequestObject) (DeleteProbeGroupResponseObject, error)
+	// Get a probe group by its ID
+	// (GET /probe-groups/{group_id})
+	GetProbeGroup(ctx context.Context, request GetProbeGroupRequestObject) (GetProbeGroupResponseObject, error)
+	// Pause or resume a probe group
+	// (PATCH /probe-groups/{group_id})
+	UpdateProbeGroup(ctx context.Context, request UpdateProbeGroupRequestObject) (UpdateProbeGroupResponseObject, error)
+	// Get the probe templates
+	// (GET /probe-templates)
+	ListProbeTemplates(ctx context.Context, request ListProbeTemplatesRequestObject) (ListProbeTemplatesResponseObject, error)
+	// Create a probe template
+	// (POST /probe-templates)
+	CreateProbeTemplate(ctx context.Context, request CreateProbeTemplateRequestObject) (CreateProbeTemplateResponseObject, error)
+	// Delete a probe template
+	// (DELETE /probe-templates/{template_id})
+	DeleteProbeTemplate(ctx context.Context, request DeleteProbeTemplateRequestObject) (DeleteProbeTemplateResponseObject, error)
+	// Get a probe template by its ID
+	// (GET /probe-templates/{template_id})
+	GetProbeTemplate(ctx context.Context, request GetProbeTemplateRequestObject) (GetProbeTemplateResponseObject, error)
+	// Replace a probe template
+	// (PUT /probe-templates/{template_id})
+	UpdateProbeTemplate(ctx context.Context, request UpdateProbeTemplateRequestObject) (UpdateProbeTemplateResponseObject, error)
+	// Get a list of all configured probes
+	// (GET /probes)
+	ListProbes(ctx context.Context, request ListProbesRequestObject) (ListProbesResponseObject, error)
+	// Update the statuses of several probes
+	// (PATCH /probes)
+	UpdateProbeStatuses(ctx context.Context, request UpdateProbeStatusesRequestObject) (UpdateProbeStatusesResponseObject, error)
+	// Creates a new probe
+	// (POST /probes)
+	CreateProbe(ctx context.Context, request CreateProbeRequestObject) (CreateProbeResponseObject, error)
+	// Compare the probes matched by two label selectors
+	// (GET /probes/diff)
+	DiffProbes(ctx context.Context, request DiffProbesRequestObject) (DiffProbesResponseObject, error)
+	// Export probes as a bundle
+	// (GET /probes/export)
+	ExportProbes(ctx context.Context, request ExportProbesRequestObject) (ExportProbesResponseObject, error)
+	// Import probes from a bundle
+	// (POST /probes/import)
+	ImportProbes(ctx context.Context, request ImportProbesRequestObject) (ImportProbesResponseObject, error)
+	// Get the probes in an anomalous state
+	// (GET /probes/problems)
+	ListProbeProblems(ctx context.Context, request ListProbeProblemsRequestObject) (ListProbeProblemsResponseObject, error)
+	// Search probes by URL and label values
+	// (GET /probes/search)
+	SearchProbes(ctx context.Context, request SearchProbesRequestObject) (SearchProbesResponseObject, error)
+	// Deletes a probe matching provided ID
+	// (DELETE /probes/{probe_id})
+	DeleteProbe(ctx context.Context, request DeleteProbeRequestObject) (DeleteProbeResponseObject, error)
+	// Get a probe by its ID
+	// (GET /probes/{probe_id})
+	GetProbeById(ctx context.Context, request GetProbeByIdRequestObject) (GetProbeByIdResponseObject, error)
+	// Updates a probe by its ID
+	// (PATCH /probes/{probe_id})
+	UpdateProbe(ctx context.Context, request UpdateProbeRequestObject) (UpdateProbeResponseObject, error)
+	// Get the credentials of a probe
+	// (GET /probes/{probe_id}/auth)
+	GetProbeAuth(ctx context.Context, request GetProbeAuthRequestObject) (GetProbeAuthResponseObject, error)
+	// Get the status history of a probe
+	// (GET /probes/{probe_id}/history)
+	GetProbeHistory(ctx context.Context, request GetProbeHistoryRequestObject) (GetProbeHistoryResponseObject, error)
+	// Pause a probe
+	// (POST /probes/{probe_id}/pause)
+	PauseProbe(ctx context.Context, request PauseProbeRequestObject) (PauseProbeResponseObject, error)
+	// Get the most recent results reported for a probe
+	// (GET /probes/{probe_id}/results)
+	ListProbeResults(ctx context.Context, request ListProbeResultsRequestObject) (ListProbeResultsResponseObject, error)
+	// Report the outcome of a probe run
+	// (POST /probes/{probe_id}/results)
+	ReportProbeResult(ctx context.Context, request ReportProbeResultRequestObject) (ReportProbeResultResponseObject, error)
+	// Summarize the results reported for a probe
+	// (GET /probes/{probe_id}/results/summary)
+	SummarizeProbeResults(ctx context.Context, request SummarizeProbeResultsRequestObject) (SummarizeProbeResultsResponseObject, error)
+	// Resume a paused probe
+	// (POST /probes/{probe_id}/resume)
+	ResumeProbe(ctx context.Context, request ResumeProbeRequestObject) (ResumeProbeResponseObject, error)
+	// Get the webhook subscriptions
+	// (GET /webhooks)
+	ListWebhooks(ctx context.Context, request ListWebhooksRequestObject) (ListWebhooksResponseObject, error)
+	// Subscribe a webhook to probe lifecycle events
+	// (POST /webhooks)
+	CreateWebhook(ctx context.Context, request CreateWebhookRequestObject) (CreateWebhookResponseObject, error)
+	// Unsubscribe a webhook
+	// (DELETE /webhooks/{webhook_id})
+	DeleteWebhook(ctx context.Context, request DeleteWebhookRequestObject) (DeleteWebhookResponseObject, error)
+	// Get a webhook subscription by its ID
+	// (GET /webhooks/{webhook_id})
+	GetWebhook(ctx context.Context, request GetWebhookRequestObject) (GetWebhookResponseObject, error)
+	// Replace a webhook subscription
+	// (PUT /webhooks/{webhook_id})
+	UpdateWebhook(ctx context.Context, request UpdateWebhookRequestObject) (UpdateWebhookResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// CreateAgentBootstrapToken operation middleware
+func (sh *strictHandler) CreateAgentBootstrapToken(w http.ResponseWriter, r *http.Request) {
+	var request CreateAgentBootstrapTokenRequestObject
+
+	var body CreateAgentBootstrapTokenJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateAgentBootstrapToken(ctx, request.(CreateAgentBootstrapTokenRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateAgentBootstrapToken")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateAgentBootstrapTokenResponseObject); ok {
+		if err := validResponse.VisitCreateAgentBootstrapTokenResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RegisterAgent operation middleware
+func (sh *strictHandler) RegisterAgent(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam) {
+	var request RegisterAgentRequestObject
+
+	request.AgentId = agentId
+
+	var body RegisterAgentJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RegisterAgent(ctx, request.(RegisterAgentRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RegisterAgent")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RegisterAgentResponseObject); ok {
+		if err := validResponse.VisitRegisterAgentResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// RevokeAgentCredentials operation middleware
+func (sh *strictHandler) RevokeAgentCredentials(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam) {
+	var request RevokeAgentCredentialsRequestObject
+
+	request.AgentId = agentId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RevokeAgentCredentials(ctx, request.(RevokeAgentCredentialsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RevokeAgentCredentials")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RevokeAgentCredentialsResponseObject); ok {
+		if err := validResponse.VisitRevokeAgentCredentialsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateAgentCredential operation middleware
+func (sh *strictHandler) CreateAgentCredential(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam) {
+	var request CreateAgentCredentialRequestObject
+
+	request.AgentId = agentId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateAgentCredential(ctx, request.(CreateAgentCredentialRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateAgentCredential")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateAgentCredentialResponseObject); ok {
+		if err := validResponse.VisitCreateAgentCredentialResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListAgentProbes operation middleware
+func (sh *strictHandler) ListAgentProbes(w http.ResponseWriter, r *http.Request, agentId AgentIdPathParam) {
+	var request ListAgentProbesRequestObject
+
+	request.AgentId = agentId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAgentProbes(ctx, request.(ListAgentProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAgentProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAgentProbesResponseObject); ok {
+		if err := validResponse.VisitListAgentProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListAPIKeys operation middleware
+func (sh *strictHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	var request ListAPIKeysRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAPIKeys(ctx, request.(ListAPIKeysRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAPIKeys")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAPIKeysResponseObject); ok {
+		if err := validResponse.VisitListAPIKeysResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateAPIKey operation middleware
+func (sh *strictHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var request CreateAPIKeyRequestObject
+
+	var body CreateAPIKeyJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateAPIKey(ctx, request.(CreateAPIKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateAPIKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateAPIKeyResponseObject); ok {
+		if err := validResponse.VisitCreateAPIKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteAPIKey operation middleware
+func (sh *strictHandler) DeleteAPIKey(w http.ResponseWriter, r *http.Request, keyId APIKeyIdPathParam) {
+	var request DeleteAPIKeyRequestObject
+
+	request.KeyId = keyId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteAPIKey(ctx, request.(DeleteAPIKeyRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteAPIKey")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteAPIKeyResponseObject); ok {
+		if err := validResponse.VisitDeleteAPIKeyResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListAuditEntries operation middleware
+func (sh *strictHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request, params ListAuditEntriesParams) {
+	var request ListAuditEntriesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListAuditEntries(ctx, request.(ListAuditEntriesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListAuditEntries")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListAuditEntriesResponseObject); ok {
+		if err := validResponse.VisitListAuditEntriesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListProbeGroups operation middleware
+func (sh *strictHandler) ListProbeGroups(w http.ResponseWriter, r *http.Request) {
+	var request ListProbeGroupsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListProbeGroups(ctx, request.(ListProbeGroupsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListProbeGroups")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListProbeGroupsResponseObject); ok {
+		if err := validResponse.VisitListProbeGroupsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateProbeGroup operation middleware
+func (sh *strictHandler) CreateProbeGroup(w http.ResponseWriter, r *http.Request) {
+	var request CreateProbeGroupRequestObject
+
+	var body CreateProbeGroupJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateProbeGroup(ctx, request.(CreateProbeGroupRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateProbeGroup")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateProbeGroupResponseObject); ok {
+		if err := validResponse.VisitCreateProbeGroupResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteProbeGroup operation middleware
+func (sh *strictHandler) DeleteProbeGroup(w http.ResponseWriter, r *http.Request, groupId GroupIdPathParam) {
+	var request DeleteProbeGroupRequestObject
+
+	request.GroupId = groupId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteProbeGroup(ctx, request.(DeleteProbeGroupRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteProbeGroup")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteProbeGroupResponseObject); ok {
+		if err := validResponse.VisitDeleteProbeGroupResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetProbeGroup operation middleware
+func (sh *strictHandler) GetProbeGroup(w http.ResponseWriter, r *http.Request, groupId GroupIdPathParam) {
+	var request GetProbeGroupRequestObject
+
+	request.GroupId = groupId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProbeGroup(ctx, request.(GetProbeGroupRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProbeGroup")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProbeGroupResponseObject); ok {
+		if err := validResponse.VisitGetProbeGroupResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateProbeGroup operation middleware
+func (sh *strictHandler) UpdateProbeGroup(w http.ResponseWriter, r *http.Request, groupId GroupIdPathParam) {
+	var request UpdateProbeGroupRequestObject
+
+	request.GroupId = groupId
+
+	var body UpdateProbeGroupJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateProbeGroup(ctx, request.(UpdateProbeGroupRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateProbeGroup")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateProbeGroupResponseObject); ok {
+		if err := validResponse.VisitUpdateProbeGroupResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListProbeTemplates operation middleware
+func (sh *strictHandler) ListProbeTemplates(w http.ResponseWriter, r *http.Request) {
+	var request ListProbeTemplatesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListProbeTemplates(ctx, request.(ListProbeTemplatesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListProbeTemplates")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListProbeTemplatesResponseObject); ok {
+		if err := validResponse.VisitListProbeTemplatesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateProbeTemplate operation middleware
+func (sh *strictHandler) CreateProbeTemplate(w http.ResponseWriter, r *http.Request) {
+	var request CreateProbeTemplateRequestObject
+
+	var body CreateProbeTemplateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateProbeTemplate(ctx, request.(CreateProbeTemplateRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateProbeTemplate")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateProbeTemplateResponseObject); ok {
+		if err := validResponse.VisitCreateProbeTemplateResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteProbeTemplate operation middleware
+func (sh *strictHandler) DeleteProbeTemplate(w http.ResponseWriter, r *http.Request, templateId TemplateIdPathParam) {
+	var request DeleteProbeTemplateRequestObject
+
+	request.TemplateId = templateId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteProbeTemplate(ctx, request.(DeleteProbeTemplateRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteProbeTemplate")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteProbeTemplateResponseObject); ok {
+		if err := validResponse.VisitDeleteProbeTemplateResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetProbeTemplate operation middleware
+func (sh *strictHandler) GetProbeTemplate(w http.ResponseWriter, r *http.Request, templateId TemplateIdPathParam) {
+	var request GetProbeTemplateRequestObject
+
+	request.TemplateId = templateId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProbeTemplate(ctx, request.(GetProbeTemplateRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProbeTemplate")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProbeTemplateResponseObject); ok {
+		if err := validResponse.VisitGetProbeTemplateResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateProbeTemplate operation middleware
+func (sh *strictHandler) UpdateProbeTemplate(w http.ResponseWriter, r *http.Request, templateId TemplateIdPathParam) {
+	var request UpdateProbeTemplateRequestObject
+
+	request.TemplateId = templateId
+
+	var body UpdateProbeTemplateJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateProbeTemplate(ctx, request.(UpdateProbeTemplateRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateProbeTemplate")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateProbeTemplateResponseObject); ok {
+		if err := validResponse.VisitUpdateProbeTemplateResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListProbes operation middleware
+func (sh *strictHandler) ListProbes(w http.ResponseWriter, r *http.Request, params ListProbesParams) {
+	var request ListProbesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListProbes(ctx, request.(ListProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListProbesResponseObject); ok {
+		if err := validResponse.VisitListProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateProbeStatuses operation middleware
+func (sh *strictHandler) UpdateProbeStatuses(w http.ResponseWriter, r *http.Request) {
+	var request UpdateProbeStatusesRequestObject
+
+	var body UpdateProbeStatusesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateProbeStatuses(ctx, request.(UpdateProbeStatusesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateProbeStatuses")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateProbeStatusesResponseObject); ok {
+		if err := validResponse.VisitUpdateProbeStatusesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CreateProbe operation middleware
+func (sh *strictHandler) CreateProbe(w http.ResponseWriter, r *http.Request, params CreateProbeParams) {
+	var request CreateProbeRequestObject
+
+	request.Params = params
+
+	var body CreateProbeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CreateProbe(ctx, request.(CreateProbeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CreateProbe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CreateProbeResponseObject); ok {
+		if err := validResponse.VisitCreateProbeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DiffProbes operation middleware
+func (sh *strictHandler) DiffProbes(w http.ResponseWriter, r *http.Request, params DiffProbesParams) {
+	var request DiffProbesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DiffProbes(ctx, request.(DiffProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DiffProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DiffProbesResponseObject); ok {
+		if err := validResponse.VisitDiffProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportProbes operation middleware
+func (sh *strictHandler) ExportProbes(w http.ResponseWriter, r *http.Request, params ExportProbesParams) {
+	var request ExportProbesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportProbes(ctx, request.(ExportProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportProbesResponseObject); ok {
+		if err := validResponse.VisitExportProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ImportProbes operation middleware
+func (sh *strictHandler) ImportProbes(w http.ResponseWriter, r *http.Request, params ImportProbesParams) {
+	var request ImportProbesRequestObject
+
+	request.Params = params
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+
+		var body ImportProbesJSONRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+			return
+		}
+		request.JSONBody = &body
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/yaml") {
+		request.Body = r.Body
+	}
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ImportProbes(ctx, request.(ImportProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ImportProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ImportProbesResponseObject); ok {
+		if err := validResponse.VisitImportProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ListProbeProblems operation middleware
+func (sh *strictHandler) ListProbeProblems(w http.ResponseWriter, r *http.Request, params ListProbeProblemsParams) {
+	var request ListProbeProblemsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListProbeProblems(ctx, request.(ListProbeProblemsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListProbeProblems")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListProbeProblemsResponseObject); ok {
+		if err := validResponse.VisitListProbeProblemsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// SearchProbes operation middleware
+func (sh *strictHandler) SearchProbes(w http.ResponseWriter, r *http.Request, params SearchProbesParams) {
+	var request SearchProbesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SearchProbes(ctx, request.(SearchProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SearchProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SearchProbesResponseObject); ok {
+		if err := validResponse.VisitSearchProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// DeleteProbe operation middleware
+func (sh *strictHandler) DeleteProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params DeleteProbeParams) {
+	var request DeleteProbeRequestObject
+
+	request.ProbeId = probeId
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.DeleteProbe(ctx, request.(DeleteProbeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "DeleteProbe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(DeleteProbeResponseObject); ok {
+		if err := validResponse.VisitDeleteProbeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetProbeById operation middleware
+func (sh *strictHandler) GetProbeById(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
+	var request GetProbeByIdRequestObject
+
+	request.ProbeId = probeId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProbeById(ctx, request.(GetProbeByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProbeById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProbeByIdResponseObject); ok {
+		if err := validResponse.VisitGetProbeByIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// UpdateProbe operation middleware
+func (sh *strictHandler) UpdateProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params UpdateProbeParams) {
+	var request UpdateProbeRequestObject
+
+	request.ProbeId = probeId
+	request.Params = params
+
+	var body UpdateProbeJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpdateProbe(ctx, request.(UpdateProbeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpdateProbe")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpdateProbeResponseObject); ok {
+		if err := validResponse.VisitUpdateProbeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type CreateProbe500JSONResponse ErrorResponse
+// GetProbeAuth operation middleware
+func (sh *strictHandler) GetProbeAuth(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
+	var request GetProbeAuthRequestObject
 
-func (response CreateProbe500JSONResponse) VisitCreateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(500)
+	request.ProbeId = probeId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProbeAuth(ctx, request.(GetProbeAuthRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProbeAuth")
+	}
 
-type DeleteProbeRequestObject struct {
-	ProbeId ProbeIdPathParam `json:"probe_id"`
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type DeleteProbeResponseObject interface {
-	VisitDeleteProbeResponse(w http.ResponseWriter) error
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProbeAuthResponseObject); ok {
+		if err := validResponse.VisitGetProbeAuthResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type DeleteProbe204Response struct {
-}
+// GetProbeHistory operation middleware
+func (sh *strictHandler) GetProbeHistory(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
+	var request GetProbeHistoryRequestObject
 
-func (response DeleteProbe204Response) VisitDeleteProbeResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
-}
+	request.ProbeId = probeId
 
-type DeleteProbe404JSONResponse WarningResponse
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProbeHistory(ctx, request.(GetProbeHistoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProbeHistory")
+	}
 
-func (response DeleteProbe404JSONResponse) VisitDeleteProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProbeHistoryResponseObject); ok {
+		if err := validResponse.VisitGetProbeHistoryResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetProbeByIdRequestObject struct {
-	ProbeId ProbeIdPathParam `json:"probe_id"`
-}
+// PauseProbe operation middleware
+func (sh *strictHandler) PauseProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params PauseProbeParams) {
+	var request PauseProbeRequestObject
 
-type GetProbeByIdResponseObject interface {
-	VisitGetProbeByIdResponse(w http.ResponseWriter) error
-}
+	request.ProbeId = probeId
+	request.Params = params
 
-type GetProbeById200JSONResponse ProbeObject
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.PauseProbe(ctx, request.(PauseProbeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PauseProbe")
+	}
 
-func (response GetProbeById200JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(PauseProbeResponseObject); ok {
+		if err := validResponse.VisitPauseProbeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type GetProbeById404JSONResponse WarningResponse
+// ListProbeResults operation middleware
+func (sh *strictHandler) ListProbeResults(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
+	var request ListProbeResultsRequestObject
 
-func (response GetProbeById404JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	request.ProbeId = probeId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListProbeResults(ctx, request.(ListProbeResultsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListProbeResults")
+	}
 
-type UpdateProbeRequestObject struct {
-	ProbeId ProbeIdPathParam `json:"probe_id"`
-	Body    *UpdateProbeJSONRequestBody
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type UpdateProbeResponseObject interface {
-	VisitUpdateProbeResponse(w http.ResponseWriter) error
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListProbeResultsResponseObject); ok {
+		if err := validResponse.VisitListProbeResultsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type UpdateProbe200JSONResponse ProbeObject
+// ReportProbeResult operation middleware
+func (sh *strictHandler) ReportProbeResult(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
+	var request ReportProbeResultRequestObject
 
-func (response UpdateProbe200JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
+	request.ProbeId = probeId
 
-	return json.NewEncoder(w).Encode(response)
-}
+	var body ReportProbeResultJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
 
-type UpdateProbe400JSONResponse ErrorResponse
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ReportProbeResult(ctx, request.(ReportProbeResultRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ReportProbeResult")
+	}
 
-func (response UpdateProbe400JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(400)
+	response, err := handler(r.Context(), w, r, request)
 
-	return json.NewEncoder(w).Encode(response)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ReportProbeResultResponseObject); ok {
+		if err := validResponse.VisitReportProbeResultResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type UpdateProbe403JSONResponse ErrorResponse
-
-func (response UpdateProbe403JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(403)
-
-	return json.NewEncoder(w).Encode(response)
-}
+// SummarizeProbeResults operation middleware
+func (sh *strictHandler) SummarizeProbeResults(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params SummarizeProbeResultsParams) {
+	var request SummarizeProbeResultsRequestObject
 
-type UpdateProbe404JSONResponse WarningResponse
+	request.ProbeId = probeId
+	request.Params = params
 
-func (response UpdateProbe404JSONResponse) VisitUpdateProbeResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(404)
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.SummarizeProbeResults(ctx, request.(SummarizeProbeResultsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "SummarizeProbeResults")
+	}
 
-	return json.NewEncoder(w).Encode(response)
-}
+	response, err := handler(r.Context(), w, r, request)
 
-// StrictServerInterface represents all server handlers.
-type StrictServerInterface interface {
-	// Get a list of all configured probes
-	// (GET /probes)
-	ListProbes(ctx context.Context, request ListProbesRequestObject) (ListProbesResponseObject, error)
-	// Creates a new probe
-	// (POST /probes)
-	CreateProbe(ctx context.Context, request CreateProbeRequestObject) (CreateProbeResponseObject, error)
-	// Deletes a probe matching provided ID
-	// (DELETE /probes/{probe_id})
-	DeleteProbe(ctx context.Context, request DeleteProbeRequestObject) (DeleteProbeResponseObject, error)
-	// Get a probe by its ID
-	// (GET /probes/{probe_id})
-	GetProbeById(ctx context.Context, request GetProbeByIdRequestObject) (GetProbeByIdResponseObject, error)
-	// Updates a probe by its ID
-	// (PATCH /probes/{probe_id})
-	UpdateProbe(ctx context.Context, request UpdateProbeRequestObject) (UpdateProbeResponseObject, error)
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(SummarizeProbeResultsResponseObject); ok {
+		if err := validResponse.VisitSummarizeProbeResultsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
-type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+// ResumeProbe operation middleware
+func (sh *strictHandler) ResumeProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam, params ResumeProbeParams) {
+	var request ResumeProbeRequestObject
 
-type StrictHTTPServerOptions struct {
-	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
-	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
-}
+	request.ProbeId = probeId
+	request.Params = params
 
-func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
-		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		},
-		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
-	}}
-}
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ResumeProbe(ctx, request.(ResumeProbeRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ResumeProbe")
+	}
 
-func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
-	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
-}
+	response, err := handler(r.Context(), w, r, request)
 
-type strictHandler struct {
-	ssi         StrictServerInterface
-	middlewares []StrictMiddlewareFunc
-	options     StrictHTTPServerOptions
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ResumeProbeResponseObject); ok {
+		if err := validResponse.VisitResumeProbeResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
 }
 
-// ListProbes operation middleware
-func (sh *strictHandler) ListProbes(w http.ResponseWriter, r *http.Request, params ListProbesParams) {
-	var request ListProbesRequestObject
-
-	request.Params = params
+// ListWebhooks operation middleware
+func (sh *strictHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	var request ListWebhooksRequestObject
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.ListProbes(ctx, request.(ListProbesRequestObject))
+		return sh.ssi.ListWebhooks(ctx, request.(ListWebhooksRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "ListProbes")
+		handler = middleware(handler, "ListWebhooks")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(ListProbesResponseObject); ok {
-		if err := validResponse.VisitListProbesResponse(w); err != nil {
+	} else if validResponse, ok := response.(ListWebhooksResponseObject); ok {
+		if err := validResponse.VisitListWebhooksResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -627,11 +5999,11 @@ func (sh *strictHandler) ListProbes(w http.ResponseWriter, r *http.Request, para
 	}
 }
 
-// CreateProbe operation middleware
-func (sh *strictHandler) CreateProbe(w http.ResponseWriter, r *http.Request) {
-	var request CreateProbeRequestObject
+// CreateWebhook operation middleware
+func (sh *strictHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var request CreateWebhookRequestObject
 
-	var body CreateProbeJSONRequestBody
+	var body CreateWebhookJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -639,18 +6011,18 @@ func (sh *strictHandler) CreateProbe(w http.ResponseWriter, r *http.Request) {
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.CreateProbe(ctx, request.(CreateProbeRequestObject))
+		return sh.ssi.CreateWebhook(ctx, request.(CreateWebhookRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "CreateProbe")
+		handler = middleware(handler, "CreateWebhook")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(CreateProbeResponseObject); ok {
-		if err := validResponse.VisitCreateProbeResponse(w); err != nil {
+	} else if validResponse, ok := response.(CreateWebhookResponseObject); ok {
+		if err := validResponse.VisitCreateWebhookResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -658,25 +6030,25 @@ func (sh *strictHandler) CreateProbe(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// DeleteProbe operation middleware
-func (sh *strictHandler) DeleteProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
-	var request DeleteProbeRequestObject
+// DeleteWebhook operation middleware
+func (sh *strictHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request, webhookId WebhookIdPathParam) {
+	var request DeleteWebhookRequestObject
 
-	request.ProbeId = probeId
+	request.WebhookId = webhookId
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.DeleteProbe(ctx, request.(DeleteProbeRequestObject))
+		return sh.ssi.DeleteWebhook(ctx, request.(DeleteWebhookRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "DeleteProbe")
+		handler = middleware(handler, "DeleteWebhook")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(DeleteProbeResponseObject); ok {
-		if err := validResponse.VisitDeleteProbeResponse(w); err != nil {
+	} else if validResponse, ok := response.(DeleteWebhookResponseObject); ok {
+		if err := validResponse.VisitDeleteWebhookResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -684,25 +6056,25 @@ func (sh *strictHandler) DeleteProbe(w http.ResponseWriter, r *http.Request, pro
 	}
 }
 
-// GetProbeById operation middleware
-func (sh *strictHandler) GetProbeById(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
-	var request GetProbeByIdRequestObject
+// GetWebhook operation middleware
+func (sh *strictHandler) GetWebhook(w http.ResponseWriter, r *http.Request, webhookId WebhookIdPathParam) {
+	var request GetWebhookRequestObject
 
-	request.ProbeId = probeId
+	request.WebhookId = webhookId
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.GetProbeById(ctx, request.(GetProbeByIdRequestObject))
+		return sh.ssi.GetWebhook(ctx, request.(GetWebhookRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "GetProbeById")
+		handler = middleware(handler, "GetWebhook")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(GetProbeByIdResponseObject); ok {
-		if err := validResponse.VisitGetProbeByIdResponse(w); err != nil {
+	} else if validResponse, ok := response.(GetWebhookResponseObject); ok {
+		if err := validResponse.VisitGetWebhookResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -710,13 +6082,13 @@ func (sh *strictHandler) GetProbeById(w http.ResponseWriter, r *http.Request, pr
 	}
 }
 
-// UpdateProbe operation middleware
-func (sh *strictHandler) UpdateProbe(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
-	var request UpdateProbeRequestObject
+// UpdateWebhook operation middleware
+func (sh *strictHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request, webhookId WebhookIdPathParam) {
+	var request UpdateWebhookRequestObject
 
-	request.ProbeId = probeId
+	request.WebhookId = webhookId
 
-	var body UpdateProbeJSONRequestBody
+	var body UpdateWebhookJSONRequestBody
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
 		return
@@ -724,18 +6096,18 @@ func (sh *strictHandler) UpdateProbe(w http.ResponseWriter, r *http.Request, pro
 	request.Body = &body
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		return sh.ssi.UpdateProbe(ctx, request.(UpdateProbeRequestObject))
+		return sh.ssi.UpdateWebhook(ctx, request.(UpdateWebhookRequestObject))
 	}
 	for _, middleware := range sh.middlewares {
-		handler = middleware(handler, "UpdateProbe")
+		handler = middleware(handler, "UpdateWebhook")
 	}
 
 	response, err := handler(r.Context(), w, r, request)
 
 	if err != nil {
 		sh.options.ResponseErrorHandlerFunc(w, r, err)
-	} else if validResponse, ok := response.(UpdateProbeResponseObject); ok {
-		if err := validResponse.VisitUpdateProbeResponse(w); err != nil {
+	} else if validResponse, ok := response.(UpdateWebhookResponseObject); ok {
+		if err := validResponse.VisitUpdateWebhookResponse(w); err != nil {
 			sh.options.ResponseErrorHandlerFunc(w, r, err)
 		}
 	} else if response != nil {
@@ -746,31 +6118,320 @@ func (sh *strictHandler) UpdateProbe(w http.ResponseWriter, r *http.Request, pro
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xYb2/bthP+KgR/P6AbIP9Jm/4z0BdJs3YGiiVLFuxFEQS0eLLZSqRCnpx4gb77cKRk",
-	"S5a8pJ0XNC8C2KLI55577rmj73lsstxo0Oj45J7nwooMEKz/9EnMIL2AFGI09vcC7OqMntMjCS62Kkdl",
-	"NJ/wIxabLBMDB7QBgmSpcshMwr7C6t1SpAWwlDZzDA1LVIpgmdFDHnG4E1meAp/wOC0cgr1W8p18/nac",
-	"HAAMXsUvDweHs/HB4O0YXg3k6/HB68M3yfjNy4Mot2opEN6hLYBHXBGQGwLJI65FRlv6M69dFQGPuIsX",
-	"kAkKAFc5rXBolZ7zsoz4mTUzmMozgYsdYf6xADY9obBwASyn9RSPBbQKltAO5zEx1LBzgYsNar/xtZI8",
-	"4hZuCmVB8glF2cT/fwsJn/D/jTYJHIWnblRFchEWlxRc9YjefG9BIPg153BTgEOfeWtysKjArwnJeugc",
-	"rw9XHxNxhwJVfF3Y9KE3L/zKS5uuMTZj/dzc6Sqqc2VmXyBGOugXa409DR872DNwTsyhT6WLIhN6YEFI",
-	"MUuBAW3DqvXt7E31UqRKBtWyWkEsMTYTyKMe+TTh1xB2Yj8HlxvtoIveY3qIvmb822eHDfpObqVrcs+F",
-	"lIqoEelZC8JWbFGHRgd1bQ9CbedCWcdwIZDFQrMZsMKBpNowdi60+guY0LKiMRSOa/F936j+x9dO5QB8",
-	"wr0HlD0xt0uht6ILrW4KYEqCRpUocqaEiaq8f7q8nJ5Uaf/5uwq8ksyEF4Uv6Q67HuJGzG2A55BbcJR8",
-	"JphTep7WxhMbnah5YQWtHHo2mkkkHr/JJaKnL/rwcuEe82LhdliFJ7UBYr3p1S45uCNrxWp3CQZ99vgH",
-	"vUa8o1Ba6TkzGhgZiLF1UsJJXtsKIXOPysHpGl6FV9BBnUArWH1hbTPbq/PAEbs8/0SFOasgy7aoF4i5",
-	"m4xGIlfD6ttBVZrDxJihhKVbqASHxs5b4vbMd7TdylwvqriwFjSykLRWb/XIdJH54EFL2jPiIka1pIaf",
-	"CJWCryiwmdICw3MJKSBI4mkT1vqlDsLLXPZ0wzbODwpS6UeXwq8mP6gdolt5319G31EJHSn8KSxpc8/N",
-	"kSktVewp9vmx4ExhY2C3wjFtkCWm0FtS8pyyW4ULGpqe3VV/g55/9d+zzV7/qsdWJOwu8duw4CG622Ru",
-	"I6g36SKglUonpofms6lXTya0mBObx6mIv87MHQvWRGEr9Pyd/3p6fMEuVhoXgCp21Qp2dDblEV+CdWHL",
-	"8XA8PKCoTQ5a5IpP+IvhwdA3SIELH+9o42lz8LIgNnzrmFLD/aQcrgE0LwGf+wnaLBntuiSUV8RWSIA/",
-	"+Pl47Bu90QjaYxB5nnpVGT364iiY+2+ZcLd83LO+Ler6HiLSdN0xQa5HkDLih3uE1Z7tegDVY6UNVsM2",
-	"PA69vFyRZcKu+IR/BGTin+GTVsTcNXtDGfHcuJ4EN4b+6loBDo+NXO0t9p5rRdmuGBrSyo4oDvYritNG",
-	"Cba5D34Ue5iSuSKOwbmkSNNVpYO3T6eDo2pe8O5IhupEVndommKYSMmIVwzulMMg1JdPK1QEqwVdfewS",
-	"bOgG2xoNKafJVMNtiKhPk2VU+8/ovr7blsEaqVV3xXriv6/F+m121LnF9/jQYdeXgziq2aEtDvabYRXp",
-	"lVAO95aH7Ua1U7aNHtvOQeDKrS8smcB4QY0lt2apJEg2Pek3it5G8BFCHzheTeV/wv34qcr9/ZZdbpip",
-	"BsyanR8wqcH8A+zZiil0O7OYU1TdPDbG2n2lcf8to2f2flTLGD9tywhDf2/L+FFGB8Ly4umwfDB2pqQE",
-	"zQZMIEKWI92OMiNVsiLdIsSesZVDyKqffn/AOgsCdI+rNX/dCl9ud4/TuvYcs5B6sRAdgJbm9vW01vx9",
-	"1/Hyqvw7AAD//3gp4w5/FwAA",
+	"H4sIAAAAAAAC/+y9C3fbtpoo+ldwdPZdaWcoR37k5ayuuW6S7vq23c047nTfabJ9IRKSMKYIFQBtq925",
+	"v/2s7wESpEg9HDtJZzrnrN1YBMEPwIfv/fh9kJr5whSq8G5w/PtgpmSmLP7z1bmcfot/wl+ZcqnVC69N",
+	"MTgenM+UWFgzVg+csMqZ0qbq4kpZp02RiF9L41W2J15L54T2QjpxOhn+IH06E96IcpFJr4SxIlO5gn8V",
+	"+VL4mXaCp9gbJAN1I+eLXA2OB28HT4/2D94OBsnApTM1lwCPXy7gmfNWF9PB+/fJ4Hvt/DqYv9HFVNmF",
+	"1YUXZiL8TAHoC1M4FUBORDqTxVQXU3E9U4W6Ulb4sFSXiImSvrTKAeyFuvEXCzlVF95cqkJY5UtbqExk",
+	"JhGyyESmJxMF0Imx8tdKFSKXY5VfOJWr1BubiIlWeVb9jS/hT05cybxUrr2DfzOFqrdxYfJcpDMlF/my",
+	"vWGPsqP9o9GBHKdH4wP55PH42ZP9Z9mz/f3R/pP00bNNm/k+GSyklXPlGRdOXp9+p5an2WvpZ6/hSTdK",
+	"nL4MO3vy+lRcqhZch5Nncj8dZY/Uk/GBPHo6SAYaXl1IPxskg0LOYdSlWl7obJAMrPq11FZlg2NvSxXD",
+	"u5DeKwuv/uOX0fCZHE7e/b7/+P1fBkkHXpxMVeG3AR3gljBYWDXVziurMnGt/ay5ChwyLN1QSeeH+0PZ",
+	"vQwctmkhf7FqMjge/O+H9S18SE/dQ4b7DQ2Glby0y7Oy+PdS2WXPSv5D5hovF2H3r6VyiDylK2WeCF2k",
+	"eZkBSi6s8Sr1KiOkdIlwXvrSCW9l4TRM5xKRlYtcpzDfT2ffu0TMSy/hkZgZc+kQYcPFJpyXhbtWFjcN",
+	"Qbg2ZZ4Nx3jTytzjA1N64bzBmyGLpZ/pYpoIq1JjM/pNyDLTXqjC2yVeNeP1ZIm3Uo3x03viG7oo8BGY",
+	"TIm51IWXGsB2ZTqDVU9VoSwCnKxQKQQX3vZ6rpyX84VLhLRK5GqCW+Znaok/4PRZAoDIsQP0mBjLJEH4",
+	"mfS0SjFWIrVKAuULGPErHFWNEpldXtiyaFy9TE1kmfvB8UTmTlX4OzYmV7LAY8elvmEqse70T0Rq5nM5",
+	"dApuLx6udkjsUlNkdKjCFAQ7k5pEyDyHIdcznc7EvHRezOFA98SbcrEwFqahbUD8+OKrRHz1VSL+11eA",
+	"TgmeTfFlInRWPcK/zXWh7J5Xct7zCh4ATKrTi9Lm4ouvcF9lIdSNTBmIRPyDfxYLqyb6hn5+jif309n3",
+	"Yi6XMB8sEA5fSNqCL5tXlmHXhfhCpl5fqWShCkC2L5Magn98NfN+4Y4fPpQL3XeETZK9gSMRjt7uxCq2",
+	"E1iCN8xiEvinm1ldXIpc2qnCd3QxdXvipFgKbxbDXF2pnN6EySRPBbs1VgLWkj0PKDwzeSaA1S35BWB9",
+	"wHS0Y4TfE4R9ePPlYqEKJ+TEKysmOvfI4xLhTJufwddKVy0ALxZc/pmyqnk+OkvoiKLjWHcAbsPG/9Wa",
+	"crEDt6LdmcJbTcBm6fAgPZo8y/ZVN5XHdz6Eyr+GTzO8Eak/zdR8Ybwq0uV3akkiTS8OlYX+tVTAb2va",
+	"J8VPP52+TIhAzeWlcg2e4OREMUrZ5Z44U95q5WrC7eQcJ8RbOjbZUkyVb8hMYe8m2jovpPdqvvCJmEt7",
+	"yWxTvK2X4YdnapHLpcqOBWzP2wHQAueVRARFwgkEvj4NOZW62BPfqaVD+nOpFl4slBVeFbLwCFgq81xZ",
+	"eDtThdcyR1oBc6SmmOhpaVWGBL55qgeT/fSZfKqGj8ejbHgkHz0ZPpOHT4ejbH/8eDJKD9XRQThuEojr",
+	"A48OZvidWjYQcS5vvlfF1M8GxwePHiWDuS7C3/tdksnpBFnn2tMFibaWLcdLooRX2pRO/PXVOXCl1yfn",
+	"L75toPKeOI/OWjuSsOVikWuVCR2NFDPpiICC4Ksy4XSRqufi7eBf3g6I2Cpg9MuNonn3brF0sOG+nk5A",
+	"tN1uM1xjN6q94MW2URipRyJq+jpeEsl1e+JnoHMNlCZGPpNXSpgiYPg8EYejI9jF6sNBjJF0NRiRbyWE",
+	"921bLetvUntAfvsw6eBSLb9CjYOFQSAMRNlF+8DTvHRe2QudfZUdPBtN9pUaPk4fHQ2PxqP94bORejzM",
+	"noz2nxw9nYyePtpPFlZfSa++gjvfQ9GbWtFGJW+u/bpV/iBv9Lyci6KcjwH+SSWpBf7JBz8noRGFjAYS",
+	"pNIiLZRtFa+xE/ujUc9yAMImWdAFgBQTAV14NVUWl/SDLv5aCarrlvYjXGJaQ1jU9cw4Fcm5yLO9yJV0",
+	"njVqONc9UX+BqGlqygJQYKFYlG0s7qh7aXNdXNTfaqxxYuxcelrZ46NBsmnRP9pMrcXWn2fKz1QlZwPM",
+	"joRRkPJcSvIbGRGivzJl+0Q3fNgtew+kS2H9BQD8C/8F8w7eddHt13KqzgEj1p7WQgJTJuPAxJp5TLkD",
+	"sj1wK0gmTmuKfQXqXIuirTciJKJ5SAnu2sV4mdDmkNpDHFR7cS2d0M6VKgPO2bdzNXQbbicKMzvLXSyG",
+	"aHXV4tPbUJhusQwn/mCxrCGRvTHWf71cd+LnM5Z1O5AWDoDOUSsnxhaxYrwUOtsTPzM30T7pfFNolsnp",
+	"BLUTTnnBgk5FtrQTCznVhUQ7Fhxzxa50gUqsnCqewsDVutZO7YnXTEgqjkaimCkuKsUYIRFjNTFWkbYI",
+	"rztkpaTwXkjfhzuMfg3ECfesfhsex5I/aQPdt+9czRe59LfAM36xbZR6nB6AMLifHY2HR+kTOXymDibD",
+	"x+On2Ujup4/Uk0k3koX5NuFZRRvLEkeuLulnMmvssCI2hAhXjqtBzXU9Gu9PRpOjw+GhPHw2PJJHk+HT",
+	"7EgNn06eqgM5Sp+lfToNz/2hy3ofBkcWxB/H/6VSD38vrFkoC7cB/oowIZ45k14NAQ9Xp4elLrRVjt9Z",
+	"4R4k2oEK47xZODFWaFxKU7VA4/TfjMd7BBrDpVo6JrZl4XUurLoyl2TI2Q4Yna0CcYpKyUQrV4GiC5Gb",
+	"KVnO5spbnbrnQIdTWYAQPlaidHRhtXdikctUbTShrsByqZbd6IMKojfCqSIT0om3g5PSz4zVv+GNPxZf",
+	"K2mVFW/L0egwvVRL/Id6O9gTkeyhmBpVa3LAc9jstQIM4dTvqw8s3BwSllaAPQvC/EJZ4RRYr6rPgVUB",
+	"FtBxgjgbkUwY7ZS9UvaBC8ZoAZ+kQc2DNeU4j06VREe8mDX2/zJAJMflJDG+1jTKEHK/TxjZeRWry/M+",
+	"h13jFT0AwCcKMOt5RYe1j/e3BzWbdyjsdPsmmHgm4PLiBep6Tsxlpmrp4pLtnWh8VYggcqEv1fKY8AHm",
+	"x3+1UDLVw4VeqFwXsDORDrx/8HSDDvzhWMBcdVxaGAimLlhWsXxOlsyxEgvjNJj89sRLEvdQFbgL/Ejg",
+	"IDcJEi9LEsQiSSJGKjy0fhRyJ9bK5Rnz+FW6CWgP/9Vezd1Gh0JMgt9X35TwiRXAcOZOwDKyJMv8J5u7",
+	"N5Ew3XC2lRbFd/AbiHSmUjAKeTMloR7PrGb4iVB7071gt3Emr4xLrG6yngPnRIeGQlD1Ppo7lsLNpFU1",
+	"v3/gSFZ2iYAdyMpcJWJu6L8yh01Eb0MW2Y/cnvipyPWlakDnZ4xxZCRh22cQlHAK+DKZUWipQJMq74kj",
+	"Y5bzJDkRePApdIQ6YRVSegQddXK0313PTK6eo0F8vvBLemLV3FwRQ5k3ruEvg2C95i0cmoUq3ExP/JB/",
+	"2ZOLhdvjN4a8tXsTY/YydYUj94ydwqFvhU5vcId+snlAbbz8p/Tq/qiFX8mArJT83NtSBd/c18Z4561c",
+	"oE7VJyJU/rTd3GZbygmkpnVKCncpA9BnWArYhvOvfARn6Gbv47CP9Blk9TPU90zt23R7nRLoCqML6l60",
+	"e53UYPUAe9leOEFhFXw59fGeeIMmNxyTBGeUXAp1w5dOe2aA2gsGqsEuwUaJb6+8ZopUJaIscuXYYUfj",
+	"tIscvXsi4soIUsSXE7E/A6mCDQZ0472YG+ebnGRO1qdV5nxr7L0di+k+pxcVnbvzS1aT0G7cxIkfuIjU",
+	"7iCJ1i9VAumtFYJ6rs7b/lxYVahroSuF189Ucac0IILgQwgBmW120Ji6bnkUpBCdYDz5dhSgxqwzdWVS",
+	"PMQ7xzGWfDvPtwbABdkhXHJYCdxWY+sjhXuu5wrZtlX/hZEQ2x5yax8bcR4VgL07VS2o85rogCq2dtsi",
+	"esKrtUwrWSNA+YZNvBtjVOKgGTn8bTR89u6LX4b0r713v4+Sx/vvw4Mv/+0vXTiHK+g711ucKMK/UdBA",
+	"D4eL33L+Yqak9WO19q4TBsDwmNBvfZfn8uaCRLXd3AzSOT0tiOtqF85uJOZKFk4UplYxOgzjK1c0gmJl",
+	"6b1YdobLJbYQ8ePmgd1u9+9/Vyo0fjQaRY6EUed+ra6fJfu+a3ZmSngs5srLTHpZuYxRJXDCSu1qGwK7",
+	"U3FTHcgdxikOyCPGf6Ws9stE2LIYg9EMQlkwskXnqkjVRVYCOl1gdJIqZJFWTrbYNvnACS/tVJF41jym",
+	"aOYOp14hQO4H4gb/BZGluAwCH79ZrTB8ilbainRg7YHfqfSEvdTMH7pl4WfK69RBbMwwM9dFfItKq7vu",
+	"T9icjYoEj6txrH/z+h1FfHzxrpIZneYCo5XOFTJV2mqhMSIomryxI2Tu7AjHWsU45+C0TNGrDf+MQmch",
+	"vj0/f11b7JGa53BAqHE2TgmOcCGdS4QqJsamNUaSFB8HT/iZ0raSTYFvFEtxcHPDIVtsvOObyPsDx30B",
+	"Y0ghRpkZ/d6oWHZppvNurZS2YUUvbaIweMkvrJqqm04MPnt1AAS6zKWFK2aVwwi9hnsDpoij01q+dlrq",
+	"28Hx27fuX94OzOXbQcsYNTo46sDRKN65CRbFIbgmEPh92KZEKJnOMJCqUgQMY9BWyjNNX2HOBuX5ffCI",
+	"XKQmU65bdqARykWybEAEtNdyLFfTaHAwGg2SweFof3g4OthJ9S/dC5OpM1Cy+gwAc12Ev1YX5HN3gaLl",
+	"8iKTy441fSN1XluaU9ioCQWjwt98hwFZAmnWlh1ZuiAeA4ZAAZNDgBOyVQeIS4QyMh81/PpHuApiOYeP",
+	"H230ZK+SA7CfviowoGqD+U7RqO0teGHq5Ub7XZj63ToIl51RIqQ4o3EY+HYdH9AEXmK4RqfBmd6dKZ7r",
+	"mP5t5nNT0J0J5j2M3wK1MNeq8PEhY7ytyh3N8/fhN8ZeS5upbPiTU1bQvUXz/3hJIcOgqHl4l+Obb5Z7",
+	"4u3ALZ1X87cDJK8pG75rnZ1A1d6pfLInTvCKREjH8WUYFsTaWSWiZ3viBMzGKhMz6WYcoFrHvs3mMh26",
+	"mTx49Pj47aCelD8M7+Bl9ca2eLGdmy5+imbHrRzXtY2XNJ4dX+JtWuPhZjsKpThU+Q3BRQw6PcDKxvkQ",
+	"WsZyD9gxyb1AP+y13E0heI3itUPcmdB1BGkCL1PEKiNryfxKt+kbf0IVVw2vcnXbVja5Taa6FPqfKN6y",
+	"9sZipHpDsej2iSYDuEAUPbPNVf+xGv0+qWMadgtdSAbAm726kFnWk8lTKH9t7KWAEWQjq4MHU7iuEL6C",
+	"F1J7Jx4eHIkvTl9fHX0Jvzw8eop/Pf6ymqaN6d6WBZvB6QOqhe/7o739g6d78L/HR0/3D0ZdO8cAXeis",
+	"exF/H7KiM6zPJSyCg2AbRKnbuoqRMd0foGcxXZCYQDExNoGYSlm00l28kvOh7PxMCK1YZ6gizL6W5Ke7",
+	"pXWCsLD6XIyASRwlE658L7v4MUbcDuk2XPJKgj2OQijRc1N92SEqkcR4ruxcF0izEW9XkIeGZVUIO3mC",
+	"fP2amFqZKrFQVhugxBnKzaTnNwNN8APgiFhk0V+Ug9bxDIOyB8mgG9DBu/iom5OsnPfXZZHl6hs+vjjw",
+	"7L+cKSJA+c+lnIMVrsjw73e9M2b0xQ4mTpuFCRFjHHqMdzeERXNsWDCf+5quA/EOUaCruTUdUkDlDgRR",
+	"arME0+U9BN7GWvvG95vaPbxZaV8b323rae+TQbb5tZfxeJ3OF5teOE3ni+iN3Qm2LryyV3Jnw/9tDWqk",
+	"A24F5g84tH4V03w2vfkjDKrfWcjSqc27gqNiLjbd5pDPaFj9XhRXtrtDkyWGrdSh+i2fbsSR8zRCEaDP",
+	"pvQfGEqAdDxabRcpf1ETwl433SuN9pSGA7yOdAtxh055uIdoUABCz1aL5UIlIgMS71O0SsGFSSrDtVMe",
+	"pGYazEE0ITg2fERMlXdVgte41LmnIX5Wx/A9cKK0+QXbtJFqXUmr5ThXLqlz++rRIRQg3K1E8K7jYLaC",
+	"XM+UbeZO5koGswZInv/t6B+oTVtdfPDPNdx9rSDRjXcE2fl5GP5RKfB/b3J6N4Sx256kU7yE3mAkFUCc",
+	"dZuNIWVyY2RJw2ScrwhKyeBmODVD+HHoLvViaBZ0VYYLg2dYhY3sTGBr+rWmlkFNgbxh4hSnYVoz30rF",
+	"uyU1D7LnHVypihI2CdTrBuHaEJi3klde1sbjan6hizVUOQHTTCFb2Xe/RzlE28b4r5rZ3ncwt5eFO8Ms",
+	"8vPlQq3zssKbYS0v//aGc8+dkMC5+LghjF2zosrC+ckgGZycnMB/Xvzt5IdXg2Tww98HyeBvbwbJ4PX5",
+	"2SAZvPkRnr45+49BMjj/+zmMPDlpqgonXTjzcpPvIILMKmfyK+UwU8QGuyasBcaEADeKoOFcgUqpoqex",
+	"LQVmiY2h8CxTVl8FxuxntBlLtP8X4uybF+Lo0Whf/HR2yoF7WQEkYH+0B/9vf3T86DCmB+BB+jdY8Vcn",
+	"CWdthkiHqb5SxXNyf4CtNgYD/TPd4XSYbNxM8asi7KqfeZswVJAoF1noyRuibhbo9L+gigWuP7xvleW3",
+	"3+0sw1DymdAYEoA4SZ13rTKG4OpOAhYm3Y4gyu3m2eAHzP5SlRvGVrnaW0QVrjgBDuHg9g/3njSMYxtI",
+	"RG3sP+hwWOCxXHQHJaMdsczzpfi1lDkaU8ku7E04t+dCCm+lzkHFzwzlRDE/aMU6bMd6Gsm5h50GJtj/",
+	"C/p9o0CyQmlwBkK57gXPjPO/HC+M9e9i4hOMZCbkqsII8egwijcji2gIoaoQu8+rM4hv4kYLUXROzT3o",
+	"0h9aTKvL8MDh1iLjoVVG+tvB4chB3vfbwf4c/wlY+3bwaDSau7eD5hIOR64ZsvIFFHp5969fvH27R//6",
+	"8t++mLt/un/O/zn78st/7QxXeWWtsb1hSHlurlV2Edxmq4t5EyinDMUvQkyhq4KGjtlKQnNE1xboCdiN",
+	"MJcW6CiaX0prVeF5fOsWUmUKkDCkzhWKFrXNaUfXXCT6tK7lXDknp502o1k5l8XQKpkBcxcKdk/w+Obp",
+	"nBZx/FFV8IFFo8675e3yAgnrBUXyd+13OZ0q9A3U8SM8GHbxWtZBeTifLqZQmcILU9APNdhOfHE0epaI",
+	"o4NniXg0OqRqIzK/lksnFBCdECNxBi8OT5DkV35e8i41Y1FWnX8gaGG5HVCE4NBKuwGNmKKT6xLecKKe",
+	"ApZBUmeCGjUcN6Y+ED4QL9zawXyOH/mPavZvCL6NfsOAH123H+/TGm8mPN4EV3wn29+mCbq+/A0X3qrp",
+	"Tp9Yu0GQJZkZIuK9NTluq1zIsc61X4qZLjxxYwqySNi9N16Gyl/EpeqE3KpCTlVVqMq45pAhN0PnoZ4W",
+	"gLc8DVcXygw6FS8Lc00mCzh9IcVcOwdsL3xUOlEW1bda0vRY+nQ2DIaqwdU+2bS9HLplkQ45XnxwdTDo",
+	"kpnbcQgdZKF1KyIaF4TPbXORzmfVJDAg4foScAZODXXhVEHMo13Q7IUpsIYIsNtWJOP/+t9/+b/Ab3jw",
+	"+MG//OvePy7+v3/+/6Phs5Phf8rhb8N33XwBT2j3eJTIn0GreMCH7Rplk6JVYhJ3oVTmKhVa1R7mLtb9",
+	"DyzSQQG0D9kbsCmKZduUosgq0huhBNYVPt0FlRRqKxk44l60DBCQWDaGjxw/fBgJpnekOoRgKJleKn+B",
+	"VRB2Ef0BxH7pjmMbrDh9XftSOchdpTNTFynxplWRpl5oF8LG4HaEKplrCnVpfgMjlGxZ4Pfdc7Hfi3WH",
+	"UcjL/mhjxEuMbLghncg2B2r1whSTXKf+jbfSq+my6fwCxhbp12DzGSQDc6XstdU+iEKd/i+a/lXgOG1k",
+	"XlbM1kwqFxj6UgF99LympM3zTU2m1kVXCRggXv/45lw8DCU46poxpJ3FTlBkFcfiaDSKq2ppEp8ScTQ6",
+	"rH53yrvY88Y+Zi5w1izPMRp1SSPowVtXloCJF+8G/JNkPCz0hXEVjdJfYBw3RUv0o1X/cvhurzKr9qZB",
+	"7Oj0ytRNN/SkU5giLKBybtarSYiMjNp4vbpLkQBcr4pPpPIOgI5y83ZwjE6CY1EWwKMLURbai7eDm7cD",
+	"+Hql4dDgrn3YzsYKz9eurU6HGqsgHWy0w2Lk7kzJ3M9WQWv7jnDzE8L/ZK0QeMqXp3I975oFsHLyH+Ch",
+	"u4ULrGGtv70geUJFOLB40pCKJy2kthwYlcqiyuTxRhg7lYX+jUKjSF0KSaAfahpNBlxiaXA8wCJL7zvX",
+	"jAXLXiubKsih61JTeIxY1IMwPFrnuWYtLBHKeT2PnXYz7byZWjk/rgtmUv1Gb+pYTFWPE+MSeFnCgRxj",
+	"U4J6N7XmmqbcnyPPPBx1SJVzedNMdupNzF48Gm078tn2I59tNbKFkwAKfYamQF7biZmxX6czrNJcF5GJ",
+	"Aa2gK0kLGjQY1kUDGlpTehXSQOf92QzofbrwSs4RUZVLZU7aLVouU78+dYFENOIeC2n9CkkLRUtg2Ivc",
+	"lNmrKwRkIZe5kZnbE6SekTWWN1Eo8kczCysEpOfVl6elgq6A3LWTyqJZhgerjtyIqI5iIdRc6jwIdImQ",
+	"YiGnygpruBgulidNQxBUoVr2SWfV0BTAzv/viDK3LZKPN5ZKgHPpi0yTczi8RgHFRJQODSKwiP5MMYAO",
+	"fDeA1i3VipQozhOr/riocsXq553pYl2EqBHU0ZvKESENIAe8wnoBlYKNk2mudZGZ6wqnUREDGQ8EX3q1",
+	"qt49Lr24VGqBlvYiJdMyevbJnaCtqFKr0FavixKSjGtw4G2HVyykVkRhYfydSEeh76+GFVdrg3E8aHPq",
+	"STJoO+LXZkHWH6pi5L2J0l+OQS6WTqcYOg2sylI6Q0ERdNfGctFjMaZkXK5PhhIZDxCUXg4J3FWNTgw6",
+	"+64cK1sor5x4o1KrPE4FjwqhitQuF8hEdF7nveQmpaRcq7BGcG1mYXyWRVYZIxyW6VtWiSlnr16evDh/",
+	"9RJICNWCC7+IsUwv+eSqiLaMrkIoWt2by9IsDcE4NlFYgX2mgvaPjIv1gYe/h6jK9w9hYzuSYXA3L9Yl",
+	"8kf7/TzCp9TMxzrUn4wOr3mjw8K7FUk6t57v1ugQBj6vlf+AIdt/Lbyx8WvdU+NG2i0JC4yloMguGxbL",
+	"anxD1Q2JsGyUCeogphtch+rEKz4/HLM+uTzSM8ML26eb1kmVWxl4GxGgHYZ+tkh27z0/bGmEBCdYCrgO",
+	"Atqv2irgZqNA+HS1pnd9J0aViFa1CK7y3Al7FXkWpSAci1YcVl3hJRF1hFSC6MYBahSZVseDBZsWSkFJ",
+	"xXc4vCZpBsRRcBuHemCFmKInnQHpG9pi6oYQOJJzyKjM354gxw0R1KokfV1XxswXEsvQF0CSqyi1jGLQ",
+	"r6r4jBV68MsgUtQZRBQldkqD6K0pFmuwlkygF5GwgWD6a1MVLFWWFCWUVm9V7nEFVjAy7pjhYvV0tts7",
+	"q+WRBvzlMFsSsLYX21/qyaTffSKzTK0LT3KVuXy8FPjJutQ6XFQMt8EhofPGVnSktTPtg+e4/h646CAb",
+	"xWir60no/iFQMXXogIqzArbdLTinj7FZZdG7XZWRtrlnIeeWYlbC3jWtfQcbCS6hTr0t9bHFMPXiZbP8",
+	"/JrKkzKulB/XmgczsMqqcl2nL9FvsHW5imaZ/Ugveny4pUryL5u0kXipt69g0VWtPw5o79dncMseuLjA",
+	"a1APOnSImuyXrcqKkTpAgmaPUwAObaUMgy5qWLrqT8RCSO+9MpN6kiSqUlvVQ8OC++L70O7BTOoGFXd0",
+	"z7YLy68Pi3hrI4265IZXG8x/0dZssb/x1sBmE4NfDfb4PQR7gImaW5EMjvc7wxw77ZslRccg2jURob3E",
+	"9Ze+tzbIba/C7SKmd8S6PfEKNrbKEwh3K8T4s1fSO5DlKpvVlbJWZ9un6HfkSrQy3NenuHed3SZ5OMbW",
+	"dW6o5h0E4MvKKAvrpu8cU4O10M4sdPohndk2sugSkamplVko7gqcaiYdx54Eabg2YTCK1+aZhTVTdJRX",
+	"Hyuw4Chjd9DeZbasYQEY6CL0EsGqaUxdsjryGOJ8tK3h4xj9QCuJr0jYiGYsbnh/Da+gaMree/JhpH/Q",
+	"WWCkYT2m+dcjzKYyAwjA9prlCqPcFDPE8/cC+a123tg1AM5owPo+g40YPJcIk2fKeeo/s/Wlprt1XjU5",
+	"27i2AFrv4tbLTdyap6uulxJfQIse1rq/vJUutDEZgUBEA0f/9nMi1lr6y2OSyiqnQcwTodSGKq60NcVc",
+	"FdufRdOT2MHmKZRvG7ZQubSlD+FydWBdEuQeLn4fv7IjsBRw0AFqCFrwfTY9NjsGsOvh1NMn5UCJivx9",
+	"EJhr9hSCLBYbzrq5qXXVhY6jV1V6cZySje0vVa48Bt2HjLx4jfArzUeuGnzwFQB3V0tt3eOA482jqvej",
+	"wrbei97I1eq2aOYyvRybm2D8syESqmH0B89E1VWSGVkoxzSg3CbOcqPkuHdtP38Y2HnXa92mXUy7cgNI",
+	"AYwyDyA1csL/THBcl+DYY/blUB9ZUcnK5RN1iuRHIYKZm69QwD3V+4SpXuBh/CAXwSmasF9kgg556PZl",
+	"nJ9a9ebfvxfWXLt2HNnB4+HocDjaP9/fPx6Njkej/+wzQFslMwiGa/mb4oCHXO24B2OF9UIiWgDpvr4t",
+	"27GpKHwguMIQE+28dmRHdXqpCoQpOKGlqrjbqv7guPoD9TGrK312nYguuB638xga2LuTBx+8kzvmuEZN",
+	"llbDyb20sDVe7JO/HRaSWgWsl3auURmHw9yDENW47FQTglq7rpZKDzcWkO6axdng3CfDxZuWRFZ5T8mM",
+	"TZsbF5PAWkx1NQnKrqbKzjRJ1o7fWukr1bPXkZ7+Z4mGP1qJhnZv3t5mWi23VSxTJVUFmuoGUD5taKgV",
+	"3wLoI5iIsuBG5Y2LDw0Nt7nTn6CuBFt2ttKWsOrtwWi91iROcmeIluK+dfiw+WNd9JPt/fQBapbe6CLZ",
+	"5nEfpKT1nEe7PmIU3bn5Cz/Q4JUNtko6U2w3xxmOraeg4IpGVtnmNJ03PPqjlxDpTjpfx+LbPAS4A6MA",
+	"ohxjwHPKd4tNwly+H+upi2/vgFV0oORKJ9MecWud1HT4YbweEuBn0s360l9uxJtvT4YHjx5jeluzh4mw",
+	"MzN2w6jcLg0YljYfwqS0RdiymeKQ8B6Lx4ewaitTr6xLOAfD+WbcV1XjNQmtt5e019dy2eg7h34yIgg/",
+	"nX1ftYhjatsjv2JBXUzF81hR7saHzFbnpV1JUB09fjqS2aOjx6l6LB89eTI5Opg8Osgmh4fjo3SSpfLJ",
+	"o8dPHz1Tjx8fjZ9mTzJ1ePBsvP9olI2epepZK1BtNHwmh5N3vz8+ev+XzUe0IWa4o/lcSxGE/8nVfNWO",
+	"0ptbiUeLhCIR17RfgLSYcNmSO9leis8PZqP5qO7NR50KFhrTWspFKHwJMnJvPMmufvGtKF+8C0T/Bli0",
+	"ua8+c6whqMJXKRKw0YplSqs4CGdi7HGDeCT4V6UrcBVCJjaQEhnTATb+NLrzK6sq9oQ5QbfXmdaj0oLr",
+	"v/Euro+j79jEtYks0R4dC+fL9PIi4EocW0EL7USSJFbELrwxF7lpWtshxTYgH9l58EWsh0K6GfxcnUVF",
+	"SHJ10dx4/gseo4ebAthUEU6AiHNsAWmsqJn7XIFKd7P6WGdiULytm+ziCx7Wd2EZI0P8KItO/NaOhucG",
+	"5dhkrKoA60WcM+XK3PcZewB8U/rUzDkBqmHwsWWHmSen8P+Lzt1ocO+oXTYxAAVpfEk7WWDLFmpRXeuO",
+	"oAmolx5nXsWtv+sq0NBGQE9EYbpB6/Z0uzJNlXPbRCFTLLFzfY747e0jVha3LOQZwG3uWBKfWwzIBsRZ",
+	"0xPiHtCgDh08ONw76kKLjiYPHx1FKigPRqMoIfLRs2frm1B8QlRaaWkIr4fDKXNmrLxEccY1K8R11f/c",
+	"z2TRtKeluUkvhbtU18KbXFkMspczbjWgPY+4PyzegLlvyvlc2uUq5lJeUZ+3CO2D7HGgvbmtB5HA+Bq/",
+	"1uVgad6g9TaelaysVpnnje49rAVTblNPOtz7any/xMZxBnUCDykZtIfC4QHo33YJbeZjv0CVseeD2LzR",
+	"TMLpkOxGV+U5Jy1X4QcgqigMkSrUtnyGQOiLMqlDeTq+381AvPEy33a2IEx0T0VJLN1z0bNo27H6OWd5",
+	"dzbd7ZJKqcozf6eBNwENwoLirUqqW7XhVm6StHgbutxSgPv1lSzU9e5XclUi2iRgBXh6l0UWma+lT2e9",
+	"vJKLzvc4/ukhEGa5WEDAKQaDEe2+VcuQCC4KSvmgcKQA/HY7sMW53m4NdGp3dV68L10JIPi8oWXWZWtX",
+	"peFbeAQ+zPL4ISbH2xiT1wQWbrXHfG6bNA/YYsI0LsQQevfcquzCyfmLbzts1L0lGPjLwPQ5fxBEO3E0",
+	"OhLGiqPRs1Wxr8Od9EGosKrPR4CF4LpQdSHTWUcGFMIPARfbxASFbDEsPYbhgLyDIRzDGw65uxuTURce",
+	"4Wn2YtE51wrtC8KOWquvadfLkzSc7js26dwoXP2BfHy9XfZv7xeo67h2TtyoMduRYBYew71v1ITVRR2H",
+	"JRcLJa1sc8EN2Uhr+vLHUMcwbuzY30DN/qjoPx5GtBkh/B6iEuXcFNPGfWo3BcS0jlBVcygXerA5Tf2O",
+	"MG5tQeq4soBrFpKPl8Mha7/Dot9TL11wnSjrqqzjGlEj+cyVUIVLuf4aK7/XlTreNwqu5PpK/bZpl7oq",
+	"djU3YCOObpK4qxPdTTZrUedNd6/+Sj/AZj523hSqH1bmTetJfh1kFYKBmL0Zu1Kf8mB08Gg4ejIcPT3f",
+	"f3J8eHQ8evKfu+Xh9lYKj5sLERiVCLmRoVxLW2wRAvczDethsWGSRvueaAd7D2ITxoTahJvAa9ViBFqj",
+	"bvzFQk5VX1I7h29Und4X0jmBsVrhHfi1zquHCfFh5dthvyJ6fRayp3tTXxoJLjxUX+boU8xWNTbgFe3V",
+	"nSUobQpkmehiquzC6sK3aFkwX3I8S0inQK8OF43cE69h/6huC3+JCB34by4mxl7UwV/wU0XscF972191",
+	"2Q26LzZF8KwLhQ1d/iTnT8N2/1Y1+KcgWF2gKQMr+QVrLY8mt3XU7jT0b64jZ6vLXnW53tDjersW183g",
+	"pB7HEA7h+BcGECvWlEWjaXBUAw78t+E9WxaOSmEs+betinZHTVfxSShTsdLzLt4QVQ7BoDLc37qAcONs",
+	"11f5TgYkSPBzbs7QMJD2bGDTKIbh8KSOo41xVUFEc+S2je5bxr41lrsNabX01S7DWPelaFlYscEnx3eb",
+	"0gKZlstOp2V3J4fOSsLjZWSvfx5VlJxJLxwFYcRd7YkwQAHCr2UmWLLdu3V0S6tXcweI9DxQtWZ9qlYF",
+	"HKyA3OzTpr1Oca9rNqeLiWkGwUfDVgFsBdt9Hq1NGLDVnrld5WfbLq1ESJHm0rkq4frg5oZK8wARBZj0",
+	"FTqEpqoeMhoND589a8tF+GO7svr+8NE7LKr++8H7f+JfNzf/bPw6bPz15V/6F9i0bK2665o1xjPlAQdC",
+	"KFQVe8dlWkMnY0JiGcIKgJtFIeUdCbaDTMscq3RElVWPj44Oj4V+aELdjo4aXD2rapjces06VaBGJ5wJ",
+	"0fIXcq7yF9IFcYhvCKbMSDcbG2kzqtxG5QlutxlVGaTGttbBeiFQMjAcJ8bGz56vlLqPOgTORZoraese",
+	"3/VuUxTjT4UFHUqySzfK4j/qyuJ/F6Xs/8sajFp3kZsF9RuiVExX6rCS9UX2K0G6SW/6DWYrkar9/ZTr",
+	"vL8ql3GnnsohrFH741owYhmkrlLV6t1qrVZZs5UyfoL+VWbai9xM69YjVT3nbRonXyrHiRrrmidrF8qn",
+	"NnEG4R92FnQBxW7X2Gi7JoTKR4Zk2kW2uXaAhQFGUR24nuiwW7V0bcJw+3yT1Y+bD7L+437jLJtCSOJw",
+	"5Y3G/0phw6Cv4A5o1Y6E7JPVe9BrQe/nH77+eCL8cgECQg7J5suql6B2Ils58DvjFHC6an0MSHM3Algo",
+	"Vqqs2bIWW88CtK1Gs/DLLdEPvoX28k1RkDvj3x2U7q1Db9VGzFNrecJaDEQjXwcKJrUywhI2Rz6CWE0K",
+	"m+eyE6XN636WjepXjN6rpchid6844U/VrLdT2cPK9nVGVSJ03I1S1CliKDq0w/jjMPkfaUPwlsBamwa0",
+	"FrSoPGTWLBY7JGw0yELTK72/ahzpa02y6gqCU+th/PAoiu9iaahxgwijAETVDnngmskXmho8o9SCPUCa",
+	"t60xbAXrN7r5GqC19fRBq1R31ddNeCOwgxN1PRAMRChAu9FuQ7u2Pvi4zh3p6zcHFLHKDy5USk0LVrpB",
+	"wLB7aQZRldtFv61PoR1ENo437PjR0eHB3baF8PlOjeDCifQ2hMBuX3CeZqEKIcX5i9dhO+HitrtAZOON",
+	"iiYuupMB5FvFH8rcGawXkyuvHID0/Rsxk0XmZvJSUX4tQ7hVUdrVQmS4I10491PdWr239/A31ITcm8pF",
+	"jgVoe6I0/kxN/7P37v+0Vua3zhr9MzPy07Tj7apW3PTwbZ9Htr5Hn9BFhq2j2Kkf0qqrvjcTaLbQZDmv",
+	"GxFGD274/4Yd/xP+70E910ZZZJ0MwpvQ75C8U3dpJwTUjwC7EPRUdDHZEtv9UOQUNzBwdU1f4qq5nqh0",
+	"mcKBXHEFpK54wub8P1EQRu1QxncTYtFoSuGaIH8fnmFa6JsqLXT4UkGcgV1GnQo3ep8XVl1pU7qL25GR",
+	"26QTbqOV4qrFTC4WqtgliGubPq3xAWPvuM7YIZwpBjYsdhPOnDMI7UvaiRSg2dG7aNx15Rhewga/DVMl",
+	"ijp1tSL6O2RLVFVd6edOa2XPGysbyCv5oDi8sCKgMNWKdjhE3JkeAZqeiYxQvepfgjnJ2yqmqwiwqo5u",
+	"GQ3Y28MJzCoMq7SqphbNS2n1YKtMZNJaeV82hq3x+noD1rbYX2/CFkM5iDxeSr31zU7TZq6938E8sM0p",
+	"OJXaLn/xd6ryJX77w8mL4ZtvTyB33ulpQc0xN1DKN9XA0JORjUC8uGWoD0IhFiH8Yq8VwrVi8EsG2KOu",
+	"dpauQxFwJcLGwX/dWoRZdT8iw2mEmLWLBOyKZ2wYoQ1fg1WbAoYCN9w6wqxJcTbFllXTr4L4/j27hVeJ",
+	"7+tTZM5zWUgMnvk61GSjGCik8546V3z749dvRI0qPEKcvD4dRBE8A2yljQrCQhVyoaE79d7+HoebzHDV",
+	"D8mbMTbGO2/lgtqu4qNFZ8vIM8QzJ6RwM2P9MEfrB75FNkcZejSxLaJOyYaatg2HjzXldMZNCem1h7/j",
+	"f7GAS6ODCSAjfUQ76u4QEF5g3wvxAp02TrjULDhaXGCrHc+GFnrMteSo1h/BGsPETQvFXGPyOGwFIDcg",
+	"DwrZpxk1q5FeYU+Vr8O+ncPYAeGBcv5rky0pPwD7v8I/V/qjQnRIZcpaq4Kvfqmq69vEPb7OVa8ZmPlg",
+	"tH+fkPwYYXaLfsBj3EmgSu+TwdFodGeQNDs6d3w9dPrmAxELaeVcUZmRuvw7iDqYeirH5qpVoI0TaRn0",
+	"w48H+vlKE03Cx+qSVpj5Phk8Gu1/PMhOWvclrvdOpXWw1F20j3tIHV3IfR38oFGgbC0lal0NN7cRMweE",
+	"T04dGulwxOAdTLlKMJBmlR0ki7sfwZZSXT0K1iI/2544KWKXyYxVTniItu9GM8DKu3p+jl651BROZyhp",
+	"TDFIEPvINSomWyUdMH2VrVKSM17oCZdCqZF0cPxL91nVQ+g2nmavpZ+9hl8H79/dIwEiWAn4ncjP6G7h",
+	"6Cc4+LhCns+L6DAsn/yuhsNCTO0IucDMZLoJmnv8Y/jP2wHYco3Vv3EBxq+p1Rf1DKq/gn+rt4NPRDVr",
+	"Tj5WUHiF0lgLqtKFt7xNkMIVrMUBY4VVE6scVeKv7vzWhCiWXOosgS5R6r/QPcVlL2vYtXNlrTYSVM6I",
+	"ibRJoK5W4UZy2iCKM+SmxYPlQbhsFwjY4UiE0hbivJqXmrxLbE7ZosoJM2+kztonANBU+Wo/a4jvWvyy",
+	"6spcNnvYddBOGINYHnUPvCsiep8UrAYX1kDz9FO1aHG8L9lnIZJ0ndHnL49gSFntVXTeWBW8sNxiMxyJ",
+	"WyUVuOJWd0UMnIiqmbVJRNKjSNVXENvcdwhFfPs69KQVfW0zicZxgTpzTwusrwJfjukmmMdDw02C7/Ql",
+	"Xn0As+74BLX3TFH1KuGrLHNU1gimiHrUdIw7+wb1MKvr8iyFulloq54Lv/I+eMSZOlNnv5SLAZJFP6Le",
+	"gXVFYkAt4TmOLQWoQwRmTHcL55Vs7AyQRVNQy2JN0FsFP2ofFRQkwNerivU9vhcatX9fNGobysQc6+ML",
+	"OeedEkyQW8qCDoaknbLATjArTA77EtIrMTKEPAAWGj4BwW3Tg2vZuhORWIN0rfPCBEEvKkneEJD/6ATb",
+	"B8tQN8FoE/FXwS7Vo4SuSiUJSYRMCbaXAuukvmmXfZiNdrmeay5vENKsGqcF69RFo6VQgkZYiFrKYUN0",
+	"nougYhLh5vgfsrqFWaPg3SaN+l47j+dSGRo/ZwmqKyO0A9F4d5nI58vGDrXv9Z9a2WeglQFgR3cGWNtH",
+	"33sUJMpGFLFBLf6qfJzjGiPROpkPCcJCX6pl//2Ha0d3HYYF41NccpSl/STOZdFWkP/D7WFOXBXPKLO5",
+	"LrjTefcVf336HcBzn9oNfWLj5QRvHfg7YOGfVmbIdMbWPu7J3tzHj80e/2bi77OBkdniRkYY7WgnDofn",
+	"McIyjvZrKbW751It2cFTejPH5Ys017BAUg020qOqs/TbQSVqc+NnIA9VABCpOz+evnxBhgr4cqfX5/nK",
+	"flC3fDTbSDfb5YqwpI4YfF9+HJz8U7lu8OP9wjy4qz8/X82f1OG+qQM5ZIrwvJM4ROzs4e+Xahm8Lf2G",
+	"Tc7txo0LUX1wjRsJ3iFkvqBUUZQG2K6Jcq9OVex8YQhr4yxH6O5yy18ixNUt31HQxdc2SLpH3ZEgwXIn",
+	"/mYEY8vnjtsfWRyDXYpiPf9bXC62GG5zvSCldQtZEdOxLNUeDfH0LqmKdlb9C+Hnur9zqzypeFV4q9k/",
+	"eakWqGHO1dzYZdu/gBx/LjM2e6IOSWy33h7Ox3W6uGQGDM8nZZ6L0ISnWyKF1xiU1cvYKj9TM/86p9ew",
+	"O7fKmt61C6mGqX8tlV2GSmzHcXGiHTTSuori+2Qb2HFLMWFPO8psTup6MlSDxnKuLz6lswKhBtBxjD2O",
+	"W+Vk7Nz0LAlnaKxnJc5qZ5ir49zr+Wg1YOuNRHz4sXptF6gkuu2C2SO0ztsmT7QL9FBXuAZ7u4rhbXB/",
+	"oAiSqKqy4ovnDS+jWWV+NOoGCI1EDYCquu77q6U/79eDFV3ajYoeMBwsBoEOP3gz7ECLIvWoLFWTnujK",
+	"L6pwuEBIYV4mo/hwWLeN7qSm1Hq6sjxyeyBT1GFqx0LWjcLjen1BntHeRZ1kV5of0buYCBQ8B+oGaTgZ",
+	"7cA6yUXpeWYojgU0UWX04dA3qlb7aWA3JY06ag/u2/bW1bi7R8cndknrgX0IWcqnL9faWfiN6Igbx9qv",
+	"rJIO5zp7lzv2n/HfWHmXm0fmoTRJ9R5AKE4neE4EUp2oWfWTju1COTWI52cs5lKuspxKXVSGPcp7CeXp",
+	"pc7r1qLaNYJ4u9TT+gDuSUWtP/CJ1NTVFu6rqIWP6+K5n522+hHNqyFlwynvKN3Uk26FiM3wPPu4Ggbd",
+	"oHAliO5R0ScGdlJXHPCNmlZABtWKEE2oX709ZfTvoQ1tLvDwd/zvJpWVFMMQi9NoZM7rceLlq+9fnb/q",
+	"reONlD6QFyAnlS9prLCuQ1ztiLzeumodTEQrzZUsykWf3tq4/rvprvjW7qorvhZKcHcorx9VQyRgmjri",
+	"R8Xuky7ECHEMxNlj5w34axEaaARilQc9qInadKzbonYSxJkmbvxV+XtGjNFHJe/nTTmA7lItKn0emLci",
+	"vTTOMLQBP325VogBybjDMSxLRx3TrXLlfB1RCh5BgKxdsC0Wd+K+uJj5zQFAMD0Rr1WSE1U9uFPMuk+h",
+	"JbR4+CTRyduLLmStyf6koXdBQ/G61LdldzmhUXC8U2GsSpfXVV7oPlWvCqc8RbVivl4eNfNmSxq4srji",
+	"nYyj+oJlOzbFtUyNKFg0JsOmU6SHCqdI45mjjLXekBdaquH4utJN7DsrDGvd61TNakPuXdvsqRq/VuGs",
+	"TiXWOQsJBqF1WqePFtVEmvpJrHv2KmgB5vvU0dodHz6FmtYuud/BynnEZ6qsrVMzfH2I/cjQQUQe/h7+",
+	"uUnleN1tO1jpBVGHyPVFcEXaQYR7u3Hr8OLuOkJ1yJ+JmlDB8wm53Hl8flxYjAl7swl9swhYsBm1mMMG",
+	"tWErVN2gPNw33ow+OulZoeufDS726g4VxvSrDy1WVPr7pCuRCnAP+PE5ccbRJ+OMTV3gczJj/km0tyPa",
+	"QXnZQLTPuEHG7QSMWDnpEcp3j9zGGnRvVK5Sb+y/g8uR72ey8VWsd3i7V3/QxV+rGrC7vfo9+EF3e+W1",
+	"nCrMR7rF+txu77wx1n+93O2dH22mdty/08nfTKF+AOPRt0pmytZvNvH5a+yIXfc9Z9t0rF5bDu/M9GSi",
+	"bOARximhMWR7okl74qIiqGJGgyrfbqVKhomlpbKoehLeDXYqp3zC5if4NqmifqaKPUHVmajsXcipIgcx",
+	"eduq0sDN/Hr2mYkflCx8XEpgYXL0r9UlSGonapebvdWFqOFwz6hh+eB4InPXWU90peK3uRYQ7S7kSnuj",
+	"sEtjOCEXyixzn4UskJlQpuJw5PbECY0RB/M+6OuK3x1QDw5HrhEOQX9vDGHAloh8gJToqKTNtbJVO3qo",
+	"C923voBe0glnTAH/jRCxA+l81EYKG4RkMXYtK6EmM327wMCujXb5DPJTTqhuNmxpnsexVIyh4mewek+Q",
+	"CjU6MGMUNPQC5CgrHCGk49LAPdtmORn8Wrs62wS2kGpL4S68Ope9JQt52ENgNjCOCA8t7LBTKURaQOvn",
+	"NrdjsuyZok1cTidDoGhDJGm88hZGJU28ySK5NaRhwgC+Ane0sM+mXk19pLLReW4myUDq5XxB1c5g64wN",
+	"BQSQFPI1U4UXeFVIrto//Nhhp67MgainSjHq1lFRWINL+GaOOdsqExTDxsvo8QMXSgssTK7TZYjOpITB",
+	"4bXOYOTiuSikteYan1FfNscCC7wBGxk60mB8lSiMyKWdYgiZLCpkdR71J5YEuTBotxq39k63Bb21TqDv",
+	"VUi/Dt3GokYcVBF/XkWnYFM2AJWRJ9T7p0rUurKgxwnb6kamlBe3qT01vEz6KH09abXmAV7eLNWb1OE0",
+	"mCvuqzYzVMXcJSEwNGlGheFcoXwamr1NUYfJRE0EuLIFr2tPRCgWyuTpqE1bDU7UlVt7XiBmXc1kJmRu",
+	"CrVWDQ5Vfe/TtNvRwX8rHfbJPYKx1eUOO094l4QbUzVNjuTDj6/uApzYxB0QAwtCJsIbvvEo11Yx1EFB",
+	"u9bpyj0nXFi5i05dKSvz9Td9k99gZ/3tpV2elTsqNqeZmi+MV0W6/E4t1ysQL6peFaHjCDeGYJZLAejo",
+	"hmK2HXoEXGncXy4yik1H0EsXRf2EMmsyz821ygSepHIJZW8Z5+k1bi6RUAosB01ytX5FTQ/Gqmo1QR6w",
+	"kFrqFirVMh8uSrswjhvNkfIhi1ZtS14ZfpOK6ggpvn118rJ2RdapKBXwQeAQZyrTVqW+DiudGFrYnviG",
+	"2mgQ6WvoLADsVdVO5GJC/UQ4fOjo4KBXyKV3mhpK1YswOoOOpo33ZXyLEPlTOqX6TW74uDKHcktMyFJY",
+	"tvI7SK4KMmt4wRQgS1lslvq5ljMMujOonbEYU6VKffQAxW+MHessU4UYCuk9EF6qJeOjYEXq2EYymvt0",
+	"IQlNoSVqwNIZyxiThfqtiLwOv6O8VarQoAv4ytQqxys8OPi4zK8NGUZW8MJK16Et8AKry9FsCuRDLyMX",
+	"BgY5KxCn552kDfP98pDPNOdEr496k7yyhcyZiFNEdrczOBQAWjB3XmHqtXn2Iexbf60PqW3D9IGGM9J+",
+	"czXxF5ViwthUGddojNXTWT0I83+aDbxYP7qSealIN/Dp7IITEIDfVTseIOA6SvgTTi++kFmmsi+TxiOA",
+	"TnzBfskvaa6F1LVWw40cOf6lMup8wZa6L/cEdTohHIOoGE28OVLFxstVgIkmDLEccohndklULma+kJia",
+	"h8k6tZlD3SyIqHjDsOyJn8i05E3VfUt6IcVcT9nSBhgeDP8W+HaJ5CkrU8b0oGZ54WaVztDhiNeTSZ8t",
+	"fvVGtnXSRjv1sEDKH3A+9JOaKWFy4Eaq1TiWWmZ8ZedmeNWX29bAtUGbN++UILb1AiLAuYTOGsAPegBv",
+	"XoC7gpxQly7NAq8owy5TaxxdF39thNMZmORe10ZnvgLNe4j2Ni4x/zyOBOckHv6qJGNVgXhMEzU3hFto",
+	"DXXWsxvRZfm0dk7A9018JzAUbNWr/LVSRb2xykdZqp+hD/Rjeh+vTU2bIzFEZ4osFNVPePyQTYwWEqR8",
+	"tTcyIFSLn9Fl7GFBcBTNu+w2cDt1szC2P185VA3p4XjwqZrl1fajpBHpjNdpbPyMzNd0r9hHwlZ+mK8s",
+	"spxl9kbIs55zr0rnjVUOSxiOJTjEFnHP6+JKFR7jJa0AJseMIqiQqrjS1hRzVfi1RU6RZ9KusKuJHjxw",
+	"vYl7r3D0PbhuV/o4m/lcDp2C1zE3iK2UwYDxnKCPU1dqRX0euCpa0pqEirtTJ1Wb6c5kWvzKbtnH/TBH",
+	"Pg8z4cOvRBFvGNCASDiSEElik+c98Z1Si0ZzxQJOPNSoDNiEvfUJg+Q4b2US6yypJ0i43Vv34gnUwc78",
+	"SRWpga0NSya49kSRAdURzlsl5w6PifB4oazIdaEaxaIqzx1csXCEF9I3Z01o9cbkiKupcg6+jIax6n5o",
+	"LFVXYGgS/LfPMsGJ0tumfH+NEHxDL30EhkXfQ+IYz3QzpI3dfjaaKMNJV6Zbynl+W8C6DZf4tClgrSTU",
+	"fraRtUTookxcyQvawGTo/vW3IonTfXlyM6kmr8oV/PVVzRQCeZBO/D9vfvybMFb8vyc/fF/JdqGipLaQ",
+	"3l0WuXKO+696idVC6SFdenJDkJmlpa+YAk6Is361rfSj58SvnZewI0w/qetrpWm6wNeDAwjPXbuYGYmF",
+	"JtPsXJSLPXEepRZWNUCacRLuUmNrZHFSt8ed5Dr1IVsxJMLXSZodthBakykuwtsiUymIx+J6JkM3NEdl",
+	"KMktw6cR6hZS7jRMUn8fwMuNqVzYbF6us6+1Y2Icw48sf4Jd1GtDQ8PtowvS7l04iMpvZFU1YRKOWeb5",
+	"hbEXwPjRpu3g0FbZ9un8A9h2p8W+o40xKK8mnEvzuNCz3zgwU6hjPF4s0m6ulMUOT3R8QS9HIwBj7aTO",
+	"+shi2xf7BinKR3LUEMtRga2gXk9otsSN6uMEEY5szQ5ob1/wa2+8lV5Nl11MkkaK6rDiXP0Gd1vBkCSq",
+	"mX90cFDb7whVkrg0Ha09FLDCOSoFtmvNTRTaLX7oPmNie9jeB/KpjxxUS0e+jiXR1alxNtNUuols+Zlp",
+	"2PDZSV2H1iALvXM36mao35h5zL9aqKuymLqh68pS0/2YwD1v9HtQDfJWbcBYNTbgE/lgK3agCy5kS6UB",
+	"ApEU+hMo3z+T0lNRrK9g65PuUwljCH9qGnz3dv1t0R65QYP0gASxG1bxi+gWqHkts8/aXp80WSk/uW5d",
+	"vCiuIsOQiqZAyMSbocP4vy1FQvhPzg37NlRKq6JdJTU5EbLgolUG3c4ezgf5AZKAig1QVMtxeJ+8Nyyp",
+	"cTxnqK69pKtWVYQJn4yEsc4XWDqjveTjiMFtlgEOjf+p6ht0rHrNuxCTApNnUTml3szN8Oo9GhzquFdc",
+	"NvZa4Ep1QlY7GdW8Ccj2klgkSo/7j+Z7rXjVeV/BNpoRogW3FjTaTbO3WkUorCdk44jvbiXRrPe/mlw6",
+	"z/4Q2UDC5lJO6h8DIqI9JUwxtCo1RYqv1+3RcIpCQVwHO0sxQiiamZsKbdir/Vm/VSlXF7ia22/TvZsb",
+	"wm3bqlBaHcon4f+bucxNSUF3Kgn3mwlgVTbts1X6W5XTO1e1gdg7JW06257Usyey4RiVbNgmJw9sktSF",
+	"E78mQk8Lg0pAKh0FgJGJGvV9NFJYNS1zacFyZpVDQxpyCaum6uYrb0tVOTeD2j9eNhL6Kd8BVgFX6fVq",
+	"0kLDhhO8q1O0d16q2HKxStLf4Lzbexu9uuHQC3iPFG1qeHH26mCbtTYu5p5ZqMLN9MTLxcJBd9+ei/rr",
+	"WmfdXN6ETsUHjx43OxdvVWARPCG/0nHBOQ514RTGml6pvnXFpccdbkufOoeL3zkL5P6SrrrSn1oFMhcS",
+	"WvNzPxMSrEL7/Coe/4EThbrxF3UoeWgljX4AuuHNsJZfE8KEpMt5wy4QTcHodex537bW3/3cszQa9Ss/",
+	"d7NrI0+gCOpV1w1ghSucQl+6AFGKP1i+wPM7DuwnMhtYzHiJAa2VMY34yiZvaR1S36wH0VvFYWfpnGsQ",
+	"RynW2+QRduYQ7mzB3KpIBAJYmaXXBF+mwVQRxjZMFp+4tASt4hOnKPM+rZRMOp1QBhXvHBLjsUJqEAoo",
+	"TRQ81z4qShpqKsEdP/gEC3lQd+KD/CusAIk7TFEOYVHdJTDqYP2YSl/pTGUdxRO2KIPx9fI0u4PLd++s",
+	"a01TvlbaUb0zfMfC7twiSW41Qe7T3779uy7dYOZj502htruGcMlC1ZbaQPMi9MVZFmktOmD3SK9yrPuJ",
+	"wlnV1atQGIp6g7YcqPuu05mYKu/E0ehoT1RAoS0vfC9y7mHlMODfB0diZkqLnIqF1XUVR3oLjbSS03oT",
+	"ov54nOruXSzRdnzKoiObMh+40sg65itdnRUXvdH0GtwB2fgfWq25JxlibjI9WW7Ih/hTzlmRcwg9d5Jz",
+	"xEnuTG19qcpWNIq8Yxgg0ue4TzCnjnL7yDrnwRTqeUhiu6hyAptpDnWuIPcQxk94QxYfhKJpU2YHfO2e",
+	"5yuJdaRzMG6ERgLBDXMVFjE3hfbGOgT+p7Pv/3iy3U+tRMy1TKpTzXooSz/bPjr1gWt05Aymj6rXImt5",
+	"gS9TbKHhflW0D8KqTKbe7QnsUVK3cK2xSzf6RyaNXvgr7YcT1GlBvQUj+SDpkVeh3d4fQV4FOCuz+9oW",
+	"93GM23MhC8rVFQaZW3A9iJkERCrUJ+2EHwovbzzrz4WEf3atnjHQrTBhQ7k3vDdYdUBassMX3Y2cg4Oh",
+	"3Yp/q2ytiFRwJYKdqEXchSuUFEA+5ZKGI/YYfBKCpPUg8xMNqbkJ/h1aPSE3WEZEBL10B6MwfSXs95OE",
+	"b3k9fwCqwKCuDY6hfeJDalCHz+NWdeKkW4F6Z7TEMu39sa9v5Fytr90BTOz3twOcJ3s7OBaohOwJqJcd",
+	"SsnAI6ZYoVST9nWZrVUkg5c/seL3CW0rKNhEm/ZH04VOxLz0OLOACit1J87P7E59jspGXJD+ltoGhlS3",
+	"A0cqqTJO4QIU++PJ7a+ps8SulI5cPf0hXaew9oz3L1Pjcgrxx8/ZRdQIgmr2lOsNgjrjL/4BWCSDutFT",
+	"eUbCSNiTPwifjOWoAHojQGgNMvU1gTuhnAlUnai7JoY8YZAfupAq2arr66GGEdvDWNpKdumrsIp4Z6pK",
+	"GaDzvCvMu6dwcQLyU1ZxIQj6OTE9r/pD/k+vnrzhup3VBeRM6dGGVIukcCl2JtYPq8l7iPar0K6E7nId",
+	"/5easmCvuyzQV5EvBc+WiLmyU3yIubWZ1FiJQvEdPnrKzg1McbJmsVAZP3o2EplcUuaUvJI6l2Oda79k",
+	"Vz2WPQn6JeV/MIVpl8pp0YNK3xLfQ0SWr0ITHJW3Ish36KW8gVS8ofl+U3fMqDojQifSUkq0N2Ehv6m+",
+	"KrT7B1ha8gmELXMt2mcjLr3H5ydSc6W40EkQIBbKapPhMagC62Nfz0yu+HcXcr3akaAHR7PeKr26yMx1",
+	"s/RVFZf2JNu2qi0DFgj+uEwvld8T3xJG0p8t51qFfxAn1YQXfscxBB0yknIBT8JLZOvN5LKuxNofd+ZM",
+	"Xu7UOzmQ7OrF9x9LNnnDlKBLeadHDWnkgQsX6NMRbToj7hnFG/YZku2KFsRkZ2v5qJ9+z+/WroChkO+h",
+	"BJ4r52xZqBM02UhKw7e0MuBM/7PNDHROf9oZ/rQz/HeM3jqrWvVF1rQ+IhbqAffKmm/KcfXn7eQwFBtJ",
+	"Q+QxGAkd/Ldcmh6OZKqvVNFtWvg5wHmPlCN8Y6tueLxxwsX706eKdw6OjqQ6hX7NG4tOV+Fm6koVSP6h",
+	"Kg9Vywn1YFHA5KW8gmGJYF9ZHXUegfHAsR+2ry06T3VPRZl59k+kDvPX+7nFz82DG3/eXfbeBCiFrFDO",
+	"c+ErkeuJSpdprgh5etAvpgkPf+d/bRdcXSPKbkIFv7d7b7xwOJ9Ja7wATq/I+VPhVg+ojwr0RdLe7y6P",
+	"Pt7VOu+hi5/l0VFYZxe4nQE0TXpe+r4ozzs/zM+DQI8+PoH+s9PbdohcN0rrQuYenvC++nk1JY6R2gmr",
+	"csmlYefKW526umB/yE2jv1dNRm9mWI01q2w+IERGcd1RpXAI82jNGDV1W536jMGqEszY4kaCp5mgBXRm",
+	"0MPFtUGTKuMUZamy0L79Re503fW5qXY+VL6l4puoZ5DjpK5ZFWKCQTabMz/mT9DYrm1qyOIdnL0w0PiH",
+	"kCuasDrLLnjByB+MSCEQBqt7hfljyKCfSFcZxNenUDLTkdmk9GZOG5ByqD6eJ/tgS6fEj6cvX0SzLjS8",
+	"PHj/7v3/GQBiAMWeeXQBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,663 @@
+//go:build go1.22
+
+// Package v2 provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.5.0 DO NOT EDIT.
+package v2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oapi-codegen/runtime"
+	strictnethttp "github.com/oapi-codegen/runtime/strictmiddleware/nethttp"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// Defines values for ProbeModuleSchema.
+const (
+	Dns     ProbeModuleSchema = "dns"
+	Http2xx ProbeModuleSchema = "http_2xx"
+	Icmp    ProbeModuleSchema = "icmp"
+	Tcp     ProbeModuleSchema = "tcp"
+)
+
+// Defines values for ProbeTargetState.
+const (
+	Down ProbeTargetState = "down"
+	Up   ProbeTargetState = "up"
+)
+
+// Defines values for StatusSchema.
+const (
+	Active      StatusSchema = "active"
+	Deleted     StatusSchema = "deleted"
+	Failed      StatusSchema = "failed"
+	Pending     StatusSchema = "pending"
+	Terminating StatusSchema = "terminating"
+)
+
+// DurationSchema A positive duration such as "30s", "1m30s" or "500ms".
+type DurationSchema = string
+
+// ErrorObject defines model for ErrorObject.
+type ErrorObject struct {
+	// Message A human-readable error message.
+	Message string `json:"message"`
+
+	// RetryAfterSeconds Suggested number of seconds to wait before retrying. Set on retryable errors (409, 429, 503) and always equal to the Retry-After response header.
+	RetryAfterSeconds *int `json:"retry_after_seconds,omitempty"`
+}
+
+// ErrorResponse defines model for ErrorResponse.
+type ErrorResponse struct {
+	Error ErrorObject `json:"error"`
+}
+
+// LabelsSchema A set of key-value pairs that can be used to organize and select probes.
+type LabelsSchema map[string]string
+
+// ProbeIdSchema The unique identifier of a probe (UUID format).
+type ProbeIdSchema = openapi_types.UUID
+
+// ProbeModuleSchema The blackbox exporter module the probe is run with.
+type ProbeModuleSchema string
+
+// ProbeObject Represents a single probe configuration.
+type ProbeObject struct {
+	// CreationTimestamp When the probe was created.
+	CreationTimestamp *time.Time `json:"creation_timestamp,omitempty"`
+
+	// Generation Incremented by the server whenever the probe's spec changes.
+	Generation *int64 `json:"generation,omitempty"`
+
+	// Id The unique identifier of a probe (UUID format).
+	Id ProbeIdSchema `json:"id"`
+
+	// Interval A positive duration such as "30s", "1m30s" or "500ms".
+	Interval *DurationSchema `json:"interval,omitempty"`
+
+	// Labels A set of key-value pairs that can be used to organize and select probes.
+	Labels *LabelsSchema `json:"labels,omitempty"`
+
+	// Module The blackbox exporter module the probe is run with.
+	Module *ProbeModuleSchema `json:"module,omitempty"`
+
+	// Paused Whether agents leave the probe alone; absent when it is not paused.
+	Paused *bool `json:"paused,omitempty"`
+
+	// Status The current status of the probe.
+	Status StatusSchema `json:"status"`
+
+	// Targets The endpoints the probe checks together, each with the probe's labels and settings. The first one is the probe's primary URL, which cannot change.
+	Targets []ProbeTarget `json:"targets"`
+
+	// Timeout A positive duration such as "30s", "1m30s" or "500ms".
+	Timeout *DurationSchema `json:"timeout,omitempty"`
+
+	// UpdateTimestamp When the probe was last changed.
+	UpdateTimestamp *time.Time `json:"update_timestamp,omitempty"`
+}
+
+// ProbeTarget An endpoint the probe checks, with the outcome of its last check once reported.
+type ProbeTarget struct {
+	// Message Human-readable details of the outcome, typically why the URL is down.
+	Message *string `json:"message,omitempty"`
+
+	// State Whether the last check of the URL succeeded; absent until the agent reports it.
+	State *ProbeTargetState `json:"state,omitempty"`
+
+	// Timestamp When the URL was last checked.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Url The URL to be probed.
+	Url string `json:"url"`
+}
+
+// ProbeTargetState Whether the last check of the URL succeeded; absent until the agent reports it.
+type ProbeTargetState string
+
+// ProbesArrayResponse defines model for ProbesArrayResponse.
+type ProbesArrayResponse struct {
+	// NextPageToken Opaque token to pass as page_token to fetch the next page. Absent on the last page.
+	NextPageToken *string       `json:"next_page_token,omitempty"`
+	Probes        []ProbeObject `json:"probes"`
+}
+
+// StatusSchema The current status of the probe.
+type StatusSchema string
+
+// WarningObject defines model for WarningObject.
+type WarningObject struct {
+	// Message A human-readable error message indicating the resource was not found.
+	Message string `json:"message"`
+}
+
+// WarningResponse defines model for WarningResponse.
+type WarningResponse struct {
+	Warning WarningObject `json:"warning"`
+}
+
+// LabelSelectorQueryParam defines model for LabelSelectorQueryParam.
+type LabelSelectorQueryParam = string
+
+// LimitQueryParam defines model for LimitQueryParam.
+type LimitQueryParam = int
+
+// PageTokenQueryParam defines model for PageTokenQueryParam.
+type PageTokenQueryParam = string
+
+// ProbeIdPathParam The unique identifier of a probe (UUID format).
+type ProbeIdPathParam = ProbeIdSchema
+
+// ListProbesParams defines parameters for ListProbes.
+type ListProbesParams struct {
+	// LabelSelector A comma-separated list of key=value labels to filter on.
+	LabelSelector *LabelSelectorQueryParam `form:"label_selector,omitempty" json:"label_selector,omitempty"`
+
+	// Limit Maximum number of probes to return. When more match, the response carries a next_page_token.
+	Limit *LimitQueryParam `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// PageToken Opaque token from a previous response's next_page_token. It is only valid with the same label_selector and tenant it was issued for.
+	PageToken *PageTokenQueryParam `form:"page_token,omitempty" json:"page_token,omitempty"`
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get a list of all configured probes
+	// (GET /probes)
+	ListProbes(w http.ResponseWriter, r *http.Request, params ListProbesParams)
+	// Get a probe by its ID
+	// (GET /probes/{probe_id})
+	GetProbeById(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListProbes operation middleware
+func (siw *ServerInterfaceWrapper) ListProbes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListProbesParams
+
+	// ------------- Optional query parameter "label_selector" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label_selector", r.URL.Query(), &params.LabelSelector)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label_selector", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProbes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProbeById operation middleware
+func (siw *ServerInterfaceWrapper) GetProbeById(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "probe_id" -------------
+	var probeId ProbeIdPathParam
+
+	err = runtime.BindStyledParameterWithOptions("simple", "probe_id", r.PathValue("probe_id"), &probeId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "probe_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProbeById(w, r, probeId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/probes", wrapper.ListProbes)
+	m.HandleFunc("GET "+options.BaseURL+"/probes/{probe_id}", wrapper.GetProbeById)
+
+	return m
+}
+
+type ListProbesRequestObject struct {
+	Params ListProbesParams
+}
+
+type ListProbesResponseObject interface {
+	VisitListProbesResponse(w http.ResponseWriter) error
+}
+
+type ListProbes200JSONResponse ProbesArrayResponse
+
+func (response ListProbes200JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbes400JSONResponse ErrorResponse
+
+func (response ListProbes400JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProbes413JSONResponse ErrorResponse
+
+func (response ListProbes413JSONResponse) VisitListProbesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(413)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeByIdRequestObject struct {
+	ProbeId ProbeIdPathParam `json:"probe_id"`
+}
+
+type GetProbeByIdResponseObject interface {
+	VisitGetProbeByIdResponse(w http.ResponseWriter) error
+}
+
+type GetProbeById200JSONResponse ProbeObject
+
+func (response GetProbeById200JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeById404JSONResponse WarningResponse
+
+func (response GetProbeById404JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProbeById410JSONResponse WarningResponse
+
+func (response GetProbeById410JSONResponse) VisitGetProbeByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(410)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// StrictServerInterface represents all server handlers.
+type StrictServerInterface interface {
+	// Get a list of all configured probes
+	// (GET /probes)
+	ListProbes(ctx context.Context, request ListProbesRequestObject) (ListProbesResponseObject, error)
+	// Get a probe by its ID
+	// (GET /probes/{probe_id})
+	GetProbeById(ctx context.Context, request GetProbeByIdRequestObject) (GetProbeByIdResponseObject, error)
+}
+
+type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
+type StrictMiddlewareFunc = strictnethttp.StrictHTTPMiddlewareFunc
+
+type StrictHTTPServerOptions struct {
+	RequestErrorHandlerFunc  func(w http.ResponseWriter, r *http.Request, err error)
+	ResponseErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func NewStrictHandler(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: StrictHTTPServerOptions{
+		RequestErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+		ResponseErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		},
+	}}
+}
+
+func NewStrictHandlerWithOptions(ssi StrictServerInterface, middlewares []StrictMiddlewareFunc, options StrictHTTPServerOptions) ServerInterface {
+	return &strictHandler{ssi: ssi, middlewares: middlewares, options: options}
+}
+
+type strictHandler struct {
+	ssi         StrictServerInterface
+	middlewares []StrictMiddlewareFunc
+	options     StrictHTTPServerOptions
+}
+
+// ListProbes operation middleware
+func (sh *strictHandler) ListProbes(w http.ResponseWriter, r *http.Request, params ListProbesParams) {
+	var request ListProbesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ListProbes(ctx, request.(ListProbesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListProbes")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ListProbesResponseObject); ok {
+		if err := validResponse.VisitListProbesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetProbeById operation middleware
+func (sh *strictHandler) GetProbeById(w http.ResponseWriter, r *http.Request, probeId ProbeIdPathParam) {
+	var request GetProbeByIdRequestObject
+
+	request.ProbeId = probeId
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProbeById(ctx, request.(GetProbeByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProbeById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProbeByIdResponseObject); ok {
+		if err := validResponse.VisitGetProbeByIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/7xZfW8bN9L/KgM+BZKgK3llO2mjonjgng+tgBT22c4VuNhnUOSslvUuuSG5knWpvvth",
+	"yF2tVi+xU+TqP2JFJIe/mfnNC8efmDBlZTRq79j4E6u45SV6tOF/7/gUi2ssUHhj/1GjXV7SOi1JdMKq",
+	"yiuj2ZidgTBlyQcOSYBHCYVyHkwGD7j8cc6LGqEgYQ68gUwVHi0YPWQJw0deVgWyMRNF7TzaeyV/lMdv",
+	"02yEOHgjXp8OTqfpaPA2xTcD+V06+u70+yz9/vUoqayac48/elsjS5giIB8JJEuY5iWJDHfeu0YDljAn",
+	"ciw5KeCXFe1w3io9Y6tVwt6pUvnPafkrf1RlXYKuyynhz6CyZopBJ4u+tnoIv+WooTQWoeRe5An4HMGi",
+	"q4x2CIJbq9ABB42P/r7iM7z35gH7lhil6QF1CGFPi1JpgsTGo6TVSGmPM7RBpUs+wxuS/zm1Lir+sUYI",
+	"OCCzpgQOlcW5MrVbQ3/hdiDDxINyYHSxhDkvlISF8nlQ2PESoW984FqCR821B+VhwR0o52qUkBk7POC/",
+	"7rYnfHdJjpjIS+7zA1re5AiTc3IaAQyOa/xmFc6xT8Xn8K+FXHGfbyAmwfdKsoRZ/Fgri5KNiaGb+L+x",
+	"mLEx+7+jLviO4qo7ajS5jptXpFyzRCfPa8tJoeu1rO1ArIxTXs0RZLMVXC1y4A5u2UnqblkCt2xUho9g",
+	"LNyy12laulvWt8BJ6lhCunm0JPjfLz+kg7d33768vR3GT6/+/2Xp/nB/lH/kr159+w1Ltt2SsL9ba+zF",
+	"9HcUPiQXayq0XmFQpUTn+Az36ZDXJdcDi1zyaYGAJAaa/X2YEx2ZF7gGa65lxpbc74NE/l7e84wSjUNh",
+	"tHS7CK7r2QwdpbEu1JvNxJkFVx6mmFGUB3lKz4ZwjR6Mjl90sB28PE3fJnB6/DaB1+nJqxAHvFjwpQP8",
+	"WPOCJBIlr+jg4IyQdQkjRy7R9nR+vTfSO7J9WFv2br3TRB+0LrlqxO86JWB+iqKbbt2+OwrYd3MoJq4j",
+	"LpdSkb15cdmDsOOybXY4bCvLIFaWiivrwOfcg+Aapgi1Q0l2NXbGtfoPBqNHdjQ5u2fSTxu15/nR39Qf",
+	"NmahAq326NwP5r05qdaKsq+SqL3KVCQbbxLUy/fvJ+cNm1/9qRQVz7Ixq+uQlHasGyD+amRd4OdgTgsu",
+	"HqbmEfCxMtajhTKc2cimyoGtdSgCAaqmuvSB5d5X98ePj3S5qChripJ+Se3Y3aZGmxv3ouwySR/fFVYW",
+	"HVEUODilZ0ULSRidqVmTCIfBZ5tUExbDyr1XJTrPy2pXeCjonZJUt8IxlMNN80rucUBiQuLn8kIXyzbx",
+	"72gzQ40R0+59Ey0slqgp+0yX4WaHdo4WFjlqpA9rNC8cuAoFiJzrGboeIKX9m9PDYNa5I2FKPhXwWzUp",
+	"CaftnBdPHdwqV6skdmTuqXO9XLFKWOTas2D2qLyiEkbJYK9ffY4W+CwQp0A+3yQzL4zGH4BPiVfB9KBC",
+	"u6ONhyhz2BF1akyBXNN9znNfP6ngddjVofTcztC7/bGHWlZGEcoOn8hRPFA1mgU1EkAu8q4Ba+nRNN0x",
+	"/Xmv9MwNgWRmyjqqVyFsN09UVpXcLuH91bsEFrkSOSVV0jqyLLRqHkv3LG/cBL2CC5WexGNdq8qt5cug",
+	"virR1P7L6VRXFHhfGL8Fd602fzqIt8pezK2NE9ckuDtUEhqz7LY+eu3sHV8nnXdN7YUpkSqF8mt9UDyA",
+	"0QLBYkjRcjfhHey5ful3XBI9V4Vru+XmvgT8slKCF8USFnnMTe+v3hGDpFlsveakotZGVHCcngzT4Wh0",
+	"MvxufHp6MgZ1ZKB1+J7kSLbDw/FKl24qnK1huFoIRIlyHba19qoI6yHKG8M4UH6zQNWhGpmF7pejutqH",
+	"7jlMIzAbPEPxEH3RyT5Oj98M0pNBOroZHY/TdJym/zpExB0ItS325wm61xuYNrzZupPKqxsfHfFKDZtv",
+	"B03bM8yMGUqcu1xlfmjsrNc42II9RX/ac5Ds7ozC/HDPufWufOKB6g1U3DngDroz9G2GXsTwIIFhcQhn",
+	"kQhGd7ypmkfEjl1jY0jXPz+/XaxV7ae0Lfs0oveZqFcJ9rpV1NaSEjGp9F6wmzSuUEvSJGFc0AOQfMhV",
+	"gSEzoS2V5j6uSyzQo+zTfX1oxy6/cauVnn3ldxwoLZUIkNoZiamtiBmayk1mar1F4cuYwikNTs7hxWPz",
+	"M9jzT/vzopP1JIs/925qjHCYxYu44SnW9I25jaAVsotgFbquzOya+Z9oHT3zj1tmnF1O6MkAJdd8Rub9",
+	"qe3dYzgmsaGUUGuJFighHM2P23FO/FIY7bytBd0xbsdcObVIfD3aa8odKO08chnfLU37TVxV4r62RRKa",
+	"jzAkim6mnVXT/jrgFoHPuSoCQ5yBjNthAzQsupxbbEZL80bV0Q9BVgtg48HUtOa0TIIthgLZoYHMFIVZ",
+	"xN6alrpXKC2HFtorH9h29cvFT9dwvdQ+R6+Ea1GdXU5YwhoslMyH6TAd8KLKQ0tiKtS8UjRDGY6GozhF",
+	"yQNHjroU05T/tSUmko3ZO+V8vIQlvVHsh/2k6rYcHRrVrpKnj27NPZ9xZN9ccXVHXI7hEVQ8TlP6JYz2",
+	"qIO2vKqKEPNGH/3u4uvnC6ZiW4UkxMR2ymnJyYti/fpDuX70rxJ2+hVh9acpewC18ykKcgzFp7VhAkqL",
+	"oqaUDbxXymiOQUmQugxcc9/YjXkpcJAqyzDUhTAzjaqNTv461W5i0q4LD/goEGV8R3QTs1BFw3fUMiIl",
+	"pCVYFKjm+ANobq1ZNGW5N7wzNpgjqk3ppqR+jQC4uqS3CRuzn9ED/7yzQ0M+c5vll2Q0MXj0qZ3Urg6G",
+	"488Yo/Gn5UR+cUDuTKT/9/FxsVEr+r7625Zx4h8n2tJbWTNXEiVMzpsIOf1qwLbr5h5wsapvlPzA5PSv",
+	"hHDTex9aLA3VR4sCtS+W+8kX9zdlZHK+l250LAxvImVC586aghsI0RzZ7XjXBdJiESuagRK9pTq05vfm",
+	"3xwcW92t/jsAmPRdQM8bAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	res := make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	resolvePath := PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		pathToFile := url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
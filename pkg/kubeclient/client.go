@@ -2,9 +2,11 @@ package kubeclient
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -33,6 +35,11 @@ func NewClient(cfg Config) (*Client, error) {
 
 	config.QPS = 100
 	config.Burst = 100
+	// Record API server calls as client spans; without a configured tracer
+	// provider this is a no-op.
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(rt)
+	})
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -105,7 +112,7 @@ func createConfig(kubeconfigPath string) (*rest.Config, bool, error) {
 	}
 
 	// If in-cluster fails, try to use kubeconfig from default locations
-	log.Printf("Could not create in-cluster config: %v. Trying to use kubeconfig.", err)
+	slog.Info("Could not create in-cluster config, trying kubeconfig", "error", err)
 	config, err = clientcmd.BuildConfigFromFlags("", "")
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to create kubernetes client config from kubeconfig: %w", err)
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// runtimeSnapshot is the state reported by /debug/vars.
+type runtimeSnapshot struct {
+	Goroutines int            `json:"goroutines"`
+	GOMAXPROCS int            `json:"gomaxprocs"`
+	Uptime     string         `json:"uptime"`
+	Heap       heapSnapshot   `json:"heap"`
+	Caches     map[string]int `json:"caches"`
+}
+
+type heapSnapshot struct {
+	AllocBytes    uint64 `json:"alloc_bytes"`
+	InuseBytes    uint64 `json:"inuse_bytes"`
+	SysBytes      uint64 `json:"sys_bytes"`
+	Objects       uint64 `json:"objects"`
+	GCCycles      uint32 `json:"gc_cycles"`
+	LastGCPauseNs uint64 `json:"last_gc_pause_ns"`
+}
+
+// debugHandler serves the Go profiler under /debug/pprof/ and a snapshot of
+// the runtime and of the in-memory caches, as counted by caches, under
+// /debug/vars, to diagnose memory growth in long-running replicas. When
+// token is set, requests must carry it as a bearer token.
+func debugHandler(token string, caches func() map[string]int) http.Handler {
+	started := time.Now()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("GET /debug/vars", func(w http.ResponseWriter, r *http.Request) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+		snapshot := runtimeSnapshot{
+			Goroutines: runtime.NumGoroutine(),
+			GOMAXPROCS: runtime.GOMAXPROCS(0),
+			Uptime:     time.Since(started).Round(time.Second).String(),
+			Heap: heapSnapshot{
+				AllocBytes:    mem.HeapAlloc,
+				InuseBytes:    mem.HeapInuse,
+				SysBytes:      mem.Sys,
+				Objects:       mem.HeapObjects,
+				GCCycles:      mem.NumGC,
+				LastGCPauseNs: mem.PauseNs[(mem.NumGC+255)%256],
+			},
+			Caches: caches(),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(snapshot)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && !adminAuthorized(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "a valid admin token is required", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugEndpoints(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	get := func(srv *Server, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		srv, err := New(Config{Store: probestore.NewIndexedProbeStore(store)})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, get(srv, "/debug/vars", "").Code)
+	})
+
+	srv, err := New(Config{Store: probestore.NewIndexedProbeStore(store), Profiling: true, AdminToken: "secret"})
+	require.NoError(t, err)
+
+	t.Run("requires the admin token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get(srv, "/debug/vars", "").Code)
+		assert.Equal(t, http.StatusUnauthorized, get(srv, "/debug/pprof/", "wrong").Code)
+	})
+
+	t.Run("runtime snapshot", func(t *testing.T) {
+		w := get(srv, "/debug/vars", "secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		var snapshot runtimeSnapshot
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+		assert.Positive(t, snapshot.Goroutines)
+		assert.Positive(t, snapshot.Heap.AllocBytes)
+		assert.Equal(t, map[string]int{"probe_results": 0, "audit_entries": 0, "idempotency_keys": 0, "url_hash_index": 0, "api_keys": 0}, snapshot.Caches)
+	})
+
+	t.Run("profiler", func(t *testing.T) {
+		w := get(srv, "/debug/pprof/goroutine?debug=1", "secret")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "goroutine profile")
+	})
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+)
+
+// role is who a caller is, as far as the documentation served to it goes.
+type role string
+
+const (
+	roleClient role = "client"
+	roleAgent  role = "agent"
+	roleAdmin  role = "admin"
+)
+
+// agentOperations are the operations agents call: registering, fetching the
+// probes to run and their credentials, and reporting their status and
+// results.
+var agentOperations = []string{
+	"registerAgent", "createAgentCredential", "listAgentProbes",
+	"listProbes", "getProbeById", "getProbeAuth", "updateProbe", "updateProbeStatuses", "reportProbeResult",
+}
+
+// adminOperations are the operations reserved to operators, which are only
+// documented to callers presenting the admin token.
+var adminOperations = []string{
+	"createAgentBootstrapToken", "revokeAgentCredentials",
+	"listWebhooks", "createWebhook", "getWebhook", "updateWebhook", "deleteWebhook",
+	"listAPIKeys", "createAPIKey", "deleteAPIKey",
+}
+
+// allows reports whether the documentation served to r covers the operation.
+// The embedded spec capitalizes operation IDs, so they are matched ignoring
+// case.
+func (r role) allows(operationID string) bool {
+	listed := func(ids []string) bool {
+		return slices.ContainsFunc(ids, func(id string) bool { return strings.EqualFold(id, operationID) })
+	}
+	switch r {
+	case roleAdmin:
+		return true
+	case roleAgent:
+		return listed(agentOperations)
+	default:
+		return !listed(adminOperations)
+	}
+}
+
+// docsPolicy tells the roles of callers apart: operators present the admin
+// token, agents their credential, and everyone else is a client. Without an
+// admin token no caller is an admin, and without an issuer no caller is an
+// agent.
+type docsPolicy struct {
+	adminToken string
+	agentAuth  *agentauth.Issuer
+}
+
+// role returns the role of the caller of r.
+func (p docsPolicy) role(r *http.Request) role {
+	if p.adminToken != "" && adminAuthorized(r, p.adminToken) {
+		return roleAdmin
+	}
+	if p.agentAuth != nil {
+		if _, err := p.agentAuth.Verify(agentauth.BearerToken(r)); err == nil {
+			return roleAgent
+		}
+	}
+	return roleClient
+}
+
+// scopedSpecs renders the spec for each role, leaving out the operations the
+// role does not cover and the tags and components only they refer to.
+func scopedSpecs(swagger *openapi3.T) (map[role][]byte, error) {
+	full, err := swagger.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal swagger spec: %w", err)
+	}
+	specs := map[role][]byte{roleAdmin: full}
+	for _, r := range []role{roleClient, roleAgent} {
+		var spec map[string]any
+		if err := json.Unmarshal(full, &spec); err != nil {
+			return nil, fmt.Errorf("failed to decode swagger spec: %w", err)
+		}
+		scope(spec, r)
+		if specs[r], err = json.Marshal(spec); err != nil {
+			return nil, fmt.Errorf("failed to marshal swagger spec: %w", err)
+		}
+	}
+	return specs, nil
+}
+
+// httpMethods are the keys of a path item holding operations.
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// scope removes from spec what r does not cover.
+func scope(spec map[string]any, r role) {
+	paths, _ := spec["paths"].(map[string]any)
+	usedTags := map[string]bool{}
+	for path, item := range paths {
+		item, _ := item.(map[string]any)
+		remaining := 0
+		for _, method := range httpMethods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if id, _ := op["operationId"].(string); !r.allows(id) {
+				delete(item, method)
+				continue
+			}
+			remaining++
+			tags, _ := op["tags"].([]any)
+			for _, tag := range tags {
+				if name, ok := tag.(string); ok {
+					usedTags[name] = true
+				}
+			}
+		}
+		if remaining == 0 {
+			delete(paths, path)
+		}
+	}
+
+	if tags, ok := spec["tags"].([]any); ok {
+		spec["tags"] = slices.DeleteFunc(tags, func(tag any) bool {
+			name, _ := tag.(map[string]any)["name"].(string)
+			return !usedTags[name]
+		})
+	}
+
+	// Components are kept while something kept refers to them, directly or
+	// through other components. Security schemes are named rather than
+	// referred to, so they are left alone.
+	components, _ := spec["components"].(map[string]any)
+	if components == nil {
+		return
+	}
+	rest := make(map[string]any, len(spec))
+	for key, value := range spec {
+		if key != "components" {
+			rest[key] = value
+		}
+	}
+	used := map[string]bool{}
+	pending := refs(rest, nil)
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if used[ref] {
+			continue
+		}
+		used[ref] = true
+		kind, name, ok := strings.Cut(strings.TrimPrefix(ref, "#/components/"), "/")
+		if !ok {
+			continue
+		}
+		if section, ok := components[kind].(map[string]any); ok {
+			pending = refs(section[name], pending)
+		}
+	}
+	for kind, section := range components {
+		section, ok := section.(map[string]any)
+		if !ok || kind == "securitySchemes" {
+			continue
+		}
+		for name := range section {
+			if !used["#/components/"+kind+"/"+name] {
+				delete(section, name)
+			}
+		}
+	}
+}
+
+// refs appends the component references found in value to found.
+func refs(value any, found []string) []string {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, v := range value {
+			if ref, ok := v.(string); ok && key == "$ref" && strings.HasPrefix(ref, "#/components/") {
+				found = append(found, ref)
+				continue
+			}
+			found = refs(v, found)
+		}
+	case []any:
+		for _, v := range value {
+			found = refs(v, found)
+		}
+	}
+	return found
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocsByRole(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	key := []byte("0123456789abcdef0123456789abcdef")
+	srv, err := New(Config{Store: store, AdminToken: "secret", AgentCredentialKey: key})
+	require.NoError(t, err)
+	bootstrap, err := srv.api.AgentAuth.MintBootstrap("agent-1", 0)
+	require.NoError(t, err)
+	credential, err := srv.api.AgentAuth.Exchange(context.Background(), bootstrap.Token, "agent-1")
+	require.NoError(t, err)
+
+	type spec struct {
+		Paths      map[string]map[string]any `json:"paths"`
+		Tags       []struct{ Name string }   `json:"tags"`
+		Components struct {
+			Schemas map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	get := func(token string) spec {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/v1/openapi.json", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		require.Equal(t, 200, w.Code)
+		assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+		var s spec
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &s))
+		return s
+	}
+
+	admin := get("secret")
+	assert.Contains(t, admin.Paths, "/webhooks")
+	assert.Contains(t, admin.Paths, "/agent-bootstrap-tokens")
+	assert.Contains(t, admin.Paths, "/apikeys")
+	assert.Contains(t, admin.Components.Schemas, "WebhookObject")
+
+	for _, token := range []string{"", "wrong"} {
+		client := get(token)
+		assert.NotContains(t, client.Paths, "/webhooks", "admin routes are not advertised")
+		assert.NotContains(t, client.Paths, "/agent-bootstrap-tokens")
+		assert.NotContains(t, client.Paths, "/apikeys")
+		assert.NotContains(t, client.Components.Schemas, "WebhookObject", "schemas only admin routes use are left out")
+		assert.Contains(t, client.Paths, "/probes/export")
+		assert.Contains(t, client.Components.Schemas, "ProbeObject")
+		for _, tag := range client.Tags {
+			assert.NotEqual(t, "webhooks", tag.Name)
+		}
+	}
+
+	agent := get(credential.Credential)
+	assert.Contains(t, agent.Paths, "/probes/{probe_id}/results")
+	assert.Contains(t, agent.Paths["/probes/{probe_id}/results"], "post")
+	assert.NotContains(t, agent.Paths["/probes/{probe_id}/results"], "get", "agents only report results")
+	assert.Contains(t, agent.Paths["/probes/{probe_id}"], "patch")
+	assert.NotContains(t, agent.Paths["/probes/{probe_id}"], "delete")
+	assert.Contains(t, agent.Paths, "/agents/{agent_id}/probes")
+	assert.NotContains(t, agent.Paths, "/probes/export")
+	assert.NotContains(t, agent.Paths, "/audit")
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// drainer takes a replica out of rotation ahead of a shutdown, or on an
+// operator's request: while draining, /readyz fails so load balancers stop
+// sending new requests, and every response closes its connection so clients
+// holding keep-alive connections reconnect to another replica. Requests still
+// arriving are served as usual.
+type drainer struct {
+	// token authorizes /admin/drain; the endpoint is disabled when empty.
+	token    string
+	draining atomic.Bool
+}
+
+// handler serves /admin/drain and reports draining in /readyz, passing other
+// requests on to next.
+func (d *drainer) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/drain" && d.token != "" {
+			d.serveAdmin(w, r)
+			return
+		}
+		if !d.draining.Load() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Connection", "close")
+		if r.URL.Path == "/readyz" {
+			if r.URL.Query().Get("format") == "json" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"status":"draining","checks":[]}` + "\n"))
+				return
+			}
+			http.Error(w, "not ready: draining", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveAdmin lets operators drain the replica with POST /admin/drain, undo it
+// with DELETE and check it with GET, given the admin token as a bearer token.
+func (d *drainer) serveAdmin(w http.ResponseWriter, r *http.Request) {
+	if !adminAuthorized(r, d.token) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "a valid admin token is required", http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !d.draining.Swap(true) {
+			slog.InfoContext(r.Context(), "Draining on request, /readyz now fails")
+		}
+	case http.MethodDelete:
+		if d.draining.Swap(false) {
+			slog.InfoContext(r.Context(), "Stopped draining on request, /readyz checks the backends again")
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"draining":%t}`+"\n", d.draining.Load())
+}
+
+// adminAuthorized reports whether r carries token as a bearer token.
+func adminAuthorized(r *http.Request, token string) bool {
+	scheme, bearer, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	return strings.EqualFold(scheme, "Bearer") && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+// drain starts draining and waits for delay, so load balancers notice the
+// failing /readyz before the listener closes. An operator having drained the
+// replica already does not shorten the wait.
+func (d *drainer) drain(delay time.Duration) {
+	d.draining.Store(true)
+	if delay <= 0 {
+		return
+	}
+	slog.Info("Draining before shutdown", "delay", delay)
+	time.Sleep(delay)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrain(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store, AdminToken: "secret"})
+	require.NoError(t, err)
+
+	do := func(method, target, token string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, do("GET", "/readyz", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do("POST", "/admin/drain", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do("POST", "/admin/drain", "wrong").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do("PUT", "/admin/drain", "secret").Code)
+
+	w := do("POST", "/admin/drain", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"draining":true}`, w.Body.String())
+
+	w = do("GET", "/readyz", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "not ready: draining\n", w.Body.String())
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+	w = do("GET", "/readyz?format=json", "")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"draining","checks":[]}`, w.Body.String())
+
+	w = do("GET", "/probes", "")
+	assert.Equal(t, http.StatusOK, w.Code, "requests are still served")
+	assert.Equal(t, "close", w.Header().Get("Connection"), "clients reconnect elsewhere")
+	assert.JSONEq(t, `{"draining":true}`, do("GET", "/admin/drain", "secret").Body.String())
+
+	assert.JSONEq(t, `{"draining":false}`, do("DELETE", "/admin/drain", "secret").Body.String())
+	assert.Equal(t, http.StatusOK, do("GET", "/readyz", "").Code)
+	assert.Empty(t, do("GET", "/readyz", "").Header().Get("Connection"))
+
+	t.Run("disabled without a token", func(t *testing.T) {
+		srv, err := New(Config{Store: store})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest("POST", "/admin/drain", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestServer_RunDrains(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Addr: "127.0.0.1:0", Store: store, DrainDelay: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	cancel()
+	require.Eventually(t, srv.drainer.draining.Load, time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("Run returned before the drain delay")
+	case <-time.After(50 * time.Millisecond):
+	}
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the drain delay")
+	}
+}
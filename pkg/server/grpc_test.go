@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/grpcapi"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1/probespb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCAPI(t *testing.T) {
+	local, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: probestore.NewIndexedProbeStore(local)})
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpcapi.NewGRPCServer(srv.grpcAPI)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := probespb.NewProbesClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watch, err := client.Watch(ctx, &probespb.WatchRequest{LabelSelector: "env=prod"})
+	require.NoError(t, err)
+
+	created, err := client.CreateProbe(ctx, &probespb.CreateProbeRequest{
+		StaticUrl:      "https://example.com",
+		AdditionalUrls: []string{"https://console.example.com"},
+		Labels:         map[string]string{"env": "prod"},
+		Assertions:     &probespb.Assertions{StatusCodes: []string{"200-399"}, Headers: []*probespb.HeaderAssertion{{Name: "Content-Type"}}},
+		Regions:        []string{"us-east-1"},
+		Owner:          &probespb.Owner{Team: new("sre")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"us-east-1"}, created.Regions)
+	assert.Equal(t, "sre", created.Owner.GetTeam())
+	assert.Equal(t, []string{"200-399"}, created.Assertions.StatusCodes)
+	assert.Equal(t, "pending", created.Status)
+	assert.Equal(t, "prod", created.Labels["env"])
+	assert.Equal(t, []string{"https://console.example.com"}, created.AdditionalUrls)
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, probespb.WatchEvent_ADDED, event.Type)
+	assert.Equal(t, created.Id, event.Probe.Id)
+
+	_, err = client.CreateProbe(ctx, &probespb.CreateProbeRequest{StaticUrl: "https://example.com"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.CreateProbe(ctx, &probespb.CreateProbeRequest{StaticUrl: "https://example.org", Validate: "everything"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "requests are validated against the spec")
+
+	got, err := client.GetProbe(ctx, &probespb.GetProbeRequest{Id: created.Id})
+	require.NoError(t, err)
+	assert.Equal(t, created.StaticUrl, got.StaticUrl)
+	_, err = client.GetProbe(ctx, &probespb.GetProbeRequest{Id: "00000000-0000-0000-0000-000000000000"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := client.ListProbes(ctx, &probespb.ListProbesRequest{LabelSelector: "env=prod"})
+	require.NoError(t, err)
+	require.Len(t, list.Probes, 1)
+	assert.NotEmpty(t, list.Version)
+
+	active := "active"
+	updated, err := client.UpdateProbe(ctx, &probespb.UpdateProbeRequest{
+		Id:              created.Id,
+		Status:          &active,
+		ResourceVersion: got.ResourceVersion,
+		TargetStatuses:  []*probespb.TargetStatus{{Url: "https://console.example.com", State: "down", Message: "connection refused"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "active", updated.Status)
+	require.Len(t, updated.TargetStatuses, 1)
+	assert.Equal(t, "down", updated.TargetStatuses[0].State)
+	assert.Equal(t, []string{"https://console.example.com"}, updated.AdditionalUrls, "an empty list leaves the URLs unchanged")
+	_, err = client.UpdateProbe(ctx, &probespb.UpdateProbeRequest{Id: created.Id, Status: &active, ResourceVersion: got.ResourceVersion})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "a stale resource version is rejected")
+
+	dns, err := client.CreateProbe(ctx, &probespb.CreateProbeRequest{
+		Dns: &probespb.Dns{QueryName: "api.example.com", RecordType: "A", ExpectedAnswers: []string{"203.0.113.7"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dns:api.example.com?type=A", dns.StaticUrl)
+	assert.Equal(t, "dns", dns.Module)
+	assert.Equal(t, []string{"203.0.113.7"}, dns.Dns.ExpectedAnswers)
+	icmp, err := client.CreateProbe(ctx, &probespb.CreateProbeRequest{Icmp: &probespb.Icmp{Host: "203.0.113.7", PacketCount: new(int32(3))}})
+	require.NoError(t, err)
+	assert.Equal(t, "icmp://203.0.113.7", icmp.StaticUrl)
+	assert.Equal(t, int32(3), icmp.Icmp.GetPacketCount())
+
+	pending, err := client.CreateProbe(ctx, &probespb.CreateProbeRequest{StaticUrl: "https://example.net", Labels: map[string]string{"env": "prod"}})
+	require.NoError(t, err)
+	_, err = client.DeleteProbe(ctx, &probespb.DeleteProbeRequest{Id: pending.Id})
+	require.NoError(t, err)
+
+	// Changes made in quick succession may be seen as one.
+	statuses := map[string]string{}
+	for {
+		event, err := watch.Recv()
+		require.NoError(t, err)
+		if event.Type == probespb.WatchEvent_DELETED {
+			assert.Equal(t, pending.Id, event.Probe.Id)
+			break
+		}
+		statuses[event.Probe.Id] = event.Probe.Status
+	}
+	assert.Equal(t, "active", statuses[created.Id])
+
+	close(srv.waitDone)
+	_, err = watch.Recv()
+	assert.Error(t, err, "watches end when the server shuts down")
+}
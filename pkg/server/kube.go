@@ -0,0 +1,30 @@
+//go:build !nokube
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/health"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+type (
+	// KubernetesInterface is the clientset checked by /readyz.
+	KubernetesInterface = kubernetes.Interface
+	// DynamicInterface is the client Prometheus Operator Probe resources
+	// are written with.
+	DynamicInterface = dynamic.Interface
+)
+
+// kubernetesCheck checks that the Kubernetes API can be reached.
+func kubernetesCheck(clientset KubernetesInterface) health.Check {
+	return health.Check{Name: "kubernetes", Func: func(context.Context) error {
+		if _, err := clientset.Discovery().ServerVersion(); err != nil {
+			return fmt.Errorf("failed to connect to Kubernetes: %w", err)
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,64 @@
+//go:build !nokube
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReadyzBackends_Kubernetes(t *testing.T) {
+	swagger := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test API", Version: "1.0.0"}}
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	router, err := createRouter(http.NotFoundHandler(), readProber(Config{Store: store, Clientset: fake.NewClientset()}), nil, swagger, docsPolicy{})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz?verbose", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[+]storage ok\n[+]kubernetes ok\nok", w.Body.String())
+}
+
+func TestNew_PrometheusProbes(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = New(Config{Store: store, DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), PrometheusProbes: PrometheusProbeConfig{Namespace: "monitoring"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid prometheus prober url")
+
+	srv, err := New(Config{Store: store, DynamicClient: dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), PrometheusProbes: PrometheusProbeConfig{Namespace: "monitoring", ProberURL: "http://blackbox:9115"}})
+	require.NoError(t, err)
+	assert.NotNil(t, srv.Handler())
+}
+
+func TestNew_Standby(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	election := LeaderElectionConfig{Namespace: "rhobs", Name: "synthetics-api", Identity: "http://10.0.0.1:8080"}
+
+	_, err = New(Config{Store: store, Standby: true})
+	assert.ErrorContains(t, err, "standby mode requires leader election")
+	_, err = New(Config{Store: store, LeaderElection: election})
+	assert.ErrorContains(t, err, "a Kubernetes client is required for leader election")
+	_, err = New(Config{Store: store, LeaseClient: fake.NewClientset(), LeaderElection: LeaderElectionConfig{Namespace: "rhobs", Name: "synthetics-api", Identity: "replica-0"}, Standby: true})
+	assert.ErrorContains(t, err, "identity to be the URL of the replica")
+
+	srv, err := New(Config{Store: store, LeaseClient: fake.NewClientset(), LeaderElection: election, Standby: true})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/probes", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "writes wait for a leader to be elected")
+	assert.Contains(t, w.Body.String(), "no leader is elected")
+}
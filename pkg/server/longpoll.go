@@ -0,0 +1,33 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/api"
+)
+
+// longPollDeadline extends the write deadline of ListProbes requests waiting
+// for a change by their timeout, so a write timeout shorter than it does not
+// cut them off. Other requests keep writeTimeout; zero disables it anyway.
+func longPollDeadline(writeTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if writeTimeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if r.Method == http.MethodGet && r.URL.Path == "/probes" && query.Get("wait_for_change") == "true" {
+				timeout := api.DefaultWaitTimeout
+				if value := query.Get("timeout"); value != "" {
+					if parsed, err := time.ParseDuration(value); err == nil {
+						timeout = min(parsed, api.MaxWaitTimeout)
+					}
+				}
+				// Writers that cannot change their deadline keep it.
+				_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + writeTimeout))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLongPollDeadline(t *testing.T) {
+	const writeTimeout = 50 * time.Millisecond
+	slow := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(3 * writeTimeout)
+		_, _ = w.Write([]byte("ok"))
+	})
+	ts := httptest.NewUnstartedServer(longPollDeadline(writeTimeout)(slow))
+	ts.Config.WriteTimeout = writeTimeout
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	get := func(path string) (string, error) {
+		t.Helper()
+		resp, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	body, err := get("/probes?wait_for_change=true&timeout=1s")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", body, "waiting requests get their timeout on top of the write timeout")
+
+	_, err = get("/probes")
+	assert.Error(t, err, "other requests keep the write timeout")
+}
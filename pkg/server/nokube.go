@@ -0,0 +1,24 @@
+//go:build nokube
+
+package server
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/health"
+)
+
+// Binaries built with the nokube tag have no Kubernetes clients; the Config
+// fields holding them are kept so that both builds share one Config.
+type (
+	KubernetesInterface = any
+	DynamicInterface    = any
+)
+
+// kubernetesCheck always fails, as the binary cannot reach Kubernetes.
+func kubernetesCheck(KubernetesInterface) health.Check {
+	return health.Check{Name: "kubernetes", Func: func(context.Context) error {
+		return errors.New("built without Kubernetes support (nokube)")
+	}}
+}
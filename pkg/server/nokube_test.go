@@ -0,0 +1,27 @@
+//go:build nokube
+
+package server
+
+import (
+	"testing"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_PrometheusProbesWithoutKubernetes(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = New(Config{Store: store, DynamicClient: struct{}{}, PrometheusProbes: PrometheusProbeConfig{Namespace: "monitoring", ProberURL: "http://blackbox:9115"}})
+	assert.ErrorContains(t, err, "built without Kubernetes support")
+}
+
+func TestNew_LeaderElectionWithoutKubernetes(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = New(Config{Store: store, LeaseClient: struct{}{}, LeaderElection: LeaderElectionConfig{Namespace: "rhobs", Name: "synthetics-api", Identity: "http://10.0.0.1:8080"}})
+	assert.ErrorContains(t, err, "built without Kubernetes support")
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/api"
+)
+
+// Settings are the parts of the configuration a running server can change
+// with Reload. The other parts need a restart.
+type Settings struct {
+	TenantLimits TenantLimits
+	// ReservedLabelPrefixes are reserved on top of "rhobs-synthetics/".
+	ReservedLabelPrefixes []string
+	// Webhooks changes the timeout, attempts and backoff of later
+	// deliveries; the queue size and workers keep their startup values.
+	Webhooks WebhookConfig
+}
+
+func (c Config) settings() Settings {
+	return Settings{
+		TenantLimits:          c.TenantLimits,
+		ReservedLabelPrefixes: c.ReservedLabelPrefixes,
+		Webhooks:              c.Webhooks,
+	}
+}
+
+// Reload applies settings to the requests and deliveries that start after
+// it returns, and reports which of them changed, by their configuration
+// keys, e.g. "tenant_limits". Unchanged settings are left alone.
+func (s *Server) Reload(settings Settings) []string {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	var changed []string
+	if !reflect.DeepEqual(settings.TenantLimits, s.settings.TenantLimits) {
+		s.limiter.Update(settings.TenantLimits)
+		changed = append(changed, "tenant_limits")
+	}
+	if !slices.Equal(settings.ReservedLabelPrefixes, s.settings.ReservedLabelPrefixes) {
+		s.api.SetLabelPolicy(api.DefaultLabelPolicy().WithReservedPrefixes(settings.ReservedLabelPrefixes...))
+		changed = append(changed, "reserved_label_prefixes")
+	}
+	if settings.Webhooks != s.settings.Webhooks {
+		s.api.Webhooks.Reconfigure(settings.Webhooks)
+		changed = append(changed, "webhooks")
+	}
+	s.settings = settings
+	return changed
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Reload(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	cfg := Config{Store: store, Webhooks: WebhookConfig{Timeout: time.Second}}
+	srv, err := New(cfg)
+	require.NoError(t, err)
+
+	create := func(url string) int {
+		t.Helper()
+		body := `{"static_url":"` + url + `","labels":{"example.com/team":"sre"}}`
+		req := httptest.NewRequest(http.MethodPost, "/probes", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusCreated, create("https://one.example.com"))
+
+	settings := cfg.settings()
+	assert.Empty(t, srv.Reload(settings), "nothing changed")
+
+	settings.ReservedLabelPrefixes = []string{"example.com/"}
+	settings.TenantLimits = limits.Config{Default: limits.Policy{MaxItems: 1}}
+	assert.Equal(t, []string{"tenant_limits", "reserved_label_prefixes"}, srv.Reload(settings))
+	assert.Equal(t, http.StatusForbidden, create("https://two.example.com"), "the label is now reserved")
+
+	settings.Webhooks.Timeout = 2 * time.Second
+	assert.Equal(t, []string{"webhooks"}, srv.Reload(settings))
+}
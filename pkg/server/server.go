@@ -0,0 +1,1073 @@
+// Package server runs the synthetics API in-process. The rhobs-synthetics-api
+// binary is a thin wrapper around it; other components and tests can embed the
+// API with their own ProbeStorage instead of running the binary.
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	middleware "github.com/oapi-codegen/nethttp-middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rhobs/rhobs-synthetics-api/internal/agentauth"
+	"github.com/rhobs/rhobs-synthetics-api/internal/api"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apikeys"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apiv2"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apiversion"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/cachecontrol"
+	"github.com/rhobs/rhobs-synthetics-api/internal/clock"
+	"github.com/rhobs/rhobs-synthetics-api/internal/compression"
+	"github.com/rhobs/rhobs-synthetics-api/internal/events"
+	"github.com/rhobs/rhobs-synthetics-api/internal/fieldcase"
+	"github.com/rhobs/rhobs-synthetics-api/internal/grpcapi"
+	"github.com/rhobs/rhobs-synthetics-api/internal/health"
+	"github.com/rhobs/rhobs-synthetics-api/internal/idempotency"
+	"github.com/rhobs/rhobs-synthetics-api/internal/leader"
+	"github.com/rhobs/rhobs-synthetics-api/internal/limits"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/metrics"
+	"github.com/rhobs/rhobs-synthetics-api/internal/mutation"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/pagetoken"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	"github.com/rhobs/rhobs-synthetics-api/internal/promprobes"
+	"github.com/rhobs/rhobs-synthetics-api/internal/readonly"
+	"github.com/rhobs/rhobs-synthetics-api/internal/results"
+	"github.com/rhobs/rhobs-synthetics-api/internal/retryafter"
+	"github.com/rhobs/rhobs-synthetics-api/internal/secrets"
+	"github.com/rhobs/rhobs-synthetics-api/internal/shadow"
+	"github.com/rhobs/rhobs-synthetics-api/internal/standby"
+	"github.com/rhobs/rhobs-synthetics-api/internal/targetcheck"
+	"github.com/rhobs/rhobs-synthetics-api/internal/templates"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tlsreload"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
+	"github.com/rhobs/rhobs-synthetics-api/internal/webhooks"
+	"github.com/rhobs/rhobs-synthetics-api/internal/writelimit"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	v2 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v2"
+	"github.com/rhobs/rhobs-synthetics-api/web"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// The settings below are defined by internal packages; the aliases let code
+// outside this module fill in a Config.
+type (
+	// ProbeStorage is the backend the API stores probes in.
+	ProbeStorage = probestore.ProbeStorage
+	// Schedule is the interval, timeout and module given to probes created
+	// without them.
+	Schedule = api.Schedule
+	// TenantLimits are the per-tenant request budgets.
+	TenantLimits = limits.Config
+	// ShadowConfig configures mirroring of read requests.
+	ShadowConfig = shadow.Config
+	// WriteLimit bounds the probe writes run against the store at once.
+	WriteLimit = writelimit.Config
+	// TargetValidation sets what the targets of probes created with
+	// validate=connectivity must pass.
+	TargetValidation = targetcheck.Config
+	// TLSConfig names the certificate files to serve HTTPS with.
+	TLSConfig = tlsreload.Config
+	// StatusTransitions are the status changes allowed on probe updates.
+	StatusTransitions = api.StatusTransitions
+	// WebhookConfig controls the delivery of probe events to webhooks.
+	WebhookConfig = webhooks.Config
+	// EventsConfig selects the message bus probe events are published to.
+	EventsConfig = events.Config
+	// MutationHook changes probes before they are created or updated.
+	MutationHook = mutation.Hook
+	// ProbeValidator accepts or rejects probes before they are created or
+	// updated.
+	ProbeValidator = api.Validator
+	// ProbeReview is the probe a ProbeValidator decides on.
+	ProbeReview = api.ProbeReview
+	// DefaultLabelsConfig are the labels merged into every probe created,
+	// and how probes setting them to other values are treated.
+	DefaultLabelsConfig = api.DefaultLabels
+	// ProbeValidatorConfig selects a built-in probe validator.
+	ProbeValidatorConfig = api.ValidatorConfig
+	// AuditSink durably stores the audit log.
+	AuditSink = audit.Sink
+	// AuditPrivacy controls the personal data audit entries keep.
+	AuditPrivacy = audit.Privacy
+	// CacheControlRule sets the Cache-Control header of a route.
+	CacheControlRule = cachecontrol.Rule
+	// APIDeprecation announces the retirement of version 1 of the API.
+	APIDeprecation = apiversion.Deprecation
+	// ProbeTemplate holds the defaults of probes created from it.
+	ProbeTemplate = templates.Template
+	// PrometheusProbeConfig selects where Prometheus Operator Probe
+	// resources are rendered.
+	PrometheusProbeConfig = promprobes.Config
+	// NotificationConfig names the Slack and email receivers of policy
+	// events and routes events to them.
+	NotificationConfig = notify.Config
+	// LeaderElectionConfig locates the Lease replicas elect a leader with.
+	LeaderElectionConfig = leader.Config
+	// SecretStore keeps the passwords and bearer tokens of probe
+	// credentials.
+	SecretStore = secrets.Store
+)
+
+var (
+	// ValidationForbidden marks an error of a ProbeValidator as a rejection
+	// reported with 403 rather than 400.
+	ValidationForbidden = api.Forbidden
+	// ValidationFailed marks an error of a ProbeValidator as a failure to
+	// decide, which fails the request with 500.
+	ValidationFailed = api.Failed
+)
+
+// DefaultGracefulTimeout is how long Run waits for in-flight requests when
+// Config.GracefulTimeout is zero.
+const DefaultGracefulTimeout = 15 * time.Second
+
+// assignmentInterval is how often probes are reassigned to agents.
+const assignmentInterval = 30 * time.Second
+
+// Config configures a Server. Only Store is required; zero values of the other
+// fields select the same defaults as the binary's flags.
+type Config struct {
+	// Addr is the address Run listens on, e.g. ":8080".
+	Addr string
+	// GRPCAddr is the address Run serves the gRPC API on, e.g. ":9090",
+	// with the TLS settings of Addr. Empty disables it.
+	GRPCAddr string
+	// Store is where probes are kept. Wrap it with probe store tracing before
+	// passing it in if spans are wanted.
+	Store ProbeStorage
+	// Secrets keeps the secret values of probe credentials apart from Store.
+	// Without it, probes cannot be given a password or bearer token.
+	Secrets SecretStore
+	// Clientset, when set, is checked by /readyz alongside the store so a
+	// replica that cannot reach the Kubernetes API is taken out of rotation.
+	Clientset KubernetesInterface
+	// DynamicClient writes the Prometheus Operator Probe resources; it is
+	// required when PrometheusProbes is enabled.
+	DynamicClient DynamicInterface
+	// LeaseClient holds the Lease of LeaderElection, without being checked
+	// by /readyz; Clientset is used when it is nil.
+	LeaseClient KubernetesInterface
+
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	GracefulTimeout time.Duration
+	// DrainDelay is how long Run keeps serving with /readyz failing once ctx
+	// is cancelled, before it stops accepting connections. It should exceed
+	// the time load balancers take to notice, e.g. the readiness probe's
+	// period times its failure threshold.
+	DrainDelay time.Duration
+	// AdminToken enables /admin/drain and the /apikeys operations, which
+	// require it as a bearer token.
+	AdminToken string
+	// APIKeyRateLimit is the requests per second API keys minted without a
+	// rate limit may send. Zero leaves them unlimited.
+	APIKeyRateLimit float64
+	// APIKeyRefreshInterval is how often the API keys are reloaded from the
+	// store, so keys minted or revoked on other replicas take effect. Zero
+	// selects apikeys.DefaultRefreshInterval.
+	APIKeyRefreshInterval time.Duration
+	// Profiling serves the Go profiler under /debug/pprof/ and a runtime
+	// snapshot under /debug/vars, which require AdminToken when it is set.
+	Profiling bool
+
+	TLS               TLSConfig
+	TLSReloadInterval time.Duration
+
+	// ReadinessCheckInterval is how long /readyz reuses the result of a
+	// backend check while the backend is healthy; failing backends are
+	// checked less often, up to health.DefaultMaxInterval. Zero selects
+	// health.DefaultInterval.
+	ReadinessCheckInterval time.Duration
+	// ReadinessLatencyBudget is how long a backend check may take before
+	// /readyz reports the backend as failing. Zero selects
+	// health.DefaultLatencyBudget.
+	ReadinessLatencyBudget time.Duration
+
+	// ReservedLabelPrefixes are reserved on top of "rhobs-synthetics/".
+	ReservedLabelPrefixes []string
+	// AgentFeatures are the capability hints returned to agents.
+	AgentFeatures v1.FeaturesSchema
+	// AgentHeartbeatTTL and AgentAffinityKeys configure probe assignment;
+	// zero values keep the assignment defaults.
+	AgentHeartbeatTTL time.Duration
+	AgentAffinityKeys []string
+	// MaxListItems caps GET /probes responses; zero means no cap.
+	MaxListItems int
+	// WriteLimit bounds concurrent probe writes; a zero MaxConcurrent means
+	// no limit, and a zero QueueTimeout selects writelimit.DefaultQueueTimeout.
+	WriteLimit WriteLimit
+	// TargetValidation sets the schemes and timeout of the checks run on
+	// the targets of probes created with validate=connectivity; zero values
+	// select the targetcheck defaults.
+	TargetValidation TargetValidation
+	// ProbeResultRetention is the number of results kept per probe.
+	ProbeResultRetention int
+	// ProbeMonitorInterval is how often every probe is listed to refresh the
+	// probe metrics; zero selects api.DefaultMonitorInterval.
+	ProbeMonitorInterval time.Duration
+	// TerminatingGracePeriod is how long a probe may stay terminating before
+	// it is removed without its agent's confirmation; zero selects
+	// api.DefaultTerminatingGracePeriod.
+	TerminatingGracePeriod time.Duration
+	// ClockSkewTolerance is how far ahead of the server clock the timestamps
+	// clients supply, such as those of probe results and heartbeats, may be;
+	// zero selects clock.DefaultSkewTolerance.
+	ClockSkewTolerance time.Duration
+	// Schedule defaults to api.DefaultSchedule when zero.
+	Schedule Schedule
+	// StatusTransitions defaults to api.DefaultStatusTransitions when nil.
+	StatusTransitions StatusTransitions
+	// Webhooks controls event delivery; zero values select the defaults.
+	Webhooks WebhookConfig
+	// Events, when its sink is set, publishes probe lifecycle events as
+	// CloudEvents through an outbox kept in the store, which must then be an
+	// OutboxStore.
+	Events EventsConfig
+	// DefaultLabels are merged into every probe created or imported, before
+	// the mutation hooks run. They may not be protected by the label policy.
+	DefaultLabels DefaultLabelsConfig
+	// MutationHooks run in order on every probe before it is stored.
+	MutationHooks []MutationHook
+	// ProbeValidators select the built-in validators every probe must pass,
+	// in order, after the label policy and tenant checks.
+	ProbeValidators []ProbeValidatorConfig
+	// Validators run after ProbeValidators.
+	Validators []ProbeValidator
+	// AuditSinks receive every audit entry. Without any, the audit log is
+	// only kept in memory.
+	AuditSinks []AuditSink
+	// AuditHistory is the number of audit entries kept in memory for
+	// GET /audit; zero selects audit.DefaultHistory.
+	AuditHistory int
+	// AuditPrivacy truncates caller addresses, hashes actors and limits how
+	// long audit entries are kept in memory; the zero value does neither.
+	AuditPrivacy AuditPrivacy
+	// CacheControl sets the Cache-Control header of successful GET responses
+	// per route; it defaults to cachecontrol.DefaultRules when nil.
+	CacheControl []CacheControlRule
+	// CompressResponses gzips responses of at least compression.MinSize
+	// bytes for clients that accept it.
+	CompressResponses bool
+	// APIDeprecation, once its date is set, adds the Deprecation, Sunset
+	// and successor Link headers to version 1 responses.
+	APIDeprecation APIDeprecation
+	// ProbeTemplates are available on every replica from startup, on top of
+	// those created through the API, and cannot be changed through it.
+	ProbeTemplates []ProbeTemplate
+	// PrometheusProbes, when its namespace is set, renders every probe into
+	// a Prometheus Operator Probe resource scraped through a blackbox
+	// exporter.
+	PrometheusProbes PrometheusProbeConfig
+	// PageTokenKey encrypts pagination tokens; a random key is used when empty.
+	PageTokenKey []byte
+	// AgentCredentialKey signs agent bootstrap tokens and credentials. Both
+	// are disabled when empty; it must be the same on every replica.
+	AgentCredentialKey []byte
+	// AgentBootstrapMaxTTL is the longest lifetime a bootstrap token may be
+	// minted with; zero selects agentauth.DefaultMaxBootstrapTTL.
+	AgentBootstrapMaxTTL time.Duration
+	// AgentCredentialTTL is the lifetime of agent credentials; zero selects
+	// agentauth.DefaultCredentialTTL.
+	AgentCredentialTTL time.Duration
+	// RequireAgentCredentials rejects agent requests without a credential.
+	RequireAgentCredentials bool
+	// IdempotencyKeyTTL is how long the responses of probe creations made
+	// with an Idempotency-Key are kept for replay; zero selects
+	// idempotency.DefaultTTL.
+	IdempotencyKeyTTL time.Duration
+	// Notifications sends policy events, such as agents nearly out of
+	// capacity, stuck probes and drifted Prometheus Probe resources, to Slack
+	// or email; nothing is sent without a receiver.
+	Notifications NotificationConfig
+
+	TenantLimits TenantLimits
+	// TenantIsolation scopes requests that carry a tenant, from the tenant
+	// header or a client certificate, to the probes that tenant created.
+	TenantIsolation bool
+	// ReadOnly rejects requests that change probes.
+	ReadOnly bool
+	Shadow   ShadowConfig
+
+	// LeaderElection, when its lease name is set, elects one replica as the
+	// leader with a Kubernetes Lease.
+	LeaderElection LeaderElectionConfig
+	// Standby makes the replicas that are not the leader serve reads
+	// themselves and forward writes to it. It requires LeaderElection, whose
+	// identity must then be the URL the other replicas reach this one at.
+	Standby bool
+}
+
+// Server is an API server that has been configured but not started.
+type Server struct {
+	config  Config
+	api     api.Server
+	handler http.Handler
+	certs   *tlsreload.Reloader
+	drainer *drainer
+	limiter *limits.Limiter
+	// waitDone is closed to end the ListProbes requests waiting for a
+	// change, and gRPC watches, before shutting down.
+	waitDone chan struct{}
+	// grpcAPI serves the gRPC API, calling the REST API handler.
+	grpcAPI *grpcapi.Server
+	// reloadMu serializes Reload, which keeps the settings it last applied.
+	reloadMu sync.Mutex
+	settings Settings
+	// probeResources is nil unless Prometheus Probe resources are rendered.
+	probeResources *promprobes.Controller
+	// elector is nil unless leader election is enabled.
+	elector *leader.Elector
+	// idempotencyKeys records the probe creations made with an
+	// Idempotency-Key.
+	idempotencyKeys *idempotency.Cache
+}
+
+// New validates the configuration and builds the server's handler.
+func New(cfg Config) (*Server, error) {
+	if cfg.Store == nil {
+		return nil, errors.New("a probe store is required")
+	}
+	if cfg.Schedule == (Schedule{}) {
+		cfg.Schedule = api.DefaultSchedule()
+	}
+	if err := cfg.Schedule.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid default probe schedule: %w", err)
+	}
+	if cfg.StatusTransitions == nil {
+		cfg.StatusTransitions = api.DefaultStatusTransitions()
+	}
+	if err := cfg.StatusTransitions.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid status transitions: %w", err)
+	}
+	if err := cfg.TLS.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.WriteLimit.QueueTimeout == 0 {
+		cfg.WriteLimit.QueueTimeout = writelimit.DefaultQueueTimeout
+	}
+	if err := cfg.WriteLimit.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.GracefulTimeout == 0 {
+		cfg.GracefulTimeout = DefaultGracefulTimeout
+	}
+	if cfg.DrainDelay < 0 {
+		return nil, fmt.Errorf("drain delay must be positive, got %s", cfg.DrainDelay)
+	}
+	if cfg.TLSReloadInterval == 0 {
+		cfg.TLSReloadInterval = tlsreload.DefaultInterval
+	}
+	if cfg.APIKeyRefreshInterval < 0 {
+		return nil, fmt.Errorf("API key refresh interval must be positive, got %s", cfg.APIKeyRefreshInterval)
+	}
+	if cfg.APIKeyRefreshInterval == 0 {
+		cfg.APIKeyRefreshInterval = apikeys.DefaultRefreshInterval
+	}
+	if cfg.ReadinessCheckInterval < 0 {
+		return nil, fmt.Errorf("readiness check interval must be positive, got %s", cfg.ReadinessCheckInterval)
+	}
+	if cfg.ReadinessLatencyBudget < 0 {
+		return nil, fmt.Errorf("readiness latency budget must be positive, got %s", cfg.ReadinessLatencyBudget)
+	}
+	if cfg.ProbeMonitorInterval == 0 {
+		cfg.ProbeMonitorInterval = api.DefaultMonitorInterval
+	}
+	if cfg.ProbeMonitorInterval < 0 {
+		return nil, fmt.Errorf("probe monitor interval must be positive, got %s", cfg.ProbeMonitorInterval)
+	}
+	if cfg.TerminatingGracePeriod == 0 {
+		cfg.TerminatingGracePeriod = api.DefaultTerminatingGracePeriod
+	}
+	if cfg.TerminatingGracePeriod < 0 {
+		return nil, fmt.Errorf("terminating grace period must be positive, got %s", cfg.TerminatingGracePeriod)
+	}
+	if cfg.ClockSkewTolerance == 0 {
+		cfg.ClockSkewTolerance = clock.DefaultSkewTolerance
+	}
+	if cfg.ClockSkewTolerance < 0 {
+		return nil, fmt.Errorf("clock skew tolerance must be positive, got %s", cfg.ClockSkewTolerance)
+	}
+	if cfg.IdempotencyKeyTTL < 0 {
+		return nil, fmt.Errorf("idempotency key TTL must be positive, got %s", cfg.IdempotencyKeyTTL)
+	}
+	if cfg.AuditHistory < 0 {
+		return nil, fmt.Errorf("audit history must be positive, got %d", cfg.AuditHistory)
+	}
+	if err := cfg.AuditPrivacy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.TargetValidation.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.AuditPrivacy.HashActors && len(cfg.AuditPrivacy.HashKey) == 0 {
+		slog.Warn("No audit hash key configured; hashed actors will differ between replicas and restarts")
+	}
+
+	var agentAuth *agentauth.Issuer
+	if len(cfg.AgentCredentialKey) > 0 {
+		issuer, err := agentauth.NewIssuer(cfg.AgentCredentialKey, cfg.AgentBootstrapMaxTTL, cfg.AgentCredentialTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid agent credential settings: %w", err)
+		}
+		agentAuth = issuer
+	} else if cfg.RequireAgentCredentials {
+		return nil, errors.New("an agent credential key is required to require agent credentials")
+	}
+
+	var elector *leader.Elector
+	if cfg.LeaderElection.Enabled() {
+		client := cfg.LeaseClient
+		if client == nil {
+			client = cfg.Clientset
+		}
+		if client == nil {
+			return nil, errors.New("a Kubernetes client is required for leader election")
+		}
+		var err error
+		if elector, err = leader.New(client, cfg.LeaderElection); err != nil {
+			return nil, fmt.Errorf("invalid leader election settings: %w", err)
+		}
+	}
+	// Standby replicas forward writes to the leader at its identity.
+	var standbyLeader standby.Leader
+	if cfg.Standby {
+		if elector == nil {
+			return nil, errors.New("standby mode requires leader election")
+		}
+		if u, err := url.Parse(cfg.LeaderElection.Identity); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("standby mode requires the leader election identity to be the URL of the replica, got %q", cfg.LeaderElection.Identity)
+		}
+		standbyLeader = elector
+	}
+
+	server := api.NewServer(cfg.Store)
+	server.SetLabelPolicy(api.DefaultLabelPolicy().WithReservedPrefixes(cfg.ReservedLabelPrefixes...))
+	if err := cfg.DefaultLabels.Validate(server.LabelPolicy()); err != nil {
+		return nil, fmt.Errorf("invalid default_labels: %w", err)
+	}
+	server.DefaultLabels = cfg.DefaultLabels
+	server.Features = cfg.AgentFeatures
+	if cfg.AgentHeartbeatTTL > 0 {
+		server.Assignments.HeartbeatTTL = cfg.AgentHeartbeatTTL
+	}
+	if cfg.AgentAffinityKeys != nil {
+		server.Assignments.AffinityKeys = cfg.AgentAffinityKeys
+	}
+	server.MaxListItems = cfg.MaxListItems
+	server.TenantIsolation = cfg.TenantIsolation
+	if agentAuth != nil {
+		if records, ok := probestore.Implements[probestore.RecordStore](cfg.Store); ok {
+			agentAuth.Records = records
+		}
+		agentAuth.Registered = func(ctx context.Context, agentID string) (bool, error) {
+			_, registered, err := server.Assignments.Agent(ctx, agentID)
+			return registered, err
+		}
+	}
+	server.AgentAuth = agentAuth
+	server.RequireAgentCredentials = cfg.RequireAgentCredentials
+	server.Secrets = cfg.Secrets
+	server.TargetCheck = targetcheck.New(cfg.TargetValidation)
+	if keys, ok := probestore.Implements[probestore.APIKeyStore](cfg.Store); ok {
+		manager, err := apikeys.NewManager(keys, cfg.APIKeyRateLimit)
+		if err != nil {
+			return nil, err
+		}
+		server.APIKeys = manager
+	}
+	server.AdminToken = cfg.AdminToken
+	waitDone := make(chan struct{})
+	server.WaitDone = waitDone
+	server.Results = results.NewStore(cfg.ProbeResultRetention)
+	server.Schedule = cfg.Schedule
+	server.MonitorInterval = cfg.ProbeMonitorInterval
+	server.TerminatingGracePeriod = cfg.TerminatingGracePeriod
+	server.ClockSkewTolerance = cfg.ClockSkewTolerance
+	server.StatusTransitions = cfg.StatusTransitions
+	server.Mutations = mutation.Chain(cfg.MutationHooks)
+	validators, err := api.BuildValidators(cfg.ProbeValidators, cfg.Store)
+	if err != nil {
+		return nil, err
+	}
+	server.Validators = append(validators, cfg.Validators...)
+	server.Webhooks = webhooks.NewNotifier(cfg.Webhooks)
+	if cfg.Events.Enabled() {
+		outbox, ok := probestore.Implements[probestore.OutboxStore](cfg.Store)
+		if !ok {
+			return nil, errors.New("publishing events requires a store that keeps an event outbox")
+		}
+		publisher, err := events.New(cfg.Events, outbox)
+		if err != nil {
+			return nil, fmt.Errorf("invalid events settings: %w", err)
+		}
+		server.Events = publisher
+	}
+	notifier, err := notify.New(cfg.Notifications)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification settings: %w", err)
+	}
+	server.Notifications = notifier
+	server.Assignments.Notifications = notifier
+	server.Audit = audit.NewLog(cfg.AuditHistory, cfg.AuditSinks...)
+	server.Audit.SetPrivacy(cfg.AuditPrivacy)
+	if err := server.AddTemplates(cfg.ProbeTemplates); err != nil {
+		return nil, err
+	}
+	if len(cfg.PageTokenKey) > 0 {
+		codec, err := pagetoken.NewCodec(cfg.PageTokenKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page token key: %w", err)
+		}
+		server.PageTokens = codec
+	} else {
+		slog.Warn("No page token key configured; page tokens will only be valid on this replica until it restarts")
+	}
+
+	swagger, err := v1.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading swagger spec: %w", err)
+	}
+	swagger.Servers = nil
+
+	serverHandler := v1.NewStrictHandler(server, []v1.StrictMiddlewareFunc{server.RequireTenant, logging.StrictMiddleware, tracing.StrictMiddleware})
+	metrics.RegisterMetrics()
+
+	// The API handlers are registered on a separate router and validated.
+	apiRouter := http.NewServeMux()
+	v1.HandlerFromMux(serverHandler, apiRouter)
+	validatedAPI := middleware.OapiRequestValidator(swagger)(apiRouter)
+	// Version 2 is served under its prefix, behind the same middlewares.
+	v2API, err := apiV2Handler(server, server.RequireTenant)
+	if err != nil {
+		return nil, err
+	}
+	versions := http.NewServeMux()
+	versions.Handle("/", validatedAPI)
+	versions.Handle(apiv2.Prefix+"/", v2API)
+	validatedAPI = versions
+	// Writes replayed from the idempotency cache do not reach the store, so
+	// they are not limited.
+	validatedAPI = writelimit.Middleware(cfg.WriteLimit)(validatedAPI)
+
+	// Idempotency keys are scoped to the tenant, which the limits middleware
+	// attaches, so it runs first, and to the caller's credential, which the
+	// API key and agent credential middlewares attach.
+	idempotencyKeys := idempotency.NewCache(cfg.IdempotencyKeyTTL)
+	if records, ok := probestore.Implements[probestore.RecordStore](cfg.Store); ok {
+		idempotencyKeys.Records = records
+	}
+	validatedAPI = idempotencyKeys.Middleware(validatedAPI)
+	limiter := limits.NewLimiter(cfg.TenantLimits, validatedAPI)
+	validatedAPI = limiter
+	// API keys set the actor of the changes made with them, so they are
+	// verified inside the audit middleware.
+	validatedAPI = apikeys.Middleware(server.APIKeys)(validatedAPI)
+	validatedAPI = audit.Middleware(validatedAPI)
+	validatedAPI = agentauth.Middleware(agentAuth)(validatedAPI)
+	if cfg.ReadOnly {
+		slog.Warn("API is in read-only mode; writes will be rejected")
+	}
+	validatedAPI = readonly.Middleware(cfg.ReadOnly)(validatedAPI)
+	validatedAPI = standby.Middleware(standbyLeader)(validatedAPI)
+	validatedAPI = retryafter.Middleware(validatedAPI)
+	validatedAPI = fieldcase.Middleware(validatedAPI)
+
+	mirror, err := shadow.NewMirror(cfg.Shadow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure request mirroring: %w", err)
+	}
+	validatedAPI = mirror.Middleware(validatedAPI)
+	v1Operation, err := operationNamer(swagger)
+	if err != nil {
+		return nil, err
+	}
+	v2Operation, err := v2OperationNamer()
+	if err != nil {
+		return nil, err
+	}
+	validatedAPI = metrics.Middleware(func(r *http.Request) string {
+		return cmp.Or(v2Operation(r), v1Operation(r))
+	})(validatedAPI)
+	validatedAPI = logging.Middleware(validatedAPI)
+	validatedAPI = tracing.Middleware(validatedAPI)
+	// Requests asking for version 2 with the API-Version header are routed
+	// before anything else sees their path.
+	validatedAPI = apiversion.Middleware(cfg.APIDeprecation)(validatedAPI)
+
+	if cfg.CacheControl == nil {
+		cfg.CacheControl = cachecontrol.DefaultRules()
+	}
+	cacheHeaders, err := cachecontrol.Middleware(cfg.CacheControl)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		config:          cfg,
+		api:             server,
+		drainer:         &drainer{token: cfg.AdminToken},
+		limiter:         limiter,
+		waitDone:        waitDone,
+		grpcAPI:         grpcapi.NewServer(validatedAPI, waitDone),
+		settings:        cfg.settings(),
+		elector:         elector,
+		idempotencyKeys: idempotencyKeys,
+	}
+	router, err := createRouter(validatedAPI, readProber(cfg), writeReadiness(writeProber(cfg), cfg.ReadOnly), swagger, docsPolicy{adminToken: cfg.AdminToken, agentAuth: agentAuth})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Profiling {
+		if cfg.AdminToken == "" {
+			slog.Warn("Profiling is enabled without an admin token; /debug is open to every caller")
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/debug/", debugHandler(cfg.AdminToken, func() map[string]int {
+			caches := map[string]int{
+				"probe_results":    server.Results.Len(),
+				"audit_entries":    server.Audit.Len(),
+				"idempotency_keys": idempotencyKeys.Len(),
+			}
+			if indexer, ok := probestore.Implements[probestore.URLHashIndexer](cfg.Store); ok {
+				caches["url_hash_index"] = indexer.URLHashIndexSize()
+			}
+			if server.APIKeys != nil {
+				caches["api_keys"] = server.APIKeys.Len()
+			}
+			return caches
+		}))
+		mux.Handle("/", router)
+		router = mux
+	}
+	if cfg.CompressResponses {
+		router = compression.Middleware(router)
+	}
+	s.handler = longPollDeadline(cfg.WriteTimeout)(s.drainer.handler(cacheHeaders(router)))
+	if cfg.PrometheusProbes.Enabled() {
+		if cfg.DynamicClient == nil {
+			return nil, errors.New("a dynamic client is required to render prometheus probes")
+		}
+		s.probeResources, err = promprobes.NewController(cfg.DynamicClient, cfg.Store, cfg.PrometheusProbes)
+		if err != nil {
+			return nil, err
+		}
+		s.probeResources.Notifications = notifier
+	}
+	if cfg.TLS.Enabled() {
+		s.certs, err = tlsreload.New(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificates: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Handler returns the HTTP handler serving the API, health checks, docs and
+// metrics, e.g. to mount it in another server or an httptest.Server. The
+// background loops only run while Run does.
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// Run listens on Config.Addr and serves until ctx is cancelled, then drains
+// for Config.DrainDelay and shuts down gracefully. It delivers notifications
+// for as long as it serves, keeps the store's URL hash index current if it
+// has one, and writes the snapshots of an in-memory store, the last once it
+// stopped serving. While it leads (always, without leader election), it also
+// runs probe monitoring, garbage collection, removal of probes stuck
+// terminating, agent assignment, the sync of Prometheus Probe resources when enabled, the
+// relay of the event outbox when events are enabled, and backfills the full
+// URL hash of probes stored before it was recorded.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.config.Addr, err)
+	}
+
+	httpServer := &http.Server{
+		Handler:      s.handler,
+		ReadTimeout:  s.config.ReadTimeout,
+		WriteTimeout: s.config.WriteTimeout,
+	}
+
+	monitorCtx, cancelMonitor := context.WithCancel(ctx)
+	defer cancelMonitor()
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		s.elector.Run(monitorCtx, s.runLeaderLoops)
+	}()
+	go s.api.Webhooks.Run(monitorCtx)
+	go s.api.Notifications.Run(monitorCtx)
+	if indexer, ok := probestore.Implements[probestore.URLHashIndexer](s.api.Store); ok {
+		go indexer.RunURLHashIndex(monitorCtx)
+	}
+	if persister, ok := probestore.Implements[probestore.Persister](s.api.Store); ok {
+		// Persistence outlives the other loops, so that the last write is
+		// made once requests have drained.
+		persistCtx, stopPersistence := context.WithCancel(context.WithoutCancel(ctx))
+		persisted := make(chan struct{})
+		go func() {
+			defer close(persisted)
+			persister.RunPersistence(persistCtx)
+		}()
+		defer func() {
+			stopPersistence()
+			<-persisted
+		}()
+	}
+	if s.api.AgentAuth != nil {
+		if err := s.api.AgentAuth.Refresh(ctx); err != nil {
+			slog.Error("Failed to load agent credential revocations; revoked credentials are accepted until the next refresh", "error", err)
+		}
+		go s.api.AgentAuth.Run(monitorCtx, agentauth.DefaultRefreshInterval)
+	}
+	go s.idempotencyKeys.Run(monitorCtx, idempotency.DefaultPruneInterval)
+	if s.api.APIKeys != nil {
+		if err := s.api.APIKeys.Refresh(ctx); err != nil {
+			slog.Error("Failed to load API keys; they are rejected until the next refresh", "error", err)
+		}
+		go s.api.APIKeys.Run(monitorCtx, s.config.APIKeyRefreshInterval)
+	}
+
+	scheme := "http"
+	if s.certs != nil {
+		httpServer.TLSConfig = s.certs.TLSConfig()
+		go s.certs.Run(monitorCtx, s.config.TLSReloadInterval)
+		scheme = "https"
+	}
+
+	serveErr := make(chan error, 2)
+	var grpcServer *grpc.Server
+	if s.config.GRPCAddr != "" {
+		grpcListener, err := net.Listen("tcp", s.config.GRPCAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", s.config.GRPCAddr, err)
+		}
+		var opts []grpc.ServerOption
+		if httpServer.TLSConfig != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(httpServer.TLSConfig)))
+		}
+		grpcServer = grpcapi.NewGRPCServer(s.grpcAPI, opts...)
+		go func() {
+			slog.Info("gRPC API listening", "addr", grpcListener.Addr().String(), "tls", httpServer.TLSConfig != nil)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				serveErr <- fmt.Errorf("gRPC: %w", err)
+			}
+		}()
+	}
+	go func() {
+		addr := listener.Addr().String()
+		slog.Info("API server listening", "url", scheme+"://"+addr, "docs", scheme+"://"+addr+"/docs", "client_auth", s.config.TLS.ClientCAFile != "")
+		if httpServer.TLSConfig != nil {
+			// The certificates come from TLSConfig, so no files are passed here.
+			serveErr <- httpServer.ServeTLS(listener, "", "")
+		} else {
+			serveErr <- httpServer.Serve(listener)
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+	slog.Info("Initiating graceful shutdown")
+	s.drainer.drain(s.config.DrainDelay)
+	// Requests waiting for a change return now rather than hold up the
+	// shutdown until their timeout.
+	close(s.waitDone)
+
+	// Stop the probe monitor first, and hand leadership over once it stopped
+	cancelMonitor()
+	<-leaderDone
+	if err := s.api.Events.Close(); err != nil {
+		slog.Warn("Failed to close the event sink", "error", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.GracefulTimeout)
+	defer cancel()
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server forced to shutdown: %w", err)
+	}
+	slog.Info("Server stopped serving new connections")
+	return nil
+}
+
+// runLeaderLoops runs the loops that act on every probe of the store, which
+// only the leader runs, until ctx is cancelled.
+func (s *Server) runLeaderLoops(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Go(func() { s.api.MonitorProbes(ctx) })
+	wg.Go(func() { s.api.GarbageCollectProbes(ctx) })
+	wg.Go(func() { s.api.ReconcileTerminatingProbes(ctx) })
+	wg.Go(func() { s.api.Assignments.Run(ctx, assignmentInterval) })
+	if s.probeResources != nil {
+		wg.Go(func() { s.probeResources.Run(ctx) })
+	}
+	if !s.config.ReadOnly {
+		wg.Go(func() { backfillURLHashes(ctx, s.api.Store) })
+	}
+	if s.api.Events != nil && !s.config.ReadOnly {
+		wg.Go(func() { s.api.Events.Run(ctx) })
+	}
+	wg.Wait()
+}
+
+// backfillURLHashes runs the URL hash backfill once. Failures are logged
+// rather than stopping the server: probes without a full hash still have their
+// label, and the backfill is retried on the next start.
+func backfillURLHashes(ctx context.Context, store probestore.ProbeStorage) {
+	migrated, err := probestore.BackfillURLHashes(ctx, store)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error backfilling full URL hashes", "error", err)
+		return
+	}
+	if migrated > 0 {
+		slog.InfoContext(ctx, "Backfilled full URL hashes", "probes", migrated)
+	}
+}
+
+// operationNamer returns a function naming the API operation of a request by
+// its operationId in the spec, or "" when it matches none, for the HTTP
+// metrics.
+func operationNamer(swagger *openapi3.T) (func(*http.Request) string, error) {
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route API operations: %w", err)
+	}
+	return func(r *http.Request) string {
+		route, _, err := router.FindRoute(r)
+		if err != nil {
+			return ""
+		}
+		return route.Operation.OperationID
+	}, nil
+}
+
+// readProber returns the backend checks behind /readyz: the store, and the
+// Kubernetes API when a clientset is configured.
+func readProber(cfg Config) *health.Prober {
+	checks := []health.Check{{Name: "storage", Budget: cfg.ReadinessLatencyBudget, Func: func(ctx context.Context) error {
+		if checker, ok := probestore.Implements[probestore.HealthChecker](cfg.Store); ok {
+			return checker.CheckHealth(ctx)
+		}
+		return nil
+	}}}
+	if cfg.Clientset != nil {
+		check := kubernetesCheck(cfg.Clientset)
+		check.Budget = cfg.ReadinessLatencyBudget
+		checks = append(checks, check)
+	}
+	return health.NewProber(cfg.ReadinessCheckInterval, 0, checks...)
+}
+
+// writeProber returns the backend check added by /readyz?verb=write: whether
+// the store reports it can take writes.
+func writeProber(cfg Config) *health.Prober {
+	return health.NewProber(cfg.ReadinessCheckInterval, 0, health.Check{Name: "storage-writable", Budget: cfg.ReadinessLatencyBudget, Func: func(ctx context.Context) error {
+		if checker, ok := probestore.Implements[probestore.WriteChecker](cfg.Store); ok {
+			return checker.CheckWritable(ctx)
+		}
+		return nil
+	}})
+}
+
+// writeReadiness returns the check behind /readyz?verb=write: writes fail
+// while the API is read-only, or while the store reports it cannot take them.
+// Read-only mode is checked on every request; the store through the prober.
+func writeReadiness(prober *health.Prober, readOnly bool) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if readOnly {
+			return readonly.ErrReadOnly
+		}
+		for _, result := range prober.Check(ctx) {
+			if result.Err != nil {
+				return result.Err
+			}
+		}
+		return nil
+	}
+}
+
+// writeDiagnostics writes one line per backend check, marking healthy
+// backends with [+] and failing ones with [-].
+func writeDiagnostics(w io.Writer, results []health.Result) {
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(w, "[-]%s failed: %v (%d in a row, checked %s)\n", result.Name, result.Err, result.Failures, result.CheckedAt.UTC().Format(time.RFC3339))
+			continue
+		}
+		fmt.Fprintf(w, "[+]%s ok\n", result.Name)
+	}
+}
+
+// readyzCheck is a backend check as /readyz?format=json reports it.
+type readyzCheck struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Failures  int       `json:"failures,omitempty"`
+	Latency   string    `json:"latency,omitempty"`
+	CheckedAt time.Time `json:"checked_at,omitzero"`
+}
+
+// writeReadyzJSON writes the outcome of /readyz and every backend check as
+// JSON. writeErr is why writes are not ready, if they were checked.
+func writeReadyzJSON(w http.ResponseWriter, code int, results []health.Result, writeErr error) {
+	body := struct {
+		Status string        `json:"status"`
+		Checks []readyzCheck `json:"checks"`
+	}{Status: "ready", Checks: []readyzCheck{}}
+	if code != http.StatusOK {
+		body.Status = "not ready"
+	}
+	for _, result := range results {
+		check := readyzCheck{Name: result.Name, Status: "ok", Failures: result.Failures, Latency: result.Latency.String(), CheckedAt: result.CheckedAt.UTC()}
+		if result.Err != nil {
+			check.Status, check.Error = "failed", result.Err.Error()
+		}
+		body.Checks = append(body.Checks, check)
+	}
+	if writeErr != nil {
+		body.Checks = append(body.Checks, readyzCheck{Name: "writes", Status: "failed", Error: writeErr.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func createRouter(validatedAPI http.Handler, ready *health.Prober, writeReady func(context.Context) error, swagger *openapi3.T, docs docsPolicy) (http.Handler, error) {
+	specs, err := scopedSpecs(swagger)
+	if err != nil {
+		return nil, err
+	}
+	swaggerV2, err := v2.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading v2 swagger spec: %w", err)
+	}
+	specsV2, err := scopedSpecs(swaggerV2)
+	if err != nil {
+		return nil, err
+	}
+
+	// The main router
+	mux := http.NewServeMux()
+
+	// Liveness and Readiness probes
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	// /readyz reports whether reads can be served; /readyz?verb=write also
+	// checks that writes would succeed, so load balancers can keep routing
+	// reads to a replica that automation should not send writes to. Backend
+	// checks are cached by the prober, so frequent probes do not reach the
+	// backends on every request. /readyz?verbose lists every backend check,
+	// and /readyz?format=json reports them as JSON.
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		verb := query.Get("verb")
+		if verb != "" && verb != "read" && verb != "write" {
+			http.Error(w, fmt.Sprintf("unknown verb %q, expected read or write", verb), http.StatusBadRequest)
+			return
+		}
+		format := query.Get("format")
+		if format != "" && format != "text" && format != "json" {
+			http.Error(w, fmt.Sprintf("unknown format %q, expected text or json", format), http.StatusBadRequest)
+			return
+		}
+
+		results := ready.Check(r.Context())
+		for _, result := range results {
+			if result.Err != nil {
+				if format == "json" {
+					writeReadyzJSON(w, http.StatusServiceUnavailable, results, nil)
+					return
+				}
+				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+				w.Header().Set("X-Content-Type-Options", "nosniff")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte("not ready\n"))
+				writeDiagnostics(w, results)
+				return
+			}
+		}
+
+		if verb == "write" && writeReady != nil {
+			if err := writeReady(r.Context()); err != nil {
+				slog.WarnContext(r.Context(), "Write readiness check failed", "error", err)
+				if format == "json" {
+					writeReadyzJSON(w, http.StatusServiceUnavailable, results, err)
+					return
+				}
+				http.Error(w, fmt.Sprintf("not ready for writes: %v", err), http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		if format == "json" {
+			writeReadyzJSON(w, http.StatusOK, results, nil)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		if query.Has("verbose") {
+			writeDiagnostics(w, results)
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	// Add the Swagger UI handler at /docs
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(web.SwaggerHTML)
+	})
+
+	// Add the OpenAPI spec handler at /api/v1/openapi.json, which /docs
+	// renders. The spec only documents the operations of the caller's role,
+	// so it varies with the Authorization header.
+	mux.HandleFunc("/api/v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Vary", "Authorization")
+		_, _ = w.Write(specs[docs.role(r)])
+	})
+	mux.HandleFunc(apiv2.Prefix+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Add("Vary", "Authorization")
+		_, _ = w.Write(specsV2[docs.role(r)])
+	})
+	mux.Handle("/metrics", promhttp.Handler())
+
+	// Mount the validated API router to the main router.
+	// Requests will be matched against the UI handlers first, then fall through to the API.
+	mux.Handle("/", validatedAPI)
+	return mux, nil
+}
@@ -0,0 +1,760 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/rhobs/rhobs-synthetics-api/internal/audit"
+	"github.com/rhobs/rhobs-synthetics-api/internal/health"
+	"github.com/rhobs/rhobs-synthetics-api/internal/idempotency"
+	"github.com/rhobs/rhobs-synthetics-api/internal/notify"
+	"github.com/rhobs/rhobs-synthetics-api/internal/probestore"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	v2 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateRouter(t *testing.T) {
+	// Create a simple test handler for the validated API
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("test api"))
+	})
+
+	// Create a minimal swagger spec for testing
+	swagger := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info: &openapi3.Info{
+			Title:   "Test API",
+			Version: "1.0.0",
+		},
+	}
+
+	// Test with nil clientset (local storage mode)
+	router, err := createRouter(testHandler, nil, nil, swagger, docsPolicy{})
+	require.NoError(t, err)
+	assert.NotNil(t, router)
+
+	// Test health endpoints
+	testCases := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"/livez", http.StatusOK, "ok"},
+		{"/readyz", http.StatusOK, "ok"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+		})
+	}
+
+	// Test docs endpoint
+	t.Run("/docs", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/docs", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	})
+
+	// Test OpenAPI spec endpoint
+	t.Run("/api/v1/openapi.json", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/openapi.json", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+}
+
+func TestReadyzVerb(t *testing.T) {
+	swagger := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test API", Version: "1.0.0"}}
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		readOnly       bool
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "reads are ready", query: "", expectedStatus: http.StatusOK, expectedBody: "ok"},
+		{name: "writes are ready", query: "?verb=write", expectedStatus: http.StatusOK, expectedBody: "ok"},
+		{name: "reads stay ready in read-only mode", readOnly: true, query: "?verb=read", expectedStatus: http.StatusOK, expectedBody: "ok"},
+		{name: "writes are not ready in read-only mode", readOnly: true, query: "?verb=write", expectedStatus: http.StatusServiceUnavailable, expectedBody: "not ready for writes: the API is in read-only mode\n"},
+		{name: "unknown verb", query: "?verb=delete", expectedStatus: http.StatusBadRequest, expectedBody: "unknown verb \"delete\", expected read or write\n"},
+		{name: "unknown format", query: "?format=xml", expectedStatus: http.StatusBadRequest, expectedBody: "unknown format \"xml\", expected text or json\n"},
+		{
+			name: "write failure as json", readOnly: true, query: "?verb=write&format=json", expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: `{"status":"not ready","checks":[{"name":"writes","status":"failed","error":"the API is in read-only mode"}]}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, err := createRouter(http.NotFoundHandler(), nil, writeReadiness(writeProber(Config{Store: store}), tc.readOnly), swagger, docsPolicy{})
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz"+tc.query, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestReadyzBackends(t *testing.T) {
+	swagger := &openapi3.T{OpenAPI: "3.0.0", Info: &openapi3.Info{Title: "Test API", Version: "1.0.0"}}
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	t.Run("checks the store", func(t *testing.T) {
+		router, err := createRouter(http.NotFoundHandler(), readProber(Config{Store: store}), nil, swagger, docsPolicy{})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz?verbose", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[+]storage ok\nok", w.Body.String())
+	})
+
+	t.Run("reports failing backends", func(t *testing.T) {
+		var calls atomic.Int32
+		prober := health.NewProber(time.Minute, 0,
+			health.Check{Name: "storage", Func: func(context.Context) error { return nil }},
+			health.Check{Name: "kubernetes", Func: func(context.Context) error {
+				calls.Add(1)
+				return errors.New("connection refused")
+			}},
+		)
+		router, err := createRouter(http.NotFoundHandler(), prober, nil, swagger, docsPolicy{})
+		require.NoError(t, err)
+
+		for range 3 {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+			assert.Contains(t, w.Body.String(), "not ready\n[+]storage ok\n[-]kubernetes failed: connection refused (1 in a row")
+		}
+		assert.EqualValues(t, 1, calls.Load(), "results are cached between probes")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz?format=json", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var body struct {
+			Status string `json:"status"`
+			Checks []struct {
+				Name     string `json:"name"`
+				Status   string `json:"status"`
+				Error    string `json:"error"`
+				Failures int    `json:"failures"`
+				Latency  string `json:"latency"`
+			} `json:"checks"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "not ready", body.Status)
+		require.Len(t, body.Checks, 2)
+		assert.Equal(t, "ok", body.Checks[0].Status)
+		assert.NotEmpty(t, body.Checks[0].Latency)
+		assert.Equal(t, "kubernetes", body.Checks[1].Name)
+		assert.Equal(t, "failed", body.Checks[1].Status)
+		assert.Equal(t, "connection refused", body.Checks[1].Error)
+		assert.Equal(t, 1, body.Checks[1].Failures)
+	})
+
+	t.Run("reports slow backends", func(t *testing.T) {
+		prober := readProber(Config{Store: slowStore{store}, ReadinessLatencyBudget: time.Millisecond})
+		router, err := createRouter(http.NotFoundHandler(), prober, nil, swagger, docsPolicy{})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), "[-]storage failed: took ")
+		assert.Contains(t, w.Body.String(), "over the 1ms latency budget")
+	})
+}
+
+// slowStore is a store whose health checks take 20ms.
+type slowStore struct {
+	*probestore.LocalProbeStore
+}
+
+func (s slowStore) CheckHealth(ctx context.Context) error {
+	time.Sleep(20 * time.Millisecond)
+	return s.LocalProbeStore.CheckHealth(ctx)
+}
+
+func TestNew(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name        string
+		config      Config
+		expectedErr string
+	}{
+		{name: "store only", config: Config{Store: store}},
+		{name: "missing store", config: Config{}, expectedErr: "a probe store is required"},
+		{
+			name:        "timeout longer than interval",
+			config:      Config{Store: store, Schedule: Schedule{Interval: time.Second, Timeout: time.Minute, Module: "http_2xx"}},
+			expectedErr: "invalid default probe schedule: probe timeout 1m0s is longer than the interval 1s",
+		},
+		{
+			name:        "negative idempotency key TTL",
+			config:      Config{Store: store, IdempotencyKeyTTL: -time.Hour},
+			expectedErr: "idempotency key TTL must be positive, got -1h0m0s",
+		},
+		{
+			name: "notification route naming an unknown receiver",
+			config: Config{Store: store, Notifications: NotificationConfig{
+				Slack:  []notify.SlackConfig{{Name: "ops", WebhookURL: "https://hooks.slack.com/services/x"}},
+				Routes: []notify.Route{{Receivers: []string{"pager"}}},
+			}},
+			expectedErr: `invalid notification settings: notifications.routes[0]: unknown receiver "pager"`,
+		},
+		{
+			name:   "events published through the store's outbox",
+			config: Config{Store: store, Events: EventsConfig{Sink: "http", URL: "https://events.example.com"}},
+		},
+		{
+			name:        "events with an unknown sink",
+			config:      Config{Store: store, Events: EventsConfig{Sink: "sqs"}},
+			expectedErr: `invalid events settings: unknown event sink "sqs", expected one of http, kafka, nats`,
+		},
+		{
+			name:        "events without an outbox",
+			config:      Config{Store: struct{ probestore.ProbeStorage }{store}, Events: EventsConfig{Sink: "http", URL: "https://events.example.com"}},
+			expectedErr: "publishing events requires a store that keeps an event outbox",
+		},
+		{
+			name:   "default labels",
+			config: Config{Store: store, DefaultLabels: DefaultLabelsConfig{Labels: map[string]string{"environment": "production"}}},
+		},
+		{
+			name:        "protected default label",
+			config:      Config{Store: store, ReservedLabelPrefixes: []string{"example.com/"}, DefaultLabels: DefaultLabelsConfig{Labels: map[string]string{"example.com/owner": "sre"}}},
+			expectedErr: `invalid default_labels: label "example.com/owner" is managed by the system and cannot have a default`,
+		},
+		{
+			name:   "probe validators",
+			config: Config{Store: store, ProbeValidators: []ProbeValidatorConfig{{Name: "quota", MaxProbes: 10}}},
+		},
+		{
+			name:        "unknown probe validator",
+			config:      Config{Store: store, ProbeValidators: []ProbeValidatorConfig{{Name: "opa"}}},
+			expectedErr: `probe_validators[0]: unknown validator "opa", expected one of url_policy, quota, required_labels, required_owner`,
+		},
+		{
+			name:        "negative readiness check interval",
+			config:      Config{Store: store, ReadinessCheckInterval: -time.Second},
+			expectedErr: "readiness check interval must be positive, got -1s",
+		},
+		{
+			name:        "negative drain delay",
+			config:      Config{Store: store, DrainDelay: -time.Second},
+			expectedErr: "drain delay must be positive, got -1s",
+		},
+		{
+			name:        "negative readiness latency budget",
+			config:      Config{Store: store, ReadinessLatencyBudget: -time.Second},
+			expectedErr: "readiness latency budget must be positive, got -1s",
+		},
+		{
+			name:        "negative probe monitor interval",
+			config:      Config{Store: store, ProbeMonitorInterval: -time.Second},
+			expectedErr: "probe monitor interval must be positive, got -1s",
+		},
+		{
+			name:        "negative terminating grace period",
+			config:      Config{Store: store, TerminatingGracePeriod: -time.Minute},
+			expectedErr: "terminating grace period must be positive, got -1m0s",
+		},
+		{
+			name:        "negative audit history",
+			config:      Config{Store: store, AuditHistory: -1},
+			expectedErr: "audit history must be positive, got -1",
+		},
+		{
+			name:        "invalid cache control route",
+			config:      Config{Store: store, CacheControl: []CacheControlRule{{Route: "probes"}}},
+			expectedErr: `cache_control[0]: route "probes" must start with /`,
+		},
+		{
+			name:        "unknown status in transitions",
+			config:      Config{Store: store, StatusTransitions: StatusTransitions{"pending": {"running"}}},
+			expectedErr: `invalid status transitions: unknown probe status "running" in the transitions from "pending", expected one of [pending active failed terminating deleted]`,
+		},
+		{
+			name:        "short page token key",
+			config:      Config{Store: store, PageTokenKey: []byte("short")},
+			expectedErr: "invalid page token key",
+		},
+		{
+			name:        "tls key without certificate",
+			config:      Config{Store: store, TLS: TLSConfig{KeyFile: "tls.key"}},
+			expectedErr: "--tls-cert and --tls-key must be set together",
+		},
+		{
+			name:        "invalid probe template",
+			config:      Config{Store: store, ProbeTemplates: []ProbeTemplate{{Name: "console"}}},
+			expectedErr: `probe_templates[0]: template "console": url_pattern cannot be empty`,
+		},
+		{
+			name:        "short audit hash key",
+			config:      Config{Store: store, AuditPrivacy: AuditPrivacy{HashActors: true, HashKey: []byte("short")}},
+			expectedErr: "audit hash key must be at least 32 bytes long",
+		},
+		{
+			name:        "short agent credential key",
+			config:      Config{Store: store, AgentCredentialKey: []byte("short")},
+			expectedErr: "agent credential key must be at least 32 bytes",
+		},
+		{
+			name:        "agent credentials required without key",
+			config:      Config{Store: store, RequireAgentCredentials: true},
+			expectedErr: "an agent credential key is required to require agent credentials",
+		},
+		{
+			name:        "prometheus probes without dynamic client",
+			config:      Config{Store: store, PrometheusProbes: PrometheusProbeConfig{Namespace: "monitoring", ProberURL: "http://blackbox:9115"}},
+			expectedErr: "a dynamic client is required to render prometheus probes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, err := New(tc.config)
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				assert.Nil(t, srv)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, srv.Handler())
+		})
+	}
+}
+
+func TestServer_Handler(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store})
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/probes")
+	require.NoError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestServer_Audit(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	var sink bytes.Buffer
+	srv, err := New(Config{Store: store, AuditSinks: []AuditSink{audit.NewWriterSink("buffer", &sink)}})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/probes", strings.NewReader(`{"static_url":"https://example.com"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(audit.ActorHeader, "alice")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	res, err = http.Get(ts.URL + "/audit?actor=alice&operation=createProbe")
+	require.NoError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body v1.AuditEntriesArrayResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	require.Len(t, body.Entries, 1)
+	entry := body.Entries[0]
+	assert.Equal(t, "alice", *entry.Actor)
+	assert.NotEmpty(t, *entry.RemoteAddr)
+	assert.NotEmpty(t, *entry.RequestId)
+	assert.Equal(t, "https://example.com", entry.After.StaticUrl)
+
+	var written v1.AuditEntry
+	require.NoError(t, json.Unmarshal(sink.Bytes(), &written))
+	assert.Equal(t, entry.Id, written.Id, "the entry is written to the sink")
+}
+
+func TestServer_APIKeys(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store, AdminToken: "secret", APIKeyRateLimit: 2})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	do := func(method, path, token, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { res.Body.Close() }) //nolint:errcheck
+		return res
+	}
+
+	res := do(http.MethodPost, "/apikeys", "", `{"name":"ci"}`)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "minting requires the admin token")
+
+	res = do(http.MethodPost, "/apikeys", "secret", `{"name":"ci"}`)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var key v1.APIKeyObject
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&key))
+	require.NotNil(t, key.Key)
+
+	res = do(http.MethodPost, "/apikeys", *key.Key, `{"name":"more"}`)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "API keys cannot mint keys")
+
+	res = do(http.MethodPost, "/probes", *key.Key, `{"static_url":"https://example.com"}`)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	// The rejected mint counted too, so the burst of 2 is spent.
+	res = do(http.MethodPost, "/probes", *key.Key, `{"static_url":"https://example.org"}`)
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode, "the default rate limit applies")
+
+	res = do(http.MethodGet, "/audit?operation=createProbe", "", "")
+	var entries v1.AuditEntriesArrayResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&entries))
+	require.Len(t, entries.Entries, 1)
+	assert.Equal(t, "apikey:ci", *entries.Entries[0].Actor)
+
+	res = do(http.MethodDelete, "/apikeys/"+key.Id, "secret", "")
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+	res = do(http.MethodGet, "/probes", *key.Key, "")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode, "revoked keys are rejected")
+}
+
+func TestServer_IdempotencyKey(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	create := func(key, body string) (*http.Response, v1.ProbeObject) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/probes", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.Header, key)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close() //nolint:errcheck
+		var probe v1.ProbeObject
+		if res.StatusCode == http.StatusCreated {
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&probe))
+		}
+		return res, probe
+	}
+
+	res, first := create("retry-1", `{"static_url":"https://example.com"}`)
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	res, retried := create("retry-1", `{"static_url":"https://example.com"}`)
+	assert.Equal(t, http.StatusCreated, res.StatusCode, "the retry does not get a 409 for the existing probe")
+	assert.Equal(t, "true", res.Header.Get(idempotency.ReplayedHeader))
+	assert.Equal(t, first.Id, retried.Id)
+
+	res, _ = create("retry-2", `{"static_url":"https://example.com"}`)
+	assert.Equal(t, http.StatusConflict, res.StatusCode, "another key creates the probe again")
+
+	res, _ = create("retry-1", `{"static_url":"https://other.example.com"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+
+	res, _ = create(strings.Repeat("k", 256), `{"static_url":"https://other.example.com"}`)
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode, "overlong keys are rejected")
+}
+
+func TestServer_ProbeTemplates(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store, ProbeTemplates: []ProbeTemplate{{
+		ID:         uuid.MustParse("5b2c1c0e-3b8f-4d8e-9a55-0d3f0c6c2a11"),
+		Name:       "console",
+		URLPattern: "https://console.{region}.example.com",
+	}}})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/probes", "application/json",
+		strings.NewReader(`{"template_id":"5b2c1c0e-3b8f-4d8e-9a55-0d3f0c6c2a11","variables":{"region":"eu-west-1"}}`))
+	require.NoError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var probe v1.ProbeObject
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&probe))
+	assert.Equal(t, "https://console.eu-west-1.example.com", probe.StaticUrl)
+	assert.Equal(t, "5b2c1c0e-3b8f-4d8e-9a55-0d3f0c6c2a11", (*probe.Labels)["rhobs-synthetics/template"])
+}
+
+func TestServer_ProbeProblems(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	// The route is not taken for a probe ID.
+	res, err := http.Get(ts.URL + "/probes/problems?pending_for=1h")
+	require.NoError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body v1.ProbeProblemsArrayResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Empty(t, body.Problems)
+}
+
+func TestServer_CacheControl(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		rules    []CacheControlRule
+		path     string
+		expected string
+	}{
+		{name: "default spec", path: "/api/v1/openapi.json", expected: "public, max-age=300"},
+		{name: "default probes", path: "/probes", expected: "private, no-cache"},
+		{name: "configured", rules: []CacheControlRule{{Route: "/probes", MaxAge: time.Minute, Private: true}}, path: "/probes", expected: "private, max-age=60"},
+		{name: "configured elsewhere", rules: []CacheControlRule{{Route: "/probes", MaxAge: time.Minute}}, path: "/api/v1/openapi.json"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, err := New(Config{Store: store, CacheControl: tc.rules})
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+
+			srv.Handler().ServeHTTP(w, httptest.NewRequest("GET", tc.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expected, w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func TestServer_ConditionalList(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	for i := range 20 {
+		_, err := store.CreateProbe(context.Background(), v1.ProbeObject{Id: uuid.New(), StaticUrl: fmt.Sprintf("https://%d.example.com", i), Status: v1.Active}, fmt.Sprint(i))
+		require.NoError(t, err)
+	}
+	srv, err := New(Config{Store: store, CompressResponses: true})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/probes", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	gz, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	var list v1.ProbesArrayResponse
+	require.NoError(t, json.NewDecoder(gz).Decode(&list))
+	assert.Len(t, list.Probes, 20)
+	tag := w.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+
+	req = httptest.NewRequest("GET", "/probes", nil)
+	req.Header.Set("If-None-Match", tag)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, tag, w.Header().Get("ETag"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	assert.Zero(t, w.Body.Len())
+
+	req = httptest.NewRequest("GET", "/probes?label_selector=env%3Dprod", nil)
+	req.Header.Set("If-None-Match", tag)
+	w = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "a different listing has a different ETag")
+}
+
+func TestServer_Run(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Addr: "127.0.0.1:0", Store: store})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestServer_RunListenError(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Addr: "256.0.0.1:80", Store: store})
+	require.NoError(t, err)
+
+	err = srv.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to listen on 256.0.0.1:80")
+}
+
+func TestServer_CamelCaseFields(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store})
+	require.NoError(t, err)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL+"/probes", strings.NewReader(`{"staticUrl":"https://example.com","labels":{"cluster_id":"abc"}}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Field-Casing", "camelCase")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var probe map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&probe))
+	assert.Equal(t, "https://example.com", probe["staticUrl"])
+	assert.Contains(t, probe, "urlHash")
+	assert.NotContains(t, probe, "static_url")
+	assert.Equal(t, "abc", probe["labels"].(map[string]any)["cluster_id"], "label keys are kept as given")
+}
+
+func TestOperationNamer(t *testing.T) {
+	swagger, err := v1.GetSwagger()
+	require.NoError(t, err)
+	swagger.Servers = nil
+	operation, err := operationNamer(swagger)
+	require.NoError(t, err)
+
+	for _, tc := range []struct{ method, target, want string }{
+		{http.MethodGet, "/probes?label_selector=a%3Db", "ListProbes"},
+		{http.MethodGet, "/probes/" + uuid.NewString(), "GetProbeById"},
+		{http.MethodDelete, "/probes/" + uuid.NewString(), "DeleteProbe"},
+		{http.MethodGet, "/no-such-path", ""},
+		{http.MethodPut, "/probes", ""},
+	} {
+		assert.Equal(t, tc.want, operation(httptest.NewRequest(tc.method, tc.target, nil)), tc.method+" "+tc.target)
+	}
+}
+
+func TestV2OperationNamer(t *testing.T) {
+	operation, err := v2OperationNamer()
+	require.NoError(t, err)
+
+	for _, tc := range []struct{ method, target, want string }{
+		{http.MethodGet, "/api/v2/probes", "v2/ListProbes"},
+		{http.MethodGet, "/api/v2/probes/" + uuid.NewString(), "v2/GetProbeById"},
+		{http.MethodDelete, "/api/v2/probes/" + uuid.NewString(), ""},
+		{http.MethodGet, "/probes", ""},
+		{http.MethodGet, "/api/v2", ""},
+	} {
+		assert.Equal(t, tc.want, operation(httptest.NewRequest(tc.method, tc.target, nil)), tc.method+" "+tc.target)
+	}
+}
+
+func TestServer_APIVersions(t *testing.T) {
+	store, err := probestore.NewLocalProbeStoreWithDir(t.TempDir())
+	require.NoError(t, err)
+	probe, err := store.CreateProbe(context.Background(), v1.ProbeObject{Id: uuid.New(), StaticUrl: "https://example.com", Status: v1.Active}, "probe")
+	require.NoError(t, err)
+	srv, err := New(Config{Store: store, APIDeprecation: APIDeprecation{At: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}})
+	require.NoError(t, err)
+	get := func(path, version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if version != "" {
+			req.Header.Set("API-Version", version)
+		}
+		w := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/probes/"+probe.Id.String(), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "v1", w.Header().Get("API-Version"))
+	assert.Equal(t, "@1793491200", w.Header().Get("Deprecation"))
+	assert.Contains(t, w.Header().Get("Link"), `rel="successor-version"`)
+	assert.Contains(t, w.Body.String(), `"static_url":"https://example.com"`)
+
+	for _, w := range []*httptest.ResponseRecorder{get("/api/v2/probes/"+probe.Id.String(), ""), get("/probes/"+probe.Id.String(), "v2")} {
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "v2", w.Header().Get("API-Version"))
+		assert.Empty(t, w.Header().Get("Deprecation"))
+		var got v2.ProbeObject
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&got))
+		assert.Equal(t, []v2.ProbeTarget{{Url: "https://example.com"}}, got.Targets)
+	}
+
+	w = get("/api/v2/probes", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var list v2.ProbesArrayResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	assert.Len(t, list.Probes, 1)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/v2/probes?limit=0", "").Code, "requests are validated against the v2 spec")
+	assert.Equal(t, http.StatusBadRequest, get("/probes", "v9").Code)
+
+	w = get("/api/v2/openapi.json", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"targets"`)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	middleware "github.com/oapi-codegen/nethttp-middleware"
+	"github.com/rhobs/rhobs-synthetics-api/internal/apiv2"
+	"github.com/rhobs/rhobs-synthetics-api/internal/logging"
+	"github.com/rhobs/rhobs-synthetics-api/internal/tracing"
+	v1 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v1"
+	v2 "github.com/rhobs/rhobs-synthetics-api/pkg/apis/v2"
+)
+
+// apiV2Handler serves version 2 of the API under its prefix, validated
+// against its own spec, with requireTenant applied as in version 1.
+func apiV2Handler(server v1.StrictServerInterface, requireTenant v2.StrictMiddlewareFunc) (http.Handler, error) {
+	swagger, err := v2.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading v2 swagger spec: %w", err)
+	}
+	swagger.Servers = nil
+
+	router := http.NewServeMux()
+	v2.HandlerFromMux(v2.NewStrictHandler(apiv2.NewServer(server), []v2.StrictMiddlewareFunc{requireTenant, logging.StrictMiddleware, tracing.StrictMiddleware}), router)
+	return http.StripPrefix(apiv2.Prefix, middleware.OapiRequestValidator(swagger)(router)), nil
+}
+
+// v2OperationNamer names the version 2 operations for the HTTP metrics,
+// as "v2/" followed by the operation ID. It returns "" for other requests.
+func v2OperationNamer() (func(*http.Request) string, error) {
+	swagger, err := v2.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading v2 swagger spec: %w", err)
+	}
+	swagger.Servers = nil
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to route v2 API operations: %w", err)
+	}
+	return func(r *http.Request) string {
+		path, ok := strings.CutPrefix(r.URL.Path, apiv2.Prefix)
+		if !ok || !strings.HasPrefix(path, "/") {
+			return ""
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path, r2.URL.RawPath = path, ""
+		route, _, err := router.FindRoute(r2)
+		if err != nil {
+			return ""
+		}
+		return "v2/" + route.Operation.OperationID
+	}, nil
+}
@@ -3,6 +3,7 @@ package templates
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"gopkg.in/yaml.v3"
@@ -60,9 +61,12 @@ func TestSyntheticsAPITemplateStructure(t *testing.T) {
 	}
 
 	expectedKinds := map[string]bool{
-		"Service":        false,
-		"ServiceAccount": false,
-		"Deployment":     false,
+		"Service":                 false,
+		"ServiceAccount":          false,
+		"Deployment":              false,
+		"NetworkPolicy":           false,
+		"PodDisruptionBudget":     false,
+		"HorizontalPodAutoscaler": false,
 	}
 
 	for _, obj := range objects {
@@ -187,3 +191,188 @@ func TestServiceMonitorTemplateStructure(t *testing.T) {
 		t.Error("IMAGE_TAG parameter should be present in service monitor template")
 	}
 }
+
+// findObject returns the first template object of the given kind.
+func findObject(t *testing.T, objects []interface{}, kind string) map[string]interface{} {
+	t.Helper()
+	for _, obj := range objects {
+		objMap, ok := obj.(map[string]interface{})
+		if ok && objMap["kind"] == kind {
+			return objMap
+		}
+	}
+	t.Fatalf("Expected to find %s object in template", kind)
+	return nil
+}
+
+// nested walks a chain of map keys, failing the test if any is missing.
+func nested(t *testing.T, obj map[string]interface{}, keys ...string) interface{} {
+	t.Helper()
+	var cur interface{} = obj
+	for _, key := range keys {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected %v to be a map while looking up %v", cur, keys)
+		}
+		if cur, ok = m[key]; !ok {
+			t.Fatalf("Missing key %q while looking up %v", key, keys)
+		}
+	}
+	return cur
+}
+
+func TestSyntheticsAPITemplateAvailabilityObjects(t *testing.T) {
+	content, err := os.ReadFile("synthetics-api-template.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read synthetics-api-template.yaml: %v", err)
+	}
+
+	var template map[string]interface{}
+	if err := yaml.Unmarshal(content, &template); err != nil {
+		t.Fatalf("Template is not valid YAML: %v", err)
+	}
+
+	objects, ok := template["objects"].([]interface{})
+	if !ok {
+		t.Fatal("Template should have objects array")
+	}
+
+	deployment := findObject(t, objects, "Deployment")
+	podLabels := nested(t, deployment, "spec", "template", "metadata", "labels")
+	deploymentName := nested(t, deployment, "metadata", "name")
+
+	if _, ok := nested(t, deployment, "spec").(map[string]interface{})["replicas"]; ok {
+		t.Error("Deployment should not set replicas; the HorizontalPodAutoscaler owns them")
+	}
+
+	t.Run("NetworkPolicy", func(t *testing.T) {
+		np := findObject(t, objects, "NetworkPolicy")
+		if apiVersion := np["apiVersion"]; apiVersion != "networking.k8s.io/v1" {
+			t.Errorf("Expected apiVersion 'networking.k8s.io/v1', got %v", apiVersion)
+		}
+		if selector := nested(t, np, "spec", "podSelector", "matchLabels"); !reflect.DeepEqual(selector, podLabels) {
+			t.Errorf("NetworkPolicy podSelector %v should match the Deployment pod labels %v", selector, podLabels)
+		}
+		ingress, ok := nested(t, np, "spec", "ingress").([]interface{})
+		if !ok || len(ingress) == 0 {
+			t.Fatal("NetworkPolicy should have ingress rules")
+		}
+		from := nested(t, ingress[0].(map[string]interface{}), "from").([]interface{})
+		if values := nested(t, from[1].(map[string]interface{}), "namespaceSelector", "matchExpressions").([]interface{})[0].(map[string]interface{})["values"]; values != "${{INGRESS_NAMESPACES}}" {
+			t.Errorf("NetworkPolicy should admit the namespaces of INGRESS_NAMESPACES, got %v", values)
+		}
+		ports := nested(t, ingress[0].(map[string]interface{}), "ports").([]interface{})
+		if port := nested(t, ports[0].(map[string]interface{}), "port"); port != 8080 {
+			t.Errorf("NetworkPolicy should admit the API port 8080, got %v", port)
+		}
+	})
+
+	t.Run("PodDisruptionBudget", func(t *testing.T) {
+		pdb := findObject(t, objects, "PodDisruptionBudget")
+		if apiVersion := pdb["apiVersion"]; apiVersion != "policy/v1" {
+			t.Errorf("Expected apiVersion 'policy/v1', got %v", apiVersion)
+		}
+		if selector := nested(t, pdb, "spec", "selector", "matchLabels"); !reflect.DeepEqual(selector, podLabels) {
+			t.Errorf("PodDisruptionBudget selector %v should match the Deployment pod labels %v", selector, podLabels)
+		}
+		if maxUnavailable := nested(t, pdb, "spec", "maxUnavailable"); maxUnavailable != "${{PDB_MAX_UNAVAILABLE}}" {
+			t.Errorf("PodDisruptionBudget maxUnavailable should be parameterized, got %v", maxUnavailable)
+		}
+	})
+
+	t.Run("HorizontalPodAutoscaler", func(t *testing.T) {
+		hpa := findObject(t, objects, "HorizontalPodAutoscaler")
+		if apiVersion := hpa["apiVersion"]; apiVersion != "autoscaling/v2" {
+			t.Errorf("Expected apiVersion 'autoscaling/v2', got %v", apiVersion)
+		}
+		if kind := nested(t, hpa, "spec", "scaleTargetRef", "kind"); kind != "Deployment" {
+			t.Errorf("HorizontalPodAutoscaler should target a Deployment, got %v", kind)
+		}
+		if name := nested(t, hpa, "spec", "scaleTargetRef", "name"); name != deploymentName {
+			t.Errorf("HorizontalPodAutoscaler should target %v, got %v", deploymentName, name)
+		}
+		for key, param := range map[string]string{
+			"minReplicas": "${{HPA_MIN_REPLICAS}}",
+			"maxReplicas": "${{HPA_MAX_REPLICAS}}",
+		} {
+			if value := nested(t, hpa, "spec", key); value != param {
+				t.Errorf("HorizontalPodAutoscaler %s should be %s, got %v", key, param, value)
+			}
+		}
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		declared := map[string]bool{}
+		for _, param := range template["parameters"].([]interface{}) {
+			if name, ok := param.(map[string]interface{})["name"].(string); ok {
+				declared[name] = true
+			}
+		}
+		for _, name := range []string{"INGRESS_NAMESPACES", "PDB_MAX_UNAVAILABLE", "HPA_MIN_REPLICAS", "HPA_MAX_REPLICAS", "HPA_CPU_UTILIZATION"} {
+			if !declared[name] {
+				t.Errorf("%s parameter should be present in template", name)
+			}
+		}
+	})
+}
+
+func TestSyntheticsAPITemplateLeaderElection(t *testing.T) {
+	content, err := os.ReadFile("synthetics-api-template.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read synthetics-api-template.yaml: %v", err)
+	}
+
+	var template map[string]interface{}
+	if err := yaml.Unmarshal(content, &template); err != nil {
+		t.Fatalf("Template is not valid YAML: %v", err)
+	}
+
+	objects, ok := template["objects"].([]interface{})
+	if !ok {
+		t.Fatal("Template should have objects array")
+	}
+
+	t.Run("Role", func(t *testing.T) {
+		role := findObject(t, objects, "Role")
+		for _, rule := range nested(t, role, "rules").([]interface{}) {
+			rule := rule.(map[string]interface{})
+			if reflect.DeepEqual(rule["apiGroups"], []interface{}{"coordination.k8s.io"}) && reflect.DeepEqual(rule["resources"], []interface{}{"leases"}) {
+				return
+			}
+		}
+		t.Error("Role should grant access to coordination.k8s.io leases")
+	})
+
+	t.Run("Deployment", func(t *testing.T) {
+		deployment := findObject(t, objects, "Deployment")
+		container := nested(t, deployment, "spec", "template", "spec", "containers").([]interface{})[0].(map[string]interface{})
+		args, _ := container["args"].([]interface{})
+		for _, want := range []string{
+			"--leader-election-lease=${LEADER_ELECTION_LEASE}",
+			"--advertise-url=http://$(POD_IP):8080",
+			"--standby=${STANDBY}",
+		} {
+			found := false
+			for _, arg := range args {
+				found = found || arg == want
+			}
+			if !found {
+				t.Errorf("Deployment container should be started with %s, got %v", want, args)
+			}
+		}
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		declared := map[string]bool{}
+		for _, param := range template["parameters"].([]interface{}) {
+			if name, ok := param.(map[string]interface{})["name"].(string); ok {
+				declared[name] = true
+			}
+		}
+		for _, name := range []string{"LEADER_ELECTION_LEASE", "STANDBY"} {
+			if !declared[name] {
+				t.Errorf("%s parameter should be present in template", name)
+			}
+		}
+	})
+}